@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/correctedcloud/aws-overview/internal/config"
+	"github.com/correctedcloud/aws-overview/internal/terminal"
+	"github.com/correctedcloud/aws-overview/internal/ui"
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// effectiveConfig returns the value and source of every flag after merging
+// the flags, environment variables and config file with the defaults.
+// selection holds the services by name, and defaulted reports whether they
+// were all selected because no service flag was given.
+func effectiveConfig(selection map[string]bool, defaulted bool, settings config.File) []config.Setting {
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	caps := terminal.Current()
+
+	services := config.Setting{Name: "services", Source: "service flags", Children: []config.Setting{}}
+	if defaulted {
+		services.Source = "default, no service flag was given so all are shown"
+	}
+
+	// One-shot runs and demo data do not touch the session, see main
+	disablesSession := ""
+	for _, name := range []string{"demo", "no-tui"} {
+		if f := flag.Lookup(name); f != nil && f.Value.String() == "true" {
+			disablesSession = "disabled by -" + name
+		}
+	}
+	if f := flag.Lookup("output"); f != nil && f.Value.String() != "" {
+		disablesSession = "disabled by -output"
+	}
+
+	var effective []config.Setting
+	flag.VisitAll(func(f *flag.Flag) {
+		if f.Name == "print-effective-config" {
+			return
+		}
+		setting := config.Setting{Name: f.Name, Value: f.Value.String(), Source: config.SourceDefault}
+		if set[f.Name] {
+			setting.Source = config.SourceFlag
+		}
+
+		if enabled, ok := selection[f.Name]; ok {
+			setting.Value = strconv.FormatBool(enabled)
+			if defaulted && !set[f.Name] {
+				setting.Source = config.SourceDefault
+			}
+			services.Children = append(services.Children, setting)
+			return
+		}
+
+		switch {
+		case set[f.Name]:
+		case f.Name == "region":
+			setting.Value, setting.Source = effectiveRegion()
+		case f.Name == "theme" && settings.Theme != "":
+			setting.Value, setting.Source = settings.Theme, config.SourceConfigFile
+		case f.Name == "theme":
+			setting.Value = ui.DefaultTheme
+		case f.Name == "graphs" && settings.Graphs != "":
+			setting.Value, setting.Source = settings.Graphs, config.SourceConfigFile
+		case f.Name == "graphs":
+			setting.Value = string(common.GraphLine)
+		case f.Name == "encrypt-state" && settings.EncryptState != "":
+			setting.Value, setting.Source = settings.EncryptState, config.SourceConfigFile
+		case f.Name == "endpoint-url" && settings.EndpointURL != "":
+			setting.Value, setting.Source = settings.EndpointURL, config.SourceConfigFile
+		case f.Name == "fips" && settings.UseFIPSEndpoint:
+			setting.Value, setting.Source = "true", config.SourceConfigFile
+		case f.Name == "dual-stack" && settings.UseDualStackEndpoint:
+			setting.Value, setting.Source = "true", config.SourceConfigFile
+		case f.Name == "no-color" && os.Getenv(terminal.NoColorEnv) != "":
+			setting.Source = config.EnvSource(terminal.NoColorEnv)
+		case f.Name == "no-color" && caps.Colorless():
+			setting.Source = "detected terminal"
+		case f.Name == "ascii" && os.Getenv(terminal.ASCIIEnv) != "" && os.Getenv(terminal.ASCIIEnv) != "0":
+			setting.Source = config.EnvSource(terminal.ASCIIEnv)
+		case f.Name == "ascii" && !caps.Emoji:
+			setting.Source = "detected terminal"
+		case f.Name == "no-alt-screen" && !caps.AltScreen:
+			setting.Source = "detected terminal"
+		}
+		if f.Name == "session-file" && disablesSession != "" {
+			setting.Value, setting.Source = "", disablesSession
+		}
+
+		// Report the outcome rather than the raw flag where they differ
+		switch f.Name {
+		case "no-color":
+			setting.Value = strconv.FormatBool(caps.Colorless() || set[f.Name] && f.Value.String() == "true")
+		case "ascii":
+			setting.Value = strconv.FormatBool(!caps.Emoji || set[f.Name] && f.Value.String() == "true")
+		case "no-alt-screen":
+			setting.Value = strconv.FormatBool(!caps.AltScreen || set[f.Name] && f.Value.String() == "true")
+		}
+
+		effective = append(effective, setting)
+		if f.Name == "config" {
+			effective = append(effective, fileMapSetting("colors", settings.Colors), retrySettings(settings.Retry))
+		}
+		if f.Name == "endpoint-url" {
+			effective = append(effective, fileMapSetting("endpoints", settings.Endpoints))
+		}
+	})
+
+	profile, profileSource := effectiveProfile()
+	effective = append(effective,
+		config.Setting{Name: "profile", Value: profile, Source: profileSource},
+		services,
+	)
+	return effective
+}
+
+// effectiveRegion returns the region used when -region is not given and
+// where it comes from: the environment, or else the shared AWS config of the
+// profile
+func effectiveRegion() (string, string) {
+	for _, name := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		if region := os.Getenv(name); region != "" {
+			return region, config.EnvSource(name)
+		}
+	}
+
+	// Loading the shared config reads local files only
+	cfg := config.NewConfig("")
+	if _, err := config.LoadAWSConfig(context.Background(), cfg); err != nil || cfg.Region == "" {
+		return "", "unset, no region is configured"
+	}
+	return cfg.Region, "shared AWS config"
+}
+
+// effectiveProfile returns the AWS profile whose credentials and settings
+// are used, and where it comes from
+func effectiveProfile() (string, string) {
+	for _, name := range []string{"AWS_PROFILE", "AWS_DEFAULT_PROFILE"} {
+		if profile := os.Getenv(name); profile != "" {
+			return profile, config.EnvSource(name)
+		}
+	}
+	return "default", config.SourceDefault
+}
+
+// fileMapSetting returns a map of the config file sorted by key, e.g. the
+// color overrides or the endpoints of services
+func fileMapSetting(name string, values map[string]string) config.Setting {
+	setting := config.Setting{Name: name, Source: config.SourceConfigFile, Children: []config.Setting{}}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		setting.Children = append(setting.Children, config.Setting{Name: key, Value: values[key]})
+	}
+	return setting
+}
+
+// retrySettings returns the retry policy of AWS calls. The attempts and mode
+// the config file leaves out come from the environment or the shared AWS
+// config, and else from the defaults, as in config.NewRetryer.
+func retrySettings(policy config.RetryPolicy) config.Setting {
+	attempts := config.Setting{Name: "max_attempts", Value: strconv.Itoa(config.DefaultMaxAttempts), Source: config.SourceDefault}
+	mode := config.Setting{Name: "mode", Value: string(aws.RetryModeStandard), Source: config.SourceDefault}
+
+	// Loading the shared config reads local files only
+	awsConfig, err := config.LoadAWSConfig(context.Background(), config.NewConfig(""))
+	if err != nil {
+		awsConfig = aws.Config{}
+	}
+
+	switch {
+	case policy.MaxAttempts > 0:
+		attempts.Value, attempts.Source = strconv.Itoa(policy.MaxAttempts), config.SourceConfigFile
+	case os.Getenv("AWS_MAX_ATTEMPTS") != "":
+		attempts.Value, attempts.Source = os.Getenv("AWS_MAX_ATTEMPTS"), config.EnvSource("AWS_MAX_ATTEMPTS")
+	case awsConfig.RetryMaxAttempts > 0:
+		attempts.Value, attempts.Source = strconv.Itoa(awsConfig.RetryMaxAttempts), "shared AWS config"
+	}
+	switch {
+	case policy.Mode != "":
+		mode.Value, mode.Source = policy.Mode, config.SourceConfigFile
+	case os.Getenv("AWS_RETRY_MODE") != "":
+		mode.Value, mode.Source = os.Getenv("AWS_RETRY_MODE"), config.EnvSource("AWS_RETRY_MODE")
+	case awsConfig.RetryMode != "":
+		mode.Value, mode.Source = string(awsConfig.RetryMode), "shared AWS config"
+	}
+	return config.Setting{Name: "retry", Children: []config.Setting{attempts, mode}}
+}
@@ -1,13 +1,36 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/service/kms"
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/correctedcloud/aws-overview/internal/audit"
+	"github.com/correctedcloud/aws-overview/internal/cache"
+	"github.com/correctedcloud/aws-overview/internal/config"
+	"github.com/correctedcloud/aws-overview/internal/logging"
+	"github.com/correctedcloud/aws-overview/internal/permissions"
+	"github.com/correctedcloud/aws-overview/internal/pins"
+	"github.com/correctedcloud/aws-overview/internal/runbook"
+	"github.com/correctedcloud/aws-overview/internal/seal"
+	"github.com/correctedcloud/aws-overview/internal/session"
+	"github.com/correctedcloud/aws-overview/internal/terminal"
+	"github.com/correctedcloud/aws-overview/internal/tracing"
 	"github.com/correctedcloud/aws-overview/internal/ui"
+	"github.com/correctedcloud/aws-overview/pkg/cloudwatchmetrics"
+	"github.com/correctedcloud/aws-overview/pkg/common"
+	"github.com/correctedcloud/aws-overview/pkg/hygiene"
+	"github.com/correctedcloud/aws-overview/pkg/metrics"
+	"github.com/correctedcloud/aws-overview/pkg/probe"
 )
 
 func main() {
@@ -17,33 +40,597 @@ func main() {
 	var showEC2 bool
 	var showECS bool
 	var showSQS bool
+	var showSSM bool
+	var showDNS bool
+	var showDR bool
+	var showSNS bool
+	var showLambda bool
+	var showLag bool
+	var showCloudFront bool
+	var showEBS bool
+	var showVPC bool
+	var showECR bool
+	var showAPIGateway bool
+	var showCost bool
+	var showFindings bool
+	var showHygiene bool
+	var stoppedDays int
+	var showProbes bool
+	var probePorts string
+	var probeTimeout time.Duration
+	var showMetrics bool
+	var containerInsights bool
+	var allowActions bool
 	var region string
+	var sessionFile string
+	var auditFile string
+	var runbooksFile string
+	var pinsFile string
+	var configFile string
+	var encryptState string
+	var themeName string
+	var graphsName string
+	var windowName string
+	var noColor bool
+	var rateLimits string
+	var queuePrefix string
+	var maxResults int
+	var maxConcurrency int
+	var cacheTTL time.Duration
+	var diskCache bool
+	var timeout time.Duration
+	var demoMode bool
+	var asciiSymbols bool
+	var noAltScreen bool
+	var checkPermissions bool
+	var noTUI bool
+	var outputFormat string
+	var outputDir string
+	var printConfig bool
+	var handoffFile string
+	var notes []string
+	var reportTo string
+	var reportFrom string
+	var reportSMTP string
+	var serveInterval time.Duration
+	var debug bool
+	var logFile string
+	var otlpEndpoint string
+	var endpointURL string
+	var useFIPS, useDualStack bool
 
-	flag.BoolVar(&showALB, "alb", false, "Show ALB resources")
+	flag.BoolVar(&showALB, "alb", false, "Show load balancer (application, network and gateway) resources")
 	flag.BoolVar(&showRDS, "rds", false, "Show RDS resources")
 	flag.BoolVar(&showEC2, "ec2", false, "Show EC2 resources")
+	flag.BoolVar(&showEBS, "ebs", false, "Show EBS volumes and flag unattached volumes and those low on burst balance")
+	flag.BoolVar(&showVPC, "vpc", false, "Show VPCs with the free IPs of their subnets, NAT gateways and their port allocation errors, internet gateways and endpoints")
 	flag.BoolVar(&showECS, "ecs", false, "Show ECS services")
+	flag.BoolVar(&containerInsights, "container-insights", false, "Graph the CPU and memory utilization of each ECS service against its reservation from Container Insights, which must be enabled on the cluster")
+	flag.BoolVar(&showECR, "ecr", false, "Show ECR repositories with their latest image and its critical and high vulnerability findings")
+	flag.BoolVar(&showAPIGateway, "apigw", false, "Show API Gateway REST and HTTP APIs with the throttling and 4xx, 5xx and latency metrics of their stages")
+	flag.BoolVar(&showCost, "cost", false, "Show the month-to-date spend by service and the daily trend from Cost Explorer, which bills every request; added to the other services rather than replacing them")
+	flag.BoolVar(&showFindings, "findings", false, "Flag idle load balancers, underutilized EC2 instances, DB instances without connections and single-AZ DB instances from days of CloudWatch statistics; added to the other services rather than replacing them")
+	flag.BoolVar(&showHygiene, "hygiene", false, "Add a Hygiene section to the Overview flagging likely wasted resources: EC2 instances stopped for -stopped-days, empty target groups, and SQS queues and DB instances without traffic in 30 days; added to the other services rather than replacing them")
+	flag.IntVar(&stoppedDays, "stopped-days", hygiene.DefaultStoppedDays, "How many days an EC2 instance is stopped before -hygiene flags it")
+	flag.BoolVar(&showProbes, "probe", false, "Probe the listeners of internet-facing load balancers and the -probe-ports of instances with a public IP from this machine, showing whether they answer next to their health in AWS; added to the other services rather than replacing them")
+	flag.StringVar(&probePorts, "probe-ports", "22", "Comma separated TCP ports -probe connects to on the public IPs of EC2 instances (empty to probe only load balancers)")
+	flag.DurationVar(&probeTimeout, "probe-timeout", probe.DefaultTimeout, "How long -probe waits for a connection or an HTTP response")
+	flag.BoolVar(&showMetrics, "metrics", false, "Browse the CloudWatch namespaces, metrics and dimensions, plot any metric and pin it to the Custom Metrics tab; added to the other services rather than replacing them")
 	flag.BoolVar(&showSQS, "sqs", false, "Show SQS queues")
+	flag.BoolVar(&showSSM, "ssm", false, "Show SSM managed instances and patch compliance")
+	flag.BoolVar(&showDNS, "dns", false, "Show Route53 records and flag those pointing at deleted load balancers, CloudFront distributions or EC2 addresses")
+	flag.BoolVar(&showDR, "dr", false, "Show the cross-region replication status of RDS instances, S3 buckets, ECR and DynamoDB tables")
+	flag.BoolVar(&showSNS, "sns", false, "Show which SQS queues each SNS topic fans out to, with filter policies and raw delivery")
+	flag.BoolVar(&showLambda, "lambda", false, "Show Lambda functions and, with -allow-actions, test-invoke them")
+	flag.BoolVar(&showCloudFront, "cloudfront", false, "Show CloudFront distributions and, with -allow-actions, invalidate their caches")
+	flag.BoolVar(&showLag, "lag", false, "Show the consumer lag of Kinesis streams, DynamoDB streams and SQS queues at the top of the Overview")
+	flag.BoolVar(&allowActions, "allow-actions", false, "Enable actions that change resources or run code, e.g. starting and stopping EC2 instances, Session Manager and ECS Exec sessions, scaling ECS services, deregistering load balancer targets, purging SQS queues, Lambda test invocations, one-off ECS tasks, CloudFront invalidations and runbooks")
+	flag.BoolVar(&debug, "debug", false, "Log every AWS call with its duration, retries and errors, and how long each service takes to load, to -log-file")
+	flag.StringVar(&logFile, "log-file", "", "File structured logs are appended to as JSON lines, - for stderr: failed and retried AWS calls, and with -debug every call (defaults to "+logging.DefaultPath()+" with -debug, else no log)")
+	flag.StringVar(&otlpEndpoint, "otlp-endpoint", "", "OTLP/HTTP endpoint traces of the refreshes are exported to, with a span per service and per AWS call, e.g. http://localhost:4318 (defaults to "+tracing.EndpointEnv+" or "+tracing.TracesEndpointEnv+", else no traces)")
+	flag.StringVar(&auditFile, "audit-log", audit.DefaultPath(), "File the actions taken with -allow-actions are appended to as JSON lines, - for stderr (empty to disable)")
 	flag.StringVar(&region, "region", "", "AWS region (defaults to AWS_REGION env var)")
-	flag.Parse()
+	flag.StringVar(&endpointURL, "endpoint-url", "", "Endpoint of every AWS API, e.g. http://localhost:4566 for LocalStack or another AWS-compatible emulator (defaults to the config file's endpoint_url; its endpoints override single services)")
+	flag.BoolVar(&useFIPS, "fips", false, "Use the FIPS endpoints of the AWS APIs, e.g. in GovCloud (defaults to the config file's use_fips_endpoint)")
+	flag.BoolVar(&useDualStack, "dual-stack", false, "Use the dual-stack (IPv6) endpoints of the AWS APIs (defaults to the config file's use_dualstack_endpoint)")
+	flag.StringVar(&sessionFile, "session-file", session.DefaultPath(), "File the session is saved to on exit and restored from on start (empty to disable)")
+	flag.StringVar(&configFile, "config", config.DefaultFilePath(), "JSON config file with the theme, color overrides and graph style (empty to disable)")
+	flag.StringVar(&themeName, "theme", "", "Color theme: "+strings.Join(ui.ThemeNames(), ", ")+" (defaults to the config file's theme, or "+ui.DefaultTheme+")")
+	flag.StringVar(&windowName, "window", "1h", "How far back the metric graphs of the RDS and SQS tabs go: 1h, 3h, 6h or 24h; the 1, 3, 6 and 24 keys change it")
+	flag.StringVar(&graphsName, "graphs", "", "Graph style: line, braille (denser, two data points per column) or blocks (defaults to the config file's graphs, or line)")
+	flag.BoolVar(&noColor, "no-color", false, "Disable colors (also disabled when NO_COLOR is set)")
+	flag.StringVar(&runbooksFile, "runbooks", runbook.DefaultPath(), "JSON file of break-glass runbooks shown on the Runbooks tab (empty to disable)")
+	flag.StringVar(&encryptState, "encrypt-state", "", "Encrypt the session, pins and disk cache files with a key kept in the OS keychain (keychain) or generated by a KMS key (kms:<key ID, ARN or alias>); defaults to the config file's encrypt_state")
+	flag.StringVar(&pinsFile, "pins-file", pins.DefaultPath(), "File the metrics pinned to the Custom Metrics tab are saved to (empty to keep them for the session only)")
+	flag.StringVar(&queuePrefix, "queue-prefix", "", "Only show SQS queues whose name starts with this prefix")
+	flag.IntVar(&maxResults, "max-results", ui.DefaultMaxResults, "Number of resources each tab shows at first, press + for more; load balancers and ECR repositories beyond it are not loaded (0 for all)")
+	flag.IntVar(&maxConcurrency, "max-concurrency", common.DefaultMaxConcurrency, "Maximum number of AWS calls in flight at once; throttled calls are retried with backoff")
+	flag.DurationVar(&cacheTTL, "cache-ttl", 0, "Reuse AWS responses younger than this on automatic refreshes, e.g. 5m (0 disables caching; r always reloads)")
+	flag.BoolVar(&diskCache, "disk-cache", false, "Also keep cached responses on disk, so restarts within -cache-ttl do not call AWS")
+	flag.DurationVar(&timeout, "timeout", ui.DefaultTimeout, "Give up loading a service after this long and show the error on its tab, e.g. 20s")
+	flag.StringVar(&rateLimits, "rate-limits", "", "Client-side API rate limits per AWS service in requests/second, e.g. default=10,ecs=2,cloudwatch=5")
+	flag.BoolVar(&demoMode, "demo", false, "Show fixture data instead of querying AWS (no credentials needed)")
+	flag.BoolVar(&asciiSymbols, "ascii", false, "Show ASCII symbols instead of emoji (detected automatically on consoles without emoji fonts)")
+	flag.BoolVar(&noAltScreen, "no-alt-screen", false, "Render inline instead of in the alternate screen buffer")
+	flag.BoolVar(&noTUI, "no-tui", false, "Load the selected services once, print them as plain text and exit")
+	flag.StringVar(&outputFormat, "output", "", "Load the selected services once, print them in this format and exit: text (as -no-tui), markdown (a table per resource type, for documents) or csv (a file per resource type with all its fields, written to -output-dir)")
+	flag.StringVar(&outputDir, "output-dir", "", "Directory -output csv writes its files to (defaults to csv-<date>-<time>)")
+	flag.BoolVar(&printConfig, "print-effective-config", false, "Print the configuration merged from flags, environment variables, the config file and defaults as YAML, with the source of each value, and exit")
+	flag.BoolVar(&checkPermissions, "check-permissions", false, "Dry-run the AWS calls of the selected services, print which IAM permissions are missing and exit")
+	flag.StringVar(&handoffFile, "handoff-file", "", "File export-handoff writes the handoff to (defaults to handoff-<date>-<time>.md, - for stdout)")
+	flag.Func("note", "Note added to the handoff by export-handoff, e.g. \"orders-db failover scheduled for 02:00\" (repeatable)", func(value string) error {
+		notes = append(notes, value)
+		return nil
+	})
+	flag.StringVar(&reportTo, "report-to", "", "Comma separated recipients of email-report")
+	flag.StringVar(&reportFrom, "report-from", "", "Sender address of email-report, a verified SES identity unless -report-smtp is set")
+	flag.StringVar(&reportSMTP, "report-smtp", "", "SMTP server email-report sends through as host:port instead of SES, authenticating with "+smtpUsernameEnv+" and "+smtpPasswordEnv+" when set")
+	flag.DurationVar(&serveInterval, "serve-interval", time.Minute, "How often serve loads the services its alert rules check")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [policy|export-handoff|email-report|serve|snapshot] [flags]\n       %s diff [flags] <before.json> <after.json>\n\n", os.Args[0], os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "The policy subcommand prints the least-privilege IAM policy of the selected services and exits.\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "The export-handoff subcommand loads the selected services once and writes an on-call handoff in markdown.\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "The email-report subcommand loads the selected services once and emails an HTML summary, e.g. daily from cron.\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "The serve subcommand checks the alert rules of the config file every -serve-interval without the UI, notifying their command, webhook and Slack.\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "The snapshot subcommand loads the selected services once and saves them as JSON to the file given after the flags (defaults to snapshot-<date>-<time>.json, - for stdout).\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "The diff subcommand prints the resources added and removed, their state changes and the count of each type between two snapshots or session files, exiting with 1 when they differ.\n\n")
+		flag.PrintDefaults()
+	}
+
+	// "aws-overview policy [flags]" prints the IAM policy of the selected
+	// services instead of showing them, "aws-overview export-handoff [flags]"
+	// writes a handoff of their state, "aws-overview email-report [flags]"
+	// emails a summary of it, "aws-overview serve [flags]" watches them for
+	// alert rule breaches, "aws-overview snapshot [flags] [file]" saves
+	// their resources and "aws-overview diff [flags] a.json b.json" compares
+	// two saved snapshots
+	args := os.Args[1:]
+	var subcommand string
+	if len(args) > 0 && (args[0] == "policy" || args[0] == "export-handoff" || args[0] == "email-report" || args[0] == "serve" || args[0] == "snapshot" || args[0] == "diff") {
+		subcommand, args = args[0], args[1:]
+	}
+	printPolicy := subcommand == "policy"
+	exportHandoff := subcommand == "export-handoff"
+	emailReport := subcommand == "email-report"
+	serve := subcommand == "serve"
+	saveSnapshot := subcommand == "snapshot"
+	compareSnapshots := subcommand == "diff"
+	flag.CommandLine.Parse(args)
+
+	// Logs go to a file, as anything printed would corrupt the terminal UI
+	if debug && logFile == "" {
+		logFile = logging.DefaultPath()
+	}
+	if logFile != "" {
+		logCloser, err := logging.Open(logFile, debug)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
+		}
+		defer logCloser.Close()
+	} else {
+		logging.Discard()
+	}
+	if compareSnapshots && flag.NArg() != 2 {
+		fmt.Fprintf(os.Stderr, "Error: diff needs the two snapshots to compare\n")
+		os.Exit(2)
+	}
+	if saveSnapshot && flag.NArg() > 1 {
+		fmt.Fprintf(os.Stderr, "Error: snapshot takes a single file\n")
+		os.Exit(2)
+	}
+
+	limits, err := config.ParseRateLimits(rateLimits)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+	if maxConcurrency < 1 {
+		fmt.Fprintf(os.Stderr, "Error: -max-concurrency must be at least 1\n")
+		os.Exit(2)
+	}
+	if maxResults < 0 {
+		fmt.Fprintf(os.Stderr, "Error: -max-results must not be negative\n")
+		os.Exit(2)
+	}
+	ports, err := probe.ParsePorts(probePorts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -probe-ports: %v\n", err)
+		os.Exit(2)
+	}
+	if ports == nil {
+		ports = []int32{}
+	}
 
-	// Check if at least one resource type is selected
-	if !showALB && !showRDS && !showEC2 && !showECS && !showSQS {
+	var settings config.File
+	if configFile != "" {
+		settings, err = config.LoadFile(configFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", configFile, err)
+			os.Exit(2)
+		}
+	}
+	theme, err := loadTheme(themeName, settings)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+	graphs, err := loadGraphStyle(graphsName, settings)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+	endpoints := loadEndpoints(endpointURL, useFIPS, useDualStack, settings)
+	if err := endpoints.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -endpoint-url: %v\n", err)
+		os.Exit(2)
+	}
+	window, err := cloudwatchmetrics.ParseWindow(windowName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -window: %v\n", err)
+		os.Exit(2)
+	}
+	if err := parseOutputFormat(outputFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -output: %v\n", err)
+		os.Exit(2)
+	}
+	if outputFormat != "" {
+		// Any output format is a one-shot run
+		noTUI = true
+	}
+
+	// A broken runbook must not go unnoticed until it is needed
+	var runbooks []runbook.Runbook
+	if runbooksFile != "" {
+		runbooks, err = runbook.Load(runbooksFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", runbooksFile, err)
+			os.Exit(2)
+		}
+	}
+
+	// Check if at least one resource type is selected. -cost, -findings,
+	// -hygiene, -probe, -metrics and -container-insights are opt-in on top
+	// of the others, so they do not count.
+	defaulted := false
+	if !showALB && !showRDS && !showEC2 && !showECS && !showSQS && !showSSM && !showDNS && !showDR && !showSNS && !showLambda && !showLag && !showCloudFront && !showEBS && !showVPC && !showECR && !showAPIGateway {
 		// Default to showing all resource types if none specified
 		showALB = true
 		showRDS = true
 		showEC2 = true
 		showECS = true
 		showSQS = true
+		showSSM = true
+		showDNS = true
+		showDR = true
+		showSNS = true
+		showLambda = true
+		showLag = true
+		showCloudFront = true
+		showEBS = true
+		showVPC = true
+		showECR = true
+		showAPIGateway = true
+		defaulted = true
+	}
+
+	selection := map[string]bool{"alb": showALB, "rds": showRDS, "ec2": showEC2, "ecs": showECS, "sqs": showSQS, "ssm": showSSM, "dns": showDNS, "dr": showDR, "sns": showSNS, "lambda": showLambda, "lag": showLag, "cloudfront": showCloudFront, "ebs": showEBS, "vpc": showVPC, "ecr": showECR, "apigw": showAPIGateway, "cost": showCost, "findings": showFindings, "hygiene": showHygiene, "probe": showProbes, "metrics": showMetrics, "container-insights": containerInsights}
+	if printConfig {
+		fmt.Print(config.FormatYAML(effectiveConfig(selection, defaulted, settings)))
+		return
+	}
+
+	// Fixture data is never saved, so demo runs need no key
+	var sealer *seal.Sealer
+	if !demoMode {
+		sealer, err = loadSealer(encryptState, region, endpoints, settings)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
+		}
+	}
+
+	if compareSnapshots {
+		os.Exit(runDiff(flag.Arg(0), flag.Arg(1), sealer))
+	}
+
+	// Fixture metrics do not match the metrics pinned in a real account
+	if demoMode {
+		pinsFile = ""
+	}
+	var pinned []metrics.Pin
+	if pinsFile != "" {
+		pinned, err = pins.Load(pinsFile, sealer)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", pinsFile, err)
+			os.Exit(2)
+		}
+	}
+
+	var services []string
+	for service, enabled := range selection {
+		if enabled {
+			services = append(services, service)
+		}
+	}
+	// Pinned metrics are loaded even without -metrics
+	if !showMetrics && len(pinned) > 0 {
+		services = append(services, "metrics")
+	}
+	sort.Strings(services)
+
+	if printPolicy {
+		os.Exit(runPolicy(services, allowActions, len(runbooks) > 0))
+	}
+
+	if checkPermissions {
+		os.Exit(runPermissionCheck(region, endpoints, settings.Retry, services))
+	}
+
+	// Demo data must not replace or be replaced by a real session, and
+	// one-shot and headless runs always load fresh data
+	if demoMode || noTUI || exportHandoff || emailReport || serve || saveSnapshot {
+		sessionFile = ""
+	}
+
+	// Only actions on real resources are audited, and only the terminal UI
+	// takes actions
+	var auditLog *audit.Log
+	if allowActions && auditFile != "" && !demoMode && !noTUI && !exportHandoff && !emailReport && !serve && !saveSnapshot {
+		var err error
+		auditLog, err = audit.Open(auditFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
+		}
+		defer auditLog.Close()
+	}
+
+	var cacheDir string
+	if diskCache {
+		cacheDir = cache.DefaultDir()
+	}
+
+	// Restore the previous session, if any
+	var restore *session.Snapshot
+	if sessionFile != "" {
+		snapshot, err := session.Load(sessionFile, sealer)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Ignoring saved session: %v\n", err)
+		}
+		restore = snapshot
+	}
+
+	// Cancelled on shutdown to abort in-flight AWS calls
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	shutdownTracing, err := tracing.Start(ctx, otlpEndpoint)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -otlp-endpoint: %v\n", err)
+		os.Exit(2)
+	}
+	// Exports the spans of the last refreshes, which os.Exit would drop
+	flushTraces := func() {
+		flushCtx, cancelFlush := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancelFlush()
+		if err := shutdownTracing(flushCtx); err != nil {
+			fmt.Fprintf(os.Stderr, "Error exporting traces: %v\n", err)
+		}
+	}
+	exit := func(code int) {
+		flushTraces()
+		os.Exit(code)
+	}
+
+	// Adapt to what the terminal can display, e.g. the classic Windows console
+	caps := terminal.Current()
+	if noColor {
+		caps.DisableColor()
+	}
+	caps.Apply()
+	if asciiSymbols {
+		caps.Emoji = false
+	}
+	if noAltScreen {
+		caps.AltScreen = false
+	}
+
+	opts := ui.Options{
+		ShowALB:        showALB,
+		ShowRDS:        showRDS,
+		ShowEC2:        showEC2,
+		ShowECS:        showECS,
+		ShowSQS:        showSQS,
+		ShowSSM:        showSSM,
+		ShowDNS:        showDNS,
+		ShowDR:         showDR,
+		ShowSNS:        showSNS,
+		ShowLambda:     showLambda,
+		ShowLag:        showLag,
+		ShowCloudFront: showCloudFront,
+		ShowEBS:        showEBS,
+		ShowVPC:        showVPC,
+		ShowECR:        showECR,
+		ShowAPIGateway: showAPIGateway,
+		MetricsWindow:  window,
+		ShowCost:       showCost,
+		ShowFindings:   showFindings,
+		ShowHygiene:    showHygiene,
+		StoppedDays:    stoppedDays,
+		ShowProbes:     showProbes,
+		ProbePorts:     ports,
+		ProbeTimeout:   probeTimeout,
+		ShowMetrics:    showMetrics,
+		Pins:           pinned,
+		PinsFile:       pinsFile,
+		AllowActions:   allowActions,
+		AuditLog:       auditLog,
+		Runbooks:       runbooks,
+		Alerts:         settings.Alerts,
+		Region:         region,
+		Endpoints:      endpoints,
+		Retry:          settings.Retry,
+		Context:        ctx,
+		Timeout:        timeout,
+		RateLimits:     limits,
+		QueuePrefix:    queuePrefix,
+		MaxResults:     maxResults,
+		MaxConcurrency: maxConcurrency,
+		CacheTTL:       cacheTTL,
+		CacheDir:       cacheDir,
+		Sealer:         sealer,
+		Restore:        restore,
+		Demo:           demoMode,
+		ASCIISymbols:   !caps.Emoji,
+		Theme:          theme,
+		Graphs:         graphs,
+		NoColor:        caps.Colorless(),
+
+		ContainerInsights: containerInsights,
+	}
+
+	if noTUI {
+		exit(runOutput(opts, outputFormat, outputDir))
+	}
+
+	if exportHandoff {
+		exit(runHandoff(opts, notes, handoffFile))
 	}
 
-	// Create the UI model
-	m := ui.NewModel(showALB, showRDS, showEC2, showECS, showSQS, region)
+	if emailReport {
+		exit(runEmailReport(opts, reportTo, reportFrom, reportSMTP))
+	}
+
+	if serve {
+		exit(runServe(opts, serveInterval))
+	}
 
-	// Initialize the terminal UI
-	p := tea.NewProgram(m, tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	if saveSnapshot {
+		exit(runSnapshot(opts, flag.Arg(0)))
+	}
+
+	// Initialize the terminal UI. SIGINT and SIGTERM make Run return the
+	// final model so the session can still be saved.
+	p := tea.NewProgram(ui.New(opts), caps.ProgramOptions()...)
+	final, err := p.Run()
+	cancel()
+	flushTraces()
+
+	if model, ok := final.(ui.Model); ok && sessionFile != "" {
+		if err := session.Save(sessionFile, model.Snapshot(), sealer); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving session: %v\n", err)
+		}
+	}
+
+	if err != nil && !errors.Is(err, tea.ErrInterrupted) {
 		fmt.Printf("Error running UI: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// loadTheme returns the theme named by the -theme flag, or else by the config
+// file, with the config file's color overrides applied
+func loadTheme(name string, settings config.File) (ui.Theme, error) {
+	if name == "" {
+		name = settings.Theme
+	}
+	if name == "" {
+		name = ui.DefaultTheme
+	}
+
+	theme, err := ui.LookupTheme(name)
+	if err != nil {
+		return ui.Theme{}, err
+	}
+	return theme.WithOverrides(settings.Colors)
+}
+
+// loadGraphStyle returns the graph style named by the -graphs flag, or else
+// by the config file
+func loadGraphStyle(name string, settings config.File) (common.GraphStyle, error) {
+	if name == "" {
+		name = settings.Graphs
+	}
+	if name == "" {
+		return common.GraphLine, nil
+	}
+	return common.ParseGraphStyle(name)
+}
+
+// loadEndpoints returns the endpoints of the AWS APIs: the one of every
+// service set by the -endpoint-url flag, or else by the config file, the
+// config file's endpoints of single services, and the FIPS and dual-stack
+// variants turned on by their flags or the config file
+func loadEndpoints(url string, fips, dualStack bool, settings config.File) config.Endpoints {
+	if url == "" {
+		url = settings.EndpointURL
+	}
+	return config.Endpoints{
+		URL:       url,
+		Services:  settings.Endpoints,
+		FIPS:      fips || settings.UseFIPSEndpoint,
+		DualStack: dualStack || settings.UseDualStackEndpoint,
+	}
+}
+
+// loadSealer returns the sealer of the state files selected by the
+// -encrypt-state flag, or else by the config file, or nil when encryption is
+// off. KMS is called in region at endpoints, unless the key is an ARN.
+func loadSealer(spec, region string, endpoints config.Endpoints, settings config.File) (*seal.Sealer, error) {
+	if spec == "" {
+		spec = settings.EncryptState
+	}
+	source, keyID, err := seal.ParseSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	switch source {
+	case seal.SourceKeychain:
+		return seal.NewKeychain()
+	case seal.SourceKMS:
+		ctx := context.Background()
+		awsConfig, err := config.LoadAWSConfig(ctx, config.NewConfig(region).WithEndpoints(endpoints).WithRetryer(config.NewRetryer(settings.Retry)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load AWS config: %w", err)
+		}
+		return seal.NewKMS(ctx, kms.NewFromConfig(awsConfig), keyID)
+	}
+	return nil, nil
+}
+
+// runPolicy prints the IAM policy of the selected services and returns the
+// process exit code
+func runPolicy(services []string, allowActions, runbooks bool) int {
+	data, err := json.MarshalIndent(permissions.Policy(services, allowActions, runbooks), "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding policy: %v\n", err)
+		return 1
+	}
+	fmt.Println(string(data))
+	return 0
+}
+
+// runPermissionCheck dry-runs the calls of the selected services, prints a
+// report and returns the process exit code
+func runPermissionCheck(region string, endpoints config.Endpoints, retry config.RetryPolicy, services []string) int {
+	ctx := context.Background()
+
+	cfg := config.NewConfig(region).WithEndpoints(endpoints).WithRetryer(config.NewRetryer(retry))
+	awsConfig, err := config.LoadAWSConfig(ctx, cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading AWS config: %v\n", err)
+		return 1
+	}
+
+	results := permissions.Run(ctx, permissions.Checks(awsConfig, services))
+	fmt.Print(permissions.FormatReport(results))
+
+	for _, result := range results {
+		if result.Status == permissions.Denied || result.Status == permissions.Unknown {
+			return 1
+		}
+	}
+	return 0
+}
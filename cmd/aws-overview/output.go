@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/correctedcloud/aws-overview/internal/export"
+	"github.com/correctedcloud/aws-overview/internal/ui"
+)
+
+// outputFormats are the formats -output prints the selected services in
+var outputFormats = []string{"text", "csv", "markdown"}
+
+// parseOutputFormat checks that format is one of outputFormats, or empty
+// for the terminal UI
+func parseOutputFormat(format string) error {
+	for _, known := range outputFormats {
+		if format == known {
+			return nil
+		}
+	}
+	if format == "" {
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q, expected one of %s", format, strings.Join(outputFormats, ", "))
+}
+
+// runOutput loads the services selected by opts once and prints them in
+// format, or writes them to dir for csv, and returns the exit code
+func runOutput(opts ui.Options, format, dir string) int {
+	switch format {
+	case "markdown":
+		fmt.Print(export.Markdown(ui.LoadSnapshot(opts), time.Now()))
+		return 0
+	case "csv":
+		// Written to files below
+	default:
+		fmt.Print(ui.RenderPlain(opts))
+		return 0
+	}
+
+	if dir == "" {
+		dir = "csv-" + time.Now().Format("20060102-1504")
+	}
+	paths, err := export.WriteCSV(dir, ui.LoadSnapshot(opts))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Wrote %d CSV files to %s:\n", len(paths), dir)
+	for _, path := range paths {
+		fmt.Printf("  %s\n", path)
+	}
+	return 0
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+
+	"github.com/correctedcloud/aws-overview/internal/config"
+	"github.com/correctedcloud/aws-overview/internal/permissions"
+	"github.com/correctedcloud/aws-overview/internal/report"
+	"github.com/correctedcloud/aws-overview/internal/ui"
+)
+
+// The credentials email-report authenticates to the -report-smtp server
+// with, kept out of the flags so they do not show up in process listings
+const (
+	smtpUsernameEnv = "AWS_OVERVIEW_SMTP_USERNAME"
+	smtpPasswordEnv = "AWS_OVERVIEW_SMTP_PASSWORD"
+)
+
+// runHandoff writes the on-call handoff of the services selected by opts to
+// path, and returns the exit code
+func runHandoff(opts ui.Options, notes []string, path string) int {
+	r := ui.LoadReport(opts)
+	r.Notes = notes
+	handoff := report.Markdown(r, "On-call handoff")
+	if path == "-" {
+		fmt.Print(handoff)
+		return 0
+	}
+
+	if path == "" {
+		path = "handoff-" + time.Now().Format("20060102-1504") + ".md"
+	}
+	// The handoff holds resource names and account data, like the session
+	if err := os.WriteFile(path, []byte(handoff), 0o600); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Wrote the handoff to %s\n", path)
+	return 0
+}
+
+// runEmailReport emails a summary of the services selected by opts from
+// from to the comma separated recipients in to, through the SMTP server at
+// smtpAddr or else through SES, and returns the exit code
+func runEmailReport(opts ui.Options, to, from, smtpAddr string) int {
+	var recipients []string
+	for _, recipient := range strings.Split(to, ",") {
+		if recipient = strings.TrimSpace(recipient); recipient != "" {
+			recipients = append(recipients, recipient)
+		}
+	}
+	if len(recipients) == 0 || from == "" {
+		fmt.Fprintf(os.Stderr, "Error: email-report needs -report-to and -report-from\n")
+		return 2
+	}
+
+	sender, err := reportSender(opts.Context, opts.Region, opts.Endpoints, opts.Retry, smtpAddr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	const title = "aws-overview report"
+	r := ui.LoadReport(opts)
+	r.Snapshot = nil
+	html, err := report.HTML(r, title)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error rendering the report: %v\n", err)
+		return 1
+	}
+
+	ctx := opts.Context
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	err = sender.Send(ctx, report.Email{
+		From:    from,
+		To:      recipients,
+		Subject: report.Subject(r, title),
+		HTML:    html,
+		Text:    report.Markdown(r, title),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", permissions.Describe(err))
+		return 1
+	}
+	fmt.Printf("Sent the report to %s\n", strings.Join(recipients, ", "))
+	return 0
+}
+
+// reportSender returns the sender of email-report: the SMTP server at
+// smtpAddr, or SES in region at endpoints, retried by retry, when smtpAddr
+// is empty
+func reportSender(ctx context.Context, region string, endpoints config.Endpoints, retry config.RetryPolicy, smtpAddr string) (report.Sender, error) {
+	if smtpAddr != "" {
+		var auth smtp.Auth
+		if username := os.Getenv(smtpUsernameEnv); username != "" {
+			host, _, err := net.SplitHostPort(smtpAddr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid -report-smtp %q: %w", smtpAddr, err)
+			}
+			auth = smtp.PlainAuth("", username, os.Getenv(smtpPasswordEnv), host)
+		}
+		return report.SMTPSender{Addr: smtpAddr, Auth: auth}, nil
+	}
+
+	awsConfig, err := config.LoadAWSConfig(ctx, config.NewConfig(region).WithEndpoints(endpoints).WithRetryer(config.NewRetryer(retry)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return report.NewSESSender(sesv2.NewFromConfig(awsConfig)), nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/correctedcloud/aws-overview/internal/alerts"
+	"github.com/correctedcloud/aws-overview/internal/permissions"
+	"github.com/correctedcloud/aws-overview/internal/ui"
+)
+
+// runServe checks the alert rules of opts every interval without the
+// terminal UI until interrupted, notifying the rules that start to be
+// breached, and returns the exit code. Breaches, their clearing and failed
+// loads are logged to stdout, one line each.
+func runServe(opts ui.Options, interval time.Duration) int {
+	if len(opts.Alerts) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: serve needs alert rules in the config file\n")
+		return 2
+	}
+	if interval <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: -serve-interval must be positive\n")
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(opts.Context, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	opts.Context = ctx
+
+	monitor := ui.NewAlertMonitor(opts)
+	if len(monitor.Services()) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: none of the selected services is checked by an alert rule\n")
+		return 2
+	}
+	logf("checking %d alert rules on %s every %s", len(opts.Alerts), strings.Join(monitor.Services(), ", "), interval)
+
+	// Breaches by key, as of the last complete load of their service
+	active := make(map[string]alerts.Breach)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		breaches, failed := monitor.Check()
+		if ctx.Err() != nil {
+			return 0
+		}
+		for _, service := range monitor.Services() {
+			if err, ok := failed[service]; ok {
+				logf("failed to load %s, keeping its alerts as they were: %s", service, permissions.Describe(err))
+				continue
+			}
+			checkBreaches(ctx, active, service, breaches[service], monitor.Region())
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkBreaches updates active with the current breaches of service,
+// notifying those that are new and logging those that cleared
+func checkBreaches(ctx context.Context, active map[string]alerts.Breach, service string, current []alerts.Breach, region string) {
+	seen := make(map[string]bool)
+	for _, breach := range current {
+		seen[breach.Key()] = true
+		if _, ok := active[breach.Key()]; ok {
+			continue
+		}
+		active[breach.Key()] = breach
+		logf("%s breached by %s (%s)", breach.Rule.Label(), breach.Resource, breach.Detail)
+		if breach.Rule.Notifies() {
+			if err := alerts.Notify(ctx, breach, region); err != nil {
+				logf("failed to notify %s breached by %s: %v", breach.Rule.Label(), breach.Resource, err)
+			}
+		}
+	}
+
+	for key, breach := range active {
+		if breach.Rule.Service() == service && !seen[key] {
+			delete(active, key)
+			logf("%s no longer breached by %s", breach.Rule.Label(), breach.Resource)
+		}
+	}
+}
+
+// logf prints a line of the serve log with the time
+func logf(format string, args ...any) {
+	fmt.Printf("%s %s\n", time.Now().Format("2006-01-02 15:04:05"), fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/correctedcloud/aws-overview/internal/diff"
+	"github.com/correctedcloud/aws-overview/internal/seal"
+	"github.com/correctedcloud/aws-overview/internal/session"
+	"github.com/correctedcloud/aws-overview/internal/ui"
+)
+
+// runSnapshot saves the resources of the services selected by opts to path
+// as JSON, and returns the exit code
+func runSnapshot(opts ui.Options, path string) int {
+	data, err := json.MarshalIndent(ui.LoadSnapshot(opts), "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding the snapshot: %v\n", err)
+		return 1
+	}
+	data = append(data, '\n')
+	if path == "-" {
+		os.Stdout.Write(data)
+		return 0
+	}
+
+	if path == "" {
+		path = "snapshot-" + time.Now().Format("20060102-1504") + ".json"
+	}
+	// The snapshot holds resource names and account data, like the session
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Wrote the snapshot to %s\n", path)
+	return 0
+}
+
+// runDiff prints what changed from the snapshot at beforePath to the one at
+// afterPath, either of which may be the session file, encrypted by sealer.
+// Like diff(1), it exits with 0 when nothing changed, 1 when something did
+// and 2 on trouble.
+func runDiff(beforePath, afterPath string, sealer *seal.Sealer) int {
+	before, err := loadSnapshot(beforePath, sealer)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	after, err := loadSnapshot(afterPath, sealer)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	d := diff.Compare(*before, *after)
+	fmt.Print(diff.Format(d))
+	if d.Changed() {
+		return 1
+	}
+	return 0
+}
+
+// loadSnapshot reads the snapshot at path, which must exist
+func loadSnapshot(path string, sealer *seal.Sealer) (*session.Snapshot, error) {
+	snapshot, err := session.Load(path, sealer)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if snapshot == nil {
+		return nil, fmt.Errorf("%s: no such file", path)
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,235 @@
+// Package alerts checks the alert rules of the config file against the data
+// of each refresh, e.g. an SQS queue backing up or an ECS service running
+// fewer tasks than desired, and notifies through a command or a webhook when
+// a rule starts to be breached.
+package alerts
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/correctedcloud/aws-overview/pkg/alb"
+	"github.com/correctedcloud/aws-overview/pkg/ecs"
+	"github.com/correctedcloud/aws-overview/pkg/sqs"
+)
+
+// Supported rule types
+const (
+	TypeQueueDepth       = "queue-depth"       // Messages waiting in an SQS queue above a threshold
+	TypeUnhealthyTargets = "unhealthy-targets" // Unhealthy targets of a load balancer above a threshold
+	TypeECSBelowDesired  = "ecs-below-desired" // ECS service running fewer tasks than desired
+)
+
+// Rule is an alert rule of the config file
+type Rule struct {
+	Name     string `json:"name,omitempty"` // Defaults to a description of the rule
+	Type     string `json:"type"`
+	Resource string `json:"resource,omitempty"` // Name of the queue, load balancer or service, empty for all
+	Above    int64  `json:"above,omitempty"`    // Threshold of queue-depth and unhealthy-targets, defaults to 0
+
+	// Command is run by the shell when the rule starts to be breached, with
+	// the breach in the ALERT_RULE, ALERT_RESOURCE and ALERT_DETAIL
+	// environment variables
+	Command string `json:"command,omitempty"`
+
+	// Webhook is sent the breach and its message as a JSON POST when the rule
+	// starts to be breached
+	Webhook string `json:"webhook,omitempty"`
+
+	// Slack is the URL of a Slack incoming webhook posted the message when
+	// the rule starts to be breached
+	Slack string `json:"slack,omitempty"`
+
+	// Message is the text/template of the message sent to Slack and the
+	// webhook, with the fields of Message, defaulting to DefaultMessage
+	Message string `json:"message,omitempty"`
+}
+
+// DefaultMessage is the message template of rules without one
+const DefaultMessage = "🚨 {{.Rule}} breached by {{.Resource}} ({{.Detail}})"
+
+// Message holds the fields of a message template, e.g. {{.Resource}}
+type Message struct {
+	Rule     string // Label of the rule
+	Resource string
+	Detail   string
+	Region   string
+	Time     time.Time
+}
+
+// Breach is a resource breaching a rule
+type Breach struct {
+	Rule     Rule
+	Resource string
+	Detail   string // e.g. "1204 messages, above 1000"
+}
+
+// Key identifies the breach across refreshes
+func (b Breach) Key() string {
+	return b.Rule.Label() + "/" + b.Resource
+}
+
+// Validate checks that the rule's type is supported and its fields are valid
+func (r Rule) Validate() error {
+	switch r.Type {
+	case TypeQueueDepth, TypeUnhealthyTargets, TypeECSBelowDesired:
+	case "":
+		return errors.New("alert rule without a type")
+	default:
+		return fmt.Errorf("unknown alert rule type %q, supported are %s", r.Type,
+			strings.Join([]string{TypeQueueDepth, TypeUnhealthyTargets, TypeECSBelowDesired}, ", "))
+	}
+	if r.Above < 0 {
+		return fmt.Errorf("alert rule %q needs an above of at least 0", r.Label())
+	}
+	for _, target := range []struct{ name, url string }{{"webhook", r.Webhook}, {"slack", r.Slack}} {
+		if target.url == "" {
+			continue
+		}
+		u, err := url.Parse(target.url)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("alert rule %q has an invalid %s URL %q", r.Label(), target.name, target.url)
+		}
+	}
+	if _, err := r.template(); err != nil {
+		return fmt.Errorf("alert rule %q has an invalid message: %w", r.Label(), err)
+	}
+	return nil
+}
+
+// Notifies reports whether the rule runs a command or posts to a webhook or
+// Slack when breached
+func (r Rule) Notifies() bool {
+	return r.Command != "" || r.Webhook != "" || r.Slack != ""
+}
+
+// template parses the message template of the rule
+func (r Rule) template() (*template.Template, error) {
+	text := r.Message
+	if text == "" {
+		text = DefaultMessage
+	}
+	return template.New("message").Option("missingkey=error").Parse(text)
+}
+
+// Message returns the message of the breach in region, rendered with the
+// template of its rule
+func (b Breach) Message(region string, at time.Time) (string, error) {
+	tmpl, err := b.Rule.template()
+	if err != nil {
+		return "", fmt.Errorf("failed to parse alert message: %w", err)
+	}
+	var message strings.Builder
+	err = tmpl.Execute(&message, Message{
+		Rule:     b.Rule.Label(),
+		Resource: b.Resource,
+		Detail:   b.Detail,
+		Region:   region,
+		Time:     at,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render alert message: %w", err)
+	}
+	return message.String(), nil
+}
+
+// Label returns the name of the rule, or a description when it has none,
+// e.g. "queue-depth above 1000 on orders"
+func (r Rule) Label() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	label := r.Type
+	if r.Type != TypeECSBelowDesired {
+		label += fmt.Sprintf(" above %d", r.Above)
+	}
+	if r.Resource != "" {
+		label += " on " + r.Resource
+	}
+	return label
+}
+
+// Service returns the service whose data the rule checks, as named by the
+// service flags
+func (r Rule) Service() string {
+	switch r.Type {
+	case TypeQueueDepth:
+		return "sqs"
+	case TypeUnhealthyTargets:
+		return "alb"
+	case TypeECSBelowDesired:
+		return "ecs"
+	}
+	return ""
+}
+
+// matches reports whether the rule applies to the named resource
+func (r Rule) matches(name string) bool {
+	return r.Resource == "" || r.Resource == name
+}
+
+// Data holds the data of a refresh the rules are checked against. Only the
+// rules of the services given are checked.
+type Data struct {
+	Queues        []sqs.QueueSummary
+	LoadBalancers []alb.LoadBalancerSummary
+	Services      []ecs.ServiceSummary
+}
+
+// Check returns the breaches of the rules of service in data, sorted by rule
+// and resource
+func Check(rules []Rule, service string, data Data) []Breach {
+	var breaches []Breach
+	for _, rule := range rules {
+		if rule.Service() != service {
+			continue
+		}
+
+		switch rule.Type {
+		case TypeQueueDepth:
+			for _, queue := range data.Queues {
+				if rule.matches(queue.Name) && queue.ApproximateMessages > rule.Above {
+					breaches = append(breaches, Breach{Rule: rule, Resource: queue.Name,
+						Detail: fmt.Sprintf("%d messages, above %d", queue.ApproximateMessages, rule.Above)})
+				}
+			}
+
+		case TypeUnhealthyTargets:
+			for _, lb := range data.LoadBalancers {
+				if !rule.matches(lb.Name) {
+					continue
+				}
+				var unhealthy int64
+				for _, tg := range lb.TargetGroups {
+					for _, target := range tg.Targets {
+						if target.Unhealthy() {
+							unhealthy++
+						}
+					}
+				}
+				if unhealthy > rule.Above {
+					breaches = append(breaches, Breach{Rule: rule, Resource: lb.Name,
+						Detail: fmt.Sprintf("%d unhealthy targets, above %d", unhealthy, rule.Above)})
+				}
+			}
+
+		case TypeECSBelowDesired:
+			for _, service := range data.Services {
+				if rule.matches(service.ServiceName) && service.RunningCount < service.DesiredCount {
+					breaches = append(breaches, Breach{Rule: rule, Resource: service.ServiceName,
+						Detail: fmt.Sprintf("%d of %d desired tasks running", service.RunningCount, service.DesiredCount)})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(breaches, func(i, j int) bool {
+		return breaches[i].Key() < breaches[j].Key()
+	})
+	return breaches
+}
@@ -0,0 +1,87 @@
+package alerts
+
+import (
+	"testing"
+
+	"github.com/correctedcloud/aws-overview/pkg/alb"
+	"github.com/correctedcloud/aws-overview/pkg/ecs"
+	"github.com/correctedcloud/aws-overview/pkg/sqs"
+)
+
+func TestValidate(t *testing.T) {
+	for _, tc := range []struct {
+		rule    Rule
+		wantErr bool
+	}{
+		{Rule{Type: TypeQueueDepth, Above: 100}, false},
+		{Rule{Type: TypeECSBelowDesired, Webhook: "https://hooks.example.com/alert"}, false},
+		{Rule{}, true},
+		{Rule{Type: "cpu-above"}, true},
+		{Rule{Type: TypeUnhealthyTargets, Above: -1}, true},
+		{Rule{Type: TypeQueueDepth, Webhook: "hooks.example.com/alert"}, true},
+		{Rule{Type: TypeQueueDepth, Slack: "https://hooks.slack.com/services/T0/B0/x", Message: "{{.Resource}} is backing up"}, false},
+		{Rule{Type: TypeQueueDepth, Message: "{{.Resource"}, true},
+	} {
+		if err := tc.rule.Validate(); (err != nil) != tc.wantErr {
+			t.Errorf("Validate(%+v) = %v, want error %v", tc.rule, err, tc.wantErr)
+		}
+	}
+}
+
+func TestLabel(t *testing.T) {
+	if got := (Rule{Type: TypeQueueDepth, Resource: "orders", Above: 1000}).Label(); got != "queue-depth above 1000 on orders" {
+		t.Errorf("Unexpected label %q", got)
+	}
+	if got := (Rule{Type: TypeECSBelowDesired}).Label(); got != "ecs-below-desired" {
+		t.Errorf("Unexpected label %q", got)
+	}
+	if got := (Rule{Name: "orders backing up", Type: TypeQueueDepth}).Label(); got != "orders backing up" {
+		t.Errorf("Unexpected label %q", got)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	rules := []Rule{
+		{Type: TypeQueueDepth, Resource: "orders", Above: 100},
+		{Type: TypeUnhealthyTargets},
+		{Type: TypeECSBelowDesired},
+	}
+	data := Data{
+		Queues: []sqs.QueueSummary{
+			{Name: "orders", ApproximateMessages: 250},
+			{Name: "emails", ApproximateMessages: 5000},
+		},
+		LoadBalancers: []alb.LoadBalancerSummary{
+			{Name: "web-prod", TargetGroups: []alb.TargetGroupSummary{{Targets: []alb.TargetSummary{
+				{ID: "i-1", Status: "healthy"},
+				{ID: "i-2", Status: "unhealthy"},
+			}}}},
+			{Name: "api", TargetGroups: []alb.TargetGroupSummary{{Targets: []alb.TargetSummary{
+				{ID: "i-3", Status: "draining"},
+			}}}},
+		},
+		Services: []ecs.ServiceSummary{
+			{ServiceName: "web", RunningCount: 2, DesiredCount: 3},
+			{ServiceName: "worker", RunningCount: 1, DesiredCount: 1},
+		},
+	}
+
+	sqsBreaches := Check(rules, "sqs", data)
+	if len(sqsBreaches) != 1 || sqsBreaches[0].Resource != "orders" || sqsBreaches[0].Detail != "250 messages, above 100" {
+		t.Errorf("Unexpected SQS breaches %+v", sqsBreaches)
+	}
+
+	albBreaches := Check(rules, "alb", data)
+	if len(albBreaches) != 1 || albBreaches[0].Resource != "web-prod" || albBreaches[0].Detail != "1 unhealthy targets, above 0" {
+		t.Errorf("Unexpected ALB breaches %+v", albBreaches)
+	}
+
+	ecsBreaches := Check(rules, "ecs", data)
+	if len(ecsBreaches) != 1 || ecsBreaches[0].Resource != "web" || ecsBreaches[0].Detail != "2 of 3 desired tasks running" {
+		t.Errorf("Unexpected ECS breaches %+v", ecsBreaches)
+	}
+
+	if breaches := Check(rules, "rds", data); len(breaches) != 0 {
+		t.Errorf("Expected no breaches for a service without rules, got %+v", breaches)
+	}
+}
@@ -0,0 +1,123 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// notifyTimeout bounds the command and webhook calls of a breach
+const notifyTimeout = 30 * time.Second
+
+// webhookPayload is the JSON body sent to the webhook of a rule
+type webhookPayload struct {
+	Rule     string    `json:"rule"`
+	Resource string    `json:"resource"`
+	Detail   string    `json:"detail"`
+	Region   string    `json:"region,omitempty"`
+	Time     time.Time `json:"time"`
+	Message  string    `json:"message"`
+}
+
+// slackPayload is the JSON body sent to a Slack incoming webhook
+type slackPayload struct {
+	Text string `json:"text"`
+}
+
+// Notify runs the command of the breached rule and posts the breach to its
+// webhook and Slack, if any, returning the errors of all of them. region is
+// where the breach was found, for the message.
+func Notify(ctx context.Context, breach Breach, region string) error {
+	ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+
+	var errs []error
+	if breach.Rule.Command != "" {
+		if err := runCommand(ctx, breach); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if breach.Rule.Webhook == "" && breach.Rule.Slack == "" {
+		return errors.Join(errs...)
+	}
+
+	now := time.Now().UTC()
+	message, err := breach.Message(region, now)
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	if breach.Rule.Webhook != "" {
+		payload := webhookPayload{
+			Rule:     breach.Rule.Label(),
+			Resource: breach.Resource,
+			Detail:   breach.Detail,
+			Region:   region,
+			Time:     now,
+			Message:  message,
+		}
+		if err := post(ctx, breach.Rule.Webhook, payload); err != nil {
+			errs = append(errs, fmt.Errorf("failed to call alert webhook: %w", err))
+		}
+	}
+	if breach.Rule.Slack != "" {
+		if err := post(ctx, breach.Rule.Slack, slackPayload{Text: message}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to post alert to Slack: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runCommand runs the command of the breached rule with the shell
+func runCommand(ctx context.Context, breach Breach) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", breach.Rule.Command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", breach.Rule.Command)
+	}
+	cmd.Env = append(os.Environ(),
+		"ALERT_RULE="+breach.Rule.Label(),
+		"ALERT_RESOURCE="+breach.Resource,
+		"ALERT_DETAIL="+breach.Detail,
+	)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if text := strings.TrimSpace(string(output)); text != "" {
+			return fmt.Errorf("failed to run alert command: %w: %s", err, text)
+		}
+		return fmt.Errorf("failed to run alert command: %w", err)
+	}
+	return nil
+}
+
+// post sends payload to url as a JSON POST
+func post(ctx context.Context, url string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return errors.New(resp.Status)
+	}
+	return nil
+}
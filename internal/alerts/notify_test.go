@@ -0,0 +1,93 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestNotifyWebhook(t *testing.T) {
+	var payload webhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Failed to decode the webhook body: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	breach := Breach{Rule: Rule{Type: TypeECSBelowDesired, Webhook: server.URL}, Resource: "web", Detail: "2 of 3 desired tasks running"}
+	if err := Notify(context.Background(), breach, "us-east-1"); err != nil {
+		t.Fatalf("Notify returned an error: %v", err)
+	}
+	if payload.Rule != "ecs-below-desired" || payload.Resource != "web" || payload.Detail != "2 of 3 desired tasks running" ||
+		payload.Region != "us-east-1" || payload.Message != "🚨 ecs-below-desired breached by web (2 of 3 desired tasks running)" {
+		t.Errorf("Unexpected payload %+v", payload)
+	}
+}
+
+func TestNotifySlack(t *testing.T) {
+	var payload slackPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Failed to decode the Slack body: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	breach := Breach{
+		Rule:     Rule{Type: TypeQueueDepth, Above: 100, Slack: server.URL, Message: ":warning: *{{.Resource}}* in {{.Region}}: {{.Detail}}"},
+		Resource: "orders",
+		Detail:   "250 messages, above 100",
+	}
+	if err := Notify(context.Background(), breach, "us-east-1"); err != nil {
+		t.Fatalf("Notify returned an error: %v", err)
+	}
+	if payload.Text != ":warning: *orders* in us-east-1: 250 messages, above 100" {
+		t.Errorf("Unexpected Slack text %q", payload.Text)
+	}
+}
+
+func TestNotifyWebhookFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	breach := Breach{Rule: Rule{Type: TypeECSBelowDesired, Webhook: server.URL}, Resource: "web"}
+	if err := Notify(context.Background(), breach, "us-east-1"); err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("Expected the status in the error, got %v", err)
+	}
+}
+
+func TestNotifyCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The command uses sh")
+	}
+	path := filepath.Join(t.TempDir(), "alert.txt")
+	breach := Breach{
+		Rule:     Rule{Type: TypeQueueDepth, Above: 100, Command: `echo "$ALERT_RULE|$ALERT_RESOURCE|$ALERT_DETAIL" > ` + path},
+		Resource: "orders",
+		Detail:   "250 messages, above 100",
+	}
+	if err := Notify(context.Background(), breach, "us-east-1"); err != nil {
+		t.Fatalf("Notify returned an error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "queue-depth above 100|orders|250 messages, above 100" {
+		t.Errorf("Unexpected environment %q", got)
+	}
+
+	breach.Rule.Command = "echo broken >&2; exit 3"
+	if err := Notify(context.Background(), breach, "us-east-1"); err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("Expected the command's output in the error, got %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+// Package audit records the actions taken on AWS resources, e.g. scaling an
+// ECS service or stopping an EC2 instance, as JSON lines in an audit log.
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Stderr is the path of the audit log that writes to stderr
+const Stderr = "-"
+
+// Entry is an action recorded in the audit log
+type Entry struct {
+	Time     time.Time `json:"time"`
+	Action   string    `json:"action"`   // e.g. "ecs:UpdateService"
+	Resource string    `json:"resource"` // e.g. "production/web"
+	Region   string    `json:"region,omitempty"`
+	Detail   string    `json:"detail,omitempty"` // e.g. "desired count 2 -> 4"
+	Error    string    `json:"error,omitempty"`  // Why the action failed, empty when it succeeded
+}
+
+// Log appends entries to a file or stderr. A nil Log records nothing.
+type Log struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+}
+
+// DefaultPath returns the default location of the audit log
+func DefaultPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "aws-overview", "audit.log")
+}
+
+// Open opens the audit log at path for appending, creating it and its parent
+// directories as needed. A path of Stderr writes to stderr instead.
+func Open(path string) (*Log, error) {
+	if path == Stderr {
+		return &Log{w: os.Stderr}, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &Log{w: file, closer: file}, nil
+}
+
+// Record appends entry to the log, stamping it with the current time unless
+// it has one
+func (l *Log) Record(entry Entry) error {
+	if l == nil {
+		return nil
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+	// Keep details like "desired count 2 -> 4" readable
+	var line bytes.Buffer
+	encoder := json.NewEncoder(&line)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(entry); err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.w.Write(line.Bytes()); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// Close closes the file of the log
+func (l *Log) Close() error {
+	if l == nil || l.closer == nil {
+		return nil
+	}
+	return l.closer.Close()
+}
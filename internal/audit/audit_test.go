@@ -0,0 +1,79 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "audit.log")
+	log, err := Open(path)
+	if err != nil {
+		t.Fatalf("Expected no error opening the audit log, got %v", err)
+	}
+	if err := log.Record(Entry{Action: "ecs:UpdateService", Resource: "production/web", Detail: "desired count 2 -> 4"}); err != nil {
+		t.Fatalf("Expected no error recording, got %v", err)
+	}
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	if err := log.Record(Entry{Time: at, Action: "ec2:StopInstances", Resource: "i-1", Error: errors.New("denied").Error()}); err != nil {
+		t.Fatalf("Expected no error recording, got %v", err)
+	}
+	if err := log.Close(); err != nil {
+		t.Fatalf("Expected no error closing, got %v", err)
+	}
+
+	// Reopening appends rather than truncating
+	log, err = Open(path)
+	if err != nil {
+		t.Fatalf("Expected no error reopening the audit log, got %v", err)
+	}
+	log.Record(Entry{Action: "ecs:UpdateService", Resource: "production/api"})
+	log.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 entries, got %d: %s", len(lines), data)
+	}
+
+	var first, second Entry
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("Expected a JSON entry, got %q: %v", lines[0], err)
+	}
+	if !strings.Contains(lines[0], "2 -> 4") {
+		t.Errorf("Expected details unescaped, got %s", lines[0])
+	}
+	if first.Time.IsZero() || first.Detail != "desired count 2 -> 4" || first.Error != "" {
+		t.Errorf("Unexpected first entry %+v", first)
+	}
+	json.Unmarshal([]byte(lines[1]), &second)
+	if !second.Time.Equal(at) || second.Error != "denied" {
+		t.Errorf("Unexpected second entry %+v", second)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm()&0o077 != 0 && os.PathSeparator == '/' {
+		t.Errorf("Expected the audit log to be private, got %v", info.Mode().Perm())
+	}
+}
+
+func TestNilLog(t *testing.T) {
+	var log *Log
+	if err := log.Record(Entry{Action: "ecs:UpdateService"}); err != nil {
+		t.Errorf("Expected a nil log to record nothing, got %v", err)
+	}
+	if err := log.Close(); err != nil {
+		t.Errorf("Expected no error closing a nil log, got %v", err)
+	}
+}
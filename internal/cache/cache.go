@@ -0,0 +1,184 @@
+// Package cache keeps the responses of the AWS collectors for a TTL, so that
+// refreshes and restarts within the TTL do not call AWS again.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/correctedcloud/aws-overview/internal/seal"
+)
+
+// Cache stores responses in memory and, when it has a directory, on disk.
+// A nil Cache stores nothing.
+type Cache struct {
+	ttl    time.Duration
+	dir    string
+	sealer *seal.Sealer // Encrypts the on-disk entries, nil to write them in the clear
+
+	mu      sync.Mutex
+	entries map[string]entry
+	account string // Resolved on first use, as the credentials do not change while running
+}
+
+// entry is a stored response, kept as JSON so memory and disk behave the same
+type entry struct {
+	StoredAt time.Time       `json:"stored_at"`
+	Data     json.RawMessage `json:"data"`
+}
+
+// New returns a cache whose entries expire after ttl, or nil when ttl is not
+// positive. Entries are also written under dir unless it is empty, encrypted
+// by sealer unless it is nil.
+func New(ttl time.Duration, dir string, sealer *seal.Sealer) *Cache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &Cache{
+		ttl:     ttl,
+		dir:     dir,
+		sealer:  sealer,
+		entries: make(map[string]entry),
+	}
+}
+
+// DefaultDir returns the default directory of the on-disk cache
+func DefaultDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "aws-overview", "responses")
+}
+
+// Key returns the key of the responses of a service in an account and region
+func Key(account, region, service string) string {
+	return account + "/" + region + "/" + service
+}
+
+// Account returns the account ID the cache is keyed by, calling lookup the
+// first time only
+func (c *Cache) Account(ctx context.Context, lookup func(context.Context) (string, error)) (string, error) {
+	if c == nil {
+		return "", errors.New("cache disabled")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.account != "" {
+		return c.account, nil
+	}
+
+	account, err := lookup(ctx)
+	if err != nil {
+		return "", err
+	}
+	c.account = account
+	return account, nil
+}
+
+// Get decodes the response stored under key into v and returns when it was
+// stored. It reports false when there is no entry younger than the TTL.
+func (c *Cache) Get(key string, v any) (time.Time, bool) {
+	if c == nil {
+		return time.Time{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok && c.dir != "" {
+		e, ok = c.read(key)
+		if ok {
+			c.entries[key] = e
+		}
+	}
+	if !ok || time.Since(e.StoredAt) > c.ttl {
+		return time.Time{}, false
+	}
+
+	if err := json.Unmarshal(e.Data, v); err != nil {
+		return time.Time{}, false
+	}
+	return e.StoredAt, true
+}
+
+// Put stores v under key. Failing to write the on-disk copy is reported, but
+// the response is still cached in memory.
+func (c *Cache) Put(key string, v any) error {
+	if c == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+	e := entry{StoredAt: time.Now(), Data: data}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = e
+
+	if c.dir == "" {
+		return nil
+	}
+	return c.write(key, e)
+}
+
+// path returns the file an entry is stored in, e.g. <dir>/<account>/<region>/<service>.json
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, filepath.FromSlash(key)+".json")
+}
+
+// read loads an entry from disk, treating unreadable files as missing
+func (c *Cache) read(key string) (entry, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return entry{}, false
+	}
+	data, err = c.sealer.Open(data)
+	if err != nil {
+		return entry{}, false
+	}
+
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return entry{}, false
+	}
+	return e, true
+}
+
+// write saves an entry to disk, replacing the file atomically so concurrent
+// runs never read a partial entry
+func (c *Cache) write(key string, e entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+	data, err = c.sealer.Seal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return nil
+}
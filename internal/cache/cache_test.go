@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zalando/go-keyring"
+
+	"github.com/correctedcloud/aws-overview/internal/seal"
+	"github.com/correctedcloud/aws-overview/pkg/sqs"
+)
+
+func TestGetAndPut(t *testing.T) {
+	c := New(time.Minute, "", nil)
+	key := Key("123456789012", "us-east-1", "sqs")
+
+	var queues []sqs.QueueSummary
+	if _, ok := c.Get(key, &queues); ok {
+		t.Fatalf("Expected an empty cache")
+	}
+
+	if err := c.Put(key, []sqs.QueueSummary{{Name: "orders", ApproximateMessages: 3}}); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	storedAt, ok := c.Get(key, &queues)
+	if !ok || time.Since(storedAt) > time.Second {
+		t.Fatalf("Expected a fresh entry, got %v %v", storedAt, ok)
+	}
+	if len(queues) != 1 || queues[0].Name != "orders" || queues[0].ApproximateMessages != 3 {
+		t.Errorf("Unexpected cached queues %v", queues)
+	}
+
+	// Entries are separate per account, region and service
+	if _, ok := c.Get(Key("123456789012", "eu-west-1", "sqs"), &queues); ok {
+		t.Errorf("Expected no entry for another region")
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	c := New(time.Minute, "", nil)
+	c.entries["a/b/c"] = entry{StoredAt: time.Now().Add(-2 * time.Minute), Data: []byte(`[]`)}
+
+	var v []string
+	if _, ok := c.Get("a/b/c", &v); ok {
+		t.Errorf("Expected an expired entry to be ignored")
+	}
+}
+
+func TestDiskPersistence(t *testing.T) {
+	dir := t.TempDir()
+	key := Key("123456789012", "us-east-1", "alb")
+
+	if err := New(time.Minute, dir, nil).Put(key, []string{"web"}); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "123456789012", "us-east-1", "alb.json")); err != nil {
+		t.Fatalf("Expected the entry on disk: %v", err)
+	}
+
+	// A new cache, as after a restart, reads the entry back
+	var names []string
+	if _, ok := New(time.Minute, dir, nil).Get(key, &names); !ok || len(names) != 1 || names[0] != "web" {
+		t.Errorf("Expected the entry to survive a restart, got %v", names)
+	}
+}
+
+func TestDiskEncryption(t *testing.T) {
+	keyring.MockInit()
+	sealer, err := seal.NewKeychain()
+	if err != nil {
+		t.Fatalf("NewKeychain() error = %v", err)
+	}
+	dir := t.TempDir()
+	key := Key("123456789012", "us-east-1", "alb")
+
+	if err := New(time.Minute, dir, sealer).Put(key, []string{"web"}); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "123456789012", "us-east-1", "alb.json"))
+	if err != nil || !seal.Sealed(data) {
+		t.Fatalf("Expected the entry encrypted on disk, got %s, %v", data, err)
+	}
+
+	var names []string
+	if _, ok := New(time.Minute, dir, sealer).Get(key, &names); !ok || len(names) != 1 || names[0] != "web" {
+		t.Errorf("Expected the encrypted entry to survive a restart, got %v", names)
+	}
+	// Without the key the entry is a miss rather than an error
+	if _, ok := New(time.Minute, dir, nil).Get(key, &names); ok {
+		t.Error("Expected a miss reading an encrypted entry without a sealer")
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	c := New(0, t.TempDir(), nil)
+	if c != nil {
+		t.Fatalf("Expected a zero TTL to disable the cache")
+	}
+
+	if err := c.Put("a/b/c", "value"); err != nil {
+		t.Errorf("Put() on a disabled cache error = %v", err)
+	}
+	var v string
+	if _, ok := c.Get("a/b/c", &v); ok {
+		t.Errorf("Expected a disabled cache to return nothing")
+	}
+}
+
+func TestAccount(t *testing.T) {
+	c := New(time.Minute, "", nil)
+
+	calls := 0
+	lookup := func(ctx context.Context) (string, error) {
+		calls++
+		if calls == 1 {
+			return "", errors.New("ExpiredToken")
+		}
+		return "123456789012", nil
+	}
+
+	if _, err := c.Account(context.Background(), lookup); err == nil {
+		t.Errorf("Expected the lookup error")
+	}
+	for i := 0; i < 2; i++ {
+		if account, err := c.Account(context.Background(), lookup); err != nil || account != "123456789012" {
+			t.Errorf("Account() = %s, %v", account, err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("Expected the account to be looked up until it succeeds, got %d calls", calls)
+	}
+}
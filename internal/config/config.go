@@ -6,11 +6,14 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 )
 
 // Config holds the AWS configuration
 type Config struct {
-	Region string
+	Region    string
+	Endpoints Endpoints
+	Retryer   *Retryer // Nil for the retryer of the SDK
 }
 
 // AWSConfig is an alias for aws.Config to make imports cleaner
@@ -33,13 +36,29 @@ func NewConfig(region string) *Config {
 	}
 }
 
+// WithEndpoints returns the configuration with its endpoints overridden
+func (c *Config) WithEndpoints(endpoints Endpoints) *Config {
+	c.Endpoints = endpoints
+	return c
+}
+
+// WithRetryer returns the configuration with its clients sharing retryer
+func (c *Config) WithRetryer(retryer *Retryer) *Config {
+	c.Retryer = retryer
+	return c
+}
+
 // LoadAWSConfig loads the AWS SDK configuration
 func LoadAWSConfig(ctx context.Context, cfg *Config) (aws.Config, error) {
-	awsConfig, err := config.LoadDefaultConfig(ctx, config.WithRegion(cfg.Region))
+	options := append([]func(*config.LoadOptions) error{config.WithRegion(cfg.Region)}, cfg.Endpoints.loadOptions()...)
+	awsConfig, err := config.LoadDefaultConfig(ctx, options...)
 	if err != nil {
 		return awsConfig, err
 	}
 
+	cfg.Endpoints.apply(&awsConfig)
+	cfg.Retryer.apply(&awsConfig)
+
 	// Update config region if empty (this happens when using AWS profile)
 	if cfg.Region == "" {
 		cfg.Region = awsConfig.Region
@@ -47,3 +66,27 @@ func LoadAWSConfig(ctx context.Context, cfg *Config) (aws.Config, error) {
 
 	return awsConfig, nil
 }
+
+// Identity is who the credentials of a configuration belong to
+type Identity struct {
+	Account string
+	ARN     string // e.g. "arn:aws:sts::123456789012:assumed-role/ReadOnly/jane"
+}
+
+// CallerIdentity returns who the credentials of awsConfig belong to
+func CallerIdentity(ctx context.Context, awsConfig aws.Config) (Identity, error) {
+	identity, err := sts.NewFromConfig(awsConfig).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return Identity{}, err
+	}
+	return Identity{
+		Account: aws.ToString(identity.Account),
+		ARN:     aws.ToString(identity.Arn),
+	}, nil
+}
+
+// AccountID returns the ID of the account the credentials of awsConfig belong to
+func AccountID(ctx context.Context, awsConfig aws.Config) (string, error) {
+	identity, err := CallerIdentity(ctx, awsConfig)
+	return identity.Account, err
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+)
+
+// The sources a setting can take its value from, besides environment
+// variables (see EnvSource)
+const (
+	SourceFlag       = "flag"
+	SourceConfigFile = "config file"
+	SourceDefault    = "default"
+)
+
+// Setting is a value of the effective configuration and where it came from
+type Setting struct {
+	Name     string
+	Value    string
+	Source   string    // e.g. SourceFlag or "env AWS_REGION"
+	Children []Setting // Nested settings, in which case Value is ignored
+}
+
+// EnvSource returns the source of a value read from an environment variable
+func EnvSource(name string) string {
+	return "env " + name
+}
+
+// FormatYAML formats the settings as a YAML document with the source of each
+// value as a trailing comment, e.g. "region: eu-west-1 # env AWS_REGION"
+func FormatYAML(settings []Setting) string {
+	var output strings.Builder
+	output.WriteString("# Effective configuration, with the source of each value\n")
+	writeSettings(&output, settings, "")
+	return output.String()
+}
+
+// writeSettings writes the settings as YAML mappings indented by indent
+func writeSettings(output *strings.Builder, settings []Setting, indent string) {
+	for _, setting := range settings {
+		output.WriteString(indent + yamlScalar(setting.Name) + ":")
+		switch {
+		case setting.Children == nil:
+			output.WriteString(" " + yamlScalar(setting.Value))
+		case len(setting.Children) == 0:
+			output.WriteString(" {}")
+		}
+		if setting.Source != "" {
+			output.WriteString(" # " + setting.Source)
+		}
+		output.WriteString("\n")
+		writeSettings(output, setting.Children, indent+"  ")
+	}
+}
+
+// yamlScalar returns s as a plain YAML scalar, or double-quoted when a plain
+// scalar would be empty, be read as another type or break the syntax.
+// Booleans and numbers, such as the values of boolean and integer flags, stay
+// plain.
+func yamlScalar(s string) string {
+	if s == "true" || s == "false" {
+		return s
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return s
+	}
+	switch strings.ToLower(s) {
+	case "", "true", "false", "yes", "no", "on", "off", "null", "~":
+		return strconv.Quote(s)
+	}
+	if strings.ContainsAny(s[:1], "!&*-?{}[],#|>@`\"'%: ") ||
+		strings.HasSuffix(s, " ") || strings.Contains(s, ": ") || strings.Contains(s, " #") ||
+		strings.ContainsAny(s, "\n\t\\") {
+		return strconv.Quote(s)
+	}
+	return s
+}
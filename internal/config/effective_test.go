@@ -0,0 +1,60 @@
+package config
+
+import "testing"
+
+func TestFormatYAML(t *testing.T) {
+	settings := []Setting{
+		{Name: "region", Value: "eu-west-1", Source: EnvSource("AWS_REGION")},
+		{Name: "profile", Value: "", Source: SourceDefault},
+		{Name: "services", Source: "flag", Children: []Setting{
+			{Name: "ecs", Value: "true", Source: SourceFlag},
+			{Name: "sqs", Value: "false", Source: SourceDefault},
+		}},
+		{Name: "colors", Source: SourceConfigFile, Children: []Setting{
+			{Name: "accent", Value: "#D33682"},
+		}},
+		{Name: "tags", Source: SourceDefault, Children: []Setting{}},
+		{Name: "rate-limits", Value: "default=10,ecs=2"},
+		{Name: "max-concurrency", Value: "8"},
+		{Name: "session-file", Value: "no"},
+	}
+
+	expected := `# Effective configuration, with the source of each value
+region: eu-west-1 # env AWS_REGION
+profile: "" # default
+services: # flag
+  ecs: true # flag
+  sqs: false # default
+colors: # config file
+  accent: "#D33682"
+tags: {} # default
+rate-limits: default=10,ecs=2
+max-concurrency: 8
+session-file: "no"
+`
+	if output := FormatYAML(settings); output != expected {
+		t.Errorf("Unexpected YAML:\n%s\nexpected:\n%s", output, expected)
+	}
+}
+
+func TestYAMLScalar(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"us-east-1", "us-east-1"},
+		{"30s", "30s"},
+		{"", `""`},
+		{"null", `"null"`},
+		{"- item", `"- item"`},
+		{"a: b", `"a: b"`},
+		{"C:\\Users\\me", `"C:\\Users\\me"`},
+		{"/home/me/.config/aws-overview/config.json", "/home/me/.config/aws-overview/config.json"},
+	}
+
+	for _, tt := range tests {
+		if scalar := yamlScalar(tt.value); scalar != tt.expected {
+			t.Errorf("yamlScalar(%q) = %s, expected %s", tt.value, scalar, tt.expected)
+		}
+	}
+}
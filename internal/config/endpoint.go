@@ -0,0 +1,117 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+// Endpoints overrides the endpoints of the AWS APIs, e.g. to run against
+// LocalStack or another AWS-compatible emulator, or picks their FIPS or
+// dual-stack variants. The zero value keeps the endpoints of AWS.
+type Endpoints struct {
+	// URL is the endpoint of every service, e.g. "http://localhost:4566"
+	URL string
+
+	// Services are endpoints by service ID, e.g. "sqs" or
+	// "elastic_load_balancing_v2" as in AWS_ENDPOINT_URL_<SERVICE>, taking
+	// precedence over URL
+	Services map[string]string
+
+	// FIPS uses the FIPS 140 validated endpoints, e.g. in GovCloud
+	FIPS bool
+
+	// DualStack uses the endpoints reachable over IPv4 and IPv6
+	DualStack bool
+}
+
+// Validate checks that the endpoints are HTTP or HTTPS URLs
+func (e Endpoints) Validate() error {
+	if err := validateEndpoint(e.URL); err != nil {
+		return err
+	}
+	for _, service := range sortedServices(e.Services) {
+		if err := validateEndpoint(e.Services[service]); err != nil {
+			return fmt.Errorf("%s: %w", service, err)
+		}
+	}
+	return nil
+}
+
+// validateEndpoint checks that endpoint is empty or an HTTP or HTTPS URL
+func validateEndpoint(endpoint string) error {
+	if endpoint == "" {
+		return nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%q is not an http:// or https:// URL", endpoint)
+	}
+	return nil
+}
+
+// loadOptions returns the options loading the AWS configuration with the
+// endpoint variants, which also apply to the clients of the credential
+// providers, e.g. STS when assuming a role
+func (e Endpoints) loadOptions() []func(*config.LoadOptions) error {
+	var options []func(*config.LoadOptions) error
+	if e.FIPS {
+		options = append(options, config.WithUseFIPSEndpoint(aws.FIPSEndpointStateEnabled))
+	}
+	if e.DualStack {
+		options = append(options, config.WithUseDualStackEndpoint(aws.DualStackEndpointStateEnabled))
+	}
+	return options
+}
+
+// apply makes the clients created from awsConfig call the endpoints
+func (e Endpoints) apply(awsConfig *aws.Config) {
+	if e.URL != "" {
+		awsConfig.BaseEndpoint = aws.String(e.URL)
+	}
+	if e.URL != "" || len(e.Services) > 0 {
+		// Looked up by every client before the endpoints of single services
+		// in the shared config and environment, which would otherwise win
+		sources := make([]interface{}, 0, len(awsConfig.ConfigSources)+1)
+		awsConfig.ConfigSources = append(append(sources, serviceEndpoints(e)), awsConfig.ConfigSources...)
+	}
+}
+
+// serviceEndpoints is a configuration source of the endpoint of each client
+// by the SDK ID of its service, e.g. "Elastic Load Balancing v2"
+type serviceEndpoints Endpoints
+
+// GetServiceBaseEndpoint returns the endpoint of the service with sdkID
+func (s serviceEndpoints) GetServiceBaseEndpoint(ctx context.Context, sdkID string) (string, bool, error) {
+	for service, endpoint := range s.Services {
+		if normalizeServiceID(service) == normalizeServiceID(sdkID) {
+			return endpoint, true, nil
+		}
+	}
+	return s.URL, s.URL != "", nil
+}
+
+// normalizeServiceID lowercases a service ID and drops its separators, so
+// that "Elastic Load Balancing v2", "elastic_load_balancing_v2" and
+// "ELASTIC-LOAD-BALANCING-V2" are the same
+func normalizeServiceID(id string) string {
+	return strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.ToLower(id))
+}
+
+// sortedServices returns the services of endpoints in order
+func sortedServices(endpoints map[string]string) []string {
+	services := make([]string, 0, len(endpoints))
+	for service := range endpoints {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	return services
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+func TestEndpointsValidate(t *testing.T) {
+	valid := []Endpoints{
+		{},
+		{URL: "http://localhost:4566"},
+		{URL: "https://emulator.example.com", Services: map[string]string{"sqs": "http://localhost:9324"}},
+	}
+	for _, e := range valid {
+		if err := e.Validate(); err != nil {
+			t.Errorf("Validate(%+v) returned an error: %v", e, err)
+		}
+	}
+
+	invalid := []Endpoints{
+		{URL: "localhost:4566"},
+		{URL: "ftp://localhost"},
+		{URL: "http://"},
+		{Services: map[string]string{"sqs": "localhost"}},
+	}
+	for _, e := range invalid {
+		if err := e.Validate(); err == nil {
+			t.Errorf("Expected an error validating %+v", e)
+		}
+	}
+}
+
+func TestEndpointsApply(t *testing.T) {
+	// Clients ignore the config sources when only AWS_ENDPOINT_URL is set
+	if value, ok := os.LookupEnv("AWS_ENDPOINT_URL"); ok {
+		os.Unsetenv("AWS_ENDPOINT_URL")
+		t.Cleanup(func() { os.Setenv("AWS_ENDPOINT_URL", value) })
+	}
+	t.Setenv("AWS_ENDPOINT_URL_SQS", "http://localhost:1234")
+
+	awsConfig := aws.Config{Region: "us-east-1"}
+	Endpoints{
+		URL:      "http://localhost:4566",
+		Services: map[string]string{"elastic_load_balancing_v2": "http://localhost:4567"},
+	}.apply(&awsConfig)
+
+	// The flag wins over the environment's endpoint of the service
+	if endpoint := aws.ToString(sqs.NewFromConfig(awsConfig).Options().BaseEndpoint); endpoint != "http://localhost:4566" {
+		t.Errorf("Expected the SQS endpoint http://localhost:4566, got %q", endpoint)
+	}
+	if endpoint := aws.ToString(elasticloadbalancingv2.NewFromConfig(awsConfig).Options().BaseEndpoint); endpoint != "http://localhost:4567" {
+		t.Errorf("Expected the ELBv2 endpoint http://localhost:4567, got %q", endpoint)
+	}
+}
+
+func TestEndpointsApplyEmpty(t *testing.T) {
+	awsConfig := aws.Config{Region: "us-east-1"}
+	Endpoints{}.apply(&awsConfig)
+	if awsConfig.BaseEndpoint != nil || len(awsConfig.ConfigSources) != 0 {
+		t.Errorf("Expected the endpoints of AWS, got %+v", awsConfig)
+	}
+}
+
+func TestLoadAWSConfigEndpointVariants(t *testing.T) {
+	t.Setenv("AWS_USE_FIPS_ENDPOINT", "")
+	t.Setenv("AWS_USE_DUALSTACK_ENDPOINT", "")
+
+	cfg := NewConfig("us-gov-west-1").WithEndpoints(Endpoints{FIPS: true, DualStack: true})
+	awsConfig, err := LoadAWSConfig(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("LoadAWSConfig returned an error: %v", err)
+	}
+	options := sqs.NewFromConfig(awsConfig).Options().EndpointOptions
+	if options.UseFIPSEndpoint != aws.FIPSEndpointStateEnabled || options.UseDualStackEndpoint != aws.DualStackEndpointStateEnabled {
+		t.Errorf("Expected FIPS and dual-stack endpoints, got %+v", options)
+	}
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/correctedcloud/aws-overview/internal/alerts"
+)
+
+// File holds the settings of the config file
+type File struct {
+	// Theme is the name of the color theme, e.g. "light"
+	Theme string `json:"theme,omitempty"`
+
+	// Colors overrides colors of the theme by name with hex values, e.g.
+	// {"accent": "#D33682"}
+	Colors map[string]string `json:"colors,omitempty"`
+
+	// Graphs is the style of the metric graphs, e.g. "braille"
+	Graphs string `json:"graphs,omitempty"`
+
+	// EncryptState encrypts the state files, e.g. "keychain" or
+	// "kms:alias/aws-overview"
+	EncryptState string `json:"encrypt_state,omitempty"`
+
+	// EndpointURL is the endpoint of every AWS service, e.g.
+	// "http://localhost:4566" for LocalStack
+	EndpointURL string `json:"endpoint_url,omitempty"`
+
+	// Endpoints overrides the endpoint of services by service ID, e.g.
+	// {"sqs": "http://localhost:9324"}
+	Endpoints map[string]string `json:"endpoints,omitempty"`
+
+	// UseFIPSEndpoint uses the FIPS endpoints of the AWS services
+	UseFIPSEndpoint bool `json:"use_fips_endpoint,omitempty"`
+
+	// UseDualStackEndpoint uses the dual-stack (IPv6) endpoints of the AWS
+	// services
+	UseDualStackEndpoint bool `json:"use_dualstack_endpoint,omitempty"`
+
+	// Retry is how AWS calls are retried, e.g.
+	// {"max_attempts": 10, "mode": "adaptive"}
+	Retry RetryPolicy `json:"retry"`
+
+	// Alerts are the rules checked after each refresh, ringing the bell
+	// when one starts to be breached
+	Alerts []alerts.Rule `json:"alerts,omitempty"`
+}
+
+// DefaultFilePath returns the default location of the config file
+func DefaultFilePath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "aws-overview", "config.json")
+}
+
+// LoadFile reads the config file at path. It returns empty settings without
+// an error when the file does not exist.
+func LoadFile(path string) (File, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return File{}, nil
+	}
+	if err != nil {
+		return File{}, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return File{}, fmt.Errorf("failed to decode config: %w", err)
+	}
+	if err := (Endpoints{URL: file.EndpointURL, Services: file.Endpoints}).Validate(); err != nil {
+		return File{}, fmt.Errorf("endpoints: %w", err)
+	}
+	if err := file.Retry.Validate(); err != nil {
+		return File{}, fmt.Errorf("retry: %w", err)
+	}
+	for i, rule := range file.Alerts {
+		if err := rule.Validate(); err != nil {
+			return File{}, fmt.Errorf("alert %d: %w", i+1, err)
+		}
+	}
+	return file, nil
+}
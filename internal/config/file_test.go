@@ -0,0 +1,81 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"theme": "light", "colors": {"accent": "#D33682"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile returned an error: %v", err)
+	}
+	if file.Theme != "light" || file.Colors["accent"] != "#D33682" {
+		t.Errorf("Unexpected settings %+v", file)
+	}
+}
+
+func TestLoadFileMissing(t *testing.T) {
+	file, err := LoadFile(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil || file.Theme != "" || file.Colors != nil {
+		t.Errorf("Expected empty settings without an error, got %+v, %v", file, err)
+	}
+}
+
+func TestLoadFileInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`theme = "light"`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFile(path); err == nil {
+		t.Error("Expected an error for a file that is not JSON")
+	}
+}
+
+func TestLoadFileAlerts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"alerts": [{"type": "queue-depth", "resource": "orders", "above": 1000}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	file, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile returned an error: %v", err)
+	}
+	if len(file.Alerts) != 1 || file.Alerts[0].Resource != "orders" || file.Alerts[0].Above != 1000 {
+		t.Errorf("Unexpected alerts %+v", file.Alerts)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"alerts": [{"type": "cpu"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFile(path); err == nil {
+		t.Error("Expected an error for an unknown alert rule type")
+	}
+}
+
+func TestLoadFileEndpoints(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"endpoint_url": "http://localhost:4566", "endpoints": {"s3": "http://localhost:9000"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	file, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile returned an error: %v", err)
+	}
+	if file.EndpointURL != "http://localhost:4566" || file.Endpoints["s3"] != "http://localhost:9000" {
+		t.Errorf("Unexpected endpoints %q, %v", file.EndpointURL, file.Endpoints)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"endpoints": {"s3": "localhost:9000"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFile(path); err == nil {
+		t.Error("Expected an error for an endpoint that is not a URL")
+	}
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go/middleware"
+	"golang.org/x/time/rate"
+)
+
+// DefaultRateLimitKey is the RateLimits key that applies to services without their own limit
+const DefaultRateLimitKey = "default"
+
+// RateLimits maps AWS service names (e.g. "ec2", "cloudwatch") to a maximum
+// number of API requests per second made by this tool
+type RateLimits map[string]float64
+
+// ParseRateLimits parses a comma separated list of service=requests-per-second
+// pairs such as "default=10,ecs=2,cloudwatch=5"
+func ParseRateLimits(value string) (RateLimits, error) {
+	limits := RateLimits{}
+	if strings.TrimSpace(value) == "" {
+		return limits, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		service, rps, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rate limit %q: expected service=requests-per-second", pair)
+		}
+
+		limit, err := strconv.ParseFloat(rps, 64)
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("invalid rate limit for %s: %q is not a positive number", service, rps)
+		}
+
+		limits[strings.ToLower(strings.TrimSpace(service))] = limit
+	}
+
+	return limits, nil
+}
+
+// String formats the limits in the format accepted by ParseRateLimits
+func (r RateLimits) String() string {
+	var pairs []string
+	for service, limit := range r {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", service, strconv.FormatFloat(limit, 'f', -1, 64)))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// Limiters holds one client-side rate limiter per AWS service. Limiters are
+// created on first use and shared by every client of that service, so the
+// budget holds across refreshes and across tabs using the same API.
+type Limiters struct {
+	mu         sync.Mutex
+	limits     RateLimits
+	limiters   map[string]*rate.Limiter
+	instrument []func(service string) func(*middleware.Stack) error
+}
+
+// NewLimiters returns limiters enforcing the given limits
+func NewLimiters(limits RateLimits) *Limiters {
+	return &Limiters{
+		limits:   limits,
+		limiters: make(map[string]*rate.Limiter),
+	}
+}
+
+// Instrument makes Apply also add the API options returned by options for
+// the service, e.g. to record the outcome of every call
+func (l *Limiters) Instrument(options ...func(service string) func(*middleware.Stack) error) {
+	l.instrument = append(l.instrument, options...)
+}
+
+// Apply returns a copy of cfg whose API calls to the given service wait for
+// that service's rate limiter. cfg is returned unchanged when the service
+// has no limit and the limiters are not instrumented.
+func (l *Limiters) Apply(cfg aws.Config, service string) aws.Config {
+	var options []func(*middleware.Stack) error
+	if limiter := l.limiter(service); limiter != nil {
+		options = append(options, rateLimitMiddleware(limiter))
+	}
+	if l != nil {
+		for _, option := range l.instrument {
+			options = append(options, option(service))
+		}
+	}
+	if len(options) == 0 {
+		return cfg
+	}
+
+	// Copy the options so clients of other services are not affected
+	apiOptions := make([]func(*middleware.Stack) error, len(cfg.APIOptions), len(cfg.APIOptions)+len(options))
+	copy(apiOptions, cfg.APIOptions)
+	cfg.APIOptions = append(apiOptions, options...)
+
+	return cfg
+}
+
+// limiter returns the shared limiter for a service, or nil if it is unlimited
+func (l *Limiters) limiter(service string) *rate.Limiter {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limiter, ok := l.limiters[service]; ok {
+		return limiter
+	}
+
+	limit, ok := l.limits[service]
+	if !ok {
+		limit, ok = l.limits[DefaultRateLimitKey]
+	}
+	if !ok {
+		return nil
+	}
+
+	limiter := rate.NewLimiter(rate.Limit(limit), int(math.Max(1, math.Ceil(limit))))
+	l.limiters[service] = limiter
+	return limiter
+}
+
+// rateLimitMiddleware waits for the limiter before every request attempt,
+// including retries
+func rateLimitMiddleware(limiter *rate.Limiter) func(*middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		return stack.Finalize.Add(middleware.FinalizeMiddlewareFunc("ClientRateLimit",
+			func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+				if err := limiter.Wait(ctx); err != nil {
+					return middleware.FinalizeOutput{}, middleware.Metadata{}, fmt.Errorf("rate limit: %w", err)
+				}
+				return next.HandleFinalize(ctx, in)
+			}), middleware.After)
+	}
+}
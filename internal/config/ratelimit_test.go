@@ -0,0 +1,87 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestParseRateLimits(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		expected RateLimits
+		wantErr  bool
+	}{
+		{"empty", "", RateLimits{}, false},
+		{"single", "ecs=2", RateLimits{"ecs": 2}, false},
+		{"multiple", "default=10, CloudWatch=0.5", RateLimits{"default": 10, "cloudwatch": 0.5}, false},
+		{"missing value", "ecs", nil, true},
+		{"not a number", "ecs=fast", nil, true},
+		{"zero", "ecs=0", nil, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			limits, err := ParseRateLimits(tc.value)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseRateLimits() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if len(limits) != len(tc.expected) {
+				t.Fatalf("Expected %d limits, got %d", len(tc.expected), len(limits))
+			}
+			for service, limit := range tc.expected {
+				if limits[service] != limit {
+					t.Errorf("Expected limit %v for %s, got %v", limit, service, limits[service])
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitsString(t *testing.T) {
+	limits := RateLimits{"ecs": 2, "default": 10, "cloudwatch": 0.5}
+	if got := limits.String(); got != "cloudwatch=0.5,default=10,ecs=2" {
+		t.Errorf("Expected sorted limits, got '%s'", got)
+	}
+}
+
+func TestLimitersApply(t *testing.T) {
+	limiters := NewLimiters(RateLimits{"ecs": 2})
+	cfg := aws.Config{}
+
+	// Unlimited services get the config unchanged
+	if got := limiters.Apply(cfg, "sqs"); len(got.APIOptions) != 0 {
+		t.Errorf("Expected no API options for an unlimited service, got %d", len(got.APIOptions))
+	}
+
+	limited := limiters.Apply(cfg, "ecs")
+	if len(limited.APIOptions) != 1 {
+		t.Fatalf("Expected 1 API option for a limited service, got %d", len(limited.APIOptions))
+	}
+	if len(cfg.APIOptions) != 0 {
+		t.Errorf("Expected the original config to be left untouched")
+	}
+
+	// The same limiter is shared by all clients of a service
+	if limiters.limiter("ecs") != limiters.limiter("ecs") {
+		t.Errorf("Expected the ecs limiter to be shared")
+	}
+
+	// A nil Limiters never limits
+	var none *Limiters
+	if got := none.Apply(cfg, "ecs"); len(got.APIOptions) != 0 {
+		t.Errorf("Expected nil limiters to leave the config unchanged")
+	}
+}
+
+func TestLimitersDefault(t *testing.T) {
+	limiters := NewLimiters(RateLimits{DefaultRateLimitKey: 5, "ecs": 1})
+
+	if limiter := limiters.limiter("sqs"); limiter == nil || limiter.Limit() != 5 {
+		t.Errorf("Expected the default limit of 5 for sqs, got %v", limiter)
+	}
+	if limiter := limiters.limiter("ecs"); limiter == nil || limiter.Limit() != 1 {
+		t.Errorf("Expected a limit of 1 for ecs, got %v", limiter)
+	}
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/ratelimit"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+)
+
+// DefaultMaxAttempts is the number of attempts of an AWS call when neither
+// the config file nor the AWS configuration sets it, more than the SDK's 3,
+// which give up too soon when a big account is throttled
+const DefaultMaxAttempts = 5
+
+// RetryPolicy is how AWS calls are retried, as set in the config file
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts of a call, including the first
+	MaxAttempts int `json:"max_attempts,omitempty"`
+
+	// Mode is "standard", or "adaptive" to also slow down the calls of
+	// every service while AWS throttles them
+	Mode string `json:"mode,omitempty"`
+}
+
+// Validate checks the number of attempts and the mode
+func (p RetryPolicy) Validate() error {
+	if p.MaxAttempts < 0 {
+		return fmt.Errorf("max_attempts must be positive, got %d", p.MaxAttempts)
+	}
+	if p.Mode != "" {
+		if _, err := aws.ParseRetryMode(p.Mode); err != nil {
+			return fmt.Errorf("mode must be standard or adaptive, got %q", p.Mode)
+		}
+	}
+	return nil
+}
+
+// Retryer is the retryer shared by the clients of every service, so that
+// the backoff of adaptive mode after throttling holds across all of them
+type Retryer struct {
+	policy RetryPolicy
+
+	once    sync.Once
+	retryer aws.Retryer
+}
+
+// NewRetryer returns a retryer following policy
+func NewRetryer(policy RetryPolicy) *Retryer {
+	return &Retryer{policy: policy}
+}
+
+// apply makes the clients created from awsConfig use the shared retryer.
+// Unset in the policy, the number of attempts and the mode come from the
+// AWS configuration the first time, e.g. AWS_MAX_ATTEMPTS and
+// AWS_RETRY_MODE, and else from the defaults.
+func (r *Retryer) apply(awsConfig *aws.Config) {
+	if r == nil {
+		return
+	}
+
+	r.once.Do(func() {
+		maxAttempts, mode := r.policy.MaxAttempts, aws.RetryMode(r.policy.Mode)
+		if maxAttempts == 0 {
+			maxAttempts = awsConfig.RetryMaxAttempts
+		}
+		if maxAttempts == 0 {
+			maxAttempts = DefaultMaxAttempts
+		}
+		if mode == "" {
+			mode = awsConfig.RetryMode
+		}
+		r.retryer = newRetryer(maxAttempts, mode)
+	})
+
+	retryer := r.retryer
+	awsConfig.Retryer = func() aws.Retryer { return retryer }
+	// Else each client would wrap the retryer with them
+	awsConfig.RetryMaxAttempts, awsConfig.RetryMode = 0, ""
+}
+
+// newRetryer returns a retryer of mode making up to maxAttempts attempts
+func newRetryer(maxAttempts int, mode aws.RetryMode) aws.Retryer {
+	standard := func(o *retry.StandardOptions) {
+		o.MaxAttempts = maxAttempts
+		// Shared by every client, the retry quota would soon stop retrying
+		// throttled calls in a big account
+		o.RateLimiter = ratelimit.None
+	}
+	if mode == aws.RetryModeAdaptive {
+		return retry.NewAdaptiveMode(func(o *retry.AdaptiveModeOptions) {
+			o.StandardOptions = append(o.StandardOptions, standard)
+		})
+	}
+	return retry.NewStandard(standard)
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+func TestRetryPolicyValidate(t *testing.T) {
+	for _, policy := range []RetryPolicy{{}, {MaxAttempts: 10}, {Mode: "standard"}, {MaxAttempts: 8, Mode: "adaptive"}} {
+		if err := policy.Validate(); err != nil {
+			t.Errorf("Validate(%+v) returned an error: %v", policy, err)
+		}
+	}
+	for _, policy := range []RetryPolicy{{MaxAttempts: -1}, {Mode: "legacy"}} {
+		if err := policy.Validate(); err == nil {
+			t.Errorf("Expected an error validating %+v", policy)
+		}
+	}
+}
+
+func TestRetryerShared(t *testing.T) {
+	retryer := NewRetryer(RetryPolicy{MaxAttempts: 10, Mode: "adaptive"})
+
+	first := aws.Config{RetryMaxAttempts: 2}
+	retryer.apply(&first)
+	second := aws.Config{}
+	retryer.apply(&second)
+
+	if first.Retryer() != second.Retryer() {
+		t.Error("Expected the configurations to share the retryer")
+	}
+	if _, ok := first.Retryer().(*retry.AdaptiveMode); !ok {
+		t.Errorf("Expected the adaptive mode, got %T", first.Retryer())
+	}
+	if attempts := first.Retryer().MaxAttempts(); attempts != 10 {
+		t.Errorf("Expected 10 attempts, got %d", attempts)
+	}
+	if first.RetryMaxAttempts != 0 {
+		t.Errorf("Expected the clients not to override the attempts, got %d", first.RetryMaxAttempts)
+	}
+}
+
+func TestRetryerDefaults(t *testing.T) {
+	awsConfig := aws.Config{RetryMaxAttempts: 7}
+	NewRetryer(RetryPolicy{}).apply(&awsConfig)
+	if attempts := awsConfig.Retryer().MaxAttempts(); attempts != 7 {
+		t.Errorf("Expected the 7 attempts of the AWS configuration, got %d", attempts)
+	}
+
+	awsConfig = aws.Config{}
+	NewRetryer(RetryPolicy{}).apply(&awsConfig)
+	if _, ok := awsConfig.Retryer().(*retry.Standard); !ok {
+		t.Errorf("Expected the standard mode, got %T", awsConfig.Retryer())
+	}
+	if attempts := awsConfig.Retryer().MaxAttempts(); attempts != DefaultMaxAttempts {
+		t.Errorf("Expected %d attempts, got %d", DefaultMaxAttempts, attempts)
+	}
+
+	awsConfig = aws.Config{}
+	(*Retryer)(nil).apply(&awsConfig)
+	if awsConfig.Retryer != nil {
+		t.Error("Expected a nil retryer to keep the retryer of the SDK")
+	}
+}
+
+func TestRetryerOwnsThrottleRetries(t *testing.T) {
+	// AWS throttles every attempt
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"__type":"com.amazonaws.sqs#ThrottlingException","message":"Rate exceeded"}`))
+	}))
+	defer server.Close()
+
+	awsConfig := aws.Config{
+		Region:      "us-east-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKID", "secret", ""),
+	}
+	NewRetryer(RetryPolicy{}).apply(&awsConfig)
+	client := sqs.NewFromConfig(awsConfig, func(o *sqs.Options) {
+		o.BaseEndpoint = aws.String(server.URL)
+		o.Retryer = retry.AddWithMaxBackoffDelay(o.Retryer, time.Millisecond)
+	})
+
+	// The pool of the collectors leaves the retries to the retryer
+	pool := common.NewPool(1).WithoutRetries()
+	err := pool.Do(context.Background(), func() error {
+		_, err := client.ListQueues(context.Background(), &sqs.ListQueuesInput{})
+		return err
+	})
+	if !common.IsThrottlingError(err) {
+		t.Errorf("Expected a throttling error, got %v", err)
+	}
+	if attempts != DefaultMaxAttempts {
+		t.Errorf("Expected %d attempts, got %d", DefaultMaxAttempts, attempts)
+	}
+}
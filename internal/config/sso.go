@@ -0,0 +1,223 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc"
+	ssooidctypes "github.com/aws/aws-sdk-go-v2/service/ssooidc/types"
+)
+
+// ssoScopes are the scopes requested for sso-session profiles, whose tokens
+// the SDK refreshes without signing in again
+var ssoScopes = []string{"sso:account:access"}
+
+// ssoClientName is the name the overview registers with IAM Identity Center
+const ssoClientName = "aws-overview"
+
+// deviceCodeGrant is the grant type of tokens created by a device authorization
+const deviceCodeGrant = "urn:ietf:params:oauth:grant-type:device_code"
+
+// ssoOIDCClientAPI is the subset of the SSO OIDC client used to sign in
+type ssoOIDCClientAPI interface {
+	RegisterClient(ctx context.Context, params *ssooidc.RegisterClientInput, optFns ...func(*ssooidc.Options)) (*ssooidc.RegisterClientOutput, error)
+	StartDeviceAuthorization(ctx context.Context, params *ssooidc.StartDeviceAuthorizationInput, optFns ...func(*ssooidc.Options)) (*ssooidc.StartDeviceAuthorizationOutput, error)
+	CreateToken(ctx context.Context, params *ssooidc.CreateTokenInput, optFns ...func(*ssooidc.Options)) (*ssooidc.CreateTokenOutput, error)
+}
+
+// SSOProfile is the IAM Identity Center (SSO) sign-in of a profile
+type SSOProfile struct {
+	Session  string // Name of the sso-session section, empty for legacy profiles
+	StartURL string
+	Region   string
+}
+
+// LoadSSOProfile returns the SSO sign-in of the profile, "" for the default
+// one, or of the profile it assumes a role from. It returns false when the
+// profile does not sign in with SSO.
+func LoadSSOProfile(ctx context.Context, profile string) (SSOProfile, bool, error) {
+	if profile == "" {
+		profile = "default"
+	}
+	shared, err := config.LoadSharedConfigProfile(ctx, profile, func(o *config.LoadSharedConfigOptions) {
+		// Like the configuration, honor the files named by the environment
+		if file := os.Getenv("AWS_CONFIG_FILE"); file != "" {
+			o.ConfigFiles = []string{file}
+		}
+		if file := os.Getenv("AWS_SHARED_CREDENTIALS_FILE"); file != "" {
+			o.CredentialsFiles = []string{file}
+		}
+	})
+	if err != nil {
+		return SSOProfile{}, false, err
+	}
+
+	for c := &shared; c != nil; c = c.Source {
+		switch {
+		case c.SSOSession != nil:
+			return SSOProfile{Session: c.SSOSession.Name, StartURL: c.SSOSession.SSOStartURL, Region: c.SSOSession.SSORegion}, true, nil
+		case c.SSOStartURL != "":
+			return SSOProfile{StartURL: c.SSOStartURL, Region: c.SSORegion}, true, nil
+		}
+	}
+	return SSOProfile{}, false, nil
+}
+
+// cacheKey returns the key the token of the profile is cached under
+func (p SSOProfile) cacheKey() string {
+	if p.Session != "" {
+		return p.Session
+	}
+	return p.StartURL
+}
+
+// DeviceAuthorization is a sign-in the user approves in a browser, after
+// which its token is cached where the SDK and the AWS CLI look for it
+type DeviceAuthorization struct {
+	VerificationURI string // Includes the user code
+	UserCode        string
+	Expires         time.Time
+
+	client       ssoOIDCClientAPI
+	profile      SSOProfile
+	clientID     string
+	clientSecret string
+	clientExpiry time.Time
+	deviceCode   string
+	interval     time.Duration
+}
+
+// StartDeviceAuthorization registers the overview with IAM Identity Center
+// and starts a sign-in with the profile
+func StartDeviceAuthorization(ctx context.Context, profile SSOProfile) (*DeviceAuthorization, error) {
+	client := ssooidc.NewFromConfig(aws.Config{Region: profile.Region})
+	return startDeviceAuthorization(ctx, client, profile)
+}
+
+// startDeviceAuthorization starts a sign-in with the profile using client
+func startDeviceAuthorization(ctx context.Context, client ssoOIDCClientAPI, profile SSOProfile) (*DeviceAuthorization, error) {
+	input := &ssooidc.RegisterClientInput{
+		ClientName: aws.String(ssoClientName),
+		ClientType: aws.String("public"),
+	}
+	if profile.Session != "" {
+		input.Scopes = ssoScopes
+	}
+	registration, err := client.RegisterClient(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register with IAM Identity Center: %w", err)
+	}
+
+	authorization, err := client.StartDeviceAuthorization(ctx, &ssooidc.StartDeviceAuthorizationInput{
+		ClientId:     registration.ClientId,
+		ClientSecret: registration.ClientSecret,
+		StartUrl:     aws.String(profile.StartURL),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start the sign-in: %w", err)
+	}
+
+	interval := time.Duration(authorization.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	return &DeviceAuthorization{
+		VerificationURI: aws.ToString(authorization.VerificationUriComplete),
+		UserCode:        aws.ToString(authorization.UserCode),
+		Expires:         time.Now().Add(time.Duration(authorization.ExpiresIn) * time.Second),
+		client:          client,
+		profile:         profile,
+		clientID:        aws.ToString(registration.ClientId),
+		clientSecret:    aws.ToString(registration.ClientSecret),
+		clientExpiry:    time.Unix(registration.ClientSecretExpiresAt, 0),
+		deviceCode:      aws.ToString(authorization.DeviceCode),
+		interval:        interval,
+	}, nil
+}
+
+// Wait polls until the user approved the sign-in, then caches its token. It
+// fails once the sign-in expires or is denied.
+func (d *DeviceAuthorization) Wait(ctx context.Context) error {
+	ctx, cancel := context.WithDeadline(ctx, d.Expires)
+	defer cancel()
+
+	interval := d.interval
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("the sign-in was not approved in time: %w", ctx.Err())
+		case <-time.After(interval):
+		}
+
+		token, err := d.client.CreateToken(ctx, &ssooidc.CreateTokenInput{
+			ClientId:     aws.String(d.clientID),
+			ClientSecret: aws.String(d.clientSecret),
+			DeviceCode:   aws.String(d.deviceCode),
+			GrantType:    aws.String(deviceCodeGrant),
+		})
+		var pending *ssooidctypes.AuthorizationPendingException
+		var slowDown *ssooidctypes.SlowDownException
+		switch {
+		case errors.As(err, &pending):
+			continue
+		case errors.As(err, &slowDown):
+			interval += 5 * time.Second
+			continue
+		case err != nil:
+			return fmt.Errorf("failed to sign in: %w", err)
+		}
+
+		return d.cacheToken(token)
+	}
+}
+
+// cachedToken is the SSO token as the SDK and the AWS CLI cache it
+type cachedToken struct {
+	AccessToken           string `json:"accessToken"`
+	ExpiresAt             string `json:"expiresAt"`
+	RefreshToken          string `json:"refreshToken,omitempty"`
+	ClientID              string `json:"clientId,omitempty"`
+	ClientSecret          string `json:"clientSecret,omitempty"`
+	RegistrationExpiresAt string `json:"registrationExpiresAt,omitempty"`
+	Region                string `json:"region,omitempty"`
+	StartURL              string `json:"startUrl,omitempty"`
+}
+
+// cacheToken writes the token of the approved sign-in to the SSO cache
+func (d *DeviceAuthorization) cacheToken(token *ssooidc.CreateTokenOutput) error {
+	path, err := ssocreds.StandardCachedTokenFilepath(d.profile.cacheKey())
+	if err != nil {
+		return err
+	}
+
+	cached := cachedToken{
+		AccessToken: aws.ToString(token.AccessToken),
+		ExpiresAt:   time.Now().Add(time.Duration(token.ExpiresIn) * time.Second).UTC().Format(time.RFC3339),
+		Region:      d.profile.Region,
+		StartURL:    d.profile.StartURL,
+	}
+	// Only sso-session profiles refresh their token, which needs the client
+	if d.profile.Session != "" {
+		cached.RefreshToken = aws.ToString(token.RefreshToken)
+		cached.ClientID = d.clientID
+		cached.ClientSecret = d.clientSecret
+		cached.RegistrationExpiresAt = d.clientExpiry.UTC().Format(time.RFC3339)
+	}
+
+	data, err := json.MarshalIndent(cached, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
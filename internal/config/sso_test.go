@@ -0,0 +1,145 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc"
+	ssooidctypes "github.com/aws/aws-sdk-go-v2/service/ssooidc/types"
+)
+
+func TestLoadSSOProfile(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config")
+	err := os.WriteFile(configFile, []byte(`[profile prod]
+sso_session = corp
+sso_account_id = 123456789012
+sso_role_name = ReadOnly
+
+[sso-session corp]
+sso_start_url = https://corp.awsapps.com/start
+sso_region = eu-west-1
+
+[profile legacy]
+sso_start_url = https://legacy.awsapps.com/start
+sso_region = us-east-1
+sso_account_id = 123456789012
+sso_role_name = ReadOnly
+
+[profile admin]
+role_arn = arn:aws:iam::123456789012:role/Admin
+source_profile = prod
+
+[profile keys]
+aws_access_key_id = AKIAEXAMPLE
+aws_secret_access_key = secret
+`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AWS_CONFIG_FILE", configFile)
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+
+	testCases := []struct {
+		profile  string
+		expected SSOProfile
+		ok       bool
+	}{
+		{"prod", SSOProfile{Session: "corp", StartURL: "https://corp.awsapps.com/start", Region: "eu-west-1"}, true},
+		{"legacy", SSOProfile{StartURL: "https://legacy.awsapps.com/start", Region: "us-east-1"}, true},
+		{"admin", SSOProfile{Session: "corp", StartURL: "https://corp.awsapps.com/start", Region: "eu-west-1"}, true},
+		{"keys", SSOProfile{}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.profile, func(t *testing.T) {
+			profile, ok, err := LoadSSOProfile(context.Background(), tc.profile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != tc.ok || profile != tc.expected {
+				t.Errorf("Expected %+v (%v), got %+v (%v)", tc.expected, tc.ok, profile, ok)
+			}
+		})
+	}
+}
+
+// mockSSOOIDCClient approves the sign-in after a number of polls
+type mockSSOOIDCClient struct {
+	pending int
+	scopes  []string
+}
+
+func (m *mockSSOOIDCClient) RegisterClient(ctx context.Context, params *ssooidc.RegisterClientInput, optFns ...func(*ssooidc.Options)) (*ssooidc.RegisterClientOutput, error) {
+	m.scopes = params.Scopes
+	return &ssooidc.RegisterClientOutput{
+		ClientId:              aws.String("client"),
+		ClientSecret:          aws.String("secret"),
+		ClientSecretExpiresAt: time.Now().Add(90 * 24 * time.Hour).Unix(),
+	}, nil
+}
+
+func (m *mockSSOOIDCClient) StartDeviceAuthorization(ctx context.Context, params *ssooidc.StartDeviceAuthorizationInput, optFns ...func(*ssooidc.Options)) (*ssooidc.StartDeviceAuthorizationOutput, error) {
+	return &ssooidc.StartDeviceAuthorizationOutput{
+		DeviceCode:              aws.String("device"),
+		UserCode:                aws.String("ABCD-EFGH"),
+		VerificationUriComplete: aws.String("https://device.sso.eu-west-1.amazonaws.com/?user_code=ABCD-EFGH"),
+		ExpiresIn:               600,
+		Interval:                1,
+	}, nil
+}
+
+func (m *mockSSOOIDCClient) CreateToken(ctx context.Context, params *ssooidc.CreateTokenInput, optFns ...func(*ssooidc.Options)) (*ssooidc.CreateTokenOutput, error) {
+	if m.pending > 0 {
+		m.pending--
+		return nil, &ssooidctypes.AuthorizationPendingException{}
+	}
+	return &ssooidc.CreateTokenOutput{
+		AccessToken:  aws.String("token"),
+		RefreshToken: aws.String("refresh"),
+		ExpiresIn:    3600,
+	}, nil
+}
+
+func TestDeviceAuthorization(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	profile := SSOProfile{Session: "corp", StartURL: "https://corp.awsapps.com/start", Region: "eu-west-1"}
+	client := &mockSSOOIDCClient{pending: 2}
+
+	authorization, err := startDeviceAuthorization(context.Background(), client, profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authorization.UserCode != "ABCD-EFGH" || authorization.interval != time.Second || len(client.scopes) != 1 {
+		t.Errorf("Unexpected authorization %+v with scopes %v", authorization, client.scopes)
+	}
+
+	authorization.interval = time.Millisecond
+	if err := authorization.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := ssocreds.StandardCachedTokenFilepath("corp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cached cachedToken
+	if err := json.Unmarshal(data, &cached); err != nil {
+		t.Fatal(err)
+	}
+	if cached.AccessToken != "token" || cached.RefreshToken != "refresh" || cached.ClientID != "client" || cached.Region != "eu-west-1" {
+		t.Errorf("Unexpected cached token %+v", cached)
+	}
+	if expiresAt, err := time.Parse(time.RFC3339, cached.ExpiresAt); err != nil || time.Until(expiresAt) < 50*time.Minute {
+		t.Errorf("Expected the token to expire in an hour, got %s", cached.ExpiresAt)
+	}
+}
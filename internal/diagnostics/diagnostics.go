@@ -0,0 +1,244 @@
+// Package diagnostics keeps the tool's own runtime statistics: how long the
+// refresh of each service takes and how many of its AWS calls fail, next to
+// the goroutines and heap of the process. They help tell a slow account from
+// a leak in long-running deployments.
+package diagnostics
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/smithy-go/middleware"
+
+	"github.com/correctedcloud/aws-overview/internal/permissions"
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// Stats collects the statistics of a running overview. It is safe for
+// concurrent use, and a nil *Stats records nothing.
+type Stats struct {
+	started time.Time
+
+	mu        sync.Mutex
+	refreshes map[string]*RefreshStats
+	calls     map[string]*CallStats
+	expiredAt time.Time // When a call last failed on expired credentials
+}
+
+// RefreshStats describes the refreshes of a service
+type RefreshStats struct {
+	Count     int
+	Last      time.Duration
+	LastCalls int // AWS API calls made by the last refresh
+	Max       time.Duration
+	Total     time.Duration
+}
+
+// Average returns the mean duration of the refreshes
+func (r RefreshStats) Average() time.Duration {
+	if r.Count == 0 {
+		return 0
+	}
+	return r.Total / time.Duration(r.Count)
+}
+
+// CallStats counts the AWS API calls made to a service. A call counts once
+// however many times the SDK retried it.
+type CallStats struct {
+	Calls     int
+	Errors    int
+	Throttled int // Errors due to rate limiting, also counted in Errors
+}
+
+// ErrorRate returns the share of failed calls, from 0 to 1
+func (c CallStats) ErrorRate() float64 {
+	if c.Calls == 0 {
+		return 0
+	}
+	return float64(c.Errors) / float64(c.Calls)
+}
+
+// Snapshot is a copy of the statistics at a point in time
+type Snapshot struct {
+	Uptime     time.Duration
+	Goroutines int
+	HeapAlloc  uint64 // Bytes of allocated heap objects
+	HeapSys    uint64 // Bytes of heap memory obtained from the OS
+	NumGC      uint32
+	Refreshes  map[string]RefreshStats // By service
+	Calls      map[string]CallStats    // By AWS service, e.g. "cloudwatch"
+}
+
+// New returns empty statistics of a process starting now
+func New() *Stats {
+	return &Stats{
+		started:   time.Now(),
+		refreshes: make(map[string]*RefreshStats),
+		calls:     make(map[string]*CallStats),
+	}
+}
+
+// RecordRefresh records that loading the data of a service took d and
+// calls AWS API calls
+func (s *Stats) RecordRefresh(service string, d time.Duration, calls int) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	refresh, ok := s.refreshes[service]
+	if !ok {
+		refresh = &RefreshStats{}
+		s.refreshes[service] = refresh
+	}
+	refresh.Count++
+	refresh.Last = d
+	refresh.LastCalls = calls
+	refresh.Max = max(refresh.Max, d)
+	refresh.Total += d
+}
+
+// RecordCall records the outcome of an AWS API call to a service
+func (s *Stats) RecordCall(service string, err error) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	call, ok := s.calls[service]
+	if !ok {
+		call = &CallStats{}
+		s.calls[service] = call
+	}
+	call.Calls++
+	if err != nil {
+		call.Errors++
+		if common.IsThrottlingError(err) {
+			call.Throttled++
+		}
+		if permissions.IsExpired(err) {
+			s.expiredAt = time.Now()
+		}
+	}
+}
+
+// ExpiredAt returns when a call last failed because the credentials had
+// expired, or the zero time when none did
+func (s *Stats) ExpiredAt() time.Time {
+	if s == nil {
+		return time.Time{}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.expiredAt
+}
+
+// TotalCalls returns the number of AWS API calls made to all services
+func (s *Stats) TotalCalls() int {
+	if s == nil {
+		return 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	total := 0
+	for _, call := range s.calls {
+		total += call.Calls
+	}
+	return total
+}
+
+// Refreshes returns the statistics of the refreshes by service, without
+// the cost of reading those of the Go runtime as Snapshot does
+func (s *Stats) Refreshes() map[string]RefreshStats {
+	refreshes := make(map[string]RefreshStats)
+	if s == nil {
+		return refreshes
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for service, refresh := range s.refreshes {
+		refreshes[service] = *refresh
+	}
+	return refreshes
+}
+
+// Snapshot returns the current statistics, including those of the Go runtime
+func (s *Stats) Snapshot() Snapshot {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	snapshot := Snapshot{
+		Goroutines: runtime.NumGoroutine(),
+		HeapAlloc:  memStats.HeapAlloc,
+		HeapSys:    memStats.HeapSys,
+		NumGC:      memStats.NumGC,
+		Refreshes:  make(map[string]RefreshStats),
+		Calls:      make(map[string]CallStats),
+	}
+	if s == nil {
+		return snapshot
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot.Uptime = time.Since(s.started)
+	for service, refresh := range s.refreshes {
+		snapshot.Refreshes[service] = *refresh
+	}
+	for service, call := range s.calls {
+		snapshot.Calls[service] = *call
+	}
+	return snapshot
+}
+
+// callCounterKey is the context key of the counter of the calls made with
+// a context, see CountCalls
+type callCounterKey struct{}
+
+// CountCalls returns a context whose AWS calls are counted by the clients
+// instrumented with CallMiddleware, e.g. for the refresh of a service, and a
+// function returning how many were made so far
+func CountCalls(ctx context.Context) (context.Context, func() int) {
+	counter := new(atomic.Int64)
+	return context.WithValue(ctx, callCounterKey{}, counter), func() int { return int(counter.Load()) }
+}
+
+// CallMiddleware returns an API option recording the outcome of every call a
+// client of the given AWS service makes
+func (s *Stats) CallMiddleware(service string) func(*middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("Diagnostics",
+			func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+				out, metadata, err := next.HandleInitialize(ctx, in)
+				s.RecordCall(service, err)
+				if counter, ok := ctx.Value(callCounterKey{}).(*atomic.Int64); ok {
+					counter.Add(1)
+				}
+				return out, metadata, err
+			}), middleware.Before)
+	}
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,118 @@
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+)
+
+func TestStats(t *testing.T) {
+	stats := New()
+	stats.RecordRefresh("ec2", 2*time.Second, 10)
+	stats.RecordRefresh("ec2", 4*time.Second, 12)
+	stats.RecordCall("ec2", nil)
+	stats.RecordCall("ec2", errors.New("access denied"))
+	stats.RecordCall("ec2", &smithy.GenericAPIError{Code: "Throttling"})
+	stats.RecordCall("ec2", nil)
+
+	snapshot := stats.Snapshot()
+	if snapshot.Goroutines == 0 || snapshot.HeapAlloc == 0 {
+		t.Errorf("Expected runtime statistics, got %+v", snapshot)
+	}
+
+	refresh := snapshot.Refreshes["ec2"]
+	if refresh.Count != 2 || refresh.Last != 4*time.Second || refresh.LastCalls != 12 || refresh.Max != 4*time.Second || refresh.Average() != 3*time.Second {
+		t.Errorf("Unexpected refresh statistics %+v", refresh)
+	}
+	if refreshes := stats.Refreshes(); refreshes["ec2"] != refresh {
+		t.Errorf("Expected the refreshes of the snapshot, got %+v", refreshes)
+	}
+
+	call := snapshot.Calls["ec2"]
+	if call.Calls != 4 || call.Errors != 2 || call.Throttled != 1 || call.ErrorRate() != 0.5 {
+		t.Errorf("Unexpected call statistics %+v", call)
+	}
+
+	stats.RecordCall("sqs", nil)
+	if total := stats.TotalCalls(); total != 5 {
+		t.Errorf("Expected 5 calls in total, got %d", total)
+	}
+
+	if !stats.ExpiredAt().IsZero() {
+		t.Errorf("Expected no call to fail on expired credentials, got %v", stats.ExpiredAt())
+	}
+	stats.RecordCall("sqs", &smithy.GenericAPIError{Code: "ExpiredToken"})
+	if stats.ExpiredAt().IsZero() {
+		t.Error("Expected the call failing on expired credentials to be recorded")
+	}
+}
+
+func TestNilStats(t *testing.T) {
+	var stats *Stats
+	stats.RecordRefresh("ec2", time.Second, 1)
+	stats.RecordCall("ec2", nil)
+	if stats.TotalCalls() != 0 {
+		t.Errorf("Expected no calls, got %d", stats.TotalCalls())
+	}
+	if snapshot := stats.Snapshot(); len(snapshot.Refreshes) != 0 || snapshot.Goroutines == 0 {
+		t.Errorf("Expected only runtime statistics, got %+v", snapshot)
+	}
+}
+
+func TestCallMiddleware(t *testing.T) {
+	stats := New()
+	stack := middleware.NewStack("test", func() interface{} { return nil })
+	if err := stats.CallMiddleware("sqs")(stack); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := middleware.DecorateHandler(middleware.HandlerFunc(func(ctx context.Context, in interface{}) (interface{}, middleware.Metadata, error) {
+		return nil, middleware.Metadata{}, errors.New("denied")
+	}), stack)
+	handler.Handle(context.Background(), nil)
+
+	if call := stats.Snapshot().Calls["sqs"]; call.Calls != 1 || call.Errors != 1 {
+		t.Errorf("Expected one failed call, got %+v", call)
+	}
+
+	// The calls of a refresh are counted on their own
+	ctx, calls := CountCalls(context.Background())
+	handler.Handle(ctx, nil)
+	handler.Handle(ctx, nil)
+	if calls() != 2 || stats.TotalCalls() != 3 {
+		t.Errorf("Expected 2 calls of the refresh and 3 in total, got %d and %d", calls(), stats.TotalCalls())
+	}
+}
+
+func TestFormat(t *testing.T) {
+	output := Format(Snapshot{
+		Uptime:     90 * time.Minute,
+		Goroutines: 12,
+		HeapAlloc:  3 * 1024 * 1024,
+		HeapSys:    8 * 1024 * 1024,
+		NumGC:      7,
+		Refreshes:  map[string]RefreshStats{"rds": {Count: 2, Last: 1500 * time.Millisecond, LastCalls: 38, Max: 2 * time.Second, Total: 3500 * time.Millisecond}},
+		Calls:      map[string]CallStats{"cloudwatch": {Calls: 40, Errors: 2, Throttled: 2}},
+	})
+
+	for _, expected := range []string{
+		"Uptime:     1h30m0s\n",
+		"Goroutines: 12\n",
+		"Heap:       3.0 MiB in use, 8.0 MiB reserved, 7 GC cycles\n",
+		"  rds               2     1.5s         38     1.8s       2s\n",
+		"  cloudwatch                   40      2         2   5.0%\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain '%s', got:\n%s", expected, output)
+		}
+	}
+
+	if output := Format(Snapshot{}); !strings.Contains(output, "No refreshes yet") || !strings.Contains(output, "No calls yet") {
+		t.Errorf("Expected empty sections, got:\n%s", output)
+	}
+}
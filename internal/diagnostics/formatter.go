@@ -0,0 +1,69 @@
+package diagnostics
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// Format formats a snapshot of the statistics for terminal display
+func Format(snapshot Snapshot) string {
+	var output strings.Builder
+	output.WriteString("DIAGNOSTICS\n")
+	output.WriteString(common.Rule("DIAGNOSTICS", "=") + "\n\n")
+
+	output.WriteString(fmt.Sprintf("Uptime:     %s\n", snapshot.Uptime.Round(time.Second)))
+	output.WriteString(fmt.Sprintf("Goroutines: %d\n", snapshot.Goroutines))
+	output.WriteString(fmt.Sprintf("Heap:       %s in use, %s reserved, %d GC cycles\n",
+		formatBytes(snapshot.HeapAlloc), formatBytes(snapshot.HeapSys), snapshot.NumGC))
+
+	output.WriteString("\nREFRESHES\n")
+	if len(snapshot.Refreshes) == 0 {
+		output.WriteString("  No refreshes yet\n")
+	} else {
+		output.WriteString(fmt.Sprintf("  %-12s %6s %8s %10s %8s %8s\n", "Service", "Count", "Last", "Last calls", "Average", "Max"))
+		for _, service := range sortedKeys(snapshot.Refreshes) {
+			refresh := snapshot.Refreshes[service]
+			output.WriteString(fmt.Sprintf("  %-12s %6d %8s %10d %8s %8s\n", service, refresh.Count,
+				FormatDuration(refresh.Last), refresh.LastCalls, FormatDuration(refresh.Average()), FormatDuration(refresh.Max)))
+		}
+	}
+
+	output.WriteString("\nAWS API CALLS\n")
+	if len(snapshot.Calls) == 0 {
+		output.WriteString("  No calls yet\n")
+	} else {
+		output.WriteString(fmt.Sprintf("  %-24s %6s %6s %9s %6s\n", "Service", "Calls", "Errors", "Throttled", "Rate"))
+		for _, service := range sortedKeys(snapshot.Calls) {
+			call := snapshot.Calls[service]
+			output.WriteString(fmt.Sprintf("  %-24s %6d %6d %9d %6s\n", service, call.Calls, call.Errors, call.Throttled,
+				common.FormatFloatWithPrecision(call.ErrorRate()*100, 1)+"%"))
+		}
+	}
+
+	return output.String()
+}
+
+// FormatDuration rounds a refresh duration for display
+func FormatDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
+
+// formatBytes formats a size in bytes with a binary unit
+func formatBytes(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	value, exponent := float64(bytes)/unit, 0
+	for value >= unit && exponent < 3 {
+		value /= unit
+		exponent++
+	}
+	return fmt.Sprintf("%.1f %ciB", value, "KMGT"[exponent])
+}
@@ -0,0 +1,325 @@
+// Package diff compares two snapshots of the resources, e.g. taken before
+// and after a deployment, and reports the resources added and removed, the
+// changes of their state and how many there are of each type.
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/correctedcloud/aws-overview/internal/session"
+	"github.com/correctedcloud/aws-overview/pkg/alb"
+	"github.com/correctedcloud/aws-overview/pkg/apigateway"
+	"github.com/correctedcloud/aws-overview/pkg/cloudfront"
+	"github.com/correctedcloud/aws-overview/pkg/dns"
+	"github.com/correctedcloud/aws-overview/pkg/dr"
+	"github.com/correctedcloud/aws-overview/pkg/ebs"
+	"github.com/correctedcloud/aws-overview/pkg/ec2"
+	"github.com/correctedcloud/aws-overview/pkg/ecr"
+	"github.com/correctedcloud/aws-overview/pkg/ecs"
+	"github.com/correctedcloud/aws-overview/pkg/findings"
+	"github.com/correctedcloud/aws-overview/pkg/hygiene"
+	"github.com/correctedcloud/aws-overview/pkg/lambda"
+	"github.com/correctedcloud/aws-overview/pkg/rds"
+	"github.com/correctedcloud/aws-overview/pkg/sns"
+	"github.com/correctedcloud/aws-overview/pkg/sqs"
+	"github.com/correctedcloud/aws-overview/pkg/ssm"
+	"github.com/correctedcloud/aws-overview/pkg/vpc"
+)
+
+// Diff holds the differences between two snapshots, a type each for the
+// resource types either of them has resources of
+type Diff struct {
+	Before, After time.Time // When the snapshots were saved
+	Types         []Type
+}
+
+// Changed reports whether any resource was added, removed or changed
+func (d Diff) Changed() bool {
+	for _, t := range d.Types {
+		if t.Changed() {
+			return true
+		}
+	}
+	return false
+}
+
+// Type holds the differences of the resources of one type
+type Type struct {
+	Title         string // e.g. "RDS instances"
+	Before, After int    // Number of resources in each snapshot
+	Added         []string
+	Removed       []string
+	Changes       []Change
+}
+
+// Changed reports whether any resource of the type was added, removed or
+// changed
+func (t Type) Changed() bool {
+	return len(t.Added) > 0 || len(t.Removed) > 0 || len(t.Changes) > 0
+}
+
+// Change is a change of an attribute of a resource in both snapshots
+type Change struct {
+	Resource      string
+	Attribute     string // e.g. "status"
+	Before, After string
+}
+
+// attribute is a part of the state of a resource compared between snapshots
+type attribute struct {
+	name  string
+	value string
+}
+
+// item is a resource as compared between snapshots
+type item struct {
+	key        string // Identifies the resource in both snapshots
+	label      string
+	attributes []attribute
+}
+
+// kind lists the resources of one type of a snapshot
+type kind struct {
+	title string
+	items func(session.Snapshot) []item
+}
+
+// itemsOf returns the items of resources, identified by key and labelled by
+// label, with the attributes returned by state
+func itemsOf[T any](resources []T, key, label func(T) string, state func(T) []attribute) []item {
+	items := make([]item, len(resources))
+	for i, resource := range resources {
+		items[i] = item{key: key(resource), label: label(resource)}
+		if state != nil {
+			items[i].attributes = state(resource)
+		}
+	}
+	return items
+}
+
+// kinds are the compared resource types, in the order of the snapshot.
+// Metrics are left out: they differ between any two snapshots, and the
+// graphs show them better.
+var kinds = []kind{
+	{"Load balancers", func(s session.Snapshot) []item {
+		return itemsOf(s.LoadBalancers, func(lb alb.LoadBalancerSummary) string { return lb.ARN },
+			func(lb alb.LoadBalancerSummary) string { return lb.Name }, func(lb alb.LoadBalancerSummary) []attribute {
+				return []attribute{{"scheme", lb.Scheme}, {"target groups", strconv.Itoa(len(lb.TargetGroups))}}
+			})
+	}},
+	{"RDS instances", func(s session.Snapshot) []item {
+		return itemsOf(s.DBInstances, func(i rds.DBInstanceSummary) string { return i.Identifier },
+			func(i rds.DBInstanceSummary) string { return i.Identifier }, func(i rds.DBInstanceSummary) []attribute {
+				return []attribute{{"status", i.Status}, {"multi-AZ", strconv.FormatBool(i.MultiAZ)}, {"storage", fmt.Sprintf("%d GB", i.AllocatedStorageGB)}}
+			})
+	}},
+	{"EC2 instances", func(s session.Snapshot) []item {
+		return itemsOf(s.EC2Instances, func(i ec2.InstanceSummary) string { return i.InstanceID },
+			func(i ec2.InstanceSummary) string { return nameAndID(i.Name, i.InstanceID) }, func(i ec2.InstanceSummary) []attribute {
+				return []attribute{{"state", i.State}, {"type", i.InstanceType}}
+			})
+	}},
+	{"ECS services", func(s session.Snapshot) []item {
+		return itemsOf(s.ECSServices, func(svc ecs.ServiceSummary) string { return svc.ClusterName + "/" + svc.ServiceName },
+			func(svc ecs.ServiceSummary) string { return svc.ClusterName + "/" + svc.ServiceName }, func(svc ecs.ServiceSummary) []attribute {
+				return []attribute{
+					{"status", svc.Status},
+					{"task definition", svc.TaskDefinition},
+					{"tasks", fmt.Sprintf("%d/%d running", svc.RunningCount, svc.DesiredCount)},
+					{"health", svc.HealthStatus},
+				}
+			})
+	}},
+	{"ECS scheduled tasks", func(s session.Snapshot) []item {
+		return itemsOf(s.ECSScheduledTasks, func(t ecs.ScheduledTask) string { return t.ClusterName + "/" + t.RuleName },
+			func(t ecs.ScheduledTask) string { return t.ClusterName + "/" + t.RuleName }, func(t ecs.ScheduledTask) []attribute {
+				return []attribute{{"state", t.State}, {"task definition", t.TaskDefinition}, {"schedule", t.ScheduleExpression}}
+			})
+	}},
+	{"SQS queues", func(s session.Snapshot) []item {
+		return itemsOf(s.SQSQueues, func(q sqs.QueueSummary) string { return q.Name },
+			func(q sqs.QueueSummary) string { return q.Name }, func(q sqs.QueueSummary) []attribute {
+				return []attribute{{"dead-letter queue", q.DeadLetterQueue}}
+			})
+	}},
+	{"SSM managed instances", func(s session.Snapshot) []item {
+		return itemsOf(s.SSMInstances, func(i ssm.InstanceSummary) string { return i.InstanceID },
+			func(i ssm.InstanceSummary) string { return nameAndID(i.Name, i.InstanceID) }, func(i ssm.InstanceSummary) []attribute {
+				return []attribute{{"ping status", i.PingStatus}, {"agent", i.AgentVersion}, {"missing patches", strconv.Itoa(int(i.MissingPatches))}}
+			})
+	}},
+	{"DNS records", func(s session.Snapshot) []item {
+		return itemsOf(s.DNSRecords, func(r dns.RecordSummary) string { return r.Zone + " " + r.Name + " " + r.Type },
+			func(r dns.RecordSummary) string { return r.Name + " " + r.Type }, func(r dns.RecordSummary) []attribute {
+				return []attribute{{"target", r.Target}, {"status", r.Status}}
+			})
+	}},
+	{"DR readiness", func(s session.Snapshot) []item {
+		return itemsOf(s.DRResources, func(r dr.ResourceSummary) string { return r.Service + " " + r.Name },
+			func(r dr.ResourceSummary) string { return r.Service + " " + r.Name }, func(r dr.ResourceSummary) []attribute {
+				return []attribute{{"status", r.Status}, {"regions", strings.Join(r.Regions, ", ")}}
+			})
+	}},
+	{"SNS topics", func(s session.Snapshot) []item {
+		return itemsOf(s.SNSTopics, func(t sns.TopicSummary) string { return t.Name },
+			func(t sns.TopicSummary) string { return t.Name }, func(t sns.TopicSummary) []attribute {
+				return []attribute{{"subscriptions", strconv.Itoa(len(t.Subscriptions) + t.OtherSubscriptions)}}
+			})
+	}},
+	{"Lambda functions", func(s session.Snapshot) []item {
+		return itemsOf(s.LambdaFunctions, func(f lambda.FunctionSummary) string { return f.Name },
+			func(f lambda.FunctionSummary) string { return f.Name }, func(f lambda.FunctionSummary) []attribute {
+				return []attribute{{"state", f.State}, {"runtime", f.Runtime}, {"last modified", f.LastModified}}
+			})
+	}},
+	{"CloudFront distributions", func(s session.Snapshot) []item {
+		return itemsOf(s.CloudFrontDistributions, func(d cloudfront.DistributionSummary) string { return d.ID },
+			func(d cloudfront.DistributionSummary) string { return nameAndID(d.Name(), d.ID) }, func(d cloudfront.DistributionSummary) []attribute {
+				return []attribute{{"status", d.Status}, {"enabled", strconv.FormatBool(d.Enabled)}}
+			})
+	}},
+	{"EBS volumes", func(s session.Snapshot) []item {
+		return itemsOf(s.EBSVolumes, func(v ebs.VolumeSummary) string { return v.ID },
+			func(v ebs.VolumeSummary) string { return nameAndID(v.Name, v.ID) }, func(v ebs.VolumeSummary) []attribute {
+				return []attribute{{"state", v.State}, {"size", fmt.Sprintf("%d GiB", v.SizeGiB)}, {"type", v.Type}}
+			})
+	}},
+	{"VPCs", func(s session.Snapshot) []item {
+		return itemsOf(s.VPCs, func(v vpc.VPCSummary) string { return v.ID },
+			func(v vpc.VPCSummary) string { return nameAndID(v.Name, v.ID) }, func(v vpc.VPCSummary) []attribute {
+				return []attribute{{"state", v.State}, {"subnets", strconv.Itoa(len(v.Subnets))}, {"NAT gateways", strconv.Itoa(len(v.NATGateways))}}
+			})
+	}},
+	{"ECR repositories", func(s session.Snapshot) []item {
+		return itemsOf(s.ECRRepositories, func(r ecr.RepositorySummary) string { return r.Name },
+			func(r ecr.RepositorySummary) string { return r.Name }, func(r ecr.RepositorySummary) []attribute {
+				return []attribute{{"latest tags", strings.Join(r.LatestTags, ", ")}}
+			})
+	}},
+	{"API Gateway APIs", func(s session.Snapshot) []item {
+		return itemsOf(s.APIGatewayAPIs, func(a apigateway.APISummary) string { return a.ID },
+			func(a apigateway.APISummary) string { return nameAndID(a.Name, a.ID) }, func(a apigateway.APISummary) []attribute {
+				return []attribute{{"stages", strconv.Itoa(len(a.Stages))}}
+			})
+	}},
+	{"Findings", func(s session.Snapshot) []item {
+		return itemsOf(s.Findings, func(f findings.Finding) string { return f.Check + ": " + f.Resource },
+			func(f findings.Finding) string { return f.Check + ": " + f.Resource }, nil)
+	}},
+	{"Hygiene", func(s session.Snapshot) []item {
+		return itemsOf(s.HygieneResources, func(r hygiene.Resource) string { return r.Kind + ": " + r.Name },
+			func(r hygiene.Resource) string { return r.Kind + ": " + r.Name }, nil)
+	}},
+}
+
+// nameAndID labels a resource with its name and ID, or only its ID when it
+// has no name
+func nameAndID(name, id string) string {
+	if name == "" || name == id {
+		return id
+	}
+	return name + " (" + id + ")"
+}
+
+// Compare returns the differences from before to after
+func Compare(before, after session.Snapshot) Diff {
+	d := Diff{Before: before.SavedAt, After: after.SavedAt}
+	for _, k := range kinds {
+		t := compareItems(k.items(before), k.items(after))
+		if t.Before == 0 && t.After == 0 {
+			continue
+		}
+		t.Title = k.title
+		d.Types = append(d.Types, t)
+	}
+	return d
+}
+
+// compareItems returns the differences from the items before to those
+// after, sorted by label
+func compareItems(before, after []item) Type {
+	t := Type{Before: len(before), After: len(after)}
+	previous := make(map[string]item, len(before))
+	for _, i := range before {
+		previous[i.key] = i
+	}
+	current := make(map[string]bool, len(after))
+
+	for _, i := range after {
+		current[i.key] = true
+		old, ok := previous[i.key]
+		if !ok {
+			t.Added = append(t.Added, i.label)
+			continue
+		}
+		for j, a := range i.attributes {
+			if j < len(old.attributes) && old.attributes[j].value != a.value {
+				t.Changes = append(t.Changes, Change{Resource: i.label, Attribute: a.name, Before: old.attributes[j].value, After: a.value})
+			}
+		}
+	}
+	for _, i := range before {
+		if !current[i.key] {
+			t.Removed = append(t.Removed, i.label)
+		}
+	}
+
+	sort.Strings(t.Added)
+	sort.Strings(t.Removed)
+	sort.SliceStable(t.Changes, func(i, j int) bool { return t.Changes[i].Resource < t.Changes[j].Resource })
+	return t
+}
+
+// Format renders d as plain text, a line with the counts of each resource
+// type followed by its added (+), removed (-) and changed (~) resources
+func Format(d Diff) string {
+	var output strings.Builder
+	fmt.Fprintf(&output, "Comparing the snapshot of %s with that of %s\n", formatTime(d.Before), formatTime(d.After))
+
+	for _, t := range d.Types {
+		fmt.Fprintf(&output, "\n%s: %s\n", t.Title, formatCount(t.Before, t.After))
+		for _, name := range t.Added {
+			fmt.Fprintf(&output, "  + %s\n", name)
+		}
+		for _, name := range t.Removed {
+			fmt.Fprintf(&output, "  - %s\n", name)
+		}
+		for _, c := range t.Changes {
+			fmt.Fprintf(&output, "  ~ %s: %s %s → %s\n", c.Resource, c.Attribute, orNone(c.Before), orNone(c.After))
+		}
+	}
+
+	if !d.Changed() {
+		output.WriteString("\nNo changes.\n")
+	}
+	return output.String()
+}
+
+// formatCount formats the number of resources of a type before and after,
+// e.g. "3 → 4 (+1)", or "3" when it did not change
+func formatCount(before, after int) string {
+	if before == after {
+		return strconv.Itoa(before)
+	}
+	return fmt.Sprintf("%d → %d (%+d)", before, after, after-before)
+}
+
+// formatTime formats when a snapshot was saved
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "unknown time"
+	}
+	return t.Local().Format("Mon Jan 2 2006 15:04 MST")
+}
+
+// orNone shows empty attributes, e.g. a dead-letter queue that was removed
+func orNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
@@ -0,0 +1,69 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/correctedcloud/aws-overview/internal/session"
+	"github.com/correctedcloud/aws-overview/pkg/ec2"
+	"github.com/correctedcloud/aws-overview/pkg/ecs"
+	"github.com/correctedcloud/aws-overview/pkg/rds"
+)
+
+func TestCompare(t *testing.T) {
+	before := session.Snapshot{
+		DBInstances: []rds.DBInstanceSummary{
+			{Identifier: "orders-db", Status: "available", CPUData: []float64{10}},
+			{Identifier: "legacy-db", Status: "available"},
+		},
+		ECSServices: []ecs.ServiceSummary{
+			{ClusterName: "production", ServiceName: "orders-api", Status: "ACTIVE", TaskDefinition: "orders-api:41", RunningCount: 2, DesiredCount: 2},
+		},
+		EC2Instances: []ec2.InstanceSummary{{InstanceID: "i-1", Name: "web-1", State: "running"}},
+	}
+	after := session.Snapshot{
+		DBInstances: []rds.DBInstanceSummary{
+			// Metrics are not compared
+			{Identifier: "orders-db", Status: "modifying", CPUData: []float64{90}},
+			{Identifier: "reports-db", Status: "creating"},
+		},
+		ECSServices: []ecs.ServiceSummary{
+			{ClusterName: "production", ServiceName: "orders-api", Status: "ACTIVE", TaskDefinition: "orders-api:42", RunningCount: 1, DesiredCount: 2},
+		},
+		EC2Instances: []ec2.InstanceSummary{{InstanceID: "i-1", Name: "web-1", State: "running"}},
+	}
+
+	d := Compare(before, after)
+	if !d.Changed() || len(d.Types) != 3 {
+		t.Fatalf("Expected 3 resource types with changes, got %+v", d.Types)
+	}
+
+	result := Format(d)
+	for _, expected := range []string{
+		"\nRDS instances: 2\n  + reports-db\n  - legacy-db\n  ~ orders-db: status available → modifying\n",
+		"\nEC2 instances: 1\n\nECS services: 1\n" +
+			"  ~ production/orders-api: task definition orders-api:41 → orders-api:42\n" +
+			"  ~ production/orders-api: tasks 2/2 running → 1/2 running\n",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected the diff to contain %q, got:\n%s", expected, result)
+		}
+	}
+	if strings.Contains(result, "No changes") {
+		t.Errorf("Expected changes, got:\n%s", result)
+	}
+}
+
+func TestCompareCounts(t *testing.T) {
+	before := session.Snapshot{EC2Instances: []ec2.InstanceSummary{{InstanceID: "i-1"}}}
+	after := session.Snapshot{EC2Instances: []ec2.InstanceSummary{{InstanceID: "i-1"}, {InstanceID: "i-2", Name: "web-2"}, {InstanceID: "i-3"}}}
+
+	result := Format(Compare(before, after))
+	if !strings.Contains(result, "EC2 instances: 1 → 3 (+2)\n  + i-3\n  + web-2 (i-2)\n") {
+		t.Errorf("Unexpected diff:\n%s", result)
+	}
+
+	if d := Compare(after, after); d.Changed() || !strings.HasSuffix(Format(d), "\nNo changes.\n") {
+		t.Errorf("Expected no changes, got:\n%s", Format(d))
+	}
+}
@@ -0,0 +1,244 @@
+// Package export writes the resources of a session snapshot as tables, one
+// per resource type, for spreadsheets and documents.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/correctedcloud/aws-overview/internal/session"
+)
+
+// Table holds the resources of one type, a row each, with a column for
+// every field of their summary
+type Table struct {
+	Name   string // e.g. "db_instances", as the resources are named in the session file
+	Title  string // e.g. "RDS instances"
+	Header []string
+	Rows   [][]string
+}
+
+// titles are the titles of the tables by name
+var titles = map[string]string{
+	"load_balancers":           "Load balancers",
+	"db_instances":             "RDS instances",
+	"ec2_instances":            "EC2 instances",
+	"ecs_services":             "ECS services",
+	"ecs_scheduled_tasks":      "ECS scheduled tasks",
+	"sqs_queues":               "SQS queues",
+	"ssm_instances":            "SSM managed instances",
+	"dns_records":              "DNS records",
+	"dr_resources":             "DR readiness",
+	"sns_topics":               "SNS topics",
+	"lambda_functions":         "Lambda functions",
+	"cloudfront_distributions": "CloudFront distributions",
+	"ebs_volumes":              "EBS volumes",
+	"vpcs":                     "VPCs",
+	"ecr_repositories":         "ECR repositories",
+	"api_gateway_apis":         "API Gateway APIs",
+	"costs":                    "Costs",
+	"findings":                 "Findings",
+	"hygiene_resources":        "Hygiene",
+	"probes":                   "Probes",
+}
+
+// Tables returns a table for each resource type of snapshot that has
+// resources, in the order of the snapshot. Fields of nested structs get a
+// column each, named after the path to them, e.g. "Alarm.State". Lists and
+// maps of values are joined with semicolons, e.g. the datapoints of a
+// metric, and those of structs are written as JSON.
+func Tables(snapshot session.Snapshot) []Table {
+	return tablesOf(snapshot, func(reflect.Type) bool { return true })
+}
+
+// tablesOf returns the tables of snapshot as Tables does, with only the
+// columns of the fields whose type keep accepts
+func tablesOf(snapshot session.Snapshot, keep func(reflect.Type) bool) []Table {
+	var tables []Table
+	value := reflect.ValueOf(snapshot)
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+		var rows []reflect.Value
+		switch resources := value.Field(i); {
+		case resources.Kind() == reflect.Slice && resources.Type().Elem().Kind() == reflect.Struct:
+			for j := 0; j < resources.Len(); j++ {
+				rows = append(rows, resources.Index(j))
+			}
+		case resources.Kind() == reflect.Pointer && !resources.IsNil() && resources.Elem().Kind() == reflect.Struct:
+			rows = append(rows, resources.Elem())
+		}
+		if len(rows) == 0 {
+			continue
+		}
+
+		table := Table{Name: name, Title: titles[name]}
+		if table.Title == "" {
+			table.Title = name
+		}
+		var columns []column
+		for _, c := range columnsOf(rows[0].Type(), nil, "") {
+			if keep(rows[0].Type().FieldByIndex(c.index).Type) {
+				columns = append(columns, c)
+			}
+		}
+		for _, c := range columns {
+			table.Header = append(table.Header, c.name)
+		}
+		for _, row := range rows {
+			cells := make([]string, len(columns))
+			for j, c := range columns {
+				cells[j] = formatValue(row.FieldByIndex(c.index))
+			}
+			table.Rows = append(table.Rows, cells)
+		}
+		tables = append(tables, table)
+	}
+	return tables
+}
+
+// WriteCSV writes the tables of snapshot to dir, which it creates, as a CSV
+// file each named after the table, e.g. db_instances.csv, and returns their
+// paths
+func WriteCSV(dir string, snapshot session.Snapshot) ([]string, error) {
+	// The files hold resource names and account data, like the session
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, table := range Tables(snapshot) {
+		path := filepath.Join(dir, table.Name+".csv")
+		if err := writeCSVFile(path, table); err != nil {
+			return paths, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// writeCSVFile writes table to the CSV file at path
+func writeCSVFile(path string, table Table) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+
+	w := csv.NewWriter(file)
+	w.Write(table.Header)
+	w.WriteAll(table.Rows)
+	if err := w.Error(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// column is a field of a summary, possibly of a struct nested in it
+type column struct {
+	name  string
+	index []int // As taken by reflect.Value.FieldByIndex
+}
+
+// timeType is the type of the fields written as timestamps rather than
+// nested structs
+var timeType = reflect.TypeOf(time.Time{})
+
+// columnsOf returns the columns of the exported fields of t, whose index and
+// name start with those of the struct it is nested in, if any
+func columnsOf(t reflect.Type, index []int, prefix string) []column {
+	var columns []column
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fieldIndex := append(append([]int(nil), index...), i)
+		if field.Type.Kind() == reflect.Struct && field.Type != timeType {
+			nested := prefix + field.Name + "."
+			if field.Anonymous {
+				nested = prefix
+			}
+			columns = append(columns, columnsOf(field.Type, fieldIndex, nested)...)
+			continue
+		}
+		columns = append(columns, column{name: prefix + field.Name, index: fieldIndex})
+	}
+	return columns
+}
+
+// formatValue formats a field for a cell, empty for nil and zero times
+func formatValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return ""
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return ""
+		}
+		return formatValue(v.Elem())
+	case reflect.Slice, reflect.Array:
+		if !isScalar(v.Type().Elem()) {
+			return formatJSON(v)
+		}
+		values := make([]string, v.Len())
+		for i := range values {
+			values[i] = formatValue(v.Index(i))
+		}
+		return strings.Join(values, ";")
+	case reflect.Map:
+		if !isScalar(v.Type().Elem()) {
+			return formatJSON(v)
+		}
+		entries := make([]string, 0, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			entries = append(entries, formatValue(iter.Key())+"="+formatValue(iter.Value()))
+		}
+		sort.Strings(entries)
+		return strings.Join(entries, ";")
+	case reflect.Struct:
+		if v.Type() == timeType {
+			if t := v.Interface().(time.Time); !t.IsZero() {
+				return t.UTC().Format(time.RFC3339)
+			}
+			return ""
+		}
+		return formatJSON(v)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	}
+
+	if d, ok := v.Interface().(time.Duration); ok {
+		return d.String()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// isScalar reports whether values of t fit a cell without JSON
+func isScalar(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Struct:
+		return t == timeType
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Pointer, reflect.Interface:
+		return false
+	}
+	return true
+}
+
+// formatJSON formats a value too complex for a cell of its own as JSON
+func formatJSON(v reflect.Value) string {
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
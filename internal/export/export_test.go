@@ -0,0 +1,96 @@
+package export
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/correctedcloud/aws-overview/internal/session"
+	"github.com/correctedcloud/aws-overview/pkg/cost"
+	"github.com/correctedcloud/aws-overview/pkg/rds"
+	"github.com/correctedcloud/aws-overview/pkg/sns"
+)
+
+func TestTables(t *testing.T) {
+	end := time.Date(2024, 5, 10, 14, 30, 0, 0, time.UTC)
+	snapshot := session.Snapshot{
+		Region: "eu-west-1",
+		DBInstances: []rds.DBInstanceSummary{{
+			Identifier:    "orders-db",
+			MultiAZ:       true,
+			CPUData:       []float64{12.5, 40},
+			MetricsEnd:    end,
+			MetricsWindow: time.Hour,
+			PendingMaintenance: []rds.MaintenanceAction{
+				{Action: "system-update"},
+			},
+		}},
+		SNSTopics: []sns.TopicSummary{},
+		Costs:     &cost.Summary{Currency: "USD", MonthToDate: 1234.5},
+	}
+
+	tables := Tables(snapshot)
+	if len(tables) != 2 || tables[0].Name != "db_instances" || tables[1].Name != "costs" {
+		t.Fatalf("Expected the DB instances and costs tables, got %+v", tables)
+	}
+
+	instances := tables[0]
+	cell := func(column string) string {
+		i := slices.Index(instances.Header, column)
+		if i < 0 {
+			t.Fatalf("Expected a %s column, got %v", column, instances.Header)
+		}
+		return instances.Rows[0][i]
+	}
+	for column, expected := range map[string]string{
+		"Identifier":          "orders-db",
+		"MultiAZ":             "true",
+		"CPUData":             "12.5;40",
+		"MemoryData":          "",
+		"MetricsEnd":          "2024-05-10T14:30:00Z",
+		"MetricsWindow":       "1h0m0s",
+		"CACertificateExpiry": "",
+	} {
+		if got := cell(column); got != expected {
+			t.Errorf("Expected %s to be %q, got %q", column, expected, got)
+		}
+	}
+	if got := cell("PendingMaintenance"); got == "" || got[0] != '[' {
+		t.Errorf("Expected the maintenance actions as JSON, got %q", got)
+	}
+
+	if costs := tables[1]; len(costs.Rows) != 1 || costs.Rows[0][slices.Index(costs.Header, "MonthToDate")] != "1234.5" {
+		t.Errorf("Expected a row of costs, got %+v", costs)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "export")
+	snapshot := session.Snapshot{
+		DBInstances: []rds.DBInstanceSummary{{Identifier: "orders-db"}, {Identifier: "users, \"legacy\""}},
+	}
+
+	paths, err := WriteCSV(dir, snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 || paths[0] != filepath.Join(dir, "db_instances.csv") {
+		t.Fatalf("Expected db_instances.csv, got %v", paths)
+	}
+
+	file, err := os.Open(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || records[0][0] != "Identifier" || records[2][0] != "users, \"legacy\"" {
+		t.Errorf("Expected a header and two rows, got %v", records)
+	}
+}
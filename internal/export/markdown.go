@@ -0,0 +1,83 @@
+package export
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/correctedcloud/aws-overview/internal/session"
+	"github.com/correctedcloud/aws-overview/pkg/common"
+	"github.com/correctedcloud/aws-overview/pkg/ec2"
+	"github.com/correctedcloud/aws-overview/pkg/rds"
+	"github.com/correctedcloud/aws-overview/pkg/sqs"
+)
+
+// tabTables builds the tables of the resource types with a table on their
+// tab, whose columns summarize them better than their fields, by name
+var tabTables = map[string]func(session.Snapshot) ([]string, [][]string){
+	"db_instances":  func(s session.Snapshot) ([]string, [][]string) { return columnTable(s.DBInstances, rds.Columns) },
+	"ec2_instances": func(s session.Snapshot) ([]string, [][]string) { return columnTable(s.EC2Instances, ec2.Columns) },
+	"sqs_queues":    func(s session.Snapshot) ([]string, [][]string) { return columnTable(s.SQSQueues, sqs.Columns) },
+}
+
+// columnTable returns the header and rows of rows in columns
+func columnTable[T any](rows []T, columns []common.Column[T]) ([]string, [][]string) {
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.Title
+	}
+	return header, common.TableCells(rows, columns)
+}
+
+// SummaryTables returns a table for each resource type of snapshot that
+// has resources, like Tables, but only with the columns that fit a table
+// read by people: those of the table on the tab of the resource type where
+// it has one, and else the fields holding a single value
+func SummaryTables(snapshot session.Snapshot) []Table {
+	tables := tablesOf(snapshot, isScalar)
+	for i, table := range tables {
+		if build, ok := tabTables[table.Name]; ok {
+			tables[i].Header, tables[i].Rows = build(snapshot)
+		}
+	}
+	return tables
+}
+
+// Markdown renders the summary tables of snapshot as GitHub-flavored
+// markdown, e.g. for pasting into an incident document or a pull request
+func Markdown(snapshot session.Snapshot, generated time.Time) string {
+	var output strings.Builder
+	title := "AWS overview"
+	if snapshot.Region != "" {
+		title += ": " + snapshot.Region
+	}
+	output.WriteString("# " + title + "\n\n")
+	output.WriteString(fmt.Sprintf("Generated %s by aws-overview.\n", generated.Format("Mon Jan 2 2006 15:04 MST")))
+
+	tables := SummaryTables(snapshot)
+	if len(tables) == 0 {
+		output.WriteString("\nNo resources found.\n")
+	}
+	for _, table := range tables {
+		output.WriteString(fmt.Sprintf("\n## %s (%d)\n\n", table.Title, len(table.Rows)))
+		writeMarkdownRow(&output, table.Header)
+		writeMarkdownRow(&output, slices.Repeat([]string{"---"}, len(table.Header)))
+		for _, row := range table.Rows {
+			writeMarkdownRow(&output, row)
+		}
+	}
+	return output.String()
+}
+
+// writeMarkdownRow writes cells as a row of a markdown table, escaping the
+// characters that would end a cell or the row
+func writeMarkdownRow(output *strings.Builder, cells []string) {
+	output.WriteString("|")
+	for _, cell := range cells {
+		cell = strings.ReplaceAll(cell, "|", `\|`)
+		cell = strings.ReplaceAll(cell, "\n", "<br>")
+		output.WriteString(" " + cell + " |")
+	}
+	output.WriteString("\n")
+}
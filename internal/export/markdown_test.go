@@ -0,0 +1,43 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/correctedcloud/aws-overview/internal/session"
+	"github.com/correctedcloud/aws-overview/pkg/rds"
+	"github.com/correctedcloud/aws-overview/pkg/sns"
+)
+
+func TestMarkdown(t *testing.T) {
+	snapshot := session.Snapshot{
+		Region: "eu-west-1",
+		DBInstances: []rds.DBInstanceSummary{
+			{Identifier: "orders-db", Engine: "postgres", Status: "available", CPUData: []float64{12.5, 40}},
+		},
+		SNSTopics: []sns.TopicSummary{
+			{Name: "alerts|prod", Subscriptions: []sns.SubscriptionSummary{{Queue: "orders"}}},
+		},
+	}
+	generated := time.Date(2024, 5, 10, 14, 30, 0, 0, time.UTC)
+
+	result := Markdown(snapshot, generated)
+	for _, expected := range []string{
+		"# AWS overview: eu-west-1\n\nGenerated Fri May 10 2024 14:30 UTC by aws-overview.\n",
+		// The columns of the RDS tab
+		"## RDS instances (1)\n\n| Identifier | Engine | Status | CPU |",
+		"| orders-db | postgres | available | 40.00% |",
+		// Lists of structs do not fit a table read by people
+		"## SNS topics (1)\n\n| Name | ARN | OtherSubscriptions |",
+		`| alerts\|prod |  | 0 |`,
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected the markdown to contain %q, got:\n%s", expected, result)
+		}
+	}
+
+	if result := Markdown(session.Snapshot{}, generated); !strings.Contains(result, "No resources found.") {
+		t.Errorf("Expected a note without resources, got:\n%s", result)
+	}
+}
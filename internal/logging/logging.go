@@ -0,0 +1,124 @@
+// Package logging writes the tool's own structured logs, e.g. the AWS calls
+// it makes, how long they take and how often they are retried, as JSON lines
+// to a file, so they can be read while the terminal UI owns the screen.
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// Stderr is the path of the log that writes to stderr
+const Stderr = "-"
+
+// DefaultPath returns the default location of the log
+func DefaultPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "aws-overview", "debug.log")
+}
+
+// Open makes the default slog logger append to the log at path, creating it
+// and its parent directories as needed. A path of Stderr writes to stderr
+// instead. Only warnings, e.g. failed calls, and retries are logged, or
+// every call when debug is set. The returned closer closes the file.
+func Open(path string, debug bool) (io.Closer, error) {
+	var w io.WriteCloser = nopCloser{os.Stderr}
+	if path != Stderr {
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create log directory: %w", err)
+		}
+		file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open log: %w", err)
+		}
+		w = file
+	}
+
+	level := slog.LevelInfo
+	if debug {
+		level = slog.LevelDebug
+	}
+	slog.SetDefault(slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})))
+	return w, nil
+}
+
+// Discard makes the default slog logger drop every record, rather than
+// print it over the terminal UI
+func Discard() {
+	slog.SetDefault(slog.New(discardHandler{}))
+}
+
+// discardHandler is a slog handler that is never enabled
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+// nopCloser keeps Close from closing stderr
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
+// CallMiddleware returns an API option logging every call a client of the
+// given AWS service makes to the default slog logger: at debug level when it
+// succeeds at once, at info level when it was retried and as a warning when
+// it failed
+func CallMiddleware(service string) func(*middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		// After the operation's metadata is registered, which names it
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("Logging",
+			func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+				start := time.Now()
+				out, metadata, err := next.HandleInitialize(ctx, in)
+				logCall(ctx, service, time.Since(start), metadata, err)
+				return out, metadata, err
+			}), middleware.After)
+	}
+}
+
+// logCall logs an AWS call that took d and ended with err
+func logCall(ctx context.Context, service string, d time.Duration, metadata middleware.Metadata, err error) {
+	attrs := []any{
+		slog.String("service", service),
+		slog.String("operation", awsmiddleware.GetOperationName(ctx)),
+		slog.Duration("duration", d),
+	}
+
+	level, msg := slog.LevelDebug, "AWS call"
+	if results, ok := retry.GetAttemptResults(metadata); ok && len(results.Results) > 1 {
+		level, msg = slog.LevelInfo, "AWS call retried"
+		var retried []string
+		for _, result := range results.Results {
+			if result.Err != nil {
+				retried = append(retried, result.Err.Error())
+			}
+		}
+		attrs = append(attrs, slog.Int("attempts", len(results.Results)), slog.Any("attempt_errors", retried))
+	}
+	switch {
+	case errors.Is(err, context.Canceled):
+		// Superseded by a newer refresh, or shutting down
+		level, msg = slog.LevelDebug, "AWS call cancelled"
+	case err != nil:
+		level, msg = slog.LevelWarn, "AWS call failed"
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	slog.Log(ctx, level, msg, attrs...)
+}
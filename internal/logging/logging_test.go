@@ -0,0 +1,82 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/smithy-go/middleware"
+)
+
+func TestCallMiddleware(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+	path := filepath.Join(t.TempDir(), "logs", "debug.log")
+	closer, err := Open(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Fails once, then answers
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		if calls == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"__type":"InternalError","message":"try again"}`))
+			return
+		}
+		w.Write([]byte(`{"QueueUrls":[]}`))
+	}))
+	defer server.Close()
+
+	client := sqs.NewFromConfig(aws.Config{
+		Region:      "eu-west-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKID", "secret", ""),
+		Retryer: func() aws.Retryer {
+			return retry.NewStandard(func(o *retry.StandardOptions) { o.Backoff = retry.BackoffDelayerFunc(noDelay) })
+		},
+		APIOptions: []func(*middleware.Stack) error{CallMiddleware("sqs")},
+	}, func(o *sqs.Options) { o.BaseEndpoint = aws.String(server.URL) })
+	if _, err := client.ListQueues(context.Background(), &sqs.ListQueuesInput{}); err != nil {
+		t.Fatal(err)
+	}
+	closer.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var record struct {
+		Level         string
+		Msg           string
+		Service       string
+		Operation     string
+		Attempts      int
+		AttemptErrors []string `json:"attempt_errors"`
+	}
+	if err := json.Unmarshal(data, &record); err != nil {
+		t.Fatalf("Expected a JSON line, got %q: %v", data, err)
+	}
+	if record.Level != "INFO" || record.Msg != "AWS call retried" || record.Service != "sqs" || record.Operation != "ListQueues" || record.Attempts != 2 {
+		t.Errorf("Unexpected record %+v", record)
+	}
+	if len(record.AttemptErrors) != 1 || !strings.Contains(record.AttemptErrors[0], "try again") {
+		t.Errorf("Expected the error of the first attempt, got %v", record.AttemptErrors)
+	}
+}
+
+func noDelay(int, error) (time.Duration, error) {
+	return 0, nil
+}
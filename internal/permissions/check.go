@@ -0,0 +1,703 @@
+package permissions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/apigateway"
+	"github.com/aws/aws-sdk-go-v2/service/apigatewayv2"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbv2types "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/smithy-go"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// placeholderID names resources that do not exist in the caller's account.
+// Calls against them are authorized before the resource is looked up, so a
+// "not found" error still proves the permission is present.
+const placeholderID = "aws-overview-permission-check"
+
+// Status is the outcome of a permission check
+type Status int
+
+const (
+	// Allowed means the call was authorized
+	Allowed Status = iota
+	// Denied means the IAM permission for the call is missing
+	Denied
+	// Unknown means the call failed before authorization, e.g. on invalid
+	// credentials or a network error
+	Unknown
+	// Skipped means there was no resource to run the check against
+	Skipped
+)
+
+// errNoResource is returned by checks that need an existing resource when
+// there is none
+var errNoResource = errors.New("no resource to check against")
+
+// Check is a read-only call the collectors of a service depend on
+type Check struct {
+	Service string // Service flag the check belongs to, e.g. "alb"
+	Action  string // IAM action, e.g. "elasticloadbalancing:DescribeTargetHealth"
+	call    func(ctx context.Context) error
+}
+
+// Result is the outcome of running a check
+type Result struct {
+	Check
+	Status Status
+	Err    error
+}
+
+// Checks returns the checks for the given services ("alb", "rds", "ec2",
+// "ecs", "sqs", "ssm", "dns", "dr", "sns", "lambda", "lag", "cloudfront",
+// "ebs", "vpc", "ecr", "apigw", "findings", "hygiene", "metrics" and
+// "container-insights")
+// using clients created from cfg. "cost" has no check, as Cost Explorer
+// bills every request, and "probe" none, as it makes no AWS calls.
+func Checks(cfg aws.Config, services []string) []Check {
+	var checks []Check
+	for _, service := range services {
+		switch service {
+		case "alb":
+			checks = append(checks, albChecks(elasticloadbalancingv2.NewFromConfig(cfg), acm.NewFromConfig(cfg))...)
+		case "rds":
+			checks = append(checks, rdsChecks(rds.NewFromConfig(cfg))...)
+			checks = append(checks, cloudwatchCheck("rds", cloudwatch.NewFromConfig(cfg)))
+			checks = append(checks, instanceTypesCheck("rds", ec2.NewFromConfig(cfg)))
+			checks = append(checks, logsChecks("rds", cloudwatchlogs.NewFromConfig(cfg), true)...)
+			checks = append(checks, eventsChecks("rds", cfg)...)
+		case "ec2":
+			client := ec2.NewFromConfig(cfg)
+			checks = append(checks, ec2Check("ec2", client), ec2StatusCheck(client), securityGroupsCheck(client), spotRequestsCheck(client))
+		case "ecs":
+			checks = append(checks, ecsChecks(ecs.NewFromConfig(cfg))...)
+			checks = append(checks, scheduleChecks(eventbridge.NewFromConfig(cfg))...)
+			checks = append(checks, cloudwatchCheck("ecs", cloudwatch.NewFromConfig(cfg)))
+			checks = append(checks, logsChecks("ecs", cloudwatchlogs.NewFromConfig(cfg), false)...)
+			checks = append(checks, eventsChecks("ecs", cfg)...)
+		case "sqs":
+			checks = append(checks, sqsChecks(sqs.NewFromConfig(cfg))...)
+			checks = append(checks, cloudwatchCheck("sqs", cloudwatch.NewFromConfig(cfg)))
+		case "ssm":
+			checks = append(checks, ssmChecks(ssm.NewFromConfig(cfg))...)
+			checks = append(checks, ec2Check("ssm", ec2.NewFromConfig(cfg)))
+		case "dns":
+			checks = append(checks, dnsChecks(route53.NewFromConfig(cfg), cloudfront.NewFromConfig(cfg),
+				elasticloadbalancingv2.NewFromConfig(cfg), elasticloadbalancing.NewFromConfig(cfg), ec2.NewFromConfig(cfg))...)
+			checks = append(checks, ec2Check("dns", ec2.NewFromConfig(cfg)))
+		case "dr":
+			checks = append(checks, drChecks(rds.NewFromConfig(cfg), s3.NewFromConfig(cfg), ecr.NewFromConfig(cfg), dynamodb.NewFromConfig(cfg))...)
+		case "sns":
+			checks = append(checks, snsChecks(sns.NewFromConfig(cfg))...)
+		case "lambda":
+			checks = append(checks, lambdaChecks(lambda.NewFromConfig(cfg))...)
+			checks = append(checks, logsChecks("lambda", cloudwatchlogs.NewFromConfig(cfg), false)...)
+			checks = append(checks, eventsChecks("lambda", cfg)...)
+		case "cloudfront":
+			checks = append(checks, cloudfrontChecks(cloudfront.NewFromConfig(cfg))...)
+		case "ebs":
+			checks = append(checks, ebsCheck(ec2.NewFromConfig(cfg)))
+			checks = append(checks, cloudwatchCheck("ebs", cloudwatch.NewFromConfig(cfg)))
+		case "vpc":
+			checks = append(checks, vpcChecks(ec2.NewFromConfig(cfg))...)
+			checks = append(checks, cloudwatchCheck("vpc", cloudwatch.NewFromConfig(cfg)))
+		case "ecr":
+			checks = append(checks, ecrChecks(ecr.NewFromConfig(cfg))...)
+		case "apigw":
+			checks = append(checks, apigwCheck(apigateway.NewFromConfig(cfg), apigatewayv2.NewFromConfig(cfg)))
+			checks = append(checks, cloudwatchCheck("apigw", cloudwatch.NewFromConfig(cfg)))
+		case "lag":
+			checks = append(checks, lagChecks(cloudwatch.NewFromConfig(cfg), lambda.NewFromConfig(cfg))...)
+		case "findings":
+			checks = append(checks, cloudwatchCheck("findings", cloudwatch.NewFromConfig(cfg)))
+		case "hygiene":
+			checks = append(checks, cloudwatchCheck("hygiene", cloudwatch.NewFromConfig(cfg)))
+		case "metrics":
+			checks = append(checks, metricsChecks(cloudwatch.NewFromConfig(cfg))...)
+		case "container-insights":
+			checks = append(checks, cloudwatchCheck("container-insights", cloudwatch.NewFromConfig(cfg)))
+		}
+	}
+	return checks
+}
+
+// Run performs the checks one after another and classifies their outcome
+func Run(ctx context.Context, checks []Check) []Result {
+	results := make([]Result, 0, len(checks))
+	for _, check := range checks {
+		err := check.call(ctx)
+		results = append(results, Result{Check: check, Status: classify(err), Err: err})
+	}
+	return results
+}
+
+// classify turns the error of a check call into a status. Any API error
+// other than an authentication or authorization failure means the request
+// was authorized and failed for another reason, e.g. a placeholder
+// resource that does not exist.
+func classify(err error) Status {
+	if err == nil {
+		return Allowed
+	}
+	if errors.Is(err, errNoResource) {
+		return Skipped
+	}
+	if IsAccessDenied(err) {
+		return Denied
+	}
+	if isAuthenticationError(err) {
+		return Unknown
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return Allowed
+	}
+	return Unknown
+}
+
+// FormatReport formats check results as a plain text report
+func FormatReport(results []Result) string {
+	var output strings.Builder
+	output.WriteString("PERMISSION CHECK\n")
+	output.WriteString(common.Rule("PERMISSION CHECK", "=") + "\n\n")
+
+	denied, unknown, skipped := 0, 0, 0
+	for _, result := range results {
+		switch result.Status {
+		case Allowed:
+			output.WriteString(fmt.Sprintf("  ok       %-4s %s\n", result.Service, result.Action))
+		case Denied:
+			denied++
+			output.WriteString(fmt.Sprintf("  MISSING  %-4s %s\n", result.Service, result.Action))
+		case Skipped:
+			skipped++
+			output.WriteString(fmt.Sprintf("  skipped  %-4s %s (%v)\n", result.Service, result.Action, result.Err))
+		default:
+			unknown++
+			output.WriteString(fmt.Sprintf("  ERROR    %-4s %s: %v\n", result.Service, result.Action, result.Err))
+		}
+	}
+
+	output.WriteString(fmt.Sprintf("\n%d checks, %d missing permissions, %d errors, %d skipped\n", len(results), denied, unknown, skipped))
+	return output.String()
+}
+
+func albChecks(client *elasticloadbalancingv2.Client, acmClient *acm.Client) []Check {
+	return []Check{
+		{"alb", "elasticloadbalancing:DescribeLoadBalancers", func(ctx context.Context) error {
+			_, err := client.DescribeLoadBalancers(ctx, &elasticloadbalancingv2.DescribeLoadBalancersInput{PageSize: aws.Int32(1)})
+			return err
+		}},
+		{"alb", "elasticloadbalancing:DescribeTargetGroups", func(ctx context.Context) error {
+			_, err := client.DescribeTargetGroups(ctx, &elasticloadbalancingv2.DescribeTargetGroupsInput{PageSize: aws.Int32(1)})
+			return err
+		}},
+		{"alb", "elasticloadbalancing:DescribeTargetHealth", func(ctx context.Context) error {
+			// Target group ARNs contain the account ID, so check against an existing one
+			groups, err := client.DescribeTargetGroups(ctx, &elasticloadbalancingv2.DescribeTargetGroupsInput{PageSize: aws.Int32(1)})
+			if err != nil {
+				// Reported by the check of the listing call itself
+				return errNoResource
+			}
+			if len(groups.TargetGroups) == 0 {
+				return errNoResource
+			}
+			_, err = client.DescribeTargetHealth(ctx, &elasticloadbalancingv2.DescribeTargetHealthInput{TargetGroupArn: groups.TargetGroups[0].TargetGroupArn})
+			return err
+		}},
+		{"alb", "elasticloadbalancing:DescribeListeners", func(ctx context.Context) error {
+			lbs, err := client.DescribeLoadBalancers(ctx, &elasticloadbalancingv2.DescribeLoadBalancersInput{PageSize: aws.Int32(1)})
+			if err != nil || len(lbs.LoadBalancers) == 0 {
+				return errNoResource
+			}
+			_, err = client.DescribeListeners(ctx, &elasticloadbalancingv2.DescribeListenersInput{LoadBalancerArn: lbs.LoadBalancers[0].LoadBalancerArn})
+			return err
+		}},
+		{"alb", "elasticloadbalancing:DescribeRules", func(ctx context.Context) error {
+			lbs, err := client.DescribeLoadBalancers(ctx, &elasticloadbalancingv2.DescribeLoadBalancersInput{PageSize: aws.Int32(1)})
+			if err != nil || len(lbs.LoadBalancers) == 0 {
+				return errNoResource
+			}
+			listeners, err := client.DescribeListeners(ctx, &elasticloadbalancingv2.DescribeListenersInput{LoadBalancerArn: lbs.LoadBalancers[0].LoadBalancerArn})
+			if err != nil || len(listeners.Listeners) == 0 {
+				return errNoResource
+			}
+			_, err = client.DescribeRules(ctx, &elasticloadbalancingv2.DescribeRulesInput{ListenerArn: listeners.Listeners[0].ListenerArn})
+			return err
+		}},
+		{"alb", "elasticloadbalancing:DescribeListenerCertificates", func(ctx context.Context) error {
+			listener, err := firstSecureListener(ctx, client)
+			if err != nil {
+				return err
+			}
+			_, err = client.DescribeListenerCertificates(ctx, &elasticloadbalancingv2.DescribeListenerCertificatesInput{ListenerArn: listener.ListenerArn})
+			return err
+		}},
+		{"alb", "acm:DescribeCertificate", func(ctx context.Context) error {
+			// Certificate ARNs contain the account ID, so check against one in use
+			listener, err := firstSecureListener(ctx, client)
+			if err != nil {
+				return err
+			}
+			_, err = acmClient.DescribeCertificate(ctx, &acm.DescribeCertificateInput{CertificateArn: listener.Certificates[0].CertificateArn})
+			return err
+		}},
+	}
+}
+
+// firstSecureListener returns an HTTPS or TLS listener with a certificate
+// among the listeners of the first few load balancers, or errNoResource
+func firstSecureListener(ctx context.Context, client *elasticloadbalancingv2.Client) (elbv2types.Listener, error) {
+	lbs, err := client.DescribeLoadBalancers(ctx, &elasticloadbalancingv2.DescribeLoadBalancersInput{PageSize: aws.Int32(5)})
+	if err != nil {
+		return elbv2types.Listener{}, errNoResource
+	}
+	for _, lb := range lbs.LoadBalancers {
+		listeners, err := client.DescribeListeners(ctx, &elasticloadbalancingv2.DescribeListenersInput{LoadBalancerArn: lb.LoadBalancerArn})
+		if err != nil {
+			return elbv2types.Listener{}, errNoResource
+		}
+		for _, listener := range listeners.Listeners {
+			if len(listener.Certificates) > 0 {
+				return listener, nil
+			}
+		}
+	}
+	return elbv2types.Listener{}, errNoResource
+}
+
+func rdsChecks(client *rds.Client) []Check {
+	return []Check{
+		{"rds", "rds:DescribeDBInstances", func(ctx context.Context) error {
+			_, err := client.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{MaxRecords: aws.Int32(20)})
+			return err
+		}},
+		{"rds", "rds:DescribePendingMaintenanceActions", func(ctx context.Context) error {
+			_, err := client.DescribePendingMaintenanceActions(ctx, &rds.DescribePendingMaintenanceActionsInput{MaxRecords: aws.Int32(20)})
+			return err
+		}},
+	}
+}
+
+func cloudwatchCheck(service string, client *cloudwatch.Client) Check {
+	return Check{service, "cloudwatch:GetMetricData", func(ctx context.Context) error {
+		endTime := time.Now()
+		startTime := endTime.Add(-5 * time.Minute)
+		_, err := client.GetMetricData(ctx, &cloudwatch.GetMetricDataInput{
+			StartTime: &startTime,
+			EndTime:   &endTime,
+			MetricDataQueries: []cwtypes.MetricDataQuery{
+				{
+					Id: aws.String("check"),
+					MetricStat: &cwtypes.MetricStat{
+						Metric: &cwtypes.Metric{
+							Namespace:  aws.String("AWS/" + strings.ToUpper(service)),
+							MetricName: aws.String("CPUUtilization"),
+						},
+						Period: aws.Int32(300),
+						Stat:   aws.String("Average"),
+					},
+				},
+			},
+		})
+		return err
+	}}
+}
+
+func ec2StatusCheck(client *ec2.Client) Check {
+	return Check{"ec2", "ec2:DescribeInstanceStatus", func(ctx context.Context) error {
+		// A permitted dry run fails with DryRunOperation
+		_, err := client.DescribeInstanceStatus(ctx, &ec2.DescribeInstanceStatusInput{DryRun: aws.Bool(true)})
+		return err
+	}}
+}
+
+func securityGroupsCheck(client *ec2.Client) Check {
+	return Check{"ec2", "ec2:DescribeSecurityGroups", func(ctx context.Context) error {
+		// A permitted dry run fails with DryRunOperation
+		_, err := client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{DryRun: aws.Bool(true)})
+		return err
+	}}
+}
+
+func spotRequestsCheck(client *ec2.Client) Check {
+	return Check{"ec2", "ec2:DescribeSpotInstanceRequests", func(ctx context.Context) error {
+		// A permitted dry run fails with DryRunOperation
+		_, err := client.DescribeSpotInstanceRequests(ctx, &ec2.DescribeSpotInstanceRequestsInput{DryRun: aws.Bool(true)})
+		return err
+	}}
+}
+
+func ec2Check(service string, client *ec2.Client) Check {
+	return Check{service, "ec2:DescribeInstances", func(ctx context.Context) error {
+		// A permitted dry run fails with DryRunOperation
+		_, err := client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{DryRun: aws.Bool(true)})
+		return err
+	}}
+}
+
+func instanceTypesCheck(service string, client *ec2.Client) Check {
+	return Check{service, "ec2:DescribeInstanceTypes", func(ctx context.Context) error {
+		// A permitted dry run fails with DryRunOperation
+		_, err := client.DescribeInstanceTypes(ctx, &ec2.DescribeInstanceTypesInput{DryRun: aws.Bool(true)})
+		return err
+	}}
+}
+
+func ebsCheck(client *ec2.Client) Check {
+	return Check{"ebs", "ec2:DescribeVolumes", func(ctx context.Context) error {
+		// A permitted dry run fails with DryRunOperation
+		_, err := client.DescribeVolumes(ctx, &ec2.DescribeVolumesInput{DryRun: aws.Bool(true)})
+		return err
+	}}
+}
+
+func vpcChecks(client *ec2.Client) []Check {
+	// A permitted dry run fails with DryRunOperation
+	return []Check{
+		{"vpc", "ec2:DescribeVpcs", func(ctx context.Context) error {
+			_, err := client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{DryRun: aws.Bool(true)})
+			return err
+		}},
+		{"vpc", "ec2:DescribeSubnets", func(ctx context.Context) error {
+			_, err := client.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{DryRun: aws.Bool(true)})
+			return err
+		}},
+		{"vpc", "ec2:DescribeNatGateways", func(ctx context.Context) error {
+			_, err := client.DescribeNatGateways(ctx, &ec2.DescribeNatGatewaysInput{DryRun: aws.Bool(true)})
+			return err
+		}},
+		{"vpc", "ec2:DescribeInternetGateways", func(ctx context.Context) error {
+			_, err := client.DescribeInternetGateways(ctx, &ec2.DescribeInternetGatewaysInput{DryRun: aws.Bool(true)})
+			return err
+		}},
+		{"vpc", "ec2:DescribeVpcEndpoints", func(ctx context.Context) error {
+			_, err := client.DescribeVpcEndpoints(ctx, &ec2.DescribeVpcEndpointsInput{DryRun: aws.Bool(true)})
+			return err
+		}},
+	}
+}
+
+func ecsChecks(client *ecs.Client) []Check {
+	return []Check{
+		{"ecs", "ecs:ListClusters", func(ctx context.Context) error {
+			_, err := client.ListClusters(ctx, &ecs.ListClustersInput{MaxResults: aws.Int32(1)})
+			return err
+		}},
+		{"ecs", "ecs:DescribeClusters", func(ctx context.Context) error {
+			_, err := client.DescribeClusters(ctx, &ecs.DescribeClustersInput{Clusters: []string{placeholderID}})
+			return err
+		}},
+		{"ecs", "ecs:ListServices", func(ctx context.Context) error {
+			_, err := client.ListServices(ctx, &ecs.ListServicesInput{Cluster: aws.String(placeholderID)})
+			return err
+		}},
+		{"ecs", "ecs:DescribeServices", func(ctx context.Context) error {
+			_, err := client.DescribeServices(ctx, &ecs.DescribeServicesInput{Cluster: aws.String(placeholderID), Services: []string{placeholderID}})
+			return err
+		}},
+		{"ecs", "ecs:ListTagsForResource", func(ctx context.Context) error {
+			_, err := client.ListTagsForResource(ctx, &ecs.ListTagsForResourceInput{ResourceArn: aws.String(placeholderID)})
+			return err
+		}},
+		{"ecs", "ecs:DescribeTaskDefinition", func(ctx context.Context) error {
+			_, err := client.DescribeTaskDefinition(ctx, &ecs.DescribeTaskDefinitionInput{TaskDefinition: aws.String(placeholderID + ":1")})
+			return err
+		}},
+	}
+}
+
+// scheduleChecks checks listing the EventBridge rules that run ECS tasks
+func scheduleChecks(client *eventbridge.Client) []Check {
+	return []Check{
+		{"ecs", "events:ListRules", func(ctx context.Context) error {
+			_, err := client.ListRules(ctx, &eventbridge.ListRulesInput{Limit: aws.Int32(1)})
+			return err
+		}},
+		{"ecs", "events:ListTargetsByRule", func(ctx context.Context) error {
+			_, err := client.ListTargetsByRule(ctx, &eventbridge.ListTargetsByRuleInput{Rule: aws.String(placeholderID)})
+			return err
+		}},
+	}
+}
+
+// logsChecks checks tailing the error logs of the service's resources, which
+// look up their log groups by prefix when describeGroups is set
+func logsChecks(service string, client *cloudwatchlogs.Client, describeGroups bool) []Check {
+	checks := []Check{
+		{service, "logs:FilterLogEvents", func(ctx context.Context) error {
+			_, err := client.FilterLogEvents(ctx, &cloudwatchlogs.FilterLogEventsInput{LogGroupName: aws.String(placeholderID), Limit: aws.Int32(1)})
+			return err
+		}},
+	}
+	if describeGroups {
+		checks = append(checks, Check{service, "logs:DescribeLogGroups", func(ctx context.Context) error {
+			_, err := client.DescribeLogGroups(ctx, &cloudwatchlogs.DescribeLogGroupsInput{Limit: aws.Int32(1)})
+			return err
+		}})
+	}
+	return checks
+}
+
+// eventsChecks checks looking up the related events of the service's
+// resources: their alarms' state changes, their CloudTrail changes and, for
+// RDS instances, their RDS events
+func eventsChecks(service string, cfg aws.Config) []Check {
+	cloudwatchClient := cloudwatch.NewFromConfig(cfg)
+	cloudtrailClient := cloudtrail.NewFromConfig(cfg)
+	checks := []Check{
+		{service, "cloudwatch:DescribeAlarms", func(ctx context.Context) error {
+			_, err := cloudwatchClient.DescribeAlarms(ctx, &cloudwatch.DescribeAlarmsInput{MaxRecords: aws.Int32(1)})
+			return err
+		}},
+		{service, "cloudwatch:DescribeAlarmHistory", func(ctx context.Context) error {
+			_, err := cloudwatchClient.DescribeAlarmHistory(ctx, &cloudwatch.DescribeAlarmHistoryInput{AlarmName: aws.String(placeholderID), MaxRecords: aws.Int32(1)})
+			return err
+		}},
+		{service, "cloudtrail:LookupEvents", func(ctx context.Context) error {
+			_, err := cloudtrailClient.LookupEvents(ctx, &cloudtrail.LookupEventsInput{MaxResults: aws.Int32(1)})
+			return err
+		}},
+	}
+	if service == "rds" {
+		rdsClient := rds.NewFromConfig(cfg)
+		checks = append(checks, Check{service, "rds:DescribeEvents", func(ctx context.Context) error {
+			_, err := rdsClient.DescribeEvents(ctx, &rds.DescribeEventsInput{MaxRecords: aws.Int32(20)})
+			return err
+		}})
+	}
+	return checks
+}
+
+func sqsChecks(client *sqs.Client) []Check {
+	return []Check{
+		{"sqs", "sqs:ListQueues", func(ctx context.Context) error {
+			_, err := client.ListQueues(ctx, &sqs.ListQueuesInput{MaxResults: aws.Int32(1)})
+			return err
+		}},
+		{"sqs", "sqs:GetQueueAttributes", func(ctx context.Context) error {
+			// Queue URLs contain the account ID, so check against an existing queue
+			queues, err := client.ListQueues(ctx, &sqs.ListQueuesInput{MaxResults: aws.Int32(1)})
+			if err != nil {
+				// Reported by the check of the listing call itself
+				return errNoResource
+			}
+			if len(queues.QueueUrls) == 0 {
+				return errNoResource
+			}
+			_, err = client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{QueueUrl: aws.String(queues.QueueUrls[0])})
+			return err
+		}},
+	}
+}
+
+func ssmChecks(client *ssm.Client) []Check {
+	return []Check{
+		{"ssm", "ssm:DescribeInstanceInformation", func(ctx context.Context) error {
+			_, err := client.DescribeInstanceInformation(ctx, &ssm.DescribeInstanceInformationInput{MaxResults: aws.Int32(5)})
+			return err
+		}},
+		{"ssm", "ssm:DescribeInstancePatchStates", func(ctx context.Context) error {
+			_, err := client.DescribeInstancePatchStates(ctx, &ssm.DescribeInstancePatchStatesInput{InstanceIds: []string{"i-00000000000000000"}})
+			return err
+		}},
+	}
+}
+
+func dnsChecks(route53Client *route53.Client, cloudfrontClient *cloudfront.Client, elbv2Client *elasticloadbalancingv2.Client,
+	elbClient *elasticloadbalancing.Client, ec2Client *ec2.Client) []Check {
+	return []Check{
+		{"dns", "route53:ListHostedZones", func(ctx context.Context) error {
+			_, err := route53Client.ListHostedZones(ctx, &route53.ListHostedZonesInput{MaxItems: aws.Int32(1)})
+			return err
+		}},
+		{"dns", "route53:ListResourceRecordSets", func(ctx context.Context) error {
+			zones, err := route53Client.ListHostedZones(ctx, &route53.ListHostedZonesInput{MaxItems: aws.Int32(1)})
+			if err != nil || len(zones.HostedZones) == 0 {
+				return errNoResource
+			}
+			_, err = route53Client.ListResourceRecordSets(ctx, &route53.ListResourceRecordSetsInput{
+				HostedZoneId: zones.HostedZones[0].Id,
+				MaxItems:     aws.Int32(1),
+			})
+			return err
+		}},
+		{"dns", "cloudfront:ListDistributions", func(ctx context.Context) error {
+			_, err := cloudfrontClient.ListDistributions(ctx, &cloudfront.ListDistributionsInput{MaxItems: aws.Int32(1)})
+			return err
+		}},
+		{"dns", "elasticloadbalancing:DescribeLoadBalancers", func(ctx context.Context) error {
+			if _, err := elbv2Client.DescribeLoadBalancers(ctx, &elasticloadbalancingv2.DescribeLoadBalancersInput{PageSize: aws.Int32(1)}); err != nil {
+				return err
+			}
+			_, err := elbClient.DescribeLoadBalancers(ctx, &elasticloadbalancing.DescribeLoadBalancersInput{PageSize: aws.Int32(1)})
+			return err
+		}},
+		{"dns", "ec2:DescribeAddresses", func(ctx context.Context) error {
+			// A permitted dry run fails with DryRunOperation
+			_, err := ec2Client.DescribeAddresses(ctx, &ec2.DescribeAddressesInput{DryRun: aws.Bool(true)})
+			return err
+		}},
+	}
+}
+
+func drChecks(rdsClient *rds.Client, s3Client *s3.Client, ecrClient *ecr.Client, dynamodbClient *dynamodb.Client) []Check {
+	return []Check{
+		{"dr", "rds:DescribeDBInstances", func(ctx context.Context) error {
+			_, err := rdsClient.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{MaxRecords: aws.Int32(20)})
+			return err
+		}},
+		{"dr", "s3:ListAllMyBuckets", func(ctx context.Context) error {
+			_, err := s3Client.ListBuckets(ctx, &s3.ListBucketsInput{MaxBuckets: aws.Int32(1)})
+			return err
+		}},
+		{"dr", "s3:GetReplicationConfiguration", func(ctx context.Context) error {
+			// S3 reports missing buckets before checking permissions, so a real bucket is needed
+			buckets, err := s3Client.ListBuckets(ctx, &s3.ListBucketsInput{MaxBuckets: aws.Int32(1)})
+			if err != nil || len(buckets.Buckets) == 0 {
+				return errNoResource
+			}
+			_, err = s3Client.GetBucketReplication(ctx, &s3.GetBucketReplicationInput{Bucket: buckets.Buckets[0].Name})
+			return err
+		}},
+		{"dr", "ecr:DescribeRepositories", func(ctx context.Context) error {
+			_, err := ecrClient.DescribeRepositories(ctx, &ecr.DescribeRepositoriesInput{MaxResults: aws.Int32(1)})
+			return err
+		}},
+		{"dr", "ecr:DescribeRegistry", func(ctx context.Context) error {
+			_, err := ecrClient.DescribeRegistry(ctx, &ecr.DescribeRegistryInput{})
+			return err
+		}},
+		{"dr", "dynamodb:ListTables", func(ctx context.Context) error {
+			_, err := dynamodbClient.ListTables(ctx, &dynamodb.ListTablesInput{Limit: aws.Int32(1)})
+			return err
+		}},
+		{"dr", "dynamodb:DescribeTable", func(ctx context.Context) error {
+			_, err := dynamodbClient.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(placeholderID)})
+			return err
+		}},
+	}
+}
+
+func snsChecks(client *sns.Client) []Check {
+	return []Check{
+		{"sns", "sns:ListTopics", func(ctx context.Context) error {
+			_, err := client.ListTopics(ctx, &sns.ListTopicsInput{})
+			return err
+		}},
+		{"sns", "sns:ListSubscriptions", func(ctx context.Context) error {
+			_, err := client.ListSubscriptions(ctx, &sns.ListSubscriptionsInput{})
+			return err
+		}},
+		{"sns", "sns:GetSubscriptionAttributes", func(ctx context.Context) error {
+			// SNS validates subscription ARNs before checking permissions, so a real subscription is needed
+			subscriptions, err := client.ListSubscriptions(ctx, &sns.ListSubscriptionsInput{})
+			if err != nil {
+				return errNoResource
+			}
+			for _, subscription := range subscriptions.Subscriptions {
+				if arn := aws.ToString(subscription.SubscriptionArn); strings.HasPrefix(arn, "arn:") {
+					_, err = client.GetSubscriptionAttributes(ctx, &sns.GetSubscriptionAttributesInput{SubscriptionArn: aws.String(arn)})
+					return err
+				}
+			}
+			return errNoResource
+		}},
+	}
+}
+
+// lambdaChecks covers listing functions. lambda:InvokeFunction is not checked,
+// as Lambda has no dry run and invoking a function would run its code.
+func lambdaChecks(client *lambda.Client) []Check {
+	return []Check{
+		{"lambda", "lambda:ListFunctions", func(ctx context.Context) error {
+			_, err := client.ListFunctions(ctx, &lambda.ListFunctionsInput{MaxItems: aws.Int32(1)})
+			return err
+		}},
+	}
+}
+
+func lagChecks(cloudwatchClient *cloudwatch.Client, lambdaClient *lambda.Client) []Check {
+	return []Check{
+		{"lag", "cloudwatch:ListMetrics", func(ctx context.Context) error {
+			_, err := cloudwatchClient.ListMetrics(ctx, &cloudwatch.ListMetricsInput{Namespace: aws.String("AWS/Kinesis")})
+			return err
+		}},
+		{"lag", "lambda:ListEventSourceMappings", func(ctx context.Context) error {
+			_, err := lambdaClient.ListEventSourceMappings(ctx, &lambda.ListEventSourceMappingsInput{MaxItems: aws.Int32(1)})
+			return err
+		}},
+		cloudwatchCheck("lag", cloudwatchClient),
+	}
+}
+
+func metricsChecks(client *cloudwatch.Client) []Check {
+	return []Check{
+		{"metrics", "cloudwatch:ListMetrics", func(ctx context.Context) error {
+			_, err := client.ListMetrics(ctx, &cloudwatch.ListMetricsInput{RecentlyActive: cwtypes.RecentlyActivePt3h})
+			return err
+		}},
+		cloudwatchCheck("metrics", client),
+	}
+}
+
+func ecrChecks(client *ecr.Client) []Check {
+	return []Check{
+		{"ecr", "ecr:DescribeRepositories", func(ctx context.Context) error {
+			_, err := client.DescribeRepositories(ctx, &ecr.DescribeRepositoriesInput{MaxResults: aws.Int32(1)})
+			return err
+		}},
+		{"ecr", "ecr:DescribeImages", func(ctx context.Context) error {
+			// A missing repository means the call was permitted
+			_, err := client.DescribeImages(ctx, &ecr.DescribeImagesInput{RepositoryName: aws.String("aws-overview-permission-check")})
+			return err
+		}},
+	}
+}
+
+func apigwCheck(restClient *apigateway.Client, httpClient *apigatewayv2.Client) Check {
+	return Check{"apigw", "apigateway:GET", func(ctx context.Context) error {
+		// The one action covers both the REST and the HTTP APIs
+		if _, err := restClient.GetRestApis(ctx, &apigateway.GetRestApisInput{Limit: aws.Int32(1)}); err != nil {
+			return err
+		}
+		_, err := httpClient.GetApis(ctx, &apigatewayv2.GetApisInput{MaxResults: aws.String("1")})
+		return err
+	}}
+}
+
+func cloudfrontChecks(client *cloudfront.Client) []Check {
+	return []Check{
+		{"cloudfront", "cloudfront:ListDistributions", func(ctx context.Context) error {
+			_, err := client.ListDistributions(ctx, &cloudfront.ListDistributionsInput{MaxItems: aws.Int32(1)})
+			return err
+		}},
+	}
+}
@@ -0,0 +1,132 @@
+package permissions
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// expiredCodes are the authentication error codes of credentials that were
+// valid but have expired, as opposed to credentials that were never valid
+var expiredCodes = map[string]bool{
+	"ExpiredToken":          true,
+	"ExpiredTokenException": true,
+	"RequestExpired":        true,
+}
+
+// IsExpired reports whether err was caused by credentials that were valid
+// but have expired, including an expired SSO session
+func IsExpired(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr smithy.APIError
+	text := err.Error()
+	return errors.As(err, &apiErr) && expiredCodes[apiErr.ErrorCode()] ||
+		strings.Contains(text, "SSO session has expired") || strings.Contains(text, "refresh cached SSO token failed")
+}
+
+// problem is a common failure of AWS calls described in plain words, with
+// what usually fixes it
+type problem struct {
+	message string
+	hint    string
+}
+
+// diagnose recognizes the common failures of AWS calls: expired, invalid or
+// missing credentials, missing permissions, throttling, a missing region,
+// timeouts and an unreachable network
+func diagnose(err error) (problem, bool) {
+	var apiErr smithy.APIError
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	var missingRegion *aws.MissingRegionError
+	text := err.Error()
+
+	switch {
+	case IsExpired(err):
+		return problem{
+			message: "AWS credentials have expired",
+			hint:    "Refresh them, e.g. with aws sso login or by exporting a new AWS_SESSION_TOKEN, then press R to reload",
+		}, true
+	case isAuthenticationError(err) && errors.As(err, &apiErr):
+		return problem{
+			message: "AWS rejected the credentials (" + apiErr.ErrorCode() + ")",
+			hint:    "Check that AWS_PROFILE or AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY name valid credentials, and that the system clock is correct",
+		}, true
+	case strings.Contains(text, "get identity:") && strings.Contains(text, "failed to refresh cached credentials"):
+		return problem{
+			message: "no AWS credentials found",
+			hint:    "Set AWS_PROFILE to a profile of ~/.aws/config, export AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY, or run aws configure",
+		}, true
+	case IsAccessDenied(err):
+		message := "missing permission: " + text
+		if action, ok := MissingAction(err); ok {
+			message = "missing permission: " + action
+		}
+		return problem{
+			message: message,
+			hint:    "Grant it to your IAM user or role: aws-overview policy prints the policy the selected services need, and -check-permissions lists all that are missing",
+		}, true
+	case common.IsThrottlingError(err):
+		return problem{
+			message: "throttled by AWS" + operationSuffix(err),
+			hint:    "Lower -max-concurrency, or limit the calls to the service with -rate-limits, e.g. -rate-limits ecs=2",
+		}, true
+	case errors.As(err, &missingRegion), strings.Contains(text, "Missing Region"):
+		return problem{
+			message: "no AWS region is configured",
+			hint:    "Pass -region, set AWS_REGION, or set the region of the profile in ~/.aws/config",
+		}, true
+	case errors.As(err, &dnsErr):
+		return problem{
+			message: "cannot resolve " + dnsErr.Name + operationSuffix(err),
+			hint:    "Check the network connection, VPN and HTTPS_PROXY settings",
+		}, true
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return problem{
+			message: "timed out" + operationSuffix(err),
+			hint:    "Raise -timeout, or check the network connection, VPN and HTTPS_PROXY settings",
+		}, true
+	}
+	return problem{}, false
+}
+
+// Hint returns a suggested fix for err, or "" when it is not a common failure
+func Hint(err error) string {
+	if p, ok := diagnose(err); ok {
+		return p.hint
+	}
+	return ""
+}
+
+// Hints returns the suggested fixes for errs, each only once
+func Hints(errs []error) []string {
+	var hints []string
+	seen := make(map[string]bool)
+	for _, err := range errs {
+		hint := Hint(err)
+		if hint == "" || seen[hint] {
+			continue
+		}
+		seen[hint] = true
+		hints = append(hints, hint)
+	}
+	return hints
+}
+
+// operationSuffix names the IAM action of the call that failed with err,
+// e.g. " (ecs:ListServices)", or returns "" when err names no call
+func operationSuffix(err error) string {
+	var opErr *smithy.OperationError
+	if !errors.As(err, &opErr) {
+		return ""
+	}
+	return " (" + action(opErr) + ")"
+}
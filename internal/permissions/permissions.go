@@ -0,0 +1,125 @@
+// Package permissions recognizes AWS authorization failures and names the
+// IAM action that is missing, describes other common failures such as
+// expired credentials along with a suggested fix, dry-runs the calls the
+// collectors need so missing permissions can be reported before the UI
+// starts, and generates the least-privilege policy of the selected services.
+package permissions
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/aws/smithy-go"
+)
+
+// accessDeniedCodes are the error codes AWS services return when the caller
+// lacks the IAM permission for a request
+var accessDeniedCodes = map[string]bool{
+	"AccessDenied":          true,
+	"AccessDeniedException": true,
+	"UnauthorizedOperation": true,
+	"AuthorizationError":    true,
+	"NotAuthorized":         true,
+}
+
+// authenticationCodes are the error codes of requests that were rejected
+// before authorization was evaluated, e.g. because of expired credentials
+var authenticationCodes = map[string]bool{
+	"ExpiredToken":                true,
+	"ExpiredTokenException":       true,
+	"InvalidClientTokenId":        true,
+	"UnrecognizedClientException": true,
+	"SignatureDoesNotMatch":       true,
+	"AuthFailure":                 true,
+}
+
+// actionPrefixes maps SDK service IDs to the prefix of their IAM actions
+var actionPrefixes = map[string]string{
+	"Elastic Load Balancing v2": "elasticloadbalancing",
+	"CloudWatch":                "cloudwatch",
+	"CloudWatch Logs":           "logs",
+	"Cost Explorer":             "ce",
+	"EC2":                       "ec2",
+	"ECS":                       "ecs",
+	"RDS":                       "rds",
+	"SQS":                       "sqs",
+	"SSM":                       "ssm",
+}
+
+// actionNames maps operations to their IAM action where the two are named differently
+var actionNames = map[string]string{
+	"S3.ListBuckets":          "s3:ListAllMyBuckets",
+	"S3.GetBucketReplication": "s3:GetReplicationConfiguration",
+
+	// API Gateway authorizes requests by HTTP method and resource path
+	"API Gateway.GetRestApis": "apigateway:GET",
+	"API Gateway.GetStages":   "apigateway:GET",
+	"ApiGatewayV2.GetApis":    "apigateway:GET",
+	"ApiGatewayV2.GetStages":  "apigateway:GET",
+}
+
+// IsAccessDenied reports whether err is an AWS authorization failure
+func IsAccessDenied(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && accessDeniedCodes[apiErr.ErrorCode()]
+}
+
+// isAuthenticationError reports whether err was caused by invalid or expired credentials
+func isAuthenticationError(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && authenticationCodes[apiErr.ErrorCode()]
+}
+
+// MissingAction returns the IAM action, e.g. "elasticloadbalancing:DescribeTargetHealth",
+// whose permission is missing when err is an authorization failure
+func MissingAction(err error) (string, bool) {
+	if !IsAccessDenied(err) {
+		return "", false
+	}
+
+	var opErr *smithy.OperationError
+	if !errors.As(err, &opErr) {
+		return "", false
+	}
+	return action(opErr), true
+}
+
+// action returns the IAM action of the call that failed with opErr
+func action(opErr *smithy.OperationError) string {
+	if action, ok := actionNames[opErr.ServiceID+"."+opErr.OperationName]; ok {
+		return action
+	}
+
+	prefix, ok := actionPrefixes[opErr.ServiceID]
+	if !ok {
+		prefix = strings.ToLower(strings.ReplaceAll(opErr.ServiceID, " ", ""))
+	}
+	return prefix + ":" + opErr.OperationName
+}
+
+// Describe returns a short description of err in plain words for common
+// failures, such as expired credentials or throttling, naming the missing
+// IAM action for authorization failures, and the error text otherwise. Hint
+// suggests how to fix them.
+func Describe(err error) string {
+	if p, ok := diagnose(err); ok {
+		return p.message
+	}
+	return err.Error()
+}
+
+// DescribeAll describes each error on its own line, listing every missing
+// permission only once
+func DescribeAll(errs []error) string {
+	var lines []string
+	seen := make(map[string]bool)
+	for _, err := range errs {
+		line := Describe(err)
+		if seen[line] {
+			continue
+		}
+		seen[line] = true
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,232 @@
+package permissions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go"
+)
+
+func operationError(serviceID, operation, code string) error {
+	return &smithy.OperationError{
+		ServiceID:     serviceID,
+		OperationName: operation,
+		Err:           &smithy.GenericAPIError{Code: code, Message: "denied"},
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{
+			name:     "access denied",
+			err:      fmt.Errorf("failed to describe target health for TG web: %w", operationError("Elastic Load Balancing v2", "DescribeTargetHealth", "AccessDenied")),
+			expected: "missing permission: elasticloadbalancing:DescribeTargetHealth",
+		},
+		{
+			name:     "ec2 unauthorized operation",
+			err:      operationError("EC2", "DescribeInstances", "UnauthorizedOperation"),
+			expected: "missing permission: ec2:DescribeInstances",
+		},
+		{
+			name:     "s3 action named differently",
+			err:      operationError("S3", "GetBucketReplication", "AccessDenied"),
+			expected: "missing permission: s3:GetReplicationConfiguration",
+		},
+		{
+			name:     "other api error",
+			err:      operationError("SQS", "GetQueueAttributes", "QueueDoesNotExist"),
+			expected: "operation error SQS: GetQueueAttributes, api error QueueDoesNotExist: denied",
+		},
+		{
+			name:     "plain error",
+			err:      errors.New("connection refused"),
+			expected: "connection refused",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Describe(tc.err); got != tc.expected {
+				t.Errorf("Expected '%s', got '%s'", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestDescribeCommonFailures(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected string
+		hint     string // Part of the expected hint
+	}{
+		{
+			name:     "expired token",
+			err:      fmt.Errorf("failed to list clusters: %w", operationError("ECS", "ListClusters", "ExpiredTokenException")),
+			expected: "AWS credentials have expired",
+			hint:     "aws sso login",
+		},
+		{
+			name:     "expired sso session",
+			err:      errors.New("operation error ECS: ListClusters, get identity: get credentials: failed to refresh cached credentials, refresh cached SSO token failed, unable to refresh SSO token"),
+			expected: "AWS credentials have expired",
+			hint:     "aws sso login",
+		},
+		{
+			name:     "invalid credentials",
+			err:      operationError("STS", "GetCallerIdentity", "InvalidClientTokenId"),
+			expected: "AWS rejected the credentials (InvalidClientTokenId)",
+			hint:     "AWS_PROFILE",
+		},
+		{
+			name:     "no credentials",
+			err:      errors.New("operation error ECS: ListClusters, get identity: get credentials: failed to refresh cached credentials, no EC2 IMDS role found"),
+			expected: "no AWS credentials found",
+			hint:     "aws configure",
+		},
+		{
+			name:     "access denied",
+			err:      operationError("EC2", "DescribeInstances", "UnauthorizedOperation"),
+			expected: "missing permission: ec2:DescribeInstances",
+			hint:     "aws-overview policy",
+		},
+		{
+			name:     "throttling",
+			err:      fmt.Errorf("failed to get services: %w", operationError("ECS", "DescribeServices", "ThrottlingException")),
+			expected: "throttled by AWS (ecs:DescribeServices)",
+			hint:     "-rate-limits",
+		},
+		{
+			name:     "missing region",
+			err:      fmt.Errorf("resolve endpoint: %w", &aws.MissingRegionError{}),
+			expected: "no AWS region is configured",
+			hint:     "-region",
+		},
+		{
+			name:     "missing region from endpoint rules",
+			err:      errors.New("operation error EC2: DescribeInstances, resolve auth scheme: endpoint rule error, Invalid Configuration: Missing Region"),
+			expected: "no AWS region is configured",
+			hint:     "AWS_REGION",
+		},
+		{
+			name:     "unresolvable endpoint",
+			err:      &smithy.OperationError{ServiceID: "SQS", OperationName: "ListQueues", Err: &net.DNSError{Name: "sqs.eu-west-1.amazonaws.com", Err: "no such host"}},
+			expected: "cannot resolve sqs.eu-west-1.amazonaws.com (sqs:ListQueues)",
+			hint:     "HTTPS_PROXY",
+		},
+		{
+			name:     "timeout",
+			err:      &smithy.OperationError{ServiceID: "RDS", OperationName: "DescribeDBInstances", Err: fmt.Errorf("request send failed: %w", context.DeadlineExceeded)},
+			expected: "timed out (rds:DescribeDBInstances)",
+			hint:     "-timeout",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Describe(tc.err); got != tc.expected {
+				t.Errorf("Expected '%s', got '%s'", tc.expected, got)
+			}
+			if hint := Hint(tc.err); !strings.Contains(hint, tc.hint) {
+				t.Errorf("Expected the hint to mention '%s', got '%s'", tc.hint, hint)
+			}
+		})
+	}
+
+	if hint := Hint(errors.New("connection refused")); hint != "" {
+		t.Errorf("Expected no hint for an unknown failure, got '%s'", hint)
+	}
+}
+
+func TestIsExpired(t *testing.T) {
+	for _, err := range []error{
+		fmt.Errorf("failed to list clusters: %w", operationError("ECS", "ListClusters", "ExpiredTokenException")),
+		operationError("SQS", "ListQueues", "RequestExpired"),
+		errors.New("operation error ECS: ListClusters, get identity: get credentials: failed to refresh cached credentials, refresh cached SSO token failed, unable to refresh SSO token"),
+	} {
+		if !IsExpired(err) {
+			t.Errorf("Expected %v to be expired credentials", err)
+		}
+	}
+
+	for _, err := range []error{nil, operationError("STS", "GetCallerIdentity", "InvalidClientTokenId"), errors.New("connection refused")} {
+		if IsExpired(err) {
+			t.Errorf("Expected %v not to be expired credentials", err)
+		}
+	}
+}
+
+func TestHintsDeduplicates(t *testing.T) {
+	errs := []error{
+		operationError("ECS", "ListServices", "ThrottlingException"),
+		operationError("ECS", "DescribeServices", "ThrottlingException"),
+		errors.New("connection refused"),
+	}
+	if hints := Hints(errs); len(hints) != 1 {
+		t.Errorf("Expected the throttling hint once, got %v", hints)
+	}
+}
+
+func TestDescribeAllDeduplicatesMissingPermissions(t *testing.T) {
+	errs := []error{
+		fmt.Errorf("TG a: %w", operationError("Elastic Load Balancing v2", "DescribeTargetHealth", "AccessDenied")),
+		fmt.Errorf("TG b: %w", operationError("Elastic Load Balancing v2", "DescribeTargetHealth", "AccessDenied")),
+		errors.New("timeout"),
+	}
+
+	expected := "missing permission: elasticloadbalancing:DescribeTargetHealth\ntimeout"
+	if got := DescribeAll(errs); got != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, got)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected Status
+	}{
+		{"success", nil, Allowed},
+		{"dry run", operationError("EC2", "DescribeInstances", "DryRunOperation"), Allowed},
+		{"not found", operationError("ECS", "ListServices", "ClusterNotFoundException"), Allowed},
+		{"denied", operationError("ECS", "ListServices", "AccessDeniedException"), Denied},
+		{"expired credentials", operationError("ECS", "ListServices", "ExpiredTokenException"), Unknown},
+		{"network", errors.New("dial tcp: no such host"), Unknown},
+		{"no resource", errNoResource, Skipped},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := classify(tc.err); got != tc.expected {
+				t.Errorf("Expected status %d, got %d", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestFormatReport(t *testing.T) {
+	results := []Result{
+		{Check: Check{Service: "ecs", Action: "ecs:ListClusters"}, Status: Allowed},
+		{Check: Check{Service: "alb", Action: "elasticloadbalancing:DescribeTargetHealth"}, Status: Denied},
+	}
+
+	report := FormatReport(results)
+	for _, expected := range []string{
+		"ok       ecs  ecs:ListClusters",
+		"MISSING  alb  elasticloadbalancing:DescribeTargetHealth",
+		"2 checks, 1 missing permissions, 0 errors, 0 skipped",
+	} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("Expected report to contain '%s', got:\n%s", expected, report)
+		}
+	}
+}
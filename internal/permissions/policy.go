@@ -0,0 +1,189 @@
+package permissions
+
+import "sort"
+
+// readActions are the read-only IAM actions the collector of each service
+// calls, including those of tailing the error logs of its resources
+var readActions = map[string][]string{
+	"alb": {
+		"elasticloadbalancing:DescribeLoadBalancers",
+		"elasticloadbalancing:DescribeListeners",
+		"elasticloadbalancing:DescribeRules",
+		"elasticloadbalancing:DescribeListenerCertificates",
+		"elasticloadbalancing:DescribeTargetGroups",
+		"elasticloadbalancing:DescribeTargetHealth",
+		"acm:DescribeCertificate",
+	},
+	"rds": {
+		"rds:DescribeDBInstances",
+		"rds:DescribePendingMaintenanceActions",
+		"cloudwatch:GetMetricData",
+		"ec2:DescribeInstanceTypes",
+		"logs:DescribeLogGroups",
+		"logs:FilterLogEvents",
+		"rds:DescribeEvents",
+		"cloudwatch:DescribeAlarms",
+		"cloudwatch:DescribeAlarmHistory",
+		"cloudtrail:LookupEvents",
+	},
+	"ec2": {"ec2:DescribeInstances", "ec2:DescribeInstanceStatus", "ec2:DescribeSecurityGroups", "ec2:DescribeSpotInstanceRequests"},
+	"ecs": {
+		"ecs:ListClusters",
+		"ecs:DescribeClusters",
+		"ecs:ListServices",
+		"ecs:DescribeServices",
+		"ecs:ListTagsForResource",
+		"ecs:DescribeTaskDefinition",
+		"events:ListRules",
+		"events:ListTargetsByRule",
+		"cloudwatch:GetMetricData",
+		"logs:FilterLogEvents",
+		"cloudwatch:DescribeAlarms",
+		"cloudwatch:DescribeAlarmHistory",
+		"cloudtrail:LookupEvents",
+	},
+	"sqs": {"sqs:ListQueues", "sqs:GetQueueAttributes", "cloudwatch:GetMetricData"},
+	"ssm": {"ssm:DescribeInstanceInformation", "ssm:DescribeInstancePatchStates", "ec2:DescribeInstances"},
+	"dns": {
+		"route53:ListHostedZones",
+		"route53:ListResourceRecordSets",
+		"cloudfront:ListDistributions",
+		"elasticloadbalancing:DescribeLoadBalancers",
+		"ec2:DescribeInstances",
+		"ec2:DescribeAddresses",
+	},
+	"dr": {
+		"rds:DescribeDBInstances",
+		"s3:ListAllMyBuckets",
+		"s3:GetBucketLocation",
+		"s3:GetReplicationConfiguration",
+		"ecr:DescribeRegistry",
+		"ecr:DescribeRepositories",
+		"dynamodb:ListTables",
+		"dynamodb:DescribeTable",
+	},
+	"sns": {"sns:ListTopics", "sns:ListSubscriptions", "sns:GetSubscriptionAttributes"},
+	"lambda": {
+		"lambda:ListFunctions",
+		"logs:FilterLogEvents",
+		"cloudwatch:DescribeAlarms",
+		"cloudwatch:DescribeAlarmHistory",
+		"cloudtrail:LookupEvents",
+	},
+	"lag":        {"cloudwatch:ListMetrics", "cloudwatch:GetMetricData", "lambda:ListEventSourceMappings"},
+	"cloudfront": {"cloudfront:ListDistributions"},
+	"ebs":        {"ec2:DescribeVolumes", "cloudwatch:GetMetricData"},
+	"vpc":        {"ec2:DescribeVpcs", "ec2:DescribeSubnets", "ec2:DescribeNatGateways", "ec2:DescribeInternetGateways", "ec2:DescribeVpcEndpoints", "cloudwatch:GetMetricData"},
+	"ecr":        {"ecr:DescribeRepositories", "ecr:DescribeImages"},
+	"apigw":      {"apigateway:GET", "cloudwatch:GetMetricData"},
+	"cost":       {"ce:GetCostAndUsage"},
+	"findings":   {"cloudwatch:GetMetricData"},
+	"hygiene":    {"cloudwatch:GetMetricData"},
+	"metrics":    {"cloudwatch:ListMetrics", "cloudwatch:GetMetricData"},
+	"container-insights": {
+		"cloudwatch:GetMetricData",
+	},
+}
+
+// writeActions are the IAM actions of the actions each service offers with
+// -allow-actions, including the calls that track their progress
+var writeActions = map[string][]string{
+	"alb":        {"elasticloadbalancing:DeregisterTargets", "elasticloadbalancing:RegisterTargets"},
+	"ec2":        {"ec2:StartInstances", "ec2:StopInstances", "ec2:RebootInstances", "ssm:StartSession", "ssm:TerminateSession"},
+	"ecs":        {"ecs:RunTask", "ecs:DescribeTasks", "ecs:DescribeTaskDefinition", "ecs:UpdateService", "ecs:ListTasks", "ecs:ExecuteCommand"},
+	"sqs":        {"sqs:ReceiveMessage", "sqs:ChangeMessageVisibility", "sqs:PurgeQueue"},
+	"lambda":     {"lambda:InvokeFunction"},
+	"cloudfront": {"cloudfront:CreateInvalidation", "cloudfront:GetInvalidation"},
+}
+
+// runbookActions are the IAM actions of the runbook steps
+var runbookActions = []string{"ecs:UpdateService", "events:DisableRule", "cloudwatch:SetAlarmState"}
+
+// PolicyDocument is an IAM policy document
+type PolicyDocument struct {
+	Version   string            `json:"Version"`
+	Statement []PolicyStatement `json:"Statement"`
+}
+
+// PolicyStatement is a statement of an IAM policy document
+type PolicyStatement struct {
+	Sid       string                       `json:"Sid"`
+	Effect    string                       `json:"Effect"`
+	Action    []string                     `json:"Action"`
+	Resource  string                       `json:"Resource"`
+	Condition map[string]map[string]string `json:"Condition,omitempty"`
+}
+
+// Policy returns the least-privilege policy for the given services (see
+// Checks). Unless allowActions is set it only grants reads. With
+// allowActions it also grants the actions of the services, and those of the
+// runbook steps when runbooks is set.
+func Policy(services []string, allowActions, runbooks bool) PolicyDocument {
+	policy := PolicyDocument{Version: "2012-10-17"}
+
+	var read, write []string
+	for _, service := range services {
+		read = append(read, readActions[service]...)
+		if allowActions {
+			write = append(write, writeActions[service]...)
+		}
+	}
+	if allowActions && runbooks {
+		write = append(write, runbookActions...)
+	}
+
+	if len(read) > 0 {
+		policy.Statement = append(policy.Statement, PolicyStatement{
+			Sid:      "AWSOverviewRead",
+			Effect:   "Allow",
+			Action:   uniqueSorted(read),
+			Resource: "*",
+		})
+	}
+	if len(write) > 0 {
+		policy.Statement = append(policy.Statement, PolicyStatement{
+			Sid:      "AWSOverviewActions",
+			Effect:   "Allow",
+			Action:   uniqueSorted(write),
+			Resource: "*",
+		})
+	}
+	// One-off tasks pass the task and execution roles of the service to ECS
+	if allowActions && contains(services, "ecs") {
+		policy.Statement = append(policy.Statement, PolicyStatement{
+			Sid:      "AWSOverviewPassTaskRoles",
+			Effect:   "Allow",
+			Action:   []string{"iam:PassRole"},
+			Resource: "*",
+			Condition: map[string]map[string]string{
+				"StringEquals": {"iam:PassedToService": "ecs-tasks.amazonaws.com"},
+			},
+		})
+	}
+
+	return policy
+}
+
+// uniqueSorted returns the distinct values of s in order
+func uniqueSorted(s []string) []string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, value := range s {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
+
+// contains reports whether s holds value
+func contains(s []string, value string) bool {
+	for _, v := range s {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
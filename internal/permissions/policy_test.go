@@ -0,0 +1,67 @@
+package permissions
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestPolicy(t *testing.T) {
+	policy := Policy([]string{"rds", "sqs"}, false, true)
+
+	if len(policy.Statement) != 1 {
+		t.Fatalf("Expected only the read statement without -allow-actions, got %+v", policy.Statement)
+	}
+	actions := strings.Join(policy.Statement[0].Action, ",")
+	if actions != "cloudtrail:LookupEvents,cloudwatch:DescribeAlarmHistory,cloudwatch:DescribeAlarms,cloudwatch:GetMetricData,ec2:DescribeInstanceTypes,logs:DescribeLogGroups,logs:FilterLogEvents,rds:DescribeDBInstances,rds:DescribeEvents,rds:DescribePendingMaintenanceActions,sqs:GetQueueAttributes,sqs:ListQueues" {
+		t.Errorf("Expected the distinct actions of RDS and SQS in order, got %s", actions)
+	}
+	if policy.Version != "2012-10-17" || policy.Statement[0].Resource != "*" {
+		t.Errorf("Unexpected policy %+v", policy)
+	}
+
+	data, err := json.Marshal(policy)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if strings.Contains(string(data), "Condition") {
+		t.Errorf("Expected no empty condition in %s", data)
+	}
+}
+
+func TestPolicyWithActions(t *testing.T) {
+	policy := Policy([]string{"ec2", "ecs", "lambda"}, true, true)
+
+	if len(policy.Statement) != 3 {
+		t.Fatalf("Expected read, action and pass role statements, got %+v", policy.Statement)
+	}
+	actions := strings.Join(policy.Statement[1].Action, ",")
+	for _, action := range []string{"ec2:StopInstances", "ecs:RunTask", "lambda:InvokeFunction", "ecs:UpdateService", "events:DisableRule"} {
+		if !strings.Contains(actions, action) {
+			t.Errorf("Expected %s among the actions, got %s", action, actions)
+		}
+	}
+	if policy.Statement[2].Condition["StringEquals"]["iam:PassedToService"] != "ecs-tasks.amazonaws.com" {
+		t.Errorf("Expected iam:PassRole to be limited to ECS tasks, got %+v", policy.Statement[2])
+	}
+
+	// Runbook steps are only granted when runbooks are configured
+	policy = Policy([]string{"lambda"}, true, false)
+	if strings.Contains(strings.Join(policy.Statement[1].Action, ","), "ecs:UpdateService") {
+		t.Errorf("Expected no runbook actions without runbooks, got %+v", policy.Statement[1])
+	}
+
+	// Scaling and redeploying from the ECS tab need UpdateService regardless
+	policy = Policy([]string{"ecs"}, true, false)
+	if !strings.Contains(strings.Join(policy.Statement[1].Action, ","), "ecs:UpdateService") {
+		t.Errorf("Expected ecs:UpdateService for the ECS tab, got %+v", policy.Statement[1])
+	}
+}
+
+func TestPolicyCoversChecks(t *testing.T) {
+	for _, service := range []string{"alb", "rds", "ec2", "ecs", "sqs", "ssm", "dns", "dr", "sns", "lambda", "lag", "cloudfront", "ebs", "vpc", "ecr", "apigw", "cost", "findings", "hygiene", "metrics", "container-insights"} {
+		if len(readActions[service]) == 0 {
+			t.Errorf("No read actions for service %s", service)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Package pins stores the metrics pinned to the Custom Metrics tab, so they
+// are kept across sessions without editing the config file.
+package pins
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/correctedcloud/aws-overview/internal/seal"
+	"github.com/correctedcloud/aws-overview/pkg/metrics"
+)
+
+// File is the layout of the pins file
+type File struct {
+	Pins []metrics.Pin `json:"pins"`
+}
+
+// DefaultPath returns the default location of the pins file
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "aws-overview", "pins.json")
+}
+
+// Load reads the pins from path, decrypting them with sealer when they were
+// saved encrypted. It returns no pins without an error when nothing has been
+// pinned yet.
+func Load(path string, sealer *seal.Sealer) ([]metrics.Pin, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pins: %w", err)
+	}
+	data, err = sealer.Open(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pins: %w", err)
+	}
+
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to decode pins: %w", err)
+	}
+	return file.Pins, nil
+}
+
+// Save writes the pins to path, encrypted by sealer unless it is nil,
+// creating parent directories as needed. The file is written to a temporary
+// name first so that a crash never leaves a half-written file behind.
+func Save(path string, pins []metrics.Pin, sealer *seal.Sealer) error {
+	data, err := json.MarshalIndent(File{Pins: pins}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode pins: %w", err)
+	}
+	data, err = sealer.Seal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode pins: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create pins directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write pins: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write pins: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package pins
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/correctedcloud/aws-overview/pkg/metrics"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "pins.json")
+
+	pinned := []metrics.Pin{
+		{Metric: metrics.Metric{Namespace: "Checkout", Name: "OrdersPlaced", Dimensions: map[string]string{"Service": "checkout"}}, Stat: "Sum"},
+		{Metric: metrics.Metric{Namespace: "Checkout", Name: "CartAbandonments"}, Stat: "Average"},
+	}
+	if err := Save(path, pinned, nil); err != nil {
+		t.Fatalf("Expected no error saving pins, got %v", err)
+	}
+
+	loaded, err := Load(path, nil)
+	if err != nil {
+		t.Fatalf("Expected no error loading pins, got %v", err)
+	}
+	if !reflect.DeepEqual(loaded, pinned) {
+		t.Errorf("Expected pins to round-trip, got %+v", loaded)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	loaded, err := Load(filepath.Join(t.TempDir(), "missing.json"), nil)
+	if err != nil {
+		t.Fatalf("Expected no error for a missing pins file, got %v", err)
+	}
+	if loaded != nil {
+		t.Errorf("Expected no pins for a missing file, got %+v", loaded)
+	}
+}
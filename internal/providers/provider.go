@@ -0,0 +1,29 @@
+// Package providers defines the interface of the resource collectors added
+// to the overview by the tools embedding it, so that new collectors can be
+// added without changing the UI. Each provider gets a tab of its own.
+package providers
+
+import "context"
+
+// Provider collects and renders the resources of one service. Load runs in
+// the background while Render is called from the UI, so implementations must
+// be safe for concurrent use.
+type Provider interface {
+	// Name is the title of the provider's tab and must be unique
+	Name() string
+
+	// Load collects the resources and summarizes them for the Overview tab.
+	// It returns an error when nothing could be loaded; resources that
+	// failed to load while others loaded are reported as warnings.
+	Load(ctx context.Context) (Summary, error)
+
+	// Render formats the resources of the last successful Load for a tab
+	// that is width columns wide
+	Render(width int) string
+}
+
+// Summary is the outcome of a load shown on the Overview tab
+type Summary struct {
+	Text     string   // One line, e.g. "4 LBs, 6/9 healthy targets"
+	Warnings []string // Problems flagged below the line, e.g. a full DLQ
+}
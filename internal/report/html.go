@@ -0,0 +1,81 @@
+package report
+
+import (
+	"html/template"
+	"strings"
+)
+
+// htmlTemplate lays the report out for email clients, which ignore style
+// sheets, so the few styles are inline
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif; color: #222;">
+<h1 style="font-size: 20px;">{{.Title}}</h1>
+<p style="color: #666;">Generated {{.Generated}} by aws-overview.</p>
+
+<h2 style="font-size: 16px;">Needs attention</h2>
+{{- if .Flagged}}
+{{- range .Flagged}}
+<h3 style="font-size: 14px;">{{.Name}}</h3>
+<ul>
+{{- range .Items}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- else}}
+<p>Nothing flagged.</p>
+{{- end}}
+
+<h2 style="font-size: 16px;">Active alarms</h2>
+{{- if .AlarmsErr}}
+<p>Could not load the alarms: {{.AlarmsErr}}</p>
+{{- else if .Alarms}}
+<ul>
+{{- range .Alarms}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- else}}
+<p>No CloudWatch alarms are firing.</p>
+{{- end}}
+
+<h2 style="font-size: 16px;">Status</h2>
+<ul>
+{{- range .Statuses}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+</body>
+</html>
+`))
+
+// HTML renders the report as an HTML document under the given title, e.g.
+// for an email. The notes and the snapshot are left out.
+func HTML(r Report, title string) (string, error) {
+	var alarms []string
+	for _, alarm := range r.Alarms {
+		alarms = append(alarms, alarm.Name+" "+alarmDetail(alarm, r.Generated))
+	}
+
+	var output strings.Builder
+	err := htmlTemplate.Execute(&output, struct {
+		Title     string
+		Generated string
+		Flagged   []Group
+		Alarms    []string
+		AlarmsErr string
+		Statuses  []string
+	}{
+		Title:     r.Title(title),
+		Generated: r.Generated.Format("Mon Jan 2 2006 15:04 MST"),
+		Flagged:   r.Flagged,
+		Alarms:    alarms,
+		AlarmsErr: r.AlarmsErr,
+		Statuses:  r.Statuses,
+	})
+	if err != nil {
+		return "", err
+	}
+	return output.String(), nil
+}
@@ -0,0 +1,145 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2/types"
+)
+
+// Email is a report to send
+type Email struct {
+	From    string
+	To      []string
+	Subject string
+	HTML    string
+	Text    string // Shown by clients that do not display HTML
+}
+
+// Sender sends emails
+type Sender interface {
+	Send(ctx context.Context, email Email) error
+}
+
+// sesClientAPI defines the interface for the SES client
+type sesClientAPI interface {
+	SendEmail(ctx context.Context, params *sesv2.SendEmailInput, optFns ...func(*sesv2.Options)) (*sesv2.SendEmailOutput, error)
+}
+
+// SESSender sends emails through Amazon SES. The sender address must be a
+// verified identity.
+type SESSender struct {
+	client sesClientAPI
+}
+
+// NewSESSender returns a sender using the SES client
+func NewSESSender(client sesClientAPI) *SESSender {
+	return &SESSender{client: client}
+}
+
+// Send sends email through SES
+func (s *SESSender) Send(ctx context.Context, email Email) error {
+	_, err := s.client.SendEmail(ctx, &sesv2.SendEmailInput{
+		FromEmailAddress: aws.String(email.From),
+		Destination:      &types.Destination{ToAddresses: email.To},
+		Content: &types.EmailContent{Simple: &types.Message{
+			Subject: &types.Content{Data: aws.String(email.Subject), Charset: aws.String("UTF-8")},
+			Body: &types.Body{
+				Html: &types.Content{Data: aws.String(email.HTML), Charset: aws.String("UTF-8")},
+				Text: &types.Content{Data: aws.String(email.Text), Charset: aws.String("UTF-8")},
+			},
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send the report through SES: %w", err)
+	}
+	return nil
+}
+
+// SMTPSender sends emails through an SMTP server, with STARTTLS when the
+// server offers it
+type SMTPSender struct {
+	Addr string    // host:port, e.g. "smtp.example.com:587"
+	Auth smtp.Auth // nil to send without authenticating
+}
+
+// Send sends email through the SMTP server. The context is not honored
+// beyond the check before connecting, as net/smtp takes none.
+func (s SMTPSender) Send(ctx context.Context, email Email) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	message, err := buildMessage(email, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := smtp.SendMail(s.Addr, s.Auth, email.From, email.To, message); err != nil {
+		return fmt.Errorf("failed to send the report through %s: %w", s.Addr, err)
+	}
+	return nil
+}
+
+// buildMessage returns email as a MIME message with a text and an HTML part
+func buildMessage(email Email, date time.Time) ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain", email.Text},
+		{"text/html", email.HTML},
+	} {
+		writer, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType + "; charset=UTF-8"},
+			"Content-Transfer-Encoding": {"8bit"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to build the report email: %w", err)
+		}
+		// SMTP lines end in CRLF
+		content := strings.ReplaceAll(strings.ReplaceAll(part.content, "\r\n", "\n"), "\n", "\r\n")
+		writer.Write([]byte(content))
+	}
+	if err := parts.Close(); err != nil {
+		return nil, fmt.Errorf("failed to build the report email: %w", err)
+	}
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", email.From)
+	fmt.Fprintf(&message, "To: %s\r\n", strings.Join(email.To, ", "))
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", email.Subject))
+	fmt.Fprintf(&message, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	fmt.Fprintf(&message, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&message, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", parts.Boundary())
+	message.Write(body.Bytes())
+	return message.Bytes(), nil
+}
+
+// Subject returns the subject of the email of r, e.g. "aws-overview daily
+// report: us-east-1, 4 resources need attention"
+func Subject(r Report, title string) string {
+	subject := r.Title(title)
+	switch count := r.FlaggedCount(); count {
+	case 0:
+		subject += ", nothing flagged"
+	case 1:
+		subject += ", 1 resource needs attention"
+	default:
+		subject += fmt.Sprintf(", %d resources need attention", count)
+	}
+	switch len(r.Alarms) {
+	case 0:
+	case 1:
+		subject += ", 1 alarm firing"
+	default:
+		subject += fmt.Sprintf(", %d alarms firing", len(r.Alarms))
+	}
+	return subject
+}
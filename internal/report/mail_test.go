@@ -0,0 +1,74 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+)
+
+// Mock SES client
+type mockSESClient struct {
+	sendEmailFunc func(ctx context.Context, params *sesv2.SendEmailInput, optFns ...func(*sesv2.Options)) (*sesv2.SendEmailOutput, error)
+}
+
+func (m *mockSESClient) SendEmail(ctx context.Context, params *sesv2.SendEmailInput, optFns ...func(*sesv2.Options)) (*sesv2.SendEmailOutput, error) {
+	return m.sendEmailFunc(ctx, params, optFns...)
+}
+
+func TestSESSender(t *testing.T) {
+	var sent *sesv2.SendEmailInput
+	sender := NewSESSender(&mockSESClient{
+		sendEmailFunc: func(ctx context.Context, params *sesv2.SendEmailInput, optFns ...func(*sesv2.Options)) (*sesv2.SendEmailOutput, error) {
+			sent = params
+			return &sesv2.SendEmailOutput{}, nil
+		},
+	})
+
+	email := Email{From: "ops@example.com", To: []string{"a@example.com", "b@example.com"}, Subject: "report", HTML: "<p>hi</p>", Text: "hi"}
+	if err := sender.Send(context.Background(), email); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if aws.ToString(sent.FromEmailAddress) != "ops@example.com" || len(sent.Destination.ToAddresses) != 2 {
+		t.Errorf("Unexpected addresses %+v", sent)
+	}
+	body := sent.Content.Simple.Body
+	if aws.ToString(body.Html.Data) != "<p>hi</p>" || aws.ToString(body.Text.Data) != "hi" {
+		t.Errorf("Expected both the HTML and the text body, got %+v", body)
+	}
+
+	sender = NewSESSender(&mockSESClient{
+		sendEmailFunc: func(ctx context.Context, params *sesv2.SendEmailInput, optFns ...func(*sesv2.Options)) (*sesv2.SendEmailOutput, error) {
+			return nil, errors.New("MessageRejected: Email address is not verified")
+		},
+	})
+	if err := sender.Send(context.Background(), email); err == nil || !strings.Contains(err.Error(), "failed to send the report through SES") {
+		t.Errorf("Expected the SES error, got %v", err)
+	}
+}
+
+func TestBuildMessage(t *testing.T) {
+	email := Email{From: "ops@example.com", To: []string{"a@example.com", "b@example.com"}, Subject: "Report: 🚨 1 alarm", HTML: "<p>hi</p>", Text: "hi"}
+	message, err := buildMessage(email, time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("buildMessage() error = %v", err)
+	}
+
+	for _, expected := range []string{
+		"From: ops@example.com\r\n",
+		"To: a@example.com, b@example.com\r\n",
+		"Subject: =?UTF-8?q?",
+		"Date: Fri, 10 May 2024 12:00:00 +0000\r\n",
+		"Content-Type: multipart/alternative; boundary=",
+		"Content-Type: text/plain; charset=UTF-8\r\n\r\nhi\r\n",
+		"Content-Type: text/html; charset=UTF-8\r\n\r\n<p>hi</p>\r\n",
+	} {
+		if !strings.Contains(string(message), expected) {
+			t.Errorf("Expected message to contain %q, got:\n%s", expected, message)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// Package report holds the state of the selected services at one point in
+// time for sharing outside the UI, and renders it as markdown for an on-call
+// handoff or as HTML for an email.
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/correctedcloud/aws-overview/pkg/events"
+)
+
+// Group is a service and the resources flagged on its Overview block
+type Group struct {
+	Name  string
+	Items []string // e.g. "🚨 orders: 12 messages in DLQ orders-dlq"
+}
+
+// Report is the state of the selected services at one point in time
+type Report struct {
+	Region    string
+	Generated time.Time
+	Flagged   []Group        // Services with flagged resources or that failed to load
+	Alarms    []events.Alarm // CloudWatch alarms in the ALARM state
+	AlarmsErr string         // Why the alarms failed to load, empty when they loaded
+	Notes     []string
+	Statuses  []string // Status line of each service, as on the Overview tab
+	Snapshot  []byte   // The loaded data as a session snapshot in JSON, nil to leave it out
+}
+
+// Title returns the title of the report, e.g. "On-call handoff: us-east-1"
+func (r Report) Title(kind string) string {
+	if r.Region == "" {
+		return kind
+	}
+	return kind + ": " + r.Region
+}
+
+// FlaggedCount returns how many resources are flagged across services
+func (r Report) FlaggedCount() int {
+	count := 0
+	for _, group := range r.Flagged {
+		count += len(group.Items)
+	}
+	return count
+}
+
+// alarmDetail describes how long an alarm has been firing at now and why
+func alarmDetail(alarm events.Alarm, now time.Time) string {
+	age := strings.TrimSuffix(now.Sub(alarm.Since).Truncate(time.Minute).String(), "0s")
+	detail := fmt.Sprintf("since %s (%s ago)", alarm.Since.Local().Format("Jan 2 15:04"), age)
+	if alarm.Reason != "" {
+		detail += ": " + alarm.Reason
+	}
+	return detail
+}
+
+// Markdown renders the report as markdown under the given title, e.g. for
+// pasting into an on-call handoff
+func Markdown(r Report, title string) string {
+	var output strings.Builder
+	output.WriteString("# " + r.Title(title) + "\n\n")
+	output.WriteString(fmt.Sprintf("Generated %s by aws-overview.\n\n", r.Generated.Format("Mon Jan 2 2006 15:04 MST")))
+
+	output.WriteString("## Needs attention\n\n")
+	if len(r.Flagged) == 0 {
+		output.WriteString("Nothing flagged.\n\n")
+	}
+	for _, group := range r.Flagged {
+		output.WriteString("### " + group.Name + "\n\n")
+		for _, item := range group.Items {
+			output.WriteString("- " + item + "\n")
+		}
+		output.WriteString("\n")
+	}
+
+	output.WriteString("## Active alarms\n\n")
+	switch {
+	case r.AlarmsErr != "":
+		output.WriteString("Could not load the alarms: " + r.AlarmsErr + "\n\n")
+	case len(r.Alarms) == 0:
+		output.WriteString("No CloudWatch alarms are firing.\n\n")
+	default:
+		for _, alarm := range r.Alarms {
+			// Alarm names often hold underscores, which markdown would
+			// take for emphasis
+			output.WriteString("- `" + alarm.Name + "` " + alarmDetail(alarm, r.Generated) + "\n")
+		}
+		output.WriteString("\n")
+	}
+
+	output.WriteString("## Notes\n\n")
+	if len(r.Notes) == 0 {
+		output.WriteString("None.\n")
+	}
+	for _, note := range r.Notes {
+		output.WriteString("- " + note + "\n")
+	}
+	output.WriteString("\n")
+
+	output.WriteString("## Status\n\n")
+	for _, status := range r.Statuses {
+		output.WriteString("- " + status + "\n")
+	}
+
+	if r.Snapshot != nil {
+		output.WriteString("\n## Snapshot\n\n")
+		output.WriteString("<details>\n<summary>Loaded data as an aws-overview session snapshot (JSON)</summary>\n\n")
+		output.WriteString("```json\n" + string(r.Snapshot) + "\n```\n\n")
+		output.WriteString("</details>\n")
+	}
+
+	return output.String()
+}
@@ -0,0 +1,80 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/correctedcloud/aws-overview/pkg/events"
+)
+
+// testReport returns a report with a flagged resource and a firing alarm
+func testReport() Report {
+	generated := time.Date(2024, 5, 10, 12, 0, 0, 0, time.Local)
+	return Report{
+		Region:    "us-east-1",
+		Generated: generated,
+		Flagged:   []Group{{Name: "SQS Queues", Items: []string{"🚨 orders: 12 messages in DLQ orders-dlq"}}},
+		Alarms: []events.Alarm{
+			{Name: "orders_db_cpu", Reason: "Threshold Crossed", Since: generated.Add(-90 * time.Minute)},
+		},
+		Notes:    []string{"orders-db failover at 02:00"},
+		Statuses: []string{"✅ SQS Queues: 4 queues", "✅ Lambda Functions: 3 functions <new>"},
+		Snapshot: []byte(`{"region":"us-east-1"}`),
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	output := Markdown(testReport(), "On-call handoff")
+	for _, expected := range []string{
+		"# On-call handoff: us-east-1\n",
+		"### SQS Queues\n\n- 🚨 orders: 12 messages in DLQ orders-dlq\n",
+		"- `orders_db_cpu` since May 10 10:30 (1h30m ago): Threshold Crossed\n",
+		"## Notes\n\n- orders-db failover at 02:00\n",
+		"- ✅ SQS Queues: 4 queues\n",
+		"```json\n{\"region\":\"us-east-1\"}\n```",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+
+	output = Markdown(Report{AlarmsErr: "missing permission: cloudwatch:DescribeAlarms"}, "On-call handoff")
+	for _, expected := range []string{"# On-call handoff\n", "Nothing flagged.", "Could not load the alarms: missing permission", "## Notes\n\nNone."} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "## Snapshot") {
+		t.Errorf("Expected no snapshot section without a snapshot, got:\n%s", output)
+	}
+}
+
+func TestHTML(t *testing.T) {
+	output, err := HTML(testReport(), "aws-overview report")
+	if err != nil {
+		t.Fatalf("HTML() error = %v", err)
+	}
+	for _, expected := range []string{
+		"<h1 style=\"font-size: 20px;\">aws-overview report: us-east-1</h1>",
+		"<li>🚨 orders: 12 messages in DLQ orders-dlq</li>",
+		"<li>orders_db_cpu since May 10 10:30 (1h30m ago): Threshold Crossed</li>",
+		"3 functions &lt;new&gt;",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "us-east-1\"}") || strings.Contains(output, "failover") {
+		t.Errorf("Expected the snapshot and notes to be left out, got:\n%s", output)
+	}
+}
+
+func TestSubject(t *testing.T) {
+	if got := Subject(testReport(), "aws-overview report"); got != "aws-overview report: us-east-1, 1 resource needs attention, 1 alarm firing" {
+		t.Errorf("Unexpected subject %q", got)
+	}
+	if got := Subject(Report{}, "aws-overview report"); got != "aws-overview report, nothing flagged" {
+		t.Errorf("Unexpected subject %q", got)
+	}
+}
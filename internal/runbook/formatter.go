@@ -0,0 +1,70 @@
+package runbook
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// FormatRunbooks formats the runbooks and their steps for terminal display,
+// marking the runbook at index selected (-1 for none)
+func FormatRunbooks(runbooks []Runbook, selected int) string {
+	if len(runbooks) == 0 {
+		return "No runbooks configured"
+	}
+
+	var output strings.Builder
+	output.WriteString("RUNBOOKS\n")
+	output.WriteString(common.Rule("RUNBOOKS", "=") + "\n\n")
+
+	for i, runbook := range runbooks {
+		marker := "  "
+		if i == selected {
+			marker = "> "
+		}
+
+		output.WriteString(fmt.Sprintf("%s%s %s", marker, common.Symbol("🚨"), runbook.Name))
+		if runbook.Description != "" {
+			output.WriteString(" - " + runbook.Description)
+		}
+		output.WriteString("\n")
+		for j, step := range runbook.Steps {
+			output.WriteString(fmt.Sprintf("     %d. %s\n", j+1, step.Describe()))
+		}
+		output.WriteString("\n")
+	}
+
+	return output.String()
+}
+
+// FormatResults formats the outcome of each step of a run
+func FormatResults(runbook Runbook, results []StepResult) string {
+	title := "RUN: " + runbook.Name
+
+	var output strings.Builder
+	output.WriteString(title + "\n")
+	output.WriteString(common.Rule(title, "-") + "\n")
+
+	for _, result := range results {
+		if result.Err != nil {
+			output.WriteString(fmt.Sprintf("%s %s: %v\n", common.Symbol("❌"), result.Step.Describe(), result.Err))
+			continue
+		}
+		output.WriteString(fmt.Sprintf("%s %s (%s)\n", common.Symbol("✅"), result.Step.Describe(), result.Duration.Round(time.Millisecond)))
+	}
+
+	return output.String()
+}
+
+// Failed returns the number of steps that failed
+func Failed(results []StepResult) int {
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
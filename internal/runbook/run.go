@@ -0,0 +1,107 @@
+package runbook
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+)
+
+// ecsClientAPI defines the ECS calls of runbook steps
+type ecsClientAPI interface {
+	UpdateService(ctx context.Context, params *ecs.UpdateServiceInput, optFns ...func(*ecs.Options)) (*ecs.UpdateServiceOutput, error)
+}
+
+// eventBridgeClientAPI defines the EventBridge calls of runbook steps
+type eventBridgeClientAPI interface {
+	DisableRule(ctx context.Context, params *eventbridge.DisableRuleInput, optFns ...func(*eventbridge.Options)) (*eventbridge.DisableRuleOutput, error)
+}
+
+// cloudWatchClientAPI defines the CloudWatch calls of runbook steps
+type cloudWatchClientAPI interface {
+	SetAlarmState(ctx context.Context, params *cloudwatch.SetAlarmStateInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.SetAlarmStateOutput, error)
+}
+
+// Runner runs the steps of runbooks
+type Runner struct {
+	ecsClient         ecsClientAPI
+	eventBridgeClient eventBridgeClientAPI
+	cloudWatchClient  cloudWatchClientAPI
+}
+
+// StepResult is the outcome of a step
+type StepResult struct {
+	Step     Step
+	Err      error
+	Duration time.Duration
+}
+
+// NewRunner returns a runner calling the given clients
+func NewRunner(ecsClient ecsClientAPI, eventBridgeClient eventBridgeClientAPI, cloudWatchClient cloudWatchClientAPI) *Runner {
+	return &Runner{
+		ecsClient:         ecsClient,
+		eventBridgeClient: eventBridgeClient,
+		cloudWatchClient:  cloudWatchClient,
+	}
+}
+
+// Run runs the steps of the runbook in order and returns the outcome of each.
+// A failed step does not stop the ones after it, since during an incident
+// stopping as much of the damage as possible beats stopping none of it.
+func (r *Runner) Run(ctx context.Context, runbook Runbook) []StepResult {
+	results := make([]StepResult, 0, len(runbook.Steps))
+	for _, step := range runbook.Steps {
+		if ctx.Err() != nil {
+			results = append(results, StepResult{Step: step, Err: ctx.Err()})
+			continue
+		}
+		start := time.Now()
+		err := r.runStep(ctx, step)
+		results = append(results, StepResult{Step: step, Err: err, Duration: time.Since(start)})
+	}
+	return results
+}
+
+// runStep runs a single step
+func (r *Runner) runStep(ctx context.Context, step Step) error {
+	switch step.Action {
+	case ActionScaleService:
+		_, err := r.ecsClient.UpdateService(ctx, &ecs.UpdateServiceInput{
+			Cluster:      aws.String(step.Cluster),
+			Service:      aws.String(step.Service),
+			DesiredCount: aws.Int32(step.DesiredCount),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scale service %s: %w", step.Service, err)
+		}
+	case ActionDisableRule:
+		input := &eventbridge.DisableRuleInput{Name: aws.String(step.Rule)}
+		if step.EventBus != "" {
+			input.EventBusName = aws.String(step.EventBus)
+		}
+		if _, err := r.eventBridgeClient.DisableRule(ctx, input); err != nil {
+			return fmt.Errorf("failed to disable rule %s: %w", step.Rule, err)
+		}
+	case ActionSetAlarmState:
+		reason := step.Reason
+		if reason == "" {
+			reason = "Set by an aws-overview runbook"
+		}
+		_, err := r.cloudWatchClient.SetAlarmState(ctx, &cloudwatch.SetAlarmStateInput{
+			AlarmName:   aws.String(step.Alarm),
+			StateValue:  cwtypes.StateValue(step.alarmState()),
+			StateReason: aws.String(reason),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to set state of alarm %s: %w", step.Alarm, err)
+		}
+	default:
+		return step.Validate()
+	}
+	return nil
+}
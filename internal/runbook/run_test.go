@@ -0,0 +1,113 @@
+package runbook
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+
+	"github.com/correctedcloud/aws-overview/pkg/demo"
+)
+
+// Mock clients recording the calls of the steps
+type mockECSClient struct {
+	updates []*ecs.UpdateServiceInput
+}
+
+func (m *mockECSClient) UpdateService(ctx context.Context, params *ecs.UpdateServiceInput, optFns ...func(*ecs.Options)) (*ecs.UpdateServiceOutput, error) {
+	m.updates = append(m.updates, params)
+	return &ecs.UpdateServiceOutput{}, nil
+}
+
+type mockEventBridgeClient struct {
+	err error
+}
+
+func (m *mockEventBridgeClient) DisableRule(ctx context.Context, params *eventbridge.DisableRuleInput, optFns ...func(*eventbridge.Options)) (*eventbridge.DisableRuleOutput, error) {
+	return &eventbridge.DisableRuleOutput{}, m.err
+}
+
+type mockCloudWatchClient struct {
+	inputs []*cloudwatch.SetAlarmStateInput
+}
+
+func (m *mockCloudWatchClient) SetAlarmState(ctx context.Context, params *cloudwatch.SetAlarmStateInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.SetAlarmStateOutput, error) {
+	m.inputs = append(m.inputs, params)
+	return &cloudwatch.SetAlarmStateOutput{}, nil
+}
+
+func TestRunContinuesAfterFailedStep(t *testing.T) {
+	ecsClient := &mockECSClient{}
+	cloudWatchClient := &mockCloudWatchClient{}
+	runner := NewRunner(ecsClient, &mockEventBridgeClient{err: errors.New("AccessDeniedException")}, cloudWatchClient)
+
+	runbook := Runbook{Name: "stop-emails", Steps: []Step{
+		{Action: ActionDisableRule, Rule: "send-digest"},
+		{Action: ActionScaleService, Cluster: "production", Service: "email-worker"},
+		{Action: ActionSetAlarmState, Alarm: "email-errors", State: "ok"},
+	}}
+	results := runner.Run(context.Background(), runbook)
+
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	if results[0].Err == nil || results[1].Err != nil || results[2].Err != nil {
+		t.Errorf("Expected only the first step to fail, got %+v", results)
+	}
+	if Failed(results) != 1 {
+		t.Errorf("Expected 1 failed step, got %d", Failed(results))
+	}
+
+	if len(ecsClient.updates) != 1 || aws.ToInt32(ecsClient.updates[0].DesiredCount) != 0 {
+		t.Errorf("Expected the service to be scaled to 0, got %+v", ecsClient.updates)
+	}
+	if len(cloudWatchClient.inputs) != 1 || cloudWatchClient.inputs[0].StateValue != "OK" {
+		t.Errorf("Expected the alarm to be set to OK, got %+v", cloudWatchClient.inputs)
+	}
+
+	output := FormatResults(runbook, results)
+	if !strings.Contains(output, "AccessDeniedException") || !strings.Contains(output, "Scale ECS service production/email-worker to 0") {
+		t.Errorf("Unexpected results output:\n%s", output)
+	}
+}
+
+func TestRunCancelled(t *testing.T) {
+	ecsClient := &mockECSClient{}
+	runner := NewRunner(ecsClient, &mockEventBridgeClient{}, &mockCloudWatchClient{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results := runner.Run(ctx, Runbook{Name: "a", Steps: []Step{{Action: ActionScaleService, Cluster: "c", Service: "s"}}})
+
+	if len(results) != 1 || !errors.Is(results[0].Err, context.Canceled) {
+		t.Errorf("Expected the step to be skipped as cancelled, got %+v", results)
+	}
+	if len(ecsClient.updates) != 0 {
+		t.Errorf("Expected no calls after cancellation, got %d", len(ecsClient.updates))
+	}
+}
+
+func TestRunWithFixtures(t *testing.T) {
+	runner := NewRunner(demo.NewECS(), demo.NewEventBridge(), demo.NewCloudWatch())
+
+	results := runner.Run(context.Background(), Runbook{Name: "stop-emails", Steps: []Step{
+		{Action: ActionScaleService, Cluster: "production", Service: "email-worker"},
+		{Action: ActionDisableRule, Rule: "send-digest"},
+		{Action: ActionSetAlarmState, Alarm: "email-errors"},
+		{Action: ActionScaleService, Cluster: "production", Service: "missing"},
+	}})
+
+	for i, result := range results[:3] {
+		if result.Err != nil {
+			t.Errorf("Step %d failed: %v", i+1, result.Err)
+		}
+	}
+	if results[3].Err == nil {
+		t.Error("Expected scaling an unknown service to fail")
+	}
+}
@@ -0,0 +1,163 @@
+// Package runbook loads the break-glass runbooks of the config file and runs
+// their steps, e.g. scaling a misbehaving ECS service to zero during an
+// incident.
+package runbook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Supported step actions
+const (
+	ActionScaleService  = "scale-service"   // Set the desired count of an ECS service
+	ActionDisableRule   = "disable-rule"    // Disable an EventBridge rule
+	ActionSetAlarmState = "set-alarm-state" // Set the state of a CloudWatch alarm
+)
+
+// Runbook is a named sequence of steps run together
+type Runbook struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Steps       []Step `json:"steps"`
+}
+
+// Step is one action of a runbook. Which fields apply depends on the action.
+type Step struct {
+	Action string `json:"action"`
+
+	// scale-service
+	Cluster      string `json:"cluster,omitempty"`
+	Service      string `json:"service,omitempty"`
+	DesiredCount int32  `json:"desired_count,omitempty"` // Defaults to 0
+
+	// disable-rule
+	Rule     string `json:"rule,omitempty"`
+	EventBus string `json:"event_bus,omitempty"` // Defaults to the default bus
+
+	// set-alarm-state
+	Alarm  string `json:"alarm,omitempty"`
+	State  string `json:"state,omitempty"`  // OK, ALARM or INSUFFICIENT_DATA, defaults to OK
+	Reason string `json:"reason,omitempty"` // Defaults to a note that the runbook set it
+}
+
+// File is the layout of the runbooks config file
+type File struct {
+	Runbooks []Runbook `json:"runbooks"`
+}
+
+// DefaultPath returns the default location of the runbooks config file
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "aws-overview", "runbooks.json")
+}
+
+// Load reads and validates the runbooks of the config file at path. It
+// returns nil without an error when the file does not exist.
+func Load(path string) ([]Runbook, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read runbooks: %w", err)
+	}
+
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to decode runbooks: %w", err)
+	}
+
+	names := make(map[string]bool)
+	for _, runbook := range file.Runbooks {
+		if err := runbook.Validate(); err != nil {
+			return nil, err
+		}
+		if names[runbook.Name] {
+			return nil, fmt.Errorf("runbook %q is defined twice", runbook.Name)
+		}
+		names[runbook.Name] = true
+	}
+
+	return file.Runbooks, nil
+}
+
+// Validate checks that the runbook is named and that each step has the
+// fields its action needs
+func (r Runbook) Validate() error {
+	if r.Name == "" {
+		return errors.New("runbook without a name")
+	}
+	if len(r.Steps) == 0 {
+		return fmt.Errorf("runbook %q has no steps", r.Name)
+	}
+	for i, step := range r.Steps {
+		if err := step.Validate(); err != nil {
+			return fmt.Errorf("runbook %q step %d: %w", r.Name, i+1, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks that the step's action is supported and has its fields
+func (s Step) Validate() error {
+	switch s.Action {
+	case ActionScaleService:
+		if s.Cluster == "" || s.Service == "" {
+			return errors.New("scale-service needs a cluster and a service")
+		}
+		if s.DesiredCount < 0 {
+			return errors.New("scale-service needs a desired_count of at least 0")
+		}
+	case ActionDisableRule:
+		if s.Rule == "" {
+			return errors.New("disable-rule needs a rule")
+		}
+	case ActionSetAlarmState:
+		if s.Alarm == "" {
+			return errors.New("set-alarm-state needs an alarm")
+		}
+		switch s.alarmState() {
+		case "OK", "ALARM", "INSUFFICIENT_DATA":
+		default:
+			return fmt.Errorf("unknown alarm state %q", s.State)
+		}
+	case "":
+		return errors.New("step without an action")
+	default:
+		return fmt.Errorf("unknown action %q, supported are %s", s.Action,
+			strings.Join([]string{ActionScaleService, ActionDisableRule, ActionSetAlarmState}, ", "))
+	}
+	return nil
+}
+
+// Describe returns what the step does, e.g. "Scale ECS service production/orders-api to 0"
+func (s Step) Describe() string {
+	switch s.Action {
+	case ActionScaleService:
+		return fmt.Sprintf("Scale ECS service %s/%s to %d", s.Cluster, s.Service, s.DesiredCount)
+	case ActionDisableRule:
+		if s.EventBus != "" {
+			return fmt.Sprintf("Disable EventBridge rule %s on bus %s", s.Rule, s.EventBus)
+		}
+		return "Disable EventBridge rule " + s.Rule
+	case ActionSetAlarmState:
+		return fmt.Sprintf("Set CloudWatch alarm %s to %s", s.Alarm, s.alarmState())
+	}
+	return s.Action
+}
+
+// alarmState returns the state a set-alarm-state step sets
+func (s Step) alarmState() string {
+	if s.State == "" {
+		return "OK"
+	}
+	return strings.ToUpper(s.State)
+}
@@ -0,0 +1,82 @@
+package runbook
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeRunbooks(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "runbooks.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeRunbooks(t, `{"runbooks": [{
+		"name": "stop-emails",
+		"description": "Stop sending emails",
+		"steps": [
+			{"action": "scale-service", "cluster": "production", "service": "email-worker"},
+			{"action": "disable-rule", "rule": "send-digest"},
+			{"action": "set-alarm-state", "alarm": "email-errors"}
+		]
+	}]}`)
+
+	runbooks, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load returned an error: %v", err)
+	}
+	if len(runbooks) != 1 || len(runbooks[0].Steps) != 3 {
+		t.Fatalf("Expected 1 runbook with 3 steps, got %+v", runbooks)
+	}
+
+	descriptions := []string{
+		"Scale ECS service production/email-worker to 0",
+		"Disable EventBridge rule send-digest",
+		"Set CloudWatch alarm email-errors to OK",
+	}
+	for i, want := range descriptions {
+		if got := runbooks[0].Steps[i].Describe(); got != want {
+			t.Errorf("Step %d: expected %q, got %q", i+1, want, got)
+		}
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	runbooks, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil || runbooks != nil {
+		t.Errorf("Expected no runbooks and no error, got %v, %v", runbooks, err)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := map[string]struct {
+		content string
+		err     string
+	}{
+		"no name":         {`{"runbooks": [{"steps": [{"action": "disable-rule", "rule": "r"}]}]}`, "without a name"},
+		"no steps":        {`{"runbooks": [{"name": "a"}]}`, "has no steps"},
+		"unknown action":  {`{"runbooks": [{"name": "a", "steps": [{"action": "reboot"}]}]}`, `unknown action "reboot"`},
+		"missing service": {`{"runbooks": [{"name": "a", "steps": [{"action": "scale-service", "cluster": "c"}]}]}`, "step 1: scale-service needs"},
+		"bad state":       {`{"runbooks": [{"name": "a", "steps": [{"action": "set-alarm-state", "alarm": "x", "state": "fine"}]}]}`, "unknown alarm state"},
+		"duplicate": {`{"runbooks": [
+			{"name": "a", "steps": [{"action": "disable-rule", "rule": "r"}]},
+			{"name": "a", "steps": [{"action": "disable-rule", "rule": "r"}]}
+		]}`, "defined twice"},
+		"not json": {`runbooks:`, "failed to decode"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Load(writeRunbooks(t, test.content))
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("Expected an error containing %q, got %v", test.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,269 @@
+// Package seal encrypts the state the tool keeps on disk, such as the saved
+// session, the pinned metrics and the response cache, which hold resource
+// names and account IDs. Files are sealed with AES-256-GCM under a data key
+// kept in the OS keychain or generated by KMS and stored wrapped next to the
+// data, so only the keychain's user or principals allowed to decrypt with
+// the KMS key can read them.
+package seal
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/zalando/go-keyring"
+)
+
+// kmsClientAPI defines the interface for the KMS client
+type kmsClientAPI interface {
+	GenerateDataKey(ctx context.Context, params *kms.GenerateDataKeyInput, optFns ...func(*kms.Options)) (*kms.GenerateDataKeyOutput, error)
+	Decrypt(ctx context.Context, params *kms.DecryptInput, optFns ...func(*kms.Options)) (*kms.DecryptOutput, error)
+}
+
+// Key sources of a sealer
+const (
+	SourceKeychain = "keychain"
+	SourceKMS      = "kms"
+)
+
+// Keychain entry holding the data key of SourceKeychain
+const (
+	KeychainService = "aws-overview"
+	KeychainUser    = "state-encryption-key"
+)
+
+// header starts every sealed file, telling it apart from the plain JSON
+// files written without encryption
+var header = []byte("aws-overview-sealed-v1\n")
+
+// kmsTimeout bounds each KMS call, as sealing happens outside of any fetch
+const kmsTimeout = 10 * time.Second
+
+// envelope is the layout of a sealed file after the header
+type envelope struct {
+	Source     string `json:"source"`
+	WrappedKey []byte `json:"wrapped_key,omitempty"` // Data key encrypted by KMS, empty for the keychain
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// Sealer encrypts and decrypts state files. A nil Sealer writes them in the
+// clear and refuses to read sealed ones.
+type Sealer struct {
+	source    string
+	kmsClient kmsClientAPI
+
+	mu         sync.Mutex
+	key        []byte            // Data key new files are sealed with
+	wrappedKey []byte            // key encrypted by KMS, nil for the keychain
+	unwrapped  map[string][]byte // Data keys decrypted by KMS, by wrapped key
+}
+
+// ParseSpec splits a -encrypt-state value, "keychain" or "kms:<key>" where
+// the key is a KMS key ID, ARN or alias, into its source and KMS key. An
+// empty spec disables encryption.
+func ParseSpec(spec string) (source, kmsKeyID string, err error) {
+	switch {
+	case spec == "":
+		return "", "", nil
+	case spec == SourceKeychain:
+		return SourceKeychain, "", nil
+	case strings.HasPrefix(spec, SourceKMS+":") && len(spec) > len(SourceKMS)+1:
+		return SourceKMS, strings.TrimPrefix(spec, SourceKMS+":"), nil
+	}
+	return "", "", fmt.Errorf("invalid state encryption %q, expected keychain or kms:<key ID, ARN or alias>", spec)
+}
+
+// NewKeychain returns a sealer whose data key is kept in the OS keychain
+// (the macOS Keychain, the Secret Service on Linux or the Windows Credential
+// Manager), creating the key on first use
+func NewKeychain() (*Sealer, error) {
+	encoded, err := keychainKey()
+	if err != nil {
+		return nil, err
+	}
+	key, err := decodeKeychainKey(encoded)
+	if err != nil {
+		return nil, err
+	}
+	return &Sealer{source: SourceKeychain, key: key, unwrapped: make(map[string][]byte)}, nil
+}
+
+// NewKMS returns a sealer whose data key is generated by the KMS key, a key
+// ID, ARN or alias, and stored wrapped in each file it seals. The data key is
+// generated once, so a run makes a single GenerateDataKey call, plus a
+// Decrypt call per data key of the files it reads.
+func NewKMS(ctx context.Context, kmsClient kmsClientAPI, keyID string) (*Sealer, error) {
+	ctx, cancel := context.WithTimeout(ctx, kmsTimeout)
+	defer cancel()
+
+	output, err := kmsClient.GenerateDataKey(ctx, &kms.GenerateDataKeyInput{
+		KeyId:   aws.String(keyID),
+		KeySpec: kmstypes.DataKeySpecAes256,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate data key with KMS key %s: %w", keyID, err)
+	}
+
+	s := &Sealer{
+		source:     SourceKMS,
+		kmsClient:  kmsClient,
+		key:        output.Plaintext,
+		wrappedKey: output.CiphertextBlob,
+		unwrapped:  make(map[string][]byte),
+	}
+	s.unwrapped[string(output.CiphertextBlob)] = output.Plaintext
+	return s, nil
+}
+
+// Sealed reports whether data was written by a sealer
+func Sealed(data []byte) bool {
+	return bytes.HasPrefix(data, header)
+}
+
+// Seal encrypts data, or returns it unchanged when s is nil
+func (s *Sealer) Seal(data []byte) ([]byte, error) {
+	if s == nil {
+		return data, nil
+	}
+
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt state: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt state: %w", err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to encrypt state: %w", err)
+	}
+
+	sealed, err := json.Marshal(envelope{
+		Source:     s.source,
+		WrappedKey: s.wrappedKey,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, data, header),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt state: %w", err)
+	}
+	return append(append([]byte{}, header...), sealed...), nil
+}
+
+// Open decrypts data written by Seal. Data written without encryption is
+// returned unchanged, so turning encryption on keeps the existing files
+// readable until they are written again. Files sealed with the keychain can
+// be read by any sealer, files sealed with KMS only by a KMS sealer.
+func (s *Sealer) Open(data []byte) ([]byte, error) {
+	if !Sealed(data) {
+		return data, nil
+	}
+	if s == nil {
+		return nil, errors.New("the file is encrypted, read it with -encrypt-state")
+	}
+
+	var sealed envelope
+	if err := json.Unmarshal(data[len(header):], &sealed); err != nil {
+		return nil, fmt.Errorf("failed to decode encrypted state: %w", err)
+	}
+	key, err := s.dataKey(sealed)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt state: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt state: %w", err)
+	}
+	plaintext, err := gcm.Open(nil, sealed.Nonce, sealed.Ciphertext, header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt state, the key does not match: %w", err)
+	}
+	return plaintext, nil
+}
+
+// dataKey returns the data key a file was sealed with
+func (s *Sealer) dataKey(sealed envelope) ([]byte, error) {
+	switch sealed.Source {
+	case SourceKeychain:
+		if s.source == SourceKeychain {
+			return s.key, nil
+		}
+		encoded, err := keyring.Get(KeychainService, KeychainUser)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the state encryption key from the keychain: %w", err)
+		}
+		return decodeKeychainKey(encoded)
+
+	case SourceKMS:
+		if s.kmsClient == nil {
+			return nil, errors.New("the file is encrypted with KMS, read it with -encrypt-state kms:<key>")
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if key, ok := s.unwrapped[string(sealed.WrappedKey)]; ok {
+			return key, nil
+		}
+
+		// Symmetric KMS keys find the key from the wrapped data key, so
+		// files sealed before switching to another key stay readable
+		ctx, cancel := context.WithTimeout(context.Background(), kmsTimeout)
+		defer cancel()
+		output, err := s.kmsClient.Decrypt(ctx, &kms.DecryptInput{CiphertextBlob: sealed.WrappedKey})
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt data key with KMS: %w", err)
+		}
+		s.unwrapped[string(sealed.WrappedKey)] = output.Plaintext
+		return output.Plaintext, nil
+	}
+	return nil, fmt.Errorf("unknown key source %q of encrypted state", sealed.Source)
+}
+
+// decodeKeychainKey decodes the data key stored in the keychain
+func decodeKeychainKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("invalid state encryption key in the keychain entry %s/%s", KeychainService, KeychainUser)
+	}
+	return key, nil
+}
+
+// keychainKey returns the base64 data key stored in the keychain, storing
+// a new random one when there is none
+func keychainKey() (string, error) {
+	encoded, err := keyring.Get(KeychainService, KeychainUser)
+	if err == nil {
+		return encoded, nil
+	}
+	if !errors.Is(err, keyring.ErrNotFound) {
+		return "", fmt.Errorf("failed to read the state encryption key from the keychain: %w", err)
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate state encryption key: %w", err)
+	}
+	encoded = base64.StdEncoding.EncodeToString(key)
+	if err := keyring.Set(KeychainService, KeychainUser, encoded); err != nil {
+		return "", fmt.Errorf("failed to store the state encryption key in the keychain: %w", err)
+	}
+	return encoded, nil
+}
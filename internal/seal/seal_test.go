@@ -0,0 +1,172 @@
+package seal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/zalando/go-keyring"
+)
+
+// Mock KMS client "wrapping" data keys by reversing them
+type mockKMSClient struct {
+	err       error
+	generated int
+	decrypted int
+}
+
+func (m *mockKMSClient) GenerateDataKey(ctx context.Context, params *kms.GenerateDataKeyInput, optFns ...func(*kms.Options)) (*kms.GenerateDataKeyOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.generated++
+	key := bytes.Repeat([]byte{byte(m.generated)}, 31)
+	key = append(key, 0xff)
+	return &kms.GenerateDataKeyOutput{Plaintext: key, CiphertextBlob: reverse(key)}, nil
+}
+
+func (m *mockKMSClient) Decrypt(ctx context.Context, params *kms.DecryptInput, optFns ...func(*kms.Options)) (*kms.DecryptOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.decrypted++
+	return &kms.DecryptOutput{Plaintext: reverse(params.CiphertextBlob)}, nil
+}
+
+func reverse(data []byte) []byte {
+	reversed := make([]byte, len(data))
+	for i, b := range data {
+		reversed[len(data)-1-i] = b
+	}
+	return reversed
+}
+
+func TestParseSpec(t *testing.T) {
+	tests := []struct {
+		spec    string
+		source  string
+		keyID   string
+		wantErr bool
+	}{
+		{spec: ""},
+		{spec: "keychain", source: SourceKeychain},
+		{spec: "kms:alias/aws-overview", source: SourceKMS, keyID: "alias/aws-overview"},
+		{spec: "kms:", wantErr: true},
+		{spec: "vault", wantErr: true},
+	}
+	for _, tt := range tests {
+		source, keyID, err := ParseSpec(tt.spec)
+		if (err != nil) != tt.wantErr || source != tt.source || keyID != tt.keyID {
+			t.Errorf("ParseSpec(%q) = %q, %q, %v", tt.spec, source, keyID, err)
+		}
+	}
+}
+
+func TestKeychain(t *testing.T) {
+	keyring.MockInit()
+
+	sealer, err := NewKeychain()
+	if err != nil {
+		t.Fatalf("NewKeychain() error = %v", err)
+	}
+	sealed, err := sealer.Seal([]byte(`{"region":"us-east-1"}`))
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+	if !Sealed(sealed) || strings.Contains(string(sealed), "us-east-1") {
+		t.Fatalf("Expected sealed data without the plaintext, got %s", sealed)
+	}
+
+	// A later run reuses the key stored in the keychain
+	again, err := NewKeychain()
+	if err != nil {
+		t.Fatalf("NewKeychain() error = %v", err)
+	}
+	opened, err := again.Open(sealed)
+	if err != nil || string(opened) != `{"region":"us-east-1"}` {
+		t.Errorf("Open() = %s, %v", opened, err)
+	}
+
+	// Files written before encryption was turned on stay readable
+	if opened, err := again.Open([]byte(`{"plain":true}`)); err != nil || string(opened) != `{"plain":true}` {
+		t.Errorf("Open() of plain data = %s, %v", opened, err)
+	}
+
+	// Without a sealer, sealed files are refused and plain files written
+	var none *Sealer
+	if _, err := none.Open(sealed); err == nil {
+		t.Error("Expected an error opening sealed data without a sealer")
+	}
+	if plain, _ := none.Seal([]byte("x")); string(plain) != "x" {
+		t.Errorf("Expected a nil sealer to leave data unchanged, got %s", plain)
+	}
+
+	// A tampered file does not open
+	tampered := append([]byte{}, sealed...)
+	tampered[len(tampered)-5] ^= 1
+	if _, err := again.Open(tampered); err == nil {
+		t.Error("Expected an error opening tampered data")
+	}
+}
+
+func TestKMS(t *testing.T) {
+	client := &mockKMSClient{}
+	sealer, err := NewKMS(context.Background(), client, "alias/aws-overview")
+	if err != nil {
+		t.Fatalf("NewKMS() error = %v", err)
+	}
+
+	var files [][]byte
+	for _, data := range []string{"session", "pins"} {
+		sealed, err := sealer.Seal([]byte(data))
+		if err != nil {
+			t.Fatalf("Seal() error = %v", err)
+		}
+		files = append(files, sealed)
+	}
+	if client.generated != 1 {
+		t.Errorf("Expected a single data key for the run, got %d", client.generated)
+	}
+
+	// The next run generates another data key but decrypts the previous
+	// one once to read the files of the last run
+	next, err := NewKMS(context.Background(), client, "alias/aws-overview")
+	if err != nil {
+		t.Fatalf("NewKMS() error = %v", err)
+	}
+	for i, data := range []string{"session", "pins"} {
+		opened, err := next.Open(files[i])
+		if err != nil || string(opened) != data {
+			t.Errorf("Open() = %s, %v", opened, err)
+		}
+	}
+	if client.decrypted != 1 {
+		t.Errorf("Expected the previous data key to be decrypted once, got %d", client.decrypted)
+	}
+
+	// The keychain cannot read files sealed with KMS
+	keyring.MockInit()
+	keychain, err := NewKeychain()
+	if err != nil {
+		t.Fatalf("NewKeychain() error = %v", err)
+	}
+	if _, err := keychain.Open(files[0]); err == nil || !strings.Contains(err.Error(), "kms:") {
+		t.Errorf("Expected an error naming the KMS option, got %v", err)
+	}
+
+	// But KMS sealers read files sealed with the keychain
+	sealed, _ := keychain.Seal([]byte("favorites"))
+	if opened, err := next.Open(sealed); err != nil || string(opened) != "favorites" {
+		t.Errorf("Open() of keychain data = %s, %v", opened, err)
+	}
+}
+
+func TestKMSError(t *testing.T) {
+	_, err := NewKMS(context.Background(), &mockKMSClient{err: errors.New("AccessDeniedException")}, "alias/missing")
+	if err == nil || !strings.Contains(err.Error(), "alias/missing") {
+		t.Errorf("Expected an error naming the key, got %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/correctedcloud/aws-overview/internal/seal"
+	"github.com/correctedcloud/aws-overview/pkg/alb"
+	"github.com/correctedcloud/aws-overview/pkg/apigateway"
+	"github.com/correctedcloud/aws-overview/pkg/cloudfront"
+	"github.com/correctedcloud/aws-overview/pkg/cost"
+	"github.com/correctedcloud/aws-overview/pkg/dns"
+	"github.com/correctedcloud/aws-overview/pkg/dr"
+	"github.com/correctedcloud/aws-overview/pkg/ebs"
+	"github.com/correctedcloud/aws-overview/pkg/ec2"
+	"github.com/correctedcloud/aws-overview/pkg/ecr"
+	"github.com/correctedcloud/aws-overview/pkg/ecs"
+	"github.com/correctedcloud/aws-overview/pkg/findings"
+	"github.com/correctedcloud/aws-overview/pkg/hygiene"
+	"github.com/correctedcloud/aws-overview/pkg/lambda"
+	"github.com/correctedcloud/aws-overview/pkg/probe"
+	"github.com/correctedcloud/aws-overview/pkg/rds"
+	"github.com/correctedcloud/aws-overview/pkg/sns"
+	"github.com/correctedcloud/aws-overview/pkg/sqs"
+	"github.com/correctedcloud/aws-overview/pkg/ssm"
+	"github.com/correctedcloud/aws-overview/pkg/vpc"
+)
+
+// Snapshot holds the data and UI state of a session so it can be restored on the next start
+type Snapshot struct {
+	SavedAt                 time.Time                        `json:"saved_at"`
+	Region                  string                           `json:"region"`
+	Profile                 string                           `json:"profile"`
+	Account                 string                           `json:"account,omitempty"`
+	ActiveTab               string                           `json:"active_tab"`
+	ScrollOffset            int                              `json:"scroll_offset"`
+	LastRefresh             time.Time                        `json:"last_refresh"`
+	LoadBalancers           []alb.LoadBalancerSummary        `json:"load_balancers,omitempty"`
+	DBInstances             []rds.DBInstanceSummary          `json:"db_instances,omitempty"`
+	EC2Instances            []ec2.InstanceSummary            `json:"ec2_instances,omitempty"`
+	ECSServices             []ecs.ServiceSummary             `json:"ecs_services,omitempty"`
+	ECSScheduledTasks       []ecs.ScheduledTask              `json:"ecs_scheduled_tasks,omitempty"`
+	SQSQueues               []sqs.QueueSummary               `json:"sqs_queues,omitempty"`
+	SSMInstances            []ssm.InstanceSummary            `json:"ssm_instances,omitempty"`
+	DNSRecords              []dns.RecordSummary              `json:"dns_records,omitempty"`
+	DRResources             []dr.ResourceSummary             `json:"dr_resources,omitempty"`
+	SNSTopics               []sns.TopicSummary               `json:"sns_topics,omitempty"`
+	LambdaFunctions         []lambda.FunctionSummary         `json:"lambda_functions,omitempty"`
+	CloudFrontDistributions []cloudfront.DistributionSummary `json:"cloudfront_distributions,omitempty"`
+	EBSVolumes              []ebs.VolumeSummary              `json:"ebs_volumes,omitempty"`
+	VPCs                    []vpc.VPCSummary                 `json:"vpcs,omitempty"`
+	ECRRepositories         []ecr.RepositorySummary          `json:"ecr_repositories,omitempty"`
+	APIGatewayAPIs          []apigateway.APISummary          `json:"api_gateway_apis,omitempty"`
+	Costs                   *cost.Summary                    `json:"costs,omitempty"`
+	Findings                []findings.Finding               `json:"findings,omitempty"`
+	HygieneResources        []hygiene.Resource               `json:"hygiene_resources,omitempty"`
+	Probes                  []probe.Result                   `json:"probes,omitempty"`
+}
+
+// DefaultPath returns the default location of the session file
+func DefaultPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "aws-overview", "session.json")
+}
+
+// Save writes the snapshot to path, encrypted by sealer unless it is nil,
+// creating parent directories as needed. The file is written to a temporary
+// name first so that a crash never leaves a half-written snapshot behind.
+func Save(path string, snapshot Snapshot, sealer *seal.Sealer) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+	data, err = sealer.Seal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+
+	return nil
+}
+
+// Load reads a snapshot from path, decrypting it with sealer when it was
+// saved encrypted. It returns nil without an error when no session has been
+// saved yet.
+func Load(path string, sealer *seal.Sealer) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+	data, err = sealer.Open(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode session: %w", err)
+	}
+
+	return &snapshot, nil
+}
@@ -0,0 +1,99 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zalando/go-keyring"
+
+	"github.com/correctedcloud/aws-overview/internal/seal"
+	"github.com/correctedcloud/aws-overview/pkg/sqs"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "session.json")
+
+	snapshot := Snapshot{
+		SavedAt:      time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		Region:       "eu-west-1",
+		Profile:      "staging",
+		Account:      "123456789012",
+		ActiveTab:    "SQS Queues",
+		ScrollOffset: 7,
+		SQSQueues: []sqs.QueueSummary{
+			{Name: "orders", Type: "Standard", DeadLetterQueue: "orders-dlq"},
+		},
+	}
+
+	if err := Save(path, snapshot, nil); err != nil {
+		t.Fatalf("Expected no error saving session, got %v", err)
+	}
+
+	loaded, err := Load(path, nil)
+	if err != nil {
+		t.Fatalf("Expected no error loading session, got %v", err)
+	}
+	if loaded == nil {
+		t.Fatal("Expected a snapshot, got nil")
+	}
+
+	if loaded.Region != snapshot.Region {
+		t.Errorf("Expected region '%s', got '%s'", snapshot.Region, loaded.Region)
+	}
+	if loaded.Profile != snapshot.Profile || loaded.Account != snapshot.Account {
+		t.Errorf("Expected profile '%s' of account %s, got '%s' of %s", snapshot.Profile, snapshot.Account, loaded.Profile, loaded.Account)
+	}
+	if loaded.ActiveTab != snapshot.ActiveTab {
+		t.Errorf("Expected active tab '%s', got '%s'", snapshot.ActiveTab, loaded.ActiveTab)
+	}
+	if loaded.ScrollOffset != snapshot.ScrollOffset {
+		t.Errorf("Expected scroll offset %d, got %d", snapshot.ScrollOffset, loaded.ScrollOffset)
+	}
+	if !loaded.SavedAt.Equal(snapshot.SavedAt) {
+		t.Errorf("Expected saved at %v, got %v", snapshot.SavedAt, loaded.SavedAt)
+	}
+	if len(loaded.SQSQueues) != 1 || loaded.SQSQueues[0].DeadLetterQueue != "orders-dlq" {
+		t.Errorf("Expected SQS queues to round-trip, got %+v", loaded.SQSQueues)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	loaded, err := Load(filepath.Join(t.TempDir(), "missing.json"), nil)
+	if err != nil {
+		t.Fatalf("Expected no error for a missing session, got %v", err)
+	}
+	if loaded != nil {
+		t.Errorf("Expected nil snapshot for a missing session, got %+v", loaded)
+	}
+}
+
+func TestSaveEncrypted(t *testing.T) {
+	keyring.MockInit()
+	sealer, err := seal.NewKeychain()
+	if err != nil {
+		t.Fatalf("NewKeychain() error = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "session.json")
+
+	if err := Save(path, Snapshot{Region: "eu-west-1"}, sealer); err != nil {
+		t.Fatalf("Expected no error saving session, got %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected the session on disk, got %v", err)
+	}
+	if strings.Contains(string(data), "eu-west-1") {
+		t.Errorf("Expected the session to be encrypted, got %s", data)
+	}
+
+	loaded, err := Load(path, sealer)
+	if err != nil || loaded == nil || loaded.Region != "eu-west-1" {
+		t.Errorf("Expected the encrypted session to load, got %+v, %v", loaded, err)
+	}
+	if _, err := Load(path, nil); err == nil {
+		t.Error("Expected an error loading an encrypted session without a sealer")
+	}
+}
@@ -0,0 +1,113 @@
+// Package terminal detects what the terminal the UI runs in can display,
+// so that the UI can fall back to plain output on consoles such as the
+// classic Windows console host. Detection only looks at the operating
+// system and environment variables, which keeps it testable in CI on any
+// platform.
+package terminal
+
+import (
+	"os"
+	"runtime"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// ASCIIEnv forces ASCII symbols instead of emoji when set to anything but "0"
+const ASCIIEnv = "AWS_OVERVIEW_ASCII"
+
+// NoColorEnv disables colors when set to anything, see https://no-color.org
+const NoColorEnv = "NO_COLOR"
+
+// Capabilities describes what the terminal can display
+type Capabilities struct {
+	// Emoji reports whether the terminal font renders emoji. When false the
+	// UI shows ASCII fallbacks instead.
+	Emoji bool
+
+	// AltScreen reports whether the UI should take over the alternate
+	// screen buffer. When false it renders inline.
+	AltScreen bool
+
+	// ColorProfile is the color support of the terminal when it can be told
+	// from the environment. Nil leaves detection to lipgloss.
+	ColorProfile *termenv.Profile
+}
+
+// Current detects the capabilities of the terminal the process runs in
+func Current() Capabilities {
+	return Detect(runtime.GOOS, os.Getenv)
+}
+
+// Detect determines the terminal capabilities for the given operating
+// system and environment lookup function
+func Detect(goos string, getenv func(string) string) Capabilities {
+	caps := Capabilities{Emoji: true, AltScreen: true}
+
+	switch {
+	case getenv("TERM") == "dumb":
+		caps = Capabilities{ColorProfile: profile(termenv.Ascii)}
+	case goos == "windows":
+		caps = detectWindows(getenv)
+	}
+
+	if v := getenv(ASCIIEnv); v != "" && v != "0" {
+		caps.Emoji = false
+	}
+	if getenv(NoColorEnv) != "" {
+		caps.ColorProfile = profile(termenv.Ascii)
+	}
+	return caps
+}
+
+// DisableColor makes Apply render without colors, keeping bold and other
+// attributes
+func (c *Capabilities) DisableColor() {
+	c.ColorProfile = profile(termenv.Ascii)
+}
+
+// Colorless reports whether colors are disabled
+func (c Capabilities) Colorless() bool {
+	return c.ColorProfile != nil && *c.ColorProfile == termenv.Ascii
+}
+
+// detectWindows tells Windows Terminal and other modern hosts apart from
+// the classic console host, which handles VT sequences through ConPTY but
+// whose fonts lack emoji
+func detectWindows(getenv func(string) string) Capabilities {
+	switch {
+	case getenv("WT_SESSION") != "":
+		// Windows Terminal
+		return Capabilities{Emoji: true, AltScreen: true, ColorProfile: profile(termenv.TrueColor)}
+	case getenv("TERM_PROGRAM") == "vscode":
+		return Capabilities{Emoji: true, AltScreen: true, ColorProfile: profile(termenv.TrueColor)}
+	case getenv("ConEmuANSI") == "ON":
+		return Capabilities{Emoji: false, AltScreen: true, ColorProfile: profile(termenv.ANSI256)}
+	case getenv("TERM") != "":
+		// mintty, ssh and other xterm compatible hosts attached through ConPTY
+		return Capabilities{Emoji: true, AltScreen: true}
+	default:
+		return Capabilities{Emoji: false, AltScreen: true}
+	}
+}
+
+// Apply configures lipgloss for the detected color profile
+func (c Capabilities) Apply() {
+	if c.ColorProfile != nil {
+		lipgloss.SetColorProfile(*c.ColorProfile)
+	}
+}
+
+// ProgramOptions returns the bubbletea options matching the capabilities
+func (c Capabilities) ProgramOptions() []tea.ProgramOption {
+	var opts []tea.ProgramOption
+	if c.AltScreen {
+		opts = append(opts, tea.WithAltScreen())
+	}
+	return opts
+}
+
+func profile(p termenv.Profile) *termenv.Profile {
+	return &p
+}
@@ -0,0 +1,100 @@
+package terminal
+
+import (
+	"testing"
+
+	"github.com/muesli/termenv"
+)
+
+func TestDetect(t *testing.T) {
+	testCases := []struct {
+		name          string
+		goos          string
+		env           map[string]string
+		expectEmoji   bool
+		expectAlt     bool
+		expectProfile *termenv.Profile
+	}{
+		{
+			name:        "linux xterm",
+			goos:        "linux",
+			env:         map[string]string{"TERM": "xterm-256color"},
+			expectEmoji: true,
+			expectAlt:   true,
+		},
+		{
+			name:          "dumb terminal",
+			goos:          "linux",
+			env:           map[string]string{"TERM": "dumb"},
+			expectProfile: profile(termenv.Ascii),
+		},
+		{
+			name:          "windows terminal",
+			goos:          "windows",
+			env:           map[string]string{"WT_SESSION": "5b2c4f6e-1d5a-4c1b-9e0f-0123456789ab"},
+			expectEmoji:   true,
+			expectAlt:     true,
+			expectProfile: profile(termenv.TrueColor),
+		},
+		{
+			name:      "windows console host",
+			goos:      "windows",
+			env:       map[string]string{},
+			expectAlt: true,
+		},
+		{
+			name:          "conemu",
+			goos:          "windows",
+			env:           map[string]string{"ConEmuANSI": "ON"},
+			expectAlt:     true,
+			expectProfile: profile(termenv.ANSI256),
+		},
+		{
+			name:        "mintty",
+			goos:        "windows",
+			env:         map[string]string{"TERM": "xterm"},
+			expectEmoji: true,
+			expectAlt:   true,
+		},
+		{
+			name:      "forced ascii",
+			goos:      "darwin",
+			env:       map[string]string{ASCIIEnv: "1"},
+			expectAlt: true,
+		},
+		{
+			name:          "no color",
+			goos:          "windows",
+			env:           map[string]string{"WT_SESSION": "5b2c4f6e-1d5a-4c1b-9e0f-0123456789ab", NoColorEnv: "1"},
+			expectEmoji:   true,
+			expectAlt:     true,
+			expectProfile: profile(termenv.Ascii),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			caps := Detect(tc.goos, func(key string) string { return tc.env[key] })
+
+			if caps.Emoji != tc.expectEmoji {
+				t.Errorf("Expected Emoji %v, got %v", tc.expectEmoji, caps.Emoji)
+			}
+			if caps.AltScreen != tc.expectAlt {
+				t.Errorf("Expected AltScreen %v, got %v", tc.expectAlt, caps.AltScreen)
+			}
+			if (caps.ColorProfile == nil) != (tc.expectProfile == nil) ||
+				(caps.ColorProfile != nil && *caps.ColorProfile != *tc.expectProfile) {
+				t.Errorf("Expected color profile %v, got %v", tc.expectProfile, caps.ColorProfile)
+			}
+		})
+	}
+}
+
+func TestProgramOptions(t *testing.T) {
+	if got := len(Capabilities{AltScreen: true}.ProgramOptions()); got != 1 {
+		t.Errorf("Expected 1 program option with alt screen, got %d", got)
+	}
+	if got := len(Capabilities{}.ProgramOptions()); got != 0 {
+		t.Errorf("Expected no program options without alt screen, got %d", got)
+	}
+}
@@ -0,0 +1,143 @@
+// Package tracing exports OpenTelemetry traces of the refreshes to an OTLP
+// endpoint: a trace per refresh, with a span per service loaded and a span
+// per AWS call, to tell which API is slow in a large account.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The environment variables the OTLP exporter reads its endpoint from
+const (
+	EndpointEnv       = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	TracesEndpointEnv = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+)
+
+// tracerName names the instrumentation in the spans
+const tracerName = "github.com/correctedcloud/aws-overview"
+
+// Start exports the spans to the OTLP/HTTP endpoint, e.g.
+// http://localhost:4318, or to the one named by the OTEL_EXPORTER_OTLP_*
+// environment variables when endpoint is empty. Without either, spans are
+// not recorded. The returned function flushes the spans not exported yet.
+func Start(ctx context.Context, endpoint string) (func(context.Context) error, error) {
+	if endpoint == "" && os.Getenv(EndpointEnv) == "" && os.Getenv(TracesEndpointEnv) == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var options []otlptracehttp.Option
+	if endpoint != "" {
+		options = append(options, otlptracehttp.WithEndpointURL(endpoint))
+	}
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName("aws-overview")))
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// refresh is the root span of a refresh, which ends once the services it
+// started loading are loaded
+type refresh struct {
+	span trace.Span
+
+	mu      sync.Mutex
+	pending int
+}
+
+// refreshKey is the context key of the refresh a service is loaded for
+type refreshKey struct{}
+
+// StartRefresh starts the trace of a refresh named name, e.g. "refresh", in
+// the returned context. The services joining it with Join before the
+// returned function is called are part of the trace, which ends when the
+// last of them is loaded.
+func StartRefresh(ctx context.Context, name string) (context.Context, func()) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, name, trace.WithNewRoot())
+	r := &refresh{span: span, pending: 1}
+	return context.WithValue(ctx, refreshKey{}, r), r.done
+}
+
+// Join adds a service about to be loaded to the refresh of ctx, if any, and
+// returns the function to call once it is loaded
+func Join(ctx context.Context) func() {
+	r, ok := ctx.Value(refreshKey{}).(*refresh)
+	if !ok {
+		return func() {}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pending++
+	return r.done
+}
+
+// done ends the span of the refresh once nothing is pending
+func (r *refresh) done() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.pending--; r.pending == 0 {
+		r.span.End()
+	}
+}
+
+// StartService starts the span of loading a service in the returned context
+func StartService(ctx context.Context, service string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, "load "+service, trace.WithAttributes(attribute.String("aws_overview.service", service)))
+}
+
+// CallMiddleware returns an API option recording a span of every call a
+// client of the given AWS service makes, in the trace of the context of the
+// call
+func CallMiddleware(service string) func(*middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		// After the operation's metadata is registered, which names it
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("Tracing",
+			func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+				serviceID, operation := awsmiddleware.GetServiceID(ctx), awsmiddleware.GetOperationName(ctx)
+				ctx, span := otel.Tracer(tracerName).Start(ctx, serviceID+"."+operation,
+					trace.WithSpanKind(trace.SpanKindClient),
+					trace.WithAttributes(
+						semconv.RPCSystemKey.String("aws-api"),
+						semconv.RPCService(serviceID),
+						semconv.RPCMethod(operation),
+						semconv.CloudRegion(awsmiddleware.GetRegion(ctx)),
+					))
+				defer span.End()
+
+				out, metadata, err := next.HandleInitialize(ctx, in)
+				if requestID, ok := awsmiddleware.GetRequestIDMetadata(metadata); ok {
+					span.SetAttributes(semconv.AWSRequestID(requestID))
+				}
+				if results, ok := retry.GetAttemptResults(metadata); ok {
+					span.SetAttributes(attribute.Int("aws.attempts", len(results.Results)))
+				}
+				if err != nil && !errors.Is(err, context.Canceled) {
+					span.RecordError(err)
+					span.SetStatus(codes.Error, err.Error())
+				}
+				return out, metadata, err
+			}), middleware.After)
+	}
+}
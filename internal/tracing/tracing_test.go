@@ -0,0 +1,89 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/smithy-go/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans records the spans ended during the test
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func TestStartRefresh(t *testing.T) {
+	recorder := recordSpans(t)
+
+	ctx, started := StartRefresh(context.Background(), "refresh")
+	rdsLoaded, sqsLoaded := Join(ctx), Join(ctx)
+	started()
+
+	_, span := StartService(ctx, "rds")
+	span.End()
+	rdsLoaded()
+	if ended := len(recorder.Ended()); ended != 1 {
+		t.Fatalf("Expected the refresh to wait for SQS, got %d spans", ended)
+	}
+	sqsLoaded()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 || spans[1].Name() != "refresh" || spans[0].Name() != "load rds" {
+		t.Fatalf("Expected the span of RDS and then of the refresh, got %v", spans)
+	}
+	if spans[0].Parent().SpanID() != spans[1].SpanContext().SpanID() {
+		t.Errorf("Expected the refresh to be the parent of the service")
+	}
+
+	// Loading outside of a refresh
+	Join(context.Background())()
+}
+
+func TestCallMiddleware(t *testing.T) {
+	recorder := recordSpans(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		w.Header().Set("X-Amzn-RequestId", "request-1")
+		w.Write([]byte(`{"QueueUrls":[]}`))
+	}))
+	defer server.Close()
+
+	client := sqs.NewFromConfig(aws.Config{
+		Region:      "eu-west-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKID", "secret", ""),
+		APIOptions:  []func(*middleware.Stack) error{CallMiddleware("sqs")},
+	}, func(o *sqs.Options) { o.BaseEndpoint = aws.String(server.URL) })
+
+	ctx, service := StartService(context.Background(), "sqs")
+	if _, err := client.ListQueues(ctx, &sqs.ListQueuesInput{}); err != nil {
+		t.Fatal(err)
+	}
+	service.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 || spans[0].Name() != "SQS.ListQueues" {
+		t.Fatalf("Expected the span of the call, got %v", spans)
+	}
+	if spans[0].Parent().SpanID() != spans[1].SpanContext().SpanID() {
+		t.Errorf("Expected the service to be the parent of the call")
+	}
+	attributes := attribute.NewSet(spans[0].Attributes()...)
+	for key, expected := range map[attribute.Key]string{"rpc.method": "ListQueues", "cloud.region": "eu-west-1", "aws.request_id": "request-1"} {
+		if value, _ := attributes.Value(key); value.AsString() != expected {
+			t.Errorf("Expected %s %q, got %q", key, expected, value.AsString())
+		}
+	}
+}
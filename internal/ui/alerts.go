@@ -0,0 +1,250 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/correctedcloud/aws-overview/internal/alerts"
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// alertFlashDuration is how long the tab of a service flashes after one of
+// its alert rules starts to be breached
+const alertFlashDuration = 10 * time.Second
+
+// alertFlashInterval is how often a flashing tab switches style
+const alertFlashInterval = 500 * time.Millisecond
+
+// bellOutput is where the terminal bell is rung. Bubble Tea owns stdout, and
+// stderr reaches the same terminal.
+var bellOutput io.Writer = os.Stderr
+
+// alertFlashMsg switches the style of the flashing tabs
+type alertFlashMsg struct{}
+
+// alertNotifiedMsg is sent when the command and webhook of a breached rule
+// have run
+type alertNotifiedMsg struct {
+	service string
+	breach  alerts.Breach
+	err     error
+}
+
+// checkAlerts checks the alert rules of service against the data of a load
+// without errors, as a partial load may lack the resources breaching a rule.
+// Rules starting to be breached ring the bell, flash the service's tab, are
+// logged to the event log and notify their command and webhook; rules no
+// longer breached are logged as cleared.
+func (m *Model) checkAlerts(service string, data alerts.Data) tea.Cmd {
+	if len(m.alertRules) == 0 {
+		return nil
+	}
+
+	current := make(map[string]alerts.Breach)
+	var cmds []tea.Cmd
+	var events []string
+	for _, breach := range alerts.Check(m.alertRules, service, data) {
+		current[breach.Key()] = breach
+		if _, ok := m.alertBreaches[breach.Key()]; ok {
+			continue
+		}
+		events = append(events, fmt.Sprintf("%s breached by %s (%s)", breach.Rule.Label(), breach.Resource, breach.Detail))
+		if breach.Rule.Notifies() {
+			cmds = append(cmds, m.notifyAlert(service, breach))
+		}
+	}
+	breached := len(events) > 0
+
+	var cleared []string
+	for key, breach := range m.alertBreaches {
+		if _, ok := current[key]; !ok && breach.Rule.Service() == service {
+			cleared = append(cleared, key)
+		}
+	}
+	sort.Strings(cleared)
+	for _, key := range cleared {
+		breach := m.alertBreaches[key]
+		events = append(events, fmt.Sprintf("%s no longer breached by %s", breach.Rule.Label(), breach.Resource))
+		delete(m.alertBreaches, key)
+	}
+	for key, breach := range current {
+		m.alertBreaches[key] = breach
+	}
+	m.recordEvents(service, events)
+
+	if breached {
+		cmds = append(cmds, ringBell)
+		m.alertFlashUntil[service] = time.Now().Add(alertFlashDuration)
+		if !m.alertFlashing {
+			m.alertFlashing = true
+			cmds = append(cmds, alertFlashTick())
+		}
+	} else if len(cleared) > 0 && !m.alertFlashing {
+		cmds = append(cmds, m.alertWindowTitle())
+	}
+	return tea.Batch(cmds...)
+}
+
+// notifyAlert runs the command of a breached rule and posts the breach to
+// its webhook and Slack
+func (m Model) notifyAlert(service string, breach alerts.Breach) tea.Cmd {
+	ctx, region := m.ctx, m.region
+	return func() tea.Msg {
+		return alertNotifiedMsg{service: service, breach: breach, err: alerts.Notify(ctx, breach, region)}
+	}
+}
+
+// ringBell rings the terminal bell
+func ringBell() tea.Msg {
+	fmt.Fprint(bellOutput, "\a")
+	return nil
+}
+
+// alertFlashTick schedules the next switch of the flashing tabs
+func alertFlashTick() tea.Cmd {
+	return tea.Tick(alertFlashInterval, func(time.Time) tea.Msg {
+		return alertFlashMsg{}
+	})
+}
+
+// updateAlertFlash switches the style of the flashing tabs, along with the
+// title of the terminal, until the last of them is done flashing
+func (m Model) updateAlertFlash() (Model, tea.Cmd) {
+	now := time.Now()
+	for service, until := range m.alertFlashUntil {
+		if !now.Before(until) {
+			delete(m.alertFlashUntil, service)
+		}
+	}
+	if len(m.alertFlashUntil) == 0 {
+		m.alertFlashing = false
+		m.alertFlashOn = false
+		return m, m.alertWindowTitle()
+	}
+
+	m.alertFlashOn = !m.alertFlashOn
+	return m, tea.Batch(m.alertWindowTitle(), alertFlashTick())
+}
+
+// alertWindowTitle sets the title of the terminal, and so of its tab, to the
+// number of breached rules, switching to sirens while flashing. An embedded
+// component leaves the title to its host.
+func (m Model) alertWindowTitle() tea.Cmd {
+	if m.embedded {
+		return nil
+	}
+	title := "aws-overview"
+	switch {
+	case m.alertFlashOn:
+		title = "🚨🚨🚨"
+	case len(m.alertBreaches) == 1:
+		title = "🚨 1 alert • aws-overview"
+	case len(m.alertBreaches) > 1:
+		title = fmt.Sprintf("🚨 %d alerts • aws-overview", len(m.alertBreaches))
+	}
+	if m.asciiSymbols {
+		title = common.ASCIISymbols(title)
+	}
+	return tea.SetWindowTitle(title)
+}
+
+// alertBreached reports whether an alert rule of service is breached
+func (m Model) alertBreached(service string) bool {
+	for _, breach := range m.alertBreaches {
+		if breach.Rule.Service() == service {
+			return true
+		}
+	}
+	return false
+}
+
+// alertFlashed reports whether the tab of service is in its flashed style
+func (m Model) alertFlashed(service string) bool {
+	_, ok := m.alertFlashUntil[service]
+	return ok && m.alertFlashOn
+}
+
+// alertData returns the data of the last load of service that the alert
+// rules check, and the errors of that load
+func (m Model) alertData(service string) (alerts.Data, error) {
+	switch service {
+	case "sqs":
+		return alerts.Data{Queues: m.sqsQueues}, errors.Join(m.sqsErrs...)
+	case "alb":
+		return alerts.Data{LoadBalancers: m.loadBalancers}, errors.Join(m.albErrs...)
+	case "ecs":
+		return alerts.Data{Services: m.ecsServices}, m.ecsErr
+	}
+	return alerts.Data{}, nil
+}
+
+// AlertMonitor checks the alert rules of its options without the terminal
+// UI, loading only the selected services the rules check
+type AlertMonitor struct {
+	m        Model
+	services []string
+}
+
+// NewAlertMonitor returns a monitor of the alert rules of opts
+func NewAlertMonitor(opts Options) *AlertMonitor {
+	m := NewModel(opts)
+	m.plain = true
+	m.maxResults = 0
+
+	seen := make(map[string]bool)
+	var services []string
+	for _, rule := range opts.Alerts {
+		if service := rule.Service(); !seen[service] && m.hasTab(service) {
+			seen[service] = true
+			services = append(services, service)
+		}
+	}
+	sort.Strings(services)
+	return &AlertMonitor{m: m, services: services}
+}
+
+// Services returns the services the monitor loads, e.g. "sqs"
+func (a *AlertMonitor) Services() []string {
+	return a.services
+}
+
+// Region returns the region of the last check
+func (a *AlertMonitor) Region() string {
+	return a.m.region
+}
+
+// Check loads the services of the monitor and returns the breaches of the
+// rules by service. Services that failed to load, even partly, return their
+// error instead, as they may lack the resources breaching a rule.
+func (a *AlertMonitor) Check() (map[string][]alerts.Breach, map[string]error) {
+	// Commands are built on this goroutine, since fetch records them in a map
+	var loads []tea.Cmd
+	for _, t := range a.m.tabs {
+		if t.load != nil && slices.Contains(a.services, t.service) {
+			loads = append(loads, t.load(a.m))
+		}
+	}
+	for _, msg := range collect(tea.Batch(loads...)) {
+		updated, _ := a.m.Update(msg)
+		a.m = updated.(Model)
+	}
+
+	breaches := make(map[string][]alerts.Breach)
+	failed := make(map[string]error)
+	for _, service := range a.services {
+		data, err := a.m.alertData(service)
+		if err != nil {
+			failed[service] = err
+			continue
+		}
+		breaches[service] = alerts.Check(a.m.alertRules, service, data)
+	}
+	return breaches, failed
+}
@@ -0,0 +1,20 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/correctedcloud/aws-overview/internal/audit"
+)
+
+// audit records an action on a resource in the audit log, if any. A failure
+// to record it is logged to the event log of service, since the action was
+// already taken.
+func (m *Model) audit(service string, entry audit.Entry, err error) {
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	entry.Region = m.region
+	if err := m.auditLog.Record(entry); err != nil {
+		m.recordEvents(service, []string{fmt.Sprintf("%s on %s not audited: %v", entry.Action, entry.Resource, err)})
+	}
+}
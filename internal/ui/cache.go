@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	"github.com/correctedcloud/aws-overview/internal/cache"
+	"github.com/correctedcloud/aws-overview/internal/config"
+	"github.com/correctedcloud/aws-overview/pkg/cloudwatchmetrics"
+	ecspkg "github.com/correctedcloud/aws-overview/pkg/ecs"
+)
+
+// cacheKey returns the key a service's responses are cached under for the
+// account and region of awsConfig, or "" when they are not cached
+func (m Model) cacheKey(ctx context.Context, awsConfig aws.Config, service string) string {
+	if m.cache == nil {
+		return ""
+	}
+
+	account, err := m.cache.Account(ctx, func(ctx context.Context) (string, error) {
+		return config.AccountID(ctx, awsConfig)
+	})
+	if err != nil {
+		// Without the account, responses of different accounts could mix
+		return ""
+	}
+	return cache.Key(account, awsConfig.Region, service)
+}
+
+// cached decodes the cached response under key into v and returns when it
+// was cached. Manual refreshes bypass the cache.
+func (m Model) cached(key string, v any) (time.Time, bool) {
+	if key == "" || m.bypassCache {
+		return time.Time{}, false
+	}
+	return m.cache.Get(key, v)
+}
+
+// store caches a complete response under key
+func (m Model) store(key string, v any) {
+	if key == "" {
+		return
+	}
+	// A response that cannot be written to disk is still cached in memory,
+	// which is all the current session needs
+	_ = m.cache.Put(key, v)
+}
+
+// sqsCacheService returns the cache key of the SQS data, which depends on
+// the queue name prefix and the metrics window
+func (m Model) sqsCacheService() string {
+	if m.queuePrefix == "" {
+		return m.windowCacheService("sqs")
+	}
+	return m.windowCacheService("sqs-" + url.PathEscape(m.queuePrefix))
+}
+
+// windowCacheService returns the cache key of the data of a service with
+// metrics over the window chosen with -window or the 1, 3, 6 and 24 keys
+func (m Model) windowCacheService(service string) string {
+	if m.window == cloudwatchmetrics.DefaultWindow {
+		return service
+	}
+	return service + "-" + m.window.String()
+}
+
+// ecsCacheService returns the cache key of the ECS data, which holds the
+// Container Insights metrics when they are loaded
+func (m Model) ecsCacheService() string {
+	if m.containerInsights {
+		return "ecs-insights"
+	}
+	return "ecs"
+}
+
+// cachedECS is the cached response of the ECS tab
+type cachedECS struct {
+	Services  []ecspkg.ServiceSummary
+	Scheduled []ecspkg.ScheduledTask
+}
+
+// cachedPage is the cached response of a limited service, which loads only
+// the first of its resources
+type cachedPage[T any] struct {
+	Items []T
+	Total int // Number of resources in the account
+}
+
+// fresh returns a copy of the model whose loads bypass the cache
+func (m Model) fresh() Model {
+	m.bypassCache = true
+	return m
+}
+
+// setCachedAt records when the data of a service shown now was cached
+func (m *Model) setCachedAt(service string, cachedAt time.Time) {
+	if cachedAt.IsZero() {
+		delete(m.cachedAt, service)
+		return
+	}
+	m.cachedAt[service] = cachedAt
+}
@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// toastDuration is how long a toast replaces the help text
+const toastDuration = 3 * time.Second
+
+// clipboardOutput is where the OSC 52 sequence setting the clipboard of the
+// terminal is written when the system clipboard is unavailable, e.g. over
+// SSH. Bubble Tea owns stdout, and stderr reaches the same terminal.
+var clipboardOutput io.Writer = os.Stderr
+
+// writeClipboard copies text to the system clipboard, falling back to asking
+// the terminal to do so
+var writeClipboard = func(text string) error {
+	if err := clipboard.WriteAll(text); err == nil {
+		return nil
+	}
+	_, err := fmt.Fprint(clipboardOutput, ansi.SetSystemClipboard(text))
+	return err
+}
+
+// identifier is the primary identifier of a selected resource, e.g. the ID
+// of an instance, copied by y
+type identifier struct {
+	kind  string // e.g. "instance ID"
+	value string
+}
+
+// toastMsg hides the toast with the same id once its time is up
+type toastMsg struct {
+	id int
+}
+
+// copyIdentifier copies the identifier of the resource selected on the
+// active tab to the clipboard and confirms it with a toast
+func (m Model) copyIdentifier() (Model, tea.Cmd) {
+	copyable := m.currentTab().identifier
+	if copyable == nil {
+		return m, nil
+	}
+	id, ok := copyable(m)
+	if !ok {
+		return m, nil
+	}
+	if id.value == "" {
+		return m.showToast(fmt.Sprintf("The %s is unknown, press r to reload", id.kind), errorColor)
+	}
+	if err := writeClipboard(id.value); err != nil {
+		return m.showToast(fmt.Sprintf("Failed to copy the %s: %v", id.kind, err), errorColor)
+	}
+	return m.showToast(fmt.Sprintf("Copied %s %s", id.kind, id.value), successColor)
+}
+
+// showToast shows text in color in place of the help text for a few seconds
+func (m Model) showToast(text string, color lipgloss.Color) (Model, tea.Cmd) {
+	m.toastID++
+	m.toast = lipgloss.NewStyle().Foreground(color).Render(text)
+	id := m.toastID
+	return m, tea.Tick(toastDuration, func(time.Time) tea.Msg {
+		return toastMsg{id: id}
+	})
+}
+
+// albIdentifier returns the DNS name of the load balancer of the selected target
+func (m Model) albIdentifier() (identifier, bool) {
+	target, _, ok := m.selectedTarget()
+	if !ok {
+		return identifier{}, false
+	}
+	for _, lb := range m.loadBalancers {
+		if lb.Name == target.LoadBalancer {
+			return identifier{kind: "DNS name", value: lb.DNSName}, true
+		}
+	}
+	return identifier{}, false
+}
+
+// rdsIdentifier returns the endpoint of the selected DB instance
+func (m Model) rdsIdentifier() (identifier, bool) {
+	instance, ok := m.selectedDBInstance()
+	return identifier{kind: "endpoint", value: instance.Endpoint}, ok
+}
+
+// ec2Identifier returns the ID of the selected instance
+func (m Model) ec2Identifier() (identifier, bool) {
+	instance, ok := m.selectedEC2Instance()
+	return identifier{kind: "instance ID", value: instance.InstanceID}, ok
+}
+
+// ecsIdentifier returns the ARN of the selected service
+func (m Model) ecsIdentifier() (identifier, bool) {
+	service, ok := m.selectedService()
+	return identifier{kind: "service ARN", value: service.ARN}, ok
+}
+
+// sqsIdentifier returns the URL of the selected queue
+func (m Model) sqsIdentifier() (identifier, bool) {
+	queue, ok := m.selectedQueue()
+	return identifier{kind: "queue URL", value: queue.URL}, ok
+}
+
+// lambdaIdentifier returns the ARN of the selected function
+func (m Model) lambdaIdentifier() (identifier, bool) {
+	function, ok := m.selectedFunction()
+	return identifier{kind: "function ARN", value: function.ARN}, ok
+}
+
+// cloudfrontIdentifier returns the ID of the selected distribution
+func (m Model) cloudfrontIdentifier() (identifier, bool) {
+	distribution, ok := m.selectedDistribution()
+	return identifier{kind: "distribution ID", value: distribution.ID}, ok
+}
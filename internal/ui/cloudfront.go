@@ -0,0 +1,282 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/correctedcloud/aws-overview/internal/audit"
+	"github.com/correctedcloud/aws-overview/internal/permissions"
+	cloudfrontpkg "github.com/correctedcloud/aws-overview/pkg/cloudfront"
+	"github.com/correctedcloud/aws-overview/pkg/demo"
+)
+
+// invalidationPollInterval is how often an invalidation is checked until it completes
+const invalidationPollInterval = 5 * time.Second
+
+// invalidationMsg carries the state of an invalidation after it was created or polled
+type invalidationMsg struct {
+	invalidation cloudfrontpkg.InvalidationSummary
+	err          error
+
+	// Set when the invalidation was created, for the audit log
+	distribution string
+	paths        []string
+}
+
+// invalidationPollMsg is sent when it's time to check the tracked invalidation again
+type invalidationPollMsg struct {
+	id string
+}
+
+// newInvalidationInput returns the prompt of the paths to invalidate
+func newInvalidationInput() textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "paths separated by spaces, e.g. /index.html /assets/*"
+	input.CharLimit = 4096
+	return input
+}
+
+// selectedDistribution returns the distribution selected on the CloudFront tab
+func (m Model) selectedDistribution() (cloudfrontpkg.DistributionSummary, bool) {
+	if m.cloudfrontSelected < 0 || m.cloudfrontSelected >= len(m.cloudfrontDistributions) {
+		return cloudfrontpkg.DistributionSummary{}, false
+	}
+	return m.cloudfrontDistributions[m.cloudfrontSelected], true
+}
+
+// distributionByID returns the listed distribution with id, which refreshes
+// may have moved or removed since the invalidation prompt was opened for it
+func (m Model) distributionByID(id string) (cloudfrontpkg.DistributionSummary, bool) {
+	for _, distribution := range m.cloudfrontDistributions {
+		if distribution.ID == id {
+			return distribution, true
+		}
+	}
+	return cloudfrontpkg.DistributionSummary{}, false
+}
+
+// updateCloudFrontKeys handles the keys of the CloudFront tab: the arrow keys
+// select a distribution instead of scrolling, i opens the invalidation prompt
+// and esc stops tracking the invalidation
+func (m Model) updateCloudFrontKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "up", "k":
+		m.moveCloudFrontSelection(-1)
+		return m, nil, true
+	case "down", "j":
+		m.moveCloudFrontSelection(1)
+		return m, nil, true
+	case "i":
+		cmd := m.openInvalidationInput()
+		return m, cmd, true
+	case "esc":
+		if m.creatingInvalidation {
+			return m, nil, true
+		}
+		m.invalidation = nil
+		m.invalidationErr = nil
+		m.updateViewportContent()
+		return m, nil, true
+	}
+	return m, nil, false
+}
+
+// cloudfrontHelp describes the keys of the CloudFront tab
+func (m Model) cloudfrontHelp() string {
+	if !m.allowActions {
+		return "↑↓ Select"
+	}
+	return "↑↓ Select • i Invalidate"
+}
+
+// moveCloudFrontSelection moves the selection by delta distributions and
+// scrolls the viewport so the selected distribution stays visible
+func (m *Model) moveCloudFrontSelection(delta int) {
+	if len(m.cloudfrontDistributions) == 0 {
+		return
+	}
+	m.cloudfrontSelected = max(0, min(m.shown("cloudfront", len(m.cloudfrontDistributions))-1, m.cloudfrontSelected+delta))
+	m.updateViewportContent()
+	m.scrollToSelection(m.renderCloudFront())
+}
+
+// openInvalidationInput starts entering the paths to invalidate on the
+// selected distribution, suggesting all paths
+func (m *Model) openInvalidationInput() tea.Cmd {
+	if !m.allowActions {
+		m.invalidationErr = errActionsDisabled
+		m.updateViewportContent()
+		return nil
+	}
+	distribution, ok := m.selectedDistribution()
+	if !ok || m.creatingInvalidation {
+		return nil
+	}
+
+	m.invalidationErr = nil
+	m.invalidationTarget = distribution.ID
+	m.invalidationInput.Prompt = "Invalidate " + distribution.Name() + " › "
+	m.invalidationInput.SetValue("/*")
+	m.invalidationInput.CursorEnd()
+	return m.invalidationInput.Focus()
+}
+
+// updateInvalidationInput handles a key while the invalidation prompt has
+// focus. Enter creates the invalidation on the distribution the prompt was
+// opened for and esc closes the prompt.
+func (m Model) updateInvalidationInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.invalidationInput.Blur()
+		m.invalidationErr = nil
+		return m, nil
+	case "enter":
+		paths, err := cloudfrontpkg.ParsePaths(m.invalidationInput.Value())
+		if err != nil {
+			m.invalidationErr = err
+			return m, nil
+		}
+		distribution, ok := m.distributionByID(m.invalidationTarget)
+		if !ok {
+			m.invalidationInput.Blur()
+			m.invalidation = nil
+			m.invalidationErr = fmt.Errorf("distribution %s is no longer listed, no invalidation created", m.invalidationTarget)
+			m.updateViewportContent()
+			return m, nil
+		}
+
+		m.invalidationInput.Blur()
+		m.invalidation = nil
+		m.invalidationErr = nil
+		m.creatingInvalidation = true
+		m.updateViewportContent()
+		return m, m.createInvalidation(distribution, paths)
+	}
+
+	var cmd tea.Cmd
+	m.invalidationInput, cmd = m.invalidationInput.Update(msg)
+	return m, cmd
+}
+
+// cloudfrontActionClient returns a CloudFront client for actions, which are not cached
+func (m Model) cloudfrontActionClient(ctx context.Context) (*cloudfrontpkg.Client, error) {
+	if m.demo {
+		return cloudfrontpkg.NewClient(demo.NewCloudFront(), m.pool), nil
+	}
+
+	awsConfig, _, err := m.loadAWSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return cloudfrontpkg.NewClient(cloudfront.NewFromConfig(m.limiters.Apply(awsConfig, "cloudfront")), m.pool), nil
+}
+
+// createInvalidation is a command that invalidates paths of the distribution
+func (m Model) createInvalidation(distribution cloudfrontpkg.DistributionSummary, paths []string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(m.ctx, m.timeout)
+		defer cancel()
+
+		client, err := m.cloudfrontActionClient(ctx)
+		if err != nil {
+			return invalidationMsg{err: err, distribution: distribution.ID, paths: paths}
+		}
+		invalidation, err := client.CreateInvalidation(ctx, distribution.ID, paths)
+		return invalidationMsg{invalidation: invalidation, err: err, distribution: distribution.ID, paths: paths}
+	}
+}
+
+// pollInvalidation is a command that checks the tracked invalidation after the poll interval
+func pollInvalidation(id string) tea.Cmd {
+	return tea.Tick(invalidationPollInterval, func(time.Time) tea.Msg {
+		return invalidationPollMsg{id: id}
+	})
+}
+
+// getInvalidation is a command that returns the current state of an invalidation
+func (m Model) getInvalidation(invalidation cloudfrontpkg.InvalidationSummary) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(m.ctx, m.timeout)
+		defer cancel()
+
+		client, err := m.cloudfrontActionClient(ctx)
+		if err != nil {
+			return invalidationMsg{invalidation: invalidation, err: err}
+		}
+		current, err := client.GetInvalidation(ctx, invalidation.DistributionID, invalidation.ID)
+		if err != nil {
+			// Keep showing the last known state, a later poll may succeed
+			return invalidationMsg{invalidation: invalidation, err: err}
+		}
+		return invalidationMsg{invalidation: current}
+	}
+}
+
+// updateInvalidation records the state of the tracked invalidation and polls
+// it again until it completes
+func (m Model) updateInvalidation(msg invalidationMsg) (Model, tea.Cmd) {
+	creating := m.creatingInvalidation
+	m.creatingInvalidation = false
+	if creating {
+		m.audit("cloudfront", audit.Entry{
+			Action:   "cloudfront:CreateInvalidation",
+			Resource: msg.distribution,
+			Detail:   "paths " + strings.Join(msg.paths, " "),
+		}, msg.err)
+	}
+
+	// Ignore polls of an invalidation that is no longer tracked
+	if !creating && (m.invalidation == nil || m.invalidation.ID != msg.invalidation.ID) {
+		return m, nil
+	}
+
+	m.invalidationErr = msg.err
+	if msg.invalidation.ID == "" {
+		m.updateViewportContent()
+		return m, nil
+	}
+
+	invalidation := msg.invalidation
+	m.invalidation = &invalidation
+	m.updateViewportContent()
+	if creating {
+		m.viewport.GotoTop()
+	}
+	if invalidation.Completed() {
+		return m, nil
+	}
+	return m, pollInvalidation(invalidation.ID)
+}
+
+// renderInvalidationInput shows the invalidation prompt and why the last input was rejected
+func (m Model) renderInvalidationInput() string {
+	view := m.invalidationInput.View()
+	if m.invalidationErr != nil {
+		view += "  " + lipgloss.NewStyle().Foreground(errorColor).Render(m.invalidationErr.Error())
+	}
+	return view
+}
+
+// renderInvalidation shows the tracked invalidation above the distributions
+func (m Model) renderInvalidation() string {
+	switch {
+	case m.creatingInvalidation:
+		return m.spinner.View() + " Creating invalidation...\n\n"
+	case m.invalidation != nil:
+		content := cloudfrontpkg.FormatInvalidation(*m.invalidation)
+		if m.invalidationErr != nil {
+			content += lipgloss.NewStyle().Foreground(warningColor).Render("⚠️ "+permissions.Describe(m.invalidationErr)) + "\n"
+		}
+		return content + "\n"
+	case m.invalidationErr != nil && !m.invalidationInput.Focused():
+		return "Invalidation failed: " + permissions.Describe(m.invalidationErr) + "\n" + renderHints([]error{m.invalidationErr}) + "\n"
+	}
+	return ""
+}
@@ -6,221 +6,922 @@ import (
 
 	"github.com/charmbracelet/bubbletea"
 
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/apigateway"
+	"github.com/aws/aws-sdk-go-v2/service/apigatewayv2"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
 	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing"
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
 	rdssvc "github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
 
-	"github.com/correctedcloud/aws-overview/internal/config"
 	"github.com/correctedcloud/aws-overview/pkg/alb"
+	apigatewaypkg "github.com/correctedcloud/aws-overview/pkg/apigateway"
+	cloudfrontpkg "github.com/correctedcloud/aws-overview/pkg/cloudfront"
+	costpkg "github.com/correctedcloud/aws-overview/pkg/cost"
+	"github.com/correctedcloud/aws-overview/pkg/demo"
+	dnspkg "github.com/correctedcloud/aws-overview/pkg/dns"
+	drpkg "github.com/correctedcloud/aws-overview/pkg/dr"
+	"github.com/correctedcloud/aws-overview/pkg/ebs"
 	ec2pkg "github.com/correctedcloud/aws-overview/pkg/ec2"
+	ecrpkg "github.com/correctedcloud/aws-overview/pkg/ecr"
 	ecspkg "github.com/correctedcloud/aws-overview/pkg/ecs"
+	"github.com/correctedcloud/aws-overview/pkg/lag"
+	lambdapkg "github.com/correctedcloud/aws-overview/pkg/lambda"
 	"github.com/correctedcloud/aws-overview/pkg/rds"
+	snspkg "github.com/correctedcloud/aws-overview/pkg/sns"
 	sqspkg "github.com/correctedcloud/aws-overview/pkg/sqs"
+	ssmpkg "github.com/correctedcloud/aws-overview/pkg/ssm"
+	vpcpkg "github.com/correctedcloud/aws-overview/pkg/vpc"
 )
 
 // Message types for bubbletea
 type albDataLoadedMsg struct {
 	loadBalancers []alb.LoadBalancerSummary
-	err           error
+	total         int // Number of load balancers in the account, of which the first were loaded
+	errs          []error
 	region        string
+	cachedAt      time.Time // When the data was cached, zero when freshly loaded
 }
 
 type rdsDataLoadedMsg struct {
 	dbInstances []rds.DBInstanceSummary
-	err         error
+	errs        []error
 	region      string
+	cachedAt    time.Time // When the data was cached, zero when freshly loaded
 }
 
 type ec2DataLoadedMsg struct {
 	instances []ec2pkg.InstanceSummary
-	err       error
+	errs      []error
 	region    string
+	cachedAt  time.Time // When the data was cached, zero when freshly loaded
+}
+
+type ebsDataLoadedMsg struct {
+	volumes  []ebs.VolumeSummary
+	errs     []error
+	region   string
+	cachedAt time.Time // When the data was cached, zero when freshly loaded
+}
+
+type vpcDataLoadedMsg struct {
+	vpcs     []vpcpkg.VPCSummary
+	errs     []error
+	region   string
+	cachedAt time.Time // When the data was cached, zero when freshly loaded
 }
 
 type ecsDataLoadedMsg struct {
+	services  []ecspkg.ServiceSummary
+	scheduled []ecspkg.ScheduledTask
+	err       error
+	errs      []error // Scheduled tasks and Container Insights metrics that failed to load
+	region    string
+	cachedAt  time.Time // When the data was cached, zero when freshly loaded
+}
+
+// albPartialMsg carries the load balancers loaded so far by a fetch in progress
+type albPartialMsg struct {
+	loadBalancers []alb.LoadBalancerSummary
+}
+
+// ecsPartialMsg carries the services loaded so far by a fetch in progress
+type ecsPartialMsg struct {
 	services []ecspkg.ServiceSummary
-	err      error
+}
+
+type ecrDataLoadedMsg struct {
+	repositories []ecrpkg.RepositorySummary
+	total        int // Number of repositories in the account, of which the first were loaded
+	errs         []error
+	region       string
+	cachedAt     time.Time // When the data was cached, zero when freshly loaded
+}
+
+type apiGatewayDataLoadedMsg struct {
+	apis     []apigatewaypkg.APISummary
+	errs     []error
 	region   string
+	cachedAt time.Time // When the data was cached, zero when freshly loaded
+}
+
+type costDataLoadedMsg struct {
+	summary  *costpkg.Summary // Nil when the spend failed to load
+	errs     []error
+	region   string
+	cachedAt time.Time // When the data was cached, zero when freshly loaded
 }
 
 type sqsDataLoadedMsg struct {
-	queues []sqspkg.QueueSummary
-	err    error
-	region string
+	queues   []sqspkg.QueueSummary
+	errs     []error
+	region   string
+	cachedAt time.Time // When the data was cached, zero when freshly loaded
+}
+
+type ssmDataLoadedMsg struct {
+	instances []ssmpkg.InstanceSummary
+	errs      []error
+	region    string
+	cachedAt  time.Time // When the data was cached, zero when freshly loaded
+}
+
+type dnsDataLoadedMsg struct {
+	records  []dnspkg.RecordSummary
+	errs     []error
+	region   string
+	cachedAt time.Time // When the data was cached, zero when freshly loaded
+}
+
+type drDataLoadedMsg struct {
+	resources []drpkg.ResourceSummary
+	errs      []error
+	region    string
+	cachedAt  time.Time // When the data was cached, zero when freshly loaded
+}
+
+type snsDataLoadedMsg struct {
+	topics   []snspkg.TopicSummary
+	errs     []error
+	region   string
+	cachedAt time.Time // When the data was cached, zero when freshly loaded
+}
+
+type lambdaDataLoadedMsg struct {
+	functions []lambdapkg.FunctionSummary
+	err       error
+	region    string
+	cachedAt  time.Time // When the data was cached, zero when freshly loaded
+}
+
+type cloudfrontDataLoadedMsg struct {
+	distributions []cloudfrontpkg.DistributionSummary
+	err           error
+	region        string
+	cachedAt      time.Time // When the data was cached, zero when freshly loaded
+}
+
+type lagDataLoadedMsg struct {
+	indicators lag.Indicators
+	errs       []error
+	region     string
+	cachedAt   time.Time // When the data was cached, zero when freshly loaded
 }
 
 // refreshTimerMsg is sent when it's time to refresh data
 type refreshTimerMsg struct{}
 
-// loadALBData is a command that loads ALB data and returns a message
+// loadALBData is a command that loads load balancer data and returns a message
 func (m Model) loadALBData() tea.Cmd {
-	return func() tea.Msg {
-		// Create context
-		ctx := context.Background()
+	return m.fetchProgressively("alb", func(ctx context.Context, partial func(tea.Msg)) tea.Msg {
+		loaded := func(lbs []alb.LoadBalancerSummary) {
+			partial(albPartialMsg{loadBalancers: lbs})
+		}
+		limit := m.limit("alb")
+
+		if m.demo {
+			lbs, total, errs := alb.NewClient(demo.NewELBv2(), demo.NewACM(), m.pool).StreamLoadBalancers(ctx, limit, loaded)
+			return albDataLoadedMsg{loadBalancers: lbs, total: total, errs: errs, region: demo.Region}
+		}
 
 		// Load AWS config
-		cfg := config.NewConfig(m.region)
-		awsConfig, err := config.LoadAWSConfig(ctx, cfg)
+		awsConfig, region, err := m.loadAWSConfig(ctx)
 		if err != nil {
-			return albDataLoadedMsg{err: err}
+			return albDataLoadedMsg{errs: []error{err}}
 		}
 
-		// Create ALB client
-		albClient := alb.NewClient(elasticloadbalancingv2.NewFromConfig(awsConfig))
+		// Serve the cached response while it is fresh
+		key := m.cacheKey(ctx, awsConfig, limitedCacheService("alb", limit))
+		var cached cachedPage[alb.LoadBalancerSummary]
+		if cachedAt, ok := m.cached(key, &cached); ok {
+			return albDataLoadedMsg{loadBalancers: cached.Items, total: cached.Total, region: region, cachedAt: cachedAt}
+		}
+
+		// Create load balancer client
+		albClient := alb.NewClient(
+			elasticloadbalancingv2.NewFromConfig(m.limiters.Apply(awsConfig, "elasticloadbalancing")),
+			acm.NewFromConfig(m.limiters.Apply(awsConfig, "acm")),
+			m.pool,
+		)
 
-		// Get load balancer data
-		lbs, err := albClient.GetLoadBalancers(ctx)
+		// Get load balancer data, showing each one as soon as it is loaded
+		lbs, total, errs := albClient.StreamLoadBalancers(ctx, limit, loaded)
+		if len(errs) == 0 {
+			m.store(key, cachedPage[alb.LoadBalancerSummary]{Items: lbs, Total: total})
+		}
 		return albDataLoadedMsg{
 			loadBalancers: lbs,
-			err:           err,
-			region:        cfg.Region, // Pass the potentially updated region
+			total:         total,
+			errs:          errs,
+			region:        region, // Pass the potentially updated region
 		}
-	}
+	})
 }
 
 // loadRDSData is a command that loads RDS data and returns a message
 func (m Model) loadRDSData() tea.Cmd {
-	return func() tea.Msg {
-		// Create context
-		ctx := context.Background()
+	return m.fetch("rds", func(ctx context.Context) tea.Msg {
+		if m.demo {
+			instances, errs := rds.NewClient(demo.NewRDS(), demo.NewCloudWatch(), demo.NewEC2(), m.pool, m.window).GetDBInstances(ctx)
+			return rdsDataLoadedMsg{dbInstances: instances, errs: errs, region: demo.Region}
+		}
 
 		// Load AWS config
-		cfg := config.NewConfig(m.region)
-		awsConfig, err := config.LoadAWSConfig(ctx, cfg)
+		awsConfig, region, err := m.loadAWSConfig(ctx)
 		if err != nil {
-			return rdsDataLoadedMsg{err: err}
+			return rdsDataLoadedMsg{errs: []error{err}}
+		}
+
+		// Serve the cached response while it is fresh
+		key := m.cacheKey(ctx, awsConfig, m.windowCacheService("rds"))
+		var cached []rds.DBInstanceSummary
+		if cachedAt, ok := m.cached(key, &cached); ok {
+			return rdsDataLoadedMsg{dbInstances: cached, region: region, cachedAt: cachedAt}
 		}
 
 		// Create RDS client
 		rdsClient := rds.NewClient(
-			rdssvc.NewFromConfig(awsConfig),
-			cloudwatch.NewFromConfig(awsConfig),
+			rdssvc.NewFromConfig(m.limiters.Apply(awsConfig, "rds")),
+			cloudwatch.NewFromConfig(m.limiters.Apply(awsConfig, "cloudwatch")),
+			ec2.NewFromConfig(m.limiters.Apply(awsConfig, "ec2")),
+			m.pool,
+			m.window,
 		)
 
 		// Get DB instance data
-		instances, err := rdsClient.GetDBInstances(ctx)
+		instances, errs := rdsClient.GetDBInstances(ctx)
+		if len(errs) == 0 {
+			m.store(key, instances)
+		}
 		return rdsDataLoadedMsg{
 			dbInstances: instances,
-			err:         err,
-			region:      cfg.Region, // Pass the potentially updated region
+			errs:        errs,
+			region:      region, // Pass the potentially updated region
 		}
-	}
+	})
 }
 
 // loadEC2Data is a command that loads EC2 data and returns a message
 func (m Model) loadEC2Data() tea.Cmd {
-	return func() tea.Msg {
-		// Create context
-		ctx := context.Background()
+	return m.fetch("ec2", func(ctx context.Context) tea.Msg {
+		if m.demo {
+			instances, errs := ec2pkg.NewClient(demo.NewEC2()).GetInstances(ctx)
+			return ec2DataLoadedMsg{instances: instances, errs: errs, region: demo.Region}
+		}
 
 		// Load AWS config
-		cfg := config.NewConfig(m.region)
-		awsConfig, err := config.LoadAWSConfig(ctx, cfg)
+		awsConfig, region, err := m.loadAWSConfig(ctx)
 		if err != nil {
-			return ec2DataLoadedMsg{err: err}
+			return ec2DataLoadedMsg{errs: []error{err}}
+		}
+
+		// Serve the cached response while it is fresh
+		key := m.cacheKey(ctx, awsConfig, "ec2")
+		var cached []ec2pkg.InstanceSummary
+		if cachedAt, ok := m.cached(key, &cached); ok {
+			return ec2DataLoadedMsg{instances: cached, region: region, cachedAt: cachedAt}
 		}
 
 		// Create EC2 client
-		ec2Client := ec2pkg.NewClient(ec2.NewFromConfig(awsConfig))
+		ec2Client := ec2pkg.NewClient(ec2.NewFromConfig(m.limiters.Apply(awsConfig, "ec2")))
 
 		// Get instance data
-		instances, err := ec2Client.GetInstances(ctx)
+		instances, errs := ec2Client.GetInstances(ctx)
+		if len(errs) == 0 {
+			m.store(key, instances)
+		}
 		return ec2DataLoadedMsg{
 			instances: instances,
-			err:       err,
-			region:    cfg.Region, // Pass the potentially updated region
+			errs:      errs,
+			region:    region, // Pass the potentially updated region
 		}
-	}
+	})
+}
+
+// loadEBSData is a command that loads EBS volumes and returns a message
+func (m Model) loadEBSData() tea.Cmd {
+	return m.fetch("ebs", func(ctx context.Context) tea.Msg {
+		if m.demo {
+			volumes, errs := ebs.NewClient(demo.NewEC2(), demo.NewCloudWatch(), m.pool).GetVolumes(ctx)
+			return ebsDataLoadedMsg{volumes: volumes, errs: errs, region: demo.Region}
+		}
+
+		// Load AWS config
+		awsConfig, region, err := m.loadAWSConfig(ctx)
+		if err != nil {
+			return ebsDataLoadedMsg{errs: []error{err}}
+		}
+
+		// Serve the cached response while it is fresh
+		key := m.cacheKey(ctx, awsConfig, "ebs")
+		var cached []ebs.VolumeSummary
+		if cachedAt, ok := m.cached(key, &cached); ok {
+			return ebsDataLoadedMsg{volumes: cached, region: region, cachedAt: cachedAt}
+		}
+
+		// Create EBS client
+		ebsClient := ebs.NewClient(
+			ec2.NewFromConfig(m.limiters.Apply(awsConfig, "ec2")),
+			cloudwatch.NewFromConfig(m.limiters.Apply(awsConfig, "cloudwatch")),
+			m.pool,
+		)
+
+		// Get volume data
+		volumes, errs := ebsClient.GetVolumes(ctx)
+		if len(errs) == 0 {
+			m.store(key, volumes)
+		}
+		return ebsDataLoadedMsg{
+			volumes: volumes,
+			errs:    errs,
+			region:  region, // Pass the potentially updated region
+		}
+	})
+}
+
+// loadAPIGatewayData is a command that loads the REST and HTTP APIs of API
+// Gateway and returns a message
+func (m Model) loadAPIGatewayData() tea.Cmd {
+	return m.fetch("apigw", func(ctx context.Context) tea.Msg {
+		if m.demo {
+			apis, errs := apigatewaypkg.NewClient(demo.NewAPIGateway(), demo.NewAPIGatewayV2(), demo.NewCloudWatch(), m.pool).GetAPIs(ctx)
+			return apiGatewayDataLoadedMsg{apis: apis, errs: errs, region: demo.Region}
+		}
+
+		// Load AWS config
+		awsConfig, region, err := m.loadAWSConfig(ctx)
+		if err != nil {
+			return apiGatewayDataLoadedMsg{errs: []error{err}}
+		}
+
+		// Serve the cached response while it is fresh
+		key := m.cacheKey(ctx, awsConfig, "apigw")
+		var cached []apigatewaypkg.APISummary
+		if cachedAt, ok := m.cached(key, &cached); ok {
+			return apiGatewayDataLoadedMsg{apis: cached, region: region, cachedAt: cachedAt}
+		}
+
+		// Create API Gateway client; REST and HTTP APIs share the apigateway rate limit
+		apiGatewayClient := apigatewaypkg.NewClient(
+			apigateway.NewFromConfig(m.limiters.Apply(awsConfig, "apigateway")),
+			apigatewayv2.NewFromConfig(m.limiters.Apply(awsConfig, "apigateway")),
+			cloudwatch.NewFromConfig(m.limiters.Apply(awsConfig, "cloudwatch")),
+			m.pool,
+		)
+
+		// Get API data
+		apis, errs := apiGatewayClient.GetAPIs(ctx)
+		if len(errs) == 0 {
+			m.store(key, apis)
+		}
+		return apiGatewayDataLoadedMsg{
+			apis:   apis,
+			errs:   errs,
+			region: region, // Pass the potentially updated region
+		}
+	})
+}
+
+// loadCostData is a command that loads the spend from Cost Explorer and
+// returns a message
+func (m Model) loadCostData() tea.Cmd {
+	return m.fetch("cost", func(ctx context.Context) tea.Msg {
+		if m.demo {
+			summary, err := costpkg.NewClient(demo.NewCostExplorer(), m.pool).GetSummary(ctx)
+			if err != nil {
+				return costDataLoadedMsg{errs: []error{err}, region: demo.Region}
+			}
+			return costDataLoadedMsg{summary: &summary, region: demo.Region}
+		}
+
+		// Load AWS config
+		awsConfig, region, err := m.loadAWSConfig(ctx)
+		if err != nil {
+			return costDataLoadedMsg{errs: []error{err}}
+		}
+
+		// Serve the cached response while it is fresh
+		key := m.cacheKey(ctx, awsConfig, "cost")
+		var cached costpkg.Summary
+		if cachedAt, ok := m.cached(key, &cached); ok {
+			return costDataLoadedMsg{summary: &cached, region: region, cachedAt: cachedAt}
+		}
+
+		// Create Cost Explorer client; the spend is global, whatever the region
+		costClient := costpkg.NewClient(costexplorer.NewFromConfig(m.limiters.Apply(awsConfig, "ce")), m.pool)
+
+		// Get cost data
+		summary, err := costClient.GetSummary(ctx)
+		if err != nil {
+			return costDataLoadedMsg{errs: []error{err}, region: region}
+		}
+		m.store(key, summary)
+		return costDataLoadedMsg{
+			summary: &summary,
+			region:  region, // Pass the potentially updated region
+		}
+	})
+}
+
+// loadVPCData is a command that loads the VPCs and their networking
+// resources and returns a message
+func (m Model) loadVPCData() tea.Cmd {
+	return m.fetch("vpc", func(ctx context.Context) tea.Msg {
+		if m.demo {
+			vpcs, errs := vpcpkg.NewClient(demo.NewEC2(), demo.NewCloudWatch(), m.pool).GetVPCs(ctx)
+			return vpcDataLoadedMsg{vpcs: vpcs, errs: errs, region: demo.Region}
+		}
+
+		// Load AWS config
+		awsConfig, region, err := m.loadAWSConfig(ctx)
+		if err != nil {
+			return vpcDataLoadedMsg{errs: []error{err}}
+		}
+
+		// Serve the cached response while it is fresh
+		key := m.cacheKey(ctx, awsConfig, "vpc")
+		var cached []vpcpkg.VPCSummary
+		if cachedAt, ok := m.cached(key, &cached); ok {
+			return vpcDataLoadedMsg{vpcs: cached, region: region, cachedAt: cachedAt}
+		}
+
+		// Create VPC client
+		vpcClient := vpcpkg.NewClient(
+			ec2.NewFromConfig(m.limiters.Apply(awsConfig, "ec2")),
+			cloudwatch.NewFromConfig(m.limiters.Apply(awsConfig, "cloudwatch")),
+			m.pool,
+		)
+
+		// Get VPC data
+		vpcs, errs := vpcClient.GetVPCs(ctx)
+		if len(errs) == 0 {
+			m.store(key, vpcs)
+		}
+		return vpcDataLoadedMsg{
+			vpcs:   vpcs,
+			errs:   errs,
+			region: region, // Pass the potentially updated region
+		}
+	})
 }
 
 // loadECSData is a command that loads ECS data and returns a message
 func (m Model) loadECSData() tea.Cmd {
-	return func() tea.Msg {
-		// Create context
-		ctx := context.Background()
+	return m.fetchProgressively("ecs", func(ctx context.Context, partial func(tea.Msg)) tea.Msg {
+		loaded := func(services []ecspkg.ServiceSummary) {
+			partial(ecsPartialMsg{services: services})
+		}
+
+		if m.demo {
+			services, err := ecspkg.NewClient(demo.NewECS()).StreamServices(ctx, loaded)
+			scheduled, errs := ecspkg.NewScheduleClient(demo.NewEventBridge(), demo.NewCloudWatch(), m.pool).GetScheduledTasks(ctx)
+			if m.containerInsights {
+				errs = append(errs, ecspkg.NewInsightsClient(demo.NewCloudWatch(), m.pool).GetServiceMetrics(ctx, services)...)
+			}
+			return ecsDataLoadedMsg{services: services, scheduled: scheduled, err: err, errs: errs, region: demo.Region}
+		}
 
 		// Load AWS config
-		cfg := config.NewConfig(m.region)
-		awsConfig, err := config.LoadAWSConfig(ctx, cfg)
+		awsConfig, region, err := m.loadAWSConfig(ctx)
 		if err != nil {
 			return ecsDataLoadedMsg{err: err}
 		}
 
+		// Serve the cached response while it is fresh
+		key := m.cacheKey(ctx, awsConfig, m.ecsCacheService())
+		var cached cachedECS
+		if cachedAt, ok := m.cached(key, &cached); ok {
+			return ecsDataLoadedMsg{services: cached.Services, scheduled: cached.Scheduled, region: region, cachedAt: cachedAt}
+		}
+
 		// Create ECS client
-		ecsClient := ecspkg.NewClient(ecs.NewFromConfig(awsConfig))
+		ecsClient := ecspkg.NewClient(ecs.NewFromConfig(m.limiters.Apply(awsConfig, "ecs")))
+
+		// Get service data, showing each cluster's as soon as it is loaded
+		services, err := ecsClient.StreamServices(ctx, loaded)
 
-		// Get service data
-		services, err := ecsClient.GetServices(ctx)
+		if err != nil {
+			return ecsDataLoadedMsg{services: services, err: err, region: region}
+		}
+
+		// Get the EventBridge rules that run tasks in the clusters
+		cloudwatchClient := cloudwatch.NewFromConfig(m.limiters.Apply(awsConfig, "cloudwatch"))
+		scheduleClient := ecspkg.NewScheduleClient(
+			eventbridge.NewFromConfig(m.limiters.Apply(awsConfig, "events")),
+			cloudwatchClient,
+			m.pool,
+		)
+		scheduled, errs := scheduleClient.GetScheduledTasks(ctx)
+
+		// Add the Container Insights metrics, which only clusters with it
+		// enabled publish
+		if m.containerInsights {
+			insightsClient := ecspkg.NewInsightsClient(cloudwatchClient, m.pool)
+			errs = append(errs, insightsClient.GetServiceMetrics(ctx, services)...)
+		}
+
+		if len(errs) == 0 {
+			m.store(key, cachedECS{Services: services, Scheduled: scheduled})
+		}
 		return ecsDataLoadedMsg{
-			services: services,
-			err:      err,
-			region:   cfg.Region, // Pass the potentially updated region
+			services:  services,
+			scheduled: scheduled,
+			errs:      errs,
+			region:    region, // Pass the potentially updated region
 		}
-	}
+	})
 }
 
-// refreshTimer is a command that triggers data refresh every minute
-func refreshTimer() tea.Cmd {
-	return tea.Tick(time.Minute, func(time.Time) tea.Msg {
+// refreshTimer is a command that triggers a data refresh after the given interval
+func refreshTimer(interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(time.Time) tea.Msg {
 		return refreshTimerMsg{}
 	})
 }
 
+// loadECRData is a command that loads ECR repositories and returns a message
+func (m Model) loadECRData() tea.Cmd {
+	return m.fetch("ecr", func(ctx context.Context) tea.Msg {
+		limit := m.limit("ecr")
+
+		if m.demo {
+			repositories, total, errs := ecrpkg.NewClient(demo.NewECR(), m.pool).GetFirstRepositories(ctx, limit)
+			return ecrDataLoadedMsg{repositories: repositories, total: total, errs: errs, region: demo.Region}
+		}
+
+		// Load AWS config
+		awsConfig, region, err := m.loadAWSConfig(ctx)
+		if err != nil {
+			return ecrDataLoadedMsg{errs: []error{err}}
+		}
+
+		// Serve the cached response while it is fresh
+		key := m.cacheKey(ctx, awsConfig, limitedCacheService("ecr", limit))
+		var cached cachedPage[ecrpkg.RepositorySummary]
+		if cachedAt, ok := m.cached(key, &cached); ok {
+			return ecrDataLoadedMsg{repositories: cached.Items, total: cached.Total, region: region, cachedAt: cachedAt}
+		}
+
+		// Create ECR client
+		ecrClient := ecrpkg.NewClient(ecr.NewFromConfig(m.limiters.Apply(awsConfig, "ecr")), m.pool)
+
+		// Get repositories
+		repositories, total, errs := ecrClient.GetFirstRepositories(ctx, limit)
+		if len(errs) == 0 {
+			m.store(key, cachedPage[ecrpkg.RepositorySummary]{Items: repositories, Total: total})
+		}
+		return ecrDataLoadedMsg{
+			repositories: repositories,
+			total:        total,
+			errs:         errs,
+			region:       region, // Pass the potentially updated region
+		}
+	})
+}
+
 // loadSQSData is a command that loads SQS data and returns a message
 func (m Model) loadSQSData() tea.Cmd {
-	return func() tea.Msg {
-		// Create context
-		ctx := context.Background()
+	return m.fetch("sqs", func(ctx context.Context) tea.Msg {
+		if m.demo {
+			queues, errs := sqspkg.NewClient(demo.NewSQS(), demo.NewCloudWatch(), m.queuePrefix, m.pool, m.window).GetQueues(ctx)
+			return sqsDataLoadedMsg{queues: queues, errs: errs, region: demo.Region}
+		}
 
 		// Load AWS config
-		cfg := config.NewConfig(m.region)
-		awsConfig, err := config.LoadAWSConfig(ctx, cfg)
+		awsConfig, region, err := m.loadAWSConfig(ctx)
 		if err != nil {
-			return sqsDataLoadedMsg{err: err}
+			return sqsDataLoadedMsg{errs: []error{err}}
+		}
+
+		// Serve the cached response while it is fresh
+		key := m.cacheKey(ctx, awsConfig, m.sqsCacheService())
+		var cached []sqspkg.QueueSummary
+		if cachedAt, ok := m.cached(key, &cached); ok {
+			return sqsDataLoadedMsg{queues: cached, region: region, cachedAt: cachedAt}
 		}
 
 		// Create SQS client
 		sqsClient := sqspkg.NewClient(
-			sqs.NewFromConfig(awsConfig),
-			cloudwatch.NewFromConfig(awsConfig),
+			sqs.NewFromConfig(m.limiters.Apply(awsConfig, "sqs")),
+			cloudwatch.NewFromConfig(m.limiters.Apply(awsConfig, "cloudwatch")),
+			m.queuePrefix,
+			m.pool,
+			m.window,
 		)
 
 		// Get queues data
-		queues, err := sqsClient.GetQueues(ctx)
+		queues, errs := sqsClient.GetQueues(ctx)
+		if len(errs) == 0 {
+			m.store(key, queues)
+		}
 		return sqsDataLoadedMsg{
 			queues: queues,
-			err:    err,
-			region: cfg.Region, // Pass the potentially updated region
+			errs:   errs,
+			region: region, // Pass the potentially updated region
 		}
-	}
+	})
 }
 
-// refreshData triggers a refresh of all enabled data sources
-func (m Model) refreshData() tea.Cmd {
-	var cmds []tea.Cmd
+// loadSSMData is a command that loads SSM data and returns a message
+func (m Model) loadSSMData() tea.Cmd {
+	return m.fetch("ssm", func(ctx context.Context) tea.Msg {
+		if m.demo {
+			instances, errs := ssmpkg.NewClient(demo.NewSSM(), demo.NewEC2()).GetInstances(ctx)
+			return ssmDataLoadedMsg{instances: instances, errs: errs, region: demo.Region}
+		}
 
-	if m.showALB {
-		cmds = append(cmds, m.loadALBData())
-	}
+		// Load AWS config
+		awsConfig, region, err := m.loadAWSConfig(ctx)
+		if err != nil {
+			return ssmDataLoadedMsg{errs: []error{err}}
+		}
 
-	if m.showRDS {
-		cmds = append(cmds, m.loadRDSData())
-	}
+		// Serve the cached response while it is fresh
+		key := m.cacheKey(ctx, awsConfig, "ssm")
+		var cached []ssmpkg.InstanceSummary
+		if cachedAt, ok := m.cached(key, &cached); ok {
+			return ssmDataLoadedMsg{instances: cached, region: region, cachedAt: cachedAt}
+		}
 
-	if m.showEC2 {
-		cmds = append(cmds, m.loadEC2Data())
-	}
+		// Create SSM client
+		ssmClient := ssmpkg.NewClient(
+			ssm.NewFromConfig(m.limiters.Apply(awsConfig, "ssm")),
+			ec2.NewFromConfig(m.limiters.Apply(awsConfig, "ec2")),
+		)
 
-	if m.showECS {
-		cmds = append(cmds, m.loadECSData())
-	}
+		// Get managed instance data
+		instances, errs := ssmClient.GetInstances(ctx)
+		if len(errs) == 0 {
+			m.store(key, instances)
+		}
+		return ssmDataLoadedMsg{
+			instances: instances,
+			errs:      errs,
+			region:    region, // Pass the potentially updated region
+		}
+	})
+}
 
-	if m.showSQS {
-		cmds = append(cmds, m.loadSQSData())
-	}
+// loadDNSData is a command that loads DNS records and returns a message
+func (m Model) loadDNSData() tea.Cmd {
+	return m.fetch("dns", func(ctx context.Context) tea.Msg {
+		if m.demo {
+			records, errs := dnspkg.NewClient(demo.NewRoute53(), demo.NewCloudFront(), demo.NewELBv2(), demo.NewELB(), demo.NewEC2(), demo.Region).GetRecords(ctx)
+			return dnsDataLoadedMsg{records: records, errs: errs, region: demo.Region}
+		}
+
+		// Load AWS config
+		awsConfig, region, err := m.loadAWSConfig(ctx)
+		if err != nil {
+			return dnsDataLoadedMsg{errs: []error{err}}
+		}
+
+		// Serve the cached response while it is fresh
+		key := m.cacheKey(ctx, awsConfig, "dns")
+		var cached []dnspkg.RecordSummary
+		if cachedAt, ok := m.cached(key, &cached); ok {
+			return dnsDataLoadedMsg{records: cached, region: region, cachedAt: cachedAt}
+		}
+
+		// Create DNS client
+		dnsClient := dnspkg.NewClient(
+			route53.NewFromConfig(m.limiters.Apply(awsConfig, "route53")),
+			cloudfront.NewFromConfig(m.limiters.Apply(awsConfig, "cloudfront")),
+			elasticloadbalancingv2.NewFromConfig(m.limiters.Apply(awsConfig, "elasticloadbalancing")),
+			elasticloadbalancing.NewFromConfig(m.limiters.Apply(awsConfig, "elasticloadbalancing")),
+			ec2.NewFromConfig(m.limiters.Apply(awsConfig, "ec2")),
+			awsConfig.Region,
+		)
+
+		// Get DNS records and check their targets
+		records, errs := dnsClient.GetRecords(ctx)
+		if len(errs) == 0 {
+			m.store(key, records)
+		}
+		return dnsDataLoadedMsg{
+			records: records,
+			errs:    errs,
+			region:  region, // Pass the potentially updated region
+		}
+	})
+}
+
+// loadDRData is a command that loads the replication status of resources and returns a message
+func (m Model) loadDRData() tea.Cmd {
+	return m.fetch("dr", func(ctx context.Context) tea.Msg {
+		if m.demo {
+			resources, errs := drpkg.NewClient(demo.NewRDS(), demo.NewS3(), demo.NewECR(), demo.NewDynamoDB(), demo.Region, m.pool).GetResources(ctx)
+			return drDataLoadedMsg{resources: resources, errs: errs, region: demo.Region}
+		}
+
+		// Load AWS config
+		awsConfig, region, err := m.loadAWSConfig(ctx)
+		if err != nil {
+			return drDataLoadedMsg{errs: []error{err}}
+		}
+
+		// Serve the cached response while it is fresh
+		key := m.cacheKey(ctx, awsConfig, "dr")
+		var cached []drpkg.ResourceSummary
+		if cachedAt, ok := m.cached(key, &cached); ok {
+			return drDataLoadedMsg{resources: cached, region: region, cachedAt: cachedAt}
+		}
+
+		// Create DR client
+		drClient := drpkg.NewClient(
+			rdssvc.NewFromConfig(m.limiters.Apply(awsConfig, "rds")),
+			s3.NewFromConfig(m.limiters.Apply(awsConfig, "s3")),
+			ecr.NewFromConfig(m.limiters.Apply(awsConfig, "ecr")),
+			dynamodb.NewFromConfig(m.limiters.Apply(awsConfig, "dynamodb")),
+			awsConfig.Region,
+			m.pool,
+		)
 
-	return tea.Batch(cmds...)
+		// Get the replication status of resources
+		resources, errs := drClient.GetResources(ctx)
+		if len(errs) == 0 {
+			m.store(key, resources)
+		}
+		return drDataLoadedMsg{
+			resources: resources,
+			errs:      errs,
+			region:    region, // Pass the potentially updated region
+		}
+	})
+}
+
+// loadSNSData is a command that loads SNS topics with their SQS subscriptions and returns a message
+func (m Model) loadSNSData() tea.Cmd {
+	return m.fetch("sns", func(ctx context.Context) tea.Msg {
+		if m.demo {
+			topics, errs := snspkg.NewClient(demo.NewSNS(), m.pool).GetTopics(ctx)
+			return snsDataLoadedMsg{topics: topics, errs: errs, region: demo.Region}
+		}
+
+		// Load AWS config
+		awsConfig, region, err := m.loadAWSConfig(ctx)
+		if err != nil {
+			return snsDataLoadedMsg{errs: []error{err}}
+		}
+
+		// Serve the cached response while it is fresh
+		key := m.cacheKey(ctx, awsConfig, "sns")
+		var cached []snspkg.TopicSummary
+		if cachedAt, ok := m.cached(key, &cached); ok {
+			return snsDataLoadedMsg{topics: cached, region: region, cachedAt: cachedAt}
+		}
+
+		// Create SNS client
+		snsClient := snspkg.NewClient(sns.NewFromConfig(m.limiters.Apply(awsConfig, "sns")), m.pool)
+
+		// Get topics and their subscriptions
+		topics, errs := snsClient.GetTopics(ctx)
+		if len(errs) == 0 {
+			m.store(key, topics)
+		}
+		return snsDataLoadedMsg{
+			topics: topics,
+			errs:   errs,
+			region: region, // Pass the potentially updated region
+		}
+	})
+}
+
+// loadLambdaData is a command that loads Lambda functions and returns a message
+func (m Model) loadLambdaData() tea.Cmd {
+	return m.fetch("lambda", func(ctx context.Context) tea.Msg {
+		if m.demo {
+			functions, err := lambdapkg.NewClient(demo.NewLambda(), m.pool).GetFunctions(ctx)
+			return lambdaDataLoadedMsg{functions: functions, err: err, region: demo.Region}
+		}
+
+		// Load AWS config
+		awsConfig, region, err := m.loadAWSConfig(ctx)
+		if err != nil {
+			return lambdaDataLoadedMsg{err: err}
+		}
+
+		// Serve the cached response while it is fresh
+		key := m.cacheKey(ctx, awsConfig, "lambda")
+		var cached []lambdapkg.FunctionSummary
+		if cachedAt, ok := m.cached(key, &cached); ok {
+			return lambdaDataLoadedMsg{functions: cached, region: region, cachedAt: cachedAt}
+		}
+
+		// Create Lambda client
+		lambdaClient := lambdapkg.NewClient(lambda.NewFromConfig(m.limiters.Apply(awsConfig, "lambda")), m.pool)
+
+		// Get functions
+		functions, err := lambdaClient.GetFunctions(ctx)
+		if err == nil {
+			m.store(key, functions)
+		}
+		return lambdaDataLoadedMsg{
+			functions: functions,
+			err:       err,
+			region:    region, // Pass the potentially updated region
+		}
+	})
+}
+
+// loadCloudFrontData is a command that loads CloudFront distributions and returns a message
+func (m Model) loadCloudFrontData() tea.Cmd {
+	return m.fetch("cloudfront", func(ctx context.Context) tea.Msg {
+		if m.demo {
+			distributions, err := cloudfrontpkg.NewClient(demo.NewCloudFront(), m.pool).GetDistributions(ctx)
+			return cloudfrontDataLoadedMsg{distributions: distributions, err: err, region: demo.Region}
+		}
+
+		// Load AWS config
+		awsConfig, region, err := m.loadAWSConfig(ctx)
+		if err != nil {
+			return cloudfrontDataLoadedMsg{err: err}
+		}
+
+		// Serve the cached response while it is fresh
+		key := m.cacheKey(ctx, awsConfig, "cloudfront")
+		var cached []cloudfrontpkg.DistributionSummary
+		if cachedAt, ok := m.cached(key, &cached); ok {
+			return cloudfrontDataLoadedMsg{distributions: cached, region: region, cachedAt: cachedAt}
+		}
+
+		// Create CloudFront client
+		cloudfrontClient := cloudfrontpkg.NewClient(cloudfront.NewFromConfig(m.limiters.Apply(awsConfig, "cloudfront")), m.pool)
+
+		// Get distributions
+		distributions, err := cloudfrontClient.GetDistributions(ctx)
+		if err == nil {
+			m.store(key, distributions)
+		}
+		return cloudfrontDataLoadedMsg{
+			distributions: distributions,
+			err:           err,
+			region:        region, // Pass the potentially updated region
+		}
+	})
+}
+
+// loadLagData is a command that loads the consumer lag indicators and returns a message
+func (m Model) loadLagData() tea.Cmd {
+	return m.fetch("lag", func(ctx context.Context) tea.Msg {
+		if m.demo {
+			indicators, errs := lag.NewClient(demo.NewCloudWatch(), demo.NewLambda(), m.pool).GetIndicators(ctx)
+			return lagDataLoadedMsg{indicators: indicators, errs: errs, region: demo.Region}
+		}
+
+		// Load AWS config
+		awsConfig, region, err := m.loadAWSConfig(ctx)
+		if err != nil {
+			return lagDataLoadedMsg{errs: []error{err}}
+		}
+
+		// Serve the cached response while it is fresh
+		key := m.cacheKey(ctx, awsConfig, "lag")
+		var cached lag.Indicators
+		if cachedAt, ok := m.cached(key, &cached); ok {
+			return lagDataLoadedMsg{indicators: cached, region: region, cachedAt: cachedAt}
+		}
+
+		// Create lag client
+		lagClient := lag.NewClient(
+			cloudwatch.NewFromConfig(m.limiters.Apply(awsConfig, "cloudwatch")),
+			lambda.NewFromConfig(m.limiters.Apply(awsConfig, "lambda")),
+			m.pool,
+		)
+
+		// Get indicators
+		indicators, errs := lagClient.GetIndicators(ctx)
+		if len(errs) == 0 {
+			m.store(key, indicators)
+		}
+		return lagDataLoadedMsg{
+			indicators: indicators,
+			errs:       errs,
+			region:     region, // Pass the potentially updated region
+		}
+	})
+}
+
+// refreshData triggers a refresh of all enabled data sources
+func (m Model) refreshData() tea.Cmd {
+	var tabs []tab
+	for _, t := range m.tabs {
+		if t.load != nil {
+			tabs = append(tabs, t)
+		}
+	}
+	return m.loadTraced(tabs)
 }
@@ -0,0 +1,165 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/charmbracelet/bubbletea"
+
+	"github.com/correctedcloud/aws-overview/internal/audit"
+)
+
+// errDemoSession is shown when a session is started in demo mode, whose
+// resources do not exist
+var errDemoSession = errors.New("sessions need real resources, they are not available in demo mode")
+
+// shellSession is an interactive AWS CLI session that takes over the terminal,
+// such as a Session Manager shell on an instance
+type shellSession struct {
+	service string      // Tab that started the session, e.g. "ec2"
+	entry   audit.Entry // Recorded in the audit log when the session ends
+	name    string      // Shown when the session ends, e.g. "web-1 (i-0a1b2c3d)"
+	args    []string    // Arguments of the AWS CLI, without the region
+	plugins []string    // Programs the CLI runs besides itself
+	cmd     *exec.Cmd   // Set once the session is ready
+}
+
+// sessionReadyMsg is sent when the command of a session is ready to take over
+// the terminal
+type sessionReadyMsg struct {
+	shell shellSession
+	err   error
+}
+
+// sessionEndedMsg is sent when a session ends
+type sessionEndedMsg struct {
+	shell shellSession
+	err   error
+}
+
+// startSession checks that a Session Manager session can be started on the
+// selected instance and prepares its command
+func (m Model) startSession() (Model, tea.Cmd) {
+	instance, ok := m.selectedEC2Instance()
+	if !ok || m.ec2Acting || m.ec2Connecting {
+		return m, nil
+	}
+	m.ec2ActionNote = ""
+	m.ec2ActionErr = nil
+	switch {
+	case !m.allowActions:
+		m.ec2ActionErr = errActionsDisabled
+	case m.demo:
+		m.ec2ActionErr = errDemoSession
+	case instance.State != "running":
+		m.ec2ActionErr = fmt.Errorf("sessions need a running instance, %s is %s", ec2InstanceName(instance), instance.State)
+	default:
+		m.ec2Connecting = true
+	}
+	m.updateViewportContent()
+	m.viewport.GotoTop()
+	if !m.ec2Connecting {
+		return m, nil
+	}
+	return m, m.prepareSession(shellSession{
+		service: "ec2",
+		entry:   audit.Entry{Action: "ssm:StartSession", Resource: instance.InstanceID, Detail: instance.Name},
+		name:    ec2InstanceName(instance),
+		args:    []string{"ssm", "start-session", "--target", instance.InstanceID},
+		plugins: []string{"session-manager-plugin"},
+	})
+}
+
+// prepareSession is a command that builds the AWS CLI command of a session.
+// The CLI is given the credentials and region the overview resolved, so the
+// session runs as the same identity even when it came from flags the CLI
+// does not know about.
+func (m Model) prepareSession(s shellSession) tea.Cmd {
+	return func() tea.Msg {
+		for _, program := range append([]string{"aws"}, s.plugins...) {
+			if _, err := exec.LookPath(program); err != nil {
+				return sessionReadyMsg{shell: s, err: fmt.Errorf("sessions need the AWS CLI and the Session Manager plugin, %s was not found", program)}
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(m.ctx, m.timeout)
+		defer cancel()
+
+		awsConfig, region, err := m.loadAWSConfig(ctx)
+		if err != nil {
+			return sessionReadyMsg{shell: s, err: err}
+		}
+		credentials, err := awsConfig.Credentials.Retrieve(ctx)
+		if err != nil {
+			return sessionReadyMsg{shell: s, err: fmt.Errorf("failed to retrieve credentials: %w", err)}
+		}
+
+		s.cmd = exec.Command("aws", append(s.args, "--region", region)...)
+		s.cmd.Env = sessionEnv(os.Environ(),
+			"AWS_ACCESS_KEY_ID="+credentials.AccessKeyID,
+			"AWS_SECRET_ACCESS_KEY="+credentials.SecretAccessKey,
+			"AWS_SESSION_TOKEN="+credentials.SessionToken,
+		)
+		return sessionReadyMsg{shell: s}
+	}
+}
+
+// sessionEnv returns env with the variables choosing the credentials of the
+// AWS CLI replaced by vars
+func sessionEnv(env []string, vars ...string) []string {
+	var result []string
+	for _, entry := range env {
+		name, _, _ := strings.Cut(entry, "=")
+		switch name {
+		case "AWS_PROFILE", "AWS_DEFAULT_PROFILE", "AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN":
+			continue
+		}
+		result = append(result, entry)
+	}
+	return append(result, vars...)
+}
+
+// updateSessionReady hands the terminal over to the session, suspending the
+// UI until it ends
+func (m Model) updateSessionReady(msg sessionReadyMsg) (Model, tea.Cmd) {
+	if msg.err != nil {
+		m.endSession(msg.shell.service, "", msg.err)
+		return m, nil
+	}
+
+	s := msg.shell
+	return m, tea.ExecProcess(s.cmd, func(err error) tea.Msg {
+		return sessionEndedMsg{shell: s, err: err}
+	})
+}
+
+// updateSessionEnded records the end of a session once the UI is back
+func (m Model) updateSessionEnded(msg sessionEndedMsg) (Model, tea.Cmd) {
+	s := msg.shell
+	m.audit(s.service, s.entry, msg.err)
+	if msg.err != nil {
+		m.endSession(s.service, "", fmt.Errorf("session on %s failed: %w", s.name, msg.err))
+	} else {
+		m.endSession(s.service, "Session on "+s.name+" ended", nil)
+	}
+	return m, nil
+}
+
+// endSession shows the outcome of a session on the tab that started it
+func (m *Model) endSession(service, note string, err error) {
+	switch service {
+	case "ec2":
+		m.ec2Connecting = false
+		m.ec2ActionNote = note
+		m.ec2ActionErr = err
+	case "ecs":
+		m.ecsExecuting = false
+		m.ecsExecNote = note
+		m.ecsExecErr = err
+	}
+	m.updateViewportContent()
+}
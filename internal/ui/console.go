@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbletea"
+
+	"github.com/correctedcloud/aws-overview/pkg/console"
+)
+
+// openLink opens a link in the default browser
+var openLink = console.Open
+
+// openConsole opens the page of the resource selected on the active tab in
+// the AWS console and confirms it with a toast
+func (m Model) openConsole() (Model, tea.Cmd) {
+	consoleLink := m.currentTab().console
+	if consoleLink == nil {
+		return m, nil
+	}
+	if m.region == "" {
+		return m.showToast("The region is unknown until the first load completes", errorColor)
+	}
+	link, ok := consoleLink(m)
+	if !ok {
+		return m, nil
+	}
+	if err := openLink(link); err != nil {
+		return m.showToast(err.Error()+", the link is "+link, errorColor)
+	}
+	return m.showToast("Opened "+link, successColor)
+}
+
+// albConsole links to the load balancer of the selected target
+func (m Model) albConsole() (string, bool) {
+	target, _, ok := m.selectedTarget()
+	if !ok {
+		return "", false
+	}
+	for _, lb := range m.loadBalancers {
+		if lb.Name == target.LoadBalancer {
+			return console.LoadBalancer(m.region, lb.ARN), true
+		}
+	}
+	return "", false
+}
+
+// rdsConsole links to the selected DB instance
+func (m Model) rdsConsole() (string, bool) {
+	instance, ok := m.selectedDBInstance()
+	return console.DBInstance(m.region, instance.Identifier), ok
+}
+
+// ec2Console links to the selected instance
+func (m Model) ec2Console() (string, bool) {
+	instance, ok := m.selectedEC2Instance()
+	return console.EC2Instance(m.region, instance.InstanceID), ok
+}
+
+// ecsConsole links to the selected service
+func (m Model) ecsConsole() (string, bool) {
+	service, ok := m.selectedService()
+	return console.ECSService(m.region, service.ClusterName, service.ServiceName), ok
+}
+
+// sqsConsole links to the selected queue
+func (m Model) sqsConsole() (string, bool) {
+	queue, ok := m.selectedQueue()
+	if ok && queue.URL == "" {
+		return "", false
+	}
+	return console.Queue(m.region, queue.URL), ok
+}
+
+// lambdaConsole links to the selected function
+func (m Model) lambdaConsole() (string, bool) {
+	function, ok := m.selectedFunction()
+	return console.Function(m.region, function.Name), ok
+}
+
+// cloudfrontConsole links to the selected distribution
+func (m Model) cloudfrontConsole() (string, bool) {
+	distribution, ok := m.selectedDistribution()
+	return console.Distribution(distribution.ID), ok
+}
@@ -0,0 +1,221 @@
+package ui
+
+import (
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/correctedcloud/aws-overview/internal/config"
+	"github.com/correctedcloud/aws-overview/internal/permissions"
+)
+
+// credentialsWarnBefore is how close to their expiry temporary credentials
+// are shown as expiring
+const credentialsWarnBefore = 10 * time.Minute
+
+// credentialsLoadedMsg carries when the credentials expire, the zero time
+// for credentials that do not
+type credentialsLoadedMsg struct {
+	expires time.Time
+	err     error
+}
+
+// reauthEndedMsg is sent when aws sso login returns the terminal
+type reauthEndedMsg struct {
+	err error
+}
+
+// ssoAuthorizationMsg carries the sign-in started with AWS SSO, which the
+// user approves in a browser
+type ssoAuthorizationMsg struct {
+	authorization *config.DeviceAuthorization
+	err           error
+}
+
+// ssoLoginEndedMsg is sent when the sign-in was approved, or failed
+type ssoLoginEndedMsg struct {
+	err error
+}
+
+// loadCredentials is a command that retrieves the credentials to learn when
+// they expire, and returns a message. Temporary credentials that can be
+// renewed, such as those of an assumed role, are renewed on the way.
+func (m Model) loadCredentials() tea.Cmd {
+	if m.demo {
+		return nil
+	}
+	return func() tea.Msg {
+		awsConfig, _, err := m.loadAWSConfig(m.ctx)
+		if err != nil || awsConfig.Credentials == nil {
+			return credentialsLoadedMsg{err: err}
+		}
+		credentials, err := awsConfig.Credentials.Retrieve(m.ctx)
+		if err != nil {
+			return credentialsLoadedMsg{err: err}
+		}
+		if !credentials.CanExpire {
+			return credentialsLoadedMsg{}
+		}
+		return credentialsLoadedMsg{expires: credentials.Expires}
+	}
+}
+
+// credentialsExpired reports whether the credentials expired since they
+// were last renewed, so that loads fail until they are renewed again
+func (m Model) credentialsExpired() bool {
+	return permissions.IsExpired(m.credentialsErr) || m.diagnostics.ExpiredAt().After(m.credentialsRenewedAt)
+}
+
+// retryCredentials lets the next loads try the credentials again once they
+// expired, loading the configuration anew to pick up credentials renewed
+// outside the overview, e.g. by aws sso login in another terminal
+func (m *Model) retryCredentials() {
+	if !m.credentialsExpired() {
+		return
+	}
+	m.awsConfig.reset()
+	m.credentialsErr = nil
+	m.credentialsRenewedAt = time.Now()
+	m.updateViewportContent()
+}
+
+// reauthenticate signs in again with AWS SSO when the profile in use signs
+// in with it, or hands the terminal to aws sso login when the profile cannot
+// be read
+func (m Model) reauthenticate() (Model, tea.Cmd) {
+	if m.demo || !m.credentialsExpired() || m.ssoSigningIn {
+		return m, nil
+	}
+	profile, ok, err := config.LoadSSOProfile(m.ctx, getAWSProfile())
+	switch {
+	case err == nil && ok:
+		return m.startSSOLogin(profile)
+	case err == nil:
+		return m.showToast("The profile does not sign in with AWS SSO, renew its credentials and press R", errorColor)
+	}
+	if _, err := exec.LookPath("aws"); err != nil {
+		return m.showToast("Renewing the credentials needs the AWS CLI, aws was not found", errorColor)
+	}
+	return m, tea.ExecProcess(exec.Command("aws", reauthArgs()...), func(err error) tea.Msg {
+		return reauthEndedMsg{err: err}
+	})
+}
+
+// reauthArgs returns the arguments of the AWS CLI renewing the credentials
+// of the profile in use
+func reauthArgs() []string {
+	args := []string{"sso", "login"}
+	if profile := getAWSProfile(); profile != "" {
+		args = append(args, "--profile", profile)
+	}
+	return args
+}
+
+// updateReauthEnded reloads all tabs with the renewed credentials once aws
+// sso login succeeded
+func (m Model) updateReauthEnded(msg reauthEndedMsg) (Model, tea.Cmd) {
+	if msg.err != nil {
+		return m.showToast(fmt.Sprintf("aws sso login failed: %v", msg.err), errorColor)
+	}
+	return m.credentialsRenewed()
+}
+
+// startSSOLogin starts the device authorization of the profile: the user
+// approves the sign-in in a browser while the overview waits for it
+func (m Model) startSSOLogin(profile config.SSOProfile) (Model, tea.Cmd) {
+	m.ssoSigningIn = true
+	m.ssoErr = nil
+	m.updateViewportContent()
+	return m, func() tea.Msg {
+		authorization, err := config.StartDeviceAuthorization(m.ctx, profile)
+		return ssoAuthorizationMsg{authorization: authorization, err: err}
+	}
+}
+
+// updateSSOAuthorization opens the page approving the sign-in and waits for
+// the approval
+func (m Model) updateSSOAuthorization(msg ssoAuthorizationMsg) (Model, tea.Cmd) {
+	if msg.err != nil {
+		m.ssoSigningIn = false
+		m.ssoErr = msg.err
+		m.updateViewportContent()
+		return m, nil
+	}
+
+	m.ssoAuthorization = msg.authorization
+	// The page is listed in the prompt when no browser opens
+	_ = openLink(msg.authorization.VerificationURI)
+	m.updateViewportContent()
+	return m, func() tea.Msg {
+		return ssoLoginEndedMsg{err: msg.authorization.Wait(m.ctx)}
+	}
+}
+
+// updateSSOLoginEnded reloads all tabs once the sign-in was approved
+func (m Model) updateSSOLoginEnded(msg ssoLoginEndedMsg) (Model, tea.Cmd) {
+	m.ssoSigningIn = false
+	m.ssoAuthorization = nil
+	if msg.err != nil {
+		m.ssoErr = msg.err
+		m.updateViewportContent()
+		return m, nil
+	}
+	return m.credentialsRenewed()
+}
+
+// credentialsRenewed reloads all tabs with the renewed credentials
+func (m Model) credentialsRenewed() (Model, tea.Cmd) {
+	m.retryCredentials()
+	m, toast := m.showToast("Credentials renewed, reloading", successColor)
+	return m, tea.Batch(toast, m.loadCredentials(), m.fresh().refreshData())
+}
+
+// renderCredentialsExpiry shows how long temporary credentials remain
+// valid, next to the tabs
+func (m Model) renderCredentialsExpiry() string {
+	if m.credentialsExpired() {
+		return lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render("🔑 credentials expired")
+	}
+	if m.credentialsExpire.IsZero() {
+		return ""
+	}
+
+	left := max(time.Until(m.credentialsExpire), 0).Truncate(time.Second)
+	color := dimTextColor
+	if left < credentialsWarnBefore {
+		color = warningColor
+	}
+	return lipgloss.NewStyle().Foreground(color).Render("🔑 credentials expire in " + left.String())
+}
+
+// renderReauthPrompt takes the place of the active tab once the credentials
+// expired, rather than every tab listing the calls that failed
+func (m Model) renderReauthPrompt() string {
+	titleStyle := lipgloss.NewStyle().Foreground(errorColor).Bold(true)
+	keyStyle := lipgloss.NewStyle().Foreground(secondaryColor).Bold(true)
+	textStyle := lipgloss.NewStyle().Foreground(textColor)
+
+	content := titleStyle.Render("🔑 AWS credentials have expired") + "\n\n" +
+		textStyle.Render("The tabs keep the data loaded before, but loading more fails until the credentials are renewed.") + "\n\n"
+
+	switch {
+	case m.ssoAuthorization != nil:
+		return content +
+			textStyle.Render("Approve the sign-in in the browser that opened, or open") + "\n\n" +
+			"  " + keyStyle.Render(m.ssoAuthorization.VerificationURI) + "\n\n" +
+			textStyle.Render("and check that it shows the code ") + keyStyle.Render(m.ssoAuthorization.UserCode) + "\n\n" +
+			m.spinner.View() + textStyle.Render(" Waiting for the approval until "+m.ssoAuthorization.Expires.Format("15:04:05")+"...")
+	case m.ssoSigningIn:
+		return content + m.spinner.View() + textStyle.Render(" Starting the sign-in with AWS SSO...")
+	}
+
+	if m.ssoErr != nil {
+		content += lipgloss.NewStyle().Foreground(errorColor).Render("❌ "+m.ssoErr.Error()) + "\n\n"
+	}
+	return content +
+		"  " + keyStyle.Render("A") + textStyle.Render("  Sign in again with AWS SSO in the browser, then reload all tabs") + "\n" +
+		"  " + keyStyle.Render("R") + textStyle.Render("  Reload all tabs after renewing the credentials another way, e.g. in another terminal")
+}
@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+
+	"github.com/correctedcloud/aws-overview/internal/diagnostics"
+)
+
+// diagnosticsInterval is how often the Diagnostics tab updates while shown
+const diagnosticsInterval = time.Second
+
+// diagnosticsTickMsg is sent when it's time to update the Diagnostics tab.
+// Ticks of a tab that was hidden since carry an outdated generation.
+type diagnosticsTickMsg struct {
+	generation int
+}
+
+// diagnosticsTab shows the runtime statistics of the tool. It is hidden
+// until D is pressed, and not saved with the session.
+var diagnosticsTab = tab{
+	name:   "Diagnostics",
+	render: Model.renderDiagnostics,
+	help:   func(Model) string { return "D Hide" },
+	keymap: []binding{{keys: "D", description: "Hide the Diagnostics tab"}},
+}
+
+// showingDiagnostics reports whether the Diagnostics tab is shown, always as the last tab
+func (m Model) showingDiagnostics() bool {
+	return m.tabs[len(m.tabs)-1].name == diagnosticsTab.name
+}
+
+// toggleDiagnostics shows the Diagnostics tab and switches to it, or hides it
+func (m Model) toggleDiagnostics() (Model, tea.Cmd) {
+	m.diagnosticsGeneration++
+	if m.showingDiagnostics() {
+		m.tabs = m.tabs[:len(m.tabs)-1]
+		m.activeTab = min(m.activeTab, len(m.tabs)-1)
+		// The split ends with its pinned tab
+		if m.split && m.splitTab >= len(m.tabs) {
+			m.split = false
+			m.resize()
+		}
+		m.updateViewportContent()
+		return m, nil
+	}
+
+	// Copy the tabs, they are shared with earlier copies of the model
+	m.tabs = append(m.tabs[:len(m.tabs):len(m.tabs)], diagnosticsTab)
+	m.activeTab = len(m.tabs) - 1
+	m.updateViewportContent()
+	return m, tickDiagnostics(m.diagnosticsGeneration)
+}
+
+// tickDiagnostics is a command that updates the Diagnostics tab after the interval
+func tickDiagnostics(generation int) tea.Cmd {
+	return tea.Tick(diagnosticsInterval, func(time.Time) tea.Msg {
+		return diagnosticsTickMsg{generation: generation}
+	})
+}
+
+// updateDiagnostics redraws the Diagnostics tab while it is active, and keeps
+// ticking until it is hidden
+func (m Model) updateDiagnostics(msg diagnosticsTickMsg) (Model, tea.Cmd) {
+	if msg.generation != m.diagnosticsGeneration || !m.showingDiagnostics() {
+		return m, nil
+	}
+	if m.activeTab == len(m.tabs)-1 {
+		m.updateViewportContent()
+	}
+	return m, tickDiagnostics(msg.generation)
+}
+
+// renderDiagnostics shows the goroutines, heap, refresh durations and API
+// error rates of the running overview
+func (m Model) renderDiagnostics() string {
+	return diagnostics.Format(m.diagnostics.Snapshot())
+}
@@ -0,0 +1,24 @@
+// Package ui implements the AWS overview terminal UI as a bubbletea component.
+//
+// The component is created with New and can run on its own:
+//
+//	p := tea.NewProgram(ui.New(ui.Options{ShowEC2: true}), tea.WithAltScreen())
+//
+// or be embedded as a pane of a larger bubbletea program. Embedding hosts must
+// follow this message contract:
+//
+//   - Return the component's Init command from the host's Init (or batch it
+//     with the host's own commands); it starts the spinner, the first load and
+//     the refresh timer.
+//   - Forward every message the host does not handle itself to the
+//     component's Update and keep the returned model. Data loads, spinner
+//     ticks and refresh timers arrive as unexported messages, so they must
+//     not be dropped.
+//   - Send a tea.WindowSizeMsg with the dimensions of the pane (not of the
+//     whole terminal) whenever the pane is resized.
+//   - Send a RefreshMsg to trigger an immediate reload of all services.
+//   - Set Options.Embedded so that q and ctrl+c are left to the host.
+//
+// Code outside this module should import the component through the
+// github.com/correctedcloud/aws-overview/pkg/overview package.
+package ui
@@ -0,0 +1,291 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/correctedcloud/aws-overview/internal/audit"
+	"github.com/correctedcloud/aws-overview/internal/permissions"
+	"github.com/correctedcloud/aws-overview/pkg/common"
+	"github.com/correctedcloud/aws-overview/pkg/demo"
+	ec2pkg "github.com/correctedcloud/aws-overview/pkg/ec2"
+)
+
+// ec2ActionMsg is sent when an action on an instance was requested
+type ec2ActionMsg struct {
+	action   ec2pkg.Action
+	instance ec2pkg.InstanceSummary
+	state    string // State of the instance afterwards, e.g. "stopping"
+	err      error
+}
+
+// updateEC2Keys handles the keys of the EC2 tab: the arrow keys select an
+// instance instead of scrolling, c starts a Session Manager session on it
+// and a opens the menu of the actions that apply to it. In the menu the arrow keys select an action, enter asks to
+// confirm it and esc closes the menu; while confirming, y runs the action
+// and any other key cancels.
+func (m Model) updateEC2Keys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	if m.ec2Confirming != "" {
+		if msg.String() == "ctrl+c" {
+			return m, nil, false
+		}
+		action := m.ec2Confirming
+		m.ec2Confirming = ""
+		if msg.String() != "y" {
+			m.updateViewportContent()
+			return m, nil, true
+		}
+		// The instance confirmed, even if a refresh moved the selection
+		instance, ok := m.ec2MenuTarget()
+		if !ok {
+			m.ec2ActionErr = fmt.Errorf("instance %s is no longer listed, %s not requested", m.ec2MenuInstance, action)
+			m.updateViewportContent()
+			return m, nil, true
+		}
+		if !slices.Contains(ec2pkg.Actions(instance), action) {
+			m.ec2ActionErr = fmt.Errorf("%s does not apply to %s, which is now %s", action, ec2InstanceName(instance), instance.State)
+			m.updateViewportContent()
+			return m, nil, true
+		}
+		m.ec2Acting = true
+		m.ec2ActionNote = ""
+		m.ec2ActionErr = nil
+		m.updateViewportContent()
+		m.viewport.GotoTop()
+		return m, m.runEC2Action(action, instance), true
+	}
+
+	if m.ec2Menu {
+		instance, ok := m.ec2MenuTarget()
+		if !ok && msg.String() != "ctrl+c" {
+			m.ec2Menu = false
+			m.ec2ActionErr = fmt.Errorf("instance %s is no longer listed", m.ec2MenuInstance)
+			m.updateViewportContent()
+			return m, nil, true
+		}
+		actions := ec2pkg.Actions(instance)
+		switch msg.String() {
+		case "up", "k":
+			m.ec2MenuSelected = max(0, m.ec2MenuSelected-1)
+		case "down", "j":
+			m.ec2MenuSelected = max(0, min(len(actions)-1, m.ec2MenuSelected+1))
+		case "enter":
+			m.ec2Menu = false
+			if m.ec2MenuSelected < len(actions) {
+				m.ec2Confirming = actions[m.ec2MenuSelected]
+			}
+		case "esc":
+			m.ec2Menu = false
+		case "ctrl+c":
+			return m, nil, false
+		}
+		m.updateViewportContent()
+		return m, nil, true
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		m.moveEC2Selection(-1)
+		return m, nil, true
+	case "down", "j":
+		m.moveEC2Selection(1)
+		return m, nil, true
+	case "a":
+		instance, ok := m.selectedEC2Instance()
+		if !ok || m.ec2Acting || m.ec2Connecting {
+			return m, nil, true
+		}
+		m.ec2ActionNote = ""
+		m.ec2ActionErr = nil
+		switch {
+		case !m.allowActions:
+			m.ec2ActionErr = errActionsDisabled
+		case len(ec2pkg.Actions(instance)) == 0:
+			m.ec2ActionErr = fmt.Errorf("no actions apply to an instance that is %s", instance.State)
+		default:
+			m.ec2Menu = true
+			m.ec2MenuSelected = 0
+			m.ec2MenuInstance = instance.InstanceID
+		}
+		m.updateViewportContent()
+		m.viewport.GotoTop()
+		return m, nil, true
+	case "c":
+		var cmd tea.Cmd
+		m, cmd = m.startSession()
+		return m, cmd, true
+	case "esc":
+		if m.ec2Acting || m.ec2Connecting {
+			return m, nil, true
+		}
+		m.ec2ActionNote = ""
+		m.ec2ActionErr = nil
+		m.updateViewportContent()
+		return m, nil, true
+	}
+	return m, nil, false
+}
+
+// ec2Help describes the keys of the EC2 tab
+func (m Model) ec2Help() string {
+	switch {
+	case m.ec2Confirming != "":
+		return "y Confirm • any other key Cancel"
+	case m.ec2Menu:
+		return "↑↓ Select Action • enter Choose • esc Close"
+	case !m.allowActions:
+		return "↑↓ Select"
+	}
+	return "↑↓ Select • c Connect • a Actions"
+}
+
+// moveEC2Selection moves the selection by delta instances and scrolls the
+// viewport so the selected instance stays visible
+func (m *Model) moveEC2Selection(delta int) {
+	if len(m.ec2Instances) == 0 {
+		return
+	}
+	m.ec2Selected = max(0, min(m.shown("ec2", len(m.ec2Instances))-1, m.ec2Selected+delta))
+	m.updateViewportContent()
+	m.scrollToSelection(m.renderEC2())
+}
+
+// selectedEC2Instance returns the instance selected on the EC2 tab
+func (m Model) selectedEC2Instance() (ec2pkg.InstanceSummary, bool) {
+	sorted := capRows(m, "ec2", common.SortRows(m.ec2Instances, ec2pkg.Columns, m.sortKeys["ec2"]))
+	if m.ec2Selected < 0 || m.ec2Selected >= len(sorted) {
+		return ec2pkg.InstanceSummary{}, false
+	}
+	return sorted[m.ec2Selected], true
+}
+
+// ec2MenuTarget returns the instance the action menu or confirmation was
+// opened for, which refreshes may have moved or removed
+func (m Model) ec2MenuTarget() (ec2pkg.InstanceSummary, bool) {
+	for _, instance := range m.ec2Instances {
+		if instance.InstanceID == m.ec2MenuInstance {
+			return instance, true
+		}
+	}
+	return ec2pkg.InstanceSummary{}, false
+}
+
+// ec2ActionClient returns an EC2 client for actions, which are not cached
+func (m Model) ec2ActionClient(ctx context.Context) (*ec2pkg.Client, error) {
+	if m.demo {
+		return ec2pkg.NewClient(demo.NewEC2()), nil
+	}
+
+	awsConfig, _, err := m.loadAWSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ec2pkg.NewClient(ec2.NewFromConfig(m.limiters.Apply(awsConfig, "ec2"))), nil
+}
+
+// runEC2Action is a command that runs action on the instance
+func (m Model) runEC2Action(action ec2pkg.Action, instance ec2pkg.InstanceSummary) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(m.ctx, m.timeout)
+		defer cancel()
+
+		client, err := m.ec2ActionClient(ctx)
+		if err != nil {
+			return ec2ActionMsg{action: action, instance: instance, err: err}
+		}
+		state, err := client.RunAction(ctx, action, instance.InstanceID)
+		return ec2ActionMsg{action: action, instance: instance, state: state, err: err}
+	}
+}
+
+// updateEC2Action records the outcome of an action and reloads the instances
+// to show their new state
+func (m Model) updateEC2Action(msg ec2ActionMsg) (Model, tea.Cmd) {
+	m.ec2Acting = false
+	m.ec2ActionErr = msg.err
+	m.audit("ec2", audit.Entry{
+		Action:   "ec2:" + string(msg.action) + "Instances",
+		Resource: msg.instance.InstanceID,
+		Detail:   msg.instance.Name,
+	}, msg.err)
+	if msg.err != nil {
+		m.updateViewportContent()
+		return m, nil
+	}
+
+	m.ec2ActionNote = fmt.Sprintf("%s requested for %s", msg.action, ec2InstanceName(msg.instance))
+	if msg.state != "" {
+		m.ec2ActionNote += ", now " + msg.state
+	}
+	m.updateViewportContent()
+	return m, m.fresh().loadEC2Data()
+}
+
+// ec2InstanceName returns the name and ID of an instance, or its ID when unnamed
+func ec2InstanceName(instance ec2pkg.InstanceSummary) string {
+	if instance.Name == "" {
+		return instance.InstanceID
+	}
+	return fmt.Sprintf("%s (%s)", instance.Name, instance.InstanceID)
+}
+
+// renderEC2Action shows the action menu, the confirmation or the outcome of
+// an action above the instances
+func (m Model) renderEC2Action() string {
+	instance, _ := m.selectedEC2Instance()
+	if m.ec2Menu || m.ec2Confirming != "" {
+		instance, _ = m.ec2MenuTarget()
+	}
+	switch {
+	case m.ec2Menu:
+		content := lipgloss.NewStyle().Bold(true).Render("Actions for "+ec2InstanceName(instance)+":") + "\n"
+		for i, action := range ec2pkg.Actions(instance) {
+			marker := "  "
+			if i == m.ec2MenuSelected {
+				marker = "> "
+			}
+			content += marker + string(action) + "\n"
+		}
+		return content + "\n"
+	case m.ec2Confirming != "":
+		question := fmt.Sprintf("%s instance %s", m.ec2Confirming, m.ec2MenuInstance)
+		if instance.InstanceID != "" {
+			question = fmt.Sprintf("%s instance %s", m.ec2Confirming, ec2InstanceName(instance))
+		}
+		if m.region != "" {
+			question += " in " + m.region
+		}
+		return lipgloss.NewStyle().Foreground(warningColor).Bold(true).Render(question+"?") + "\n" +
+			lipgloss.NewStyle().Foreground(warningColor).Render("Press y to confirm, any other key to cancel") + "\n\n"
+	case m.ec2Acting:
+		return m.spinner.View() + " Requesting the action...\n\n"
+	case m.ec2Connecting:
+		return m.spinner.View() + " Starting a session on " + ec2InstanceName(instance) + "...\n\n"
+	case m.ec2ActionErr != nil:
+		return "Action failed: " + permissions.Describe(m.ec2ActionErr) + "\n" + renderHints([]error{m.ec2ActionErr}) + "\n"
+	case m.ec2ActionNote != "":
+		return lipgloss.NewStyle().Foreground(successColor).Render(m.ec2ActionNote) + "\n\n"
+	}
+	return ""
+}
+
+// markSelectedRow marks the selected row of a table rendered by renderTable
+// with "> ", indenting the other lines to match
+func markSelectedRow(table string, selected int) string {
+	lines := strings.Split(table, "\n")
+	for i, line := range lines {
+		// The header and its border come before the rows
+		if i-2 == selected {
+			lines[i] = "> " + line
+		} else {
+			lines[i] = "  " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
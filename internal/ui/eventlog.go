@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// eventLogHeight is how many events the event log pane shows at once, the
+// latest last
+const eventLogHeight = 6
+
+// maxEventLog is how many events the event log keeps, dropping the oldest
+const maxEventLog = 1000
+
+// eventLogEntry is a state transition noticed between two loads of a service
+type eventLogEntry struct {
+	at      time.Time
+	service string // Name of the tab of the service, e.g. "ECS"
+	text    string // e.g. "service web scaled 3→5"
+}
+
+// String formats the entry as a line of the exported log
+func (e eventLogEntry) String() string {
+	return fmt.Sprintf("%s [%s] %s", e.at.Format("2006-01-02 15:04:05"), e.service, e.text)
+}
+
+// logChanges adds the changes a load of service found against the previous
+// load to the event log. Nothing is logged for the first load of the
+// session, which has nothing to compare with, nor for loads with errors,
+// which may lack resources that still exist. It must be called before the
+// load is marked as loaded.
+func (m *Model) logChanges(service string, failed bool, changes []string) {
+	if _, ok := m.loadedAt[service]; !ok || failed {
+		return
+	}
+	m.recordEvents(service, changes)
+}
+
+// recordEvents adds events of service to the event log
+func (m *Model) recordEvents(service string, events []string) {
+	name := service
+	for _, t := range m.tabs {
+		if t.service == service {
+			name = t.name
+		}
+	}
+	now := time.Now()
+	for _, event := range events {
+		m.eventLog = append(m.eventLog, eventLogEntry{at: now, service: name, text: event})
+	}
+	if len(m.eventLog) > maxEventLog {
+		m.eventLog = m.eventLog[len(m.eventLog)-maxEventLog:]
+	}
+}
+
+// EventLog returns the state transitions noticed since the component
+// started, one per line with their time and service, for exporting
+func (m Model) EventLog() string {
+	var output strings.Builder
+	for _, entry := range m.eventLog {
+		output.WriteString(entry.String() + "\n")
+	}
+	return output.String()
+}
+
+// toggleEventLog shows or hides the event log pane below the active tab
+func (m Model) toggleEventLog() Model {
+	m.showEventLog = !m.showEventLog
+	m.eventLogNote = ""
+	m.resize()
+	m.updateViewportContent()
+	return m
+}
+
+// writeEventLog writes the event log to events-<date>-<time>.txt in the
+// working directory and notes the outcome in the pane
+func (m Model) writeEventLog() Model {
+	path := "events-" + time.Now().Format("20060102-150405") + ".txt"
+	if err := os.WriteFile(path, []byte(m.EventLog()), 0o600); err != nil {
+		m.eventLogNote = "Error writing the event log: " + err.Error()
+		return m
+	}
+	m.eventLogNote = fmt.Sprintf("Wrote %d events to %s", len(m.eventLog), path)
+	return m
+}
+
+// renderEventLog shows the latest events in a pane below the active tab
+func (m Model) renderEventLog() string {
+	title := fmt.Sprintf("Events (%d)", len(m.eventLog))
+	if m.eventLogNote != "" {
+		title += " • " + m.eventLogNote
+	}
+
+	lines := []string{lipgloss.NewStyle().Foreground(accentColor).Bold(true).Render(title)}
+	if len(m.eventLog) == 0 {
+		lines = append(lines, lipgloss.NewStyle().Foreground(dimTextColor).Render("No state changes yet, they are noticed between refreshes"))
+	}
+	for _, entry := range m.eventLog[max(0, len(m.eventLog)-eventLogHeight):] {
+		// Long events are cut rather than wrapped, which would push the
+		// pane's first lines out of view
+		text := common.Truncate(entry.text, max(10, m.width-8-len("15:04:05 ")-common.DisplayWidth(entry.service)-1))
+		lines = append(lines, lipgloss.NewStyle().Foreground(dimTextColor).Render(entry.at.Format("15:04:05"))+" "+
+			lipgloss.NewStyle().Foreground(primaryColor).Render(entry.service)+" "+
+			lipgloss.NewStyle().Foreground(textColor).Render(text))
+	}
+	for len(lines) < eventLogHeight+1 {
+		lines = append(lines, "")
+	}
+
+	return lipgloss.NewStyle().
+		Width(m.width-4).
+		Padding(0, 1).
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(primaryColor).
+		Render(strings.Join(lines, "\n"))
+}
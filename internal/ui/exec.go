@@ -0,0 +1,143 @@
+package ui
+
+import (
+	"context"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/correctedcloud/aws-overview/internal/audit"
+	"github.com/correctedcloud/aws-overview/internal/permissions"
+	ecspkg "github.com/correctedcloud/aws-overview/pkg/ecs"
+)
+
+// execShell is the command ECS Exec runs in the container
+const execShell = "/bin/sh"
+
+// ecsExecTargetsMsg carries the containers of a service ECS Exec can open a
+// shell in
+type ecsExecTargetsMsg struct {
+	service ecspkg.ServiceSummary
+	targets []ecspkg.ExecTarget
+	err     error
+}
+
+// openExec looks up the running containers of the selected service to open
+// a shell in with ECS Exec
+func (m Model) openExec() (Model, tea.Cmd) {
+	service, ok := m.selectedService()
+	if !ok || m.ecsExecuting {
+		return m, nil
+	}
+	m.ecsExecNote = ""
+	m.ecsExecErr = nil
+	switch {
+	case !m.allowActions:
+		m.ecsExecErr = errActionsDisabled
+	case m.demo:
+		m.ecsExecErr = errDemoSession
+	default:
+		m.ecsExecuting = true
+	}
+	m.updateViewportContent()
+	m.viewport.GotoTop()
+	if !m.ecsExecuting {
+		return m, nil
+	}
+	return m, m.loadExecTargets(service)
+}
+
+// loadExecTargets is a command that lists the running containers of the
+// service
+func (m Model) loadExecTargets(service ecspkg.ServiceSummary) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(m.ctx, m.timeout)
+		defer cancel()
+
+		client, err := m.ecsActionClient(ctx)
+		if err != nil {
+			return ecsExecTargetsMsg{service: service, err: err}
+		}
+		targets, err := client.ExecTargets(ctx, service)
+		return ecsExecTargetsMsg{service: service, targets: targets, err: err}
+	}
+}
+
+// updateExecTargets starts the session right away when the service runs a
+// single container, and otherwise opens the menu to choose one
+func (m Model) updateExecTargets(msg ecsExecTargetsMsg) (Model, tea.Cmd) {
+	if msg.err != nil {
+		m.endSession("ecs", "", msg.err)
+		return m, nil
+	}
+	if len(msg.targets) == 1 {
+		return m, m.prepareSession(execSession(msg.service, msg.targets[0]))
+	}
+
+	m.ecsExecuting = false
+	m.ecsExecService = msg.service
+	m.ecsExecTargets = msg.targets
+	m.ecsExecSelected = 0
+	m.updateViewportContent()
+	return m, nil
+}
+
+// updateExecMenu handles a key while the menu of containers is open: the
+// arrow keys select a container, enter opens a shell in it and esc closes
+// the menu
+func (m Model) updateExecMenu(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "up", "k":
+		m.ecsExecSelected = max(0, m.ecsExecSelected-1)
+	case "down", "j":
+		m.ecsExecSelected = min(len(m.ecsExecTargets)-1, m.ecsExecSelected+1)
+	case "enter":
+		target := m.ecsExecTargets[m.ecsExecSelected]
+		m.ecsExecTargets = nil
+		m.ecsExecuting = true
+		m.updateViewportContent()
+		return m, m.prepareSession(execSession(m.ecsExecService, target)), true
+	case "esc":
+		m.ecsExecTargets = nil
+	case "ctrl+c":
+		return m, nil, false
+	}
+	m.updateViewportContent()
+	return m, nil, true
+}
+
+// execSession returns the ECS Exec session opening a shell in the container
+func execSession(service ecspkg.ServiceSummary, target ecspkg.ExecTarget) shellSession {
+	return shellSession{
+		service: "ecs",
+		entry:   audit.Entry{Action: "ecs:ExecuteCommand", Resource: target.TaskARN, Detail: "container " + target.Container},
+		name:    service.ServiceName + " " + target.String(),
+		args: []string{"ecs", "execute-command", "--cluster", target.ClusterName, "--task", target.TaskARN,
+			"--container", target.Container, "--interactive", "--command", execShell},
+		plugins: []string{"session-manager-plugin"},
+	}
+}
+
+// renderECSExec shows the menu of containers, the lookup of the containers
+// or the outcome of the last session above the services
+func (m Model) renderECSExec() string {
+	switch {
+	case m.ecsExecTargets != nil:
+		content := lipgloss.NewStyle().Bold(true).Render("Open a shell in a container of "+m.ecsExecService.ServiceName+":") + "\n"
+		for i, target := range m.ecsExecTargets {
+			marker := "  "
+			if i == m.ecsExecSelected {
+				marker = "> "
+			}
+			content += marker + target.String() + "\n"
+		}
+		return content + "\n"
+	case m.ecsExecuting:
+		return m.spinner.View() + " Starting ECS Exec...\n\n"
+	case m.ecsExecErr != nil:
+		return "ECS Exec failed: " + permissions.Describe(m.ecsExecErr) + "\n" + renderHints([]error{m.ecsExecErr}) + "\n"
+	case m.ecsExecNote != "":
+		return lipgloss.NewStyle().Foreground(successColor).Render(m.ecsExecNote) + "\n\n"
+	}
+	return ""
+}
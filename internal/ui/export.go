@@ -0,0 +1,32 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+
+	"github.com/correctedcloud/aws-overview/internal/export"
+	"github.com/correctedcloud/aws-overview/internal/session"
+)
+
+// LoadSnapshot loads the services selected by opts once and returns their
+// data, e.g. for -output csv
+func LoadSnapshot(opts Options) session.Snapshot {
+	return loadOnce(opts).Snapshot()
+}
+
+// exportCSV writes the resources loaded so far to a CSV file per resource
+// type in csv-<date>-<time> in the working directory, and notes the outcome
+// in a toast
+func (m Model) exportCSV() (Model, tea.Cmd) {
+	dir := "csv-" + time.Now().Format("20060102-150405")
+	paths, err := export.WriteCSV(dir, m.Snapshot())
+	if err != nil {
+		return m.showToast("Error exporting CSV: "+err.Error(), errorColor)
+	}
+	if len(paths) == 0 {
+		return m.showToast("No resources to export yet", warningColor)
+	}
+	return m.showToast(fmt.Sprintf("Exported %d resource types to %s", len(paths), dir), successColor)
+}
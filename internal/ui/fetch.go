@@ -0,0 +1,228 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/correctedcloud/aws-overview/internal/diagnostics"
+	"github.com/correctedcloud/aws-overview/internal/tracing"
+)
+
+// fetch returns a command that loads a service with a context derived from
+// the component's context. The context is cancelled after the fetch timeout
+// and when the service is fetched again, so a hung call cannot block its tab
+// forever. A superseded fetch returns no message, so it never overwrites the
+// data of the fetch that replaced it.
+func (m Model) fetch(service string, load func(ctx context.Context) tea.Msg) tea.Cmd {
+	// Commands are built on the event loop, so the map needs no lock
+	if cancel, ok := m.fetches[service]; ok {
+		cancel()
+	}
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.fetches[service] = cancel
+	loaded := tracing.Join(ctx)
+
+	return func() tea.Msg {
+		defer loaded()
+		defer cancel()
+		start := time.Now()
+		ctx, span := tracing.StartService(ctx, service)
+		defer span.End()
+		ctx, calls := diagnostics.CountCalls(ctx)
+
+		fetchCtx := ctx
+		if m.timeout > 0 {
+			var cancelTimeout context.CancelFunc
+			fetchCtx, cancelTimeout = context.WithTimeout(ctx, m.timeout)
+			defer cancelTimeout()
+		}
+
+		msg := load(fetchCtx)
+		if ctx.Err() != nil {
+			// Superseded by a newer fetch, or the component is shutting down
+			return nil
+		}
+		m.diagnostics.RecordRefresh(service, time.Since(start), calls())
+		slog.Debug("Loaded service", "service", service, "duration", time.Since(start), "calls", calls())
+		return msg
+	}
+}
+
+// partialMsg carries the partial results of a progressive fetch along with
+// the command waiting for the next ones
+type partialMsg struct {
+	msg  tea.Msg
+	next tea.Cmd
+}
+
+// fetchProgressively is fetch for loaders that report their results through
+// partial as they arrive, e.g. each load balancer once its target groups are
+// described, so that the first rows of a slow service show up early. The
+// loader still returns the complete results as its final message.
+func (m Model) fetchProgressively(service string, load func(ctx context.Context, partial func(tea.Msg)) tea.Msg) tea.Cmd {
+	partials := make(chan tea.Msg)
+	fetch := m.fetch(service, func(ctx context.Context) tea.Msg {
+		defer close(partials)
+		return load(ctx, func(msg tea.Msg) {
+			select {
+			case partials <- msg:
+			case <-ctx.Done():
+			}
+		})
+	})
+	return tea.Batch(fetch, waitForPartial(partials))
+}
+
+// waitForPartial returns a command that waits for the next partial result of
+// a progressive fetch, and returns nothing once the fetch is done
+func waitForPartial(partials <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-partials
+		if !ok {
+			return nil
+		}
+		return partialMsg{msg: msg, next: waitForPartial(partials)}
+	}
+}
+
+// cancelFetches aborts the AWS calls of all in-flight fetches
+func (m Model) cancelFetches() {
+	for _, cancel := range m.fetches {
+		cancel()
+	}
+}
+
+// loaded records that the fetch of a service completed, with cachedAt set
+// when the data was served from the cache
+func (m *Model) loaded(service string, cachedAt time.Time) {
+	delete(m.fetches, service)
+	m.setCachedAt(service, cachedAt)
+	if cachedAt.IsZero() {
+		cachedAt = time.Now()
+	}
+	m.loadedAt[service] = cachedAt
+}
+
+// fetching reports whether a fetch of the service is in flight
+func (m Model) fetching(service string) bool {
+	_, ok := m.fetches[service]
+	return ok
+}
+
+// refreshTab triggers a refresh of the service shown on the active tab, or
+// of all services on the Overview tab
+func (m Model) refreshTab() tea.Cmd {
+	if m.activeTab == 0 {
+		return m.refreshData()
+	}
+	if m.currentTab().load != nil {
+		return m.loadTraced([]tab{m.currentTab()})
+	}
+	return nil
+}
+
+// loadTraced starts a fetch of the data of each of tabs, in the trace of a
+// refresh that ends once they are all loaded
+func (m Model) loadTraced(tabs []tab) tea.Cmd {
+	if len(tabs) == 0 {
+		return nil
+	}
+	ctx, started := tracing.StartRefresh(m.ctx, "refresh")
+	defer started()
+	m.ctx = ctx
+
+	cmds := make([]tea.Cmd, len(tabs))
+	for i, t := range tabs {
+		cmds[i] = t.load(m)
+	}
+	return tea.Batch(cmds...)
+}
+
+// refreshIdle triggers a refresh of the services that are not being fetched
+// already, so a slow service does not hold back the others. Services with
+// their own refreshEvery are left alone until their data is that old.
+func (m Model) refreshIdle() tea.Cmd {
+	var tabs []tab
+	for _, t := range m.tabs {
+		if t.load == nil || m.fetching(t.service) {
+			continue
+		}
+		if loadedAt := m.loadedAt[t.service]; t.refreshEvery > 0 && !loadedAt.IsZero() && time.Since(loadedAt) < t.refreshEvery {
+			continue
+		}
+		tabs = append(tabs, t)
+	}
+	return m.loadTraced(tabs)
+}
+
+// renderLoadProgress lists the services on the Overview tab while the first
+// load of any of them is in flight, e.g. "Load Balancers ✅ 1.2s, RDS
+// Instances ⏳", so that a slow service does not hold back the blocks of
+// those already loaded
+func (m Model) renderLoadProgress() string {
+	refreshes := m.diagnostics.Refreshes()
+	var entries []string
+	inFlight := false
+	for _, t := range m.tabs[1:] {
+		if t.load == nil {
+			continue
+		}
+		switch {
+		case !m.loadedAt[t.service].IsZero():
+			entry := t.name + " ✅"
+			if refresh, ok := refreshes[t.service]; ok {
+				entry += " " + diagnostics.FormatDuration(refresh.Last)
+			}
+			entries = append(entries, entry)
+		case m.fetching(t.service):
+			inFlight = true
+			entries = append(entries, t.name+" ⏳")
+		case t.loading != nil && t.loading(m):
+			// Waiting for other services, e.g. the findings for the inventory
+			entries = append(entries, t.name+" ⏳")
+		}
+	}
+	if !inFlight {
+		return ""
+	}
+	return m.spinner.View() + " Loading: " + lipgloss.NewStyle().Foreground(dimTextColor).Render(strings.Join(entries, ", ")) + "\n\n"
+}
+
+// renderStaleness notes how old the data of the active tab is, or on the
+// Overview tab which service's data is the oldest among those refreshed
+// every interval
+func (m Model) renderStaleness() string {
+	tab := m.currentTab().name
+	service := m.currentTab().service
+	if m.activeTab == 0 {
+		for _, t := range m.tabs {
+			if t.service == "" || t.refreshEvery > 0 || m.loadedAt[t.service].IsZero() {
+				continue
+			}
+			if service == "" || m.loadedAt[t.service].Before(m.loadedAt[service]) {
+				service, tab = t.service, t.name
+			}
+		}
+	}
+
+	loadedAt := m.loadedAt[service]
+	if loadedAt.IsZero() {
+		return ""
+	}
+
+	age := time.Since(loadedAt).Truncate(time.Second)
+	indicator := fmt.Sprintf("updated %s ago", age)
+	if _, cached := m.cachedAt[service]; cached {
+		indicator = fmt.Sprintf("cached %s ago", age)
+	}
+	if m.activeTab == 0 {
+		indicator = "oldest: " + tab + " " + indicator
+	}
+	return indicator
+}
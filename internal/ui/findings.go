@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/correctedcloud/aws-overview/internal/permissions"
+	"github.com/correctedcloud/aws-overview/pkg/demo"
+	findingspkg "github.com/correctedcloud/aws-overview/pkg/findings"
+)
+
+// findingsDataLoadedMsg carries the findings about the collected inventory
+type findingsDataLoadedMsg struct {
+	findings []findingspkg.Finding
+	errs     []error
+	region   string
+	cachedAt time.Time // When the data was cached, zero when freshly loaded
+}
+
+// hasTab reports whether the tab of a service is enabled
+func (m Model) hasTab(service string) bool {
+	for _, t := range m.tabs {
+		if t.service == service {
+			return true
+		}
+	}
+	return false
+}
+
+// hasInventory reports whether a service whose resources the Findings tab
+// checks is enabled
+func (m Model) hasInventory() bool {
+	return m.hasTab("alb") || m.hasTab("ec2") || m.hasTab("rds")
+}
+
+// inventoryLoading reports whether an inventory service has not loaded yet
+func (m Model) inventoryLoading() bool {
+	return m.hasTab("alb") && m.loadingALB ||
+		m.hasTab("ec2") && m.loadingEC2 ||
+		m.hasTab("rds") && m.loadingRDS
+}
+
+// inventory returns the resources of the enabled inventory services
+func (m Model) inventory() findingspkg.Inventory {
+	var inventory findingspkg.Inventory
+	if m.hasTab("alb") {
+		inventory.LoadBalancers = m.loadBalancers
+	}
+	if m.hasTab("ec2") {
+		inventory.Instances = m.ec2Instances
+	}
+	if m.hasTab("rds") {
+		inventory.DBInstances = m.dbInstances
+	}
+	return inventory
+}
+
+// loadFindingsData is a command that checks the collected inventory against
+// its CloudWatch statistics and returns a message. It waits for the first
+// load of the inventory, which starts it once complete.
+func (m Model) loadFindingsData() tea.Cmd {
+	if m.inventoryLoading() {
+		return nil
+	}
+	inventory := m.inventory()
+
+	return m.fetch("findings", func(ctx context.Context) tea.Msg {
+		if m.demo {
+			found, errs := findingspkg.NewClient(demo.NewCloudWatch(), m.pool).GetFindings(ctx, inventory)
+			return findingsDataLoadedMsg{findings: found, errs: errs, region: demo.Region}
+		}
+
+		// Load AWS config
+		awsConfig, region, err := m.loadAWSConfig(ctx)
+		if err != nil {
+			return findingsDataLoadedMsg{errs: []error{err}}
+		}
+
+		// Serve the cached response while it is fresh
+		key := m.cacheKey(ctx, awsConfig, "findings")
+		var cached []findingspkg.Finding
+		if cachedAt, ok := m.cached(key, &cached); ok {
+			return findingsDataLoadedMsg{findings: cached, region: region, cachedAt: cachedAt}
+		}
+
+		// Create findings client
+		findingsClient := findingspkg.NewClient(cloudwatch.NewFromConfig(m.limiters.Apply(awsConfig, "cloudwatch")), m.pool)
+
+		// Get findings
+		found, errs := findingsClient.GetFindings(ctx, inventory)
+		if len(errs) == 0 {
+			m.store(key, found)
+		}
+		return findingsDataLoadedMsg{
+			findings: found,
+			errs:     errs,
+			region:   region, // Pass the potentially updated region
+		}
+	})
+}
+
+// loadFindingsAfterInventory starts the first load of the Findings tab once
+// the inventory it checks has loaded
+func (m Model) loadFindingsAfterInventory() tea.Cmd {
+	if !m.hasTab("findings") || !m.loadingFindings || m.fetching("findings") {
+		return nil
+	}
+	return m.loadFindingsData()
+}
+
+// renderFindings shows the cost and fault-tolerance findings
+func (m Model) renderFindings() string {
+	if !m.hasInventory() {
+		return "Findings check the resources of the Load Balancers, EC2 and RDS tabs; enable at least one of them, e.g. with -alb -ec2 -rds"
+	}
+	if m.loadingFindings {
+		if m.inventoryLoading() {
+			return m.spinner.View() + " Waiting for the inventory to load..."
+		}
+		return m.spinner.View() + " Loading findings..."
+	}
+
+	if len(m.findingsErrs) > 0 && len(m.findings) == 0 {
+		return "Error loading findings: " + permissions.DescribeAll(m.findingsErrs) + "\n\n" + renderHints(m.findingsErrs)
+	}
+
+	return renderLoadErrors(m.findingsErrs) + findingspkg.FormatFindings(m.findings)
+}
+
+// renderFindingsSummary shows the number of findings on the Overview tab
+func (m Model) renderFindingsSummary() string {
+	if m.loadingFindings || !m.hasInventory() {
+		return ""
+	}
+	if len(m.findingsErrs) > 0 && len(m.findings) == 0 {
+		return lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render("❌ Findings Error: ") +
+			lipgloss.NewStyle().Foreground(errorColor).Render(permissions.DescribeAll(m.findingsErrs)) + "\n\n"
+	}
+
+	header := lipgloss.NewStyle().Foreground(successColor).Bold(true).Render("✅ Findings: ")
+	if len(m.findings) > 0 {
+		header = lipgloss.NewStyle().Foreground(warningColor).Bold(true).Render("⚠️ Findings: ")
+	}
+	return header +
+		lipgloss.NewStyle().Foreground(textColor).Render(findingspkg.GetFindingsSummary(m.findings)) + "\n" +
+		renderLoadWarning(m.findingsErrs) + "\n"
+}
@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/correctedcloud/aws-overview/internal/permissions"
+	"github.com/correctedcloud/aws-overview/internal/report"
+	"github.com/correctedcloud/aws-overview/pkg/common"
+	eventspkg "github.com/correctedcloud/aws-overview/pkg/events"
+)
+
+// LoadReport loads the services selected by opts once and returns their
+// state for a report: the resources the Overview tab flags, the CloudWatch
+// alarms that are firing, the status line of each service and the loaded
+// data as a session snapshot.
+func LoadReport(opts Options) report.Report {
+	m := loadOnce(opts)
+	r := report.Report{Region: m.region, Generated: time.Now()}
+
+	// The Overview blocks, with the lag indicators first as on the tab
+	type block struct {
+		name    string
+		summary string
+	}
+	var blocks []block
+	if m.tabs[0].load != nil {
+		blocks = append(blocks, block{"Pipeline lag", m.renderLagSummary()})
+	}
+	for _, t := range m.tabs[1:] {
+		if t.summary != nil {
+			blocks = append(blocks, block{t.name, t.summary(m)})
+		}
+	}
+
+	for _, b := range blocks {
+		summary := ansi.Strip(b.summary)
+		if m.asciiSymbols {
+			summary = common.ASCIISymbols(summary)
+		}
+		status, flags := summaryLines(summary)
+		if status == "" {
+			continue
+		}
+		r.Statuses = append(r.Statuses, status)
+		// A service that failed to load needs attention too
+		if strings.HasPrefix(ansi.Strip(b.summary), "❌") {
+			flags = append([]string{status}, flags...)
+		}
+		if len(flags) > 0 {
+			r.Flagged = append(r.Flagged, report.Group{Name: b.name, Items: flags})
+		}
+	}
+
+	alarms, err := m.activeAlarms()
+	if err != nil {
+		r.AlarmsErr = permissions.Describe(err)
+	}
+	r.Alarms = alarms
+
+	if data, err := json.MarshalIndent(m.Snapshot(), "", "  "); err == nil {
+		r.Snapshot = data
+	}
+	return r
+}
+
+// summaryLines splits a block of the Overview tab into the status line of
+// its service and the resources flagged below it, which the blocks indent
+func summaryLines(block string) (string, []string) {
+	var status string
+	var flags []string
+	for _, line := range strings.Split(block, "\n") {
+		switch {
+		case strings.TrimSpace(line) == "":
+		case status == "":
+			status = strings.TrimSpace(line)
+		case strings.HasPrefix(line, " "):
+			flags = append(flags, strings.TrimSpace(line))
+		}
+	}
+	return status, flags
+}
+
+// activeAlarms returns the CloudWatch alarms that are firing
+func (m Model) activeAlarms() ([]eventspkg.Alarm, error) {
+	ctx := m.ctx
+	if m.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.timeout)
+		defer cancel()
+	}
+
+	client, err := m.eventsClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.ActiveAlarms(ctx)
+}
@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/correctedcloud/aws-overview/internal/permissions"
+	"github.com/correctedcloud/aws-overview/pkg/demo"
+	hygienepkg "github.com/correctedcloud/aws-overview/pkg/hygiene"
+)
+
+// hygieneRefreshEvery is how old the hygiene check gets before a load of
+// the inventory checks it again, as it looks at a month of statistics
+const hygieneRefreshEvery = time.Hour
+
+// hygieneDataLoadedMsg carries the likely wasted resources of the inventory
+type hygieneDataLoadedMsg struct {
+	resources []hygienepkg.Resource
+	errs      []error
+	region    string
+	cachedAt  time.Time // When the data was cached, zero when freshly loaded
+}
+
+// hasHygieneInventory reports whether a service whose resources the
+// hygiene check looks at is enabled
+func (m Model) hasHygieneInventory() bool {
+	return m.hasTab("alb") || m.hasTab("ec2") || m.hasTab("rds") || m.hasTab("sqs")
+}
+
+// hygieneInventoryLoading reports whether a checked service has not loaded yet
+func (m Model) hygieneInventoryLoading() bool {
+	return m.inventoryLoading() || m.hasTab("sqs") && m.loadingSQS
+}
+
+// hygieneInventory returns the resources of the enabled services the
+// hygiene check looks at
+func (m Model) hygieneInventory() hygienepkg.Inventory {
+	inventory := m.inventory()
+	hygieneInventory := hygienepkg.Inventory{
+		Instances:     inventory.Instances,
+		LoadBalancers: inventory.LoadBalancers,
+		DBInstances:   inventory.DBInstances,
+	}
+	if m.hasTab("sqs") {
+		hygieneInventory.Queues = m.sqsQueues
+	}
+	return hygieneInventory
+}
+
+// loadHygieneData is a command that checks the collected inventory for
+// likely wasted resources and returns a message
+func (m Model) loadHygieneData() tea.Cmd {
+	inventory := m.hygieneInventory()
+
+	return m.fetch("hygiene", func(ctx context.Context) tea.Msg {
+		if m.demo {
+			resources, errs := hygienepkg.NewClient(demo.NewCloudWatch(), m.pool, m.stoppedDays).GetResources(ctx, inventory)
+			return hygieneDataLoadedMsg{resources: resources, errs: errs, region: demo.Region}
+		}
+
+		// Load AWS config
+		awsConfig, region, err := m.loadAWSConfig(ctx)
+		if err != nil {
+			return hygieneDataLoadedMsg{errs: []error{err}}
+		}
+
+		// Serve the cached response while it is fresh
+		key := m.cacheKey(ctx, awsConfig, "hygiene")
+		var cached []hygienepkg.Resource
+		if cachedAt, ok := m.cached(key, &cached); ok {
+			return hygieneDataLoadedMsg{resources: cached, region: region, cachedAt: cachedAt}
+		}
+
+		// Create hygiene client
+		hygieneClient := hygienepkg.NewClient(cloudwatch.NewFromConfig(m.limiters.Apply(awsConfig, "cloudwatch")), m.pool, m.stoppedDays)
+
+		// Get likely wasted resources
+		resources, errs := hygieneClient.GetResources(ctx, inventory)
+		if len(errs) == 0 {
+			m.store(key, resources)
+		}
+		return hygieneDataLoadedMsg{
+			resources: resources,
+			errs:      errs,
+			region:    region, // Pass the potentially updated region
+		}
+	})
+}
+
+// loadHygieneAfterInventory checks the inventory once all of it has
+// loaded, then again after loads at least hygieneRefreshEvery later. The
+// hygiene check has no tab of its own, so the loads of the inventory drive it.
+func (m Model) loadHygieneAfterInventory() tea.Cmd {
+	if !m.showHygiene || !m.hasHygieneInventory() || m.hygieneInventoryLoading() || m.fetching("hygiene") {
+		return nil
+	}
+	if loadedAt := m.loadedAt["hygiene"]; !loadedAt.IsZero() && time.Since(loadedAt) < hygieneRefreshEvery {
+		return nil
+	}
+	return m.loadHygieneData()
+}
+
+// renderHygieneSummary shows the likely wasted resources in the Hygiene
+// section of the Overview tab
+func (m Model) renderHygieneSummary() string {
+	if !m.showHygiene {
+		return ""
+	}
+	if !m.hasHygieneInventory() {
+		return lipgloss.NewStyle().Foreground(dimTextColor).Render("🧹 Hygiene: checks the resources of the Load Balancers, EC2, RDS and SQS tabs; enable at least one of them") + "\n\n"
+	}
+	if m.loadingHygiene {
+		return lipgloss.NewStyle().Foreground(dimTextColor).Render("🧹 Hygiene: "+m.spinner.View()+" checking for wasted resources...") + "\n\n"
+	}
+	if len(m.hygieneErrs) > 0 && len(m.hygieneResources) == 0 {
+		return lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render("❌ Hygiene Error: ") +
+			lipgloss.NewStyle().Foreground(errorColor).Render(permissions.DescribeAll(m.hygieneErrs)) + "\n\n"
+	}
+
+	header := lipgloss.NewStyle().Foreground(successColor).Bold(true).Render("🧹 Hygiene: ")
+	if len(m.hygieneResources) > 0 {
+		header = lipgloss.NewStyle().Foreground(warningColor).Bold(true).Render("🧹 Hygiene: ")
+	}
+	content := header +
+		lipgloss.NewStyle().Foreground(textColor).Render(hygienepkg.GetSummary(m.hygieneResources)) + "\n" +
+		renderLoadWarning(m.hygieneErrs)
+	for _, resource := range m.hygieneResources {
+		content += lipgloss.NewStyle().Foreground(warningColor).Render("   🗑️ "+hygienepkg.FormatResource(resource)) + "\n"
+	}
+	return content + "\n"
+}
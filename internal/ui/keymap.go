@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// binding is a key of the overview and what it does, as listed by the help
+// overlay that ? opens
+type binding struct {
+	keys        string // As shown, e.g. "↑↓/j k"
+	description string
+	actions     bool // Only offered with -allow-actions
+	hosted      bool // Left to the host program when embedded
+}
+
+// keyGroup is a titled group of the keys that work on every tab
+type keyGroup struct {
+	title    string
+	bindings []binding
+}
+
+// globalKeymap lists the keys that work on every tab. Keys specific to a tab
+// are registered with the tab, see tab.keymap. A new key registers here or
+// there so that the help overlay lists it.
+var globalKeymap = []keyGroup{
+	{"Navigation", []binding{
+		{keys: "← →/h l/tab", description: "Switch to the previous or next tab"},
+		{keys: "↑↓/j k", description: "Scroll the tab, or move the selection on tabs with one"},
+		{keys: "pgup pgdown", description: "Scroll a page"},
+		{keys: "|", description: "Split the screen, pinning the active tab to the left pane, or end the split"},
+		{keys: "J K", description: "Scroll the pinned tab while the screen is split"},
+		{keys: "?", description: "Show or hide this help"},
+		{keys: "q/ctrl+c", description: "Quit", hosted: true},
+	}},
+	{"Data", []binding{
+		{keys: "r", description: "Refresh the active tab, or all tabs on the Overview"},
+		{keys: "R", description: "Refresh all tabs"},
+		{keys: "+", description: "Show more resources on a tab capped by -max-results"},
+		{keys: "1 3 6 24", description: "Graph the metrics of the last 1, 3, 6 or 24 hours on the RDS and SQS tabs"},
+		{keys: "D", description: "Show or hide the Diagnostics tab"},
+		{keys: "A", description: "Sign in again with AWS SSO once the credentials have expired, then reload all tabs"},
+	}},
+	{"Events and export", []binding{
+		{keys: "!", description: "Show or hide the event log of state changes"},
+		{keys: "w", description: "Write the event log to a file while it is shown"},
+		{keys: "X", description: "Export the loaded resources to a CSV file per resource type in csv-<date>-<time>"},
+	}},
+}
+
+// paneKeymap lists the keys opening the resource pane on the selected
+// resource, in the order of paneKind
+var paneKeymap = []binding{
+	{keys: "L", description: "Tail the error logs of the selected resource"},
+	{keys: "E", description: "Show the alarms, changes and events related to the selected resource"},
+	{keys: "P", description: "Peek at the messages of the selected queue"},
+	{keys: "G", description: "Graph the metrics of the selected resource in detail"},
+}
+
+// paneKeymapOf returns the keys opening the resource pane on a tab. Only
+// queues have messages to peek at, and only the tabs graphing the metrics
+// of a window have graphs to show in detail.
+func paneKeymapOf(t tab) []binding {
+	var bindings []binding
+	for kind, b := range paneKeymap {
+		if (paneKind(kind) == paneMessages && t.service != "sqs") || (paneKind(kind) == paneGraphs && !t.windowed) {
+			continue
+		}
+		bindings = append(bindings, b)
+	}
+	return bindings
+}
+
+// tabKeymap returns the keys of a tab: its own followed by those it gets
+// from sorting, selecting, copying and opening resources
+func tabKeymap(t tab) []binding {
+	bindings := append([]binding(nil), t.keymap...)
+	if t.sortColumns > 0 {
+		bindings = append(bindings, binding{keys: "s", description: "Sort the table by the next column"})
+	}
+	if t.selected != nil {
+		bindings = append(bindings, paneKeymapOf(t)...)
+	}
+	if t.identifier != nil {
+		bindings = append(bindings, binding{keys: "y", description: "Copy the identifier of the selected resource"})
+	}
+	if t.console != nil {
+		bindings = append(bindings, binding{keys: "o", description: "Open the selected resource in the AWS console"})
+	}
+	return bindings
+}
+
+// toggleKeymap shows the help overlay in place of the active tab, or hides it
+func (m Model) toggleKeymap() Model {
+	m.showKeymap = !m.showKeymap
+	m.updateViewportContent()
+	m.viewport.GotoTop()
+	return m
+}
+
+// updateKeymapKeys handles a key while the help overlay is shown: ? and esc
+// close it, the viewport scrolls it and quitting still works. Other keys are
+// ignored so they do not act on the tab hidden behind it.
+func (m Model) updateKeymapKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "?", "esc":
+		return m.toggleKeymap(), nil, true
+	case "q", "ctrl+c":
+		return m, nil, false
+	}
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd, true
+}
+
+// renderKeymap lists the keys that work on every tab by group, then the keys
+// of each tab shown
+func (m Model) renderKeymap() string {
+	titleStyle := lipgloss.NewStyle().Foreground(accentColor).Bold(true)
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Keys") + "\n\n")
+	for _, group := range globalKeymap {
+		b.WriteString(titleStyle.Render(group.title) + "\n")
+		b.WriteString(m.renderBindings(group.bindings) + "\n")
+	}
+
+	for _, t := range m.tabs {
+		bindings := tabKeymap(t)
+		if len(bindings) == 0 {
+			continue
+		}
+		b.WriteString(titleStyle.Render(t.name+" tab") + "\n")
+		b.WriteString(m.renderBindings(bindings) + "\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// renderBindings lists bindings one per line with their keys aligned, noting
+// those that need -allow-actions while it is not given
+func (m Model) renderBindings(bindings []binding) string {
+	keyStyle := lipgloss.NewStyle().Foreground(secondaryColor).Bold(true)
+	disabledStyle := lipgloss.NewStyle().Foreground(dimTextColor)
+
+	var b strings.Builder
+	for _, binding := range bindings {
+		if binding.hosted && m.embedded {
+			continue
+		}
+		line := fmt.Sprintf("  %s  %s", keyStyle.Render(fmt.Sprintf("%-20s", binding.keys)), binding.description)
+		if binding.actions && !m.allowActions {
+			line = fmt.Sprintf("  %s  %s", disabledStyle.Render(fmt.Sprintf("%-20s", binding.keys)), disabledStyle.Render(binding.description+" (needs -allow-actions)"))
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
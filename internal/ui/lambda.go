@@ -0,0 +1,227 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/correctedcloud/aws-overview/internal/permissions"
+	"github.com/correctedcloud/aws-overview/pkg/demo"
+	eventspkg "github.com/correctedcloud/aws-overview/pkg/events"
+	lambdapkg "github.com/correctedcloud/aws-overview/pkg/lambda"
+	logspkg "github.com/correctedcloud/aws-overview/pkg/logs"
+)
+
+// errActionsDisabled is shown when an action is triggered without -allow-actions
+var errActionsDisabled = errors.New("actions are disabled, start with -allow-actions to enable them")
+
+// lambdaInvokedMsg carries the result of a test invocation
+type lambdaInvokedMsg struct {
+	function string
+	result   lambdapkg.InvocationResult
+	err      error
+}
+
+// newPayloadEditor returns the editor of test invocation payloads
+func newPayloadEditor() textarea.Model {
+	editor := textarea.New()
+	editor.Placeholder = `{"key": "value"}`
+	editor.ShowLineNumbers = false
+	editor.CharLimit = 256 * 1024 // Synchronous invocations accept up to 6 MB, but nobody types that
+	editor.SetHeight(10)
+	editor.SetValue("{}")
+	return editor
+}
+
+// updateLambdaKeys handles the keys of the Lambda tab: the arrow keys select a
+// function instead of scrolling, i opens the payload editor and esc closes
+// the invocation result
+func (m Model) updateLambdaKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "up", "k":
+		m.moveLambdaSelection(-1)
+		return m, nil, true
+	case "down", "j":
+		m.moveLambdaSelection(1)
+		return m, nil, true
+	case "i":
+		cmd := m.openPayloadEditor()
+		return m, cmd, true
+	case "esc":
+		if m.invokingLambda {
+			return m, nil, true
+		}
+		m.lambdaInvocation = nil
+		m.lambdaInvokeErr = nil
+		m.updateViewportContent()
+		return m, nil, true
+	}
+	return m, nil, false
+}
+
+// lambdaHelp describes the keys of the Lambda tab
+func (m Model) lambdaHelp() string {
+	if !m.allowActions {
+		return "↑↓ Select"
+	}
+	return "↑↓ Select • i Invoke"
+}
+
+// selectedFunction returns the function selected on the Lambda tab
+func (m Model) selectedFunction() (lambdapkg.FunctionSummary, bool) {
+	if m.lambdaSelected < 0 || m.lambdaSelected >= len(m.lambdaFunctions) {
+		return lambdapkg.FunctionSummary{}, false
+	}
+	return m.lambdaFunctions[m.lambdaSelected], true
+}
+
+// functionByName returns the listed function named name, which refreshes may
+// have moved or removed since the payload editor was opened for it
+func (m Model) functionByName(name string) (lambdapkg.FunctionSummary, bool) {
+	for _, function := range m.lambdaFunctions {
+		if function.Name == name {
+			return function, true
+		}
+	}
+	return lambdapkg.FunctionSummary{}, false
+}
+
+// selectedFunctionResource returns the function selected on the Lambda tab
+// for the resource pane
+func (m Model) selectedFunctionResource() (resource, bool) {
+	function, ok := m.selectedFunction()
+	if !ok {
+		return resource{}, false
+	}
+	return resource{
+		name: "Lambda function " + function.Name,
+		logSources: func(context.Context, *logspkg.Client) ([]logspkg.Source, error) {
+			return []logspkg.Source{{Group: function.LogGroupName()}}, nil
+		},
+		events: eventspkg.Resource{
+			TrailNames:      []string{function.Name, function.ARN},
+			AlarmDimensions: map[string]string{"FunctionName": function.Name},
+		},
+	}, true
+}
+
+// moveLambdaSelection moves the selection by delta functions and scrolls the
+// viewport so the selected function stays visible
+func (m *Model) moveLambdaSelection(delta int) {
+	if len(m.lambdaFunctions) == 0 {
+		return
+	}
+	m.lambdaSelected = max(0, min(m.shown("lambda", len(m.lambdaFunctions))-1, m.lambdaSelected+delta))
+	m.updateViewportContent()
+	m.scrollToSelection(m.renderLambda())
+}
+
+// openPayloadEditor starts editing the payload of a test invocation of the
+// selected function, keeping the previous payload
+func (m *Model) openPayloadEditor() tea.Cmd {
+	if !m.allowActions {
+		m.lambdaInvokeErr = errActionsDisabled
+		m.updateViewportContent()
+		return nil
+	}
+	function, ok := m.selectedFunction()
+	if !ok || m.invokingLambda {
+		return nil
+	}
+
+	m.lambdaInvokeErr = nil
+	m.payloadFunction = function.Name
+	m.payloadEditor.SetWidth(max(20, m.width-12))
+	return m.payloadEditor.Focus()
+}
+
+// updatePayloadEditor handles a key while the payload editor has focus.
+// ctrl+s invokes the function the editor was opened for and esc closes the
+// editor.
+func (m Model) updatePayloadEditor(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.payloadEditor.Blur()
+		m.lambdaInvokeErr = nil
+		return m, nil
+	case "ctrl+s":
+		payload := strings.TrimSpace(m.payloadEditor.Value())
+		if !json.Valid([]byte(payload)) {
+			m.lambdaInvokeErr = errors.New("the payload is not valid JSON")
+			return m, nil
+		}
+		function, ok := m.functionByName(m.payloadFunction)
+		if !ok {
+			m.payloadEditor.Blur()
+			m.lambdaInvocation = nil
+			m.lambdaInvokeErr = fmt.Errorf("function %s is no longer listed, not invoked", m.payloadFunction)
+			m.updateViewportContent()
+			return m, nil
+		}
+
+		m.payloadEditor.Blur()
+		m.lambdaInvokeErr = nil
+		m.invokingLambda = true
+		m.updateViewportContent()
+		return m, m.invokeLambda(function, []byte(payload))
+	}
+
+	var cmd tea.Cmd
+	m.payloadEditor, cmd = m.payloadEditor.Update(msg)
+	return m, cmd
+}
+
+// invokeLambda is a command that invokes a function synchronously and
+// returns its result. The call may take as long as the function's timeout.
+func (m Model) invokeLambda(function lambdapkg.FunctionSummary, payload []byte) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(m.ctx, time.Duration(function.Timeout)*time.Second+m.timeout)
+		defer cancel()
+
+		if m.demo {
+			result, err := lambdapkg.NewClient(demo.NewLambda(), m.pool).Invoke(ctx, function.Name, payload)
+			return lambdaInvokedMsg{function: function.Name, result: result, err: err}
+		}
+
+		awsConfig, _, err := m.loadAWSConfig(ctx)
+		if err != nil {
+			return lambdaInvokedMsg{function: function.Name, err: err}
+		}
+
+		client := lambdapkg.NewClient(lambda.NewFromConfig(m.limiters.Apply(awsConfig, "lambda")), m.pool)
+		result, err := client.Invoke(ctx, function.Name, payload)
+		return lambdaInvokedMsg{function: function.Name, result: result, err: err}
+	}
+}
+
+// renderPayloadEditor shows the payload editor in place of the tab content
+func (m Model) renderPayloadEditor() string {
+	view := lipgloss.NewStyle().Foreground(accentColor).Bold(true).Render("Invoke "+m.payloadFunction+" with payload:") + "\n\n" +
+		m.payloadEditor.View() + "\n\n" +
+		lipgloss.NewStyle().Foreground(dimTextColor).Render("ctrl+s Invoke • esc Cancel")
+	if m.lambdaInvokeErr != nil {
+		view += "\n" + lipgloss.NewStyle().Foreground(errorColor).Render(m.lambdaInvokeErr.Error())
+	}
+	return view
+}
+
+// renderInvocation shows the last test invocation above the functions
+func (m Model) renderInvocation() string {
+	switch {
+	case m.invokingLambda:
+		return m.spinner.View() + " Invoking...\n\n"
+	case m.lambdaInvokeErr != nil:
+		return "Invocation failed: " + permissions.Describe(m.lambdaInvokeErr) + "\n" + renderHints([]error{m.lambdaInvokeErr}) + "\n"
+	case m.lambdaInvocation != nil:
+		return lambdapkg.FormatInvocation(*m.lambdaInvocation) + "\n"
+	}
+	return ""
+}
@@ -0,0 +1,408 @@
+package ui
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/correctedcloud/aws-overview/internal/permissions"
+	"github.com/correctedcloud/aws-overview/internal/pins"
+	"github.com/correctedcloud/aws-overview/pkg/cloudwatchmetrics"
+	"github.com/correctedcloud/aws-overview/pkg/demo"
+	metricspkg "github.com/correctedcloud/aws-overview/pkg/metrics"
+)
+
+// metricNamespacesLoadedMsg carries the namespaces listed on the CloudWatch tab
+type metricNamespacesLoadedMsg struct {
+	namespaces []metricspkg.Namespace
+	truncated  bool
+	err        error
+	region     string
+}
+
+// metricListLoadedMsg carries the metrics of the namespace browsed on the
+// CloudWatch tab
+type metricListLoadedMsg struct {
+	namespace string
+	metrics   []metricspkg.Metric
+	truncated bool
+	err       error
+}
+
+// metricPlotLoadedMsg carries the series of the metric plotted on the
+// CloudWatch tab
+type metricPlotLoadedMsg struct {
+	pin    metricspkg.Pin
+	result cloudwatchmetrics.Result
+}
+
+// pinnedMetricsLoadedMsg carries the series of the pinned metrics
+type pinnedMetricsLoadedMsg struct {
+	pins    []metricspkg.Pin
+	results []cloudwatchmetrics.Result
+	err     error
+	region  string
+}
+
+// pinsSavedMsg carries the outcome of writing the pins file
+type pinsSavedMsg struct {
+	err error
+}
+
+// metricsClient returns a client browsing the CloudWatch metrics
+func (m Model) metricsClient(ctx context.Context) (*metricspkg.Client, string, error) {
+	if m.demo {
+		return metricspkg.NewClient(demo.NewCloudWatch(), m.pool), demo.Region, nil
+	}
+
+	awsConfig, region, err := m.loadAWSConfig(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	return metricspkg.NewClient(cloudwatch.NewFromConfig(m.limiters.Apply(awsConfig, "cloudwatch")), m.pool), region, nil
+}
+
+// loadMetricBrowser is a command that reloads what the CloudWatch tab shows:
+// the plotted metric, the metrics of the browsed namespace or the namespaces.
+// The listings are not cached or saved with the session.
+func (m Model) loadMetricBrowser() tea.Cmd {
+	switch {
+	case m.metricPlot != nil:
+		return m.plotMetric(*m.metricPlot)
+	case m.metricNamespace != "":
+		return m.loadMetricList(m.metricNamespace)
+	}
+
+	return m.fetch("metrics", func(ctx context.Context) tea.Msg {
+		client, region, err := m.metricsClient(ctx)
+		if err != nil {
+			return metricNamespacesLoadedMsg{err: err}
+		}
+		namespaces, truncated, err := client.ListNamespaces(ctx)
+		return metricNamespacesLoadedMsg{namespaces: namespaces, truncated: truncated, err: err, region: region}
+	})
+}
+
+// loadMetricList is a command that lists the metrics of a namespace
+func (m Model) loadMetricList(namespace string) tea.Cmd {
+	return m.fetch("metrics", func(ctx context.Context) tea.Msg {
+		client, _, err := m.metricsClient(ctx)
+		if err != nil {
+			return metricListLoadedMsg{namespace: namespace, err: err}
+		}
+		metrics, truncated, err := client.ListMetrics(ctx, namespace)
+		return metricListLoadedMsg{namespace: namespace, metrics: metrics, truncated: truncated, err: err}
+	})
+}
+
+// plotMetric is a command that fetches the series of a metric to plot
+func (m Model) plotMetric(pin metricspkg.Pin) tea.Cmd {
+	return m.fetch("metrics", func(ctx context.Context) tea.Msg {
+		client, _, err := m.metricsClient(ctx)
+		if err != nil {
+			return metricPlotLoadedMsg{pin: pin, result: cloudwatchmetrics.Result{Err: err}}
+		}
+		return metricPlotLoadedMsg{pin: pin, result: client.GetSeries(ctx, []metricspkg.Pin{pin})[0]}
+	})
+}
+
+// loadPinnedMetrics is a command that fetches the series of the pinned metrics
+func (m Model) loadPinnedMetrics() tea.Cmd {
+	pinned := m.pins
+	return m.fetch("pins", func(ctx context.Context) tea.Msg {
+		client, region, err := m.metricsClient(ctx)
+		if err != nil {
+			return pinnedMetricsLoadedMsg{pins: pinned, err: err}
+		}
+		return pinnedMetricsLoadedMsg{pins: pinned, results: client.GetSeries(ctx, pinned), region: region}
+	})
+}
+
+// savePins is a command that writes the pins to the pins file, if any
+func (m Model) savePins() tea.Cmd {
+	path, pinned, sealer := m.pinsFile, m.pins, m.sealer
+	if path == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		return pinsSavedMsg{err: pins.Save(path, pinned, sealer)}
+	}
+}
+
+// updateMetricBrowser records the outcome of a fetch of the CloudWatch tab
+func (m *Model) updateMetricBrowser(msg tea.Msg) {
+	m.loaded("metrics", time.Time{})
+	m.loadingMetrics = false
+
+	switch msg := msg.(type) {
+	case metricNamespacesLoadedMsg:
+		m.metricsErr = msg.err
+		if msg.err == nil {
+			m.metricNamespaces, m.namespacesTruncated = msg.namespaces, msg.truncated
+			m.metricNamespaceSelected = min(m.metricNamespaceSelected, max(0, len(m.metricNamespaces)-1))
+		}
+		// Update region if it was empty and we got it from AWS config
+		if m.region == "" && msg.region != "" {
+			m.region = msg.region
+		}
+	case metricListLoadedMsg:
+		if msg.namespace != m.metricNamespace {
+			return
+		}
+		m.metricsErr = msg.err
+		if msg.err == nil {
+			m.metricList, m.metricListTruncated = msg.metrics, msg.truncated
+			m.metricSelected = min(m.metricSelected, max(0, len(m.metricList)-1))
+		}
+	case metricPlotLoadedMsg:
+		if m.metricPlot == nil || m.metricPlot.Label() != msg.pin.Label() {
+			return
+		}
+		m.metricPlotResult = &msg.result
+	}
+	m.updateViewportContent()
+}
+
+// updatePinnedMetrics records the series of the pinned metrics by pin, so
+// that pins added or removed during the fetch keep their own
+func (m *Model) updatePinnedMetrics(msg pinnedMetricsLoadedMsg) {
+	m.loaded("pins", time.Time{})
+	m.loadingPins = false
+	m.pinsLoadErr = msg.err
+	for i, pin := range msg.pins {
+		if i < len(msg.results) {
+			m.pinResults[pin.Label()] = msg.results[i]
+		}
+	}
+	// Update region if it was empty and we got it from AWS config
+	if m.region == "" && msg.region != "" {
+		m.region = msg.region
+	}
+	m.updateViewportContent()
+}
+
+// updateMetricBrowserKeys handles the keys of the CloudWatch tab: the arrow
+// keys select a namespace or metric, enter opens the namespace or plots the
+// metric, t cycles the statistic of the plot, p pins it to the Custom Metrics
+// tab and esc goes back a level
+func (m Model) updateMetricBrowserKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "up", "k":
+		m.moveMetricSelection(-1)
+		return m, nil, true
+	case "down", "j":
+		m.moveMetricSelection(1)
+		return m, nil, true
+	case "enter":
+		return m.openMetricSelection()
+	case "t":
+		if m.metricPlot == nil {
+			return m, nil, false
+		}
+		pin := *m.metricPlot
+		pin.Stat = metricspkg.NextStat(pin.Stat)
+		return m.showPlot(pin)
+	case "p":
+		if m.metricPlot == nil {
+			return m, nil, false
+		}
+		m.pinMetric(*m.metricPlot)
+		m.updateViewportContent()
+		return m, tea.Batch(m.savePins(), m.loadPinnedMetrics()), true
+	case "esc":
+		switch {
+		case m.metricPlot != nil:
+			m.metricPlot, m.metricPlotResult = nil, nil
+		case m.metricNamespace != "":
+			m.metricNamespace, m.metricList = "", nil
+			m.metricsErr = nil
+		default:
+			return m, nil, true
+		}
+		m.pinNote = ""
+		m.updateViewportContent()
+		return m, nil, true
+	}
+	return m, nil, false
+}
+
+// openMetricSelection enters the selected namespace, or plots the selected
+// metric with the default statistic
+func (m Model) openMetricSelection() (Model, tea.Cmd, bool) {
+	if m.metricNamespace == "" {
+		if m.metricNamespaceSelected >= len(m.metricNamespaces) {
+			return m, nil, true
+		}
+		m.metricNamespace = m.metricNamespaces[m.metricNamespaceSelected].Name
+		m.metricList, m.metricSelected = nil, 0
+		m.metricsErr = nil
+		m.loadingMetrics = true
+		m.updateViewportContent()
+		m.viewport.GotoTop()
+		return m, m.loadMetricList(m.metricNamespace), true
+	}
+
+	if m.metricSelected >= len(m.metricList) {
+		return m, nil, true
+	}
+	return m.showPlot(metricspkg.Pin{Metric: m.metricList[m.metricSelected], Stat: metricspkg.Stats[0]})
+}
+
+// showPlot plots a metric above the metrics of its namespace
+func (m Model) showPlot(pin metricspkg.Pin) (Model, tea.Cmd, bool) {
+	m.metricPlot, m.metricPlotResult = &pin, nil
+	m.pinNote = ""
+	m.loadingMetrics = true
+	m.updateViewportContent()
+	m.viewport.GotoTop()
+	return m, m.plotMetric(pin), true
+}
+
+// pinMetric adds a metric to the Custom Metrics tab unless it is pinned already
+func (m *Model) pinMetric(pin metricspkg.Pin) {
+	for _, pinned := range m.pins {
+		if pinned.Label() == pin.Label() {
+			m.pinNote = "Already pinned to the Custom Metrics tab"
+			return
+		}
+	}
+	m.pins = append(m.pins, pin)
+	m.pinNote = "Pinned to the Custom Metrics tab"
+	if m.metricPlotResult != nil {
+		m.pinResults[pin.Label()] = *m.metricPlotResult
+	}
+}
+
+// metricBrowserHelp describes the keys of the CloudWatch tab
+func (m Model) metricBrowserHelp() string {
+	switch {
+	case m.metricPlot != nil:
+		return "↑↓ Select • enter Plot • t Stat • p Pin • esc Close"
+	case m.metricNamespace != "":
+		return "↑↓ Select • enter Plot • esc Namespaces"
+	}
+	return "↑↓ Select • enter Open"
+}
+
+// moveMetricSelection moves the selection by delta namespaces or metrics and
+// scrolls the viewport so the selected one stays visible
+func (m *Model) moveMetricSelection(delta int) {
+	if m.metricNamespace == "" {
+		if len(m.metricNamespaces) == 0 {
+			return
+		}
+		m.metricNamespaceSelected = max(0, min(len(m.metricNamespaces)-1, m.metricNamespaceSelected+delta))
+	} else {
+		if len(m.metricList) == 0 {
+			return
+		}
+		m.metricSelected = max(0, min(len(m.metricList)-1, m.metricSelected+delta))
+	}
+	m.updateViewportContent()
+	m.scrollToSelection(m.renderMetricBrowser())
+}
+
+// updatePinKeys handles the keys of the Custom Metrics tab: the arrow keys
+// select a pinned metric and x unpins it
+func (m Model) updatePinKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "up", "k":
+		m.movePinSelection(-1)
+		return m, nil, true
+	case "down", "j":
+		m.movePinSelection(1)
+		return m, nil, true
+	case "x":
+		if m.pinSelected >= len(m.pins) {
+			return m, nil, true
+		}
+		m.pins = append(m.pins[:m.pinSelected:m.pinSelected], m.pins[m.pinSelected+1:]...)
+		m.pinSelected = min(m.pinSelected, max(0, len(m.pins)-1))
+		m.updateViewportContent()
+		return m, m.savePins(), true
+	}
+	return m, nil, false
+}
+
+// pinHelp describes the keys of the Custom Metrics tab
+func (m Model) pinHelp() string {
+	return "↑↓ Select • x Unpin"
+}
+
+// movePinSelection moves the selection by delta pins and scrolls the
+// viewport so the selected pin stays visible
+func (m *Model) movePinSelection(delta int) {
+	if len(m.pins) == 0 {
+		return
+	}
+	m.pinSelected = max(0, min(len(m.pins)-1, m.pinSelected+delta))
+	m.updateViewportContent()
+	m.scrollToSelection(m.renderPinnedMetrics())
+}
+
+// renderMetricBrowser shows the namespaces, or the plotted metric above the
+// metrics of the browsed namespace
+func (m Model) renderMetricBrowser() string {
+	var content string
+	if m.metricsErr != nil {
+		content = "Error loading CloudWatch metrics: " + permissions.Describe(m.metricsErr) + "\n" + renderHints([]error{m.metricsErr}) + "\n"
+	}
+
+	if m.metricNamespace == "" {
+		if m.loadingMetrics && m.metricNamespaces == nil {
+			return m.spinner.View() + " Loading CloudWatch namespaces..."
+		}
+		if m.metricNamespaces == nil {
+			return content
+		}
+		return content + metricspkg.FormatNamespaces(m.metricNamespaces, m.selection(m.metricNamespaceSelected), m.namespacesTruncated)
+	}
+
+	if m.metricPlot != nil {
+		if m.metricPlotResult == nil {
+			content += m.spinner.View() + " Loading " + m.metricPlot.Label() + "...\n\n"
+		} else {
+			content += metricspkg.FormatSeries(*m.metricPlot, *m.metricPlotResult)
+			if m.pinNote != "" {
+				content += lipgloss.NewStyle().Foreground(successColor).Render(m.pinNote) + "\n"
+			}
+			content += "\n"
+		}
+	}
+
+	if m.loadingMetrics && m.metricList == nil {
+		return content + m.spinner.View() + " Loading metrics of " + m.metricNamespace + "..."
+	}
+	return content + metricspkg.FormatMetrics(m.metricNamespace, m.metricList, m.selection(m.metricSelected), m.metricListTruncated)
+}
+
+// renderPinnedMetrics shows the graphs of the pinned metrics
+func (m Model) renderPinnedMetrics() string {
+	var content string
+	if m.pinsSaveErr != nil {
+		content += lipgloss.NewStyle().Foreground(warningColor).Render("⚠️ Pins are not saved: "+m.pinsSaveErr.Error()) + "\n\n"
+	}
+	if m.pinsLoadErr != nil {
+		return content + "Error loading custom metrics: " + permissions.Describe(m.pinsLoadErr) + "\n\n" + renderHints([]error{m.pinsLoadErr})
+	}
+	if m.loadingPins && len(m.pinResults) == 0 && len(m.pins) > 0 {
+		return content + m.spinner.View() + " Loading custom metrics..."
+	}
+	return content + metricspkg.FormatPins(m.pins, m.pinResults, m.selection(m.pinSelected))
+}
+
+// renderPinnedMetricsSummary shows the pinned metrics on the Overview tab
+func (m Model) renderPinnedMetricsSummary() string {
+	if m.pinsLoadErr != nil {
+		return lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render("❌ Custom Metrics Error: ") +
+			lipgloss.NewStyle().Foreground(errorColor).Render(permissions.Describe(m.pinsLoadErr)) + "\n\n"
+	}
+	if len(m.pins) == 0 || len(m.pinResults) == 0 {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(successColor).Bold(true).Render("✅ Custom Metrics: ") +
+		lipgloss.NewStyle().Foreground(textColor).Render(metricspkg.GetPinsSummary(m.pins, m.pinResults)) + "\n\n"
+}
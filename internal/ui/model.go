@@ -1,134 +1,293 @@
 package ui
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	"github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/correctedcloud/aws-overview/internal/alerts"
+	"github.com/correctedcloud/aws-overview/internal/audit"
+	"github.com/correctedcloud/aws-overview/internal/cache"
+	"github.com/correctedcloud/aws-overview/internal/config"
+	"github.com/correctedcloud/aws-overview/internal/diagnostics"
+	"github.com/correctedcloud/aws-overview/internal/logging"
+	"github.com/correctedcloud/aws-overview/internal/permissions"
+	"github.com/correctedcloud/aws-overview/internal/runbook"
+	"github.com/correctedcloud/aws-overview/internal/seal"
+	"github.com/correctedcloud/aws-overview/internal/tracing"
 	"github.com/correctedcloud/aws-overview/pkg/alb"
+	apigatewaypkg "github.com/correctedcloud/aws-overview/pkg/apigateway"
+	"github.com/correctedcloud/aws-overview/pkg/changes"
+	cloudfrontpkg "github.com/correctedcloud/aws-overview/pkg/cloudfront"
+	"github.com/correctedcloud/aws-overview/pkg/cloudwatchmetrics"
+	"github.com/correctedcloud/aws-overview/pkg/common"
+	costpkg "github.com/correctedcloud/aws-overview/pkg/cost"
+	"github.com/correctedcloud/aws-overview/pkg/demo"
+	"github.com/correctedcloud/aws-overview/pkg/dns"
+	"github.com/correctedcloud/aws-overview/pkg/dr"
+	"github.com/correctedcloud/aws-overview/pkg/ebs"
 	"github.com/correctedcloud/aws-overview/pkg/ec2"
+	ecrpkg "github.com/correctedcloud/aws-overview/pkg/ecr"
 	"github.com/correctedcloud/aws-overview/pkg/ecs"
+	eventspkg "github.com/correctedcloud/aws-overview/pkg/events"
+	findingspkg "github.com/correctedcloud/aws-overview/pkg/findings"
+	hygienepkg "github.com/correctedcloud/aws-overview/pkg/hygiene"
+	"github.com/correctedcloud/aws-overview/pkg/lag"
+	lambdapkg "github.com/correctedcloud/aws-overview/pkg/lambda"
+	logspkg "github.com/correctedcloud/aws-overview/pkg/logs"
+	metricspkg "github.com/correctedcloud/aws-overview/pkg/metrics"
+	probepkg "github.com/correctedcloud/aws-overview/pkg/probe"
 	"github.com/correctedcloud/aws-overview/pkg/rds"
+	"github.com/correctedcloud/aws-overview/pkg/sns"
 	"github.com/correctedcloud/aws-overview/pkg/sqs"
-)
-
-// Color scheme for the UI
-var (
-	// Define color palette
-	primaryColor    = lipgloss.Color("#7D56F4") // Vibrant purple
-	secondaryColor  = lipgloss.Color("#5AD4E6") // Bright cyan
-	accentColor     = lipgloss.Color("#FFB938") // Warm amber
-	errorColor      = lipgloss.Color("#FF5F87") // Soft red
-	successColor    = lipgloss.Color("#39DA8A") // Vibrant green
-	warningColor    = lipgloss.Color("#FFBD54") // Amber
-	backgroundColor = lipgloss.Color("#1A1B26") // Dark background
-	textColor       = lipgloss.Color("#FAFAFA") // Light text
-	dimTextColor    = lipgloss.Color("#9699B7") // Dimmed text
-)
-
-var (
-	titleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(textColor).
-			Background(primaryColor).
-			Padding(0, 2).
-			Margin(0, 0, 0, 0).
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(primaryColor)
-
-	tabStyle = lipgloss.NewStyle().
-			Foreground(textColor).
-			Background(dimTextColor).
-			Padding(0, 2).
-			Margin(0, 1, 0, 0).
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderBottom(true).
-			BorderForeground(dimTextColor)
-
-	activeTabStyle = lipgloss.NewStyle().
-			Foreground(textColor).
-			Background(accentColor).
-			Padding(0, 2).
-			Margin(0, 1, 0, 0).
-			Bold(true).
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderBottom(true).
-			BorderForeground(accentColor)
-
-	contentStyle = lipgloss.NewStyle().
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(secondaryColor).
-			Margin(1, 0, 0, 0).
-			Padding(1, 2)
-
-	tabGap = lipgloss.NewStyle().Padding(0, 1)
-
-	headerStyle = lipgloss.NewStyle().
-			Foreground(textColor).
-			Background(backgroundColor).
-			Width(100).
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(primaryColor).
-			Margin(0, 0, 1, 0).
-			Padding(0, 2).
-			Bold(true)
+	"github.com/correctedcloud/aws-overview/pkg/ssm"
+	vpcpkg "github.com/correctedcloud/aws-overview/pkg/vpc"
 )
 
 // Model is the main UI model
 type Model struct {
-	spinner       spinner.Model
-	viewport      viewport.Model
-	loadingALB    bool
-	loadingRDS    bool
-	loadingEC2    bool
-	loadingECS    bool
-	loadingSQS    bool
-	loadBalancers []alb.LoadBalancerSummary
-	dbInstances   []rds.DBInstanceSummary
-	ec2Instances  []ec2.InstanceSummary
-	ecsServices   []ecs.ServiceSummary
-	sqsQueues     []sqs.QueueSummary
-	albErr        error
-	rdsErr        error
-	ec2Err        error
-	ecsErr        error
-	sqsErr        error
-	width         int
-	height        int
-	showALB       bool
-	showRDS       bool
-	showEC2       bool
-	showECS       bool
-	showSQS       bool
-	region        string
-	activeTab     int
-	tabs          []string
-	lastRefresh   time.Time
+	spinner                 spinner.Model
+	viewport                viewport.Model
+	splitViewport           viewport.Model // Scrolls the tab pinned to the left pane while the screen is split
+	split                   bool           // Whether the screen is split, see toggleSplit
+	splitTab                int            // Index of the tab pinned to the left pane
+	loadingALB              bool
+	loadingRDS              bool
+	loadingEC2              bool
+	loadingEBS              bool
+	loadingVPC              bool
+	loadingECS              bool
+	loadingECR              bool
+	loadingAPIGateway       bool
+	loadingCost             bool
+	loadingSQS              bool
+	loadingSSM              bool
+	loadingDNS              bool
+	loadingDR               bool
+	loadingSNS              bool
+	loadingLambda           bool
+	loadingCloudFront       bool
+	loadingFindings         bool
+	loadingProbes           bool
+	loadingHygiene          bool
+	loadBalancers           []alb.LoadBalancerSummary
+	dbInstances             []rds.DBInstanceSummary
+	ec2Instances            []ec2.InstanceSummary
+	ebsVolumes              []ebs.VolumeSummary
+	vpcs                    []vpcpkg.VPCSummary
+	ecsServices             []ecs.ServiceSummary
+	ecrRepositories         []ecrpkg.RepositorySummary
+	albTotal                int // Number of load balancers in the account, of which the first are loaded
+	ecrTotal                int // Number of repositories in the account, of which the first are loaded
+	apiGatewayAPIs          []apigatewaypkg.APISummary
+	costSummary             *costpkg.Summary
+	sqsQueues               []sqs.QueueSummary
+	lagIndicators           lag.Indicators
+	lagErrs                 []error
+	ssmInstances            []ssm.InstanceSummary
+	dnsRecords              []dns.RecordSummary
+	drResources             []dr.ResourceSummary
+	snsTopics               []sns.TopicSummary
+	lambdaFunctions         []lambdapkg.FunctionSummary
+	cloudfrontDistributions []cloudfrontpkg.DistributionSummary
+	findings                []findingspkg.Finding
+	probeResults            []probepkg.Result
+	hygieneResources        []hygienepkg.Resource
+	albErrs                 []error
+	rdsErrs                 []error
+	ec2Errs                 []error
+	ebsErrs                 []error
+	vpcErrs                 []error
+	ecsErr                  error
+	ecsScheduled            []ecs.ScheduledTask
+	ecsErrs                 []error // Scheduled tasks and Container Insights metrics that failed to load
+	ecrErrs                 []error
+	apiGatewayErrs          []error
+	costErrs                []error
+	sqsErrs                 []error
+	ssmErrs                 []error
+	dnsErrs                 []error
+	drErrs                  []error
+	snsErrs                 []error
+	lambdaErr               error
+	cloudfrontErr           error
+	findingsErrs            []error
+	hygieneErrs             []error
+	width                   int
+	height                  int
+	region                  string
+	activeTab               int
+	tabs                    []tab
+	lastRefresh             time.Time
+	nextRefresh             time.Time       // When the auto-refresh fires next
+	identity                config.Identity // Who the credentials belong to, once resolved
+	credentialsExpire       time.Time       // When temporary credentials expire, zero otherwise
+	credentialsErr          error           // Why the credentials could not be retrieved
+	credentialsRenewedAt    time.Time       // Calls failing on expired credentials before are ignored
+	ssoSigningIn            bool
+	ssoAuthorization        *config.DeviceAuthorization // Sign-in waiting for approval in a browser
+	ssoErr                  error
+	interval                time.Duration
+	embedded                bool
+	ctx                     context.Context
+	timeout                 time.Duration
+	fetches                 map[string]context.CancelFunc // Cancels the in-flight fetch of each service
+	limiters                *config.Limiters
+	restoredAt              time.Time
+	restoredAccount         string // Account of the restored session, empty when unknown
+	demo                    bool
+	asciiSymbols            bool
+	queuePrefix             string
+	containerInsights       bool
+	showHygiene             bool          // Whether the Overview tab has a Hygiene section
+	stoppedDays             int           // Days an EC2 instance is stopped before the hygiene check flags it
+	window                  time.Duration // How far back the metric graphs go
+	windowPrefix            string        // "2" while 24 is being typed
+	pool                    *common.Pool
+	cache                   *cache.Cache
+	cachedAt                map[string]time.Time // When the cached data shown was stored, by service
+	loadedAt                map[string]time.Time // When the data shown was fetched from AWS, by service
+	bypassCache             bool
+	routeInput              textinput.Model // Prompt of the listener rule simulator, focused while typing
+	routeRequest            *alb.Request    // Last simulated request, shown on the Load Balancers tab
+	routeErr                error
+	allowActions            bool
+	lambdaSelected          int            // Index of the function selected on the Lambda tab
+	payloadEditor           textarea.Model // Payload of a test invocation, focused while editing
+	payloadFunction         string         // Name of the function the payload editor was opened for
+	invokingLambda          bool
+	lambdaInvocation        *lambdapkg.InvocationResult // Result of the last test invocation
+	lambdaInvokeErr         error
+	ecsSelected             int             // Index of the service selected on the ECS tab
+	taskInput               textinput.Model // Command of a one-off task, focused while typing
+	taskCluster             string          // Cluster of the service the task prompt was opened for
+	taskService             string          // Name of the service the task prompt was opened for
+	startingTask            bool
+	ecsTask                 *ecs.TaskSummary // One-off task tracked until it stops
+	ecsTaskErr              error
+	scaleInput              textinput.Model // Desired count of the selected service, focused while typing
+	ecsConfirming           *ecsUpdate      // Update of a service waiting for confirmation
+	ecsUpdating             bool            // Whether an update of a service is being requested
+	ecsUpdateNote           string          // Outcome of the last update
+	ecsUpdateErr            error
+	ecsExecService          ecs.ServiceSummary // Service whose containers the exec menu lists
+	ecsExecTargets          []ecs.ExecTarget   // Containers of the exec menu, nil when it is closed
+	ecsExecSelected         int                // Index of the container selected in the menu
+	ecsExecuting            bool               // Whether an ECS Exec session is starting or under way
+	ecsExecNote             string             // Outcome of the last session
+	ecsExecErr              error
+	auditLog                *audit.Log
+	toast                   string          // Shown in place of the help text, e.g. to confirm a copy
+	toastID                 int             // Identifies the last toast, so that only its tick hides it
+	sqsSelected             int             // Index of the queue selected on the SQS tab, in table order
+	purgeInput              textinput.Model // Name of the queue to purge, focused while typing
+	purgingQueue            bool
+	purgeNote               string // Outcome of the last purge
+	purgeErr                error
+	rdsSelected             int        // Index of the instance selected on the RDS tab, in the order of its table
+	cloudfrontSelected      int        // Index of the distribution selected on the CloudFront tab
+	ec2Selected             int        // Index of the instance selected on the EC2 tab, in table order
+	ec2Menu                 bool       // Whether the action menu of the selected instance is open
+	ec2MenuInstance         string     // ID of the instance the menu or confirmation is for
+	ec2MenuSelected         int        // Index of the action selected in the menu
+	ec2Confirming           ec2.Action // Action waiting for confirmation, empty when none
+	ec2Acting               bool       // Whether an action is being requested
+	ec2Connecting           bool       // Whether a session is starting or under way
+	ec2ActionNote           string     // Outcome of the last action
+	ec2ActionErr            error
+	albSelected             string                   // Key of the target selected on the Load Balancers tab
+	albDeregistered         map[string]alb.TargetRef // Targets deregistered this session, by key
+	albConfirming           alb.TargetAction         // Target action waiting for confirmation, empty when none
+	albConfirmTarget        alb.TargetRef            // Target the action waiting for confirmation is for
+	albActing               bool                     // Whether a target action is being requested
+	albActionNote           string                   // Outcome of the last target action
+	albActionErr            error
+	invalidationInput       textinput.Model // Paths of an invalidation, focused while typing
+	invalidationTarget      string          // ID of the distribution the invalidation prompt was opened for
+	creatingInvalidation    bool
+	invalidation            *cloudfrontpkg.InvalidationSummary // Invalidation tracked until it completes
+	invalidationErr         error
+	providerResults         map[string]providerResult // Outcome of the last load of each provider, by name
+	runbooks                []runbook.Runbook
+	runbookSelected         int // Index of the runbook selected on the Runbooks tab
+	confirmingRunbook       bool
+	runningRunbook          bool
+	ranRunbook              runbook.Runbook      // Runbook of the last run
+	runbookResults          []runbook.StepResult // Outcome of each step of the last run
+	runbookErr              error
+	loadingMetrics          bool // Whether a fetch of the CloudWatch tab is in flight
+	metricNamespaces        []metricspkg.Namespace
+	namespacesTruncated     bool
+	metricNamespaceSelected int    // Index of the namespace selected on the CloudWatch tab
+	metricNamespace         string // Namespace browsed on the CloudWatch tab, empty while choosing one
+	metricList              []metricspkg.Metric
+	metricListTruncated     bool
+	metricSelected          int                       // Index of the metric selected in the browsed namespace
+	metricPlot              *metricspkg.Pin           // Metric plotted on the CloudWatch tab, nil when none is
+	metricPlotResult        *cloudwatchmetrics.Result // Series of the plotted metric once loaded
+	metricsErr              error
+	pins                    []metricspkg.Pin                    // Metrics pinned to the Custom Metrics tab
+	pinsFile                string                              // File the pins are saved to, empty to keep them for the session only
+	sealer                  *seal.Sealer                        // Encrypts the pins file, nil to write it in the clear
+	pinNote                 string                              // Outcome of pinning the plotted metric
+	pinResults              map[string]cloudwatchmetrics.Result // Series of each pinned metric, by Pin.Label
+	pinSelected             int                                 // Index of the pin selected on the Custom Metrics tab
+	loadingPins             bool
+	pinsLoadErr             error
+	pinsSaveErr             error
+	paneService             string   // Tab the resource pane is open on, empty when it is closed
+	pane                    paneKind // What the resource pane shows
+	paneResource            resource // Resource the pane shows the error logs or related events of
+	loadingPane             bool
+	logSources              []logspkg.Source
+	logEvents               []logspkg.Event
+	relatedEvents           []eventspkg.Event
+	queueMessages           []sqs.Message
+	paneErrs                []error
+	sortKeys                map[string]int // Index of the column each table is sorted by, by service
+	maxResults              int            // Number of resources each tab shows at first and + adds, 0 for all
+	limits                  map[string]int // Number of resources shown after pressing +, by service
+	plain                   bool           // Render the plain formatters instead of tables, for -no-tui
+	diagnostics             *diagnostics.Stats
+	diagnosticsGeneration   int // Incremented each time the Diagnostics tab is shown or hidden
+	prober                  *probepkg.Client
+	probePorts              []int32         // Ports probed on the public IPs of instances
+	showEventLog            bool            // Whether the event log pane is shown below the active tab
+	showKeymap              bool            // Whether the help overlay is shown in place of the active tab
+	eventLog                []eventLogEntry // State transitions noticed since the start, oldest first
+	eventLogNote            string          // Outcome of writing the event log to a file
+	alertRules              []alerts.Rule
+	alertBreaches           map[string]alerts.Breach // Breaches of the alert rules by key, as of the last loads
+	alertFlashUntil         map[string]time.Time     // When the tab of each service with a new breach stops flashing
+	alertFlashing           bool                     // Whether the flash ticker runs
+	alertFlashOn            bool                     // Whether the flashing tabs are in their flashed style
+
+	awsConfig *sharedConfig // AWS configuration shared by the clients of all services
+	prefetch  tea.Cmd       // First loads started by New, delivered by Init
+}
+
+// New creates the AWS overview as a bubbletea component configured by opts.
+// It starts loading the services right away, before the program runs.
+func New(opts Options) tea.Model {
+	m := NewModel(opts)
+	m.prefetch = prestart(m.refreshData())
+	return m
 }
 
 // NewModel creates a new UI model
-func NewModel(showALB, showRDS, showEC2, showECS, showSQS bool, region string) Model {
-	// Create tabs list
-	tabs := []string{"Overview"}
-	if showALB {
-		tabs = append(tabs, "Load Balancers")
-	}
-	if showRDS {
-		tabs = append(tabs, "RDS Instances")
-	}
-	if showEC2 {
-		tabs = append(tabs, "EC2 Instances")
-	}
-	if showECS {
-		tabs = append(tabs, "ECS Services")
-	}
-	if showSQS {
-		tabs = append(tabs, "SQS Queues")
-	}
+func NewModel(opts Options) Model {
+	opts = opts.withDefaults()
+	applyTheme(opts.Theme, opts.NoColor)
+	common.SetGraphStyle(opts.Graphs)
 
 	// Create a fancier spinner with custom styling
 	s := spinner.New()
@@ -138,62 +297,221 @@ func NewModel(showALB, showRDS, showEC2, showECS, showSQS bool, region string) M
 	// Initialize viewport with default size (will be adjusted when window size is known)
 	vp := viewport.New(80, 20)
 
-	return Model{
-		spinner:     s,
-		viewport:    vp,
-		loadingALB:  showALB,
-		loadingRDS:  showRDS,
-		loadingEC2:  showEC2,
-		loadingECS:  showECS,
-		loadingSQS:  showSQS,
-		showALB:     showALB,
-		showRDS:     showRDS,
-		showEC2:     showEC2,
-		showECS:     showECS,
-		showSQS:     showSQS,
-		region:      region,
-		activeTab:   0,
-		tabs:        tabs,
-		lastRefresh: time.Now(),
+	m := Model{
+		spinner:              s,
+		viewport:             vp,
+		splitViewport:        viewport.New(80, 20),
+		loadingALB:           opts.ShowALB,
+		loadingRDS:           opts.ShowRDS,
+		loadingEC2:           opts.ShowEC2,
+		loadingEBS:           opts.ShowEBS,
+		loadingVPC:           opts.ShowVPC,
+		loadingECS:           opts.ShowECS,
+		loadingECR:           opts.ShowECR,
+		loadingAPIGateway:    opts.ShowAPIGateway,
+		loadingCost:          opts.ShowCost,
+		loadingSQS:           opts.ShowSQS,
+		loadingSSM:           opts.ShowSSM,
+		loadingDNS:           opts.ShowDNS,
+		loadingDR:            opts.ShowDR,
+		loadingSNS:           opts.ShowSNS,
+		loadingLambda:        opts.ShowLambda,
+		loadingCloudFront:    opts.ShowCloudFront,
+		loadingFindings:      opts.ShowFindings,
+		loadingProbes:        opts.ShowProbes,
+		loadingHygiene:       opts.ShowHygiene,
+		region:               opts.Region,
+		activeTab:            0,
+		tabs:                 enabledTabs(opts),
+		lastRefresh:          time.Now(),
+		nextRefresh:          time.Now().Add(opts.RefreshInterval),
+		credentialsRenewedAt: time.Now(),
+		interval:             opts.RefreshInterval,
+		embedded:             opts.Embedded,
+		ctx:                  opts.Context,
+		timeout:              opts.Timeout,
+		fetches:              make(map[string]context.CancelFunc),
+		limiters:             config.NewLimiters(opts.RateLimits),
+		demo:                 opts.Demo,
+		asciiSymbols:         opts.ASCIISymbols,
+		queuePrefix:          opts.QueuePrefix,
+		containerInsights:    opts.ContainerInsights,
+		showHygiene:          opts.ShowHygiene,
+		stoppedDays:          opts.StoppedDays,
+		window:               opts.MetricsWindow,
+		pool:                 common.NewPool(opts.MaxConcurrency).WithoutRetries(), // The shared retryer retries throttled calls
+		cache:                cache.New(opts.CacheTTL, opts.CacheDir, opts.Sealer),
+		cachedAt:             make(map[string]time.Time),
+		loadedAt:             make(map[string]time.Time),
+		routeInput:           newRouteInput(),
+		albDeregistered:      make(map[string]alb.TargetRef),
+		allowActions:         opts.AllowActions,
+		payloadEditor:        newPayloadEditor(),
+		taskInput:            newTaskInput(),
+		scaleInput:           newScaleInput(),
+		purgeInput:           newPurgeInput(),
+		auditLog:             opts.AuditLog,
+		invalidationInput:    newInvalidationInput(),
+		providerResults:      make(map[string]providerResult),
+		runbooks:             opts.Runbooks,
+		alertRules:           opts.Alerts,
+		alertBreaches:        make(map[string]alerts.Breach),
+		alertFlashUntil:      make(map[string]time.Time),
+		loadingMetrics:       opts.ShowMetrics,
+		pins:                 opts.Pins,
+		pinsFile:             opts.PinsFile,
+		sealer:               opts.Sealer,
+		pinResults:           make(map[string]cloudwatchmetrics.Result),
+		loadingPins:          opts.ShowMetrics || len(opts.Pins) > 0,
+		sortKeys:             make(map[string]int),
+		maxResults:           opts.MaxResults,
+		limits:               make(map[string]int),
+		diagnostics:          diagnostics.New(),
+		prober:               newProber(opts),
+		probePorts:           opts.ProbePorts,
+		awsConfig:            newSharedConfig(opts.Context, opts.Endpoints, config.NewRetryer(opts.Retry)),
+	}
+	m.limiters.Instrument(m.diagnostics.CallMiddleware, logging.CallMiddleware, tracing.CallMiddleware)
+
+	// Demo data is always reported for the fixture region and never mixed
+	// with a saved session of real resources
+	if opts.Demo {
+		m.region = demo.Region
+		return m
+	}
+
+	// Only restore sessions saved for the profile and region this session
+	// will query. The account is checked once the identity is resolved.
+	resolvedRegion := config.NewConfig(opts.Region).Region
+	if opts.Restore != nil && opts.Restore.Profile == getAWSProfile() &&
+		(resolvedRegion == "" || resolvedRegion == opts.Restore.Region) {
+		m.restore(*opts.Restore)
 	}
+
+	// Resolve the credentials while the UI starts rather than with the
+	// first call of each service
+	m.prewarm()
+
+	return m
 }
 
 // Init initializes the model and triggers data loading
 func (m Model) Init() tea.Cmd {
-	cmds := []tea.Cmd{
-		m.spinner.Tick,
-		refreshTimer(),
+	load := m.prefetch
+	if load == nil {
+		load = m.refreshData()
 	}
+	return tea.Batch(
+		m.spinner.Tick,
+		refreshTimer(m.interval),
+		m.loadIdentity(),
+		m.loadCredentials(),
+		load,
+	)
+}
 
-	if m.showALB {
-		cmds = append(cmds, m.loadALBData())
-	}
+// Update handles various events and messages
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
 
-	if m.showRDS {
-		cmds = append(cmds, m.loadRDSData())
+	// While the route prompt has focus it receives the keys, except ctrl+c
+	if m.routeInput.Focused() {
+		if key, ok := msg.(tea.KeyMsg); ok && key.String() != "ctrl+c" {
+			return m.updateRouteInput(key)
+		}
+		var cmd tea.Cmd
+		m.routeInput, cmd = m.routeInput.Update(msg)
+		cmds = append(cmds, cmd)
 	}
 
-	if m.showEC2 {
-		cmds = append(cmds, m.loadEC2Data())
+	// Likewise for the command prompt of one-off ECS tasks
+	if m.taskInput.Focused() {
+		if key, ok := msg.(tea.KeyMsg); ok && key.String() != "ctrl+c" {
+			return m.updateTaskInput(key)
+		}
+		var cmd tea.Cmd
+		m.taskInput, cmd = m.taskInput.Update(msg)
+		cmds = append(cmds, cmd)
 	}
 
-	if m.showECS {
-		cmds = append(cmds, m.loadECSData())
+	// Likewise for the desired count prompt of ECS services
+	if m.scaleInput.Focused() {
+		if key, ok := msg.(tea.KeyMsg); ok && key.String() != "ctrl+c" {
+			return m.updateScaleInput(key)
+		}
+		var cmd tea.Cmd
+		m.scaleInput, cmd = m.scaleInput.Update(msg)
+		cmds = append(cmds, cmd)
 	}
 
-	if m.showSQS {
-		cmds = append(cmds, m.loadSQSData())
+	// Likewise for the name prompt confirming the purge of a queue
+	if m.purgeInput.Focused() {
+		if key, ok := msg.(tea.KeyMsg); ok && key.String() != "ctrl+c" {
+			return m.updatePurgeInput(key)
+		}
+		var cmd tea.Cmd
+		m.purgeInput, cmd = m.purgeInput.Update(msg)
+		cmds = append(cmds, cmd)
 	}
 
-	return tea.Batch(cmds...)
-}
+	// Likewise for the path prompt of CloudFront invalidations
+	if m.invalidationInput.Focused() {
+		if key, ok := msg.(tea.KeyMsg); ok && key.String() != "ctrl+c" {
+			return m.updateInvalidationInput(key)
+		}
+		var cmd tea.Cmd
+		m.invalidationInput, cmd = m.invalidationInput.Update(msg)
+		cmds = append(cmds, cmd)
+	}
 
-// Update handles various events and messages
-func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	var cmds []tea.Cmd
+	// Likewise for the payload editor of Lambda test invocations
+	if m.payloadEditor.Focused() {
+		if key, ok := msg.(tea.KeyMsg); ok && key.String() != "ctrl+c" {
+			return m.updatePayloadEditor(key)
+		}
+		var cmd tea.Cmd
+		m.payloadEditor, cmd = m.payloadEditor.Update(msg)
+		cmds = append(cmds, cmd)
+	}
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// Quitting is left to the host program when embedded
+		if m.embedded && (msg.String() == "q" || msg.String() == "ctrl+c") {
+			break
+		}
+
+		// The 2 of 24 only counts when the very next key is the 4
+		windowPrefix := m.windowPrefix
+		m.windowPrefix = ""
+
+		// The help overlay covers everything else, so its keys come first
+		if m.showKeymap {
+			if updated, cmd, handled := m.updateKeymapKeys(msg); handled {
+				return updated, tea.Batch(append(cmds, cmd)...)
+			}
+		}
+
+		// J and K scroll the pinned pane of a split screen
+		if updated, handled := m.updateSplitKeys(msg); handled {
+			return updated, tea.Batch(cmds...)
+		}
+
+		// The resource pane covers its tab, so its keys come first
+		if m.currentTab().selected != nil {
+			if updated, cmd, handled := m.updatePaneKeys(msg); handled {
+				return updated, tea.Batch(append(cmds, cmd)...)
+			}
+		}
+
+		// Keys of the active tab, such as selecting a function on the Lambda
+		// tab, take precedence over scrolling
+		if keys := m.currentTab().keys; keys != nil {
+			if updated, cmd, handled := keys(m, msg); handled {
+				return updated, tea.Batch(append(cmds, cmd)...)
+			}
+		}
+
 		// Let viewport handle keys first if not a tab-switching key
 		if msg.String() != "tab" && msg.String() != "right" && msg.String() != "l" &&
 			msg.String() != "shift+tab" && msg.String() != "left" && msg.String() != "h" &&
@@ -208,6 +526,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		switch msg.String() {
 		case "q", "ctrl+c":
+			m.cancelFetches()
 			return m, tea.Quit
 		case "tab", "right", "l":
 			// Cycle to next tab
@@ -219,64 +538,254 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.activeTab = (m.activeTab - 1 + len(m.tabs)) % len(m.tabs)
 			// Update content for the new tab
 			m.updateViewportContent()
-		case "r": // Manual refresh
-			cmds = append(cmds, m.refreshData())
+		case "r": // Manual refresh of the active tab
+			m.retryCredentials()
+			cmds = append(cmds, m.fresh().refreshTab())
+		case "R": // Manual refresh of all tabs
+			m.retryCredentials()
+			cmds = append(cmds, m.fresh().refreshData(), m.loadCredentials())
+		case "1", "2", "3", "4", "6": // Show the metrics of the last 1, 3, 6 or 24 hours
+			var cmd tea.Cmd
+			m, cmd = m.updateWindowKey(windowPrefix, msg.String())
+			cmds = append(cmds, cmd)
+		case "A": // Renew expired credentials by signing in with AWS SSO
+			var cmd tea.Cmd
+			m, cmd = m.reauthenticate()
+			cmds = append(cmds, cmd)
+		case "?": // Show the help overlay
+			m = m.toggleKeymap()
+		case "D": // Show or hide the Diagnostics tab
+			var cmd tea.Cmd
+			m, cmd = m.toggleDiagnostics()
+			cmds = append(cmds, cmd)
+		case "|": // Split the screen or end the split
+			var cmd tea.Cmd
+			m, cmd = m.toggleSplit()
+			cmds = append(cmds, cmd)
+		case "!": // Show or hide the event log pane
+			m = m.toggleEventLog()
+		case "w": // Write the event log to a file while it is shown
+			if m.showEventLog {
+				m = m.writeEventLog()
+			}
+		case "X": // Export the loaded resources as CSV
+			var cmd tea.Cmd
+			m, cmd = m.exportCSV()
+			cmds = append(cmds, cmd)
+		case "+": // Show more resources on a tab capped by -max-results
+			var cmd tea.Cmd
+			m, cmd = m.showMore()
+			cmds = append(cmds, cmd)
+		case "y": // Copy the identifier of the selected resource
+			var cmd tea.Cmd
+			m, cmd = m.copyIdentifier()
+			cmds = append(cmds, cmd)
+		case "o": // Open the selected resource in the AWS console
+			var cmd tea.Cmd
+			m, cmd = m.openConsole()
+			cmds = append(cmds, cmd)
+		case "s": // Sort the table of the active tab by the next column
+			if columns := m.currentTab().sortColumns; columns > 0 {
+				service := m.currentTab().service
+				m.sortKeys[service] = (m.sortKeys[service] + 1) % columns
+				m.updateViewportContent()
+			}
 		}
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-
-		// Update viewport height and width
-		headerHeight := 12                                             // Increased space for header elements
-		footerHeight := 1                                              // Help text
-		m.viewport.Width = m.width - 4                                 // Account for padding
-		m.viewport.Height = m.height - headerHeight - footerHeight - 2 // Account for margins
+		m.resize()
 
 		// Update content for the viewport with the new dimensions
 		m.updateViewportContent()
 
+	case RefreshMsg:
+		cmds = append(cmds, m.fresh().refreshData())
+
+	case diagnosticsTickMsg:
+		var cmd tea.Cmd
+		m, cmd = m.updateDiagnostics(msg)
+		cmds = append(cmds, cmd)
+
 	case spinner.TickMsg:
 		var cmd tea.Cmd
 		m.spinner, cmd = m.spinner.Update(msg)
 		cmds = append(cmds, cmd)
+		// The sign-in prompt animates while waiting for the approval
+		if m.ssoSigningIn {
+			m.updateViewportContent()
+		}
+
+	case alertFlashMsg:
+		var cmd tea.Cmd
+		m, cmd = m.updateAlertFlash()
+		cmds = append(cmds, cmd)
+
+	case credentialsLoadedMsg:
+		m.credentialsExpire = msg.expires
+		m.credentialsErr = msg.err
+		m.updateViewportContent()
+
+	case reauthEndedMsg:
+		var cmd tea.Cmd
+		m, cmd = m.updateReauthEnded(msg)
+		cmds = append(cmds, cmd)
+
+	case ssoAuthorizationMsg:
+		var cmd tea.Cmd
+		m, cmd = m.updateSSOAuthorization(msg)
+		cmds = append(cmds, cmd)
+
+	case ssoLoginEndedMsg:
+		var cmd tea.Cmd
+		m, cmd = m.updateSSOLoginEnded(msg)
+		cmds = append(cmds, cmd)
+
+	case identityLoadedMsg:
+		if msg.err == nil {
+			m.identity = msg.identity
+			if m.restoredAccount != "" && m.restoredAccount != msg.identity.Account {
+				m.forgetRestored()
+				m.updateViewportContent()
+			}
+		}
+
+	case alertNotifiedMsg:
+		if msg.err != nil {
+			m.recordEvents(msg.service, []string{fmt.Sprintf("failed to notify %s breached by %s: %v", msg.breach.Rule.Label(), msg.breach.Resource, msg.err)})
+		}
 
 	case refreshTimerMsg:
 		// Update last refresh time
 		m.lastRefresh = time.Now()
 
-		// Start data refresh
-		if !m.loadingALB && !m.loadingRDS && !m.loadingEC2 && !m.loadingECS && !m.loadingSQS {
-			cmds = append(cmds, m.refreshData())
-		}
+		// Start data refresh, leaving services that are still loading alone
+		cmds = append(cmds, m.refreshIdle())
 
 		// Schedule next refresh
-		cmds = append(cmds, refreshTimer())
+		m.nextRefresh = m.lastRefresh.Add(m.interval)
+		cmds = append(cmds, refreshTimer(m.interval), m.loadCredentials())
+
+	case partialMsg:
+		// Handle the partial results as they come, then wait for the next
+		updated, cmd := m.Update(msg.msg)
+		return updated, tea.Batch(cmd, msg.next)
+
+	// Partial results only fill tabs that have nothing to show yet; a
+	// refresh keeps showing the previous data until it completes
+	case albPartialMsg:
+		if m.loadingALB {
+			m.loadBalancers = msg.loadBalancers
+			m.updateViewportContent()
+		}
+
+	case ecsPartialMsg:
+		if m.loadingECS {
+			m.ecsServices = msg.services
+			ecs.SortServices(m.ecsServices)
+			m.ecsSelected = min(m.ecsSelected, max(0, len(m.ecsServices)-1))
+			m.updateViewportContent()
+		}
 
 	case albDataLoadedMsg:
+		m.restoredAt = time.Time{}
+		m.logChanges("alb", len(msg.errs) > 0, changes.LoadBalancers(m.loadBalancers, msg.loadBalancers))
+		if len(msg.errs) == 0 {
+			cmds = append(cmds, m.checkAlerts("alb", alerts.Data{LoadBalancers: msg.loadBalancers}))
+		}
+		m.loaded("alb", msg.cachedAt)
 		m.loadingALB = false
 		m.loadBalancers = msg.loadBalancers
-		m.albErr = msg.err
+		m.albTotal = max(msg.total, len(msg.loadBalancers))
+		m.albErrs = msg.errs
 		// Update region if it was empty and we got it from AWS config
 		if m.region == "" && msg.region != "" {
 			m.region = msg.region
 		}
 		m.updateViewportContent()
+		cmds = append(cmds, m.loadFindingsAfterInventory(), m.loadProbesAfterInventory(), m.loadHygieneAfterInventory())
 
 	case rdsDataLoadedMsg:
+		m.restoredAt = time.Time{}
+		m.logChanges("rds", len(msg.errs) > 0, changes.DBInstances(m.dbInstances, msg.dbInstances))
+		m.loaded("rds", msg.cachedAt)
 		m.loadingRDS = false
 		m.dbInstances = msg.dbInstances
-		m.rdsErr = msg.err
+		m.rdsErrs = msg.errs
+		m.rdsSelected = min(m.rdsSelected, max(0, len(m.dbInstances)-1))
 		// Update region if it was empty and we got it from AWS config
 		if m.region == "" && msg.region != "" {
 			m.region = msg.region
 		}
 		m.updateViewportContent()
+		cmds = append(cmds, m.loadFindingsAfterInventory(), m.loadHygieneAfterInventory())
 
 	case ec2DataLoadedMsg:
+		m.restoredAt = time.Time{}
+		m.logChanges("ec2", len(msg.errs) > 0, changes.Instances(m.ec2Instances, msg.instances))
+		m.loaded("ec2", msg.cachedAt)
 		m.loadingEC2 = false
 		m.ec2Instances = msg.instances
-		m.ec2Err = msg.err
+		m.ec2Errs = msg.errs
+		m.ec2Selected = min(m.ec2Selected, max(0, len(m.ec2Instances)-1))
+		// Update region if it was empty and we got it from AWS config
+		if m.region == "" && msg.region != "" {
+			m.region = msg.region
+		}
+		m.updateViewportContent()
+		cmds = append(cmds, m.loadFindingsAfterInventory(), m.loadProbesAfterInventory(), m.loadHygieneAfterInventory())
+
+	case findingsDataLoadedMsg:
+		m.restoredAt = time.Time{}
+		m.loaded("findings", msg.cachedAt)
+		m.loadingFindings = false
+		m.findings = msg.findings
+		m.findingsErrs = msg.errs
+		// Update region if it was empty and we got it from AWS config
+		if m.region == "" && msg.region != "" {
+			m.region = msg.region
+		}
+		m.updateViewportContent()
+
+	case hygieneDataLoadedMsg:
+		m.restoredAt = time.Time{}
+		m.loaded("hygiene", msg.cachedAt)
+		m.loadingHygiene = false
+		m.hygieneResources = msg.resources
+		m.hygieneErrs = msg.errs
+		// Update region if it was empty and we got it from AWS config
+		if m.region == "" && msg.region != "" {
+			m.region = msg.region
+		}
+		m.updateViewportContent()
+
+	case probesDataLoadedMsg:
+		m.restoredAt = time.Time{}
+		m.logChanges("probe", false, changes.Probes(m.probeResults, msg.results))
+		m.loaded("probe", time.Time{})
+		m.loadingProbes = false
+		m.probeResults = msg.results
+		m.updateViewportContent()
+
+	case ebsDataLoadedMsg:
+		m.restoredAt = time.Time{}
+		m.loaded("ebs", msg.cachedAt)
+		m.loadingEBS = false
+		m.ebsVolumes = msg.volumes
+		m.ebsErrs = msg.errs
+		// Update region if it was empty and we got it from AWS config
+		if m.region == "" && msg.region != "" {
+			m.region = msg.region
+		}
+		m.updateViewportContent()
+
+	case vpcDataLoadedMsg:
+		m.restoredAt = time.Time{}
+		m.loaded("vpc", msg.cachedAt)
+		m.loadingVPC = false
+		m.vpcs = msg.vpcs
+		m.vpcErrs = msg.errs
 		// Update region if it was empty and we got it from AWS config
 		if m.region == "" && msg.region != "" {
 			m.region = msg.region
@@ -284,9 +793,60 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.updateViewportContent()
 
 	case ecsDataLoadedMsg:
+		m.restoredAt = time.Time{}
+		m.logChanges("ecs", msg.err != nil, changes.Services(m.ecsServices, msg.services))
+		if msg.err == nil {
+			cmds = append(cmds, m.checkAlerts("ecs", alerts.Data{Services: msg.services}))
+		}
+		m.loaded("ecs", msg.cachedAt)
 		m.loadingECS = false
 		m.ecsServices = msg.services
 		m.ecsErr = msg.err
+		m.ecsScheduled = msg.scheduled
+		m.ecsErrs = msg.errs
+		ecs.SortServices(m.ecsServices)
+		m.ecsSelected = min(m.ecsSelected, max(0, len(m.ecsServices)-1))
+		// Update region if it was empty and we got it from AWS config
+		if m.region == "" && msg.region != "" {
+			m.region = msg.region
+		}
+		m.updateViewportContent()
+
+	case ecrDataLoadedMsg:
+		m.restoredAt = time.Time{}
+		m.loaded("ecr", msg.cachedAt)
+		m.loadingECR = false
+		m.ecrRepositories = msg.repositories
+		m.ecrTotal = max(msg.total, len(msg.repositories))
+		m.ecrErrs = msg.errs
+		// Update region if it was empty and we got it from AWS config
+		if m.region == "" && msg.region != "" {
+			m.region = msg.region
+		}
+		m.updateViewportContent()
+
+	case apiGatewayDataLoadedMsg:
+		m.restoredAt = time.Time{}
+		m.loaded("apigw", msg.cachedAt)
+		m.loadingAPIGateway = false
+		m.apiGatewayAPIs = msg.apis
+		m.apiGatewayErrs = msg.errs
+		// Update region if it was empty and we got it from AWS config
+		if m.region == "" && msg.region != "" {
+			m.region = msg.region
+		}
+		m.updateViewportContent()
+
+	case costDataLoadedMsg:
+		m.restoredAt = time.Time{}
+		m.loaded("cost", msg.cachedAt)
+		m.loadingCost = false
+		// Keep the last spend when a refresh fails, as the next one is up
+		// to an hour away
+		if msg.summary != nil {
+			m.costSummary = msg.summary
+		}
+		m.costErrs = msg.errs
 		// Update region if it was empty and we got it from AWS config
 		if m.region == "" && msg.region != "" {
 			m.region = msg.region
@@ -294,14 +854,196 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.updateViewportContent()
 
 	case sqsDataLoadedMsg:
+		m.restoredAt = time.Time{}
+		if len(msg.errs) == 0 {
+			cmds = append(cmds, m.checkAlerts("sqs", alerts.Data{Queues: msg.queues}))
+		}
+		m.loaded("sqs", msg.cachedAt)
 		m.loadingSQS = false
 		m.sqsQueues = msg.queues
-		m.sqsErr = msg.err
+		m.sqsErrs = msg.errs
+		m.sqsSelected = min(m.sqsSelected, max(0, len(m.sqsQueues)-1))
 		// Update region if it was empty and we got it from AWS config
 		if m.region == "" && msg.region != "" {
 			m.region = msg.region
 		}
 		m.updateViewportContent()
+		cmds = append(cmds, m.loadHygieneAfterInventory())
+
+	case ssmDataLoadedMsg:
+		m.restoredAt = time.Time{}
+		m.loaded("ssm", msg.cachedAt)
+		m.loadingSSM = false
+		m.ssmInstances = msg.instances
+		m.ssmErrs = msg.errs
+		// Update region if it was empty and we got it from AWS config
+		if m.region == "" && msg.region != "" {
+			m.region = msg.region
+		}
+		m.updateViewportContent()
+
+	case dnsDataLoadedMsg:
+		m.restoredAt = time.Time{}
+		m.loaded("dns", msg.cachedAt)
+		m.loadingDNS = false
+		m.dnsRecords = msg.records
+		m.dnsErrs = msg.errs
+		// Update region if it was empty and we got it from AWS config
+		if m.region == "" && msg.region != "" {
+			m.region = msg.region
+		}
+		m.updateViewportContent()
+
+	case drDataLoadedMsg:
+		m.restoredAt = time.Time{}
+		m.loaded("dr", msg.cachedAt)
+		m.loadingDR = false
+		m.drResources = msg.resources
+		m.drErrs = msg.errs
+		// Update region if it was empty and we got it from AWS config
+		if m.region == "" && msg.region != "" {
+			m.region = msg.region
+		}
+		m.updateViewportContent()
+
+	case snsDataLoadedMsg:
+		m.restoredAt = time.Time{}
+		m.loaded("sns", msg.cachedAt)
+		m.loadingSNS = false
+		m.snsTopics = msg.topics
+		m.snsErrs = msg.errs
+		// Update region if it was empty and we got it from AWS config
+		if m.region == "" && msg.region != "" {
+			m.region = msg.region
+		}
+		m.updateViewportContent()
+
+	case lambdaDataLoadedMsg:
+		m.restoredAt = time.Time{}
+		m.loaded("lambda", msg.cachedAt)
+		m.loadingLambda = false
+		m.lambdaFunctions = msg.functions
+		m.lambdaErr = msg.err
+		m.lambdaSelected = min(m.lambdaSelected, max(0, len(m.lambdaFunctions)-1))
+		// Update region if it was empty and we got it from AWS config
+		if m.region == "" && msg.region != "" {
+			m.region = msg.region
+		}
+		m.updateViewportContent()
+
+	case cloudfrontDataLoadedMsg:
+		m.restoredAt = time.Time{}
+		m.loaded("cloudfront", msg.cachedAt)
+		m.loadingCloudFront = false
+		m.cloudfrontDistributions = msg.distributions
+		m.cloudfrontErr = msg.err
+		m.cloudfrontSelected = min(m.cloudfrontSelected, max(0, len(m.cloudfrontDistributions)-1))
+		// Update region if it was empty and we got it from AWS config
+		if m.region == "" && msg.region != "" {
+			m.region = msg.region
+		}
+		m.updateViewportContent()
+
+	case invalidationMsg:
+		var cmd tea.Cmd
+		m, cmd = m.updateInvalidation(msg)
+		cmds = append(cmds, cmd)
+
+	case ec2ActionMsg:
+		var cmd tea.Cmd
+		m, cmd = m.updateEC2Action(msg)
+		cmds = append(cmds, cmd)
+
+	case ecsExecTargetsMsg:
+		var cmd tea.Cmd
+		m, cmd = m.updateExecTargets(msg)
+		cmds = append(cmds, cmd)
+
+	case toastMsg:
+		if msg.id == m.toastID {
+			m.toast = ""
+		}
+
+	case sessionReadyMsg:
+		var cmd tea.Cmd
+		m, cmd = m.updateSessionReady(msg)
+		cmds = append(cmds, cmd)
+
+	case sessionEndedMsg:
+		var cmd tea.Cmd
+		m, cmd = m.updateSessionEnded(msg)
+		cmds = append(cmds, cmd)
+
+	case albTargetMsg:
+		var cmd tea.Cmd
+		m, cmd = m.updateTargetAction(msg)
+		cmds = append(cmds, cmd)
+
+	case invalidationPollMsg:
+		if m.invalidation != nil && m.invalidation.ID == msg.id {
+			cmds = append(cmds, m.getInvalidation(*m.invalidation))
+		}
+
+	case lagDataLoadedMsg:
+		m.restoredAt = time.Time{}
+		m.loaded("lag", msg.cachedAt)
+		m.lagIndicators = msg.indicators
+		m.lagErrs = msg.errs
+		// Update region if it was empty and we got it from AWS config
+		if m.region == "" && msg.region != "" {
+			m.region = msg.region
+		}
+		m.updateViewportContent()
+
+	case lambdaInvokedMsg:
+		m.invokingLambda = false
+		m.lambdaInvokeErr = msg.err
+		m.audit("lambda", audit.Entry{Action: "lambda:InvokeFunction", Resource: msg.function, Detail: "test invocation"}, msg.err)
+		m.lambdaInvocation = nil
+		if msg.err == nil {
+			m.lambdaInvocation = &msg.result
+		}
+		m.updateViewportContent()
+		m.viewport.GotoTop()
+
+	case ecsTaskMsg:
+		var cmd tea.Cmd
+		m, cmd = m.updateTask(msg)
+		cmds = append(cmds, cmd)
+
+	case ecsUpdatedMsg:
+		var cmd tea.Cmd
+		m, cmd = m.updateECSUpdated(msg)
+		cmds = append(cmds, cmd)
+
+	case sqsPurgedMsg:
+		var cmd tea.Cmd
+		m, cmd = m.updateSQSPurged(msg)
+		cmds = append(cmds, cmd)
+
+	case paneLoadedMsg:
+		m.updatePane(msg)
+
+	case runbookRanMsg:
+		m.updateRunbook(msg)
+
+	case metricNamespacesLoadedMsg, metricListLoadedMsg, metricPlotLoadedMsg:
+		m.updateMetricBrowser(msg)
+
+	case pinnedMetricsLoadedMsg:
+		m.updatePinnedMetrics(msg)
+
+	case pinsSavedMsg:
+		m.pinsSaveErr = msg.err
+		m.updateViewportContent()
+
+	case providerLoadedMsg:
+		m.updateProvider(msg)
+
+	case ecsTaskPollMsg:
+		if m.ecsTask != nil && m.ecsTask.ARN == msg.taskARN {
+			cmds = append(cmds, m.describeTask(*m.ecsTask))
+		}
 	}
 
 	return m, tea.Batch(cmds...)
@@ -309,36 +1051,54 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // updateViewportContent updates the viewport content based on the active tab
 func (m *Model) updateViewportContent() {
-	var content string
+	// Set the content for scrolling
+	if pinned, ok := m.pinnedTab(); ok {
+		m.splitViewport.SetContent(pinned.render(*m))
+	}
+	if m.showKeymap {
+		m.viewport.SetContent(m.renderKeymap())
+		return
+	}
+	if m.credentialsExpired() {
+		m.viewport.SetContent(m.renderReauthPrompt())
+		return
+	}
+	if m.paneOpen() {
+		m.viewport.SetContent(m.renderPane())
+		return
+	}
+	m.viewport.SetContent(m.currentTab().render(*m))
+}
 
-	switch {
-	case m.activeTab == 0: // Overview tab
-		content = m.renderOverview()
-	case m.activeTab == 1 && m.showALB: // Load Balancers tab
-		content = m.renderALB()
-	case (m.activeTab == 1 && !m.showALB && m.showRDS) || (m.activeTab == 2 && m.showALB && m.showRDS): // RDS tab
-		content = m.renderRDS()
-	case (m.activeTab == 1 && !m.showALB && !m.showRDS && m.showEC2) ||
-		(m.activeTab == 2 && !m.showALB && m.showEC2) ||
-		(m.activeTab == 2 && !m.showRDS && m.showEC2) ||
-		(m.activeTab == 3 && m.showALB && m.showRDS && m.showEC2): // EC2 tab
-		content = m.renderEC2()
-	case (m.activeTab == 1 && !m.showALB && !m.showRDS && !m.showEC2 && m.showECS) ||
-		(m.activeTab == 2 && !m.showALB && !m.showRDS && m.showECS) ||
-		(m.activeTab == 2 && !m.showALB && !m.showEC2 && m.showECS) ||
-		(m.activeTab == 2 && !m.showRDS && !m.showEC2 && m.showECS) ||
-		(m.activeTab == 3 && !m.showALB && m.showECS) ||
-		(m.activeTab == 3 && !m.showRDS && m.showECS) ||
-		(m.activeTab == 3 && !m.showEC2 && m.showECS) ||
-		(m.activeTab == 4 && m.showALB && m.showRDS && m.showEC2 && m.showECS): // ECS tab
-		content = m.renderECS()
-	case m.activeTab >= 1 && m.activeTab <= 5 && m.showSQS &&
-		((m.activeTab == len(m.tabs)-1) || m.tabs[m.activeTab] == "SQS Queues"): // SQS tab
-		content = m.renderSQS()
+// scrollToSelection scrolls the viewport so that the line of content marked
+// as selected with "> " is visible
+func (m *Model) scrollToSelection(content string) {
+	for i, line := range strings.Split(content, "\n") {
+		if !strings.HasPrefix(line, "> ") {
+			continue
+		}
+		if i < m.viewport.YOffset {
+			m.viewport.SetYOffset(i)
+		} else if i >= m.viewport.YOffset+m.viewport.Height {
+			m.viewport.SetYOffset(i - m.viewport.Height + 1)
+		}
+		return
 	}
+}
 
-	// Set the content for scrolling
-	m.viewport.SetContent(content)
+// resize fits the viewport between the header and the help text, and the
+// event log pane when it is shown
+func (m *Model) resize() {
+	headerHeight := 12                                             // Increased space for header elements
+	footerHeight := 2                                              // Help text and status bar
+	m.viewport.Width = m.width - 4                                 // Account for padding
+	m.viewport.Height = m.height - headerHeight - footerHeight - 2 // Account for margins
+	if m.showEventLog {
+		m.viewport.Height -= eventLogHeight + 3 // Title and border
+	}
+	if m.split {
+		m.resizeSplit()
+	}
 }
 
 // View renders the UI
@@ -346,25 +1106,116 @@ func (m Model) View() string {
 	// Generate tabs with prominent styling
 	var renderedTabs []string
 	for i, t := range m.tabs {
-		if i == m.activeTab {
-			renderedTabs = append(renderedTabs, activeTabStyle.Render(t))
-		} else {
-			renderedTabs = append(renderedTabs, tabStyle.Render(t))
+		name := t.name
+		// Mark the tabs whose data is being reloaded
+		if t.service != "" && m.fetching(t.service) {
+			name += " " + m.spinner.View()
+		}
+		// Mark the tabs with a breached alert rule, flashing while new
+		if t.service != "" && m.alertBreached(t.service) {
+			name += " 🚨"
+		}
+		switch {
+		case t.service != "" && m.alertFlashed(t.service):
+			renderedTabs = append(renderedTabs, alertTabStyle.Render(name))
+		case i == m.activeTab:
+			renderedTabs = append(renderedTabs, activeTabStyle.Render(name))
+		default:
+			renderedTabs = append(renderedTabs, tabStyle.Render(name))
 		}
 	}
 	tabBar := lipgloss.JoinHorizontal(lipgloss.Top, renderedTabs...)
 
+	// Note how old the data shown is, and whether it was served from the cache
+	if indicator := m.renderStaleness(); indicator != "" {
+		tabBar = lipgloss.JoinHorizontal(lipgloss.Center, tabBar, lipgloss.NewStyle().Foreground(dimTextColor).Padding(0, 2).Render(indicator))
+	}
+
+	// Count down to the expiry of temporary credentials
+	if expiry := m.renderCredentialsExpiry(); expiry != "" {
+		tabBar = lipgloss.JoinHorizontal(lipgloss.Center, tabBar, lipgloss.NewStyle().Padding(0, 2).Render(expiry))
+	}
+
 	// Make tab bar more prominent
 	tabBar = lipgloss.NewStyle().Margin(0, 0, 1, 0).Render(tabBar)
 
-	// Use viewport for scrollable content
+	// Use viewport for scrollable content, or the payload editor while editing
 	viewportContent := m.viewport.View()
+	if m.payloadEditor.Focused() {
+		viewportContent = m.renderPayloadEditor()
+	}
 
 	// Apply content styling with proper border rendering using full width
 	contentStyleCopy := contentStyle.Copy().Width(m.width - 4) // Subtract padding
 	styledContent := contentStyleCopy.Render(viewportContent)
+	if pinned, ok := m.pinnedTab(); ok {
+		styledContent = m.renderSplit(pinned, viewportContent)
+	}
+	if m.showEventLog {
+		styledContent = lipgloss.JoinVertical(lipgloss.Left, styledContent, m.renderEventLog())
+	}
 
 	// Show help text at the bottom
+	help := "← → Navigate Tabs • ↑↓/j k Scroll • r Refresh Tab • R Refresh All • q Quit"
+	if m.embedded {
+		help = "← → Navigate Tabs • ↑↓/j k Scroll • r Refresh Tab • R Refresh All"
+	}
+	if m.currentTab().sortColumns > 0 {
+		help += " • s Sort"
+	}
+	if tabHelp := m.currentTab().help; tabHelp != nil {
+		help += " • " + tabHelp(m)
+	}
+	if m.currentTab().selected != nil {
+		if keys := m.paneKeysHelp(); keys != "" {
+			help += " • " + keys
+		}
+	}
+	if m.currentTab().identifier != nil {
+		help += " • y Copy"
+	}
+	if m.currentTab().console != nil {
+		help += " • o Console"
+	}
+	if m.showEventLog {
+		help += " • ! Hide Events • w Write Events"
+	} else {
+		help += " • ! Events"
+	}
+	if m.split {
+		help += " • | Unsplit • J K Scroll Pinned"
+	}
+	help += " • ? Help"
+	if m.paneOpen() {
+		help = m.paneHelp()
+	}
+	if m.credentialsExpired() && !m.demo {
+		help = "A Sign In • R Reload All • q Quit"
+		if m.embedded {
+			help = "A Sign In • R Reload All"
+		}
+	}
+	if m.showKeymap {
+		help = "↑↓/j k Scroll • ? or esc Close Help"
+	}
+	if m.toast != "" {
+		help = m.toast
+	}
+	if m.routeInput.Focused() {
+		help = m.renderRouteInput()
+	}
+	if m.taskInput.Focused() {
+		help = m.renderTaskInput()
+	}
+	if m.scaleInput.Focused() {
+		help = m.renderScaleInput()
+	}
+	if m.purgeInput.Focused() {
+		help = m.renderPurgeInput()
+	}
+	if m.invalidationInput.Focused() {
+		help = m.renderInvalidationInput()
+	}
 	helpText := lipgloss.NewStyle().
 		Foreground(dimTextColor).
 		Background(backgroundColor).
@@ -373,7 +1224,7 @@ func (m Model) View() string {
 		Margin(1, 0, 0, 0).
 		BorderStyle(lipgloss.RoundedBorder()).
 		BorderForeground(primaryColor).
-		Render("← → Navigate Tabs • ↑↓/j k Scroll • r Refresh • q Quit")
+		Render(help)
 
 	// Force tabs to top of screen with no margins above
 	header := lipgloss.JoinVertical(
@@ -382,12 +1233,20 @@ func (m Model) View() string {
 	)
 
 	// Ensure content has adequate spacing from header
-	return lipgloss.JoinVertical(
+	view := lipgloss.JoinVertical(
 		lipgloss.Left,
 		header,
 		styledContent,
 		helpText,
+		m.renderStatusBar(),
 	)
+
+	// Fallbacks keep the width of the emoji they replace, so the layout
+	// above stays intact
+	if m.asciiSymbols {
+		view = common.ASCIISymbols(view)
+	}
+	return view
 }
 
 // getRegionFlag returns the flag emoji for a given AWS region
@@ -466,91 +1325,461 @@ func getAWSProfile() string {
 
 // renderOverview shows a summary view
 func (m Model) renderOverview() string {
-	if (m.loadingALB && m.showALB) || (m.loadingRDS && m.showRDS) || (m.loadingEC2 && m.showEC2) {
-		return m.spinner.View() + " Loading AWS resources..."
-	}
-
 	var content string
 	flag := getRegionFlag(m.region)
 	content += lipgloss.NewStyle().Foreground(accentColor).Bold(true).Render("Region: "+flag+" "+m.region) + "\n"
 
 	// Display AWS profile if set
 	profile := getAWSProfile()
-	if profile != "" {
+	if m.demo {
+		content += lipgloss.NewStyle().Foreground(warningColor).Bold(true).Render("Demo mode: showing fixture data") + "\n"
+	} else if profile != "" {
 		content += lipgloss.NewStyle().Foreground(secondaryColor).Bold(true).Render("Profile: "+profile) + "\n"
 	}
 
+	// Point out that the data shown comes from the previous session
+	if !m.restoredAt.IsZero() {
+		content += lipgloss.NewStyle().Foreground(warningColor).Render("Showing session saved at "+m.restoredAt.Format("2006-01-02 15:04:05")+", refreshing...") + "\n"
+	}
+
 	// Display last refresh time
-	content += lipgloss.NewStyle().Foreground(dimTextColor).Render("Last refresh: "+m.lastRefresh.Format("15:04:05")+" (auto-refreshes every minute)") + "\n\n"
+	content += lipgloss.NewStyle().Foreground(dimTextColor).Render("Last refresh: "+m.lastRefresh.Format("15:04:05")+" (auto-refreshes every "+m.interval.String()+")") + "\n\n"
+
+	// The services still loading are listed rather than blocking the others
+	content += m.renderLoadProgress()
+
+	// Pipeline stalls come first
+	if m.tabs[0].load != nil {
+		content += m.renderLagSummary()
+	}
+
+	for _, t := range m.tabs[1:] {
+		if t.summary != nil && (t.loading == nil || !t.loading(m)) {
+			content += t.summary(m)
+		}
+	}
 
-	if m.showALB {
-		if m.albErr != nil {
-			content += lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render("❌ Load Balancer Error: ") +
-				lipgloss.NewStyle().Foreground(errorColor).Render(m.albErr.Error()) + "\n\n"
-		} else {
-			content += lipgloss.NewStyle().Foreground(successColor).Bold(true).Render("✅ Load Balancers: ") +
-				lipgloss.NewStyle().Foreground(textColor).Render(alb.GetLoadBalancersSummary(m.loadBalancers)) + "\n\n"
+	// Wasted resources come last, as they cost money rather than uptime
+	content += m.renderHygieneSummary()
+
+	if len(m.tabs) == 1 {
+		var flags []string
+		for _, t := range serviceTabs {
+			if t.service != "" {
+				flags = append(flags, "-"+t.service+"=true")
+			}
 		}
+		content += "No services selected. Use " + strings.Join(flags[:len(flags)-1], ", ") + " and/or " + flags[len(flags)-1] + " flags."
 	}
 
-	if m.showRDS {
-		if m.rdsErr != nil {
-			content += lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render("❌ RDS Error: ") +
-				lipgloss.NewStyle().Foreground(errorColor).Render(m.rdsErr.Error()) + "\n\n"
-		} else {
-			content += lipgloss.NewStyle().Foreground(successColor).Bold(true).Render("✅ RDS Instances: ") +
-				lipgloss.NewStyle().Foreground(textColor).Render(rds.GetDBInstancesSummary(m.dbInstances)) + "\n\n"
+	return content
+}
+
+// renderALBSummary shows the load balancers on the Overview tab
+func (m Model) renderALBSummary() string {
+	var content string
+	if len(m.albErrs) > 0 && len(m.loadBalancers) == 0 {
+		content += lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render("❌ Load Balancer Error: ") +
+			lipgloss.NewStyle().Foreground(errorColor).Render(permissions.DescribeAll(m.albErrs)) + "\n\n"
+	} else {
+		content += lipgloss.NewStyle().Foreground(successColor).Bold(true).Render("✅ Load Balancers: ") +
+			lipgloss.NewStyle().Foreground(textColor).Render(alb.GetLoadBalancersSummary(m.loadBalancers)) +
+			renderFirst(len(m.loadBalancers), m.albTotal) + renderStillLoading(m.loadingALB) + "\n" +
+			renderLoadWarning(m.albErrs)
+
+		// Flag certificates about to expire in red, as clients reject them once they do
+		for _, certificate := range alb.GetExpiringCertificates(m.loadBalancers) {
+			renewal := ""
+			if !certificate.RenewsAutomatically() {
+				renewal = ", not renewed automatically"
+			}
+			content += lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render(
+				fmt.Sprintf("   🔒 %s: expires in ~%.0f days%s", certificate.Name(), max(0, certificate.DaysUntilExpiry()), renewal)) + "\n"
 		}
+		content += "\n"
 	}
+	return content
+}
+
+// renderStillLoading notes that a summary only covers the partial results
+// of a service that is still loading
+func renderStillLoading(loading bool) string {
+	if !loading {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(dimTextColor).Render(" (still loading)")
+}
 
-	if m.showEC2 {
-		if m.ec2Err != nil {
-			content += lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render("❌ EC2 Error: ") +
-				lipgloss.NewStyle().Foreground(errorColor).Render(m.ec2Err.Error()) + "\n\n"
-		} else {
-			content += lipgloss.NewStyle().Foreground(successColor).Bold(true).Render("✅ EC2 Instances: ") +
-				lipgloss.NewStyle().Foreground(textColor).Render(ec2.GetInstancesSummary(m.ec2Instances)) + "\n\n"
+// renderRDSSummary shows the RDS instances on the Overview tab
+func (m Model) renderRDSSummary() string {
+	var content string
+	if len(m.rdsErrs) > 0 && len(m.dbInstances) == 0 {
+		content += lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render("❌ RDS Error: ") +
+			lipgloss.NewStyle().Foreground(errorColor).Render(permissions.DescribeAll(m.rdsErrs)) + "\n\n"
+	} else {
+		content += lipgloss.NewStyle().Foreground(successColor).Bold(true).Render("✅ RDS Instances: ") +
+			lipgloss.NewStyle().Foreground(textColor).Render(rds.GetDBInstancesSummary(m.dbInstances)) + "\n" +
+			renderLoadWarning(m.rdsErrs)
+
+		// Flag instances projected to run out of storage
+		for _, instance := range rds.GetInstancesRunningOutOfStorage(m.dbInstances) {
+			content += lipgloss.NewStyle().Foreground(warningColor).Bold(true).Render(
+				fmt.Sprintf("   ⚠️ %s: storage full in ~%.0f days", instance.Identifier, instance.DaysUntilStorageFull)) + "\n"
+		}
+		// Flag maintenance scheduled soon and CA certificates about to expire
+		for _, instance := range rds.GetInstancesWithMaintenanceDue(m.dbInstances) {
+			next, _ := instance.NextMaintenance()
+			at, _ := next.ScheduledAt()
+			content += lipgloss.NewStyle().Foreground(warningColor).Render(
+				fmt.Sprintf("   🔧 %s: %s in ~%.0f days", instance.Identifier, next.Action, max(0, rds.DaysUntil(at)))) + "\n"
+		}
+		for _, instance := range rds.GetInstancesWithExpiringCertificates(m.dbInstances) {
+			content += lipgloss.NewStyle().Foreground(warningColor).Render(
+				fmt.Sprintf("   🔒 %s: CA certificate %s expires in ~%.0f days", instance.Identifier, instance.CACertificate, max(0, rds.DaysUntil(instance.CACertificateExpiry)))) + "\n"
 		}
+		content += "\n"
+	}
+	return content
+}
+
+// renderEC2Summary shows the EC2 instances on the Overview tab
+func (m Model) renderEC2Summary() string {
+	var content string
+	if len(m.ec2Errs) > 0 && len(m.ec2Instances) == 0 {
+		content += lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render("❌ EC2 Error: ") +
+			lipgloss.NewStyle().Foreground(errorColor).Render(permissions.DescribeAll(m.ec2Errs)) + "\n\n"
+	} else {
+		content += lipgloss.NewStyle().Foreground(successColor).Bold(true).Render("✅ EC2 Instances: ") +
+			lipgloss.NewStyle().Foreground(textColor).Render(ec2.GetInstancesSummary(m.ec2Instances)) + "\n" +
+			renderLoadWarning(m.ec2Errs) +
+			renderInstanceAlerts(m.ec2Instances) + "\n"
+	}
+	return content
+}
+
+// renderEBSSummary shows the EBS volumes on the Overview tab, flagging those
+// running low on burst balance
+func (m Model) renderEBSSummary() string {
+	var content string
+	if len(m.ebsErrs) > 0 && len(m.ebsVolumes) == 0 {
+		content += lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render("❌ EBS Error: ") +
+			lipgloss.NewStyle().Foreground(errorColor).Render(permissions.DescribeAll(m.ebsErrs)) + "\n\n"
+	} else {
+		content += lipgloss.NewStyle().Foreground(successColor).Bold(true).Render("✅ EBS Volumes: ") +
+			lipgloss.NewStyle().Foreground(textColor).Render(ebs.GetVolumesSummary(m.ebsVolumes)) + "\n" +
+			renderLoadWarning(m.ebsErrs)
+		for _, volume := range ebs.GetThrottledVolumes(m.ebsVolumes) {
+			balance, _ := volume.BurstBalance()
+			content += lipgloss.NewStyle().Foreground(warningColor).Render(
+				fmt.Sprintf("   🐢 %s: burst balance %s", volume.ID, common.FormatPercentage(balance))) + "\n"
+		}
+		content += "\n"
+	}
+	return content
+}
+
+// renderVPCSummary shows the VPCs on the Overview tab, flagging the subnets
+// running out of free IPs and the NAT gateways out of source ports
+func (m Model) renderVPCSummary() string {
+	var content string
+	if len(m.vpcErrs) > 0 && len(m.vpcs) == 0 {
+		content += lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render("❌ VPC Error: ") +
+			lipgloss.NewStyle().Foreground(errorColor).Render(permissions.DescribeAll(m.vpcErrs)) + "\n\n"
+	} else {
+		content += lipgloss.NewStyle().Foreground(successColor).Bold(true).Render("✅ VPCs: ") +
+			lipgloss.NewStyle().Foreground(textColor).Render(vpcpkg.GetVPCsSummary(m.vpcs)) + "\n" +
+			renderLoadWarning(m.vpcErrs)
+		for _, subnet := range vpcpkg.GetLowIPSubnets(m.vpcs) {
+			content += lipgloss.NewStyle().Foreground(warningColor).Render(
+				fmt.Sprintf("   ⚠️ %s in %s: %d IPs free (%s)", subnet.ID, subnet.VPC, subnet.AvailableIPs, common.FormatPercentage(subnet.FreePercent()))) + "\n"
+		}
+		for _, gateway := range vpcpkg.GetSaturatedNATGateways(m.vpcs) {
+			content += lipgloss.NewStyle().Foreground(errorColor).Render(
+				fmt.Sprintf("   ⚠️ %s: %.0f port allocation errors in the past hour", gateway.ID, gateway.PortAllocationErrors())) + "\n"
+		}
+		content += "\n"
+	}
+	return content
+}
+
+// renderECSSummary shows the ECS services on the Overview tab
+func (m Model) renderECSSummary() string {
+	var content string
+	if m.ecsErr != nil {
+		content += lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render("❌ ECS Error: ") +
+			lipgloss.NewStyle().Foreground(errorColor).Render(permissions.Describe(m.ecsErr)) + "\n\n"
+	} else {
+		content += lipgloss.NewStyle().Foreground(successColor).Bold(true).Render("✅ ECS Services: ") +
+			lipgloss.NewStyle().Foreground(textColor).Render(ecs.GetServicesSummary(m.ecsServices)) + renderStillLoading(m.loadingECS) + "\n\n"
+	}
+	return content
+}
+
+// renderLagSummary shows the highest consumer lag of each kind of pipeline,
+// flagging those above lag.WarningThreshold
+func (m Model) renderLagSummary() string {
+	names := []string{"Kinesis", "DynamoDB streams"}
+	indicators := []lag.Indicator{m.lagIndicators.Kinesis, m.lagIndicators.DynamoDB}
+	// The SQS lag comes from the queues already loaded for their tab
+	for _, t := range m.tabs {
+		if t.service == "sqs" {
+			names = append(names, "SQS")
+			indicators = append(indicators, lag.FromQueues(m.sqsQueues))
+		}
+	}
+
+	var parts, stalled []string
+	for i, indicator := range indicators {
+		parts = append(parts, names[i]+" "+lag.FormatIndicator(indicator))
+		if indicator.Stalled() {
+			stalled = append(stalled, fmt.Sprintf("   🚨 %s stalled: %s", names[i], lag.FormatIndicator(indicator)))
+		}
+	}
+
+	color := successColor
+	if len(stalled) > 0 {
+		color = errorColor
+	}
+	content := lipgloss.NewStyle().Foreground(color).Bold(true).Render("⏱️ Pipeline lag: ") +
+		lipgloss.NewStyle().Foreground(textColor).Render(strings.Join(parts, " • ")) + "\n" +
+		renderLoadWarning(m.lagErrs)
+	for _, line := range stalled {
+		content += lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render(line) + "\n"
+	}
+	return content + "\n"
+}
+
+// renderECRSummary shows the ECR repositories on the Overview tab, flagging
+// those whose latest image has critical findings
+func (m Model) renderECRSummary() string {
+	var content string
+	if len(m.ecrErrs) > 0 && len(m.ecrRepositories) == 0 {
+		content += lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render("❌ ECR Error: ") +
+			lipgloss.NewStyle().Foreground(errorColor).Render(permissions.DescribeAll(m.ecrErrs)) + "\n\n"
+	} else {
+		content += lipgloss.NewStyle().Foreground(successColor).Bold(true).Render("✅ ECR Repositories: ") +
+			lipgloss.NewStyle().Foreground(textColor).Render(ecrpkg.GetRepositoriesSummary(m.ecrRepositories)) +
+			renderFirst(len(m.ecrRepositories), m.ecrTotal) + "\n" +
+			renderLoadWarning(m.ecrErrs)
+		for _, repository := range ecrpkg.GetVulnerableRepositories(m.ecrRepositories) {
+			if repository.Critical() > 0 {
+				content += lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render(
+					fmt.Sprintf("   🚨 %s: %d critical, %d high findings in the latest image", repository.Name, repository.Critical(), repository.High())) + "\n"
+			}
+		}
+		content += "\n"
+	}
+	return content
+}
+
+// renderAPIGatewaySummary shows the API Gateway APIs on the Overview tab,
+// flagging the stages that returned 5xx responses within the hour
+func (m Model) renderAPIGatewaySummary() string {
+	var content string
+	if len(m.apiGatewayErrs) > 0 && len(m.apiGatewayAPIs) == 0 {
+		content += lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render("❌ API Gateway Error: ") +
+			lipgloss.NewStyle().Foreground(errorColor).Render(permissions.DescribeAll(m.apiGatewayErrs)) + "\n\n"
+	} else {
+		content += lipgloss.NewStyle().Foreground(successColor).Bold(true).Render("✅ API Gateway: ") +
+			lipgloss.NewStyle().Foreground(textColor).Render(apigatewaypkg.GetAPIsSummary(m.apiGatewayAPIs)) + "\n" +
+			renderLoadWarning(m.apiGatewayErrs)
+		for _, api := range apigatewaypkg.GetAPIsWithServerErrors(m.apiGatewayAPIs) {
+			for _, stage := range api.Stages {
+				if stage.HasServerErrors() {
+					content += lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render(
+						fmt.Sprintf("   🚨 %s/%s: %s 5xx responses in the last hour", api.Name, stage.Name, common.FormatFloatWithPrecision(stage.TotalServerErrors(), 0))) + "\n"
+				}
+			}
+		}
+		content += "\n"
+	}
+	return content
+}
+
+// renderCostSummary shows the month-to-date spend on the Overview tab
+func (m Model) renderCostSummary() string {
+	if len(m.costErrs) > 0 && m.costSummary == nil {
+		return lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render("❌ Costs Error: ") +
+			lipgloss.NewStyle().Foreground(errorColor).Render(permissions.DescribeAll(m.costErrs)) + "\n\n"
+	}
+	if m.costSummary == nil {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(successColor).Bold(true).Render("✅ Costs: ") +
+		lipgloss.NewStyle().Foreground(textColor).Render(costpkg.GetCostSummary(*m.costSummary)) + "\n" +
+		renderLoadWarning(m.costErrs) + "\n"
+}
+
+// renderSQSSummary shows the SQS queues on the Overview tab
+func (m Model) renderSQSSummary() string {
+	var content string
+	if len(m.sqsErrs) > 0 && len(m.sqsQueues) == 0 {
+		content += lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render("❌ SQS Error: ") +
+			lipgloss.NewStyle().Foreground(errorColor).Render(permissions.DescribeAll(m.sqsErrs)) + "\n\n"
+	} else {
+		content += lipgloss.NewStyle().Foreground(successColor).Bold(true).Render("✅ SQS Queues: ") +
+			lipgloss.NewStyle().Foreground(textColor).Render(sqs.GetQueuesSummary(m.sqsQueues)) + "\n" +
+			renderLoadWarning(m.sqsErrs)
+
+		// Flag queues whose dead-letter queue has messages
+		for _, queue := range sqs.GetQueuesWithNonEmptyDLQ(m.sqsQueues) {
+			content += lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render(
+				fmt.Sprintf("   🚨 %s: %d messages in DLQ %s", queue.Name, queue.DeadLetterQueueMessages, queue.DeadLetterQueue)) + "\n"
+		}
+		content += "\n"
+	}
+	return content
+}
+
+// renderSSMSummary shows the SSM managed instances on the Overview tab
+func (m Model) renderSSMSummary() string {
+	var content string
+	if len(m.ssmErrs) > 0 && len(m.ssmInstances) == 0 {
+		content += lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render("❌ SSM Error: ") +
+			lipgloss.NewStyle().Foreground(errorColor).Render(permissions.DescribeAll(m.ssmErrs)) + "\n\n"
+	} else {
+		content += lipgloss.NewStyle().Foreground(successColor).Bold(true).Render("✅ SSM Instances: ") +
+			lipgloss.NewStyle().Foreground(textColor).Render(ssm.GetInstancesSummary(m.ssmInstances)) + "\n" +
+			renderLoadWarning(m.ssmErrs) + "\n"
 	}
+	return content
+}
 
-	if m.showECS {
-		if m.ecsErr != nil {
-			content += lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render("❌ ECS Error: ") +
-				lipgloss.NewStyle().Foreground(errorColor).Render(m.ecsErr.Error()) + "\n\n"
-		} else {
-			content += lipgloss.NewStyle().Foreground(successColor).Bold(true).Render("✅ ECS Services: ") +
-				lipgloss.NewStyle().Foreground(textColor).Render(ecs.GetServicesSummary(m.ecsServices)) + "\n\n"
+// renderDNSSummary shows the DNS records on the Overview tab
+func (m Model) renderDNSSummary() string {
+	var content string
+	if len(m.dnsErrs) > 0 && len(m.dnsRecords) == 0 {
+		content += lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render("❌ DNS Error: ") +
+			lipgloss.NewStyle().Foreground(errorColor).Render(permissions.DescribeAll(m.dnsErrs)) + "\n\n"
+	} else {
+		content += lipgloss.NewStyle().Foreground(successColor).Bold(true).Render("✅ DNS Records: ") +
+			lipgloss.NewStyle().Foreground(textColor).Render(dns.GetRecordsSummary(m.dnsRecords)) + "\n" +
+			renderLoadWarning(m.dnsErrs)
+
+		// Flag records pointing at deleted resources
+		for _, record := range dns.GetDanglingRecords(m.dnsRecords) {
+			content += lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render(
+				fmt.Sprintf("   🚨 %s points at missing %s %s", record.Name, record.TargetKind, record.Target)) + "\n"
 		}
+		content += "\n"
 	}
+	return content
+}
 
-	if m.showSQS {
-		if m.sqsErr != nil {
-			content += lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render("❌ SQS Error: ") +
-				lipgloss.NewStyle().Foreground(errorColor).Render(m.sqsErr.Error()) + "\n\n"
-		} else {
-			content += lipgloss.NewStyle().Foreground(successColor).Bold(true).Render("✅ SQS Queues: ") +
-				lipgloss.NewStyle().Foreground(textColor).Render(sqs.GetQueuesSummary(m.sqsQueues)) + "\n\n"
+// renderDRSummary shows the cross-region replication status on the Overview tab
+func (m Model) renderDRSummary() string {
+	var content string
+	if len(m.drErrs) > 0 && len(m.drResources) == 0 {
+		content += lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render("❌ DR Readiness Error: ") +
+			lipgloss.NewStyle().Foreground(errorColor).Render(permissions.DescribeAll(m.drErrs)) + "\n\n"
+	} else {
+		content += lipgloss.NewStyle().Foreground(successColor).Bold(true).Render("✅ DR Readiness: ") +
+			lipgloss.NewStyle().Foreground(textColor).Render(dr.GetResourcesSummary(m.drResources)) + "\n" +
+			renderLoadWarning(m.drErrs)
+
+		// Flag resources whose replication is failing
+		for _, resource := range dr.GetDegradedResources(m.drResources) {
+			content += lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render(
+				fmt.Sprintf("   🚨 %s %s: %s", resource.Service, resource.Name, resource.Detail)) + "\n"
 		}
+		content += "\n"
 	}
+	return content
+}
 
-	if !m.showALB && !m.showRDS && !m.showEC2 && !m.showECS && !m.showSQS {
-		content += "No services selected. Use -alb=true, -rds=true, -ec2=true, and/or -ecs=true flags."
+// renderSNSSummary shows the SNS topics on the Overview tab
+func (m Model) renderSNSSummary() string {
+	var content string
+	if len(m.snsErrs) > 0 && len(m.snsTopics) == 0 {
+		content += lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render("❌ SNS Error: ") +
+			lipgloss.NewStyle().Foreground(errorColor).Render(permissions.DescribeAll(m.snsErrs)) + "\n\n"
+	} else {
+		content += lipgloss.NewStyle().Foreground(successColor).Bold(true).Render("✅ SNS Topics: ") +
+			lipgloss.NewStyle().Foreground(textColor).Render(sns.GetTopicsSummary(m.snsTopics)) + "\n" +
+			renderLoadWarning(m.snsErrs) + "\n"
 	}
+	return content
+}
 
+// renderLambdaSummary shows the Lambda functions on the Overview tab
+func (m Model) renderLambdaSummary() string {
+	var content string
+	if m.lambdaErr != nil {
+		content += lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render("❌ Lambda Error: ") +
+			lipgloss.NewStyle().Foreground(errorColor).Render(permissions.Describe(m.lambdaErr)) + "\n\n"
+	} else {
+		content += lipgloss.NewStyle().Foreground(successColor).Bold(true).Render("✅ Lambda Functions: ") +
+			lipgloss.NewStyle().Foreground(textColor).Render(lambdapkg.GetFunctionsSummary(m.lambdaFunctions)) + "\n\n"
+	}
 	return content
 }
 
-// renderALB shows detailed ALB information
+// renderCloudFrontSummary shows the CloudFront distributions on the Overview tab
+func (m Model) renderCloudFrontSummary() string {
+	var content string
+	if m.cloudfrontErr != nil {
+		content += lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render("❌ CloudFront Error: ") +
+			lipgloss.NewStyle().Foreground(errorColor).Render(permissions.Describe(m.cloudfrontErr)) + "\n\n"
+	} else {
+		content += lipgloss.NewStyle().Foreground(successColor).Bold(true).Render("✅ CloudFront Distributions: ") +
+			lipgloss.NewStyle().Foreground(textColor).Render(cloudfrontpkg.GetDistributionsSummary(m.cloudfrontDistributions)) + "\n\n"
+	}
+	return content
+}
+
+// renderLoadWarning notes on the Overview tab that some resources of a
+// service only partially loaded
+func renderLoadWarning(errs []error) string {
+	if len(errs) == 0 {
+		return ""
+	}
+	problems := strings.Split(permissions.DescribeAll(errs), "\n")
+	return lipgloss.NewStyle().Foreground(warningColor).Render(
+		fmt.Sprintf("   ⚠️ %d problems while loading, see the service tab for details", len(problems))) + "\n"
+}
+
+// renderLoadErrors lists the errors of the resources that failed to load
+// above the data of those that did, naming missing IAM permissions
+func renderLoadErrors(errs []error) string {
+	if len(errs) == 0 {
+		return ""
+	}
+
+	var content string
+	for _, problem := range strings.Split(permissions.DescribeAll(errs), "\n") {
+		content += lipgloss.NewStyle().Foreground(warningColor).Render("⚠️ "+problem) + "\n"
+	}
+	return content + renderHints(errs) + "\n"
+}
+
+// renderHints suggests how to fix the common failures among errs, such as
+// expired credentials or throttling
+func renderHints(errs []error) string {
+	var content string
+	for _, hint := range permissions.Hints(errs) {
+		content += lipgloss.NewStyle().Foreground(dimTextColor).Render("💡 "+hint) + "\n"
+	}
+	return content
+}
+
+// renderALB shows detailed load balancer information
 func (m Model) renderALB() string {
+	if m.loadingALB && len(m.loadBalancers) == 0 {
+		return m.spinner.View() + " Loading load balancer data..."
+	}
+	_, selected, _ := m.selectedTarget()
+	selected = m.selection(selected)
 	if m.loadingALB {
-		return m.spinner.View() + " Loading ALB data..."
+		return fmt.Sprintf("Loading load balancer data, %d so far...\n\n", len(m.loadBalancers)) +
+			alb.FormatLoadBalancers(m.shownLoadBalancers(), selected)
 	}
 
-	if m.albErr != nil {
-		return "Error loading ALB data: " + m.albErr.Error()
+	if len(m.albErrs) > 0 && len(m.loadBalancers) == 0 {
+		return "Error loading load balancer data: " + permissions.DescribeAll(m.albErrs) + "\n\n" + renderHints(m.albErrs)
 	}
 
-	return alb.FormatLoadBalancers(m.loadBalancers)
+	return m.renderTargetAction() + m.renderRouteSimulation() + renderLoadErrors(m.albErrs) + m.renderMore(len(m.loadBalancers), m.albTotal, "load balancers") +
+		alb.FormatLoadBalancers(m.shownLoadBalancers(), selected)
 }
 
 // renderRDS shows detailed RDS information
@@ -559,11 +1788,18 @@ func (m Model) renderRDS() string {
 		return m.spinner.View() + " Loading RDS data..."
 	}
 
-	if m.rdsErr != nil {
-		return "Error loading RDS data: " + m.rdsErr.Error()
+	if len(m.rdsErrs) > 0 && len(m.dbInstances) == 0 {
+		return "Error loading RDS data: " + permissions.DescribeAll(m.rdsErrs) + "\n\n" + renderHints(m.rdsErrs)
+	}
+
+	more := m.renderMore(m.shown("rds", len(m.dbInstances)), len(m.dbInstances), "instances")
+	if m.plain || len(m.dbInstances) == 0 {
+		return renderLoadErrors(m.rdsErrs) + more + rds.FormatDBInstances(capRows(m, "rds", m.dbInstances), m.selection(m.rdsSelected))
 	}
 
-	return rds.FormatDBInstances(m.dbInstances)
+	// The table compares the instances, the graphs below follow its order
+	view, sorted := renderTable(m, "rds", m.dbInstances, rds.Columns)
+	return renderLoadErrors(m.rdsErrs) + more + view + "\n\n" + rds.FormatDBInstances(sorted, m.rdsSelected)
 }
 
 // renderEC2 shows detailed EC2 information
@@ -572,24 +1808,132 @@ func (m Model) renderEC2() string {
 		return m.spinner.View() + " Loading EC2 data..."
 	}
 
-	if m.ec2Err != nil {
-		return "Error loading EC2 data: " + m.ec2Err.Error()
+	if len(m.ec2Errs) > 0 && len(m.ec2Instances) == 0 {
+		return "Error loading EC2 data: " + permissions.DescribeAll(m.ec2Errs) + "\n\n" + renderHints(m.ec2Errs)
+	}
+
+	more := m.renderMore(m.shown("ec2", len(m.ec2Instances)), len(m.ec2Instances), "instances")
+	if m.plain || len(m.ec2Instances) == 0 {
+		return renderLoadErrors(m.ec2Errs) + more + ec2.FormatInstances(capRows(m, "ec2", m.ec2Instances))
+	}
+
+	view, _ := renderTable(m, "ec2", m.ec2Instances, ec2.Columns)
+	alerts := renderInstanceAlerts(m.ec2Instances)
+	if alerts != "" {
+		alerts += "\n"
+	}
+	return m.renderEC2Action() + renderLoadErrors(m.ec2Errs) + alerts + more + fmt.Sprintf("EC2 Instances (%d):\n\n", len(m.ec2Instances)) + markSelectedRow(view, m.ec2Selected)
+}
+
+// renderInstanceAlerts flags the instances failing a status check and those
+// with upcoming scheduled maintenance
+func renderInstanceAlerts(instances []ec2.InstanceSummary) string {
+	var content string
+	for _, instance := range ec2.GetImpairedInstances(instances) {
+		content += lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render(
+			fmt.Sprintf("   🚨 %s (%s): system status %s, instance status %s", instance.Name, instance.InstanceID, instance.SystemStatus, instance.InstanceStatus)) + "\n"
+	}
+	for _, instance := range ec2.GetInstancesWithScheduledEvents(instances) {
+		for _, event := range instance.ScheduledEvents {
+			content += lipgloss.NewStyle().Foreground(warningColor).Render(
+				fmt.Sprintf("   🔧 %s (%s): %s", instance.Name, instance.InstanceID, ec2.FormatScheduledEvent(event))) + "\n"
+		}
+	}
+	for _, risky := range ec2.GetRiskyRules(instances) {
+		content += lipgloss.NewStyle().Foreground(warningColor).Render(
+			fmt.Sprintf("   🔓 %s (%s): %s on %s", risky.Group.Name, risky.Group.ID, risky.Rule.DescribeRisk(), strings.Join(risky.Instances, ", "))) + "\n"
+	}
+	return content
+}
+
+// renderEBS shows the EBS volumes with the orphaned and throttled ones first
+func (m Model) renderEBS() string {
+	if m.loadingEBS {
+		return m.spinner.View() + " Loading EBS data..."
+	}
+
+	if len(m.ebsErrs) > 0 && len(m.ebsVolumes) == 0 {
+		return "Error loading EBS data: " + permissions.DescribeAll(m.ebsErrs) + "\n\n" + renderHints(m.ebsErrs)
+	}
+
+	return renderLoadErrors(m.ebsErrs) + m.renderMore(m.shown("ebs", len(m.ebsVolumes)), len(m.ebsVolumes), "volumes") +
+		ebs.FormatVolumes(capRows(m, "ebs", m.ebsVolumes))
+}
+
+// renderVPC shows the VPCs with the subnets low on free IPs and the NAT
+// gateways out of source ports first
+func (m Model) renderVPC() string {
+	if m.loadingVPC {
+		return m.spinner.View() + " Loading VPC data..."
+	}
+
+	if len(m.vpcErrs) > 0 && len(m.vpcs) == 0 {
+		return "Error loading VPC data: " + permissions.DescribeAll(m.vpcErrs) + "\n\n" + renderHints(m.vpcErrs)
 	}
 
-	return ec2.FormatInstances(m.ec2Instances)
+	return renderLoadErrors(m.vpcErrs) + vpcpkg.FormatVPCs(m.vpcs)
 }
 
 // renderECS shows detailed ECS information
 func (m Model) renderECS() string {
-	if m.loadingECS {
+	if m.loadingECS && len(m.ecsServices) == 0 {
 		return m.spinner.View() + " Loading ECS data..."
 	}
+	if m.loadingECS {
+		return fmt.Sprintf("Loading ECS data, %d services so far...\n\n", len(m.ecsServices)) +
+			ecs.FormatServices(m.ecsServices, m.ecsScheduled, m.selection(m.ecsSelected))
+	}
 
 	if m.ecsErr != nil {
-		return "Error loading ECS data: " + m.ecsErr.Error()
+		return "Error loading ECS data: " + permissions.Describe(m.ecsErr) + "\n\n" + renderHints([]error{m.ecsErr})
+	}
+
+	return renderLoadErrors(m.ecsErrs) + m.renderECSExec() + m.renderECSUpdate() + m.renderTask() + m.renderMore(m.shown("ecs", len(m.ecsServices)), len(m.ecsServices), "services") +
+		ecs.FormatServices(capRows(m, "ecs", m.ecsServices), m.ecsScheduled, m.selection(m.ecsSelected))
+}
+
+// renderECR shows the repositories with the scan findings of their latest image
+func (m Model) renderECR() string {
+	if m.loadingECR {
+		return m.spinner.View() + " Loading ECR data..."
+	}
+
+	if len(m.ecrErrs) > 0 && len(m.ecrRepositories) == 0 {
+		return "Error loading ECR data: " + permissions.DescribeAll(m.ecrErrs) + "\n\n" + renderHints(m.ecrErrs)
+	}
+
+	return renderLoadErrors(m.ecrErrs) + m.renderMore(len(m.ecrRepositories), m.ecrTotal, "repositories") +
+		ecrpkg.FormatRepositories(m.ecrRepositories)
+}
+
+// renderAPIGateway shows the APIs with the throttling and metrics of their stages
+func (m Model) renderAPIGateway() string {
+	if m.loadingAPIGateway {
+		return m.spinner.View() + " Loading API Gateway data..."
+	}
+
+	if len(m.apiGatewayErrs) > 0 && len(m.apiGatewayAPIs) == 0 {
+		return "Error loading API Gateway data: " + permissions.DescribeAll(m.apiGatewayErrs) + "\n\n" + renderHints(m.apiGatewayErrs)
 	}
 
-	return ecs.FormatServices(m.ecsServices)
+	return renderLoadErrors(m.apiGatewayErrs) + m.renderMore(m.shown("apigw", len(m.apiGatewayAPIs)), len(m.apiGatewayAPIs), "APIs") +
+		apigatewaypkg.FormatAPIs(capRows(m, "apigw", m.apiGatewayAPIs))
+}
+
+// renderCost shows the month-to-date spend by service and the daily trend
+func (m Model) renderCost() string {
+	if m.loadingCost {
+		return m.spinner.View() + " Loading Cost Explorer data..."
+	}
+
+	if len(m.costErrs) > 0 && m.costSummary == nil {
+		return "Error loading Cost Explorer data: " + permissions.DescribeAll(m.costErrs) + "\n\n" + renderHints(m.costErrs)
+	}
+	if m.costSummary == nil {
+		return "No Cost Explorer data loaded"
+	}
+
+	return renderLoadErrors(m.costErrs) + costpkg.FormatSummary(*m.costSummary)
 }
 
 // renderSQS shows detailed SQS information
@@ -598,9 +1942,102 @@ func (m Model) renderSQS() string {
 		return m.spinner.View() + " Loading SQS data..."
 	}
 
-	if m.sqsErr != nil {
-		return "Error loading SQS data: " + m.sqsErr.Error()
+	if len(m.sqsErrs) > 0 && len(m.sqsQueues) == 0 {
+		return "Error loading SQS data: " + permissions.DescribeAll(m.sqsErrs) + "\n\n" + renderHints(m.sqsErrs)
+	}
+
+	more := m.renderMore(m.shown("sqs", len(m.sqsQueues)), len(m.sqsQueues), "queues")
+	if m.plain || len(m.sqsQueues) == 0 {
+		return renderLoadErrors(m.sqsErrs) + more + sqs.FormatQueues(capRows(m, "sqs", m.sqsQueues))
+	}
+
+	// The table compares the queues, the graphs below follow its order
+	view, sorted := renderTable(m, "sqs", m.sqsQueues, sqs.Columns)
+	return m.renderSQSPurge() + renderLoadErrors(m.sqsErrs) + more + markSelectedRow(view, m.sqsSelected) + "\n\n" + sqs.FormatQueues(sorted)
+}
+
+// renderSSM shows SSM management and patch compliance of instances
+func (m Model) renderSSM() string {
+	if m.loadingSSM {
+		return m.spinner.View() + " Loading SSM data..."
+	}
+
+	if len(m.ssmErrs) > 0 && len(m.ssmInstances) == 0 {
+		return "Error loading SSM data: " + permissions.DescribeAll(m.ssmErrs) + "\n\n" + renderHints(m.ssmErrs)
+	}
+
+	return renderLoadErrors(m.ssmErrs) + m.renderMore(m.shown("ssm", len(m.ssmInstances)), len(m.ssmInstances), "instances") +
+		ssm.FormatInstances(capRows(m, "ssm", m.ssmInstances))
+}
+
+// renderDNS shows DNS records pointing at AWS resources and flags dangling ones
+func (m Model) renderDNS() string {
+	if m.loadingDNS {
+		return m.spinner.View() + " Loading DNS data..."
+	}
+
+	if len(m.dnsErrs) > 0 && len(m.dnsRecords) == 0 {
+		return "Error loading DNS data: " + permissions.DescribeAll(m.dnsErrs) + "\n\n" + renderHints(m.dnsErrs)
+	}
+
+	return renderLoadErrors(m.dnsErrs) + m.renderMore(m.shown("dns", len(m.dnsRecords)), len(m.dnsRecords), "records") +
+		dns.FormatRecords(capRows(m, "dns", m.dnsRecords))
+}
+
+// renderDR shows the cross-region replication status of resources
+func (m Model) renderDR() string {
+	if m.loadingDR {
+		return m.spinner.View() + " Loading DR readiness data..."
+	}
+
+	if len(m.drErrs) > 0 && len(m.drResources) == 0 {
+		return "Error loading DR readiness data: " + permissions.DescribeAll(m.drErrs) + "\n\n" + renderHints(m.drErrs)
+	}
+
+	return renderLoadErrors(m.drErrs) + m.renderMore(m.shown("dr", len(m.drResources)), len(m.drResources), "resources") +
+		dr.FormatResources(capRows(m, "dr", m.drResources))
+}
+
+// renderSNS shows which SQS queues each SNS topic fans out to
+func (m Model) renderSNS() string {
+	if m.loadingSNS {
+		return m.spinner.View() + " Loading SNS data..."
+	}
+
+	if len(m.snsErrs) > 0 && len(m.snsTopics) == 0 {
+		return "Error loading SNS data: " + permissions.DescribeAll(m.snsErrs) + "\n\n" + renderHints(m.snsErrs)
+	}
+
+	return renderLoadErrors(m.snsErrs) + m.renderMore(m.shown("sns", len(m.snsTopics)), len(m.snsTopics), "topics") +
+		sns.FormatTopics(capRows(m, "sns", m.snsTopics))
+}
+
+// renderLambda shows the functions with the selected one marked, below the
+// result of the last test invocation
+func (m Model) renderLambda() string {
+	if m.loadingLambda {
+		return m.spinner.View() + " Loading Lambda data..."
+	}
+
+	if m.lambdaErr != nil {
+		return "Error loading Lambda data: " + permissions.Describe(m.lambdaErr) + "\n\n" + renderHints([]error{m.lambdaErr})
+	}
+
+	return m.renderInvocation() + m.renderMore(m.shown("lambda", len(m.lambdaFunctions)), len(m.lambdaFunctions), "functions") +
+		lambdapkg.FormatFunctions(capRows(m, "lambda", m.lambdaFunctions), m.selection(m.lambdaSelected))
+}
+
+// renderCloudFront shows the distributions with the selected one marked,
+// below the tracked invalidation
+func (m Model) renderCloudFront() string {
+	if m.loadingCloudFront {
+		return m.spinner.View() + " Loading CloudFront data..."
+	}
+
+	if m.cloudfrontErr != nil {
+		return "Error loading CloudFront data: " + permissions.Describe(m.cloudfrontErr) + "\n\n" + renderHints([]error{m.cloudfrontErr})
 	}
 
-	return sqs.FormatQueues(m.sqsQueues)
+	return m.renderInvalidation() + m.renderMore(m.shown("cloudfront", len(m.cloudfrontDistributions)), len(m.cloudfrontDistributions), "distributions") +
+		cloudfrontpkg.FormatDistributions(capRows(m, "cloudfront", m.cloudfrontDistributions), m.selection(m.cloudfrontSelected))
 }
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DefaultMaxResults is how many resources each tab shows at first with the
+// -max-results flag unset
+const DefaultMaxResults = 200
+
+// limitedServices are the services that only load the resources their tab
+// shows, because each resource costs API calls of its own. The other
+// services load all resources and only cap how many are rendered.
+var limitedServices = map[string]bool{"alb": true, "ecr": true}
+
+// limit returns how many resources the tab of a service shows, 0 for all
+func (m Model) limit(service string) int {
+	if limit, ok := m.limits[service]; ok {
+		return limit
+	}
+	return m.maxResults
+}
+
+// shown returns how many of the total resources of a service its tab shows
+func (m Model) shown(service string, total int) int {
+	if limit := m.limit(service); limit > 0 {
+		return min(total, limit)
+	}
+	return total
+}
+
+// capRows returns the rows the tab of a service shows
+func capRows[T any](m Model, service string, rows []T) []T {
+	return rows[:m.shown(service, len(rows))]
+}
+
+// limitedCacheService returns the cache key of a limited service's data,
+// which depends on how many resources were loaded
+func limitedCacheService(service string, limit int) string {
+	if limit == 0 {
+		return service
+	}
+	return service + "-first-" + strconv.Itoa(limit)
+}
+
+// renderMore notes that a tab shows only the first of a service's
+// resources, and how to show more
+func (m Model) renderMore(shown, total int, noun string) string {
+	if shown >= total {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(warningColor).Render(
+		fmt.Sprintf("Showing %d of %d %s. Press + to show %d more.", shown, total, noun, min(m.maxResults, total-shown))) + "\n\n"
+}
+
+// renderFirst notes that a summary only covers the first resources of a
+// limited service
+func renderFirst(loaded, total int) string {
+	if loaded >= total {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(dimTextColor).Render(fmt.Sprintf(" (first %d of %d)", loaded, total))
+}
+
+// showMore raises the number of resources the active tab shows by
+// -max-results, loading them for the limited services
+func (m Model) showMore() (Model, tea.Cmd) {
+	t := m.currentTab()
+	if m.maxResults == 0 || t.service == "" {
+		return m, nil
+	}
+
+	m.limits[t.service] = m.limit(t.service) + m.maxResults
+	m.updateViewportContent()
+	if limitedServices[t.service] && t.load != nil {
+		return m, t.load(m)
+	}
+	return m, nil
+}
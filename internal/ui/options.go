@@ -0,0 +1,257 @@
+package ui
+
+import (
+	"context"
+	"time"
+
+	"github.com/correctedcloud/aws-overview/internal/alerts"
+	"github.com/correctedcloud/aws-overview/internal/audit"
+	"github.com/correctedcloud/aws-overview/internal/config"
+	"github.com/correctedcloud/aws-overview/internal/providers"
+	"github.com/correctedcloud/aws-overview/internal/runbook"
+	"github.com/correctedcloud/aws-overview/internal/seal"
+	"github.com/correctedcloud/aws-overview/internal/session"
+	"github.com/correctedcloud/aws-overview/pkg/cloudwatchmetrics"
+	"github.com/correctedcloud/aws-overview/pkg/common"
+	"github.com/correctedcloud/aws-overview/pkg/metrics"
+	"github.com/correctedcloud/aws-overview/pkg/probe"
+)
+
+// DefaultRefreshInterval is how often data is reloaded when Options.RefreshInterval is unset
+const DefaultRefreshInterval = time.Minute
+
+// DefaultTimeout is how long loading a service may take when Options.Timeout is unset
+const DefaultTimeout = 30 * time.Second
+
+// Options configures the AWS overview component
+type Options struct {
+	// ShowALB, ShowRDS, ShowEC2, ShowECS, ShowSQS, ShowSSM, ShowDNS, ShowDR,
+	// ShowSNS, ShowLambda, ShowCloudFront, ShowEBS, ShowVPC, ShowECR and
+	// ShowAPIGateway select which services get a tab and are loaded.
+	// The Overview tab is always shown.
+	ShowALB    bool
+	ShowRDS    bool
+	ShowEC2    bool
+	ShowECS    bool
+	ShowSQS    bool
+	ShowSSM    bool
+	ShowDNS    bool
+	ShowDR     bool
+	ShowSNS    bool
+	ShowLambda bool
+
+	ShowCloudFront bool
+	ShowEBS        bool
+	ShowVPC        bool
+	ShowECR        bool
+	ShowAPIGateway bool
+
+	// MetricsWindow is how far back the metric graphs of the RDS and SQS
+	// tabs go, one of cloudwatchmetrics.Windows. Defaults to
+	// cloudwatchmetrics.DefaultWindow; the 1, 3, 6 and 24 keys change it.
+	MetricsWindow time.Duration
+
+	// ShowCost adds a Costs tab with the month-to-date spend by service and
+	// the daily trend from Cost Explorer. It is opt-in because every Cost
+	// Explorer request is billed; the tab refreshes at most hourly.
+	ShowCost bool
+
+	// ShowFindings adds a Findings tab flagging idle load balancers,
+	// underutilized EC2 instances, DB instances without connections and
+	// single-AZ DB instances among the resources of the other tabs. It is
+	// opt-in because it reads days of CloudWatch statistics of every
+	// resource; the tab refreshes at most hourly.
+	ShowFindings bool
+
+	// ShowHygiene adds a Hygiene section to the Overview tab flagging likely
+	// wasted resources of the other tabs: EC2 instances stopped for
+	// StoppedDays, target groups without targets, and SQS queues and DB
+	// instances without traffic in hygiene.IdleDays. It is opt-in because it
+	// reads a month of CloudWatch statistics of every queue and DB instance;
+	// it checks again at most hourly.
+	ShowHygiene bool
+
+	// StoppedDays is how many days an EC2 instance is stopped before the
+	// Hygiene section flags it. Defaults to hygiene.DefaultStoppedDays.
+	StoppedDays int
+
+	// ShowProbes adds a Probes tab that connects from this machine to the
+	// listeners of the internet-facing load balancers and to ProbePorts on
+	// the public IPs of the instances of the other tabs, showing whether they
+	// answer next to the health AWS reports for them. It is opt-in because
+	// it sends traffic to the resources from outside of AWS.
+	ShowProbes bool
+
+	// ProbePorts are the TCP ports probed on the public IPs of instances.
+	// Nil probes probe.DefaultPorts, an empty slice no instance.
+	ProbePorts []int32
+
+	// ProbeTimeout is how long a probe waits for a connection or a response.
+	// Defaults to probe.DefaultTimeout.
+	ProbeTimeout time.Duration
+
+	// ShowMetrics adds a CloudWatch tab browsing the namespaces, metrics and
+	// dimensions of the account, which plots any metric on demand and pins
+	// it to the Custom Metrics tab
+	ShowMetrics bool
+
+	// Pins are the metrics shown on the Custom Metrics tab, which is hidden
+	// when there are none and the CloudWatch tab is not shown
+	Pins []metrics.Pin
+
+	// PinsFile is where the pins are saved when they change on the
+	// CloudWatch or Custom Metrics tab. Empty keeps them for the session only.
+	PinsFile string
+
+	// ShowLag adds the consumer lag of Kinesis streams, DynamoDB streams and
+	// SQS queues to the top of the Overview tab
+	ShowLag bool
+
+	// Runbooks are the break-glass runbooks shown on the Runbooks tab, which
+	// is hidden when there are none. Running them requires AllowActions.
+	Runbooks []runbook.Runbook
+
+	// Alerts are checked after each load of their service without errors.
+	// A rule starting to be breached rings the terminal bell, flashes the
+	// tab of its service and the title of the terminal, and runs its command
+	// and webhook.
+	Alerts []alerts.Rule
+
+	// Providers adds a tab for each collector, after the tabs of the built-in
+	// services. Their names must be unique and differ from the built-in tabs.
+	Providers []providers.Provider
+
+	// Region is the AWS region to query. When empty the region is resolved
+	// from AWS_REGION, AWS_DEFAULT_REGION or the active profile.
+	Region string
+
+	// Endpoints overrides the endpoints of the AWS APIs, e.g. to run against
+	// LocalStack. The zero value calls AWS.
+	Endpoints config.Endpoints
+
+	// Retry is how AWS calls are retried by the retryer all clients share.
+	// Unset fields come from the AWS configuration, e.g. AWS_MAX_ATTEMPTS,
+	// or else the defaults, e.g. config.DefaultMaxAttempts.
+	Retry config.RetryPolicy
+
+	// RefreshInterval controls how often data is reloaded automatically.
+	// Defaults to DefaultRefreshInterval.
+	RefreshInterval time.Duration
+
+	// Embedded disables the q and ctrl+c quit keys so that a host program
+	// embedding the component stays in control of its own lifecycle.
+	Embedded bool
+
+	// Context is the parent context of all AWS calls. Cancelling it aborts
+	// in-flight requests. Defaults to context.Background().
+	Context context.Context
+
+	// Timeout bounds how long loading a single service may take, after which
+	// its tab shows the error. A refresh also cancels the fetches it
+	// replaces. Defaults to DefaultTimeout.
+	Timeout time.Duration
+
+	// RateLimits caps the API requests per second sent to each AWS service.
+	// Services without a limit (and no "default" entry) are not limited.
+	RateLimits config.RateLimits
+
+	// QueuePrefix limits the SQS queues to those whose name starts with it,
+	// reducing API calls in accounts with many queues.
+	QueuePrefix string
+
+	// ContainerInsights adds the CPU and memory utilization of each ECS
+	// service against its reservation to the ECS tab. It is opt-in because
+	// the metrics only exist for clusters with Container Insights enabled
+	// and cost CloudWatch calls for every service.
+	ContainerInsights bool
+
+	// MaxResults is how many resources each tab shows at first, with + on a
+	// tab showing as many more, so that huge accounts stay responsive. Load
+	// balancers and ECR repositories beyond it are not loaded at all, since
+	// each costs API calls of its own. Zero shows all resources.
+	MaxResults int
+
+	// MaxConcurrency caps the AWS calls the collectors run at once. Throttled
+	// calls are retried with exponential backoff. Defaults to
+	// common.DefaultMaxConcurrency.
+	MaxConcurrency int
+
+	// CacheTTL keeps AWS responses for this long, keyed by account, region
+	// and service, so automatic refreshes within the TTL do not call AWS
+	// again. Manual refreshes always reload. Zero disables the cache.
+	CacheTTL time.Duration
+
+	// CacheDir additionally stores cached responses on disk, so restarts
+	// within CacheTTL do not call AWS either. Empty keeps them in memory only.
+	CacheDir string
+
+	// Sealer encrypts the pins file and the on-disk cache, which hold
+	// resource names and account IDs. Nil writes them in the clear.
+	Sealer *seal.Sealer
+
+	// AllowActions enables actions that change resources or run code, such
+	// as test invocations of Lambda functions and one-off ECS tasks. The
+	// component is read-only without it.
+	AllowActions bool
+
+	// AuditLog records the actions taken, whether they succeeded or not. Nil
+	// records nothing.
+	AuditLog *audit.Log
+
+	// ASCIISymbols replaces emoji with ASCII fallbacks for terminals whose
+	// fonts cannot render them, such as the classic Windows console.
+	ASCIISymbols bool
+
+	// Theme is the color palette, see Themes and LookupTheme. Defaults to the
+	// DefaultTheme. The theme applies to the whole process, not just this
+	// component.
+	Theme Theme
+
+	// Graphs is the style of the metric graphs in the detail views, see
+	// common.GraphStyles. Defaults to common.GraphLine. Like the theme, it
+	// applies to the whole process.
+	Graphs common.GraphStyle
+
+	// NoColor marks the active tab in reverse video, for when colors are
+	// disabled through lipgloss, e.g. because NO_COLOR is set
+	NoColor bool
+
+	// Demo serves fixture data from pkg/demo instead of calling AWS, so the
+	// component works without credentials or network access.
+	Demo bool
+
+	// Restore is a previously saved session whose data is shown until the
+	// first load completes. It is ignored when it was saved for another
+	// profile or region, and dropped once the credentials turn out to belong
+	// to another account.
+	// Use Model.Snapshot to capture a session when the program exits.
+	Restore *session.Snapshot
+}
+
+// withDefaults returns a copy of the options with unset values filled in
+func (o Options) withDefaults() Options {
+	if o.RefreshInterval <= 0 {
+		o.RefreshInterval = DefaultRefreshInterval
+	}
+	if o.Context == nil {
+		o.Context = context.Background()
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultTimeout
+	}
+	if o.MetricsWindow <= 0 {
+		o.MetricsWindow = cloudwatchmetrics.DefaultWindow
+	}
+	if o.ProbePorts == nil {
+		o.ProbePorts = probe.DefaultPorts
+	}
+	if o.Theme == (Theme{}) {
+		o.Theme = Themes[DefaultTheme]
+	}
+	return o
+}
+
+// RefreshMsg asks the component to reload all enabled services. Host programs
+// can send it (for example via a tea.Cmd) to trigger a refresh on their own
+// schedule in addition to the automatic refresh.
+type RefreshMsg struct{}
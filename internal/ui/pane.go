@@ -0,0 +1,316 @@
+package ui
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/charmbracelet/bubbletea"
+
+	"github.com/correctedcloud/aws-overview/internal/permissions"
+	"github.com/correctedcloud/aws-overview/pkg/demo"
+	eventspkg "github.com/correctedcloud/aws-overview/pkg/events"
+	logspkg "github.com/correctedcloud/aws-overview/pkg/logs"
+	sqspkg "github.com/correctedcloud/aws-overview/pkg/sqs"
+)
+
+// resource is a resource selected on a tab, whose error logs, related
+// events, messages or graphs the resource pane shows in place of the tab
+type resource struct {
+	name       string         // e.g. "Lambda function resize"
+	logSources logSourcesFunc // Finds where the resource logs to, nil for resources without logs
+	events     eventspkg.Resource
+	messages   messagesFunc // Peeks at the messages of a queue, nil for other resources
+	graphs     graphsFunc   // Draws the metrics of the resource, nil for resources without
+}
+
+// logSourcesFunc finds where a resource logs to
+type logSourcesFunc func(ctx context.Context, client *logspkg.Client) ([]logspkg.Source, error)
+
+// messagesFunc peeks at the messages of a queue
+type messagesFunc func(ctx context.Context) ([]sqspkg.Message, error)
+
+// graphsFunc draws the metrics of a resource as detail graphs width columns
+// wide, from the data last loaded on its tab
+type graphsFunc func(m Model, width int) string
+
+// offers reports whether the pane can show kind for the resource
+func (r resource) offers(kind paneKind) bool {
+	switch kind {
+	case paneLogs:
+		return r.logSources != nil
+	case paneEvents:
+		return len(r.events.TrailNames) > 0 || len(r.events.AlarmDimensions) > 0 ||
+			r.events.ECSService != "" || r.events.RDSInstance != ""
+	case paneMessages:
+		return r.messages != nil
+	case paneGraphs:
+		return r.graphs != nil
+	}
+	return false
+}
+
+// paneKind is what the resource pane shows
+type paneKind int
+
+const (
+	paneLogs     paneKind = iota // Recent error log events
+	paneEvents                   // Recent alarms, changes and service events
+	paneMessages                 // Messages peeked at in a queue
+	paneGraphs                   // Detail graphs of the metrics loaded on the tab
+)
+
+// paneKeys are the keys opening each kind of pane
+var paneKeys = map[string]paneKind{"L": paneLogs, "E": paneEvents, "P": paneMessages, "G": paneGraphs}
+
+// paneKeyHelp describes the keys opening each kind of pane, in the order of
+// the kinds
+var paneKeyHelp = []string{"L Error Logs", "E Related Events", "P Peek Messages", "G Graphs"}
+
+// paneLoadedMsg carries the content loaded for a resource pane
+type paneLoadedMsg struct {
+	kind       paneKind
+	resource   string
+	logSources []logspkg.Source
+	logEvents  []logspkg.Event
+	events     []eventspkg.Event
+	messages   []sqspkg.Message
+	errs       []error
+}
+
+// paneOpen reports whether the resource pane replaces the content of the
+// active tab
+func (m Model) paneOpen() bool {
+	return m.paneService != "" && m.paneService == m.currentTab().service
+}
+
+// updatePaneKeys handles the keys of the resource pane on tabs with a
+// selection, before those of the tab: L, E, P and G open the pane on the
+// selected resource, switch what it shows or close it, for the kinds the
+// resource offers. While it is open the arrow keys scroll instead of
+// selecting, r loads it again and esc closes it. The graphs are drawn from
+// the data of the tab, so r refreshes the tab instead.
+func (m Model) updatePaneKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	if kind, ok := paneKeys[msg.String()]; ok {
+		if m.paneOpen() && m.pane == kind {
+			m.closePane()
+			return m, nil, true
+		}
+		if m.paneOpen() {
+			if !m.paneResource.offers(kind) {
+				return m, nil, true
+			}
+			cmd := m.openPane(kind, m.paneResource)
+			return m, cmd, true
+		}
+		selected, ok := m.currentTab().selected(m)
+		if !ok || !selected.offers(kind) {
+			return m, nil, true
+		}
+		cmd := m.openPane(kind, selected)
+		return m, cmd, true
+	}
+
+	if !m.paneOpen() {
+		return m, nil, false
+	}
+	switch msg.String() {
+	case "up", "k", "down", "j":
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd, true
+	case "r":
+		if m.pane == paneGraphs {
+			return m, nil, false
+		}
+		if m.loadingPane {
+			return m, nil, true
+		}
+		m.loadingPane = true
+		m.updateViewportContent()
+		return m, m.loadPane(m.pane, m.paneResource), true
+	case "esc":
+		m.closePane()
+		return m, nil, true
+	}
+	return m, nil, false
+}
+
+// openPane opens the resource pane on the active tab and loads what it shows
+// of selected
+func (m *Model) openPane(kind paneKind, selected resource) tea.Cmd {
+	m.paneService = m.currentTab().service
+	m.pane = kind
+	m.paneResource = selected
+	m.loadingPane = kind != paneGraphs
+	m.logSources, m.logEvents, m.relatedEvents, m.queueMessages, m.paneErrs = nil, nil, nil, nil, nil
+	m.updateViewportContent()
+	m.viewport.GotoTop()
+	return m.loadPane(kind, selected)
+}
+
+// closePane closes the resource pane, showing its tab again
+func (m *Model) closePane() {
+	m.paneService = ""
+	m.updateViewportContent()
+	m.viewport.GotoTop()
+}
+
+// loadPane is a command that loads the error events or related events of
+// the past hour of selected, or peeks at its messages. Graphs need no load.
+func (m Model) loadPane(kind paneKind, selected resource) tea.Cmd {
+	if kind == paneGraphs {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(m.ctx, m.timeout)
+		defer cancel()
+
+		msg := paneLoadedMsg{kind: kind, resource: selected.name}
+		if kind == paneMessages {
+			var err error
+			msg.messages, err = selected.messages(ctx)
+			if err != nil {
+				msg.errs = []error{err}
+			}
+			return msg
+		}
+		if kind == paneEvents {
+			client, err := m.eventsClient(ctx)
+			if err != nil {
+				msg.errs = []error{err}
+				return msg
+			}
+			msg.events, msg.errs = client.Recent(ctx, selected.events)
+			return msg
+		}
+
+		client, err := m.logsClient(ctx)
+		if err != nil {
+			msg.errs = []error{err}
+			return msg
+		}
+		msg.logSources, err = selected.logSources(ctx, client)
+		if err != nil {
+			msg.errs = []error{err}
+			return msg
+		}
+		msg.logEvents, msg.errs = client.TailErrors(ctx, msg.logSources)
+		return msg
+	}
+}
+
+// logsClient returns a CloudWatch Logs client for the resource pane
+func (m Model) logsClient(ctx context.Context) (*logspkg.Client, error) {
+	if m.demo {
+		return logspkg.NewClient(demo.NewCloudWatchLogs(), m.pool), nil
+	}
+
+	awsConfig, _, err := m.loadAWSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return logspkg.NewClient(cloudwatchlogs.NewFromConfig(m.limiters.Apply(awsConfig, "logs")), m.pool), nil
+}
+
+// eventsClient returns a client of the sources of related events for the
+// resource pane
+func (m Model) eventsClient(ctx context.Context) (*eventspkg.Client, error) {
+	if m.demo {
+		return eventspkg.NewClient(demo.NewCloudWatch(), demo.NewCloudTrail(), demo.NewECS(), demo.NewRDS(), m.pool), nil
+	}
+
+	awsConfig, _, err := m.loadAWSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return eventspkg.NewClient(
+		cloudwatch.NewFromConfig(m.limiters.Apply(awsConfig, "cloudwatch")),
+		cloudtrail.NewFromConfig(m.limiters.Apply(awsConfig, "cloudtrail")),
+		ecs.NewFromConfig(m.limiters.Apply(awsConfig, "ecs")),
+		rds.NewFromConfig(m.limiters.Apply(awsConfig, "rds")),
+		m.pool,
+	), nil
+}
+
+// updatePane shows the loaded content if the pane still shows it
+func (m *Model) updatePane(msg paneLoadedMsg) {
+	if msg.resource != m.paneResource.name || msg.kind != m.pane {
+		return
+	}
+	m.loadingPane = false
+	m.logSources, m.logEvents, m.relatedEvents, m.queueMessages, m.paneErrs = msg.logSources, msg.logEvents, msg.events, msg.messages, msg.errs
+	m.updateViewportContent()
+}
+
+// renderPane shows the resource pane in place of the tab content
+func (m Model) renderPane() string {
+	name := m.paneResource.name
+	if m.pane == paneGraphs {
+		return m.paneResource.graphs(m, m.viewport.Width)
+	}
+	if m.pane == paneMessages {
+		if m.loadingPane {
+			return m.spinner.View() + " Peeking at the messages of " + name + "..."
+		}
+		if len(m.paneErrs) > 0 {
+			return "Error peeking at the messages of " + name + ": " + permissions.DescribeAll(m.paneErrs) + "\n\n" + renderHints(m.paneErrs)
+		}
+		return sqspkg.FormatMessages(name, m.queueMessages)
+	}
+	if m.pane == paneEvents {
+		if m.loadingPane {
+			return m.spinner.View() + " Loading the related events of " + name + "..."
+		}
+		if len(m.paneErrs) > 0 && len(m.relatedEvents) == 0 {
+			return "Error loading the related events of " + name + ": " + permissions.DescribeAll(m.paneErrs) + "\n\n" + renderHints(m.paneErrs)
+		}
+		return renderLoadErrors(m.paneErrs) + eventspkg.FormatEvents(name, m.relatedEvents)
+	}
+
+	if m.loadingPane {
+		return m.spinner.View() + " Tailing the error logs of " + name + "..."
+	}
+	if len(m.paneErrs) > 0 && len(m.logEvents) == 0 {
+		return "Error tailing the logs of " + name + ": " + permissions.DescribeAll(m.paneErrs) + "\n\n" + renderHints(m.paneErrs)
+	}
+
+	view := renderLoadErrors(m.paneErrs) + logspkg.FormatEvents(name, m.logSources, m.logEvents)
+	if len(m.logSources) == 0 && m.paneService == "rds" {
+		view += "\nPublish the instance's logs to CloudWatch Logs to tail them here.\n"
+	}
+	return view
+}
+
+// paneHelp describes the keys of the resource pane
+func (m Model) paneHelp() string {
+	switch m.pane {
+	case paneMessages:
+		return "↑↓/j k Scroll • r Peek Again • esc Close Messages"
+	case paneEvents:
+		return "↑↓/j k Scroll • r Reload Events • L Error Logs • esc Close Events"
+	case paneGraphs:
+		return "↑↓/j k Scroll • r Refresh • esc Close Graphs"
+	}
+	return "↑↓/j k Scroll • r Tail Again • E Related Events • esc Close Logs"
+}
+
+// paneKeysHelp describes the keys opening the resource pane on the selected
+// resource of the active tab
+func (m Model) paneKeysHelp() string {
+	selected, ok := m.currentTab().selected(m)
+	if !ok {
+		return ""
+	}
+	var keys []string
+	for kind, help := range paneKeyHelp {
+		if selected.offers(paneKind(kind)) {
+			keys = append(keys, help)
+		}
+	}
+	return strings.Join(keys, " • ")
+}
@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/bubbletea"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// RenderPlain loads the services selected by opts once and returns their
+// tabs as plain text, using the plain formatters instead of tables. It backs
+// the -no-tui output for scripts and pipes.
+func RenderPlain(opts Options) string {
+	m := loadOnce(opts)
+
+	var output strings.Builder
+	if m.tabs[0].load != nil {
+		output.WriteString(m.renderLagSummary())
+	}
+	for _, t := range m.tabs[1:] {
+		if t.load == nil {
+			continue
+		}
+		output.WriteString(strings.TrimRight(t.render(m), "\n") + "\n\n")
+	}
+
+	if m.asciiSymbols {
+		return common.ASCIISymbols(output.String())
+	}
+	return output.String()
+}
+
+// selection returns index, the selected row of a tab, or -1 in one-shot
+// output, where no row is selected
+func (m Model) selection(index int) int {
+	if m.plain {
+		return -1
+	}
+	return index
+}
+
+// loadOnce returns a model for one-shot output with the services selected
+// by opts loaded
+func loadOnce(opts Options) Model {
+	m := NewModel(opts)
+	m.plain = true
+	// There is no + key to show more in one-shot output
+	m.maxResults = 0
+
+	// Commands are built on this goroutine, since fetch records them in a map
+	for _, msg := range collect(m.refreshData()) {
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+	return m
+}
+
+// collect runs cmd to completion and returns its messages. Batched commands
+// run concurrently, and the partial results of progressive fetches are
+// skipped in favor of their complete results.
+func collect(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+
+	switch msg := cmd().(type) {
+	case nil:
+		return nil
+	case tea.BatchMsg:
+		results := make([][]tea.Msg, len(msg))
+		var wg sync.WaitGroup
+		for i, cmd := range msg {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i] = collect(cmd)
+			}()
+		}
+		wg.Wait()
+
+		var msgs []tea.Msg
+		for _, result := range results {
+			msgs = append(msgs, result...)
+		}
+		return msgs
+	case partialMsg:
+		return collect(msg.next)
+	default:
+		return []tea.Msg{msg}
+	}
+}
@@ -0,0 +1,28 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderPlainMarksNoSelection(t *testing.T) {
+	output := RenderPlain(Options{
+		Demo:           true,
+		ShowALB:        true,
+		ShowRDS:        true,
+		ShowECS:        true,
+		ShowLambda:     true,
+		ShowCloudFront: true,
+	})
+
+	for _, expected := range []string{"LAMBDA FUNCTIONS", "ECS"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain '%s', got:\n%s", expected, output)
+		}
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, ">") {
+			t.Errorf("Expected no selected row in plain output, got '%s'", line)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"context"
+	"net"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/correctedcloud/aws-overview/pkg/demo"
+	probepkg "github.com/correctedcloud/aws-overview/pkg/probe"
+)
+
+// probesDataLoadedMsg carries the results of probing the inventory
+type probesDataLoadedMsg struct {
+	results []probepkg.Result
+}
+
+// newProber returns the client probing the inventory, which probes the
+// fixture network in demo mode
+func newProber(opts Options) *probepkg.Client {
+	if opts.Demo {
+		network := demo.NewNetwork()
+		return probepkg.NewClient(network, network, opts.ProbeTimeout)
+	}
+	return probepkg.NewClient(&net.Dialer{}, probepkg.NewHTTPClient(), opts.ProbeTimeout)
+}
+
+// hasProbeInventory reports whether a service whose resources the Probes
+// tab probes is enabled
+func (m Model) hasProbeInventory() bool {
+	return m.hasTab("alb") || m.hasTab("ec2")
+}
+
+// probeInventoryLoading reports whether a probed service has not loaded yet
+func (m Model) probeInventoryLoading() bool {
+	return m.hasTab("alb") && m.loadingALB || m.hasTab("ec2") && m.loadingEC2
+}
+
+// loadProbesData is a command that probes the load balancers and instances
+// of the inventory from this machine and returns a message. It waits for the
+// first load of the inventory, which starts it once complete.
+func (m Model) loadProbesData() tea.Cmd {
+	if m.probeInventoryLoading() {
+		return nil
+	}
+	inventory := m.inventory()
+	targets := probepkg.Targets(inventory.LoadBalancers, inventory.Instances, m.probePorts)
+
+	// Probes are not cached: they check the network as it is now, which is
+	// their point, and make no AWS calls
+	return m.fetch("probe", func(ctx context.Context) tea.Msg {
+		return probesDataLoadedMsg{results: m.prober.Probe(ctx, targets)}
+	})
+}
+
+// loadProbesAfterInventory starts the first load of the Probes tab once the
+// inventory it probes has loaded
+func (m Model) loadProbesAfterInventory() tea.Cmd {
+	if !m.hasTab("probe") || !m.loadingProbes || m.fetching("probe") {
+		return nil
+	}
+	return m.loadProbesData()
+}
+
+// renderProbes shows whether the endpoints answer from this machine next
+// to their health in AWS
+func (m Model) renderProbes() string {
+	if !m.hasProbeInventory() {
+		return "Probes check the load balancers and instances of the Load Balancers and EC2 tabs; enable at least one of them, e.g. with -alb -ec2"
+	}
+	if m.loadingProbes {
+		if m.probeInventoryLoading() {
+			return m.spinner.View() + " Waiting for the inventory to load..."
+		}
+		return m.spinner.View() + " Probing..."
+	}
+
+	return probepkg.FormatResults(m.probeResults)
+}
+
+// renderProbesSummary shows how many endpoints are unreachable on the
+// Overview tab
+func (m Model) renderProbesSummary() string {
+	if m.loadingProbes || !m.hasProbeInventory() {
+		return ""
+	}
+
+	header := lipgloss.NewStyle().Foreground(successColor).Bold(true).Render("✅ Probes: ")
+	for _, result := range m.probeResults {
+		if !result.Reachable {
+			header = lipgloss.NewStyle().Foreground(warningColor).Bold(true).Render("⚠️ Probes: ")
+			break
+		}
+	}
+	return header +
+		lipgloss.NewStyle().Foreground(textColor).Render(probepkg.GetResultsSummary(m.probeResults)) + "\n\n"
+}
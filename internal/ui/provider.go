@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/correctedcloud/aws-overview/internal/permissions"
+	"github.com/correctedcloud/aws-overview/internal/providers"
+)
+
+// providerLoadedMsg carries the outcome of loading a provider
+type providerLoadedMsg struct {
+	name    string
+	summary providers.Summary
+	err     error
+}
+
+// providerResult is the outcome of a provider's last load
+type providerResult struct {
+	loaded  bool
+	summary providers.Summary
+	err     error
+}
+
+// providerTab returns the tab of a provider added through Options.Providers
+func providerTab(p providers.Provider) tab {
+	return tab{
+		name:    p.Name(),
+		service: "provider:" + p.Name(),
+		load:    func(m Model) tea.Cmd { return m.loadProvider(p) },
+		render:  func(m Model) string { return m.renderProvider(p) },
+		summary: func(m Model) string { return m.renderProviderSummary(p) },
+		loading: func(m Model) bool { return !m.providerResults[p.Name()].loaded },
+	}
+}
+
+// loadProvider is a command that loads a provider and returns a message.
+// Providers are not cached or saved with the session.
+func (m Model) loadProvider(p providers.Provider) tea.Cmd {
+	return m.fetch("provider:"+p.Name(), func(ctx context.Context) tea.Msg {
+		summary, err := p.Load(ctx)
+		return providerLoadedMsg{name: p.Name(), summary: summary, err: err}
+	})
+}
+
+// updateProvider records the outcome of loading a provider
+func (m *Model) updateProvider(msg providerLoadedMsg) {
+	m.loaded("provider:"+msg.name, time.Time{})
+	m.providerResults[msg.name] = providerResult{loaded: true, summary: msg.summary, err: msg.err}
+	m.updateViewportContent()
+}
+
+// renderProvider shows the resources of a provider
+func (m Model) renderProvider(p providers.Provider) string {
+	result := m.providerResults[p.Name()]
+	if !result.loaded {
+		return m.spinner.View() + " Loading " + p.Name() + "..."
+	}
+	if result.err != nil {
+		return "Error loading " + p.Name() + ": " + permissions.Describe(result.err) + "\n\n" + renderHints([]error{result.err})
+	}
+	return p.Render(m.viewport.Width)
+}
+
+// renderProviderSummary shows the summary of a provider on the Overview tab
+func (m Model) renderProviderSummary(p providers.Provider) string {
+	result := m.providerResults[p.Name()]
+	switch {
+	case !result.loaded:
+		return m.spinner.View() + " " + p.Name() + "\n\n"
+	case result.err != nil:
+		return lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render("❌ "+p.Name()+" Error: ") +
+			lipgloss.NewStyle().Foreground(errorColor).Render(permissions.Describe(result.err)) + "\n\n"
+	}
+
+	var content strings.Builder
+	content.WriteString(lipgloss.NewStyle().Foreground(successColor).Bold(true).Render("✅ "+p.Name()+": ") +
+		lipgloss.NewStyle().Foreground(textColor).Render(result.summary.Text) + "\n")
+	for _, warning := range result.summary.Warnings {
+		content.WriteString(lipgloss.NewStyle().Foreground(warningColor).Bold(true).Render("   ⚠️ "+warning) + "\n")
+	}
+	content.WriteString("\n")
+	return content.String()
+}
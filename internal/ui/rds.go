@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"context"
+
+	"github.com/charmbracelet/bubbletea"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+	eventspkg "github.com/correctedcloud/aws-overview/pkg/events"
+	logspkg "github.com/correctedcloud/aws-overview/pkg/logs"
+	"github.com/correctedcloud/aws-overview/pkg/rds"
+)
+
+// updateRDSKeys handles the keys of the RDS tab: the arrow keys select an
+// instance instead of scrolling
+func (m Model) updateRDSKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "up", "k":
+		m.rdsSelected = max(0, m.rdsSelected-1)
+	case "down", "j":
+		m.rdsSelected = max(0, min(m.shown("rds", len(m.dbInstances))-1, m.rdsSelected+1))
+	default:
+		return m, nil, false
+	}
+	m.updateViewportContent()
+	m.scrollToSelection(m.renderRDS())
+	return m, nil, true
+}
+
+// selectedDBInstance returns the instance selected on the RDS tab
+func (m Model) selectedDBInstance() (rds.DBInstanceSummary, bool) {
+	sorted := m.sortedDBInstances()
+	if m.rdsSelected < 0 || m.rdsSelected >= len(sorted) {
+		return rds.DBInstanceSummary{}, false
+	}
+	return sorted[m.rdsSelected], true
+}
+
+// sortedDBInstances returns the instances shown on the RDS tab in the order
+// they are listed, which follows the table unless the output is plain
+func (m Model) sortedDBInstances() []rds.DBInstanceSummary {
+	if m.plain {
+		return capRows(m, "rds", m.dbInstances)
+	}
+	return capRows(m, "rds", common.SortRows(m.dbInstances, rds.Columns, m.sortKeys["rds"]))
+}
+
+// selectedInstanceResource returns the instance selected on the RDS tab for
+// the resource pane. It logs to the log groups it publishes its logs to, and
+// its metrics are graphed in detail.
+func (m Model) selectedInstanceResource() (resource, bool) {
+	instance, ok := m.selectedDBInstance()
+	if !ok {
+		return resource{}, false
+	}
+	return resource{
+		name: "RDS instance " + instance.Identifier,
+		logSources: func(ctx context.Context, client *logspkg.Client) ([]logspkg.Source, error) {
+			groups, err := client.GroupsWithPrefix(ctx, logspkg.RDSLogGroupPrefix(instance.Identifier))
+			if err != nil {
+				return nil, err
+			}
+			sources := make([]logspkg.Source, len(groups))
+			for i, group := range groups {
+				sources[i] = logspkg.Source{Group: group}
+			}
+			return sources, nil
+		},
+		events: eventspkg.Resource{
+			TrailNames:      []string{instance.Identifier},
+			AlarmDimensions: map[string]string{"DBInstanceIdentifier": instance.Identifier},
+			RDSInstance:     instance.Identifier,
+		},
+		graphs: func(m Model, width int) string {
+			// The instance as last loaded, which may be newer than the selection
+			for _, current := range m.dbInstances {
+				if current.Identifier == instance.Identifier {
+					return rds.FormatInstanceGraphs(current, width)
+				}
+			}
+			return rds.FormatInstanceGraphs(instance, width)
+		},
+	}, true
+}
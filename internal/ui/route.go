@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/correctedcloud/aws-overview/pkg/alb"
+)
+
+// newRouteInput returns the prompt of the listener rule simulator
+func newRouteInput() textinput.Model {
+	input := textinput.New()
+	input.Prompt = "Test route › "
+	input.Placeholder = "[METHOD] host/path?query Header:value ..."
+	input.CharLimit = 512
+	return input
+}
+
+// updateALBKeys handles the keys of the Load Balancers tab: t opens the route
+// prompt, the arrow keys select a target instead of scrolling, a asks to
+// deregister or register it and esc closes the simulation result and the
+// outcome of the last target action. While confirming, y runs the action and
+// any other key cancels.
+func (m Model) updateALBKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	if m.albConfirming != "" {
+		return m.updateTargetConfirm(msg)
+	}
+
+	switch msg.String() {
+	case "t":
+		return m, m.routeInput.Focus(), true
+	case "up", "k", "down", "j":
+		if _, _, ok := m.selectedTarget(); !ok {
+			return m, nil, false
+		}
+		if msg.String() == "up" || msg.String() == "k" {
+			m.moveTargetSelection(-1)
+		} else {
+			m.moveTargetSelection(1)
+		}
+		return m, nil, true
+	case "a":
+		m.confirmTargetAction()
+		return m, nil, true
+	case "esc":
+		if m.albActing {
+			return m, nil, true
+		}
+		if m.routeRequest == nil && m.albActionNote == "" && m.albActionErr == nil {
+			return m, nil, false
+		}
+		m.routeRequest = nil
+		m.albActionNote = ""
+		m.albActionErr = nil
+		m.updateViewportContent()
+		return m, nil, true
+	}
+	return m, nil, false
+}
+
+// albHelp describes the keys of the Load Balancers tab
+func (m Model) albHelp() string {
+	if m.albConfirming != "" {
+		return "y Confirm • any other key Cancel"
+	}
+	target, _, ok := m.selectedTarget()
+	switch {
+	case !ok:
+		return "t Test Route"
+	case !m.allowActions:
+		return "t Test Route • ↑↓ Select"
+	}
+	return "t Test Route • ↑↓ Select • a " + string(alb.ActionFor(target.Target))
+}
+
+// updateRouteInput handles a key while the route prompt has focus. Enter
+// simulates the request against the listener rules and esc closes the prompt.
+func (m Model) updateRouteInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.routeInput.Blur()
+		m.routeErr = nil
+		return m, nil
+	case "enter":
+		request, err := alb.ParseRequest(m.routeInput.Value())
+		if err != nil {
+			m.routeErr = err
+			return m, nil
+		}
+		m.routeInput.Blur()
+		m.routeRequest = &request
+		m.routeErr = nil
+		m.updateViewportContent()
+		m.viewport.GotoTop()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.routeInput, cmd = m.routeInput.Update(msg)
+	return m, cmd
+}
+
+// renderRouteInput shows the route prompt and why the last input was rejected
+func (m Model) renderRouteInput() string {
+	view := m.routeInput.View()
+	if m.routeErr != nil {
+		view += "  " + lipgloss.NewStyle().Foreground(errorColor).Render(m.routeErr.Error())
+	}
+	return view
+}
+
+// renderRouteSimulation shows where the last simulated request is routed,
+// or nothing when no request has been simulated
+func (m Model) renderRouteSimulation() string {
+	if m.routeRequest == nil {
+		return ""
+	}
+	return alb.FormatRouteSimulation(m.loadBalancers, *m.routeRequest) + "\n"
+}
@@ -0,0 +1,170 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/correctedcloud/aws-overview/internal/audit"
+	"github.com/correctedcloud/aws-overview/internal/permissions"
+	"github.com/correctedcloud/aws-overview/internal/runbook"
+	"github.com/correctedcloud/aws-overview/pkg/demo"
+)
+
+// runbookRanMsg carries the outcome of each step of a runbook
+type runbookRanMsg struct {
+	runbook runbook.Runbook
+	results []runbook.StepResult
+	err     error
+}
+
+// updateRunbookKeys handles the keys of the Runbooks tab: the arrow keys
+// select a runbook, enter asks to confirm running it and y confirms. While
+// confirming, any other key cancels.
+func (m Model) updateRunbookKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	if m.confirmingRunbook {
+		if msg.String() == "ctrl+c" {
+			return m, nil, false
+		}
+		m.confirmingRunbook = false
+		if msg.String() != "y" {
+			m.updateViewportContent()
+			return m, nil, true
+		}
+		selected := m.runbooks[m.runbookSelected]
+		m.runningRunbook = true
+		m.runbookResults = nil
+		m.runbookErr = nil
+		m.updateViewportContent()
+		m.viewport.GotoTop()
+		return m, m.runRunbook(selected), true
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		m.moveRunbookSelection(-1)
+		return m, nil, true
+	case "down", "j":
+		m.moveRunbookSelection(1)
+		return m, nil, true
+	case "enter":
+		if m.runningRunbook || len(m.runbooks) == 0 {
+			return m, nil, true
+		}
+		if !m.allowActions {
+			m.runbookErr = errActionsDisabled
+		} else {
+			m.runbookErr = nil
+			m.confirmingRunbook = true
+		}
+		m.updateViewportContent()
+		m.viewport.GotoTop()
+		return m, nil, true
+	case "esc":
+		if m.runningRunbook {
+			return m, nil, true
+		}
+		m.runbookResults = nil
+		m.runbookErr = nil
+		m.updateViewportContent()
+		return m, nil, true
+	}
+	return m, nil, false
+}
+
+// runbookHelp describes the keys of the Runbooks tab
+func (m Model) runbookHelp() string {
+	switch {
+	case m.confirmingRunbook:
+		return "y Run • any other key Cancel"
+	case !m.allowActions:
+		return "↑↓ Select"
+	}
+	return "↑↓ Select • enter Run"
+}
+
+// moveRunbookSelection moves the selection by delta runbooks and scrolls the
+// viewport so the selected runbook stays visible
+func (m *Model) moveRunbookSelection(delta int) {
+	if len(m.runbooks) == 0 {
+		return
+	}
+	m.runbookSelected = max(0, min(len(m.runbooks)-1, m.runbookSelected+delta))
+	m.updateViewportContent()
+	m.scrollToSelection(m.renderRunbooks())
+}
+
+// runRunbook is a command that runs the steps of a runbook
+func (m Model) runRunbook(selected runbook.Runbook) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(m.ctx, m.timeout)
+		defer cancel()
+
+		if m.demo {
+			runner := runbook.NewRunner(demo.NewECS(), demo.NewEventBridge(), demo.NewCloudWatch())
+			return runbookRanMsg{runbook: selected, results: runner.Run(ctx, selected)}
+		}
+
+		awsConfig, _, err := m.loadAWSConfig(ctx)
+		if err != nil {
+			return runbookRanMsg{runbook: selected, err: err}
+		}
+
+		runner := runbook.NewRunner(
+			ecs.NewFromConfig(m.limiters.Apply(awsConfig, "ecs")),
+			eventbridge.NewFromConfig(m.limiters.Apply(awsConfig, "events")),
+			cloudwatch.NewFromConfig(m.limiters.Apply(awsConfig, "cloudwatch")),
+		)
+		return runbookRanMsg{runbook: selected, results: runner.Run(ctx, selected)}
+	}
+}
+
+// updateRunbook records the outcome of a run
+func (m *Model) updateRunbook(msg runbookRanMsg) {
+	m.runningRunbook = false
+	if msg.err != nil {
+		m.audit("Runbooks", audit.Entry{Action: "runbook", Resource: msg.runbook.Name}, msg.err)
+	}
+	for _, result := range msg.results {
+		m.audit("Runbooks", audit.Entry{Action: "runbook:" + result.Step.Action, Resource: msg.runbook.Name, Detail: result.Step.Describe()}, result.Err)
+	}
+	m.ranRunbook = msg.runbook
+	m.runbookResults = msg.results
+	m.runbookErr = msg.err
+	m.updateViewportContent()
+}
+
+// renderRunbooks shows the confirmation or outcome of a run above the runbooks
+func (m Model) renderRunbooks() string {
+	var content string
+	switch {
+	case m.confirmingRunbook:
+		selected := m.runbooks[m.runbookSelected]
+		question := fmt.Sprintf("Run runbook %s? It makes %d changes", selected.Name, len(selected.Steps))
+		if m.region != "" {
+			question += " in " + m.region
+		}
+		content = lipgloss.NewStyle().Foreground(warningColor).Bold(true).Render(question+":") + "\n"
+		for i, step := range selected.Steps {
+			content += fmt.Sprintf("  %d. %s\n", i+1, step.Describe())
+		}
+		content += lipgloss.NewStyle().Foreground(warningColor).Render("Press y to run, any other key to cancel") + "\n\n"
+	case m.runningRunbook:
+		content = m.spinner.View() + " Running runbook...\n\n"
+	case m.runbookErr != nil:
+		content = "Runbook failed: " + permissions.Describe(m.runbookErr) + "\n" + renderHints([]error{m.runbookErr}) + "\n"
+	case m.runbookResults != nil:
+		content = runbook.FormatResults(m.ranRunbook, m.runbookResults)
+		if failed := runbook.Failed(m.runbookResults); failed > 0 {
+			content += lipgloss.NewStyle().Foreground(errorColor).Render(
+				fmt.Sprintf("%d of %d steps failed", failed, len(m.runbookResults))) + "\n"
+		}
+		content += "\n"
+	}
+	return content + runbook.FormatRunbooks(m.runbooks, m.runbookSelected)
+}
@@ -0,0 +1,227 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/correctedcloud/aws-overview/internal/audit"
+	"github.com/correctedcloud/aws-overview/internal/permissions"
+	ecspkg "github.com/correctedcloud/aws-overview/pkg/ecs"
+)
+
+// maxDesiredCount bounds the desired count entered in the scale prompt, to
+// catch a mistyped extra digit before it is confirmed
+const maxDesiredCount = 1000
+
+// ecsUpdate is a change to an ECS service made with UpdateService
+type ecsUpdate struct {
+	service      ecspkg.ServiceSummary
+	redeploy     bool  // Force a new deployment rather than scale
+	desiredCount int32 // Desired count when scaling
+}
+
+// describe returns what the update does, e.g. "scale web from 2 to 4 tasks"
+func (u ecsUpdate) describe() string {
+	if u.redeploy {
+		return "force a new deployment of " + u.service.ServiceName
+	}
+	return fmt.Sprintf("scale %s from %d to %d tasks", u.service.ServiceName, u.service.DesiredCount, u.desiredCount)
+}
+
+// ecsUpdatedMsg is sent when an update of a service was requested
+type ecsUpdatedMsg struct {
+	update ecsUpdate
+	err    error
+}
+
+// newScaleInput returns the prompt of the desired count of a service
+func newScaleInput() textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "desired count"
+	input.CharLimit = 6
+	return input
+}
+
+// openScaleInput starts entering the desired count of the selected service,
+// starting from its current count
+func (m *Model) openScaleInput() tea.Cmd {
+	service, ok := m.selectedService()
+	if !ok || m.ecsUpdating {
+		return nil
+	}
+	m.ecsUpdateNote = ""
+	m.ecsUpdateErr = nil
+	if !m.allowActions {
+		m.ecsUpdateErr = errActionsDisabled
+		m.updateViewportContent()
+		return nil
+	}
+
+	m.scaleInput.Prompt = "Scale " + service.ServiceName + " to › "
+	m.scaleInput.SetValue(strconv.Itoa(int(service.DesiredCount)))
+	m.scaleInput.CursorEnd()
+	return m.scaleInput.Focus()
+}
+
+// confirmRedeploy asks to confirm a forced new deployment of the selected
+// service
+func (m *Model) confirmRedeploy() {
+	service, ok := m.selectedService()
+	if !ok || m.ecsUpdating {
+		return
+	}
+	m.ecsUpdateNote = ""
+	m.ecsUpdateErr = nil
+	if !m.allowActions {
+		m.ecsUpdateErr = errActionsDisabled
+	} else {
+		m.ecsConfirming = &ecsUpdate{service: service, redeploy: true}
+	}
+	m.updateViewportContent()
+	m.viewport.GotoTop()
+}
+
+// updateScaleInput handles a key while the scale prompt has focus. Enter asks
+// to confirm the new count and esc closes the prompt.
+func (m Model) updateScaleInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.scaleInput.Blur()
+		m.ecsUpdateErr = nil
+		return m, nil
+	case "enter":
+		count, err := parseDesiredCount(m.scaleInput.Value())
+		if err != nil {
+			m.ecsUpdateErr = err
+			return m, nil
+		}
+		service, ok := m.selectedService()
+		if !ok {
+			return m, nil
+		}
+
+		m.scaleInput.Blur()
+		m.ecsUpdateErr = nil
+		m.ecsConfirming = &ecsUpdate{service: service, desiredCount: count}
+		m.updateViewportContent()
+		m.viewport.GotoTop()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.scaleInput, cmd = m.scaleInput.Update(msg)
+	return m, cmd
+}
+
+// parseDesiredCount parses the desired count entered in the scale prompt
+func parseDesiredCount(value string) (int32, error) {
+	count, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || count < 0 {
+		return 0, fmt.Errorf("desired count must be a whole number of at least 0")
+	}
+	if count > maxDesiredCount {
+		return 0, fmt.Errorf("desired count must be at most %d", maxDesiredCount)
+	}
+	return int32(count), nil
+}
+
+// updateECSConfirm handles a key while an update waits for confirmation: y
+// requests it and any other key cancels
+func (m Model) updateECSConfirm(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	if msg.String() == "ctrl+c" {
+		return m, nil, false
+	}
+	update := *m.ecsConfirming
+	m.ecsConfirming = nil
+	if msg.String() != "y" {
+		m.updateViewportContent()
+		return m, nil, true
+	}
+	m.ecsUpdating = true
+	m.updateViewportContent()
+	m.viewport.GotoTop()
+	return m, m.updateService(update), true
+}
+
+// updateService is a command that requests the update of a service
+func (m Model) updateService(update ecsUpdate) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(m.ctx, m.timeout)
+		defer cancel()
+
+		client, err := m.ecsActionClient(ctx)
+		if err != nil {
+			return ecsUpdatedMsg{update: update, err: err}
+		}
+		if update.redeploy {
+			err = client.ForceNewDeployment(ctx, update.service)
+		} else {
+			err = client.ScaleService(ctx, update.service, update.desiredCount)
+		}
+		return ecsUpdatedMsg{update: update, err: err}
+	}
+}
+
+// updateECSUpdated records the outcome of an update in the audit log and on
+// the tab, and reloads the services to show their new counts and deployments
+func (m Model) updateECSUpdated(msg ecsUpdatedMsg) (Model, tea.Cmd) {
+	m.ecsUpdating = false
+	m.ecsUpdateErr = msg.err
+
+	detail := fmt.Sprintf("desired count %d -> %d", msg.update.service.DesiredCount, msg.update.desiredCount)
+	if msg.update.redeploy {
+		detail = "force new deployment"
+	}
+	m.audit("ecs", audit.Entry{
+		Action:   "ecs:UpdateService",
+		Resource: msg.update.service.ClusterName + "/" + msg.update.service.ServiceName,
+		Detail:   detail,
+	}, msg.err)
+
+	if msg.err != nil {
+		m.updateViewportContent()
+		return m, nil
+	}
+	m.ecsUpdateNote = "Requested to " + msg.update.describe()
+	m.updateViewportContent()
+	return m, m.fresh().loadECSData()
+}
+
+// renderScaleInput shows the scale prompt and why the last input was rejected
+func (m Model) renderScaleInput() string {
+	view := m.scaleInput.View()
+	if m.ecsUpdateErr != nil {
+		view += "  " + lipgloss.NewStyle().Foreground(errorColor).Render(m.ecsUpdateErr.Error())
+	}
+	return view
+}
+
+// renderECSUpdate shows the confirmation or the outcome of an update above
+// the services
+func (m Model) renderECSUpdate() string {
+	switch {
+	case m.ecsConfirming != nil:
+		question := "Really " + m.ecsConfirming.describe()
+		if cluster := m.ecsConfirming.service.ClusterName; cluster != "" {
+			question += " in cluster " + cluster
+		}
+		if m.region != "" {
+			question += " in " + m.region
+		}
+		return lipgloss.NewStyle().Foreground(warningColor).Bold(true).Render(question+"?") + "\n" +
+			lipgloss.NewStyle().Foreground(warningColor).Render("Press y to confirm, any other key to cancel") + "\n\n"
+	case m.ecsUpdating:
+		return m.spinner.View() + " Updating service...\n\n"
+	case m.ecsUpdateErr != nil && !m.scaleInput.Focused():
+		return "Update failed: " + permissions.Describe(m.ecsUpdateErr) + "\n" + renderHints([]error{m.ecsUpdateErr}) + "\n"
+	case m.ecsUpdateNote != "":
+		return lipgloss.NewStyle().Foreground(successColor).Render(m.ecsUpdateNote) + "\n\n"
+	}
+	return ""
+}
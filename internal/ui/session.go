@@ -0,0 +1,180 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/correctedcloud/aws-overview/internal/session"
+	ecspkg "github.com/correctedcloud/aws-overview/pkg/ecs"
+)
+
+// Snapshot captures the data and UI state of the model so it can be saved on exit
+func (m Model) Snapshot() session.Snapshot {
+	// Restored data still shown belongs to the account it was saved for
+	account := m.identity.Account
+	if account == "" {
+		account = m.restoredAccount
+	}
+	return session.Snapshot{
+		SavedAt:                 time.Now(),
+		Region:                  m.region,
+		Profile:                 getAWSProfile(),
+		Account:                 account,
+		ActiveTab:               m.currentTab().name,
+		ScrollOffset:            m.viewport.YOffset,
+		LastRefresh:             m.lastRefresh,
+		LoadBalancers:           m.loadBalancers,
+		DBInstances:             m.dbInstances,
+		EC2Instances:            m.ec2Instances,
+		ECSServices:             m.ecsServices,
+		ECSScheduledTasks:       m.ecsScheduled,
+		SQSQueues:               m.sqsQueues,
+		SSMInstances:            m.ssmInstances,
+		DNSRecords:              m.dnsRecords,
+		DRResources:             m.drResources,
+		SNSTopics:               m.snsTopics,
+		LambdaFunctions:         m.lambdaFunctions,
+		CloudFrontDistributions: m.cloudfrontDistributions,
+		EBSVolumes:              m.ebsVolumes,
+		VPCs:                    m.vpcs,
+		ECRRepositories:         m.ecrRepositories,
+		APIGatewayAPIs:          m.apiGatewayAPIs,
+		Costs:                   m.costSummary,
+		Findings:                m.findings,
+		HygieneResources:        m.hygieneResources,
+		Probes:                  m.probeResults,
+	}
+}
+
+// restore populates the model from a saved session. The restored data is
+// displayed until the first load of the new session replaces it.
+func (m *Model) restore(snapshot session.Snapshot) {
+	m.lastRefresh = snapshot.LastRefresh
+	m.restoredAt = snapshot.SavedAt
+	m.restoredAccount = snapshot.Account
+
+	m.loadBalancers = snapshot.LoadBalancers
+	m.dbInstances = snapshot.DBInstances
+	m.ec2Instances = snapshot.EC2Instances
+	m.ecsServices = snapshot.ECSServices
+	ecspkg.SortServices(m.ecsServices)
+	m.ecsScheduled = snapshot.ECSScheduledTasks
+	m.sqsQueues = snapshot.SQSQueues
+	m.ssmInstances = snapshot.SSMInstances
+	m.dnsRecords = snapshot.DNSRecords
+	m.drResources = snapshot.DRResources
+	m.snsTopics = snapshot.SNSTopics
+	m.lambdaFunctions = snapshot.LambdaFunctions
+	m.cloudfrontDistributions = snapshot.CloudFrontDistributions
+	m.ebsVolumes = snapshot.EBSVolumes
+	m.vpcs = snapshot.VPCs
+	m.ecrRepositories = snapshot.ECRRepositories
+	m.apiGatewayAPIs = snapshot.APIGatewayAPIs
+	m.costSummary = snapshot.Costs
+	m.findings = snapshot.Findings
+	m.hygieneResources = snapshot.HygieneResources
+	m.probeResults = snapshot.Probes
+
+	// Show the restored data instead of loading spinners
+	m.loadingALB = false
+	m.loadingRDS = false
+	m.loadingEC2 = false
+	m.loadingECS = false
+	m.loadingSQS = false
+	m.loadingSSM = false
+	m.loadingDNS = false
+	m.loadingDR = false
+	m.loadingSNS = false
+	m.loadingLambda = false
+	m.loadingCloudFront = false
+	m.loadingEBS = false
+	m.loadingVPC = false
+	m.loadingECR = false
+	m.loadingAPIGateway = false
+	m.loadingCost = false
+	m.loadingFindings = false
+	m.loadingHygiene = false
+	m.loadingProbes = false
+
+	for i, t := range m.tabs {
+		if t.name == snapshot.ActiveTab {
+			m.activeTab = i
+			m.viewport.YOffset = snapshot.ScrollOffset
+			break
+		}
+	}
+}
+
+// forgetRestored drops the restored data of the services that have not
+// loaded yet this session, showing their spinners until they do. It is
+// called when the credentials belong to another account than the restored
+// session was saved for.
+func (m *Model) forgetRestored() {
+	m.restoredAt = time.Time{}
+	m.restoredAccount = ""
+
+	enabled := map[string]bool{"hygiene": m.showHygiene}
+	for _, t := range m.tabs {
+		enabled[t.service] = t.load != nil
+	}
+	forget := func(service string) bool {
+		return m.loadedAt[service].IsZero()
+	}
+
+	if forget("alb") {
+		m.loadBalancers, m.loadingALB = nil, enabled["alb"]
+	}
+	if forget("rds") {
+		m.dbInstances, m.loadingRDS = nil, enabled["rds"]
+	}
+	if forget("ec2") {
+		m.ec2Instances, m.loadingEC2 = nil, enabled["ec2"]
+	}
+	if forget("ecs") {
+		m.ecsServices, m.ecsScheduled, m.loadingECS = nil, nil, enabled["ecs"]
+	}
+	if forget("sqs") {
+		m.sqsQueues, m.loadingSQS = nil, enabled["sqs"]
+	}
+	if forget("ssm") {
+		m.ssmInstances, m.loadingSSM = nil, enabled["ssm"]
+	}
+	if forget("dns") {
+		m.dnsRecords, m.loadingDNS = nil, enabled["dns"]
+	}
+	if forget("dr") {
+		m.drResources, m.loadingDR = nil, enabled["dr"]
+	}
+	if forget("sns") {
+		m.snsTopics, m.loadingSNS = nil, enabled["sns"]
+	}
+	if forget("lambda") {
+		m.lambdaFunctions, m.loadingLambda = nil, enabled["lambda"]
+	}
+	if forget("cloudfront") {
+		m.cloudfrontDistributions, m.loadingCloudFront = nil, enabled["cloudfront"]
+	}
+	if forget("ebs") {
+		m.ebsVolumes, m.loadingEBS = nil, enabled["ebs"]
+	}
+	if forget("vpc") {
+		m.vpcs, m.loadingVPC = nil, enabled["vpc"]
+	}
+	if forget("ecr") {
+		m.ecrRepositories, m.loadingECR = nil, enabled["ecr"]
+	}
+	if forget("apigw") {
+		m.apiGatewayAPIs, m.loadingAPIGateway = nil, enabled["apigw"]
+	}
+	if forget("cost") {
+		m.costSummary, m.loadingCost = nil, enabled["cost"]
+	}
+	if forget("findings") {
+		m.findings, m.loadingFindings = nil, enabled["findings"]
+	}
+	if forget("hygiene") {
+		m.hygieneResources, m.loadingHygiene = nil, enabled["hygiene"]
+	}
+	if forget("probe") {
+		m.probeResults, m.loadingProbes = nil, enabled["probe"]
+	}
+}
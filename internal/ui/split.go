@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// pinnedTab returns the tab pinned to the left pane while the screen is
+// split. Hiding the Diagnostics tab while it is pinned ends the split.
+func (m Model) pinnedTab() (tab, bool) {
+	if !m.split || m.splitTab >= len(m.tabs) {
+		return tab{}, false
+	}
+	return m.tabs[m.splitTab], true
+}
+
+// toggleSplit splits the screen, pinning the active tab to the left pane and
+// showing the next tab on the right, or ends the split
+func (m Model) toggleSplit() (Model, tea.Cmd) {
+	if m.split {
+		m.split = false
+		m.resize()
+		m.updateViewportContent()
+		return m, nil
+	}
+	if len(m.tabs) < 2 {
+		return m.showToast("Select at least one service to split the screen", errorColor)
+	}
+
+	m.split = true
+	m.splitTab = m.activeTab
+	m.activeTab = (m.activeTab + 1) % len(m.tabs)
+	m.splitViewport.GotoTop()
+	m.resize()
+	m.updateViewportContent()
+	return m, nil
+}
+
+// updateSplitKeys scrolls the pinned pane with J and K while the screen is
+// split, leaving the other keys to the active tab on the right
+func (m Model) updateSplitKeys(msg tea.KeyMsg) (Model, bool) {
+	if _, ok := m.pinnedTab(); !ok {
+		return m, false
+	}
+	switch msg.String() {
+	case "J":
+		m.splitViewport.LineDown(1)
+	case "K":
+		m.splitViewport.LineUp(1)
+	default:
+		return m, false
+	}
+	return m, true
+}
+
+// resizeSplit fits the viewports of both panes side by side, each with a
+// title line above it
+func (m *Model) resizeSplit() {
+	width := m.splitWidth() - 4 // Padding
+	m.viewport.Width = width
+	m.viewport.Height--
+	m.splitViewport.Width = width
+	m.splitViewport.Height = m.viewport.Height
+}
+
+// splitWidth returns the width of each pane, excluding its border
+func (m Model) splitWidth() int {
+	return (m.width-4)/2 - 1
+}
+
+// renderSplit shows the pinned tab and the active tab side by side, each
+// under its name
+func (m Model) renderSplit(pinned tab, active string) string {
+	titleStyle := lipgloss.NewStyle().Foreground(accentColor).Bold(true)
+	paneStyle := contentStyle.Copy().Width(m.splitWidth())
+
+	left := paneStyle.Render(lipgloss.JoinVertical(lipgloss.Left,
+		titleStyle.Render("📌 "+pinned.name), m.splitViewport.View()))
+	right := paneStyle.Render(lipgloss.JoinVertical(lipgloss.Left,
+		titleStyle.Render(m.currentTab().name), active))
+	return lipgloss.JoinHorizontal(lipgloss.Top, left, right)
+}
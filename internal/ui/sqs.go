@@ -0,0 +1,259 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/correctedcloud/aws-overview/internal/audit"
+	"github.com/correctedcloud/aws-overview/internal/permissions"
+	"github.com/correctedcloud/aws-overview/pkg/common"
+	"github.com/correctedcloud/aws-overview/pkg/demo"
+	sqspkg "github.com/correctedcloud/aws-overview/pkg/sqs"
+)
+
+// errQueueURLUnknown is returned for queues restored from a session or cache
+// saved before queue URLs were kept
+var errQueueURLUnknown = errors.New("the URL of the queue is unknown, press r to reload the queues")
+
+// sqsPurgedMsg is sent when the purge of a queue was requested
+type sqsPurgedMsg struct {
+	queue sqspkg.QueueSummary
+	err   error
+}
+
+// newPurgeInput returns the prompt confirming the purge of a queue
+func newPurgeInput() textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "queue name"
+	input.CharLimit = 80
+	return input
+}
+
+// updateSQSKeys handles the keys of the SQS tab: the arrow keys select a
+// queue instead of scrolling, x asks to purge it and esc clears the outcome
+// of the last purge. Peeking at messages is a pane, see updatePaneKeys.
+func (m Model) updateSQSKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "up", "k":
+		m.moveSQSSelection(-1)
+		return m, nil, true
+	case "down", "j":
+		m.moveSQSSelection(1)
+		return m, nil, true
+	case "x":
+		cmd := m.openPurgeInput()
+		return m, cmd, true
+	case "esc":
+		if m.purgingQueue {
+			return m, nil, true
+		}
+		m.purgeNote = ""
+		m.purgeErr = nil
+		m.updateViewportContent()
+		return m, nil, true
+	}
+	return m, nil, false
+}
+
+// sqsHelp describes the keys of the SQS tab
+func (m Model) sqsHelp() string {
+	if !m.allowActions {
+		return "↑↓ Select"
+	}
+	return "↑↓ Select • x Purge"
+}
+
+// moveSQSSelection moves the selection by delta queues and scrolls the
+// viewport so the selected queue stays visible
+func (m *Model) moveSQSSelection(delta int) {
+	if len(m.sqsQueues) == 0 {
+		return
+	}
+	m.sqsSelected = max(0, min(m.shown("sqs", len(m.sqsQueues))-1, m.sqsSelected+delta))
+	m.updateViewportContent()
+	m.scrollToSelection(m.renderSQS())
+}
+
+// selectedQueue returns the queue selected on the SQS tab
+func (m Model) selectedQueue() (sqspkg.QueueSummary, bool) {
+	sorted := capRows(m, "sqs", common.SortRows(m.sqsQueues, sqspkg.Columns, m.sortKeys["sqs"]))
+	if m.sqsSelected < 0 || m.sqsSelected >= len(sorted) {
+		return sqspkg.QueueSummary{}, false
+	}
+	return sorted[m.sqsSelected], true
+}
+
+// selectedQueueResource returns the queue selected on the SQS tab for the
+// resource pane, which peeks at its messages. Peeking counts as a receive,
+// which moves messages to the dead-letter queue once they reach its maximum
+// receive count, so it is an action. It also graphs the metrics of the
+// queue in detail.
+func (m Model) selectedQueueResource() (resource, bool) {
+	queue, ok := m.selectedQueue()
+	if !ok {
+		return resource{}, false
+	}
+	return resource{
+		name: queue.Name,
+		messages: func(ctx context.Context) ([]sqspkg.Message, error) {
+			if !m.allowActions {
+				return nil, errActionsDisabled
+			}
+			if queue.URL == "" {
+				return nil, errQueueURLUnknown
+			}
+			client, err := m.sqsActionClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return client.PeekMessages(ctx, queue.URL)
+		},
+		graphs: func(m Model, width int) string {
+			// The queue as last loaded, which may be newer than the selection
+			for _, current := range m.sqsQueues {
+				if current.Name == queue.Name {
+					return sqspkg.FormatQueueGraphs(current, width)
+				}
+			}
+			return sqspkg.FormatQueueGraphs(queue, width)
+		},
+	}, true
+}
+
+// openPurgeInput starts typing the name of the selected queue to confirm
+// purging it
+func (m *Model) openPurgeInput() tea.Cmd {
+	queue, ok := m.selectedQueue()
+	if !ok || m.purgingQueue {
+		return nil
+	}
+	m.purgeNote = ""
+	m.purgeErr = nil
+	if !m.allowActions {
+		m.purgeErr = errActionsDisabled
+		m.updateViewportContent()
+		return nil
+	}
+
+	m.purgeInput.Prompt = fmt.Sprintf("Type %s to delete its %d messages › ", queue.Name, queue.ApproximateMessages)
+	m.purgeInput.SetValue("")
+	return m.purgeInput.Focus()
+}
+
+// updatePurgeInput handles a key while the purge prompt has focus. Enter
+// purges the queue if its name was typed and esc closes the prompt.
+func (m Model) updatePurgeInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.purgeInput.Blur()
+		m.purgeErr = nil
+		return m, nil
+	case "enter":
+		queue, ok := m.selectedQueue()
+		if !ok {
+			return m, nil
+		}
+		if m.purgeInput.Value() != queue.Name {
+			m.purgeErr = fmt.Errorf("type %s exactly to purge it", queue.Name)
+			return m, nil
+		}
+
+		m.purgeInput.Blur()
+		m.purgeErr = nil
+		m.purgingQueue = true
+		m.updateViewportContent()
+		m.viewport.GotoTop()
+		return m, m.purgeQueue(queue)
+	}
+
+	var cmd tea.Cmd
+	m.purgeInput, cmd = m.purgeInput.Update(msg)
+	return m, cmd
+}
+
+// sqsActionClient returns an SQS client for actions, which are not cached
+func (m Model) sqsActionClient(ctx context.Context) (*sqspkg.Client, error) {
+	if m.demo {
+		return sqspkg.NewClient(demo.NewSQS(), nil, "", nil, 0), nil
+	}
+
+	awsConfig, _, err := m.loadAWSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sqspkg.NewClient(sqs.NewFromConfig(m.limiters.Apply(awsConfig, "sqs")), nil, "", nil, 0), nil
+}
+
+// purgeQueue is a command that purges the queue
+func (m Model) purgeQueue(queue sqspkg.QueueSummary) tea.Cmd {
+	return func() tea.Msg {
+		if queue.URL == "" {
+			return sqsPurgedMsg{queue: queue, err: errQueueURLUnknown}
+		}
+
+		ctx, cancel := context.WithTimeout(m.ctx, m.timeout)
+		defer cancel()
+
+		client, err := m.sqsActionClient(ctx)
+		if err != nil {
+			return sqsPurgedMsg{queue: queue, err: err}
+		}
+		return sqsPurgedMsg{queue: queue, err: client.PurgeQueue(ctx, queue.URL)}
+	}
+}
+
+// updateSQSPurged records the outcome of a purge in the audit log and on the
+// tab, and reloads the queues to show their new depth
+func (m Model) updateSQSPurged(msg sqsPurgedMsg) (Model, tea.Cmd) {
+	m.purgingQueue = false
+	m.purgeErr = msg.err
+	m.audit("sqs", audit.Entry{
+		Action:   "sqs:PurgeQueue",
+		Resource: msg.queue.Name,
+		Detail:   fmt.Sprintf("about %d messages", msg.queue.ApproximateMessages),
+	}, msg.err)
+
+	if msg.err != nil {
+		m.updateViewportContent()
+		return m, nil
+	}
+	// The purge takes up to 60 seconds, so the reload may still count some
+	m.purgeNote = fmt.Sprintf("Purge of %s requested, its messages are deleted within 60 seconds", msg.queue.Name)
+	m.updateViewportContent()
+	return m, m.fresh().loadSQSData()
+}
+
+// renderPurgeInput shows the purge prompt and why the last input was rejected
+func (m Model) renderPurgeInput() string {
+	view := m.purgeInput.View()
+	if m.purgeErr != nil {
+		view += "  " + lipgloss.NewStyle().Foreground(errorColor).Render(m.purgeErr.Error())
+	}
+	return view
+}
+
+// renderSQSPurge shows the progress or outcome of a purge above the queues
+func (m Model) renderSQSPurge() string {
+	switch {
+	case m.purgeInput.Focused():
+		queue, _ := m.selectedQueue()
+		warning := fmt.Sprintf("Purging deletes all messages of %s", queue.Name)
+		if m.region != "" {
+			warning += " in " + m.region
+		}
+		return lipgloss.NewStyle().Foreground(warningColor).Bold(true).Render(warning+", which cannot be undone") + "\n\n"
+	case m.purgingQueue:
+		return m.spinner.View() + " Purging queue...\n\n"
+	case m.purgeErr != nil:
+		return "Purge failed: " + permissions.Describe(m.purgeErr) + "\n" + renderHints([]error{m.purgeErr}) + "\n"
+	case m.purgeNote != "":
+		return lipgloss.NewStyle().Foreground(successColor).Render(m.purgeNote) + "\n\n"
+	}
+	return ""
+}
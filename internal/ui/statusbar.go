@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/correctedcloud/aws-overview/internal/config"
+	"github.com/correctedcloud/aws-overview/internal/diagnostics"
+	"github.com/correctedcloud/aws-overview/pkg/cloudwatchmetrics"
+	"github.com/correctedcloud/aws-overview/pkg/demo"
+)
+
+// identityLoadedMsg carries who the credentials belong to
+type identityLoadedMsg struct {
+	identity config.Identity
+	err      error
+}
+
+// loadIdentity is a command that resolves the caller identity shown in the
+// status bar and returns a message
+func (m Model) loadIdentity() tea.Cmd {
+	return func() tea.Msg {
+		if m.demo {
+			return identityLoadedMsg{identity: config.Identity{Account: demo.AccountID, ARN: demo.CallerARN}}
+		}
+
+		awsConfig, _, err := m.loadAWSConfig(m.ctx)
+		if err != nil {
+			return identityLoadedMsg{err: err}
+		}
+		identity, err := sts.NewFromConfig(m.limiters.Apply(awsConfig, "sts")).GetCallerIdentity(m.ctx, &sts.GetCallerIdentityInput{})
+		if err != nil {
+			return identityLoadedMsg{err: err}
+		}
+		return identityLoadedMsg{identity: config.Identity{Account: aws.ToString(identity.Account), ARN: aws.ToString(identity.Arn)}}
+	}
+}
+
+// renderStatusBar shows the account and identity the data comes from, the
+// region and profile, the API calls made so far, how long the last refresh
+// took and when the data refreshes
+func (m Model) renderStatusBar() string {
+	var parts []string
+
+	account, arn := "resolving...", ""
+	if m.identity.Account != "" {
+		account, arn = m.identity.Account, m.identity.ARN
+	}
+	parts = append(parts, "Account "+account)
+	if arn != "" {
+		parts = append(parts, arn)
+	}
+
+	if m.region != "" {
+		parts = append(parts, getRegionFlag(m.region)+" "+m.region)
+	}
+	if m.demo {
+		parts = append(parts, "demo")
+	} else if profile := getAWSProfile(); profile != "" {
+		parts = append(parts, "profile "+profile)
+	}
+
+	parts = append(parts, "metrics of "+cloudwatchmetrics.FormatWindow(m.window))
+	parts = append(parts, fmt.Sprintf("%d API calls", m.diagnostics.TotalCalls()))
+	if refresh := m.renderRefreshStats(); refresh != "" {
+		parts = append(parts, refresh)
+	}
+	if !m.nextRefresh.IsZero() {
+		parts = append(parts, "next refresh in "+max(time.Until(m.nextRefresh), 0).Round(time.Second).String())
+	}
+
+	return lipgloss.NewStyle().
+		Foreground(dimTextColor).
+		Padding(0, 2).
+		MaxWidth(m.width).
+		Render(strings.Join(parts, " • "))
+}
+
+// renderRefreshStats notes how long the last refresh of the active tab's
+// service took and how many API calls it made, e.g. "ECS: 4.2s, 38 API calls",
+// or on the Overview tab those of the slowest service, to tell what to
+// narrow down with filters or -max-concurrency
+func (m Model) renderRefreshStats() string {
+	refreshes := m.diagnostics.Refreshes()
+	service, name := m.currentTab().service, m.currentTab().name
+	if m.activeTab == 0 {
+		service = ""
+		for _, t := range m.tabs[1:] {
+			if refresh, ok := refreshes[t.service]; ok && (service == "" || refresh.Last > refreshes[service].Last) {
+				service, name = t.service, "slowest "+t.name
+			}
+		}
+	}
+
+	refresh, ok := refreshes[service]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%s: %s, %d API calls", name, diagnostics.FormatDuration(refresh.Last), refresh.LastCalls)
+}
@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// sortIndicator marks the title of the column a table is sorted by
+const sortIndicator = " ▼"
+
+// renderTable renders rows as a table sorted by the column selected with s on
+// the service's tab, and returns the rows in the order shown. The first rows
+// up to the tab's limit are rendered, scrolling is left to the viewport.
+func renderTable[T any](m Model, service string, rows []T, columns []common.Column[T]) (string, []T) {
+	key := m.sortKeys[service]
+	sorted := capRows(m, service, common.SortRows(rows, columns, key))
+
+	tableColumns := make([]table.Column, len(columns))
+	width := 0
+	for i, column := range columns {
+		title := column.Title
+		if i == key {
+			title += sortIndicator
+		}
+		tableColumns[i] = table.Column{Title: title, Width: max(column.Width, common.DisplayWidth(title))}
+		width += tableColumns[i].Width + 2 // Cells are padded by one column on each side
+	}
+
+	tableRows := make([]table.Row, len(sorted))
+	for i, cells := range common.TableCells(sorted, columns) {
+		tableRows[i] = cells
+	}
+
+	// Rows are not selectable, so the selected row looks like any other
+	styles := table.DefaultStyles()
+	styles.Header = styles.Header.
+		Bold(true).
+		Foreground(accentColor).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(dimTextColor).
+		BorderBottom(true)
+	styles.Selected = lipgloss.NewStyle()
+
+	t := table.New(
+		table.WithColumns(tableColumns),
+		table.WithRows(tableRows),
+		table.WithStyles(styles),
+		table.WithWidth(width),
+		table.WithHeight(len(tableRows)+2), // Header and its border
+		table.WithFocused(false),
+	)
+	return t.View(), sorted
+}
@@ -0,0 +1,373 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+
+	"github.com/correctedcloud/aws-overview/pkg/ec2"
+	"github.com/correctedcloud/aws-overview/pkg/rds"
+	"github.com/correctedcloud/aws-overview/pkg/sqs"
+)
+
+// tab describes a tab of the overview. The model keeps the tabs of the enabled
+// services in order and dispatches loading, rendering and tab-specific keys
+// to the active one by index.
+type tab struct {
+	name    string // Shown in the tab bar and saved with the session
+	service string // Fetch and cache key of the tab's data, also the name of the flag enabling it
+
+	enabled func(Options) bool  // Whether the options select the service
+	load    func(Model) tea.Cmd // Starts a fetch of the tab's data
+	render  func(Model) string  // Content of the tab
+	summary func(Model) string  // Block of the service on the Overview tab, nil for tabs without one
+	loading func(Model) bool    // Whether the tab has no data to show yet, nil for tabs that always have
+
+	// keys handles the keys specific to the tab before the viewport scrolls,
+	// reporting whether it consumed the key. It is nil for tabs without keys.
+	keys func(Model, tea.KeyMsg) (Model, tea.Cmd, bool)
+	// help describes the tab's own keys, e.g. "t Test Route"
+	help func(Model) string
+	// keymap lists the tab's own keys for the help overlay, including those
+	// help only mentions in some states. Sorting and the keys acting on the
+	// selected resource are added from the fields below.
+	keymap []binding
+	// selected returns the resource selected on the tab for the resource
+	// pane, which L and E open. It is nil for tabs without a selection.
+	selected func(Model) (resource, bool)
+	// identifier returns the primary identifier of the resource selected on
+	// the tab, which y copies. It is nil for tabs without a selection.
+	identifier func(Model) (identifier, bool)
+	// console returns the link to the resource selected on the tab in the
+	// AWS console, which o opens. It is nil for tabs without a selection.
+	console func(Model) (string, bool)
+	// sortColumns is the number of columns s cycles the sort of the tab's
+	// table through, 0 for tabs without a table
+	sortColumns int
+	// refreshEvery is how old the tab's data gets before the periodic
+	// refresh loads it again, for services billed per call. It is 0 for tabs
+	// refreshed every interval; r and R always load the data again.
+	refreshEvery time.Duration
+	// windowed is whether the tab's metric graphs go back the window chosen
+	// with -window or the 1, 3, 6 and 24 keys, reloading when it changes
+	windowed bool
+}
+
+// overviewTab summarizes all services and is always the first tab
+var overviewTab = tab{name: "Overview", render: Model.renderOverview}
+
+// serviceTabs are the tabs of all services in the order they are shown. A new
+// service registers its tab here.
+var serviceTabs = []tab{
+	{
+		name:    "Load Balancers",
+		service: "alb",
+		enabled: func(o Options) bool { return o.ShowALB },
+		load:    Model.loadALBData,
+		render:  Model.renderALB,
+		summary: Model.renderALBSummary,
+		loading: func(m Model) bool { return m.loadingALB && len(m.loadBalancers) == 0 },
+		keys:    Model.updateALBKeys,
+		help:    Model.albHelp,
+		keymap: []binding{
+			{keys: "t", description: "Test which listener rule a request would match"},
+			{keys: "↑↓/j k", description: "Select a target"},
+			{keys: "a", description: "Deregister or register the selected target", actions: true},
+		},
+
+		identifier: Model.albIdentifier,
+		console:    Model.albConsole,
+	},
+	{
+		name:    "RDS Instances",
+		service: "rds",
+		enabled: func(o Options) bool { return o.ShowRDS },
+		load:    Model.loadRDSData,
+		render:  Model.renderRDS,
+		summary: Model.renderRDSSummary,
+		loading: func(m Model) bool { return m.loadingRDS },
+		keys:    Model.updateRDSKeys,
+		help:    func(Model) string { return "↑↓ Select" },
+		keymap:  []binding{{keys: "↑↓/j k", description: "Select an instance"}},
+
+		selected:    Model.selectedInstanceResource,
+		identifier:  Model.rdsIdentifier,
+		console:     Model.rdsConsole,
+		sortColumns: len(rds.Columns),
+		windowed:    true,
+	},
+	{
+		name:    "EC2 Instances",
+		service: "ec2",
+		enabled: func(o Options) bool { return o.ShowEC2 },
+		load:    Model.loadEC2Data,
+		render:  Model.renderEC2,
+		summary: Model.renderEC2Summary,
+		loading: func(m Model) bool { return m.loadingEC2 },
+		keys:    Model.updateEC2Keys,
+		help:    Model.ec2Help,
+		keymap: []binding{
+			{keys: "↑↓/j k", description: "Select an instance"},
+			{keys: "c", description: "Start a Session Manager session on the selected instance", actions: true},
+			{keys: "a", description: "Start, stop or reboot the selected instance", actions: true},
+		},
+
+		identifier:  Model.ec2Identifier,
+		console:     Model.ec2Console,
+		sortColumns: len(ec2.Columns),
+	},
+	{
+		name:    "EBS Volumes",
+		service: "ebs",
+		enabled: func(o Options) bool { return o.ShowEBS },
+		load:    Model.loadEBSData,
+		render:  Model.renderEBS,
+		summary: Model.renderEBSSummary,
+		loading: func(m Model) bool { return m.loadingEBS },
+	},
+	{
+		name:    "VPC",
+		service: "vpc",
+		enabled: func(o Options) bool { return o.ShowVPC },
+		load:    Model.loadVPCData,
+		render:  Model.renderVPC,
+		summary: Model.renderVPCSummary,
+		loading: func(m Model) bool { return m.loadingVPC },
+	},
+	{
+		name:    "ECS Services",
+		service: "ecs",
+		enabled: func(o Options) bool { return o.ShowECS },
+		load:    Model.loadECSData,
+		render:  Model.renderECS,
+		summary: Model.renderECSSummary,
+		loading: func(m Model) bool { return m.loadingECS },
+		keys:    Model.updateECSKeys,
+		help:    Model.ecsHelp,
+		keymap: []binding{
+			{keys: "↑↓/j k", description: "Select a service"},
+			{keys: "x", description: "Run a one-off task of the selected service", actions: true},
+			{keys: "c", description: "Scale the selected service", actions: true},
+			{keys: "d", description: "Force a new deployment of the selected service", actions: true},
+			{keys: "e", description: "Open a shell in a running container with ECS Exec", actions: true},
+		},
+
+		selected:   Model.selectedServiceResource,
+		identifier: Model.ecsIdentifier,
+		console:    Model.ecsConsole,
+	},
+	{
+		name:    "ECR",
+		service: "ecr",
+		enabled: func(o Options) bool { return o.ShowECR },
+		load:    Model.loadECRData,
+		render:  Model.renderECR,
+		summary: Model.renderECRSummary,
+		loading: func(m Model) bool { return m.loadingECR },
+	},
+	{
+		name:    "API Gateway",
+		service: "apigw",
+		enabled: func(o Options) bool { return o.ShowAPIGateway },
+		load:    Model.loadAPIGatewayData,
+		render:  Model.renderAPIGateway,
+		summary: Model.renderAPIGatewaySummary,
+		loading: func(m Model) bool { return m.loadingAPIGateway },
+	},
+	{
+		name:    "SQS Queues",
+		service: "sqs",
+		enabled: func(o Options) bool { return o.ShowSQS },
+		load:    Model.loadSQSData,
+		render:  Model.renderSQS,
+		summary: Model.renderSQSSummary,
+		loading: func(m Model) bool { return m.loadingSQS },
+		keys:    Model.updateSQSKeys,
+		help:    Model.sqsHelp,
+		keymap: []binding{
+			{keys: "↑↓/j k", description: "Select a queue"},
+			{keys: "x", description: "Purge the selected queue", actions: true},
+		},
+		selected: Model.selectedQueueResource,
+
+		identifier:  Model.sqsIdentifier,
+		console:     Model.sqsConsole,
+		sortColumns: len(sqs.Columns),
+		windowed:    true,
+	},
+	{
+		name:    "SSM Instances",
+		service: "ssm",
+		enabled: func(o Options) bool { return o.ShowSSM },
+		load:    Model.loadSSMData,
+		render:  Model.renderSSM,
+		summary: Model.renderSSMSummary,
+		loading: func(m Model) bool { return m.loadingSSM },
+	},
+	{
+		name:    "DNS Records",
+		service: "dns",
+		enabled: func(o Options) bool { return o.ShowDNS },
+		load:    Model.loadDNSData,
+		render:  Model.renderDNS,
+		summary: Model.renderDNSSummary,
+		loading: func(m Model) bool { return m.loadingDNS },
+	},
+	{
+		name:    "DR Readiness",
+		service: "dr",
+		enabled: func(o Options) bool { return o.ShowDR },
+		load:    Model.loadDRData,
+		render:  Model.renderDR,
+		summary: Model.renderDRSummary,
+		loading: func(m Model) bool { return m.loadingDR },
+	},
+	{
+		name:    "SNS Topics",
+		service: "sns",
+		enabled: func(o Options) bool { return o.ShowSNS },
+		load:    Model.loadSNSData,
+		render:  Model.renderSNS,
+		summary: Model.renderSNSSummary,
+		loading: func(m Model) bool { return m.loadingSNS },
+	},
+	{
+		name:    "Lambda Functions",
+		service: "lambda",
+		enabled: func(o Options) bool { return o.ShowLambda },
+		load:    Model.loadLambdaData,
+		render:  Model.renderLambda,
+		summary: Model.renderLambdaSummary,
+		loading: func(m Model) bool { return m.loadingLambda },
+		keys:    Model.updateLambdaKeys,
+		help:    Model.lambdaHelp,
+		keymap: []binding{
+			{keys: "↑↓/j k", description: "Select a function"},
+			{keys: "i", description: "Test-invoke the selected function with a payload", actions: true},
+		},
+
+		selected:   Model.selectedFunctionResource,
+		identifier: Model.lambdaIdentifier,
+		console:    Model.lambdaConsole,
+	},
+	{
+		name:    "CloudFront",
+		service: "cloudfront",
+		enabled: func(o Options) bool { return o.ShowCloudFront },
+		load:    Model.loadCloudFrontData,
+		render:  Model.renderCloudFront,
+		summary: Model.renderCloudFrontSummary,
+		loading: func(m Model) bool { return m.loadingCloudFront },
+		keys:    Model.updateCloudFrontKeys,
+		help:    Model.cloudfrontHelp,
+		keymap: []binding{
+			{keys: "↑↓/j k", description: "Select a distribution"},
+			{keys: "i", description: "Invalidate paths in the cache of the selected distribution", actions: true},
+		},
+
+		identifier: Model.cloudfrontIdentifier,
+		console:    Model.cloudfrontConsole,
+	},
+	{
+		name:    "Costs",
+		service: "cost",
+		enabled: func(o Options) bool { return o.ShowCost },
+		load:    Model.loadCostData,
+		render:  Model.renderCost,
+		summary: Model.renderCostSummary,
+		loading: func(m Model) bool { return m.loadingCost },
+
+		// Cost Explorer bills every request and updates the spend a few
+		// times a day
+		refreshEvery: time.Hour,
+	},
+	{
+		name:    "Findings",
+		service: "findings",
+		enabled: func(o Options) bool { return o.ShowFindings },
+		load:    Model.loadFindingsData,
+		render:  Model.renderFindings,
+		summary: Model.renderFindingsSummary,
+		loading: func(m Model) bool { return m.loadingFindings },
+
+		// The checks look at days of statistics, which an hour barely moves
+		refreshEvery: time.Hour,
+	},
+	{
+		name:    "Probes",
+		service: "probe",
+		enabled: func(o Options) bool { return o.ShowProbes },
+		load:    Model.loadProbesData,
+		render:  Model.renderProbes,
+		summary: Model.renderProbesSummary,
+		loading: func(m Model) bool { return m.loadingProbes },
+	},
+	{
+		name:    "CloudWatch",
+		service: "metrics",
+		enabled: func(o Options) bool { return o.ShowMetrics },
+		load:    Model.loadMetricBrowser,
+		render:  Model.renderMetricBrowser,
+		keys:    Model.updateMetricBrowserKeys,
+		help:    Model.metricBrowserHelp,
+		keymap: []binding{
+			{keys: "↑↓/j k", description: "Select a namespace or metric"},
+			{keys: "enter", description: "Open the selected namespace, or plot the selected metric"},
+			{keys: "t", description: "Cycle the statistic of the plot"},
+			{keys: "p", description: "Pin the plotted metric to the Custom Metrics tab"},
+			{keys: "esc", description: "Close the plot, or go back to the namespaces"},
+		},
+	},
+	{
+		name:    "Custom Metrics",
+		service: "pins",
+		enabled: func(o Options) bool { return o.ShowMetrics || len(o.Pins) > 0 },
+		load:    Model.loadPinnedMetrics,
+		render:  Model.renderPinnedMetrics,
+		summary: Model.renderPinnedMetricsSummary,
+		loading: func(m Model) bool { return m.loadingPins },
+		keys:    Model.updatePinKeys,
+		help:    Model.pinHelp,
+		keymap: []binding{
+			{keys: "↑↓/j k", description: "Select a pinned metric"},
+			{keys: "x", description: "Unpin the selected metric"},
+		},
+	},
+	{
+		// Runbooks load no data and have no block on the Overview tab
+		name:    "Runbooks",
+		enabled: func(o Options) bool { return len(o.Runbooks) > 0 },
+		render:  Model.renderRunbooks,
+		keys:    Model.updateRunbookKeys,
+		help:    Model.runbookHelp,
+		keymap: []binding{
+			{keys: "↑↓/j k", description: "Select a runbook"},
+			{keys: "enter", description: "Run the selected runbook", actions: true},
+		},
+	},
+}
+
+// enabledTabs returns the Overview tab followed by the tabs of the services
+// selected by opts and of the providers added through opts
+func enabledTabs(opts Options) []tab {
+	tabs := []tab{overviewTab}
+	// The lag indicators have no tab of their own and load with the Overview
+	if opts.ShowLag {
+		tabs[0].service = "lag"
+		tabs[0].load = Model.loadLagData
+	}
+	for _, t := range serviceTabs {
+		if t.enabled(opts) {
+			tabs = append(tabs, t)
+		}
+	}
+	for _, p := range opts.Providers {
+		tabs = append(tabs, providerTab(p))
+	}
+	return tabs
+}
+
+// currentTab returns the active tab
+func (m Model) currentTab() tab {
+	return m.tabs[m.activeTab]
+}
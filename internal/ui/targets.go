@@ -0,0 +1,222 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/correctedcloud/aws-overview/internal/audit"
+	"github.com/correctedcloud/aws-overview/internal/permissions"
+	"github.com/correctedcloud/aws-overview/pkg/alb"
+	"github.com/correctedcloud/aws-overview/pkg/demo"
+)
+
+// albTargetMsg is sent when a target was deregistered or registered
+type albTargetMsg struct {
+	action alb.TargetAction
+	target alb.TargetRef
+	err    error
+}
+
+// confirmTargetAction asks to deregister the selected target, or to register
+// it again when it was deregistered. The target is kept for the confirmation,
+// as refreshes may move or remove it meanwhile.
+func (m *Model) confirmTargetAction() {
+	target, _, ok := m.selectedTarget()
+	if !ok || m.albActing {
+		return
+	}
+	m.albActionNote = ""
+	m.albActionErr = nil
+	switch {
+	case !m.allowActions:
+		m.albActionErr = errActionsDisabled
+	case m.albSelected != "" && target.Key() != m.albSelected:
+		// The selected target is gone and the first one only stands in for it
+		m.albActionErr = errors.New("the selected target is no longer listed, select a target again")
+		m.albSelected = target.Key()
+	default:
+		m.albSelected = target.Key()
+		m.albConfirmTarget = target
+		m.albConfirming = alb.ActionFor(target.Target)
+	}
+	m.updateViewportContent()
+	m.viewport.GotoTop()
+}
+
+// updateTargetConfirm handles a key while a target action waits for
+// confirmation: y runs it on the target it was asked for, unless that target
+// is gone or the action no longer applies, and any other key cancels
+func (m Model) updateTargetConfirm(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	if msg.String() == "ctrl+c" {
+		return m, nil, false
+	}
+	action := m.albConfirming
+	m.albConfirming = ""
+	if msg.String() != "y" {
+		m.updateViewportContent()
+		return m, nil, true
+	}
+	target, ok := m.targetByKey(m.albConfirmTarget.Key())
+	if !ok {
+		m.albActionErr = fmt.Errorf("target %s of %s is no longer listed, %s not requested", targetName(m.albConfirmTarget), m.albConfirmTarget.TargetGroup, action)
+		m.updateViewportContent()
+		return m, nil, true
+	}
+	if alb.ActionFor(target.Target) != action {
+		m.albActionErr = fmt.Errorf("%s does not apply to target %s, which is now %s", action, targetName(target), target.Target.Status)
+		m.updateViewportContent()
+		return m, nil, true
+	}
+	m.albActing = true
+	m.updateViewportContent()
+	m.viewport.GotoTop()
+	return m, m.runTargetAction(action, target), true
+}
+
+// shownLoadBalancers returns the load balancers with the targets deregistered
+// from this session that left their target group, so that they can be
+// registered again
+func (m Model) shownLoadBalancers() []alb.LoadBalancerSummary {
+	keys := make([]string, 0, len(m.albDeregistered))
+	for key := range m.albDeregistered {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	deregistered := make([]alb.TargetRef, len(keys))
+	for i, key := range keys {
+		deregistered[i] = m.albDeregistered[key]
+	}
+	return alb.WithDeregistered(m.loadBalancers, deregistered)
+}
+
+// selectedTarget returns the target selected on the Load Balancers tab and
+// its index, the first target when the selected one is gone. Actions check
+// that it is the selected one, see confirmTargetAction.
+func (m Model) selectedTarget() (alb.TargetRef, int, bool) {
+	targets := alb.Targets(m.shownLoadBalancers())
+	if len(targets) == 0 {
+		return alb.TargetRef{}, -1, false
+	}
+	for i, target := range targets {
+		if target.Key() == m.albSelected {
+			return target, i, true
+		}
+	}
+	return targets[0], 0, true
+}
+
+// targetByKey returns the shown target with key
+func (m Model) targetByKey(key string) (alb.TargetRef, bool) {
+	for _, target := range alb.Targets(m.shownLoadBalancers()) {
+		if target.Key() == key {
+			return target, true
+		}
+	}
+	return alb.TargetRef{}, false
+}
+
+// moveTargetSelection moves the selection by delta targets and scrolls the
+// viewport so the selected target stays visible. The selection is kept by
+// key, as load balancers load in no particular order.
+func (m *Model) moveTargetSelection(delta int) {
+	targets := alb.Targets(m.shownLoadBalancers())
+	_, index, ok := m.selectedTarget()
+	if !ok {
+		return
+	}
+	m.albSelected = targets[max(0, min(len(targets)-1, index+delta))].Key()
+	m.updateViewportContent()
+	m.scrollToSelection(m.renderALB())
+}
+
+// albActionClient returns a load balancer client for actions, which are not cached
+func (m Model) albActionClient(ctx context.Context) (*alb.Client, error) {
+	if m.demo {
+		return alb.NewClient(demo.NewELBv2(), nil, nil), nil
+	}
+
+	awsConfig, _, err := m.loadAWSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return alb.NewClient(elasticloadbalancingv2.NewFromConfig(m.limiters.Apply(awsConfig, "elasticloadbalancing")), nil, nil), nil
+}
+
+// runTargetAction is a command that deregisters or registers a target
+func (m Model) runTargetAction(action alb.TargetAction, target alb.TargetRef) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(m.ctx, m.timeout)
+		defer cancel()
+
+		client, err := m.albActionClient(ctx)
+		if err != nil {
+			return albTargetMsg{action: action, target: target, err: err}
+		}
+		return albTargetMsg{action: action, target: target, err: client.RunTargetAction(ctx, action, target)}
+	}
+}
+
+// updateTargetAction records the outcome of a target action and reloads the
+// load balancers to show the new health of the target
+func (m Model) updateTargetAction(msg albTargetMsg) (Model, tea.Cmd) {
+	m.albActing = false
+	m.albActionErr = msg.err
+	m.audit("alb", audit.Entry{
+		Action:   "elasticloadbalancing:" + string(msg.action) + "Targets",
+		Resource: msg.target.TargetGroupARN,
+		Detail:   fmt.Sprintf("%s:%d", msg.target.Target.ID, msg.target.Target.Port),
+	}, msg.err)
+	if msg.err != nil {
+		m.updateViewportContent()
+		return m, nil
+	}
+
+	if msg.action == alb.ActionDeregister {
+		m.albDeregistered[msg.target.Key()] = msg.target
+		m.albActionNote = fmt.Sprintf("Deregistered %s from %s, draining its connections", targetName(msg.target), msg.target.TargetGroup)
+	} else {
+		delete(m.albDeregistered, msg.target.Key())
+		m.albActionNote = fmt.Sprintf("Registered %s with %s", targetName(msg.target), msg.target.TargetGroup)
+	}
+	m.updateViewportContent()
+	return m, m.fresh().loadALBData()
+}
+
+// targetName returns the ID and port of a target, e.g. "i-0a1b2c3d:80"
+func targetName(target alb.TargetRef) string {
+	return fmt.Sprintf("%s:%d", target.Target.ID, target.Target.Port)
+}
+
+// renderTargetAction shows the confirmation or the outcome of a target
+// action above the load balancers
+func (m Model) renderTargetAction() string {
+	switch {
+	case m.albConfirming != "":
+		target := m.albConfirmTarget
+		question := fmt.Sprintf("%s target %s", m.albConfirming, targetName(target))
+		if m.albConfirming == alb.ActionDeregister {
+			question += " from "
+		} else {
+			question += " with "
+		}
+		question += fmt.Sprintf("%s of %s", target.TargetGroup, target.LoadBalancer)
+		if m.region != "" {
+			question += " in " + m.region
+		}
+		return lipgloss.NewStyle().Foreground(warningColor).Bold(true).Render(question+"?") + "\n" +
+			lipgloss.NewStyle().Foreground(warningColor).Render("Press y to confirm, any other key to cancel") + "\n\n"
+	case m.albActing:
+		return m.spinner.View() + " Requesting the change...\n\n"
+	case m.albActionErr != nil:
+		return "Target action failed: " + permissions.Describe(m.albActionErr) + "\n" + renderHints([]error{m.albActionErr}) + "\n"
+	case m.albActionNote != "":
+		return lipgloss.NewStyle().Foreground(successColor).Render(m.albActionNote) + "\n\n"
+	}
+	return ""
+}
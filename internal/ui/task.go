@@ -0,0 +1,333 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/correctedcloud/aws-overview/internal/audit"
+	"github.com/correctedcloud/aws-overview/internal/permissions"
+	"github.com/correctedcloud/aws-overview/pkg/demo"
+	ecspkg "github.com/correctedcloud/aws-overview/pkg/ecs"
+	eventspkg "github.com/correctedcloud/aws-overview/pkg/events"
+	logspkg "github.com/correctedcloud/aws-overview/pkg/logs"
+)
+
+// taskPollInterval is how often a one-off task is described until it stops
+const taskPollInterval = 3 * time.Second
+
+// ecsTaskMsg carries the state of a one-off task after it was started or polled
+type ecsTaskMsg struct {
+	task ecspkg.TaskSummary
+	err  error
+
+	// Set when the task was started, for the audit log
+	service ecspkg.ServiceSummary
+	command []string
+}
+
+// ecsTaskPollMsg is sent when it's time to describe the tracked task again
+type ecsTaskPollMsg struct {
+	taskARN string
+}
+
+// newTaskInput returns the prompt of the command override of one-off tasks
+func newTaskInput() textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "command override, e.g. bin/migrate --to latest (empty for the default command)"
+	input.CharLimit = 1024
+	return input
+}
+
+// selectedService returns the service selected on the ECS tab
+func (m Model) selectedService() (ecspkg.ServiceSummary, bool) {
+	if m.ecsSelected < 0 || m.ecsSelected >= len(m.ecsServices) {
+		return ecspkg.ServiceSummary{}, false
+	}
+	return m.ecsServices[m.ecsSelected], true
+}
+
+// serviceByName returns the listed service named name in cluster, which
+// refreshes may have moved or removed since a prompt was opened for it
+func (m Model) serviceByName(cluster, name string) (ecspkg.ServiceSummary, bool) {
+	for _, service := range m.ecsServices {
+		if service.ClusterName == cluster && service.ServiceName == name {
+			return service, true
+		}
+	}
+	return ecspkg.ServiceSummary{}, false
+}
+
+// selectedServiceResource returns the service selected on the ECS tab for the
+// resource pane. It logs to the awslogs log groups of its containers.
+func (m Model) selectedServiceResource() (resource, bool) {
+	service, ok := m.selectedService()
+	if !ok {
+		return resource{}, false
+	}
+	return resource{
+		name: "ECS service " + service.ClusterName + "/" + service.ServiceName,
+		logSources: func(ctx context.Context, _ *logspkg.Client) ([]logspkg.Source, error) {
+			client, err := m.ecsActionClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return client.LogSources(ctx, service)
+		},
+		events: eventspkg.Resource{
+			TrailNames:      []string{service.ServiceName},
+			AlarmDimensions: map[string]string{"ClusterName": service.ClusterName, "ServiceName": service.ServiceName},
+			ECSCluster:      service.ClusterName,
+			ECSService:      service.ServiceName,
+		},
+	}, true
+}
+
+// updateECSKeys handles the keys of the ECS tab: the arrow keys select a
+// service instead of scrolling, x opens the one-off task prompt, c opens the
+// desired count prompt, d asks to force a new deployment, e opens a shell in
+// a container with ECS Exec and esc stops tracking the task and clears the
+// outcome of the last update
+func (m Model) updateECSKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	if m.ecsConfirming != nil {
+		return m.updateECSConfirm(msg)
+	}
+	if m.ecsExecTargets != nil {
+		return m.updateExecMenu(msg)
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		m.ecsSelected = max(0, m.ecsSelected-1)
+		m.updateViewportContent()
+		m.scrollToSelection(m.renderECS())
+		return m, nil, true
+	case "down", "j":
+		m.ecsSelected = max(0, min(m.shown("ecs", len(m.ecsServices))-1, m.ecsSelected+1))
+		m.updateViewportContent()
+		m.scrollToSelection(m.renderECS())
+		return m, nil, true
+	case "x":
+		cmd := m.openTaskInput()
+		return m, cmd, true
+	case "c":
+		cmd := m.openScaleInput()
+		return m, cmd, true
+	case "d":
+		m.confirmRedeploy()
+		return m, nil, true
+	case "e":
+		var cmd tea.Cmd
+		m, cmd = m.openExec()
+		return m, cmd, true
+	case "esc":
+		if m.startingTask || m.ecsUpdating || m.ecsExecuting {
+			return m, nil, true
+		}
+		m.ecsTask = nil
+		m.ecsTaskErr = nil
+		m.ecsUpdateNote = ""
+		m.ecsUpdateErr = nil
+		m.ecsExecNote = ""
+		m.ecsExecErr = nil
+		m.updateViewportContent()
+		return m, nil, true
+	}
+	return m, nil, false
+}
+
+// ecsHelp describes the keys of the ECS tab
+func (m Model) ecsHelp() string {
+	switch {
+	case m.ecsConfirming != nil:
+		return "y Confirm • any other key Cancel"
+	case m.ecsExecTargets != nil:
+		return "↑↓ Select Container • enter Open Shell • esc Close"
+	case !m.allowActions:
+		return "↑↓ Select"
+	}
+	return "↑↓ Select • x Run Task • c Scale • d Redeploy • e Exec"
+}
+
+// openTaskInput starts entering the command of a one-off task of the selected
+// service
+func (m *Model) openTaskInput() tea.Cmd {
+	if !m.allowActions {
+		m.ecsTaskErr = errActionsDisabled
+		m.updateViewportContent()
+		return nil
+	}
+	service, ok := m.selectedService()
+	if !ok || m.startingTask {
+		return nil
+	}
+
+	m.ecsTaskErr = nil
+	m.taskCluster, m.taskService = service.ClusterName, service.ServiceName
+	m.taskInput.Prompt = "Run task of " + service.ServiceName + " › "
+	m.taskInput.SetValue("")
+	return m.taskInput.Focus()
+}
+
+// updateTaskInput handles a key while the task prompt has focus. Enter runs
+// the task of the service the prompt was opened for and esc closes the prompt.
+func (m Model) updateTaskInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.taskInput.Blur()
+		m.ecsTaskErr = nil
+		return m, nil
+	case "enter":
+		command, err := ecspkg.ParseCommand(m.taskInput.Value())
+		if err != nil {
+			m.ecsTaskErr = err
+			return m, nil
+		}
+		service, ok := m.serviceByName(m.taskCluster, m.taskService)
+		if !ok {
+			m.taskInput.Blur()
+			m.ecsTask = nil
+			m.ecsTaskErr = fmt.Errorf("service %s of %s is no longer listed, task not started", m.taskService, m.taskCluster)
+			m.updateViewportContent()
+			return m, nil
+		}
+
+		m.taskInput.Blur()
+		m.ecsTask = nil
+		m.ecsTaskErr = nil
+		m.startingTask = true
+		m.updateViewportContent()
+		return m, m.runTask(service, command)
+	}
+
+	var cmd tea.Cmd
+	m.taskInput, cmd = m.taskInput.Update(msg)
+	return m, cmd
+}
+
+// ecsActionClient returns an ECS client for actions and lookups, which are
+// not cached
+func (m Model) ecsActionClient(ctx context.Context) (*ecspkg.Client, error) {
+	if m.demo {
+		return ecspkg.NewClient(demo.NewECS()), nil
+	}
+
+	awsConfig, _, err := m.loadAWSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ecspkg.NewClient(ecs.NewFromConfig(m.limiters.Apply(awsConfig, "ecs"))), nil
+}
+
+// runTask is a command that starts a one-off task of the service
+func (m Model) runTask(service ecspkg.ServiceSummary, command []string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(m.ctx, m.timeout)
+		defer cancel()
+
+		client, err := m.ecsActionClient(ctx)
+		if err != nil {
+			return ecsTaskMsg{err: err, service: service, command: command}
+		}
+		task, err := client.RunTask(ctx, service, command)
+		return ecsTaskMsg{task: task, err: err, service: service, command: command}
+	}
+}
+
+// pollTask is a command that describes the tracked task after the poll interval
+func pollTask(taskARN string) tea.Cmd {
+	return tea.Tick(taskPollInterval, func(time.Time) tea.Msg {
+		return ecsTaskPollMsg{taskARN: taskARN}
+	})
+}
+
+// describeTask is a command that returns the current state of a task
+func (m Model) describeTask(task ecspkg.TaskSummary) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(m.ctx, m.timeout)
+		defer cancel()
+
+		client, err := m.ecsActionClient(ctx)
+		if err != nil {
+			return ecsTaskMsg{task: task, err: err}
+		}
+		described, err := client.DescribeTask(ctx, task.ClusterName, task.ARN)
+		if err != nil {
+			// Keep showing the last known state, a later poll may succeed
+			return ecsTaskMsg{task: task, err: err}
+		}
+		return ecsTaskMsg{task: described}
+	}
+}
+
+// updateTask records the state of the tracked task and polls it again until
+// it stops
+func (m Model) updateTask(msg ecsTaskMsg) (Model, tea.Cmd) {
+	starting := m.startingTask
+	m.startingTask = false
+	if starting {
+		detail := "default command"
+		if len(msg.command) > 0 {
+			detail = "command " + strings.Join(msg.command, " ")
+		}
+		m.audit("ecs", audit.Entry{
+			Action:   "ecs:RunTask",
+			Resource: msg.service.ClusterName + "/" + msg.service.ServiceName,
+			Detail:   detail,
+		}, msg.err)
+	}
+
+	// Ignore polls of a task that is no longer tracked
+	if !starting && (m.ecsTask == nil || m.ecsTask.ARN != msg.task.ARN) {
+		return m, nil
+	}
+
+	m.ecsTaskErr = msg.err
+	if msg.task.ARN == "" {
+		m.updateViewportContent()
+		return m, nil
+	}
+
+	task := msg.task
+	m.ecsTask = &task
+	m.updateViewportContent()
+	if starting {
+		m.viewport.GotoTop()
+	}
+	if task.Stopped() {
+		return m, nil
+	}
+	return m, pollTask(task.ARN)
+}
+
+// renderTaskInput shows the task prompt and why the last input was rejected
+func (m Model) renderTaskInput() string {
+	view := m.taskInput.View()
+	if m.ecsTaskErr != nil {
+		view += "  " + lipgloss.NewStyle().Foreground(errorColor).Render(m.ecsTaskErr.Error())
+	}
+	return view
+}
+
+// renderTask shows the tracked one-off task above the services
+func (m Model) renderTask() string {
+	switch {
+	case m.startingTask:
+		return m.spinner.View() + " Starting task...\n\n"
+	case m.ecsTask != nil:
+		content := ecspkg.FormatTask(*m.ecsTask)
+		if m.ecsTaskErr != nil {
+			content += lipgloss.NewStyle().Foreground(warningColor).Render("⚠️ "+permissions.Describe(m.ecsTaskErr)) + "\n"
+		}
+		return content + "\n"
+	case m.ecsTaskErr != nil && !m.taskInput.Focused():
+		return "Task failed to start: " + permissions.Describe(m.ecsTaskErr) + "\n" + renderHints([]error{m.ecsTaskErr}) + "\n"
+	}
+	return ""
+}
@@ -0,0 +1,198 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DefaultTheme is the theme used when Options.Theme is unset
+const DefaultTheme = "dark"
+
+// Theme is the color palette of the UI
+type Theme struct {
+	Primary    lipgloss.Color // Borders of the help line
+	Secondary  lipgloss.Color // Border of the content
+	Accent     lipgloss.Color // Active tab, spinners and table headers
+	Error      lipgloss.Color
+	Success    lipgloss.Color
+	Warning    lipgloss.Color
+	Background lipgloss.Color // Background of the help line
+	Text       lipgloss.Color // Text, also on the tabs
+	DimText    lipgloss.Color // Inactive tabs and notes
+}
+
+// Themes are the built-in themes by name
+var Themes = map[string]Theme{
+	"dark": {
+		Primary:    "#7D56F4", // Vibrant purple
+		Secondary:  "#5AD4E6", // Bright cyan
+		Accent:     "#FFB938", // Warm amber
+		Error:      "#FF5F87", // Soft red
+		Success:    "#39DA8A", // Vibrant green
+		Warning:    "#FFBD54", // Amber
+		Background: "#1A1B26", // Dark background
+		Text:       "#FAFAFA", // Light text
+		DimText:    "#9699B7", // Dimmed text
+	},
+	"light": {
+		Primary:    "#5A3FC0",
+		Secondary:  "#0087AF",
+		Accent:     "#D97706",
+		Error:      "#C4002F",
+		Success:    "#1B7F3B",
+		Warning:    "#9A6700",
+		Background: "#EFF1F5",
+		Text:       "#1A1B26",
+		DimText:    "#7C7F93",
+	},
+	"solarized": {
+		Primary:    "#6C71C4", // violet
+		Secondary:  "#2AA198", // cyan
+		Accent:     "#B58900", // yellow
+		Error:      "#DC322F", // red
+		Success:    "#859900", // green
+		Warning:    "#CB4B16", // orange
+		Background: "#002B36", // base03
+		Text:       "#FDF6E3", // base3
+		DimText:    "#586E75", // base01
+	},
+	"high-contrast": {
+		Primary:    "#FFFFFF",
+		Secondary:  "#FFFFFF",
+		Accent:     "#FF00FF",
+		Error:      "#FF0000",
+		Success:    "#00FF00",
+		Warning:    "#FFFF00",
+		Background: "#000000",
+		Text:       "#FFFFFF",
+		DimText:    "#808080",
+	},
+}
+
+// ThemeNames returns the names of the built-in themes in alphabetical order
+func ThemeNames() []string {
+	names := make([]string, 0, len(Themes))
+	for name := range Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupTheme returns the built-in theme with the given name
+func LookupTheme(name string) (Theme, error) {
+	theme, ok := Themes[strings.ToLower(name)]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown theme %q, available are %s", name, strings.Join(ThemeNames(), ", "))
+	}
+	return theme, nil
+}
+
+// hexColor matches the #RGB and #RRGGBB colors accepted as overrides
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// WithOverrides returns the theme with colors replaced by hex values keyed by
+// color name, e.g. {"accent": "#D33682", "dim-text": "#888"}
+func (t Theme) WithOverrides(overrides map[string]string) (Theme, error) {
+	colors := map[string]*lipgloss.Color{
+		"primary":    &t.Primary,
+		"secondary":  &t.Secondary,
+		"accent":     &t.Accent,
+		"error":      &t.Error,
+		"success":    &t.Success,
+		"warning":    &t.Warning,
+		"background": &t.Background,
+		"text":       &t.Text,
+		"dim-text":   &t.DimText,
+	}
+
+	for name, value := range overrides {
+		color, ok := colors[name]
+		if !ok {
+			return Theme{}, fmt.Errorf("unknown color %q", name)
+		}
+		if !hexColor.MatchString(value) {
+			return Theme{}, fmt.Errorf("color %s: %q is not a hex color like #RRGGBB", name, value)
+		}
+		*color = lipgloss.Color(value)
+	}
+	return t, nil
+}
+
+// Colors and styles of the current theme, set by applyTheme
+var (
+	primaryColor    lipgloss.Color
+	secondaryColor  lipgloss.Color
+	accentColor     lipgloss.Color
+	errorColor      lipgloss.Color
+	successColor    lipgloss.Color
+	warningColor    lipgloss.Color
+	backgroundColor lipgloss.Color
+	textColor       lipgloss.Color
+	dimTextColor    lipgloss.Color
+
+	tabStyle       lipgloss.Style
+	activeTabStyle lipgloss.Style
+	alertTabStyle  lipgloss.Style
+	contentStyle   lipgloss.Style
+)
+
+func init() {
+	applyTheme(Themes[DefaultTheme], false)
+}
+
+// applyTheme switches the colors and styles of the UI to theme. Without
+// colors the active tab is shown in reverse video instead. The theme applies
+// to all models of the process.
+func applyTheme(theme Theme, noColor bool) {
+	primaryColor = theme.Primary
+	secondaryColor = theme.Secondary
+	accentColor = theme.Accent
+	errorColor = theme.Error
+	successColor = theme.Success
+	warningColor = theme.Warning
+	backgroundColor = theme.Background
+	textColor = theme.Text
+	dimTextColor = theme.DimText
+
+	tabStyle = lipgloss.NewStyle().
+		Foreground(textColor).
+		Background(dimTextColor).
+		Padding(0, 2).
+		Margin(0, 1, 0, 0).
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderBottom(true).
+		BorderForeground(dimTextColor)
+
+	activeTabStyle = lipgloss.NewStyle().
+		Foreground(textColor).
+		Background(accentColor).
+		Padding(0, 2).
+		Margin(0, 1, 0, 0).
+		Bold(true).
+		Reverse(noColor).
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderBottom(true).
+		BorderForeground(accentColor)
+
+	alertTabStyle = lipgloss.NewStyle().
+		Foreground(backgroundColor).
+		Background(errorColor).
+		Padding(0, 2).
+		Margin(0, 1, 0, 0).
+		Bold(true).
+		Reverse(noColor).
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderBottom(true).
+		BorderForeground(errorColor)
+
+	contentStyle = lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(secondaryColor).
+		Margin(1, 0, 0, 0).
+		Padding(1, 2)
+}
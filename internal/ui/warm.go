@@ -0,0 +1,136 @@
+package ui
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/charmbracelet/bubbletea"
+
+	"github.com/correctedcloud/aws-overview/internal/config"
+)
+
+// sharedConfig loads the AWS configuration once for all services, so that
+// their clients share its credentials cache and HTTP connections instead of
+// each resolving credentials, e.g. assuming a role, on its own. A load that
+// failed is retried by the next service needing the configuration.
+type sharedConfig struct {
+	ctx       context.Context // Lifetime of the loads, independent of the fetch that started them
+	endpoints config.Endpoints
+	retryer   *config.Retryer
+
+	mu   sync.Mutex
+	load *configLoad
+}
+
+// configLoad is a load of the AWS configuration, complete once done is closed
+type configLoad struct {
+	region    string // Requested region, empty for the region of the profile
+	done      chan struct{}
+	awsConfig aws.Config
+	err       error
+}
+
+// newSharedConfig returns a shared configuration calling endpoints with
+// retryer, whose loads run until ctx is cancelled
+func newSharedConfig(ctx context.Context, endpoints config.Endpoints, retryer *config.Retryer) *sharedConfig {
+	return &sharedConfig{ctx: ctx, endpoints: endpoints, retryer: retryer}
+}
+
+// get returns the AWS configuration of region, waiting for the load in
+// flight or starting one unless an earlier load of the region succeeded
+func (s *sharedConfig) get(ctx context.Context, region string) (aws.Config, error) {
+	s.mu.Lock()
+	load := s.load
+	if load == nil || !load.serves(region) {
+		load = s.start(region)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-load.done:
+		return load.awsConfig, load.err
+	case <-ctx.Done():
+		return aws.Config{}, ctx.Err()
+	}
+}
+
+// reset makes the next get load the configuration anew, e.g. to pick up
+// credentials renewed since the last load
+func (s *sharedConfig) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.load = nil
+}
+
+// start loads the configuration of region in the background, then retrieves
+// the credentials so the first calls of the services find them cached
+func (s *sharedConfig) start(region string) *configLoad {
+	load := &configLoad{region: region, done: make(chan struct{})}
+	s.load = load
+
+	go func() {
+		defer close(load.done)
+		load.awsConfig, load.err = config.LoadAWSConfig(s.ctx, config.NewConfig(region).WithEndpoints(s.endpoints).WithRetryer(s.retryer))
+		if load.err == nil && load.awsConfig.Credentials != nil {
+			// Failures surface with the calls that need the credentials
+			_, _ = load.awsConfig.Credentials.Retrieve(s.ctx)
+		}
+	}()
+	return load
+}
+
+// serves reports whether the load is for region and has not failed. The
+// region resolved from the profile matches too, since services ask for it
+// once the first of them reported it.
+func (l *configLoad) serves(region string) bool {
+	select {
+	case <-l.done:
+		return l.err == nil && (l.region == region || l.awsConfig.Region == region)
+	default:
+		return l.region == region
+	}
+}
+
+// loadAWSConfig returns the AWS configuration shared by all services and the
+// region it resolved to
+func (m Model) loadAWSConfig(ctx context.Context) (aws.Config, string, error) {
+	awsConfig, err := m.awsConfig.get(ctx, m.region)
+	return awsConfig, awsConfig.Region, err
+}
+
+// prewarm resolves the AWS configuration and credentials, and the account
+// the cache is keyed by, in the background while the UI starts
+func (m Model) prewarm() {
+	go func() {
+		awsConfig, _, err := m.loadAWSConfig(m.ctx)
+		if err == nil {
+			m.cacheKey(m.ctx, awsConfig, "")
+		}
+	}()
+}
+
+// prestart runs cmd in the background right away and returns a command
+// waiting for its message, so that the first loads are under way before the
+// program renders its first frame. Batched commands are started likewise.
+func prestart(cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+
+	result := make(chan tea.Msg, 1)
+	go func() {
+		msg := cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			started := make(tea.BatchMsg, len(batch))
+			for i, cmd := range batch {
+				started[i] = prestart(cmd)
+			}
+			msg = started
+		}
+		result <- msg
+	}()
+	return func() tea.Msg {
+		return <-result
+	}
+}
@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+
+	"github.com/correctedcloud/aws-overview/pkg/cloudwatchmetrics"
+)
+
+// windowKeys maps the keys choosing how far back the metric graphs go to
+// the window. 24 is typed as 2 then 4.
+var windowKeys = map[string]time.Duration{
+	"1":  time.Hour,
+	"3":  3 * time.Hour,
+	"6":  6 * time.Hour,
+	"24": 24 * time.Hour,
+}
+
+// updateWindowKey chooses the metrics window with 1, 3, 6 or 24 and reloads
+// the tabs whose graphs follow it. prefix is the 2 typed just before key,
+// which starts a fresh window key unless it completes 24, e.g. 2 then 1
+// chooses the last hour.
+func (m Model) updateWindowKey(prefix, key string) (Model, tea.Cmd) {
+	if _, ok := windowKeys[prefix+key]; ok {
+		key = prefix + key
+	}
+	if key == "2" {
+		m.windowPrefix = key
+		return m, nil
+	}
+	window, ok := windowKeys[key]
+	if !ok || window == m.window {
+		return m, nil
+	}
+
+	m.window = window
+	var tabs []tab
+	for _, t := range m.tabs {
+		if t.windowed && t.load != nil {
+			tabs = append(tabs, t)
+		}
+	}
+	m, toast := m.showToast("Metrics of the last "+cloudwatchmetrics.FormatWindow(window)+", reloading", successColor)
+	return m, tea.Batch(m.loadTraced(tabs), toast)
+}
@@ -3,10 +3,16 @@ package alb
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
+	"strconv"
 	"sync"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
 )
 
 // elbv2ClientAPI defines the interface for the ELBv2 client
@@ -14,18 +20,61 @@ type elbv2ClientAPI interface {
 	DescribeLoadBalancers(ctx context.Context, params *elasticloadbalancingv2.DescribeLoadBalancersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error)
 	DescribeTargetGroups(ctx context.Context, params *elasticloadbalancingv2.DescribeTargetGroupsInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTargetGroupsOutput, error)
 	DescribeTargetHealth(ctx context.Context, params *elasticloadbalancingv2.DescribeTargetHealthInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTargetHealthOutput, error)
+	DescribeListeners(ctx context.Context, params *elasticloadbalancingv2.DescribeListenersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeListenersOutput, error)
+	DescribeRules(ctx context.Context, params *elasticloadbalancingv2.DescribeRulesInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeRulesOutput, error)
+	DescribeListenerCertificates(ctx context.Context, params *elasticloadbalancingv2.DescribeListenerCertificatesInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeListenerCertificatesOutput, error)
+	DeregisterTargets(ctx context.Context, params *elasticloadbalancingv2.DeregisterTargetsInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DeregisterTargetsOutput, error)
+	RegisterTargets(ctx context.Context, params *elasticloadbalancingv2.RegisterTargetsInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.RegisterTargetsOutput, error)
 }
 
-// Client represents an ALB client
+// Client represents an Elastic Load Balancing client for application,
+// network and gateway load balancers
 type Client struct {
 	elbv2Client elbv2ClientAPI
+	acmClient   certificatesClientAPI
+	pool        *common.Pool
+
+	certificatesMu sync.Mutex
+	certificates   map[string]certificateDetail // By ARN
 }
 
 // LoadBalancerSummary represents a summary of a load balancer and its target groups
 type LoadBalancerSummary struct {
-	Name         string
-	DNSName      string
-	TargetGroups []TargetGroupSummary
+	Name            string
+	ARN             string
+	Type            string // "application", "network" or "gateway"
+	Scheme          string // "internet-facing" or "internal"
+	DNSName         string
+	TargetGroups    []TargetGroupSummary
+	Listeners       []ListenerSummary
+	ListenersLoaded bool // False when the listeners could not be described
+}
+
+// ListenerSummary represents a listener and the target groups its default action forwards to
+type ListenerSummary struct {
+	ARN             string
+	Protocol        string
+	Port            int32
+	TargetGroupARNs []string
+	Rules           []RuleSummary        // Sorted by priority, the default rule last
+	RulesLoaded     bool                 // False when the rules could not be described
+	Certificates    []CertificateSummary // Of HTTPS and TLS listeners, the default one first
+}
+
+// RuleSummary represents a listener rule and where it sends matching requests
+type RuleSummary struct {
+	Priority        string // "default" for the listener's default rule
+	IsDefault       bool
+	Conditions      []ConditionSummary // All conditions must match
+	Action          string             // Type of the final action, e.g. "forward", "redirect" or "fixed-response"
+	TargetGroupARNs []string
+}
+
+// ConditionSummary represents a rule condition, which matches when any of its values does
+type ConditionSummary struct {
+	Field      string   // e.g. "host-header", "path-pattern" or "http-header"
+	HeaderName string   // Header compared by http-header conditions
+	Values     []string // "key=value" for query-string conditions, with an empty key matching any key
 }
 
 // TargetGroupSummary represents a summary of a target group and its targets
@@ -43,26 +92,57 @@ type TargetSummary struct {
 	Reason string
 }
 
-// NewClient returns a new ALB client
-func NewClient(elbv2Client elbv2ClientAPI) *Client {
+// NewClient returns a new load balancing client whose calls run in pool,
+// which may be nil. acmClient describes the listener certificates and may be
+// nil to leave out their domain and expiry.
+func NewClient(elbv2Client elbv2ClientAPI, acmClient certificatesClientAPI, pool *common.Pool) *Client {
 	return &Client{
-		elbv2Client: elbv2Client,
+		elbv2Client:  elbv2Client,
+		acmClient:    acmClient,
+		pool:         pool,
+		certificates: make(map[string]certificateDetail),
 	}
 }
 
-// GetLoadBalancers returns a list of load balancers with their target groups and health status
-func (c *Client) GetLoadBalancers(ctx context.Context) ([]LoadBalancerSummary, error) {
-	result, err := c.elbv2Client.DescribeLoadBalancers(ctx, &elasticloadbalancingv2.DescribeLoadBalancersInput{})
+// GetLoadBalancers returns a list of load balancers with their target groups and health status.
+// A target group that fails to load is left out and its error returned
+// alongside the load balancers that did load.
+func (c *Client) GetLoadBalancers(ctx context.Context) ([]LoadBalancerSummary, []error) {
+	summaries, _, errs := c.StreamLoadBalancers(ctx, 0, nil)
+	return summaries, errs
+}
+
+// StreamLoadBalancers is GetLoadBalancers limited to the first limit load
+// balancers by name, or all of them when limit is 0, which also returns how
+// many there are in total. Only the load balancers within the limit have
+// their listeners and target groups described, which is where most calls go
+// in large accounts.
+//
+// loaded is called with the load balancers loaded so far each time one of
+// them completes, so that callers can show the first ones without waiting
+// for the slowest. The calls are not concurrent and each gets its own slice.
+// loaded may be nil.
+func (c *Client) StreamLoadBalancers(ctx context.Context, limit int, loaded func([]LoadBalancerSummary)) ([]LoadBalancerSummary, int, []error) {
+	loadBalancers, err := c.describeLoadBalancers(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to describe load balancers: %w", err)
+		return nil, 0, []error{err}
+	}
+
+	total := len(loadBalancers)
+	if limit > 0 && total > limit {
+		sort.Slice(loadBalancers, func(i, j int) bool {
+			return aws.ToString(loadBalancers[i].LoadBalancerName) < aws.ToString(loadBalancers[j].LoadBalancerName)
+		})
+		loadBalancers = loadBalancers[:limit]
 	}
 
 	// Process load balancers in parallel
 	var wg sync.WaitGroup
-	summariesCh := make(chan LoadBalancerSummary, len(result.LoadBalancers))
-	errorsCh := make(chan error, len(result.LoadBalancers))
+	var mu sync.Mutex
+	var summaries []LoadBalancerSummary
+	var errs []error
 
-	for _, lb := range result.LoadBalancers {
+	for _, lb := range loadBalancers {
 		wg.Add(1)
 		go func(loadBalancer types.LoadBalancer) {
 			defer wg.Done()
@@ -70,74 +150,302 @@ func (c *Client) GetLoadBalancers(ctx context.Context) ([]LoadBalancerSummary, e
 			// Create a summary for this load balancer
 			lbSummary := LoadBalancerSummary{
 				Name:    *loadBalancer.LoadBalancerName,
+				ARN:     aws.ToString(loadBalancer.LoadBalancerArn),
+				Type:    string(loadBalancer.Type),
+				Scheme:  string(loadBalancer.Scheme),
 				DNSName: *loadBalancer.DNSName,
 			}
 
+			listeners, err := c.getListeners(ctx, loadBalancer)
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			} else {
+				lbSummary.Listeners = listeners
+				lbSummary.ListenersLoaded = true
+			}
+
+			if !lbSummary.HasRules() {
+				defaultRules(lbSummary.Listeners)
+			} else if ruleErrs := c.loadRules(ctx, *loadBalancer.LoadBalancerName, lbSummary.Listeners); len(ruleErrs) > 0 {
+				mu.Lock()
+				errs = append(errs, ruleErrs...)
+				mu.Unlock()
+			}
+
+			if certificateErrs := c.loadCertificates(ctx, *loadBalancer.LoadBalancerName, lbSummary.Listeners); len(certificateErrs) > 0 {
+				mu.Lock()
+				errs = append(errs, certificateErrs...)
+				mu.Unlock()
+			}
+
 			// Get target groups for this load balancer
-			tgResult, err := c.elbv2Client.DescribeTargetGroups(ctx, &elasticloadbalancingv2.DescribeTargetGroupsInput{
-				LoadBalancerArn: loadBalancer.LoadBalancerArn,
+			var tgResult *elasticloadbalancingv2.DescribeTargetGroupsOutput
+			err = c.pool.Do(ctx, func() (err error) {
+				tgResult, err = c.elbv2Client.DescribeTargetGroups(ctx, &elasticloadbalancingv2.DescribeTargetGroupsInput{
+					LoadBalancerArn: loadBalancer.LoadBalancerArn,
+				})
+				return err
 			})
 			if err != nil {
-				errorsCh <- fmt.Errorf("failed to describe target groups for LB %s: %w", *loadBalancer.LoadBalancerName, err)
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("failed to describe target groups for LB %s: %w", *loadBalancer.LoadBalancerName, err))
+				summaries = append(summaries, lbSummary)
+				if loaded != nil {
+					loaded(append([]LoadBalancerSummary(nil), summaries...))
+				}
+				mu.Unlock()
 				return
 			}
 
 			// Process target groups in parallel
 			var tgWg sync.WaitGroup
-			tgSummariesCh := make(chan TargetGroupSummary, len(tgResult.TargetGroups))
-			tgErrorsCh := make(chan error, len(tgResult.TargetGroups))
+			var tgMu sync.Mutex
+			var tgErrs []error
 
 			for _, tg := range tgResult.TargetGroups {
 				tgWg.Add(1)
 				go func(targetGroup types.TargetGroup) {
 					defer tgWg.Done()
 					tgSummary, err := c.getTargetGroupSummary(ctx, targetGroup)
+
+					tgMu.Lock()
+					defer tgMu.Unlock()
 					if err != nil {
-						tgErrorsCh <- err
+						tgErrs = append(tgErrs, err)
 						return
 					}
-					tgSummariesCh <- tgSummary
+					lbSummary.TargetGroups = append(lbSummary.TargetGroups, tgSummary)
 				}(tg)
 			}
 
 			// Wait for all target group goroutines to complete
 			tgWg.Wait()
-			close(tgSummariesCh)
-			close(tgErrorsCh)
-
-			// Check for errors
-			if len(tgErrorsCh) > 0 {
-				errorsCh <- <-tgErrorsCh
-				return
-			}
 
-			// Collect target group summaries
-			for tgSummary := range tgSummariesCh {
-				lbSummary.TargetGroups = append(lbSummary.TargetGroups, tgSummary)
+			mu.Lock()
+			errs = append(errs, tgErrs...)
+			summaries = append(summaries, lbSummary)
+			if loaded != nil {
+				loaded(append([]LoadBalancerSummary(nil), summaries...))
 			}
-
-			// Send the load balancer summary
-			summariesCh <- lbSummary
+			mu.Unlock()
 		}(lb)
 	}
 
 	// Wait for all load balancer goroutines to complete
 	wg.Wait()
-	close(summariesCh)
-	close(errorsCh)
 
-	// Check for errors
-	if len(errorsCh) > 0 {
-		return nil, <-errorsCh
+	return summaries, total, errs
+}
+
+// describeLoadBalancers returns all load balancers, following the pagination markers
+func (c *Client) describeLoadBalancers(ctx context.Context) ([]types.LoadBalancer, error) {
+	var loadBalancers []types.LoadBalancer
+	var marker *string
+
+	for {
+		var result *elasticloadbalancingv2.DescribeLoadBalancersOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.elbv2Client.DescribeLoadBalancers(ctx, &elasticloadbalancingv2.DescribeLoadBalancersInput{
+				Marker: marker,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe load balancers: %w", err)
+		}
+
+		loadBalancers = append(loadBalancers, result.LoadBalancers...)
+
+		marker = result.NextMarker
+		if marker == nil {
+			break
+		}
+	}
+
+	return loadBalancers, nil
+}
+
+// getListeners returns the listeners of a load balancer
+func (c *Client) getListeners(ctx context.Context, lb types.LoadBalancer) ([]ListenerSummary, error) {
+	var result *elasticloadbalancingv2.DescribeListenersOutput
+	err := c.pool.Do(ctx, func() (err error) {
+		result, err = c.elbv2Client.DescribeListeners(ctx, &elasticloadbalancingv2.DescribeListenersInput{
+			LoadBalancerArn: lb.LoadBalancerArn,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe listeners for LB %s: %w", *lb.LoadBalancerName, err)
 	}
 
-	// Collect all load balancer summaries
-	var summaries []LoadBalancerSummary
-	for summary := range summariesCh {
-		summaries = append(summaries, summary)
+	var listeners []ListenerSummary
+	for _, listener := range result.Listeners {
+		summary := ListenerSummary{
+			ARN:      aws.ToString(listener.ListenerArn),
+			Protocol: string(listener.Protocol),
+		}
+		if listener.Port != nil {
+			summary.Port = *listener.Port
+		}
+		if lb.Type == types.LoadBalancerTypeEnumGateway && summary.Protocol == "" {
+			summary.Protocol, summary.Port = geneveProtocol, genevePort
+		}
+		summary.TargetGroupARNs = forwardTargetGroups(listener.DefaultActions)
+		// Only the default certificate is described with the listener
+		for _, certificate := range listener.Certificates {
+			summary.Certificates = append(summary.Certificates, CertificateSummary{ARN: aws.ToString(certificate.CertificateArn), IsDefault: true})
+		}
+		listeners = append(listeners, summary)
 	}
 
-	return summaries, nil
+	return listeners, nil
+}
+
+// loadRules fills in the rules of each listener. Listeners whose rules fail to
+// load are left without them and the errors returned.
+func (c *Client) loadRules(ctx context.Context, lbName string, listeners []ListenerSummary) []error {
+	var errs []error
+	for i := range listeners {
+		rules, err := c.getRules(ctx, listeners[i].ARN)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to describe rules of listener %s:%d on LB %s: %w",
+				listeners[i].Protocol, listeners[i].Port, lbName, err))
+			continue
+		}
+		listeners[i].Rules = rules
+		listeners[i].RulesLoaded = true
+	}
+	return errs
+}
+
+// defaultRules gives the listeners of load balancers without rules the
+// default rule they route all traffic with
+func defaultRules(listeners []ListenerSummary) {
+	for i := range listeners {
+		listeners[i].Rules = []RuleSummary{{
+			Priority:        "default",
+			IsDefault:       true,
+			Action:          string(types.ActionTypeEnumForward),
+			TargetGroupARNs: listeners[i].TargetGroupARNs,
+		}}
+		listeners[i].RulesLoaded = true
+	}
+}
+
+// getRules returns the rules of a listener sorted by priority, following the pagination markers
+func (c *Client) getRules(ctx context.Context, listenerARN string) ([]RuleSummary, error) {
+	var rules []RuleSummary
+	var marker *string
+
+	for {
+		var result *elasticloadbalancingv2.DescribeRulesOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.elbv2Client.DescribeRules(ctx, &elasticloadbalancingv2.DescribeRulesInput{
+				ListenerArn: aws.String(listenerARN),
+				Marker:      marker,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rule := range result.Rules {
+			rules = append(rules, newRuleSummary(rule))
+		}
+
+		marker = result.NextMarker
+		if marker == nil {
+			break
+		}
+	}
+
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rulePriority(rules[i]) < rulePriority(rules[j])
+	})
+	return rules, nil
+}
+
+// newRuleSummary summarizes a listener rule
+func newRuleSummary(rule types.Rule) RuleSummary {
+	summary := RuleSummary{
+		Priority:        aws.ToString(rule.Priority),
+		IsDefault:       aws.ToBool(rule.IsDefault),
+		TargetGroupARNs: forwardTargetGroups(rule.Actions),
+	}
+
+	// Authenticate actions run first, the remaining action decides the response
+	for _, action := range rule.Actions {
+		if action.Type != types.ActionTypeEnumAuthenticateOidc && action.Type != types.ActionTypeEnumAuthenticateCognito {
+			summary.Action = string(action.Type)
+		}
+	}
+
+	for _, condition := range rule.Conditions {
+		summary.Conditions = append(summary.Conditions, newConditionSummary(condition))
+	}
+	return summary
+}
+
+// newConditionSummary summarizes a rule condition, reading the values from
+// the field specific configuration when present
+func newConditionSummary(condition types.RuleCondition) ConditionSummary {
+	summary := ConditionSummary{
+		Field:  aws.ToString(condition.Field),
+		Values: condition.Values,
+	}
+
+	switch {
+	case condition.HostHeaderConfig != nil:
+		summary.Values = condition.HostHeaderConfig.Values
+	case condition.PathPatternConfig != nil:
+		summary.Values = condition.PathPatternConfig.Values
+	case condition.HttpHeaderConfig != nil:
+		summary.HeaderName = aws.ToString(condition.HttpHeaderConfig.HttpHeaderName)
+		summary.Values = condition.HttpHeaderConfig.Values
+	case condition.HttpRequestMethodConfig != nil:
+		summary.Values = condition.HttpRequestMethodConfig.Values
+	case condition.SourceIpConfig != nil:
+		summary.Values = condition.SourceIpConfig.Values
+	case condition.QueryStringConfig != nil:
+		summary.Values = nil
+		for _, pair := range condition.QueryStringConfig.Values {
+			summary.Values = append(summary.Values, aws.ToString(pair.Key)+"="+aws.ToString(pair.Value))
+		}
+	}
+	return summary
+}
+
+// rulePriority returns the numeric priority of a rule, with the default rule evaluated last
+func rulePriority(rule RuleSummary) int {
+	priority, err := strconv.Atoi(rule.Priority)
+	if rule.IsDefault || err != nil {
+		return math.MaxInt
+	}
+	return priority
+}
+
+// forwardTargetGroups returns the ARNs of the target groups that forward actions send traffic to
+func forwardTargetGroups(actions []types.Action) []string {
+	var arns []string
+	for _, action := range actions {
+		if action.Type != types.ActionTypeEnumForward {
+			continue
+		}
+		if action.TargetGroupArn != nil {
+			arns = append(arns, *action.TargetGroupArn)
+		}
+		if action.ForwardConfig != nil {
+			for _, tg := range action.ForwardConfig.TargetGroups {
+				if tg.TargetGroupArn != nil && (action.TargetGroupArn == nil || *tg.TargetGroupArn != *action.TargetGroupArn) {
+					arns = append(arns, *tg.TargetGroupArn)
+				}
+			}
+		}
+	}
+	return arns
 }
 
 // getTargetGroupSummary returns a summary of a target group with health status
@@ -147,8 +455,12 @@ func (c *Client) getTargetGroupSummary(ctx context.Context, tg types.TargetGroup
 		ARN:  *tg.TargetGroupArn,
 	}
 
-	healthResult, err := c.elbv2Client.DescribeTargetHealth(ctx, &elasticloadbalancingv2.DescribeTargetHealthInput{
-		TargetGroupArn: tg.TargetGroupArn,
+	var healthResult *elasticloadbalancingv2.DescribeTargetHealthOutput
+	err := c.pool.Do(ctx, func() (err error) {
+		healthResult, err = c.elbv2Client.DescribeTargetHealth(ctx, &elasticloadbalancingv2.DescribeTargetHealthInput{
+			TargetGroupArn: tg.TargetGroupArn,
+		})
+		return err
 	})
 	if err != nil {
 		return TargetGroupSummary{}, fmt.Errorf("failed to describe target health for TG %s: %w", *tg.TargetGroupName, err)
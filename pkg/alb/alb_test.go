@@ -2,8 +2,11 @@ package alb
 
 import (
 	"context"
+	"errors"
+	"sync"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
 )
@@ -13,6 +16,11 @@ type mockELBV2Client struct {
 	describeLoadBalancersFunc func(ctx context.Context, params *elasticloadbalancingv2.DescribeLoadBalancersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error)
 	describeTargetGroupsFunc  func(ctx context.Context, params *elasticloadbalancingv2.DescribeTargetGroupsInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTargetGroupsOutput, error)
 	describeTargetHealthFunc  func(ctx context.Context, params *elasticloadbalancingv2.DescribeTargetHealthInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTargetHealthOutput, error)
+	describeListenersFunc     func(ctx context.Context, params *elasticloadbalancingv2.DescribeListenersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeListenersOutput, error)
+	describeRulesFunc         func(ctx context.Context, params *elasticloadbalancingv2.DescribeRulesInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeRulesOutput, error)
+	describeCertificatesFunc  func(ctx context.Context, params *elasticloadbalancingv2.DescribeListenerCertificatesInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeListenerCertificatesOutput, error)
+	deregisterTargetsFunc     func(ctx context.Context, params *elasticloadbalancingv2.DeregisterTargetsInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DeregisterTargetsOutput, error)
+	registerTargetsFunc       func(ctx context.Context, params *elasticloadbalancingv2.RegisterTargetsInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.RegisterTargetsOutput, error)
 }
 
 func (m *mockELBV2Client) DescribeLoadBalancers(ctx context.Context, params *elasticloadbalancingv2.DescribeLoadBalancersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error) {
@@ -27,6 +35,35 @@ func (m *mockELBV2Client) DescribeTargetHealth(ctx context.Context, params *elas
 	return m.describeTargetHealthFunc(ctx, params, optFns...)
 }
 
+func (m *mockELBV2Client) DescribeListeners(ctx context.Context, params *elasticloadbalancingv2.DescribeListenersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeListenersOutput, error) {
+	if m.describeListenersFunc == nil {
+		return &elasticloadbalancingv2.DescribeListenersOutput{}, nil
+	}
+	return m.describeListenersFunc(ctx, params, optFns...)
+}
+
+func (m *mockELBV2Client) DescribeRules(ctx context.Context, params *elasticloadbalancingv2.DescribeRulesInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeRulesOutput, error) {
+	if m.describeRulesFunc == nil {
+		return &elasticloadbalancingv2.DescribeRulesOutput{}, nil
+	}
+	return m.describeRulesFunc(ctx, params, optFns...)
+}
+
+func (m *mockELBV2Client) DescribeListenerCertificates(ctx context.Context, params *elasticloadbalancingv2.DescribeListenerCertificatesInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeListenerCertificatesOutput, error) {
+	if m.describeCertificatesFunc == nil {
+		return &elasticloadbalancingv2.DescribeListenerCertificatesOutput{}, nil
+	}
+	return m.describeCertificatesFunc(ctx, params, optFns...)
+}
+
+func (m *mockELBV2Client) DeregisterTargets(ctx context.Context, params *elasticloadbalancingv2.DeregisterTargetsInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DeregisterTargetsOutput, error) {
+	return m.deregisterTargetsFunc(ctx, params, optFns...)
+}
+
+func (m *mockELBV2Client) RegisterTargets(ctx context.Context, params *elasticloadbalancingv2.RegisterTargetsInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.RegisterTargetsOutput, error) {
+	return m.registerTargetsFunc(ctx, params, optFns...)
+}
+
 func TestGetLoadBalancers(t *testing.T) {
 	// Create mock data
 	lbName := "test-lb"
@@ -86,11 +123,11 @@ func TestGetLoadBalancers(t *testing.T) {
 	}
 
 	// Call the method being tested
-	lbs, err := client.GetLoadBalancers(context.Background())
+	lbs, errs := client.GetLoadBalancers(context.Background())
 
 	// Assertions
-	if err != nil {
-		t.Fatalf("Expected no error, got %v", err)
+	if len(errs) > 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
 	}
 
 	if len(lbs) != 1 {
@@ -132,3 +169,256 @@ func TestGetLoadBalancers(t *testing.T) {
 		t.Errorf("Expected target status %s, got %s", targetStatus, target.Status)
 	}
 }
+
+func TestGetLoadBalancersPartialFailure(t *testing.T) {
+	healthyName, brokenName := "healthy-lb", "broken-lb"
+	healthyARN, brokenARN := "arn:healthy", "arn:broken"
+	dnsName := "lb.us-east-1.elb.amazonaws.com"
+	tgName, tgARN := "tg", "arn:tg"
+
+	mockClient := &mockELBV2Client{
+		describeLoadBalancersFunc: func(ctx context.Context, params *elasticloadbalancingv2.DescribeLoadBalancersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error) {
+			return &elasticloadbalancingv2.DescribeLoadBalancersOutput{
+				LoadBalancers: []types.LoadBalancer{
+					{LoadBalancerName: &healthyName, LoadBalancerArn: &healthyARN, DNSName: &dnsName},
+					{LoadBalancerName: &brokenName, LoadBalancerArn: &brokenARN, DNSName: &dnsName},
+				},
+			}, nil
+		},
+		describeTargetGroupsFunc: func(ctx context.Context, params *elasticloadbalancingv2.DescribeTargetGroupsInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTargetGroupsOutput, error) {
+			if *params.LoadBalancerArn == brokenARN {
+				return nil, errors.New("AccessDenied")
+			}
+			return &elasticloadbalancingv2.DescribeTargetGroupsOutput{
+				TargetGroups: []types.TargetGroup{{TargetGroupName: &tgName, TargetGroupArn: &tgARN}},
+			}, nil
+		},
+		describeTargetHealthFunc: func(ctx context.Context, params *elasticloadbalancingv2.DescribeTargetHealthInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTargetHealthOutput, error) {
+			return &elasticloadbalancingv2.DescribeTargetHealthOutput{}, nil
+		},
+	}
+
+	lbs, errs := NewClient(mockClient, nil, nil).GetLoadBalancers(context.Background())
+
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %v", errs)
+	}
+	if len(lbs) != 2 {
+		t.Fatalf("Expected both load balancers despite the failure, got %d", len(lbs))
+	}
+	for _, lb := range lbs {
+		if lb.Name == healthyName && len(lb.TargetGroups) != 1 {
+			t.Errorf("Expected 1 target group for '%s', got %d", healthyName, len(lb.TargetGroups))
+		}
+	}
+}
+
+func TestStreamLoadBalancers(t *testing.T) {
+	names := []string{"lb-1", "lb-2", "lb-3"}
+	dnsName := "lb.us-east-1.elb.amazonaws.com"
+
+	var loadBalancers []types.LoadBalancer
+	for i := range names {
+		loadBalancers = append(loadBalancers, types.LoadBalancer{
+			LoadBalancerName: &names[i],
+			LoadBalancerArn:  aws.String("arn:" + names[i]),
+			DNSName:          &dnsName,
+		})
+	}
+
+	mockClient := &mockELBV2Client{
+		describeLoadBalancersFunc: func(ctx context.Context, params *elasticloadbalancingv2.DescribeLoadBalancersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error) {
+			return &elasticloadbalancingv2.DescribeLoadBalancersOutput{LoadBalancers: loadBalancers}, nil
+		},
+		describeTargetGroupsFunc: func(ctx context.Context, params *elasticloadbalancingv2.DescribeTargetGroupsInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTargetGroupsOutput, error) {
+			return &elasticloadbalancingv2.DescribeTargetGroupsOutput{}, nil
+		},
+	}
+
+	var partials [][]LoadBalancerSummary
+	lbs, total, errs := NewClient(mockClient, nil, nil).StreamLoadBalancers(context.Background(), 0, func(lbs []LoadBalancerSummary) {
+		partials = append(partials, lbs)
+	})
+
+	if len(errs) != 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+	if len(partials) != len(names) {
+		t.Fatalf("Expected a partial result per load balancer, got %d", len(partials))
+	}
+	for i, partial := range partials {
+		if len(partial) != i+1 {
+			t.Errorf("Expected partial result %d to hold %d load balancers, got %d", i, i+1, len(partial))
+		}
+	}
+	if len(lbs) != len(names) || total != len(names) {
+		t.Errorf("Expected %d load balancers, got %d of %d", len(names), len(lbs), total)
+	}
+
+	// A limit loads the first load balancers by name only
+	var mu sync.Mutex
+	var described []string
+	mockClient.describeTargetGroupsFunc = func(ctx context.Context, params *elasticloadbalancingv2.DescribeTargetGroupsInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTargetGroupsOutput, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		described = append(described, aws.ToString(params.LoadBalancerArn))
+		return &elasticloadbalancingv2.DescribeTargetGroupsOutput{}, nil
+	}
+	loadBalancers[0], loadBalancers[2] = loadBalancers[2], loadBalancers[0]
+	lbs, total, _ = NewClient(mockClient, nil, nil).StreamLoadBalancers(context.Background(), 2, nil)
+	if len(lbs) != 2 || total != 3 {
+		t.Fatalf("Expected 2 of 3 load balancers, got %d of %d", len(lbs), total)
+	}
+	for _, lb := range lbs {
+		if lb.Name == "lb-3" {
+			t.Errorf("Expected lb-3 to be beyond the limit, got %v", lbs)
+		}
+	}
+	if len(described) != 2 {
+		t.Errorf("Expected the target groups of 2 load balancers to be described, got %v", described)
+	}
+}
+
+func TestGetLoadBalancersListeners(t *testing.T) {
+	lbName, lbARN := "lb", "arn:lb"
+	dnsName := "lb.us-east-1.elb.amazonaws.com"
+	tgName, tgARN := "tg", "arn:tg"
+
+	mockClient := &mockELBV2Client{
+		describeLoadBalancersFunc: func(ctx context.Context, params *elasticloadbalancingv2.DescribeLoadBalancersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error) {
+			return &elasticloadbalancingv2.DescribeLoadBalancersOutput{
+				LoadBalancers: []types.LoadBalancer{{LoadBalancerName: &lbName, LoadBalancerArn: &lbARN, DNSName: &dnsName}},
+			}, nil
+		},
+		describeTargetGroupsFunc: func(ctx context.Context, params *elasticloadbalancingv2.DescribeTargetGroupsInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTargetGroupsOutput, error) {
+			return &elasticloadbalancingv2.DescribeTargetGroupsOutput{
+				TargetGroups: []types.TargetGroup{{TargetGroupName: &tgName, TargetGroupArn: &tgARN}},
+			}, nil
+		},
+		describeTargetHealthFunc: func(ctx context.Context, params *elasticloadbalancingv2.DescribeTargetHealthInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTargetHealthOutput, error) {
+			return &elasticloadbalancingv2.DescribeTargetHealthOutput{}, nil
+		},
+		describeListenersFunc: func(ctx context.Context, params *elasticloadbalancingv2.DescribeListenersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeListenersOutput, error) {
+			return &elasticloadbalancingv2.DescribeListenersOutput{
+				Listeners: []types.Listener{
+					{
+						ListenerArn:    aws.String("arn:listener/https"),
+						Protocol:       types.ProtocolEnumHttps,
+						Port:           aws.Int32(443),
+						DefaultActions: []types.Action{{Type: types.ActionTypeEnumForward, TargetGroupArn: &tgARN}},
+					},
+					{
+						ListenerArn:    aws.String("arn:listener/http"),
+						Protocol:       types.ProtocolEnumHttp,
+						Port:           aws.Int32(80),
+						DefaultActions: []types.Action{{Type: types.ActionTypeEnumRedirect}},
+					},
+				},
+			}, nil
+		},
+		describeRulesFunc: func(ctx context.Context, params *elasticloadbalancingv2.DescribeRulesInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeRulesOutput, error) {
+			if *params.ListenerArn == "arn:listener/http" {
+				return nil, errors.New("access denied")
+			}
+			if params.Marker == nil {
+				return &elasticloadbalancingv2.DescribeRulesOutput{
+					Rules: []types.Rule{
+						{Priority: aws.String("default"), IsDefault: aws.Bool(true), Actions: []types.Action{{Type: types.ActionTypeEnumForward, TargetGroupArn: &tgARN}}},
+						{
+							Priority:   aws.String("20"),
+							Conditions: []types.RuleCondition{{Field: aws.String("path-pattern"), PathPatternConfig: &types.PathPatternConditionConfig{Values: []string{"/api/*"}}}},
+							Actions: []types.Action{
+								{Type: types.ActionTypeEnumAuthenticateOidc},
+								{Type: types.ActionTypeEnumFixedResponse},
+							},
+						},
+					},
+					NextMarker: aws.String("page-2"),
+				}, nil
+			}
+			return &elasticloadbalancingv2.DescribeRulesOutput{
+				Rules: []types.Rule{
+					{
+						Priority: aws.String("3"),
+						Conditions: []types.RuleCondition{
+							{Field: aws.String("http-header"), HttpHeaderConfig: &types.HttpHeaderConditionConfig{HttpHeaderName: aws.String("X-Canary"), Values: []string{"true"}}},
+							{Field: aws.String("query-string"), QueryStringConfig: &types.QueryStringConditionConfig{Values: []types.QueryStringKeyValuePair{{Key: aws.String("v"), Value: aws.String("2")}}}},
+						},
+						Actions: []types.Action{{Type: types.ActionTypeEnumForward, TargetGroupArn: &tgARN}},
+					},
+				},
+			}, nil
+		},
+	}
+
+	lbs, errs := NewClient(mockClient, nil, nil).GetLoadBalancers(context.Background())
+	if len(errs) != 1 {
+		t.Fatalf("Expected the rules error of the HTTP listener, got %v", errs)
+	}
+
+	lb := lbs[0]
+	if !lb.ListenersLoaded || len(lb.Listeners) != 2 {
+		t.Fatalf("Expected 2 loaded listeners, got %+v", lb.Listeners)
+	}
+	if https := lb.Listeners[0]; https.Port != 443 || len(https.TargetGroupARNs) != 1 || https.TargetGroupARNs[0] != tgARN {
+		t.Errorf("Expected HTTPS:443 forwarding to '%s', got %+v", tgARN, https)
+	}
+	if len(lb.Listeners[1].TargetGroupARNs) != 0 {
+		t.Errorf("Expected the redirect listener not to forward, got %v", lb.Listeners[1].TargetGroupARNs)
+	}
+
+	if lb.Listeners[1].RulesLoaded {
+		t.Errorf("Expected the rules of the HTTP listener not to be loaded")
+	}
+	rules := lb.Listeners[0].Rules
+	if !lb.Listeners[0].RulesLoaded || len(rules) != 3 {
+		t.Fatalf("Expected 3 rules across both pages, got %+v", rules)
+	}
+	if rules[0].Priority != "3" || rules[1].Priority != "20" || !rules[2].IsDefault {
+		t.Errorf("Expected rules sorted by priority with the default last, got %+v", rules)
+	}
+	if header := rules[0].Conditions[0]; header.Field != "http-header" || header.HeaderName != "X-Canary" || header.Values[0] != "true" {
+		t.Errorf("Expected the X-Canary header condition, got %+v", header)
+	}
+	if query := rules[0].Conditions[1]; query.Values[0] != "v=2" {
+		t.Errorf("Expected the query-string condition 'v=2', got %+v", query)
+	}
+	if rules[1].Action != "fixed-response" {
+		t.Errorf("Expected the action after authentication to be 'fixed-response', got '%s'", rules[1].Action)
+	}
+}
+
+func TestGetLoadBalancersPaginates(t *testing.T) {
+	dnsName := "lb.us-east-1.elb.amazonaws.com"
+	pages := map[string]*elasticloadbalancingv2.DescribeLoadBalancersOutput{
+		"": {
+			LoadBalancers: []types.LoadBalancer{{LoadBalancerName: aws.String("lb-1"), LoadBalancerArn: aws.String("arn:lb-1"), DNSName: &dnsName}},
+			NextMarker:    aws.String("page-2"),
+		},
+		"page-2": {
+			LoadBalancers: []types.LoadBalancer{{LoadBalancerName: aws.String("lb-2"), LoadBalancerArn: aws.String("arn:lb-2"), DNSName: &dnsName}},
+		},
+	}
+
+	calls := 0
+	mockClient := &mockELBV2Client{
+		describeLoadBalancersFunc: func(ctx context.Context, params *elasticloadbalancingv2.DescribeLoadBalancersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error) {
+			calls++
+			return pages[aws.ToString(params.Marker)], nil
+		},
+		describeTargetGroupsFunc: func(ctx context.Context, params *elasticloadbalancingv2.DescribeTargetGroupsInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTargetGroupsOutput, error) {
+			return &elasticloadbalancingv2.DescribeTargetGroupsOutput{}, nil
+		},
+	}
+
+	lbs, errs := NewClient(mockClient, nil, nil).GetLoadBalancers(context.Background())
+	if len(errs) > 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 DescribeLoadBalancers calls, got %d", calls)
+	}
+	if len(lbs) != 2 {
+		t.Fatalf("Expected load balancers from both pages, got %d", len(lbs))
+	}
+}
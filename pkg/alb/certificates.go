@@ -0,0 +1,279 @@
+package alb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	acmtypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+)
+
+// certificatesClientAPI defines the interface for the ACM client
+type certificatesClientAPI interface {
+	DescribeCertificate(ctx context.Context, params *acm.DescribeCertificateInput, optFns ...func(*acm.Options)) (*acm.DescribeCertificateOutput, error)
+}
+
+// CertificateWarningDays is how many days ahead the expiry of a listener
+// certificate is warned about
+const CertificateWarningDays = 30
+
+// timeNow returns the current time, replaced by tests
+var timeNow = time.Now
+
+// CertificateSummary represents a certificate served by an HTTPS or TLS listener
+type CertificateSummary struct {
+	ARN       string
+	IsDefault bool      // Served to clients whose SNI host matches no other certificate
+	Domain    string    // Empty when the certificate could not be described, e.g. IAM server certificates
+	NotAfter  time.Time // Zero when the certificate could not be described
+	Status    string    // ACM status, e.g. "ISSUED" or "EXPIRED"
+
+	Type               string // "AMAZON_ISSUED", "IMPORTED" or "PRIVATE"
+	RenewalEligibility string // "ELIGIBLE" when ACM renews the certificate before it expires
+	ValidationStatus   string // Of the domain validation of the latest issue or renewal, the worst of its domains, e.g. "PENDING_VALIDATION"
+}
+
+// CertificateUse is a certificate and the listeners serving it
+type CertificateUse struct {
+	CertificateSummary
+	Listeners []string // e.g. "web-prod HTTPS:443"
+}
+
+// certificateDetail is what ACM describes of a certificate
+type certificateDetail struct {
+	domain             string
+	notAfter           time.Time
+	status             string
+	kind               string
+	renewalEligibility string
+	validationStatus   string
+}
+
+// Name returns the domain of the certificate, or the last part of its ARN
+// when it was not described
+func (c CertificateSummary) Name() string {
+	if c.Domain != "" {
+		return c.Domain
+	}
+	return c.ARN[strings.LastIndex(c.ARN, "/")+1:]
+}
+
+// IsExpiring reports whether the certificate expires within
+// CertificateWarningDays, or has expired
+func (c CertificateSummary) IsExpiring() bool {
+	return !c.NotAfter.IsZero() && c.NotAfter.Before(timeNow().Add(CertificateWarningDays*24*time.Hour))
+}
+
+// RenewsAutomatically reports whether ACM renews the certificate before it
+// expires, which it does not for imported certificates
+func (c CertificateSummary) RenewsAutomatically() bool {
+	return c.RenewalEligibility == string(acmtypes.RenewalEligibilityEligible)
+}
+
+// DaysUntilExpiry returns the days until the certificate expires, negative
+// once it has
+func (c CertificateSummary) DaysUntilExpiry() float64 {
+	return c.NotAfter.Sub(timeNow()).Hours() / 24
+}
+
+// GetCertificates returns the certificates served by the listeners of the
+// load balancers once each, with the listeners serving them, soonest to
+// expire first
+func GetCertificates(summaries []LoadBalancerSummary) []CertificateUse {
+	var certificates []CertificateUse
+	index := make(map[string]int)
+	for _, lb := range summaries {
+		for _, listener := range lb.Listeners {
+			for _, certificate := range listener.Certificates {
+				i, ok := index[certificate.ARN]
+				if !ok {
+					i = len(certificates)
+					index[certificate.ARN] = i
+					certificates = append(certificates, CertificateUse{CertificateSummary: certificate})
+				}
+				certificates[i].Listeners = append(certificates[i].Listeners, fmt.Sprintf("%s %s:%d", lb.Name, listener.Protocol, listener.Port))
+			}
+		}
+	}
+
+	sort.SliceStable(certificates, func(i, j int) bool {
+		a, b := certificates[i].NotAfter, certificates[j].NotAfter
+		if a.IsZero() || b.IsZero() {
+			return !a.IsZero()
+		}
+		return a.Before(b)
+	})
+	return certificates
+}
+
+// GetExpiringCertificates returns the certificates in use that expire
+// within CertificateWarningDays, soonest first
+func GetExpiringCertificates(summaries []LoadBalancerSummary) []CertificateUse {
+	var expiring []CertificateUse
+	for _, certificate := range GetCertificates(summaries) {
+		if certificate.IsExpiring() {
+			expiring = append(expiring, certificate)
+		}
+	}
+	return expiring
+}
+
+// servesCertificates reports whether listeners of the protocol terminate TLS
+func servesCertificates(protocol string) bool {
+	return protocol == "HTTPS" || protocol == "TLS"
+}
+
+// isACMCertificate reports whether the ARN is of an ACM certificate rather
+// than an IAM server certificate, which ACM cannot describe
+func isACMCertificate(arn string) bool {
+	return strings.HasPrefix(arn, "arn:") && strings.Contains(arn, ":acm:")
+}
+
+// loadCertificates fills in all the certificates of the HTTPS and TLS
+// listeners, including those added for SNI, along with their domain and
+// expiry. Listeners whose certificates fail to be listed keep their default
+// certificate and the errors are returned.
+func (c *Client) loadCertificates(ctx context.Context, lbName string, listeners []ListenerSummary) []error {
+	var errs []error
+	for i := range listeners {
+		if !servesCertificates(listeners[i].Protocol) {
+			continue
+		}
+
+		certificates, err := c.getListenerCertificates(ctx, listeners[i].ARN)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to describe certificates of listener %s:%d on LB %s: %w",
+				listeners[i].Protocol, listeners[i].Port, lbName, err))
+		} else {
+			listeners[i].Certificates = certificates
+		}
+
+		for j, certificate := range listeners[i].Certificates {
+			detail, err := c.describeCertificate(ctx, certificate.ARN)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			listeners[i].Certificates[j].Domain = detail.domain
+			listeners[i].Certificates[j].NotAfter = detail.notAfter
+			listeners[i].Certificates[j].Status = detail.status
+			listeners[i].Certificates[j].Type = detail.kind
+			listeners[i].Certificates[j].RenewalEligibility = detail.renewalEligibility
+			listeners[i].Certificates[j].ValidationStatus = detail.validationStatus
+		}
+	}
+	return errs
+}
+
+// getListenerCertificates returns the certificates of a listener, the
+// default one first, following the pagination markers
+func (c *Client) getListenerCertificates(ctx context.Context, listenerARN string) ([]CertificateSummary, error) {
+	var certificates []CertificateSummary
+	var marker *string
+
+	for {
+		var result *elasticloadbalancingv2.DescribeListenerCertificatesOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.elbv2Client.DescribeListenerCertificates(ctx, &elasticloadbalancingv2.DescribeListenerCertificatesInput{
+				ListenerArn: aws.String(listenerARN),
+				Marker:      marker,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, certificate := range result.Certificates {
+			summary := CertificateSummary{
+				ARN:       aws.ToString(certificate.CertificateArn),
+				IsDefault: aws.ToBool(certificate.IsDefault),
+			}
+			if summary.IsDefault {
+				certificates = append([]CertificateSummary{summary}, certificates...)
+			} else {
+				certificates = append(certificates, summary)
+			}
+		}
+
+		marker = result.NextMarker
+		if marker == nil {
+			break
+		}
+	}
+
+	return certificates, nil
+}
+
+// describeCertificate returns the domain and expiry of an ACM certificate,
+// described once per client as load balancers often share certificates.
+// Certificates ACM cannot describe are returned empty.
+func (c *Client) describeCertificate(ctx context.Context, arn string) (certificateDetail, error) {
+	if c.acmClient == nil || !isACMCertificate(arn) {
+		return certificateDetail{}, nil
+	}
+
+	c.certificatesMu.Lock()
+	detail, ok := c.certificates[arn]
+	c.certificatesMu.Unlock()
+	if ok {
+		return detail, nil
+	}
+
+	var result *acm.DescribeCertificateOutput
+	err := c.pool.Do(ctx, func() (err error) {
+		result, err = c.acmClient.DescribeCertificate(ctx, &acm.DescribeCertificateInput{
+			CertificateArn: aws.String(arn),
+		})
+		return err
+	})
+	if err != nil {
+		return certificateDetail{}, fmt.Errorf("failed to describe certificate %s: %w", arn, err)
+	}
+
+	if certificate := result.Certificate; certificate != nil {
+		detail = certificateDetail{
+			domain:             aws.ToString(certificate.DomainName),
+			notAfter:           aws.ToTime(certificate.NotAfter),
+			status:             string(certificate.Status),
+			kind:               string(certificate.Type),
+			renewalEligibility: string(certificate.RenewalEligibility),
+			validationStatus:   validationStatus(certificate),
+		}
+	}
+
+	c.certificatesMu.Lock()
+	c.certificates[arn] = detail
+	c.certificatesMu.Unlock()
+	return detail, nil
+}
+
+// validationStatus returns the worst validation status of the domains of
+// the certificate, those of its renewal while one is in progress, or ""
+// when its domains are not validated, e.g. for imported certificates
+func validationStatus(certificate *acmtypes.CertificateDetail) string {
+	validations := certificate.DomainValidationOptions
+	if certificate.RenewalSummary != nil {
+		validations = certificate.RenewalSummary.DomainValidationOptions
+	}
+
+	// Ordered from best to worst
+	statuses := []acmtypes.DomainStatus{acmtypes.DomainStatusSuccess, acmtypes.DomainStatusPendingValidation, acmtypes.DomainStatusFailed}
+	worst := -1
+	for _, validation := range validations {
+		for i, status := range statuses {
+			if validation.ValidationStatus == status && i > worst {
+				worst = i
+			}
+		}
+	}
+	if worst < 0 {
+		return ""
+	}
+	return string(statuses[worst])
+}
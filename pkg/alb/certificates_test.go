@@ -0,0 +1,209 @@
+package alb
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	acmtypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+)
+
+// Mock ACM client
+type mockACMClient struct {
+	describeCertificateFunc func(ctx context.Context, params *acm.DescribeCertificateInput, optFns ...func(*acm.Options)) (*acm.DescribeCertificateOutput, error)
+}
+
+func (m *mockACMClient) DescribeCertificate(ctx context.Context, params *acm.DescribeCertificateInput, optFns ...func(*acm.Options)) (*acm.DescribeCertificateOutput, error) {
+	return m.describeCertificateFunc(ctx, params, optFns...)
+}
+
+func TestGetLoadBalancersCertificates(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	const (
+		wwwARN     = "arn:aws:acm:us-east-1:123456789012:certificate/www"
+		shopARN    = "arn:aws:acm:us-east-1:123456789012:certificate/shop"
+		revokedARN = "arn:aws:acm:us-east-1:123456789012:certificate/revoked"
+		iamARN     = "arn:aws:iam::123456789012:server-certificate/legacy"
+	)
+
+	mockClient := &mockELBV2Client{
+		describeLoadBalancersFunc: func(ctx context.Context, params *elasticloadbalancingv2.DescribeLoadBalancersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error) {
+			return &elasticloadbalancingv2.DescribeLoadBalancersOutput{
+				LoadBalancers: []types.LoadBalancer{{
+					LoadBalancerArn:  aws.String("arn:lb"),
+					LoadBalancerName: aws.String("web"),
+					DNSName:          aws.String("web.elb.amazonaws.com"),
+					Type:             types.LoadBalancerTypeEnumApplication,
+				}},
+			}, nil
+		},
+		describeListenersFunc: func(ctx context.Context, params *elasticloadbalancingv2.DescribeListenersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeListenersOutput, error) {
+			return &elasticloadbalancingv2.DescribeListenersOutput{
+				Listeners: []types.Listener{
+					{ListenerArn: aws.String("arn:listener/443"), Protocol: types.ProtocolEnumHttps, Port: aws.Int32(443), Certificates: []types.Certificate{{CertificateArn: aws.String(wwwARN)}}},
+					{ListenerArn: aws.String("arn:listener/8443"), Protocol: types.ProtocolEnumHttps, Port: aws.Int32(8443), Certificates: []types.Certificate{{CertificateArn: aws.String(wwwARN)}}},
+					{ListenerArn: aws.String("arn:listener/80"), Protocol: types.ProtocolEnumHttp, Port: aws.Int32(80)},
+				},
+			}, nil
+		},
+		describeCertificatesFunc: func(ctx context.Context, params *elasticloadbalancingv2.DescribeListenerCertificatesInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeListenerCertificatesOutput, error) {
+			switch *params.ListenerArn {
+			case "arn:listener/443":
+				// The default certificate is not necessarily listed first
+				if params.Marker == nil {
+					return &elasticloadbalancingv2.DescribeListenerCertificatesOutput{
+						Certificates: []types.Certificate{{CertificateArn: aws.String(shopARN), IsDefault: aws.Bool(false)}},
+						NextMarker:   aws.String("page-2"),
+					}, nil
+				}
+				return &elasticloadbalancingv2.DescribeListenerCertificatesOutput{
+					Certificates: []types.Certificate{
+						{CertificateArn: aws.String(wwwARN), IsDefault: aws.Bool(true)},
+						{CertificateArn: aws.String(iamARN), IsDefault: aws.Bool(false)},
+						{CertificateArn: aws.String(revokedARN), IsDefault: aws.Bool(false)},
+					},
+				}, nil
+			case "arn:listener/8443":
+				return nil, errors.New("throttled")
+			}
+			t.Errorf("Unexpected certificates call for %s", *params.ListenerArn)
+			return &elasticloadbalancingv2.DescribeListenerCertificatesOutput{}, nil
+		},
+		describeTargetGroupsFunc: func(ctx context.Context, params *elasticloadbalancingv2.DescribeTargetGroupsInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTargetGroupsOutput, error) {
+			return &elasticloadbalancingv2.DescribeTargetGroupsOutput{}, nil
+		},
+	}
+
+	described := make(map[string]int)
+	acmClient := &mockACMClient{
+		describeCertificateFunc: func(ctx context.Context, params *acm.DescribeCertificateInput, optFns ...func(*acm.Options)) (*acm.DescribeCertificateOutput, error) {
+			described[*params.CertificateArn]++
+			detail := map[string]*acmtypes.CertificateDetail{
+				wwwARN:  {DomainName: aws.String("www.example.com"), NotAfter: aws.Time(now.Add(200 * 24 * time.Hour)), Status: acmtypes.CertificateStatusIssued},
+				shopARN: {DomainName: aws.String("shop.example.com"), NotAfter: aws.Time(now.Add(12 * 24 * time.Hour)), Status: acmtypes.CertificateStatusIssued},
+			}[*params.CertificateArn]
+			if detail == nil {
+				return nil, errors.New("access denied")
+			}
+			return &acm.DescribeCertificateOutput{Certificate: detail}, nil
+		},
+	}
+
+	summaries, errs := NewClient(mockClient, acmClient, nil).GetLoadBalancers(context.Background())
+	if len(errs) != 2 || !strings.Contains(errs[0].Error()+errs[1].Error(), "HTTPS:8443") || !strings.Contains(errs[0].Error()+errs[1].Error(), revokedARN) {
+		t.Errorf("Expected the errors of the 8443 listener and the revoked certificate, got %v", errs)
+	}
+	if described[wwwARN] != 1 || described[iamARN] != 0 {
+		t.Errorf("Expected each ACM certificate to be described once, got %v", described)
+	}
+
+	listeners := summaries[0].Listeners
+	certificates := listeners[0].Certificates
+	if len(certificates) != 4 || certificates[0].ARN != wwwARN || !certificates[0].IsDefault || certificates[1].ARN != shopARN {
+		t.Fatalf("Expected the default certificate first and the SNI certificates of both pages, got %+v", certificates)
+	}
+	if certificates[0].IsExpiring() || !certificates[1].IsExpiring() || certificates[2].Name() != "legacy" {
+		t.Errorf("Expected only the shop certificate to be expiring, got %+v", certificates)
+	}
+	// The listener whose certificates failed to be listed keeps its default certificate
+	if len(listeners[1].Certificates) != 1 || listeners[1].Certificates[0].Domain != "www.example.com" {
+		t.Errorf("Expected the default certificate of the 8443 listener, got %+v", listeners[1].Certificates)
+	}
+	if len(listeners[2].Certificates) != 0 {
+		t.Errorf("Expected no certificates on the HTTP listener, got %+v", listeners[2].Certificates)
+	}
+
+	output := FormatLoadBalancers(summaries, -1)
+	for _, expected := range []string{
+		"    HTTPS:443\n      🔒 www.example.com, expires 2024-09-17\n",
+		"⚠️ 🔒 shop.example.com (SNI), expires 2024-03-13, in ~12 days",
+		"🔒 legacy (SNI)\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
+
+func TestGetCertificates(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	www := CertificateSummary{ARN: "arn:www", IsDefault: true, Domain: "www.example.com", NotAfter: now.Add(200 * 24 * time.Hour),
+		Type: "AMAZON_ISSUED", RenewalEligibility: "ELIGIBLE", ValidationStatus: "SUCCESS"}
+	shop := CertificateSummary{ARN: "arn:shop", Domain: "shop.example.com", NotAfter: now.Add(12 * 24 * time.Hour),
+		Type: "IMPORTED", RenewalEligibility: "INELIGIBLE"}
+	legacy := CertificateSummary{ARN: "arn:aws:iam::123456789012:server-certificate/legacy"}
+	summaries := []LoadBalancerSummary{
+		{Name: "web", ListenersLoaded: true, Listeners: []ListenerSummary{
+			{Protocol: "HTTPS", Port: 443, Certificates: []CertificateSummary{www, shop, legacy}},
+		}},
+		{Name: "api", ListenersLoaded: true, Listeners: []ListenerSummary{
+			{Protocol: "HTTPS", Port: 8443, Certificates: []CertificateSummary{www}},
+		}},
+	}
+
+	certificates := GetCertificates(summaries)
+	if len(certificates) != 3 || certificates[0].ARN != "arn:shop" || certificates[1].ARN != "arn:www" || certificates[2].Name() != "legacy" {
+		t.Fatalf("Expected each certificate once, soonest to expire first, got %+v", certificates)
+	}
+	if listeners := strings.Join(certificates[1].Listeners, ", "); listeners != "web HTTPS:443, api HTTPS:8443" {
+		t.Errorf("Expected both listeners serving the www certificate, got %s", listeners)
+	}
+	if expiring := GetExpiringCertificates(summaries); len(expiring) != 1 || expiring[0].RenewsAutomatically() {
+		t.Errorf("Expected only the imported certificate to be expiring, got %+v", expiring)
+	}
+
+	output := FormatLoadBalancers(summaries, -1)
+	for _, expected := range []string{
+		"🔒 CERTIFICATES (3)\n",
+		"  ⚠️ shop.example.com: expires in ~12 days on 2024-03-13, imported, not renewed automatically (web HTTPS:443)\n",
+		"  www.example.com: expires in ~200 days on 2024-09-17, renews automatically, validation success (web HTTPS:443, api HTTPS:8443)\n",
+		"  legacy: expiry unknown (web HTTPS:443)\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+	if summary := GetLoadBalancersSummary(summaries); !strings.HasSuffix(summary, ", ⚠️ 1 certificates expiring within 30 days") {
+		t.Errorf("Expected the summary to flag the expiring certificate, got %q", summary)
+	}
+}
+
+func TestValidationStatus(t *testing.T) {
+	validated := func(statuses ...acmtypes.DomainStatus) []acmtypes.DomainValidation {
+		var validations []acmtypes.DomainValidation
+		for _, status := range statuses {
+			validations = append(validations, acmtypes.DomainValidation{ValidationStatus: status})
+		}
+		return validations
+	}
+
+	for _, tc := range []struct {
+		name        string
+		certificate acmtypes.CertificateDetail
+		expected    string
+	}{
+		{"imported", acmtypes.CertificateDetail{}, ""},
+		{"issued", acmtypes.CertificateDetail{DomainValidationOptions: validated(acmtypes.DomainStatusSuccess, acmtypes.DomainStatusSuccess)}, "SUCCESS"},
+		{"worst domain", acmtypes.CertificateDetail{DomainValidationOptions: validated(acmtypes.DomainStatusSuccess, acmtypes.DomainStatusPendingValidation)}, "PENDING_VALIDATION"},
+		{"renewing", acmtypes.CertificateDetail{
+			DomainValidationOptions: validated(acmtypes.DomainStatusSuccess),
+			RenewalSummary:          &acmtypes.RenewalSummary{DomainValidationOptions: validated(acmtypes.DomainStatusFailed)},
+		}, "FAILED"},
+	} {
+		if status := validationStatus(&tc.certificate); status != tc.expected {
+			t.Errorf("%s: expected %q, got %q", tc.name, tc.expected, status)
+		}
+	}
+}
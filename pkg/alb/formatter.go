@@ -3,20 +3,45 @@ package alb
 import (
 	"fmt"
 	"strings"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
 )
 
-// FormatLoadBalancers formats load balancer summaries for terminal display
-func FormatLoadBalancers(summaries []LoadBalancerSummary) string {
+// FormatLoadBalancers formats load balancer summaries for terminal display,
+// marking the target at index selected of Targets (-1 for none)
+func FormatLoadBalancers(summaries []LoadBalancerSummary, selected int) string {
 	if len(summaries) == 0 {
 		return "No load balancers found"
 	}
 
 	var output strings.Builder
 	output.WriteString("LOAD BALANCERS\n")
-	output.WriteString("==============\n\n")
+	output.WriteString(common.Rule("LOAD BALANCERS", "=") + "\n\n")
+
+	if orphans := FindOrphans(summaries); len(orphans) > 0 {
+		output.WriteString(fmt.Sprintf("%s POSSIBLE LEFTOVERS (%d)\n", common.Symbol("⚠️"), len(orphans)))
+		for _, orphan := range orphans {
+			output.WriteString(fmt.Sprintf("  %s: %s\n", orphan.LoadBalancer, orphan.Description))
+		}
+		output.WriteString("\n")
+	}
+
+	if certificates := GetCertificates(summaries); len(certificates) > 0 {
+		output.WriteString(fmt.Sprintf("🔒 CERTIFICATES (%d)\n", len(certificates)))
+		for _, certificate := range certificates {
+			output.WriteString("  " + formatCertificateUse(certificate) + "\n")
+		}
+		output.WriteString("\n")
+	}
 
+	index := 0
 	for _, lb := range summaries {
 		output.WriteString(fmt.Sprintf("🔄 %s (%s)\n", lb.Name, lb.DNSName))
+		output.WriteString("  Type: " + lb.Describe() + "\n")
+
+		if lb.ListenersLoaded {
+			output.WriteString(formatListeners(lb))
+		}
 
 		if len(lb.TargetGroups) == 0 {
 			output.WriteString("  No target groups\n\n")
@@ -32,8 +57,19 @@ func FormatLoadBalancers(summaries []LoadBalancerSummary) string {
 			}
 
 			for _, target := range tg.Targets {
-				statusSymbol := getStatusSymbol(target.Status)
-				output.WriteString(fmt.Sprintf("    %s %s:%d - %s",
+				marker := "  "
+				if index == selected {
+					marker = "> "
+				}
+				index++
+
+				statusSymbol := common.Symbol(getStatusSymbol(target.Status))
+				if target.Healthy() {
+					// Targets without health checks receive traffic like healthy ones
+					statusSymbol = common.Symbol("✅")
+				}
+				output.WriteString(fmt.Sprintf("%s  %s %s:%d - %s",
+					marker,
 					statusSymbol,
 					target.ID,
 					target.Port,
@@ -68,17 +104,219 @@ func GetLoadBalancersSummary(summaries []LoadBalancerSummary) string {
 			totalTargets += len(tg.Targets)
 
 			for _, target := range tg.Targets {
-				if target.Status == "healthy" {
+				if target.Healthy() {
 					healthyTargets++
 				}
 			}
 		}
 	}
 
-	return fmt.Sprintf("%d LBs, %d/%d healthy targets",
+	summary := fmt.Sprintf("%d LBs%s, %d/%d healthy targets",
 		len(summaries),
+		formatTypeCounts(summaries),
 		healthyTargets,
 		totalTargets)
+
+	if orphans := len(FindOrphans(summaries)); orphans > 0 {
+		summary += fmt.Sprintf(", ⚠️ %d possible leftovers", orphans)
+	}
+	if expiring := len(GetExpiringCertificates(summaries)); expiring > 0 {
+		summary += fmt.Sprintf(", ⚠️ %d certificates expiring within %d days", expiring, CertificateWarningDays)
+	}
+
+	return summary
+}
+
+// formatTypeCounts counts the load balancers of each type, e.g.
+// " (3 application, 1 network)", or returns "" when they are all application
+// load balancers
+func formatTypeCounts(summaries []LoadBalancerSummary) string {
+	counts := make(map[string]int)
+	for _, lb := range summaries {
+		if lb.HasRules() {
+			counts[TypeApplication]++
+		} else {
+			counts[lb.Type]++
+		}
+	}
+	if counts[TypeApplication] == len(summaries) {
+		return ""
+	}
+
+	var parts []string
+	for _, kind := range []string{TypeApplication, TypeNetwork, TypeGateway} {
+		if counts[kind] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[kind], kind))
+		}
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+// FormatRouteSimulation shows which rule and target group each listener
+// would route the request to
+func FormatRouteSimulation(summaries []LoadBalancerSummary, request Request) string {
+	title := "ROUTE SIMULATION: " + request.String()
+
+	var output strings.Builder
+	output.WriteString(title + "\n")
+	output.WriteString(common.Rule(title, "-") + "\n")
+
+	matches := SimulateRoute(summaries, request)
+	if len(matches) == 0 {
+		output.WriteString("No listeners to route through\n")
+		return output.String()
+	}
+
+	targetGroups := make(map[string]string)
+	for _, lb := range summaries {
+		for _, tg := range lb.TargetGroups {
+			targetGroups[tg.ARN] = tg.Name
+		}
+	}
+
+	lastLB := ""
+	for _, match := range matches {
+		if match.LoadBalancer != lastLB {
+			output.WriteString(fmt.Sprintf("🔄 %s\n", match.LoadBalancer))
+			lastLB = match.LoadBalancer
+		}
+
+		listener := fmt.Sprintf("%s:%d", match.Listener.Protocol, match.Listener.Port)
+		switch {
+		case !match.Listener.RulesLoaded:
+			output.WriteString(fmt.Sprintf("  %s → rules not loaded\n", listener))
+		case !match.Matched:
+			output.WriteString(fmt.Sprintf("  %s → no rule matches\n", listener))
+		default:
+			output.WriteString(fmt.Sprintf("  %s → %s → %s\n", listener, formatRule(match.Rule), formatDestination(match.Rule, targetGroups)))
+		}
+
+		if len(match.Skipped) > 0 {
+			output.WriteString(fmt.Sprintf("    %s skipped rule %s: source-ip conditions are not simulated\n", common.Symbol("⚠️"), strings.Join(match.Skipped, ", ")))
+		}
+	}
+
+	return output.String()
+}
+
+// formatRule describes a rule by its priority and conditions
+func formatRule(rule RuleSummary) string {
+	if rule.IsDefault {
+		return "default rule"
+	}
+
+	conditions := make([]string, len(rule.Conditions))
+	for i, condition := range rule.Conditions {
+		field := condition.Field
+		if condition.HeaderName != "" {
+			field += " " + condition.HeaderName
+		}
+		conditions[i] = field + " " + strings.Join(condition.Values, " | ")
+	}
+	return fmt.Sprintf("rule %s (%s)", rule.Priority, strings.Join(conditions, " AND "))
+}
+
+// formatDestination names the target groups a rule forwards to, or its action otherwise
+func formatDestination(rule RuleSummary, targetGroups map[string]string) string {
+	if len(rule.TargetGroupARNs) == 0 {
+		return rule.Action
+	}
+
+	names := make([]string, len(rule.TargetGroupARNs))
+	for i, arn := range rule.TargetGroupARNs {
+		names[i] = arn
+		if name, ok := targetGroups[arn]; ok {
+			names[i] = name
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// formatListeners maps how each listener routes traffic: its protocol and
+// port, the certificates it serves and the target groups its rules forward
+// to, in priority order
+func formatListeners(lb LoadBalancerSummary) string {
+	if len(lb.Listeners) == 0 {
+		return "  No listeners\n"
+	}
+
+	targetGroups := make(map[string]string)
+	for _, tg := range lb.TargetGroups {
+		targetGroups[tg.ARN] = tg.Name
+	}
+
+	var output strings.Builder
+	output.WriteString("  Listeners:\n")
+	for _, listener := range lb.Listeners {
+		output.WriteString(fmt.Sprintf("    %s:%d\n", listener.Protocol, listener.Port))
+
+		for _, certificate := range listener.Certificates {
+			output.WriteString("      " + formatCertificate(certificate) + "\n")
+		}
+
+		if !listener.RulesLoaded {
+			output.WriteString("      rules not loaded\n")
+			continue
+		}
+		for _, rule := range listener.Rules {
+			output.WriteString(fmt.Sprintf("      %s → %s\n", formatRule(rule), formatDestination(rule, targetGroups)))
+		}
+	}
+	return output.String()
+}
+
+// formatCertificate describes a listener certificate and when it expires,
+// warning when it expires within CertificateWarningDays
+func formatCertificate(certificate CertificateSummary) string {
+	line := "🔒 " + certificate.Name()
+	if !certificate.IsDefault {
+		line += " (SNI)"
+	}
+	if certificate.NotAfter.IsZero() {
+		return line
+	}
+
+	line += ", expires " + certificate.NotAfter.Format("2006-01-02")
+	if !certificate.IsExpiring() {
+		return line
+	}
+	if days := certificate.DaysUntilExpiry(); days < 0 {
+		return fmt.Sprintf("%s %s, expired %.0f days ago", common.Symbol("⚠️"), line, -days)
+	}
+	return fmt.Sprintf("%s %s, in ~%.0f days", common.Symbol("⚠️"), line, certificate.DaysUntilExpiry())
+}
+
+// formatCertificateUse describes a certificate in use: how long until it
+// expires, whether ACM renews it, the validation of its domains and the
+// listeners serving it
+func formatCertificateUse(certificate CertificateUse) string {
+	line := certificate.Name() + ": "
+	if certificate.NotAfter.IsZero() {
+		line += "expiry unknown"
+	} else if days := certificate.DaysUntilExpiry(); days < 0 {
+		line += fmt.Sprintf("expired %.0f days ago on %s", -days, certificate.NotAfter.Format("2006-01-02"))
+	} else {
+		line += fmt.Sprintf("expires in ~%.0f days on %s", days, certificate.NotAfter.Format("2006-01-02"))
+	}
+
+	if certificate.Type == "IMPORTED" {
+		line += ", imported"
+	}
+	switch {
+	case certificate.RenewsAutomatically():
+		line += ", renews automatically"
+	case certificate.RenewalEligibility != "":
+		line += ", not renewed automatically"
+	}
+	if certificate.ValidationStatus != "" {
+		line += ", validation " + strings.ToLower(strings.ReplaceAll(certificate.ValidationStatus, "_", " "))
+	}
+	line += " (" + strings.Join(certificate.Listeners, ", ") + ")"
+
+	if certificate.IsExpiring() {
+		return common.Symbol("⚠️") + " " + line
+	}
+	return line
 }
 
 // getStatusSymbol returns an appropriate symbol for a health status
@@ -86,10 +324,12 @@ func getStatusSymbol(status string) string {
 	switch status {
 	case "healthy":
 		return "✅"
-	case "unhealthy":
+	case "unhealthy", "unhealthy.draining":
 		return "❌"
 	case "draining":
 		return "🔄"
+	case "unused":
+		return "⏸️"
 	case "unavailable":
 		return "⚠️"
 	case "initial":
@@ -7,7 +7,7 @@ import (
 
 func TestFormatLoadBalancers(t *testing.T) {
 	// Test with empty summaries
-	emptyResult := FormatLoadBalancers([]LoadBalancerSummary{})
+	emptyResult := FormatLoadBalancers([]LoadBalancerSummary{}, -1)
 	if emptyResult != "No load balancers found" {
 		t.Errorf("Expected 'No load balancers found', got '%s'", emptyResult)
 	}
@@ -15,8 +15,10 @@ func TestFormatLoadBalancers(t *testing.T) {
 	// Test with actual summaries
 	summaries := []LoadBalancerSummary{
 		{
-			Name:    "test-lb",
-			DNSName: "test-lb.example.com",
+			Name:            "test-lb",
+			DNSName:         "test-lb.example.com",
+			ListenersLoaded: true,
+			Listeners:       []ListenerSummary{{Protocol: "HTTPS", Port: 443}},
 			TargetGroups: []TargetGroupSummary{
 				{
 					Name: "test-tg",
@@ -39,15 +41,21 @@ func TestFormatLoadBalancers(t *testing.T) {
 		},
 	}
 
-	result := FormatLoadBalancers(summaries)
+	summaries = append(summaries, LoadBalancerSummary{Name: "idle-lb", DNSName: "idle-lb.example.com", ListenersLoaded: true})
+
+	result := FormatLoadBalancers(summaries, 1)
 
 	// Validate the output contains expected elements
 	expectedElements := []string{
 		"LOAD BALANCERS",
+		"POSSIBLE LEFTOVERS (1)",
+		"idle-lb: load balancer has no listeners",
 		"test-lb (test-lb.example.com)",
+		"Listeners:\n    HTTPS:443\n      rules not loaded\n",
 		"test-tg",
 		"✅ i-1234567890abcdef0:80 - healthy",
-		"❌ i-0987654321fedcba0:80 - unhealthy (Connection refused)",
+		"\n    ✅ i-1234567890abcdef0:80 - healthy",
+		"\n>   ❌ i-0987654321fedcba0:80 - unhealthy (Connection refused)",
 	}
 
 	for _, expected := range expectedElements {
@@ -0,0 +1,55 @@
+package alb
+
+import "fmt"
+
+// Types of load balancers, as reported by Elastic Load Balancing
+const (
+	TypeApplication = "application"
+	TypeNetwork     = "network"
+	TypeGateway     = "gateway"
+)
+
+// healthCheckDisabledReason is the reason of the targets of target groups
+// without health checks, which network and gateway load balancers allow
+const healthCheckDisabledReason = "Target.HealthCheckDisabled"
+
+// geneveListener is the protocol and port of gateway load balancer listeners,
+// which are not configurable and so not described
+const (
+	geneveProtocol = "GENEVE"
+	genevePort     = 6081
+)
+
+// Describe returns the type and scheme of the load balancer, e.g.
+// "network, internal"
+func (lb LoadBalancerSummary) Describe() string {
+	kind := lb.Type
+	if kind == "" {
+		kind = TypeApplication
+	}
+	if lb.Scheme == "" {
+		return kind
+	}
+	return fmt.Sprintf("%s, %s", kind, lb.Scheme)
+}
+
+// HasRules reports whether the listeners of the load balancer route by
+// rules. Only application load balancers have rules; the listeners of
+// network and gateway load balancers forward all traffic with their default
+// action.
+func (lb LoadBalancerSummary) HasRules() bool {
+	return lb.Type == "" || lb.Type == TypeApplication
+}
+
+// Healthy reports whether the target receives traffic as healthy: it passes
+// its health checks, or its target group does not check its health
+func (t TargetSummary) Healthy() bool {
+	return t.Status == "healthy" || t.Status == "unavailable" && t.Reason == healthCheckDisabledReason
+}
+
+// Unhealthy reports whether the target fails its health checks, including
+// the targets of network load balancers that keep their connections while
+// draining because they are unhealthy
+func (t TargetSummary) Unhealthy() bool {
+	return t.Status == "unhealthy" || t.Status == "unhealthy.draining"
+}
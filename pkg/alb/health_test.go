@@ -0,0 +1,112 @@
+package alb
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+)
+
+func TestGetLoadBalancersNetworkAndGateway(t *testing.T) {
+	rulesDescribed := false
+	mockClient := &mockELBV2Client{
+		describeLoadBalancersFunc: func(ctx context.Context, params *elasticloadbalancingv2.DescribeLoadBalancersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error) {
+			return &elasticloadbalancingv2.DescribeLoadBalancersOutput{
+				LoadBalancers: []types.LoadBalancer{
+					{
+						LoadBalancerArn:  aws.String("arn:nlb"),
+						LoadBalancerName: aws.String("nlb"),
+						DNSName:          aws.String("nlb.elb.amazonaws.com"),
+						Type:             types.LoadBalancerTypeEnumNetwork,
+						Scheme:           types.LoadBalancerSchemeEnumInternetFacing,
+					},
+					{
+						LoadBalancerArn:  aws.String("arn:gwlb"),
+						LoadBalancerName: aws.String("gwlb"),
+						DNSName:          aws.String("gwlb.elb.amazonaws.com"),
+						Type:             types.LoadBalancerTypeEnumGateway,
+						Scheme:           types.LoadBalancerSchemeEnumInternal,
+					},
+				},
+			}, nil
+		},
+		describeListenersFunc: func(ctx context.Context, params *elasticloadbalancingv2.DescribeListenersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeListenersOutput, error) {
+			listener := types.Listener{
+				ListenerArn:    aws.String(*params.LoadBalancerArn + "/listener"),
+				DefaultActions: []types.Action{{Type: types.ActionTypeEnumForward, TargetGroupArn: aws.String(*params.LoadBalancerArn + "/tg")}},
+			}
+			if *params.LoadBalancerArn == "arn:nlb" {
+				listener.Protocol, listener.Port = types.ProtocolEnumTls, aws.Int32(443)
+			}
+			return &elasticloadbalancingv2.DescribeListenersOutput{Listeners: []types.Listener{listener}}, nil
+		},
+		describeRulesFunc: func(ctx context.Context, params *elasticloadbalancingv2.DescribeRulesInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeRulesOutput, error) {
+			rulesDescribed = true
+			return &elasticloadbalancingv2.DescribeRulesOutput{}, nil
+		},
+		describeTargetGroupsFunc: func(ctx context.Context, params *elasticloadbalancingv2.DescribeTargetGroupsInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTargetGroupsOutput, error) {
+			return &elasticloadbalancingv2.DescribeTargetGroupsOutput{
+				TargetGroups: []types.TargetGroup{{TargetGroupName: aws.String("tg"), TargetGroupArn: aws.String(*params.LoadBalancerArn + "/tg")}},
+			}, nil
+		},
+		describeTargetHealthFunc: func(ctx context.Context, params *elasticloadbalancingv2.DescribeTargetHealthInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTargetHealthOutput, error) {
+			return &elasticloadbalancingv2.DescribeTargetHealthOutput{
+				TargetHealthDescriptions: []types.TargetHealthDescription{
+					{
+						Target:       &types.TargetDescription{Id: aws.String("10.0.1.10"), Port: aws.Int32(443)},
+						TargetHealth: &types.TargetHealth{State: types.TargetHealthStateEnumUnavailable, Reason: types.TargetHealthReasonEnumHealthCheckDisabled},
+					},
+					{
+						Target:       &types.TargetDescription{Id: aws.String("10.0.2.10"), Port: aws.Int32(443)},
+						TargetHealth: &types.TargetHealth{State: types.TargetHealthStateEnumUnhealthyDraining, Reason: types.TargetHealthReasonEnumFailedHealthChecks},
+					},
+				},
+			}, nil
+		},
+	}
+
+	summaries, errs := NewClient(mockClient, nil, nil).GetLoadBalancers(context.Background())
+	if len(errs) > 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+	if rulesDescribed {
+		t.Errorf("Expected the rules of network and gateway load balancers not to be described")
+	}
+
+	byName := make(map[string]LoadBalancerSummary)
+	for _, lb := range summaries {
+		byName[lb.Name] = lb
+	}
+	nlb, gwlb := byName["nlb"], byName["gwlb"]
+	if nlb.Describe() != "network, internet-facing" || gwlb.Describe() != "gateway, internal" {
+		t.Errorf("Unexpected types %q and %q", nlb.Describe(), gwlb.Describe())
+	}
+	if listener := nlb.Listeners[0]; listener.Protocol != "TLS" || listener.Port != 443 || !listener.RulesLoaded ||
+		len(listener.Rules) != 1 || !listener.Rules[0].IsDefault || listener.Rules[0].TargetGroupARNs[0] != "arn:nlb/tg" {
+		t.Errorf("Expected the TLS listener to forward all traffic with its default rule, got %+v", listener)
+	}
+	if listener := gwlb.Listeners[0]; listener.Protocol != "GENEVE" || listener.Port != 6081 {
+		t.Errorf("Expected the gateway listener on GENEVE:6081, got %+v", listener)
+	}
+
+	targets := nlb.TargetGroups[0].Targets
+	if !targets[0].Healthy() || targets[0].Unhealthy() {
+		t.Errorf("Expected the target without health checks to count as healthy, got %+v", targets[0])
+	}
+	if targets[1].Healthy() || !targets[1].Unhealthy() {
+		t.Errorf("Expected the unhealthy draining target to count as unhealthy, got %+v", targets[1])
+	}
+
+	if summary := GetLoadBalancersSummary(summaries); summary != "2 LBs (1 network, 1 gateway), 2/4 healthy targets" {
+		t.Errorf("Unexpected summary %q", summary)
+	}
+	output := FormatLoadBalancers(summaries, -1)
+	for _, expected := range []string{"Type: network, internet-facing", "    TLS:443\n      default rule → tg\n", "    GENEVE:6081\n", "❌ 10.0.2.10:443 - unhealthy.draining"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package alb
+
+import "fmt"
+
+// Orphan describes a load balancing resource that serves no traffic,
+// typically left over after a service was decommissioned
+type Orphan struct {
+	LoadBalancer string
+	Description  string
+}
+
+// FindOrphans returns load balancers without listeners, target groups without
+// registered targets and listeners forwarding to such empty target groups
+func FindOrphans(summaries []LoadBalancerSummary) []Orphan {
+	var orphans []Orphan
+	for _, lb := range summaries {
+		if lb.ListenersLoaded && len(lb.Listeners) == 0 {
+			orphans = append(orphans, Orphan{LoadBalancer: lb.Name, Description: "load balancer has no listeners"})
+		}
+
+		empty := make(map[string]string)
+		for _, tg := range lb.TargetGroups {
+			if len(tg.Targets) == 0 {
+				empty[tg.ARN] = tg.Name
+				orphans = append(orphans, Orphan{LoadBalancer: lb.Name, Description: fmt.Sprintf("target group %s has no registered targets", tg.Name)})
+			}
+		}
+
+		for _, listener := range lb.Listeners {
+			for _, arn := range listener.TargetGroupARNs {
+				if name, ok := empty[arn]; ok {
+					orphans = append(orphans, Orphan{LoadBalancer: lb.Name,
+						Description: fmt.Sprintf("listener %s:%d forwards to empty target group %s", listener.Protocol, listener.Port, name)})
+				}
+			}
+		}
+	}
+	return orphans
+}
@@ -0,0 +1,40 @@
+package alb
+
+import (
+	"testing"
+)
+
+func TestFindOrphans(t *testing.T) {
+	summaries := []LoadBalancerSummary{
+		{
+			Name:            "in-use",
+			ListenersLoaded: true,
+			Listeners:       []ListenerSummary{{Protocol: "HTTP", Port: 80, TargetGroupARNs: []string{"arn:web"}}},
+			TargetGroups:    []TargetGroupSummary{{Name: "web", ARN: "arn:web", Targets: []TargetSummary{{ID: "i-1", Status: "healthy"}}}},
+		},
+		{
+			Name:            "decommissioned",
+			ListenersLoaded: true,
+			Listeners:       []ListenerSummary{{Protocol: "HTTPS", Port: 443, TargetGroupARNs: []string{"arn:old"}}},
+			TargetGroups:    []TargetGroupSummary{{Name: "old", ARN: "arn:old"}},
+		},
+		{Name: "idle", ListenersLoaded: true},
+		{Name: "unknown"},
+	}
+
+	orphans := FindOrphans(summaries)
+
+	expected := []Orphan{
+		{LoadBalancer: "decommissioned", Description: "target group old has no registered targets"},
+		{LoadBalancer: "decommissioned", Description: "listener HTTPS:443 forwards to empty target group old"},
+		{LoadBalancer: "idle", Description: "load balancer has no listeners"},
+	}
+	if len(orphans) != len(expected) {
+		t.Fatalf("Expected %d orphans, got %+v", len(expected), orphans)
+	}
+	for i := range expected {
+		if orphans[i] != expected[i] {
+			t.Errorf("Expected %+v, got %+v", expected[i], orphans[i])
+		}
+	}
+}
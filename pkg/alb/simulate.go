@@ -0,0 +1,222 @@
+package alb
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Request is an HTTP request whose routing is simulated against listener rules
+type Request struct {
+	Method  string
+	Host    string
+	Path    string
+	Query   url.Values
+	Headers map[string]string // Keyed by lower-case header name
+}
+
+// RouteMatch is the rule of a listener a request is routed by
+type RouteMatch struct {
+	LoadBalancer string
+	Listener     ListenerSummary
+	Rule         RuleSummary
+	Matched      bool     // False when no rule matched, e.g. because the rules failed to load
+	Skipped      []string // Priorities of rules with source-ip conditions, which cannot be simulated
+}
+
+// ParseRequest parses a request written as "[METHOD] host[/path][?query] [Header:value ...]",
+// e.g. "api.example.com/orders?id=7 X-Canary:true". The method defaults to GET
+// and the path to "/".
+func ParseRequest(input string) (Request, error) {
+	fields := strings.Fields(input)
+	if len(fields) == 0 {
+		return Request{}, errors.New("enter a host and path, e.g. api.example.com/orders")
+	}
+
+	request := Request{Method: "GET", Path: "/", Query: url.Values{}, Headers: map[string]string{}}
+	if len(fields) > 1 && isMethod(fields[0]) {
+		request.Method = fields[0]
+		fields = fields[1:]
+	}
+
+	target := fields[0]
+	for _, scheme := range []string{"https://", "http://"} {
+		target = strings.TrimPrefix(target, scheme)
+	}
+	if i := strings.Index(target, "?"); i >= 0 {
+		query, err := url.ParseQuery(target[i+1:])
+		if err != nil {
+			return Request{}, fmt.Errorf("invalid query string: %w", err)
+		}
+		request.Query = query
+		target = target[:i]
+	}
+	if i := strings.Index(target, "/"); i >= 0 {
+		request.Path = target[i:]
+		target = target[:i]
+	}
+	if target == "" {
+		return Request{}, errors.New("missing host")
+	}
+	request.Host = target
+
+	for _, header := range fields[1:] {
+		name, value, ok := strings.Cut(header, ":")
+		if !ok || name == "" {
+			return Request{}, fmt.Errorf("invalid header %q, expected Name:value", header)
+		}
+		request.Headers[strings.ToLower(name)] = value
+	}
+
+	return request, nil
+}
+
+// String returns the request in the form accepted by ParseRequest
+func (r Request) String() string {
+	s := r.Method + " " + r.Host + r.Path
+	if len(r.Query) > 0 {
+		s += "?" + r.Query.Encode()
+	}
+
+	names := make([]string, 0, len(r.Headers))
+	for name := range r.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s += " " + name + ":" + r.Headers[name]
+	}
+	return s
+}
+
+// SimulateRoute returns, for each listener of the load balancers, the rule
+// that would route the request. Rules are evaluated in priority order like
+// the load balancer does, so the first matching rule wins.
+func SimulateRoute(summaries []LoadBalancerSummary, request Request) []RouteMatch {
+	var matches []RouteMatch
+	for _, lb := range summaries {
+		for _, listener := range lb.Listeners {
+			match := RouteMatch{LoadBalancer: lb.Name, Listener: listener}
+
+			for _, rule := range listener.Rules {
+				matched, simulated := ruleMatches(rule, request)
+				if !simulated {
+					match.Skipped = append(match.Skipped, rule.Priority)
+					continue
+				}
+				if matched {
+					match.Rule = rule
+					match.Matched = true
+					break
+				}
+			}
+
+			matches = append(matches, match)
+		}
+	}
+	return matches
+}
+
+// ruleMatches reports whether all conditions of a rule match the request, and
+// false for simulated when a condition depends on what cannot be simulated
+func ruleMatches(rule RuleSummary, request Request) (matched, simulated bool) {
+	for _, condition := range rule.Conditions {
+		if condition.Field == "source-ip" {
+			return false, false
+		}
+		if !conditionMatches(condition, request) {
+			return false, true
+		}
+	}
+	return true, true
+}
+
+// conditionMatches reports whether any value of a condition matches the request.
+// Host, header and query string comparisons ignore case, paths and methods do not.
+func conditionMatches(condition ConditionSummary, request Request) bool {
+	for _, value := range condition.Values {
+		switch condition.Field {
+		case "host-header":
+			if wildcardMatch(strings.ToLower(value), strings.ToLower(request.Host)) {
+				return true
+			}
+		case "path-pattern":
+			if wildcardMatch(value, request.Path) {
+				return true
+			}
+		case "http-header":
+			header, ok := request.Headers[strings.ToLower(condition.HeaderName)]
+			if ok && wildcardMatch(strings.ToLower(value), strings.ToLower(header)) {
+				return true
+			}
+		case "http-request-method":
+			if value == request.Method {
+				return true
+			}
+		case "query-string":
+			if queryMatches(value, request.Query) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// queryMatches reports whether a "key=value" query-string condition value
+// matches any query parameter. An empty key matches the value of any key.
+func queryMatches(pair string, query url.Values) bool {
+	key, value, _ := strings.Cut(strings.ToLower(pair), "=")
+	for name, values := range query {
+		if key != "" && !wildcardMatch(key, strings.ToLower(name)) {
+			continue
+		}
+		for _, v := range values {
+			if wildcardMatch(value, strings.ToLower(v)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// wildcardMatch reports whether s matches pattern, where "*" matches any
+// sequence of characters and "?" exactly one, as in listener rule conditions
+func wildcardMatch(pattern, s string) bool {
+	p, str := []rune(pattern), []rune(s)
+	pi, si := 0, 0
+	star, mark := -1, 0
+
+	for si < len(str) {
+		switch {
+		case pi < len(p) && (p[pi] == '?' || p[pi] == str[si]):
+			pi++
+			si++
+		case pi < len(p) && p[pi] == '*':
+			star, mark = pi, si
+			pi++
+		case star >= 0:
+			// Let the last star absorb one more character and retry
+			mark++
+			pi, si = star+1, mark
+		default:
+			return false
+		}
+	}
+
+	for pi < len(p) && p[pi] == '*' {
+		pi++
+	}
+	return pi == len(p)
+}
+
+// isMethod reports whether a word is written like an HTTP method, e.g. GET or POST
+func isMethod(word string) bool {
+	for _, r := range word {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return word != ""
+}
@@ -0,0 +1,159 @@
+package alb
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseRequest(t *testing.T) {
+	request, err := ParseRequest("POST https://API.example.com/orders/7?v=2&debug X-Canary:true")
+	if err != nil {
+		t.Fatalf("ParseRequest() error = %v", err)
+	}
+	if request.Method != "POST" || request.Host != "API.example.com" || request.Path != "/orders/7" {
+		t.Errorf("Unexpected request %+v", request)
+	}
+	if request.Query.Get("v") != "2" || !request.Query.Has("debug") {
+		t.Errorf("Expected query v=2 and debug, got %v", request.Query)
+	}
+	if request.Headers["x-canary"] != "true" {
+		t.Errorf("Expected header x-canary:true, got %v", request.Headers)
+	}
+
+	request, err = ParseRequest("example.com")
+	if err != nil {
+		t.Fatalf("ParseRequest() error = %v", err)
+	}
+	if request.Method != "GET" || request.Path != "/" {
+		t.Errorf("Expected GET / by default, got %+v", request)
+	}
+
+	for _, input := range []string{"", "/orders", "example.com :value"} {
+		if _, err := ParseRequest(input); err == nil {
+			t.Errorf("Expected an error for '%s'", input)
+		}
+	}
+}
+
+func TestWildcardMatch(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"/api/*", "/api/orders", true},
+		{"/api/*", "/api", false},
+		{"*.example.com", "api.example.com", true},
+		{"*.example.com", "example.com", false},
+		{"/v?/users", "/v2/users", true},
+		{"/v?/users", "/v10/users", false},
+		{"/*/items/*", "/a/b/items/c", true},
+		{"*", "", true},
+		{"/exact", "/exact", true},
+		{"/exact", "/exactly", false},
+	}
+
+	for _, tt := range tests {
+		if got := wildcardMatch(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("wildcardMatch(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
+
+// routingFixture is a load balancer whose HTTPS listener routes by host,
+// path, header, method and query string
+func routingFixture() []LoadBalancerSummary {
+	return []LoadBalancerSummary{{
+		Name: "web",
+		TargetGroups: []TargetGroupSummary{
+			{Name: "web-tg", ARN: "arn:tg/web"},
+			{Name: "api-tg", ARN: "arn:tg/api"},
+			{Name: "canary-tg", ARN: "arn:tg/canary"},
+		},
+		Listeners: []ListenerSummary{
+			{
+				Protocol:    "HTTPS",
+				Port:        443,
+				RulesLoaded: true,
+				Rules: []RuleSummary{
+					{Priority: "1", Action: "forward", TargetGroupARNs: []string{"arn:tg/web"},
+						Conditions: []ConditionSummary{{Field: "source-ip", Values: []string{"10.0.0.0/8"}}}},
+					{Priority: "5", Action: "forward", TargetGroupARNs: []string{"arn:tg/canary"},
+						Conditions: []ConditionSummary{
+							{Field: "host-header", Values: []string{"api.example.com"}},
+							{Field: "http-header", HeaderName: "X-Canary", Values: []string{"TRUE"}},
+						}},
+					{Priority: "10", Action: "forward", TargetGroupARNs: []string{"arn:tg/api"},
+						Conditions: []ConditionSummary{
+							{Field: "host-header", Values: []string{"api.example.com", "*.api.example.com"}},
+							{Field: "path-pattern", Values: []string{"/orders*"}},
+						}},
+					{Priority: "20", Action: "fixed-response",
+						Conditions: []ConditionSummary{
+							{Field: "http-request-method", Values: []string{"DELETE"}},
+							{Field: "query-string", Values: []string{"=drop*"}},
+						}},
+					{Priority: "default", IsDefault: true, Action: "forward", TargetGroupARNs: []string{"arn:tg/web"}},
+				},
+			},
+			{Protocol: "HTTP", Port: 80},
+		},
+	}}
+}
+
+func TestSimulateRoute(t *testing.T) {
+	tests := []struct {
+		input    string
+		priority string
+	}{
+		{"API.example.com/orders/7", "10"},
+		{"eu.api.example.com/orders", "10"},
+		{"api.example.com/Orders", "default"},
+		{"api.example.com/users x-canary:true", "5"},
+		{"DELETE example.com/?q=drop-table", "20"},
+		{"GET example.com/?q=drop-table", "default"},
+	}
+
+	for _, tt := range tests {
+		request, err := ParseRequest(tt.input)
+		if err != nil {
+			t.Fatalf("ParseRequest(%q) error = %v", tt.input, err)
+		}
+
+		matches := SimulateRoute(routingFixture(), request)
+		if len(matches) != 2 {
+			t.Fatalf("Expected a match per listener, got %d", len(matches))
+		}
+		https := matches[0]
+		if !https.Matched || https.Rule.Priority != tt.priority {
+			t.Errorf("%s: expected rule %s, got %+v", tt.input, tt.priority, https.Rule)
+		}
+		if len(https.Skipped) != 1 || https.Skipped[0] != "1" {
+			t.Errorf("%s: expected the source-ip rule to be skipped, got %v", tt.input, https.Skipped)
+		}
+		if matches[1].Matched {
+			t.Errorf("%s: expected no match on the listener without rules", tt.input)
+		}
+	}
+}
+
+func TestFormatRouteSimulation(t *testing.T) {
+	request, err := ParseRequest("api.example.com/orders/7")
+	if err != nil {
+		t.Fatalf("ParseRequest() error = %v", err)
+	}
+
+	result := FormatRouteSimulation(routingFixture(), request)
+
+	expectedElements := []string{
+		"ROUTE SIMULATION: GET api.example.com/orders/7",
+		"🔄 web",
+		"HTTPS:443 → rule 10 (host-header api.example.com | *.api.example.com AND path-pattern /orders*) → api-tg",
+		"skipped rule 1: source-ip conditions are not simulated",
+		"HTTP:80 → rules not loaded",
+	}
+	for _, expected := range expectedElements {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected output to contain '%s', got:\n%s", expected, result)
+		}
+	}
+}
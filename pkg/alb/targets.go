@@ -0,0 +1,129 @@
+package alb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+)
+
+// TargetAction is a change to the registration of a target
+type TargetAction string
+
+// Actions on targets
+const (
+	ActionDeregister TargetAction = "Deregister"
+	ActionRegister   TargetAction = "Register"
+)
+
+// notRegisteredReason is the reason of the targets that are not registered
+// with their target group
+const notRegisteredReason = "Target.NotRegistered"
+
+// TargetRef is a target of a target group of a load balancer
+type TargetRef struct {
+	LoadBalancer   string
+	TargetGroup    string
+	TargetGroupARN string
+	Target         TargetSummary
+}
+
+// Key identifies the target across refreshes
+func (r TargetRef) Key() string {
+	return fmt.Sprintf("%s/%s:%d", r.TargetGroupARN, r.Target.ID, r.Target.Port)
+}
+
+// Targets returns the targets of the load balancers in the order
+// FormatLoadBalancers lists them
+func Targets(summaries []LoadBalancerSummary) []TargetRef {
+	var targets []TargetRef
+	for _, lb := range summaries {
+		for _, tg := range lb.TargetGroups {
+			for _, target := range tg.Targets {
+				targets = append(targets, TargetRef{LoadBalancer: lb.Name, TargetGroup: tg.Name, TargetGroupARN: tg.ARN, Target: target})
+			}
+		}
+	}
+	return targets
+}
+
+// ActionFor returns the action that applies to a target: registering one that
+// is draining or no longer registered, deregistering any other
+func ActionFor(target TargetSummary) TargetAction {
+	if target.Status == "draining" || (target.Status == "unused" && target.Reason == notRegisteredReason) {
+		return ActionRegister
+	}
+	return ActionDeregister
+}
+
+// WithDeregistered returns the load balancers with the targets of deregistered
+// that left their target group added back as not registered, so that they can
+// be registered again. The summaries are not modified.
+func WithDeregistered(summaries []LoadBalancerSummary, deregistered []TargetRef) []LoadBalancerSummary {
+	if len(deregistered) == 0 {
+		return summaries
+	}
+
+	result := make([]LoadBalancerSummary, len(summaries))
+	for i, lb := range summaries {
+		result[i] = lb
+		result[i].TargetGroups = make([]TargetGroupSummary, len(lb.TargetGroups))
+		for j, tg := range lb.TargetGroups {
+			targets := append([]TargetSummary(nil), tg.Targets...)
+			for _, ref := range deregistered {
+				if ref.TargetGroupARN != tg.ARN || hasTarget(targets, ref.Target) {
+					continue
+				}
+				targets = append(targets, TargetSummary{ID: ref.Target.ID, Port: ref.Target.Port, Status: "unused", Reason: notRegisteredReason})
+			}
+			result[i].TargetGroups[j] = tg
+			result[i].TargetGroups[j].Targets = targets
+		}
+	}
+	return result
+}
+
+// hasTarget reports whether targets include the target with the ID and port
+// of target
+func hasTarget(targets []TargetSummary, target TargetSummary) bool {
+	for _, t := range targets {
+		if t.ID == target.ID && t.Port == target.Port {
+			return true
+		}
+	}
+	return false
+}
+
+// RunTargetAction deregisters a target from its target group or registers it
+// again. Deregistered targets drain their connections for the deregistration
+// delay of the target group before they leave it.
+func (c *Client) RunTargetAction(ctx context.Context, action TargetAction, ref TargetRef) error {
+	targets := []types.TargetDescription{{Id: aws.String(ref.Target.ID)}}
+	if ref.Target.Port != 0 {
+		targets[0].Port = aws.Int32(ref.Target.Port)
+	}
+
+	switch action {
+	case ActionDeregister:
+		_, err := c.elbv2Client.DeregisterTargets(ctx, &elasticloadbalancingv2.DeregisterTargetsInput{
+			TargetGroupArn: aws.String(ref.TargetGroupARN),
+			Targets:        targets,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to deregister target %s from %s: %w", ref.Target.ID, ref.TargetGroup, err)
+		}
+	case ActionRegister:
+		_, err := c.elbv2Client.RegisterTargets(ctx, &elasticloadbalancingv2.RegisterTargetsInput{
+			TargetGroupArn: aws.String(ref.TargetGroupARN),
+			Targets:        targets,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to register target %s with %s: %w", ref.Target.ID, ref.TargetGroup, err)
+		}
+	default:
+		return fmt.Errorf("unknown target action %q", action)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package alb
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+)
+
+func targetSummaries() []LoadBalancerSummary {
+	return []LoadBalancerSummary{
+		{Name: "web", TargetGroups: []TargetGroupSummary{
+			{Name: "web-tg", ARN: "arn:tg/web", Targets: []TargetSummary{
+				{ID: "i-1", Port: 80, Status: "healthy"},
+				{ID: "i-2", Port: 80, Status: "unhealthy"},
+			}},
+		}},
+		{Name: "idle"},
+		{Name: "api", TargetGroups: []TargetGroupSummary{
+			{Name: "api-tg", ARN: "arn:tg/api", Targets: []TargetSummary{{ID: "10.0.1.5", Port: 8080, Status: "draining"}}},
+		}},
+	}
+}
+
+func TestTargets(t *testing.T) {
+	targets := Targets(targetSummaries())
+	if len(targets) != 3 {
+		t.Fatalf("Expected 3 targets, got %d", len(targets))
+	}
+	if targets[1].LoadBalancer != "web" || targets[1].TargetGroup != "web-tg" || targets[1].Target.ID != "i-2" {
+		t.Errorf("Unexpected second target %+v", targets[1])
+	}
+	if targets[2].TargetGroupARN != "arn:tg/api" || targets[2].Key() != "arn:tg/api/10.0.1.5:8080" {
+		t.Errorf("Unexpected third target %+v with key %s", targets[2], targets[2].Key())
+	}
+}
+
+func TestActionFor(t *testing.T) {
+	tests := []struct {
+		target TargetSummary
+		want   TargetAction
+	}{
+		{TargetSummary{Status: "healthy"}, ActionDeregister},
+		{TargetSummary{Status: "unhealthy"}, ActionDeregister},
+		{TargetSummary{Status: "draining"}, ActionRegister},
+		{TargetSummary{Status: "unused", Reason: "Target.NotRegistered"}, ActionRegister},
+		{TargetSummary{Status: "unused", Reason: "Target.NotInUse"}, ActionDeregister},
+	}
+	for _, tt := range tests {
+		if got := ActionFor(tt.target); got != tt.want {
+			t.Errorf("ActionFor(%+v) = %s, want %s", tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestWithDeregistered(t *testing.T) {
+	summaries := targetSummaries()
+	gone := TargetRef{TargetGroupARN: "arn:tg/web", Target: TargetSummary{ID: "i-3", Port: 80, Status: "draining"}}
+	present := TargetRef{TargetGroupARN: "arn:tg/web", Target: TargetSummary{ID: "i-2", Port: 80}}
+
+	result := WithDeregistered(summaries, []TargetRef{gone, present})
+	targets := result[0].TargetGroups[0].Targets
+	if len(targets) != 3 {
+		t.Fatalf("Expected the target that left to be added back, got %+v", targets)
+	}
+	if targets[2].ID != "i-3" || targets[2].Status != "unused" || ActionFor(targets[2]) != ActionRegister {
+		t.Errorf("Unexpected target added back %+v", targets[2])
+	}
+	if len(summaries[0].TargetGroups[0].Targets) != 2 {
+		t.Errorf("Expected the summaries not to be modified")
+	}
+}
+
+func TestRunTargetAction(t *testing.T) {
+	var deregistered, registered []string
+	mock := &mockELBV2Client{
+		deregisterTargetsFunc: func(ctx context.Context, params *elasticloadbalancingv2.DeregisterTargetsInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DeregisterTargetsOutput, error) {
+			if aws.ToInt32(params.Targets[0].Port) != 80 {
+				t.Errorf("Expected port 80, got %v", params.Targets[0].Port)
+			}
+			deregistered = append(deregistered, aws.ToString(params.TargetGroupArn)+" "+aws.ToString(params.Targets[0].Id))
+			return &elasticloadbalancingv2.DeregisterTargetsOutput{}, nil
+		},
+		registerTargetsFunc: func(ctx context.Context, params *elasticloadbalancingv2.RegisterTargetsInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.RegisterTargetsOutput, error) {
+			registered = append(registered, aws.ToString(params.TargetGroupArn)+" "+aws.ToString(params.Targets[0].Id))
+			return nil, errors.New("AccessDenied")
+		},
+	}
+	client := NewClient(mock, nil, nil)
+	ref := Targets(targetSummaries())[1]
+
+	if err := client.RunTargetAction(context.Background(), ActionDeregister, ref); err != nil {
+		t.Fatalf("RunTargetAction returned an error: %v", err)
+	}
+	if len(deregistered) != 1 || deregistered[0] != "arn:tg/web i-2" {
+		t.Errorf("Unexpected deregistrations %v", deregistered)
+	}
+
+	err := client.RunTargetAction(context.Background(), ActionRegister, ref)
+	if err == nil || !strings.Contains(err.Error(), "failed to register target i-2 with web-tg") {
+		t.Errorf("Expected a register error, got %v", err)
+	}
+	if len(registered) != 1 {
+		t.Errorf("Expected one registration, got %v", registered)
+	}
+}
@@ -0,0 +1,448 @@
+package apigateway
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/apigateway"
+	resttypes "github.com/aws/aws-sdk-go-v2/service/apigateway/types"
+	"github.com/aws/aws-sdk-go-v2/service/apigatewayv2"
+	httptypes "github.com/aws/aws-sdk-go-v2/service/apigatewayv2/types"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+
+	"github.com/correctedcloud/aws-overview/pkg/cloudwatchmetrics"
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// The protocols of the APIs
+const (
+	ProtocolREST = "REST"
+	ProtocolHTTP = "HTTP"
+)
+
+// stageMethodSettings is the key of a REST stage's settings that apply to
+// all of its methods
+const stageMethodSettings = "*/*"
+
+// restClientAPI defines the interface for the API Gateway client of REST APIs
+type restClientAPI interface {
+	GetRestApis(ctx context.Context, params *apigateway.GetRestApisInput, optFns ...func(*apigateway.Options)) (*apigateway.GetRestApisOutput, error)
+	GetStages(ctx context.Context, params *apigateway.GetStagesInput, optFns ...func(*apigateway.Options)) (*apigateway.GetStagesOutput, error)
+}
+
+// httpClientAPI defines the interface for the API Gateway V2 client of HTTP APIs
+type httpClientAPI interface {
+	GetApis(ctx context.Context, params *apigatewayv2.GetApisInput, optFns ...func(*apigatewayv2.Options)) (*apigatewayv2.GetApisOutput, error)
+	GetStages(ctx context.Context, params *apigatewayv2.GetStagesInput, optFns ...func(*apigatewayv2.Options)) (*apigatewayv2.GetStagesOutput, error)
+}
+
+// cloudwatchClientAPI defines the interface for the CloudWatch client
+type cloudwatchClientAPI interface {
+	GetMetricData(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error)
+}
+
+// Client represents an API Gateway client covering REST and HTTP APIs
+type Client struct {
+	restClient       restClientAPI
+	httpClient       httpClientAPI
+	cloudwatchClient cloudwatchClientAPI
+	pool             *common.Pool
+}
+
+// APISummary represents a REST or HTTP API and its stages
+type APISummary struct {
+	ID       string
+	Name     string
+	Protocol string // ProtocolREST or ProtocolHTTP
+	Endpoint string // Endpoint type of a REST API, e.g. "REGIONAL", or the URL of an HTTP API
+	Stages   []StageSummary
+}
+
+// Throttle is a rate limit on the requests to a stage or a method. The zero
+// value means the limit is inherited, ultimately from the account's.
+type Throttle struct {
+	RateLimit  float64 // Steady-state requests per second
+	BurstLimit int32   // Requests accepted at once above the rate
+}
+
+// Set reports whether the limit is configured rather than inherited
+func (t Throttle) Set() bool {
+	return t.RateLimit > 0 || t.BurstLimit > 0
+}
+
+// StageSummary represents a stage of an API with its metrics of the last hour
+type StageSummary struct {
+	Name         string
+	DeploymentID string
+	LastUpdated  time.Time
+	Throttle     Throttle // Default limit of the stage's methods or routes
+	Overrides    int      // Methods or routes with a limit of their own
+	ClientErrors []float64
+	ServerErrors []float64
+	Latency      []float64 // Average milliseconds
+	MetricsEnd   time.Time // End of the metrics' window, zero when they were not fetched
+}
+
+// TotalServerErrors returns the number of 5xx responses within the hour
+func (s StageSummary) TotalServerErrors() float64 {
+	total := 0.0
+	for _, value := range s.ServerErrors {
+		total += value
+	}
+	return total
+}
+
+// HasServerErrors reports whether the stage returned 5xx responses within the hour
+func (s StageSummary) HasServerErrors() bool {
+	for _, value := range s.ServerErrors {
+		if value > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// NewClient returns a new API Gateway client whose calls run in pool, which may be nil
+func NewClient(restClient restClientAPI, httpClient httpClientAPI, cloudwatchClient cloudwatchClientAPI, pool *common.Pool) *Client {
+	return &Client{
+		restClient:       restClient,
+		httpClient:       httpClient,
+		cloudwatchClient: cloudwatchClient,
+		pool:             pool,
+	}
+}
+
+// GetAPIs returns the REST and HTTP APIs sorted by name, with the metrics of
+// their stages fetched together once the stages are loaded. WebSocket APIs
+// are left out. APIs whose stages or metrics fail to load are still
+// returned, with their errors alongside.
+func (c *Client) GetAPIs(ctx context.Context) ([]APISummary, []error) {
+	var errs []error
+
+	restAPIs, err := c.getRestAPIs(ctx)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	httpAPIs, err := c.getHTTPAPIs(ctx)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	summaries := append(restAPIs, httpAPIs...)
+
+	// Load the stages of each API in parallel
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	for i := range summaries {
+		wg.Add(1)
+		go func(summary *APISummary) {
+			defer wg.Done()
+			var err error
+			if summary.Protocol == ProtocolREST {
+				summary.Stages, err = c.getRestStages(ctx, summary.ID)
+			} else {
+				summary.Stages, err = c.getHTTPStages(ctx, summary.ID)
+			}
+			if err != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				errs = append(errs, fmt.Errorf("API %s: %w", summary.Name, err))
+			}
+		}(&summaries[i])
+	}
+	wg.Wait()
+
+	errs = append(errs, c.getMetrics(ctx, summaries)...)
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Name != summaries[j].Name {
+			return summaries[i].Name < summaries[j].Name
+		}
+		return summaries[i].ID < summaries[j].ID
+	})
+
+	return summaries, errs
+}
+
+// getRestAPIs returns all REST APIs without their stages, following the pagination positions
+func (c *Client) getRestAPIs(ctx context.Context) ([]APISummary, error) {
+	var summaries []APISummary
+	var position *string
+
+	for {
+		var result *apigateway.GetRestApisOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.restClient.GetRestApis(ctx, &apigateway.GetRestApisInput{
+				Position: position,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get REST APIs: %w", err)
+		}
+
+		for _, api := range result.Items {
+			summaries = append(summaries, newRestAPISummary(api))
+		}
+
+		position = result.Position
+		if position == nil {
+			break
+		}
+	}
+
+	return summaries, nil
+}
+
+// getHTTPAPIs returns all HTTP APIs without their stages, following the pagination tokens
+func (c *Client) getHTTPAPIs(ctx context.Context) ([]APISummary, error) {
+	var summaries []APISummary
+	var nextToken *string
+
+	for {
+		var result *apigatewayv2.GetApisOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.httpClient.GetApis(ctx, &apigatewayv2.GetApisInput{
+				NextToken: nextToken,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get HTTP APIs: %w", err)
+		}
+
+		for _, api := range result.Items {
+			if api.ProtocolType != httptypes.ProtocolTypeHttp {
+				continue
+			}
+			summaries = append(summaries, APISummary{
+				ID:       aws.ToString(api.ApiId),
+				Name:     aws.ToString(api.Name),
+				Protocol: ProtocolHTTP,
+				Endpoint: aws.ToString(api.ApiEndpoint),
+			})
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return summaries, nil
+}
+
+// getRestStages returns the stages of a REST API sorted by name
+func (c *Client) getRestStages(ctx context.Context, apiID string) ([]StageSummary, error) {
+	var result *apigateway.GetStagesOutput
+	err := c.pool.Do(ctx, func() (err error) {
+		result, err = c.restClient.GetStages(ctx, &apigateway.GetStagesInput{
+			RestApiId: aws.String(apiID),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stages: %w", err)
+	}
+
+	stages := make([]StageSummary, 0, len(result.Item))
+	for _, stage := range result.Item {
+		stages = append(stages, newRestStageSummary(stage))
+	}
+	sortStages(stages)
+	return stages, nil
+}
+
+// getHTTPStages returns the stages of an HTTP API sorted by name, following
+// the pagination tokens
+func (c *Client) getHTTPStages(ctx context.Context, apiID string) ([]StageSummary, error) {
+	var stages []StageSummary
+	var nextToken *string
+
+	for {
+		var result *apigatewayv2.GetStagesOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.httpClient.GetStages(ctx, &apigatewayv2.GetStagesInput{
+				ApiId:     aws.String(apiID),
+				NextToken: nextToken,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get stages: %w", err)
+		}
+
+		for _, stage := range result.Items {
+			stages = append(stages, newHTTPStageSummary(stage))
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	sortStages(stages)
+	return stages, nil
+}
+
+// sortStages sorts stages by name
+func sortStages(stages []StageSummary) {
+	sort.Slice(stages, func(i, j int) bool {
+		return stages[i].Name < stages[j].Name
+	})
+}
+
+// newRestAPISummary summarizes a REST API without its stages
+func newRestAPISummary(api resttypes.RestApi) APISummary {
+	summary := APISummary{
+		ID:       aws.ToString(api.Id),
+		Name:     aws.ToString(api.Name),
+		Protocol: ProtocolREST,
+	}
+	if api.EndpointConfiguration != nil {
+		endpointTypes := make([]string, len(api.EndpointConfiguration.Types))
+		for i, endpointType := range api.EndpointConfiguration.Types {
+			endpointTypes[i] = string(endpointType)
+		}
+		summary.Endpoint = strings.Join(endpointTypes, ", ")
+	}
+	return summary
+}
+
+// newRestStageSummary summarizes a stage of a REST API. Its default throttle
+// is in the settings of all methods, "*/*", and overrides in the settings of
+// single methods, e.g. "orders/GET".
+func newRestStageSummary(stage resttypes.Stage) StageSummary {
+	summary := StageSummary{
+		Name:         aws.ToString(stage.StageName),
+		DeploymentID: aws.ToString(stage.DeploymentId),
+		LastUpdated:  aws.ToTime(stage.LastUpdatedDate),
+	}
+	for key, settings := range stage.MethodSettings {
+		throttle := Throttle{RateLimit: settings.ThrottlingRateLimit, BurstLimit: settings.ThrottlingBurstLimit}
+		if key == stageMethodSettings {
+			summary.Throttle = throttle
+		} else if throttle.Set() {
+			summary.Overrides++
+		}
+	}
+	return summary
+}
+
+// newHTTPStageSummary summarizes a stage of an HTTP API
+func newHTTPStageSummary(stage httptypes.Stage) StageSummary {
+	summary := StageSummary{
+		Name:         aws.ToString(stage.StageName),
+		DeploymentID: aws.ToString(stage.DeploymentId),
+		LastUpdated:  aws.ToTime(stage.LastUpdatedDate),
+	}
+	if stage.DefaultRouteSettings != nil {
+		summary.Throttle = newHTTPThrottle(*stage.DefaultRouteSettings)
+	}
+	for _, settings := range stage.RouteSettings {
+		if newHTTPThrottle(settings).Set() {
+			summary.Overrides++
+		}
+	}
+	return summary
+}
+
+// newHTTPThrottle returns the throttle of an HTTP API route's settings
+func newHTTPThrottle(settings httptypes.RouteSettings) Throttle {
+	return Throttle{
+		RateLimit:  aws.ToFloat64(settings.ThrottlingRateLimit),
+		BurstLimit: aws.ToInt32(settings.ThrottlingBurstLimit),
+	}
+}
+
+// The metrics queried for each stage, in the order of their queries
+const (
+	queryClientErrors = iota
+	queryServerErrors
+	queryLatency
+	queriesPerStage
+)
+
+// metricNames are the names of the metrics of each protocol, in the order of
+// the query constants. REST APIs are identified by name, HTTP APIs by ID.
+var metricNames = map[string][queriesPerStage]string{
+	ProtocolREST: {"4XXError", "5XXError", "Latency"},
+	ProtocolHTTP: {"4xx", "5xx", "Latency"},
+}
+
+// getMetrics fills in the metrics of all stages, fetched together in as few
+// CloudWatch calls as possible, and returns the errors of the metrics that
+// could not be loaded
+func (c *Client) getMetrics(ctx context.Context, summaries []APISummary) []error {
+	var queries []cloudwatchmetrics.Query
+	var stages []*StageSummary
+	var apis []string
+
+	for i := range summaries {
+		api := &summaries[i]
+		dimensions := map[string]string{"ApiName": api.Name}
+		if api.Protocol == ProtocolHTTP {
+			dimensions = map[string]string{"ApiId": api.ID}
+		}
+
+		for j := range api.Stages {
+			stage := &api.Stages[j]
+			stageDimensions := map[string]string{"Stage": stage.Name}
+			for name, value := range dimensions {
+				stageDimensions[name] = value
+			}
+
+			query := func(metricName, stat string) cloudwatchmetrics.Query {
+				return cloudwatchmetrics.Query{
+					Namespace:  "AWS/ApiGateway",
+					MetricName: metricName,
+					Dimensions: stageDimensions,
+					Stat:       stat,
+					Period:     5 * time.Minute,
+					Window:     time.Hour,
+				}
+			}
+			names := metricNames[api.Protocol]
+			// The order must match the query constants
+			queries = append(queries,
+				query(names[queryClientErrors], "Sum"),
+				query(names[queryServerErrors], "Sum"),
+				query(names[queryLatency], "Average"),
+			)
+			stages = append(stages, stage)
+			apis = append(apis, api.Name)
+		}
+	}
+	if len(queries) == 0 {
+		return nil
+	}
+
+	results := cloudwatchmetrics.New(c.cloudwatchClient, c.pool).Fetch(ctx, queries)
+
+	var errs []error
+	for i, stage := range stages {
+		stageResults := results[i*queriesPerStage : (i+1)*queriesPerStage]
+
+		for n, result := range stageResults {
+			if result.Err != nil {
+				metricName := queries[i*queriesPerStage+n].MetricName
+				errs = append(errs, fmt.Errorf("API %s stage %s: failed to get metric data for %s: %w", apis[i], stage.Name, metricName, result.Err))
+			}
+		}
+
+		// No datapoints means no requests; callers render an explicit
+		// "no data" state for an empty slice
+		stage.MetricsEnd = stageResults[queryClientErrors].End
+		stage.ClientErrors = stageResults[queryClientErrors].Values
+		stage.ServerErrors = stageResults[queryServerErrors].Values
+		stage.Latency = stageResults[queryLatency].Values
+	}
+
+	return errs
+}
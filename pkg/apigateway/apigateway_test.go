@@ -0,0 +1,240 @@
+package apigateway
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/apigateway"
+	resttypes "github.com/aws/aws-sdk-go-v2/service/apigateway/types"
+	"github.com/aws/aws-sdk-go-v2/service/apigatewayv2"
+	httptypes "github.com/aws/aws-sdk-go-v2/service/apigatewayv2/types"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// Mock API Gateway client of REST APIs
+type mockRestClient struct {
+	getRestApisFunc func(ctx context.Context, params *apigateway.GetRestApisInput, optFns ...func(*apigateway.Options)) (*apigateway.GetRestApisOutput, error)
+	getStagesFunc   func(ctx context.Context, params *apigateway.GetStagesInput, optFns ...func(*apigateway.Options)) (*apigateway.GetStagesOutput, error)
+}
+
+func (m *mockRestClient) GetRestApis(ctx context.Context, params *apigateway.GetRestApisInput, optFns ...func(*apigateway.Options)) (*apigateway.GetRestApisOutput, error) {
+	return m.getRestApisFunc(ctx, params, optFns...)
+}
+
+func (m *mockRestClient) GetStages(ctx context.Context, params *apigateway.GetStagesInput, optFns ...func(*apigateway.Options)) (*apigateway.GetStagesOutput, error) {
+	return m.getStagesFunc(ctx, params, optFns...)
+}
+
+// Mock API Gateway V2 client of HTTP APIs
+type mockHTTPClient struct {
+	getApisFunc   func(ctx context.Context, params *apigatewayv2.GetApisInput, optFns ...func(*apigatewayv2.Options)) (*apigatewayv2.GetApisOutput, error)
+	getStagesFunc func(ctx context.Context, params *apigatewayv2.GetStagesInput, optFns ...func(*apigatewayv2.Options)) (*apigatewayv2.GetStagesOutput, error)
+}
+
+func (m *mockHTTPClient) GetApis(ctx context.Context, params *apigatewayv2.GetApisInput, optFns ...func(*apigatewayv2.Options)) (*apigatewayv2.GetApisOutput, error) {
+	return m.getApisFunc(ctx, params, optFns...)
+}
+
+func (m *mockHTTPClient) GetStages(ctx context.Context, params *apigatewayv2.GetStagesInput, optFns ...func(*apigatewayv2.Options)) (*apigatewayv2.GetStagesOutput, error) {
+	return m.getStagesFunc(ctx, params, optFns...)
+}
+
+// Mock CloudWatch client
+type mockCloudWatchClient struct {
+	getMetricDataFunc func(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error)
+}
+
+func (m *mockCloudWatchClient) GetMetricData(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+	return m.getMetricDataFunc(ctx, params, optFns...)
+}
+
+// newMockClients returns clients with a REST API "orders" with a "prod"
+// stage, an HTTP API "payments" with a "$default" stage and a WebSocket API
+// "chat"
+func newMockClients() (*mockRestClient, *mockHTTPClient) {
+	rest := &mockRestClient{
+		getRestApisFunc: func(ctx context.Context, params *apigateway.GetRestApisInput, optFns ...func(*apigateway.Options)) (*apigateway.GetRestApisOutput, error) {
+			return &apigateway.GetRestApisOutput{Items: []resttypes.RestApi{{
+				Id:                    aws.String("a1b2c3"),
+				Name:                  aws.String("orders"),
+				EndpointConfiguration: &resttypes.EndpointConfiguration{Types: []resttypes.EndpointType{resttypes.EndpointTypeRegional}},
+			}}}, nil
+		},
+		getStagesFunc: func(ctx context.Context, params *apigateway.GetStagesInput, optFns ...func(*apigateway.Options)) (*apigateway.GetStagesOutput, error) {
+			return &apigateway.GetStagesOutput{Item: []resttypes.Stage{{
+				StageName:    aws.String("prod"),
+				DeploymentId: aws.String("dep1"),
+				MethodSettings: map[string]resttypes.MethodSetting{
+					"*/*":        {ThrottlingRateLimit: 100, ThrottlingBurstLimit: 200},
+					"orders/GET": {ThrottlingRateLimit: 50, ThrottlingBurstLimit: 100},
+					"orders/PUT": {MetricsEnabled: true},
+				},
+			}}}, nil
+		},
+	}
+
+	http := &mockHTTPClient{
+		getApisFunc: func(ctx context.Context, params *apigatewayv2.GetApisInput, optFns ...func(*apigatewayv2.Options)) (*apigatewayv2.GetApisOutput, error) {
+			return &apigatewayv2.GetApisOutput{Items: []httptypes.Api{
+				{ApiId: aws.String("x9y8z7"), Name: aws.String("payments"), ProtocolType: httptypes.ProtocolTypeHttp, ApiEndpoint: aws.String("https://x9y8z7.execute-api.us-east-1.amazonaws.com")},
+				{ApiId: aws.String("w1"), Name: aws.String("chat"), ProtocolType: httptypes.ProtocolTypeWebsocket},
+			}}, nil
+		},
+		getStagesFunc: func(ctx context.Context, params *apigatewayv2.GetStagesInput, optFns ...func(*apigatewayv2.Options)) (*apigatewayv2.GetStagesOutput, error) {
+			return &apigatewayv2.GetStagesOutput{Items: []httptypes.Stage{{
+				StageName: aws.String("$default"),
+			}}}, nil
+		},
+	}
+
+	return rest, http
+}
+
+func TestGetAPIs(t *testing.T) {
+	rest, http := newMockClients()
+
+	var dimensions []map[string]string
+	mockCloudWatch := &mockCloudWatchClient{
+		getMetricDataFunc: func(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+			output := &cloudwatch.GetMetricDataOutput{}
+			for _, query := range params.MetricDataQueries {
+				metric := query.MetricStat.Metric
+				byName := make(map[string]string)
+				for _, dimension := range metric.Dimensions {
+					byName[*dimension.Name] = *dimension.Value
+				}
+				dimensions = append(dimensions, byName)
+
+				values := []float64{0, 0}
+				if *metric.MetricName == "5XXError" {
+					values = []float64{0, 3}
+				}
+				output.MetricDataResults = append(output.MetricDataResults, cwtypes.MetricDataResult{Id: query.Id, Values: values})
+			}
+			return output, nil
+		},
+	}
+
+	client := NewClient(rest, http, mockCloudWatch, nil)
+
+	apis, errs := client.GetAPIs(context.Background())
+	if len(errs) > 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+
+	if len(apis) != 2 {
+		t.Fatalf("Expected the WebSocket API to be left out, got %d APIs", len(apis))
+	}
+	if apis[0].Name != "orders" || apis[1].Name != "payments" {
+		t.Fatalf("Expected APIs sorted by name, got %s and %s", apis[0].Name, apis[1].Name)
+	}
+
+	orders := apis[0]
+	if orders.Protocol != ProtocolREST || orders.Endpoint != "REGIONAL" {
+		t.Errorf("Expected a REGIONAL REST API, got %s %s", orders.Protocol, orders.Endpoint)
+	}
+	if len(orders.Stages) != 1 {
+		t.Fatalf("Expected 1 stage, got %d", len(orders.Stages))
+	}
+	prod := orders.Stages[0]
+	if prod.Throttle != (Throttle{RateLimit: 100, BurstLimit: 200}) {
+		t.Errorf("Expected the stage-wide throttle, got %+v", prod.Throttle)
+	}
+	if prod.Overrides != 1 {
+		t.Errorf("Expected 1 method with its own throttle, got %d", prod.Overrides)
+	}
+	if !prod.HasServerErrors() || prod.TotalServerErrors() != 3 {
+		t.Errorf("Expected 3 5xx responses, got %v", prod.ServerErrors)
+	}
+
+	payments := apis[1]
+	if payments.Protocol != ProtocolHTTP || payments.Stages[0].Throttle.Set() {
+		t.Errorf("Expected an HTTP API with the account throttle, got %+v", payments)
+	}
+	if payments.Stages[0].HasServerErrors() {
+		t.Errorf("Expected no 5xx responses for the HTTP API, got %v", payments.Stages[0].ServerErrors)
+	}
+
+	if len(dimensions) != 2*queriesPerStage {
+		t.Fatalf("Expected %d queries, got %d", 2*queriesPerStage, len(dimensions))
+	}
+	for _, byName := range dimensions {
+		switch byName["Stage"] {
+		case "prod":
+			if byName["ApiName"] != "orders" {
+				t.Errorf("Expected REST metrics by API name, got %v", byName)
+			}
+		case "$default":
+			if byName["ApiId"] != "x9y8z7" {
+				t.Errorf("Expected HTTP metrics by API ID, got %v", byName)
+			}
+		default:
+			t.Errorf("Unexpected dimensions %v", byName)
+		}
+	}
+}
+
+func TestGetAPIsPartialFailure(t *testing.T) {
+	rest, http := newMockClients()
+	http.getApisFunc = func(ctx context.Context, params *apigatewayv2.GetApisInput, optFns ...func(*apigatewayv2.Options)) (*apigatewayv2.GetApisOutput, error) {
+		return nil, errors.New("access denied")
+	}
+
+	mockCloudWatch := &mockCloudWatchClient{
+		getMetricDataFunc: func(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+			return nil, errors.New("throttled")
+		},
+	}
+
+	client := NewClient(rest, http, mockCloudWatch, nil)
+
+	apis, errs := client.GetAPIs(context.Background())
+	if len(apis) != 1 || apis[0].Name != "orders" {
+		t.Fatalf("Expected the REST API despite the failures, got %+v", apis)
+	}
+	if len(apis[0].Stages) != 1 {
+		t.Errorf("Expected the stage to be kept without metrics, got %+v", apis[0].Stages)
+	}
+	// The HTTP APIs and each of the 3 metrics of the stage
+	if len(errs) != 1+queriesPerStage {
+		t.Errorf("Expected %d errors, got %d: %v", 1+queriesPerStage, len(errs), errs)
+	}
+}
+
+func TestGetAPIsPaginates(t *testing.T) {
+	rest, http := newMockClients()
+
+	calls := 0
+	rest.getRestApisFunc = func(ctx context.Context, params *apigateway.GetRestApisInput, optFns ...func(*apigateway.Options)) (*apigateway.GetRestApisOutput, error) {
+		calls++
+		if params.Position == nil {
+			return &apigateway.GetRestApisOutput{
+				Items:    []resttypes.RestApi{{Id: aws.String("a"), Name: aws.String("first")}},
+				Position: aws.String("page2"),
+			}, nil
+		}
+		return &apigateway.GetRestApisOutput{Items: []resttypes.RestApi{{Id: aws.String("b"), Name: aws.String("second")}}}, nil
+	}
+
+	mockCloudWatch := &mockCloudWatchClient{
+		getMetricDataFunc: func(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+			return &cloudwatch.GetMetricDataOutput{}, nil
+		},
+	}
+
+	client := NewClient(rest, http, mockCloudWatch, nil)
+
+	apis, errs := client.GetAPIs(context.Background())
+	if len(errs) > 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 GetRestApis calls, got %d", calls)
+	}
+	if len(apis) != 3 {
+		t.Errorf("Expected 2 REST APIs and 1 HTTP API, got %d", len(apis))
+	}
+}
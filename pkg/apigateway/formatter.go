@@ -0,0 +1,181 @@
+package apigateway
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// timeNow is the clock the age of the stages is measured against
+var timeNow = time.Now
+
+// FormatAPIs formats the APIs and their stages for terminal display, listing
+// the stages that returned 5xx responses within the hour first
+func FormatAPIs(summaries []APISummary) string {
+	if len(summaries) == 0 {
+		return "No API Gateway APIs found"
+	}
+
+	var output strings.Builder
+	output.WriteString("API GATEWAY APIS\n")
+	output.WriteString(common.Rule("API GATEWAY APIS", "=") + "\n\n")
+
+	if failing := countStagesWithServerErrors(summaries); failing > 0 {
+		output.WriteString(fmt.Sprintf("%s SERVER ERRORS (%d stages returned 5xx responses in the last hour)\n",
+			common.Symbol("🚨"), failing))
+		for _, api := range summaries {
+			for _, stage := range api.Stages {
+				if stage.HasServerErrors() {
+					output.WriteString(fmt.Sprintf("  %s/%s: %s 5xx responses\n", api.Name, stage.Name, common.FormatFloatWithPrecision(stage.TotalServerErrors(), 0)))
+				}
+			}
+		}
+		output.WriteString("\n")
+	}
+
+	for _, api := range summaries {
+		description := api.Protocol
+		if api.Endpoint != "" {
+			description += ", " + api.Endpoint
+		}
+		output.WriteString(fmt.Sprintf("%s %s (%s)\n", common.Symbol(getStatusSymbol(api)), api.Name, description))
+
+		if len(api.Stages) == 0 {
+			output.WriteString("  No stages\n\n")
+			continue
+		}
+
+		for _, stage := range api.Stages {
+			window := common.WithWindow(stage.MetricsEnd.Add(-time.Hour), stage.MetricsEnd)
+			output.WriteString(fmt.Sprintf("  Stage %s%s\n", stage.Name, formatDeployment(stage)))
+			output.WriteString(fmt.Sprintf("  Throttling: %s\n", formatThrottle(api, stage)))
+
+			output.WriteString("\n  4xx Responses (1 hour):\n")
+			if len(stage.ClientErrors) > 0 {
+				output.WriteString(common.GenerateSparkline(stage.ClientErrors, "4xx Responses", 3, common.WithStats(), window) + "\n")
+			} else {
+				output.WriteString("  No 4xx data available\n")
+			}
+
+			output.WriteString("\n  5xx Responses (1 hour):\n")
+			if len(stage.ServerErrors) > 0 {
+				output.WriteString(common.GenerateSparkline(stage.ServerErrors, "5xx Responses", 3, common.WithStats(), window) + "\n")
+			} else {
+				output.WriteString("  No 5xx data available\n")
+			}
+
+			output.WriteString("\n  Latency (1 hour):\n")
+			if len(stage.Latency) > 0 {
+				output.WriteString(common.GenerateSparkline(stage.Latency, "Average Latency (ms)", 3, common.WithStats(), window) + "\n")
+			} else {
+				output.WriteString("  No latency data available\n")
+			}
+
+			output.WriteString("\n")
+		}
+	}
+
+	return output.String()
+}
+
+// GetAPIsSummary returns a brief summary of the APIs
+func GetAPIsSummary(summaries []APISummary) string {
+	rest, http, stages := 0, 0, 0
+	for _, api := range summaries {
+		if api.Protocol == ProtocolREST {
+			rest++
+		} else {
+			http++
+		}
+		stages += len(api.Stages)
+	}
+
+	summary := fmt.Sprintf("%d APIs (%d REST, %d HTTP), %d stages", len(summaries), rest, http, stages)
+	if failing := countStagesWithServerErrors(summaries); failing > 0 {
+		summary += fmt.Sprintf(", %d with 5xx responses", failing)
+	}
+	return summary
+}
+
+// GetAPIsWithServerErrors returns the APIs with a stage that returned 5xx
+// responses within the hour
+func GetAPIsWithServerErrors(summaries []APISummary) []APISummary {
+	var failing []APISummary
+	for _, api := range summaries {
+		for _, stage := range api.Stages {
+			if stage.HasServerErrors() {
+				failing = append(failing, api)
+				break
+			}
+		}
+	}
+	return failing
+}
+
+// countStagesWithServerErrors returns how many stages returned 5xx responses
+// within the hour
+func countStagesWithServerErrors(summaries []APISummary) int {
+	count := 0
+	for _, api := range summaries {
+		for _, stage := range api.Stages {
+			if stage.HasServerErrors() {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// formatDeployment describes the deployment of a stage and when it was last updated
+func formatDeployment(stage StageSummary) string {
+	var details []string
+	if stage.DeploymentID != "" {
+		details = append(details, "deployment "+stage.DeploymentID)
+	}
+	if !stage.LastUpdated.IsZero() {
+		details = append(details, "updated "+formatAge(stage.LastUpdated)+" ago")
+	}
+	if len(details) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(details, ", ") + ")"
+}
+
+// formatThrottle describes the default rate limit of a stage and how many
+// methods of a REST API or routes of an HTTP API override it
+func formatThrottle(api APISummary, stage StageSummary) string {
+	throttle := "account default"
+	if stage.Throttle.Set() {
+		throttle = fmt.Sprintf("%s req/s, burst %d", common.FormatFloatWithPrecision(stage.Throttle.RateLimit, 0), stage.Throttle.BurstLimit)
+	}
+	if stage.Overrides > 0 {
+		noun := "methods"
+		if api.Protocol == ProtocolHTTP {
+			noun = "routes"
+		}
+		throttle += fmt.Sprintf(", %d %s with their own limit", stage.Overrides, noun)
+	}
+	return throttle
+}
+
+// formatAge returns how long ago t was, in days once it is over a day
+func formatAge(t time.Time) string {
+	age := timeNow().Sub(t)
+	if age >= 24*time.Hour {
+		return fmt.Sprintf("%dd", int(age.Hours()/24))
+	}
+	return age.Round(time.Minute).String()
+}
+
+// getStatusSymbol returns the emoji of an API's health
+func getStatusSymbol(api APISummary) string {
+	switch {
+	case len(api.Stages) == 0:
+		return "⚪"
+	case len(GetAPIsWithServerErrors([]APISummary{api})) == 1:
+		return "🚨"
+	}
+	return "✅"
+}
@@ -0,0 +1,71 @@
+package apigateway
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatAPIs(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	if got := FormatAPIs(nil); got != "No API Gateway APIs found" {
+		t.Errorf("Expected 'No API Gateway APIs found', got '%s'", got)
+	}
+
+	apis := []APISummary{
+		{
+			Name:     "orders",
+			Protocol: ProtocolREST,
+			Endpoint: "REGIONAL",
+			Stages: []StageSummary{{
+				Name:         "prod",
+				DeploymentID: "dep1",
+				LastUpdated:  now.Add(-72 * time.Hour),
+				Throttle:     Throttle{RateLimit: 100, BurstLimit: 200},
+				Overrides:    2,
+				ServerErrors: []float64{0, 4, 1},
+			}},
+		},
+		{
+			Name:     "payments",
+			Protocol: ProtocolHTTP,
+			Stages:   []StageSummary{{Name: "$default", Overrides: 1}},
+		},
+		{Name: "empty", Protocol: ProtocolREST},
+	}
+
+	output := FormatAPIs(apis)
+	for _, expected := range []string{
+		"SERVER ERRORS (1 stages returned 5xx responses in the last hour)",
+		"orders/prod: 5 5xx responses",
+		"orders (REST, REGIONAL)",
+		"Stage prod (deployment dep1, updated 3d ago)",
+		"Throttling: 100 req/s, burst 200, 2 methods with their own limit",
+		"Throttling: account default, 1 routes with their own limit",
+		"No 4xx data available",
+		"No stages",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
+
+func TestGetAPIsSummary(t *testing.T) {
+	apis := []APISummary{
+		{Protocol: ProtocolREST, Stages: []StageSummary{{ServerErrors: []float64{1}}, {}}},
+		{Protocol: ProtocolHTTP, Stages: []StageSummary{{}}},
+	}
+
+	expected := "2 APIs (1 REST, 1 HTTP), 3 stages, 1 with 5xx responses"
+	if got := GetAPIsSummary(apis); got != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, got)
+	}
+
+	if failing := GetAPIsWithServerErrors(apis); len(failing) != 1 || failing[0].Protocol != ProtocolREST {
+		t.Errorf("Expected the REST API to have server errors, got %+v", failing)
+	}
+}
@@ -0,0 +1,205 @@
+// Package changes detects the state transitions of resources between two
+// loads of a service, such as targets turning unhealthy, instances stopping
+// or services scaling, and describes each in a line.
+package changes
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/correctedcloud/aws-overview/pkg/alb"
+	"github.com/correctedcloud/aws-overview/pkg/ec2"
+	"github.com/correctedcloud/aws-overview/pkg/ecs"
+	"github.com/correctedcloud/aws-overview/pkg/probe"
+	"github.com/correctedcloud/aws-overview/pkg/rds"
+)
+
+// LoadBalancers returns the transitions of the targets of the load
+// balancers: changes of their health and targets registering or leaving.
+// Target groups missing from either load, e.g. because they failed to load,
+// are not compared.
+func LoadBalancers(before, after []alb.LoadBalancerSummary) []string {
+	previous := make(map[string]alb.TargetGroupSummary)
+	for _, lb := range before {
+		for _, tg := range lb.TargetGroups {
+			previous[tg.ARN] = tg
+		}
+	}
+
+	var changes []string
+	for _, lb := range after {
+		for _, tg := range lb.TargetGroups {
+			old, ok := previous[tg.ARN]
+			if !ok {
+				continue
+			}
+
+			status := make(map[string]string)
+			for _, target := range old.Targets {
+				status[targetID(target)] = target.Status
+			}
+			for _, target := range tg.Targets {
+				id := targetID(target)
+				was, ok := status[id]
+				switch {
+				case !ok:
+					changes = append(changes, fmt.Sprintf("target %s registered in %s (%s)", id, tg.Name, target.Status))
+				case was != target.Status:
+					changes = append(changes, fmt.Sprintf("target %s in %s went %s (was %s)", id, tg.Name, target.Status, was))
+				}
+				delete(status, id)
+			}
+			for id := range status {
+				changes = append(changes, fmt.Sprintf("target %s left %s", id, tg.Name))
+			}
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
+
+// targetID returns the ID and port of a target, as the same instance can be
+// registered on several ports
+func targetID(target alb.TargetSummary) string {
+	return fmt.Sprintf("%s:%d", target.ID, target.Port)
+}
+
+// Instances returns the transitions of the EC2 instances: launches, state
+// changes, status checks turning impaired or ok, and instances no longer
+// listed
+func Instances(before, after []ec2.InstanceSummary) []string {
+	previous := make(map[string]ec2.InstanceSummary)
+	for _, instance := range before {
+		previous[instance.InstanceID] = instance
+	}
+
+	var changes []string
+	for _, instance := range after {
+		name := instanceName(instance)
+		old, ok := previous[instance.InstanceID]
+		delete(previous, instance.InstanceID)
+		if !ok {
+			changes = append(changes, fmt.Sprintf("instance %s launched (%s)", name, instance.State))
+			continue
+		}
+		if old.State != instance.State {
+			changes = append(changes, fmt.Sprintf("instance %s went %s (was %s)", name, instance.State, old.State))
+		}
+		if impaired(old) != impaired(instance) {
+			if impaired(instance) {
+				changes = append(changes, fmt.Sprintf("instance %s status checks impaired", name))
+			} else {
+				changes = append(changes, fmt.Sprintf("instance %s status checks ok again", name))
+			}
+		}
+	}
+	for _, instance := range previous {
+		changes = append(changes, fmt.Sprintf("instance %s is gone", instanceName(instance)))
+	}
+	sort.Strings(changes)
+	return changes
+}
+
+// instanceName returns the name and ID of an instance, or its ID when unnamed
+func instanceName(instance ec2.InstanceSummary) string {
+	if instance.Name == "" {
+		return instance.InstanceID
+	}
+	return fmt.Sprintf("%s (%s)", instance.Name, instance.InstanceID)
+}
+
+// impaired reports whether a status check of an instance failed
+func impaired(instance ec2.InstanceSummary) bool {
+	return instance.SystemStatus == "impaired" || instance.InstanceStatus == "impaired"
+}
+
+// Services returns the transitions of the ECS services: scaling, changes of
+// their health and rollout state, and services created or deleted
+func Services(before, after []ecs.ServiceSummary) []string {
+	previous := make(map[string]ecs.ServiceSummary)
+	for _, service := range before {
+		previous[service.ClusterName+"/"+service.ServiceName] = service
+	}
+
+	var changes []string
+	for _, service := range after {
+		key := service.ClusterName + "/" + service.ServiceName
+		old, ok := previous[key]
+		delete(previous, key)
+		if !ok {
+			changes = append(changes, fmt.Sprintf("service %s created in %s", service.ServiceName, service.ClusterName))
+			continue
+		}
+		if old.DesiredCount != service.DesiredCount {
+			changes = append(changes, fmt.Sprintf("service %s scaled %d→%d", service.ServiceName, old.DesiredCount, service.DesiredCount))
+		}
+		if old.HealthStatus != service.HealthStatus {
+			changes = append(changes, fmt.Sprintf("service %s went %s (%d/%d running)",
+				service.ServiceName, strings.ToLower(service.HealthStatus), service.RunningCount, service.DesiredCount))
+		}
+		if old.DeploymentStatus != service.DeploymentStatus {
+			// Completed deployments have no status
+			state := "completed"
+			if service.DeploymentStatus != "" {
+				state = strings.ToLower(strings.ReplaceAll(service.DeploymentStatus, "_", "-"))
+			}
+			changes = append(changes, fmt.Sprintf("service %s deployment %s", service.ServiceName, state))
+		}
+	}
+	for _, service := range previous {
+		changes = append(changes, fmt.Sprintf("service %s deleted from %s", service.ServiceName, service.ClusterName))
+	}
+	sort.Strings(changes)
+	return changes
+}
+
+// DBInstances returns the transitions of the DB instances: status changes
+// and instances created or deleted
+func DBInstances(before, after []rds.DBInstanceSummary) []string {
+	previous := make(map[string]rds.DBInstanceSummary)
+	for _, instance := range before {
+		previous[instance.Identifier] = instance
+	}
+
+	var changes []string
+	for _, instance := range after {
+		old, ok := previous[instance.Identifier]
+		delete(previous, instance.Identifier)
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("DB instance %s created (%s)", instance.Identifier, instance.Status))
+		case old.Status != instance.Status:
+			changes = append(changes, fmt.Sprintf("DB instance %s went %s (was %s)", instance.Identifier, instance.Status, old.Status))
+		}
+	}
+	for _, instance := range previous {
+		changes = append(changes, fmt.Sprintf("DB instance %s deleted", instance.Identifier))
+	}
+	sort.Strings(changes)
+	return changes
+}
+
+// Probes returns the endpoints that became reachable or unreachable from
+// this machine
+func Probes(before, after []probe.Result) []string {
+	previous := make(map[string]probe.Result)
+	for _, result := range before {
+		previous[result.Protocol+"://"+result.Address()] = result
+	}
+
+	var changes []string
+	for _, result := range after {
+		old, ok := previous[result.Protocol+"://"+result.Address()]
+		if !ok || old.Reachable == result.Reachable {
+			continue
+		}
+		if result.Reachable {
+			changes = append(changes, fmt.Sprintf("%s %s://%s reachable again (%s)", result.Resource, result.Protocol, result.Address(), result.Status))
+		} else {
+			changes = append(changes, fmt.Sprintf("%s %s://%s became unreachable (%s)", result.Resource, result.Protocol, result.Address(), result.Status))
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
@@ -0,0 +1,114 @@
+package changes
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/correctedcloud/aws-overview/pkg/alb"
+	"github.com/correctedcloud/aws-overview/pkg/ec2"
+	"github.com/correctedcloud/aws-overview/pkg/ecs"
+	"github.com/correctedcloud/aws-overview/pkg/probe"
+	"github.com/correctedcloud/aws-overview/pkg/rds"
+)
+
+// expectChanges fails the test unless changes are the expected ones
+func expectChanges(t *testing.T, changes, expected []string) {
+	t.Helper()
+	if strings.Join(changes, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected changes\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(changes, "\n"))
+	}
+}
+
+func TestLoadBalancers(t *testing.T) {
+	before := []alb.LoadBalancerSummary{{
+		Name: "web",
+		TargetGroups: []alb.TargetGroupSummary{
+			{Name: "web-http", ARN: "tg-http", Targets: []alb.TargetSummary{
+				{ID: "i-abc", Port: 80, Status: "healthy"},
+				{ID: "i-def", Port: 80, Status: "healthy"},
+			}},
+			{Name: "web-static", ARN: "tg-static", Targets: []alb.TargetSummary{{ID: "i-ghi", Port: 8080, Status: "healthy"}}},
+		},
+	}}
+	after := []alb.LoadBalancerSummary{{
+		Name: "web",
+		TargetGroups: []alb.TargetGroupSummary{
+			{Name: "web-http", ARN: "tg-http", Targets: []alb.TargetSummary{
+				{ID: "i-abc", Port: 80, Status: "unhealthy"},
+				{ID: "i-xyz", Port: 80, Status: "initial"},
+			}},
+			// web-static failed to load
+		},
+	}}
+
+	expectChanges(t, LoadBalancers(before, after), []string{
+		"target i-abc:80 in web-http went unhealthy (was healthy)",
+		"target i-def:80 left web-http",
+		"target i-xyz:80 registered in web-http (initial)",
+	})
+	expectChanges(t, LoadBalancers(after, after), nil)
+}
+
+func TestInstances(t *testing.T) {
+	before := []ec2.InstanceSummary{
+		{InstanceID: "i-1", Name: "web-1", State: "running", SystemStatus: "ok", InstanceStatus: "ok"},
+		{InstanceID: "i-2", State: "running"},
+		{InstanceID: "i-3", Name: "old", State: "terminated"},
+	}
+	after := []ec2.InstanceSummary{
+		{InstanceID: "i-1", Name: "web-1", State: "running", SystemStatus: "impaired", InstanceStatus: "ok"},
+		{InstanceID: "i-2", State: "stopping"},
+		{InstanceID: "i-4", Name: "web-4", State: "pending"},
+	}
+
+	expectChanges(t, Instances(before, after), []string{
+		"instance i-2 went stopping (was running)",
+		"instance old (i-3) is gone",
+		"instance web-1 (i-1) status checks impaired",
+		"instance web-4 (i-4) launched (pending)",
+	})
+}
+
+func TestServices(t *testing.T) {
+	before := []ecs.ServiceSummary{
+		{ServiceName: "web", ClusterName: "prod", DesiredCount: 3, RunningCount: 3, HealthStatus: "HEALTHY"},
+		{ServiceName: "worker", ClusterName: "prod", DesiredCount: 1, RunningCount: 1, HealthStatus: "HEALTHY", DeploymentStatus: "in-progress"},
+		{ServiceName: "cron", ClusterName: "prod"},
+	}
+	after := []ecs.ServiceSummary{
+		{ServiceName: "web", ClusterName: "prod", DesiredCount: 5, RunningCount: 3, HealthStatus: "PARTIAL"},
+		{ServiceName: "worker", ClusterName: "prod", DesiredCount: 1, RunningCount: 1, HealthStatus: "HEALTHY"},
+		{ServiceName: "api", ClusterName: "staging"},
+	}
+
+	expectChanges(t, Services(before, after), []string{
+		"service api created in staging",
+		"service cron deleted from prod",
+		"service web scaled 3→5",
+		"service web went partial (3/5 running)",
+		"service worker deployment completed",
+	})
+}
+
+func TestDBInstances(t *testing.T) {
+	before := []rds.DBInstanceSummary{{Identifier: "orders", Status: "available"}, {Identifier: "old", Status: "deleting"}}
+	after := []rds.DBInstanceSummary{{Identifier: "orders", Status: "modifying"}, {Identifier: "reports", Status: "creating"}}
+
+	expectChanges(t, DBInstances(before, after), []string{
+		"DB instance old deleted",
+		"DB instance orders went modifying (was available)",
+		"DB instance reports created (creating)",
+	})
+}
+
+func TestProbes(t *testing.T) {
+	web := probe.Target{Resource: "web", Host: "web.example.com", Port: 443, Protocol: probe.ProtocolHTTPS}
+	bastion := probe.Target{Resource: "bastion", Host: "3.3.3.3", Port: 22, Protocol: probe.ProtocolTCP}
+	before := []probe.Result{{Target: web, Reachable: true}, {Target: bastion, Status: "timed out"}}
+	after := []probe.Result{{Target: web, Status: "timed out"}, {Target: bastion, Reachable: true, Status: "connected"}}
+
+	expectChanges(t, Probes(before, after), []string{
+		"bastion tcp://3.3.3.3:22 reachable again (connected)",
+		"web https://web.example.com:443 became unreachable (timed out)",
+	})
+}
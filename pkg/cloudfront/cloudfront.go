@@ -0,0 +1,217 @@
+package cloudfront
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// InvalidationCompleted is the status of an invalidation whose paths are
+// no longer served from the edge caches
+const InvalidationCompleted = "Completed"
+
+// cloudfrontClientAPI defines the interface for the CloudFront client
+type cloudfrontClientAPI interface {
+	ListDistributions(ctx context.Context, params *cloudfront.ListDistributionsInput, optFns ...func(*cloudfront.Options)) (*cloudfront.ListDistributionsOutput, error)
+	CreateInvalidation(ctx context.Context, params *cloudfront.CreateInvalidationInput, optFns ...func(*cloudfront.Options)) (*cloudfront.CreateInvalidationOutput, error)
+	GetInvalidation(ctx context.Context, params *cloudfront.GetInvalidationInput, optFns ...func(*cloudfront.Options)) (*cloudfront.GetInvalidationOutput, error)
+}
+
+// Client represents a CloudFront client
+type Client struct {
+	cloudfrontClient cloudfrontClientAPI
+	pool             *common.Pool
+}
+
+// DistributionSummary represents a CloudFront distribution
+type DistributionSummary struct {
+	ID           string
+	DomainName   string   // e.g. d111111abcdef8.cloudfront.net
+	Aliases      []string // Alternate domain names, e.g. cdn.example.com
+	Status       string   // "Deployed", or "InProgress" while a change propagates
+	Enabled      bool
+	Comment      string
+	LastModified time.Time
+}
+
+// Name returns the first alias of the distribution, or its domain name when
+// it has none
+func (d DistributionSummary) Name() string {
+	if len(d.Aliases) > 0 {
+		return d.Aliases[0]
+	}
+	return d.DomainName
+}
+
+// Deploying reports whether a configuration change is still propagating to
+// the edge locations
+func (d DistributionSummary) Deploying() bool {
+	return d.Status == "InProgress"
+}
+
+// InvalidationSummary represents an invalidation of a distribution's cached paths
+type InvalidationSummary struct {
+	ID             string
+	DistributionID string
+	Status         string // "InProgress" or "Completed"
+	Paths          []string
+	CreatedAt      time.Time
+}
+
+// Completed reports whether the invalidation has finished
+func (i InvalidationSummary) Completed() bool {
+	return i.Status == InvalidationCompleted
+}
+
+// NewClient returns a new CloudFront client whose calls run in pool, which may be nil
+func NewClient(cloudfrontClient cloudfrontClientAPI, pool *common.Pool) *Client {
+	return &Client{
+		cloudfrontClient: cloudfrontClient,
+		pool:             pool,
+	}
+}
+
+// GetDistributions returns all distributions sorted by name, following the pagination markers
+func (c *Client) GetDistributions(ctx context.Context) ([]DistributionSummary, error) {
+	var summaries []DistributionSummary
+	var marker *string
+
+	for {
+		var result *cloudfront.ListDistributionsOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.cloudfrontClient.ListDistributions(ctx, &cloudfront.ListDistributionsInput{
+				Marker: marker,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list distributions: %w", err)
+		}
+		if result.DistributionList == nil {
+			break
+		}
+
+		for _, distribution := range result.DistributionList.Items {
+			summaries = append(summaries, newDistributionSummary(distribution))
+		}
+
+		marker = result.DistributionList.NextMarker
+		if !aws.ToBool(result.DistributionList.IsTruncated) || marker == nil {
+			break
+		}
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Name() < summaries[j].Name()
+	})
+
+	return summaries, nil
+}
+
+// newDistributionSummary summarizes a distribution
+func newDistributionSummary(distribution types.DistributionSummary) DistributionSummary {
+	summary := DistributionSummary{
+		ID:           aws.ToString(distribution.Id),
+		DomainName:   aws.ToString(distribution.DomainName),
+		Status:       aws.ToString(distribution.Status),
+		Enabled:      aws.ToBool(distribution.Enabled),
+		Comment:      aws.ToString(distribution.Comment),
+		LastModified: aws.ToTime(distribution.LastModifiedTime),
+	}
+	if distribution.Aliases != nil {
+		summary.Aliases = distribution.Aliases.Items
+	}
+	return summary
+}
+
+// CreateInvalidation invalidates the paths of a distribution. The caller
+// reference is fixed before the first attempt, so a retried call cannot
+// create a second invalidation.
+func (c *Client) CreateInvalidation(ctx context.Context, distributionID string, paths []string) (InvalidationSummary, error) {
+	callerReference := fmt.Sprintf("aws-overview-%d", time.Now().UnixNano())
+
+	var result *cloudfront.CreateInvalidationOutput
+	err := c.pool.Do(ctx, func() (err error) {
+		result, err = c.cloudfrontClient.CreateInvalidation(ctx, &cloudfront.CreateInvalidationInput{
+			DistributionId: aws.String(distributionID),
+			InvalidationBatch: &types.InvalidationBatch{
+				CallerReference: aws.String(callerReference),
+				Paths: &types.Paths{
+					Items:    paths,
+					Quantity: aws.Int32(int32(len(paths))),
+				},
+			},
+		})
+		return err
+	})
+	if err != nil {
+		return InvalidationSummary{}, fmt.Errorf("failed to invalidate distribution %s: %w", distributionID, err)
+	}
+	if result.Invalidation == nil {
+		return InvalidationSummary{}, fmt.Errorf("failed to invalidate distribution %s: no invalidation was created", distributionID)
+	}
+
+	return newInvalidationSummary(*result.Invalidation, distributionID), nil
+}
+
+// GetInvalidation returns the current state of an invalidation
+func (c *Client) GetInvalidation(ctx context.Context, distributionID, invalidationID string) (InvalidationSummary, error) {
+	var result *cloudfront.GetInvalidationOutput
+	err := c.pool.Do(ctx, func() (err error) {
+		result, err = c.cloudfrontClient.GetInvalidation(ctx, &cloudfront.GetInvalidationInput{
+			DistributionId: aws.String(distributionID),
+			Id:             aws.String(invalidationID),
+		})
+		return err
+	})
+	if err != nil {
+		return InvalidationSummary{}, fmt.Errorf("failed to get invalidation %s: %w", invalidationID, err)
+	}
+	if result.Invalidation == nil {
+		return InvalidationSummary{}, fmt.Errorf("failed to get invalidation %s: invalidation not found", invalidationID)
+	}
+
+	return newInvalidationSummary(*result.Invalidation, distributionID), nil
+}
+
+// newInvalidationSummary summarizes an invalidation
+func newInvalidationSummary(invalidation types.Invalidation, distributionID string) InvalidationSummary {
+	summary := InvalidationSummary{
+		ID:             aws.ToString(invalidation.Id),
+		DistributionID: distributionID,
+		Status:         aws.ToString(invalidation.Status),
+		CreatedAt:      aws.ToTime(invalidation.CreateTime),
+	}
+	if invalidation.InvalidationBatch != nil && invalidation.InvalidationBatch.Paths != nil {
+		summary.Paths = invalidation.InvalidationBatch.Paths.Items
+	}
+	return summary
+}
+
+// ParsePaths splits a space-separated list of paths to invalidate, such as
+// "/index.html /assets/*". Each path must start with a slash and may only
+// end with a wildcard.
+func ParsePaths(line string) ([]string, error) {
+	paths := strings.Fields(line)
+	if len(paths) == 0 {
+		return nil, errors.New("enter at least one path, e.g. /*")
+	}
+	for _, path := range paths {
+		if !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("path %q must start with /", path)
+		}
+		if i := strings.Index(path, "*"); i >= 0 && i != len(path)-1 {
+			return nil, fmt.Errorf("path %q may only end with the * wildcard", path)
+		}
+	}
+	return paths, nil
+}
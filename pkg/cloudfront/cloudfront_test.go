@@ -0,0 +1,147 @@
+package cloudfront
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+)
+
+// Mock CloudFront client
+type mockCloudFrontClient struct {
+	pages         [][]types.DistributionSummary
+	invalidations []*cloudfront.CreateInvalidationInput
+	createErrs    []error // Returned by the first calls of CreateInvalidation
+	status        string
+}
+
+func (m *mockCloudFrontClient) ListDistributions(ctx context.Context, params *cloudfront.ListDistributionsInput, optFns ...func(*cloudfront.Options)) (*cloudfront.ListDistributionsOutput, error) {
+	page := 0
+	if params.Marker != nil {
+		page = int((*params.Marker)[0] - '0')
+	}
+	list := &types.DistributionList{
+		Items:       m.pages[page],
+		IsTruncated: aws.Bool(page+1 < len(m.pages)),
+	}
+	if page+1 < len(m.pages) {
+		list.NextMarker = aws.String(string(rune('0' + page + 1)))
+	}
+	return &cloudfront.ListDistributionsOutput{DistributionList: list}, nil
+}
+
+func (m *mockCloudFrontClient) CreateInvalidation(ctx context.Context, params *cloudfront.CreateInvalidationInput, optFns ...func(*cloudfront.Options)) (*cloudfront.CreateInvalidationOutput, error) {
+	m.invalidations = append(m.invalidations, params)
+	if len(m.createErrs) > 0 {
+		err := m.createErrs[0]
+		m.createErrs = m.createErrs[1:]
+		return nil, err
+	}
+	return &cloudfront.CreateInvalidationOutput{Invalidation: &types.Invalidation{
+		Id:                aws.String("I2J0I21PCUYOIK"),
+		Status:            aws.String("InProgress"),
+		CreateTime:        aws.Time(time.Now()),
+		InvalidationBatch: params.InvalidationBatch,
+	}}, nil
+}
+
+func (m *mockCloudFrontClient) GetInvalidation(ctx context.Context, params *cloudfront.GetInvalidationInput, optFns ...func(*cloudfront.Options)) (*cloudfront.GetInvalidationOutput, error) {
+	return &cloudfront.GetInvalidationOutput{Invalidation: &types.Invalidation{
+		Id:     params.Id,
+		Status: aws.String(m.status),
+	}}, nil
+}
+
+func TestGetDistributions(t *testing.T) {
+	client := NewClient(&mockCloudFrontClient{pages: [][]types.DistributionSummary{
+		{{
+			Id:         aws.String("E1"),
+			DomainName: aws.String("d1.cloudfront.net"),
+			Aliases:    &types.Aliases{Items: []string{"www.example.com"}},
+			Status:     aws.String("Deployed"),
+			Enabled:    aws.Bool(true),
+		}},
+		{{
+			Id:         aws.String("E2"),
+			DomainName: aws.String("d2.cloudfront.net"),
+			Status:     aws.String("InProgress"),
+			Enabled:    aws.Bool(true),
+		}},
+	}}, nil)
+
+	distributions, err := client.GetDistributions(context.Background())
+	if err != nil {
+		t.Fatalf("GetDistributions() error = %v", err)
+	}
+	if len(distributions) != 2 {
+		t.Fatalf("Expected 2 distributions across all pages, got %d", len(distributions))
+	}
+	if distributions[0].Name() != "d2.cloudfront.net" || distributions[1].Name() != "www.example.com" {
+		t.Errorf("Expected distributions sorted by alias or domain name, got %+v", distributions)
+	}
+	if !distributions[0].Deploying() || distributions[1].Deploying() {
+		t.Errorf("Expected only E2 to be deploying, got %+v", distributions)
+	}
+}
+
+func TestCreateInvalidation(t *testing.T) {
+	mock := &mockCloudFrontClient{status: InvalidationCompleted}
+	client := NewClient(mock, nil)
+
+	invalidation, err := client.CreateInvalidation(context.Background(), "E1", []string{"/index.html", "/assets/*"})
+	if err != nil {
+		t.Fatalf("CreateInvalidation() error = %v", err)
+	}
+	if invalidation.ID != "I2J0I21PCUYOIK" || invalidation.DistributionID != "E1" || len(invalidation.Paths) != 2 {
+		t.Errorf("Unexpected invalidation %+v", invalidation)
+	}
+	batch := mock.invalidations[0].InvalidationBatch
+	if aws.ToInt32(batch.Paths.Quantity) != 2 || aws.ToString(batch.CallerReference) == "" {
+		t.Errorf("Expected 2 paths and a caller reference, got %+v", batch)
+	}
+
+	invalidation, err = client.GetInvalidation(context.Background(), "E1", invalidation.ID)
+	if err != nil {
+		t.Fatalf("GetInvalidation() error = %v", err)
+	}
+	if !invalidation.Completed() || invalidation.DistributionID != "E1" {
+		t.Errorf("Expected a completed invalidation of E1, got %+v", invalidation)
+	}
+}
+
+func TestCreateInvalidationError(t *testing.T) {
+	client := NewClient(&mockCloudFrontClient{createErrs: []error{errors.New("AccessDenied")}}, nil)
+
+	if _, err := client.CreateInvalidation(context.Background(), "E1", []string{"/*"}); err == nil {
+		t.Error("Expected an error when the invalidation cannot be created")
+	}
+}
+
+func TestParsePaths(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    int
+		wantErr bool
+	}{
+		{"/*", 1, false},
+		{"  /index.html   /assets/* ", 2, false},
+		{"", 0, true},
+		{"index.html", 0, true},
+		{"/assets/*.js", 0, true},
+	}
+
+	for _, tt := range tests {
+		paths, err := ParsePaths(tt.line)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePaths(%q) error = %v, wantErr %v", tt.line, err, tt.wantErr)
+			continue
+		}
+		if len(paths) != tt.want {
+			t.Errorf("ParsePaths(%q) = %v, want %d paths", tt.line, paths, tt.want)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package cloudfront
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// timeNow is the clock the age of invalidations is measured against
+var timeNow = time.Now
+
+// FormatDistributions formats the distributions for terminal display,
+// marking the distribution at index selected (-1 for none)
+func FormatDistributions(summaries []DistributionSummary, selected int) string {
+	if len(summaries) == 0 {
+		return "No CloudFront distributions found"
+	}
+
+	var output strings.Builder
+	output.WriteString("CLOUDFRONT DISTRIBUTIONS\n")
+	output.WriteString(common.Rule("CLOUDFRONT DISTRIBUTIONS", "=") + "\n\n")
+
+	for i, distribution := range summaries {
+		marker := "  "
+		if i == selected {
+			marker = "> "
+		}
+
+		output.WriteString(fmt.Sprintf("%s%s %s (%s)", marker, common.Symbol(getStatusIndicator(distribution)), distribution.Name(), distribution.ID))
+		switch {
+		case !distribution.Enabled:
+			output.WriteString(" [Disabled]")
+		case distribution.Deploying():
+			output.WriteString(" [Deploying]")
+		}
+		output.WriteString("\n")
+
+		// The domain name is only worth repeating below an alias
+		var details []string
+		if len(distribution.Aliases) > 0 {
+			details = append(details, distribution.DomainName)
+		}
+		if len(distribution.Aliases) > 1 {
+			details = append(details, "also "+strings.Join(distribution.Aliases[1:], ", "))
+		}
+		if distribution.Comment != "" {
+			details = append(details, distribution.Comment)
+		}
+		if len(details) > 0 {
+			output.WriteString("     " + strings.Join(details, ", ") + "\n")
+		}
+	}
+
+	return output.String()
+}
+
+// getStatusIndicator returns the emoji of a distribution's status
+func getStatusIndicator(distribution DistributionSummary) string {
+	switch {
+	case !distribution.Enabled:
+		return "⏹"
+	case distribution.Deploying():
+		return "🔄"
+	}
+	return "✅"
+}
+
+// GetDistributionsSummary returns a brief summary of the distributions
+func GetDistributionsSummary(summaries []DistributionSummary) string {
+	deploying, disabled := 0, 0
+	for _, distribution := range summaries {
+		switch {
+		case !distribution.Enabled:
+			disabled++
+		case distribution.Deploying():
+			deploying++
+		}
+	}
+
+	summary := fmt.Sprintf("%d distributions", len(summaries))
+	if deploying > 0 {
+		summary += fmt.Sprintf(", %d deploying", deploying)
+	}
+	if disabled > 0 {
+		summary += fmt.Sprintf(", %d disabled", disabled)
+	}
+	return summary
+}
+
+// FormatInvalidation formats the status and paths of an invalidation
+func FormatInvalidation(invalidation InvalidationSummary) string {
+	title := "INVALIDATION: " + invalidation.ID
+
+	var output strings.Builder
+	output.WriteString(title + "\n")
+	output.WriteString(common.Rule(title, "-") + "\n")
+
+	indicator := "🔄"
+	if invalidation.Completed() {
+		indicator = "✅"
+	}
+	output.WriteString(fmt.Sprintf("%s %s on distribution %s", common.Symbol(indicator), invalidation.Status, invalidation.DistributionID))
+	if !invalidation.CreatedAt.IsZero() {
+		if invalidation.Completed() {
+			output.WriteString(", created at " + invalidation.CreatedAt.Format("15:04:05"))
+		} else {
+			output.WriteString(fmt.Sprintf(", running for %s", timeNow().Sub(invalidation.CreatedAt).Round(time.Second)))
+		}
+	}
+	output.WriteString("\n")
+	output.WriteString(fmt.Sprintf("   Paths: %s\n", strings.Join(invalidation.Paths, " ")))
+
+	return output.String()
+}
@@ -0,0 +1,67 @@
+package cloudfront
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatDistributions(t *testing.T) {
+	summaries := []DistributionSummary{
+		{ID: "E1", DomainName: "d1.cloudfront.net", Aliases: []string{"www.example.com", "example.com"}, Status: "Deployed", Enabled: true, Comment: "Website"},
+		{ID: "E2", DomainName: "d2.cloudfront.net", Status: "InProgress", Enabled: true},
+		{ID: "E3", DomainName: "d3.cloudfront.net", Status: "Deployed"},
+	}
+
+	output := FormatDistributions(summaries, 1)
+
+	for _, expected := range []string{
+		"CLOUDFRONT DISTRIBUTIONS",
+		"  ✅ www.example.com (E1)\n",
+		"     d1.cloudfront.net, also example.com, Website\n",
+		"> 🔄 d2.cloudfront.net (E2) [Deploying]\n",
+		"  ⏹  d3.cloudfront.net (E3) [Disabled]\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain '%s', got:\n%s", expected, output)
+		}
+	}
+
+	if output := FormatDistributions(nil, -1); output != "No CloudFront distributions found" {
+		t.Errorf("Expected an empty message, got '%s'", output)
+	}
+	if summary := GetDistributionsSummary(summaries); summary != "3 distributions, 1 deploying, 1 disabled" {
+		t.Errorf("Unexpected summary '%s'", summary)
+	}
+}
+
+func TestFormatInvalidation(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	oldTimeNow := timeNow
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = oldTimeNow }()
+
+	invalidation := InvalidationSummary{
+		ID:             "I1",
+		DistributionID: "E1",
+		Status:         "InProgress",
+		Paths:          []string{"/index.html", "/assets/*"},
+		CreatedAt:      now.Add(-42 * time.Second),
+	}
+
+	output := FormatInvalidation(invalidation)
+	for _, expected := range []string{
+		"INVALIDATION: I1",
+		"🔄 InProgress on distribution E1, running for 42s\n",
+		"   Paths: /index.html /assets/*\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain '%s', got:\n%s", expected, output)
+		}
+	}
+
+	invalidation.Status = InvalidationCompleted
+	if output := FormatInvalidation(invalidation); !strings.Contains(output, "✅ Completed on distribution E1, created at 11:59:18") {
+		t.Errorf("Expected a completed invalidation, got:\n%s", output)
+	}
+}
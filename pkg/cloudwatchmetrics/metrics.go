@@ -0,0 +1,180 @@
+package cloudwatchmetrics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// cloudwatchClientAPI defines the interface for the CloudWatch client
+type cloudwatchClientAPI interface {
+	GetMetricData(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error)
+}
+
+// MaxQueriesPerCall is the largest number of queries a GetMetricData call accepts
+const MaxQueriesPerCall = 500
+
+// Query describes a metric series to fetch over the window ending now
+type Query struct {
+	Namespace  string
+	MetricName string
+	Dimensions map[string]string
+	Stat       string        // e.g. "Average", "Sum" or "Maximum"
+	Period     time.Duration // Length of each datapoint
+	Window     time.Duration // How far back the series goes
+}
+
+// Result holds the datapoints of a query, oldest first, or the error of the
+// call it was fetched in. A query without datapoints has an empty result.
+type Result struct {
+	Values     []float64
+	Timestamps []time.Time
+	End        time.Time // End of the window, which is the same for all queries of a Fetch
+	Err        error
+}
+
+// Batcher fetches the queries of many resources in as few GetMetricData
+// calls as possible
+type Batcher struct {
+	cloudwatchClient cloudwatchClientAPI
+	pool             *common.Pool
+}
+
+// New returns a batcher whose calls run in pool, which may be nil
+func New(cloudwatchClient cloudwatchClientAPI, pool *common.Pool) *Batcher {
+	return &Batcher{
+		cloudwatchClient: cloudwatchClient,
+		pool:             pool,
+	}
+}
+
+// window identifies queries that can share a call, which has a single time range
+type window struct {
+	period time.Duration
+	length time.Duration
+}
+
+// Fetch returns the results of queries in the same order. Queries over the
+// same window are sent together, up to MaxQueriesPerCall per call, and the
+// calls run in parallel.
+func (b *Batcher) Fetch(ctx context.Context, queries []Query) []Result {
+	results := make([]Result, len(queries))
+
+	// Group the queries by window, keeping their order for stable batches
+	groups := make(map[window][]int)
+	var windows []window
+	for i, query := range queries {
+		w := window{period: query.Period, length: query.Window}
+		if _, ok := groups[w]; !ok {
+			windows = append(windows, w)
+		}
+		groups[w] = append(groups[w], i)
+	}
+
+	end := time.Now()
+	for i := range results {
+		results[i].End = end
+	}
+
+	var wg sync.WaitGroup
+	for _, w := range windows {
+		indexes := groups[w]
+		for start := 0; start < len(indexes); start += MaxQueriesPerCall {
+			batch := indexes[start:min(start+MaxQueriesPerCall, len(indexes))]
+
+			wg.Add(1)
+			go func(batch []int, w window) {
+				defer wg.Done()
+				// Each index is written by a single batch, so no locking is needed
+				b.fetchBatch(ctx, queries, batch, end.Add(-w.length), end, results)
+			}(batch, w)
+		}
+	}
+	wg.Wait()
+
+	return results
+}
+
+// fetchBatch fetches the queries at the given indexes in one call, following
+// the pagination tokens, and stores their results
+func (b *Batcher) fetchBatch(ctx context.Context, queries []Query, batch []int, startTime, endTime time.Time, results []Result) {
+	input := &cloudwatch.GetMetricDataInput{
+		StartTime: aws.Time(startTime),
+		EndTime:   aws.Time(endTime),
+		ScanBy:    cwtypes.ScanByTimestampAscending,
+	}
+
+	// Query IDs must start with a lowercase letter and be unique within a call
+	byID := make(map[string]int, len(batch))
+	for n, i := range batch {
+		id := fmt.Sprintf("q%d", n)
+		byID[id] = i
+		input.MetricDataQueries = append(input.MetricDataQueries, toMetricDataQuery(id, queries[i]))
+	}
+
+	for {
+		var output *cloudwatch.GetMetricDataOutput
+		err := b.pool.Do(ctx, func() (err error) {
+			output, err = b.cloudwatchClient.GetMetricData(ctx, input)
+			return err
+		})
+		if err != nil {
+			for _, i := range batch {
+				results[i] = Result{End: endTime, Err: err}
+			}
+			return
+		}
+
+		for _, series := range output.MetricDataResults {
+			i, ok := byID[aws.ToString(series.Id)]
+			if !ok {
+				continue
+			}
+			results[i].Values = append(results[i].Values, series.Values...)
+			results[i].Timestamps = append(results[i].Timestamps, series.Timestamps...)
+		}
+
+		if output.NextToken == nil {
+			return
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+// toMetricDataQuery converts a query to the GetMetricData form
+func toMetricDataQuery(id string, query Query) cwtypes.MetricDataQuery {
+	names := make([]string, 0, len(query.Dimensions))
+	for name := range query.Dimensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	dimensions := make([]cwtypes.Dimension, 0, len(names))
+	for _, name := range names {
+		dimensions = append(dimensions, cwtypes.Dimension{
+			Name:  aws.String(name),
+			Value: aws.String(query.Dimensions[name]),
+		})
+	}
+
+	return cwtypes.MetricDataQuery{
+		Id: aws.String(id),
+		MetricStat: &cwtypes.MetricStat{
+			Metric: &cwtypes.Metric{
+				Namespace:  aws.String(query.Namespace),
+				MetricName: aws.String(query.MetricName),
+				Dimensions: dimensions,
+			},
+			Period: aws.Int32(int32(query.Period / time.Second)),
+			Stat:   aws.String(query.Stat),
+		},
+	}
+}
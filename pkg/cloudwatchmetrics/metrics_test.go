@@ -0,0 +1,160 @@
+package cloudwatchmetrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// Mock CloudWatch client
+type mockCloudWatchClient struct {
+	mu    sync.Mutex
+	calls []*cloudwatch.GetMetricDataInput
+
+	getMetricDataFunc func(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error)
+}
+
+func (m *mockCloudWatchClient) GetMetricData(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+	m.mu.Lock()
+	copied := *params
+	m.calls = append(m.calls, &copied)
+	m.mu.Unlock()
+	return m.getMetricDataFunc(ctx, params, optFns...)
+}
+
+// echoDimension answers each query with a single datapoint parsed from its
+// "Index" dimension, so results can be matched back to their queries
+func echoDimension(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+	output := &cloudwatch.GetMetricDataOutput{}
+	for _, query := range params.MetricDataQueries {
+		var value float64
+		fmt.Sscan(aws.ToString(query.MetricStat.Metric.Dimensions[0].Value), &value)
+		output.MetricDataResults = append(output.MetricDataResults, cwtypes.MetricDataResult{
+			Id:         query.Id,
+			Values:     []float64{value},
+			Timestamps: []time.Time{*params.StartTime},
+		})
+	}
+	return output, nil
+}
+
+func indexQueries(n int, window time.Duration) []Query {
+	var queries []Query
+	for i := 0; i < n; i++ {
+		queries = append(queries, Query{
+			Namespace:  "AWS/RDS",
+			MetricName: "CPUUtilization",
+			Dimensions: map[string]string{"Index": fmt.Sprint(i)},
+			Stat:       "Average",
+			Period:     5 * time.Minute,
+			Window:     window,
+		})
+	}
+	return queries
+}
+
+func TestFetchBatchesQueries(t *testing.T) {
+	client := &mockCloudWatchClient{getMetricDataFunc: echoDimension}
+
+	// 1,200 queries over one window and 10 over another
+	queries := append(indexQueries(1200, time.Hour), indexQueries(10, 24*time.Hour)...)
+	results := New(client, nil).Fetch(context.Background(), queries)
+
+	if len(client.calls) != 4 {
+		t.Fatalf("Expected 4 calls, got %d", len(client.calls))
+	}
+	sizes := make(map[int]int)
+	for _, call := range client.calls {
+		sizes[len(call.MetricDataQueries)]++
+		if window := call.EndTime.Sub(*call.StartTime); window != time.Hour && window != 24*time.Hour {
+			t.Errorf("Expected the call to cover a query window, got %s", window)
+		}
+	}
+	if sizes[MaxQueriesPerCall] != 2 || sizes[200] != 1 || sizes[10] != 1 {
+		t.Errorf("Expected calls of 500, 500, 200 and 10 queries, got %v", sizes)
+	}
+
+	for i, result := range results {
+		if result.Err != nil || len(result.Values) != 1 {
+			t.Fatalf("Expected a datapoint for query %d, got %v", i, result)
+		}
+		if expected := float64(i % 1200); result.Values[0] != expected {
+			t.Errorf("Expected query %d to get %f, got %f", i, expected, result.Values[0])
+		}
+	}
+}
+
+func TestFetchFollowsNextToken(t *testing.T) {
+	client := &mockCloudWatchClient{
+		getMetricDataFunc: func(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+			if params.NextToken == nil {
+				return &cloudwatch.GetMetricDataOutput{
+					MetricDataResults: []cwtypes.MetricDataResult{{Id: aws.String("q0"), Values: []float64{1, 2}}},
+					NextToken:         aws.String("page-2"),
+				}, nil
+			}
+			return &cloudwatch.GetMetricDataOutput{
+				MetricDataResults: []cwtypes.MetricDataResult{{Id: aws.String("q0"), Values: []float64{3}}},
+			}, nil
+		},
+	}
+
+	results := New(client, nil).Fetch(context.Background(), indexQueries(1, time.Hour))
+
+	if len(results[0].Values) != 3 {
+		t.Errorf("Expected the datapoints of both pages, got %v", results[0].Values)
+	}
+}
+
+func TestFetchReportsFailedCalls(t *testing.T) {
+	client := &mockCloudWatchClient{
+		getMetricDataFunc: func(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+			if params.EndTime.Sub(*params.StartTime) > time.Hour {
+				return nil, errors.New("AccessDenied")
+			}
+			return echoDimension(ctx, params, optFns...)
+		},
+	}
+
+	queries := append(indexQueries(2, time.Hour), indexQueries(2, 24*time.Hour)...)
+	results := New(client, nil).Fetch(context.Background(), queries)
+
+	for i, result := range results {
+		failed := i >= 2
+		if (result.Err != nil) != failed {
+			t.Errorf("Expected query %d to fail: %t, got error %v", i, failed, result.Err)
+		}
+		if failed && len(result.Values) != 0 {
+			t.Errorf("Expected no datapoints for failed query %d, got %v", i, result.Values)
+		}
+		if !result.End.Equal(results[0].End) || result.End.IsZero() {
+			t.Errorf("Expected all queries to share the end of their window, got %s and %s", result.End, results[0].End)
+		}
+	}
+}
+
+func TestToMetricDataQuery(t *testing.T) {
+	query := toMetricDataQuery("q7", Query{
+		Namespace:  "AWS/ApplicationELB",
+		MetricName: "RequestCount",
+		Dimensions: map[string]string{"TargetGroup": "tg", "LoadBalancer": "lb"},
+		Stat:       "Sum",
+		Period:     time.Minute,
+	})
+
+	if aws.ToString(query.Id) != "q7" || aws.ToInt32(query.MetricStat.Period) != 60 {
+		t.Errorf("Unexpected query %v", query)
+	}
+	// Dimensions are sorted by name
+	dimensions := query.MetricStat.Metric.Dimensions
+	if len(dimensions) != 2 || aws.ToString(dimensions[0].Name) != "LoadBalancer" {
+		t.Errorf("Expected sorted dimensions, got %v", dimensions)
+	}
+}
@@ -0,0 +1,48 @@
+package cloudwatchmetrics
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultWindow is how far back the metric graphs go unless chosen otherwise
+const DefaultWindow = time.Hour
+
+// Windows are how far back the metric graphs can go
+var Windows = []time.Duration{time.Hour, 3 * time.Hour, 6 * time.Hour, 24 * time.Hour}
+
+// pointsPerWindow is how many datapoints a graph shows whatever its window
+const pointsPerWindow = 12
+
+// PeriodOf returns the period of the datapoints of a graph over window, so
+// that graphs keep their width: 5 minutes over an hour, 2 hours over a day
+func PeriodOf(window time.Duration) time.Duration {
+	return max(window/pointsPerWindow, time.Minute).Truncate(time.Minute)
+}
+
+// ParseWindow parses one of the Windows, e.g. "6h" or "24h"
+func ParseWindow(s string) (time.Duration, error) {
+	window, err := time.ParseDuration(s)
+	if err == nil {
+		for _, w := range Windows {
+			if w == window {
+				return window, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("invalid metrics window %q, expected 1h, 3h, 6h or 24h", s)
+}
+
+// FormatWindow describes window for the titles of graphs, e.g. "3 hours"
+func FormatWindow(window time.Duration) string {
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	if hours := window / time.Hour; window%time.Hour == 0 {
+		if hours == 1 {
+			return "1 hour"
+		}
+		return fmt.Sprintf("%d hours", hours)
+	}
+	return window.String()
+}
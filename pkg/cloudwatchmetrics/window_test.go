@@ -0,0 +1,43 @@
+package cloudwatchmetrics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPeriodOf(t *testing.T) {
+	for window, expected := range map[time.Duration]time.Duration{
+		time.Hour:       5 * time.Minute,
+		3 * time.Hour:   15 * time.Minute,
+		6 * time.Hour:   30 * time.Minute,
+		24 * time.Hour:  2 * time.Hour,
+		5 * time.Minute: time.Minute,
+	} {
+		if period := PeriodOf(window); period != expected {
+			t.Errorf("Expected a period of %s over %s, got %s", expected, window, period)
+		}
+	}
+}
+
+func TestParseWindow(t *testing.T) {
+	if window, err := ParseWindow("6h"); err != nil || window != 6*time.Hour {
+		t.Errorf("Expected 6h, got %s (%v)", window, err)
+	}
+	for _, s := range []string{"2h", "1d", ""} {
+		if _, err := ParseWindow(s); err == nil {
+			t.Errorf("Expected %q to be rejected", s)
+		}
+	}
+}
+
+func TestFormatWindow(t *testing.T) {
+	for window, expected := range map[time.Duration]string{
+		0:              "1 hour",
+		time.Hour:      "1 hour",
+		24 * time.Hour: "24 hours",
+	} {
+		if got := FormatWindow(window); got != expected {
+			t.Errorf("Expected %q, got %q", expected, got)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package common
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/guptarohit/asciigraph"
+	"github.com/muesli/termenv"
+)
+
+// Series is a labeled data series of a chart
+type Series struct {
+	Label string
+	Data  []float64
+}
+
+// chartColors are the colors of the series of a chart, in order. They read
+// on both dark and light backgrounds.
+var chartColors = []asciigraph.AnsiColor{asciigraph.DodgerBlue, asciigraph.DarkOrange}
+
+// GenerateChart plots related series on one chart to compare them, e.g. the
+// messages sent to a queue against the messages visible in it. The series
+// share the scale, and a legend below the chart names them in their colors,
+// with their minimum, maximum and last value when WithStats is given, and
+// the line of WithThreshold.
+//
+// The braille style draws the series as lines, rather than the areas below
+// them, so they overlay too. The series can only be told apart by color, so
+// without colors (see lipgloss.ColorProfile), and in the blocks style, they
+// are drawn one above the other on the shared scale instead, each with its
+// own label. Series without data are left out, and so are series beyond the
+// second.
+func GenerateChart(series []Series, height int, options ...SparklineOption) string {
+	var plotted []Series
+	for _, s := range series {
+		if len(s.Data) > 0 && len(plotted) < len(chartColors) {
+			plotted = append(plotted, s)
+		}
+	}
+	if len(plotted) == 0 {
+		return "No data available"
+	}
+
+	if height <= 0 {
+		height = 5 // Default height
+	}
+
+	o := collectOptions(options)
+	legendOptions := sparklineOptions{stats: o.stats}
+	labels := make([]string, len(plotted))
+	data := make([][]float64, len(plotted))
+	lowest, highest := minimum(plotted[0].Data), maximum(plotted[0].Data)
+	for i, s := range plotted {
+		labels[i] = annotate(s.Label, s.Data, legendOptions)
+		data[i] = s.Data
+		lowest, highest = min(lowest, minimum(s.Data)), max(highest, maximum(s.Data))
+	}
+
+	if graphStyle == GraphBlocks || lipgloss.ColorProfile() == termenv.Ascii {
+		graphs := make([]string, len(plotted))
+		for i := range plotted {
+			graphs[i] = plot(data[i:i+1], annotate(plotted[i].Label, data[i], o), height, lowest, highest, o)
+		}
+		return strings.Join(graphs, "\n")
+	}
+
+	graph := plot(data, "", height, lowest, highest, o)
+	legend := make([]string, len(plotted))
+	for i, label := range labels {
+		legend[i] = chartColors[i].String() + "■" + asciigraph.Default.String() + " " + label
+	}
+	if o.threshold != nil {
+		legend = append(legend, thresholdColor().String()+"─"+asciigraph.Default.String()+" threshold "+formatStat(*o.threshold))
+	}
+	axis := axisColumn(strings.SplitN(graph, "\n", 2)[0])
+	return graph + "\n" + strings.Repeat(" ", axis+1) + strings.Join(legend, "   ")
+}
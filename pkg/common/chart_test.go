@@ -0,0 +1,105 @@
+package common
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+)
+
+func TestGenerateChart(t *testing.T) {
+	if got := GenerateChart([]Series{{Label: "Sent"}}, 3); got != "No data available" {
+		t.Errorf("Expected 'No data available', got '%s'", got)
+	}
+
+	profile := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	defer lipgloss.SetColorProfile(profile)
+
+	end := time.Date(2024, 5, 10, 14, 30, 0, 0, time.UTC)
+	series := []Series{
+		{Label: "Sent", Data: []float64{10, 20, 30, 40}},
+		{Label: "Visible", Data: []float64{1, 2, 3, 2}},
+		{Label: "Deleted", Data: []float64{5}},
+	}
+	result := GenerateChart(series, 3, WithStats(), WithWindow(end.Add(-time.Hour), end))
+	lines := strings.Split(result, "\n")
+
+	// The plot, the window and the legend on the shared scale
+	if len(lines) != 6 {
+		t.Fatalf("Expected 4 rows, the window and the legend, got:\n%s", result)
+	}
+	if !strings.HasPrefix(lines[0], " 40.00 ") || !strings.HasPrefix(lines[3], "  1.00 ") {
+		t.Errorf("Expected the scale to span both series, got:\n%s", result)
+	}
+	if !strings.Contains(lines[4], "13:30") {
+		t.Errorf("Expected the window below the plot, got %q", lines[4])
+	}
+	legend := ansi.Strip(lines[5])
+	if !strings.Contains(legend, "■ Sent (min 10, max 40, last 40)   ■ Visible (min 1, max 3, last 2)") {
+		t.Errorf("Expected a legend of both series, got %q", legend)
+	}
+	if strings.Contains(legend, "Deleted") {
+		t.Errorf("Expected series beyond the second to be left out, got %q", legend)
+	}
+	if lines[5] == legend {
+		t.Errorf("Expected the legend to be colored, got %q", lines[5])
+	}
+}
+
+func TestGenerateChartWithoutColors(t *testing.T) {
+	profile := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.Ascii)
+	defer lipgloss.SetColorProfile(profile)
+
+	series := []Series{
+		{Label: "Sent", Data: []float64{10, 20, 30, 40}},
+		{Label: "Visible", Data: []float64{1, 2, 3, 2}},
+	}
+	result := GenerateChart(series, 3)
+
+	// One above the other, each labeled and on the shared scale
+	graphs := strings.Split(result, "Sent")
+	if len(graphs) != 2 || !strings.Contains(graphs[1], "Visible") {
+		t.Fatalf("Expected the series one above the other, got:\n%s", result)
+	}
+	if strings.Count(result, " 40.00 ┤") != 2 {
+		t.Errorf("Expected both graphs to share the scale, got:\n%s", result)
+	}
+	if strings.Contains(result, "\x1b[") {
+		t.Errorf("Expected no colors, got %q", result)
+	}
+}
+
+func TestGenerateChartBraille(t *testing.T) {
+	profile := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	defer lipgloss.SetColorProfile(profile)
+	SetGraphStyle(GraphBraille)
+	defer SetGraphStyle(GraphLine)
+
+	series := []Series{
+		{Label: "CPU (%)", Data: []float64{0, 10, 20, 30}},
+		{Label: "Memory (%)", Data: []float64{30, 30, 30, 30}},
+	}
+	result := GenerateChart(series, 2, WithStats())
+	lines := strings.Split(result, "\n")
+
+	// The plot and the legend, the series overlaid as lines
+	if len(lines) != 3 {
+		t.Fatalf("Expected 2 rows and the legend, got:\n%s", result)
+	}
+	if !strings.Contains(result, chartColors[0].String()) || !strings.Contains(result, chartColors[1].String()) {
+		t.Errorf("Expected both series in their colors, got %q", result)
+	}
+	// Memory along the top, which CPU climbs to, and CPU alone below
+	if top, bottom := ansi.Strip(lines[0]), ansi.Strip(lines[1]); !strings.HasSuffix(top, "┤⠉⡝") || !strings.HasSuffix(bottom, "┤⡰⠁") {
+		t.Errorf("Expected the lines of both series, got:\n%s\n%s", top, bottom)
+	}
+	if legend := ansi.Strip(lines[2]); !strings.Contains(legend, "■ CPU (%) (min 0, max 30, last 30)   ■ Memory (%) (min 30, max 30, last 30)") {
+		t.Errorf("Expected a legend of both series, got %q", legend)
+	}
+}
@@ -0,0 +1,237 @@
+package common
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/guptarohit/asciigraph"
+)
+
+// GraphStyle selects how GenerateSparkline draws its graphs
+type GraphStyle string
+
+const (
+	// GraphLine draws a line of box-drawing characters, one data point per column
+	GraphLine GraphStyle = "line"
+	// GraphBraille fills the area below the data with braille dots, two data
+	// points per column and four levels per row
+	GraphBraille GraphStyle = "braille"
+	// GraphBlocks fills the area below the data with half blocks, two levels
+	// per row
+	GraphBlocks GraphStyle = "blocks"
+)
+
+// GraphStyles are the graph styles, the default first
+var GraphStyles = []GraphStyle{GraphLine, GraphBraille, GraphBlocks}
+
+// graphStyle is the style of all graphs, set by SetGraphStyle
+var graphStyle = GraphLine
+
+// SetGraphStyle selects the style of all graphs drawn afterwards. Like the
+// color profile of the terminal, it applies to the whole process, so it
+// should be set once before rendering starts. The empty style is GraphLine.
+func SetGraphStyle(style GraphStyle) {
+	if style == "" {
+		style = GraphLine
+	}
+	graphStyle = style
+}
+
+// ParseGraphStyle returns the graph style named name
+func ParseGraphStyle(name string) (GraphStyle, error) {
+	for _, style := range GraphStyles {
+		if string(style) == name {
+			return style, nil
+		}
+	}
+	return "", fmt.Errorf("unknown graph style %q, expected one of %s", name, joinStyles(GraphStyles))
+}
+
+// joinStyles lists styles separated by commas
+func joinStyles(styles []GraphStyle) string {
+	names := make([]string, len(styles))
+	for i, style := range styles {
+		names[i] = string(style)
+	}
+	return strings.Join(names, ", ")
+}
+
+// brailleDots are the bits of the dots of a braille character by column and
+// by row from the top
+var brailleDots = [2][4]rune{
+	{0x01, 0x02, 0x04, 0x40},
+	{0x08, 0x10, 0x20, 0x80},
+}
+
+// plotDense draws data as the area below it in the braille or blocks style,
+// on the scale from lowest to highest, laid out by layoutDense. A threshold,
+// if not nil, is drawn as a dashed line through the empty cells of its row.
+func plotDense(data []float64, label string, height int, style GraphStyle, lowest, highest float64, threshold *float64) string {
+	// Dots per character horizontally and vertically
+	columns, rows := 1, 2
+	if style == GraphBraille {
+		columns, rows = 2, 4
+	}
+
+	level := denseLevel(height*rows, lowest, highest)
+	filled := make([]int, len(data))
+	for i, value := range data {
+		// Every data point fills at least the lowest level, so the minimum
+		// remains visible
+		filled[i] = 1 + level(value)
+	}
+
+	width := (len(data) + columns - 1) / columns
+	cells := make([][]denseCell, height)
+	for row := range cells {
+		cells[row] = make([]denseCell, width)
+		for column := range cells[row] {
+			cells[row][column].char = cell(filled, column*columns, columns, (height-1-row)*rows, style)
+		}
+	}
+	return layoutDense(cells, label, lowest, highest, thresholdRow(threshold, level, height, rows))
+}
+
+// plotBrailleLines draws series as lines of braille dots on the scale from
+// lowest to highest, two data points per column and four levels per row,
+// laid out by layoutDense. Unlike the area of plotDense, the lines can be
+// told apart where they overlap: each character takes the color of the last
+// series with a dot in it, of colors in order. A threshold, if not nil, is
+// drawn as a dashed line through the empty cells of its row.
+func plotBrailleLines(series [][]float64, height int, lowest, highest float64, colors []asciigraph.AnsiColor, threshold *float64) string {
+	level := denseLevel(height*4, lowest, highest)
+	cells := make([][]denseCell, height)
+	for row := range cells {
+		cells[row] = make([]denseCell, (longest(series)+1)/2)
+		for column := range cells[row] {
+			cells[row][column] = denseCell{char: 0x2800, color: asciigraph.Default}
+		}
+	}
+
+	for i, data := range series {
+		for point, value := range data {
+			// Join each data point to the previous one with the dots
+			// between them in its column
+			from, to := level(value), level(value)
+			if point > 0 {
+				previous := level(data[point-1])
+				if previous < to {
+					from = previous + 1
+				} else if previous > to {
+					to = previous - 1
+				}
+			}
+			for l := from; l <= to; l++ {
+				c := &cells[height-1-l/4][point/2]
+				c.char |= brailleDots[point%2][3-l%4]
+				c.color = colors[i]
+			}
+		}
+	}
+	return layoutDense(cells, "", lowest, highest, thresholdRow(threshold, level, height, 4))
+}
+
+// denseCell is a character of a dense plot in the color of the series it
+// shows, asciigraph.Default for none
+type denseCell struct {
+	char  rune
+	color asciigraph.AnsiColor
+}
+
+// denseLevel returns a function of the level, from 0 to levels-1, of a
+// value on the scale from lowest to highest
+func denseLevel(levels int, lowest, highest float64) func(float64) int {
+	return func(value float64) int {
+		if highest > lowest {
+			return int(math.Round((value - lowest) / (highest - lowest) * float64(levels-1)))
+		}
+		return 0
+	}
+}
+
+// thresholdRow returns the row from the top of a dense plot height rows
+// tall, of levels rows per row, in which threshold lies, or -1 for none
+func thresholdRow(threshold *float64, level func(float64) int, height, rows int) int {
+	if threshold == nil {
+		return -1
+	}
+	return height - 1 - level(*threshold)/rows
+}
+
+// layoutDense lays out the rows of cells of a dense plot like the line
+// graphs: the value of each row on the left of the axis, and the label
+// centered below the plot unless it is empty. The empty cells of the row
+// thresholdRow, unless it is -1, are dashed in thresholdColor.
+func layoutDense(cells [][]denseCell, label string, lowest, highest float64, thresholdRow int) string {
+	height := len(cells)
+	thresholdMark := "┄"
+	if color := thresholdColor(); color != asciigraph.Default {
+		thresholdMark = color.String() + thresholdMark + asciigraph.Default.String()
+	}
+
+	labels := make([]string, height)
+	labelWidth := 0
+	for row := range labels {
+		value := highest
+		if height > 1 {
+			value = highest - (highest-lowest)*float64(row)/float64(height-1)
+		}
+		labels[row] = FormatFloat(value)
+		labelWidth = max(labelWidth, len(labels[row]))
+	}
+
+	width := len(cells[0])
+	var lines []string
+	for row, cells := range cells {
+		var line strings.Builder
+		line.WriteString(fmt.Sprintf(" %*s ┤", labelWidth, labels[row]))
+		for _, c := range cells {
+			switch {
+			case row == thresholdRow && (c.char == ' ' || c.char == 0x2800):
+				line.WriteString(thresholdMark)
+			case c.color != asciigraph.Default:
+				line.WriteString(c.color.String() + string(c.char) + asciigraph.Default.String())
+			default:
+				line.WriteRune(c.char)
+			}
+		}
+		lines = append(lines, strings.TrimRight(line.String(), " "))
+	}
+
+	if label != "" {
+		caption := strings.Repeat(" ", labelWidth+3)
+		if len(label) < width {
+			caption += strings.Repeat(" ", (width-len(label))/2)
+		}
+		lines = append(lines, caption+label)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// cell returns the character of the data points from first on, columns of
+// them, within the levels from bottom to bottom+4 for braille or bottom+2
+// for blocks
+func cell(filled []int, first, columns, bottom int, style GraphStyle) rune {
+	if style != GraphBraille {
+		switch level := filled[first]; {
+		case level >= bottom+2:
+			return '█'
+		case level == bottom+1:
+			return '▄'
+		}
+		return ' '
+	}
+
+	char := rune(0x2800)
+	for column := 0; column < columns && first+column < len(filled); column++ {
+		for dot := 0; dot < 4; dot++ {
+			// Dots are numbered from the top, levels from the bottom
+			if filled[first+column] > bottom+3-dot {
+				char |= brailleDots[column][dot]
+			}
+		}
+	}
+	return char
+}
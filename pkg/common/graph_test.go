@@ -0,0 +1,80 @@
+package common
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateSparklineStyles(t *testing.T) {
+	defer SetGraphStyle(GraphLine)
+
+	tests := []struct {
+		style    GraphStyle
+		expected string
+	}{
+		// The lowest value fills the bottom dot, the highest the column
+		{GraphBraille, "⣸"},
+		{GraphBlocks, "▄█"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.style), func(t *testing.T) {
+			SetGraphStyle(tt.style)
+
+			result := GenerateSparkline([]float64{0, 3}, "Requests", 1)
+			lines := strings.Split(result, "\n")
+			if len(lines) != 2 {
+				t.Fatalf("Expected a row and a caption, got:\n%s", result)
+			}
+			if !strings.HasSuffix(lines[0], "3.00 ┤"+tt.expected) {
+				t.Errorf("Expected the row to end in %q, got %q", tt.expected, lines[0])
+			}
+			if !strings.Contains(lines[1], "Requests") {
+				t.Errorf("Expected the caption, got %q", lines[1])
+			}
+		})
+	}
+}
+
+func TestGenerateSparklineBrailleWindow(t *testing.T) {
+	SetGraphStyle(GraphBraille)
+	defer SetGraphStyle(GraphLine)
+
+	data := make([]float64, 24)
+	for i := range data {
+		data[i] = float64(i % 5)
+	}
+	end := time.Date(2024, 5, 10, 14, 30, 0, 0, time.UTC)
+
+	result := GenerateSparkline(data, "Requests", 2, WithStats(), WithWindow(end.Add(-time.Hour), end))
+	lines := strings.Split(result, "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected 2 rows, the window and the caption, got:\n%s", result)
+	}
+
+	// Two data points per column
+	axis := axisColumn(lines[0])
+	if got := len([]rune(lines[0])) - axis - 1; got != len(data)/2 {
+		t.Errorf("Expected %d columns, got %d", len(data)/2, got)
+	}
+	window := lines[2]
+	if !strings.HasPrefix(window, strings.Repeat(" ", axis+1)+"13:30") || !strings.HasSuffix(window, "14:30") {
+		t.Errorf("Expected the window edges below the plot, got %q", window)
+	}
+	if !strings.Contains(lines[3], "Requests (min 0, max 4, last 3)") {
+		t.Errorf("Expected the stats in the caption, got %q", lines[3])
+	}
+}
+
+func TestParseGraphStyle(t *testing.T) {
+	for _, style := range GraphStyles {
+		if got, err := ParseGraphStyle(string(style)); err != nil || got != style {
+			t.Errorf("Expected %s, got %s, %v", style, got, err)
+		}
+	}
+
+	if _, err := ParseGraphStyle("dots"); err == nil || !strings.Contains(err.Error(), "line, braille, blocks") {
+		t.Errorf("Expected an error listing the styles, got %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package common
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+)
+
+// DefaultMaxConcurrency is the number of AWS calls a pool runs at once when
+// no limit is configured
+const DefaultMaxConcurrency = 10
+
+// Pool limits how many AWS calls the collectors run at once and retries
+// throttled calls with exponential backoff. A single pool is meant to be
+// shared by all collectors, so that their calls count against one limit.
+// A nil *Pool runs calls directly without limit or retries.
+type Pool struct {
+	slots       chan struct{}
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+}
+
+// NewPool returns a pool running at most maxConcurrency calls at once, or
+// DefaultMaxConcurrency when maxConcurrency is not positive
+func NewPool(maxConcurrency int) *Pool {
+	if maxConcurrency <= 0 {
+		maxConcurrency = DefaultMaxConcurrency
+	}
+	return &Pool{
+		slots:       make(chan struct{}, maxConcurrency),
+		maxAttempts: 5,
+		baseDelay:   500 * time.Millisecond,
+		maxDelay:    10 * time.Second,
+	}
+}
+
+// WithoutRetries makes the pool run each call once, for clients whose SDK
+// retryer already retries throttled calls. Retrying them again here would
+// multiply the attempts of both.
+func (p *Pool) WithoutRetries() *Pool {
+	p.maxAttempts = 1
+	return p
+}
+
+// Do runs call once a slot is free. When the call is throttled it is retried
+// with exponential backoff, releasing its slot while waiting. Do returns the
+// error of the last attempt, or the context error if ctx ends while waiting
+// for a slot.
+func (p *Pool) Do(ctx context.Context, call func() error) error {
+	if p == nil {
+		return call()
+	}
+
+	delay := p.baseDelay
+	for attempt := 1; ; attempt++ {
+		select {
+		case p.slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		err := call()
+		<-p.slots
+
+		if err == nil || attempt >= p.maxAttempts || !IsThrottlingError(err) {
+			return err
+		}
+
+		// Wait between half and the full delay so that throttled callers spread out
+		wait := delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return err
+		}
+
+		delay *= 2
+		if delay > p.maxDelay {
+			delay = p.maxDelay
+		}
+	}
+}
+
+// IsThrottlingError reports whether err is an AWS rate limiting error, such as
+// ThrottlingException or RequestLimitExceeded
+func IsThrottlingError(err error) bool {
+	return retry.IsErrorThrottles(retry.DefaultThrottles).IsErrorThrottle(err) == aws.TrueTernary
+}
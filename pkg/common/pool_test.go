@@ -0,0 +1,104 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/smithy-go"
+)
+
+func TestPoolLimitsConcurrency(t *testing.T) {
+	pool := NewPool(2)
+
+	var running, peak int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = pool.Do(context.Background(), func() error {
+				current := atomic.AddInt32(&running, 1)
+				for {
+					previous := atomic.LoadInt32(&peak)
+					if current <= previous || atomic.CompareAndSwapInt32(&peak, previous, current) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("Expected at most 2 concurrent calls, got %d", peak)
+	}
+}
+
+func TestPoolRetriesThrottledCalls(t *testing.T) {
+	pool := NewPool(1)
+	pool.baseDelay = time.Millisecond
+
+	throttled := &smithy.GenericAPIError{Code: "ThrottlingException", Message: "Rate exceeded"}
+
+	calls := 0
+	err := pool.Do(context.Background(), func() error {
+		calls++
+		if calls < 3 {
+			return throttled
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected the call to succeed after retries, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 attempts, got %d", calls)
+	}
+
+	// Other errors are returned without retrying
+	calls = 0
+	denied := &smithy.GenericAPIError{Code: "AccessDenied"}
+	if err := pool.Do(context.Background(), func() error { calls++; return denied }); !errors.Is(err, denied) {
+		t.Errorf("Expected the AccessDenied error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected a single attempt for a non-throttling error, got %d", calls)
+	}
+
+	// Throttling that persists gives up after maxAttempts
+	calls = 0
+	if err := pool.Do(context.Background(), func() error { calls++; return throttled }); !errors.Is(err, throttled) {
+		t.Errorf("Expected the throttling error, got %v", err)
+	}
+	if calls != pool.maxAttempts {
+		t.Errorf("Expected %d attempts, got %d", pool.maxAttempts, calls)
+	}
+}
+
+func TestPoolWithoutRetries(t *testing.T) {
+	pool := NewPool(1).WithoutRetries()
+
+	calls := 0
+	throttled := &smithy.GenericAPIError{Code: "ThrottlingException", Message: "Rate exceeded"}
+	if err := pool.Do(context.Background(), func() error { calls++; return throttled }); !errors.Is(err, throttled) {
+		t.Errorf("Expected the throttling error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected a single attempt, got %d", calls)
+	}
+}
+
+func TestNilPool(t *testing.T) {
+	var pool *Pool
+	called := false
+	if err := pool.Do(context.Background(), func() error { called = true; return nil }); err != nil || !called {
+		t.Errorf("Expected a nil pool to run the call directly, got called=%v err=%v", called, err)
+	}
+}
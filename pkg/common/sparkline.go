@@ -2,11 +2,58 @@ package common
 
 import (
 	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/guptarohit/asciigraph"
+	"github.com/muesli/termenv"
 )
 
-// GenerateSparkline creates a simple ASCII sparkline from data points
-func GenerateSparkline(data []float64, label string, height int) string {
+// SparklineOption adds an annotation to a sparkline
+type SparklineOption func(*sparklineOptions)
+
+// sparklineOptions holds the annotations of a sparkline
+type sparklineOptions struct {
+	stats      bool
+	start, end time.Time
+	threshold  *float64
+}
+
+// WithStats notes the minimum, maximum and last value after the label, so
+// the scale does not have to be read off the axis
+func WithStats() SparklineOption {
+	return func(o *sparklineOptions) {
+		o.stats = true
+	}
+}
+
+// WithWindow labels the left and right edges of the sparkline with the start
+// and end of the window its data covers. The end is usually the time the
+// data was fetched, e.g. cloudwatchmetrics.Result.End; a zero end adds no
+// labels, e.g. for data restored from a session saved without it.
+func WithWindow(start, end time.Time) SparklineOption {
+	return func(o *sparklineOptions) {
+		o.start, o.end = start, end
+	}
+}
+
+// WithThreshold draws a red line across the sparkline at value, e.g. the
+// level above which a metric is flagged, and notes it after the label. The
+// scale extends to the threshold when the data stays clear of it.
+func WithThreshold(value float64) SparklineOption {
+	return func(o *sparklineOptions) {
+		o.threshold = &value
+	}
+}
+
+// GenerateSparkline creates a simple ASCII sparkline from data points, drawn
+// in the style selected by SetGraphStyle
+func GenerateSparkline(data []float64, label string, height int, options ...SparklineOption) string {
 	if len(data) == 0 {
 		return "No data available"
 	}
@@ -15,11 +62,235 @@ func GenerateSparkline(data []float64, label string, height int) string {
 		height = 5 // Default height
 	}
 
-	return asciigraph.Plot(
-		data,
-		asciigraph.Height(height),
-		asciigraph.Caption(label),
-	)
+	o := collectOptions(options)
+	return plot([][]float64{data}, annotate(label, data, o), height, minimum(data), maximum(data), o)
+}
+
+// detailHeight is the height of the graphs drawn by GenerateDetailGraph
+const detailHeight = 10
+
+// GenerateDetailGraph draws data like GenerateSparkline, but taller and
+// stretched across width columns, for a closer look at a metric in a
+// drill-down view. Data with more points than fit is drawn one point per
+// column, as by GenerateSparkline.
+func GenerateDetailGraph(data []float64, label string, width int, options ...SparklineOption) string {
+	if len(data) == 0 {
+		return "No data available"
+	}
+	return GenerateSparkline(stretch(data, detailColumns(data, width, options)), label, detailHeight, options...)
+}
+
+// GenerateDetailChart draws series like GenerateChart, but taller and
+// stretched across width columns, for a closer look in a drill-down view
+func GenerateDetailChart(series []Series, width int, options ...SparklineOption) string {
+	var all []float64
+	for _, s := range series {
+		all = append(all, s.Data...)
+	}
+	if len(all) == 0 {
+		return "No data available"
+	}
+
+	columns := detailColumns(all, width, options)
+	stretched := make([]Series, len(series))
+	for i, s := range series {
+		stretched[i] = Series{Label: s.Label, Data: stretch(s.Data, columns)}
+	}
+	return GenerateChart(stretched, detailHeight, options...)
+}
+
+// detailColumns returns the number of data points a detail graph of data
+// fits in width columns right of its y-axis, whose labels depend on the
+// scale of data
+func detailColumns(data []float64, width int, options []SparklineOption) int {
+	probe := GenerateSparkline([]float64{minimum(data), maximum(data)}, "", detailHeight, options...)
+	columns := width - axisColumn(strings.SplitN(probe, "\n", 2)[0]) - 1
+	if graphStyle == GraphBraille {
+		// Two data points per column
+		columns *= 2
+	}
+	return columns
+}
+
+// stretch interpolates data linearly to n points. Each data point keeps
+// its value at the point closest to its place, so the first, last, lowest
+// and highest value remain. Data of n points or more is returned as is.
+func stretch(data []float64, n int) []float64 {
+	if len(data) == 0 || len(data) >= n {
+		return data
+	}
+	if len(data) == 1 {
+		return slices.Repeat(data, n)
+	}
+
+	result := make([]float64, n)
+	at := func(i int) int { return int(math.Round(float64(i) * float64(n-1) / float64(len(data)-1))) }
+	for i := range data[:len(data)-1] {
+		from, to := at(i), at(i+1)
+		for point := from; point <= to; point++ {
+			result[point] = data[i] + (data[i+1]-data[i])*float64(point-from)/float64(to-from)
+		}
+	}
+	return result
+}
+
+// collectOptions applies options to the default annotations
+func collectOptions(options []SparklineOption) sparklineOptions {
+	var o sparklineOptions
+	for _, option := range options {
+		option(&o)
+	}
+	return o
+}
+
+// annotate notes after label the minimum, maximum and last value of data,
+// which must not be empty, when o asks for them, and the threshold of o
+func annotate(label string, data []float64, o sparklineOptions) string {
+	var notes []string
+	if o.stats {
+		notes = append(notes, "min "+formatStat(minimum(data)), "max "+formatStat(maximum(data)), "last "+formatStat(data[len(data)-1]))
+	}
+	if o.threshold != nil {
+		notes = append(notes, "threshold "+formatStat(*o.threshold))
+	}
+	if len(notes) == 0 {
+		return label
+	}
+	return label + " (" + strings.Join(notes, ", ") + ")"
+}
+
+// plot draws series on the scale from lowest to highest in the style
+// selected by SetGraphStyle, with label below them and the window of o, if
+// any, between the two. An empty label adds no line. The series after the
+// first are drawn in the chartColors by the line and braille styles, the
+// latter as lines without a label, and left out by the blocks style. The
+// line style draws the threshold of o in thresholdColor too.
+func plot(series [][]float64, label string, height int, lowest, highest float64, o sparklineOptions) string {
+	if o.threshold != nil {
+		lowest, highest = math.Min(lowest, *o.threshold), math.Max(highest, *o.threshold)
+	}
+
+	// The line graphs draw the first data point on the axis, the dense ones
+	// right of it
+	var graph string
+	width, offset := longest(series), 0
+	switch graphStyle {
+	case GraphBraille:
+		if len(series) > 1 {
+			graph = plotBrailleLines(series, height, lowest, highest, chartColors[:len(series)], o.threshold)
+		} else {
+			graph = plotDense(series[0], label, height, graphStyle, lowest, highest, o.threshold)
+		}
+		width, offset = (width+1)/2, 1
+	case GraphBlocks:
+		graph = plotDense(series[0], label, height, graphStyle, lowest, highest, o.threshold)
+		width, offset = len(series[0]), 1
+	default:
+		options := []asciigraph.Option{
+			asciigraph.Height(height),
+			asciigraph.Caption(label),
+			asciigraph.LowerBound(lowest),
+			asciigraph.UpperBound(highest),
+		}
+		colors := chartColors[:len(series)]
+		if o.threshold != nil {
+			line := make([]float64, width)
+			for i := range line {
+				line[i] = *o.threshold
+			}
+			if len(series) == 1 {
+				colors = []asciigraph.AnsiColor{asciigraph.Default}
+			}
+			// Leave the series of the caller as they are
+			series = append(series[:len(series):len(series)], line)
+			colors = append(colors, thresholdColor())
+		}
+		if len(series) > 1 {
+			options = append(options, asciigraph.SeriesColors(colors...))
+		}
+		graph = asciigraph.PlotMany(series, options...)
+	}
+	if o.end.IsZero() {
+		return graph
+	}
+
+	// Put the window between the plot and its label, which is the last line
+	lines := strings.Split(graph, "\n")
+	window := windowLine(axisColumn(lines[0])+offset, width, o.start, o.end)
+	if label == "" {
+		return strings.Join(append(lines, window), "\n")
+	}
+	lines = append(lines[:len(lines)-1], window, lines[len(lines)-1])
+	return strings.Join(lines, "\n")
+}
+
+// thresholdColor returns the color of threshold lines, none when the
+// terminal has no colors
+func thresholdColor() asciigraph.AnsiColor {
+	if lipgloss.ColorProfile() == termenv.Ascii {
+		return asciigraph.Default
+	}
+	return asciigraph.Red
+}
+
+// longest returns the length of the longest of series
+func longest(series [][]float64) int {
+	result := 0
+	for _, data := range series {
+		result = max(result, len(data))
+	}
+	return result
+}
+
+// axisColumn returns the column of the y-axis of a plotted line, where the
+// first data point is drawn
+func axisColumn(line string) int {
+	for _, axis := range []string{"┤", "┼"} {
+		if i := strings.Index(line, axis); i >= 0 {
+			// The colors of the series take no columns
+			return utf8.RuneCountInString(ansi.Strip(line[:i]))
+		}
+	}
+	return 0
+}
+
+// windowLine labels the first and last column of a plot of width data
+// points starting at column axis with the start and end of its window
+func windowLine(axis, width int, start, end time.Time) string {
+	layout := "15:04"
+	if end.Sub(start) >= 24*time.Hour {
+		layout = "Jan 2 15:04"
+	}
+	first, last := start.Format(layout), end.Format(layout)
+
+	gap := max(1, width-len(first)-len(last))
+	return strings.Repeat(" ", axis) + first + strings.Repeat(" ", gap) + last
+}
+
+// formatStat formats a value of a sparkline, without decimals when it is whole
+func formatStat(value float64) string {
+	if value == math.Trunc(value) && math.Abs(value) < 1e15 {
+		return FormatFloatWithPrecision(value, 0)
+	}
+	return FormatFloat(value)
+}
+
+// minimum returns the smallest of values, which must not be empty
+func minimum(values []float64) float64 {
+	result := values[0]
+	for _, value := range values[1:] {
+		result = math.Min(result, value)
+	}
+	return result
+}
+
+// maximum returns the largest of values, which must not be empty
+func maximum(values []float64) float64 {
+	result := values[0]
+	for _, value := range values[1:] {
+		result = math.Max(result, value)
+	}
+	return result
 }
 
 // FormatPercentage formats a value as a percentage string
@@ -1,8 +1,15 @@
 package common
 
 import (
+	"slices"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/guptarohit/asciigraph"
+	"github.com/muesli/termenv"
 )
 
 func TestGenerateSparkline(t *testing.T) {
@@ -33,6 +40,112 @@ func TestGenerateSparkline(t *testing.T) {
 	}
 }
 
+func TestGenerateSparklineAnnotations(t *testing.T) {
+	data := []float64{4, 1.5, 9, 2, 3, 5, 6, 7, 8, 3, 2, 6}
+	end := time.Date(2024, 5, 10, 14, 30, 0, 0, time.UTC)
+
+	result := GenerateSparkline(data, "Requests", 3, WithStats(), WithWindow(end.Add(-time.Hour), end))
+	lines := strings.Split(result, "\n")
+
+	caption := lines[len(lines)-1]
+	if !strings.Contains(caption, "Requests (min 1.50, max 9, last 6)") {
+		t.Errorf("Expected the caption to note min, max and last, got %q", caption)
+	}
+
+	window := lines[len(lines)-2]
+	axis := axisColumn(lines[0])
+	if !strings.HasPrefix(window, strings.Repeat(" ", axis)+"13:30") || !strings.HasSuffix(window, "14:30") {
+		t.Errorf("Expected the window edges below the plot, got %q", window)
+	}
+	if got := len(window) - axis; got != len(data) {
+		t.Errorf("Expected the window labels to span the %d data points, got %d columns", len(data), got)
+	}
+
+	// Windows of a day or more show the dates
+	result = GenerateSparkline(data, "Requests", 3, WithWindow(end.Add(-24*time.Hour), end))
+	if !strings.Contains(result, "May 9 14:30") || !strings.Contains(result, "May 10 14:30") {
+		t.Errorf("Expected dated window edges, got:\n%s", result)
+	}
+
+	// A zero end adds no labels
+	plain := GenerateSparkline(data, "Requests", 3)
+	if result := GenerateSparkline(data, "Requests", 3, WithWindow(time.Time{}, time.Time{})); result != plain {
+		t.Errorf("Expected no window labels without an end, got:\n%s", result)
+	}
+}
+
+func TestGenerateSparklineThreshold(t *testing.T) {
+	profile := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	defer lipgloss.SetColorProfile(profile)
+
+	data := []float64{1, 2, 3, 2}
+	result := GenerateSparkline(data, "CPU (%)", 3, WithStats(), WithThreshold(10))
+	lines := strings.Split(result, "\n")
+
+	// The scale extends to the threshold, whose line is red
+	if !strings.HasPrefix(ansi.Strip(lines[0]), " 10.00 ") || !strings.Contains(lines[0], asciigraph.Red.String()) {
+		t.Errorf("Expected a red threshold line on top, got:\n%s", result)
+	}
+	if caption := lines[len(lines)-1]; !strings.Contains(caption, "CPU (%) (min 1, max 3, last 2, threshold 10)") {
+		t.Errorf("Expected the caption to note the threshold, got %q", caption)
+	}
+	if data[len(data)-1] != 2 {
+		t.Errorf("Expected the data to be left as is, got %v", data)
+	}
+
+	// Dense styles dash the threshold through the empty cells of its row
+	SetGraphStyle(GraphBlocks)
+	defer SetGraphStyle(GraphLine)
+	result = ansi.Strip(GenerateSparkline([]float64{0, 4}, "CPU (%)", 2, WithThreshold(4)))
+	if lines := strings.Split(result, "\n"); !strings.HasSuffix(lines[0], "┤┄█") {
+		t.Errorf("Expected the threshold dashed beside the data, got:\n%s", result)
+	}
+}
+
+func TestGenerateDetailGraph(t *testing.T) {
+	if got := GenerateDetailGraph(nil, "CPU", 80); got != "No data available" {
+		t.Errorf("Expected 'No data available', got '%s'", got)
+	}
+
+	data := []float64{4, 1.5, 9, 2, 3, 6}
+	result := GenerateDetailGraph(data, "CPU (%)", 60, WithStats())
+	lines := strings.Split(result, "\n")
+	if len(lines) != detailHeight+2 {
+		t.Fatalf("Expected %d rows and the caption, got:\n%s", detailHeight+1, result)
+	}
+	widest := 0
+	for _, line := range lines {
+		widest = max(widest, len([]rune(ansi.Strip(line))))
+	}
+	if widest < 55 || widest > 60 {
+		t.Errorf("Expected the graph to stretch across 60 columns, got %d:\n%s", widest, result)
+	}
+	// Stretching keeps the data points
+	if !strings.Contains(lines[len(lines)-1], "CPU (%) (min 1.50, max 9, last 6)") {
+		t.Errorf("Expected the stats of the data, got %q", lines[len(lines)-1])
+	}
+}
+
+func TestStretch(t *testing.T) {
+	tests := []struct {
+		data     []float64
+		n        int
+		expected []float64
+	}{
+		{[]float64{0, 4}, 5, []float64{0, 1, 2, 3, 4}},
+		{[]float64{0, 2, 0}, 5, []float64{0, 1, 2, 1, 0}},
+		{[]float64{3}, 3, []float64{3, 3, 3}},
+		{[]float64{1, 2, 3}, 2, []float64{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		if got := stretch(tt.data, tt.n); !slices.Equal(got, tt.expected) {
+			t.Errorf("stretch(%v, %d) = %v, expected %v", tt.data, tt.n, got, tt.expected)
+		}
+	}
+}
+
 func TestFormatPercentage(t *testing.T) {
 	testCases := []struct {
 		value    float64
@@ -0,0 +1,64 @@
+package common
+
+import "strings"
+
+// asciiFallbacks maps the emoji used by the formatters to two-cell ASCII
+// replacements, so that columns stay aligned when they are swapped in
+var asciiFallbacks = map[rune]string{
+	'✅': "OK",
+	'❌': "XX",
+	'❓': "??",
+	'⚠': "!!",
+	'🚨': "!!",
+	'🔄': "<>",
+	'🔍': "..",
+	'🟢': "+ ",
+	'🟠': "~ ",
+	'🔴': "- ",
+	'⚪': "o ",
+	'⏹': "[]",
+	'⚙': "* ",
+	'🔧': "* ",
+	'🔒': "# ",
+	'🗑': "x ",
+	'🧹': "% ",
+	'🔑': "k ",
+	'💾': "db",
+	'🌐': "@ ",
+	'🖥': "> ",
+	'🚀': "> ",
+	'📋': "- ",
+	'📬': "@ ",
+	'⚡': "f ",
+	'⏱': "t ",
+	'⏳': "..",
+	'🐢': "v ",
+	'💡': "i ",
+}
+
+// regionalIndicatorA is the first of the letters that make up flag emoji,
+// e.g. "🇺🇸" is the regional indicators U and S
+const regionalIndicatorA = '\U0001F1E6'
+
+// ASCIISymbols replaces the emoji in s with ASCII fallbacks for terminals
+// whose fonts cannot render them, such as the classic Windows console.
+// Flags become their two-letter country code.
+func ASCIISymbols(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for _, r := range s {
+		switch {
+		case r == emojiPresentationSelector || r == zeroWidthJoiner:
+			continue
+		case r >= regionalIndicatorA && r <= regionalIndicatorA+25:
+			sb.WriteRune('A' + r - regionalIndicatorA)
+		default:
+			if fallback, ok := asciiFallbacks[r]; ok {
+				sb.WriteString(fallback)
+			} else {
+				sb.WriteRune(r)
+			}
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,28 @@
+package common
+
+import "testing"
+
+func TestASCIISymbols(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"✅ Load Balancers: 2", "OK Load Balancers: 2"},
+		{"⚠️ STUCK", "!! STUCK"},
+		{"Region: 🇺🇸 us-east-1", "Region: US us-east-1"},
+		{"← → Navigate • q Quit", "← → Navigate • q Quit"},
+		{"plain", "plain"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			got := ASCIISymbols(tc.input)
+			if got != tc.expected {
+				t.Errorf("Expected '%s', got '%s'", tc.expected, got)
+			}
+			if DisplayWidth(got) != DisplayWidth(tc.input) {
+				t.Errorf("Expected width %d to be kept, got %d", DisplayWidth(tc.input), DisplayWidth(got))
+			}
+		})
+	}
+}
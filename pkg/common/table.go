@@ -0,0 +1,42 @@
+package common
+
+import "sort"
+
+// Column describes a column of a sortable table of T
+type Column[T any] struct {
+	Title string
+	Width int
+	Value func(T) string
+	// Less orders rows when sorting by the column. Nil compares the values
+	// as strings.
+	Less func(a, b T) bool
+}
+
+// SortRows returns a copy of rows sorted by the column at index key, keeping
+// the order of rows the column considers equal
+func SortRows[T any](rows []T, columns []Column[T], key int) []T {
+	sorted := append([]T(nil), rows...)
+	if key < 0 || key >= len(columns) {
+		return sorted
+	}
+
+	column := columns[key]
+	less := column.Less
+	if less == nil {
+		less = func(a, b T) bool { return column.Value(a) < column.Value(b) }
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return less(sorted[i], sorted[j]) })
+	return sorted
+}
+
+// TableCells returns the values of each column for each row
+func TableCells[T any](rows []T, columns []Column[T]) [][]string {
+	cells := make([][]string, len(rows))
+	for i, row := range rows {
+		cells[i] = make([]string, len(columns))
+		for j, column := range columns {
+			cells[i][j] = column.Value(row)
+		}
+	}
+	return cells
+}
@@ -0,0 +1,47 @@
+package common
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+type tableRow struct {
+	name  string
+	count int
+}
+
+var tableColumns = []Column[tableRow]{
+	{Title: "Name", Value: func(r tableRow) string { return r.name }},
+	{
+		Title: "Count",
+		Value: func(r tableRow) string { return strconv.Itoa(r.count) },
+		Less:  func(a, b tableRow) bool { return a.count > b.count },
+	},
+}
+
+func TestSortRows(t *testing.T) {
+	rows := []tableRow{{"b", 2}, {"c", 10}, {"a", 2}}
+
+	byName := SortRows(rows, tableColumns, 0)
+	if got := []string{byName[0].name, byName[1].name, byName[2].name}; !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Errorf("Expected rows sorted by name, got %v", got)
+	}
+
+	// Equal counts keep their order
+	byCount := SortRows(rows, tableColumns, 1)
+	if got := []string{byCount[0].name, byCount[1].name, byCount[2].name}; !reflect.DeepEqual(got, []string{"c", "b", "a"}) {
+		t.Errorf("Expected rows sorted by count, got %v", got)
+	}
+
+	if rows[0].name != "b" {
+		t.Error("Expected the rows passed in to stay unsorted")
+	}
+}
+
+func TestTableCells(t *testing.T) {
+	cells := TableCells([]tableRow{{"a", 1}}, tableColumns)
+	if !reflect.DeepEqual(cells, [][]string{{"a", "1"}}) {
+		t.Errorf("Unexpected cells %v", cells)
+	}
+}
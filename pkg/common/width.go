@@ -0,0 +1,87 @@
+package common
+
+import (
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// emojiPresentationSelector (VS16) asks the terminal to render the preceding
+// character as a double-width emoji, e.g. "⚠️" or "🖥️"
+const emojiPresentationSelector = '\uFE0F'
+
+// zeroWidthJoiner glues emoji into a single glyph, e.g. "👩‍💻"
+const zeroWidthJoiner = '\u200D'
+
+// DisplayWidth returns the number of terminal cells needed to display s.
+// Unlike len or utf8.RuneCountInString it accounts for wide CJK characters,
+// zero-width joiners and emoji presentation selectors.
+func DisplayWidth(s string) int {
+	width := 0
+	prevWidth := 0
+	joined := false
+	for _, r := range s {
+		if r == zeroWidthJoiner {
+			joined = true
+			continue
+		}
+		if joined {
+			// The joined character shares the glyph of the one before it
+			joined = false
+			continue
+		}
+		if r == emojiPresentationSelector {
+			// Most terminals render the selected character as a wide emoji
+			if prevWidth == 1 {
+				width++
+				prevWidth = 2
+			}
+			continue
+		}
+		prevWidth = runewidth.RuneWidth(r)
+		width += prevWidth
+	}
+	return width
+}
+
+// PadRight pads s with spaces until it occupies width terminal cells
+func PadRight(s string, width int) string {
+	padding := width - DisplayWidth(s)
+	if padding <= 0 {
+		return s
+	}
+	return s + strings.Repeat(" ", padding)
+}
+
+// Truncate shortens s to at most width terminal cells, ending it with an
+// ellipsis when it has to be cut
+func Truncate(s string, width int) string {
+	if DisplayWidth(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+
+	// Measure the growing prefix so that selectors and joiners are
+	// accounted for together with the character they modify
+	truncated := ""
+	for i := range s {
+		if DisplayWidth(s[:i]) > width-1 {
+			break
+		}
+		truncated = s[:i]
+	}
+	return truncated + "…"
+}
+
+// Symbol pads a status symbol to a fixed two-cell column so that the text
+// following it lines up regardless of how wide the symbol itself is
+func Symbol(symbol string) string {
+	return PadRight(symbol, 2)
+}
+
+// Rule returns a horizontal rule of ch as wide as the displayed text s
+func Rule(s string, ch string) string {
+	return strings.Repeat(ch, DisplayWidth(s))
+}
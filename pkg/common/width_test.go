@@ -0,0 +1,82 @@
+package common
+
+import (
+	"testing"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		expected int
+	}{
+		{"ascii", "abc", 3},
+		{"empty", "", 0},
+		{"CJK", "日本", 4},
+		{"wide emoji", "✅", 2},
+		{"emoji with presentation selector", "⚠️", 2},
+		{"flag", "🇺🇸", 2},
+		{"zero-width joiner sequence", "👩‍💻", 2},
+		{"mixed", "⏹️ db-1", 7},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := DisplayWidth(tc.value); got != tc.expected {
+				t.Errorf("Expected width %d for %q, got %d", tc.expected, tc.value, got)
+			}
+		})
+	}
+}
+
+func TestPadRight(t *testing.T) {
+	testCases := []struct {
+		value    string
+		width    int
+		expected string
+	}{
+		{"ab", 4, "ab  "},
+		{"日本", 5, "日本 "},
+		{"⚠️", 2, "⚠️"},
+		{"toolong", 3, "toolong"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			if got := PadRight(tc.value, tc.width); got != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	testCases := []struct {
+		value    string
+		width    int
+		expected string
+	}{
+		{"hello", 10, "hello"},
+		{"hello world", 5, "hell…"},
+		{"日本語テキスト", 5, "日本…"},
+		{"anything", 0, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			got := Truncate(tc.value, tc.width)
+			if got != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+			if DisplayWidth(got) > tc.width && tc.width > 0 {
+				t.Errorf("Truncated value %q is wider than %d cells", got, tc.width)
+			}
+		})
+	}
+}
+
+func TestRule(t *testing.T) {
+	if got := Rule("🚀 Cluster: 本番", "-"); got != "----------------" {
+		t.Errorf("Expected rule matching display width, got %q", got)
+	}
+}
@@ -0,0 +1,88 @@
+// Package console builds links to resources in the AWS Management Console
+// and opens them in the default browser.
+package console
+
+import (
+	"fmt"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// host returns the console of the partition of region, regional for the
+// commercial partition so that the link does not redirect
+func host(region string) string {
+	switch {
+	case strings.HasPrefix(region, "us-gov-"):
+		return "https://console.amazonaws-us-gov.com"
+	case strings.HasPrefix(region, "cn-"):
+		return "https://console.amazonaws.cn"
+	}
+	return "https://" + region + ".console.aws.amazon.com"
+}
+
+// link returns the link to the page of service in region with fragment, the
+// part after # that the single-page consoles route on
+func link(region, path, fragment string) string {
+	u := fmt.Sprintf("%s/%s?region=%s", host(region), path, url.QueryEscape(region))
+	if fragment != "" {
+		u += "#" + fragment
+	}
+	return u
+}
+
+// EC2Instance returns the link to the details of an EC2 instance
+func EC2Instance(region, instanceID string) string {
+	return link(region, "ec2/home", "InstanceDetails:instanceId="+instanceID)
+}
+
+// ECSService returns the link to the health of an ECS service
+func ECSService(region, cluster, service string) string {
+	return link(region, "ecs/v2/clusters/"+url.PathEscape(cluster)+"/services/"+url.PathEscape(service)+"/health", "")
+}
+
+// LoadBalancer returns the link to the details of an Elastic Load Balancing
+// v2 load balancer
+func LoadBalancer(region, arn string) string {
+	return link(region, "ec2/home", "LoadBalancer:loadBalancerArn="+arn)
+}
+
+// DBInstance returns the link to the details of an RDS DB instance
+func DBInstance(region, identifier string) string {
+	return link(region, "rds/home", "database:id="+identifier+";is-cluster=false")
+}
+
+// Queue returns the link to the details of an SQS queue
+func Queue(region, queueURL string) string {
+	return link(region, "sqs/v3/home", "/queues/"+url.QueryEscape(queueURL))
+}
+
+// Function returns the link to the details of a Lambda function
+func Function(region, name string) string {
+	return link(region, "lambda/home", "/functions/"+url.PathEscape(name))
+}
+
+// Distribution returns the link to the details of a CloudFront distribution.
+// CloudFront is global, its console lives in us-east-1.
+func Distribution(id string) string {
+	return host("us-east-1") + "/cloudfront/v4/home#/distributions/" + id
+}
+
+// Open opens link in the default browser without waiting for it
+func Open(link string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", link)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", link)
+	default:
+		cmd = exec.Command("xdg-open", link)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to open the browser: %w", err)
+	}
+	go cmd.Wait()
+	return nil
+}
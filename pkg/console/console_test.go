@@ -0,0 +1,35 @@
+package console
+
+import "testing"
+
+func TestLinks(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"EC2 instance", EC2Instance("eu-west-1", "i-0a1b2c3d"),
+			"https://eu-west-1.console.aws.amazon.com/ec2/home?region=eu-west-1#InstanceDetails:instanceId=i-0a1b2c3d"},
+		{"ECS service", ECSService("us-east-1", "production", "web"),
+			"https://us-east-1.console.aws.amazon.com/ecs/v2/clusters/production/services/web/health?region=us-east-1"},
+		{"load balancer", LoadBalancer("us-east-1", "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/web/50dc6c495c0c9188"),
+			"https://us-east-1.console.aws.amazon.com/ec2/home?region=us-east-1#LoadBalancer:loadBalancerArn=arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/web/50dc6c495c0c9188"},
+		{"DB instance", DBInstance("us-east-1", "orders-db"),
+			"https://us-east-1.console.aws.amazon.com/rds/home?region=us-east-1#database:id=orders-db;is-cluster=false"},
+		{"queue", Queue("us-east-1", "https://sqs.us-east-1.amazonaws.com/123456789012/orders"),
+			"https://us-east-1.console.aws.amazon.com/sqs/v3/home?region=us-east-1#/queues/https%3A%2F%2Fsqs.us-east-1.amazonaws.com%2F123456789012%2Forders"},
+		{"function", Function("us-east-1", "resize"),
+			"https://us-east-1.console.aws.amazon.com/lambda/home?region=us-east-1#/functions/resize"},
+		{"distribution", Distribution("E2QWRUHAPOMQZL"),
+			"https://us-east-1.console.aws.amazon.com/cloudfront/v4/home#/distributions/E2QWRUHAPOMQZL"},
+		{"GovCloud", EC2Instance("us-gov-west-1", "i-1"),
+			"https://console.amazonaws-us-gov.com/ec2/home?region=us-gov-west-1#InstanceDetails:instanceId=i-1"},
+		{"China", DBInstance("cn-north-1", "db"),
+			"https://console.amazonaws.cn/rds/home?region=cn-north-1#database:id=db;is-cluster=false"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s link = %s, want %s", tt.name, tt.got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package cost
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// costexplorerClientAPI defines the interface for the Cost Explorer client
+type costexplorerClientAPI interface {
+	GetCostAndUsage(ctx context.Context, params *costexplorer.GetCostAndUsageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageOutput, error)
+}
+
+// Metric is the Cost Explorer metric of the spend, as in the billing console
+const Metric = "UnblendedCost"
+
+// TrendDays is how many days the daily trend covers, ending today
+const TrendDays = 30
+
+// dateLayout is the format of Cost Explorer dates
+const dateLayout = "2006-01-02"
+
+// timeNow returns the current time, replaced in tests
+var timeNow = time.Now
+
+// Client represents a Cost Explorer client. Every Cost Explorer request is
+// billed, so the client makes one per page of results.
+type Client struct {
+	costexplorerClient costexplorerClientAPI
+	pool               *common.Pool
+}
+
+// ServiceCost is the month-to-date spend of a service
+type ServiceCost struct {
+	Name   string
+	Amount float64
+}
+
+// Summary is the spend of the account
+type Summary struct {
+	MonthStart  time.Time     // First day of the month, in UTC like Cost Explorer
+	Currency    string        // e.g. "USD"
+	MonthToDate float64       // Spend since MonthStart, including today so far
+	Services    []ServiceCost // Spend since MonthStart by service, the most expensive first
+	TrendStart  time.Time     // First day of Daily
+	Daily       []float64     // Spend of each of the last TrendDays days, oldest first
+	Estimated   bool          // Whether the spend of recent days is still an estimate
+}
+
+// NewClient returns a new Cost Explorer client whose calls run in pool,
+// which may be nil
+func NewClient(costexplorerClient costexplorerClientAPI, pool *common.Pool) *Client {
+	return &Client{
+		costexplorerClient: costexplorerClient,
+		pool:               pool,
+	}
+}
+
+// GetSummary returns the month-to-date spend by service and the daily spend
+// of the last TrendDays days. Both come from the same daily, per service
+// query, which covers whichever of the month and the trend starts earlier.
+func (c *Client) GetSummary(ctx context.Context) (Summary, error) {
+	today := timeNow().UTC().Truncate(24 * time.Hour)
+	summary := Summary{
+		MonthStart: time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC),
+		TrendStart: today.AddDate(0, 0, 1-TrendDays),
+		Daily:      make([]float64, TrendDays),
+	}
+	start := summary.TrendStart
+	if summary.MonthStart.Before(start) {
+		start = summary.MonthStart
+	}
+
+	input := &costexplorer.GetCostAndUsageInput{
+		TimePeriod: &types.DateInterval{
+			Start: aws.String(start.Format(dateLayout)),
+			End:   aws.String(today.AddDate(0, 0, 1).Format(dateLayout)), // Exclusive
+		},
+		Granularity: types.GranularityDaily,
+		Metrics:     []string{Metric},
+		GroupBy: []types.GroupDefinition{{
+			Type: types.GroupDefinitionTypeDimension,
+			Key:  aws.String("SERVICE"),
+		}},
+	}
+
+	services := make(map[string]float64)
+	for {
+		var result *costexplorer.GetCostAndUsageOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.costexplorerClient.GetCostAndUsage(ctx, input)
+			return err
+		})
+		if err != nil {
+			return Summary{}, fmt.Errorf("failed to get cost and usage: %w", err)
+		}
+
+		for _, period := range result.ResultsByTime {
+			day, err := time.Parse(dateLayout, aws.ToString(period.TimePeriod.Start))
+			if err != nil {
+				return Summary{}, fmt.Errorf("unexpected cost period %q: %w", aws.ToString(period.TimePeriod.Start), err)
+			}
+			summary.Estimated = summary.Estimated || period.Estimated
+
+			for _, group := range period.Groups {
+				metric, ok := group.Metrics[Metric]
+				if !ok || len(group.Keys) == 0 {
+					continue
+				}
+				amount, err := strconv.ParseFloat(aws.ToString(metric.Amount), 64)
+				if err != nil {
+					return Summary{}, fmt.Errorf("unexpected cost amount %q: %w", aws.ToString(metric.Amount), err)
+				}
+				if summary.Currency == "" {
+					summary.Currency = aws.ToString(metric.Unit)
+				}
+
+				if index := int(day.Sub(summary.TrendStart) / (24 * time.Hour)); !day.Before(summary.TrendStart) && index < TrendDays {
+					summary.Daily[index] += amount
+				}
+				if !day.Before(summary.MonthStart) {
+					services[group.Keys[0]] += amount
+					summary.MonthToDate += amount
+				}
+			}
+		}
+
+		if result.NextPageToken == nil {
+			break
+		}
+		input.NextPageToken = result.NextPageToken
+	}
+
+	for name, amount := range services {
+		summary.Services = append(summary.Services, ServiceCost{Name: name, Amount: amount})
+	}
+	sort.Slice(summary.Services, func(i, j int) bool {
+		if summary.Services[i].Amount != summary.Services[j].Amount {
+			return summary.Services[i].Amount > summary.Services[j].Amount
+		}
+		return summary.Services[i].Name < summary.Services[j].Name
+	})
+
+	return summary, nil
+}
@@ -0,0 +1,134 @@
+package cost
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+)
+
+// Mock Cost Explorer client
+type mockCostExplorerClient struct {
+	getCostAndUsageFunc func(ctx context.Context, params *costexplorer.GetCostAndUsageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageOutput, error)
+}
+
+func (m *mockCostExplorerClient) GetCostAndUsage(ctx context.Context, params *costexplorer.GetCostAndUsageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageOutput, error) {
+	return m.getCostAndUsageFunc(ctx, params, optFns...)
+}
+
+// costDay returns the spend of services on day, as Cost Explorer does
+func costDay(day string, services map[string]string) types.ResultByTime {
+	result := types.ResultByTime{TimePeriod: &types.DateInterval{Start: aws.String(day)}}
+	for name, amount := range services {
+		result.Groups = append(result.Groups, types.Group{
+			Keys:    []string{name},
+			Metrics: map[string]types.MetricValue{Metric: {Amount: aws.String(amount), Unit: aws.String("USD")}},
+		})
+	}
+	return result
+}
+
+func TestGetSummary(t *testing.T) {
+	timeNow = func() time.Time { return time.Date(2024, 5, 2, 15, 0, 0, 0, time.UTC) }
+	defer func() { timeNow = time.Now }()
+
+	calls := 0
+	client := NewClient(&mockCostExplorerClient{
+		getCostAndUsageFunc: func(ctx context.Context, params *costexplorer.GetCostAndUsageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageOutput, error) {
+			calls++
+			if start, end := aws.ToString(params.TimePeriod.Start), aws.ToString(params.TimePeriod.End); start != "2024-04-03" || end != "2024-05-03" {
+				t.Errorf("Expected the 30 days up to today, got %s to %s", start, end)
+			}
+			if params.Granularity != types.GranularityDaily || aws.ToString(params.GroupBy[0].Key) != "SERVICE" {
+				t.Errorf("Expected the daily spend by service, got %+v", params)
+			}
+
+			if params.NextPageToken == nil {
+				return &costexplorer.GetCostAndUsageOutput{
+					ResultsByTime: []types.ResultByTime{
+						costDay("2024-04-03", map[string]string{"Amazon Relational Database Service": "50"}),
+						costDay("2024-05-01", map[string]string{"Amazon Relational Database Service": "10.5", "AWS Lambda": "0.25"}),
+					},
+					NextPageToken: aws.String("next"),
+				}, nil
+			}
+			today := costDay("2024-05-02", map[string]string{"Amazon Relational Database Service": "4", "Amazon Elastic Compute Cloud - Compute": "12"})
+			today.Estimated = true
+			return &costexplorer.GetCostAndUsageOutput{ResultsByTime: []types.ResultByTime{today}}, nil
+		},
+	}, nil)
+
+	summary, err := client.GetSummary(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected both pages to be fetched, got %d calls", calls)
+	}
+
+	if summary.MonthToDate != 26.75 || summary.Currency != "USD" || !summary.Estimated {
+		t.Errorf("Expected $26.75 estimated month to date, got %+v", summary)
+	}
+	expected := []ServiceCost{
+		{Name: "Amazon Relational Database Service", Amount: 14.5},
+		{Name: "Amazon Elastic Compute Cloud - Compute", Amount: 12},
+		{Name: "AWS Lambda", Amount: 0.25},
+	}
+	if len(summary.Services) != len(expected) {
+		t.Fatalf("Expected %d services, got %+v", len(expected), summary.Services)
+	}
+	for i := range expected {
+		if summary.Services[i] != expected[i] {
+			t.Errorf("Expected service %d to be %+v, got %+v", i, expected[i], summary.Services[i])
+		}
+	}
+
+	if len(summary.Daily) != TrendDays || summary.Daily[0] != 50 || summary.Daily[28] != 10.75 || summary.Daily[29] != 16 {
+		t.Errorf("Expected the daily totals of the last 30 days, got %v", summary.Daily)
+	}
+}
+
+func TestGetSummaryCoversTheMonth(t *testing.T) {
+	timeNow = func() time.Time { return time.Date(2024, 7, 31, 23, 0, 0, 0, time.UTC) }
+	defer func() { timeNow = time.Now }()
+
+	client := NewClient(&mockCostExplorerClient{
+		getCostAndUsageFunc: func(ctx context.Context, params *costexplorer.GetCostAndUsageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageOutput, error) {
+			if start := aws.ToString(params.TimePeriod.Start); start != "2024-07-01" {
+				t.Errorf("Expected the query to start with the month, got %s", start)
+			}
+			return &costexplorer.GetCostAndUsageOutput{ResultsByTime: []types.ResultByTime{
+				costDay("2024-07-01", map[string]string{"AWS Lambda": "3"}),
+				costDay("2024-07-31", map[string]string{"AWS Lambda": "1"}),
+			}}, nil
+		},
+	}, nil)
+
+	summary, err := client.GetSummary(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if summary.MonthToDate != 4 {
+		t.Errorf("Expected the whole month, got %v", summary.MonthToDate)
+	}
+	if summary.Daily[TrendDays-1] != 1 || summary.TrendStart.Format(dateLayout) != "2024-07-02" {
+		t.Errorf("Expected the trend to leave out July 1, got %v from %v", summary.Daily, summary.TrendStart)
+	}
+}
+
+func TestGetSummaryError(t *testing.T) {
+	client := NewClient(&mockCostExplorerClient{
+		getCostAndUsageFunc: func(ctx context.Context, params *costexplorer.GetCostAndUsageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageOutput, error) {
+			return nil, errors.New("AccessDeniedException: not authorized to perform ce:GetCostAndUsage")
+		},
+	}, nil)
+
+	if _, err := client.GetSummary(context.Background()); err == nil || !strings.Contains(err.Error(), "failed to get cost and usage") {
+		t.Errorf("Expected the Cost Explorer error, got %v", err)
+	}
+}
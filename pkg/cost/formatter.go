@@ -0,0 +1,104 @@
+package cost
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// topServices is how many services FormatSummary lists by name
+const topServices = 10
+
+// FormatSummary formats the spend for terminal display: the month-to-date
+// total, the daily trend and the most expensive services of the month
+func FormatSummary(summary Summary) string {
+	var output strings.Builder
+	output.WriteString("COSTS\n")
+	output.WriteString(common.Rule("COSTS", "=") + "\n\n")
+
+	output.WriteString(fmt.Sprintf("Month to date (since %s UTC): %s\n", summary.MonthStart.Format("Jan 2"), FormatAmount(summary.MonthToDate, summary.Currency)))
+	if summary.Estimated {
+		output.WriteString("The spend of the last days is an estimate until AWS finalizes it.\n")
+	}
+
+	output.WriteString(fmt.Sprintf("\nDaily spend (%d days):\n", len(summary.Daily)))
+	if len(summary.Daily) > 0 {
+		window := common.WithWindow(summary.TrendStart, summary.TrendStart.AddDate(0, 0, len(summary.Daily)-1))
+		output.WriteString(common.GenerateSparkline(summary.Daily, "Daily Spend ("+currencyName(summary.Currency)+")", 5, common.WithStats(), window) + "\n")
+	} else {
+		output.WriteString("  No daily data available\n")
+	}
+
+	output.WriteString("\nBy service, month to date:\n")
+	if len(summary.Services) == 0 {
+		output.WriteString("  No spend this month\n")
+		return output.String()
+	}
+
+	shown := summary.Services[:min(len(summary.Services), topServices)]
+	nameWidth, amountWidth := 0, 0
+	for _, service := range shown {
+		nameWidth = max(nameWidth, len(service.Name))
+		amountWidth = max(amountWidth, len(FormatAmount(service.Amount, summary.Currency)))
+	}
+	for _, service := range shown {
+		output.WriteString(fmt.Sprintf("  %-*s  %*s  %s\n", nameWidth, service.Name, amountWidth, FormatAmount(service.Amount, summary.Currency), formatShare(service.Amount, summary.MonthToDate)))
+	}
+	if rest := len(summary.Services) - len(shown); rest > 0 {
+		output.WriteString(fmt.Sprintf("  ... and %d more services\n", rest))
+	}
+
+	return output.String()
+}
+
+// GetCostSummary returns a brief summary of the spend
+func GetCostSummary(summary Summary) string {
+	text := FormatAmount(summary.MonthToDate, summary.Currency) + " month to date"
+	if len(summary.Services) > 0 {
+		top := summary.Services[0]
+		text += fmt.Sprintf(", most on %s (%s)", top.Name, FormatAmount(top.Amount, summary.Currency))
+	}
+	return text
+}
+
+// FormatAmount formats an amount of money, e.g. "$1,234.56" or "12.30 EUR"
+func FormatAmount(amount float64, currency string) string {
+	cents := int64(amount*100 + 0.5)
+	if amount < 0 {
+		cents = int64(amount*100 - 0.5)
+	}
+	sign := ""
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+
+	// Group the whole units by thousands
+	whole := strconv.FormatInt(cents/100, 10)
+	for i := len(whole) - 3; i > 0; i -= 3 {
+		whole = whole[:i] + "," + whole[i:]
+	}
+	formatted := fmt.Sprintf("%s.%02d", whole, cents%100)
+
+	if currency == "USD" || currency == "" {
+		return sign + "$" + formatted
+	}
+	return sign + formatted + " " + currency
+}
+
+// currencyName returns the unit of the amounts, "USD" when unknown
+func currencyName(currency string) string {
+	if currency == "" {
+		return "USD"
+	}
+	return currency
+}
+
+// formatShare formats the share of amount in total, e.g. "(42%)"
+func formatShare(amount, total float64) string {
+	if total <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("(%.0f%%)", amount/total*100)
+}
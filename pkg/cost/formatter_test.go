@@ -0,0 +1,74 @@
+package cost
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		currency string
+		expected string
+	}{
+		{0, "USD", "$0.00"},
+		{1234.567, "USD", "$1,234.57"},
+		{1234567.8, "", "$1,234,567.80"},
+		{-12.5, "USD", "-$12.50"},
+		{12.3, "EUR", "12.30 EUR"},
+	}
+	for _, test := range tests {
+		if got := FormatAmount(test.amount, test.currency); got != test.expected {
+			t.Errorf("FormatAmount(%v, %q) = %q, expected %q", test.amount, test.currency, got, test.expected)
+		}
+	}
+}
+
+func TestFormatSummary(t *testing.T) {
+	daily := make([]float64, TrendDays)
+	for i := range daily {
+		daily[i] = float64(10 + i%3)
+	}
+	services := []ServiceCost{{Name: "Amazon Relational Database Service", Amount: 150}}
+	for i := 0; i < topServices+1; i++ {
+		services = append(services, ServiceCost{Name: "AWS Lambda", Amount: 5})
+	}
+	summary := Summary{
+		MonthStart:  time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		Currency:    "USD",
+		MonthToDate: 205,
+		Services:    services,
+		TrendStart:  time.Date(2024, 4, 3, 0, 0, 0, 0, time.UTC),
+		Daily:       daily,
+		Estimated:   true,
+	}
+
+	output := FormatSummary(summary)
+	for _, expected := range []string{
+		"COSTS",
+		"Month to date (since May 1 UTC): $205.00",
+		"is an estimate",
+		"Daily spend (30 days):",
+		"Daily Spend (USD)",
+		"Amazon Relational Database Service  $150.00  (73%)",
+		"... and 2 more services",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+
+	if got := GetCostSummary(summary); got != "$205.00 month to date, most on Amazon Relational Database Service ($150.00)" {
+		t.Errorf("Unexpected summary %q", got)
+	}
+}
+
+func TestFormatSummaryWithoutSpend(t *testing.T) {
+	output := FormatSummary(Summary{MonthStart: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)})
+	for _, expected := range []string{"$0.00", "No daily data available", "No spend this month"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
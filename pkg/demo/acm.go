@@ -0,0 +1,83 @@
+package demo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/aws/smithy-go"
+)
+
+// demoCertificate is a fixture ACM certificate
+type demoCertificate struct {
+	id         string
+	domain     string
+	sans       []string
+	valid      time.Duration // Until the certificate expires
+	imported   bool
+	validation types.DomainStatus // Of the domains of ACM issued certificates
+}
+
+var certificates = []demoCertificate{
+	{"6f3b2a1c-0d4e-4b5f-9a8b-7c6d5e4f3a21", "www.example.com", []string{"www.example.com", "example.com"}, 190 * 24 * time.Hour, false, types.DomainStatusSuccess},
+	// Imported, so ACM does not renew it before it expires in twelve days
+	{"9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d", "shop.example.com", []string{"shop.example.com"}, 12 * 24 * time.Hour, true, ""},
+	{"2c1d0e9f-8a7b-4c6d-9e5f-4a3b2c1d0e9f", "api.internal.example.com", []string{"api.internal.example.com"}, 300 * 24 * time.Hour, false, types.DomainStatusSuccess},
+}
+
+// ACM is a fixture Certificate Manager API
+type ACM struct{}
+
+// NewACM returns a fixture Certificate Manager API
+func NewACM() *ACM {
+	return &ACM{}
+}
+
+// certificateARN returns the ARN of the fixture certificate for domain
+func certificateARN(domain string) string {
+	for _, certificate := range certificates {
+		if certificate.domain == domain {
+			return fmt.Sprintf("arn:aws:acm:%s:%s:certificate/%s", Region, AccountID, certificate.id)
+		}
+	}
+	return ""
+}
+
+// DescribeCertificate returns a fixture certificate
+func (a *ACM) DescribeCertificate(ctx context.Context, params *acm.DescribeCertificateInput, optFns ...func(*acm.Options)) (*acm.DescribeCertificateOutput, error) {
+	for _, certificate := range certificates {
+		if aws.ToString(params.CertificateArn) != certificateARN(certificate.domain) {
+			continue
+		}
+
+		notAfter := timeNow().Add(certificate.valid).Truncate(time.Hour)
+		detail := &types.CertificateDetail{
+			CertificateArn:          params.CertificateArn,
+			DomainName:              aws.String(certificate.domain),
+			SubjectAlternativeNames: certificate.sans,
+			NotBefore:               aws.Time(notAfter.Add(-395 * 24 * time.Hour)),
+			NotAfter:                aws.Time(notAfter),
+			Status:                  types.CertificateStatusIssued,
+			Type:                    types.CertificateTypeAmazonIssued,
+			RenewalEligibility:      types.RenewalEligibilityEligible,
+		}
+		if certificate.imported {
+			detail.Type = types.CertificateTypeImported
+			detail.RenewalEligibility = types.RenewalEligibilityIneligible
+		}
+		for _, domain := range certificate.sans {
+			if certificate.validation != "" {
+				detail.DomainValidationOptions = append(detail.DomainValidationOptions, types.DomainValidation{
+					DomainName:       aws.String(domain),
+					ValidationMethod: types.ValidationMethodDns,
+					ValidationStatus: certificate.validation,
+				})
+			}
+		}
+		return &acm.DescribeCertificateOutput{Certificate: detail}, nil
+	}
+	return nil, &smithy.GenericAPIError{Code: "ResourceNotFoundException", Message: fmt.Sprintf("Could not find certificate %s.", aws.ToString(params.CertificateArn))}
+}
@@ -0,0 +1,116 @@
+package demo
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/apigateway"
+	resttypes "github.com/aws/aws-sdk-go-v2/service/apigateway/types"
+	"github.com/aws/aws-sdk-go-v2/service/apigatewayv2"
+	httptypes "github.com/aws/aws-sdk-go-v2/service/apigatewayv2/types"
+)
+
+// APIGateway is a fixture API Gateway API of REST APIs, the checkout API
+// returning 5xx responses
+type APIGateway struct{}
+
+// NewAPIGateway returns a fixture API Gateway API
+func NewAPIGateway() *APIGateway {
+	return &APIGateway{}
+}
+
+// GetRestApis returns the fixture REST APIs
+func (a *APIGateway) GetRestApis(ctx context.Context, params *apigateway.GetRestApisInput, optFns ...func(*apigateway.Options)) (*apigateway.GetRestApisOutput, error) {
+	return &apigateway.GetRestApisOutput{
+		Items: []resttypes.RestApi{
+			{
+				Id:                    aws.String("k3x9a1b2c4"),
+				Name:                  aws.String("checkout"),
+				EndpointConfiguration: &resttypes.EndpointConfiguration{Types: []resttypes.EndpointType{resttypes.EndpointTypeRegional}},
+				CreatedDate:           ago(400 * 24 * time.Hour),
+			},
+			{
+				Id:                    aws.String("p7q2r5s8t1"),
+				Name:                  aws.String("partners"),
+				EndpointConfiguration: &resttypes.EndpointConfiguration{Types: []resttypes.EndpointType{resttypes.EndpointTypeEdge}},
+				CreatedDate:           ago(900 * 24 * time.Hour),
+			},
+		},
+	}, nil
+}
+
+// GetStages returns a production and a staging stage for checkout, whose
+// payment method has a lower limit, and a production stage for partners
+func (a *APIGateway) GetStages(ctx context.Context, params *apigateway.GetStagesInput, optFns ...func(*apigateway.Options)) (*apigateway.GetStagesOutput, error) {
+	if aws.ToString(params.RestApiId) != "k3x9a1b2c4" {
+		return &apigateway.GetStagesOutput{Item: []resttypes.Stage{{
+			StageName:       aws.String("prod"),
+			DeploymentId:    aws.String("d4e5f6"),
+			LastUpdatedDate: ago(61 * 24 * time.Hour),
+		}}}, nil
+	}
+
+	return &apigateway.GetStagesOutput{Item: []resttypes.Stage{
+		{
+			StageName:       aws.String("prod"),
+			DeploymentId:    aws.String("a1b2c3"),
+			LastUpdatedDate: ago(5 * time.Hour),
+			MethodSettings: map[string]resttypes.MethodSetting{
+				"*/*":           {ThrottlingRateLimit: 500, ThrottlingBurstLimit: 1000, MetricsEnabled: true},
+				"payments/POST": {ThrottlingRateLimit: 50, ThrottlingBurstLimit: 100},
+			},
+		},
+		{
+			StageName:       aws.String("staging"),
+			DeploymentId:    aws.String("b7c8d9"),
+			LastUpdatedDate: ago(40 * time.Minute),
+			MethodSettings: map[string]resttypes.MethodSetting{
+				"*/*": {ThrottlingRateLimit: 20, ThrottlingBurstLimit: 40},
+			},
+		},
+	}}, nil
+}
+
+// APIGatewayV2 is a fixture API Gateway V2 API with an HTTP API and a
+// WebSocket API
+type APIGatewayV2 struct{}
+
+// NewAPIGatewayV2 returns a fixture API Gateway V2 API
+func NewAPIGatewayV2() *APIGatewayV2 {
+	return &APIGatewayV2{}
+}
+
+// GetApis returns the fixture HTTP and WebSocket APIs
+func (a *APIGatewayV2) GetApis(ctx context.Context, params *apigatewayv2.GetApisInput, optFns ...func(*apigatewayv2.Options)) (*apigatewayv2.GetApisOutput, error) {
+	return &apigatewayv2.GetApisOutput{
+		Items: []httptypes.Api{
+			{
+				ApiId:        aws.String("w8h2k5m3n6"),
+				Name:         aws.String("webhooks"),
+				ProtocolType: httptypes.ProtocolTypeHttp,
+				ApiEndpoint:  aws.String("https://w8h2k5m3n6.execute-api." + Region + ".amazonaws.com"),
+			},
+			{
+				ApiId:        aws.String("s4t6u8v1x3"),
+				Name:         aws.String("notifications"),
+				ProtocolType: httptypes.ProtocolTypeWebsocket,
+				ApiEndpoint:  aws.String("wss://s4t6u8v1x3.execute-api." + Region + ".amazonaws.com"),
+			},
+		},
+	}, nil
+}
+
+// GetStages returns the auto-deployed default stage of an API
+func (a *APIGatewayV2) GetStages(ctx context.Context, params *apigatewayv2.GetStagesInput, optFns ...func(*apigatewayv2.Options)) (*apigatewayv2.GetStagesOutput, error) {
+	return &apigatewayv2.GetStagesOutput{Items: []httptypes.Stage{{
+		StageName:       aws.String("$default"),
+		AutoDeploy:      aws.Bool(true),
+		DeploymentId:    aws.String("x2y4z6"),
+		LastUpdatedDate: ago(3 * 24 * time.Hour),
+		DefaultRouteSettings: &httptypes.RouteSettings{
+			ThrottlingRateLimit:  aws.Float64(200),
+			ThrottlingBurstLimit: aws.Int32(400),
+		},
+	}}}, nil
+}
@@ -0,0 +1,143 @@
+package demo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+)
+
+// invalidationDuration is how long fixture invalidations are in progress
+const invalidationDuration = 20 * time.Second
+
+// CloudFront is a fixture CloudFront API
+type CloudFront struct{}
+
+// NewCloudFront returns a fixture CloudFront API
+func NewCloudFront() *CloudFront {
+	return &CloudFront{}
+}
+
+// demoDistribution describes a fixture distribution
+type demoDistribution struct {
+	id         string
+	domainName string
+	aliases    []string
+	status     string
+	enabled    bool
+	comment    string
+}
+
+// demoDistributions are the fixture distributions. The web distribution is
+// still deploying a configuration change.
+var demoDistributions = []demoDistribution{
+	{"E2QWRUHAPOMQZL", "d111111abcdef8.cloudfront.net", []string{"cdn.example.com"}, "Deployed", true, "Static assets"},
+	{"E1KTYZ4UOHKJBN", "d3kq0xw2n8a7bd.cloudfront.net", []string{"www.example.com", "example.com"}, "InProgress", true, "Website"},
+	{"E3VR8NHJ2M4PQS", "d1q8z0smkdy3ve.cloudfront.net", nil, "Deployed", false, "Old landing pages"},
+}
+
+// demoInvalidation is an invalidation created in demo mode
+type demoInvalidation struct {
+	distributionID string
+	batch          *types.InvalidationBatch
+	createdAt      time.Time
+}
+
+// demoInvalidations holds the invalidations created in demo mode across
+// fixture instances, keyed by invalidation ID
+var demoInvalidations = struct {
+	sync.Mutex
+	invalidations map[string]demoInvalidation
+}{invalidations: make(map[string]demoInvalidation)}
+
+// ListDistributions returns the fixture distributions
+func (c *CloudFront) ListDistributions(ctx context.Context, params *cloudfront.ListDistributionsInput, optFns ...func(*cloudfront.Options)) (*cloudfront.ListDistributionsOutput, error) {
+	var items []types.DistributionSummary
+	for _, distribution := range demoDistributions {
+		summary := types.DistributionSummary{
+			Id:               aws.String(distribution.id),
+			DomainName:       aws.String(distribution.domainName),
+			Status:           aws.String(distribution.status),
+			Enabled:          aws.Bool(distribution.enabled),
+			Comment:          aws.String(distribution.comment),
+			LastModifiedTime: ago(3 * 24 * time.Hour),
+			Aliases: &types.Aliases{
+				Items:    distribution.aliases,
+				Quantity: aws.Int32(int32(len(distribution.aliases))),
+			},
+		}
+		if distribution.status == "InProgress" {
+			summary.LastModifiedTime = ago(2 * time.Minute)
+		}
+		items = append(items, summary)
+	}
+
+	return &cloudfront.ListDistributionsOutput{
+		DistributionList: &types.DistributionList{
+			Items:       items,
+			IsTruncated: aws.Bool(false),
+			Quantity:    aws.Int32(int32(len(items))),
+		},
+	}, nil
+}
+
+// CreateInvalidation creates a fixture invalidation that completes after
+// invalidationDuration
+func (c *CloudFront) CreateInvalidation(ctx context.Context, params *cloudfront.CreateInvalidationInput, optFns ...func(*cloudfront.Options)) (*cloudfront.CreateInvalidationOutput, error) {
+	distributionID := aws.ToString(params.DistributionId)
+	if !isDemoDistribution(distributionID) {
+		return nil, &types.NoSuchDistribution{Message: aws.String("The specified distribution does not exist.")}
+	}
+
+	createdAt := timeNow()
+	id := fmt.Sprintf("I%X", createdAt.UnixNano())
+	invalidation := demoInvalidation{distributionID: distributionID, batch: params.InvalidationBatch, createdAt: createdAt}
+
+	demoInvalidations.Lock()
+	demoInvalidations.invalidations[id] = invalidation
+	demoInvalidations.Unlock()
+
+	return &cloudfront.CreateInvalidationOutput{Invalidation: describeDemoInvalidation(id, invalidation)}, nil
+}
+
+// GetInvalidation returns the state of a fixture invalidation, which depends
+// on how long ago it was created
+func (c *CloudFront) GetInvalidation(ctx context.Context, params *cloudfront.GetInvalidationInput, optFns ...func(*cloudfront.Options)) (*cloudfront.GetInvalidationOutput, error) {
+	demoInvalidations.Lock()
+	defer demoInvalidations.Unlock()
+
+	id := aws.ToString(params.Id)
+	invalidation, ok := demoInvalidations.invalidations[id]
+	if !ok || invalidation.distributionID != aws.ToString(params.DistributionId) {
+		return nil, &types.NoSuchInvalidation{Message: aws.String("The specified invalidation does not exist.")}
+	}
+	return &cloudfront.GetInvalidationOutput{Invalidation: describeDemoInvalidation(id, invalidation)}, nil
+}
+
+// describeDemoInvalidation describes a fixture invalidation
+func describeDemoInvalidation(id string, invalidation demoInvalidation) *types.Invalidation {
+	status := "InProgress"
+	if timeNow().Sub(invalidation.createdAt) >= invalidationDuration {
+		status = "Completed"
+	}
+	return &types.Invalidation{
+		Id:                aws.String(id),
+		Status:            aws.String(status),
+		CreateTime:        aws.Time(invalidation.createdAt),
+		InvalidationBatch: invalidation.batch,
+	}
+}
+
+// isDemoDistribution reports whether id is a fixture distribution
+func isDemoDistribution(id string) bool {
+	for _, distribution := range demoDistributions {
+		if distribution.id == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package demo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+// demoTrailEvent is a fixture CloudTrail event
+type demoTrailEvent struct {
+	ago      time.Duration
+	name     string
+	user     string
+	readOnly bool
+}
+
+// trailEvents are the fixture CloudTrail events, keyed by the name of the
+// resource they concern
+var trailEvents = map[string][]demoTrailEvent{
+	"payments-api": {
+		{6 * time.Minute, "UpdateService", "deploy-bot", false},
+		{2 * time.Minute, "DescribeServices", "aws-overview", true},
+	},
+	"orders-db": {
+		{55 * time.Minute, "ModifyDBInstance", "alice", false},
+	},
+	"report-export": {
+		{40 * time.Minute, "UpdateFunctionConfiguration20150331v2", "bob", false},
+	},
+}
+
+// CloudTrail is a fixture CloudTrail API
+type CloudTrail struct{}
+
+// NewCloudTrail returns a fixture CloudTrail API
+func NewCloudTrail() *CloudTrail {
+	return &CloudTrail{}
+}
+
+// LookupEvents returns the fixture events of the resource named in the
+// lookup attributes since the start time
+func (c *CloudTrail) LookupEvents(ctx context.Context, params *cloudtrail.LookupEventsInput, optFns ...func(*cloudtrail.Options)) (*cloudtrail.LookupEventsOutput, error) {
+	output := &cloudtrail.LookupEventsOutput{}
+	for _, attribute := range params.LookupAttributes {
+		if attribute.AttributeKey != types.LookupAttributeKeyResourceName {
+			continue
+		}
+		for i, event := range trailEvents[aws.ToString(attribute.AttributeValue)] {
+			eventTime := timeNow().Add(-event.ago)
+			if params.StartTime != nil && eventTime.Before(*params.StartTime) {
+				continue
+			}
+			output.Events = append(output.Events, types.Event{
+				EventId:   aws.String(fmt.Sprintf("%s-%d", aws.ToString(attribute.AttributeValue), i)),
+				EventName: aws.String(event.name),
+				EventTime: aws.Time(eventTime),
+				ReadOnly:  aws.String(fmt.Sprint(event.readOnly)),
+				Username:  aws.String(event.user),
+			})
+		}
+	}
+	return output, nil
+}
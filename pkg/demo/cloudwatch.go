@@ -0,0 +1,412 @@
+package demo
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// series describes a generated metric: a baseline with a gentle wave on top,
+// reached at the end of the window after changing by trend per hour. A
+// series with once set has a single datapoint, that long before the end,
+// like the runs of a daily job.
+type series struct {
+	base      float64
+	amplitude float64
+	trend     float64
+	once      time.Duration
+}
+
+// metricSeries holds the shape of each metric, keyed by metric name and then
+// by dimension value. The "" dimension entry is used for unknown resources.
+var metricSeries = map[string]map[string]series{
+	"CPUUtilization": {
+		"":                    {base: 20, amplitude: 8},
+		"orders-db":           {base: 42, amplitude: 15},
+		"analytics-db":        {base: 71, amplitude: 12},
+		"i-0a1b2c3d4e5f60004": {base: 2, amplitude: 1},
+	},
+	// Bytes per period; the bastion is mostly idle
+	"NetworkIn": {
+		"":                    {base: 2e9, amplitude: 5e8},
+		"i-0a1b2c3d4e5f60004": {base: 1e6, amplitude: 3e5},
+	},
+	"NetworkOut": {
+		"":                    {base: 1.5e9, amplitude: 4e8},
+		"i-0a1b2c3d4e5f60004": {base: 8e5, amplitude: 2e5},
+	},
+	"DatabaseConnections": {
+		"": {base: 25, amplitude: 10},
+	},
+	// Load balancers are keyed by the end of their ARN; marketing-legacy and
+	// staging-web serve no requests
+	"RequestCount": {
+		"": {base: 1200, amplitude: 400},
+	},
+	"NewFlowCount": {
+		"": {base: 300, amplitude: 100},
+	},
+	"FreeableMemory": {
+		"":             {base: 4 * gib, amplitude: 0.2 * gib},
+		"orders-db":    {base: 5.5 * gib, amplitude: 0.5 * gib},
+		"analytics-db": {base: 2.1 * gib, amplitude: 0.3 * gib},
+	},
+	"FreeStorageSpace": {
+		"":             {base: 40 * gib, amplitude: 0.1 * gib},
+		"orders-db":    {base: 310 * gib, amplitude: 0.5 * gib, trend: -0.25 * gib},
+		"analytics-db": {base: 18 * gib, amplitude: 0.05 * gib, trend: -1.5 * gib / 24},
+	},
+	"ReadIOPS": {
+		"":             {base: 50, amplitude: 20},
+		"orders-db":    {base: 1400, amplitude: 300},
+		"analytics-db": {base: 450, amplitude: 150},
+	},
+	"WriteIOPS": {
+		"":             {base: 20, amplitude: 10},
+		"orders-db":    {base: 600, amplitude: 150},
+		"analytics-db": {base: 80, amplitude: 30},
+	},
+	"NumberOfMessagesSent": {
+		"":              {base: 50, amplitude: 20},
+		"orders":        {base: 150, amplitude: 40},
+		"orders-dlq":    {base: 2, amplitude: 2},
+		"emails":        {base: 420, amplitude: 120},
+		"payments.fifo": {base: 35, amplitude: 10},
+	},
+	"ApproximateNumberOfMessagesVisible": {
+		"":              {base: 5, amplitude: 3},
+		"orders":        {base: 15, amplitude: 5},
+		"orders-dlq":    {base: 12, amplitude: 0},
+		"emails":        {base: 900, amplitude: 150},
+		"payments.fifo": {base: 1, amplitude: 1},
+	},
+	// Milliseconds; clickstream consumers have fallen behind
+	"GetRecords.IteratorAgeMilliseconds": {
+		"":            {base: 800, amplitude: 400},
+		"clickstream": {base: 7 * 60 * 1000, amplitude: 60 * 1000},
+	},
+	"IteratorAge": {
+		"": {base: 1500, amplitude: 1000},
+	},
+	// Percent; the bastion's small gp2 root volume has spent its credits
+	"BurstBalance": {
+		"":                      {base: 100, amplitude: 0},
+		"vol-0c1d2e3f4a5b60004": {base: 8, amplitude: 2, trend: -6},
+		"vol-0c1d2e3f4a5b60006": {base: 85, amplitude: 5},
+	},
+	// Connections per period; nat-a is running out of source ports
+	"ErrorPortAllocation": {
+		"":                      {base: 0, amplitude: 0},
+		"nat-0a1b2c3d4e5f60001": {base: 30, amplitude: 25, trend: 20},
+	},
+	// API Gateway metrics are keyed by the name of REST APIs and the ID of
+	// HTTP APIs; the checkout API is failing
+	"4XXError": {
+		"":         {base: 4, amplitude: 3},
+		"checkout": {base: 12, amplitude: 6},
+	},
+	"5XXError": {
+		"":         {base: 0, amplitude: 0},
+		"checkout": {base: 6, amplitude: 4, trend: 4},
+	},
+	"4xx": {
+		"": {base: 3, amplitude: 2},
+	},
+	"5xx": {
+		"": {base: 0, amplitude: 0},
+	},
+	// Milliseconds
+	"Latency": {
+		"":           {base: 85, amplitude: 20},
+		"checkout":   {base: 420, amplitude: 180},
+		"w8h2k5m3n6": {base: 35, amplitude: 10},
+	},
+	// Container Insights metrics are keyed by cluster, in CPU units and MiB;
+	// the memory of the production services is creeping up
+	"CpuUtilized": {
+		"": {base: 410, amplitude: 120},
+	},
+	"CpuReserved": {
+		"": {base: 1024, amplitude: 0},
+	},
+	"MemoryUtilized": {
+		"": {base: 1650, amplitude: 60, trend: 150},
+	},
+	"MemoryReserved": {
+		"": {base: 2048, amplitude: 0},
+	},
+	// Invocations of the EventBridge rules per hour; the hourly digest cannot
+	// start its task
+	"TriggeredRules": {
+		"":                        {base: 1, amplitude: 0},
+		"nightly-import-schedule": {base: 1, once: 19 * time.Hour},
+	},
+	"FailedInvocations": {
+		"":            {base: 0, amplitude: 0},
+		"send-digest": {base: 1, amplitude: 0},
+	},
+	// Custom metrics of the Checkout namespace
+	"OrdersPlaced": {
+		"":         {base: 40, amplitude: 12},
+		"checkout": {base: 85, amplitude: 25},
+	},
+	"PaymentLatency": {
+		"":       {base: 240, amplitude: 60},
+		"adyen":  {base: 310, amplitude: 90},
+		"stripe": {base: 180, amplitude: 40},
+	},
+	"CartAbandonments": {
+		"": {base: 6, amplitude: 4},
+	},
+	"ApproximateAgeOfOldestMessage": {
+		"":              {base: 30, amplitude: 15},
+		"orders":        {base: 45, amplitude: 20},
+		"orders-dlq":    {base: 5400, amplitude: 300},
+		"emails":        {base: 1500, amplitude: 200},
+		"payments.fifo": {base: 5, amplitude: 3},
+	},
+}
+
+const gib = 1024 * 1024 * 1024
+
+// withoutData lists resources that report no datapoints, like stopped
+// instances and the staging cluster, which has no Container Insights
+var withoutData = map[string]bool{
+	"staging":                               true,
+	"legacy-reports":                        true,
+	"app/marketing-legacy/50dc6c495c0c9188": true,
+	"app/staging-web/50dc6c495c0c9188":      true,
+}
+
+// CloudWatch is a fixture CloudWatch API generating smooth metric series
+type CloudWatch struct{}
+
+// NewCloudWatch returns a fixture CloudWatch API
+func NewCloudWatch() *CloudWatch {
+	return &CloudWatch{}
+}
+
+// GetMetricData returns generated datapoints for each query, one per period
+func (c *CloudWatch) GetMetricData(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+	output := &cloudwatch.GetMetricDataOutput{}
+
+	for _, query := range params.MetricDataQueries {
+		result := cwtypes.MetricDataResult{
+			Id:         query.Id,
+			StatusCode: cwtypes.StatusCodeComplete,
+		}
+
+		if query.MetricStat != nil && query.MetricStat.Metric != nil {
+			result.Values, result.Timestamps = generate(params, query.MetricStat)
+		}
+
+		output.MetricDataResults = append(output.MetricDataResults, result)
+	}
+
+	return output, nil
+}
+
+// kinesisStreams lists the fixture Kinesis streams that are being read
+var kinesisStreams = []string{"clickstream", "order-events"}
+
+// listedMetric is a metric ListMetrics reports, with its dimensions as
+// name and value pairs
+type listedMetric struct {
+	namespace  string
+	name       string
+	dimensions []string
+}
+
+// listedMetrics returns the recently active metrics of the fixture: the
+// iterator age of the Kinesis streams, a few SQS and RDS metrics and the
+// custom metrics of the checkout service
+func listedMetrics() []listedMetric {
+	var metrics []listedMetric
+	for _, stream := range kinesisStreams {
+		metrics = append(metrics, listedMetric{"AWS/Kinesis", "GetRecords.IteratorAgeMilliseconds", []string{"StreamName", stream}})
+	}
+	for _, queue := range []string{"emails", "orders", "orders-dlq", "payments.fifo"} {
+		metrics = append(metrics,
+			listedMetric{"AWS/SQS", "ApproximateNumberOfMessagesVisible", []string{"QueueName", queue}},
+			listedMetric{"AWS/SQS", "NumberOfMessagesSent", []string{"QueueName", queue}},
+		)
+	}
+	for _, instance := range []string{"analytics-db", "orders-db"} {
+		metrics = append(metrics,
+			listedMetric{"AWS/RDS", "CPUUtilization", []string{"DBInstanceIdentifier", instance}},
+			listedMetric{"AWS/RDS", "FreeStorageSpace", []string{"DBInstanceIdentifier", instance}},
+		)
+	}
+	return append(metrics,
+		listedMetric{"Checkout", "CartAbandonments", nil},
+		listedMetric{"Checkout", "OrdersPlaced", []string{"Service", "checkout"}},
+		listedMetric{"Checkout", "PaymentLatency", []string{"Provider", "adyen"}},
+		listedMetric{"Checkout", "PaymentLatency", []string{"Provider", "stripe"}},
+	)
+}
+
+// ListMetrics returns the fixture metrics matching the namespace and metric
+// name of the request, in a single page
+func (c *CloudWatch) ListMetrics(ctx context.Context, params *cloudwatch.ListMetricsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.ListMetricsOutput, error) {
+	output := &cloudwatch.ListMetricsOutput{}
+	for _, metric := range listedMetrics() {
+		if params.Namespace != nil && *params.Namespace != metric.namespace {
+			continue
+		}
+		if params.MetricName != nil && *params.MetricName != metric.name {
+			continue
+		}
+
+		listed := cwtypes.Metric{
+			Namespace:  aws.String(metric.namespace),
+			MetricName: aws.String(metric.name),
+		}
+		for i := 0; i+1 < len(metric.dimensions); i += 2 {
+			listed.Dimensions = append(listed.Dimensions, cwtypes.Dimension{
+				Name:  aws.String(metric.dimensions[i]),
+				Value: aws.String(metric.dimensions[i+1]),
+			})
+		}
+		output.Metrics = append(output.Metrics, listed)
+	}
+	return output, nil
+}
+
+// SetAlarmState accepts setting the state of any alarm
+func (c *CloudWatch) SetAlarmState(ctx context.Context, params *cloudwatch.SetAlarmStateInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.SetAlarmStateOutput, error) {
+	return &cloudwatch.SetAlarmStateOutput{}, nil
+}
+
+// demoAlarm is a fixture metric alarm with its state changes
+type demoAlarm struct {
+	name       string
+	dimensions map[string]string
+	changes    []demoAlarmChange
+}
+
+// demoAlarmChange is a fixture alarm state change
+type demoAlarmChange struct {
+	ago     time.Duration
+	summary string
+}
+
+// metricAlarms are the fixture metric alarms. The alarms of struggling
+// resources changed state in the past hour.
+var metricAlarms = []demoAlarm{
+	{"orders-api-cpu-high", map[string]string{"ClusterName": "production", "ServiceName": "orders-api"}, nil},
+	{"payments-api-cpu-high", map[string]string{"ClusterName": "production", "ServiceName": "payments-api"}, []demoAlarmChange{
+		{8 * time.Minute, "Alarm updated from OK to ALARM"},
+	}},
+	{"orders-db-cpu-high", map[string]string{"DBInstanceIdentifier": "orders-db"}, []demoAlarmChange{
+		{50 * time.Minute, "Alarm updated from OK to ALARM"},
+		{35 * time.Minute, "Alarm updated from ALARM to OK"},
+	}},
+	{"report-export-errors", map[string]string{"FunctionName": "report-export"}, []demoAlarmChange{
+		{32 * time.Minute, "Alarm updated from OK to ALARM"},
+	}},
+}
+
+// DescribeAlarms returns the fixture metric alarms in the requested state,
+// or all of them
+func (c *CloudWatch) DescribeAlarms(ctx context.Context, params *cloudwatch.DescribeAlarmsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.DescribeAlarmsOutput, error) {
+	output := &cloudwatch.DescribeAlarmsOutput{}
+	for _, alarm := range metricAlarms {
+		metricAlarm := cwtypes.MetricAlarm{
+			AlarmName:             aws.String(alarm.name),
+			StateValue:            cwtypes.StateValueOk,
+			StateReason:           aws.String("Threshold Crossed: no datapoints were greater than the threshold (80.0)."),
+			StateUpdatedTimestamp: aws.Time(timeNow().Add(-24 * time.Hour)),
+		}
+		for name, value := range alarm.dimensions {
+			metricAlarm.Dimensions = append(metricAlarm.Dimensions, cwtypes.Dimension{Name: aws.String(name), Value: aws.String(value)})
+		}
+		if len(alarm.changes) > 0 {
+			last := alarm.changes[len(alarm.changes)-1]
+			metricAlarm.StateUpdatedTimestamp = aws.Time(timeNow().Add(-last.ago))
+			if strings.HasSuffix(last.summary, "to ALARM") {
+				metricAlarm.StateValue = cwtypes.StateValueAlarm
+				metricAlarm.StateReason = aws.String("Threshold Crossed: 3 datapoints were greater than the threshold (80.0).")
+			}
+		}
+		if params.StateValue != "" && params.StateValue != metricAlarm.StateValue {
+			continue
+		}
+		output.MetricAlarms = append(output.MetricAlarms, metricAlarm)
+	}
+	return output, nil
+}
+
+// DescribeAlarmHistory returns the state changes of a fixture alarm since
+// the start date
+func (c *CloudWatch) DescribeAlarmHistory(ctx context.Context, params *cloudwatch.DescribeAlarmHistoryInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.DescribeAlarmHistoryOutput, error) {
+	output := &cloudwatch.DescribeAlarmHistoryOutput{}
+	for _, alarm := range metricAlarms {
+		if alarm.name != aws.ToString(params.AlarmName) {
+			continue
+		}
+		for _, change := range alarm.changes {
+			timestamp := timeNow().Add(-change.ago)
+			if params.StartDate != nil && timestamp.Before(*params.StartDate) {
+				continue
+			}
+			output.AlarmHistoryItems = append(output.AlarmHistoryItems, cwtypes.AlarmHistoryItem{
+				AlarmName:       aws.String(alarm.name),
+				AlarmType:       cwtypes.AlarmTypeMetricAlarm,
+				HistoryItemType: cwtypes.HistoryItemTypeStateUpdate,
+				HistorySummary:  aws.String(change.summary),
+				Timestamp:       aws.Time(timestamp),
+			})
+		}
+	}
+	return output, nil
+}
+
+// generate produces the datapoints of a metric over the requested window
+func generate(params *cloudwatch.GetMetricDataInput, stat *cwtypes.MetricStat) ([]float64, []time.Time) {
+	shapes, ok := metricSeries[*stat.Metric.MetricName]
+	if !ok {
+		return nil, nil
+	}
+
+	var dimension string
+	if len(stat.Metric.Dimensions) > 0 && stat.Metric.Dimensions[0].Value != nil {
+		dimension = *stat.Metric.Dimensions[0].Value
+	}
+	if withoutData[dimension] {
+		return nil, nil
+	}
+
+	shape, ok := shapes[dimension]
+	if !ok {
+		shape = shapes[""]
+	}
+
+	period := 5 * time.Minute
+	if stat.Period != nil && *stat.Period > 0 {
+		period = time.Duration(*stat.Period) * time.Second
+	}
+
+	end := timeNow()
+	start := end.Add(-time.Hour)
+	if params.StartTime != nil && params.EndTime != nil {
+		start, end = *params.StartTime, *params.EndTime
+	}
+
+	var values []float64
+	var timestamps []time.Time
+	for i, t := 0, start; t.Before(end); i, t = i+1, t.Add(period) {
+		if ago := end.Sub(t); shape.once > 0 && (ago > shape.once || ago <= shape.once-period) {
+			continue
+		}
+		value := shape.base + shape.amplitude*math.Sin(float64(i)/2) - shape.trend*end.Sub(t).Hours()
+		values = append(values, math.Max(0, value))
+		timestamps = append(timestamps, t)
+	}
+
+	return values, timestamps
+}
@@ -0,0 +1,82 @@
+package demo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+)
+
+// dailyCosts are the fixture daily spend of each service on weekdays, in USD
+var dailyCosts = map[string]float64{
+	"Amazon Relational Database Service":     38.40,
+	"Amazon Elastic Compute Cloud - Compute": 26.15,
+	"Amazon Elastic Container Service":       17.80,
+	"Amazon Simple Storage Service":          4.12,
+	"AWS Lambda":                             1.37,
+	"Amazon Simple Queue Service":            0.42,
+	"AmazonCloudWatch":                       3.05,
+}
+
+// costSpikeAgo is how many days ago the fixture batch jobs ran, which added
+// costSpike USD to the spend on EC2 that day
+const (
+	costSpikeAgo = 6
+	costSpike    = 64.80
+)
+
+// CostExplorer is a fixture Cost Explorer API
+type CostExplorer struct{}
+
+// NewCostExplorer returns a fixture Cost Explorer API
+func NewCostExplorer() *CostExplorer {
+	return &CostExplorer{}
+}
+
+// GetCostAndUsage returns the fixture daily spend by service of the days in
+// the requested period, lower on weekends. The spend of today is half a day
+// and, like that of yesterday, an estimate.
+func (c *CostExplorer) GetCostAndUsage(ctx context.Context, params *costexplorer.GetCostAndUsageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageOutput, error) {
+	start, err := time.Parse("2006-01-02", aws.ToString(params.TimePeriod.Start))
+	if err != nil {
+		return nil, fmt.Errorf("invalid start date: %w", err)
+	}
+	end, err := time.Parse("2006-01-02", aws.ToString(params.TimePeriod.End))
+	if err != nil {
+		return nil, fmt.Errorf("invalid end date: %w", err)
+	}
+	today := timeNow().UTC().Truncate(24 * time.Hour)
+
+	output := &costexplorer.GetCostAndUsageOutput{}
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		result := types.ResultByTime{
+			TimePeriod: &types.DateInterval{
+				Start: aws.String(day.Format("2006-01-02")),
+				End:   aws.String(day.AddDate(0, 0, 1).Format("2006-01-02")),
+			},
+			Estimated: !day.Before(today.AddDate(0, 0, -1)),
+		}
+		for name, amount := range dailyCosts {
+			if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+				amount *= 0.7
+			}
+			if name == "Amazon Elastic Compute Cloud - Compute" && day.Equal(today.AddDate(0, 0, -costSpikeAgo)) {
+				amount += costSpike
+			}
+			if day.Equal(today) {
+				amount /= 2
+			}
+			result.Groups = append(result.Groups, types.Group{
+				Keys: []string{name},
+				Metrics: map[string]types.MetricValue{
+					"UnblendedCost": {Amount: aws.String(fmt.Sprintf("%.4f", amount)), Unit: aws.String("USD")},
+				},
+			})
+		}
+		output.ResultsByTime = append(output.ResultsByTime, result)
+	}
+	return output, nil
+}
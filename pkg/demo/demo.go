@@ -0,0 +1,28 @@
+// Package demo provides fixture implementations of the AWS APIs used by the
+// collectors so the tool can run without credentials or network access.
+// Each type implements the same interface as the corresponding AWS SDK
+// client and can be passed to the collectors' NewClient functions.
+package demo
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// Region is the region reported in demo mode
+const Region = "us-east-1"
+
+// AccountID is the account ID used in fixture ARNs and queue URLs
+const AccountID = "123456789012"
+
+// CallerARN is the identity the status bar reports in demo mode
+const CallerARN = "arn:aws:sts::" + AccountID + ":assumed-role/ReadOnly/demo"
+
+// timeNow is the clock fixture timestamps are relative to
+var timeNow = time.Now
+
+// ago returns a pointer to the time d before now
+func ago(d time.Duration) *time.Time {
+	return aws.Time(timeNow().Add(-d))
+}
@@ -0,0 +1,474 @@
+package demo
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/correctedcloud/aws-overview/pkg/alb"
+	"github.com/correctedcloud/aws-overview/pkg/apigateway"
+	"github.com/correctedcloud/aws-overview/pkg/cloudfront"
+	"github.com/correctedcloud/aws-overview/pkg/cost"
+	"github.com/correctedcloud/aws-overview/pkg/dns"
+	"github.com/correctedcloud/aws-overview/pkg/dr"
+	"github.com/correctedcloud/aws-overview/pkg/ebs"
+	"github.com/correctedcloud/aws-overview/pkg/ec2"
+	"github.com/correctedcloud/aws-overview/pkg/ecr"
+	"github.com/correctedcloud/aws-overview/pkg/ecs"
+	relatedevents "github.com/correctedcloud/aws-overview/pkg/events"
+	"github.com/correctedcloud/aws-overview/pkg/findings"
+	"github.com/correctedcloud/aws-overview/pkg/hygiene"
+	"github.com/correctedcloud/aws-overview/pkg/lag"
+	"github.com/correctedcloud/aws-overview/pkg/lambda"
+	"github.com/correctedcloud/aws-overview/pkg/logs"
+	"github.com/correctedcloud/aws-overview/pkg/metrics"
+	"github.com/correctedcloud/aws-overview/pkg/probe"
+	"github.com/correctedcloud/aws-overview/pkg/rds"
+	"github.com/correctedcloud/aws-overview/pkg/sns"
+	"github.com/correctedcloud/aws-overview/pkg/sqs"
+	"github.com/correctedcloud/aws-overview/pkg/ssm"
+	"github.com/correctedcloud/aws-overview/pkg/vpc"
+)
+
+func TestCollectorsWithFixtures(t *testing.T) {
+	ctx := context.Background()
+
+	lbs, errs := alb.NewClient(NewELBv2(), NewACM(), nil).GetLoadBalancers(ctx)
+	if len(errs) > 0 {
+		t.Fatalf("GetLoadBalancers() errors = %v", errs)
+	}
+	if len(lbs) != 6 {
+		t.Errorf("Expected 6 load balancers, got %d", len(lbs))
+	}
+	for _, lb := range lbs {
+		switch lb.Name {
+		case "pgbouncer":
+			if lb.Describe() != "network, internal" || len(lb.Listeners) != 1 || !lb.Listeners[0].RulesLoaded || lb.Listeners[0].Rules[0].Priority != "default" {
+				t.Errorf("Expected the network load balancer to forward with its default rule, got %+v", lb)
+			}
+			if target := lb.TargetGroups[0].Targets[1]; !target.Unhealthy() {
+				t.Errorf("Expected the draining unhealthy target to be unhealthy, got %+v", target)
+			}
+		case "web-prod":
+			if certificates := lb.Listeners[0].Certificates; len(certificates) != 2 || certificates[0].Domain != "www.example.com" || certificates[0].IsExpiring() || !certificates[1].IsExpiring() {
+				t.Errorf("Expected the default certificate and the expiring SNI certificate, got %+v", certificates)
+			}
+		case "inspection":
+			if lb.Type != alb.TypeGateway || lb.Listeners[0].Protocol != "GENEVE" || lb.Listeners[0].Port != 6081 {
+				t.Errorf("Expected the gateway load balancer to listen on GENEVE:6081, got %+v", lb)
+			}
+		}
+	}
+	if expiring := alb.GetExpiringCertificates(lbs); len(expiring) != 1 || expiring[0].Domain != "shop.example.com" || expiring[0].RenewsAutomatically() {
+		t.Errorf("Expected the imported shop certificate to be expiring, got %+v", expiring)
+	}
+	orphans := alb.FindOrphans(lbs)
+	if len(orphans) != 3 {
+		t.Errorf("Expected 3 leftovers, got %+v", orphans)
+	}
+	for _, orphan := range orphans {
+		if orphan.LoadBalancer != "marketing-legacy" && orphan.LoadBalancer != "staging-web" {
+			t.Errorf("Unexpected leftover on '%s': %s", orphan.LoadBalancer, orphan.Description)
+		}
+	}
+	request, err := alb.ParseRequest("www.example.com/static/app.js")
+	if err != nil {
+		t.Fatalf("ParseRequest() error = %v", err)
+	}
+	for _, match := range alb.SimulateRoute(lbs, request) {
+		if match.LoadBalancer == "web-prod" && match.Listener.Port == 443 && match.Rule.Priority != "10" {
+			t.Errorf("Expected web-prod HTTPS:443 to route by rule 10, got %+v", match.Rule)
+		}
+	}
+
+	instances, errs := rds.NewClient(NewRDS(), NewCloudWatch(), NewEC2(), nil, 0).GetDBInstances(ctx)
+	if len(errs) > 0 {
+		t.Fatalf("GetDBInstances() errors = %v", errs)
+	}
+	for _, instance := range instances {
+		hasData := len(instance.CPUData) > 0
+		if hasData == withoutData[instance.Identifier] {
+			t.Errorf("Unexpected CPU data for '%s': %v", instance.Identifier, instance.CPUData)
+		}
+	}
+	runningOut := rds.GetInstancesRunningOutOfStorage(instances)
+	if len(runningOut) != 1 || runningOut[0].Identifier != "analytics-db" {
+		t.Errorf("Expected only 'analytics-db' to run out of storage, got %v", runningOut)
+	}
+
+	ec2Instances, errs := ec2.NewClient(NewEC2()).GetInstances(ctx)
+	if len(errs) > 0 {
+		t.Fatalf("GetInstances() errors = %v", errs)
+	}
+	if len(ec2Instances) == 0 {
+		t.Errorf("Expected EC2 instances")
+	}
+	impaired := ec2.GetImpairedInstances(ec2Instances)
+	if len(impaired) != 1 || impaired[0].Name != "web-2" {
+		t.Errorf("Expected 'web-2' to be the only impaired instance, got %v", impaired)
+	}
+	scheduled := ec2.GetInstancesWithScheduledEvents(ec2Instances)
+	if len(scheduled) != 1 || scheduled[0].Name != "bastion" || len(scheduled[0].ScheduledEvents) != 1 {
+		t.Errorf("Expected only the bastion's upcoming reboot, got %v", scheduled)
+	}
+	if summary := ec2.GetInstancesSummary(ec2Instances); !strings.Contains(summary, "4 on-demand, 2 spot, 1 spot interruptions") {
+		t.Errorf("Expected web-3 to be an interrupted Spot instance, got %q", summary)
+	}
+
+	volumes, errs := ebs.NewClient(NewEC2(), NewCloudWatch(), nil).GetVolumes(ctx)
+	if len(errs) > 0 {
+		t.Fatalf("GetVolumes() errors = %v", errs)
+	}
+	if unattached := ebs.GetUnattachedVolumes(volumes); len(unattached) != 2 {
+		t.Errorf("Expected 2 unattached volumes, got %v", unattached)
+	}
+	throttled := ebs.GetThrottledVolumes(volumes)
+	if len(throttled) != 1 || throttled[0].Name != "bastion-root" {
+		t.Errorf("Expected only 'bastion-root' to be low on burst balance, got %v", throttled)
+	}
+
+	vpcs, errs := vpc.NewClient(NewEC2(), NewCloudWatch(), nil).GetVPCs(ctx)
+	if len(errs) > 0 {
+		t.Fatalf("GetVPCs() errors = %v", errs)
+	}
+	if len(vpcs) != 2 || len(vpcs[1].Subnets) != 7 || len(vpcs[1].Endpoints) != 5 {
+		t.Errorf("Expected the default and production VPCs with their subnets and endpoints, got %v", vpcs)
+	}
+	if low := vpc.GetLowIPSubnets(vpcs); len(low) != 1 || low[0].Name != "workers-a" {
+		t.Errorf("Expected only 'workers-a' to be low on free IPs, got %v", low)
+	}
+	if saturated := vpc.GetSaturatedNATGateways(vpcs); len(saturated) != 1 || saturated[0].Name != "nat-a" {
+		t.Errorf("Expected only 'nat-a' to have port allocation errors, got %v", saturated)
+	}
+
+	services, err := ecs.NewClient(NewECS()).GetServices(ctx)
+	if err != nil {
+		t.Fatalf("GetServices() error = %v", err)
+	}
+	if len(services) != 5 {
+		t.Errorf("Expected 5 ECS services, got %d", len(services))
+	}
+	ecs.SortServices(services)
+	if errs := ecs.NewInsightsClient(NewCloudWatch(), nil).GetServiceMetrics(ctx, services); len(errs) != 0 {
+		t.Errorf("GetServiceMetrics() errors = %v", errs)
+	}
+	cronJobs, errs := ecs.NewScheduleClient(NewEventBridge(), NewCloudWatch(), nil).GetScheduledTasks(ctx)
+	if len(errs) != 0 || len(cronJobs) != 2 {
+		t.Errorf("Expected the 2 scheduled tasks, got %+v and %v", cronJobs, errs)
+	}
+	for _, task := range cronJobs {
+		if task.Failing() != (task.RuleName == "send-digest") || task.LastTriggered.IsZero() {
+			t.Errorf("Expected only send-digest to fail, got %+v", task)
+		}
+	}
+	for _, service := range services {
+		if service.HasInsights() != (service.ClusterName == "production") {
+			t.Errorf("Expected Container Insights metrics for the production services only, got %+v", service)
+		}
+	}
+	ecsClient := ecs.NewClient(NewECS())
+	task, err := ecsClient.RunTask(ctx, services[0], []string{"bin/migrate"})
+	if err != nil {
+		t.Fatalf("RunTask() error = %v", err)
+	}
+	if task.LastStatus != "PROVISIONING" || len(task.Command) != 1 {
+		t.Errorf("Expected a provisioning task with the command override, got %+v", task)
+	}
+	oldTimeNow := timeNow
+	timeNow = func() time.Time { return oldTimeNow().Add(time.Minute) }
+	task, err = ecsClient.DescribeTask(ctx, task.ClusterName, task.ARN)
+	timeNow = oldTimeNow
+	if err != nil {
+		t.Fatalf("DescribeTask() error = %v", err)
+	}
+	if !task.Succeeded() {
+		t.Errorf("Expected the task to have succeeded a minute later, got %+v", task)
+	}
+
+	queues, errs := sqs.NewClient(NewSQS(), NewCloudWatch(), "", nil, 0).GetQueues(ctx)
+	if len(errs) > 0 {
+		t.Fatalf("GetQueues() errors = %v", errs)
+	}
+	flagged := sqs.GetQueuesWithNonEmptyDLQ(queues)
+	if len(flagged) != 1 || flagged[0].Name != "orders" {
+		t.Errorf("Expected 'orders' to be flagged with a non-empty DLQ, got %v", flagged)
+	}
+
+	managed, errs := ssm.NewClient(NewSSM(), NewEC2()).GetInstances(ctx)
+	if len(errs) > 0 {
+		t.Fatalf("GetInstances() errors = %v", errs)
+	}
+	unmanaged := ssm.GetUnmanagedInstances(managed)
+	if len(unmanaged) != 1 || unmanaged[0].Name != "web-3" {
+		t.Errorf("Expected 'web-3' to be the only unmanaged instance, got %v", unmanaged)
+	}
+
+	records, errs := dns.NewClient(NewRoute53(), NewCloudFront(), NewELBv2(), NewELB(), NewEC2(), Region).GetRecords(ctx)
+	if len(errs) > 0 {
+		t.Fatalf("GetRecords() errors = %v", errs)
+	}
+	if len(records) != 8 {
+		t.Errorf("Expected 8 records pointing at AWS resources, got %d", len(records))
+	}
+	dangling := dns.GetDanglingRecords(records)
+	if len(dangling) != 3 {
+		t.Errorf("Expected 3 dangling records, got %v", dangling)
+	}
+	for _, record := range dangling {
+		if record.Name != "promo.example.com" && record.Name != "assets.example.com" && record.Name != "ftp.example.com" {
+			t.Errorf("Unexpected dangling record '%s'", record.Name)
+		}
+	}
+
+	resources, errs := dr.NewClient(NewRDS(), NewS3(), NewECR(), NewDynamoDB(), Region, nil).GetResources(ctx)
+	if len(errs) > 0 {
+		t.Fatalf("GetResources() errors = %v", errs)
+	}
+	if len(resources) != 10 {
+		t.Errorf("Expected 10 resources checked for replication, got %d", len(resources))
+	}
+	degraded := dr.GetDegradedResources(resources)
+	if len(degraded) != 1 || degraded[0].Name != "carts" {
+		t.Errorf("Expected 'carts' to be the only failing replication, got %v", degraded)
+	}
+
+	topics, errs := sns.NewClient(NewSNS(), nil).GetTopics(ctx)
+	if len(errs) > 0 {
+		t.Fatalf("GetTopics() errors = %v", errs)
+	}
+	if len(topics) != 3 {
+		t.Errorf("Expected 3 topics, got %d", len(topics))
+	}
+	pending := sns.GetPendingSubscriptions(topics)
+	if len(pending) != 1 || pending[0].Queue != "partner-orders" {
+		t.Errorf("Expected 'partner-orders' to be the only pending subscription, got %v", pending)
+	}
+
+	lambdaClient := lambda.NewClient(NewLambda(), nil)
+	functions, err := lambdaClient.GetFunctions(ctx)
+	if err != nil {
+		t.Fatalf("GetFunctions() error = %v", err)
+	}
+	if len(functions) != 3 {
+		t.Errorf("Expected 3 functions, got %d", len(functions))
+	}
+	result, err := lambdaClient.Invoke(ctx, "order-audit", []byte(`{"id": 7}`))
+	if err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if result.Failed() || result.Payload != `{"statusCode": 200, "echo": {"id": 7}}` || result.Billed == 0 {
+		t.Errorf("Expected the payload echoed back with a billed duration, got %+v", result)
+	}
+	if result, err := lambdaClient.Invoke(ctx, "report-export", []byte(`{}`)); err != nil || !result.Failed() {
+		t.Errorf("Expected 'report-export' to fail, got %+v, %v", result, err)
+	}
+
+	cloudfrontClient := cloudfront.NewClient(NewCloudFront(), nil)
+	distributions, err := cloudfrontClient.GetDistributions(ctx)
+	if err != nil {
+		t.Fatalf("GetDistributions() error = %v", err)
+	}
+	if len(distributions) != 3 {
+		t.Errorf("Expected 3 distributions, got %d", len(distributions))
+	}
+	invalidation, err := cloudfrontClient.CreateInvalidation(ctx, "E2QWRUHAPOMQZL", []string{"/*"})
+	if err != nil {
+		t.Fatalf("CreateInvalidation() error = %v", err)
+	}
+	if invalidation.Completed() {
+		t.Errorf("Expected a new invalidation to be in progress, got %+v", invalidation)
+	}
+	timeNow = func() time.Time { return oldTimeNow().Add(time.Minute) }
+	invalidation, err = cloudfrontClient.GetInvalidation(ctx, invalidation.DistributionID, invalidation.ID)
+	timeNow = oldTimeNow
+	if err != nil {
+		t.Fatalf("GetInvalidation() error = %v", err)
+	}
+	if !invalidation.Completed() {
+		t.Errorf("Expected the invalidation to have completed a minute later, got %+v", invalidation)
+	}
+
+	repositories, errs := ecr.NewClient(NewECR(), nil).GetRepositories(ctx)
+	if len(errs) > 0 {
+		t.Fatalf("GetRepositories() errors = %v", errs)
+	}
+	vulnerable := ecr.GetVulnerableRepositories(repositories)
+	if len(vulnerable) != 1 || vulnerable[0].Name != "api" || vulnerable[0].Critical() != 1 {
+		t.Errorf("Expected only 'api' to have critical findings, got %v", vulnerable)
+	}
+
+	apis, errs := apigateway.NewClient(NewAPIGateway(), NewAPIGatewayV2(), NewCloudWatch(), nil).GetAPIs(ctx)
+	if len(errs) > 0 {
+		t.Fatalf("GetAPIs() errors = %v", errs)
+	}
+	if len(apis) != 3 {
+		t.Errorf("Expected 2 REST APIs and 1 HTTP API, got %d", len(apis))
+	}
+	failing := apigateway.GetAPIsWithServerErrors(apis)
+	if len(failing) != 1 || failing[0].Name != "checkout" {
+		t.Errorf("Expected only 'checkout' to return 5xx responses, got %v", failing)
+	}
+
+	indicators, errs := lag.NewClient(NewCloudWatch(), NewLambda(), nil).GetIndicators(ctx)
+	if len(errs) > 0 {
+		t.Fatalf("GetIndicators() errors = %v", errs)
+	}
+	if !indicators.Kinesis.Stalled() || indicators.Kinesis.Source != "clickstream" {
+		t.Errorf("Expected 'clickstream' to be stalled, got %+v", indicators.Kinesis)
+	}
+	if indicators.DynamoDB.Stalled() || indicators.DynamoDB.Source != "order-audit" {
+		t.Errorf("Expected 'order-audit' to keep up, got %+v", indicators.DynamoDB)
+	}
+
+	logsClient := logs.NewClient(NewCloudWatchLogs(), nil)
+	for _, service := range services {
+		sources, err := ecsClient.LogSources(ctx, service)
+		if err != nil {
+			t.Fatalf("LogSources() error = %v", err)
+		}
+		events, errs := logsClient.TailErrors(ctx, sources)
+		if len(errs) > 0 {
+			t.Fatalf("TailErrors() errors = %v", errs)
+		}
+		if failing := service.ServiceName == "payments-api" || service.ServiceName == "email-worker"; failing != (len(events) > 0) {
+			t.Errorf("Expected error events only for failing services, got %d for '%s'", len(events), service.ServiceName)
+		}
+	}
+	groups, err := logsClient.GroupsWithPrefix(ctx, logs.RDSLogGroupPrefix("orders-db"))
+	if err != nil {
+		t.Fatalf("GroupsWithPrefix() error = %v", err)
+	}
+	if len(groups) != 2 {
+		t.Errorf("Expected 2 log groups of 'orders-db', got %v", groups)
+	}
+	events, errs := logsClient.TailErrors(ctx, []logs.Source{{Group: functions[1].LogGroupName()}})
+	if len(errs) > 0 || len(events) != 2 {
+		t.Errorf("Expected 2 error events of '%s', got %d, %v", functions[1].Name, len(events), errs)
+	}
+
+	eventsClient := relatedevents.NewClient(NewCloudWatch(), NewCloudTrail(), NewECS(), NewRDS(), nil)
+	related, errs := eventsClient.Recent(ctx, relatedevents.Resource{
+		TrailNames:      []string{"payments-api"},
+		AlarmDimensions: map[string]string{"ClusterName": "production", "ServiceName": "payments-api"},
+		ECSCluster:      "production",
+		ECSService:      "payments-api",
+	})
+	if len(errs) > 0 {
+		t.Fatalf("Recent() errors = %v", errs)
+	}
+	// The deployment, its two service events and the alarm it set off; the
+	// read-only lookup is left out
+	if len(related) != 4 || related[0].Source != relatedevents.SourceAlarm || related[1].Source != relatedevents.SourceCloudTrail {
+		t.Errorf("Expected 4 related events of 'payments-api', got %+v", related)
+	}
+	related, errs = eventsClient.Recent(ctx, relatedevents.Resource{
+		TrailNames:      []string{"orders-db"},
+		AlarmDimensions: map[string]string{"DBInstanceIdentifier": "orders-db"},
+		RDSInstance:     "orders-db",
+	})
+	if len(errs) > 0 || len(related) != 5 {
+		t.Errorf("Expected 5 related events of 'orders-db', got %+v, %v", related, errs)
+	}
+
+	alarms, err := eventsClient.ActiveAlarms(ctx)
+	if err != nil {
+		t.Fatalf("ActiveAlarms() error = %v", err)
+	}
+	if len(alarms) != 2 || alarms[0].Name != "report-export-errors" || alarms[1].Name != "payments-api-cpu-high" {
+		t.Errorf("Expected the alarms of 'report-export' and 'payments-api' to be firing, got %+v", alarms)
+	}
+
+	costs, err := cost.NewClient(NewCostExplorer(), nil).GetSummary(ctx)
+	if err != nil {
+		t.Fatalf("GetSummary() error = %v", err)
+	}
+	if len(costs.Services) != 7 || costs.Services[0].Name != "Amazon Relational Database Service" || costs.MonthToDate <= 0 {
+		t.Errorf("Expected the spend of 7 services, RDS the most, got %+v", costs.Services)
+	}
+	spike := costs.Daily[cost.TrendDays-1-6]
+	if spike <= costs.Daily[cost.TrendDays-1-7] || spike <= costs.Daily[cost.TrendDays-1-5] {
+		t.Errorf("Expected the spend to spike 6 days ago, got %v", costs.Daily)
+	}
+
+	browser := metrics.NewClient(NewCloudWatch(), nil)
+	namespaces, _, err := browser.ListNamespaces(ctx)
+	if err != nil {
+		t.Fatalf("ListNamespaces() error = %v", err)
+	}
+	if len(namespaces) != 4 || namespaces[0].Name != "Checkout" || !namespaces[0].Custom() {
+		t.Errorf("Expected 4 namespaces, the custom 'Checkout' first, got %+v", namespaces)
+	}
+	checkout, _, err := browser.ListMetrics(ctx, "Checkout")
+	if err != nil {
+		t.Fatalf("ListMetrics() error = %v", err)
+	}
+	if len(checkout) != 4 {
+		t.Fatalf("Expected 4 metrics in 'Checkout', got %+v", checkout)
+	}
+	series := browser.GetSeries(ctx, []metrics.Pin{{Metric: checkout[1], Stat: "Sum"}})
+	if series[0].Err != nil || len(series[0].Values) == 0 {
+		t.Errorf("Expected datapoints of %s, got %+v", checkout[1].Label(), series[0])
+	}
+
+	found, errs := findings.NewClient(NewCloudWatch(), nil).GetFindings(ctx, findings.Inventory{
+		LoadBalancers: lbs,
+		Instances:     ec2Instances,
+		DBInstances:   instances,
+	})
+	if len(errs) > 0 {
+		t.Fatalf("GetFindings() errors = %v", errs)
+	}
+	var checked []string
+	for _, finding := range found {
+		checked = append(checked, finding.Check+": "+finding.Resource)
+	}
+	expectedFindings := []string{
+		"Idle load balancer: marketing-legacy",
+		"Idle load balancer: staging-web",
+		"Underutilized EC2 instance: bastion (i-0a1b2c3d4e5f60004)",
+		"Single-AZ DB instance: analytics-db",
+	}
+	if strings.Join(checked, ", ") != strings.Join(expectedFindings, ", ") {
+		t.Errorf("Expected findings %v, got %v", expectedFindings, checked)
+	}
+
+	wasted, errs := hygiene.NewClient(NewCloudWatch(), nil, 0).GetResources(ctx, hygiene.Inventory{
+		Instances:     ec2Instances,
+		LoadBalancers: lbs,
+		Queues:        queues,
+		DBInstances:   instances,
+	})
+	if len(errs) > 0 {
+		t.Fatalf("GetResources() errors = %v", errs)
+	}
+	var wastedNames []string
+	for _, resource := range wasted {
+		wastedNames = append(wastedNames, resource.Kind+": "+resource.Name)
+	}
+	expectedWasted := []string{
+		"Stopped EC2 instance: batch-worker (i-0a1b2c3d4e5f60005)",
+		"Empty target group: marketing-legacy-http",
+	}
+	if strings.Join(wastedNames, ", ") != strings.Join(expectedWasted, ", ") {
+		t.Errorf("Expected likely wasted resources %v, got %v", expectedWasted, wastedNames)
+	}
+
+	network := NewNetwork()
+	results := probe.NewClient(network, network, 0).Probe(ctx, probe.Targets(lbs, ec2Instances, probe.DefaultPorts))
+	var probed []string
+	for _, result := range results {
+		probed = append(probed, result.Resource+" "+result.Address()+" "+result.Status)
+	}
+	expectedProbes := []string{
+		"bastion (i-0a1b2c3d4e5f60004) 3.91.44.201:22 connected",
+		"marketing-legacy " + loadBalancerDNSName("marketing-legacy") + ":80 HTTP 503",
+		"web-1 (i-0a1b2c3d4e5f60001) 54.210.10.11:22 timed out",
+		"web-2 (i-0a1b2c3d4e5f60002) 54.210.10.12:22 timed out",
+		"web-prod " + loadBalancerDNSName("web-prod") + ":80 HTTP 301",
+		"web-prod " + loadBalancerDNSName("web-prod") + ":443 HTTP 200",
+	}
+	if strings.Join(probed, "\n") != strings.Join(expectedProbes, "\n") {
+		t.Errorf("Expected probes %v, got %v", expectedProbes, probed)
+	}
+}
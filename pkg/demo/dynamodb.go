@@ -0,0 +1,53 @@
+package demo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// tableReplicas maps the fixture tables to the status of their replicas in
+// other regions; tables without replicas are not global tables
+var tableReplicas = map[string]map[string]types.ReplicaStatus{
+	"sessions": {"us-west-2": types.ReplicaStatusActive},
+	"carts":    {"eu-west-1": types.ReplicaStatusInaccessibleEncryptionCredentials},
+	"audit":    nil,
+}
+
+// DynamoDB is a fixture DynamoDB API
+type DynamoDB struct{}
+
+// NewDynamoDB returns a fixture DynamoDB API
+func NewDynamoDB() *DynamoDB {
+	return &DynamoDB{}
+}
+
+// ListTables returns the names of the fixture tables
+func (d *DynamoDB) ListTables(ctx context.Context, params *dynamodb.ListTablesInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ListTablesOutput, error) {
+	return &dynamodb.ListTablesOutput{TableNames: []string{"audit", "carts", "sessions"}}, nil
+}
+
+// DescribeTable returns a fixture table with its global table replicas
+func (d *DynamoDB) DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	name := aws.ToString(params.TableName)
+	replicas, ok := tableReplicas[name]
+	if !ok {
+		return nil, &types.ResourceNotFoundException{Message: aws.String(fmt.Sprintf("Requested resource not found: Table: %s not found", name))}
+	}
+
+	table := &types.TableDescription{
+		TableName:   aws.String(name),
+		TableArn:    aws.String(fmt.Sprintf("arn:aws:dynamodb:%s:%s:table/%s", Region, AccountID, name)),
+		TableStatus: types.TableStatusActive,
+	}
+	if len(replicas) > 0 {
+		table.Replicas = append(table.Replicas, types.ReplicaDescription{RegionName: aws.String(Region), ReplicaStatus: types.ReplicaStatusActive})
+		for region, status := range replicas {
+			table.Replicas = append(table.Replicas, types.ReplicaDescription{RegionName: aws.String(region), ReplicaStatus: status})
+		}
+	}
+	return &dynamodb.DescribeTableOutput{Table: table}, nil
+}
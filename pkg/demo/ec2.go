@@ -0,0 +1,386 @@
+package demo
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+)
+
+// EC2 is a fixture EC2 API
+type EC2 struct{}
+
+// demoInstanceStates holds the states of the fixture instances started or
+// stopped since the start, shared by all fixture EC2 APIs so that a reload
+// shows the outcome of an action
+var demoInstanceStates = struct {
+	sync.Mutex
+	states map[string]types.InstanceStateName
+}{states: make(map[string]types.InstanceStateName)}
+
+// NewEC2 returns a fixture EC2 API
+func NewEC2() *EC2 {
+	return &EC2{}
+}
+
+// DescribeInstances returns the fixture instances in a single reservation per tier
+func (e *EC2) DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	instances := []struct {
+		id           string
+		name         string
+		instanceType types.InstanceType
+		state        types.InstanceStateName
+		privateIP    string
+		publicIP     string
+		launched     time.Duration
+		platform     string
+		zone         string
+		environment  string
+		role         string
+	}{
+		{"i-0a1b2c3d4e5f60001", "web-1", types.InstanceTypeT3Medium, types.InstanceStateNameRunning, "10.0.1.11", "54.210.10.11", 36 * time.Hour, "Linux/UNIX", "us-east-1a", "production", "web"},
+		{"i-0a1b2c3d4e5f60002", "web-2", types.InstanceTypeT3Medium, types.InstanceStateNameRunning, "10.0.2.12", "54.210.10.12", 36 * time.Hour, "Linux/UNIX", "us-east-1b", "production", "web"},
+		{"i-0a1b2c3d4e5f60003", "web-3", types.InstanceTypeT3Medium, types.InstanceStateNameRunning, "10.0.3.13", "", 20 * time.Minute, "Linux/UNIX", "us-east-1c", "production", "web"},
+		{"i-0a1b2c3d4e5f60004", "bastion", types.InstanceTypeT3Micro, types.InstanceStateNameRunning, "10.0.0.5", "3.91.44.201", 96 * 24 * time.Hour, "Linux/UNIX", "us-east-1a", "shared", "bastion"},
+		{"i-0a1b2c3d4e5f60005", "batch-worker", types.InstanceTypeC5Xlarge, types.InstanceStateNameStopped, "10.0.4.21", "", 40 * 24 * time.Hour, "Linux/UNIX", "us-east-1a", "staging", "worker"},
+		{"i-0a1b2c3d4e5f60006", "reporting-win", types.InstanceTypeM5Large, types.InstanceStateNamePending, "10.0.5.31", "", 2 * time.Minute, "Windows", "us-east-1b", "staging", "reporting"},
+	}
+
+	demoInstanceStates.Lock()
+	defer demoInstanceStates.Unlock()
+
+	output := &ec2.DescribeInstancesOutput{}
+	for _, instance := range instances {
+		if state, ok := demoInstanceStates.states[instance.id]; ok {
+			instance.state = state
+		}
+		var publicIP *string
+		if instance.publicIP != "" {
+			publicIP = aws.String(instance.publicIP)
+		}
+		var lifecycle types.InstanceLifecycleType
+		var spotRequestID *string
+		if request, ok := spotRequests[instance.id]; ok {
+			lifecycle = types.InstanceLifecycleTypeSpot
+			spotRequestID = aws.String(request.id)
+		}
+		// EC2 only records when an instance was stopped in the reason
+		var transitionReason *string
+		if stopped, ok := stoppedFor[instance.id]; ok && instance.state == types.InstanceStateNameStopped {
+			transitionReason = aws.String("User initiated (" + ago(stopped).UTC().Format("2006-01-02 15:04:05") + " GMT)")
+		}
+		tenancy := types.TenancyDefault
+		if instance.platform == "Windows" {
+			// Windows runs on dedicated hardware to bring its own license
+			tenancy = types.TenancyDedicated
+		}
+
+		output.Reservations = append(output.Reservations, types.Reservation{
+			Instances: []types.Instance{
+				{
+					InstanceId:       aws.String(instance.id),
+					InstanceType:     instance.instanceType,
+					State:            &types.InstanceState{Name: instance.state},
+					PrivateIpAddress: aws.String(instance.privateIP),
+					PublicIpAddress:  publicIP,
+					LaunchTime:       ago(instance.launched),
+					PlatformDetails:  aws.String(instance.platform),
+					VpcId:            aws.String("vpc-0f1e2d3c4b5a69788"),
+					SubnetId:         aws.String("subnet-0123456789abcdef0"),
+					Placement:        &types.Placement{AvailabilityZone: aws.String(instance.zone), Tenancy: tenancy},
+					SecurityGroups: []types.GroupIdentifier{
+						{GroupId: aws.String(securityGroupID(instance.role)), GroupName: aws.String(instance.role + "-sg")},
+					},
+					Tags: []types.Tag{
+						{Key: aws.String("Name"), Value: aws.String(instance.name)},
+						{Key: aws.String("Environment"), Value: aws.String(instance.environment)},
+						{Key: aws.String("Role"), Value: aws.String(instance.role)},
+					},
+					InstanceLifecycle:     lifecycle,
+					SpotInstanceRequestId: spotRequestID,
+					StateTransitionReason: transitionReason,
+				},
+			},
+		})
+	}
+	return output, nil
+}
+
+// stoppedFor is how long ago the fixture instances that start out stopped
+// were stopped, by instance ID. The batch worker was left stopped for weeks.
+var stoppedFor = map[string]time.Duration{
+	"i-0a1b2c3d4e5f60005": 38 * 24 * time.Hour,
+}
+
+// spotRequests are the Spot requests of the fixture Spot instances, by
+// instance ID. web-3 is about to be reclaimed.
+var spotRequests = map[string]struct {
+	id      string
+	code    string
+	message string
+}{
+	"i-0a1b2c3d4e5f60003": {"sir-0a1b2c3d4e5f60003", "marked-for-termination", "Spot Instance is marked for termination."},
+	"i-0a1b2c3d4e5f60005": {"sir-0a1b2c3d4e5f60005", "instance-stopped-by-user", "Spot Instance was stopped by the user."},
+}
+
+// DescribeSpotInstanceRequests returns the fixture Spot requests filtered on
+// by request ID
+func (e *EC2) DescribeSpotInstanceRequests(ctx context.Context, params *ec2.DescribeSpotInstanceRequestsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSpotInstanceRequestsOutput, error) {
+	output := &ec2.DescribeSpotInstanceRequestsOutput{}
+	for instanceID, request := range spotRequests {
+		if !matchesFilters(params.Filters, "spot-instance-request-id", request.id) {
+			continue
+		}
+		output.SpotInstanceRequests = append(output.SpotInstanceRequests, types.SpotInstanceRequest{
+			SpotInstanceRequestId: aws.String(request.id),
+			InstanceId:            aws.String(instanceID),
+			State:                 types.SpotInstanceStateActive,
+			Status:                &types.SpotInstanceStatus{Code: aws.String(request.code), Message: aws.String(request.message)},
+		})
+	}
+	return output, nil
+}
+
+// securityGroups are the inbound rules of the security group of each role
+// of the fixture instances. The bastion is open to SSH from anywhere and the
+// reporting instance to RDP.
+var securityGroups = map[string][]types.IpPermission{
+	"web": {
+		{IpProtocol: aws.String("tcp"), FromPort: aws.Int32(443), ToPort: aws.Int32(443), IpRanges: []types.IpRange{{CidrIp: aws.String("0.0.0.0/0")}}},
+		{IpProtocol: aws.String("tcp"), FromPort: aws.Int32(80), ToPort: aws.Int32(80), IpRanges: []types.IpRange{{CidrIp: aws.String("0.0.0.0/0")}}},
+		{IpProtocol: aws.String("tcp"), FromPort: aws.Int32(22), ToPort: aws.Int32(22), UserIdGroupPairs: []types.UserIdGroupPair{{GroupId: aws.String(securityGroupID("bastion")), Description: aws.String("SSH from the bastion")}}},
+	},
+	"bastion": {
+		{IpProtocol: aws.String("tcp"), FromPort: aws.Int32(22), ToPort: aws.Int32(22), IpRanges: []types.IpRange{{CidrIp: aws.String("0.0.0.0/0"), Description: aws.String("temporary, remove after migration")}}},
+	},
+	"worker": {
+		{IpProtocol: aws.String("-1"), UserIdGroupPairs: []types.UserIdGroupPair{{GroupId: aws.String(securityGroupID("worker"))}}},
+	},
+	"reporting": {
+		{IpProtocol: aws.String("tcp"), FromPort: aws.Int32(3389), ToPort: aws.Int32(3389), IpRanges: []types.IpRange{{CidrIp: aws.String("0.0.0.0/0")}}, Ipv6Ranges: []types.Ipv6Range{{CidrIpv6: aws.String("::/0")}}},
+	},
+}
+
+// securityGroupID returns the ID of the security group of the fixture
+// instances of a role
+func securityGroupID(role string) string {
+	return map[string]string{
+		"web":       "sg-0a1b2c3d4e5f60781",
+		"bastion":   "sg-0a1b2c3d4e5f60782",
+		"worker":    "sg-0a1b2c3d4e5f60783",
+		"reporting": "sg-0a1b2c3d4e5f60784",
+	}[role]
+}
+
+// DescribeSecurityGroups returns the fixture security groups filtered on by group ID
+func (e *EC2) DescribeSecurityGroups(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
+	output := &ec2.DescribeSecurityGroupsOutput{}
+	for role, permissions := range securityGroups {
+		id := securityGroupID(role)
+		if !matchesFilters(params.Filters, "group-id", id) {
+			continue
+		}
+		output.SecurityGroups = append(output.SecurityGroups, types.SecurityGroup{
+			GroupId:       aws.String(id),
+			GroupName:     aws.String(role + "-sg"),
+			VpcId:         aws.String("vpc-0f1e2d3c4b5a69788"),
+			IpPermissions: permissions,
+		})
+	}
+	return output, nil
+}
+
+// matchesFilters reports whether value passes the filters on name, which it
+// does when there are none
+func matchesFilters(filters []types.Filter, name, value string) bool {
+	for _, filter := range filters {
+		if aws.ToString(filter.Name) == name && !slices.Contains(filter.Values, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// StartInstances starts fixture instances, which are running on the next
+// describe
+func (e *EC2) StartInstances(ctx context.Context, params *ec2.StartInstancesInput, optFns ...func(*ec2.Options)) (*ec2.StartInstancesOutput, error) {
+	return &ec2.StartInstancesOutput{StartingInstances: changeDemoInstanceStates(params.InstanceIds, types.InstanceStateNamePending, types.InstanceStateNameRunning)}, nil
+}
+
+// StopInstances stops fixture instances, which are stopped on the next
+// describe
+func (e *EC2) StopInstances(ctx context.Context, params *ec2.StopInstancesInput, optFns ...func(*ec2.Options)) (*ec2.StopInstancesOutput, error) {
+	return &ec2.StopInstancesOutput{StoppingInstances: changeDemoInstanceStates(params.InstanceIds, types.InstanceStateNameStopping, types.InstanceStateNameStopped)}, nil
+}
+
+// RebootInstances reboots fixture instances, which keep running
+func (e *EC2) RebootInstances(ctx context.Context, params *ec2.RebootInstancesInput, optFns ...func(*ec2.Options)) (*ec2.RebootInstancesOutput, error) {
+	return &ec2.RebootInstancesOutput{}, nil
+}
+
+// changeDemoInstanceStates records the state the fixture instances settle in
+// and returns their transitions to the current state
+func changeDemoInstanceStates(ids []string, current, settled types.InstanceStateName) []types.InstanceStateChange {
+	demoInstanceStates.Lock()
+	defer demoInstanceStates.Unlock()
+
+	changes := make([]types.InstanceStateChange, len(ids))
+	for i, id := range ids {
+		demoInstanceStates.states[id] = settled
+		changes[i] = types.InstanceStateChange{InstanceId: aws.String(id), CurrentState: &types.InstanceState{Name: current}}
+	}
+	return changes
+}
+
+// DescribeAddresses returns the fixture Elastic IPs
+func (e *EC2) DescribeAddresses(ctx context.Context, params *ec2.DescribeAddressesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeAddressesOutput, error) {
+	return &ec2.DescribeAddressesOutput{
+		Addresses: []types.Address{
+			{
+				AllocationId: aws.String("eipalloc-0a1b2c3d4e5f60001"),
+				PublicIp:     aws.String("3.91.44.201"),
+				InstanceId:   aws.String("i-0a1b2c3d4e5f60004"),
+				Domain:       types.DomainTypeVpc,
+			},
+		},
+	}, nil
+}
+
+// DescribeInstanceStatus returns the status checks of the fixture instances.
+// web-2 fails its reachability check and the bastion is scheduled for a
+// reboot by AWS.
+func (e *EC2) DescribeInstanceStatus(ctx context.Context, params *ec2.DescribeInstanceStatusInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstanceStatusOutput, error) {
+	statuses := []struct {
+		id       string
+		system   types.SummaryStatus
+		instance types.SummaryStatus
+	}{
+		{"i-0a1b2c3d4e5f60001", types.SummaryStatusOk, types.SummaryStatusOk},
+		{"i-0a1b2c3d4e5f60002", types.SummaryStatusOk, types.SummaryStatusImpaired},
+		{"i-0a1b2c3d4e5f60003", types.SummaryStatusOk, types.SummaryStatusOk},
+		{"i-0a1b2c3d4e5f60004", types.SummaryStatusOk, types.SummaryStatusOk},
+		{"i-0a1b2c3d4e5f60005", types.SummaryStatusNotApplicable, types.SummaryStatusNotApplicable},
+		{"i-0a1b2c3d4e5f60006", types.SummaryStatusInitializing, types.SummaryStatusInitializing},
+	}
+
+	output := &ec2.DescribeInstanceStatusOutput{}
+	for _, status := range statuses {
+		instanceStatus := types.InstanceStatus{
+			InstanceId:     aws.String(status.id),
+			SystemStatus:   &types.InstanceStatusSummary{Status: status.system},
+			InstanceStatus: &types.InstanceStatusSummary{Status: status.instance},
+		}
+		if status.id == "i-0a1b2c3d4e5f60004" {
+			instanceStatus.Events = []types.InstanceStatusEvent{
+				{
+					Code:        types.EventCodeSystemReboot,
+					Description: aws.String("scheduled reboot"),
+					NotBefore:   ago(-5 * 24 * time.Hour),
+					NotAfter:    ago(-5*24*time.Hour - 2*time.Hour),
+				},
+				{
+					Code:        types.EventCodeInstanceReboot,
+					Description: aws.String("[Completed] scheduled reboot"),
+					NotBefore:   ago(40 * 24 * time.Hour),
+				},
+			}
+		}
+		output.InstanceStatuses = append(output.InstanceStatuses, instanceStatus)
+	}
+	return output, nil
+}
+
+// DescribeVolumes returns a root volume per fixture instance, a data volume
+// on the batch worker and two volumes left behind by deleted instances
+func (e *EC2) DescribeVolumes(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+	volumes := []struct {
+		id         string
+		name       string
+		size       int32
+		volumeType types.VolumeType
+		iops       int32
+		throughput int32
+		instanceID string
+		device     string
+		zone       string
+		created    time.Duration
+	}{
+		{"vol-0c1d2e3f4a5b60001", "web-1-root", 30, types.VolumeTypeGp3, 3000, 125, "i-0a1b2c3d4e5f60001", "/dev/xvda", "us-east-1a", 36 * time.Hour},
+		{"vol-0c1d2e3f4a5b60002", "web-2-root", 30, types.VolumeTypeGp3, 3000, 125, "i-0a1b2c3d4e5f60002", "/dev/xvda", "us-east-1b", 36 * time.Hour},
+		{"vol-0c1d2e3f4a5b60003", "web-3-root", 30, types.VolumeTypeGp3, 3000, 125, "i-0a1b2c3d4e5f60003", "/dev/xvda", "us-east-1c", 20 * time.Minute},
+		{"vol-0c1d2e3f4a5b60004", "bastion-root", 8, types.VolumeTypeGp2, 100, 0, "i-0a1b2c3d4e5f60004", "/dev/xvda", "us-east-1a", 96 * 24 * time.Hour},
+		{"vol-0c1d2e3f4a5b60005", "batch-worker-root", 50, types.VolumeTypeGp2, 150, 0, "i-0a1b2c3d4e5f60005", "/dev/xvda", "us-east-1a", 40 * 24 * time.Hour},
+		{"vol-0c1d2e3f4a5b60006", "batch-scratch", 500, types.VolumeTypeSt1, 0, 0, "i-0a1b2c3d4e5f60005", "/dev/sdf", "us-east-1a", 40 * 24 * time.Hour},
+		{"vol-0c1d2e3f4a5b60007", "reporting-win-root", 100, types.VolumeTypeGp2, 300, 0, "i-0a1b2c3d4e5f60006", "/dev/sda1", "us-east-1b", 2 * time.Minute},
+		{"vol-0c1d2e3f4a5b60008", "old-web-data", 200, types.VolumeTypeGp2, 600, 0, "", "", "us-east-1b", 210 * 24 * time.Hour},
+		{"vol-0c1d2e3f4a5b60009", "", 20, types.VolumeTypeGp3, 3000, 125, "", "", "us-east-1c", 45 * 24 * time.Hour},
+	}
+
+	output := &ec2.DescribeVolumesOutput{}
+	for _, volume := range volumes {
+		summary := types.Volume{
+			VolumeId:         aws.String(volume.id),
+			Size:             aws.Int32(volume.size),
+			VolumeType:       volume.volumeType,
+			State:            types.VolumeStateAvailable,
+			AvailabilityZone: aws.String(volume.zone),
+			Encrypted:        aws.Bool(volume.volumeType == types.VolumeTypeGp3),
+			CreateTime:       ago(volume.created),
+		}
+		if volume.iops > 0 {
+			summary.Iops = aws.Int32(volume.iops)
+		}
+		if volume.throughput > 0 {
+			summary.Throughput = aws.Int32(volume.throughput)
+		}
+		if volume.name != "" {
+			summary.Tags = []types.Tag{{Key: aws.String("Name"), Value: aws.String(volume.name)}}
+		}
+		if volume.instanceID != "" {
+			summary.State = types.VolumeStateInUse
+			summary.Attachments = []types.VolumeAttachment{
+				{
+					InstanceId: aws.String(volume.instanceID),
+					Device:     aws.String(volume.device),
+					State:      types.VolumeAttachmentStateAttached,
+				},
+			}
+		}
+		output.Volumes = append(output.Volumes, summary)
+	}
+	return output, nil
+}
+
+// instanceTypeMemory is the memory in MiB of the fixture instance types,
+// including those underlying the fixture DB instance classes
+var instanceTypeMemory = map[types.InstanceType]int64{
+	types.InstanceTypeT3Micro:  1024,
+	types.InstanceTypeT3Small:  2048,
+	types.InstanceTypeT3Medium: 4096,
+	types.InstanceTypeC5Xlarge: 8192,
+	types.InstanceTypeM5Large:  8192,
+	types.InstanceTypeR6gLarge: 16384,
+}
+
+// DescribeInstanceTypes returns the memory of the requested fixture instance
+// types, failing like EC2 for a type it does not know
+func (e *EC2) DescribeInstanceTypes(ctx context.Context, params *ec2.DescribeInstanceTypesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstanceTypesOutput, error) {
+	output := &ec2.DescribeInstanceTypesOutput{}
+	for _, instanceType := range params.InstanceTypes {
+		memory, ok := instanceTypeMemory[instanceType]
+		if !ok {
+			return nil, &smithy.GenericAPIError{Code: "InvalidInstanceType", Message: fmt.Sprintf("The following supplied instance types do not exist: [%s]", instanceType)}
+		}
+		output.InstanceTypes = append(output.InstanceTypes, types.InstanceTypeInfo{
+			InstanceType: instanceType,
+			MemoryInfo:   &types.MemoryInfo{SizeInMiB: aws.Int64(memory)},
+		})
+	}
+	return output, nil
+}
@@ -0,0 +1,95 @@
+package demo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/aws/aws-sdk-go-v2/service/ecr/types"
+)
+
+// ECR is a fixture ECR API whose registry replicates to us-west-2
+type ECR struct{}
+
+// NewECR returns a fixture ECR API
+func NewECR() *ECR {
+	return &ECR{}
+}
+
+// DescribeRegistry returns the fixture registry and its replication rules
+func (e *ECR) DescribeRegistry(ctx context.Context, params *ecr.DescribeRegistryInput, optFns ...func(*ecr.Options)) (*ecr.DescribeRegistryOutput, error) {
+	return &ecr.DescribeRegistryOutput{
+		RegistryId: aws.String(AccountID),
+		ReplicationConfiguration: &types.ReplicationConfiguration{
+			Rules: []types.ReplicationRule{
+				{
+					Destinations: []types.ReplicationDestination{
+						{Region: aws.String("us-west-2"), RegistryId: aws.String(AccountID)},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// DescribeRepositories returns the fixture repositories
+func (e *ECR) DescribeRepositories(ctx context.Context, params *ecr.DescribeRepositoriesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeRepositoriesOutput, error) {
+	output := &ecr.DescribeRepositoriesOutput{}
+	for _, name := range []string{"web", "api", "worker"} {
+		output.Repositories = append(output.Repositories, types.Repository{
+			RepositoryName: aws.String(name),
+			RepositoryArn:  aws.String("arn:aws:ecr:" + Region + ":" + AccountID + ":repository/" + name),
+			RepositoryUri:  aws.String(AccountID + ".dkr.ecr." + Region + ".amazonaws.com/" + name),
+			RegistryId:     aws.String(AccountID),
+			ImageScanningConfiguration: &types.ImageScanningConfiguration{
+				ScanOnPush: ecrImages[name].scanned,
+			},
+		})
+	}
+	return output, nil
+}
+
+// ecrImages describes the images of each fixture repository: how many there
+// are, how often they were pushed, and the scan findings of the latest one
+var ecrImages = map[string]struct {
+	count    int
+	every    time.Duration
+	scanned  bool
+	findings map[string]int32
+}{
+	"web":    {count: 24, every: 18 * time.Hour, scanned: true, findings: map[string]int32{"MEDIUM": 3, "LOW": 11}},
+	"api":    {count: 9, every: 3 * 24 * time.Hour, scanned: true, findings: map[string]int32{"CRITICAL": 1, "HIGH": 4, "MEDIUM": 6}},
+	"worker": {count: 3, every: 40 * 24 * time.Hour},
+}
+
+// DescribeImages returns the images of a fixture repository, the latest
+// tagged "latest" as well as with its version
+func (e *ECR) DescribeImages(ctx context.Context, params *ecr.DescribeImagesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeImagesOutput, error) {
+	repository := ecrImages[aws.ToString(params.RepositoryName)]
+
+	output := &ecr.DescribeImagesOutput{}
+	for i := 0; i < repository.count; i++ {
+		version := repository.count - i
+		image := types.ImageDetail{
+			RepositoryName:   params.RepositoryName,
+			ImageDigest:      aws.String(fmt.Sprintf("sha256:%064x", version*7919)),
+			ImageTags:        []string{fmt.Sprintf("v1.%d.0", version)},
+			ImagePushedAt:    ago(time.Duration(i)*repository.every + 2*time.Hour),
+			ImageSizeInBytes: aws.Int64(int64(90+version) * 1024 * 1024),
+		}
+		if i == 0 {
+			image.ImageTags = append(image.ImageTags, "latest")
+		}
+		if repository.scanned {
+			image.ImageScanStatus = &types.ImageScanStatus{Status: types.ScanStatusComplete}
+			image.ImageScanFindingsSummary = &types.ImageScanFindingsSummary{FindingSeverityCounts: map[string]int32{}}
+			if i == 0 {
+				image.ImageScanFindingsSummary.FindingSeverityCounts = repository.findings
+			}
+		}
+		output.ImageDetails = append(output.ImageDetails, image)
+	}
+	return output, nil
+}
@@ -0,0 +1,333 @@
+package demo
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+// demoService is a fixture ECS service
+type demoService struct {
+	name     string
+	desired  int32
+	running  int32
+	pending  int32
+	rollout  types.DeploymentRolloutState
+	deployed time.Duration
+	awsvpc   bool
+}
+
+var ecsClusters = map[string][]demoService{
+	"production": {
+		{name: "orders-api", desired: 4, running: 4, rollout: types.DeploymentRolloutStateCompleted, deployed: 3 * 24 * time.Hour, awsvpc: true},
+		{name: "payments-api", desired: 3, running: 2, pending: 1, rollout: types.DeploymentRolloutStateInProgress, deployed: 6 * time.Minute, awsvpc: true},
+		{name: "email-worker", desired: 2, running: 0, rollout: types.DeploymentRolloutStateFailed, deployed: 2 * time.Hour, awsvpc: true},
+	},
+	"staging": {
+		{name: "orders-api", desired: 1, running: 1, rollout: types.DeploymentRolloutStateCompleted, deployed: 45 * time.Minute},
+		{name: "nightly-import", desired: 0, running: 0, rollout: types.DeploymentRolloutStateCompleted, deployed: 40 * 24 * time.Hour},
+	},
+}
+
+// serviceEvents are the fixture service events, most recent first like those
+// of ECS, keyed by cluster and service
+var serviceEvents = map[string][]struct {
+	ago     time.Duration
+	message string
+}{
+	"production/orders-api": {
+		{3 * time.Hour, "has reached a steady state."},
+	},
+	"production/payments-api": {
+		{4 * time.Minute, "registered 1 targets in (target-group api-payments)"},
+		{5 * time.Minute, "has started 1 tasks: (task 9b2e77)."},
+	},
+	"production/email-worker": {
+		{12 * time.Minute, "is unable to consistently start tasks successfully."},
+		{27 * time.Minute, "has started 1 tasks: (task e81a40)."},
+		{58 * time.Minute, "has started 1 tasks: (task c07d12)."},
+	},
+}
+
+// ECS is a fixture ECS API
+type ECS struct{}
+
+// NewECS returns a fixture ECS API
+func NewECS() *ECS {
+	return &ECS{}
+}
+
+func clusterARN(name string) string {
+	return fmt.Sprintf("arn:aws:ecs:%s:%s:cluster/%s", Region, AccountID, name)
+}
+
+func serviceARN(cluster, name string) string {
+	return fmt.Sprintf("arn:aws:ecs:%s:%s:service/%s/%s", Region, AccountID, cluster, name)
+}
+
+// ListClusters returns the fixture cluster ARNs
+func (e *ECS) ListClusters(ctx context.Context, params *ecs.ListClustersInput, optFns ...func(*ecs.Options)) (*ecs.ListClustersOutput, error) {
+	output := &ecs.ListClustersOutput{}
+	for _, name := range []string{"production", "staging"} {
+		output.ClusterArns = append(output.ClusterArns, clusterARN(name))
+	}
+	return output, nil
+}
+
+// DescribeClusters returns the requested fixture clusters
+func (e *ECS) DescribeClusters(ctx context.Context, params *ecs.DescribeClustersInput, optFns ...func(*ecs.Options)) (*ecs.DescribeClustersOutput, error) {
+	output := &ecs.DescribeClustersOutput{}
+	for _, arn := range params.Clusters {
+		name := arn[strings.LastIndex(arn, "/")+1:]
+		output.Clusters = append(output.Clusters, types.Cluster{
+			ClusterArn:  aws.String(arn),
+			ClusterName: aws.String(name),
+			Status:      aws.String("ACTIVE"),
+		})
+	}
+	return output, nil
+}
+
+// ListServices returns the fixture service ARNs of a cluster
+func (e *ECS) ListServices(ctx context.Context, params *ecs.ListServicesInput, optFns ...func(*ecs.Options)) (*ecs.ListServicesOutput, error) {
+	cluster := aws.ToString(params.Cluster)
+	output := &ecs.ListServicesOutput{}
+	for _, service := range ecsClusters[cluster] {
+		output.ServiceArns = append(output.ServiceArns, serviceARN(cluster, service.name))
+	}
+	return output, nil
+}
+
+// DescribeServices returns the requested fixture services of a cluster
+func (e *ECS) DescribeServices(ctx context.Context, params *ecs.DescribeServicesInput, optFns ...func(*ecs.Options)) (*ecs.DescribeServicesOutput, error) {
+	cluster := aws.ToString(params.Cluster)
+	requested := make(map[string]bool)
+	for _, arn := range params.Services {
+		requested[arn] = true
+	}
+
+	output := &ecs.DescribeServicesOutput{}
+	for _, service := range ecsClusters[cluster] {
+		arn := serviceARN(cluster, service.name)
+		if !requested[arn] && !requested[service.name] {
+			continue
+		}
+
+		described := types.Service{
+			ServiceArn:     aws.String(arn),
+			ServiceName:    aws.String(service.name),
+			Status:         aws.String("ACTIVE"),
+			DesiredCount:   service.desired,
+			RunningCount:   service.running,
+			PendingCount:   service.pending,
+			LaunchType:     types.LaunchTypeFargate,
+			TaskDefinition: aws.String(fmt.Sprintf("arn:aws:ecs:%s:%s:task-definition/%s:42", Region, AccountID, service.name)),
+			CreatedAt:      ago(90 * 24 * time.Hour),
+			Deployments: []types.Deployment{
+				{
+					Status:       aws.String("PRIMARY"),
+					RolloutState: service.rollout,
+					CreatedAt:    ago(service.deployed),
+					UpdatedAt:    ago(service.deployed),
+				},
+			},
+		}
+		if slices.Contains(params.Include, types.ServiceFieldTags) {
+			described.Tags = serviceTags(cluster, service.name)
+		}
+
+		for _, event := range serviceEvents[cluster+"/"+service.name] {
+			described.Events = append(described.Events, types.ServiceEvent{
+				CreatedAt: ago(event.ago),
+				Message:   aws.String(fmt.Sprintf("(service %s) %s", service.name, event.message)),
+			})
+		}
+
+		if service.awsvpc {
+			described.NetworkConfiguration = &types.NetworkConfiguration{
+				AwsvpcConfiguration: &types.AwsVpcConfiguration{
+					Subnets:        []string{"subnet-0123456789abcdef0"},
+					SecurityGroups: []string{"sg-0123456789abcdef0"},
+				},
+			}
+			described.LoadBalancers = []types.LoadBalancer{
+				{TargetGroupArn: aws.String(targetGroupARN("api-" + strings.TrimSuffix(service.name, "-api")))},
+			}
+		}
+
+		output.Services = append(output.Services, described)
+	}
+	return output, nil
+}
+
+// demoTask is a one-off task started in demo mode
+type demoTask struct {
+	cluster        string
+	taskDefinition string
+	overrides      *types.TaskOverride
+	createdAt      time.Time
+}
+
+// demoTasks holds the one-off tasks started in demo mode across fixture
+// instances, keyed by task ARN
+var demoTasks = struct {
+	sync.Mutex
+	tasks map[string]demoTask
+}{tasks: make(map[string]demoTask)}
+
+// DescribeTaskDefinition returns a fixture task definition with an app
+// container logging to the service's log group and a non-essential log
+// router
+func (e *ECS) DescribeTaskDefinition(ctx context.Context, params *ecs.DescribeTaskDefinitionInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTaskDefinitionOutput, error) {
+	// The family of .../task-definition/orders-api:42 is the service's name
+	family := aws.ToString(params.TaskDefinition)
+	family = family[strings.LastIndex(family, "/")+1:]
+	if i := strings.Index(family, ":"); i >= 0 {
+		family = family[:i]
+	}
+
+	return &ecs.DescribeTaskDefinitionOutput{
+		TaskDefinition: &types.TaskDefinition{
+			TaskDefinitionArn: params.TaskDefinition,
+			ContainerDefinitions: []types.ContainerDefinition{
+				{Name: aws.String("log-router"), Essential: aws.Bool(false)},
+				{
+					Name:      aws.String("app"),
+					Essential: aws.Bool(true),
+					LogConfiguration: &types.LogConfiguration{
+						LogDriver: types.LogDriverAwslogs,
+						Options:   map[string]string{"awslogs-group": "/ecs/" + family, "awslogs-stream-prefix": "ecs"},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// RunTask starts a fixture task that provisions, runs for a few seconds and
+// exits 0
+func (e *ECS) RunTask(ctx context.Context, params *ecs.RunTaskInput, optFns ...func(*ecs.Options)) (*ecs.RunTaskOutput, error) {
+	cluster := aws.ToString(params.Cluster)
+	createdAt := timeNow()
+	arn := fmt.Sprintf("arn:aws:ecs:%s:%s:task/%s/%x", Region, AccountID, cluster, createdAt.UnixNano())
+
+	task := demoTask{
+		cluster:        cluster,
+		taskDefinition: aws.ToString(params.TaskDefinition),
+		overrides:      params.Overrides,
+		createdAt:      createdAt,
+	}
+	demoTasks.Lock()
+	demoTasks.tasks[arn] = task
+	demoTasks.Unlock()
+
+	return &ecs.RunTaskOutput{Tasks: []types.Task{describeDemoTask(arn, task)}}, nil
+}
+
+// DescribeTasks returns the state of fixture tasks, which depends on how long
+// ago they were started
+func (e *ECS) DescribeTasks(ctx context.Context, params *ecs.DescribeTasksInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTasksOutput, error) {
+	demoTasks.Lock()
+	defer demoTasks.Unlock()
+
+	output := &ecs.DescribeTasksOutput{}
+	for _, arn := range params.Tasks {
+		task, ok := demoTasks.tasks[arn]
+		if !ok {
+			output.Failures = append(output.Failures, types.Failure{Arn: aws.String(arn), Reason: aws.String("MISSING")})
+			continue
+		}
+		output.Tasks = append(output.Tasks, describeDemoTask(arn, task))
+	}
+	return output, nil
+}
+
+// ListTasks returns no tasks, as the tasks of the fixture services are not
+// modeled
+func (e *ECS) ListTasks(ctx context.Context, params *ecs.ListTasksInput, optFns ...func(*ecs.Options)) (*ecs.ListTasksOutput, error) {
+	return &ecs.ListTasksOutput{}, nil
+}
+
+// serviceTags returns the tags of a fixture service
+func serviceTags(cluster, name string) []types.Tag {
+	return []types.Tag{
+		{Key: aws.String("Environment"), Value: aws.String(cluster)},
+		{Key: aws.String("Application"), Value: aws.String(name)},
+	}
+}
+
+// ListTagsForResource returns the tags of a fixture service
+func (e *ECS) ListTagsForResource(ctx context.Context, params *ecs.ListTagsForResourceInput, optFns ...func(*ecs.Options)) (*ecs.ListTagsForResourceOutput, error) {
+	for cluster, services := range ecsClusters {
+		for _, service := range services {
+			if serviceARN(cluster, service.name) == aws.ToString(params.ResourceArn) {
+				return &ecs.ListTagsForResourceOutput{Tags: serviceTags(cluster, service.name)}, nil
+			}
+		}
+	}
+	return &ecs.ListTagsForResourceOutput{}, nil
+}
+
+// UpdateService accepts scaling a fixture service. The fixtures do not change,
+// so the next refresh shows the old desired count again.
+func (e *ECS) UpdateService(ctx context.Context, params *ecs.UpdateServiceInput, optFns ...func(*ecs.Options)) (*ecs.UpdateServiceOutput, error) {
+	cluster := aws.ToString(params.Cluster)
+	for _, service := range ecsClusters[cluster] {
+		if service.name == aws.ToString(params.Service) {
+			return &ecs.UpdateServiceOutput{Service: &types.Service{
+				ServiceName:  aws.String(service.name),
+				ServiceArn:   aws.String(serviceARN(cluster, service.name)),
+				DesiredCount: aws.ToInt32(params.DesiredCount),
+			}}, nil
+		}
+	}
+	return nil, &types.ServiceNotFoundException{Message: aws.String("Service not found.")}
+}
+
+// describeDemoTask describes a fixture task, which is provisioned for 2
+// seconds, pending for 2 more and then runs for 8 seconds
+func describeDemoTask(arn string, task demoTask) types.Task {
+	elapsed := timeNow().Sub(task.createdAt)
+	described := types.Task{
+		TaskArn:           aws.String(arn),
+		ClusterArn:        aws.String(clusterARN(task.cluster)),
+		TaskDefinitionArn: aws.String(task.taskDefinition),
+		Overrides:         task.overrides,
+		DesiredStatus:     aws.String("RUNNING"),
+		CreatedAt:         aws.Time(task.createdAt),
+		StartedBy:         aws.String("aws-overview"),
+	}
+
+	container := types.Container{Name: aws.String("app")}
+	switch {
+	case elapsed < 2*time.Second:
+		described.LastStatus = aws.String("PROVISIONING")
+		container.LastStatus = aws.String("PENDING")
+	case elapsed < 4*time.Second:
+		described.LastStatus = aws.String("PENDING")
+		container.LastStatus = aws.String("PENDING")
+	case elapsed < 12*time.Second:
+		described.LastStatus = aws.String("RUNNING")
+		described.StartedAt = aws.Time(task.createdAt.Add(4 * time.Second))
+		container.LastStatus = aws.String("RUNNING")
+	default:
+		described.LastStatus = aws.String("STOPPED")
+		described.DesiredStatus = aws.String("STOPPED")
+		described.StartedAt = aws.Time(task.createdAt.Add(4 * time.Second))
+		described.StoppedAt = aws.Time(task.createdAt.Add(12 * time.Second))
+		described.StopCode = types.TaskStopCodeEssentialContainerExited
+		described.StoppedReason = aws.String("Essential container in task exited")
+		container.LastStatus = aws.String("STOPPED")
+		container.ExitCode = aws.Int32(0)
+	}
+	described.Containers = []types.Container{container}
+
+	return described
+}
@@ -0,0 +1,20 @@
+package demo
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing"
+)
+
+// ELB is a fixture Classic Load Balancer API without any load balancers
+type ELB struct{}
+
+// NewELB returns a fixture Classic Load Balancer API
+func NewELB() *ELB {
+	return &ELB{}
+}
+
+// DescribeLoadBalancers returns no Classic Load Balancers
+func (e *ELB) DescribeLoadBalancers(ctx context.Context, params *elasticloadbalancing.DescribeLoadBalancersInput, optFns ...func(*elasticloadbalancing.Options)) (*elasticloadbalancing.DescribeLoadBalancersOutput, error) {
+	return &elasticloadbalancing.DescribeLoadBalancersOutput{}, nil
+}
@@ -0,0 +1,412 @@
+package demo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+)
+
+// demoTarget is a fixture target and its health
+type demoTarget struct {
+	id     string
+	port   int32
+	state  types.TargetHealthStateEnum
+	reason types.TargetHealthReasonEnum
+}
+
+// demoTargetGroup is a fixture target group
+type demoTargetGroup struct {
+	name    string
+	targets []demoTarget
+}
+
+// demoListener is a fixture listener forwarding to a target group, or redirecting when targetGroup is empty
+type demoListener struct {
+	protocol     types.ProtocolEnum
+	port         int32
+	targetGroup  string
+	rules        []demoRule
+	certificates []string // Domains of the fixture certificates served, the default one first
+}
+
+// demoRule is a fixture listener rule forwarding requests for a host and path to a target group
+type demoRule struct {
+	priority    string
+	host        string
+	path        string
+	header      string // "Name:value" or empty
+	targetGroup string
+}
+
+// demoLoadBalancer is a fixture load balancer
+type demoLoadBalancer struct {
+	name         string
+	kind         types.LoadBalancerTypeEnum // Empty for an application load balancer
+	internal     bool
+	listeners    []demoListener
+	targetGroups []demoTargetGroup
+}
+
+var loadBalancers = []demoLoadBalancer{
+	{
+		name: "web-prod",
+		listeners: []demoListener{
+			{protocol: types.ProtocolEnumHttps, port: 443, targetGroup: "web-prod-http", rules: []demoRule{
+				{priority: "5", host: "www.example.com", path: "/static/*", header: "X-Canary:true", targetGroup: "web-prod-canary"},
+				{priority: "10", host: "www.example.com", path: "/static/*", targetGroup: "web-prod-static"},
+				{priority: "20", host: "*.example.com", targetGroup: "web-prod-http"},
+			}, certificates: []string{"www.example.com", "shop.example.com"}},
+			{protocol: types.ProtocolEnumHttp, port: 80},
+		},
+		targetGroups: []demoTargetGroup{
+			{
+				name: "web-prod-static",
+				targets: []demoTarget{
+					{id: "i-0a1b2c3d4e5f60004", port: 8080, state: types.TargetHealthStateEnumHealthy},
+				},
+			},
+			{
+				name: "web-prod-canary",
+				targets: []demoTarget{
+					{id: "i-0a1b2c3d4e5f60005", port: 8080, state: types.TargetHealthStateEnumHealthy},
+				},
+			},
+			{
+				name: "web-prod-http",
+				targets: []demoTarget{
+					{id: "i-0a1b2c3d4e5f60001", port: 80, state: types.TargetHealthStateEnumHealthy},
+					{id: "i-0a1b2c3d4e5f60002", port: 80, state: types.TargetHealthStateEnumHealthy},
+					{id: "i-0a1b2c3d4e5f60003", port: 80, state: types.TargetHealthStateEnumUnhealthy, reason: types.TargetHealthReasonEnumFailedHealthChecks},
+				},
+			},
+		},
+	},
+	{
+		name:     "api-internal",
+		internal: true,
+		listeners: []demoListener{
+			{protocol: types.ProtocolEnumHttp, port: 8080, targetGroup: "api-orders"},
+			{protocol: types.ProtocolEnumHttps, port: 8443, targetGroup: "api-payments", certificates: []string{"api.internal.example.com"}},
+		},
+		targetGroups: []demoTargetGroup{
+			{
+				name: "api-orders",
+				targets: []demoTarget{
+					{id: "10.0.12.34", port: 8080, state: types.TargetHealthStateEnumHealthy},
+					{id: "10.0.13.56", port: 8080, state: types.TargetHealthStateEnumHealthy},
+				},
+			},
+			{
+				name: "api-payments",
+				targets: []demoTarget{
+					{id: "10.0.14.78", port: 8443, state: types.TargetHealthStateEnumDraining, reason: types.TargetHealthReasonEnumDeregistrationInProgress},
+					{id: "10.0.15.90", port: 8443, state: types.TargetHealthStateEnumInitial, reason: types.TargetHealthReasonEnumRegistrationInProgress},
+				},
+			},
+		},
+	},
+	{
+		// Leftovers of a decommissioned site
+		name: "marketing-legacy",
+		listeners: []demoListener{
+			{protocol: types.ProtocolEnumHttp, port: 80, targetGroup: "marketing-legacy-http"},
+		},
+		targetGroups: []demoTargetGroup{
+			{name: "marketing-legacy-http"},
+		},
+	},
+	{
+		name: "staging-web",
+	},
+	{
+		// Connection pooler in front of orders-db; pgbouncer-2 fails its
+		// health checks and keeps its connections while draining
+		name:     "pgbouncer",
+		kind:     types.LoadBalancerTypeEnumNetwork,
+		internal: true,
+		listeners: []demoListener{
+			{protocol: types.ProtocolEnumTcp, port: 5432, targetGroup: "pgbouncer-tcp"},
+		},
+		targetGroups: []demoTargetGroup{
+			{
+				name: "pgbouncer-tcp",
+				targets: []demoTarget{
+					{id: "10.0.21.10", port: 6432, state: types.TargetHealthStateEnumHealthy},
+					{id: "10.0.22.10", port: 6432, state: types.TargetHealthStateEnumUnhealthyDraining, reason: types.TargetHealthReasonEnumFailedHealthChecks},
+				},
+			},
+		},
+	},
+	{
+		// Firewall appliances inspecting the traffic of the VPC
+		name:     "inspection",
+		kind:     types.LoadBalancerTypeEnumGateway,
+		internal: true,
+		listeners: []demoListener{
+			{targetGroup: "firewall-appliances"},
+		},
+		targetGroups: []demoTargetGroup{
+			{
+				name: "firewall-appliances",
+				targets: []demoTarget{
+					{id: "i-0a1b2c3d4e5f60007", port: 6081, state: types.TargetHealthStateEnumHealthy},
+					{id: "i-0a1b2c3d4e5f60008", port: 6081, state: types.TargetHealthStateEnumHealthy},
+				},
+			},
+		},
+	},
+}
+
+// ELBv2 is a fixture Elastic Load Balancing v2 API
+type ELBv2 struct{}
+
+// demoTargetHealth holds the health of the fixture targets deregistered or
+// registered since the start by target group ARN and target, shared by all
+// fixture ELBv2 APIs so that a reload shows the outcome of an action
+var demoTargetHealth = struct {
+	sync.Mutex
+	health map[string]types.TargetHealth
+}{health: make(map[string]types.TargetHealth)}
+
+// NewELBv2 returns a fixture Elastic Load Balancing v2 API
+func NewELBv2() *ELBv2 {
+	return &ELBv2{}
+}
+
+func loadBalancerARN(name string) string {
+	return fmt.Sprintf("arn:aws:elasticloadbalancing:%s:%s:loadbalancer/%s/%s/50dc6c495c0c9188", Region, AccountID, arnPrefix(name), name)
+}
+
+// arnPrefix returns the part of the ARNs of a fixture load balancer naming
+// its type, e.g. "net" for a network load balancer
+func arnPrefix(name string) string {
+	for _, lb := range loadBalancers {
+		if lb.name != name {
+			continue
+		}
+		switch lb.kind {
+		case types.LoadBalancerTypeEnumNetwork:
+			return "net"
+		case types.LoadBalancerTypeEnumGateway:
+			return "gwy"
+		}
+	}
+	return "app"
+}
+
+func loadBalancerDNSName(name string) string {
+	return fmt.Sprintf("%s-1234567890.%s.elb.amazonaws.com", name, Region)
+}
+
+func listenerARN(lbName string, port int32) string {
+	return fmt.Sprintf("arn:aws:elasticloadbalancing:%s:%s:listener/%s/%s/50dc6c495c0c9188/%d", Region, AccountID, arnPrefix(lbName), lbName, port)
+}
+
+func targetGroupARN(name string) string {
+	return fmt.Sprintf("arn:aws:elasticloadbalancing:%s:%s:targetgroup/%s/73e2d6bc24d8a067", Region, AccountID, name)
+}
+
+// DescribeLoadBalancers returns the fixture load balancers
+func (e *ELBv2) DescribeLoadBalancers(ctx context.Context, params *elasticloadbalancingv2.DescribeLoadBalancersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error) {
+	output := &elasticloadbalancingv2.DescribeLoadBalancersOutput{}
+	for _, lb := range loadBalancers {
+		scheme := types.LoadBalancerSchemeEnumInternetFacing
+		if lb.internal {
+			scheme = types.LoadBalancerSchemeEnumInternal
+		}
+		kind := lb.kind
+		if kind == "" {
+			kind = types.LoadBalancerTypeEnumApplication
+		}
+		output.LoadBalancers = append(output.LoadBalancers, types.LoadBalancer{
+			LoadBalancerName: aws.String(lb.name),
+			LoadBalancerArn:  aws.String(loadBalancerARN(lb.name)),
+			DNSName:          aws.String(loadBalancerDNSName(lb.name)),
+			Type:             kind,
+			Scheme:           scheme,
+			State:            &types.LoadBalancerState{Code: types.LoadBalancerStateEnumActive},
+		})
+	}
+	return output, nil
+}
+
+// DescribeTargetGroups returns the fixture target groups of a load balancer
+func (e *ELBv2) DescribeTargetGroups(ctx context.Context, params *elasticloadbalancingv2.DescribeTargetGroupsInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTargetGroupsOutput, error) {
+	output := &elasticloadbalancingv2.DescribeTargetGroupsOutput{}
+	for _, lb := range loadBalancers {
+		if params.LoadBalancerArn != nil && *params.LoadBalancerArn != loadBalancerARN(lb.name) {
+			continue
+		}
+		for _, tg := range lb.targetGroups {
+			output.TargetGroups = append(output.TargetGroups, types.TargetGroup{
+				TargetGroupName:  aws.String(tg.name),
+				TargetGroupArn:   aws.String(targetGroupARN(tg.name)),
+				LoadBalancerArns: []string{loadBalancerARN(lb.name)},
+			})
+		}
+	}
+	return output, nil
+}
+
+// DescribeListeners returns the fixture listeners of a load balancer
+func (e *ELBv2) DescribeListeners(ctx context.Context, params *elasticloadbalancingv2.DescribeListenersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeListenersOutput, error) {
+	output := &elasticloadbalancingv2.DescribeListenersOutput{}
+	for _, lb := range loadBalancers {
+		if params.LoadBalancerArn == nil || *params.LoadBalancerArn != loadBalancerARN(lb.name) {
+			continue
+		}
+		for _, listener := range lb.listeners {
+			described := types.Listener{
+				ListenerArn:     aws.String(listenerARN(lb.name, listener.port)),
+				LoadBalancerArn: aws.String(loadBalancerARN(lb.name)),
+				Protocol:        listener.protocol,
+				DefaultActions:  []types.Action{defaultAction(listener)},
+			}
+			// Gateway load balancer listeners have no protocol and port
+			if listener.port != 0 {
+				described.Port = aws.Int32(listener.port)
+			}
+			// Only the default certificate is described with the listener
+			if len(listener.certificates) > 0 {
+				described.Certificates = []types.Certificate{{CertificateArn: aws.String(certificateARN(listener.certificates[0]))}}
+			}
+			output.Listeners = append(output.Listeners, described)
+		}
+	}
+	return output, nil
+}
+
+// defaultAction returns the default action of a fixture listener
+func defaultAction(listener demoListener) types.Action {
+	if listener.targetGroup == "" {
+		return types.Action{
+			Type:           types.ActionTypeEnumRedirect,
+			RedirectConfig: &types.RedirectActionConfig{Protocol: aws.String("HTTPS"), Port: aws.String("443"), StatusCode: types.RedirectActionStatusCodeEnumHttp301},
+		}
+	}
+	return types.Action{Type: types.ActionTypeEnumForward, TargetGroupArn: aws.String(targetGroupARN(listener.targetGroup))}
+}
+
+// DescribeRules returns the fixture rules of a listener, followed by its default rule
+func (e *ELBv2) DescribeRules(ctx context.Context, params *elasticloadbalancingv2.DescribeRulesInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeRulesOutput, error) {
+	output := &elasticloadbalancingv2.DescribeRulesOutput{}
+	for _, lb := range loadBalancers {
+		for _, listener := range lb.listeners {
+			if params.ListenerArn == nil || *params.ListenerArn != listenerARN(lb.name, listener.port) {
+				continue
+			}
+
+			for _, rule := range listener.rules {
+				conditions := []types.RuleCondition{
+					{Field: aws.String("host-header"), HostHeaderConfig: &types.HostHeaderConditionConfig{Values: []string{rule.host}}},
+				}
+				if rule.path != "" {
+					conditions = append(conditions, types.RuleCondition{Field: aws.String("path-pattern"), PathPatternConfig: &types.PathPatternConditionConfig{Values: []string{rule.path}}})
+				}
+				if name, value, ok := strings.Cut(rule.header, ":"); ok {
+					conditions = append(conditions, types.RuleCondition{Field: aws.String("http-header"), HttpHeaderConfig: &types.HttpHeaderConditionConfig{HttpHeaderName: aws.String(name), Values: []string{value}}})
+				}
+
+				output.Rules = append(output.Rules, types.Rule{
+					Priority:   aws.String(rule.priority),
+					IsDefault:  aws.Bool(false),
+					Conditions: conditions,
+					Actions:    []types.Action{{Type: types.ActionTypeEnumForward, TargetGroupArn: aws.String(targetGroupARN(rule.targetGroup))}},
+				})
+			}
+
+			output.Rules = append(output.Rules, types.Rule{
+				Priority:  aws.String("default"),
+				IsDefault: aws.Bool(true),
+				Actions:   []types.Action{defaultAction(listener)},
+			})
+		}
+	}
+	return output, nil
+}
+
+// DescribeListenerCertificates returns the fixture certificates of a listener
+func (e *ELBv2) DescribeListenerCertificates(ctx context.Context, params *elasticloadbalancingv2.DescribeListenerCertificatesInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeListenerCertificatesOutput, error) {
+	output := &elasticloadbalancingv2.DescribeListenerCertificatesOutput{}
+	for _, lb := range loadBalancers {
+		for _, listener := range lb.listeners {
+			if params.ListenerArn == nil || *params.ListenerArn != listenerARN(lb.name, listener.port) {
+				continue
+			}
+			for i, domain := range listener.certificates {
+				output.Certificates = append(output.Certificates, types.Certificate{
+					CertificateArn: aws.String(certificateARN(domain)),
+					IsDefault:      aws.Bool(i == 0),
+				})
+			}
+		}
+	}
+	return output, nil
+}
+
+// DescribeTargetHealth returns the fixture target health of a target group
+func (e *ELBv2) DescribeTargetHealth(ctx context.Context, params *elasticloadbalancingv2.DescribeTargetHealthInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTargetHealthOutput, error) {
+	output := &elasticloadbalancingv2.DescribeTargetHealthOutput{}
+	for _, lb := range loadBalancers {
+		for _, tg := range lb.targetGroups {
+			if params.TargetGroupArn == nil || *params.TargetGroupArn != targetGroupARN(tg.name) {
+				continue
+			}
+			demoTargetHealth.Lock()
+			for _, target := range tg.targets {
+				health := types.TargetHealth{State: target.state, Reason: target.reason}
+				if changed, ok := demoTargetHealth.health[demoTargetKey(*params.TargetGroupArn, target.id, target.port)]; ok {
+					health = changed
+				}
+				output.TargetHealthDescriptions = append(output.TargetHealthDescriptions, types.TargetHealthDescription{
+					Target: &types.TargetDescription{
+						Id:   aws.String(target.id),
+						Port: aws.Int32(target.port),
+					},
+					TargetHealth: &health,
+				})
+			}
+			demoTargetHealth.Unlock()
+		}
+	}
+	return output, nil
+}
+
+// DeregisterTargets leaves the fixture targets draining, as they would for
+// the deregistration delay of their target group
+func (e *ELBv2) DeregisterTargets(ctx context.Context, params *elasticloadbalancingv2.DeregisterTargetsInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DeregisterTargetsOutput, error) {
+	changeDemoTargetHealth(aws.ToString(params.TargetGroupArn), params.Targets, types.TargetHealth{
+		State:  types.TargetHealthStateEnumDraining,
+		Reason: types.TargetHealthReasonEnumDeregistrationInProgress,
+	})
+	return &elasticloadbalancingv2.DeregisterTargetsOutput{}, nil
+}
+
+// RegisterTargets leaves the fixture targets in their initial health checks
+func (e *ELBv2) RegisterTargets(ctx context.Context, params *elasticloadbalancingv2.RegisterTargetsInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.RegisterTargetsOutput, error) {
+	changeDemoTargetHealth(aws.ToString(params.TargetGroupArn), params.Targets, types.TargetHealth{
+		State:  types.TargetHealthStateEnumInitial,
+		Reason: types.TargetHealthReasonEnumRegistrationInProgress,
+	})
+	return &elasticloadbalancingv2.RegisterTargetsOutput{}, nil
+}
+
+// changeDemoTargetHealth records the health of fixture targets of a target group
+func changeDemoTargetHealth(targetGroupARN string, targets []types.TargetDescription, health types.TargetHealth) {
+	demoTargetHealth.Lock()
+	defer demoTargetHealth.Unlock()
+
+	for _, target := range targets {
+		demoTargetHealth.health[demoTargetKey(targetGroupARN, aws.ToString(target.Id), aws.ToInt32(target.Port))] = health
+	}
+}
+
+// demoTargetKey identifies a fixture target of a target group
+func demoTargetKey(targetGroupARN, id string, port int32) string {
+	return fmt.Sprintf("%s/%s:%d", targetGroupARN, id, port)
+}
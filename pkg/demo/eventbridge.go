@@ -0,0 +1,92 @@
+package demo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+)
+
+// eventRules lists the fixture EventBridge rules of the default bus
+var eventRules = []string{"nightly-import-schedule", "send-digest", "orders-to-analytics"}
+
+// ruleTargets are the targets of the fixture rules: the scheduled rules run
+// ECS tasks, the other forwards order events to a Kinesis stream
+var ruleTargets = map[string]struct {
+	schedule string
+	cluster  string
+	task     string
+}{
+	"nightly-import-schedule": {"cron(0 2 * * ? *)", "staging", "nightly-import"},
+	"send-digest":             {"rate(1 hour)", "production", "email-worker"},
+	"orders-to-analytics":     {},
+}
+
+// EventBridge is a fixture EventBridge API
+type EventBridge struct{}
+
+// NewEventBridge returns a fixture EventBridge API
+func NewEventBridge() *EventBridge {
+	return &EventBridge{}
+}
+
+// ListRules returns the fixture rules of the default bus
+func (e *EventBridge) ListRules(ctx context.Context, params *eventbridge.ListRulesInput, optFns ...func(*eventbridge.Options)) (*eventbridge.ListRulesOutput, error) {
+	output := &eventbridge.ListRulesOutput{}
+	for _, name := range eventRules {
+		rule := types.Rule{
+			Name:         aws.String(name),
+			Arn:          aws.String(fmt.Sprintf("arn:aws:events:%s:%s:rule/%s", Region, AccountID, name)),
+			EventBusName: aws.String("default"),
+			State:        types.RuleStateEnabled,
+		}
+		if schedule := ruleTargets[name].schedule; schedule != "" {
+			rule.ScheduleExpression = aws.String(schedule)
+		} else {
+			rule.EventPattern = aws.String(`{"source":["orders"]}`)
+		}
+		output.Rules = append(output.Rules, rule)
+	}
+	return output, nil
+}
+
+// ListTargetsByRule returns the target of a fixture rule
+func (e *EventBridge) ListTargetsByRule(ctx context.Context, params *eventbridge.ListTargetsByRuleInput, optFns ...func(*eventbridge.Options)) (*eventbridge.ListTargetsByRuleOutput, error) {
+	name := aws.ToString(params.Rule)
+	target, ok := ruleTargets[name]
+	if !ok {
+		return nil, &types.ResourceNotFoundException{Message: aws.String(fmt.Sprintf("Rule %s does not exist.", name))}
+	}
+
+	if target.cluster == "" {
+		return &eventbridge.ListTargetsByRuleOutput{Targets: []types.Target{{
+			Id:  aws.String("analytics"),
+			Arn: aws.String(fmt.Sprintf("arn:aws:kinesis:%s:%s:stream/order-events", Region, AccountID)),
+		}}}, nil
+	}
+	return &eventbridge.ListTargetsByRuleOutput{Targets: []types.Target{{
+		Id:      aws.String(target.task),
+		Arn:     aws.String(clusterARN(target.cluster)),
+		RoleArn: aws.String(fmt.Sprintf("arn:aws:iam::%s:role/ecsEventsRole", AccountID)),
+		EcsParameters: &types.EcsParameters{
+			TaskDefinitionArn: aws.String(fmt.Sprintf("arn:aws:ecs:%s:%s:task-definition/%s:42", Region, AccountID, target.task)),
+			TaskCount:         aws.Int32(1),
+			LaunchType:        types.LaunchTypeFargate,
+		},
+	}}}, nil
+}
+
+// DisableRule accepts disabling a fixture rule of the default bus
+func (e *EventBridge) DisableRule(ctx context.Context, params *eventbridge.DisableRuleInput, optFns ...func(*eventbridge.Options)) (*eventbridge.DisableRuleOutput, error) {
+	bus := aws.ToString(params.EventBusName)
+	if bus == "" || bus == "default" {
+		for _, rule := range eventRules {
+			if rule == aws.ToString(params.Name) {
+				return &eventbridge.DisableRuleOutput{}, nil
+			}
+		}
+	}
+	return nil, &types.ResourceNotFoundException{Message: aws.String(fmt.Sprintf("Rule %s does not exist.", aws.ToString(params.Name)))}
+}
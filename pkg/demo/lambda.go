@@ -0,0 +1,100 @@
+package demo
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+)
+
+// demoFunction is a fixture Lambda function
+type demoFunction struct {
+	name    string
+	runtime types.Runtime // Empty for container images
+	memory  int32
+	timeout int32
+	fails   bool // Invocations throw an unhandled error
+}
+
+var functions = []demoFunction{
+	{name: "order-audit", runtime: types.RuntimePython312, memory: 256, timeout: 30},
+	{name: "thumbnail-resize", runtime: types.RuntimeNodejs20x, memory: 1024, timeout: 60},
+	{name: "report-export", memory: 2048, timeout: 900, fails: true},
+}
+
+// Lambda is a fixture Lambda API
+type Lambda struct{}
+
+// NewLambda returns a fixture Lambda API
+func NewLambda() *Lambda {
+	return &Lambda{}
+}
+
+// ListFunctions returns the fixture functions
+func (l *Lambda) ListFunctions(ctx context.Context, params *lambda.ListFunctionsInput, optFns ...func(*lambda.Options)) (*lambda.ListFunctionsOutput, error) {
+	output := &lambda.ListFunctionsOutput{}
+	for _, function := range functions {
+		output.Functions = append(output.Functions, types.FunctionConfiguration{
+			FunctionName: aws.String(function.name),
+			FunctionArn:  aws.String(fmt.Sprintf("arn:aws:lambda:%s:%s:function:%s", Region, AccountID, function.name)),
+			Runtime:      function.runtime,
+			MemorySize:   aws.Int32(function.memory),
+			Timeout:      aws.Int32(function.timeout),
+			State:        types.StateActive,
+			LastModified: aws.String(timeNow().Add(-72 * time.Hour).Format("2006-01-02T15:04:05.000-0700")),
+		})
+	}
+	return output, nil
+}
+
+// Invoke echoes the payload back, or fails for functions that throw
+func (l *Lambda) Invoke(ctx context.Context, params *lambda.InvokeInput, optFns ...func(*lambda.Options)) (*lambda.InvokeOutput, error) {
+	for _, function := range functions {
+		if function.name != aws.ToString(params.FunctionName) {
+			continue
+		}
+
+		logs := "START RequestId: 8f5c2a1e-demo Version: $LATEST\n"
+		output := &lambda.InvokeOutput{StatusCode: 200, ExecutedVersion: aws.String("$LATEST")}
+		if function.fails {
+			logs += "[ERROR] TimeoutError: export bucket not reachable\n"
+			output.FunctionError = aws.String("Unhandled")
+			output.Payload = []byte(`{"errorMessage": "export bucket not reachable", "errorType": "TimeoutError"}`)
+		} else {
+			logs += fmt.Sprintf("INFO received %d bytes\n", len(params.Payload))
+			output.Payload = []byte(fmt.Sprintf(`{"statusCode": 200, "echo": %s}`, params.Payload))
+		}
+		logs += "END RequestId: 8f5c2a1e-demo\n"
+		logs += fmt.Sprintf("REPORT RequestId: 8f5c2a1e-demo\tDuration: 41.27 ms\tBilled Duration: 42 ms\tMemory Size: %d MB\tMax Memory Used: 88 MB\n", function.memory)
+
+		if params.LogType == types.LogTypeTail {
+			output.LogResult = aws.String(base64.StdEncoding.EncodeToString([]byte(logs)))
+		}
+		return output, nil
+	}
+	return nil, fmt.Errorf("ResourceNotFoundException: Function not found: %s", aws.ToString(params.FunctionName))
+}
+
+// ListEventSourceMappings returns fixture mappings: order-audit consumes the
+// stream of the audit table and thumbnail-resize an SQS queue
+func (l *Lambda) ListEventSourceMappings(ctx context.Context, params *lambda.ListEventSourceMappingsInput, optFns ...func(*lambda.Options)) (*lambda.ListEventSourceMappingsOutput, error) {
+	functionARN := func(name string) *string {
+		return aws.String(fmt.Sprintf("arn:aws:lambda:%s:%s:function:%s", Region, AccountID, name))
+	}
+	return &lambda.ListEventSourceMappingsOutput{EventSourceMappings: []types.EventSourceMappingConfiguration{
+		{
+			EventSourceArn: aws.String(fmt.Sprintf("arn:aws:dynamodb:%s:%s:table/audit/stream/2024-01-01T00:00:00.000", Region, AccountID)),
+			FunctionArn:    functionARN("order-audit"),
+			State:          aws.String("Enabled"),
+		},
+		{
+			EventSourceArn: aws.String(fmt.Sprintf("arn:aws:sqs:%s:%s:thumbnails", Region, AccountID)),
+			FunctionArn:    functionARN("thumbnail-resize"),
+			State:          aws.String("Enabled"),
+		},
+	}}, nil
+}
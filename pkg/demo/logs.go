@@ -0,0 +1,85 @@
+package demo
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+// demoLogEvent is a fixture log event
+type demoLogEvent struct {
+	stream  string
+	ago     time.Duration
+	message string
+}
+
+// logGroups are the fixture log groups with their error events. Groups of
+// the ECS services and Lambda functions missing here have no errors.
+var logGroups = map[string][]demoLogEvent{
+	"/ecs/orders-api": nil,
+	"/ecs/payments-api": {
+		{"ecs/app/4f1c9a", 41 * time.Minute, `ERROR payment provider timeout after 10s (order 81723)`},
+		{"ecs/app/4f1c9a", 17 * time.Minute, `ERROR payment provider timeout after 10s (order 81790)`},
+		{"ecs/app/9b2e77", 5 * time.Minute, `ERROR payment provider timeout after 10s (order 81802)`},
+	},
+	"/ecs/email-worker": {
+		{"ecs/app/c07d12", 58 * time.Minute, "panic: dial tcp 10.0.3.17:587: connect: connection refused\n\ngoroutine 1 [running]:\nmain.main()\n\t/src/cmd/worker/main.go:42 +0x1d4"},
+		{"ecs/app/e81a40", 27 * time.Minute, "panic: dial tcp 10.0.3.17:587: connect: connection refused\n\ngoroutine 1 [running]:\nmain.main()\n\t/src/cmd/worker/main.go:42 +0x1d4"},
+	},
+	"/aws/lambda/order-audit":      nil,
+	"/aws/lambda/thumbnail-resize": nil,
+	"/aws/lambda/report-export": {
+		{"2024/05/10/[$LATEST]a1b2c3", 33 * time.Minute, `[ERROR] MemoryError: Unable to allocate 1.2 GiB for the export buffer`},
+		{"2024/05/10/[$LATEST]a1b2c3", 3 * time.Minute, `[ERROR] MemoryError: Unable to allocate 1.2 GiB for the export buffer`},
+	},
+	"/aws/rds/instance/orders-db/postgresql": {
+		{"orders-db.0", 12 * time.Minute, `2024-05-10 11:48:02 UTC:10.0.1.23(51234):orders@orders:[8812]:ERROR:  deadlock detected`},
+	},
+	"/aws/rds/instance/orders-db/upgrade": nil,
+}
+
+// CloudWatchLogs is a fixture CloudWatch Logs API
+type CloudWatchLogs struct{}
+
+// NewCloudWatchLogs returns a fixture CloudWatch Logs API
+func NewCloudWatchLogs() *CloudWatchLogs {
+	return &CloudWatchLogs{}
+}
+
+// FilterLogEvents returns the fixture error events of a log group since the
+// start time, in the streams with the prefix if one is given
+func (l *CloudWatchLogs) FilterLogEvents(ctx context.Context, params *cloudwatchlogs.FilterLogEventsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.FilterLogEventsOutput, error) {
+	events, ok := logGroups[aws.ToString(params.LogGroupName)]
+	if !ok {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("The specified log group does not exist.")}
+	}
+
+	output := &cloudwatchlogs.FilterLogEventsOutput{}
+	for _, event := range events {
+		timestamp := timeNow().Add(-event.ago).UnixMilli()
+		if timestamp < aws.ToInt64(params.StartTime) || !strings.HasPrefix(event.stream, aws.ToString(params.LogStreamNamePrefix)) {
+			continue
+		}
+		output.Events = append(output.Events, types.FilteredLogEvent{
+			LogStreamName: aws.String(event.stream),
+			Timestamp:     aws.Int64(timestamp),
+			Message:       aws.String(event.message),
+		})
+	}
+	return output, nil
+}
+
+// DescribeLogGroups returns the fixture log groups starting with the prefix
+func (l *CloudWatchLogs) DescribeLogGroups(ctx context.Context, params *cloudwatchlogs.DescribeLogGroupsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
+	output := &cloudwatchlogs.DescribeLogGroupsOutput{}
+	for name := range logGroups {
+		if strings.HasPrefix(name, aws.ToString(params.LogGroupNamePrefix)) {
+			output.LogGroups = append(output.LogGroups, types.LogGroup{LogGroupName: aws.String(name)})
+		}
+	}
+	return output, nil
+}
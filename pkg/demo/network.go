@@ -0,0 +1,66 @@
+package demo
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// endpoints are the addresses of the fixture load balancers and instances
+// that answer probes, with the status code of their HTTP responses. The
+// other addresses time out, as if a security group dropped the packets.
+var endpoints = map[string]int{
+	loadBalancerDNSName("web-prod") + ":443":        http.StatusOK,
+	loadBalancerDNSName("web-prod") + ":80":         http.StatusMovedPermanently,
+	loadBalancerDNSName("marketing-legacy") + ":80": http.StatusServiceUnavailable,
+	"3.91.44.201:22": 0,
+}
+
+// latency is how long the fixture endpoints take to answer
+const latency = 20 * time.Millisecond
+
+// timeoutError is the error of a probe of an address that does not answer
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// Network is a fixture network answering the probes of the fixture load
+// balancers and instances
+type Network struct{}
+
+// NewNetwork returns a fixture network
+func NewNetwork() *Network {
+	return &Network{}
+}
+
+// DialContext connects to the fixture endpoints
+func (n *Network) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if _, ok := endpoints[address]; !ok {
+		return nil, &net.OpError{Op: "dial", Net: network, Err: timeoutError{}}
+	}
+	time.Sleep(latency)
+	client, server := net.Pipe()
+	server.Close()
+	return client, nil
+}
+
+// Do answers HTTP requests to the fixture endpoints
+func (n *Network) Do(req *http.Request) (*http.Response, error) {
+	code, ok := endpoints[req.URL.Host]
+	if !ok {
+		return nil, &url.Error{Op: req.Method, URL: req.URL.String(), Err: timeoutError{}}
+	}
+	time.Sleep(latency)
+	return &http.Response{
+		StatusCode: code,
+		Status:     http.StatusText(code),
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
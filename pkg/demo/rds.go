@@ -0,0 +1,140 @@
+package demo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+// RDS is a fixture RDS API
+type RDS struct{}
+
+// NewRDS returns a fixture RDS API
+func NewRDS() *RDS {
+	return &RDS{}
+}
+
+// instanceEvents are the fixture RDS events of the past hour, keyed by DB
+// instance
+var instanceEvents = map[string][]struct {
+	ago     time.Duration
+	message string
+}{
+	"orders-db": {
+		{54 * time.Minute, "Applying modification to database instance parameters"},
+		{51 * time.Minute, "Finished applying modification to DB instance parameters"},
+	},
+}
+
+// DescribeEvents returns the fixture events of a DB instance since the start
+// time
+func (r *RDS) DescribeEvents(ctx context.Context, params *rds.DescribeEventsInput, optFns ...func(*rds.Options)) (*rds.DescribeEventsOutput, error) {
+	output := &rds.DescribeEventsOutput{}
+	for _, event := range instanceEvents[aws.ToString(params.SourceIdentifier)] {
+		date := timeNow().Add(-event.ago)
+		if params.StartTime != nil && date.Before(*params.StartTime) {
+			continue
+		}
+		output.Events = append(output.Events, types.Event{
+			Date:             aws.Time(date),
+			Message:          aws.String(event.message),
+			SourceIdentifier: params.SourceIdentifier,
+			SourceType:       types.SourceTypeDbInstance,
+		})
+	}
+	return output, nil
+}
+
+// DescribeDBInstances returns the fixture DB instances
+func (r *RDS) DescribeDBInstances(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+	instances := []struct {
+		identifier string
+		engine     string
+		class      string
+		status     string
+		port       int32
+		storage    int32 // GB
+		maxStorage int32 // GB, 0 without storage autoscaling
+		iops       int32 // 0 for gp2
+
+		ca               string
+		certificateValid time.Duration // Until the server certificate expires
+	}{
+		{"orders-db", "postgres", "db.r6g.large", "available", 5432, 500, 1000, 12000, "rds-ca-rsa2048-g1", 700 * 24 * time.Hour},
+		// analytics-db still uses the CA certificate that expires in three weeks
+		{"analytics-db", "mysql", "db.t3.medium", "available", 3306, 200, 0, 0, "rds-ca-2019", 21 * 24 * time.Hour},
+		{"legacy-reports", "mysql", "db.t3.small", "stopped", 3306, 20, 0, 0, "rds-ca-rsa2048-g1", 400 * 24 * time.Hour},
+	}
+
+	output := &rds.DescribeDBInstancesOutput{}
+	for _, instance := range instances {
+		dbInstance := types.DBInstance{
+			DBInstanceIdentifier: aws.String(instance.identifier),
+			DBInstanceArn:        aws.String(dbInstanceARN(instance.identifier)),
+			Engine:               aws.String(instance.engine),
+			DBInstanceClass:      aws.String(instance.class),
+			DBInstanceStatus:     aws.String(instance.status),
+			Endpoint: &types.Endpoint{
+				Address: aws.String(fmt.Sprintf("%s.c9akciq32.%s.rds.amazonaws.com", instance.identifier, Region)),
+				Port:    aws.Int32(instance.port),
+			},
+			AllocatedStorage: aws.Int32(instance.storage),
+			StorageType:      aws.String("gp2"),
+			CertificateDetails: &types.CertificateDetails{
+				CAIdentifier: aws.String(instance.ca),
+				ValidTill:    aws.Time(timeNow().Add(instance.certificateValid).Truncate(time.Hour)),
+			},
+		}
+		if instance.maxStorage > 0 {
+			dbInstance.MaxAllocatedStorage = aws.Int32(instance.maxStorage)
+		}
+		if instance.iops > 0 {
+			dbInstance.StorageType = aws.String("gp3")
+			dbInstance.Iops = aws.Int32(instance.iops)
+		}
+		// orders-db keeps a standby in another zone and a read replica in
+		// us-west-2 for disaster recovery
+		if instance.identifier == "orders-db" {
+			dbInstance.MultiAZ = aws.Bool(true)
+			dbInstance.ReadReplicaDBInstanceIdentifiers = []string{fmt.Sprintf("arn:aws:rds:us-west-2:%s:db:orders-db-dr", AccountID)}
+		}
+		output.DBInstances = append(output.DBInstances, dbInstance)
+	}
+	return output, nil
+}
+
+// dbInstanceARN returns the ARN of a fixture DB instance
+func dbInstanceARN(identifier string) string {
+	return fmt.Sprintf("arn:aws:rds:%s:%s:db:%s", Region, AccountID, identifier)
+}
+
+// DescribePendingMaintenanceActions returns the fixture maintenance: an OS
+// update of orders-db applied in its window in three days, and an engine
+// upgrade forced on legacy-reports in six weeks
+func (r *RDS) DescribePendingMaintenanceActions(ctx context.Context, params *rds.DescribePendingMaintenanceActionsInput, optFns ...func(*rds.Options)) (*rds.DescribePendingMaintenanceActionsOutput, error) {
+	now := timeNow().Truncate(time.Hour)
+	return &rds.DescribePendingMaintenanceActionsOutput{
+		PendingMaintenanceActions: []types.ResourcePendingMaintenanceActions{
+			{
+				ResourceIdentifier: aws.String(dbInstanceARN("orders-db")),
+				PendingMaintenanceActionDetails: []types.PendingMaintenanceAction{{
+					Action:               aws.String("system-update"),
+					Description:          aws.String("New Operating System update is available"),
+					AutoAppliedAfterDate: aws.Time(now.Add(3 * 24 * time.Hour)),
+				}},
+			},
+			{
+				ResourceIdentifier: aws.String(dbInstanceARN("legacy-reports")),
+				PendingMaintenanceActionDetails: []types.PendingMaintenanceAction{{
+					Action:          aws.String("db-upgrade"),
+					Description:     aws.String("Upgrade to MySQL 8.0.36"),
+					ForcedApplyDate: aws.Time(now.Add(42 * 24 * time.Hour)),
+				}},
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,71 @@
+package demo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+)
+
+// demoRecord is a fixture DNS record; alias records have a single value
+type demoRecord struct {
+	name   string
+	rrType types.RRType
+	alias  bool
+	value  string
+}
+
+var hostedZoneRecords = []demoRecord{
+	{"example.com.", types.RRTypeMx, false, "10 inbound-smtp.us-east-1.amazonaws.com"},
+	{"www.example.com.", types.RRTypeA, true, fmt.Sprintf("dualstack.web-prod-1234567890.%s.elb.amazonaws.com.", Region)},
+	{"api.example.com.", types.RRTypeCname, false, fmt.Sprintf("api-internal-1234567890.%s.elb.amazonaws.com", Region)},
+	{"cdn.example.com.", types.RRTypeCname, false, "d111111abcdef8.cloudfront.net"},
+	{"bastion.example.com.", types.RRTypeA, false, "3.91.44.201"},
+	{"vpn.example.com.", types.RRTypeA, false, "198.51.100.7"},
+	// Left behind when the resources were deleted
+	{"promo.example.com.", types.RRTypeA, true, fmt.Sprintf("dualstack.promo-2019-987654321.%s.elb.amazonaws.com.", Region)},
+	{"assets.example.com.", types.RRTypeCname, false, "d2c8xyzoldassets.cloudfront.net"},
+	{"ftp.example.com.", types.RRTypeCname, false, "ec2-52-4-17-90.compute-1.amazonaws.com"},
+}
+
+// Route53 is a fixture Route53 API with a single hosted zone
+type Route53 struct{}
+
+// NewRoute53 returns a fixture Route53 API
+func NewRoute53() *Route53 {
+	return &Route53{}
+}
+
+// ListHostedZones returns the fixture hosted zone
+func (r *Route53) ListHostedZones(ctx context.Context, params *route53.ListHostedZonesInput, optFns ...func(*route53.Options)) (*route53.ListHostedZonesOutput, error) {
+	return &route53.ListHostedZonesOutput{
+		HostedZones: []types.HostedZone{
+			{Id: aws.String("/hostedzone/Z0123456789ABCDEFGHIJ"), Name: aws.String("example.com.")},
+		},
+	}, nil
+}
+
+// ListResourceRecordSets returns the fixture records of the hosted zone
+func (r *Route53) ListResourceRecordSets(ctx context.Context, params *route53.ListResourceRecordSetsInput, optFns ...func(*route53.Options)) (*route53.ListResourceRecordSetsOutput, error) {
+	output := &route53.ListResourceRecordSetsOutput{}
+	for _, record := range hostedZoneRecords {
+		set := types.ResourceRecordSet{
+			Name: aws.String(record.name),
+			Type: record.rrType,
+		}
+		if record.alias {
+			set.AliasTarget = &types.AliasTarget{
+				DNSName:              aws.String(record.value),
+				HostedZoneId:         aws.String("Z35SXDOTRQ7X7K"),
+				EvaluateTargetHealth: true,
+			}
+		} else {
+			set.TTL = aws.Int64(300)
+			set.ResourceRecords = []types.ResourceRecord{{Value: aws.String(record.value)}}
+		}
+		output.ResourceRecordSets = append(output.ResourceRecordSets, set)
+	}
+	return output, nil
+}
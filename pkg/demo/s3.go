@@ -0,0 +1,71 @@
+package demo
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// bucketReplication maps the fixture buckets to the bucket they replicate to, if any
+var bucketReplication = map[string]string{
+	"example-assets":  "example-assets-dr",
+	"example-backups": "example-backups-dr",
+	"example-logs":    "",
+}
+
+// bucketLocations are the regions of the fixture replication destinations
+var bucketLocations = map[string]types.BucketLocationConstraint{
+	"example-assets-dr":  types.BucketLocationConstraintUsWest2,
+	"example-backups-dr": types.BucketLocationConstraintUsWest2,
+}
+
+// S3 is a fixture S3 API
+type S3 struct{}
+
+// NewS3 returns a fixture S3 API
+func NewS3() *S3 {
+	return &S3{}
+}
+
+// ListBuckets returns the fixture buckets
+func (s *S3) ListBuckets(ctx context.Context, params *s3.ListBucketsInput, optFns ...func(*s3.Options)) (*s3.ListBucketsOutput, error) {
+	output := &s3.ListBucketsOutput{}
+	for _, name := range []string{"example-assets", "example-backups", "example-logs"} {
+		output.Buckets = append(output.Buckets, types.Bucket{
+			Name:         aws.String(name),
+			BucketRegion: aws.String(Region),
+			CreationDate: ago(400 * 24 * time.Hour),
+		})
+	}
+	return output, nil
+}
+
+// GetBucketReplication returns the replication rule of a fixture bucket
+func (s *S3) GetBucketReplication(ctx context.Context, params *s3.GetBucketReplicationInput, optFns ...func(*s3.Options)) (*s3.GetBucketReplicationOutput, error) {
+	destination := bucketReplication[aws.ToString(params.Bucket)]
+	if destination == "" {
+		return nil, &smithy.GenericAPIError{Code: "ReplicationConfigurationNotFoundError", Message: "The replication configuration was not found"}
+	}
+	return &s3.GetBucketReplicationOutput{
+		ReplicationConfiguration: &types.ReplicationConfiguration{
+			Role: aws.String("arn:aws:iam::" + AccountID + ":role/s3-replication"),
+			Rules: []types.ReplicationRule{
+				{
+					ID:          aws.String("dr"),
+					Status:      types.ReplicationRuleStatusEnabled,
+					Destination: &types.Destination{Bucket: aws.String("arn:aws:s3:::" + destination)},
+				},
+			},
+		},
+	}, nil
+}
+
+// GetBucketLocation returns the region of a fixture bucket
+func (s *S3) GetBucketLocation(ctx context.Context, params *s3.GetBucketLocationInput, optFns ...func(*s3.Options)) (*s3.GetBucketLocationOutput, error) {
+	// Buckets in us-east-1 have no location constraint
+	return &s3.GetBucketLocationOutput{LocationConstraint: bucketLocations[aws.ToString(params.Bucket)]}, nil
+}
@@ -0,0 +1,103 @@
+package demo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+)
+
+// fixtureSubscription describes a subscription of a fixture topic
+type fixtureSubscription struct {
+	topic      string
+	protocol   string
+	endpoint   string
+	attributes map[string]string // nil for subscriptions pending confirmation
+}
+
+// topicNames lists the fixture topics
+var topicNames = []string{"order-events", "payment-events", "alarms"}
+
+// topicSubscriptions holds the fixture subscriptions. order-events fans out to
+// several queues, one of them filtered and one in a partner account that has
+// not confirmed its subscription.
+var topicSubscriptions = []fixtureSubscription{
+	{topic: "order-events", protocol: "sqs", endpoint: "orders", attributes: map[string]string{"RawMessageDelivery": "true"}},
+	{topic: "order-events", protocol: "sqs", endpoint: "emails", attributes: map[string]string{
+		"RawMessageDelivery": "false",
+		"FilterPolicy":       `{"eventType": ["OrderShipped", "OrderDelivered"]}`,
+		"FilterPolicyScope":  "MessageAttributes",
+	}},
+	{topic: "order-events", protocol: "sqs", endpoint: "arn:aws:sqs:us-east-1:210987654321:partner-orders"},
+	{topic: "order-events", protocol: "lambda", endpoint: "order-audit", attributes: map[string]string{}},
+	{topic: "payment-events", protocol: "sqs", endpoint: "payments.fifo", attributes: map[string]string{"RawMessageDelivery": "true"}},
+	{topic: "alarms", protocol: "email", endpoint: "oncall@example.com", attributes: map[string]string{}},
+}
+
+// SNS is a fixture SNS API
+type SNS struct{}
+
+// NewSNS returns a fixture SNS API
+func NewSNS() *SNS {
+	return &SNS{}
+}
+
+func topicARN(name string) string {
+	return fmt.Sprintf("arn:aws:sns:%s:%s:%s", Region, AccountID, name)
+}
+
+func subscriptionARN(index int) string {
+	return fmt.Sprintf("%s:%08d-0000-0000-0000-000000000000", topicARN(topicSubscriptions[index].topic), index)
+}
+
+// ListTopics returns the fixture topics
+func (s *SNS) ListTopics(ctx context.Context, params *sns.ListTopicsInput, optFns ...func(*sns.Options)) (*sns.ListTopicsOutput, error) {
+	output := &sns.ListTopicsOutput{}
+	for _, name := range topicNames {
+		output.Topics = append(output.Topics, types.Topic{TopicArn: aws.String(topicARN(name))})
+	}
+	return output, nil
+}
+
+// ListSubscriptions returns the fixture subscriptions of all topics
+func (s *SNS) ListSubscriptions(ctx context.Context, params *sns.ListSubscriptionsInput, optFns ...func(*sns.Options)) (*sns.ListSubscriptionsOutput, error) {
+	output := &sns.ListSubscriptionsOutput{}
+	for i, fixture := range topicSubscriptions {
+		endpoint := fixture.endpoint
+		switch fixture.protocol {
+		case "sqs":
+			if !strings.HasPrefix(endpoint, "arn:") {
+				endpoint = fmt.Sprintf("arn:aws:sqs:%s:%s:%s", Region, AccountID, endpoint)
+			}
+		case "lambda":
+			endpoint = fmt.Sprintf("arn:aws:lambda:%s:%s:function:%s", Region, AccountID, endpoint)
+		}
+
+		arn := "PendingConfirmation"
+		if fixture.attributes != nil {
+			arn = subscriptionARN(i)
+		}
+
+		output.Subscriptions = append(output.Subscriptions, types.Subscription{
+			TopicArn:        aws.String(topicARN(fixture.topic)),
+			Protocol:        aws.String(fixture.protocol),
+			Endpoint:        aws.String(endpoint),
+			SubscriptionArn: aws.String(arn),
+			Owner:           aws.String(AccountID),
+		})
+	}
+	return output, nil
+}
+
+// GetSubscriptionAttributes returns the attributes of a fixture subscription
+func (s *SNS) GetSubscriptionAttributes(ctx context.Context, params *sns.GetSubscriptionAttributesInput, optFns ...func(*sns.Options)) (*sns.GetSubscriptionAttributesOutput, error) {
+	for i, fixture := range topicSubscriptions {
+		if fixture.attributes != nil && subscriptionARN(i) == aws.ToString(params.SubscriptionArn) {
+			return &sns.GetSubscriptionAttributesOutput{Attributes: fixture.attributes}, nil
+		}
+	}
+	return nil, &types.NotFoundException{Message: aws.String("Subscription does not exist")}
+}
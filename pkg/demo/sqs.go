@@ -0,0 +1,176 @@
+package demo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// queueAttributes holds the fixture attributes of each queue, keyed by name
+var queueAttributes = map[string]map[string]string{
+	"orders": {
+		"ApproximateNumberOfMessages": "15",
+		"RedrivePolicy":               fmt.Sprintf(`{"deadLetterTargetArn":"arn:aws:sqs:%s:%s:orders-dlq","maxReceiveCount":5}`, Region, AccountID),
+	},
+	"orders-dlq": {
+		"ApproximateNumberOfMessages": "12",
+	},
+	"emails": {
+		"ApproximateNumberOfMessages": "900",
+	},
+	"payments.fifo": {
+		"ApproximateNumberOfMessages": "1",
+		"FifoQueue":                   "true",
+	},
+}
+
+// queueMessages holds the fixture messages peeked at in each queue, keyed by
+// name, with how long ago they were sent
+var queueMessages = map[string][]struct {
+	body string
+	sent time.Duration
+}{
+	"orders": {
+		{`{"orderId":"ord-10432","customerId":"c-881","total":42.5}`, 3 * time.Minute},
+		{`{"orderId":"ord-10433","customerId":"c-102","total":7.99}`, 2 * time.Minute},
+	},
+	"orders-dlq": {
+		{`{"orderId":"ord-10391","customerId":"c-417","total":129,"error":"payment declined"}`, 5 * time.Hour},
+		{`{"orderId":"ord-10397","customerId":"c-55","total":18.2,"error":"inventory timeout"}`, 4 * time.Hour},
+		{`{"orderId":"ord-10402","customerId":"c-417","total":129,"error":"payment declined"}`, 3 * time.Hour},
+	},
+	"emails": {
+		{`{"to":"customer@example.com","template":"order-shipped"}`, 40 * time.Second},
+	},
+	"payments.fifo": {
+		{`{"paymentId":"pay-3321","amount":42.5}`, 10 * time.Second},
+	},
+}
+
+// demoPurgedQueues holds the fixture queues purged since the start, shared by
+// all fixture SQS APIs so that a reload shows them empty
+var demoPurgedQueues = struct {
+	sync.Mutex
+	names map[string]bool
+}{names: make(map[string]bool)}
+
+// SQS is a fixture SQS API
+type SQS struct{}
+
+// NewSQS returns a fixture SQS API
+func NewSQS() *SQS {
+	return &SQS{}
+}
+
+func queueURL(name string) string {
+	return fmt.Sprintf("https://sqs.%s.amazonaws.com/%s/%s", Region, AccountID, name)
+}
+
+// ListQueues returns the fixture queue URLs matching the name prefix
+func (s *SQS) ListQueues(ctx context.Context, params *sqs.ListQueuesInput, optFns ...func(*sqs.Options)) (*sqs.ListQueuesOutput, error) {
+	output := &sqs.ListQueuesOutput{}
+	for _, name := range []string{"emails", "orders", "orders-dlq", "payments.fifo"} {
+		if !strings.HasPrefix(name, aws.ToString(params.QueueNamePrefix)) {
+			continue
+		}
+		output.QueueUrls = append(output.QueueUrls, queueURL(name))
+	}
+	return output, nil
+}
+
+// GetQueueAttributes returns the fixture attributes of a queue
+func (s *SQS) GetQueueAttributes(ctx context.Context, params *sqs.GetQueueAttributesInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error) {
+	demoPurgedQueues.Lock()
+	defer demoPurgedQueues.Unlock()
+
+	for name, attributes := range queueAttributes {
+		if params.QueueUrl != nil && *params.QueueUrl == queueURL(name) {
+			if demoPurgedQueues.names[name] {
+				purged := make(map[string]string, len(attributes))
+				for key, value := range attributes {
+					purged[key] = value
+				}
+				purged["ApproximateNumberOfMessages"] = "0"
+				attributes = purged
+			}
+			return &sqs.GetQueueAttributesOutput{Attributes: attributes}, nil
+		}
+	}
+	return nil, fmt.Errorf("queue does not exist: %s", *params.QueueUrl)
+}
+
+// ReceiveMessage returns the fixture messages of a queue, none once purged
+func (s *SQS) ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	name, err := fixtureQueueName(aws.ToString(params.QueueUrl))
+	if err != nil {
+		return nil, err
+	}
+
+	demoPurgedQueues.Lock()
+	defer demoPurgedQueues.Unlock()
+
+	output := &sqs.ReceiveMessageOutput{}
+	if demoPurgedQueues.names[name] {
+		return output, nil
+	}
+	for i, message := range queueMessages[name] {
+		attributes := map[string]string{
+			string(types.MessageSystemAttributeNameSentTimestamp):           strconv.FormatInt(timeNow().Add(-message.sent).UnixMilli(), 10),
+			string(types.MessageSystemAttributeNameApproximateReceiveCount): "1",
+		}
+		if name == "orders-dlq" {
+			attributes[string(types.MessageSystemAttributeNameApproximateReceiveCount)] = "6"
+		}
+		if strings.HasSuffix(name, ".fifo") {
+			attributes[string(types.MessageSystemAttributeNameMessageGroupId)] = "payments"
+		}
+		output.Messages = append(output.Messages, types.Message{
+			MessageId:  aws.String(fmt.Sprintf("3f6c1a2e-0d4b-4c1e-9a7f-%012d", i+1)),
+			Body:       aws.String(message.body),
+			Attributes: attributes,
+			MessageAttributes: map[string]types.MessageAttributeValue{
+				"source": {DataType: aws.String("String"), StringValue: aws.String("checkout")},
+			},
+		})
+	}
+	return output, nil
+}
+
+// ChangeMessageVisibilityBatch accepts making the fixture messages visible
+// again, which they never stop being
+func (s *SQS) ChangeMessageVisibilityBatch(ctx context.Context, params *sqs.ChangeMessageVisibilityBatchInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityBatchOutput, error) {
+	if _, err := fixtureQueueName(aws.ToString(params.QueueUrl)); err != nil {
+		return nil, err
+	}
+	return &sqs.ChangeMessageVisibilityBatchOutput{}, nil
+}
+
+// PurgeQueue empties a fixture queue until the process exits
+func (s *SQS) PurgeQueue(ctx context.Context, params *sqs.PurgeQueueInput, optFns ...func(*sqs.Options)) (*sqs.PurgeQueueOutput, error) {
+	name, err := fixtureQueueName(aws.ToString(params.QueueUrl))
+	if err != nil {
+		return nil, err
+	}
+
+	demoPurgedQueues.Lock()
+	defer demoPurgedQueues.Unlock()
+	demoPurgedQueues.names[name] = true
+	return &sqs.PurgeQueueOutput{}, nil
+}
+
+// fixtureQueueName returns the name of the fixture queue with the URL
+func fixtureQueueName(url string) (string, error) {
+	for name := range queueAttributes {
+		if url == queueURL(name) {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("queue does not exist: %s", url)
+}
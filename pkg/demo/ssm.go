@@ -0,0 +1,73 @@
+package demo
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// SSM is a fixture SSM API. It manages most of the EC2 fixture instances
+// and one on-premises server, leaving web-3 unmanaged.
+type SSM struct{}
+
+// NewSSM returns a fixture SSM API
+func NewSSM() *SSM {
+	return &SSM{}
+}
+
+// DescribeInstanceInformation returns the fixture managed nodes
+func (s *SSM) DescribeInstanceInformation(ctx context.Context, params *ssm.DescribeInstanceInformationInput, optFns ...func(*ssm.Options)) (*ssm.DescribeInstanceInformationOutput, error) {
+	nodes := []struct {
+		id       string
+		name     string
+		ping     types.PingStatus
+		lastPing time.Duration
+		agent    string
+		latest   bool
+		platform string
+		version  string
+	}{
+		{"i-0a1b2c3d4e5f60001", "", types.PingStatusOnline, time.Minute, "3.3.1142.0", true, "Amazon Linux", "2023"},
+		{"i-0a1b2c3d4e5f60002", "", types.PingStatusOnline, 2 * time.Minute, "3.2.582.0", false, "Amazon Linux", "2"},
+		{"i-0a1b2c3d4e5f60004", "", types.PingStatusOnline, 4 * time.Minute, "3.3.1142.0", true, "Ubuntu", "22.04"},
+		{"i-0a1b2c3d4e5f60005", "", types.PingStatusConnectionLost, 38 * 24 * time.Hour, "3.1.1732.0", false, "Amazon Linux", "2023"},
+		{"mi-0123456789abcdef0", "onprem-build", types.PingStatusOnline, 3 * time.Minute, "3.3.1142.0", true, "CentOS Linux", "7.9.2009"},
+	}
+
+	output := &ssm.DescribeInstanceInformationOutput{}
+	for _, node := range nodes {
+		output.InstanceInformationList = append(output.InstanceInformationList, types.InstanceInformation{
+			InstanceId:       aws.String(node.id),
+			ComputerName:     aws.String(node.name),
+			PingStatus:       node.ping,
+			LastPingDateTime: ago(node.lastPing),
+			AgentVersion:     aws.String(node.agent),
+			IsLatestVersion:  aws.Bool(node.latest),
+			PlatformName:     aws.String(node.platform),
+			PlatformVersion:  aws.String(node.version),
+		})
+	}
+	return output, nil
+}
+
+// DescribeInstancePatchStates returns the fixture patch states of the requested nodes
+func (s *SSM) DescribeInstancePatchStates(ctx context.Context, params *ssm.DescribeInstancePatchStatesInput, optFns ...func(*ssm.Options)) (*ssm.DescribeInstancePatchStatesOutput, error) {
+	states := map[string]types.InstancePatchState{
+		"i-0a1b2c3d4e5f60001":  {Operation: types.PatchOperationTypeInstall, OperationEndTime: ago(3 * 24 * time.Hour)},
+		"i-0a1b2c3d4e5f60002":  {Operation: types.PatchOperationTypeScan, OperationEndTime: ago(20 * time.Hour), MissingCount: 4, CriticalNonCompliantCount: aws.Int32(2)},
+		"i-0a1b2c3d4e5f60004":  {Operation: types.PatchOperationTypeInstall, OperationEndTime: ago(6 * time.Hour), InstalledPendingRebootCount: aws.Int32(1)},
+		"mi-0123456789abcdef0": {Operation: types.PatchOperationTypeScan, OperationEndTime: ago(2 * 24 * time.Hour), FailedCount: 1},
+	}
+
+	output := &ssm.DescribeInstancePatchStatesOutput{}
+	for _, id := range params.InstanceIds {
+		if state, ok := states[id]; ok {
+			state.InstanceId = aws.String(id)
+			output.InstancePatchStates = append(output.InstancePatchStates, state)
+		}
+	}
+	return output, nil
+}
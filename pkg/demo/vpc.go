@@ -0,0 +1,155 @@
+package demo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// The production VPC and the default VPC of the region
+const (
+	productionVPC = "vpc-0a1b2c3d4e5f60001"
+	defaultVPC    = "vpc-0a1b2c3d4e5f60002"
+)
+
+// demoSubnets are the fixture subnets; the workers' subnet is nearly full
+var demoSubnets = []struct {
+	id        string
+	name      string
+	vpc       string
+	cidr      string
+	zone      string
+	available int32
+	public    bool
+}{
+	{"subnet-0a1b2c3d4e5f60001", "public-a", productionVPC, "10.0.0.0/24", "us-east-1a", 246, true},
+	{"subnet-0a1b2c3d4e5f60002", "app-a", productionVPC, "10.0.1.0/24", "us-east-1a", 212, false},
+	{"subnet-0a1b2c3d4e5f60003", "app-b", productionVPC, "10.0.2.0/24", "us-east-1b", 208, false},
+	{"subnet-0a1b2c3d4e5f60004", "app-c", productionVPC, "10.0.3.0/24", "us-east-1c", 219, false},
+	{"subnet-0a1b2c3d4e5f60005", "workers-a", productionVPC, "10.0.4.0/24", "us-east-1a", 9, false},
+	{"subnet-0a1b2c3d4e5f60006", "reporting-b", productionVPC, "10.0.5.0/24", "us-east-1b", 240, false},
+	{"subnet-0a1b2c3d4e5f60007", "public-b", productionVPC, "10.0.6.0/24", "us-east-1b", 247, true},
+	{"subnet-0f9e8d7c6b5a40001", "", defaultVPC, "172.31.0.0/20", "us-east-1a", 4091, true},
+	{"subnet-0f9e8d7c6b5a40002", "", defaultVPC, "172.31.16.0/20", "us-east-1b", 4091, true},
+}
+
+// demoNATGateways are the fixture NAT gateways, one per zone of the
+// production VPC; the metric fixture gives the first port allocation errors
+var demoNATGateways = []struct {
+	id       string
+	name     string
+	subnet   string
+	publicIP string
+}{
+	{"nat-0a1b2c3d4e5f60001", "nat-a", "subnet-0a1b2c3d4e5f60001", "52.4.118.20"},
+	{"nat-0a1b2c3d4e5f60002", "nat-b", "subnet-0a1b2c3d4e5f60007", "52.4.118.21"},
+}
+
+// DescribeVpcs returns the production and default VPCs
+func (e *EC2) DescribeVpcs(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error) {
+	return &ec2.DescribeVpcsOutput{
+		Vpcs: []types.Vpc{
+			{
+				VpcId:     aws.String(productionVPC),
+				CidrBlock: aws.String("10.0.0.0/16"),
+				IsDefault: aws.Bool(false),
+				State:     types.VpcStateAvailable,
+				Tags:      []types.Tag{{Key: aws.String("Name"), Value: aws.String("production")}},
+			},
+			{
+				VpcId:     aws.String(defaultVPC),
+				CidrBlock: aws.String("172.31.0.0/16"),
+				IsDefault: aws.Bool(true),
+				State:     types.VpcStateAvailable,
+			},
+		},
+	}, nil
+}
+
+// DescribeSubnets returns the fixture subnets
+func (e *EC2) DescribeSubnets(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+	output := &ec2.DescribeSubnetsOutput{}
+	for _, subnet := range demoSubnets {
+		s := types.Subnet{
+			SubnetId:                aws.String(subnet.id),
+			VpcId:                   aws.String(subnet.vpc),
+			CidrBlock:               aws.String(subnet.cidr),
+			AvailabilityZone:        aws.String(subnet.zone),
+			AvailableIpAddressCount: aws.Int32(subnet.available),
+			MapPublicIpOnLaunch:     aws.Bool(subnet.public),
+			State:                   types.SubnetStateAvailable,
+		}
+		if subnet.name != "" {
+			s.Tags = []types.Tag{{Key: aws.String("Name"), Value: aws.String(subnet.name)}}
+		}
+		output.Subnets = append(output.Subnets, s)
+	}
+	return output, nil
+}
+
+// DescribeNatGateways returns the fixture NAT gateways
+func (e *EC2) DescribeNatGateways(ctx context.Context, params *ec2.DescribeNatGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNatGatewaysOutput, error) {
+	output := &ec2.DescribeNatGatewaysOutput{}
+	for _, gateway := range demoNATGateways {
+		output.NatGateways = append(output.NatGateways, types.NatGateway{
+			NatGatewayId:     aws.String(gateway.id),
+			VpcId:            aws.String(productionVPC),
+			SubnetId:         aws.String(gateway.subnet),
+			State:            types.NatGatewayStateAvailable,
+			ConnectivityType: types.ConnectivityTypePublic,
+			NatGatewayAddresses: []types.NatGatewayAddress{
+				{PublicIp: aws.String(gateway.publicIP), IsPrimary: aws.Bool(true)},
+			},
+			Tags: []types.Tag{{Key: aws.String("Name"), Value: aws.String(gateway.name)}},
+		})
+	}
+	return output, nil
+}
+
+// DescribeInternetGateways returns an internet gateway for each VPC
+func (e *EC2) DescribeInternetGateways(ctx context.Context, params *ec2.DescribeInternetGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInternetGatewaysOutput, error) {
+	return &ec2.DescribeInternetGatewaysOutput{
+		InternetGateways: []types.InternetGateway{
+			{
+				InternetGatewayId: aws.String("igw-0a1b2c3d4e5f60001"),
+				Attachments:       []types.InternetGatewayAttachment{{VpcId: aws.String(productionVPC), State: types.AttachmentStatusAttached}},
+				Tags:              []types.Tag{{Key: aws.String("Name"), Value: aws.String("production-igw")}},
+			},
+			{
+				InternetGatewayId: aws.String("igw-0f9e8d7c6b5a40001"),
+				Attachments:       []types.InternetGatewayAttachment{{VpcId: aws.String(defaultVPC), State: types.AttachmentStatusAttached}},
+			},
+		},
+	}, nil
+}
+
+// DescribeVpcEndpoints returns the endpoints of the production VPC, which
+// keep S3, DynamoDB, ECR and CloudWatch Logs traffic off the NAT gateways
+func (e *EC2) DescribeVpcEndpoints(ctx context.Context, params *ec2.DescribeVpcEndpointsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcEndpointsOutput, error) {
+	endpoints := []struct {
+		id           string
+		service      string
+		endpointType types.VpcEndpointType
+	}{
+		{"vpce-0a1b2c3d4e5f60001", "s3", types.VpcEndpointTypeGateway},
+		{"vpce-0a1b2c3d4e5f60002", "dynamodb", types.VpcEndpointTypeGateway},
+		{"vpce-0a1b2c3d4e5f60003", "ecr.api", types.VpcEndpointTypeInterface},
+		{"vpce-0a1b2c3d4e5f60004", "ecr.dkr", types.VpcEndpointTypeInterface},
+		{"vpce-0a1b2c3d4e5f60005", "logs", types.VpcEndpointTypeInterface},
+	}
+
+	output := &ec2.DescribeVpcEndpointsOutput{}
+	for _, endpoint := range endpoints {
+		output.VpcEndpoints = append(output.VpcEndpoints, types.VpcEndpoint{
+			VpcEndpointId:   aws.String(endpoint.id),
+			VpcId:           aws.String(productionVPC),
+			ServiceName:     aws.String(fmt.Sprintf("com.amazonaws.%s.%s", Region, endpoint.service)),
+			VpcEndpointType: endpoint.endpointType,
+			State:           types.StateAvailable,
+		})
+	}
+	return output, nil
+}
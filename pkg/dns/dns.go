@@ -0,0 +1,438 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+)
+
+// route53ClientAPI defines the interface for the Route53 client
+type route53ClientAPI interface {
+	ListHostedZones(ctx context.Context, params *route53.ListHostedZonesInput, optFns ...func(*route53.Options)) (*route53.ListHostedZonesOutput, error)
+	ListResourceRecordSets(ctx context.Context, params *route53.ListResourceRecordSetsInput, optFns ...func(*route53.Options)) (*route53.ListResourceRecordSetsOutput, error)
+}
+
+// cloudfrontClientAPI defines the interface for the CloudFront client
+type cloudfrontClientAPI interface {
+	ListDistributions(ctx context.Context, params *cloudfront.ListDistributionsInput, optFns ...func(*cloudfront.Options)) (*cloudfront.ListDistributionsOutput, error)
+}
+
+// elbv2ClientAPI defines the interface for the ELBv2 client
+type elbv2ClientAPI interface {
+	DescribeLoadBalancers(ctx context.Context, params *elasticloadbalancingv2.DescribeLoadBalancersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error)
+}
+
+// elbClientAPI defines the interface for the Classic Load Balancer client
+type elbClientAPI interface {
+	DescribeLoadBalancers(ctx context.Context, params *elasticloadbalancing.DescribeLoadBalancersInput, optFns ...func(*elasticloadbalancing.Options)) (*elasticloadbalancing.DescribeLoadBalancersOutput, error)
+}
+
+// ec2ClientAPI defines the interface for the EC2 client
+type ec2ClientAPI interface {
+	DescribeAddresses(ctx context.Context, params *ec2.DescribeAddressesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeAddressesOutput, error)
+	DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error)
+}
+
+// Record statuses
+const (
+	StatusOK         = "OK"
+	StatusDangling   = "Dangling"   // Points at an AWS resource that does not exist
+	StatusUnverified = "Unverified" // Target could not be checked, e.g. in another region
+)
+
+// Client represents a dangling DNS detector
+type Client struct {
+	route53Client    route53ClientAPI
+	cloudfrontClient cloudfrontClientAPI
+	elbv2Client      elbv2ClientAPI
+	elbClient        elbClientAPI
+	ec2Client        ec2ClientAPI
+	region           string
+}
+
+// RecordSummary represents a DNS record pointing at an AWS resource
+type RecordSummary struct {
+	Zone       string
+	Name       string
+	Type       string
+	Target     string
+	Alias      bool
+	TargetKind string // "ELB", "CloudFront", "EC2" or "IP"
+	Status     string
+}
+
+// IsDangling reports whether the record points at a resource that no longer exists
+func (r RecordSummary) IsDangling() bool {
+	return r.Status == StatusDangling
+}
+
+// NewClient returns a new dangling DNS detector for the given region. Route53
+// and CloudFront are global, load balancers and addresses are looked up in the region.
+func NewClient(route53Client route53ClientAPI, cloudfrontClient cloudfrontClientAPI, elbv2Client elbv2ClientAPI, elbClient elbClientAPI, ec2Client ec2ClientAPI, region string) *Client {
+	return &Client{
+		route53Client:    route53Client,
+		cloudfrontClient: cloudfrontClient,
+		elbv2Client:      elbv2Client,
+		elbClient:        elbClient,
+		ec2Client:        ec2Client,
+		region:           region,
+	}
+}
+
+// inventory holds the names and addresses of existing resources. A nil set
+// means that kind of resource could not be listed.
+type inventory struct {
+	loadBalancers map[string]bool
+	distributions map[string]bool
+	addresses     map[string]bool
+}
+
+// GetRecords returns the DNS records of all hosted zones that point at load
+// balancers, CloudFront distributions or EC2 addresses, and whether their
+// target still exists. Records whose target could not be checked are marked
+// Unverified rather than dangling.
+func (c *Client) GetRecords(ctx context.Context) ([]RecordSummary, []error) {
+	zones, err := c.listHostedZones(ctx)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var records []RecordSummary
+	var errs []error
+	inv := inventory{}
+
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		loadBalancers, err := c.listLoadBalancers(ctx)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		inv.loadBalancers = loadBalancers
+	}()
+	go func() {
+		defer wg.Done()
+		distributions, err := c.listDistributions(ctx)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		inv.distributions = distributions
+	}()
+	go func() {
+		defer wg.Done()
+		addresses, err := c.listAddresses(ctx)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		inv.addresses = addresses
+	}()
+
+	for _, zone := range zones {
+		wg.Add(1)
+		go func(zone types.HostedZone) {
+			defer wg.Done()
+			zoneRecords, err := c.listRecords(ctx, zone)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			records = append(records, zoneRecords...)
+		}(zone)
+	}
+
+	wg.Wait()
+
+	for i := range records {
+		records[i].TargetKind, records[i].Status = classify(records[i], inv, c.region)
+	}
+
+	// Keep only the records that point at AWS resources
+	filtered := records[:0]
+	for _, record := range records {
+		if record.TargetKind != "" {
+			filtered = append(filtered, record)
+		}
+	}
+
+	sort.Slice(filtered, func(i, j int) bool {
+		if filtered[i].Zone != filtered[j].Zone {
+			return filtered[i].Zone < filtered[j].Zone
+		}
+		return filtered[i].Name < filtered[j].Name
+	})
+
+	return filtered, errs
+}
+
+// listHostedZones returns all hosted zones, following the pagination markers
+func (c *Client) listHostedZones(ctx context.Context) ([]types.HostedZone, error) {
+	var zones []types.HostedZone
+	var marker *string
+
+	for {
+		result, err := c.route53Client.ListHostedZones(ctx, &route53.ListHostedZonesInput{
+			Marker: marker,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list hosted zones: %w", err)
+		}
+
+		zones = append(zones, result.HostedZones...)
+
+		if !result.IsTruncated || result.NextMarker == nil {
+			break
+		}
+		marker = result.NextMarker
+	}
+
+	return zones, nil
+}
+
+// listRecords returns the A, AAAA and CNAME records of a hosted zone
+func (c *Client) listRecords(ctx context.Context, zone types.HostedZone) ([]RecordSummary, error) {
+	zoneName := strings.TrimSuffix(aws.ToString(zone.Name), ".")
+
+	var records []RecordSummary
+	input := &route53.ListResourceRecordSetsInput{HostedZoneId: zone.Id}
+
+	for {
+		result, err := c.route53Client.ListResourceRecordSets(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list records of zone %s: %w", zoneName, err)
+		}
+
+		for _, set := range result.ResourceRecordSets {
+			if set.Type != types.RRTypeA && set.Type != types.RRTypeAaaa && set.Type != types.RRTypeCname {
+				continue
+			}
+
+			record := RecordSummary{
+				Zone: zoneName,
+				Name: strings.TrimSuffix(aws.ToString(set.Name), "."),
+				Type: string(set.Type),
+			}
+
+			if set.AliasTarget != nil {
+				record.Alias = true
+				record.Target = aws.ToString(set.AliasTarget.DNSName)
+				records = append(records, record)
+				continue
+			}
+
+			for _, value := range set.ResourceRecords {
+				record.Target = aws.ToString(value.Value)
+				records = append(records, record)
+			}
+		}
+
+		if !result.IsTruncated {
+			break
+		}
+		input = &route53.ListResourceRecordSetsInput{
+			HostedZoneId:          zone.Id,
+			StartRecordName:       result.NextRecordName,
+			StartRecordType:       result.NextRecordType,
+			StartRecordIdentifier: result.NextRecordIdentifier,
+		}
+	}
+
+	return records, nil
+}
+
+// listLoadBalancers returns the DNS names of the load balancers in the
+// region, including Classic Load Balancers whose names look the same
+func (c *Client) listLoadBalancers(ctx context.Context) (map[string]bool, error) {
+	names := make(map[string]bool)
+
+	var classicMarker *string
+	for {
+		result, err := c.elbClient.DescribeLoadBalancers(ctx, &elasticloadbalancing.DescribeLoadBalancersInput{
+			Marker: classicMarker,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe classic load balancers: %w", err)
+		}
+
+		for _, lb := range result.LoadBalancerDescriptions {
+			names[normalizeHost(aws.ToString(lb.DNSName))] = true
+		}
+
+		classicMarker = result.NextMarker
+		if classicMarker == nil {
+			break
+		}
+	}
+
+	var marker *string
+
+	for {
+		result, err := c.elbv2Client.DescribeLoadBalancers(ctx, &elasticloadbalancingv2.DescribeLoadBalancersInput{
+			Marker: marker,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe load balancers: %w", err)
+		}
+
+		for _, lb := range result.LoadBalancers {
+			names[normalizeHost(aws.ToString(lb.DNSName))] = true
+		}
+
+		marker = result.NextMarker
+		if marker == nil {
+			break
+		}
+	}
+
+	return names, nil
+}
+
+// listDistributions returns the domain names of all CloudFront distributions
+func (c *Client) listDistributions(ctx context.Context) (map[string]bool, error) {
+	names := make(map[string]bool)
+	var marker *string
+
+	for {
+		result, err := c.cloudfrontClient.ListDistributions(ctx, &cloudfront.ListDistributionsInput{
+			Marker: marker,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list CloudFront distributions: %w", err)
+		}
+		if result.DistributionList == nil {
+			break
+		}
+
+		for _, distribution := range result.DistributionList.Items {
+			names[normalizeHost(aws.ToString(distribution.DomainName))] = true
+		}
+
+		if !aws.ToBool(result.DistributionList.IsTruncated) {
+			break
+		}
+		marker = result.DistributionList.NextMarker
+	}
+
+	return names, nil
+}
+
+// listAddresses returns the Elastic IPs and instance public IPs in the region
+func (c *Client) listAddresses(ctx context.Context) (map[string]bool, error) {
+	addresses := make(map[string]bool)
+
+	result, err := c.ec2Client.DescribeAddresses(ctx, &ec2.DescribeAddressesInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe addresses: %w", err)
+	}
+	for _, address := range result.Addresses {
+		addresses[aws.ToString(address.PublicIp)] = true
+	}
+
+	var nextToken *string
+	for {
+		resp, err := c.ec2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe instances: %w", err)
+		}
+
+		for _, reservation := range resp.Reservations {
+			for _, instance := range reservation.Instances {
+				if instance.PublicIpAddress != nil {
+					addresses[*instance.PublicIpAddress] = true
+				}
+				if instance.Ipv6Address != nil {
+					addresses[*instance.Ipv6Address] = true
+				}
+			}
+		}
+
+		nextToken = resp.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return addresses, nil
+}
+
+// ec2HostnamePattern matches public EC2 hostnames such as
+// ec2-203-0-113-10.compute-1.amazonaws.com
+var ec2HostnamePattern = regexp.MustCompile(`^ec2-(\d+)-(\d+)-(\d+)-(\d+)\.([a-z0-9-]+\.)?compute(-1)?\.amazonaws\.com$`)
+
+// classify returns the kind of AWS resource a record points at, if any, and
+// whether that resource exists
+func classify(record RecordSummary, inv inventory, region string) (string, string) {
+	host := normalizeHost(record.Target)
+
+	switch {
+	case strings.Contains(host, ".elb.") && strings.HasSuffix(host, ".amazonaws.com"):
+		if !strings.Contains(host, "."+region+".") {
+			return "ELB", StatusUnverified
+		}
+		return "ELB", lookup(inv.loadBalancers, host)
+	case strings.HasSuffix(host, ".cloudfront.net"):
+		return "CloudFront", lookup(inv.distributions, host)
+	case ec2HostnamePattern.MatchString(host):
+		match := ec2HostnamePattern.FindStringSubmatch(host)
+		hostRegion := strings.TrimSuffix(match[5], ".")
+		if match[6] != "" {
+			hostRegion = "us-east-1"
+		}
+		if hostRegion != region {
+			return "EC2", StatusUnverified
+		}
+		return "EC2", lookup(inv.addresses, strings.Join(match[1:5], "."))
+	case !record.Alias && (record.Type == "A" || record.Type == "AAAA"):
+		// Plain addresses may belong to anything outside AWS, so only confirm them
+		if inv.addresses[host] {
+			return "IP", StatusOK
+		}
+		return "IP", StatusUnverified
+	default:
+		return "", ""
+	}
+}
+
+// lookup returns the status of a record whose target should be in the set
+func lookup(set map[string]bool, key string) string {
+	if set == nil {
+		return StatusUnverified
+	}
+	if set[key] {
+		return StatusOK
+	}
+	return StatusDangling
+}
+
+// normalizeHost lowercases a DNS name and strips the trailing dot and the
+// dualstack prefix Route53 adds to load balancer aliases
+func normalizeHost(name string) string {
+	host := strings.TrimSuffix(strings.ToLower(name), ".")
+	return strings.TrimPrefix(host, "dualstack.")
+}
@@ -0,0 +1,211 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	cftypes "github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing"
+	elbtypes "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbv2types "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+)
+
+// Mock Route53 client
+type mockRoute53Client struct {
+	records map[string][]types.ResourceRecordSet
+}
+
+func (m *mockRoute53Client) ListHostedZones(ctx context.Context, params *route53.ListHostedZonesInput, optFns ...func(*route53.Options)) (*route53.ListHostedZonesOutput, error) {
+	output := &route53.ListHostedZonesOutput{}
+	for name := range m.records {
+		output.HostedZones = append(output.HostedZones, types.HostedZone{Id: aws.String(name), Name: aws.String(name + ".")})
+	}
+	return output, nil
+}
+
+func (m *mockRoute53Client) ListResourceRecordSets(ctx context.Context, params *route53.ListResourceRecordSetsInput, optFns ...func(*route53.Options)) (*route53.ListResourceRecordSetsOutput, error) {
+	sets := m.records[*params.HostedZoneId]
+
+	// Serve one record per page to exercise pagination
+	start := 0
+	if params.StartRecordName != nil {
+		for i, set := range sets {
+			if *set.Name == *params.StartRecordName {
+				start = i
+			}
+		}
+	}
+	output := &route53.ListResourceRecordSetsOutput{ResourceRecordSets: sets[start : start+1]}
+	if start+1 < len(sets) {
+		output.IsTruncated = true
+		output.NextRecordName = sets[start+1].Name
+		output.NextRecordType = sets[start+1].Type
+	}
+	return output, nil
+}
+
+// Mock CloudFront client
+type mockCloudFrontClient struct {
+	err     error
+	domains []string
+}
+
+func (m *mockCloudFrontClient) ListDistributions(ctx context.Context, params *cloudfront.ListDistributionsInput, optFns ...func(*cloudfront.Options)) (*cloudfront.ListDistributionsOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	list := &cftypes.DistributionList{IsTruncated: aws.Bool(false)}
+	for _, domain := range m.domains {
+		list.Items = append(list.Items, cftypes.DistributionSummary{DomainName: aws.String(domain)})
+	}
+	return &cloudfront.ListDistributionsOutput{DistributionList: list}, nil
+}
+
+// Mock ELBv2 client
+type mockELBV2Client struct {
+	dnsNames []string
+}
+
+func (m *mockELBV2Client) DescribeLoadBalancers(ctx context.Context, params *elasticloadbalancingv2.DescribeLoadBalancersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error) {
+	output := &elasticloadbalancingv2.DescribeLoadBalancersOutput{}
+	for _, name := range m.dnsNames {
+		output.LoadBalancers = append(output.LoadBalancers, elbv2types.LoadBalancer{DNSName: aws.String(name)})
+	}
+	return output, nil
+}
+
+// Mock Classic Load Balancer client
+type mockELBClient struct {
+	dnsNames []string
+}
+
+func (m *mockELBClient) DescribeLoadBalancers(ctx context.Context, params *elasticloadbalancing.DescribeLoadBalancersInput, optFns ...func(*elasticloadbalancing.Options)) (*elasticloadbalancing.DescribeLoadBalancersOutput, error) {
+	output := &elasticloadbalancing.DescribeLoadBalancersOutput{}
+	for _, name := range m.dnsNames {
+		output.LoadBalancerDescriptions = append(output.LoadBalancerDescriptions, elbtypes.LoadBalancerDescription{DNSName: aws.String(name)})
+	}
+	return output, nil
+}
+
+// Mock EC2 client
+type mockEC2Client struct {
+	elasticIPs []string
+	publicIPs  []string
+}
+
+func (m *mockEC2Client) DescribeAddresses(ctx context.Context, params *ec2.DescribeAddressesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeAddressesOutput, error) {
+	output := &ec2.DescribeAddressesOutput{}
+	for _, ip := range m.elasticIPs {
+		output.Addresses = append(output.Addresses, ec2types.Address{PublicIp: aws.String(ip)})
+	}
+	return output, nil
+}
+
+func (m *mockEC2Client) DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	reservation := ec2types.Reservation{}
+	for _, ip := range m.publicIPs {
+		reservation.Instances = append(reservation.Instances, ec2types.Instance{PublicIpAddress: aws.String(ip)})
+	}
+	return &ec2.DescribeInstancesOutput{Reservations: []ec2types.Reservation{reservation}}, nil
+}
+
+func record(name string, rrType types.RRType, value string) types.ResourceRecordSet {
+	return types.ResourceRecordSet{
+		Name:            aws.String(name + "."),
+		Type:            rrType,
+		ResourceRecords: []types.ResourceRecord{{Value: aws.String(value)}},
+	}
+}
+
+func alias(name string, target string) types.ResourceRecordSet {
+	return types.ResourceRecordSet{
+		Name:        aws.String(name + "."),
+		Type:        types.RRTypeA,
+		AliasTarget: &types.AliasTarget{DNSName: aws.String(target)},
+	}
+}
+
+func TestGetRecords(t *testing.T) {
+	route53Client := &mockRoute53Client{records: map[string][]types.ResourceRecordSet{
+		"example.com": {
+			alias("www.example.com", "dualstack.web-123.us-east-1.elb.amazonaws.com."),
+			alias("old.example.com", "dualstack.old-456.us-east-1.elb.amazonaws.com."),
+			record("legacy.example.com", types.RRTypeCname, "classic-789.us-east-1.elb.amazonaws.com"),
+			record("eu.example.com", types.RRTypeCname, "eu-123.eu-west-1.elb.amazonaws.com"),
+			record("cdn.example.com", types.RRTypeCname, "d111.cloudfront.net"),
+			record("assets.example.com", types.RRTypeCname, "d222.cloudfront.net"),
+			record("ftp.example.com", types.RRTypeCname, "ec2-52-4-17-90.compute-1.amazonaws.com"),
+			record("bastion.example.com", types.RRTypeA, "3.91.44.201"),
+			record("vpn.example.com", types.RRTypeA, "198.51.100.7"),
+			record("example.com", types.RRTypeMx, "10 mail.example.com"),
+		},
+	}}
+
+	client := NewClient(
+		route53Client,
+		&mockCloudFrontClient{domains: []string{"d111.cloudfront.net"}},
+		&mockELBV2Client{dnsNames: []string{"web-123.us-east-1.elb.amazonaws.com"}},
+		&mockELBClient{dnsNames: []string{"classic-789.us-east-1.elb.amazonaws.com"}},
+		&mockEC2Client{elasticIPs: []string{"3.91.44.201"}},
+		"us-east-1",
+	)
+
+	records, errs := client.GetRecords(context.Background())
+	if len(errs) > 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+
+	expected := map[string]string{
+		"www.example.com":     StatusOK,
+		"old.example.com":     StatusDangling,
+		"legacy.example.com":  StatusOK,
+		"eu.example.com":      StatusUnverified,
+		"cdn.example.com":     StatusOK,
+		"assets.example.com":  StatusDangling,
+		"ftp.example.com":     StatusDangling,
+		"bastion.example.com": StatusOK,
+		"vpn.example.com":     StatusUnverified,
+	}
+
+	if len(records) != len(expected) {
+		t.Fatalf("Expected %d records, got %+v", len(expected), records)
+	}
+	for _, record := range records {
+		if record.Status != expected[record.Name] {
+			t.Errorf("Expected %s to be %s, got %s", record.Name, expected[record.Name], record.Status)
+		}
+	}
+}
+
+func TestGetRecordsWithoutInventory(t *testing.T) {
+	route53Client := &mockRoute53Client{records: map[string][]types.ResourceRecordSet{
+		"example.com": {record("assets.example.com", types.RRTypeCname, "d222.cloudfront.net")},
+	}}
+
+	client := NewClient(
+		route53Client,
+		&mockCloudFrontClient{err: errors.New("AccessDenied")},
+		&mockELBV2Client{},
+		&mockELBClient{},
+		&mockEC2Client{},
+		"us-east-1",
+	)
+
+	records, errs := client.GetRecords(context.Background())
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %v", errs)
+	}
+
+	// A target that could not be listed must not be reported as dangling
+	if len(records) != 1 || records[0].Status != StatusUnverified {
+		t.Errorf("Expected the record to be unverified, got %+v", records)
+	}
+}
@@ -0,0 +1,93 @@
+package dns
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// FormatRecords formats DNS records for terminal display, listing dangling
+// records first
+func FormatRecords(summaries []RecordSummary) string {
+	if len(summaries) == 0 {
+		return "No DNS records pointing at AWS resources found"
+	}
+
+	var output strings.Builder
+	output.WriteString("DNS RECORDS\n")
+	output.WriteString(common.Rule("DNS RECORDS", "=") + "\n\n")
+
+	dangling := GetDanglingRecords(summaries)
+	if len(dangling) > 0 {
+		output.WriteString(fmt.Sprintf("%s DANGLING (%d records point at resources that no longer exist, takeover risk)\n",
+			common.Symbol("🚨"), len(dangling)))
+		for _, record := range dangling {
+			output.WriteString(fmt.Sprintf("  %s\n", formatRecord(record)))
+		}
+		output.WriteString("\n")
+	}
+
+	zone := ""
+	for _, record := range summaries {
+		if record.Zone != zone {
+			zone = record.Zone
+			output.WriteString(fmt.Sprintf("🌐 %s\n", zone))
+		}
+		output.WriteString(fmt.Sprintf("  %s %s\n", common.Symbol(getStatusSymbol(record.Status)), formatRecord(record)))
+	}
+
+	return output.String()
+}
+
+// GetRecordsSummary returns a one-line summary of the checked DNS records
+func GetRecordsSummary(summaries []RecordSummary) string {
+	unverified := 0
+	for _, record := range summaries {
+		if record.Status == StatusUnverified {
+			unverified++
+		}
+	}
+
+	summary := fmt.Sprintf("%d records pointing at AWS resources", len(summaries))
+	if unverified > 0 {
+		summary += fmt.Sprintf(", %d unverified", unverified)
+	}
+	if dangling := len(GetDanglingRecords(summaries)); dangling > 0 {
+		summary += fmt.Sprintf(", 🚨 %d dangling", dangling)
+	}
+
+	return summary
+}
+
+// GetDanglingRecords returns the records pointing at resources that no longer exist
+func GetDanglingRecords(summaries []RecordSummary) []RecordSummary {
+	var records []RecordSummary
+	for _, record := range summaries {
+		if record.IsDangling() {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// formatRecord describes a record and its target
+func formatRecord(record RecordSummary) string {
+	recordType := record.Type
+	if record.Alias {
+		recordType += " alias"
+	}
+	return fmt.Sprintf("%s %s → %s (%s)", record.Name, recordType, strings.TrimSuffix(record.Target, "."), record.TargetKind)
+}
+
+// getStatusSymbol returns an appropriate symbol for a record status
+func getStatusSymbol(status string) string {
+	switch status {
+	case StatusOK:
+		return "✅"
+	case StatusDangling:
+		return "🚨"
+	default:
+		return "❓"
+	}
+}
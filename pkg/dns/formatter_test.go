@@ -0,0 +1,46 @@
+package dns
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatRecords(t *testing.T) {
+	if result := FormatRecords(nil); result != "No DNS records pointing at AWS resources found" {
+		t.Errorf("Unexpected output for no records: '%s'", result)
+	}
+
+	summaries := []RecordSummary{
+		{Zone: "example.com", Name: "old.example.com", Type: "A", Alias: true, Target: "dualstack.old-456.us-east-1.elb.amazonaws.com.", TargetKind: "ELB", Status: StatusDangling},
+		{Zone: "example.com", Name: "www.example.com", Type: "CNAME", Target: "d111.cloudfront.net", TargetKind: "CloudFront", Status: StatusOK},
+	}
+
+	result := FormatRecords(summaries)
+
+	expectedElements := []string{
+		"DNS RECORDS",
+		"DANGLING (1 records point at resources that no longer exist, takeover risk)",
+		"🌐 example.com",
+		"🚨 old.example.com A alias → dualstack.old-456.us-east-1.elb.amazonaws.com (ELB)",
+		"✅ www.example.com CNAME → d111.cloudfront.net (CloudFront)",
+	}
+
+	for _, expected := range expectedElements {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected output to contain '%s', but it didn't:\n%s", expected, result)
+		}
+	}
+}
+
+func TestGetRecordsSummary(t *testing.T) {
+	summaries := []RecordSummary{
+		{Name: "a", Status: StatusOK},
+		{Name: "b", Status: StatusUnverified},
+		{Name: "c", Status: StatusDangling},
+	}
+
+	expected := "3 records pointing at AWS resources, 1 unverified, 🚨 1 dangling"
+	if result := GetRecordsSummary(summaries); result != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, result)
+	}
+}
@@ -0,0 +1,541 @@
+package dr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// rdsClientAPI defines the interface for the RDS client
+type rdsClientAPI interface {
+	DescribeDBInstances(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error)
+}
+
+// s3ClientAPI defines the interface for the S3 client
+type s3ClientAPI interface {
+	ListBuckets(ctx context.Context, params *s3.ListBucketsInput, optFns ...func(*s3.Options)) (*s3.ListBucketsOutput, error)
+	GetBucketReplication(ctx context.Context, params *s3.GetBucketReplicationInput, optFns ...func(*s3.Options)) (*s3.GetBucketReplicationOutput, error)
+	GetBucketLocation(ctx context.Context, params *s3.GetBucketLocationInput, optFns ...func(*s3.Options)) (*s3.GetBucketLocationOutput, error)
+}
+
+// ecrClientAPI defines the interface for the ECR client
+type ecrClientAPI interface {
+	DescribeRegistry(ctx context.Context, params *ecr.DescribeRegistryInput, optFns ...func(*ecr.Options)) (*ecr.DescribeRegistryOutput, error)
+	DescribeRepositories(ctx context.Context, params *ecr.DescribeRepositoriesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeRepositoriesOutput, error)
+}
+
+// dynamodbClientAPI defines the interface for the DynamoDB client
+type dynamodbClientAPI interface {
+	ListTables(ctx context.Context, params *dynamodb.ListTablesInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ListTablesOutput, error)
+	DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)
+}
+
+// Services whose replication is checked
+const (
+	ServiceRDS      = "RDS"
+	ServiceS3       = "S3"
+	ServiceECR      = "ECR"
+	ServiceDynamoDB = "DynamoDB"
+)
+
+// Replication statuses
+const (
+	StatusReplicated    = "Replicated"     // Copies are kept in another region
+	StatusSameRegion    = "Same region"    // Copies are kept, but only in this region
+	StatusNotReplicated = "Not replicated" // No copies are kept
+	StatusDegraded      = "Degraded"       // Replication is configured but failing
+)
+
+// Client represents a cross-region replication checker
+type Client struct {
+	rdsClient      rdsClientAPI
+	s3Client       s3ClientAPI
+	ecrClient      ecrClientAPI
+	dynamodbClient dynamodbClientAPI
+	region         string
+	pool           *common.Pool
+}
+
+// ResourceSummary represents the replication status of a resource
+type ResourceSummary struct {
+	Service string
+	Name    string
+	Status  string
+	Regions []string // Regions the resource is replicated to
+	Detail  string
+}
+
+// IsProtected reports whether a copy of the resource is kept in another region
+func (r ResourceSummary) IsProtected() bool {
+	return r.Status == StatusReplicated
+}
+
+// NewClient returns a new replication checker for the given region whose
+// calls run in pool, which may be nil
+func NewClient(rdsClient rdsClientAPI, s3Client s3ClientAPI, ecrClient ecrClientAPI, dynamodbClient dynamodbClientAPI, region string, pool *common.Pool) *Client {
+	return &Client{
+		rdsClient:      rdsClient,
+		s3Client:       s3Client,
+		ecrClient:      ecrClient,
+		dynamodbClient: dynamodbClient,
+		region:         region,
+		pool:           pool,
+	}
+}
+
+// GetResources returns the replication status of the RDS instances, S3
+// buckets, ECR registry and DynamoDB tables in the region. A service that
+// cannot be checked adds an error while the others are still returned.
+func (c *Client) GetResources(ctx context.Context) ([]ResourceSummary, []error) {
+	collectors := []func(context.Context) ([]ResourceSummary, []error){
+		c.getDBInstances,
+		c.getBuckets,
+		c.getRegistry,
+		c.getTables,
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var summaries []ResourceSummary
+	var errs []error
+
+	for _, collect := range collectors {
+		wg.Add(1)
+		go func(collect func(context.Context) ([]ResourceSummary, []error)) {
+			defer wg.Done()
+			resources, resourceErrs := collect(ctx)
+
+			mu.Lock()
+			defer mu.Unlock()
+			summaries = append(summaries, resources...)
+			errs = append(errs, resourceErrs...)
+		}(collect)
+	}
+
+	wg.Wait()
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Service != summaries[j].Service {
+			return summaries[i].Service < summaries[j].Service
+		}
+		return summaries[i].Name < summaries[j].Name
+	})
+
+	return summaries, errs
+}
+
+// getDBInstances returns the replication status of the DB instances that are
+// not themselves read replicas. Aurora cluster members are skipped, as their
+// replication is configured on the cluster. Read replicas in this region are
+// only reported when their replication has failed.
+func (c *Client) getDBInstances(ctx context.Context) ([]ResourceSummary, []error) {
+	var summaries []ResourceSummary
+	var marker *string
+
+	for {
+		var result *rds.DescribeDBInstancesOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.rdsClient.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{
+				Marker: marker,
+			})
+			return err
+		})
+		if err != nil {
+			return summaries, []error{fmt.Errorf("failed to describe DB instances: %w", err)}
+		}
+
+		for _, instance := range result.DBInstances {
+			if instance.DBClusterIdentifier != nil {
+				continue
+			}
+
+			name := aws.ToString(instance.DBInstanceIdentifier)
+
+			if source := aws.ToString(instance.ReadReplicaSourceDBInstanceIdentifier); source != "" {
+				for _, info := range instance.StatusInfos {
+					if aws.ToString(info.StatusType) == "read replication" && !aws.ToBool(info.Normal) {
+						summaries = append(summaries, ResourceSummary{
+							Service: ServiceRDS,
+							Name:    name,
+							Status:  StatusDegraded,
+							Detail:  fmt.Sprintf("read replica of %s: replication %s", source, aws.ToString(info.Status)),
+						})
+					}
+				}
+				continue
+			}
+
+			summary := ResourceSummary{Service: ServiceRDS, Name: name}
+			sameRegion := 0
+			for _, replica := range instance.ReadReplicaDBInstanceIdentifiers {
+				region := arnRegion(replica)
+				if region == "" || region == c.region {
+					sameRegion++
+					continue
+				}
+				summary.Regions = appendUnique(summary.Regions, region)
+			}
+
+			switch {
+			case len(summary.Regions) > 0:
+				summary.Status = StatusReplicated
+				summary.Detail = "cross-region read replica"
+			case sameRegion > 0:
+				summary.Status = StatusSameRegion
+				summary.Detail = fmt.Sprintf("%d read replicas in %s only", sameRegion, c.region)
+			default:
+				summary.Status = StatusNotReplicated
+				summary.Detail = "no read replicas"
+			}
+			summaries = append(summaries, summary)
+		}
+
+		marker = result.Marker
+		if marker == nil {
+			break
+		}
+	}
+
+	return summaries, nil
+}
+
+// getBuckets returns the replication status of the S3 buckets in the region
+func (c *Client) getBuckets(ctx context.Context) ([]ResourceSummary, []error) {
+	buckets, err := c.listBuckets(ctx)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var summaries []ResourceSummary
+	var errs []error
+
+	for _, bucket := range buckets {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			summary, err := c.getBucketSummary(ctx, name)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			summaries = append(summaries, summary)
+		}(aws.ToString(bucket.Name))
+	}
+
+	wg.Wait()
+
+	return summaries, errs
+}
+
+// listBuckets returns the S3 buckets in the region, following the continuation tokens
+func (c *Client) listBuckets(ctx context.Context) ([]s3types.Bucket, error) {
+	var buckets []s3types.Bucket
+	var token *string
+
+	for {
+		var result *s3.ListBucketsOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.s3Client.ListBuckets(ctx, &s3.ListBucketsInput{
+				BucketRegion:      aws.String(c.region),
+				ContinuationToken: token,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list S3 buckets: %w", err)
+		}
+
+		buckets = append(buckets, result.Buckets...)
+
+		token = result.ContinuationToken
+		if token == nil {
+			break
+		}
+	}
+
+	return buckets, nil
+}
+
+// getBucketSummary returns the replication status of a bucket. The regions of
+// destination buckets owned by other accounts cannot be looked up; such
+// buckets are assumed to be in another region.
+func (c *Client) getBucketSummary(ctx context.Context, name string) (ResourceSummary, error) {
+	summary := ResourceSummary{Service: ServiceS3, Name: name}
+
+	var result *s3.GetBucketReplicationOutput
+	err := c.pool.Do(ctx, func() (err error) {
+		result, err = c.s3Client.GetBucketReplication(ctx, &s3.GetBucketReplicationInput{
+			Bucket: aws.String(name),
+		})
+		return err
+	})
+	if isErrorCode(err, "ReplicationConfigurationNotFoundError") {
+		summary.Status = StatusNotReplicated
+		summary.Detail = "no replication rules"
+		return summary, nil
+	}
+	if err != nil {
+		return summary, fmt.Errorf("failed to get replication of bucket %s: %w", name, err)
+	}
+
+	var destinations []string
+	if result.ReplicationConfiguration != nil {
+		for _, rule := range result.ReplicationConfiguration.Rules {
+			if rule.Status != s3types.ReplicationRuleStatusEnabled || rule.Destination == nil {
+				continue
+			}
+			destinations = appendUnique(destinations, strings.TrimPrefix(aws.ToString(rule.Destination.Bucket), "arn:aws:s3:::"))
+		}
+	}
+
+	if len(destinations) == 0 {
+		summary.Status = StatusNotReplicated
+		summary.Detail = "replication rules disabled"
+		return summary, nil
+	}
+
+	for _, destination := range destinations {
+		region := c.bucketRegion(ctx, destination)
+		if region == c.region {
+			continue
+		}
+		if region == "" {
+			region = "unknown region"
+		}
+		summary.Regions = appendUnique(summary.Regions, region)
+	}
+
+	summary.Detail = "to " + strings.Join(destinations, ", ")
+	if len(summary.Regions) > 0 {
+		summary.Status = StatusReplicated
+	} else {
+		summary.Status = StatusSameRegion
+	}
+
+	return summary, nil
+}
+
+// bucketRegion returns the region of a bucket, or "" when it cannot be looked up
+func (c *Client) bucketRegion(ctx context.Context, name string) string {
+	var result *s3.GetBucketLocationOutput
+	err := c.pool.Do(ctx, func() (err error) {
+		result, err = c.s3Client.GetBucketLocation(ctx, &s3.GetBucketLocationInput{
+			Bucket: aws.String(name),
+		})
+		return err
+	})
+	if err != nil {
+		return ""
+	}
+
+	// Buckets in us-east-1 have no location constraint
+	if result.LocationConstraint == "" {
+		return "us-east-1"
+	}
+	return string(result.LocationConstraint)
+}
+
+// getRegistry returns the replication status of the private ECR registry,
+// unless it has no repositories
+func (c *Client) getRegistry(ctx context.Context) ([]ResourceSummary, []error) {
+	var repositories *ecr.DescribeRepositoriesOutput
+	err := c.pool.Do(ctx, func() (err error) {
+		repositories, err = c.ecrClient.DescribeRepositories(ctx, &ecr.DescribeRepositoriesInput{
+			MaxResults: aws.Int32(1),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to describe ECR repositories: %w", err)}
+	}
+	if len(repositories.Repositories) == 0 {
+		return nil, nil
+	}
+
+	var result *ecr.DescribeRegistryOutput
+	err = c.pool.Do(ctx, func() (err error) {
+		result, err = c.ecrClient.DescribeRegistry(ctx, &ecr.DescribeRegistryInput{})
+		return err
+	})
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to describe ECR registry: %w", err)}
+	}
+
+	summary := ResourceSummary{Service: ServiceECR, Name: aws.ToString(result.RegistryId)}
+	sameRegion := false
+	if result.ReplicationConfiguration != nil {
+		for _, rule := range result.ReplicationConfiguration.Rules {
+			for _, destination := range rule.Destinations {
+				region := aws.ToString(destination.Region)
+				if region == c.region {
+					sameRegion = true
+					continue
+				}
+				summary.Regions = appendUnique(summary.Regions, region)
+			}
+		}
+	}
+
+	switch {
+	case len(summary.Regions) > 0:
+		summary.Status = StatusReplicated
+		summary.Detail = "registry replication"
+	case sameRegion:
+		summary.Status = StatusSameRegion
+		summary.Detail = "replicated to other accounts in " + c.region + " only"
+	default:
+		summary.Status = StatusNotReplicated
+		summary.Detail = "no replication rules"
+	}
+
+	return []ResourceSummary{summary}, nil
+}
+
+// getTables returns the replication status of the DynamoDB tables in the region
+func (c *Client) getTables(ctx context.Context) ([]ResourceSummary, []error) {
+	names, err := c.listTables(ctx)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var summaries []ResourceSummary
+	var errs []error
+
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			summary, err := c.getTableSummary(ctx, name)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			summaries = append(summaries, summary)
+		}(name)
+	}
+
+	wg.Wait()
+
+	return summaries, errs
+}
+
+// listTables returns the names of all DynamoDB tables, following the pagination keys
+func (c *Client) listTables(ctx context.Context) ([]string, error) {
+	var names []string
+	var start *string
+
+	for {
+		var result *dynamodb.ListTablesOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.dynamodbClient.ListTables(ctx, &dynamodb.ListTablesInput{
+				ExclusiveStartTableName: start,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list DynamoDB tables: %w", err)
+		}
+
+		names = append(names, result.TableNames...)
+
+		start = result.LastEvaluatedTableName
+		if start == nil {
+			break
+		}
+	}
+
+	return names, nil
+}
+
+// getTableSummary returns the replication status of a table from its global table replicas
+func (c *Client) getTableSummary(ctx context.Context, name string) (ResourceSummary, error) {
+	summary := ResourceSummary{Service: ServiceDynamoDB, Name: name}
+
+	var result *dynamodb.DescribeTableOutput
+	err := c.pool.Do(ctx, func() (err error) {
+		result, err = c.dynamodbClient.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+			TableName: aws.String(name),
+		})
+		return err
+	})
+	if err != nil {
+		return summary, fmt.Errorf("failed to describe table %s: %w", name, err)
+	}
+
+	var failing []string
+	if result.Table != nil {
+		for _, replica := range result.Table.Replicas {
+			region := aws.ToString(replica.RegionName)
+			if region == c.region {
+				continue
+			}
+			summary.Regions = append(summary.Regions, region)
+			if replica.ReplicaStatus != dynamodbtypes.ReplicaStatusActive && replica.ReplicaStatus != dynamodbtypes.ReplicaStatusUpdating {
+				failing = append(failing, fmt.Sprintf("%s %s", region, replica.ReplicaStatus))
+			}
+		}
+	}
+
+	switch {
+	case len(failing) > 0:
+		summary.Status = StatusDegraded
+		summary.Detail = "replica " + strings.Join(failing, ", ")
+	case len(summary.Regions) > 0:
+		summary.Status = StatusReplicated
+		summary.Detail = "global table"
+	default:
+		summary.Status = StatusNotReplicated
+		summary.Detail = "not a global table"
+	}
+
+	return summary, nil
+}
+
+// arnRegion returns the region of an ARN, or "" if s is not an ARN
+func arnRegion(s string) string {
+	parts := strings.SplitN(s, ":", 5)
+	if len(parts) < 5 || parts[0] != "arn" {
+		return ""
+	}
+	return parts[3]
+}
+
+// appendUnique appends value to values unless it is already present
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// isErrorCode reports whether err is an AWS API error with the given code
+func isErrorCode(err error, code string) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == code
+}
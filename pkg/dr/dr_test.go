@@ -0,0 +1,247 @@
+package dr
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	ecrtypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// Mock RDS client
+type mockRDSClient struct {
+	instances []rdstypes.DBInstance
+}
+
+func (m *mockRDSClient) DescribeDBInstances(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+	return &rds.DescribeDBInstancesOutput{DBInstances: m.instances}, nil
+}
+
+// Mock S3 client
+type mockS3Client struct {
+	err       error
+	rules     map[string][]s3types.ReplicationRule
+	locations map[string]s3types.BucketLocationConstraint
+}
+
+func (m *mockS3Client) ListBuckets(ctx context.Context, params *s3.ListBucketsInput, optFns ...func(*s3.Options)) (*s3.ListBucketsOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	output := &s3.ListBucketsOutput{}
+	for name := range m.rules {
+		output.Buckets = append(output.Buckets, s3types.Bucket{Name: aws.String(name)})
+	}
+	return output, nil
+}
+
+func (m *mockS3Client) GetBucketReplication(ctx context.Context, params *s3.GetBucketReplicationInput, optFns ...func(*s3.Options)) (*s3.GetBucketReplicationOutput, error) {
+	rules := m.rules[*params.Bucket]
+	if len(rules) == 0 {
+		return nil, &smithy.GenericAPIError{Code: "ReplicationConfigurationNotFoundError"}
+	}
+	return &s3.GetBucketReplicationOutput{ReplicationConfiguration: &s3types.ReplicationConfiguration{Rules: rules}}, nil
+}
+
+func (m *mockS3Client) GetBucketLocation(ctx context.Context, params *s3.GetBucketLocationInput, optFns ...func(*s3.Options)) (*s3.GetBucketLocationOutput, error) {
+	location, ok := m.locations[*params.Bucket]
+	if !ok {
+		return nil, &smithy.GenericAPIError{Code: "AccessDenied"}
+	}
+	return &s3.GetBucketLocationOutput{LocationConstraint: location}, nil
+}
+
+// Mock ECR client
+type mockECRClient struct {
+	repositories int
+	destinations []string
+}
+
+func (m *mockECRClient) DescribeRegistry(ctx context.Context, params *ecr.DescribeRegistryInput, optFns ...func(*ecr.Options)) (*ecr.DescribeRegistryOutput, error) {
+	rule := ecrtypes.ReplicationRule{}
+	for _, region := range m.destinations {
+		rule.Destinations = append(rule.Destinations, ecrtypes.ReplicationDestination{Region: aws.String(region), RegistryId: aws.String("123456789012")})
+	}
+	return &ecr.DescribeRegistryOutput{
+		RegistryId:               aws.String("123456789012"),
+		ReplicationConfiguration: &ecrtypes.ReplicationConfiguration{Rules: []ecrtypes.ReplicationRule{rule}},
+	}, nil
+}
+
+func (m *mockECRClient) DescribeRepositories(ctx context.Context, params *ecr.DescribeRepositoriesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeRepositoriesOutput, error) {
+	output := &ecr.DescribeRepositoriesOutput{}
+	for i := 0; i < m.repositories; i++ {
+		output.Repositories = append(output.Repositories, ecrtypes.Repository{})
+	}
+	return output, nil
+}
+
+// Mock DynamoDB client
+type mockDynamoDBClient struct {
+	replicas map[string][]dynamodbtypes.ReplicaDescription
+}
+
+func (m *mockDynamoDBClient) ListTables(ctx context.Context, params *dynamodb.ListTablesInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ListTablesOutput, error) {
+	var names []string
+	for name := range m.replicas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Serve one table per page to exercise pagination
+	start := 0
+	if params.ExclusiveStartTableName != nil {
+		for i, name := range names {
+			if name == *params.ExclusiveStartTableName {
+				start = i + 1
+			}
+		}
+	}
+	output := &dynamodb.ListTablesOutput{TableNames: names[start : start+1]}
+	if start+1 < len(names) {
+		output.LastEvaluatedTableName = aws.String(names[start])
+	}
+	return output, nil
+}
+
+func (m *mockDynamoDBClient) DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	return &dynamodb.DescribeTableOutput{Table: &dynamodbtypes.TableDescription{
+		TableName: params.TableName,
+		Replicas:  m.replicas[*params.TableName],
+	}}, nil
+}
+
+func newTestClient(s3Client *mockS3Client) *Client {
+	rdsClient := &mockRDSClient{instances: []rdstypes.DBInstance{
+		{
+			DBInstanceIdentifier:             aws.String("orders-db"),
+			ReadReplicaDBInstanceIdentifiers: []string{"arn:aws:rds:us-west-2:123456789012:db:orders-db-dr"},
+		},
+		{
+			DBInstanceIdentifier:             aws.String("users-db"),
+			ReadReplicaDBInstanceIdentifiers: []string{"users-db-read"},
+		},
+		{
+			DBInstanceIdentifier:                  aws.String("users-db-read"),
+			ReadReplicaSourceDBInstanceIdentifier: aws.String("users-db"),
+			StatusInfos: []rdstypes.DBInstanceStatusInfo{
+				{StatusType: aws.String("read replication"), Normal: aws.Bool(false), Status: aws.String("error")},
+			},
+		},
+		{
+			DBInstanceIdentifier: aws.String("aurora-1"),
+			DBClusterIdentifier:  aws.String("aurora"),
+		},
+		{DBInstanceIdentifier: aws.String("reports-db")},
+	}}
+
+	dynamodbClient := &mockDynamoDBClient{replicas: map[string][]dynamodbtypes.ReplicaDescription{
+		"sessions": {
+			{RegionName: aws.String("us-east-1"), ReplicaStatus: dynamodbtypes.ReplicaStatusActive},
+			{RegionName: aws.String("eu-west-1"), ReplicaStatus: dynamodbtypes.ReplicaStatusActive},
+		},
+		"carts": {
+			{RegionName: aws.String("us-east-1"), ReplicaStatus: dynamodbtypes.ReplicaStatusActive},
+			{RegionName: aws.String("eu-west-1"), ReplicaStatus: dynamodbtypes.ReplicaStatusInaccessibleEncryptionCredentials},
+		},
+		"audit": nil,
+	}}
+
+	return NewClient(rdsClient, s3Client, &mockECRClient{repositories: 1, destinations: []string{"us-west-2"}}, dynamodbClient, "us-east-1", nil)
+}
+
+func TestGetResources(t *testing.T) {
+	s3Client := &mockS3Client{
+		rules: map[string][]s3types.ReplicationRule{
+			"assets": {
+				{Status: s3types.ReplicationRuleStatusEnabled, Destination: &s3types.Destination{Bucket: aws.String("arn:aws:s3:::assets-dr")}},
+			},
+			"logs": {
+				{Status: s3types.ReplicationRuleStatusEnabled, Destination: &s3types.Destination{Bucket: aws.String("arn:aws:s3:::logs-copy")}},
+			},
+			"uploads": {
+				{Status: s3types.ReplicationRuleStatusDisabled, Destination: &s3types.Destination{Bucket: aws.String("arn:aws:s3:::uploads-dr")}},
+			},
+			"tmp": nil,
+		},
+		locations: map[string]s3types.BucketLocationConstraint{
+			"assets-dr": s3types.BucketLocationConstraintUsWest2,
+			"logs-copy": "",
+		},
+	}
+
+	summaries, errs := newTestClient(s3Client).GetResources(context.Background())
+	if len(errs) > 0 {
+		t.Fatalf("GetResources() errors = %v", errs)
+	}
+
+	expected := map[string]string{
+		"RDS/orders-db":     StatusReplicated,
+		"RDS/users-db":      StatusSameRegion,
+		"RDS/users-db-read": StatusDegraded,
+		"RDS/reports-db":    StatusNotReplicated,
+		"S3/assets":         StatusReplicated,
+		"S3/logs":           StatusSameRegion,
+		"S3/uploads":        StatusNotReplicated,
+		"S3/tmp":            StatusNotReplicated,
+		"ECR/123456789012":  StatusReplicated,
+		"DynamoDB/sessions": StatusReplicated,
+		"DynamoDB/carts":    StatusDegraded,
+		"DynamoDB/audit":    StatusNotReplicated,
+	}
+	if len(summaries) != len(expected) {
+		t.Fatalf("Expected %d resources, got %d: %v", len(expected), len(summaries), summaries)
+	}
+	for _, summary := range summaries {
+		key := summary.Service + "/" + summary.Name
+		if summary.Status != expected[key] {
+			t.Errorf("Expected %s to be '%s', got '%s' (%s)", key, expected[key], summary.Status, summary.Detail)
+		}
+	}
+
+	// Results are sorted by service and name
+	if summaries[0].Service != ServiceDynamoDB || summaries[0].Name != "audit" {
+		t.Errorf("Expected DynamoDB/audit first, got %s/%s", summaries[0].Service, summaries[0].Name)
+	}
+	for _, summary := range summaries {
+		if summary.Name == "orders-db" && (len(summary.Regions) != 1 || summary.Regions[0] != "us-west-2") {
+			t.Errorf("Expected orders-db to be replicated to us-west-2, got %v", summary.Regions)
+		}
+	}
+}
+
+func TestGetResourcesPartialFailure(t *testing.T) {
+	s3Client := &mockS3Client{err: errors.New("AccessDenied")}
+
+	summaries, errs := newTestClient(s3Client).GetResources(context.Background())
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %v", errs)
+	}
+	for _, summary := range summaries {
+		if summary.Service == ServiceS3 {
+			t.Errorf("Expected no S3 resources, got %v", summary)
+		}
+	}
+	if len(summaries) != 8 {
+		t.Errorf("Expected the 8 RDS, ECR and DynamoDB resources, got %d", len(summaries))
+	}
+}
+
+func TestGetRegistryWithoutRepositories(t *testing.T) {
+	client := NewClient(nil, nil, &mockECRClient{destinations: []string{"us-west-2"}}, nil, "us-east-1", nil)
+
+	summaries, errs := client.getRegistry(context.Background())
+	if len(errs) > 0 || len(summaries) != 0 {
+		t.Errorf("Expected an empty registry to be skipped, got %v %v", summaries, errs)
+	}
+}
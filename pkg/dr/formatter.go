@@ -0,0 +1,104 @@
+package dr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// FormatResources formats the replication status of resources for terminal
+// display, listing failing and unprotected resources first
+func FormatResources(summaries []ResourceSummary) string {
+	if len(summaries) == 0 {
+		return "No RDS instances, S3 buckets, ECR repositories or DynamoDB tables found"
+	}
+
+	var output strings.Builder
+	output.WriteString("DR READINESS\n")
+	output.WriteString(common.Rule("DR READINESS", "=") + "\n\n")
+
+	degraded := GetDegradedResources(summaries)
+	if len(degraded) > 0 {
+		output.WriteString(fmt.Sprintf("%s REPLICATION FAILING (%d)\n", common.Symbol("🚨"), len(degraded)))
+		for _, resource := range degraded {
+			output.WriteString(fmt.Sprintf("  %s %s: %s\n", resource.Service, resource.Name, resource.Detail))
+		}
+		output.WriteString("\n")
+	}
+
+	unprotected := GetUnprotectedResources(summaries)
+	if len(unprotected) > 0 {
+		output.WriteString(fmt.Sprintf("%s NO COPY IN ANOTHER REGION (%d)\n", common.Symbol("⚠️"), len(unprotected)))
+		for _, resource := range unprotected {
+			output.WriteString(fmt.Sprintf("  %s %s: %s\n", resource.Service, resource.Name, resource.Detail))
+		}
+		output.WriteString("\n")
+	}
+
+	service := ""
+	for _, resource := range summaries {
+		if resource.Service != service {
+			service = resource.Service
+			output.WriteString(fmt.Sprintf("%s\n", service))
+		}
+		output.WriteString(fmt.Sprintf("  %s %s", common.Symbol(getStatusSymbol(resource.Status)), resource.Name))
+		if len(resource.Regions) > 0 {
+			output.WriteString(" → " + strings.Join(resource.Regions, ", "))
+		}
+		output.WriteString(fmt.Sprintf(" (%s)\n", resource.Detail))
+	}
+
+	return output.String()
+}
+
+// GetResourcesSummary returns a one-line summary of the DR readiness
+func GetResourcesSummary(summaries []ResourceSummary) string {
+	protected := 0
+	for _, resource := range summaries {
+		if resource.IsProtected() {
+			protected++
+		}
+	}
+
+	summary := fmt.Sprintf("%d of %d resources replicated to another region", protected, len(summaries))
+	if degraded := len(GetDegradedResources(summaries)); degraded > 0 {
+		summary += fmt.Sprintf(", 🚨 %d failing", degraded)
+	}
+
+	return summary
+}
+
+// GetDegradedResources returns the resources whose replication is failing
+func GetDegradedResources(summaries []ResourceSummary) []ResourceSummary {
+	var resources []ResourceSummary
+	for _, resource := range summaries {
+		if resource.Status == StatusDegraded {
+			resources = append(resources, resource)
+		}
+	}
+	return resources
+}
+
+// GetUnprotectedResources returns the resources without a copy in another region
+func GetUnprotectedResources(summaries []ResourceSummary) []ResourceSummary {
+	var resources []ResourceSummary
+	for _, resource := range summaries {
+		if resource.Status == StatusNotReplicated || resource.Status == StatusSameRegion {
+			resources = append(resources, resource)
+		}
+	}
+	return resources
+}
+
+// getStatusSymbol returns an appropriate symbol for a replication status
+func getStatusSymbol(status string) string {
+	switch status {
+	case StatusReplicated:
+		return "✅"
+	case StatusDegraded:
+		return "🚨"
+	default:
+		return "⚠️"
+	}
+}
@@ -0,0 +1,50 @@
+package dr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatResources(t *testing.T) {
+	summaries := []ResourceSummary{
+		{Service: ServiceDynamoDB, Name: "carts", Status: StatusDegraded, Regions: []string{"eu-west-1"}, Detail: "replica eu-west-1 INACCESSIBLE_ENCRYPTION_CREDENTIALS"},
+		{Service: ServiceRDS, Name: "orders-db", Status: StatusReplicated, Regions: []string{"us-west-2"}, Detail: "cross-region read replica"},
+		{Service: ServiceS3, Name: "uploads", Status: StatusNotReplicated, Detail: "no replication rules"},
+	}
+
+	output := FormatResources(summaries)
+
+	for _, expected := range []string{
+		"DR READINESS",
+		"REPLICATION FAILING (1)",
+		"DynamoDB carts: replica eu-west-1 INACCESSIBLE_ENCRYPTION_CREDENTIALS",
+		"NO COPY IN ANOTHER REGION (1)",
+		"S3 uploads: no replication rules",
+		"orders-db → us-west-2 (cross-region read replica)",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain '%s', got:\n%s", expected, output)
+		}
+	}
+
+	if output := FormatResources(nil); !strings.Contains(output, "No RDS instances") {
+		t.Errorf("Expected an empty message, got '%s'", output)
+	}
+}
+
+func TestGetResourcesSummary(t *testing.T) {
+	summaries := []ResourceSummary{
+		{Service: ServiceRDS, Name: "orders-db", Status: StatusReplicated},
+		{Service: ServiceS3, Name: "logs", Status: StatusSameRegion},
+		{Service: ServiceDynamoDB, Name: "carts", Status: StatusDegraded},
+	}
+
+	summary := GetResourcesSummary(summaries)
+	if summary != "1 of 3 resources replicated to another region, 🚨 1 failing" {
+		t.Errorf("Unexpected summary '%s'", summary)
+	}
+
+	if unprotected := GetUnprotectedResources(summaries); len(unprotected) != 1 || unprotected[0].Name != "logs" {
+		t.Errorf("Expected only 'logs' to be unprotected, got %v", unprotected)
+	}
+}
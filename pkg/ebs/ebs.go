@@ -0,0 +1,229 @@
+package ebs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/correctedcloud/aws-overview/pkg/cloudwatchmetrics"
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// LowBurstBalance is the burst balance, in percent, below which a volume is
+// about to be throttled to its baseline performance
+const LowBurstBalance = 20.0
+
+// ec2ClientAPI defines the interface for the EC2 client
+type ec2ClientAPI interface {
+	DescribeVolumes(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error)
+}
+
+// cloudwatchClientAPI defines the interface for the CloudWatch client
+type cloudwatchClientAPI interface {
+	GetMetricData(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error)
+}
+
+// Client represents an EBS client
+type Client struct {
+	ec2Client        ec2ClientAPI
+	cloudwatchClient cloudwatchClientAPI
+	pool             *common.Pool
+}
+
+// VolumeSummary represents an EBS volume
+type VolumeSummary struct {
+	ID               string
+	Name             string
+	SizeGiB          int32
+	Type             string // e.g. "gp3", "io2" or "st1"
+	IOPS             int32  // Provisioned or baseline IOPS, 0 when not reported
+	Throughput       int32  // Provisioned throughput in MiB/s, gp3 only
+	State            string // e.g. "in-use" or "available"
+	AvailabilityZone string
+	Encrypted        bool
+	CreateTime       time.Time
+	Attachments      []Attachment
+	BurstBalanceData []float64 // Percent over the past hour, only for burstable volume types
+	MetricsEnd       time.Time // End of the burst balance's window, zero when it was not fetched
+}
+
+// Attachment represents the attachment of a volume to an instance
+type Attachment struct {
+	InstanceID string
+	Device     string // e.g. /dev/xvda
+	State      string // e.g. "attached" or "detaching"
+}
+
+// Unattached reports whether the volume is not attached to any instance, and
+// so is likely orphaned
+func (v VolumeSummary) Unattached() bool {
+	return v.State == string(types.VolumeStateAvailable)
+}
+
+// Burstable reports whether the volume type spends burst credits above its
+// baseline performance
+func (v VolumeSummary) Burstable() bool {
+	switch types.VolumeType(v.Type) {
+	case types.VolumeTypeGp2, types.VolumeTypeSt1, types.VolumeTypeSc1:
+		return true
+	}
+	return false
+}
+
+// BurstBalance returns the latest burst balance in percent, and false when
+// there is none
+func (v VolumeSummary) BurstBalance() (float64, bool) {
+	if len(v.BurstBalanceData) == 0 {
+		return 0, false
+	}
+	return v.BurstBalanceData[len(v.BurstBalanceData)-1], true
+}
+
+// Throttled reports whether the volume has run low on burst credits
+func (v VolumeSummary) Throttled() bool {
+	balance, ok := v.BurstBalance()
+	return ok && balance < LowBurstBalance
+}
+
+// NewClient returns a new EBS client whose calls run in pool, which may be nil
+func NewClient(ec2Client ec2ClientAPI, cloudwatchClient cloudwatchClientAPI, pool *common.Pool) *Client {
+	return &Client{
+		ec2Client:        ec2Client,
+		cloudwatchClient: cloudwatchClient,
+		pool:             pool,
+	}
+}
+
+// GetVolumes returns all volumes, unattached ones first and then by name,
+// with the burst balance of the attached burstable volumes. Burst balances
+// that fail to load are left empty and their errors returned alongside the
+// volumes.
+func (c *Client) GetVolumes(ctx context.Context) ([]VolumeSummary, []error) {
+	volumes, err := c.describeVolumes(ctx)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	summaries := make([]VolumeSummary, len(volumes))
+	for i, volume := range volumes {
+		summaries[i] = newVolumeSummary(volume)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Unattached() != summaries[j].Unattached() {
+			return summaries[i].Unattached()
+		}
+		if summaries[i].Name != summaries[j].Name {
+			return summaries[i].Name < summaries[j].Name
+		}
+		return summaries[i].ID < summaries[j].ID
+	})
+
+	errs := c.getBurstBalances(ctx, summaries)
+	return summaries, errs
+}
+
+// describeVolumes returns all volumes, following the pagination tokens
+func (c *Client) describeVolumes(ctx context.Context) ([]types.Volume, error) {
+	var volumes []types.Volume
+	var nextToken *string
+
+	for {
+		var result *ec2.DescribeVolumesOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.ec2Client.DescribeVolumes(ctx, &ec2.DescribeVolumesInput{
+				NextToken: nextToken,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe volumes: %w", err)
+		}
+
+		volumes = append(volumes, result.Volumes...)
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return volumes, nil
+}
+
+// newVolumeSummary returns the summary of a volume without metrics
+func newVolumeSummary(volume types.Volume) VolumeSummary {
+	summary := VolumeSummary{
+		ID:               aws.ToString(volume.VolumeId),
+		SizeGiB:          aws.ToInt32(volume.Size),
+		Type:             string(volume.VolumeType),
+		IOPS:             aws.ToInt32(volume.Iops),
+		Throughput:       aws.ToInt32(volume.Throughput),
+		State:            string(volume.State),
+		AvailabilityZone: aws.ToString(volume.AvailabilityZone),
+		Encrypted:        aws.ToBool(volume.Encrypted),
+		CreateTime:       aws.ToTime(volume.CreateTime),
+	}
+
+	for _, tag := range volume.Tags {
+		if aws.ToString(tag.Key) == "Name" {
+			summary.Name = aws.ToString(tag.Value)
+		}
+	}
+
+	for _, attachment := range volume.Attachments {
+		summary.Attachments = append(summary.Attachments, Attachment{
+			InstanceID: aws.ToString(attachment.InstanceId),
+			Device:     aws.ToString(attachment.Device),
+			State:      string(attachment.State),
+		})
+	}
+
+	return summary
+}
+
+// getBurstBalances fills in the burst balance of the attached burstable
+// volumes, fetched together in as few CloudWatch calls as possible, and
+// returns the errors of the balances that could not be loaded. Volumes that
+// are not attached report no metrics.
+func (c *Client) getBurstBalances(ctx context.Context, summaries []VolumeSummary) []error {
+	var queries []cloudwatchmetrics.Query
+	var queried []*VolumeSummary
+	for i := range summaries {
+		summary := &summaries[i]
+		if !summary.Burstable() || summary.Unattached() {
+			continue
+		}
+		queries = append(queries, cloudwatchmetrics.Query{
+			Namespace:  "AWS/EBS",
+			MetricName: "BurstBalance",
+			Dimensions: map[string]string{"VolumeId": summary.ID},
+			Stat:       "Minimum",
+			Period:     5 * time.Minute,
+			Window:     time.Hour,
+		})
+		queried = append(queried, summary)
+	}
+	if len(queries) == 0 {
+		return nil
+	}
+
+	results := cloudwatchmetrics.New(c.cloudwatchClient, c.pool).Fetch(ctx, queries)
+
+	var errs []error
+	for i, result := range results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("volume %s: failed to get metric data for BurstBalance: %w", queried[i].ID, result.Err))
+			continue
+		}
+		queried[i].BurstBalanceData = result.Values
+		queried[i].MetricsEnd = result.End
+	}
+	return errs
+}
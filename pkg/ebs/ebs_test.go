@@ -0,0 +1,142 @@
+package ebs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// Mock EC2 client
+type mockEC2Client struct {
+	describeVolumesFunc func(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error)
+}
+
+func (m *mockEC2Client) DescribeVolumes(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+	return m.describeVolumesFunc(ctx, params, optFns...)
+}
+
+// Mock CloudWatch client
+type mockCloudWatchClient struct {
+	getMetricDataFunc func(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error)
+}
+
+func (m *mockCloudWatchClient) GetMetricData(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+	return m.getMetricDataFunc(ctx, params, optFns...)
+}
+
+func TestGetVolumes(t *testing.T) {
+	ec2Client := &mockEC2Client{
+		describeVolumesFunc: func(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+			// Return the volumes over two pages
+			if params.NextToken == nil {
+				return &ec2.DescribeVolumesOutput{
+					Volumes: []types.Volume{
+						{
+							VolumeId:   aws.String("vol-1"),
+							Size:       aws.Int32(8),
+							VolumeType: types.VolumeTypeGp2,
+							Iops:       aws.Int32(100),
+							State:      types.VolumeStateInUse,
+							Tags:       []types.Tag{{Key: aws.String("Name"), Value: aws.String("root")}},
+							Attachments: []types.VolumeAttachment{
+								{InstanceId: aws.String("i-1"), Device: aws.String("/dev/xvda"), State: types.VolumeAttachmentStateAttached},
+							},
+						},
+						{
+							VolumeId:   aws.String("vol-2"),
+							Size:       aws.Int32(30),
+							VolumeType: types.VolumeTypeGp3,
+							State:      types.VolumeStateInUse,
+							Attachments: []types.VolumeAttachment{
+								{InstanceId: aws.String("i-1"), Device: aws.String("/dev/sdf"), State: types.VolumeAttachmentStateAttached},
+							},
+						},
+					},
+					NextToken: aws.String("page-2"),
+				}, nil
+			}
+			return &ec2.DescribeVolumesOutput{
+				Volumes: []types.Volume{
+					{VolumeId: aws.String("vol-3"), Size: aws.Int32(100), VolumeType: types.VolumeTypeGp2, State: types.VolumeStateAvailable},
+				},
+			}, nil
+		},
+	}
+
+	var queried []string
+	cloudwatchClient := &mockCloudWatchClient{
+		getMetricDataFunc: func(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+			output := &cloudwatch.GetMetricDataOutput{}
+			for _, query := range params.MetricDataQueries {
+				queried = append(queried, aws.ToString(query.MetricStat.Metric.Dimensions[0].Value))
+				output.MetricDataResults = append(output.MetricDataResults, cwtypes.MetricDataResult{
+					Id:     query.Id,
+					Values: []float64{12, 15},
+				})
+			}
+			return output, nil
+		},
+	}
+
+	client := NewClient(ec2Client, cloudwatchClient, nil)
+	volumes, errs := client.GetVolumes(context.Background())
+
+	if len(errs) > 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+	if len(volumes) != 3 {
+		t.Fatalf("Expected 3 volumes, got %d", len(volumes))
+	}
+
+	// Unattached volumes come first
+	if volumes[0].ID != "vol-3" || !volumes[0].Unattached() {
+		t.Errorf("Expected the unattached vol-3 first, got %+v", volumes[0])
+	}
+	if volumes[2].Name != "root" || len(volumes[2].Attachments) != 1 || volumes[2].Attachments[0].Device != "/dev/xvda" {
+		t.Errorf("Unexpected root volume %+v", volumes[2])
+	}
+
+	// Only the attached gp2 volume reports a burst balance
+	if len(queried) != 1 || queried[0] != "vol-1" {
+		t.Errorf("Expected only vol-1 to be queried, got %v", queried)
+	}
+	if balance, ok := volumes[2].BurstBalance(); !ok || balance != 15 {
+		t.Errorf("Expected a burst balance of 15, got %v, %v", balance, ok)
+	}
+}
+
+func TestGetVolumesErrors(t *testing.T) {
+	volumes := []types.Volume{
+		{VolumeId: aws.String("vol-1"), VolumeType: types.VolumeTypeGp2, State: types.VolumeStateInUse},
+	}
+	ec2Client := &mockEC2Client{
+		describeVolumesFunc: func(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+			return &ec2.DescribeVolumesOutput{Volumes: volumes}, nil
+		},
+	}
+	cloudwatchClient := &mockCloudWatchClient{
+		getMetricDataFunc: func(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+			return nil, errors.New("throttled")
+		},
+	}
+
+	// A failing metric keeps the volume
+	summaries, errs := NewClient(ec2Client, cloudwatchClient, nil).GetVolumes(context.Background())
+	if len(summaries) != 1 || len(errs) != 1 {
+		t.Errorf("Expected the volume with one error, got %v, %v", summaries, errs)
+	}
+
+	ec2Client.describeVolumesFunc = func(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+		return nil, errors.New("access denied")
+	}
+	summaries, errs = NewClient(ec2Client, cloudwatchClient, nil).GetVolumes(context.Background())
+	if summaries != nil || len(errs) != 1 {
+		t.Errorf("Expected only an error, got %v, %v", summaries, errs)
+	}
+}
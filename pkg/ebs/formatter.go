@@ -0,0 +1,158 @@
+package ebs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// timeNow is the clock the age of unattached volumes is measured against
+var timeNow = time.Now
+
+// FormatVolumes formats the volumes for terminal display, listing the
+// orphaned and throttled volumes first
+func FormatVolumes(summaries []VolumeSummary) string {
+	if len(summaries) == 0 {
+		return "No EBS volumes found"
+	}
+
+	var output strings.Builder
+	output.WriteString("EBS VOLUMES\n")
+	output.WriteString(common.Rule("EBS VOLUMES", "=") + "\n\n")
+
+	if unattached := GetUnattachedVolumes(summaries); len(unattached) > 0 {
+		output.WriteString(fmt.Sprintf("%s UNATTACHED (%d volumes, %d GiB not attached to any instance)\n",
+			common.Symbol("⚠️"), len(unattached), totalSize(unattached)))
+		for _, volume := range unattached {
+			output.WriteString(fmt.Sprintf("  %s: %d GiB %s, created %s ago\n",
+				volumeName(volume), volume.SizeGiB, volume.Type, formatAge(volume.CreateTime)))
+		}
+		output.WriteString("\n")
+	}
+	if throttled := GetThrottledVolumes(summaries); len(throttled) > 0 {
+		output.WriteString(fmt.Sprintf("%s LOW BURST BALANCE (%d volumes below %.0f%%)\n",
+			common.Symbol("⚠️"), len(throttled), LowBurstBalance))
+		for _, volume := range throttled {
+			balance, _ := volume.BurstBalance()
+			output.WriteString(fmt.Sprintf("  %s: %s left\n", volumeName(volume), common.FormatPercentage(balance)))
+		}
+		output.WriteString("\n")
+	}
+
+	for _, volume := range summaries {
+		output.WriteString(fmt.Sprintf("%s %s\n", common.Symbol(getStatusSymbol(volume)), volumeName(volume)))
+		output.WriteString(fmt.Sprintf("  %s\n", formatVolumeType(volume)))
+		output.WriteString(fmt.Sprintf("  State: %s in %s\n", volume.State, volume.AvailabilityZone))
+
+		for _, attachment := range volume.Attachments {
+			output.WriteString(fmt.Sprintf("  Attached to %s as %s (%s)\n", attachment.InstanceID, attachment.Device, attachment.State))
+		}
+
+		if balance, ok := volume.BurstBalance(); ok {
+			output.WriteString(fmt.Sprintf("  Burst balance: %s\n", common.FormatPercentage(balance)))
+			output.WriteString(common.GenerateSparkline(volume.BurstBalanceData, "Burst balance (%)", 3,
+				common.WithStats(), common.WithThreshold(LowBurstBalance),
+				common.WithWindow(volume.MetricsEnd.Add(-time.Hour), volume.MetricsEnd)) + "\n")
+		} else if volume.Burstable() && !volume.Unattached() {
+			output.WriteString("  No burst balance data available\n")
+		}
+
+		output.WriteString("\n")
+	}
+
+	return output.String()
+}
+
+// GetVolumesSummary returns a brief summary of the volumes
+func GetVolumesSummary(summaries []VolumeSummary) string {
+	summary := fmt.Sprintf("%d volumes, %d GiB", len(summaries), totalSize(summaries))
+	if unattached := GetUnattachedVolumes(summaries); len(unattached) > 0 {
+		summary += fmt.Sprintf(", %d unattached (%d GiB)", len(unattached), totalSize(unattached))
+	}
+	if throttled := len(GetThrottledVolumes(summaries)); throttled > 0 {
+		summary += fmt.Sprintf(", %d low on burst balance", throttled)
+	}
+	return summary
+}
+
+// GetUnattachedVolumes returns the volumes not attached to any instance
+func GetUnattachedVolumes(summaries []VolumeSummary) []VolumeSummary {
+	var unattached []VolumeSummary
+	for _, volume := range summaries {
+		if volume.Unattached() {
+			unattached = append(unattached, volume)
+		}
+	}
+	return unattached
+}
+
+// GetThrottledVolumes returns the volumes whose burst balance is below LowBurstBalance
+func GetThrottledVolumes(summaries []VolumeSummary) []VolumeSummary {
+	var throttled []VolumeSummary
+	for _, volume := range summaries {
+		if volume.Throttled() {
+			throttled = append(throttled, volume)
+		}
+	}
+	return throttled
+}
+
+// volumeName returns the name and ID of a volume, or its ID when it has no name
+func volumeName(volume VolumeSummary) string {
+	if volume.Name == "" {
+		return volume.ID
+	}
+	return fmt.Sprintf("%s (%s)", volume.Name, volume.ID)
+}
+
+// formatVolumeType describes the size, type and performance of a volume
+func formatVolumeType(volume VolumeSummary) string {
+	description := fmt.Sprintf("%d GiB %s", volume.SizeGiB, volume.Type)
+	if volume.IOPS > 0 {
+		description += fmt.Sprintf(", %d IOPS", volume.IOPS)
+	}
+	if volume.Throughput > 0 {
+		description += fmt.Sprintf(", %d MiB/s", volume.Throughput)
+	}
+	if volume.Encrypted {
+		description += ", encrypted"
+	}
+	return description
+}
+
+// formatAge returns how long ago t was, in days once it is over a day
+func formatAge(t time.Time) string {
+	age := timeNow().Sub(t)
+	if age >= 24*time.Hour {
+		return fmt.Sprintf("%dd", int(age.Hours()/24))
+	}
+	return age.Round(time.Minute).String()
+}
+
+// totalSize returns the combined size of the volumes in GiB
+func totalSize(summaries []VolumeSummary) int {
+	total := 0
+	for _, volume := range summaries {
+		total += int(volume.SizeGiB)
+	}
+	return total
+}
+
+// getStatusSymbol returns the emoji of a volume's state
+func getStatusSymbol(volume VolumeSummary) string {
+	switch {
+	case volume.Unattached():
+		return "⚠️"
+	case volume.Throttled():
+		return "🐢"
+	case volume.State == "in-use":
+		return "✅"
+	case volume.State == "creating":
+		return "🔄"
+	case volume.State == "error":
+		return "❌"
+	}
+	return "❓"
+}
@@ -0,0 +1,67 @@
+package ebs
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatVolumes(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	oldTimeNow := timeNow
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = oldTimeNow }()
+
+	summaries := []VolumeSummary{
+		{ID: "vol-1", SizeGiB: 200, Type: "gp2", IOPS: 600, State: "available", AvailabilityZone: "us-east-1a", CreateTime: now.Add(-30 * 24 * time.Hour)},
+		{ID: "vol-2", Name: "web-root", SizeGiB: 30, Type: "gp3", IOPS: 3000, Throughput: 125, State: "in-use", AvailabilityZone: "us-east-1a", Encrypted: true,
+			Attachments: []Attachment{{InstanceID: "i-1", Device: "/dev/xvda", State: "attached"}}},
+		{ID: "vol-3", Name: "bastion-root", SizeGiB: 8, Type: "gp2", IOPS: 100, State: "in-use", AvailabilityZone: "us-east-1b",
+			Attachments:      []Attachment{{InstanceID: "i-2", Device: "/dev/xvda", State: "attached"}},
+			BurstBalanceData: []float64{30, 20, 12.5}},
+	}
+
+	output := FormatVolumes(summaries)
+
+	for _, expected := range []string{
+		"EBS VOLUMES",
+		"UNATTACHED (1 volumes, 200 GiB not attached to any instance)\n",
+		"  vol-1: 200 GiB gp2, created 30d ago\n",
+		"LOW BURST BALANCE (1 volumes below 20%)\n",
+		"  bastion-root (vol-3): 12.50% left\n",
+		"✅ web-root (vol-2)\n  30 GiB gp3, 3000 IOPS, 125 MiB/s, encrypted\n  State: in-use in us-east-1a\n  Attached to i-1 as /dev/xvda (attached)\n",
+		"🐢 bastion-root (vol-3)\n",
+		"  Burst balance: 12.50%\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain '%s', got:\n%s", expected, output)
+		}
+	}
+
+	if output := FormatVolumes(nil); output != "No EBS volumes found" {
+		t.Errorf("Expected an empty message, got '%s'", output)
+	}
+	if summary := GetVolumesSummary(summaries); summary != "3 volumes, 238 GiB, 1 unattached (200 GiB), 1 low on burst balance" {
+		t.Errorf("Unexpected summary '%s'", summary)
+	}
+}
+
+func TestVolumeSummaryThrottled(t *testing.T) {
+	tests := []struct {
+		name     string
+		volume   VolumeSummary
+		expected bool
+	}{
+		{"no data", VolumeSummary{Type: "gp2"}, false},
+		{"plenty of credits", VolumeSummary{Type: "gp2", BurstBalanceData: []float64{5, 90}}, false},
+		{"running out", VolumeSummary{Type: "st1", BurstBalanceData: []float64{90, 19}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if throttled := tt.volume.Throttled(); throttled != tt.expected {
+				t.Errorf("Throttled() = %v, expected %v", throttled, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package ec2
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// Action is an operation changing the state of an instance
+type Action string
+
+// Actions on instances
+const (
+	ActionStart  Action = "Start"
+	ActionStop   Action = "Stop"
+	ActionReboot Action = "Reboot"
+)
+
+// Actions returns the actions that apply to an instance in its current
+// state: stopped instances can be started, running ones stopped or rebooted
+func Actions(instance InstanceSummary) []Action {
+	switch types.InstanceStateName(instance.State) {
+	case types.InstanceStateNameStopped:
+		return []Action{ActionStart}
+	case types.InstanceStateNameRunning:
+		return []Action{ActionStop, ActionReboot}
+	}
+	return nil
+}
+
+// RunAction runs action on an instance and returns its state afterwards,
+// e.g. "stopping", or "rebooting" for a reboot, which keeps it running
+func (c *Client) RunAction(ctx context.Context, action Action, instanceID string) (string, error) {
+	ids := []string{instanceID}
+	var changes []types.InstanceStateChange
+
+	switch action {
+	case ActionStart:
+		output, err := c.ec2Client.StartInstances(ctx, &ec2.StartInstancesInput{InstanceIds: ids})
+		if err != nil {
+			return "", fmt.Errorf("failed to start instance %s: %w", instanceID, err)
+		}
+		changes = output.StartingInstances
+	case ActionStop:
+		output, err := c.ec2Client.StopInstances(ctx, &ec2.StopInstancesInput{InstanceIds: ids})
+		if err != nil {
+			return "", fmt.Errorf("failed to stop instance %s: %w", instanceID, err)
+		}
+		changes = output.StoppingInstances
+	case ActionReboot:
+		if _, err := c.ec2Client.RebootInstances(ctx, &ec2.RebootInstancesInput{InstanceIds: ids}); err != nil {
+			return "", fmt.Errorf("failed to reboot instance %s: %w", instanceID, err)
+		}
+		return "rebooting", nil
+	default:
+		return "", fmt.Errorf("unknown instance action %q", action)
+	}
+
+	for _, change := range changes {
+		if change.CurrentState != nil {
+			return string(change.CurrentState.Name), nil
+		}
+	}
+	return "", nil
+}
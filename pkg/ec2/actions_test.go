@@ -0,0 +1,57 @@
+package ec2
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestActions(t *testing.T) {
+	tests := []struct {
+		state string
+		want  []Action
+	}{
+		{"running", []Action{ActionStop, ActionReboot}},
+		{"stopped", []Action{ActionStart}},
+		{"pending", nil},
+		{"stopping", nil},
+	}
+	for _, tt := range tests {
+		if got := Actions(InstanceSummary{State: tt.state}); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Actions(%s) = %v, want %v", tt.state, got, tt.want)
+		}
+	}
+}
+
+func TestRunAction(t *testing.T) {
+	mock := &mockEC2API{}
+	client := NewClient(mock)
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		action Action
+		want   string
+	}{
+		{ActionStart, "pending"},
+		{ActionStop, "stopping"},
+		{ActionReboot, "rebooting"},
+	} {
+		state, err := client.RunAction(ctx, tt.action, "i-1")
+		if err != nil {
+			t.Fatalf("RunAction(%s) returned an error: %v", tt.action, err)
+		}
+		if state != tt.want {
+			t.Errorf("RunAction(%s) = %q, want %q", tt.action, state, tt.want)
+		}
+	}
+	if len(mock.started) != 1 || len(mock.stopped) != 1 || len(mock.rebooted) != 1 {
+		t.Errorf("Expected one call of each action, got started %v, stopped %v, rebooted %v", mock.started, mock.stopped, mock.rebooted)
+	}
+
+	mock.actionErr = errors.New("UnauthorizedOperation")
+	if _, err := client.RunAction(ctx, ActionStop, "i-1"); err == nil || !strings.Contains(err.Error(), "failed to stop instance i-1") {
+		t.Errorf("Expected a stop error, got %v", err)
+	}
+}
@@ -15,6 +15,12 @@ import (
 // EC2API defines the interface for EC2 API operations
 type EC2API interface {
 	DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error)
+	DescribeInstanceStatus(ctx context.Context, params *ec2.DescribeInstanceStatusInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstanceStatusOutput, error)
+	DescribeSecurityGroups(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error)
+	DescribeSpotInstanceRequests(ctx context.Context, params *ec2.DescribeSpotInstanceRequestsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSpotInstanceRequestsOutput, error)
+	StartInstances(ctx context.Context, params *ec2.StartInstancesInput, optFns ...func(*ec2.Options)) (*ec2.StartInstancesOutput, error)
+	StopInstances(ctx context.Context, params *ec2.StopInstancesInput, optFns ...func(*ec2.Options)) (*ec2.StopInstancesOutput, error)
+	RebootInstances(ctx context.Context, params *ec2.RebootInstancesInput, optFns ...func(*ec2.Options)) (*ec2.RebootInstancesOutput, error)
 }
 
 // Client is the EC2 client
@@ -42,12 +48,49 @@ type InstanceSummary struct {
 	VpcID            string
 	SubnetID         string
 	SecurityGroups   []string
+	SecurityGroupIDs []string
 	Tags             map[string]string
 	AvailabilityZone string
+	SystemStatus     string           // Result of the system status check, e.g. "ok" or "impaired", empty when not reported
+	InstanceStatus   string           // Result of the instance status check
+	ScheduledEvents  []ScheduledEvent // Upcoming maintenance, such as reboots or retirement
+	Lifecycle        string           // "on-demand", "spot" or "scheduled"
+	Tenancy          string           // "default", "dedicated" or "host"
+
+	SecurityGroupDetails []SecurityGroup // Inbound rules of the security groups, empty when they could not be described
+
+	SpotRequestID     string // Spot request the instance was launched by, empty for other instances
+	SpotStatus        string // Status code of the Spot request, e.g. "fulfilled" or "marked-for-termination"
+	SpotStatusMessage string
+
+	StoppedAt time.Time // When the instance was stopped, zero when it is not stopped or EC2 did not record it
+}
+
+// ScheduledEvent represents maintenance AWS scheduled for an instance
+type ScheduledEvent struct {
+	Code        string // e.g. "system-reboot" or "instance-retirement"
+	Description string
+	NotBefore   time.Time
+	NotAfter    time.Time
+}
+
+// Impaired reports whether the system or instance status check failed
+func (i InstanceSummary) Impaired() bool {
+	return i.SystemStatus == string(types.SummaryStatusImpaired) || i.InstanceStatus == string(types.SummaryStatusImpaired)
+}
+
+// instanceStatus holds the status check results and events of an instance
+type instanceStatus struct {
+	system   string
+	instance string
+	events   []ScheduledEvent
 }
 
-// GetInstances returns a list of EC2 instances
-func (c *Client) GetInstances(ctx context.Context) ([]InstanceSummary, error) {
+// GetInstances returns a list of EC2 instances with their status checks,
+// scheduled events and security group rules. When the statuses or security
+// groups fail to load the instances are returned without them, together
+// with the errors.
+func (c *Client) GetInstances(ctx context.Context) ([]InstanceSummary, []error) {
 	var instances []InstanceSummary
 	var nextToken *string
 	var mutex sync.Mutex
@@ -59,7 +102,7 @@ func (c *Client) GetInstances(ctx context.Context) ([]InstanceSummary, error) {
 			NextToken: nextToken,
 		})
 		if err != nil {
-			return nil, fmt.Errorf("failed to describe instances: %w", err)
+			return nil, []error{fmt.Errorf("failed to describe instances: %w", err)}
 		}
 
 		// Process reservations and instances in parallel
@@ -89,10 +132,11 @@ func (c *Client) GetInstances(ctx context.Context) ([]InstanceSummary, error) {
 						}
 					}
 
-					// Extract security group names
-					var securityGroups []string
+					// Extract security group names and IDs
+					var securityGroups, securityGroupIDs []string
 					for _, sg := range instance.SecurityGroups {
 						securityGroups = append(securityGroups, aws.ToString(sg.GroupName))
+						securityGroupIDs = append(securityGroupIDs, aws.ToString(sg.GroupId))
 					}
 
 					// Create instance summary
@@ -108,8 +152,13 @@ func (c *Client) GetInstances(ctx context.Context) ([]InstanceSummary, error) {
 						VpcID:            aws.ToString(instance.VpcId),
 						SubnetID:         aws.ToString(instance.SubnetId),
 						SecurityGroups:   securityGroups,
+						SecurityGroupIDs: securityGroupIDs,
 						Tags:             tags,
 						AvailabilityZone: getAvailabilityZone(instance),
+						Lifecycle:        getLifecycle(instance),
+						Tenancy:          getTenancy(instance),
+						SpotRequestID:    aws.ToString(instance.SpotInstanceRequestId),
+						StoppedAt:        getStoppedAt(instance),
 					}
 
 					reservationInstances = append(reservationInstances, summary)
@@ -132,10 +181,78 @@ func (c *Client) GetInstances(ctx context.Context) ([]InstanceSummary, error) {
 	wg.Wait()
 
 	if fetchErr != nil {
-		return nil, fetchErr
+		return nil, []error{fetchErr}
+	}
+
+	var errs []error
+	statuses, err := c.getInstanceStatuses(ctx)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for i := range instances {
+		if status, ok := statuses[instances[i].InstanceID]; ok {
+			instances[i].SystemStatus = status.system
+			instances[i].InstanceStatus = status.instance
+			instances[i].ScheduledEvents = status.events
+		}
+	}
+
+	if err := c.resolveSecurityGroups(ctx, instances); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.resolveSpotRequests(ctx, instances); err != nil {
+		errs = append(errs, err)
+	}
+
+	return instances, errs
+}
+
+// getInstanceStatuses returns the status checks and upcoming scheduled
+// events of all instances, stopped ones included, by instance ID
+func (c *Client) getInstanceStatuses(ctx context.Context) (map[string]instanceStatus, error) {
+	statuses := make(map[string]instanceStatus)
+	var nextToken *string
+
+	for {
+		resp, err := c.ec2Client.DescribeInstanceStatus(ctx, &ec2.DescribeInstanceStatusInput{
+			IncludeAllInstances: aws.Bool(true),
+			NextToken:           nextToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe instance status: %w", err)
+		}
+
+		for _, status := range resp.InstanceStatuses {
+			var summary instanceStatus
+			if status.SystemStatus != nil {
+				summary.system = string(status.SystemStatus.Status)
+			}
+			if status.InstanceStatus != nil {
+				summary.instance = string(status.InstanceStatus.Status)
+			}
+			for _, event := range status.Events {
+				// Events stay listed for a while after they completed or were canceled
+				description := aws.ToString(event.Description)
+				if strings.HasPrefix(description, "[Completed]") || strings.HasPrefix(description, "[Canceled]") {
+					continue
+				}
+				summary.events = append(summary.events, ScheduledEvent{
+					Code:        string(event.Code),
+					Description: description,
+					NotBefore:   aws.ToTime(event.NotBefore),
+					NotAfter:    aws.ToTime(event.NotAfter),
+				})
+			}
+			statuses[aws.ToString(status.InstanceId)] = summary
+		}
+
+		nextToken = resp.NextToken
+		if nextToken == nil {
+			break
+		}
 	}
 
-	return instances, nil
+	return statuses, nil
 }
 
 // getPlatform returns the platform of the instance
@@ -159,6 +276,43 @@ func getPlatform(instance types.Instance) string {
 	return "Unknown"
 }
 
+// getStoppedAt returns when a stopped instance was stopped, which EC2 only
+// records in the state transition reason, e.g. "User initiated (2024-01-01
+// 12:00:00 GMT)"
+func getStoppedAt(instance types.Instance) time.Time {
+	if instance.State == nil || instance.State.Name != types.InstanceStateNameStopped {
+		return time.Time{}
+	}
+
+	reason := aws.ToString(instance.StateTransitionReason)
+	start, end := strings.LastIndex(reason, "("), strings.LastIndex(reason, " GMT)")
+	if start < 0 || end < start {
+		return time.Time{}
+	}
+	stoppedAt, err := time.Parse("2006-01-02 15:04:05", reason[start+1:end])
+	if err != nil {
+		return time.Time{}
+	}
+	return stoppedAt
+}
+
+// getLifecycle returns whether the instance is a Spot, Scheduled or
+// On-Demand instance, which the API leaves empty
+func getLifecycle(instance types.Instance) string {
+	if instance.InstanceLifecycle == "" {
+		return "on-demand"
+	}
+	return string(instance.InstanceLifecycle)
+}
+
+// getTenancy safely returns the tenancy of the instance
+func getTenancy(instance types.Instance) string {
+	if instance.Placement == nil || instance.Placement.Tenancy == "" {
+		return string(types.TenancyDefault)
+	}
+	return string(instance.Placement.Tenancy)
+}
+
 // getAvailabilityZone safely returns the availability zone of the instance
 func getAvailabilityZone(instance types.Instance) string {
 	if instance.Placement == nil || instance.Placement.AvailabilityZone == nil {
@@ -2,20 +2,80 @@ package ec2
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 )
 
 type mockEC2API struct {
-	DescribeInstancesFunc func(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error)
+	DescribeInstancesFunc      func(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error)
+	DescribeInstanceStatusFunc func(ctx context.Context, params *ec2.DescribeInstanceStatusInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstanceStatusOutput, error)
+	DescribeSecurityGroupsFunc func(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error)
+
+	DescribeSpotInstanceRequestsFunc func(ctx context.Context, params *ec2.DescribeSpotInstanceRequestsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSpotInstanceRequestsOutput, error)
+
+	// Instances started, stopped and rebooted, in order
+	started, stopped, rebooted []string
+	actionErr                  error
 }
 
 func (m *mockEC2API) DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
 	return m.DescribeInstancesFunc(ctx, params, optFns...)
 }
 
+func (m *mockEC2API) DescribeInstanceStatus(ctx context.Context, params *ec2.DescribeInstanceStatusInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstanceStatusOutput, error) {
+	if m.DescribeInstanceStatusFunc == nil {
+		return &ec2.DescribeInstanceStatusOutput{}, nil
+	}
+	return m.DescribeInstanceStatusFunc(ctx, params, optFns...)
+}
+
+func (m *mockEC2API) DescribeSecurityGroups(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
+	if m.DescribeSecurityGroupsFunc == nil {
+		return &ec2.DescribeSecurityGroupsOutput{}, nil
+	}
+	return m.DescribeSecurityGroupsFunc(ctx, params, optFns...)
+}
+
+func (m *mockEC2API) DescribeSpotInstanceRequests(ctx context.Context, params *ec2.DescribeSpotInstanceRequestsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSpotInstanceRequestsOutput, error) {
+	if m.DescribeSpotInstanceRequestsFunc == nil {
+		return &ec2.DescribeSpotInstanceRequestsOutput{}, nil
+	}
+	return m.DescribeSpotInstanceRequestsFunc(ctx, params, optFns...)
+}
+
+func (m *mockEC2API) StartInstances(ctx context.Context, params *ec2.StartInstancesInput, optFns ...func(*ec2.Options)) (*ec2.StartInstancesOutput, error) {
+	if m.actionErr != nil {
+		return nil, m.actionErr
+	}
+	m.started = append(m.started, params.InstanceIds...)
+	return &ec2.StartInstancesOutput{StartingInstances: []types.InstanceStateChange{
+		{InstanceId: aws.String(params.InstanceIds[0]), CurrentState: &types.InstanceState{Name: types.InstanceStateNamePending}},
+	}}, nil
+}
+
+func (m *mockEC2API) StopInstances(ctx context.Context, params *ec2.StopInstancesInput, optFns ...func(*ec2.Options)) (*ec2.StopInstancesOutput, error) {
+	if m.actionErr != nil {
+		return nil, m.actionErr
+	}
+	m.stopped = append(m.stopped, params.InstanceIds...)
+	return &ec2.StopInstancesOutput{StoppingInstances: []types.InstanceStateChange{
+		{InstanceId: aws.String(params.InstanceIds[0]), CurrentState: &types.InstanceState{Name: types.InstanceStateNameStopping}},
+	}}, nil
+}
+
+func (m *mockEC2API) RebootInstances(ctx context.Context, params *ec2.RebootInstancesInput, optFns ...func(*ec2.Options)) (*ec2.RebootInstancesOutput, error) {
+	if m.actionErr != nil {
+		return nil, m.actionErr
+	}
+	m.rebooted = append(m.rebooted, params.InstanceIds...)
+	return &ec2.RebootInstancesOutput{}, nil
+}
+
 func TestGetInstances(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -78,9 +138,9 @@ func TestGetInstances(t *testing.T) {
 				},
 			})
 
-			got, err := client.GetInstances(context.Background())
-			if (err != nil) != tt.wantErr {
-				t.Errorf("GetInstances() error = %v, wantErr %v", err, tt.wantErr)
+			got, errs := client.GetInstances(context.Background())
+			if (len(errs) > 0) != tt.wantErr {
+				t.Errorf("GetInstances() errors = %v, wantErr %v", errs, tt.wantErr)
 				return
 			}
 			if len(got) != tt.wantCount {
@@ -90,6 +150,70 @@ func TestGetInstances(t *testing.T) {
 	}
 }
 
+func TestGetInstancesWithStatus(t *testing.T) {
+	describeInstances := func(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+		return &ec2.DescribeInstancesOutput{Reservations: []types.Reservation{{Instances: []types.Instance{
+			{InstanceId: ptrString("i-12345"), State: &types.InstanceState{Name: types.InstanceStateNameRunning}},
+			{InstanceId: ptrString("i-67890"), State: &types.InstanceState{Name: types.InstanceStateNameRunning}},
+		}}}}, nil
+	}
+
+	client := NewClient(&mockEC2API{
+		DescribeInstancesFunc: describeInstances,
+		DescribeInstanceStatusFunc: func(ctx context.Context, params *ec2.DescribeInstanceStatusInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstanceStatusOutput, error) {
+			if !aws.ToBool(params.IncludeAllInstances) {
+				t.Error("Expected the status of all instances to be requested")
+			}
+			notBefore := time.Date(2024, 1, 8, 2, 0, 0, 0, time.UTC)
+			return &ec2.DescribeInstanceStatusOutput{InstanceStatuses: []types.InstanceStatus{
+				{
+					InstanceId:     ptrString("i-12345"),
+					SystemStatus:   &types.InstanceStatusSummary{Status: types.SummaryStatusOk},
+					InstanceStatus: &types.InstanceStatusSummary{Status: types.SummaryStatusImpaired},
+				},
+				{
+					InstanceId:     ptrString("i-67890"),
+					SystemStatus:   &types.InstanceStatusSummary{Status: types.SummaryStatusOk},
+					InstanceStatus: &types.InstanceStatusSummary{Status: types.SummaryStatusOk},
+					Events: []types.InstanceStatusEvent{
+						{Code: types.EventCodeSystemReboot, Description: ptrString("scheduled reboot"), NotBefore: &notBefore},
+						{Code: types.EventCodeInstanceReboot, Description: ptrString("[Completed] scheduled reboot")},
+					},
+				},
+			}}, nil
+		},
+	})
+
+	instances, errs := client.GetInstances(context.Background())
+	if len(errs) > 0 {
+		t.Fatalf("GetInstances() errors = %v", errs)
+	}
+	for _, instance := range instances {
+		switch instance.InstanceID {
+		case "i-12345":
+			if !instance.Impaired() || len(instance.ScheduledEvents) != 0 {
+				t.Errorf("Expected i-12345 to be impaired without events, got %+v", instance)
+			}
+		case "i-67890":
+			if instance.Impaired() || len(instance.ScheduledEvents) != 1 || instance.ScheduledEvents[0].Code != "system-reboot" {
+				t.Errorf("Expected i-67890 to be healthy with only the upcoming reboot, got %+v", instance)
+			}
+		}
+	}
+
+	// The instances are still returned when their statuses fail to load
+	client = NewClient(&mockEC2API{
+		DescribeInstancesFunc: describeInstances,
+		DescribeInstanceStatusFunc: func(ctx context.Context, params *ec2.DescribeInstanceStatusInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstanceStatusOutput, error) {
+			return nil, errors.New("UnauthorizedOperation")
+		},
+	})
+	instances, errs = client.GetInstances(context.Background())
+	if len(instances) != 2 || len(errs) != 1 {
+		t.Errorf("Expected 2 instances and 1 error, got %d instances and %v", len(instances), errs)
+	}
+}
+
 func TestGetPlatform(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -122,6 +246,37 @@ func TestGetPlatform(t *testing.T) {
 	}
 }
 
+func TestGetStoppedAt(t *testing.T) {
+	stopped := &types.InstanceState{Name: types.InstanceStateNameStopped}
+	tests := []struct {
+		name     string
+		instance types.Instance
+		want     time.Time
+	}{
+		{
+			name:     "Stopped by the user",
+			instance: types.Instance{State: stopped, StateTransitionReason: ptrString("User initiated (2024-01-01 12:30:00 GMT)")},
+			want:     time.Date(2024, 1, 1, 12, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "Unknown reason",
+			instance: types.Instance{State: stopped, StateTransitionReason: ptrString("Server.InternalError")},
+		},
+		{
+			name:     "Running instance",
+			instance: types.Instance{State: &types.InstanceState{Name: types.InstanceStateNameRunning}, StateTransitionReason: ptrString("User initiated (2024-01-01 12:30:00 GMT)")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getStoppedAt(tt.instance); !got.Equal(tt.want) {
+				t.Errorf("getStoppedAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func ptrString(s string) *string {
 	return &s
 }
@@ -5,6 +5,8 @@ import (
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
 )
 
 var timeNow = time.Now
@@ -14,6 +16,8 @@ func GetInstancesSummary(instances []InstanceSummary) string {
 	running := 0
 	stopped := 0
 	other := 0
+	spot := 0
+	interrupted := 0
 
 	for _, instance := range instances {
 		switch instance.State {
@@ -24,10 +28,57 @@ func GetInstancesSummary(instances []InstanceSummary) string {
 		default:
 			other++
 		}
+		if instance.IsSpot() {
+			spot++
+		}
+		if instance.SpotInterrupted() {
+			interrupted++
+		}
+	}
+
+	summary := fmt.Sprintf("%d total (%d running, %d stopped, %d other), %d on-demand, %d spot",
+		len(instances), running, stopped, other, len(instances)-spot, spot)
+	if interrupted > 0 {
+		summary += fmt.Sprintf(", %d spot interruptions", interrupted)
+	}
+	if impaired := len(GetImpairedInstances(instances)); impaired > 0 {
+		summary += fmt.Sprintf(", %d impaired", impaired)
+	}
+	if risky := len(GetRiskyRules(instances)); risky > 0 {
+		summary += fmt.Sprintf(", %d risky security group rules", risky)
+	}
+	return summary
+}
+
+// FormatSpotStatus formats the status of the Spot request of an instance,
+// e.g. "marked-for-termination: Spot Instance is marked for termination"
+func FormatSpotStatus(instance InstanceSummary) string {
+	if instance.SpotStatusMessage == "" {
+		return instance.SpotStatus
+	}
+	return instance.SpotStatus + ": " + instance.SpotStatusMessage
+}
+
+// GetImpairedInstances returns the instances failing a status check
+func GetImpairedInstances(instances []InstanceSummary) []InstanceSummary {
+	var impaired []InstanceSummary
+	for _, instance := range instances {
+		if instance.Impaired() {
+			impaired = append(impaired, instance)
+		}
 	}
+	return impaired
+}
 
-	return fmt.Sprintf("%d total (%d running, %d stopped, %d other)",
-		len(instances), running, stopped, other)
+// GetInstancesWithScheduledEvents returns the instances with upcoming maintenance
+func GetInstancesWithScheduledEvents(instances []InstanceSummary) []InstanceSummary {
+	var scheduled []InstanceSummary
+	for _, instance := range instances {
+		if len(instance.ScheduledEvents) > 0 {
+			scheduled = append(scheduled, instance)
+		}
+	}
+	return scheduled
 }
 
 // FormatInstances returns a formatted string of EC2 instances
@@ -53,7 +104,7 @@ func FormatInstances(instances []InstanceSummary) string {
 		if nameDisplay == "" {
 			nameDisplay = "<unnamed>"
 		}
-		sb.WriteString(fmt.Sprintf("🖥️  %s (%s)\n", nameDisplay, instance.InstanceID))
+		sb.WriteString(fmt.Sprintf("%s %s (%s)\n", common.Symbol("🖥️"), nameDisplay, instance.InstanceID))
 
 		// Format instance type and state with color indicators
 		stateIndicator := "🔴"
@@ -62,8 +113,29 @@ func FormatInstances(instances []InstanceSummary) string {
 		} else if instance.State == "stopped" {
 			stateIndicator = "🟠"
 		}
-		sb.WriteString(fmt.Sprintf("   Type: %s | State: %s %s\n",
+		sb.WriteString(fmt.Sprintf("   Type: %s | State: %s %s",
 			instance.InstanceType, stateIndicator, instance.State))
+		if instance.Lifecycle != "" {
+			sb.WriteString(fmt.Sprintf(" | Lifecycle: %s", instance.Lifecycle))
+		}
+		if instance.Tenancy != "" && instance.Tenancy != "default" {
+			sb.WriteString(fmt.Sprintf(" | Tenancy: %s", instance.Tenancy))
+		}
+		sb.WriteString("\n")
+
+		// Format the interruption notice of Spot instances being reclaimed
+		if instance.SpotInterrupted() {
+			sb.WriteString(fmt.Sprintf("   %s Spot interruption: %s\n",
+				common.Symbol("⚠️"), FormatSpotStatus(instance)))
+		}
+
+		// Format status checks and upcoming maintenance
+		if checks := FormatStatusChecks(instance); checks != "" {
+			sb.WriteString(fmt.Sprintf("   Status checks: %s\n", checks))
+		}
+		for _, event := range instance.ScheduledEvents {
+			sb.WriteString(fmt.Sprintf("   %s %s\n", common.Symbol("🔧"), FormatScheduledEvent(event)))
+		}
 
 		// Format IPs
 		sb.WriteString(fmt.Sprintf("   Private IP: %s", instance.PrivateIP))
@@ -83,11 +155,17 @@ func FormatInstances(instances []InstanceSummary) string {
 		sb.WriteString(fmt.Sprintf("   VPC: %s | Subnet: %s | AZ: %s\n",
 			instance.VpcID, instance.SubnetID, instance.AvailabilityZone))
 
-		// Format security groups
+		// Format security groups and their inbound rules, flagging those
+		// opening sensitive ports to the internet
 		if len(instance.SecurityGroups) > 0 {
 			sb.WriteString(fmt.Sprintf("   Security Groups: %s\n",
 				strings.Join(instance.SecurityGroups, ", ")))
 		}
+		for _, group := range instance.SecurityGroupDetails {
+			for _, rule := range group.Rules {
+				sb.WriteString("     " + formatSecurityGroupRule(group, rule) + "\n")
+			}
+		}
 
 		// Format important tags
 		importantTags := []string{"Environment", "Project", "Owner", "Role", "Application"}
@@ -108,6 +186,53 @@ func FormatInstances(instances []InstanceSummary) string {
 	return sb.String()
 }
 
+// formatSecurityGroupRule formats an inbound rule of a security group, e.g.
+// "⚠️ bastion-sg: tcp 22 from 0.0.0.0/0 (SSH (22) open to the internet)"
+func formatSecurityGroupRule(group SecurityGroup, rule SecurityGroupRule) string {
+	line := group.Name + ": " + rule.String()
+	if rule.Description != "" {
+		line += " - " + rule.Description
+	}
+	if risk := rule.DescribeRisk(); risk != "" {
+		return fmt.Sprintf("%s %s (%s)", common.Symbol("⚠️"), line, risk)
+	}
+	return line
+}
+
+// FormatStatusChecks formats the results of the system and instance status
+// checks, e.g. "🚨 system ok, instance impaired", or "" when none were reported
+func FormatStatusChecks(instance InstanceSummary) string {
+	if instance.SystemStatus == "" && instance.InstanceStatus == "" {
+		return ""
+	}
+	checks := fmt.Sprintf("system %s, instance %s", orUnknown(instance.SystemStatus), orUnknown(instance.InstanceStatus))
+	if instance.Impaired() {
+		return common.Symbol("🚨") + " " + checks
+	}
+	return checks
+}
+
+// FormatScheduledEvent formats a scheduled event, e.g.
+// "system-reboot from 2024-01-08 02:00 UTC: scheduled reboot"
+func FormatScheduledEvent(event ScheduledEvent) string {
+	description := event.Code
+	if !event.NotBefore.IsZero() {
+		description += " from " + event.NotBefore.UTC().Format("2006-01-02 15:04 MST")
+	}
+	if event.Description != "" {
+		description += ": " + event.Description
+	}
+	return description
+}
+
+// orUnknown returns s, or "unknown" when it is empty
+func orUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
+
 // formatUptime formats the uptime of an instance
 func formatUptime(launchTime time.Time) string {
 	duration := timeNow().Sub(launchTime)
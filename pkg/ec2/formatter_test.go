@@ -42,6 +42,24 @@ func TestFormatInstances(t *testing.T) {
 				"Launched: 2024-01-01 11:00:00",
 			},
 		},
+		{
+			name: "Status checks and scheduled events",
+			instances: []InstanceSummary{
+				{
+					Name:           "web",
+					InstanceID:     "i-3333",
+					SystemStatus:   "impaired",
+					InstanceStatus: "ok",
+					ScheduledEvents: []ScheduledEvent{
+						{Code: "instance-retirement", Description: "The instance is running on degraded hardware", NotBefore: time.Date(2024, 1, 8, 2, 0, 0, 0, time.UTC)},
+					},
+				},
+			},
+			contains: []string{
+				"Status checks: 🚨 system impaired, instance ok\n",
+				"🔧 instance-retirement from 2024-01-08 02:00 UTC: The instance is running on degraded hardware\n",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -70,7 +88,24 @@ func TestGetInstancesSummary(t *testing.T) {
 				{State: "stopped"},
 				{State: "pending"},
 			},
-			want: "4 total (2 running, 1 stopped, 1 other)",
+			want: "4 total (2 running, 1 stopped, 1 other), 4 on-demand, 0 spot",
+		},
+		{
+			name: "Impaired instance",
+			instances: []InstanceSummary{
+				{State: "running", SystemStatus: "ok", InstanceStatus: "impaired"},
+				{State: "running", SystemStatus: "ok", InstanceStatus: "ok"},
+			},
+			want: "2 total (2 running, 0 stopped, 0 other), 2 on-demand, 0 spot, 1 impaired",
+		},
+		{
+			name: "Spot instances",
+			instances: []InstanceSummary{
+				{State: "running", Lifecycle: "on-demand"},
+				{State: "running", Lifecycle: "spot", SpotStatus: "fulfilled"},
+				{State: "running", Lifecycle: "spot", SpotStatus: "marked-for-termination"},
+			},
+			want: "3 total (3 running, 0 stopped, 0 other), 1 on-demand, 2 spot, 1 spot interruptions",
 		},
 	}
 
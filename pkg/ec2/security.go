@@ -0,0 +1,240 @@
+package ec2
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// SensitivePorts are the ports of administration and database services that
+// should not be reachable from the whole internet, by the service on them
+var SensitivePorts = map[int32]string{
+	22:   "SSH",
+	3389: "RDP",
+	3306: "MySQL",
+	5432: "PostgreSQL",
+}
+
+// securityGroupBatch is how many group or Spot request IDs are filtered on
+// per call, the limit of values of a filter
+const securityGroupBatch = 200
+
+// SecurityGroup represents a security group and its inbound rules
+type SecurityGroup struct {
+	ID    string
+	Name  string
+	Rules []SecurityGroupRule
+}
+
+// SecurityGroupRule represents an inbound rule for a single source
+type SecurityGroupRule struct {
+	Protocol    string // e.g. "tcp", "udp", "icmp" or "-1" for all traffic
+	FromPort    int32  // -1 for all ports
+	ToPort      int32
+	Source      string // CIDR block, prefix list or security group ID
+	Description string
+}
+
+// RiskyRule is an inbound rule opening sensitive ports to the internet and
+// the instances it exposes
+type RiskyRule struct {
+	Group     SecurityGroup
+	Rule      SecurityGroupRule
+	Instances []string // Names, or IDs of unnamed instances
+}
+
+// OpenToWorld reports whether the rule admits traffic from any address
+func (r SecurityGroupRule) OpenToWorld() bool {
+	return r.Source == "0.0.0.0/0" || r.Source == "::/0"
+}
+
+// SensitivePorts returns the sensitive ports the rule opens, in order
+func (r SecurityGroupRule) SensitivePorts() []int32 {
+	if r.Protocol != "tcp" && r.Protocol != "-1" {
+		return nil
+	}
+
+	var ports []int32
+	for port := range SensitivePorts {
+		if r.FromPort == -1 || r.Protocol == "-1" || port >= r.FromPort && port <= r.ToPort {
+			ports = append(ports, port)
+		}
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+	return ports
+}
+
+// IsRisky reports whether the rule opens sensitive ports to the internet
+func (r SecurityGroupRule) IsRisky() bool {
+	return r.OpenToWorld() && len(r.SensitivePorts()) > 0
+}
+
+// String formats the rule, e.g. "tcp 22 from 0.0.0.0/0"
+func (r SecurityGroupRule) String() string {
+	traffic := r.Protocol
+	switch {
+	case r.Protocol == "-1":
+		traffic = "all traffic"
+	case r.FromPort == -1 || r.FromPort == 0 && r.ToPort == 65535:
+		traffic += " all ports"
+	case r.FromPort == r.ToPort:
+		traffic += fmt.Sprintf(" %d", r.FromPort)
+	default:
+		traffic += fmt.Sprintf(" %d-%d", r.FromPort, r.ToPort)
+	}
+	return traffic + " from " + r.Source
+}
+
+// DescribeRisk names the sensitive services the rule opens to the internet,
+// e.g. "SSH (22) open to the internet", or returns "" when it is not risky
+func (r SecurityGroupRule) DescribeRisk() string {
+	if !r.IsRisky() {
+		return ""
+	}
+
+	var services []string
+	for _, port := range r.SensitivePorts() {
+		services = append(services, fmt.Sprintf("%s (%d)", SensitivePorts[port], port))
+	}
+	return strings.Join(services, ", ") + " open to the internet"
+}
+
+// GetRiskyRules returns the rules opening sensitive ports to the internet
+// across the security groups of the instances, each once with the instances
+// it exposes
+func GetRiskyRules(instances []InstanceSummary) []RiskyRule {
+	var risky []RiskyRule
+	index := make(map[string]int)
+	for _, instance := range instances {
+		name := instance.Name
+		if name == "" {
+			name = instance.InstanceID
+		}
+		for _, group := range instance.SecurityGroupDetails {
+			for _, rule := range group.Rules {
+				if !rule.IsRisky() {
+					continue
+				}
+				key := group.ID + " " + rule.String()
+				i, ok := index[key]
+				if !ok {
+					i = len(risky)
+					index[key] = i
+					risky = append(risky, RiskyRule{Group: group, Rule: rule})
+				}
+				risky[i].Instances = append(risky[i].Instances, name)
+			}
+		}
+	}
+	return risky
+}
+
+// resolveSecurityGroups fills in the inbound rules of the security groups
+// of the instances
+func (c *Client) resolveSecurityGroups(ctx context.Context, instances []InstanceSummary) error {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, instance := range instances {
+		for _, id := range instance.SecurityGroupIDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	groups, err := c.getSecurityGroups(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range instances {
+		instances[i].SecurityGroupDetails = nil
+		for _, id := range instances[i].SecurityGroupIDs {
+			if group, ok := groups[id]; ok {
+				instances[i].SecurityGroupDetails = append(instances[i].SecurityGroupDetails, group)
+			}
+		}
+	}
+	return nil
+}
+
+// getSecurityGroups returns the security groups with the given IDs by ID,
+// following the pagination tokens
+func (c *Client) getSecurityGroups(ctx context.Context, ids []string) (map[string]SecurityGroup, error) {
+	groups := make(map[string]SecurityGroup)
+	for start := 0; start < len(ids); start += securityGroupBatch {
+		batch := ids[start:min(start+securityGroupBatch, len(ids))]
+
+		var nextToken *string
+		for {
+			resp, err := c.ec2Client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
+				Filters:   []types.Filter{{Name: aws.String("group-id"), Values: batch}},
+				NextToken: nextToken,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to describe security groups: %w", err)
+			}
+
+			for _, group := range resp.SecurityGroups {
+				summary := SecurityGroup{
+					ID:   aws.ToString(group.GroupId),
+					Name: aws.ToString(group.GroupName),
+				}
+				for _, permission := range group.IpPermissions {
+					summary.Rules = append(summary.Rules, newSecurityGroupRules(permission)...)
+				}
+				groups[summary.ID] = summary
+			}
+
+			nextToken = resp.NextToken
+			if nextToken == nil {
+				break
+			}
+		}
+	}
+	return groups, nil
+}
+
+// newSecurityGroupRules splits an inbound permission into a rule per source
+func newSecurityGroupRules(permission types.IpPermission) []SecurityGroupRule {
+	base := SecurityGroupRule{
+		Protocol: aws.ToString(permission.IpProtocol),
+		FromPort: -1,
+		ToPort:   -1,
+	}
+	if permission.FromPort != nil {
+		base.FromPort = *permission.FromPort
+	}
+	if permission.ToPort != nil {
+		base.ToPort = *permission.ToPort
+	}
+
+	var rules []SecurityGroupRule
+	add := func(source string, description *string) {
+		rule := base
+		rule.Source = source
+		rule.Description = aws.ToString(description)
+		rules = append(rules, rule)
+	}
+	for _, r := range permission.IpRanges {
+		add(aws.ToString(r.CidrIp), r.Description)
+	}
+	for _, r := range permission.Ipv6Ranges {
+		add(aws.ToString(r.CidrIpv6), r.Description)
+	}
+	for _, r := range permission.PrefixListIds {
+		add(aws.ToString(r.PrefixListId), r.Description)
+	}
+	for _, r := range permission.UserIdGroupPairs {
+		add(aws.ToString(r.GroupId), r.Description)
+	}
+	return rules
+}
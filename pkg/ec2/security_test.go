@@ -0,0 +1,120 @@
+package ec2
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func TestSecurityGroupRuleRisk(t *testing.T) {
+	tests := []struct {
+		rule  SecurityGroupRule
+		text  string
+		risky string
+	}{
+		{SecurityGroupRule{Protocol: "tcp", FromPort: 22, ToPort: 22, Source: "0.0.0.0/0"}, "tcp 22 from 0.0.0.0/0", "SSH (22) open to the internet"},
+		{SecurityGroupRule{Protocol: "tcp", FromPort: 22, ToPort: 22, Source: "10.0.0.0/16"}, "tcp 22 from 10.0.0.0/16", ""},
+		{SecurityGroupRule{Protocol: "tcp", FromPort: 443, ToPort: 443, Source: "0.0.0.0/0"}, "tcp 443 from 0.0.0.0/0", ""},
+		{SecurityGroupRule{Protocol: "tcp", FromPort: 3000, ToPort: 6000, Source: "::/0"}, "tcp 3000-6000 from ::/0", "MySQL (3306), RDP (3389), PostgreSQL (5432) open to the internet"},
+		{SecurityGroupRule{Protocol: "-1", FromPort: -1, ToPort: -1, Source: "0.0.0.0/0"}, "all traffic from 0.0.0.0/0", "SSH (22), MySQL (3306), RDP (3389), PostgreSQL (5432) open to the internet"},
+		{SecurityGroupRule{Protocol: "udp", FromPort: 0, ToPort: 65535, Source: "0.0.0.0/0"}, "udp all ports from 0.0.0.0/0", ""},
+		{SecurityGroupRule{Protocol: "-1", FromPort: -1, ToPort: -1, Source: "sg-123"}, "all traffic from sg-123", ""},
+	}
+
+	for _, tt := range tests {
+		if text := tt.rule.String(); text != tt.text {
+			t.Errorf("Expected %q, got %q", tt.text, text)
+		}
+		if risk := tt.rule.DescribeRisk(); risk != tt.risky {
+			t.Errorf("%s: expected risk %q, got %q", tt.text, tt.risky, risk)
+		}
+	}
+}
+
+func TestGetInstancesSecurityGroups(t *testing.T) {
+	var filtered [][]string
+	client := NewClient(&mockEC2API{
+		DescribeInstancesFunc: func(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+			return &ec2.DescribeInstancesOutput{Reservations: []types.Reservation{{Instances: []types.Instance{
+				{InstanceId: aws.String("i-1"), State: &types.InstanceState{Name: types.InstanceStateNameRunning}, Tags: []types.Tag{{Key: aws.String("Name"), Value: aws.String("bastion")}},
+					SecurityGroups: []types.GroupIdentifier{{GroupId: aws.String("sg-ssh"), GroupName: aws.String("ssh")}, {GroupId: aws.String("sg-web"), GroupName: aws.String("web")}}},
+				{InstanceId: aws.String("i-2"), State: &types.InstanceState{Name: types.InstanceStateNameRunning},
+					SecurityGroups: []types.GroupIdentifier{{GroupId: aws.String("sg-ssh"), GroupName: aws.String("ssh")}}},
+			}}}}, nil
+		},
+		DescribeSecurityGroupsFunc: func(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
+			filtered = append(filtered, params.Filters[0].Values)
+			if params.NextToken == nil {
+				return &ec2.DescribeSecurityGroupsOutput{
+					SecurityGroups: []types.SecurityGroup{{GroupId: aws.String("sg-ssh"), GroupName: aws.String("ssh"), IpPermissions: []types.IpPermission{{
+						IpProtocol: aws.String("tcp"), FromPort: aws.Int32(22), ToPort: aws.Int32(22),
+						IpRanges: []types.IpRange{{CidrIp: aws.String("0.0.0.0/0")}, {CidrIp: aws.String("10.0.0.0/8")}},
+					}}}},
+					NextToken: aws.String("page-2"),
+				}, nil
+			}
+			return &ec2.DescribeSecurityGroupsOutput{
+				SecurityGroups: []types.SecurityGroup{{GroupId: aws.String("sg-web"), GroupName: aws.String("web"), IpPermissions: []types.IpPermission{{
+					IpProtocol: aws.String("tcp"), FromPort: aws.Int32(443), ToPort: aws.Int32(443),
+					IpRanges: []types.IpRange{{CidrIp: aws.String("0.0.0.0/0")}},
+				}}}},
+			}, nil
+		},
+	})
+
+	instances, errs := client.GetInstances(context.Background())
+	if len(errs) > 0 {
+		t.Fatalf("GetInstances() errors = %v", errs)
+	}
+	if len(filtered) != 2 || strings.Join(filtered[0], ",") != "sg-ssh,sg-web" {
+		t.Errorf("Expected both pages of the distinct groups in use, got %v", filtered)
+	}
+	for _, instance := range instances {
+		if instance.InstanceID == "i-1" && (len(instance.SecurityGroupDetails) != 2 || len(instance.SecurityGroupDetails[0].Rules) != 2) {
+			t.Errorf("Expected the rules of both groups of i-1, got %+v", instance.SecurityGroupDetails)
+		}
+	}
+
+	// The rule shared by both instances is counted once
+	risky := GetRiskyRules(instances)
+	if len(risky) != 1 || risky[0].Group.ID != "sg-ssh" || len(risky[0].Instances) != 2 {
+		t.Errorf("Expected the SSH rule once for both instances, got %+v", risky)
+	}
+	if summary := GetInstancesSummary(instances); !strings.HasSuffix(summary, ", 1 risky security group rules") {
+		t.Errorf("Expected the summary to count the risky rule, got %q", summary)
+	}
+	output := FormatInstances(instances)
+	for _, expected := range []string{
+		"     ⚠️ ssh: tcp 22 from 0.0.0.0/0 (SSH (22) open to the internet)\n",
+		"     ssh: tcp 22 from 10.0.0.0/8\n",
+		"     web: tcp 443 from 0.0.0.0/0\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
+
+func TestGetInstancesSecurityGroupsError(t *testing.T) {
+	client := NewClient(&mockEC2API{
+		DescribeInstancesFunc: func(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+			return &ec2.DescribeInstancesOutput{Reservations: []types.Reservation{{Instances: []types.Instance{
+				{InstanceId: aws.String("i-1"), State: &types.InstanceState{Name: types.InstanceStateNameRunning},
+					SecurityGroups: []types.GroupIdentifier{{GroupId: aws.String("sg-ssh"), GroupName: aws.String("ssh")}}},
+			}}}}, nil
+		},
+		DescribeSecurityGroupsFunc: func(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
+			return nil, errors.New("access denied")
+		},
+	})
+
+	instances, errs := client.GetInstances(context.Background())
+	if len(instances) != 1 || len(errs) != 1 || !strings.Contains(errs[0].Error(), "security groups") {
+		t.Errorf("Expected the instance along with the security group error, got %v and %v", instances, errs)
+	}
+}
@@ -0,0 +1,83 @@
+package ec2
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// IsSpot reports whether the instance was launched as a Spot instance
+func (i InstanceSummary) IsSpot() bool {
+	return i.Lifecycle == string(types.InstanceLifecycleTypeSpot)
+}
+
+// SpotInterrupted reports whether EC2 gave notice that it is reclaiming the
+// Spot instance, which it does two minutes before terminating, stopping or
+// hibernating it. The notice is also in the instance metadata, which is
+// only reachable from the instance itself, so it is read from the status of
+// the Spot request instead.
+func (i InstanceSummary) SpotInterrupted() bool {
+	return strings.HasPrefix(i.SpotStatus, "marked-for-")
+}
+
+// resolveSpotRequests fills in the status of the Spot requests of the Spot
+// instances
+func (c *Client) resolveSpotRequests(ctx context.Context, instances []InstanceSummary) error {
+	var ids []string
+	for _, instance := range instances {
+		if instance.SpotRequestID != "" {
+			ids = append(ids, instance.SpotRequestID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	requests, err := c.getSpotRequests(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range instances {
+		if status, ok := requests[instances[i].SpotRequestID]; ok {
+			instances[i].SpotStatus = aws.ToString(status.Code)
+			instances[i].SpotStatusMessage = aws.ToString(status.Message)
+		}
+	}
+	return nil
+}
+
+// getSpotRequests returns the status of the Spot requests with the given
+// IDs by ID, following the pagination tokens
+func (c *Client) getSpotRequests(ctx context.Context, ids []string) (map[string]types.SpotInstanceStatus, error) {
+	statuses := make(map[string]types.SpotInstanceStatus)
+	for start := 0; start < len(ids); start += securityGroupBatch {
+		batch := ids[start:min(start+securityGroupBatch, len(ids))]
+
+		var nextToken *string
+		for {
+			resp, err := c.ec2Client.DescribeSpotInstanceRequests(ctx, &ec2.DescribeSpotInstanceRequestsInput{
+				Filters:   []types.Filter{{Name: aws.String("spot-instance-request-id"), Values: batch}},
+				NextToken: nextToken,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to describe Spot instance requests: %w", err)
+			}
+
+			for _, request := range resp.SpotInstanceRequests {
+				if request.Status != nil {
+					statuses[aws.ToString(request.SpotInstanceRequestId)] = *request.Status
+				}
+			}
+
+			nextToken = resp.NextToken
+			if nextToken == nil {
+				break
+			}
+		}
+	}
+	return statuses, nil
+}
@@ -0,0 +1,99 @@
+package ec2
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func TestGetInstancesSpot(t *testing.T) {
+	var filtered [][]string
+	client := NewClient(&mockEC2API{
+		DescribeInstancesFunc: func(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+			return &ec2.DescribeInstancesOutput{Reservations: []types.Reservation{{Instances: []types.Instance{
+				{InstanceId: aws.String("i-1"), State: &types.InstanceState{Name: types.InstanceStateNameRunning}, Tags: []types.Tag{{Key: aws.String("Name"), Value: aws.String("worker-1")}},
+					InstanceLifecycle: types.InstanceLifecycleTypeSpot, SpotInstanceRequestId: aws.String("sir-1")},
+				{InstanceId: aws.String("i-2"), State: &types.InstanceState{Name: types.InstanceStateNameRunning}, Tags: []types.Tag{{Key: aws.String("Name"), Value: aws.String("worker-2")}},
+					InstanceLifecycle: types.InstanceLifecycleTypeSpot, SpotInstanceRequestId: aws.String("sir-2")},
+				{InstanceId: aws.String("i-3"), State: &types.InstanceState{Name: types.InstanceStateNameRunning}, Tags: []types.Tag{{Key: aws.String("Name"), Value: aws.String("licensed")}},
+					Placement: &types.Placement{Tenancy: types.TenancyDedicated}},
+			}}}}, nil
+		},
+		DescribeSpotInstanceRequestsFunc: func(ctx context.Context, params *ec2.DescribeSpotInstanceRequestsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSpotInstanceRequestsOutput, error) {
+			filtered = append(filtered, params.Filters[0].Values)
+			if params.NextToken == nil {
+				return &ec2.DescribeSpotInstanceRequestsOutput{
+					SpotInstanceRequests: []types.SpotInstanceRequest{{SpotInstanceRequestId: aws.String("sir-1"),
+						Status: &types.SpotInstanceStatus{Code: aws.String("fulfilled"), Message: aws.String("Your spot request is fulfilled.")}}},
+					NextToken: aws.String("page-2"),
+				}, nil
+			}
+			return &ec2.DescribeSpotInstanceRequestsOutput{
+				SpotInstanceRequests: []types.SpotInstanceRequest{{SpotInstanceRequestId: aws.String("sir-2"),
+					Status: &types.SpotInstanceStatus{Code: aws.String("marked-for-termination"), Message: aws.String("Spot Instance is marked for termination.")}}},
+			}, nil
+		},
+	})
+
+	instances, errs := client.GetInstances(context.Background())
+	if len(errs) > 0 {
+		t.Fatalf("GetInstances() errors = %v", errs)
+	}
+	if len(filtered) != 2 || len(filtered[0]) != 2 {
+		t.Errorf("Expected both pages of the Spot requests of both Spot instances, got %v", filtered)
+	}
+
+	byID := make(map[string]InstanceSummary)
+	for _, instance := range instances {
+		byID[instance.InstanceID] = instance
+	}
+	if worker := byID["i-1"]; !worker.IsSpot() || worker.SpotStatus != "fulfilled" || worker.SpotInterrupted() || worker.Tenancy != "default" {
+		t.Errorf("Expected a fulfilled Spot instance, got %+v", worker)
+	}
+	if worker := byID["i-2"]; !worker.SpotInterrupted() {
+		t.Errorf("Expected an interrupted Spot instance, got %+v", worker)
+	}
+	if licensed := byID["i-3"]; licensed.Lifecycle != "on-demand" || licensed.Tenancy != "dedicated" || licensed.SpotRequestID != "" {
+		t.Errorf("Expected a dedicated On-Demand instance, got %+v", licensed)
+	}
+
+	if summary := GetInstancesSummary(instances); !strings.Contains(summary, ", 1 on-demand, 2 spot, 1 spot interruptions") {
+		t.Errorf("Expected the summary to count the Spot instances, got %q", summary)
+	}
+	output := FormatInstances(instances)
+	for _, expected := range []string{
+		"   Type:  | State: 🟢 running | Lifecycle: spot\n   ⚠️ Spot interruption: marked-for-termination: Spot Instance is marked for termination.\n",
+		"   Type:  | State: 🟢 running | Lifecycle: on-demand | Tenancy: dedicated\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
+
+func TestGetInstancesSpotError(t *testing.T) {
+	client := NewClient(&mockEC2API{
+		DescribeInstancesFunc: func(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+			return &ec2.DescribeInstancesOutput{Reservations: []types.Reservation{{Instances: []types.Instance{
+				{InstanceId: aws.String("i-1"), State: &types.InstanceState{Name: types.InstanceStateNameRunning},
+					InstanceLifecycle: types.InstanceLifecycleTypeSpot, SpotInstanceRequestId: aws.String("sir-1")},
+			}}}}, nil
+		},
+		DescribeSpotInstanceRequestsFunc: func(ctx context.Context, params *ec2.DescribeSpotInstanceRequestsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSpotInstanceRequestsOutput, error) {
+			return nil, errors.New("access denied")
+		},
+	})
+
+	instances, errs := client.GetInstances(context.Background())
+	if len(instances) != 1 || len(errs) != 1 || !strings.Contains(errs[0].Error(), "Spot instance requests") {
+		t.Errorf("Expected the instance along with the Spot request error, got %v and %v", instances, errs)
+	}
+	if !instances[0].IsSpot() || instances[0].SpotStatus != "" {
+		t.Errorf("Expected a Spot instance without its request status, got %+v", instances[0])
+	}
+}
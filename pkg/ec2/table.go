@@ -0,0 +1,90 @@
+package ec2
+
+import (
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// Columns are the columns of the EC2 instances table, sorted by name by default
+var Columns = []common.Column[InstanceSummary]{
+	{Title: "Name", Width: 24, Value: instanceName, Less: func(a, b InstanceSummary) bool {
+		if instanceName(a) != instanceName(b) {
+			return instanceName(a) < instanceName(b)
+		}
+		return a.InstanceID < b.InstanceID
+	}},
+	{Title: "Instance ID", Width: 20, Value: func(i InstanceSummary) string { return i.InstanceID }},
+	{Title: "Type", Width: 12, Value: func(i InstanceSummary) string { return i.InstanceType }},
+	{Title: "State", Width: 10, Value: func(i InstanceSummary) string { return i.State }},
+	// Impaired first, then instances with scheduled maintenance
+	{Title: "Checks", Width: 12, Value: statusBadge, Less: func(a, b InstanceSummary) bool {
+		return statusRank(a) > statusRank(b)
+	}},
+	// Spot instances being reclaimed first
+	{Title: "Lifecycle", Width: 10, Value: lifecycleBadge, Less: func(a, b InstanceSummary) bool {
+		return lifecycleRank(a) > lifecycleRank(b)
+	}},
+	{Title: "AZ", Width: 11, Value: func(i InstanceSummary) string { return i.AvailabilityZone }},
+	{Title: "Private IP", Width: 15, Value: func(i InstanceSummary) string { return i.PrivateIP }},
+	{Title: "Public IP", Width: 15, Value: func(i InstanceSummary) string { return i.PublicIP }},
+	// Longest running first
+	{Title: "Uptime", Width: 8, Value: func(i InstanceSummary) string { return formatUptime(i.LaunchTime) }, Less: func(a, b InstanceSummary) bool {
+		return a.LaunchTime.Before(b.LaunchTime)
+	}},
+}
+
+// statusBadge summarizes the status checks and scheduled events of an instance
+func statusBadge(instance InstanceSummary) string {
+	switch {
+	case instance.Impaired():
+		return common.Symbol("🚨") + " impaired"
+	case len(instance.ScheduledEvents) > 0:
+		return common.Symbol("🔧") + " event"
+	case instance.SystemStatus == "" && instance.InstanceStatus == "":
+		return ""
+	case instance.SystemStatus == "ok" && instance.InstanceStatus == "ok":
+		return "ok"
+	case instance.InstanceStatus != "ok":
+		return instance.InstanceStatus
+	}
+	return instance.SystemStatus
+}
+
+// statusRank orders instances by how urgently their status needs attention
+func statusRank(instance InstanceSummary) int {
+	switch {
+	case instance.Impaired():
+		return 2
+	case len(instance.ScheduledEvents) > 0:
+		return 1
+	}
+	return 0
+}
+
+// lifecycleBadge shows whether an instance is a Spot instance and whether it
+// is being reclaimed
+func lifecycleBadge(instance InstanceSummary) string {
+	if instance.SpotInterrupted() {
+		return common.Symbol("⚠️") + " spot"
+	}
+	return instance.Lifecycle
+}
+
+// lifecycleRank orders interrupted Spot instances before other Spot
+// instances, and those before On-Demand instances
+func lifecycleRank(instance InstanceSummary) int {
+	switch {
+	case instance.SpotInterrupted():
+		return 2
+	case instance.IsSpot():
+		return 1
+	}
+	return 0
+}
+
+// instanceName returns the Name tag of an instance, or <unnamed>
+func instanceName(instance InstanceSummary) string {
+	if instance.Name == "" {
+		return "<unnamed>"
+	}
+	return instance.Name
+}
@@ -0,0 +1,45 @@
+package ec2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+func TestColumns(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	instances := []InstanceSummary{
+		{Name: "web", InstanceID: "i-2", LaunchTime: now.Add(-time.Hour)},
+		{InstanceID: "i-3", LaunchTime: now.Add(-3 * 24 * time.Hour)},
+		{Name: "web", InstanceID: "i-1", LaunchTime: now.Add(-10 * time.Minute)},
+	}
+
+	byName := common.SortRows(instances, Columns, 0)
+	if byName[0].InstanceID != "i-3" || byName[1].InstanceID != "i-1" || byName[2].InstanceID != "i-2" {
+		t.Errorf("Expected unnamed first, then web by ID, got %v", byName)
+	}
+
+	byUptime := common.SortRows(instances, Columns, len(Columns)-1)
+	if byUptime[0].InstanceID != "i-3" || byUptime[2].InstanceID != "i-1" {
+		t.Errorf("Expected the longest running instance first, got %v", byUptime)
+	}
+
+	instances[0].InstanceStatus = "impaired"
+	instances[2].ScheduledEvents = []ScheduledEvent{{Code: "system-reboot"}}
+	byChecks := common.SortRows(instances, Columns, 4)
+	if byChecks[0].InstanceID != "i-2" || byChecks[1].InstanceID != "i-1" {
+		t.Errorf("Expected the impaired instance first, then the one with an event, got %v", byChecks)
+	}
+	if badge := statusBadge(byChecks[0]); badge != "🚨 impaired" {
+		t.Errorf("Unexpected badge %q", badge)
+	}
+
+	cells := common.TableCells(byUptime[:1], Columns)
+	if cells[0][0] != "<unnamed>" || cells[0][len(Columns)-1] != "3d 0h" {
+		t.Errorf("Unexpected cells %v", cells[0])
+	}
+}
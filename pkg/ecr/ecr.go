@@ -0,0 +1,220 @@
+package ecr
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/aws/aws-sdk-go-v2/service/ecr/types"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// ecrClientAPI defines the interface for the ECR client
+type ecrClientAPI interface {
+	DescribeRepositories(ctx context.Context, params *ecr.DescribeRepositoriesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeRepositoriesOutput, error)
+	DescribeImages(ctx context.Context, params *ecr.DescribeImagesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeImagesOutput, error)
+}
+
+// Client represents an ECR client
+type Client struct {
+	ecrClient ecrClientAPI
+	pool      *common.Pool
+}
+
+// RepositorySummary represents an ECR repository and its most recently
+// pushed image, which is usually the one being deployed
+type RepositorySummary struct {
+	Name       string
+	URI        string
+	ScanOnPush bool
+	ImageCount int
+
+	// Latest image; zero when the repository is empty
+	LatestPushedAt time.Time
+	LatestTags     []string
+	LatestDigest   string
+	LatestSize     int64            // Bytes
+	ScanStatus     string           // e.g. "COMPLETE" or "IN_PROGRESS", empty when the image was never scanned
+	Findings       map[string]int32 // Number of findings by severity, e.g. "CRITICAL"
+}
+
+// Critical returns the number of critical findings of the latest image
+func (r RepositorySummary) Critical() int32 {
+	return r.Findings[string(types.FindingSeverityCritical)]
+}
+
+// High returns the number of high severity findings of the latest image
+func (r RepositorySummary) High() int32 {
+	return r.Findings[string(types.FindingSeverityHigh)]
+}
+
+// Vulnerable reports whether the latest image has critical or high findings
+func (r RepositorySummary) Vulnerable() bool {
+	return r.Critical() > 0 || r.High() > 0
+}
+
+// NewClient returns a new ECR client whose calls run in pool, which may be nil
+func NewClient(ecrClient ecrClientAPI, pool *common.Pool) *Client {
+	return &Client{
+		ecrClient: ecrClient,
+		pool:      pool,
+	}
+}
+
+// GetRepositories returns all repositories sorted by name with their images
+// counted and the scan findings of their latest image. Repositories whose
+// images fail to load are still returned, with their errors alongside.
+func (c *Client) GetRepositories(ctx context.Context) ([]RepositorySummary, []error) {
+	summaries, _, errs := c.GetFirstRepositories(ctx, 0)
+	return summaries, errs
+}
+
+// GetFirstRepositories is GetRepositories limited to the first limit
+// repositories by name, or all of them when limit is 0, which also returns
+// how many there are in total. Only the images of the repositories within
+// the limit are described.
+func (c *Client) GetFirstRepositories(ctx context.Context, limit int) ([]RepositorySummary, int, []error) {
+	repositories, err := c.describeRepositories(ctx)
+	if err != nil {
+		return nil, 0, []error{err}
+	}
+
+	total := len(repositories)
+	if limit > 0 && total > limit {
+		sort.Slice(repositories, func(i, j int) bool {
+			return aws.ToString(repositories[i].RepositoryName) < aws.ToString(repositories[j].RepositoryName)
+		})
+		repositories = repositories[:limit]
+	}
+
+	summaries := make([]RepositorySummary, len(repositories))
+	for i, repository := range repositories {
+		summaries[i] = newRepositorySummary(repository)
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []error
+
+	for i := range summaries {
+		wg.Add(1)
+		go func(summary *RepositorySummary) {
+			defer wg.Done()
+			images, err := c.describeImages(ctx, summary.Name)
+			if err != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				errs = append(errs, fmt.Errorf("repository %s: %w", summary.Name, err))
+				return
+			}
+			addImages(summary, images)
+		}(&summaries[i])
+	}
+	wg.Wait()
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Name < summaries[j].Name
+	})
+
+	return summaries, total, errs
+}
+
+// describeRepositories returns all repositories, following the pagination tokens
+func (c *Client) describeRepositories(ctx context.Context) ([]types.Repository, error) {
+	var repositories []types.Repository
+	var nextToken *string
+
+	for {
+		var result *ecr.DescribeRepositoriesOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.ecrClient.DescribeRepositories(ctx, &ecr.DescribeRepositoriesInput{
+				NextToken: nextToken,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe repositories: %w", err)
+		}
+
+		repositories = append(repositories, result.Repositories...)
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return repositories, nil
+}
+
+// describeImages returns all images of a repository, following the pagination tokens
+func (c *Client) describeImages(ctx context.Context, repositoryName string) ([]types.ImageDetail, error) {
+	var images []types.ImageDetail
+	var nextToken *string
+
+	for {
+		var result *ecr.DescribeImagesOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.ecrClient.DescribeImages(ctx, &ecr.DescribeImagesInput{
+				RepositoryName: aws.String(repositoryName),
+				NextToken:      nextToken,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe images: %w", err)
+		}
+
+		images = append(images, result.ImageDetails...)
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return images, nil
+}
+
+// newRepositorySummary returns the summary of a repository without its images
+func newRepositorySummary(repository types.Repository) RepositorySummary {
+	summary := RepositorySummary{
+		Name: aws.ToString(repository.RepositoryName),
+		URI:  aws.ToString(repository.RepositoryUri),
+	}
+	if repository.ImageScanningConfiguration != nil {
+		summary.ScanOnPush = repository.ImageScanningConfiguration.ScanOnPush
+	}
+	return summary
+}
+
+// addImages counts the images of a repository and records its latest image
+func addImages(summary *RepositorySummary, images []types.ImageDetail) {
+	summary.ImageCount = len(images)
+
+	var latest *types.ImageDetail
+	for i, image := range images {
+		if latest == nil || aws.ToTime(image.ImagePushedAt).After(aws.ToTime(latest.ImagePushedAt)) {
+			latest = &images[i]
+		}
+	}
+	if latest == nil {
+		return
+	}
+
+	summary.LatestPushedAt = aws.ToTime(latest.ImagePushedAt)
+	summary.LatestTags = latest.ImageTags
+	summary.LatestDigest = aws.ToString(latest.ImageDigest)
+	summary.LatestSize = aws.ToInt64(latest.ImageSizeInBytes)
+	if latest.ImageScanStatus != nil {
+		summary.ScanStatus = string(latest.ImageScanStatus.Status)
+	}
+	if latest.ImageScanFindingsSummary != nil {
+		summary.Findings = latest.ImageScanFindingsSummary.FindingSeverityCounts
+	}
+}
@@ -0,0 +1,144 @@
+package ecr
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/aws/aws-sdk-go-v2/service/ecr/types"
+)
+
+// Mock ECR client
+type mockECRClient struct {
+	describeRepositoriesFunc func(ctx context.Context, params *ecr.DescribeRepositoriesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeRepositoriesOutput, error)
+	describeImagesFunc       func(ctx context.Context, params *ecr.DescribeImagesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeImagesOutput, error)
+}
+
+func (m *mockECRClient) DescribeRepositories(ctx context.Context, params *ecr.DescribeRepositoriesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeRepositoriesOutput, error) {
+	return m.describeRepositoriesFunc(ctx, params, optFns...)
+}
+
+func (m *mockECRClient) DescribeImages(ctx context.Context, params *ecr.DescribeImagesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeImagesOutput, error) {
+	return m.describeImagesFunc(ctx, params, optFns...)
+}
+
+func TestGetRepositories(t *testing.T) {
+	now := time.Now()
+	client := NewClient(&mockECRClient{
+		describeRepositoriesFunc: func(ctx context.Context, params *ecr.DescribeRepositoriesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeRepositoriesOutput, error) {
+			return &ecr.DescribeRepositoriesOutput{
+				Repositories: []types.Repository{
+					{RepositoryName: aws.String("web"), ImageScanningConfiguration: &types.ImageScanningConfiguration{ScanOnPush: true}},
+					{RepositoryName: aws.String("api")},
+					{RepositoryName: aws.String("broken")},
+				},
+			}, nil
+		},
+		describeImagesFunc: func(ctx context.Context, params *ecr.DescribeImagesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeImagesOutput, error) {
+			switch aws.ToString(params.RepositoryName) {
+			case "web":
+				// Return the images over two pages
+				if params.NextToken == nil {
+					return &ecr.DescribeImagesOutput{
+						ImageDetails: []types.ImageDetail{
+							{ImageDigest: aws.String("sha256:old"), ImageTags: []string{"v1"}, ImagePushedAt: aws.Time(now.Add(-48 * time.Hour))},
+						},
+						NextToken: aws.String("page-2"),
+					}, nil
+				}
+				return &ecr.DescribeImagesOutput{
+					ImageDetails: []types.ImageDetail{
+						{
+							ImageDigest:     aws.String("sha256:new"),
+							ImageTags:       []string{"v2", "latest"},
+							ImagePushedAt:   aws.Time(now.Add(-time.Hour)),
+							ImageScanStatus: &types.ImageScanStatus{Status: types.ScanStatusComplete},
+							ImageScanFindingsSummary: &types.ImageScanFindingsSummary{
+								FindingSeverityCounts: map[string]int32{"CRITICAL": 1, "HIGH": 3, "LOW": 7},
+							},
+						},
+					},
+				}, nil
+			case "broken":
+				return nil, errors.New("access denied")
+			}
+			return &ecr.DescribeImagesOutput{}, nil
+		},
+	}, nil)
+
+	repositories, errs := client.GetRepositories(context.Background())
+
+	if len(errs) != 1 {
+		t.Errorf("Expected the error of the broken repository, got %v", errs)
+	}
+	if len(repositories) != 3 {
+		t.Fatalf("Expected 3 repositories, got %d", len(repositories))
+	}
+	if repositories[0].Name != "api" || repositories[0].ImageCount != 0 {
+		t.Errorf("Expected the empty 'api' repository first, got %+v", repositories[0])
+	}
+
+	web := repositories[2]
+	if web.Name != "web" || web.ImageCount != 2 || !web.ScanOnPush {
+		t.Errorf("Unexpected repository %+v", web)
+	}
+	if web.LatestDigest != "sha256:new" || web.LatestTags[0] != "v2" || web.ScanStatus != "COMPLETE" {
+		t.Errorf("Expected the latest image to be v2, got %+v", web)
+	}
+	if web.Critical() != 1 || web.High() != 3 || !web.Vulnerable() {
+		t.Errorf("Expected 1 critical and 3 high findings, got %v", web.Findings)
+	}
+}
+
+func TestGetRepositoriesError(t *testing.T) {
+	client := NewClient(&mockECRClient{
+		describeRepositoriesFunc: func(ctx context.Context, params *ecr.DescribeRepositoriesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeRepositoriesOutput, error) {
+			return nil, errors.New("access denied")
+		},
+	}, nil)
+
+	repositories, errs := client.GetRepositories(context.Background())
+	if repositories != nil || len(errs) != 1 {
+		t.Errorf("Expected only an error, got %v, %v", repositories, errs)
+	}
+}
+
+func TestGetFirstRepositories(t *testing.T) {
+	var mu sync.Mutex
+	var described []string
+	client := NewClient(&mockECRClient{
+		describeRepositoriesFunc: func(ctx context.Context, params *ecr.DescribeRepositoriesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeRepositoriesOutput, error) {
+			return &ecr.DescribeRepositoriesOutput{
+				Repositories: []types.Repository{
+					{RepositoryName: aws.String("worker")},
+					{RepositoryName: aws.String("api")},
+					{RepositoryName: aws.String("web")},
+				},
+			}, nil
+		},
+		describeImagesFunc: func(ctx context.Context, params *ecr.DescribeImagesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeImagesOutput, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			described = append(described, aws.ToString(params.RepositoryName))
+			return &ecr.DescribeImagesOutput{}, nil
+		},
+	}, nil)
+
+	repositories, total, errs := client.GetFirstRepositories(context.Background(), 2)
+	if len(errs) != 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+	if total != 3 || len(repositories) != 2 {
+		t.Fatalf("Expected 2 of 3 repositories, got %d of %d", len(repositories), total)
+	}
+	if repositories[0].Name != "api" || repositories[1].Name != "web" {
+		t.Errorf("Expected the first repositories by name, got %v", repositories)
+	}
+	if len(described) != 2 {
+		t.Errorf("Expected the images of 2 repositories to be described, got %v", described)
+	}
+}
@@ -0,0 +1,141 @@
+package ecr
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// timeNow is the clock the age of the latest images is measured against
+var timeNow = time.Now
+
+// severities are the finding severities shown, most severe first
+var severities = []string{"CRITICAL", "HIGH", "MEDIUM", "LOW", "INFORMATIONAL", "UNDEFINED"}
+
+// FormatRepositories formats the repositories for terminal display, listing
+// those whose latest image has critical or high findings first
+func FormatRepositories(summaries []RepositorySummary) string {
+	if len(summaries) == 0 {
+		return "No ECR repositories found"
+	}
+
+	var output strings.Builder
+	output.WriteString("ECR REPOSITORIES\n")
+	output.WriteString(common.Rule("ECR REPOSITORIES", "=") + "\n\n")
+
+	if vulnerable := GetVulnerableRepositories(summaries); len(vulnerable) > 0 {
+		output.WriteString(fmt.Sprintf("%s VULNERABLE IMAGES (%d repositories whose latest image has critical or high findings)\n",
+			common.Symbol("🚨"), len(vulnerable)))
+		for _, repository := range vulnerable {
+			output.WriteString(fmt.Sprintf("  %s:%s: %d critical, %d high\n",
+				repository.Name, latestTag(repository), repository.Critical(), repository.High()))
+		}
+		output.WriteString("\n")
+	}
+
+	for _, repository := range summaries {
+		output.WriteString(fmt.Sprintf("%s %s (%d images)\n",
+			common.Symbol(getStatusSymbol(repository)), repository.Name, repository.ImageCount))
+
+		if repository.ImageCount == 0 {
+			output.WriteString("  No images\n\n")
+			continue
+		}
+
+		latest := fmt.Sprintf("  Latest: %s, pushed %s ago", latestTag(repository), formatAge(repository.LatestPushedAt))
+		if repository.LatestSize > 0 {
+			latest += fmt.Sprintf(", %s MiB", common.FormatFloatWithPrecision(float64(repository.LatestSize)/(1024*1024), 1))
+		}
+		output.WriteString(latest + "\n")
+		output.WriteString(fmt.Sprintf("  Scan: %s\n", formatScan(repository)))
+		output.WriteString("\n")
+	}
+
+	return output.String()
+}
+
+// GetRepositoriesSummary returns a brief summary of the repositories
+func GetRepositoriesSummary(summaries []RepositorySummary) string {
+	images := 0
+	for _, repository := range summaries {
+		images += repository.ImageCount
+	}
+
+	summary := fmt.Sprintf("%d repositories, %d images", len(summaries), images)
+	if vulnerable := len(GetVulnerableRepositories(summaries)); vulnerable > 0 {
+		summary += fmt.Sprintf(", %d with critical or high findings", vulnerable)
+	}
+	return summary
+}
+
+// GetVulnerableRepositories returns the repositories whose latest image has
+// critical or high findings
+func GetVulnerableRepositories(summaries []RepositorySummary) []RepositorySummary {
+	var vulnerable []RepositorySummary
+	for _, repository := range summaries {
+		if repository.Vulnerable() {
+			vulnerable = append(vulnerable, repository)
+		}
+	}
+	return vulnerable
+}
+
+// latestTag returns the first tag of the latest image, or its shortened
+// digest when it is untagged
+func latestTag(repository RepositorySummary) string {
+	if len(repository.LatestTags) > 0 {
+		return repository.LatestTags[0]
+	}
+	digest := strings.TrimPrefix(repository.LatestDigest, "sha256:")
+	if len(digest) > 12 {
+		digest = digest[:12]
+	}
+	return "<untagged " + digest + ">"
+}
+
+// formatScan describes the scan status and findings of the latest image
+func formatScan(repository RepositorySummary) string {
+	if repository.ScanStatus == "" {
+		if repository.ScanOnPush {
+			return "not scanned"
+		}
+		return "not scanned, scan on push is disabled"
+	}
+
+	var counts []string
+	for _, severity := range severities {
+		if count := repository.Findings[severity]; count > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", count, strings.ToLower(severity)))
+		}
+	}
+	if len(counts) == 0 {
+		return repository.ScanStatus + ", no findings"
+	}
+	return repository.ScanStatus + ", " + strings.Join(counts, ", ")
+}
+
+// formatAge returns how long ago t was, in days once it is over a day
+func formatAge(t time.Time) string {
+	age := timeNow().Sub(t)
+	if age >= 24*time.Hour {
+		return fmt.Sprintf("%dd", int(age.Hours()/24))
+	}
+	return age.Round(time.Minute).String()
+}
+
+// getStatusSymbol returns the emoji of the findings of a repository's latest image
+func getStatusSymbol(repository RepositorySummary) string {
+	switch {
+	case repository.ImageCount == 0:
+		return "⚪"
+	case repository.Critical() > 0:
+		return "🚨"
+	case repository.High() > 0:
+		return "⚠️"
+	case repository.ScanStatus == "":
+		return "❓"
+	}
+	return "✅"
+}
@@ -0,0 +1,44 @@
+package ecr
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatRepositories(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	oldTimeNow := timeNow
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = oldTimeNow }()
+
+	summaries := []RepositorySummary{
+		{Name: "api", ImageCount: 4, LatestTags: []string{"v7"}, LatestPushedAt: now.Add(-3 * time.Hour), LatestSize: 150 * 1024 * 1024,
+			ScanStatus: "COMPLETE", Findings: map[string]int32{"CRITICAL": 2, "HIGH": 5, "MEDIUM": 1}},
+		{Name: "cron", ImageCount: 1, LatestDigest: "sha256:0123456789abcdef", LatestPushedAt: now.Add(-10 * 24 * time.Hour)},
+		{Name: "empty"},
+		{Name: "web", ImageCount: 12, LatestTags: []string{"v1.4.2"}, LatestPushedAt: now.Add(-30 * time.Minute), ScanOnPush: true, ScanStatus: "COMPLETE"},
+	}
+
+	output := FormatRepositories(summaries)
+
+	for _, expected := range []string{
+		"ECR REPOSITORIES",
+		"VULNERABLE IMAGES (1 repositories whose latest image has critical or high findings)\n  api:v7: 2 critical, 5 high\n",
+		"🚨 api (4 images)\n  Latest: v7, pushed 3h0m0s ago, 150.0 MiB\n  Scan: COMPLETE, 2 critical, 5 high, 1 medium\n",
+		"❓ cron (1 images)\n  Latest: <untagged 0123456789ab>, pushed 10d ago\n  Scan: not scanned, scan on push is disabled\n",
+		"⚪ empty (0 images)\n  No images\n",
+		"✅ web (12 images)\n  Latest: v1.4.2, pushed 30m0s ago\n  Scan: COMPLETE, no findings\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain '%s', got:\n%s", expected, output)
+		}
+	}
+
+	if output := FormatRepositories(nil); output != "No ECR repositories found" {
+		t.Errorf("Expected an empty message, got '%s'", output)
+	}
+	if summary := GetRepositoriesSummary(summaries); summary != "4 repositories, 17 images, 1 with critical or high findings" {
+		t.Errorf("Unexpected summary '%s'", summary)
+	}
+}
@@ -3,6 +3,7 @@ package ecs
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"strings"
 	"sync"
 	"time"
@@ -18,6 +19,12 @@ type ECSAPI interface {
 	DescribeClusters(ctx context.Context, params *ecs.DescribeClustersInput, optFns ...func(*ecs.Options)) (*ecs.DescribeClustersOutput, error)
 	ListServices(ctx context.Context, params *ecs.ListServicesInput, optFns ...func(*ecs.Options)) (*ecs.ListServicesOutput, error)
 	DescribeServices(ctx context.Context, params *ecs.DescribeServicesInput, optFns ...func(*ecs.Options)) (*ecs.DescribeServicesOutput, error)
+	DescribeTaskDefinition(ctx context.Context, params *ecs.DescribeTaskDefinitionInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTaskDefinitionOutput, error)
+	RunTask(ctx context.Context, params *ecs.RunTaskInput, optFns ...func(*ecs.Options)) (*ecs.RunTaskOutput, error)
+	DescribeTasks(ctx context.Context, params *ecs.DescribeTasksInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTasksOutput, error)
+	ListTasks(ctx context.Context, params *ecs.ListTasksInput, optFns ...func(*ecs.Options)) (*ecs.ListTasksOutput, error)
+	UpdateService(ctx context.Context, params *ecs.UpdateServiceInput, optFns ...func(*ecs.Options)) (*ecs.UpdateServiceOutput, error)
+	ListTagsForResource(ctx context.Context, params *ecs.ListTagsForResourceInput, optFns ...func(*ecs.Options)) (*ecs.ListTagsForResourceOutput, error)
 }
 
 // Client is the ECS client
@@ -35,6 +42,7 @@ func NewClient(ecsClient ECSAPI) *Client {
 // ServiceSummary represents an ECS service summary
 type ServiceSummary struct {
 	ServiceName        string
+	ARN                string
 	ClusterName        string
 	Status             string
 	DesiredCount       int32
@@ -49,6 +57,19 @@ type ServiceSummary struct {
 	HealthStatus       string
 	DeploymentStatus   string
 	NetworkMode        string
+	TaskDefinitionARN  string   // Full ARN, used to run one-off tasks
+	Subnets            []string // awsvpc subnets of the service's tasks
+	SecurityGroups     []string // awsvpc security groups of the service's tasks
+	AssignPublicIP     bool
+
+	// Container Insights metrics over the past hour, only loaded by an
+	// InsightsClient: the CPU and memory the tasks used as a percentage of
+	// what they reserve, and the latest reservations
+	CPUUtilizationData    []float64
+	MemoryUtilizationData []float64
+	CPUReserved           float64   // CPU units
+	MemoryReserved        float64   // MiB
+	MetricsEnd            time.Time // End of the metrics' window, zero when they were not loaded
 }
 
 // ClusterInfo represents basic cluster information
@@ -60,6 +81,14 @@ type ClusterInfo struct {
 
 // GetServices returns a list of ECS services from all clusters
 func (c *Client) GetServices(ctx context.Context) ([]ServiceSummary, error) {
+	return c.StreamServices(ctx, nil)
+}
+
+// StreamServices is GetServices calling loaded with the services loaded so
+// far each time the services of a cluster are described, so that callers can
+// show the first clusters without waiting for the slowest. The calls are not
+// concurrent and each gets its own slice. loaded may be nil.
+func (c *Client) StreamServices(ctx context.Context, loaded func([]ServiceSummary)) ([]ServiceSummary, error) {
 	// Step 1: List all clusters
 	clusters, err := c.getClusters(ctx)
 	if err != nil {
@@ -68,7 +97,8 @@ func (c *Client) GetServices(ctx context.Context) ([]ServiceSummary, error) {
 
 	// Step 2: Process clusters in parallel using goroutines
 	var wg sync.WaitGroup
-	servicesCh := make(chan []ServiceSummary, len(clusters))
+	var mu sync.Mutex
+	var services []ServiceSummary
 	errorsCh := make(chan error, len(clusters))
 
 	for _, cluster := range clusters {
@@ -79,27 +109,24 @@ func (c *Client) GetServices(ctx context.Context) ([]ServiceSummary, error) {
 			clusterServices, err := c.getClusterServices(ctx, clusterName)
 			if err != nil {
 				// Log error but don't fail the entire operation
-				fmt.Printf("Error getting services for cluster %s: %v\n", clusterName, err)
+				slog.WarnContext(ctx, "Failed to get the services of a cluster", "cluster", clusterName, "error", err)
 				errorsCh <- fmt.Errorf("failed to get services for cluster %s: %w", clusterName, err)
 				return
 			}
 
-			// Send the cluster services to the channel
-			servicesCh <- clusterServices
+			mu.Lock()
+			defer mu.Unlock()
+			services = append(services, clusterServices...)
+			if loaded != nil {
+				loaded(append([]ServiceSummary(nil), services...))
+			}
 		}(cluster.Name)
 	}
 
 	// Wait for all goroutines to complete
 	wg.Wait()
-	close(servicesCh)
 	close(errorsCh)
 
-	// Collect all services from the channel
-	var services []ServiceSummary
-	for clusterServices := range servicesCh {
-		services = append(services, clusterServices...)
-	}
-
 	return services, nil
 }
 
@@ -166,15 +193,12 @@ func (c *Client) getClusterServices(ctx context.Context, clusterName string) ([]
 		}
 
 		// Describe services to get details
-		descResp, err := c.ecsClient.DescribeServices(ctx, &ecs.DescribeServicesInput{
-			Cluster:  aws.String(clusterName),
-			Services: listResp.ServiceArns,
-		})
+		described, err := c.describeServices(ctx, clusterName, listResp.ServiceArns)
 		if err != nil {
 			return nil, fmt.Errorf("failed to describe services: %w", err)
 		}
 
-		for _, service := range descResp.Services {
+		for _, service := range described {
 			// Extract tags into a map
 			tags := make(map[string]string)
 			for _, tag := range service.Tags {
@@ -236,8 +260,9 @@ func (c *Client) getClusterServices(ctx context.Context, clusterName string) ([]
 				healthStatus = "UNHEALTHY"
 			}
 
-			services = append(services, ServiceSummary{
+			summary := ServiceSummary{
 				ServiceName:        aws.ToString(service.ServiceName),
+				ARN:                aws.ToString(service.ServiceArn),
 				ClusterName:        clusterName,
 				Status:             aws.ToString(service.Status),
 				DesiredCount:       service.DesiredCount,
@@ -252,7 +277,15 @@ func (c *Client) getClusterServices(ctx context.Context, clusterName string) ([]
 				HealthStatus:       healthStatus,
 				DeploymentStatus:   deploymentStatus,
 				NetworkMode:        getNetworkMode(service),
-			})
+				TaskDefinitionARN:  aws.ToString(service.TaskDefinition),
+			}
+			if service.NetworkConfiguration != nil && service.NetworkConfiguration.AwsvpcConfiguration != nil {
+				vpc := service.NetworkConfiguration.AwsvpcConfiguration
+				summary.Subnets = vpc.Subnets
+				summary.SecurityGroups = vpc.SecurityGroups
+				summary.AssignPublicIP = vpc.AssignPublicIp == types.AssignPublicIpEnabled
+			}
+			services = append(services, summary)
 		}
 
 		nextToken = listResp.NextToken
@@ -264,6 +297,43 @@ func (c *Client) getClusterServices(ctx context.Context, clusterName string) ([]
 	return services, nil
 }
 
+// describeServices describes the services of a cluster with their tags.
+// When DescribeServices cannot include the tags, e.g. as an emulator does
+// not support it, the services are described without them and tagged one by
+// one through ListTagsForResource. Tags are left out if that fails too.
+func (c *Client) describeServices(ctx context.Context, clusterName string, arns []string) ([]types.Service, error) {
+	resp, err := c.ecsClient.DescribeServices(ctx, &ecs.DescribeServicesInput{
+		Cluster:  aws.String(clusterName),
+		Services: arns,
+		Include:  []types.ServiceField{types.ServiceFieldTags},
+	})
+	if err == nil {
+		return resp.Services, nil
+	}
+	if ctx.Err() != nil {
+		return nil, err
+	}
+	slog.DebugContext(ctx, "Failed to describe the services of a cluster with their tags", "cluster", clusterName, "error", err)
+
+	resp, err = c.ecsClient.DescribeServices(ctx, &ecs.DescribeServicesInput{
+		Cluster:  aws.String(clusterName),
+		Services: arns,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, service := range resp.Services {
+		tags, err := c.ecsClient.ListTagsForResource(ctx, &ecs.ListTagsForResourceInput{ResourceArn: service.ServiceArn})
+		if err != nil {
+			// The other services would fail alike
+			slog.DebugContext(ctx, "Failed to list the tags of a service", "service", aws.ToString(service.ServiceArn), "error", err)
+			break
+		}
+		resp.Services[i].Tags = tags.Tags
+	}
+	return resp.Services, nil
+}
+
 // getNetworkMode safely returns the network mode of the service
 func getNetworkMode(service types.Service) string {
 	// NetworkMode is not directly accessible in the current SDK version
@@ -15,6 +15,13 @@ type mockECSAPI struct {
 	DescribeClustersFunc func(ctx context.Context, params *ecs.DescribeClustersInput, optFns ...func(*ecs.Options)) (*ecs.DescribeClustersOutput, error)
 	ListServicesFunc     func(ctx context.Context, params *ecs.ListServicesInput, optFns ...func(*ecs.Options)) (*ecs.ListServicesOutput, error)
 	DescribeServicesFunc func(ctx context.Context, params *ecs.DescribeServicesInput, optFns ...func(*ecs.Options)) (*ecs.DescribeServicesOutput, error)
+
+	DescribeTaskDefinitionFunc func(ctx context.Context, params *ecs.DescribeTaskDefinitionInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTaskDefinitionOutput, error)
+	RunTaskFunc                func(ctx context.Context, params *ecs.RunTaskInput, optFns ...func(*ecs.Options)) (*ecs.RunTaskOutput, error)
+	DescribeTasksFunc          func(ctx context.Context, params *ecs.DescribeTasksInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTasksOutput, error)
+	UpdateServiceFunc          func(ctx context.Context, params *ecs.UpdateServiceInput, optFns ...func(*ecs.Options)) (*ecs.UpdateServiceOutput, error)
+	ListTasksFunc              func(ctx context.Context, params *ecs.ListTasksInput, optFns ...func(*ecs.Options)) (*ecs.ListTasksOutput, error)
+	ListTagsForResourceFunc    func(ctx context.Context, params *ecs.ListTagsForResourceInput, optFns ...func(*ecs.Options)) (*ecs.ListTagsForResourceOutput, error)
 }
 
 func (m *mockECSAPI) ListClusters(ctx context.Context, params *ecs.ListClustersInput, optFns ...func(*ecs.Options)) (*ecs.ListClustersOutput, error) {
@@ -33,6 +40,30 @@ func (m *mockECSAPI) DescribeServices(ctx context.Context, params *ecs.DescribeS
 	return m.DescribeServicesFunc(ctx, params, optFns...)
 }
 
+func (m *mockECSAPI) DescribeTaskDefinition(ctx context.Context, params *ecs.DescribeTaskDefinitionInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTaskDefinitionOutput, error) {
+	return m.DescribeTaskDefinitionFunc(ctx, params, optFns...)
+}
+
+func (m *mockECSAPI) RunTask(ctx context.Context, params *ecs.RunTaskInput, optFns ...func(*ecs.Options)) (*ecs.RunTaskOutput, error) {
+	return m.RunTaskFunc(ctx, params, optFns...)
+}
+
+func (m *mockECSAPI) DescribeTasks(ctx context.Context, params *ecs.DescribeTasksInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTasksOutput, error) {
+	return m.DescribeTasksFunc(ctx, params, optFns...)
+}
+
+func (m *mockECSAPI) UpdateService(ctx context.Context, params *ecs.UpdateServiceInput, optFns ...func(*ecs.Options)) (*ecs.UpdateServiceOutput, error) {
+	return m.UpdateServiceFunc(ctx, params, optFns...)
+}
+
+func (m *mockECSAPI) ListTasks(ctx context.Context, params *ecs.ListTasksInput, optFns ...func(*ecs.Options)) (*ecs.ListTasksOutput, error) {
+	return m.ListTasksFunc(ctx, params, optFns...)
+}
+
+func (m *mockECSAPI) ListTagsForResource(ctx context.Context, params *ecs.ListTagsForResourceInput, optFns ...func(*ecs.Options)) (*ecs.ListTagsForResourceOutput, error) {
+	return m.ListTagsForResourceFunc(ctx, params, optFns...)
+}
+
 func TestGetClusters(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -376,7 +407,10 @@ func TestGetServices(t *testing.T) {
 				},
 			})
 
-			services, err := client.GetServices(context.Background())
+			var partials [][]ServiceSummary
+			services, err := client.StreamServices(context.Background(), func(services []ServiceSummary) {
+				partials = append(partials, services)
+			})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetServices() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -384,10 +418,77 @@ func TestGetServices(t *testing.T) {
 			if len(services) != tt.expectedCount {
 				t.Errorf("GetServices() count = %d, want %d", len(services), tt.expectedCount)
 			}
+
+			// Each cluster adds its services to the partial results
+			if len(partials) != len(tt.listServicesResp) {
+				t.Errorf("Expected %d partial results, one per cluster, got %d", len(tt.listServicesResp), len(partials))
+			}
+			if len(partials) > 0 && len(partials[len(partials)-1]) != tt.expectedCount {
+				t.Errorf("Expected the last partial result to hold all %d services, got %d", tt.expectedCount, len(partials[len(partials)-1]))
+			}
 		})
 	}
 }
 
+func TestGetClusterServicesTags(t *testing.T) {
+	serviceARN := "arn:aws:ecs:us-west-2:123456789012:service/test-cluster/web"
+	client := NewClient(&mockECSAPI{
+		ListServicesFunc: func(ctx context.Context, params *ecs.ListServicesInput, optFns ...func(*ecs.Options)) (*ecs.ListServicesOutput, error) {
+			return &ecs.ListServicesOutput{ServiceArns: []string{serviceARN}}, nil
+		},
+		DescribeServicesFunc: func(ctx context.Context, params *ecs.DescribeServicesInput, optFns ...func(*ecs.Options)) (*ecs.DescribeServicesOutput, error) {
+			if len(params.Include) != 1 || params.Include[0] != types.ServiceFieldTags {
+				t.Errorf("Expected DescribeServices to include the tags, got %v", params.Include)
+			}
+			return &ecs.DescribeServicesOutput{Services: []types.Service{{
+				ServiceName: aws.String("web"),
+				ServiceArn:  aws.String(serviceARN),
+				Tags:        []types.Tag{{Key: aws.String("Owner"), Value: aws.String("payments")}},
+			}}}, nil
+		},
+	})
+
+	services, err := client.getClusterServices(context.Background(), "test-cluster")
+	if err != nil {
+		t.Fatalf("getClusterServices() returned an error: %v", err)
+	}
+	if len(services) != 1 || services[0].Tags["Owner"] != "payments" {
+		t.Errorf("Expected the tags of the service, got %+v", services)
+	}
+}
+
+func TestGetClusterServicesTagsFallback(t *testing.T) {
+	serviceARN := "arn:aws:ecs:us-west-2:123456789012:service/test-cluster/web"
+	client := NewClient(&mockECSAPI{
+		ListServicesFunc: func(ctx context.Context, params *ecs.ListServicesInput, optFns ...func(*ecs.Options)) (*ecs.ListServicesOutput, error) {
+			return &ecs.ListServicesOutput{ServiceArns: []string{serviceARN}}, nil
+		},
+		DescribeServicesFunc: func(ctx context.Context, params *ecs.DescribeServicesInput, optFns ...func(*ecs.Options)) (*ecs.DescribeServicesOutput, error) {
+			if len(params.Include) > 0 {
+				return nil, &types.InvalidParameterException{Message: aws.String("include is not supported")}
+			}
+			return &ecs.DescribeServicesOutput{Services: []types.Service{{
+				ServiceName: aws.String("web"),
+				ServiceArn:  aws.String(serviceARN),
+			}}}, nil
+		},
+		ListTagsForResourceFunc: func(ctx context.Context, params *ecs.ListTagsForResourceInput, optFns ...func(*ecs.Options)) (*ecs.ListTagsForResourceOutput, error) {
+			if aws.ToString(params.ResourceArn) != serviceARN {
+				t.Errorf("ListTagsForResource() called with %s, want %s", aws.ToString(params.ResourceArn), serviceARN)
+			}
+			return &ecs.ListTagsForResourceOutput{Tags: []types.Tag{{Key: aws.String("Environment"), Value: aws.String("staging")}}}, nil
+		},
+	})
+
+	services, err := client.getClusterServices(context.Background(), "test-cluster")
+	if err != nil {
+		t.Fatalf("getClusterServices() returned an error: %v", err)
+	}
+	if len(services) != 1 || services[0].Tags["Environment"] != "staging" {
+		t.Errorf("Expected the tags listed for the service, got %+v", services)
+	}
+}
+
 func TestGetNetworkMode(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -0,0 +1,78 @@
+package ecs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+// ExecTarget is a running container that ECS Exec can open a shell in
+type ExecTarget struct {
+	ClusterName string
+	TaskARN     string
+	TaskID      string
+	Container   string
+}
+
+// String names the target by its task and container, e.g. "0a1b2c3d/web"
+func (t ExecTarget) String() string {
+	return t.TaskID + "/" + t.Container
+}
+
+// ExecTargets returns the running containers of the service's running tasks
+// that have ECS Exec enabled. Services not deployed with
+// --enable-execute-command return an error explaining how to enable it.
+func (c *Client) ExecTargets(ctx context.Context, service ServiceSummary) ([]ExecTarget, error) {
+	listed, err := c.ecsClient.ListTasks(ctx, &ecs.ListTasksInput{
+		Cluster:       aws.String(service.ClusterName),
+		ServiceName:   aws.String(service.ServiceName),
+		DesiredStatus: types.DesiredStatusRunning,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks of service %s: %w", service.ServiceName, err)
+	}
+	if len(listed.TaskArns) == 0 {
+		return nil, fmt.Errorf("service %s has no running tasks", service.ServiceName)
+	}
+
+	// DescribeTasks takes up to 100 tasks, the most ListTasks returns at once
+	described, err := c.ecsClient.DescribeTasks(ctx, &ecs.DescribeTasksInput{
+		Cluster: aws.String(service.ClusterName),
+		Tasks:   listed.TaskArns,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe tasks of service %s: %w", service.ServiceName, err)
+	}
+
+	var targets []ExecTarget
+	enabled := false
+	for _, task := range described.Tasks {
+		if !task.EnableExecuteCommand || aws.ToString(task.LastStatus) != "RUNNING" {
+			continue
+		}
+		enabled = true
+		arn := aws.ToString(task.TaskArn)
+		for _, container := range task.Containers {
+			if aws.ToString(container.LastStatus) != "RUNNING" {
+				continue
+			}
+			targets = append(targets, ExecTarget{
+				ClusterName: service.ClusterName,
+				TaskARN:     arn,
+				TaskID:      taskID(arn),
+				Container:   aws.ToString(container.Name),
+			})
+		}
+	}
+
+	switch {
+	case !enabled:
+		return nil, fmt.Errorf("ECS Exec is not enabled on the running tasks of service %s, update it with --enable-execute-command and force a new deployment", service.ServiceName)
+	case len(targets) == 0:
+		return nil, fmt.Errorf("service %s has no running containers", service.ServiceName)
+	}
+	return targets, nil
+}
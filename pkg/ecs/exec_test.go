@@ -0,0 +1,66 @@
+package ecs
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+func TestExecTargets(t *testing.T) {
+	tasks := []types.Task{
+		{
+			TaskArn:              aws.String("arn:aws:ecs:us-east-1:123456789012:task/production/abc123"),
+			LastStatus:           aws.String("RUNNING"),
+			EnableExecuteCommand: true,
+			Containers: []types.Container{
+				{Name: aws.String("web"), LastStatus: aws.String("RUNNING")},
+				{Name: aws.String("migrate"), LastStatus: aws.String("STOPPED")},
+			},
+		},
+		{
+			TaskArn:    aws.String("arn:aws:ecs:us-east-1:123456789012:task/production/def456"),
+			LastStatus: aws.String("RUNNING"),
+			Containers: []types.Container{{Name: aws.String("web"), LastStatus: aws.String("RUNNING")}},
+		},
+	}
+	var listed *ecs.ListTasksInput
+	client := NewClient(&mockECSAPI{
+		ListTasksFunc: func(ctx context.Context, params *ecs.ListTasksInput, optFns ...func(*ecs.Options)) (*ecs.ListTasksOutput, error) {
+			listed = params
+			var arns []string
+			for _, task := range tasks {
+				arns = append(arns, aws.ToString(task.TaskArn))
+			}
+			return &ecs.ListTasksOutput{TaskArns: arns}, nil
+		},
+		DescribeTasksFunc: func(ctx context.Context, params *ecs.DescribeTasksInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTasksOutput, error) {
+			return &ecs.DescribeTasksOutput{Tasks: tasks}, nil
+		},
+	})
+	service := ServiceSummary{ServiceName: "web", ClusterName: "production"}
+
+	targets, err := client.ExecTargets(context.Background(), service)
+	if err != nil {
+		t.Fatalf("ExecTargets returned an error: %v", err)
+	}
+	if aws.ToString(listed.ServiceName) != "web" || listed.DesiredStatus != types.DesiredStatusRunning {
+		t.Errorf("Unexpected input %+v", listed)
+	}
+	if len(targets) != 1 || targets[0].String() != "abc123/web" || targets[0].ClusterName != "production" {
+		t.Errorf("Expected the running container of the task with ECS Exec enabled, got %+v", targets)
+	}
+
+	tasks = tasks[1:]
+	if _, err := client.ExecTargets(context.Background(), service); err == nil || !strings.Contains(err.Error(), "--enable-execute-command") {
+		t.Errorf("Expected an error explaining how to enable ECS Exec, got %v", err)
+	}
+
+	tasks = nil
+	if _, err := client.ExecTargets(context.Background(), service); err == nil || !strings.Contains(err.Error(), "no running tasks") {
+		t.Errorf("Expected an error for a service without tasks, got %v", err)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
 )
 
 var timeNow = time.Now
@@ -40,23 +42,51 @@ func GetServicesSummary(services []ServiceSummary) string {
 		len(services), len(clusters), active, draining, other, healthyServices, len(services))
 }
 
-// FormatServices returns a formatted string of ECS services
-func FormatServices(services []ServiceSummary) string {
-	if len(services) == 0 {
+// SortServices sorts services by cluster and name, the order in which
+// FormatServices lists them
+func SortServices(services []ServiceSummary) {
+	sort.Slice(services, func(i, j int) bool {
+		if services[i].ClusterName != services[j].ClusterName {
+			return services[i].ClusterName < services[j].ClusterName
+		}
+		return services[i].ServiceName < services[j].ServiceName
+	})
+}
+
+// FormatServices returns a formatted string of ECS services and the
+// scheduled tasks of their clusters, marking the service at index selected
+// (-1 for none)
+func FormatServices(services []ServiceSummary, scheduled []ScheduledTask, selected int) string {
+	if len(services) == 0 && len(scheduled) == 0 {
 		return "No ECS services found."
 	}
 
+	var selectedService *ServiceSummary
+	if selected >= 0 && selected < len(services) {
+		selectedService = &services[selected]
+	}
+
 	// First, group services by cluster
 	servicesByCluster := make(map[string][]ServiceSummary)
 	for _, service := range services {
 		servicesByCluster[service.ClusterName] = append(servicesByCluster[service.ClusterName], service)
 	}
 
-	// Get sorted cluster names
+	scheduledByCluster := make(map[string][]ScheduledTask)
+	for _, task := range scheduled {
+		scheduledByCluster[task.ClusterName] = append(scheduledByCluster[task.ClusterName], task)
+	}
+
+	// Get sorted cluster names, including those with only scheduled tasks
 	clusterNames := make([]string, 0, len(servicesByCluster))
 	for cluster := range servicesByCluster {
 		clusterNames = append(clusterNames, cluster)
 	}
+	for cluster := range scheduledByCluster {
+		if _, ok := servicesByCluster[cluster]; !ok {
+			clusterNames = append(clusterNames, cluster)
+		}
+	}
 	sort.Strings(clusterNames)
 
 	var sb strings.Builder
@@ -72,8 +102,9 @@ func FormatServices(services []ServiceSummary) string {
 		})
 
 		// Cluster header
-		sb.WriteString(fmt.Sprintf("🚀 Cluster: %s (%d services)\n", clusterName, len(clusterServices)))
-		sb.WriteString(strings.Repeat("-", 40) + "\n")
+		clusterHeader := fmt.Sprintf("🚀 Cluster: %s (%d services)", clusterName, len(clusterServices))
+		sb.WriteString(clusterHeader + "\n")
+		sb.WriteString(common.Rule(clusterHeader, "-") + "\n")
 
 		// Format each service
 		for _, service := range clusterServices {
@@ -87,7 +118,11 @@ func FormatServices(services []ServiceSummary) string {
 				healthIndicator = "⚪"
 			}
 
-			sb.WriteString(fmt.Sprintf("%s %s\n", healthIndicator, service.ServiceName))
+			marker := ""
+			if selectedService != nil && service.ClusterName == selectedService.ClusterName && service.ServiceName == selectedService.ServiceName {
+				marker = "> "
+			}
+			sb.WriteString(fmt.Sprintf("%s%s %s\n", marker, healthIndicator, service.ServiceName))
 
 			// Status and deployment status
 			deploymentInfo := ""
@@ -128,6 +163,22 @@ func FormatServices(services []ServiceSummary) string {
 				sb.WriteString(fmt.Sprintf("   Tags: %s\n", strings.Join(tagStrings, " | ")))
 			}
 
+			// Container Insights metrics, when they were loaded
+			if service.HasInsights() {
+				sb.WriteString(formatInsights(service))
+			} else if !service.MetricsEnd.IsZero() {
+				sb.WriteString("   No Container Insights data, is it enabled on the cluster?\n")
+			}
+
+			sb.WriteString("\n")
+		}
+
+		// Scheduled tasks of the cluster
+		if tasks := scheduledByCluster[clusterName]; len(tasks) > 0 {
+			sb.WriteString(fmt.Sprintf("⏱️ Scheduled tasks (%d):\n", len(tasks)))
+			for _, task := range tasks {
+				sb.WriteString(fmt.Sprintf("   %s\n", formatScheduledTask(task)))
+			}
 			sb.WriteString("\n")
 		}
 
@@ -138,6 +189,70 @@ func FormatServices(services []ServiceSummary) string {
 	return sb.String()
 }
 
+// formatInsights draws the CPU and memory utilization of a service against
+// its reservation over the past hour
+func formatInsights(service ServiceSummary) string {
+	var sb strings.Builder
+	for _, series := range []struct {
+		title    string
+		label    string
+		reserved string
+		data     []float64
+	}{
+		{"CPU", "CPU (%)", fmt.Sprintf("%.0f CPU units", service.CPUReserved), service.CPUUtilizationData},
+		{"Memory", "Memory (%)", fmt.Sprintf("%.0f MiB", service.MemoryReserved), service.MemoryUtilizationData},
+	} {
+		sb.WriteString(fmt.Sprintf("\n   %s Utilization of %s reserved (1 hour):\n", series.title, series.reserved))
+		if len(series.data) == 0 {
+			sb.WriteString(fmt.Sprintf("   No %s data available\n", strings.ToLower(series.title)))
+			continue
+		}
+		graph := common.GenerateSparkline(series.data, series.label, 3,
+			common.WithStats(), common.WithWindow(service.MetricsEnd.Add(-time.Hour), service.MetricsEnd))
+		sb.WriteString(fmt.Sprintf("%s\n", graph))
+	}
+	return sb.String()
+}
+
+// formatScheduledTask describes a scheduled task, e.g. "⚠️ send-digest:
+// rate(1 hour) runs email-worker:42, last triggered 12m ago, 3 failed
+// invocations in the past 24 hours"
+func formatScheduledTask(task ScheduledTask) string {
+	indicator := "🟢"
+	switch {
+	case !task.Enabled():
+		indicator = "⏹️"
+	case task.Failing():
+		indicator = "⚠️"
+	}
+
+	schedule := task.ScheduleExpression
+	if schedule == "" {
+		schedule = "on matching events"
+	}
+	description := fmt.Sprintf("%s %s: %s runs %s", indicator, task.RuleName, schedule, task.TaskDefinition)
+	if task.TaskCount > 1 {
+		description += fmt.Sprintf(" (%d tasks)", task.TaskCount)
+	}
+	if !task.Enabled() {
+		return description + ", " + strings.ToLower(task.State)
+	}
+	if !task.MetricsLoaded {
+		return description
+	}
+
+	window := fmt.Sprintf("%.0f hours", ScheduleWindow.Hours())
+	if task.LastTriggered.IsZero() {
+		description += ", not triggered in the past " + window
+	} else {
+		description += fmt.Sprintf(", last triggered %s ago", formatUptime(task.LastTriggered))
+	}
+	if task.Failing() {
+		description += fmt.Sprintf(", %.0f failed invocations in the past %s", task.FailedInvocations, window)
+	}
+	return description
+}
+
 // formatUptime formats the uptime of a service
 func formatUptime(createdTime time.Time) string {
 	duration := timeNow().Sub(createdTime)
@@ -165,3 +280,61 @@ func formatUptime(createdTime time.Time) string {
 
 	return fmt.Sprintf("%dm", minutes)
 }
+
+// FormatTask formats the state of a one-off task and its containers
+func FormatTask(task TaskSummary) string {
+	title := "ONE-OFF TASK: " + task.ID
+
+	var sb strings.Builder
+	sb.WriteString(title + "\n")
+	sb.WriteString(common.Rule(title, "-") + "\n")
+
+	indicator := "🔄"
+	if task.Stopped() {
+		indicator = "❌"
+		if task.Succeeded() {
+			indicator = "✅"
+		}
+	}
+	sb.WriteString(fmt.Sprintf("%s %s in cluster %s, task definition %s\n", indicator, task.LastStatus, task.ClusterName, task.TaskDefinition))
+
+	command := "default command of the task definition"
+	if len(task.Command) > 0 {
+		args := make([]string, len(task.Command))
+		for i, arg := range task.Command {
+			args[i] = arg
+			if arg == "" || strings.ContainsAny(arg, " \t'\"") {
+				args[i] = fmt.Sprintf("%q", arg)
+			}
+		}
+		command = strings.Join(args, " ")
+	}
+	sb.WriteString(fmt.Sprintf("   Command: %s\n", command))
+
+	switch {
+	case !task.StoppedAt.IsZero() && !task.StartedAt.IsZero():
+		sb.WriteString(fmt.Sprintf("   Ran for %s, stopped at %s\n",
+			task.StoppedAt.Sub(task.StartedAt).Round(time.Second), task.StoppedAt.Format("15:04:05")))
+	case !task.StartedAt.IsZero():
+		sb.WriteString(fmt.Sprintf("   Running for %s\n", timeNow().Sub(task.StartedAt).Round(time.Second)))
+	case !task.CreatedAt.IsZero():
+		sb.WriteString(fmt.Sprintf("   Waiting to start for %s\n", timeNow().Sub(task.CreatedAt).Round(time.Second)))
+	}
+
+	for _, container := range task.Containers {
+		status := container.LastStatus
+		if container.ExitCode != nil {
+			status += fmt.Sprintf(", exit code %d", *container.ExitCode)
+		}
+		if container.Reason != "" {
+			status += " (" + container.Reason + ")"
+		}
+		sb.WriteString(fmt.Sprintf("   Container %s: %s\n", container.Name, status))
+	}
+
+	if task.StoppedReason != "" {
+		sb.WriteString(fmt.Sprintf("   Stopped: %s (%s)\n", task.StoppedReason, task.StopCode))
+	}
+
+	return sb.String()
+}
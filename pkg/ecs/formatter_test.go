@@ -148,13 +148,46 @@ func TestFormatServices(t *testing.T) {
 			},
 			notContains: []string{
 				"Status: ACTIVE (deployment: stable)", // We don't show stable deployments with status
+				"Container Insights",
+				"Utilization",
+			},
+		},
+		{
+			name: "Container Insights metrics",
+			services: []ServiceSummary{
+				{
+					ServiceName:           "api-service",
+					ClusterName:           "production",
+					Status:                "ACTIVE",
+					DeploymentStatus:      "stable",
+					CPUUtilizationData:    []float64{25, 50, 40},
+					MemoryUtilizationData: []float64{60, 70, 80},
+					CPUReserved:           1024,
+					MemoryReserved:        2048,
+					MetricsEnd:            refTime,
+				},
+				{
+					ServiceName:      "legacy-service",
+					ClusterName:      "legacy",
+					Status:           "ACTIVE",
+					DeploymentStatus: "stable",
+					MetricsEnd:       refTime,
+				},
+			},
+			contains: []string{
+				"CPU Utilization of 1024 CPU units reserved (1 hour):",
+				"CPU (%)",
+				"Memory Utilization of 2048 MiB reserved (1 hour):",
+				"Memory (%)",
+				"legacy-service\n   Status: ACTIVE\n",
+				"No Container Insights data, is it enabled on the cluster?",
 			},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := FormatServices(tt.services)
+			got := FormatServices(tt.services, nil, -1)
 
 			for _, s := range tt.contains {
 				if !strings.Contains(got, s) {
@@ -171,6 +204,44 @@ func TestFormatServices(t *testing.T) {
 	}
 }
 
+func TestFormatServicesScheduledTasks(t *testing.T) {
+	refTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	oldTimeNow := timeNow
+	defer func() { timeNow = oldTimeNow }()
+	timeNow = func() time.Time { return refTime }
+
+	services := []ServiceSummary{
+		{ServiceName: "api", ClusterName: "production", Status: "ACTIVE", DesiredCount: 1, RunningCount: 1, DeploymentStatus: "stable"},
+	}
+	scheduled := []ScheduledTask{
+		{RuleName: "cleanup", ClusterName: "batch", TaskDefinition: "cleanup:1", TaskCount: 1, ScheduleExpression: "rate(1 hour)", State: "DISABLED", MetricsLoaded: true},
+		{RuleName: "reports", ClusterName: "production", TaskDefinition: "reports:7", TaskCount: 2, ScheduleExpression: "cron(0 6 * * ? *)", State: "ENABLED",
+			LastTriggered: refTime.Add(-6 * time.Hour), FailedInvocations: 3, MetricsLoaded: true},
+		{RuleName: "sync", ClusterName: "production", TaskDefinition: "sync:2", TaskCount: 1, State: "ENABLED", MetricsLoaded: true},
+	}
+
+	got := FormatServices(services, scheduled, -1)
+	for _, expected := range []string{
+		"🚀 Cluster: batch (0 services)",
+		"⏱️ Scheduled tasks (1):\n   ⏹️ cleanup: rate(1 hour) runs cleanup:1, disabled\n",
+		"⏱️ Scheduled tasks (2):\n",
+		"   ⚠️ reports: cron(0 6 * * ? *) runs reports:7 (2 tasks), last triggered 6h 0m ago, 3 failed invocations in the past 24 hours\n",
+		"   🟢 sync: on matching events runs sync:2, not triggered in the past 24 hours\n",
+	} {
+		if !strings.Contains(got, expected) {
+			t.Errorf("FormatServices() missing expected string %q, got:\n%s", expected, got)
+		}
+	}
+	if strings.Index(got, "Cluster: batch") > strings.Index(got, "Cluster: production") {
+		t.Errorf("Expected the clusters in order, got:\n%s", got)
+	}
+
+	if got := FormatServices(nil, scheduled[:1], -1); !strings.Contains(got, "cleanup") {
+		t.Errorf("Expected the scheduled tasks of clusters without services, got:\n%s", got)
+	}
+}
+
 func TestFormatUptime(t *testing.T) {
 	refTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
 
@@ -222,3 +293,66 @@ func TestFormatUptime(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatServicesSelection(t *testing.T) {
+	services := []ServiceSummary{
+		{ServiceName: "api", ClusterName: "staging"},
+		{ServiceName: "worker", ClusterName: "production"},
+		{ServiceName: "api", ClusterName: "production"},
+	}
+	SortServices(services)
+
+	if services[0].ClusterName != "production" || services[0].ServiceName != "api" || services[2].ClusterName != "staging" {
+		t.Fatalf("SortServices() = %v, want production/api, production/worker, staging/api", services)
+	}
+
+	got := FormatServices(services, nil, 2)
+	if strings.Count(got, "> ") != 1 {
+		t.Errorf("FormatServices() marks %d services, want 1:\n%s", strings.Count(got, "> "), got)
+	}
+	staging := got[strings.Index(got, "Cluster: staging"):]
+	if !strings.Contains(staging, "> ⚪ api") {
+		t.Errorf("FormatServices() does not mark staging/api:\n%s", got)
+	}
+}
+
+func TestFormatTask(t *testing.T) {
+	startedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	oldTimeNow := timeNow
+	defer func() { timeNow = oldTimeNow }()
+	timeNow = func() time.Time { return startedAt.Add(90 * time.Second) }
+
+	running := TaskSummary{
+		ID:             "abc123",
+		ClusterName:    "production",
+		TaskDefinition: "orders-api:42",
+		Command:        []string{"bin/migrate", "--to", "latest"},
+		LastStatus:     "RUNNING",
+		StartedAt:      startedAt,
+		Containers:     []ContainerSummary{{Name: "app", LastStatus: "RUNNING"}},
+	}
+	got := FormatTask(running)
+	for _, s := range []string{"ONE-OFF TASK: abc123", "🔄 RUNNING in cluster production, task definition orders-api:42",
+		"Command: bin/migrate --to latest", "Running for 1m30s", "Container app: RUNNING"} {
+		if !strings.Contains(got, s) {
+			t.Errorf("FormatTask() missing %q:\n%s", s, got)
+		}
+	}
+
+	exitCode := int32(1)
+	stopped := running
+	stopped.Command = nil
+	stopped.LastStatus = "STOPPED"
+	stopped.StoppedAt = startedAt.Add(time.Minute)
+	stopped.StopCode = "EssentialContainerExited"
+	stopped.StoppedReason = "Essential container in task exited"
+	stopped.Containers = []ContainerSummary{{Name: "app", LastStatus: "STOPPED", ExitCode: &exitCode}}
+	got = FormatTask(stopped)
+	for _, s := range []string{"❌ STOPPED", "Command: default command of the task definition", "Ran for 1m0s, stopped at 12:01:00",
+		"Container app: STOPPED, exit code 1", "Stopped: Essential container in task exited (EssentialContainerExited)"} {
+		if !strings.Contains(got, s) {
+			t.Errorf("FormatTask() missing %q:\n%s", s, got)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+
+	"github.com/correctedcloud/aws-overview/pkg/cloudwatchmetrics"
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// insightsNamespace is the namespace of the Container Insights metrics,
+// which are only published for clusters that have it enabled
+const insightsNamespace = "ECS/ContainerInsights"
+
+// cloudwatchClientAPI defines the interface for the CloudWatch client
+type cloudwatchClientAPI interface {
+	GetMetricData(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error)
+}
+
+// InsightsClient loads the Container Insights metrics of ECS services
+type InsightsClient struct {
+	cloudwatchClient cloudwatchClientAPI
+	pool             *common.Pool
+}
+
+// NewInsightsClient returns a Container Insights client whose calls run in
+// pool, which may be nil
+func NewInsightsClient(cloudwatchClient cloudwatchClientAPI, pool *common.Pool) *InsightsClient {
+	return &InsightsClient{
+		cloudwatchClient: cloudwatchClient,
+		pool:             pool,
+	}
+}
+
+// HasInsights reports whether Container Insights metrics were loaded for
+// the service, which they are not on clusters without it enabled
+func (s ServiceSummary) HasInsights() bool {
+	return len(s.CPUUtilizationData) > 0 || len(s.MemoryUtilizationData) > 0
+}
+
+// GetServiceMetrics fills in the CPU and memory the tasks of each service
+// used over the past hour, as a share of what they reserve, fetched together
+// in as few CloudWatch calls as possible. It returns the errors of the
+// metrics that could not be loaded.
+func (c *InsightsClient) GetServiceMetrics(ctx context.Context, services []ServiceSummary) []error {
+	if len(services) == 0 {
+		return nil
+	}
+
+	// Utilized and reserved CPU and memory of each service, in that order
+	metricNames := []string{"CpuUtilized", "CpuReserved", "MemoryUtilized", "MemoryReserved"}
+	var queries []cloudwatchmetrics.Query
+	for _, service := range services {
+		for _, name := range metricNames {
+			queries = append(queries, cloudwatchmetrics.Query{
+				Namespace:  insightsNamespace,
+				MetricName: name,
+				Dimensions: map[string]string{"ClusterName": service.ClusterName, "ServiceName": service.ServiceName},
+				Stat:       "Average",
+				Period:     5 * time.Minute,
+				Window:     time.Hour,
+			})
+		}
+	}
+
+	results := cloudwatchmetrics.New(c.cloudwatchClient, c.pool).Fetch(ctx, queries)
+
+	var errs []error
+	for i := range services {
+		service := &services[i]
+		metrics := results[i*len(metricNames) : (i+1)*len(metricNames)]
+
+		// The four metrics usually share a call, so report one error per service
+		var err error
+		for j, result := range metrics {
+			if result.Err != nil {
+				err = fmt.Errorf("service %s/%s: failed to get metric data for %s: %w",
+					service.ClusterName, service.ServiceName, metricNames[j], result.Err)
+				break
+			}
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		service.CPUUtilizationData = utilization(metrics[0], metrics[1])
+		service.CPUReserved = latest(metrics[1].Values)
+		service.MemoryUtilizationData = utilization(metrics[2], metrics[3])
+		service.MemoryReserved = latest(metrics[3].Values)
+		service.MetricsEnd = metrics[0].End
+	}
+	return errs
+}
+
+// utilization returns the utilized series as a percentage of the reserved
+// series, skipping the datapoints without a reservation at the same time
+func utilization(utilized, reserved cloudwatchmetrics.Result) []float64 {
+	reservedAt := make(map[time.Time]float64, len(reserved.Values))
+	for i, value := range reserved.Values {
+		if i < len(reserved.Timestamps) {
+			reservedAt[reserved.Timestamps[i]] = value
+		}
+	}
+
+	var percentages []float64
+	for i, value := range utilized.Values {
+		if i >= len(utilized.Timestamps) {
+			break
+		}
+		if total := reservedAt[utilized.Timestamps[i]]; total > 0 {
+			percentages = append(percentages, value/total*100)
+		}
+	}
+	return percentages
+}
+
+// latest returns the last value of a series, 0 when it is empty
+func latest(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	return values[len(values)-1]
+}
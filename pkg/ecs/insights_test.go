@@ -0,0 +1,98 @@
+package ecs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// Mock CloudWatch client
+type mockCloudWatchClient struct {
+	getMetricDataFunc func(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error)
+}
+
+func (m *mockCloudWatchClient) GetMetricData(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+	return m.getMetricDataFunc(ctx, params, optFns...)
+}
+
+func TestGetServiceMetrics(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	timestamps := []time.Time{start, start.Add(5 * time.Minute), start.Add(10 * time.Minute)}
+
+	cloudwatchClient := &mockCloudWatchClient{
+		getMetricDataFunc: func(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+			output := &cloudwatch.GetMetricDataOutput{}
+			for _, query := range params.MetricDataQueries {
+				metric := query.MetricStat.Metric
+				if aws.ToString(metric.Namespace) != "ECS/ContainerInsights" {
+					t.Errorf("Unexpected namespace %s", aws.ToString(metric.Namespace))
+				}
+				result := cwtypes.MetricDataResult{Id: query.Id}
+				// The legacy cluster has no Container Insights
+				if aws.ToString(metric.Dimensions[0].Value) == "production" {
+					switch aws.ToString(metric.MetricName) {
+					case "CpuUtilized":
+						result.Values, result.Timestamps = []float64{256, 512, 128}, timestamps
+					case "CpuReserved":
+						// The reservation of the last datapoint is missing
+						result.Values, result.Timestamps = []float64{1024, 1024}, timestamps[:2]
+					case "MemoryUtilized":
+						result.Values, result.Timestamps = []float64{1024, 1536, 1843.2}, timestamps
+					case "MemoryReserved":
+						result.Values, result.Timestamps = []float64{2048, 2048, 2048}, timestamps
+					}
+				}
+				output.MetricDataResults = append(output.MetricDataResults, result)
+			}
+			return output, nil
+		},
+	}
+
+	services := []ServiceSummary{
+		{ServiceName: "api", ClusterName: "production"},
+		{ServiceName: "api", ClusterName: "legacy"},
+	}
+	errs := NewInsightsClient(cloudwatchClient, nil).GetServiceMetrics(context.Background(), services)
+	if len(errs) != 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+
+	api := services[0]
+	if len(api.CPUUtilizationData) != 2 || api.CPUUtilizationData[0] != 25 || api.CPUUtilizationData[1] != 50 {
+		t.Errorf("Expected the CPU utilization of the reserved datapoints, got %v", api.CPUUtilizationData)
+	}
+	if len(api.MemoryUtilizationData) != 3 || api.MemoryUtilizationData[2] != 90 {
+		t.Errorf("Expected the memory utilization of all datapoints, got %v", api.MemoryUtilizationData)
+	}
+	if api.CPUReserved != 1024 || api.MemoryReserved != 2048 || api.MetricsEnd.IsZero() || !api.HasInsights() {
+		t.Errorf("Expected the latest reservations, got %+v", api)
+	}
+
+	legacy := services[1]
+	if legacy.HasInsights() || legacy.MetricsEnd.IsZero() {
+		t.Errorf("Expected the legacy service to be queried without data, got %+v", legacy)
+	}
+}
+
+func TestGetServiceMetricsError(t *testing.T) {
+	cloudwatchClient := &mockCloudWatchClient{
+		getMetricDataFunc: func(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+			return nil, errors.New("access denied")
+		},
+	}
+
+	services := []ServiceSummary{{ServiceName: "api", ClusterName: "production"}}
+	errs := NewInsightsClient(cloudwatchClient, nil).GetServiceMetrics(context.Background(), services)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "production/api") {
+		t.Errorf("Expected a single error for the service, got %v", errs)
+	}
+	if !services[0].MetricsEnd.IsZero() {
+		t.Errorf("Expected no metrics, got %+v", services[0])
+	}
+}
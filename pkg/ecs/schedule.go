@@ -0,0 +1,266 @@
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+
+	"github.com/correctedcloud/aws-overview/pkg/cloudwatchmetrics"
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// ScheduleWindow is how far back the triggers and failed invocations of
+// scheduled tasks are looked up
+const ScheduleWindow = 24 * time.Hour
+
+// eventbridgeClientAPI defines the interface for the EventBridge client
+type eventbridgeClientAPI interface {
+	ListRules(ctx context.Context, params *eventbridge.ListRulesInput, optFns ...func(*eventbridge.Options)) (*eventbridge.ListRulesOutput, error)
+	ListTargetsByRule(ctx context.Context, params *eventbridge.ListTargetsByRuleInput, optFns ...func(*eventbridge.Options)) (*eventbridge.ListTargetsByRuleOutput, error)
+}
+
+// ScheduleClient loads the EventBridge rules that run ECS tasks
+type ScheduleClient struct {
+	eventbridgeClient eventbridgeClientAPI
+	cloudwatchClient  cloudwatchClientAPI
+	pool              *common.Pool
+}
+
+// ScheduledTask is an EventBridge rule of the default event bus with an ECS
+// RunTask target, usually a cron job
+type ScheduledTask struct {
+	RuleName           string
+	ClusterName        string
+	TaskDefinition     string // Family and revision, e.g. "nightly-import:42"
+	TaskCount          int32
+	ScheduleExpression string // e.g. "cron(0 2 * * ? *)" or "rate(1 hour)", empty for rules matching events
+	State              string // e.g. "ENABLED" or "DISABLED"
+
+	// LastTriggered is the start of the last hour in which the rule
+	// triggered, zero when it did not within ScheduleWindow
+	LastTriggered     time.Time
+	FailedInvocations float64 // Targets that could not be invoked within ScheduleWindow
+	MetricsLoaded     bool
+}
+
+// NewScheduleClient returns a scheduled task client whose calls run in pool,
+// which may be nil
+func NewScheduleClient(eventbridgeClient eventbridgeClientAPI, cloudwatchClient cloudwatchClientAPI, pool *common.Pool) *ScheduleClient {
+	return &ScheduleClient{
+		eventbridgeClient: eventbridgeClient,
+		cloudwatchClient:  cloudwatchClient,
+		pool:              pool,
+	}
+}
+
+// Enabled reports whether the rule runs the task
+func (t ScheduledTask) Enabled() bool {
+	return t.State == "ENABLED"
+}
+
+// Failing reports whether EventBridge failed to run the task within
+// ScheduleWindow
+func (t ScheduledTask) Failing() bool {
+	return t.FailedInvocations > 0
+}
+
+// GetScheduledTasks returns the rules of the default event bus that run ECS
+// tasks, by cluster and rule name, with when they last triggered and how
+// often they failed to run the task. Rules whose targets or metrics fail to
+// load are left out or without metrics, and their errors returned alongside.
+func (c *ScheduleClient) GetScheduledTasks(ctx context.Context) ([]ScheduledTask, []error) {
+	rules, err := c.listRules(ctx)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	var tasks []ScheduledTask
+	var errs []error
+	for _, rule := range rules {
+		targets, err := c.listTargets(ctx, rule.RuleName)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, target := range targets {
+			task := rule
+			task.ClusterName = target.ClusterName
+			task.TaskDefinition = target.TaskDefinition
+			task.TaskCount = target.TaskCount
+			tasks = append(tasks, task)
+		}
+	}
+
+	sort.Slice(tasks, func(i, j int) bool {
+		if tasks[i].ClusterName != tasks[j].ClusterName {
+			return tasks[i].ClusterName < tasks[j].ClusterName
+		}
+		return tasks[i].RuleName < tasks[j].RuleName
+	})
+
+	errs = append(errs, c.getInvocations(ctx, tasks)...)
+	return tasks, errs
+}
+
+// listRules returns the rules of the default event bus, following the
+// pagination tokens
+func (c *ScheduleClient) listRules(ctx context.Context) ([]ScheduledTask, error) {
+	var rules []ScheduledTask
+	var nextToken *string
+
+	for {
+		var result *eventbridge.ListRulesOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.eventbridgeClient.ListRules(ctx, &eventbridge.ListRulesInput{
+				NextToken: nextToken,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list EventBridge rules: %w", err)
+		}
+
+		for _, rule := range result.Rules {
+			rules = append(rules, ScheduledTask{
+				RuleName:           aws.ToString(rule.Name),
+				ScheduleExpression: aws.ToString(rule.ScheduleExpression),
+				State:              string(rule.State),
+			})
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return rules, nil
+}
+
+// ecsTarget is the cluster and task an ECS target of a rule runs
+type ecsTarget struct {
+	ClusterName    string
+	TaskDefinition string
+	TaskCount      int32
+}
+
+// listTargets returns the ECS targets of a rule, following the pagination
+// tokens
+func (c *ScheduleClient) listTargets(ctx context.Context, rule string) ([]ecsTarget, error) {
+	var targets []ecsTarget
+	var nextToken *string
+
+	for {
+		var result *eventbridge.ListTargetsByRuleOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.eventbridgeClient.ListTargetsByRule(ctx, &eventbridge.ListTargetsByRuleInput{
+				Rule:      aws.String(rule),
+				NextToken: nextToken,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: failed to list targets: %w", rule, err)
+		}
+
+		for _, target := range result.Targets {
+			// Only ECS targets have ECS parameters; their ARN is the cluster's
+			if target.EcsParameters == nil {
+				continue
+			}
+			targets = append(targets, ecsTarget{
+				ClusterName:    lastSegment(aws.ToString(target.Arn)),
+				TaskDefinition: lastSegment(aws.ToString(target.EcsParameters.TaskDefinitionArn)),
+				TaskCount:      max(1, aws.ToInt32(target.EcsParameters.TaskCount)),
+			})
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return targets, nil
+}
+
+// getInvocations fills in when the rules last triggered and their failed
+// invocations, fetched together in as few CloudWatch calls as possible, and
+// returns the errors of those that could not be loaded
+func (c *ScheduleClient) getInvocations(ctx context.Context, tasks []ScheduledTask) []error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	// A rule with several ECS targets has a single set of metrics
+	var rules []string
+	seen := make(map[string]bool)
+	for _, task := range tasks {
+		if !seen[task.RuleName] {
+			seen[task.RuleName] = true
+			rules = append(rules, task.RuleName)
+		}
+	}
+
+	metricNames := []string{"TriggeredRules", "FailedInvocations"}
+	var queries []cloudwatchmetrics.Query
+	for _, rule := range rules {
+		for _, name := range metricNames {
+			queries = append(queries, cloudwatchmetrics.Query{
+				Namespace:  "AWS/Events",
+				MetricName: name,
+				Dimensions: map[string]string{"RuleName": rule},
+				Stat:       "Sum",
+				Period:     time.Hour,
+				Window:     ScheduleWindow,
+			})
+		}
+	}
+
+	results := cloudwatchmetrics.New(c.cloudwatchClient, c.pool).Fetch(ctx, queries)
+
+	var errs []error
+	loaded := make(map[string]ScheduledTask)
+	for i, rule := range rules {
+		triggered, failed := results[2*i], results[2*i+1]
+		if triggered.Err != nil || failed.Err != nil {
+			err := triggered.Err
+			if err == nil {
+				err = failed.Err
+			}
+			errs = append(errs, fmt.Errorf("rule %s: failed to get metric data: %w", rule, err))
+			continue
+		}
+
+		invocations := ScheduledTask{MetricsLoaded: true}
+		for j, value := range triggered.Values {
+			if value > 0 && j < len(triggered.Timestamps) && triggered.Timestamps[j].After(invocations.LastTriggered) {
+				invocations.LastTriggered = triggered.Timestamps[j]
+			}
+		}
+		for _, value := range failed.Values {
+			invocations.FailedInvocations += value
+		}
+		loaded[rule] = invocations
+	}
+
+	for i := range tasks {
+		if invocations, ok := loaded[tasks[i].RuleName]; ok {
+			tasks[i].LastTriggered = invocations.LastTriggered
+			tasks[i].FailedInvocations = invocations.FailedInvocations
+			tasks[i].MetricsLoaded = true
+		}
+	}
+	return errs
+}
+
+// lastSegment returns what follows the last slash of an ARN, e.g. the name
+// of a cluster or the family and revision of a task definition
+func lastSegment(arn string) string {
+	return arn[strings.LastIndex(arn, "/")+1:]
+}
@@ -0,0 +1,139 @@
+package ecs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	ebtypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+)
+
+// Mock EventBridge client
+type mockEventBridgeClient struct {
+	listRulesFunc         func(ctx context.Context, params *eventbridge.ListRulesInput, optFns ...func(*eventbridge.Options)) (*eventbridge.ListRulesOutput, error)
+	listTargetsByRuleFunc func(ctx context.Context, params *eventbridge.ListTargetsByRuleInput, optFns ...func(*eventbridge.Options)) (*eventbridge.ListTargetsByRuleOutput, error)
+}
+
+func (m *mockEventBridgeClient) ListRules(ctx context.Context, params *eventbridge.ListRulesInput, optFns ...func(*eventbridge.Options)) (*eventbridge.ListRulesOutput, error) {
+	return m.listRulesFunc(ctx, params, optFns...)
+}
+
+func (m *mockEventBridgeClient) ListTargetsByRule(ctx context.Context, params *eventbridge.ListTargetsByRuleInput, optFns ...func(*eventbridge.Options)) (*eventbridge.ListTargetsByRuleOutput, error) {
+	return m.listTargetsByRuleFunc(ctx, params, optFns...)
+}
+
+func TestGetScheduledTasks(t *testing.T) {
+	eventbridgeClient := &mockEventBridgeClient{
+		listRulesFunc: func(ctx context.Context, params *eventbridge.ListRulesInput, optFns ...func(*eventbridge.Options)) (*eventbridge.ListRulesOutput, error) {
+			// Return the rules over two pages
+			if params.NextToken == nil {
+				return &eventbridge.ListRulesOutput{
+					Rules: []ebtypes.Rule{
+						{Name: aws.String("reports"), ScheduleExpression: aws.String("cron(0 6 * * ? *)"), State: ebtypes.RuleStateEnabled},
+						{Name: aws.String("to-lambda"), EventPattern: aws.String(`{"source":["orders"]}`), State: ebtypes.RuleStateEnabled},
+					},
+					NextToken: aws.String("page-2"),
+				}, nil
+			}
+			return &eventbridge.ListRulesOutput{
+				Rules: []ebtypes.Rule{
+					{Name: aws.String("cleanup"), ScheduleExpression: aws.String("rate(1 hour)"), State: ebtypes.RuleStateDisabled},
+					{Name: aws.String("broken"), ScheduleExpression: aws.String("rate(1 day)"), State: ebtypes.RuleStateEnabled},
+				},
+			}, nil
+		},
+		listTargetsByRuleFunc: func(ctx context.Context, params *eventbridge.ListTargetsByRuleInput, optFns ...func(*eventbridge.Options)) (*eventbridge.ListTargetsByRuleOutput, error) {
+			switch aws.ToString(params.Rule) {
+			case "reports":
+				return &eventbridge.ListTargetsByRuleOutput{Targets: []ebtypes.Target{{
+					Arn: aws.String("arn:aws:ecs:us-east-1:123456789012:cluster/production"),
+					EcsParameters: &ebtypes.EcsParameters{
+						TaskDefinitionArn: aws.String("arn:aws:ecs:us-east-1:123456789012:task-definition/reports:7"),
+						TaskCount:         aws.Int32(2),
+					},
+				}}}, nil
+			case "cleanup":
+				return &eventbridge.ListTargetsByRuleOutput{Targets: []ebtypes.Target{{
+					Arn:           aws.String("arn:aws:ecs:us-east-1:123456789012:cluster/batch"),
+					EcsParameters: &ebtypes.EcsParameters{TaskDefinitionArn: aws.String("arn:aws:ecs:us-east-1:123456789012:task-definition/cleanup:1")},
+				}}}, nil
+			case "to-lambda":
+				return &eventbridge.ListTargetsByRuleOutput{Targets: []ebtypes.Target{{
+					Arn: aws.String("arn:aws:lambda:us-east-1:123456789012:function:orders"),
+				}}}, nil
+			}
+			return nil, errors.New("access denied")
+		},
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var queried []string
+	cloudwatchClient := &mockCloudWatchClient{
+		getMetricDataFunc: func(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+			output := &cloudwatch.GetMetricDataOutput{}
+			for _, query := range params.MetricDataQueries {
+				metric := query.MetricStat.Metric
+				queried = append(queried, aws.ToString(metric.Dimensions[0].Value))
+				result := cwtypes.MetricDataResult{Id: query.Id}
+				if aws.ToString(metric.Dimensions[0].Value) == "reports" {
+					switch aws.ToString(metric.MetricName) {
+					case "TriggeredRules":
+						result.Values = []float64{1, 1, 0}
+						result.Timestamps = []time.Time{start, start.Add(6 * time.Hour), start.Add(12 * time.Hour)}
+					case "FailedInvocations":
+						result.Values = []float64{1}
+						result.Timestamps = []time.Time{start}
+					}
+				}
+				output.MetricDataResults = append(output.MetricDataResults, result)
+			}
+			return output, nil
+		},
+	}
+
+	tasks, errs := NewScheduleClient(eventbridgeClient, cloudwatchClient, nil).GetScheduledTasks(context.Background())
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "rule broken") {
+		t.Errorf("Expected only the error of the broken rule, got %v", errs)
+	}
+	if len(tasks) != 2 || tasks[0].RuleName != "cleanup" || tasks[1].RuleName != "reports" {
+		t.Fatalf("Expected the ECS rules by cluster, got %+v", tasks)
+	}
+
+	cleanup := tasks[0]
+	if cleanup.ClusterName != "batch" || cleanup.TaskDefinition != "cleanup:1" || cleanup.TaskCount != 1 || cleanup.Enabled() {
+		t.Errorf("Expected the disabled cleanup task in batch, got %+v", cleanup)
+	}
+	if !cleanup.MetricsLoaded || !cleanup.LastTriggered.IsZero() || cleanup.Failing() {
+		t.Errorf("Expected cleanup not to have triggered, got %+v", cleanup)
+	}
+
+	reports := tasks[1]
+	if reports.ClusterName != "production" || reports.TaskDefinition != "reports:7" || reports.TaskCount != 2 || reports.ScheduleExpression != "cron(0 6 * * ? *)" {
+		t.Errorf("Expected the reports task in production, got %+v", reports)
+	}
+	if !reports.LastTriggered.Equal(start.Add(6*time.Hour)) || reports.FailedInvocations != 1 || !reports.Failing() {
+		t.Errorf("Expected reports to have last triggered at 06:00 with a failure, got %+v", reports)
+	}
+	if len(queried) != 4 {
+		t.Errorf("Expected two metrics for each ECS rule, got %v", queried)
+	}
+}
+
+func TestGetScheduledTasksError(t *testing.T) {
+	eventbridgeClient := &mockEventBridgeClient{
+		listRulesFunc: func(ctx context.Context, params *eventbridge.ListRulesInput, optFns ...func(*eventbridge.Options)) (*eventbridge.ListRulesOutput, error) {
+			return nil, errors.New("access denied")
+		},
+	}
+
+	tasks, errs := NewScheduleClient(eventbridgeClient, nil, nil).GetScheduledTasks(context.Background())
+	if tasks != nil || len(errs) != 1 {
+		t.Errorf("Expected a single error and no tasks, got %v and %v", tasks, errs)
+	}
+}
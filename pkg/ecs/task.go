@@ -0,0 +1,287 @@
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+
+	"github.com/correctedcloud/aws-overview/pkg/logs"
+)
+
+// TaskStartedBy tags the one-off tasks started from the overview
+const TaskStartedBy = "aws-overview"
+
+// TaskSummary represents a one-off task and its containers
+type TaskSummary struct {
+	ARN            string
+	ID             string
+	ClusterName    string
+	TaskDefinition string   // family:revision
+	Command        []string // Command override, empty when the default command runs
+	LastStatus     string
+	DesiredStatus  string
+	StopCode       string
+	StoppedReason  string
+	Containers     []ContainerSummary
+	CreatedAt      time.Time
+	StartedAt      time.Time
+	StoppedAt      time.Time
+}
+
+// ContainerSummary represents a container of a task
+type ContainerSummary struct {
+	Name       string
+	LastStatus string
+	ExitCode   *int32 // Set once the container exited
+	Reason     string
+}
+
+// Stopped reports whether the task has stopped
+func (t TaskSummary) Stopped() bool {
+	return t.LastStatus == "STOPPED"
+}
+
+// Succeeded reports whether the task stopped with all containers exiting 0
+func (t TaskSummary) Succeeded() bool {
+	if !t.Stopped() || len(t.Containers) == 0 {
+		return false
+	}
+	for _, container := range t.Containers {
+		if container.ExitCode == nil || *container.ExitCode != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// RunTask starts a one-off task from the service's task definition in the
+// service's cluster, subnets and security groups. A non-empty command
+// overrides the command of the task definition's first essential container.
+func (c *Client) RunTask(ctx context.Context, service ServiceSummary, command []string) (TaskSummary, error) {
+	input := &ecs.RunTaskInput{
+		Cluster:        aws.String(service.ClusterName),
+		TaskDefinition: aws.String(service.TaskDefinitionARN),
+		Count:          aws.Int32(1),
+		StartedBy:      aws.String(TaskStartedBy),
+	}
+
+	// Services using a capacity provider strategy have no launch type, in
+	// which case the cluster's default strategy applies
+	if service.LaunchType != "" {
+		input.LaunchType = types.LaunchType(service.LaunchType)
+	}
+
+	if len(service.Subnets) > 0 {
+		assignPublicIP := types.AssignPublicIpDisabled
+		if service.AssignPublicIP {
+			assignPublicIP = types.AssignPublicIpEnabled
+		}
+		input.NetworkConfiguration = &types.NetworkConfiguration{
+			AwsvpcConfiguration: &types.AwsVpcConfiguration{
+				Subnets:        service.Subnets,
+				SecurityGroups: service.SecurityGroups,
+				AssignPublicIp: assignPublicIP,
+			},
+		}
+	}
+
+	if len(command) > 0 {
+		container, err := c.getEssentialContainer(ctx, service.TaskDefinitionARN)
+		if err != nil {
+			return TaskSummary{}, err
+		}
+		input.Overrides = &types.TaskOverride{
+			ContainerOverrides: []types.ContainerOverride{
+				{Name: aws.String(container), Command: command},
+			},
+		}
+	}
+
+	result, err := c.ecsClient.RunTask(ctx, input)
+	if err != nil {
+		return TaskSummary{}, fmt.Errorf("failed to run task of service %s: %w", service.ServiceName, err)
+	}
+	if len(result.Failures) > 0 {
+		failure := result.Failures[0]
+		return TaskSummary{}, fmt.Errorf("failed to run task of service %s: %s %s", service.ServiceName,
+			aws.ToString(failure.Reason), aws.ToString(failure.Detail))
+	}
+	if len(result.Tasks) == 0 {
+		return TaskSummary{}, fmt.Errorf("failed to run task of service %s: no task was started", service.ServiceName)
+	}
+
+	return newTaskSummary(result.Tasks[0], service.ClusterName), nil
+}
+
+// DescribeTask returns the current state of a task
+func (c *Client) DescribeTask(ctx context.Context, clusterName, taskARN string) (TaskSummary, error) {
+	result, err := c.ecsClient.DescribeTasks(ctx, &ecs.DescribeTasksInput{
+		Cluster: aws.String(clusterName),
+		Tasks:   []string{taskARN},
+	})
+	if err != nil {
+		return TaskSummary{}, fmt.Errorf("failed to describe task %s: %w", taskID(taskARN), err)
+	}
+	if len(result.Tasks) == 0 {
+		reason := "task not found"
+		if len(result.Failures) > 0 {
+			reason = aws.ToString(result.Failures[0].Reason)
+		}
+		return TaskSummary{}, fmt.Errorf("failed to describe task %s: %s", taskID(taskARN), reason)
+	}
+
+	return newTaskSummary(result.Tasks[0], clusterName), nil
+}
+
+// getEssentialContainer returns the name of the first essential container of
+// a task definition, whose exit stops the task
+func (c *Client) getEssentialContainer(ctx context.Context, taskDefinition string) (string, error) {
+	result, err := c.ecsClient.DescribeTaskDefinition(ctx, &ecs.DescribeTaskDefinitionInput{
+		TaskDefinition: aws.String(taskDefinition),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe task definition: %w", err)
+	}
+	if result.TaskDefinition == nil || len(result.TaskDefinition.ContainerDefinitions) == 0 {
+		return "", fmt.Errorf("task definition %s has no containers", taskDefinition)
+	}
+
+	for _, container := range result.TaskDefinition.ContainerDefinitions {
+		// Containers are essential unless marked otherwise
+		if container.Essential == nil || *container.Essential {
+			return aws.ToString(container.Name), nil
+		}
+	}
+	return aws.ToString(result.TaskDefinition.ContainerDefinitions[0].Name), nil
+}
+
+// LogSources returns where the containers of a service log to through the
+// awslogs driver: their log group, narrowed to the streams of the container
+// when the streams are prefixed
+func (c *Client) LogSources(ctx context.Context, service ServiceSummary) ([]logs.Source, error) {
+	result, err := c.ecsClient.DescribeTaskDefinition(ctx, &ecs.DescribeTaskDefinitionInput{
+		TaskDefinition: aws.String(service.TaskDefinitionARN),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe task definition: %w", err)
+	}
+	if result.TaskDefinition == nil {
+		return nil, nil
+	}
+
+	var sources []logs.Source
+	for _, container := range result.TaskDefinition.ContainerDefinitions {
+		config := container.LogConfiguration
+		if config == nil || config.LogDriver != types.LogDriverAwslogs || config.Options["awslogs-group"] == "" {
+			continue
+		}
+
+		// Prefixed streams are named prefix/container/task-id
+		source := logs.Source{Group: config.Options["awslogs-group"]}
+		if prefix := config.Options["awslogs-stream-prefix"]; prefix != "" {
+			source.StreamPrefix = prefix + "/" + aws.ToString(container.Name) + "/"
+		}
+		if !slices.Contains(sources, source) {
+			sources = append(sources, source)
+		}
+	}
+	return sources, nil
+}
+
+// newTaskSummary summarizes a task
+func newTaskSummary(task types.Task, clusterName string) TaskSummary {
+	arn := aws.ToString(task.TaskArn)
+	summary := TaskSummary{
+		ARN:            arn,
+		ID:             taskID(arn),
+		ClusterName:    clusterName,
+		TaskDefinition: taskID(aws.ToString(task.TaskDefinitionArn)),
+		LastStatus:     aws.ToString(task.LastStatus),
+		DesiredStatus:  aws.ToString(task.DesiredStatus),
+		StopCode:       string(task.StopCode),
+		StoppedReason:  aws.ToString(task.StoppedReason),
+		CreatedAt:      aws.ToTime(task.CreatedAt),
+		StartedAt:      aws.ToTime(task.StartedAt),
+		StoppedAt:      aws.ToTime(task.StoppedAt),
+	}
+
+	if task.Overrides != nil {
+		for _, override := range task.Overrides.ContainerOverrides {
+			if len(override.Command) > 0 {
+				summary.Command = override.Command
+				break
+			}
+		}
+	}
+
+	for _, container := range task.Containers {
+		summary.Containers = append(summary.Containers, ContainerSummary{
+			Name:       aws.ToString(container.Name),
+			LastStatus: aws.ToString(container.LastStatus),
+			ExitCode:   container.ExitCode,
+			Reason:     aws.ToString(container.Reason),
+		})
+	}
+
+	return summary
+}
+
+// taskID returns the last segment of a task or task definition ARN
+func taskID(arn string) string {
+	return arn[strings.LastIndex(arn, "/")+1:]
+}
+
+// ParseCommand splits a command line into its arguments the way a shell
+// would, honoring single and double quotes and backslash escapes
+func ParseCommand(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune
+
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote == '\'':
+			current.WriteRune(r)
+		case r == '\\':
+			if i+1 == len(runes) {
+				return nil, fmt.Errorf("command ends with an escape character")
+			}
+			i++
+			current.WriteRune(runes[i])
+			inArg = true
+		case quote == '"':
+			current.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("command has an unterminated %c quote", quote)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
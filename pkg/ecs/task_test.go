@@ -0,0 +1,239 @@
+package ecs
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+
+	"github.com/correctedcloud/aws-overview/pkg/logs"
+)
+
+func TestRunTask(t *testing.T) {
+	service := ServiceSummary{
+		ServiceName:       "orders-api",
+		ClusterName:       "production",
+		LaunchType:        "FARGATE",
+		TaskDefinitionARN: "arn:aws:ecs:us-east-1:123456789012:task-definition/orders-api:42",
+		Subnets:           []string{"subnet-1", "subnet-2"},
+		SecurityGroups:    []string{"sg-1"},
+	}
+	taskARN := "arn:aws:ecs:us-east-1:123456789012:task/production/abc123"
+
+	tests := []struct {
+		name          string
+		command       []string
+		runErr        error
+		failures      []types.Failure
+		wantContainer string
+		wantErr       string
+	}{
+		{
+			name: "Default command",
+		},
+		{
+			name:          "Command override on the essential container",
+			command:       []string{"bin/migrate", "--to", "latest"},
+			wantContainer: "app",
+		},
+		{
+			name:     "Failure",
+			failures: []types.Failure{{Reason: aws.String("RESOURCE:MEMORY")}},
+			wantErr:  "RESOURCE:MEMORY",
+		},
+		{
+			name:    "API error",
+			runErr:  errors.New("AccessDeniedException"),
+			wantErr: "AccessDeniedException",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(&mockECSAPI{
+				DescribeTaskDefinitionFunc: func(ctx context.Context, params *ecs.DescribeTaskDefinitionInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTaskDefinitionOutput, error) {
+					return &ecs.DescribeTaskDefinitionOutput{TaskDefinition: &types.TaskDefinition{
+						ContainerDefinitions: []types.ContainerDefinition{
+							{Name: aws.String("log-router"), Essential: aws.Bool(false)},
+							{Name: aws.String("app")},
+						},
+					}}, nil
+				},
+				RunTaskFunc: func(ctx context.Context, params *ecs.RunTaskInput, optFns ...func(*ecs.Options)) (*ecs.RunTaskOutput, error) {
+					if aws.ToString(params.Cluster) != "production" || aws.ToString(params.TaskDefinition) != service.TaskDefinitionARN {
+						t.Errorf("RunTask() called with cluster %s and task definition %s", aws.ToString(params.Cluster), aws.ToString(params.TaskDefinition))
+					}
+					if params.LaunchType != types.LaunchTypeFargate {
+						t.Errorf("RunTask() launch type = %s, want FARGATE", params.LaunchType)
+					}
+					vpc := params.NetworkConfiguration.AwsvpcConfiguration
+					if !reflect.DeepEqual(vpc.Subnets, service.Subnets) || !reflect.DeepEqual(vpc.SecurityGroups, service.SecurityGroups) || vpc.AssignPublicIp != types.AssignPublicIpDisabled {
+						t.Errorf("RunTask() network configuration = %+v", vpc)
+					}
+
+					container := ""
+					if params.Overrides != nil {
+						override := params.Overrides.ContainerOverrides[0]
+						container = aws.ToString(override.Name)
+						if !reflect.DeepEqual(override.Command, tt.command) {
+							t.Errorf("RunTask() command = %v, want %v", override.Command, tt.command)
+						}
+					}
+					if container != tt.wantContainer {
+						t.Errorf("RunTask() overrides container %q, want %q", container, tt.wantContainer)
+					}
+
+					if tt.runErr != nil {
+						return nil, tt.runErr
+					}
+					if tt.failures != nil {
+						return &ecs.RunTaskOutput{Failures: tt.failures}, nil
+					}
+					return &ecs.RunTaskOutput{Tasks: []types.Task{{
+						TaskArn:           aws.String(taskARN),
+						TaskDefinitionArn: params.TaskDefinition,
+						LastStatus:        aws.String("PROVISIONING"),
+						Overrides:         params.Overrides,
+					}}}, nil
+				},
+			})
+
+			task, err := client.RunTask(context.Background(), service, tt.command)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("RunTask() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RunTask() error = %v", err)
+			}
+			if task.ID != "abc123" || task.TaskDefinition != "orders-api:42" || task.LastStatus != "PROVISIONING" {
+				t.Errorf("RunTask() = %+v", task)
+			}
+			if !reflect.DeepEqual(task.Command, tt.command) {
+				t.Errorf("RunTask() command = %v, want %v", task.Command, tt.command)
+			}
+		})
+	}
+}
+
+func TestDescribeTask(t *testing.T) {
+	stoppedAt := time.Date(2024, 1, 1, 12, 5, 0, 0, time.UTC)
+	client := NewClient(&mockECSAPI{
+		DescribeTasksFunc: func(ctx context.Context, params *ecs.DescribeTasksInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTasksOutput, error) {
+			if params.Tasks[0] == "missing" {
+				return &ecs.DescribeTasksOutput{Failures: []types.Failure{{Reason: aws.String("MISSING")}}}, nil
+			}
+			return &ecs.DescribeTasksOutput{Tasks: []types.Task{{
+				TaskArn:       aws.String(params.Tasks[0]),
+				LastStatus:    aws.String("STOPPED"),
+				StopCode:      types.TaskStopCodeEssentialContainerExited,
+				StoppedReason: aws.String("Essential container in task exited"),
+				StoppedAt:     aws.Time(stoppedAt),
+				Containers:    []types.Container{{Name: aws.String("app"), LastStatus: aws.String("STOPPED"), ExitCode: aws.Int32(0)}},
+			}}}, nil
+		},
+	})
+
+	task, err := client.DescribeTask(context.Background(), "production", "arn:aws:ecs:us-east-1:123456789012:task/production/abc123")
+	if err != nil {
+		t.Fatalf("DescribeTask() error = %v", err)
+	}
+	if !task.Stopped() || !task.Succeeded() || !task.StoppedAt.Equal(stoppedAt) {
+		t.Errorf("DescribeTask() = %+v, want a succeeded task", task)
+	}
+
+	if _, err := client.DescribeTask(context.Background(), "production", "missing"); err == nil || !strings.Contains(err.Error(), "MISSING") {
+		t.Errorf("DescribeTask() error = %v, want MISSING", err)
+	}
+}
+
+func TestTaskSucceeded(t *testing.T) {
+	tests := []struct {
+		name string
+		task TaskSummary
+		want bool
+	}{
+		{"Running", TaskSummary{LastStatus: "RUNNING", Containers: []ContainerSummary{{ExitCode: nil}}}, false},
+		{"Exit 0", TaskSummary{LastStatus: "STOPPED", Containers: []ContainerSummary{{ExitCode: aws.Int32(0)}}}, true},
+		{"Exit 1", TaskSummary{LastStatus: "STOPPED", Containers: []ContainerSummary{{ExitCode: aws.Int32(0)}, {ExitCode: aws.Int32(1)}}}, false},
+		{"Never started", TaskSummary{LastStatus: "STOPPED", Containers: []ContainerSummary{{ExitCode: nil}}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.task.Succeeded(); got != tt.want {
+				t.Errorf("Succeeded() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    []string
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"bin/migrate --to latest", []string{"bin/migrate", "--to", "latest"}, false},
+		{`sh -c "echo 'hi there' && exit 1"`, []string{"sh", "-c", "echo 'hi there' && exit 1"}, false},
+		{`echo 'a "b"'  c\ d ""`, []string{"echo", `a "b"`, "c d", ""}, false},
+		{`echo "unterminated`, nil, true},
+		{`echo \`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, err := ParseCommand(tt.line)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCommand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogSources(t *testing.T) {
+	client := NewClient(&mockECSAPI{
+		DescribeTaskDefinitionFunc: func(ctx context.Context, params *ecs.DescribeTaskDefinitionInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTaskDefinitionOutput, error) {
+			if aws.ToString(params.TaskDefinition) != "orders-api:42" {
+				t.Errorf("Expected the service's task definition, got %s", aws.ToString(params.TaskDefinition))
+			}
+			awslogs := func(options map[string]string) *types.LogConfiguration {
+				return &types.LogConfiguration{LogDriver: types.LogDriverAwslogs, Options: options}
+			}
+			return &ecs.DescribeTaskDefinitionOutput{TaskDefinition: &types.TaskDefinition{
+				ContainerDefinitions: []types.ContainerDefinition{
+					{Name: aws.String("app"), LogConfiguration: awslogs(map[string]string{"awslogs-group": "/ecs/orders", "awslogs-stream-prefix": "ecs"})},
+					{Name: aws.String("proxy"), LogConfiguration: awslogs(map[string]string{"awslogs-group": "/ecs/proxies"})},
+					{Name: aws.String("xray"), LogConfiguration: awslogs(map[string]string{"awslogs-group": "/ecs/proxies"})},
+					{Name: aws.String("router"), LogConfiguration: &types.LogConfiguration{LogDriver: types.LogDriverAwsfirelens}},
+					{Name: aws.String("init")},
+				},
+			}}, nil
+		},
+	})
+
+	sources, err := client.LogSources(context.Background(), ServiceSummary{TaskDefinitionARN: "orders-api:42"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []logs.Source{
+		{Group: "/ecs/orders", StreamPrefix: "ecs/app/"},
+		{Group: "/ecs/proxies"},
+	}
+	if !reflect.DeepEqual(sources, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, sources)
+	}
+}
@@ -0,0 +1,37 @@
+package ecs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+)
+
+// ScaleService sets the desired count of a service
+func (c *Client) ScaleService(ctx context.Context, service ServiceSummary, desiredCount int32) error {
+	_, err := c.ecsClient.UpdateService(ctx, &ecs.UpdateServiceInput{
+		Cluster:      aws.String(service.ClusterName),
+		Service:      aws.String(service.ServiceName),
+		DesiredCount: aws.Int32(desiredCount),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scale service %s: %w", service.ServiceName, err)
+	}
+	return nil
+}
+
+// ForceNewDeployment starts a deployment of a service with its current task
+// definition, replacing its tasks, e.g. to pull an image pushed to the same
+// tag
+func (c *Client) ForceNewDeployment(ctx context.Context, service ServiceSummary) error {
+	_, err := c.ecsClient.UpdateService(ctx, &ecs.UpdateServiceInput{
+		Cluster:            aws.String(service.ClusterName),
+		Service:            aws.String(service.ServiceName),
+		ForceNewDeployment: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to redeploy service %s: %w", service.ServiceName, err)
+	}
+	return nil
+}
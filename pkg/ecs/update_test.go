@@ -0,0 +1,48 @@
+package ecs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+)
+
+func TestScaleService(t *testing.T) {
+	var input *ecs.UpdateServiceInput
+	client := NewClient(&mockECSAPI{
+		UpdateServiceFunc: func(ctx context.Context, params *ecs.UpdateServiceInput, optFns ...func(*ecs.Options)) (*ecs.UpdateServiceOutput, error) {
+			input = params
+			return &ecs.UpdateServiceOutput{}, nil
+		},
+	})
+
+	service := ServiceSummary{ServiceName: "web", ClusterName: "production"}
+	if err := client.ScaleService(context.Background(), service, 5); err != nil {
+		t.Fatalf("ScaleService returned an error: %v", err)
+	}
+	if aws.ToString(input.Cluster) != "production" || aws.ToString(input.Service) != "web" || aws.ToInt32(input.DesiredCount) != 5 || input.ForceNewDeployment {
+		t.Errorf("Unexpected input %+v", input)
+	}
+
+	if err := client.ForceNewDeployment(context.Background(), service); err != nil {
+		t.Fatalf("ForceNewDeployment returned an error: %v", err)
+	}
+	if !input.ForceNewDeployment || input.DesiredCount != nil {
+		t.Errorf("Expected a forced deployment keeping the desired count, got %+v", input)
+	}
+}
+
+func TestScaleServiceError(t *testing.T) {
+	client := NewClient(&mockECSAPI{
+		UpdateServiceFunc: func(ctx context.Context, params *ecs.UpdateServiceInput, optFns ...func(*ecs.Options)) (*ecs.UpdateServiceOutput, error) {
+			return nil, errors.New("AccessDeniedException")
+		},
+	})
+	err := client.ScaleService(context.Background(), ServiceSummary{ServiceName: "web"}, 2)
+	if err == nil || !strings.Contains(err.Error(), "failed to scale service web") {
+		t.Errorf("Expected a scale error, got %v", err)
+	}
+}
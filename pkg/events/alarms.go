@@ -0,0 +1,58 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// Alarm is a metric alarm in the ALARM state
+type Alarm struct {
+	Name   string
+	Reason string    // Why it went off, e.g. "Threshold Crossed: 1 datapoint [92.1] was greater than the threshold (80.0)."
+	Since  time.Time // When it went off
+}
+
+// ActiveAlarms returns the metric alarms in the ALARM state, the one that
+// went off first first
+func (c *Client) ActiveAlarms(ctx context.Context) ([]Alarm, error) {
+	var alarms []Alarm
+	var nextToken *string
+	for {
+		var result *cloudwatch.DescribeAlarmsOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.cloudwatchClient.DescribeAlarms(ctx, &cloudwatch.DescribeAlarmsInput{
+				AlarmTypes: []cwtypes.AlarmType{cwtypes.AlarmTypeMetricAlarm},
+				StateValue: cwtypes.StateValueAlarm,
+				NextToken:  nextToken,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe alarms: %w", err)
+		}
+
+		for _, alarm := range result.MetricAlarms {
+			alarms = append(alarms, Alarm{
+				Name:   aws.ToString(alarm.AlarmName),
+				Reason: aws.ToString(alarm.StateReason),
+				Since:  aws.ToTime(alarm.StateUpdatedTimestamp),
+			})
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	sort.SliceStable(alarms, func(i, j int) bool {
+		return alarms[i].Since.Before(alarms[j].Since)
+	})
+	return alarms, nil
+}
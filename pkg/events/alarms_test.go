@@ -0,0 +1,44 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+func TestActiveAlarms(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	cloudwatchClient := &mockCloudWatchClient{
+		describeAlarmsFunc: func(ctx context.Context, params *cloudwatch.DescribeAlarmsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.DescribeAlarmsOutput, error) {
+			if params.StateValue != cwtypes.StateValueAlarm {
+				t.Errorf("Expected only alarms in the ALARM state, got %q", params.StateValue)
+			}
+			if params.NextToken == nil {
+				return &cloudwatch.DescribeAlarmsOutput{
+					MetricAlarms: []cwtypes.MetricAlarm{{
+						AlarmName:             aws.String("web-cpu"),
+						StateReason:           aws.String("Threshold Crossed"),
+						StateUpdatedTimestamp: aws.Time(now.Add(-5 * time.Minute)),
+					}},
+					NextToken: aws.String("next"),
+				}, nil
+			}
+			return &cloudwatch.DescribeAlarmsOutput{MetricAlarms: []cwtypes.MetricAlarm{{
+				AlarmName:             aws.String("db-storage"),
+				StateUpdatedTimestamp: aws.Time(now.Add(-2 * time.Hour)),
+			}}}, nil
+		},
+	}
+
+	alarms, err := NewClient(cloudwatchClient, nil, nil, nil, nil).ActiveAlarms(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(alarms) != 2 || alarms[0].Name != "db-storage" || alarms[1].Reason != "Threshold Crossed" {
+		t.Errorf("Expected both pages of alarms, the oldest first, got %+v", alarms)
+	}
+}
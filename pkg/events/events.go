@@ -0,0 +1,353 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	cttypes "github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// cloudwatchClientAPI defines the interface for the CloudWatch client
+type cloudwatchClientAPI interface {
+	DescribeAlarms(ctx context.Context, params *cloudwatch.DescribeAlarmsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.DescribeAlarmsOutput, error)
+	DescribeAlarmHistory(ctx context.Context, params *cloudwatch.DescribeAlarmHistoryInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.DescribeAlarmHistoryOutput, error)
+}
+
+// cloudtrailClientAPI defines the interface for the CloudTrail client
+type cloudtrailClientAPI interface {
+	LookupEvents(ctx context.Context, params *cloudtrail.LookupEventsInput, optFns ...func(*cloudtrail.Options)) (*cloudtrail.LookupEventsOutput, error)
+}
+
+// ecsClientAPI defines the interface for the ECS client
+type ecsClientAPI interface {
+	DescribeServices(ctx context.Context, params *ecs.DescribeServicesInput, optFns ...func(*ecs.Options)) (*ecs.DescribeServicesOutput, error)
+}
+
+// rdsClientAPI defines the interface for the RDS client
+type rdsClientAPI interface {
+	DescribeEvents(ctx context.Context, params *rds.DescribeEventsInput, optFns ...func(*rds.Options)) (*rds.DescribeEventsOutput, error)
+}
+
+// Window is how far back related events are looked up
+const Window = time.Hour
+
+// maxTrailPages bounds the LookupEvents calls per resource name. CloudTrail
+// allows two lookups per second per account, so busy resources are cut short
+// rather than holding up the pane.
+const maxTrailPages = 5
+
+// timeNow returns the current time, replaced in tests
+var timeNow = time.Now
+
+// Sources of related events
+const (
+	SourceAlarm      = "Alarm"
+	SourceCloudTrail = "CloudTrail"
+	SourceECS        = "ECS"
+	SourceRDS        = "RDS"
+)
+
+// Client represents a client of the services related events come from
+type Client struct {
+	cloudwatchClient cloudwatchClientAPI
+	cloudtrailClient cloudtrailClientAPI
+	ecsClient        ecsClientAPI
+	rdsClient        rdsClientAPI
+	pool             *common.Pool
+}
+
+// Resource identifies a resource in each source of related events. Sources
+// whose fields are empty are not looked up.
+type Resource struct {
+	// TrailNames are the names CloudTrail records the resource's changes
+	// under, e.g. its name and its ARN
+	TrailNames []string
+	// AlarmDimensions are the dimensions of the resource's metrics. Alarms
+	// on metrics with all of them are related.
+	AlarmDimensions map[string]string
+	// ECSCluster and ECSService name an ECS service, whose service events
+	// are related
+	ECSCluster string
+	ECSService string
+	// RDSInstance is the identifier of an RDS instance, whose RDS events are
+	// related
+	RDSInstance string
+}
+
+// Event is a related event
+type Event struct {
+	Time    time.Time
+	Source  string // One of the Source constants
+	Message string
+}
+
+// NewClient returns a new related events client whose calls run in pool,
+// which may be nil
+func NewClient(cloudwatchClient cloudwatchClientAPI, cloudtrailClient cloudtrailClientAPI, ecsClient ecsClientAPI, rdsClient rdsClientAPI, pool *common.Pool) *Client {
+	return &Client{
+		cloudwatchClient: cloudwatchClient,
+		cloudtrailClient: cloudtrailClient,
+		ecsClient:        ecsClient,
+		rdsClient:        rdsClient,
+		pool:             pool,
+	}
+}
+
+// Recent returns the events of the past Window related to resource from
+// each of its sources, oldest first: the state changes of its alarms, the
+// changes CloudTrail recorded, and its ECS service or RDS events. Sources
+// that fail to load are left out and their errors returned alongside the
+// events of the others.
+func (c *Client) Recent(ctx context.Context, resource Resource) ([]Event, []error) {
+	start := timeNow().Add(-Window)
+
+	var lookups []func() ([]Event, error)
+	if len(resource.AlarmDimensions) > 0 {
+		lookups = append(lookups, func() ([]Event, error) { return c.alarmEvents(ctx, resource.AlarmDimensions, start) })
+	}
+	for _, name := range resource.TrailNames {
+		lookups = append(lookups, func() ([]Event, error) { return c.trailEvents(ctx, name, start) })
+	}
+	if resource.ECSService != "" {
+		lookups = append(lookups, func() ([]Event, error) {
+			return c.serviceEvents(ctx, resource.ECSCluster, resource.ECSService, start)
+		})
+	}
+	if resource.RDSInstance != "" {
+		lookups = append(lookups, func() ([]Event, error) { return c.instanceEvents(ctx, resource.RDSInstance, start) })
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var events []Event
+	var errs []error
+
+	for _, lookup := range lookups {
+		wg.Add(1)
+		go func(lookup func() ([]Event, error)) {
+			defer wg.Done()
+
+			found, err := lookup()
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			events = append(events, found...)
+		}(lookup)
+	}
+	wg.Wait()
+
+	events = dedupe(events)
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+	return events, errs
+}
+
+// dedupe drops repeated events, e.g. a CloudTrail event recorded under both
+// the name and the ARN of a resource
+func dedupe(events []Event) []Event {
+	seen := make(map[Event]bool, len(events))
+	var unique []Event
+	for _, event := range events {
+		if !seen[event] {
+			seen[event] = true
+			unique = append(unique, event)
+		}
+	}
+	return unique
+}
+
+// alarmEvents returns the state changes since start of the alarms on metrics
+// with all of dimensions
+func (c *Client) alarmEvents(ctx context.Context, dimensions map[string]string, start time.Time) ([]Event, error) {
+	var names []string
+	var nextToken *string
+	for {
+		var result *cloudwatch.DescribeAlarmsOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.cloudwatchClient.DescribeAlarms(ctx, &cloudwatch.DescribeAlarmsInput{
+				AlarmTypes: []cwtypes.AlarmType{cwtypes.AlarmTypeMetricAlarm},
+				NextToken:  nextToken,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe alarms: %w", err)
+		}
+
+		for _, alarm := range result.MetricAlarms {
+			if hasDimensions(alarm.Dimensions, dimensions) {
+				names = append(names, aws.ToString(alarm.AlarmName))
+			}
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	var events []Event
+	for _, name := range names {
+		var result *cloudwatch.DescribeAlarmHistoryOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.cloudwatchClient.DescribeAlarmHistory(ctx, &cloudwatch.DescribeAlarmHistoryInput{
+				AlarmName:       aws.String(name),
+				HistoryItemType: cwtypes.HistoryItemTypeStateUpdate,
+				StartDate:       aws.Time(start),
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe the history of alarm %s: %w", name, err)
+		}
+
+		for _, item := range result.AlarmHistoryItems {
+			events = append(events, Event{
+				Time:    aws.ToTime(item.Timestamp),
+				Source:  SourceAlarm,
+				Message: name + ": " + aws.ToString(item.HistorySummary),
+			})
+		}
+	}
+	return events, nil
+}
+
+// hasDimensions reports whether an alarm's metric has all of dimensions
+func hasDimensions(alarm []cwtypes.Dimension, dimensions map[string]string) bool {
+	matched := 0
+	for _, dimension := range alarm {
+		if value, ok := dimensions[aws.ToString(dimension.Name)]; ok && value == aws.ToString(dimension.Value) {
+			matched++
+		}
+	}
+	return matched == len(dimensions)
+}
+
+// trailEvents returns the changes CloudTrail recorded since start to the
+// resource named name. Reads are left out.
+func (c *Client) trailEvents(ctx context.Context, name string, start time.Time) ([]Event, error) {
+	input := &cloudtrail.LookupEventsInput{
+		LookupAttributes: []cttypes.LookupAttribute{{
+			AttributeKey:   cttypes.LookupAttributeKeyResourceName,
+			AttributeValue: aws.String(name),
+		}},
+		StartTime: aws.Time(start),
+	}
+
+	var events []Event
+	for page := 0; page < maxTrailPages; page++ {
+		var result *cloudtrail.LookupEventsOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.cloudtrailClient.LookupEvents(ctx, input)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up CloudTrail events of %s: %w", name, err)
+		}
+
+		for _, event := range result.Events {
+			if aws.ToString(event.ReadOnly) == "true" {
+				continue
+			}
+			message := aws.ToString(event.EventName)
+			if user := aws.ToString(event.Username); user != "" {
+				message += " by " + user
+			}
+			events = append(events, Event{
+				Time:    aws.ToTime(event.EventTime),
+				Source:  SourceCloudTrail,
+				Message: message,
+			})
+		}
+
+		if result.NextToken == nil {
+			break
+		}
+		input.NextToken = result.NextToken
+	}
+	return events, nil
+}
+
+// serviceEvents returns the service events of an ECS service since start,
+// such as deployments, scaling and tasks failing to start
+func (c *Client) serviceEvents(ctx context.Context, cluster, service string, start time.Time) ([]Event, error) {
+	var result *ecs.DescribeServicesOutput
+	err := c.pool.Do(ctx, func() (err error) {
+		result, err = c.ecsClient.DescribeServices(ctx, &ecs.DescribeServicesInput{
+			Cluster:  aws.String(cluster),
+			Services: []string{service},
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe service %s: %w", service, err)
+	}
+
+	var events []Event
+	for _, described := range result.Services {
+		for _, event := range described.Events {
+			if aws.ToTime(event.CreatedAt).Before(start) {
+				continue
+			}
+			events = append(events, Event{
+				Time:    aws.ToTime(event.CreatedAt),
+				Source:  SourceECS,
+				Message: aws.ToString(event.Message),
+			})
+		}
+	}
+	return events, nil
+}
+
+// instanceEvents returns the RDS events of a DB instance since start, such
+// as reboots, failovers and configuration changes
+func (c *Client) instanceEvents(ctx context.Context, instance string, start time.Time) ([]Event, error) {
+	input := &rds.DescribeEventsInput{
+		SourceIdentifier: aws.String(instance),
+		SourceType:       rdstypes.SourceTypeDbInstance,
+		StartTime:        aws.Time(start),
+	}
+
+	var events []Event
+	for {
+		var result *rds.DescribeEventsOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.rdsClient.DescribeEvents(ctx, input)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe events of %s: %w", instance, err)
+		}
+
+		for _, event := range result.Events {
+			events = append(events, Event{
+				Time:    aws.ToTime(event.Date),
+				Source:  SourceRDS,
+				Message: aws.ToString(event.Message),
+			})
+		}
+
+		if result.Marker == nil {
+			break
+		}
+		input.Marker = result.Marker
+	}
+	return events, nil
+}
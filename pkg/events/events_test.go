@@ -0,0 +1,205 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	cttypes "github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+// Mock CloudWatch client
+type mockCloudWatchClient struct {
+	describeAlarmsFunc       func(ctx context.Context, params *cloudwatch.DescribeAlarmsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.DescribeAlarmsOutput, error)
+	describeAlarmHistoryFunc func(ctx context.Context, params *cloudwatch.DescribeAlarmHistoryInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.DescribeAlarmHistoryOutput, error)
+}
+
+func (m *mockCloudWatchClient) DescribeAlarms(ctx context.Context, params *cloudwatch.DescribeAlarmsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.DescribeAlarmsOutput, error) {
+	return m.describeAlarmsFunc(ctx, params, optFns...)
+}
+
+func (m *mockCloudWatchClient) DescribeAlarmHistory(ctx context.Context, params *cloudwatch.DescribeAlarmHistoryInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.DescribeAlarmHistoryOutput, error) {
+	return m.describeAlarmHistoryFunc(ctx, params, optFns...)
+}
+
+// Mock CloudTrail client
+type mockCloudTrailClient struct {
+	lookupEventsFunc func(ctx context.Context, params *cloudtrail.LookupEventsInput, optFns ...func(*cloudtrail.Options)) (*cloudtrail.LookupEventsOutput, error)
+}
+
+func (m *mockCloudTrailClient) LookupEvents(ctx context.Context, params *cloudtrail.LookupEventsInput, optFns ...func(*cloudtrail.Options)) (*cloudtrail.LookupEventsOutput, error) {
+	return m.lookupEventsFunc(ctx, params, optFns...)
+}
+
+// Mock ECS client
+type mockECSClient struct {
+	describeServicesFunc func(ctx context.Context, params *ecs.DescribeServicesInput, optFns ...func(*ecs.Options)) (*ecs.DescribeServicesOutput, error)
+}
+
+func (m *mockECSClient) DescribeServices(ctx context.Context, params *ecs.DescribeServicesInput, optFns ...func(*ecs.Options)) (*ecs.DescribeServicesOutput, error) {
+	return m.describeServicesFunc(ctx, params, optFns...)
+}
+
+// Mock RDS client
+type mockRDSClient struct {
+	describeEventsFunc func(ctx context.Context, params *rds.DescribeEventsInput, optFns ...func(*rds.Options)) (*rds.DescribeEventsOutput, error)
+}
+
+func (m *mockRDSClient) DescribeEvents(ctx context.Context, params *rds.DescribeEventsInput, optFns ...func(*rds.Options)) (*rds.DescribeEventsOutput, error) {
+	return m.describeEventsFunc(ctx, params, optFns...)
+}
+
+func TestRecentECSService(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	var historyAlarms []string
+	cloudwatchClient := &mockCloudWatchClient{
+		describeAlarmsFunc: func(ctx context.Context, params *cloudwatch.DescribeAlarmsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.DescribeAlarmsOutput, error) {
+			return &cloudwatch.DescribeAlarmsOutput{MetricAlarms: []cwtypes.MetricAlarm{
+				{AlarmName: aws.String("web-cpu"), Dimensions: []cwtypes.Dimension{
+					{Name: aws.String("ClusterName"), Value: aws.String("prod")},
+					{Name: aws.String("ServiceName"), Value: aws.String("web")},
+				}},
+				{AlarmName: aws.String("worker-cpu"), Dimensions: []cwtypes.Dimension{
+					{Name: aws.String("ClusterName"), Value: aws.String("prod")},
+					{Name: aws.String("ServiceName"), Value: aws.String("worker")},
+				}},
+				{AlarmName: aws.String("prod-memory"), Dimensions: []cwtypes.Dimension{
+					{Name: aws.String("ClusterName"), Value: aws.String("prod")},
+				}},
+			}}, nil
+		},
+		describeAlarmHistoryFunc: func(ctx context.Context, params *cloudwatch.DescribeAlarmHistoryInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.DescribeAlarmHistoryOutput, error) {
+			historyAlarms = append(historyAlarms, aws.ToString(params.AlarmName))
+			if !aws.ToTime(params.StartDate).Equal(now.Add(-Window)) {
+				t.Errorf("Expected the history of the last hour, got start %v", params.StartDate)
+			}
+			return &cloudwatch.DescribeAlarmHistoryOutput{AlarmHistoryItems: []cwtypes.AlarmHistoryItem{
+				{Timestamp: aws.Time(now.Add(-20 * time.Minute)), HistorySummary: aws.String("Alarm updated from OK to ALARM")},
+			}}, nil
+		},
+	}
+	cloudtrailClient := &mockCloudTrailClient{
+		lookupEventsFunc: func(ctx context.Context, params *cloudtrail.LookupEventsInput, optFns ...func(*cloudtrail.Options)) (*cloudtrail.LookupEventsOutput, error) {
+			if aws.ToString(params.LookupAttributes[0].AttributeValue) != "web" {
+				t.Errorf("Expected a lookup of web, got %q", aws.ToString(params.LookupAttributes[0].AttributeValue))
+			}
+			return &cloudtrail.LookupEventsOutput{Events: []cttypes.Event{
+				{EventName: aws.String("UpdateService"), Username: aws.String("deployer"), EventTime: aws.Time(now.Add(-30 * time.Minute)), ReadOnly: aws.String("false")},
+				{EventName: aws.String("DescribeServices"), Username: aws.String("viewer"), EventTime: aws.Time(now.Add(-5 * time.Minute)), ReadOnly: aws.String("true")},
+			}}, nil
+		},
+	}
+	ecsClient := &mockECSClient{
+		describeServicesFunc: func(ctx context.Context, params *ecs.DescribeServicesInput, optFns ...func(*ecs.Options)) (*ecs.DescribeServicesOutput, error) {
+			return &ecs.DescribeServicesOutput{Services: []ecstypes.Service{{Events: []ecstypes.ServiceEvent{
+				{CreatedAt: aws.Time(now.Add(-10 * time.Minute)), Message: aws.String("(service web) has reached a steady state.")},
+				{CreatedAt: aws.Time(now.Add(-3 * time.Hour)), Message: aws.String("(service web) has started 1 tasks.")},
+			}}}}, nil
+		},
+	}
+
+	client := NewClient(cloudwatchClient, cloudtrailClient, ecsClient, nil, nil)
+	events, errs := client.Recent(context.Background(), Resource{
+		TrailNames:      []string{"web"},
+		AlarmDimensions: map[string]string{"ClusterName": "prod", "ServiceName": "web"},
+		ECSCluster:      "prod",
+		ECSService:      "web",
+	})
+	if len(errs) > 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+
+	if len(historyAlarms) != 1 || historyAlarms[0] != "web-cpu" {
+		t.Errorf("Expected the history of web-cpu only, got %v", historyAlarms)
+	}
+
+	expected := []Event{
+		{Time: now.Add(-30 * time.Minute), Source: SourceCloudTrail, Message: "UpdateService by deployer"},
+		{Time: now.Add(-20 * time.Minute), Source: SourceAlarm, Message: "web-cpu: Alarm updated from OK to ALARM"},
+		{Time: now.Add(-10 * time.Minute), Source: SourceECS, Message: "(service web) has reached a steady state."},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %+v", len(expected), events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Errorf("Expected event %d to be %+v, got %+v", i, expected[i], events[i])
+		}
+	}
+}
+
+func TestRecentRDSInstance(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	rdsClient := &mockRDSClient{
+		describeEventsFunc: func(ctx context.Context, params *rds.DescribeEventsInput, optFns ...func(*rds.Options)) (*rds.DescribeEventsOutput, error) {
+			if aws.ToString(params.SourceIdentifier) != "db1" || params.SourceType != rdstypes.SourceTypeDbInstance {
+				t.Errorf("Expected the events of instance db1, got %+v", params)
+			}
+			if params.Marker == nil {
+				return &rds.DescribeEventsOutput{
+					Events: []rdstypes.Event{{Date: aws.Time(now.Add(-40 * time.Minute)), Message: aws.String("DB instance restarted")}},
+					Marker: aws.String("next"),
+				}, nil
+			}
+			return &rds.DescribeEventsOutput{
+				Events: []rdstypes.Event{{Date: aws.Time(now.Add(-35 * time.Minute)), Message: aws.String("Multi-AZ instance failover completed")}},
+			}, nil
+		},
+	}
+	cloudtrailClient := &mockCloudTrailClient{
+		lookupEventsFunc: func(ctx context.Context, params *cloudtrail.LookupEventsInput, optFns ...func(*cloudtrail.Options)) (*cloudtrail.LookupEventsOutput, error) {
+			return nil, errors.New("AccessDeniedException: not authorized to perform cloudtrail:LookupEvents")
+		},
+	}
+
+	client := NewClient(nil, cloudtrailClient, nil, rdsClient, nil)
+	events, errs := client.Recent(context.Background(), Resource{TrailNames: []string{"db1"}, RDSInstance: "db1"})
+
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "failed to look up CloudTrail events of db1") {
+		t.Errorf("Expected the CloudTrail error, got %v", errs)
+	}
+	if len(events) != 2 || events[1].Message != "Multi-AZ instance failover completed" || events[0].Source != SourceRDS {
+		t.Errorf("Expected both pages of RDS events, got %+v", events)
+	}
+}
+
+func TestRecentDedupesTrailNames(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	cloudtrailClient := &mockCloudTrailClient{
+		lookupEventsFunc: func(ctx context.Context, params *cloudtrail.LookupEventsInput, optFns ...func(*cloudtrail.Options)) (*cloudtrail.LookupEventsOutput, error) {
+			return &cloudtrail.LookupEventsOutput{Events: []cttypes.Event{
+				{EventName: aws.String("UpdateFunctionCode20150331v2"), Username: aws.String("ci"), EventTime: aws.Time(now.Add(-time.Minute))},
+			}}, nil
+		},
+	}
+
+	client := NewClient(nil, cloudtrailClient, nil, nil, nil)
+	events, errs := client.Recent(context.Background(), Resource{
+		TrailNames: []string{"orders", "arn:aws:lambda:us-east-1:123456789012:function:orders"},
+	})
+	if len(errs) > 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+	if len(events) != 1 {
+		t.Errorf("Expected the event recorded under both names once, got %+v", events)
+	}
+}
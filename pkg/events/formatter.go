@@ -0,0 +1,34 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// FormatEvents formats the events related to resource for terminal display,
+// the most recent last
+func FormatEvents(resource string, events []Event) string {
+	title := "RELATED EVENTS: " + resource
+	var output strings.Builder
+	output.WriteString(title + "\n")
+	output.WriteString(common.Rule(title, "=") + "\n\n")
+
+	if len(events) == 0 {
+		output.WriteString(fmt.Sprintf("%s No alarms, changes or events in the last hour\n", common.Symbol("✅")))
+		return output.String()
+	}
+
+	output.WriteString(fmt.Sprintf("%d events in the last hour, the most recent last:\n\n", len(events)))
+
+	width := 0
+	for _, event := range events {
+		width = max(width, len(event.Source))
+	}
+	for _, event := range events {
+		output.WriteString(fmt.Sprintf("%s  %-*s  %s\n", event.Time.Local().Format("15:04:05"), width, event.Source, event.Message))
+	}
+
+	return output.String()
+}
@@ -0,0 +1,30 @@
+package events
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatEvents(t *testing.T) {
+	if got := FormatEvents("web", nil); !strings.Contains(got, "No alarms, changes or events in the last hour") {
+		t.Errorf("Expected no events, got:\n%s", got)
+	}
+
+	at := time.Date(2024, 5, 10, 12, 3, 4, 0, time.Local)
+	events := []Event{
+		{Time: at, Source: SourceCloudTrail, Message: "UpdateService by deployer"},
+		{Time: at.Add(time.Minute), Source: SourceECS, Message: "(service web) has reached a steady state."},
+	}
+	output := FormatEvents("ECS service prod/web", events)
+	for _, expected := range []string{
+		"RELATED EVENTS: ECS service prod/web",
+		"2 events in the last hour, the most recent last:",
+		"12:03:04  CloudTrail  UpdateService by deployer\n",
+		"12:04:04  ECS         (service web) has reached a steady state.\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+// Package findings flags resources that cost money without doing much or
+// that would not survive a failure, in the spirit of the Trusted Advisor
+// checks, from the inventory of the other services and their CloudWatch
+// statistics of the past days.
+package findings
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+
+	"github.com/correctedcloud/aws-overview/pkg/alb"
+	"github.com/correctedcloud/aws-overview/pkg/cloudwatchmetrics"
+	"github.com/correctedcloud/aws-overview/pkg/common"
+	"github.com/correctedcloud/aws-overview/pkg/ec2"
+	"github.com/correctedcloud/aws-overview/pkg/rds"
+)
+
+// cloudwatchClientAPI defines the interface for the CloudWatch client
+type cloudwatchClientAPI interface {
+	GetMetricData(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error)
+}
+
+// Categories of findings
+const (
+	CategoryCost           = "Cost"
+	CategoryFaultTolerance = "Fault tolerance"
+)
+
+// Thresholds of the checks, as in the corresponding Trusted Advisor checks
+const (
+	// IdleDays is how many days a load balancer or DB instance is checked for traffic
+	IdleDays = 7
+	// IdleRequests is how many requests or new flows a load balancer serves
+	// over IdleDays at most to be idle
+	IdleRequests = 100
+
+	// UnderutilizedDays is how many days an EC2 instance is checked for load
+	UnderutilizedDays = 14
+	// UnderutilizedCPU and UnderutilizedNetwork are the highest average CPU
+	// in percent and network traffic in bytes of a quiet day
+	UnderutilizedCPU     = 10
+	UnderutilizedNetwork = 5 * 1000 * 1000
+	// UnderutilizedQuietDays is how many quiet days make an instance underutilized
+	UnderutilizedQuietDays = 4
+)
+
+// Finding is a resource flagged by a check
+type Finding struct {
+	Category string // CategoryCost or CategoryFaultTolerance
+	Check    string // e.g. "Idle load balancer"
+	Resource string // Name or ID of the resource
+	Detail   string // What the check found, e.g. "12 requests in 7 days"
+}
+
+// Inventory holds the resources collected by the other services
+type Inventory struct {
+	LoadBalancers []alb.LoadBalancerSummary
+	Instances     []ec2.InstanceSummary
+	DBInstances   []rds.DBInstanceSummary
+}
+
+// Client represents a findings client
+type Client struct {
+	batcher *cloudwatchmetrics.Batcher
+}
+
+// NewClient returns a new findings client whose CloudWatch calls run in
+// pool, which may be nil
+func NewClient(cloudwatchClient cloudwatchClientAPI, pool *common.Pool) *Client {
+	return &Client{batcher: cloudwatchmetrics.New(cloudwatchClient, pool)}
+}
+
+// check holds the CloudWatch queries whose results decide a finding about
+// a resource
+type check struct {
+	queries []cloudwatchmetrics.Query
+	decide  func(results []cloudwatchmetrics.Result) (Finding, bool)
+}
+
+// GetFindings returns the findings about the inventory, cost findings first.
+// Resources whose statistics fail to load are left out and the errors
+// returned alongside the findings about the others.
+func (c *Client) GetFindings(ctx context.Context, inventory Inventory) ([]Finding, []error) {
+	var findings []Finding
+	var checks []check
+
+	for _, loadBalancer := range inventory.LoadBalancers {
+		if lbCheck, ok := idleLoadBalancerCheck(loadBalancer); ok {
+			checks = append(checks, lbCheck)
+		}
+	}
+	for _, instance := range inventory.Instances {
+		if instance.State == "running" {
+			checks = append(checks, underutilizedInstanceCheck(instance))
+		}
+	}
+	for _, instance := range inventory.DBInstances {
+		if instance.Status != "available" {
+			continue
+		}
+		checks = append(checks, idleDBInstanceCheck(instance))
+		if !instance.MultiAZ {
+			findings = append(findings, Finding{
+				Category: CategoryFaultTolerance,
+				Check:    "Single-AZ DB instance",
+				Resource: instance.Identifier,
+				Detail:   "no standby takes over when its Availability Zone fails",
+			})
+		}
+	}
+
+	var queries []cloudwatchmetrics.Query
+	for _, check := range checks {
+		queries = append(queries, check.queries...)
+	}
+	results := c.batcher.Fetch(ctx, queries)
+
+	// A failed call fails all its queries, so report it once
+	var errs []error
+	var firstErr error
+	failed := 0
+	for _, check := range checks {
+		checkResults := results[:len(check.queries)]
+		results = results[len(check.queries):]
+
+		if err := resultsErr(checkResults); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			failed++
+			continue
+		}
+		if finding, ok := check.decide(checkResults); ok {
+			findings = append(findings, finding)
+		}
+	}
+	if firstErr != nil {
+		errs = append(errs, fmt.Errorf("failed to get CloudWatch statistics of %d resources: %w", failed, firstErr))
+	}
+
+	SortFindings(findings)
+	return findings, errs
+}
+
+// idleLoadBalancerCheck checks whether a load balancer served fewer than
+// IdleRequests requests, or new flows for network load balancers, in
+// IdleDays. Load balancers report no datapoints while idle.
+func idleLoadBalancerCheck(loadBalancer alb.LoadBalancerSummary) (check, bool) {
+	_, dimension, ok := strings.Cut(loadBalancer.ARN, ":loadbalancer/")
+	if !ok {
+		return check{}, false
+	}
+
+	namespace, metric, unit := "AWS/ApplicationELB", "RequestCount", "requests"
+	switch loadBalancer.Type {
+	case "application":
+	case "network":
+		namespace, metric, unit = "AWS/NetworkELB", "NewFlowCount", "new flows"
+	default:
+		return check{}, false
+	}
+
+	return check{
+		queries: []cloudwatchmetrics.Query{
+			dailyQuery(namespace, metric, "Sum", map[string]string{"LoadBalancer": dimension}, IdleDays),
+		},
+		decide: func(results []cloudwatchmetrics.Result) (Finding, bool) {
+			total := sum(results[0].Values)
+			if total >= IdleRequests {
+				return Finding{}, false
+			}
+			return Finding{
+				Category: CategoryCost,
+				Check:    "Idle load balancer",
+				Resource: loadBalancer.Name,
+				Detail:   fmt.Sprintf("%.0f %s in %d days", total, unit, IdleDays),
+			}, true
+		},
+	}, true
+}
+
+// underutilizedInstanceCheck checks whether an instance had at most
+// UnderutilizedCPU average CPU and UnderutilizedNetwork of traffic on
+// UnderutilizedQuietDays of the past UnderutilizedDays
+func underutilizedInstanceCheck(instance ec2.InstanceSummary) check {
+	dimensions := map[string]string{"InstanceId": instance.InstanceID}
+	name := instance.InstanceID
+	if instance.Name != "" {
+		name = instance.Name + " (" + instance.InstanceID + ")"
+	}
+
+	return check{
+		queries: []cloudwatchmetrics.Query{
+			dailyQuery("AWS/EC2", "CPUUtilization", "Average", dimensions, UnderutilizedDays),
+			dailyQuery("AWS/EC2", "NetworkIn", "Sum", dimensions, UnderutilizedDays),
+			dailyQuery("AWS/EC2", "NetworkOut", "Sum", dimensions, UnderutilizedDays),
+		},
+		decide: func(results []cloudwatchmetrics.Result) (Finding, bool) {
+			// Days without network datapoints had no traffic
+			traffic := make(map[time.Time]float64)
+			for _, network := range results[1:] {
+				for i, value := range network.Values {
+					traffic[network.Timestamps[i]] += value
+				}
+			}
+
+			cpu := results[0]
+			quiet := 0
+			for i, value := range cpu.Values {
+				if value <= UnderutilizedCPU && traffic[cpu.Timestamps[i]] <= UnderutilizedNetwork {
+					quiet++
+				}
+			}
+			if quiet < UnderutilizedQuietDays {
+				return Finding{}, false
+			}
+			return Finding{
+				Category: CategoryCost,
+				Check:    "Underutilized EC2 instance",
+				Resource: name,
+				Detail: fmt.Sprintf("%s with at most %d%% CPU and %d MB of traffic on %d of %d days",
+					instance.InstanceType, UnderutilizedCPU, UnderutilizedNetwork/1000/1000, quiet, len(cpu.Values)),
+			}, true
+		},
+	}
+}
+
+// idleDBInstanceCheck checks whether an instance had no connection in IdleDays
+func idleDBInstanceCheck(instance rds.DBInstanceSummary) check {
+	return check{
+		queries: []cloudwatchmetrics.Query{
+			dailyQuery("AWS/RDS", "DatabaseConnections", "Maximum", map[string]string{"DBInstanceIdentifier": instance.Identifier}, IdleDays),
+		},
+		decide: func(results []cloudwatchmetrics.Result) (Finding, bool) {
+			values := results[0].Values
+			// An instance without datapoints has not reported for a week,
+			// which is not the same as having no connections
+			if len(values) == 0 || maximum(values) > 0 {
+				return Finding{}, false
+			}
+			return Finding{
+				Category: CategoryCost,
+				Check:    "Idle DB instance",
+				Resource: instance.Identifier,
+				Detail:   fmt.Sprintf("no connections in %d days", IdleDays),
+			}, true
+		},
+	}
+}
+
+// dailyQuery returns a query of the daily statistic over the past days
+func dailyQuery(namespace, metric, stat string, dimensions map[string]string, days int) cloudwatchmetrics.Query {
+	return cloudwatchmetrics.Query{
+		Namespace:  namespace,
+		MetricName: metric,
+		Dimensions: dimensions,
+		Stat:       stat,
+		Period:     24 * time.Hour,
+		Window:     time.Duration(days) * 24 * time.Hour,
+	}
+}
+
+// SortFindings orders findings by category, cost first, then by check and resource
+func SortFindings(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Category != findings[j].Category {
+			return findings[i].Category == CategoryCost
+		}
+		if findings[i].Check != findings[j].Check {
+			return findings[i].Check < findings[j].Check
+		}
+		return findings[i].Resource < findings[j].Resource
+	})
+}
+
+// resultsErr returns the first error among results
+func resultsErr(results []cloudwatchmetrics.Result) error {
+	for _, result := range results {
+		if result.Err != nil {
+			return result.Err
+		}
+	}
+	return nil
+}
+
+func sum(values []float64) float64 {
+	total := 0.0
+	for _, value := range values {
+		total += value
+	}
+	return total
+}
+
+func maximum(values []float64) float64 {
+	highest := values[0]
+	for _, value := range values[1:] {
+		highest = max(highest, value)
+	}
+	return highest
+}
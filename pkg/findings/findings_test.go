@@ -0,0 +1,154 @@
+package findings
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+
+	"github.com/correctedcloud/aws-overview/pkg/alb"
+	"github.com/correctedcloud/aws-overview/pkg/ec2"
+	"github.com/correctedcloud/aws-overview/pkg/rds"
+)
+
+// Mock CloudWatch client serving daily series keyed by metric name and the
+// value of the first dimension
+type mockCloudWatchClient struct {
+	series map[string][]float64
+	err    error
+}
+
+func (m *mockCloudWatchClient) GetMetricData(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	output := &cloudwatch.GetMetricDataOutput{}
+	for _, query := range params.MetricDataQueries {
+		stat := query.MetricStat
+		key := aws.ToString(stat.Metric.MetricName) + "/" + aws.ToString(stat.Metric.Dimensions[0].Value)
+		values := m.series[key]
+
+		timestamps := make([]time.Time, len(values))
+		day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+		for i := range values {
+			timestamps[i] = day.AddDate(0, 0, i)
+		}
+		output.MetricDataResults = append(output.MetricDataResults, cwtypes.MetricDataResult{
+			Id:         query.Id,
+			Values:     values,
+			Timestamps: timestamps,
+		})
+	}
+	return output, nil
+}
+
+// repeat returns days copies of value
+func repeat(value float64, days int) []float64 {
+	values := make([]float64, days)
+	for i := range values {
+		values[i] = value
+	}
+	return values
+}
+
+func testInventory() Inventory {
+	return Inventory{
+		LoadBalancers: []alb.LoadBalancerSummary{
+			{Name: "web", Type: "application", ARN: "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/web/1"},
+			{Name: "legacy", Type: "application", ARN: "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/legacy/2"},
+			{Name: "tcp", Type: "network", ARN: "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/net/tcp/3"},
+			{Name: "inspection", Type: "gateway", ARN: "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/gwy/inspection/4"},
+		},
+		Instances: []ec2.InstanceSummary{
+			{InstanceID: "i-busy", Name: "api", InstanceType: "m5.large", State: "running"},
+			{InstanceID: "i-quiet", Name: "bastion", InstanceType: "t3.medium", State: "running"},
+			{InstanceID: "i-stopped", InstanceType: "t3.micro", State: "stopped"},
+		},
+		DBInstances: []rds.DBInstanceSummary{
+			{Identifier: "orders", Status: "available", MultiAZ: true},
+			{Identifier: "reports", Status: "available"},
+			{Identifier: "new", Status: "creating"},
+		},
+	}
+}
+
+func TestGetFindings(t *testing.T) {
+	client := NewClient(&mockCloudWatchClient{series: map[string][]float64{
+		"RequestCount/app/web/1":    repeat(5000, IdleDays),
+		"RequestCount/app/legacy/2": {3, 4},
+		"NewFlowCount/net/tcp/3":    repeat(10, IdleDays),
+
+		"CPUUtilization/i-busy":  repeat(60, UnderutilizedDays),
+		"NetworkIn/i-busy":       repeat(1e9, UnderutilizedDays),
+		"CPUUtilization/i-quiet": repeat(2, UnderutilizedDays),
+		"NetworkIn/i-quiet":      repeat(1e6, UnderutilizedDays),
+		"NetworkOut/i-quiet":     repeat(1e6, UnderutilizedDays),
+
+		"DatabaseConnections/orders":  repeat(40, IdleDays),
+		"DatabaseConnections/reports": repeat(0, IdleDays),
+	}}, nil)
+
+	findings, errs := client.GetFindings(context.Background(), testInventory())
+	if len(errs) != 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+
+	expected := []Finding{
+		{Category: CategoryCost, Check: "Idle DB instance", Resource: "reports", Detail: "no connections in 7 days"},
+		{Category: CategoryCost, Check: "Idle load balancer", Resource: "legacy", Detail: "7 requests in 7 days"},
+		{Category: CategoryCost, Check: "Idle load balancer", Resource: "tcp", Detail: "70 new flows in 7 days"},
+		{Category: CategoryCost, Check: "Underutilized EC2 instance", Resource: "bastion (i-quiet)", Detail: "t3.medium with at most 10% CPU and 5 MB of traffic on 14 of 14 days"},
+		{Category: CategoryFaultTolerance, Check: "Single-AZ DB instance", Resource: "reports", Detail: "no standby takes over when its Availability Zone fails"},
+	}
+	if len(findings) != len(expected) {
+		t.Fatalf("Expected %d findings, got %d: %+v", len(expected), len(findings), findings)
+	}
+	for i := range expected {
+		if findings[i] != expected[i] {
+			t.Errorf("Expected finding %d to be %+v, got %+v", i, expected[i], findings[i])
+		}
+	}
+}
+
+func TestGetFindingsWithoutDatapoints(t *testing.T) {
+	// Nothing reported: load balancers are idle, while instances and DB
+	// instances that did not report are not flagged
+	client := NewClient(&mockCloudWatchClient{}, nil)
+
+	findings, errs := client.GetFindings(context.Background(), testInventory())
+	if len(errs) != 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+
+	var checks []string
+	for _, finding := range findings {
+		checks = append(checks, finding.Check+" "+finding.Resource)
+	}
+	got := strings.Join(checks, ", ")
+	want := "Idle load balancer legacy, Idle load balancer tcp, Idle load balancer web, Single-AZ DB instance reports"
+	if got != want {
+		t.Errorf("Expected findings %q, got %q", want, got)
+	}
+}
+
+func TestGetFindingsError(t *testing.T) {
+	client := NewClient(&mockCloudWatchClient{err: errors.New("access denied")}, nil)
+
+	findings, errs := client.GetFindings(context.Background(), testInventory())
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %v", errs)
+	}
+	if !strings.Contains(errs[0].Error(), "of 7 resources") || !strings.Contains(errs[0].Error(), "access denied") {
+		t.Errorf("Expected the error to count the resources and wrap the cause, got %v", errs[0])
+	}
+	// The Multi-AZ check needs no statistics
+	if len(findings) != 1 || findings[0].Check != "Single-AZ DB instance" {
+		t.Errorf("Expected only the Single-AZ finding, got %+v", findings)
+	}
+}
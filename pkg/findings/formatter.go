@@ -0,0 +1,57 @@
+package findings
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// FormatFindings formats the findings for terminal display, grouped by
+// category and check
+func FormatFindings(findings []Finding) string {
+	var output strings.Builder
+	output.WriteString("FINDINGS\n")
+	output.WriteString(common.Rule("FINDINGS", "=") + "\n\n")
+
+	if len(findings) == 0 {
+		output.WriteString("No idle, underutilized or single-AZ resources found\n")
+		return output.String()
+	}
+
+	category, check := "", ""
+	for _, finding := range findings {
+		if finding.Category != category {
+			if category != "" {
+				output.WriteString("\n")
+			}
+			category, check = finding.Category, ""
+			output.WriteString(strings.ToUpper(category) + "\n")
+			output.WriteString(common.Rule(category, "-") + "\n")
+		}
+		if finding.Check != check {
+			check = finding.Check
+			output.WriteString(check + ":\n")
+		}
+		output.WriteString(fmt.Sprintf("  %s: %s\n", finding.Resource, finding.Detail))
+	}
+
+	return output.String()
+}
+
+// GetFindingsSummary returns a brief summary of the findings by category
+func GetFindingsSummary(findings []Finding) string {
+	if len(findings) == 0 {
+		return "No findings"
+	}
+
+	cost, faultTolerance := 0, 0
+	for _, finding := range findings {
+		if finding.Category == CategoryCost {
+			cost++
+		} else {
+			faultTolerance++
+		}
+	}
+	return fmt.Sprintf("%d cost, %d fault tolerance findings", cost, faultTolerance)
+}
@@ -0,0 +1,42 @@
+package findings
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatFindings(t *testing.T) {
+	output := FormatFindings([]Finding{
+		{Category: CategoryCost, Check: "Idle load balancer", Resource: "legacy", Detail: "7 requests in 7 days"},
+		{Category: CategoryCost, Check: "Idle load balancer", Resource: "tcp", Detail: "140 new flows in 7 days"},
+		{Category: CategoryFaultTolerance, Check: "Single-AZ DB instance", Resource: "reports", Detail: "no standby"},
+	})
+
+	for _, expected := range []string{
+		"FINDINGS",
+		"COST\n----\nIdle load balancer:\n  legacy: 7 requests in 7 days\n  tcp: 140 new flows in 7 days\n",
+		"\nFAULT TOLERANCE\n---------------\nSingle-AZ DB instance:\n  reports: no standby\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+
+	if output := FormatFindings(nil); !strings.Contains(output, "No idle, underutilized or single-AZ resources found") {
+		t.Errorf("Expected a message without findings, got:\n%s", output)
+	}
+}
+
+func TestGetFindingsSummary(t *testing.T) {
+	summary := GetFindingsSummary([]Finding{
+		{Category: CategoryCost},
+		{Category: CategoryCost},
+		{Category: CategoryFaultTolerance},
+	})
+	if summary != "2 cost, 1 fault tolerance findings" {
+		t.Errorf("Unexpected summary %q", summary)
+	}
+	if summary := GetFindingsSummary(nil); summary != "No findings" {
+		t.Errorf("Unexpected summary without findings %q", summary)
+	}
+}
@@ -0,0 +1,48 @@
+package hygiene
+
+import (
+	"fmt"
+	"strings"
+)
+
+// kindNouns are the nouns the summary counts each kind of resource with
+var kindNouns = []struct{ kind, noun string }{
+	{KindStoppedInstance, "stopped instance"},
+	{KindEmptyTargetGroup, "empty target group"},
+	{KindIdleQueue, "idle queue"},
+	{KindIdleDBInstance, "idle DB instance"},
+}
+
+// GetSummary returns a brief summary of the likely wasted resources by kind,
+// e.g. "3 likely wasted resources: 2 stopped instances, 1 idle queue"
+func GetSummary(resources []Resource) string {
+	if len(resources) == 0 {
+		return "No likely wasted resources"
+	}
+
+	counts := make(map[string]int)
+	for _, resource := range resources {
+		counts[resource.Kind]++
+	}
+	var parts []string
+	for _, kind := range kindNouns {
+		if counts[kind.kind] > 0 {
+			parts = append(parts, count(counts[kind.kind], kind.noun))
+		}
+	}
+	return count(len(resources), "likely wasted resource") + ": " + strings.Join(parts, ", ")
+}
+
+// FormatResource formats a likely wasted resource on one line, e.g.
+// "Idle SQS queue legacy-exports: no messages sent or received in 30 days"
+func FormatResource(resource Resource) string {
+	return fmt.Sprintf("%s %s: %s", resource.Kind, resource.Name, resource.Detail)
+}
+
+// count formats n of a noun, adding an s to all but one
+func count(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
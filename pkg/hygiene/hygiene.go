@@ -0,0 +1,272 @@
+// Package hygiene flags resources that are likely wasted, left stopped,
+// empty or without traffic for weeks, from the inventory of the other
+// services and their CloudWatch statistics.
+package hygiene
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+
+	"github.com/correctedcloud/aws-overview/pkg/alb"
+	"github.com/correctedcloud/aws-overview/pkg/cloudwatchmetrics"
+	"github.com/correctedcloud/aws-overview/pkg/common"
+	"github.com/correctedcloud/aws-overview/pkg/ec2"
+	"github.com/correctedcloud/aws-overview/pkg/rds"
+	"github.com/correctedcloud/aws-overview/pkg/sqs"
+)
+
+var timeNow = time.Now
+
+// cloudwatchClientAPI defines the interface for the CloudWatch client
+type cloudwatchClientAPI interface {
+	GetMetricData(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error)
+}
+
+// Kinds of likely wasted resources, in the order they are listed
+const (
+	KindStoppedInstance  = "Stopped EC2 instance"
+	KindEmptyTargetGroup = "Empty target group"
+	KindIdleQueue        = "Idle SQS queue"
+	KindIdleDBInstance   = "Idle DB instance"
+)
+
+var kindOrder = map[string]int{
+	KindStoppedInstance:  0,
+	KindEmptyTargetGroup: 1,
+	KindIdleQueue:        2,
+	KindIdleDBInstance:   3,
+}
+
+const (
+	// DefaultStoppedDays is how many days an EC2 instance is stopped before
+	// it is flagged, unless the client is given another threshold
+	DefaultStoppedDays = 30
+	// IdleDays is how many days a queue or DB instance is checked for traffic
+	IdleDays = 30
+)
+
+// Resource is a resource that is likely wasted
+type Resource struct {
+	Kind   string // e.g. KindStoppedInstance
+	Name   string // Name or ID of the resource
+	Detail string // Why it is flagged, e.g. "stopped for 45 days"
+}
+
+// Inventory holds the resources collected by the other services
+type Inventory struct {
+	Instances     []ec2.InstanceSummary
+	LoadBalancers []alb.LoadBalancerSummary
+	Queues        []sqs.QueueSummary
+	DBInstances   []rds.DBInstanceSummary
+}
+
+// Client represents a hygiene client
+type Client struct {
+	batcher     *cloudwatchmetrics.Batcher
+	stoppedDays int
+}
+
+// NewClient returns a new hygiene client flagging instances stopped for
+// stoppedDays, or DefaultStoppedDays when it is not positive. Its CloudWatch
+// calls run in pool, which may be nil.
+func NewClient(cloudwatchClient cloudwatchClientAPI, pool *common.Pool, stoppedDays int) *Client {
+	if stoppedDays <= 0 {
+		stoppedDays = DefaultStoppedDays
+	}
+	return &Client{batcher: cloudwatchmetrics.New(cloudwatchClient, pool), stoppedDays: stoppedDays}
+}
+
+// check holds the CloudWatch queries whose results decide whether a queue
+// or DB instance is idle
+type check struct {
+	queries  []cloudwatchmetrics.Query
+	resource Resource
+	idle     func(results []cloudwatchmetrics.Result) bool
+}
+
+// GetResources returns the likely wasted resources of the inventory by kind
+// and name. Queues and DB instances whose statistics fail to load are left
+// out and the error returned alongside the other resources.
+func (c *Client) GetResources(ctx context.Context, inventory Inventory) ([]Resource, []error) {
+	resources := append(c.stoppedInstances(inventory.Instances), emptyTargetGroups(inventory.LoadBalancers)...)
+
+	// Queues without traffic and DB instances without connections, checked
+	// together in as few CloudWatch calls as possible
+	var checks []check
+	for _, queue := range inventory.Queues {
+		// Dead-letter queues only receive messages when processing fails
+		if !queue.IsDeadLetterQueue() {
+			checks = append(checks, idleQueueCheck(queue))
+		}
+	}
+	for _, instance := range inventory.DBInstances {
+		if instance.Status == "available" {
+			checks = append(checks, idleDBInstanceCheck(instance))
+		}
+	}
+
+	var queries []cloudwatchmetrics.Query
+	for _, check := range checks {
+		queries = append(queries, check.queries...)
+	}
+	var results []cloudwatchmetrics.Result
+	if len(queries) > 0 {
+		results = c.batcher.Fetch(ctx, queries)
+	}
+
+	// A failed call fails all its queries, so report it once
+	var errs []error
+	var firstErr error
+	failed := 0
+	for _, check := range checks {
+		checkResults := results[:len(check.queries)]
+		results = results[len(check.queries):]
+
+		if err := resultsErr(checkResults); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			failed++
+			continue
+		}
+		if check.idle(checkResults) {
+			resources = append(resources, check.resource)
+		}
+	}
+	if firstErr != nil {
+		errs = append(errs, fmt.Errorf("failed to get CloudWatch statistics of %d resources: %w", failed, firstErr))
+	}
+
+	SortResources(resources)
+	return resources, errs
+}
+
+// stoppedInstances returns the instances stopped for at least the client's
+// threshold. Instances without a recorded stop time are left out.
+func (c *Client) stoppedInstances(instances []ec2.InstanceSummary) []Resource {
+	var resources []Resource
+	for _, instance := range instances {
+		if instance.State != "stopped" || instance.StoppedAt.IsZero() {
+			continue
+		}
+		days := int(timeNow().Sub(instance.StoppedAt).Hours() / 24)
+		if days < c.stoppedDays {
+			continue
+		}
+		name := instance.InstanceID
+		if instance.Name != "" {
+			name = instance.Name + " (" + instance.InstanceID + ")"
+		}
+		resources = append(resources, Resource{
+			Kind:   KindStoppedInstance,
+			Name:   name,
+			Detail: fmt.Sprintf("%s stopped for %d days, its volumes are still billed", instance.InstanceType, days),
+		})
+	}
+	return resources
+}
+
+// emptyTargetGroups returns the target groups of the load balancers without
+// registered targets
+func emptyTargetGroups(loadBalancers []alb.LoadBalancerSummary) []Resource {
+	var resources []Resource
+	for _, loadBalancer := range loadBalancers {
+		for _, targetGroup := range loadBalancer.TargetGroups {
+			if len(targetGroup.Targets) == 0 {
+				resources = append(resources, Resource{
+					Kind:   KindEmptyTargetGroup,
+					Name:   targetGroup.Name,
+					Detail: "no registered targets behind " + loadBalancer.Name,
+				})
+			}
+		}
+	}
+	return resources
+}
+
+// idleQueueCheck checks whether no messages were sent to or received from a
+// queue in IdleDays. Queues stop reporting while inactive, so a queue
+// without datapoints is idle.
+func idleQueueCheck(queue sqs.QueueSummary) check {
+	dimensions := map[string]string{"QueueName": queue.Name}
+	return check{
+		queries: []cloudwatchmetrics.Query{
+			dailyQuery("AWS/SQS", "NumberOfMessagesSent", "Sum", dimensions),
+			dailyQuery("AWS/SQS", "NumberOfMessagesReceived", "Sum", dimensions),
+		},
+		resource: Resource{
+			Kind:   KindIdleQueue,
+			Name:   queue.Name,
+			Detail: fmt.Sprintf("no messages sent or received in %d days", IdleDays),
+		},
+		idle: func(results []cloudwatchmetrics.Result) bool {
+			return maximum(results[0].Values) == 0 && maximum(results[1].Values) == 0
+		},
+	}
+}
+
+// idleDBInstanceCheck checks whether a DB instance had no connection in
+// IdleDays
+func idleDBInstanceCheck(instance rds.DBInstanceSummary) check {
+	return check{
+		queries: []cloudwatchmetrics.Query{
+			dailyQuery("AWS/RDS", "DatabaseConnections", "Maximum", map[string]string{"DBInstanceIdentifier": instance.Identifier}),
+		},
+		resource: Resource{
+			Kind:   KindIdleDBInstance,
+			Name:   instance.Identifier,
+			Detail: fmt.Sprintf("%s with no connections in %d days", instance.Engine, IdleDays),
+		},
+		idle: func(results []cloudwatchmetrics.Result) bool {
+			// An instance without datapoints has not reported, which is not
+			// the same as having no connections
+			values := results[0].Values
+			return len(values) > 0 && maximum(values) == 0
+		},
+	}
+}
+
+// dailyQuery returns a query of the daily statistic over IdleDays
+func dailyQuery(namespace, metric, stat string, dimensions map[string]string) cloudwatchmetrics.Query {
+	return cloudwatchmetrics.Query{
+		Namespace:  namespace,
+		MetricName: metric,
+		Dimensions: dimensions,
+		Stat:       stat,
+		Period:     24 * time.Hour,
+		Window:     IdleDays * 24 * time.Hour,
+	}
+}
+
+// SortResources orders resources by kind, then by name
+func SortResources(resources []Resource) {
+	sort.SliceStable(resources, func(i, j int) bool {
+		if resources[i].Kind != resources[j].Kind {
+			return kindOrder[resources[i].Kind] < kindOrder[resources[j].Kind]
+		}
+		return resources[i].Name < resources[j].Name
+	})
+}
+
+// maximum returns the highest of values, 0 when there are none
+func maximum(values []float64) float64 {
+	highest := 0.0
+	for _, value := range values {
+		highest = max(highest, value)
+	}
+	return highest
+}
+
+// resultsErr returns the first error among results
+func resultsErr(results []cloudwatchmetrics.Result) error {
+	for _, result := range results {
+		if result.Err != nil {
+			return result.Err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package hygiene
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+
+	"github.com/correctedcloud/aws-overview/pkg/alb"
+	"github.com/correctedcloud/aws-overview/pkg/ec2"
+	"github.com/correctedcloud/aws-overview/pkg/rds"
+	"github.com/correctedcloud/aws-overview/pkg/sqs"
+)
+
+// Mock CloudWatch client serving daily series keyed by metric name and the
+// value of the first dimension
+type mockCloudWatchClient struct {
+	series  map[string][]float64
+	err     error
+	queried []string
+}
+
+func (m *mockCloudWatchClient) GetMetricData(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	output := &cloudwatch.GetMetricDataOutput{}
+	for _, query := range params.MetricDataQueries {
+		stat := query.MetricStat
+		key := aws.ToString(stat.Metric.MetricName) + "/" + aws.ToString(stat.Metric.Dimensions[0].Value)
+		m.queried = append(m.queried, key)
+		values := m.series[key]
+
+		timestamps := make([]time.Time, len(values))
+		day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+		for i := range values {
+			timestamps[i] = day.AddDate(0, 0, i)
+		}
+		output.MetricDataResults = append(output.MetricDataResults, cwtypes.MetricDataResult{
+			Id:         query.Id,
+			Values:     values,
+			Timestamps: timestamps,
+		})
+	}
+	return output, nil
+}
+
+func testInventory(now time.Time) Inventory {
+	return Inventory{
+		Instances: []ec2.InstanceSummary{
+			{InstanceID: "i-1", Name: "old-worker", InstanceType: "c5.xlarge", State: "stopped", StoppedAt: now.Add(-45 * 24 * time.Hour)},
+			{InstanceID: "i-2", Name: "paused", State: "stopped", StoppedAt: now.Add(-2 * 24 * time.Hour)},
+			{InstanceID: "i-3", State: "stopped"},
+			{InstanceID: "i-4", Name: "web", State: "running"},
+		},
+		LoadBalancers: []alb.LoadBalancerSummary{{
+			Name: "web",
+			TargetGroups: []alb.TargetGroupSummary{
+				{Name: "web-blue", Targets: []alb.TargetSummary{{ID: "i-4"}}},
+				{Name: "web-green"},
+			},
+		}},
+		Queues: []sqs.QueueSummary{
+			{Name: "orders"},
+			{Name: "legacy-exports"},
+			{Name: "orders-dlq", SourceQueues: []string{"orders"}},
+		},
+		DBInstances: []rds.DBInstanceSummary{
+			{Identifier: "orders-db", Engine: "postgres", Status: "available"},
+			{Identifier: "reports-db", Engine: "mysql", Status: "available"},
+			{Identifier: "new-db", Engine: "mysql", Status: "available"},
+			{Identifier: "archive-db", Engine: "mysql", Status: "stopped"},
+		},
+	}
+}
+
+func TestGetResources(t *testing.T) {
+	now := time.Date(2026, 10, 31, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	cloudwatchClient := &mockCloudWatchClient{series: map[string][]float64{
+		"NumberOfMessagesSent/orders":         repeat(120, 30),
+		"NumberOfMessagesReceived/orders":     repeat(120, 30),
+		"NumberOfMessagesSent/legacy-exports": repeat(0, 30),
+		"DatabaseConnections/orders-db":       repeat(25, 30),
+		"DatabaseConnections/reports-db":      repeat(0, 30),
+	}}
+
+	resources, errs := NewClient(cloudwatchClient, nil, 0).GetResources(context.Background(), testInventory(now))
+	if len(errs) > 0 {
+		t.Fatalf("GetResources() errors = %v", errs)
+	}
+
+	var flagged []string
+	for _, resource := range resources {
+		flagged = append(flagged, FormatResource(resource))
+	}
+	expected := []string{
+		"Stopped EC2 instance old-worker (i-1): c5.xlarge stopped for 45 days, its volumes are still billed",
+		"Empty target group web-green: no registered targets behind web",
+		"Idle SQS queue legacy-exports: no messages sent or received in 30 days",
+		"Idle DB instance reports-db: mysql with no connections in 30 days",
+	}
+	if strings.Join(flagged, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected resources:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(flagged, "\n"))
+	}
+
+	for _, key := range cloudwatchClient.queried {
+		if strings.HasSuffix(key, "/orders-dlq") || strings.HasSuffix(key, "/archive-db") {
+			t.Errorf("Expected dead-letter queues and stopped DB instances not to be checked, got %s", key)
+		}
+	}
+
+	if summary := GetSummary(resources); summary != "4 likely wasted resources: 1 stopped instance, 1 empty target group, 1 idle queue, 1 idle DB instance" {
+		t.Errorf("Unexpected summary %q", summary)
+	}
+	if summary := GetSummary(nil); summary != "No likely wasted resources" {
+		t.Errorf("Unexpected summary without resources %q", summary)
+	}
+}
+
+func TestGetResourcesStoppedDays(t *testing.T) {
+	now := time.Date(2026, 10, 31, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	inventory := Inventory{Instances: testInventory(now).Instances}
+	resources, errs := NewClient(&mockCloudWatchClient{}, nil, 1).GetResources(context.Background(), inventory)
+	if len(errs) > 0 || len(resources) != 2 || resources[0].Name != "old-worker (i-1)" || resources[1].Name != "paused (i-2)" {
+		t.Errorf("Expected both instances stopped for a day or more, got %v and %v", resources, errs)
+	}
+}
+
+func TestGetResourcesError(t *testing.T) {
+	now := time.Date(2026, 10, 31, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	cloudwatchClient := &mockCloudWatchClient{err: errors.New("access denied")}
+	resources, errs := NewClient(cloudwatchClient, nil, 0).GetResources(context.Background(), testInventory(now))
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "5 resources") {
+		t.Errorf("Expected a single error for the queues and DB instances, got %v", errs)
+	}
+	// The checks of the inventory alone do not need CloudWatch
+	if len(resources) != 2 || resources[0].Kind != KindStoppedInstance || resources[1].Kind != KindEmptyTargetGroup {
+		t.Errorf("Expected the stopped instance and the empty target group, got %v", resources)
+	}
+}
+
+// repeat returns days copies of value
+func repeat(value float64, days int) []float64 {
+	values := make([]float64, days)
+	for i := range values {
+		values[i] = value
+	}
+	return values
+}
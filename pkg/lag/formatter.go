@@ -0,0 +1,34 @@
+package lag
+
+import (
+	"fmt"
+	"time"
+)
+
+// FormatIndicator formats the lag of an indicator and its source, e.g.
+// "2m 30s (orders-stream)", or "none" when nothing was measured
+func FormatIndicator(indicator Indicator) string {
+	if indicator.Consumers == 0 {
+		return "none"
+	}
+	if indicator.Source == "" {
+		return "0s"
+	}
+	return fmt.Sprintf("%s (%s)", formatLag(indicator.Lag), indicator.Source)
+}
+
+// formatLag formats a lag as a short human-readable duration
+func formatLag(lag time.Duration) string {
+	lag = lag.Truncate(time.Second)
+	hours := int(lag.Hours())
+	minutes := int(lag.Minutes()) % 60
+	seconds := int(lag.Seconds()) % 60
+
+	switch {
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	case minutes > 0:
+		return fmt.Sprintf("%dm %ds", minutes, seconds)
+	}
+	return fmt.Sprintf("%ds", seconds)
+}
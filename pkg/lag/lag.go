@@ -0,0 +1,252 @@
+// Package lag measures how far the consumers of Kinesis streams, DynamoDB
+// streams and SQS queues are behind, so that stalled pipelines stand out.
+package lag
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+
+	"github.com/correctedcloud/aws-overview/pkg/cloudwatchmetrics"
+	"github.com/correctedcloud/aws-overview/pkg/common"
+	"github.com/correctedcloud/aws-overview/pkg/sqs"
+)
+
+// WarningThreshold is the lag above which a pipeline is flagged as stalled
+const WarningThreshold = 5 * time.Minute
+
+// window is how far back the latest lag datapoint is looked for
+const window = 15 * time.Minute
+
+// cloudwatchClientAPI defines the interface for the CloudWatch client
+type cloudwatchClientAPI interface {
+	GetMetricData(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error)
+	ListMetrics(ctx context.Context, params *cloudwatch.ListMetricsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.ListMetricsOutput, error)
+}
+
+// lambdaClientAPI defines the interface for the Lambda client
+type lambdaClientAPI interface {
+	ListEventSourceMappings(ctx context.Context, params *lambda.ListEventSourceMappingsInput, optFns ...func(*lambda.Options)) (*lambda.ListEventSourceMappingsOutput, error)
+}
+
+// Client represents a client measuring consumer lag
+type Client struct {
+	cloudwatchClient cloudwatchClientAPI
+	lambdaClient     lambdaClientAPI
+	pool             *common.Pool
+}
+
+// Indicator is the highest lag among the consumers of one kind of pipeline
+type Indicator struct {
+	Lag       time.Duration
+	Source    string // Stream, function or queue with the highest lag, empty when none lags
+	Consumers int    // Number of streams, functions or queues measured
+}
+
+// Stalled reports whether the lag is above WarningThreshold
+func (i Indicator) Stalled() bool {
+	return i.Lag > WarningThreshold
+}
+
+// Indicators holds the lag of the Kinesis and DynamoDB stream pipelines
+type Indicators struct {
+	Kinesis  Indicator // Iterator age of the streams' GetRecords calls
+	DynamoDB Indicator // Iterator age of the Lambda functions consuming DynamoDB streams
+}
+
+// NewClient returns a new lag client whose calls run in pool, which may be nil
+func NewClient(cloudwatchClient cloudwatchClientAPI, lambdaClient lambdaClientAPI, pool *common.Pool) *Client {
+	return &Client{
+		cloudwatchClient: cloudwatchClient,
+		lambdaClient:     lambdaClient,
+		pool:             pool,
+	}
+}
+
+// GetIndicators measures the latest maximum iterator age of the Kinesis
+// streams read in the past hours and of the functions consuming DynamoDB
+// streams. An indicator that fails to load is left empty and its error
+// returned alongside the other.
+func (c *Client) GetIndicators(ctx context.Context) (Indicators, []error) {
+	var indicators Indicators
+	var errs []error
+
+	streams, err := c.listKinesisStreams(ctx)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	functions, err := c.listDynamoDBConsumers(ctx)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	queries := make([]cloudwatchmetrics.Query, 0, len(streams)+len(functions))
+	for _, stream := range streams {
+		queries = append(queries, iteratorAgeQuery("AWS/Kinesis", "GetRecords.IteratorAgeMilliseconds", "StreamName", stream))
+	}
+	for _, function := range functions {
+		queries = append(queries, iteratorAgeQuery("AWS/Lambda", "IteratorAge", "FunctionName", function))
+	}
+	results := cloudwatchmetrics.New(c.cloudwatchClient, c.pool).Fetch(ctx, queries)
+
+	var kinesisErr, dynamoDBErr error
+	indicators.Kinesis, kinesisErr = maxIndicator(streams, results[:len(streams)])
+	indicators.DynamoDB, dynamoDBErr = maxIndicator(functions, results[len(streams):])
+	if kinesisErr != nil {
+		errs = append(errs, fmt.Errorf("failed to get Kinesis iterator age: %w", kinesisErr))
+	}
+	if dynamoDBErr != nil {
+		errs = append(errs, fmt.Errorf("failed to get DynamoDB stream iterator age: %w", dynamoDBErr))
+	}
+
+	return indicators, errs
+}
+
+// FromQueues returns the age of the oldest message among the queues, leaving
+// out dead-letter queues, whose messages are expected to grow old
+func FromQueues(queues []sqs.QueueSummary) Indicator {
+	var indicator Indicator
+	for _, queue := range queues {
+		if queue.IsDeadLetterQueue() {
+			continue
+		}
+		indicator.Consumers++
+		if age := queue.CurrentOldestMessageAge(); age > indicator.Lag {
+			indicator.Lag = age
+			indicator.Source = queue.Name
+		}
+	}
+	return indicator
+}
+
+// listKinesisStreams returns the streams that reported an iterator age in
+// the past 3 hours, which leaves out streams nobody reads
+func (c *Client) listKinesisStreams(ctx context.Context) ([]string, error) {
+	var streams []string
+	var nextToken *string
+
+	for {
+		var result *cloudwatch.ListMetricsOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.cloudwatchClient.ListMetrics(ctx, &cloudwatch.ListMetricsInput{
+				Namespace:      aws.String("AWS/Kinesis"),
+				MetricName:     aws.String("GetRecords.IteratorAgeMilliseconds"),
+				RecentlyActive: cwtypes.RecentlyActivePt3h,
+				NextToken:      nextToken,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list Kinesis streams: %w", err)
+		}
+
+		for _, metric := range result.Metrics {
+			// Stream-level metrics have the stream name as their only dimension
+			if len(metric.Dimensions) == 1 && aws.ToString(metric.Dimensions[0].Name) == "StreamName" {
+				streams = append(streams, aws.ToString(metric.Dimensions[0].Value))
+			}
+		}
+
+		if result.NextToken == nil {
+			return streams, nil
+		}
+		nextToken = result.NextToken
+	}
+}
+
+// listDynamoDBConsumers returns the functions with an enabled event source
+// mapping reading a DynamoDB stream
+func (c *Client) listDynamoDBConsumers(ctx context.Context) ([]string, error) {
+	var functions []string
+	seen := make(map[string]bool)
+	var marker *string
+
+	for {
+		var result *lambda.ListEventSourceMappingsOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.lambdaClient.ListEventSourceMappings(ctx, &lambda.ListEventSourceMappingsInput{
+				Marker: marker,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list event source mappings: %w", err)
+		}
+
+		for _, mapping := range result.EventSourceMappings {
+			if !isEnabledDynamoDBMapping(mapping) {
+				continue
+			}
+			function := functionName(aws.ToString(mapping.FunctionArn))
+			if !seen[function] {
+				seen[function] = true
+				functions = append(functions, function)
+			}
+		}
+
+		if result.NextMarker == nil {
+			return functions, nil
+		}
+		marker = result.NextMarker
+	}
+}
+
+// isEnabledDynamoDBMapping reports whether a mapping reads a DynamoDB stream
+func isEnabledDynamoDBMapping(mapping lambdatypes.EventSourceMappingConfiguration) bool {
+	return strings.HasPrefix(aws.ToString(mapping.EventSourceArn), "arn:aws:dynamodb:") &&
+		aws.ToString(mapping.State) == "Enabled"
+}
+
+// functionName returns the name of a function from its ARN, e.g.
+// arn:aws:lambda:us-east-1:123456789012:function:sync-orders:live
+func functionName(arn string) string {
+	parts := strings.Split(arn, ":")
+	if len(parts) < 7 {
+		return arn
+	}
+	return parts[6]
+}
+
+// iteratorAgeQuery returns the query of the maximum iterator age of a
+// resource, in milliseconds
+func iteratorAgeQuery(namespace, metricName, dimension, value string) cloudwatchmetrics.Query {
+	return cloudwatchmetrics.Query{
+		Namespace:  namespace,
+		MetricName: metricName,
+		Dimensions: map[string]string{dimension: value},
+		Stat:       "Maximum",
+		Period:     time.Minute,
+		Window:     window,
+	}
+}
+
+// maxIndicator returns the highest latest iterator age among the sources,
+// and the first error of their queries
+func maxIndicator(sources []string, results []cloudwatchmetrics.Result) (Indicator, error) {
+	indicator := Indicator{Consumers: len(sources)}
+	var err error
+	for i, result := range results {
+		if result.Err != nil {
+			if err == nil {
+				err = result.Err
+			}
+			continue
+		}
+		if len(result.Values) == 0 {
+			continue
+		}
+		age := time.Duration(result.Values[len(result.Values)-1]) * time.Millisecond
+		if age > indicator.Lag {
+			indicator.Lag = age
+			indicator.Source = sources[i]
+		}
+	}
+	return indicator, err
+}
@@ -0,0 +1,130 @@
+package lag
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+
+	"github.com/correctedcloud/aws-overview/pkg/sqs"
+)
+
+// Mock CloudWatch client serving the latest iterator age of each resource
+type mockCloudWatchClient struct {
+	streams []string
+	ages    map[string]float64 // Milliseconds by dimension value
+}
+
+func (m *mockCloudWatchClient) ListMetrics(ctx context.Context, params *cloudwatch.ListMetricsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.ListMetricsOutput, error) {
+	output := &cloudwatch.ListMetricsOutput{}
+	for _, stream := range m.streams {
+		output.Metrics = append(output.Metrics, cwtypes.Metric{Dimensions: []cwtypes.Dimension{
+			{Name: aws.String("StreamName"), Value: aws.String(stream)},
+		}})
+	}
+	// Shard-level metrics are left out
+	output.Metrics = append(output.Metrics, cwtypes.Metric{Dimensions: []cwtypes.Dimension{
+		{Name: aws.String("StreamName"), Value: aws.String("orders")},
+		{Name: aws.String("ShardId"), Value: aws.String("shardId-000000000000")},
+	}})
+	return output, nil
+}
+
+func (m *mockCloudWatchClient) GetMetricData(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+	output := &cloudwatch.GetMetricDataOutput{}
+	for _, query := range params.MetricDataQueries {
+		result := cwtypes.MetricDataResult{Id: query.Id, StatusCode: cwtypes.StatusCodeComplete}
+		if age, ok := m.ages[aws.ToString(query.MetricStat.Metric.Dimensions[0].Value)]; ok {
+			result.Values = []float64{0, age}
+			result.Timestamps = []time.Time{time.Now().Add(-time.Minute), time.Now()}
+		}
+		output.MetricDataResults = append(output.MetricDataResults, result)
+	}
+	return output, nil
+}
+
+// Mock Lambda client serving one mapping per page
+type mockLambdaClient struct {
+	mappings []lambdatypes.EventSourceMappingConfiguration
+	err      error
+}
+
+func (m *mockLambdaClient) ListEventSourceMappings(ctx context.Context, params *lambda.ListEventSourceMappingsInput, optFns ...func(*lambda.Options)) (*lambda.ListEventSourceMappingsOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	start := 0
+	if params.Marker != nil {
+		start = int(aws.ToString(params.Marker)[0] - '0')
+	}
+	output := &lambda.ListEventSourceMappingsOutput{EventSourceMappings: m.mappings[start : start+1]}
+	if start+1 < len(m.mappings) {
+		output.NextMarker = aws.String(string(rune('0' + start + 1)))
+	}
+	return output, nil
+}
+
+func mapping(source, function, state string) lambdatypes.EventSourceMappingConfiguration {
+	return lambdatypes.EventSourceMappingConfiguration{
+		EventSourceArn: aws.String(source),
+		FunctionArn:    aws.String(function),
+		State:          aws.String(state),
+	}
+}
+
+func TestGetIndicators(t *testing.T) {
+	cloudwatchClient := &mockCloudWatchClient{
+		streams: []string{"orders", "clicks"},
+		ages:    map[string]float64{"orders": 1500, "clicks": 150000, "sync-orders": 4000, "disabled-sync": 999999},
+	}
+	lambdaClient := &mockLambdaClient{mappings: []lambdatypes.EventSourceMappingConfiguration{
+		mapping("arn:aws:dynamodb:us-east-1:123456789012:table/orders/stream/2024-01-01T00:00:00.000", "arn:aws:lambda:us-east-1:123456789012:function:sync-orders:live", "Enabled"),
+		mapping("arn:aws:dynamodb:us-east-1:123456789012:table/users/stream/2024-01-01T00:00:00.000", "arn:aws:lambda:us-east-1:123456789012:function:disabled-sync", "Disabled"),
+		mapping("arn:aws:sqs:us-east-1:123456789012:orders", "arn:aws:lambda:us-east-1:123456789012:function:queue-worker", "Enabled"),
+	}}
+
+	indicators, errs := NewClient(cloudwatchClient, lambdaClient, nil).GetIndicators(context.Background())
+	if len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+
+	if indicators.Kinesis != (Indicator{Lag: 150 * time.Second, Source: "clicks", Consumers: 2}) {
+		t.Errorf("Unexpected Kinesis indicator %+v", indicators.Kinesis)
+	}
+	if indicators.DynamoDB != (Indicator{Lag: 4 * time.Second, Source: "sync-orders", Consumers: 1}) {
+		t.Errorf("Unexpected DynamoDB indicator %+v", indicators.DynamoDB)
+	}
+	if FormatIndicator(indicators.Kinesis) != "2m 30s (clicks)" {
+		t.Errorf("Unexpected Kinesis format %q", FormatIndicator(indicators.Kinesis))
+	}
+}
+
+func TestGetIndicatorsPartialFailure(t *testing.T) {
+	cloudwatchClient := &mockCloudWatchClient{streams: []string{"orders"}, ages: map[string]float64{"orders": 400000}}
+	lambdaClient := &mockLambdaClient{err: errors.New("AccessDeniedException")}
+
+	indicators, errs := NewClient(cloudwatchClient, lambdaClient, nil).GetIndicators(context.Background())
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %v", errs)
+	}
+	if !indicators.Kinesis.Stalled() || FormatIndicator(indicators.DynamoDB) != "none" {
+		t.Errorf("Unexpected indicators %+v", indicators)
+	}
+}
+
+func TestFromQueues(t *testing.T) {
+	indicator := FromQueues([]sqs.QueueSummary{
+		{Name: "orders", OldestMessageAge: []float64{30}, DeadLetterQueue: "orders-dlq"},
+		{Name: "orders-dlq", OldestMessageAge: []float64{86400}, SourceQueues: []string{"orders"}},
+		{Name: "emails", OldestMessageAge: []float64{600}},
+	})
+	if indicator != (Indicator{Lag: 10 * time.Minute, Source: "emails", Consumers: 2}) {
+		t.Errorf("Unexpected indicator %+v", indicator)
+	}
+}
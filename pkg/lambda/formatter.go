@@ -0,0 +1,102 @@
+package lambda
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// FormatFunctions formats the functions for terminal display, marking the
+// function at index selected (-1 for none)
+func FormatFunctions(summaries []FunctionSummary, selected int) string {
+	if len(summaries) == 0 {
+		return "No Lambda functions found"
+	}
+
+	var output strings.Builder
+	output.WriteString("LAMBDA FUNCTIONS\n")
+	output.WriteString(common.Rule("LAMBDA FUNCTIONS", "=") + "\n\n")
+
+	for i, function := range summaries {
+		marker := "  "
+		if i == selected {
+			marker = "> "
+		}
+
+		runtime := function.Runtime
+		if runtime == "" {
+			runtime = "container image"
+		}
+
+		output.WriteString(fmt.Sprintf("%s%s %s (%s, %d MB, %ds timeout)", marker, common.Symbol("⚡"), function.Name, runtime, function.MemorySize, function.Timeout))
+		if function.State != "" && function.State != "Active" {
+			output.WriteString(fmt.Sprintf(" [%s]", function.State))
+		}
+		output.WriteString("\n")
+	}
+
+	return output.String()
+}
+
+// GetFunctionsSummary returns a brief summary of the functions
+func GetFunctionsSummary(summaries []FunctionSummary) string {
+	inactive := 0
+	for _, function := range summaries {
+		if function.State != "" && function.State != "Active" {
+			inactive++
+		}
+	}
+
+	summary := fmt.Sprintf("%d functions", len(summaries))
+	if inactive > 0 {
+		summary += fmt.Sprintf(", %d not active", inactive)
+	}
+	return summary
+}
+
+// FormatInvocation formats the response, duration and log tail of an invocation
+func FormatInvocation(result InvocationResult) string {
+	title := "INVOCATION: " + result.Function
+
+	var output strings.Builder
+	output.WriteString(title + "\n")
+	output.WriteString(common.Rule(title, "-") + "\n")
+
+	status := fmt.Sprintf("%s Status %d", common.Symbol("✅"), result.StatusCode)
+	if result.Failed() {
+		status = fmt.Sprintf("%s Status %d, function error: %s", common.Symbol("❌"), result.StatusCode, result.FunctionError)
+	}
+	output.WriteString(fmt.Sprintf("%s in %s", status, result.Duration.Round(time.Millisecond)))
+	if result.Billed > 0 {
+		output.WriteString(fmt.Sprintf(" (billed %s)", result.Billed))
+	}
+	output.WriteString("\n\n")
+
+	output.WriteString("Response:\n")
+	output.WriteString(indent(prettyJSON(result.Payload)) + "\n")
+
+	if result.LogTail != "" {
+		output.WriteString("Log tail:\n")
+		output.WriteString(indent(strings.TrimRight(result.LogTail, "\n")) + "\n")
+	}
+
+	return output.String()
+}
+
+// prettyJSON indents a JSON payload, returning other payloads unchanged
+func prettyJSON(payload string) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(payload), "", "  "); err != nil {
+		return payload
+	}
+	return buf.String()
+}
+
+// indent prefixes each line of s with two spaces
+func indent(s string) string {
+	return "  " + strings.ReplaceAll(s, "\n", "\n  ")
+}
@@ -0,0 +1,58 @@
+package lambda
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatFunctions(t *testing.T) {
+	summaries := []FunctionSummary{
+		{Name: "audit", Runtime: "python3.12", MemorySize: 128, Timeout: 3, State: "Active"},
+		{Name: "resize", MemorySize: 1024, Timeout: 30, State: "Failed"},
+	}
+
+	output := FormatFunctions(summaries, 1)
+
+	for _, expected := range []string{
+		"LAMBDA FUNCTIONS",
+		"  ⚡ audit (python3.12, 128 MB, 3s timeout)\n",
+		"> ⚡ resize (container image, 1024 MB, 30s timeout) [Failed]",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain '%s', got:\n%s", expected, output)
+		}
+	}
+
+	if output := FormatFunctions(nil, -1); output != "No Lambda functions found" {
+		t.Errorf("Expected an empty message, got '%s'", output)
+	}
+	if summary := GetFunctionsSummary(summaries); summary != "2 functions, 1 not active" {
+		t.Errorf("Unexpected summary '%s'", summary)
+	}
+}
+
+func TestFormatInvocation(t *testing.T) {
+	result := InvocationResult{
+		Function:      "resize",
+		StatusCode:    200,
+		FunctionError: "Unhandled",
+		Payload:       `{"errorMessage":"boom"}`,
+		Duration:      1234567 * time.Microsecond,
+		Billed:        1200 * time.Millisecond,
+		LogTail:       "START\nREPORT\n",
+	}
+
+	output := FormatInvocation(result)
+
+	for _, expected := range []string{
+		"INVOCATION: resize",
+		"❌ Status 200, function error: Unhandled in 1.235s (billed 1.2s)",
+		"Response:\n  {\n    \"errorMessage\": \"boom\"\n  }\n",
+		"Log tail:\n  START\n  REPORT\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain '%s', got:\n%s", expected, output)
+		}
+	}
+}
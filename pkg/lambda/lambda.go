@@ -0,0 +1,177 @@
+package lambda
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// lambdaClientAPI defines the interface for the Lambda client
+type lambdaClientAPI interface {
+	ListFunctions(ctx context.Context, params *lambda.ListFunctionsInput, optFns ...func(*lambda.Options)) (*lambda.ListFunctionsOutput, error)
+	Invoke(ctx context.Context, params *lambda.InvokeInput, optFns ...func(*lambda.Options)) (*lambda.InvokeOutput, error)
+}
+
+// Client represents a Lambda client
+type Client struct {
+	lambdaClient lambdaClientAPI
+	pool         *common.Pool
+}
+
+// FunctionSummary represents a Lambda function
+type FunctionSummary struct {
+	Name         string
+	ARN          string
+	Runtime      string // Empty for container image functions
+	MemorySize   int32  // MB
+	Timeout      int32  // Seconds
+	State        string
+	LastModified string
+	LogGroup     string // Custom log group, empty for the default one, see LogGroupName
+}
+
+// LogGroupName returns the CloudWatch Logs group the function logs to
+func (f FunctionSummary) LogGroupName() string {
+	if f.LogGroup != "" {
+		return f.LogGroup
+	}
+	return "/aws/lambda/" + f.Name
+}
+
+// InvocationResult represents the outcome of a synchronous invocation
+type InvocationResult struct {
+	Function      string
+	StatusCode    int32
+	FunctionError string // e.g. "Unhandled" when the function threw an error
+	Payload       string // Response payload, or the error details on a function error
+	Duration      time.Duration
+	Billed        time.Duration // Billed duration from the REPORT log line, zero when not logged
+	LogTail       string        // Last 4 KB of the invocation's logs
+}
+
+// Failed reports whether the function returned an error
+func (r InvocationResult) Failed() bool {
+	return r.FunctionError != ""
+}
+
+// NewClient returns a new Lambda client whose calls run in pool, which may be nil
+func NewClient(lambdaClient lambdaClientAPI, pool *common.Pool) *Client {
+	return &Client{
+		lambdaClient: lambdaClient,
+		pool:         pool,
+	}
+}
+
+// GetFunctions returns all Lambda functions sorted by name, following the pagination markers
+func (c *Client) GetFunctions(ctx context.Context) ([]FunctionSummary, error) {
+	var summaries []FunctionSummary
+	var marker *string
+
+	for {
+		var result *lambda.ListFunctionsOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.lambdaClient.ListFunctions(ctx, &lambda.ListFunctionsInput{
+				Marker: marker,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list functions: %w", err)
+		}
+
+		for _, function := range result.Functions {
+			summaries = append(summaries, newFunctionSummary(function))
+		}
+
+		marker = result.NextMarker
+		if marker == nil {
+			break
+		}
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Name < summaries[j].Name
+	})
+
+	return summaries, nil
+}
+
+// newFunctionSummary summarizes a function's configuration
+func newFunctionSummary(function types.FunctionConfiguration) FunctionSummary {
+	summary := FunctionSummary{
+		Name:         aws.ToString(function.FunctionName),
+		ARN:          aws.ToString(function.FunctionArn),
+		Runtime:      string(function.Runtime),
+		MemorySize:   aws.ToInt32(function.MemorySize),
+		Timeout:      aws.ToInt32(function.Timeout),
+		State:        string(function.State),
+		LastModified: aws.ToString(function.LastModified),
+	}
+	if function.LoggingConfig != nil {
+		summary.LogGroup = aws.ToString(function.LoggingConfig.LogGroup)
+	}
+	return summary
+}
+
+// Invoke runs a function synchronously with payload and returns its
+// response together with the tail of its logs. It is not retried on
+// throttling, as the function may have side effects.
+func (c *Client) Invoke(ctx context.Context, function string, payload []byte) (InvocationResult, error) {
+	start := time.Now()
+	result, err := c.lambdaClient.Invoke(ctx, &lambda.InvokeInput{
+		FunctionName:   aws.String(function),
+		InvocationType: types.InvocationTypeRequestResponse,
+		LogType:        types.LogTypeTail,
+		Payload:        payload,
+	})
+	if err != nil {
+		return InvocationResult{}, fmt.Errorf("failed to invoke function %s: %w", function, err)
+	}
+
+	invocation := InvocationResult{
+		Function:      function,
+		StatusCode:    result.StatusCode,
+		FunctionError: aws.ToString(result.FunctionError),
+		Payload:       string(result.Payload),
+		Duration:      time.Since(start),
+	}
+
+	if result.LogResult != nil {
+		logs, err := base64.StdEncoding.DecodeString(*result.LogResult)
+		if err != nil {
+			return invocation, fmt.Errorf("failed to decode logs of function %s: %w", function, err)
+		}
+		invocation.LogTail = string(logs)
+		invocation.Billed = billedDuration(invocation.LogTail)
+	}
+
+	return invocation, nil
+}
+
+// billedDurationPattern matches the billed duration of a REPORT log line,
+// e.g. "Billed Duration: 13 ms"
+var billedDurationPattern = regexp.MustCompile(`Billed Duration: (\d+) ms`)
+
+// billedDuration returns the billed duration reported in the logs, or zero
+// when the REPORT line is missing, e.g. because it was cut off
+func billedDuration(logs string) time.Duration {
+	match := billedDurationPattern.FindStringSubmatch(logs)
+	if match == nil {
+		return 0
+	}
+	ms, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0
+	}
+	return time.Duration(ms) * time.Millisecond
+}
@@ -0,0 +1,133 @@
+package lambda
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+)
+
+// Mock Lambda client
+type mockLambdaClient struct {
+	functions []string
+	invoke    func(params *lambda.InvokeInput) (*lambda.InvokeOutput, error)
+}
+
+func (m *mockLambdaClient) ListFunctions(ctx context.Context, params *lambda.ListFunctionsInput, optFns ...func(*lambda.Options)) (*lambda.ListFunctionsOutput, error) {
+	// Serve one function per page to exercise pagination
+	start := 0
+	if params.Marker != nil {
+		for i, name := range m.functions {
+			if name == *params.Marker {
+				start = i
+			}
+		}
+	}
+	output := &lambda.ListFunctionsOutput{Functions: []types.FunctionConfiguration{{
+		FunctionName: aws.String(m.functions[start]),
+		Runtime:      types.RuntimePython312,
+		MemorySize:   aws.Int32(128),
+		Timeout:      aws.Int32(3),
+		State:        types.StateActive,
+	}}}
+	if start+1 < len(m.functions) {
+		output.NextMarker = aws.String(m.functions[start+1])
+	}
+	return output, nil
+}
+
+func (m *mockLambdaClient) Invoke(ctx context.Context, params *lambda.InvokeInput, optFns ...func(*lambda.Options)) (*lambda.InvokeOutput, error) {
+	return m.invoke(params)
+}
+
+func TestGetFunctions(t *testing.T) {
+	client := NewClient(&mockLambdaClient{functions: []string{"resize", "audit", "notify"}}, nil)
+
+	functions, err := client.GetFunctions(context.Background())
+	if err != nil {
+		t.Fatalf("GetFunctions() error = %v", err)
+	}
+	if len(functions) != 3 {
+		t.Fatalf("Expected 3 functions across all pages, got %d", len(functions))
+	}
+	if functions[0].Name != "audit" || functions[2].Name != "resize" {
+		t.Errorf("Expected functions sorted by name, got %+v", functions)
+	}
+	if functions[0].Runtime != "python3.12" || functions[0].MemorySize != 128 || functions[0].Timeout != 3 {
+		t.Errorf("Unexpected configuration %+v", functions[0])
+	}
+	if got := functions[0].LogGroupName(); got != "/aws/lambda/audit" {
+		t.Errorf("Expected the default log group, got %s", got)
+	}
+}
+
+func TestLogGroupName(t *testing.T) {
+	function := newFunctionSummary(types.FunctionConfiguration{
+		FunctionName:  aws.String("resize"),
+		LoggingConfig: &types.LoggingConfig{LogGroup: aws.String("/shared/functions")},
+	})
+	if got := function.LogGroupName(); got != "/shared/functions" {
+		t.Errorf("Expected the custom log group, got %s", got)
+	}
+}
+
+func TestInvoke(t *testing.T) {
+	logs := "START RequestId: 1\nEND RequestId: 1\nREPORT RequestId: 1\tDuration: 12.34 ms\tBilled Duration: 13 ms\n"
+
+	var input *lambda.InvokeInput
+	client := NewClient(&mockLambdaClient{invoke: func(params *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		input = params
+		return &lambda.InvokeOutput{
+			StatusCode: 200,
+			Payload:    []byte(`{"ok":true}`),
+			LogResult:  aws.String(base64.StdEncoding.EncodeToString([]byte(logs))),
+		}, nil
+	}}, nil)
+
+	result, err := client.Invoke(context.Background(), "resize", []byte(`{"size":1}`))
+	if err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if input.LogType != types.LogTypeTail || input.InvocationType != types.InvocationTypeRequestResponse || string(input.Payload) != `{"size":1}` {
+		t.Errorf("Expected a synchronous invocation with the log tail and payload, got %+v", input)
+	}
+	if result.StatusCode != 200 || result.Payload != `{"ok":true}` || result.Failed() {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if result.LogTail != logs {
+		t.Errorf("Expected the decoded log tail, got '%s'", result.LogTail)
+	}
+	if result.Billed != 13*time.Millisecond {
+		t.Errorf("Expected billed duration 13ms, got %s", result.Billed)
+	}
+}
+
+func TestInvokeFunctionError(t *testing.T) {
+	client := NewClient(&mockLambdaClient{invoke: func(params *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		return &lambda.InvokeOutput{
+			StatusCode:    200,
+			FunctionError: aws.String("Unhandled"),
+			Payload:       []byte(`{"errorMessage":"boom"}`),
+		}, nil
+	}}, nil)
+
+	result, err := client.Invoke(context.Background(), "resize", []byte(`{}`))
+	if err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if !result.Failed() || result.LogTail != "" || result.Billed != 0 {
+		t.Errorf("Expected a failed invocation without logs, got %+v", result)
+	}
+
+	client = NewClient(&mockLambdaClient{invoke: func(params *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		return nil, errors.New("AccessDeniedException")
+	}}, nil)
+	if _, err := client.Invoke(context.Background(), "resize", []byte(`{}`)); err == nil {
+		t.Errorf("Expected the invoke error to be returned")
+	}
+}
@@ -0,0 +1,59 @@
+package logs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// FormatEvents formats the error events of resource tailed from sources for
+// terminal display, the most recent last
+func FormatEvents(resource string, sources []Source, events []Event) string {
+	title := "ERROR LOGS: " + resource
+	var output strings.Builder
+	output.WriteString(title + "\n")
+	output.WriteString(common.Rule(title, "=") + "\n\n")
+
+	if len(sources) == 0 {
+		output.WriteString("No log groups found\n")
+		return output.String()
+	}
+
+	output.WriteString("Log groups:\n")
+	for _, source := range sources {
+		if source.StreamPrefix != "" {
+			output.WriteString(fmt.Sprintf("  %s (streams %s*)\n", source.Group, source.StreamPrefix))
+		} else {
+			output.WriteString(fmt.Sprintf("  %s\n", source.Group))
+		}
+	}
+	output.WriteString("\n")
+
+	if len(events) == 0 {
+		output.WriteString(fmt.Sprintf("%s No error events in the last hour\n", common.Symbol("✅")))
+		return output.String()
+	}
+
+	count := fmt.Sprintf("%d error events in the last hour", len(events))
+	if len(events) == MaxEvents {
+		count = fmt.Sprintf("The last %d error events in the last hour", MaxEvents)
+	}
+	output.WriteString(fmt.Sprintf("%s %s, the most recent last:\n\n", common.Symbol("🚨"), count))
+
+	for _, event := range events {
+		prefix := event.Timestamp.Local().Format("15:04:05")
+		if len(sources) > 1 {
+			prefix += "  " + event.Group
+		}
+
+		// Continuation lines, e.g. of stack traces, are indented below the first
+		lines := strings.Split(strings.TrimRight(event.Message, " \t\r\n"), "\n")
+		output.WriteString(fmt.Sprintf("%s  %s\n", prefix, lines[0]))
+		for _, line := range lines[1:] {
+			output.WriteString(fmt.Sprintf("          %s\n", strings.TrimRight(line, "\r")))
+		}
+	}
+
+	return output.String()
+}
@@ -0,0 +1,36 @@
+package logs
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatEvents(t *testing.T) {
+	if got := FormatEvents("db1", nil, nil); !strings.Contains(got, "No log groups found") {
+		t.Errorf("Expected no log groups, got:\n%s", got)
+	}
+
+	sources := []Source{{Group: "/aws/lambda/orders"}}
+	if got := FormatEvents("orders", sources, nil); !strings.Contains(got, "No error events in the last hour") {
+		t.Errorf("Expected no events, got:\n%s", got)
+	}
+
+	at := time.Date(2024, 5, 10, 12, 3, 4, 0, time.Local)
+	events := []Event{
+		{Group: "/ecs/web", Timestamp: at, Message: "ERROR failed\n  at main.go:12\n"},
+		{Group: "/ecs/worker", Timestamp: at.Add(time.Second), Message: "panic: boom"},
+	}
+	output := FormatEvents("web", []Source{{Group: "/ecs/web", StreamPrefix: "web/app/"}, {Group: "/ecs/worker"}}, events)
+	for _, expected := range []string{
+		"ERROR LOGS: web",
+		"/ecs/web (streams web/app/*)",
+		"2 error events in the last hour, the most recent last:",
+		"12:03:04  /ecs/web  ERROR failed\n            at main.go:12\n",
+		"12:03:05  /ecs/worker  panic: boom",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
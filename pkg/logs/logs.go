@@ -0,0 +1,197 @@
+package logs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// logsClientAPI defines the interface for the CloudWatch Logs client
+type logsClientAPI interface {
+	FilterLogEvents(ctx context.Context, params *cloudwatchlogs.FilterLogEventsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.FilterLogEventsOutput, error)
+	DescribeLogGroups(ctx context.Context, params *cloudwatchlogs.DescribeLogGroupsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogGroupsOutput, error)
+}
+
+// ErrorPattern is the filter pattern of error events. Terms are matched
+// case-sensitively, so the common spellings are listed.
+const ErrorPattern = `?ERROR ?Error ?FATAL ?Fatal ?Exception ?panic`
+
+// Window is how far back error events are tailed
+const Window = time.Hour
+
+// MaxEvents is how many of the most recent error events are kept
+const MaxEvents = 100
+
+// maxPages bounds the FilterLogEvents calls per source. Pages may come back
+// empty while CloudWatch Logs scans large groups, so a busy group would
+// otherwise take many calls.
+const maxPages = 10
+
+// timeNow returns the current time, replaced in tests
+var timeNow = time.Now
+
+// Client represents a CloudWatch Logs client
+type Client struct {
+	logsClient logsClientAPI
+	pool       *common.Pool
+}
+
+// Source is a log group, or the streams in it whose name starts with
+// StreamPrefix when it is set
+type Source struct {
+	Group        string
+	StreamPrefix string
+}
+
+// Event is a log event
+type Event struct {
+	Group     string
+	Stream    string
+	Timestamp time.Time
+	Message   string
+}
+
+// NewClient returns a new CloudWatch Logs client whose calls run in pool,
+// which may be nil
+func NewClient(logsClient logsClientAPI, pool *common.Pool) *Client {
+	return &Client{
+		logsClient: logsClient,
+		pool:       pool,
+	}
+}
+
+// TailErrors returns the last MaxEvents events of the past Window matching
+// ErrorPattern in sources, oldest first. Log groups that do not exist, e.g.
+// of a function that never ran, have no events. Sources that fail to load are
+// left out and their errors returned alongside the events of the others.
+func (c *Client) TailErrors(ctx context.Context, sources []Source) ([]Event, []error) {
+	start := timeNow().Add(-Window)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var events []Event
+	var errs []error
+
+	for _, source := range sources {
+		wg.Add(1)
+		go func(source Source) {
+			defer wg.Done()
+
+			sourceEvents, err := c.filterErrors(ctx, source, start)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			events = append(events, sourceEvents...)
+		}(source)
+	}
+	wg.Wait()
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+	return latest(events), errs
+}
+
+// filterErrors returns the last MaxEvents error events of source since start
+func (c *Client) filterErrors(ctx context.Context, source Source, start time.Time) ([]Event, error) {
+	input := &cloudwatchlogs.FilterLogEventsInput{
+		LogGroupName:  aws.String(source.Group),
+		FilterPattern: aws.String(ErrorPattern),
+		StartTime:     aws.Int64(start.UnixMilli()),
+	}
+	if source.StreamPrefix != "" {
+		input.LogStreamNamePrefix = aws.String(source.StreamPrefix)
+	}
+
+	var events []Event
+	for page := 0; page < maxPages; page++ {
+		var result *cloudwatchlogs.FilterLogEventsOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.logsClient.FilterLogEvents(ctx, input)
+			return err
+		})
+		var notFound *types.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to filter log events of %s: %w", source.Group, err)
+		}
+
+		for _, event := range result.Events {
+			events = append(events, Event{
+				Group:     source.Group,
+				Stream:    aws.ToString(event.LogStreamName),
+				Timestamp: time.UnixMilli(aws.ToInt64(event.Timestamp)),
+				Message:   aws.ToString(event.Message),
+			})
+		}
+		events = latest(events)
+
+		if result.NextToken == nil {
+			break
+		}
+		input.NextToken = result.NextToken
+	}
+
+	return events, nil
+}
+
+// latest returns the last MaxEvents of events
+func latest(events []Event) []Event {
+	if len(events) > MaxEvents {
+		return events[len(events)-MaxEvents:]
+	}
+	return events
+}
+
+// GroupsWithPrefix returns the names of the log groups starting with prefix,
+// following the pagination tokens
+func (c *Client) GroupsWithPrefix(ctx context.Context, prefix string) ([]string, error) {
+	var groups []string
+	var nextToken *string
+
+	for {
+		var result *cloudwatchlogs.DescribeLogGroupsOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.logsClient.DescribeLogGroups(ctx, &cloudwatchlogs.DescribeLogGroupsInput{
+				LogGroupNamePrefix: aws.String(prefix),
+				NextToken:          nextToken,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe log groups: %w", err)
+		}
+
+		for _, group := range result.LogGroups {
+			groups = append(groups, aws.ToString(group.LogGroupName))
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return groups, nil
+}
+
+// RDSLogGroupPrefix returns the prefix of the log groups an RDS instance
+// exports its logs to, one per log type, e.g. /aws/rds/instance/db1/error
+func RDSLogGroupPrefix(instance string) string {
+	return "/aws/rds/instance/" + instance + "/"
+}
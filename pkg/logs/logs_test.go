@@ -0,0 +1,164 @@
+package logs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+// Mock CloudWatch Logs client
+type mockLogsClient struct {
+	filterLogEventsFunc   func(ctx context.Context, params *cloudwatchlogs.FilterLogEventsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.FilterLogEventsOutput, error)
+	describeLogGroupsFunc func(ctx context.Context, params *cloudwatchlogs.DescribeLogGroupsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogGroupsOutput, error)
+}
+
+func (m *mockLogsClient) FilterLogEvents(ctx context.Context, params *cloudwatchlogs.FilterLogEventsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.FilterLogEventsOutput, error) {
+	return m.filterLogEventsFunc(ctx, params, optFns...)
+}
+
+func (m *mockLogsClient) DescribeLogGroups(ctx context.Context, params *cloudwatchlogs.DescribeLogGroupsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
+	return m.describeLogGroupsFunc(ctx, params, optFns...)
+}
+
+func TestTailErrors(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	// The groups are filtered concurrently
+	var mu sync.Mutex
+	var inputs []*cloudwatchlogs.FilterLogEventsInput
+	client := NewClient(&mockLogsClient{
+		filterLogEventsFunc: func(ctx context.Context, params *cloudwatchlogs.FilterLogEventsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.FilterLogEventsOutput, error) {
+			mu.Lock()
+			inputs = append(inputs, params)
+			mu.Unlock()
+			switch aws.ToString(params.LogGroupName) {
+			case "/ecs/web":
+				return &cloudwatchlogs.FilterLogEventsOutput{Events: []types.FilteredLogEvent{
+					{LogStreamName: aws.String("web/app/1"), Timestamp: aws.Int64(now.Add(-30 * time.Minute).UnixMilli()), Message: aws.String("ERROR first")},
+					{LogStreamName: aws.String("web/app/1"), Timestamp: aws.Int64(now.Add(-10 * time.Minute).UnixMilli()), Message: aws.String("ERROR third")},
+				}}, nil
+			case "/ecs/worker":
+				return &cloudwatchlogs.FilterLogEventsOutput{Events: []types.FilteredLogEvent{
+					{LogStreamName: aws.String("w/1"), Timestamp: aws.Int64(now.Add(-20 * time.Minute).UnixMilli()), Message: aws.String("panic: second")},
+				}}, nil
+			}
+			return nil, &types.ResourceNotFoundException{Message: aws.String("The specified log group does not exist.")}
+		},
+	}, nil)
+
+	events, errs := client.TailErrors(context.Background(), []Source{
+		{Group: "/ecs/web", StreamPrefix: "web/app/"},
+		{Group: "/ecs/worker"},
+		{Group: "/aws/lambda/never-ran"},
+	})
+	if len(errs) > 0 {
+		t.Fatalf("Expected no errors for a missing group, got %v", errs)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events, got %d", len(events))
+	}
+	for i, expected := range []string{"ERROR first", "panic: second", "ERROR third"} {
+		if events[i].Message != expected {
+			t.Errorf("Expected event %d to be %q, got %q", i, expected, events[i].Message)
+		}
+	}
+	if events[1].Group != "/ecs/worker" || events[1].Stream != "w/1" {
+		t.Errorf("Expected the event's group and stream, got %+v", events[1])
+	}
+
+	for _, input := range inputs {
+		if aws.ToString(input.FilterPattern) != ErrorPattern {
+			t.Errorf("Expected the error pattern, got %q", aws.ToString(input.FilterPattern))
+		}
+		if aws.ToInt64(input.StartTime) != now.Add(-Window).UnixMilli() {
+			t.Errorf("Expected events of the last hour, got start %d", aws.ToInt64(input.StartTime))
+		}
+		if aws.ToString(input.LogGroupName) == "/ecs/web" && aws.ToString(input.LogStreamNamePrefix) != "web/app/" {
+			t.Errorf("Expected the stream prefix, got %q", aws.ToString(input.LogStreamNamePrefix))
+		}
+	}
+}
+
+func TestTailErrorsKeepsTheLatest(t *testing.T) {
+	calls := 0
+	client := NewClient(&mockLogsClient{
+		filterLogEventsFunc: func(ctx context.Context, params *cloudwatchlogs.FilterLogEventsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.FilterLogEventsOutput, error) {
+			// Every page has events, so only maxPages are read
+			calls++
+			var events []types.FilteredLogEvent
+			for i := 0; i < 50; i++ {
+				events = append(events, types.FilteredLogEvent{Timestamp: aws.Int64(int64(calls*1000 + i)), Message: aws.String("ERROR")})
+			}
+			return &cloudwatchlogs.FilterLogEventsOutput{Events: events, NextToken: aws.String("more")}, nil
+		},
+	}, nil)
+
+	events, errs := client.TailErrors(context.Background(), []Source{{Group: "busy"}})
+	if len(errs) > 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+	if calls != maxPages {
+		t.Errorf("Expected %d calls, got %d", maxPages, calls)
+	}
+	if len(events) != MaxEvents {
+		t.Fatalf("Expected %d events, got %d", MaxEvents, len(events))
+	}
+	if last := events[len(events)-1].Timestamp.UnixMilli(); last != int64(maxPages*1000+49) {
+		t.Errorf("Expected the most recent event last, got %d", last)
+	}
+}
+
+func TestTailErrorsPartialFailure(t *testing.T) {
+	client := NewClient(&mockLogsClient{
+		filterLogEventsFunc: func(ctx context.Context, params *cloudwatchlogs.FilterLogEventsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.FilterLogEventsOutput, error) {
+			if aws.ToString(params.LogGroupName) == "denied" {
+				return nil, errors.New("access denied")
+			}
+			return &cloudwatchlogs.FilterLogEventsOutput{Events: []types.FilteredLogEvent{{Message: aws.String("ERROR")}}}, nil
+		},
+	}, nil)
+
+	events, errs := client.TailErrors(context.Background(), []Source{{Group: "denied"}, {Group: "allowed"}})
+	if len(events) != 1 || events[0].Group != "allowed" {
+		t.Errorf("Expected the events of the allowed group, got %+v", events)
+	}
+	if len(errs) != 1 {
+		t.Errorf("Expected 1 error, got %v", errs)
+	}
+}
+
+func TestGroupsWithPrefix(t *testing.T) {
+	client := NewClient(&mockLogsClient{
+		describeLogGroupsFunc: func(ctx context.Context, params *cloudwatchlogs.DescribeLogGroupsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
+			if aws.ToString(params.LogGroupNamePrefix) != RDSLogGroupPrefix("db1") {
+				t.Errorf("Expected the RDS prefix, got %q", aws.ToString(params.LogGroupNamePrefix))
+			}
+			if params.NextToken == nil {
+				return &cloudwatchlogs.DescribeLogGroupsOutput{
+					LogGroups: []types.LogGroup{{LogGroupName: aws.String("/aws/rds/instance/db1/error")}},
+					NextToken: aws.String("page2"),
+				}, nil
+			}
+			return &cloudwatchlogs.DescribeLogGroupsOutput{
+				LogGroups: []types.LogGroup{{LogGroupName: aws.String("/aws/rds/instance/db1/slowquery")}},
+			}, nil
+		},
+	}, nil)
+
+	groups, err := client.GroupsWithPrefix(context.Background(), RDSLogGroupPrefix("db1"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(groups) != 2 || groups[1] != "/aws/rds/instance/db1/slowquery" {
+		t.Errorf("Expected the groups of both pages, got %v", groups)
+	}
+}
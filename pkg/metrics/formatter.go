@@ -0,0 +1,162 @@
+package metrics
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/correctedcloud/aws-overview/pkg/cloudwatchmetrics"
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// FormatNamespaces formats the namespaces for terminal display, marking the
+// namespace at index selected (-1 for none)
+func FormatNamespaces(namespaces []Namespace, selected int, truncated bool) string {
+	var output strings.Builder
+	output.WriteString("CLOUDWATCH NAMESPACES\n")
+	output.WriteString(common.Rule("CLOUDWATCH NAMESPACES", "=") + "\n\n")
+
+	if len(namespaces) == 0 {
+		output.WriteString("No metrics reported in the past 3 hours\n")
+		return output.String()
+	}
+	if truncated {
+		output.WriteString(fmt.Sprintf("Only the first %d metrics were listed, some namespaces may be missing\n\n", MaxMetrics))
+	}
+
+	nameWidth := 0
+	for _, namespace := range namespaces {
+		nameWidth = max(nameWidth, len(namespace.Name))
+	}
+	for i, namespace := range namespaces {
+		marker := "  "
+		if i == selected {
+			marker = "> "
+		}
+		kind := "AWS"
+		if namespace.Custom() {
+			kind = "custom"
+		}
+		output.WriteString(fmt.Sprintf("%s%-*s  %-6s  %s\n", marker, nameWidth, namespace.Name, kind, plural(namespace.Metrics, "metric")))
+	}
+
+	return output.String()
+}
+
+// FormatMetrics formats the metrics of a namespace with their dimensions for
+// terminal display, marking the metric at index selected (-1 for none)
+func FormatMetrics(namespace string, metrics []Metric, selected int, truncated bool) string {
+	title := "METRICS: " + namespace
+
+	var output strings.Builder
+	output.WriteString(title + "\n")
+	output.WriteString(common.Rule(title, "=") + "\n\n")
+
+	if len(metrics) == 0 {
+		output.WriteString("No metrics reported in the past 3 hours\n")
+		return output.String()
+	}
+	if truncated {
+		output.WriteString(fmt.Sprintf("Only the first %d metrics of the namespace are listed\n\n", MaxMetrics))
+	}
+
+	nameWidth := 0
+	for _, metric := range metrics {
+		nameWidth = max(nameWidth, len(metric.Name))
+	}
+	for i, metric := range metrics {
+		marker := "  "
+		if i == selected {
+			marker = "> "
+		}
+		output.WriteString(fmt.Sprintf("%s%-*s  %s\n", marker, nameWidth, metric.Name, metric.DimensionsLabel()))
+	}
+
+	return output.String()
+}
+
+// FormatSeries formats the series of a pinned or plotted metric as a graph
+// titled with its label
+func FormatSeries(pin Pin, result cloudwatchmetrics.Result) string {
+	var output strings.Builder
+	output.WriteString(pin.Metric.Namespace + " " + pin.Metric.Name + " (" + pin.Stat + ")\n")
+	output.WriteString("  " + pin.Metric.DimensionsLabel() + "\n")
+
+	switch {
+	case result.Err != nil:
+		output.WriteString(fmt.Sprintf("  Error: %v\n", result.Err))
+	case len(result.Values) == 0:
+		output.WriteString(fmt.Sprintf("  No datapoints in the past %s\n", formatWindow()))
+	default:
+		window := common.WithWindow(result.End.Add(-Window), result.End)
+		output.WriteString(common.GenerateSparkline(result.Values, pin.Stat, 5, common.WithStats(), window) + "\n")
+	}
+
+	return output.String()
+}
+
+// FormatPins formats the graphs of the pinned metrics for terminal display,
+// marking the pin at index selected (-1 for none). results holds the series
+// of the pins loaded so far by Pin.Label.
+func FormatPins(pins []Pin, results map[string]cloudwatchmetrics.Result, selected int) string {
+	var output strings.Builder
+	output.WriteString("CUSTOM METRICS\n")
+	output.WriteString(common.Rule("CUSTOM METRICS", "=") + "\n\n")
+
+	if len(pins) == 0 {
+		output.WriteString("No metrics pinned. Pin one with p while it is plotted on the CloudWatch tab.\n")
+		return output.String()
+	}
+
+	for i, pin := range pins {
+		marker := "  "
+		if i == selected {
+			marker = "> "
+		}
+		result, ok := results[pin.Label()]
+		if !ok {
+			output.WriteString(marker + pin.Label() + "\n  Loading...\n\n")
+			continue
+		}
+		output.WriteString(marker + strings.ReplaceAll(strings.TrimSuffix(FormatSeries(pin, result), "\n"), "\n", "\n  ") + "\n\n")
+	}
+
+	return output.String()
+}
+
+// GetPinsSummary returns a brief summary of the pinned metrics
+func GetPinsSummary(pins []Pin, results map[string]cloudwatchmetrics.Result) string {
+	failed, empty := 0, 0
+	for _, pin := range pins {
+		result, ok := results[pin.Label()]
+		switch {
+		case !ok:
+			// Pinned since the last load
+		case result.Err != nil:
+			failed++
+		case len(result.Values) == 0:
+			empty++
+		}
+	}
+
+	text := plural(len(pins), "pinned metric")
+	if failed > 0 {
+		text += fmt.Sprintf(", %d failed to load", failed)
+	}
+	if empty > 0 {
+		text += fmt.Sprintf(", %d without datapoints", empty)
+	}
+	return text
+}
+
+// formatWindow returns Window as e.g. "3 hours"
+func formatWindow() string {
+	return plural(int(Window.Hours()), "hour")
+}
+
+// plural returns count with noun, adding an s unless count is 1
+func plural(count int, noun string) string {
+	if count == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
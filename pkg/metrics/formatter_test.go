@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/correctedcloud/aws-overview/pkg/cloudwatchmetrics"
+)
+
+func TestFormatNamespaces(t *testing.T) {
+	output := FormatNamespaces([]Namespace{{Name: "Checkout", Metrics: 4}, {Name: "AWS/SQS", Metrics: 1}}, 1, true)
+
+	for _, expected := range []string{
+		"CLOUDWATCH NAMESPACES",
+		"some namespaces may be missing",
+		"  Checkout  custom  4 metrics",
+		"> AWS/SQS   AWS     1 metric",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
+
+func TestFormatMetrics(t *testing.T) {
+	output := FormatMetrics("Checkout", []Metric{
+		{Namespace: "Checkout", Name: "CartAbandonments"},
+		{Namespace: "Checkout", Name: "PaymentLatency", Dimensions: map[string]string{"Provider": "stripe"}},
+	}, 0, false)
+
+	for _, expected := range []string{
+		"METRICS: Checkout",
+		"> CartAbandonments  (no dimensions)",
+		"  PaymentLatency    Provider=stripe",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
+
+func TestFormatPins(t *testing.T) {
+	pins := []Pin{
+		{Metric: Metric{Namespace: "Checkout", Name: "OrdersPlaced"}, Stat: "Sum"},
+		{Metric: Metric{Namespace: "Checkout", Name: "PaymentLatency"}, Stat: "Average"},
+		{Metric: Metric{Namespace: "Checkout", Name: "CartAbandonments"}, Stat: "Maximum"},
+	}
+	results := map[string]cloudwatchmetrics.Result{
+		pins[0].Label(): {Values: []float64{1, 3, 2}, End: time.Now()},
+		pins[1].Label(): {Err: errors.New("throttled")},
+		pins[2].Label(): {},
+	}
+
+	output := FormatPins(pins, results, 1)
+	for _, expected := range []string{
+		"CUSTOM METRICS",
+		"  Checkout OrdersPlaced (Sum)",
+		"> Checkout PaymentLatency (Average)",
+		"Error: throttled",
+		"No datapoints in the past 3 hours",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+
+	if got := GetPinsSummary(pins, results); got != "3 pinned metrics, 1 failed to load, 1 without datapoints" {
+		t.Errorf("Unexpected summary %q", got)
+	}
+
+	// A pin added since the last load waits for the next one
+	pins = append(pins, Pin{Metric: Metric{Namespace: "Checkout", Name: "Refunds"}, Stat: "Sum"})
+	if output := FormatPins(pins, results, -1); !strings.Contains(output, "Sum of Checkout Refunds (no dimensions)\n  Loading...") {
+		t.Errorf("Expected the new pin to be loading, got:\n%s", output)
+	}
+	if got := GetPinsSummary(pins, results); got != "4 pinned metrics, 1 failed to load, 1 without datapoints" {
+		t.Errorf("Unexpected summary %q", got)
+	}
+}
+
+func TestFormatPinsEmpty(t *testing.T) {
+	if output := FormatPins(nil, nil, -1); !strings.Contains(output, "No metrics pinned") {
+		t.Errorf("Expected a hint on pinning, got:\n%s", output)
+	}
+}
@@ -0,0 +1,224 @@
+// Package metrics browses the CloudWatch metrics of an account by namespace
+// and fetches the recent series of any of them, for plotting metrics that
+// have no tab of their own.
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+
+	"github.com/correctedcloud/aws-overview/pkg/cloudwatchmetrics"
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// cloudwatchClientAPI defines the interface for the CloudWatch client
+type cloudwatchClientAPI interface {
+	ListMetrics(ctx context.Context, params *cloudwatch.ListMetricsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.ListMetricsOutput, error)
+	GetMetricData(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error)
+}
+
+// MaxMetrics caps how many metrics a listing reads, 20 pages of ListMetrics,
+// so that accounts with huge custom namespaces stay responsive
+const MaxMetrics = 10000
+
+// Period and Window are the resolution and length of the fetched series
+const (
+	Period = 5 * time.Minute
+	Window = 3 * time.Hour
+)
+
+// Stats are the statistics a series can be plotted with, the default first
+var Stats = []string{"Average", "Sum", "Maximum", "Minimum", "SampleCount"}
+
+// Metric identifies a CloudWatch metric by its namespace, name and dimensions
+type Metric struct {
+	Namespace  string            `json:"namespace"`
+	Name       string            `json:"name"`
+	Dimensions map[string]string `json:"dimensions,omitempty"`
+}
+
+// DimensionsLabel returns the dimensions as "Name=value" pairs sorted by
+// name, or "(no dimensions)"
+func (m Metric) DimensionsLabel() string {
+	if len(m.Dimensions) == 0 {
+		return "(no dimensions)"
+	}
+	names := make([]string, 0, len(m.Dimensions))
+	for name := range m.Dimensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + "=" + m.Dimensions[name]
+	}
+	return strings.Join(pairs, ", ")
+}
+
+// Label returns the namespace, name and dimensions of the metric
+func (m Metric) Label() string {
+	return m.Namespace + " " + m.Name + " " + m.DimensionsLabel()
+}
+
+// Pin is a metric plotted with a statistic, as pinned to the Custom Metrics tab
+type Pin struct {
+	Metric Metric `json:"metric"`
+	Stat   string `json:"stat"` // One of Stats
+}
+
+// Label returns the statistic and label of the metric
+func (p Pin) Label() string {
+	return p.Stat + " of " + p.Metric.Label()
+}
+
+// Namespace is a namespace with recently active metrics
+type Namespace struct {
+	Name    string
+	Metrics int // Number of recently active metrics
+}
+
+// Custom reports whether the namespace is published by the account rather
+// than by an AWS service
+func (n Namespace) Custom() bool {
+	return !strings.HasPrefix(n.Name, "AWS/")
+}
+
+// Client represents a CloudWatch metrics browser
+type Client struct {
+	cloudwatchClient cloudwatchClientAPI
+	batcher          *cloudwatchmetrics.Batcher
+	pool             *common.Pool
+}
+
+// NewClient returns a new metrics browser whose calls run in pool, which may
+// be nil
+func NewClient(cloudwatchClient cloudwatchClientAPI, pool *common.Pool) *Client {
+	return &Client{
+		cloudwatchClient: cloudwatchClient,
+		batcher:          cloudwatchmetrics.New(cloudwatchClient, pool),
+		pool:             pool,
+	}
+}
+
+// ListNamespaces returns the namespaces with metrics reported in the past 3
+// hours, custom namespaces first, and whether MaxMetrics cut the listing short
+func (c *Client) ListNamespaces(ctx context.Context) ([]Namespace, bool, error) {
+	counts := make(map[string]int)
+	truncated, err := c.listMetrics(ctx, nil, func(metric cwtypes.Metric) {
+		counts[aws.ToString(metric.Namespace)]++
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	namespaces := make([]Namespace, 0, len(counts))
+	for name, count := range counts {
+		namespaces = append(namespaces, Namespace{Name: name, Metrics: count})
+	}
+	sort.Slice(namespaces, func(i, j int) bool {
+		if namespaces[i].Custom() != namespaces[j].Custom() {
+			return namespaces[i].Custom()
+		}
+		return namespaces[i].Name < namespaces[j].Name
+	})
+	return namespaces, truncated, nil
+}
+
+// ListMetrics returns the metrics of a namespace reported in the past 3
+// hours, sorted by name and dimensions, and whether MaxMetrics cut the
+// listing short
+func (c *Client) ListMetrics(ctx context.Context, namespace string) ([]Metric, bool, error) {
+	var metrics []Metric
+	truncated, err := c.listMetrics(ctx, aws.String(namespace), func(metric cwtypes.Metric) {
+		dimensions := make(map[string]string, len(metric.Dimensions))
+		for _, dimension := range metric.Dimensions {
+			dimensions[aws.ToString(dimension.Name)] = aws.ToString(dimension.Value)
+		}
+		metrics = append(metrics, Metric{
+			Namespace:  aws.ToString(metric.Namespace),
+			Name:       aws.ToString(metric.MetricName),
+			Dimensions: dimensions,
+		})
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	sort.Slice(metrics, func(i, j int) bool {
+		if metrics[i].Name != metrics[j].Name {
+			return metrics[i].Name < metrics[j].Name
+		}
+		return metrics[i].DimensionsLabel() < metrics[j].DimensionsLabel()
+	})
+	return metrics, truncated, nil
+}
+
+// listMetrics calls visit with each recently active metric of namespace, or
+// of all namespaces when it is nil, and reports whether it stopped at MaxMetrics
+func (c *Client) listMetrics(ctx context.Context, namespace *string, visit func(cwtypes.Metric)) (bool, error) {
+	var nextToken *string
+	listed := 0
+
+	for {
+		var result *cloudwatch.ListMetricsOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.cloudwatchClient.ListMetrics(ctx, &cloudwatch.ListMetricsInput{
+				Namespace:      namespace,
+				RecentlyActive: cwtypes.RecentlyActivePt3h,
+				NextToken:      nextToken,
+			})
+			return err
+		})
+		if err != nil {
+			return false, fmt.Errorf("failed to list metrics: %w", err)
+		}
+
+		for _, metric := range result.Metrics {
+			visit(metric)
+		}
+		listed += len(result.Metrics)
+
+		if result.NextToken == nil {
+			return false, nil
+		}
+		if listed >= MaxMetrics {
+			return true, nil
+		}
+		nextToken = result.NextToken
+	}
+}
+
+// GetSeries returns the series of each pin over the last Window, in the same
+// order, fetched in as few calls as possible
+func (c *Client) GetSeries(ctx context.Context, pins []Pin) []cloudwatchmetrics.Result {
+	queries := make([]cloudwatchmetrics.Query, len(pins))
+	for i, pin := range pins {
+		queries[i] = cloudwatchmetrics.Query{
+			Namespace:  pin.Metric.Namespace,
+			MetricName: pin.Metric.Name,
+			Dimensions: pin.Metric.Dimensions,
+			Stat:       pin.Stat,
+			Period:     Period,
+			Window:     Window,
+		}
+	}
+	return c.batcher.Fetch(ctx, queries)
+}
+
+// NextStat returns the statistic after stat in Stats, wrapping around
+func NextStat(stat string) string {
+	for i, s := range Stats {
+		if s == stat {
+			return Stats[(i+1)%len(Stats)]
+		}
+	}
+	return Stats[0]
+}
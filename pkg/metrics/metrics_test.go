@@ -0,0 +1,199 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// Mock CloudWatch client serving its metrics pageSize per page, two by default
+type mockCloudWatchClient struct {
+	metrics  []cwtypes.Metric
+	pageSize int
+	err      error
+	pages    int
+	queries  []cwtypes.MetricDataQuery
+}
+
+func (m *mockCloudWatchClient) ListMetrics(ctx context.Context, params *cloudwatch.ListMetricsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.ListMetricsOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.pages++
+
+	var matching []cwtypes.Metric
+	for _, metric := range m.metrics {
+		if params.Namespace == nil || *params.Namespace == aws.ToString(metric.Namespace) {
+			matching = append(matching, metric)
+		}
+	}
+
+	start := 0
+	if params.NextToken != nil {
+		fmt.Sscan(*params.NextToken, &start)
+	}
+	pageSize := m.pageSize
+	if pageSize == 0 {
+		pageSize = 2
+	}
+	end := min(start+pageSize, len(matching))
+	output := &cloudwatch.ListMetricsOutput{Metrics: matching[start:end]}
+	if end < len(matching) {
+		output.NextToken = aws.String(fmt.Sprint(end))
+	}
+	return output, nil
+}
+
+func (m *mockCloudWatchClient) GetMetricData(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+	output := &cloudwatch.GetMetricDataOutput{}
+	for _, query := range params.MetricDataQueries {
+		m.queries = append(m.queries, query)
+		output.MetricDataResults = append(output.MetricDataResults, cwtypes.MetricDataResult{
+			Id:         query.Id,
+			Values:     []float64{1, 2},
+			Timestamps: []time.Time{time.Now().Add(-time.Minute), time.Now()},
+		})
+	}
+	return output, nil
+}
+
+// metric returns a listed metric with dimensions given as name and value pairs
+func metric(namespace, name string, dimensions ...string) cwtypes.Metric {
+	listed := cwtypes.Metric{Namespace: aws.String(namespace), MetricName: aws.String(name)}
+	for i := 0; i+1 < len(dimensions); i += 2 {
+		listed.Dimensions = append(listed.Dimensions, cwtypes.Dimension{Name: aws.String(dimensions[i]), Value: aws.String(dimensions[i+1])})
+	}
+	return listed
+}
+
+func TestListNamespaces(t *testing.T) {
+	client := NewClient(&mockCloudWatchClient{metrics: []cwtypes.Metric{
+		metric("AWS/SQS", "NumberOfMessagesSent", "QueueName", "orders"),
+		metric("Checkout", "OrdersPlaced"),
+		metric("AWS/SQS", "NumberOfMessagesSent", "QueueName", "emails"),
+		metric("AWS/EC2", "CPUUtilization", "InstanceId", "i-1"),
+		metric("Checkout", "PaymentLatency", "Provider", "stripe"),
+	}}, nil)
+
+	namespaces, truncated, err := client.ListNamespaces(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if truncated {
+		t.Error("Expected the listing to be complete")
+	}
+
+	expected := []Namespace{{Name: "Checkout", Metrics: 2}, {Name: "AWS/EC2", Metrics: 1}, {Name: "AWS/SQS", Metrics: 2}}
+	if len(namespaces) != len(expected) {
+		t.Fatalf("Expected %d namespaces, got %+v", len(expected), namespaces)
+	}
+	for i, namespace := range namespaces {
+		if namespace != expected[i] {
+			t.Errorf("Expected namespace %d to be %+v, got %+v", i, expected[i], namespace)
+		}
+	}
+}
+
+func TestListNamespacesTruncated(t *testing.T) {
+	mock := &mockCloudWatchClient{pageSize: 500}
+	for i := 0; i < MaxMetrics+3; i++ {
+		mock.metrics = append(mock.metrics, metric("Checkout", "OrdersPlaced", "Shard", fmt.Sprint(i)))
+	}
+
+	namespaces, truncated, err := NewClient(mock, nil).ListNamespaces(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !truncated {
+		t.Error("Expected the listing to stop at MaxMetrics")
+	}
+	if len(namespaces) != 1 || namespaces[0].Metrics != MaxMetrics {
+		t.Errorf("Expected %d metrics in one namespace, got %+v", MaxMetrics, namespaces)
+	}
+	if mock.pages != MaxMetrics/500 {
+		t.Errorf("Expected %d pages, got %d", MaxMetrics/500, mock.pages)
+	}
+}
+
+func TestListMetrics(t *testing.T) {
+	client := NewClient(&mockCloudWatchClient{metrics: []cwtypes.Metric{
+		metric("Checkout", "PaymentLatency", "Provider", "stripe"),
+		metric("AWS/SQS", "NumberOfMessagesSent", "QueueName", "orders"),
+		metric("Checkout", "PaymentLatency", "Provider", "adyen"),
+		metric("Checkout", "OrdersPlaced", "Service", "checkout", "Country", "DE"),
+	}}, nil)
+
+	metrics, _, err := client.ListMetrics(context.Background(), "Checkout")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{
+		"Checkout OrdersPlaced Country=DE, Service=checkout",
+		"Checkout PaymentLatency Provider=adyen",
+		"Checkout PaymentLatency Provider=stripe",
+	}
+	if len(metrics) != len(expected) {
+		t.Fatalf("Expected %d metrics, got %+v", len(expected), metrics)
+	}
+	for i, metric := range metrics {
+		if metric.Label() != expected[i] {
+			t.Errorf("Expected metric %d to be %q, got %q", i, expected[i], metric.Label())
+		}
+	}
+}
+
+func TestListMetricsError(t *testing.T) {
+	client := NewClient(&mockCloudWatchClient{err: errors.New("access denied")}, nil)
+
+	if _, _, err := client.ListMetrics(context.Background(), "Checkout"); err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+func TestGetSeries(t *testing.T) {
+	mock := &mockCloudWatchClient{}
+	pins := []Pin{
+		{Metric: Metric{Namespace: "Checkout", Name: "OrdersPlaced", Dimensions: map[string]string{"Service": "checkout"}}, Stat: "Sum"},
+		{Metric: Metric{Namespace: "Checkout", Name: "CartAbandonments"}, Stat: "Average"},
+	}
+
+	results := NewClient(mock, nil).GetSeries(context.Background(), pins)
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	for i, result := range results {
+		if result.Err != nil || len(result.Values) != 2 {
+			t.Errorf("Expected 2 datapoints for pin %d, got %+v", i, result)
+		}
+	}
+
+	if len(mock.queries) != 2 {
+		t.Fatalf("Expected 2 queries, got %d", len(mock.queries))
+	}
+	stat := mock.queries[0].MetricStat
+	if aws.ToString(stat.Stat) != "Sum" || aws.ToInt32(stat.Period) != int32(Period/time.Second) {
+		t.Errorf("Expected the Sum over %s periods, got %s over %ds", Period, aws.ToString(stat.Stat), aws.ToInt32(stat.Period))
+	}
+	if len(stat.Metric.Dimensions) != 1 || aws.ToString(stat.Metric.Dimensions[0].Value) != "checkout" {
+		t.Errorf("Expected the Service dimension, got %+v", stat.Metric.Dimensions)
+	}
+}
+
+func TestNextStat(t *testing.T) {
+	if got := NextStat("Average"); got != "Sum" {
+		t.Errorf("Expected Sum after Average, got %s", got)
+	}
+	if got := NextStat(Stats[len(Stats)-1]); got != Stats[0] {
+		t.Errorf("Expected the stats to wrap around, got %s", got)
+	}
+	if got := NextStat("p99"); got != Stats[0] {
+		t.Errorf("Expected an unknown stat to start over, got %s", got)
+	}
+}
@@ -0,0 +1,37 @@
+// Package overview exposes the AWS overview terminal UI as a reusable
+// bubbletea component for other charm-based tools. See the documentation of
+// Options and RefreshMsg for the configuration and message contract.
+package overview
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/correctedcloud/aws-overview/internal/providers"
+	"github.com/correctedcloud/aws-overview/internal/ui"
+)
+
+// Options configures the AWS overview component
+type Options = ui.Options
+
+// Provider collects and renders the resources of a service that is not built
+// in. Add providers through Options.Providers to show them in their own tabs.
+type Provider = providers.Provider
+
+// ProviderSummary is the outcome of a provider's load shown on the Overview tab
+type ProviderSummary = providers.Summary
+
+// Theme is the color palette of the component, set through Options.Theme
+type Theme = ui.Theme
+
+// LookupTheme returns a built-in theme: dark, light, solarized or high-contrast
+func LookupTheme(name string) (Theme, error) {
+	return ui.LookupTheme(name)
+}
+
+// RefreshMsg asks the component to reload all enabled services
+type RefreshMsg = ui.RefreshMsg
+
+// New creates the AWS overview as a bubbletea component configured by opts
+func New(opts Options) tea.Model {
+	return ui.New(opts)
+}
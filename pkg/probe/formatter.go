@@ -0,0 +1,68 @@
+package probe
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// FormatResults formats the probe results for terminal display, next to the
+// health AWS reports for each target
+func FormatResults(results []Result) string {
+	var output strings.Builder
+	output.WriteString("PROBES\n")
+	output.WriteString(common.Rule("PROBES", "=") + "\n\n")
+
+	if len(results) == 0 {
+		output.WriteString("Nothing to probe: no internet-facing load balancers or running instances with a public IP\n")
+		return output.String()
+	}
+
+	for _, result := range results {
+		output.WriteString(fmt.Sprintf("%s %s %s://%s\n", common.Symbol(getStatusSymbol(result)), result.Resource, result.Protocol, result.Address()))
+		if result.Reachable {
+			output.WriteString(fmt.Sprintf("  From here: %s in %s\n", result.Status, result.Latency.Round(time.Millisecond)))
+		} else {
+			output.WriteString(fmt.Sprintf("  From here: %s\n", result.Status))
+		}
+		output.WriteString(fmt.Sprintf("  In AWS: %s\n", result.AWSHealth))
+		if diagnosis := result.Diagnosis(); diagnosis != "ok" {
+			output.WriteString(fmt.Sprintf("  %s\n", diagnosis))
+		}
+		output.WriteString("\n")
+	}
+
+	return output.String()
+}
+
+// GetResultsSummary returns a brief summary of the probe results
+func GetResultsSummary(results []Result) string {
+	if len(results) == 0 {
+		return "Nothing to probe"
+	}
+
+	unreachable := 0
+	for _, result := range results {
+		if !result.Reachable {
+			unreachable++
+		}
+	}
+	if unreachable == 0 {
+		return fmt.Sprintf("All %d endpoints reachable", len(results))
+	}
+	return fmt.Sprintf("%d of %d endpoints unreachable", unreachable, len(results))
+}
+
+// getStatusSymbol returns the symbol of a result: a check mark when the
+// target is reachable and healthy, a warning when only one of them holds
+func getStatusSymbol(result Result) string {
+	switch {
+	case result.Reachable && result.AWSHealthy:
+		return "✅"
+	case result.Reachable || result.AWSHealthy:
+		return "⚠️"
+	}
+	return "❌"
+}
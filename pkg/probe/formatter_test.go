@@ -0,0 +1,46 @@
+package probe
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatResults(t *testing.T) {
+	output := FormatResults([]Result{
+		{
+			Target:    Target{Resource: "web", Host: "web.elb.amazonaws.com", Port: 443, Protocol: ProtocolHTTPS, AWSHealth: "2/2 targets healthy", AWSHealthy: true},
+			Reachable: true,
+			Status:    "HTTP 200",
+			Latency:   42 * time.Millisecond,
+		},
+		{
+			Target: Target{Resource: "bastion (i-1)", Host: "3.3.3.3", Port: 22, Protocol: ProtocolTCP, AWSHealth: "status checks ok", AWSHealthy: true},
+			Status: "timed out",
+		},
+	})
+
+	for _, expected := range []string{
+		"PROBES",
+		"✅ web https://web.elb.amazonaws.com:443\n  From here: HTTP 200 in 42ms\n  In AWS: 2/2 targets healthy\n\n",
+		"⚠️ bastion (i-1) tcp://3.3.3.3:22\n  From here: timed out\n  In AWS: status checks ok\n  healthy in AWS but unreachable from here",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+
+	if output := FormatResults(nil); !strings.Contains(output, "Nothing to probe") {
+		t.Errorf("Expected a message without targets, got:\n%s", output)
+	}
+}
+
+func TestGetResultsSummary(t *testing.T) {
+	results := []Result{{Reachable: true}, {}, {Reachable: true}}
+	if summary := GetResultsSummary(results); summary != "1 of 3 endpoints unreachable" {
+		t.Errorf("Unexpected summary %q", summary)
+	}
+	if summary := GetResultsSummary(results[:1]); summary != "All 1 endpoints reachable" {
+		t.Errorf("Unexpected summary %q", summary)
+	}
+}
@@ -0,0 +1,343 @@
+// Package probe checks from the operator's machine whether the load balancers
+// and EC2 instances of the inventory accept connections. Set against the
+// health AWS reports, it tells problems inside AWS, which the health checks
+// see, from problems on the way there, such as security groups, network ACLs,
+// DNS or the local network, which they do not.
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/correctedcloud/aws-overview/pkg/alb"
+	"github.com/correctedcloud/aws-overview/pkg/ec2"
+)
+
+// dialerAPI defines the interface for opening the connections of TCP probes
+type dialerAPI interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// httpClientAPI defines the interface for sending the requests of HTTP probes
+type httpClientAPI interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Protocols of a probe
+const (
+	ProtocolTCP   = "tcp"
+	ProtocolHTTP  = "http"
+	ProtocolHTTPS = "https"
+)
+
+// DefaultTimeout is how long a probe waits for a connection or a response
+// when NewClient is given no timeout
+const DefaultTimeout = 3 * time.Second
+
+// DefaultPorts are the ports probed on the public IPs of EC2 instances
+var DefaultPorts = []int32{22}
+
+// maxConcurrency is how many probes run at once
+const maxConcurrency = 20
+
+// Target is an endpoint to probe, with the health AWS reports for it
+type Target struct {
+	Resource   string // Name of the load balancer or instance
+	Host       string // DNS name of the load balancer or public IP of the instance
+	Port       int32
+	Protocol   string // ProtocolTCP, ProtocolHTTP or ProtocolHTTPS
+	AWSHealth  string // e.g. "2/3 targets healthy" or "status checks ok"
+	AWSHealthy bool
+}
+
+// Address returns the host and port of the target
+func (t Target) Address() string {
+	return net.JoinHostPort(t.Host, strconv.Itoa(int(t.Port)))
+}
+
+// Result is the outcome of probing a target
+type Result struct {
+	Target
+	Reachable bool
+	Status    string        // e.g. "HTTP 200", "connected" or why it failed, such as "timed out"
+	Latency   time.Duration // Until connected or the response headers arrived, zero when unreachable
+}
+
+// Diagnosis sets the reachability of the target against its health in AWS
+func (r Result) Diagnosis() string {
+	switch {
+	case r.Reachable && r.AWSHealthy:
+		return "ok"
+	case r.Reachable:
+		return "reachable but unhealthy in AWS: look behind the load balancer or on the instance"
+	case r.AWSHealthy:
+		return "healthy in AWS but unreachable from here: check security groups, network ACLs, DNS and the local network"
+	default:
+		return "unhealthy in AWS and unreachable"
+	}
+}
+
+// Client represents a probe client
+type Client struct {
+	dialer     dialerAPI
+	httpClient httpClientAPI
+	timeout    time.Duration
+}
+
+// NewClient returns a new probe client waiting up to timeout for each probe,
+// or DefaultTimeout when timeout is not positive
+func NewClient(dialer dialerAPI, httpClient httpClientAPI, timeout time.Duration) *Client {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Client{
+		dialer:     dialer,
+		httpClient: httpClient,
+		timeout:    timeout,
+	}
+}
+
+// NewHTTPClient returns the HTTP client of the probes. It does not follow
+// redirects, as any response proves the target reachable, and does not
+// verify certificates, as the DNS name of a load balancer is rarely on its
+// certificate.
+func NewHTTPClient() *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// ParsePorts parses a comma separated list of ports, e.g. "22,443"
+func ParsePorts(s string) ([]int32, error) {
+	var ports []int32
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		port, err := strconv.ParseUint(field, 10, 16)
+		if err != nil || port == 0 {
+			return nil, fmt.Errorf("invalid port %q", field)
+		}
+		ports = append(ports, int32(port))
+	}
+	return ports, nil
+}
+
+// Targets returns the targets of the inventory: every TCP, TLS, HTTP and
+// HTTPS listener of the internet-facing load balancers, and the given ports
+// on the public IPs of the running instances. Internal load balancers and
+// instances without a public IP cannot be reached from outside the VPC, so
+// they are left out.
+func Targets(loadBalancers []alb.LoadBalancerSummary, instances []ec2.InstanceSummary, ports []int32) []Target {
+	var targets []Target
+	for _, lb := range loadBalancers {
+		if lb.Scheme != "internet-facing" || lb.DNSName == "" {
+			continue
+		}
+		for _, listener := range lb.Listeners {
+			protocol := listenerProtocol(listener.Protocol)
+			if protocol == "" {
+				continue
+			}
+			health, healthy := listenerHealth(lb, listener)
+			targets = append(targets, Target{
+				Resource:   lb.Name,
+				Host:       lb.DNSName,
+				Port:       listener.Port,
+				Protocol:   protocol,
+				AWSHealth:  health,
+				AWSHealthy: healthy,
+			})
+		}
+	}
+
+	for _, instance := range instances {
+		if instance.State != "running" || instance.PublicIP == "" {
+			continue
+		}
+		name := instance.InstanceID
+		if instance.Name != "" {
+			name = fmt.Sprintf("%s (%s)", instance.Name, instance.InstanceID)
+		}
+		health, healthy := instanceHealth(instance)
+		for _, port := range ports {
+			targets = append(targets, Target{
+				Resource:   name,
+				Host:       instance.PublicIP,
+				Port:       port,
+				Protocol:   ProtocolTCP,
+				AWSHealth:  health,
+				AWSHealthy: healthy,
+			})
+		}
+	}
+
+	sort.SliceStable(targets, func(i, j int) bool {
+		if targets[i].Resource != targets[j].Resource {
+			return targets[i].Resource < targets[j].Resource
+		}
+		return targets[i].Port < targets[j].Port
+	})
+	return targets
+}
+
+// listenerProtocol returns the protocol probing a listener, or "" for
+// listeners that cannot be probed, such as UDP and the GENEVE listeners of
+// gateway load balancers
+func listenerProtocol(protocol string) string {
+	switch protocol {
+	case "HTTP":
+		return ProtocolHTTP
+	case "HTTPS":
+		return ProtocolHTTPS
+	case "TCP", "TLS":
+		return ProtocolTCP
+	}
+	return ""
+}
+
+// listenerHealth returns the health AWS reports for the targets a listener
+// forwards to. A listener without target groups answers by itself, e.g. with
+// a redirect.
+func listenerHealth(lb alb.LoadBalancerSummary, listener alb.ListenerSummary) (string, bool) {
+	arns := make(map[string]bool)
+	for _, arn := range listener.TargetGroupARNs {
+		arns[arn] = true
+	}
+	for _, rule := range listener.Rules {
+		for _, arn := range rule.TargetGroupARNs {
+			arns[arn] = true
+		}
+	}
+	if len(arns) == 0 {
+		return "answers without targets", true
+	}
+
+	healthy, total := 0, 0
+	for _, tg := range lb.TargetGroups {
+		if !arns[tg.ARN] {
+			continue
+		}
+		for _, target := range tg.Targets {
+			total++
+			if target.Healthy() {
+				healthy++
+			}
+		}
+	}
+	if total == 0 {
+		return "no registered targets", false
+	}
+	return fmt.Sprintf("%d/%d targets healthy", healthy, total), healthy > 0
+}
+
+// instanceHealth returns the health the status checks of an instance report
+func instanceHealth(instance ec2.InstanceSummary) (string, bool) {
+	switch {
+	case instance.SystemStatus == "impaired" || instance.InstanceStatus == "impaired":
+		return "status checks impaired", false
+	case instance.SystemStatus == "ok" && instance.InstanceStatus == "ok":
+		return "status checks ok", true
+	}
+	return "running", true
+}
+
+// Probe probes the targets concurrently and returns their results in the
+// order of the targets
+func (c *Client) Probe(ctx context.Context, targets []Target) []Result {
+	results := make([]Result, len(targets))
+	slots := make(chan struct{}, maxConcurrency)
+
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		go func(i int, target Target) {
+			defer wg.Done()
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				results[i] = Result{Target: target, Status: describeError(ctx.Err())}
+				return
+			}
+			defer func() { <-slots }()
+
+			results[i] = c.probe(ctx, target)
+		}(i, target)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// probe probes a single target, connecting for TCP and sending a GET request
+// for HTTP
+func (c *Client) probe(ctx context.Context, target Target) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	result := Result{Target: target}
+	start := time.Now()
+
+	if target.Protocol == ProtocolTCP {
+		conn, err := c.dialer.DialContext(ctx, "tcp", target.Address())
+		if err != nil {
+			result.Status = describeError(err)
+			return result
+		}
+		conn.Close()
+		result.Reachable = true
+		result.Status = "connected"
+		result.Latency = time.Since(start)
+		return result
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.Protocol+"://"+target.Address()+"/", nil)
+	if err != nil {
+		result.Status = describeError(err)
+		return result
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		result.Status = describeError(err)
+		return result
+	}
+	resp.Body.Close()
+	result.Reachable = true
+	result.Status = fmt.Sprintf("HTTP %d", resp.StatusCode)
+	result.Latency = time.Since(start)
+	return result
+}
+
+// describeError returns why a probe failed in a few words
+func describeError(err error) string {
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &dnsErr):
+		return "DNS lookup failed"
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return "connection refused"
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return "timed out"
+	case errors.Is(err, context.Canceled):
+		return "cancelled"
+	}
+	return err.Error()
+}
@@ -0,0 +1,144 @@
+package probe
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/correctedcloud/aws-overview/pkg/alb"
+	"github.com/correctedcloud/aws-overview/pkg/ec2"
+)
+
+// Mock network accepting connections on the open addresses and answering
+// HTTP requests with their status code
+type mockNetwork struct {
+	open map[string]int
+}
+
+func (m *mockNetwork) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if _, ok := m.open[address]; !ok {
+		return nil, &net.OpError{Op: "dial", Net: network, Err: syscall.ECONNREFUSED}
+	}
+	client, server := net.Pipe()
+	server.Close()
+	return client, nil
+}
+
+func (m *mockNetwork) Do(req *http.Request) (*http.Response, error) {
+	code, ok := m.open[req.URL.Host]
+	if !ok {
+		// Wait for the probe's timeout, like a dropped packet
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	}
+	return &http.Response{StatusCode: code, Body: io.NopCloser(strings.NewReader(""))}, nil
+}
+
+func TestTargets(t *testing.T) {
+	loadBalancers := []alb.LoadBalancerSummary{
+		{
+			Name:    "web",
+			Scheme:  "internet-facing",
+			DNSName: "web.elb.amazonaws.com",
+			Listeners: []alb.ListenerSummary{
+				{Protocol: "HTTPS", Port: 443, TargetGroupARNs: []string{"tg-web"}},
+				{Protocol: "HTTP", Port: 80},
+			},
+			TargetGroups: []alb.TargetGroupSummary{
+				{ARN: "tg-web", Targets: []alb.TargetSummary{{Status: "healthy"}, {Status: "unhealthy"}}},
+			},
+		},
+		{
+			Name:      "empty",
+			Scheme:    "internet-facing",
+			DNSName:   "empty.elb.amazonaws.com",
+			Listeners: []alb.ListenerSummary{{Protocol: "TLS", Port: 8443, TargetGroupARNs: []string{"tg-empty"}}},
+		},
+		{
+			Name:      "internal",
+			Scheme:    "internal",
+			DNSName:   "internal.elb.amazonaws.com",
+			Listeners: []alb.ListenerSummary{{Protocol: "HTTP", Port: 80}},
+		},
+	}
+	instances := []ec2.InstanceSummary{
+		{InstanceID: "i-1", Name: "bastion", State: "running", PublicIP: "3.3.3.3", SystemStatus: "ok", InstanceStatus: "impaired"},
+		{InstanceID: "i-2", State: "running", PrivateIP: "10.0.0.2"},
+		{InstanceID: "i-3", State: "stopped", PublicIP: "4.4.4.4"},
+	}
+
+	targets := Targets(loadBalancers, instances, []int32{22, 443})
+	var got []string
+	for _, target := range targets {
+		got = append(got, target.Resource+" "+target.Protocol+"://"+target.Address()+" "+target.AWSHealth)
+	}
+	want := []string{
+		"bastion (i-1) tcp://3.3.3.3:22 status checks impaired",
+		"bastion (i-1) tcp://3.3.3.3:443 status checks impaired",
+		"empty tcp://empty.elb.amazonaws.com:8443 no registered targets",
+		"web http://web.elb.amazonaws.com:80 answers without targets",
+		"web https://web.elb.amazonaws.com:443 1/2 targets healthy",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected targets\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+	if !targets[4].AWSHealthy || targets[2].AWSHealthy || targets[0].AWSHealthy {
+		t.Errorf("Unexpected AWS health of the targets: %+v", targets)
+	}
+}
+
+func TestProbe(t *testing.T) {
+	network := &mockNetwork{open: map[string]int{
+		"3.3.3.3:22":                 0,
+		"web.elb.amazonaws.com:443":  503,
+		"web.elb.amazonaws.com:8080": 200,
+	}}
+	client := NewClient(network, network, 50*time.Millisecond)
+
+	results := client.Probe(context.Background(), []Target{
+		{Resource: "bastion", Host: "3.3.3.3", Port: 22, Protocol: ProtocolTCP, AWSHealthy: true},
+		{Resource: "bastion", Host: "3.3.3.3", Port: 443, Protocol: ProtocolTCP, AWSHealthy: true},
+		{Resource: "web", Host: "web.elb.amazonaws.com", Port: 443, Protocol: ProtocolHTTPS},
+		{Resource: "web", Host: "web.elb.amazonaws.com", Port: 80, Protocol: ProtocolHTTP},
+	})
+
+	expected := []struct {
+		reachable bool
+		status    string
+		diagnosis string
+	}{
+		{true, "connected", "ok"},
+		{false, "connection refused", "healthy in AWS but unreachable"},
+		{true, "HTTP 503", "reachable but unhealthy in AWS"},
+		{false, "timed out", "unhealthy in AWS and unreachable"},
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("Expected %d results, got %d", len(expected), len(results))
+	}
+	for i, want := range expected {
+		result := results[i]
+		if result.Reachable != want.reachable || result.Status != want.status || !strings.HasPrefix(result.Diagnosis(), want.diagnosis) {
+			t.Errorf("Result %d: expected %v %q %q, got %v %q %q", i, want.reachable, want.status, want.diagnosis, result.Reachable, result.Status, result.Diagnosis())
+		}
+	}
+}
+
+func TestParsePorts(t *testing.T) {
+	ports, err := ParsePorts("22, 443,")
+	if err != nil || len(ports) != 2 || ports[0] != 22 || ports[1] != 443 {
+		t.Errorf("Expected ports 22 and 443, got %v, %v", ports, err)
+	}
+	if ports, err := ParsePorts(""); err != nil || len(ports) != 0 {
+		t.Errorf("Expected no ports, got %v, %v", ports, err)
+	}
+	for _, invalid := range []string{"ssh", "0", "70000"} {
+		if _, err := ParsePorts(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
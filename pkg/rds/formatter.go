@@ -4,43 +4,120 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/correctedcloud/aws-overview/pkg/cloudwatchmetrics"
 	"github.com/correctedcloud/aws-overview/pkg/common"
 )
 
-// FormatDBInstances formats DB instance summaries for terminal display
-func FormatDBInstances(summaries []DBInstanceSummary) string {
+// FormatDBInstances formats DB instance summaries for terminal display,
+// marking the instance at index selected (-1 for none)
+func FormatDBInstances(summaries []DBInstanceSummary, selected int) string {
 	if len(summaries) == 0 {
 		return "No DB instances found"
 	}
 
 	var output strings.Builder
 	output.WriteString("RDS INSTANCES\n")
-	output.WriteString("=============\n\n")
+	output.WriteString(common.Rule("RDS INSTANCES", "=") + "\n\n")
 
-	for _, instance := range summaries {
-		statusSymbol := getStatusSymbol(instance.Status)
-		output.WriteString(fmt.Sprintf("%s %s (%s)\n", statusSymbol, instance.Identifier, instance.Engine))
+	runningOut := GetInstancesRunningOutOfStorage(summaries)
+	if len(runningOut) > 0 {
+		output.WriteString(fmt.Sprintf("%s STORAGE RUNNING OUT (%d projected to fill up within %d days)\n",
+			common.Symbol("⚠️"), len(runningOut), StorageWarningDays))
+		for _, instance := range runningOut {
+			output.WriteString(fmt.Sprintf("  %s: full in ~%.0f days\n", instance.Identifier, instance.DaysUntilStorageFull))
+		}
+		output.WriteString("\n")
+	}
+
+	for i, instance := range summaries {
+		marker := ""
+		if i == selected {
+			marker = "> "
+		}
+		statusSymbol := common.Symbol(getStatusSymbol(instance.Status))
+		flag := ""
+		if instance.IsStorageNearlyFull() {
+			flag = " " + common.Symbol("⚠️")
+		}
+		output.WriteString(fmt.Sprintf("%s%s %s (%s)%s\n", marker, statusSymbol, instance.Identifier, instance.Engine, flag))
 
 		if instance.Endpoint != "" {
 			output.WriteString(fmt.Sprintf("  Endpoint: %s\n", instance.Endpoint))
 		}
 
-		output.WriteString("\n  CPU Utilization (1 hour):\n")
+		if instance.AllocatedStorageGB > 0 {
+			output.WriteString(fmt.Sprintf("  Storage: %s\n", formatStorage(instance)))
+		}
+		if instance.IOPSLimit > 0 || len(instance.IOPSData) > 0 {
+			output.WriteString(fmt.Sprintf("  IOPS: %s\n", formatIOPS(instance)))
+		}
+		if used, _ := instance.StorageUsedPercent(); instance.IsStorageNearlyFull() {
+			output.WriteString(fmt.Sprintf("  %s Storage %s used, above %d%%\n",
+				common.Symbol("⚠️"), common.FormatPercentage(used), StorageUsedWarningPercent))
+		}
+		if instance.IsStorageRunningOut() {
+			output.WriteString(fmt.Sprintf("  %s Storage projected to run out in ~%.0f days\n",
+				common.Symbol("⚠️"), instance.DaysUntilStorageFull))
+		}
+		if instance.CACertificate != "" {
+			output.WriteString(fmt.Sprintf("  CA certificate: %s\n", formatCertificate(instance)))
+		}
+		for _, action := range instance.PendingMaintenance {
+			output.WriteString(fmt.Sprintf("  Maintenance: %s\n", formatMaintenance(action)))
+		}
+		if next, ok := instance.NextMaintenance(); ok && instance.IsMaintenanceDue() {
+			at, _ := next.ScheduledAt()
+			output.WriteString(fmt.Sprintf("  %s Maintenance (%s) scheduled in ~%.0f days\n",
+				common.Symbol("⚠️"), next.Action, max(0, DaysUntil(at))))
+		}
+		if instance.IsCertificateExpiring() {
+			output.WriteString(fmt.Sprintf("  %s %s\n", common.Symbol("⚠️"), describeCertificateExpiry(instance)))
+		}
+
+		output.WriteString("\n  CPU Utilization (" + cloudwatchmetrics.FormatWindow(instance.MetricsWindow) + "):\n")
 		if len(instance.CPUData) > 0 {
-			cpuGraph := common.GenerateSparkline(instance.CPUData, "CPU (%)", 3)
+			cpuGraph := common.GenerateSparkline(instance.CPUData, "CPU (%)", 3,
+				common.WithStats(), common.WithWindow(instance.MetricsStart(), instance.MetricsEnd))
 			output.WriteString(fmt.Sprintf("%s\n", cpuGraph))
 		} else {
 			output.WriteString("  No CPU data available\n")
 		}
 
-		output.WriteString("\n  Memory Utilization (1 hour):\n")
+		output.WriteString("\n  Memory Utilization (" + cloudwatchmetrics.FormatWindow(instance.MetricsWindow) + "):\n")
 		if len(instance.MemoryData) > 0 {
-			memoryGraph := common.GenerateSparkline(instance.MemoryData, "Memory (%)", 3)
+			memoryGraph := common.GenerateSparkline(instance.MemoryData, "Memory (%)", 3,
+				common.WithStats(), common.WithWindow(instance.MetricsStart(), instance.MetricsEnd))
 			output.WriteString(fmt.Sprintf("%s\n", memoryGraph))
 		} else {
 			output.WriteString("  No memory data available\n")
 		}
 
+		output.WriteString("\n  Connections (" + cloudwatchmetrics.FormatWindow(instance.MetricsWindow) + "):\n")
+		if len(instance.ConnectionsData) > 0 {
+			connectionsGraph := common.GenerateSparkline(instance.ConnectionsData, "Connections", 3,
+				common.WithStats(), common.WithWindow(instance.MetricsStart(), instance.MetricsEnd))
+			output.WriteString(fmt.Sprintf("%s\n", connectionsGraph))
+		} else {
+			output.WriteString("  No connections data available\n")
+		}
+
+		output.WriteString("\n  Read and Write IOPS (" + cloudwatchmetrics.FormatWindow(instance.MetricsWindow) + "):\n")
+		if len(instance.ReadIOPSData) > 0 || len(instance.WriteIOPSData) > 0 {
+			for _, series := range []struct {
+				label string
+				data  []float64
+			}{{"Read IOPS", instance.ReadIOPSData}, {"Write IOPS", instance.WriteIOPSData}} {
+				if len(series.data) == 0 {
+					continue
+				}
+				graph := common.GenerateSparkline(series.data, series.label, 3,
+					common.WithStats(), common.WithWindow(instance.MetricsStart(), instance.MetricsEnd))
+				output.WriteString(fmt.Sprintf("%s\n", graph))
+			}
+		} else {
+			output.WriteString("  No IOPS data available\n")
+		}
+
 		output.WriteString("\n  Recent Errors:\n")
 		if len(instance.RecentErrors) > 0 {
 			for _, err := range instance.RecentErrors {
@@ -56,6 +133,34 @@ func FormatDBInstances(summaries []DBInstanceSummary) string {
 	return output.String()
 }
 
+// FormatInstanceGraphs draws the metrics of an instance as detail graphs
+// width columns wide, for a closer look than FormatDBInstances gives
+func FormatInstanceGraphs(instance DBInstanceSummary, width int) string {
+	window := common.WithWindow(instance.MetricsStart(), instance.MetricsEnd)
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("Graphs of %s (%s)\n", instance.Identifier, cloudwatchmetrics.FormatWindow(instance.MetricsWindow)))
+	// Both are percentages, so they share a scale
+	output.WriteString("\nCPU and Memory Utilization:\n")
+	output.WriteString(common.GenerateDetailChart([]common.Series{
+		{Label: "CPU (%)", Data: instance.CPUData},
+		{Label: "Memory (%)", Data: instance.MemoryData},
+	}, width, common.WithStats(), window) + "\n")
+	for _, series := range []struct {
+		title string
+		label string
+		data  []float64
+	}{
+		{"Connections", "Connections", instance.ConnectionsData},
+		{"Read IOPS", "Read IOPS", instance.ReadIOPSData},
+		{"Write IOPS", "Write IOPS", instance.WriteIOPSData},
+	} {
+		output.WriteString("\n" + series.title + ":\n")
+		output.WriteString(common.GenerateDetailGraph(series.data, series.label, width, common.WithStats(), window) + "\n")
+	}
+	return output.String()
+}
+
 // GetDBInstancesSummary returns a brief summary of DB instances
 func GetDBInstancesSummary(summaries []DBInstanceSummary) string {
 	if len(summaries) == 0 {
@@ -92,20 +197,139 @@ func GetDBInstancesSummary(summaries []DBInstanceSummary) string {
 		}
 	}
 
-	// Calculate averages
-	var cpuAvg, memoryAvg float64
+	// Calculate averages, leaving them as "n/a" when no instance reported data
+	cpuAvg, memoryAvg := "n/a", "n/a"
 	if cpuDataPoints > 0 {
-		cpuAvg = totalCPU / float64(cpuDataPoints)
+		cpuAvg = common.FormatPercentage(totalCPU / float64(cpuDataPoints))
 	}
 	if memoryDataPoints > 0 {
-		memoryAvg = totalMemory / float64(memoryDataPoints)
+		memoryAvg = common.FormatPercentage(totalMemory / float64(memoryDataPoints))
 	}
 
-	return fmt.Sprintf("%d instances (%d available), Avg CPU: %s, Avg Memory: %s",
+	summary := fmt.Sprintf("%d instances (%d available), Avg CPU: %s, Avg Memory: %s",
 		len(summaries),
 		available,
-		common.FormatPercentage(cpuAvg),
-		common.FormatPercentage(memoryAvg))
+		cpuAvg,
+		memoryAvg)
+	if runningOut := len(GetInstancesRunningOutOfStorage(summaries)); runningOut > 0 {
+		summary += fmt.Sprintf(", ⚠️ %d running out of storage", runningOut)
+	}
+	if nearlyFull := len(GetInstancesNearlyFull(summaries)); nearlyFull > 0 {
+		summary += fmt.Sprintf(", ⚠️ %d above %d%% storage used", nearlyFull, StorageUsedWarningPercent)
+	}
+	if due := len(GetInstancesWithMaintenanceDue(summaries)); due > 0 {
+		summary += fmt.Sprintf(", ⚠️ %d with maintenance within %d days", due, MaintenanceWarningDays)
+	}
+	if expiring := len(GetInstancesWithExpiringCertificates(summaries)); expiring > 0 {
+		summary += fmt.Sprintf(", ⚠️ %d with CA certificates expiring within %d days", expiring, CertificateWarningDays)
+	}
+
+	return summary
+}
+
+// GetInstancesRunningOutOfStorage returns the instances whose storage is
+// projected to run out within StorageWarningDays
+func GetInstancesRunningOutOfStorage(summaries []DBInstanceSummary) []DBInstanceSummary {
+	var instances []DBInstanceSummary
+	for _, instance := range summaries {
+		if instance.IsStorageRunningOut() {
+			instances = append(instances, instance)
+		}
+	}
+	return instances
+}
+
+// GetInstancesNearlyFull returns the instances using more than
+// StorageUsedWarningPercent of their allocated storage
+func GetInstancesNearlyFull(summaries []DBInstanceSummary) []DBInstanceSummary {
+	var instances []DBInstanceSummary
+	for _, instance := range summaries {
+		if instance.IsStorageNearlyFull() {
+			instances = append(instances, instance)
+		}
+	}
+	return instances
+}
+
+// formatStorage describes the allocated and free storage and whether it autoscales
+func formatStorage(instance DBInstanceSummary) string {
+	description := fmt.Sprintf("%d GB", instance.AllocatedStorageGB)
+	if instance.StorageType != "" {
+		description += " " + instance.StorageType
+	}
+
+	if percent, ok := instance.FreeStoragePercent(); ok {
+		free := instance.FreeStorageData[len(instance.FreeStorageData)-1] / bytesPerGB
+		description += fmt.Sprintf(", %s GB free (%s)", common.FormatFloatWithPrecision(free, 1), common.FormatPercentage(percent))
+	}
+
+	if instance.StorageAutoscaling() {
+		description += fmt.Sprintf(", autoscaling up to %d GB", instance.MaxAllocatedStorageGB)
+	} else {
+		description += ", autoscaling disabled"
+	}
+
+	return description
+}
+
+// formatCertificate describes the CA certificate of an instance and when its
+// server certificate expires
+func formatCertificate(instance DBInstanceSummary) string {
+	if instance.CACertificateExpiry.IsZero() {
+		return instance.CACertificate
+	}
+	return fmt.Sprintf("%s, expires %s", instance.CACertificate, instance.CACertificateExpiry.Format("2006-01-02"))
+}
+
+// describeCertificateExpiry describes when the CA certificate of an instance
+// expires, or that it has expired
+func describeCertificateExpiry(instance DBInstanceSummary) string {
+	days := DaysUntil(instance.CACertificateExpiry)
+	if days < 0 {
+		return fmt.Sprintf("CA certificate %s has expired", instance.CACertificate)
+	}
+	return fmt.Sprintf("CA certificate %s expires in ~%.0f days", instance.CACertificate, days)
+}
+
+// formatMaintenance describes a pending maintenance action and when it is
+// applied
+func formatMaintenance(action MaintenanceAction) string {
+	description := action.Action
+	if action.Description != "" {
+		description += " (" + action.Description + ")"
+	}
+	switch {
+	case !action.CurrentApplyDate.IsZero():
+		description += ", scheduled " + action.CurrentApplyDate.Format("2006-01-02 15:04")
+	case !action.AutoAppliedAfter.IsZero():
+		description += ", applied in the maintenance window after " + action.AutoAppliedAfter.Format("2006-01-02")
+	case !action.ForcedApplyDate.IsZero():
+		description += ", forced on " + action.ForcedApplyDate.Format("2006-01-02")
+	default:
+		description += ", not scheduled"
+	}
+	if action.OptInStatus != "" {
+		description += ", opted in for " + action.OptInStatus
+	}
+	return description
+}
+
+// formatIOPS describes the current IOPS against the limit of the storage
+func formatIOPS(instance DBInstanceSummary) string {
+	current := "n/a"
+	if len(instance.IOPSData) > 0 {
+		current = fmt.Sprintf("%.0f", instance.IOPSData[len(instance.IOPSData)-1])
+	}
+
+	if instance.IOPSLimit == 0 {
+		return current
+	}
+
+	description := fmt.Sprintf("%s of %d", current, instance.IOPSLimit)
+	if headroom, ok := instance.IOPSHeadroomPercent(); ok {
+		description += fmt.Sprintf(" (%s headroom)", common.FormatPercentage(headroom))
+	}
+	return description
 }
 
 // getStatusSymbol returns an appropriate symbol for an instance status
@@ -7,7 +7,7 @@ import (
 
 func TestFormatDBInstances(t *testing.T) {
 	// Test with empty summaries
-	emptyResult := FormatDBInstances([]DBInstanceSummary{})
+	emptyResult := FormatDBInstances([]DBInstanceSummary{}, -1)
 	if emptyResult != "No DB instances found" {
 		t.Errorf("Expected 'No DB instances found', got '%s'", emptyResult)
 	}
@@ -34,7 +34,7 @@ func TestFormatDBInstances(t *testing.T) {
 		},
 	}
 
-	result := FormatDBInstances(summaries)
+	result := FormatDBInstances(summaries, -1)
 
 	// Validate the output contains expected elements
 	expectedElements := []string{
@@ -83,3 +83,40 @@ func TestGetStatusSymbol(t *testing.T) {
 		})
 	}
 }
+
+func TestGetDBInstancesSummary(t *testing.T) {
+	testCases := []struct {
+		name      string
+		summaries []DBInstanceSummary
+		expected  string
+	}{
+		{
+			name:      "no instances",
+			summaries: []DBInstanceSummary{},
+			expected:  "No DB instances found",
+		},
+		{
+			name: "with metrics",
+			summaries: []DBInstanceSummary{
+				{Status: "available", CPUData: []float64{10, 20}, MemoryData: []float64{40}},
+				{Status: "stopped", CPUData: []float64{30}},
+			},
+			expected: "2 instances (1 available), Avg CPU: 25.00%, Avg Memory: 40.00%",
+		},
+		{
+			name: "without metrics",
+			summaries: []DBInstanceSummary{
+				{Status: "stopped"},
+			},
+			expected: "1 instances (0 available), Avg CPU: n/a, Avg Memory: n/a",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := GetDBInstancesSummary(tc.summaries); got != tc.expected {
+				t.Errorf("Expected '%s', got '%s'", tc.expected, got)
+			}
+		})
+	}
+}
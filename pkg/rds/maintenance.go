@@ -0,0 +1,144 @@
+package rds
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+)
+
+// MaintenanceWarningDays is how many days ahead scheduled maintenance is warned about
+const MaintenanceWarningDays = 7
+
+// CertificateWarningDays is how many days ahead the expiry of an instance's
+// CA certificate is warned about
+const CertificateWarningDays = 30
+
+// timeNow returns the current time, replaced by tests
+var timeNow = time.Now
+
+// MaintenanceAction is a maintenance action pending on an instance
+type MaintenanceAction struct {
+	Action      string // e.g. "system-update" or "db-upgrade"
+	Description string
+	OptInStatus string // e.g. "next-maintenance", empty when not opted in
+
+	AutoAppliedAfter time.Time // Start of the maintenance window it is applied in, zero when not scheduled
+	ForcedApplyDate  time.Time // When it is applied regardless of the window, zero when never
+	CurrentApplyDate time.Time // When it is applied as scheduled now, zero when not scheduled
+}
+
+// ScheduledAt returns when the action is applied, the earliest of its
+// current, automatic and forced dates, and false when it is not scheduled
+func (a MaintenanceAction) ScheduledAt() (time.Time, bool) {
+	var earliest time.Time
+	for _, date := range []time.Time{a.CurrentApplyDate, a.AutoAppliedAfter, a.ForcedApplyDate} {
+		if !date.IsZero() && (earliest.IsZero() || date.Before(earliest)) {
+			earliest = date
+		}
+	}
+	return earliest, !earliest.IsZero()
+}
+
+// NextMaintenance returns the pending maintenance action scheduled first, and
+// false when none is scheduled
+func (s DBInstanceSummary) NextMaintenance() (MaintenanceAction, bool) {
+	var next MaintenanceAction
+	var nextAt time.Time
+	for _, action := range s.PendingMaintenance {
+		if at, ok := action.ScheduledAt(); ok && (nextAt.IsZero() || at.Before(nextAt)) {
+			next, nextAt = action, at
+		}
+	}
+	return next, !nextAt.IsZero()
+}
+
+// IsMaintenanceDue reports whether maintenance is scheduled within
+// MaintenanceWarningDays
+func (s DBInstanceSummary) IsMaintenanceDue() bool {
+	next, ok := s.NextMaintenance()
+	if !ok {
+		return false
+	}
+	at, _ := next.ScheduledAt()
+	return at.Before(timeNow().Add(MaintenanceWarningDays * 24 * time.Hour))
+}
+
+// IsCertificateExpiring reports whether the CA certificate of the instance
+// expires within CertificateWarningDays, or has expired
+func (s DBInstanceSummary) IsCertificateExpiring() bool {
+	return !s.CACertificateExpiry.IsZero() && s.CACertificateExpiry.Before(timeNow().Add(CertificateWarningDays*24*time.Hour))
+}
+
+// DaysUntil returns the days from now until t, negative once it has passed
+func DaysUntil(t time.Time) float64 {
+	return t.Sub(timeNow()).Hours() / 24
+}
+
+// GetInstancesWithMaintenanceDue returns the instances with maintenance
+// scheduled within MaintenanceWarningDays
+func GetInstancesWithMaintenanceDue(summaries []DBInstanceSummary) []DBInstanceSummary {
+	var instances []DBInstanceSummary
+	for _, instance := range summaries {
+		if instance.IsMaintenanceDue() {
+			instances = append(instances, instance)
+		}
+	}
+	return instances
+}
+
+// GetInstancesWithExpiringCertificates returns the instances whose CA
+// certificate expires within CertificateWarningDays
+func GetInstancesWithExpiringCertificates(summaries []DBInstanceSummary) []DBInstanceSummary {
+	var instances []DBInstanceSummary
+	for _, instance := range summaries {
+		if instance.IsCertificateExpiring() {
+			instances = append(instances, instance)
+		}
+	}
+	return instances
+}
+
+// getPendingMaintenance returns the pending maintenance actions of the
+// account's DB instances and clusters by resource ARN, following the
+// pagination markers
+func (c *Client) getPendingMaintenance(ctx context.Context) (map[string][]MaintenanceAction, error) {
+	actions := make(map[string][]MaintenanceAction)
+	var marker *string
+
+	for {
+		var result *rds.DescribePendingMaintenanceActionsOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.rdsClient.DescribePendingMaintenanceActions(ctx, &rds.DescribePendingMaintenanceActionsInput{
+				Marker: marker,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe pending maintenance actions: %w", err)
+		}
+
+		for _, resource := range result.PendingMaintenanceActions {
+			arn := aws.ToString(resource.ResourceIdentifier)
+			for _, detail := range resource.PendingMaintenanceActionDetails {
+				actions[arn] = append(actions[arn], MaintenanceAction{
+					Action:           aws.ToString(detail.Action),
+					Description:      aws.ToString(detail.Description),
+					OptInStatus:      aws.ToString(detail.OptInStatus),
+					AutoAppliedAfter: aws.ToTime(detail.AutoAppliedAfterDate),
+					ForcedApplyDate:  aws.ToTime(detail.ForcedApplyDate),
+					CurrentApplyDate: aws.ToTime(detail.CurrentApplyDate),
+				})
+			}
+		}
+
+		marker = result.Marker
+		if marker == nil {
+			break
+		}
+	}
+
+	return actions, nil
+}
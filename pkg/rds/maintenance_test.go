@@ -0,0 +1,145 @@
+package rds
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+func TestGetDBInstancesMaintenance(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	instance := func(identifier, ca string, validTill time.Time) types.DBInstance {
+		return types.DBInstance{
+			DBInstanceIdentifier: aws.String(identifier),
+			DBInstanceArn:        aws.String("arn:aws:rds:us-east-1:123456789012:db:" + identifier),
+			Engine:               aws.String("postgres"),
+			DBInstanceStatus:     aws.String("available"),
+			CertificateDetails:   &types.CertificateDetails{CAIdentifier: aws.String(ca), ValidTill: aws.Time(validTill)},
+		}
+	}
+
+	var markers []string
+	client := NewClient(
+		&mockRDSClient{
+			describeDBInstancesFunc: func(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+				return &rds.DescribeDBInstancesOutput{DBInstances: []types.DBInstance{
+					instance("orders-db", "rds-ca-rsa2048-g1", now.Add(400*24*time.Hour)),
+					instance("reports-db", "rds-ca-2019", now.Add(10*24*time.Hour)),
+				}}, nil
+			},
+			describePendingMaintenanceActionsFunc: func(ctx context.Context, params *rds.DescribePendingMaintenanceActionsInput, optFns ...func(*rds.Options)) (*rds.DescribePendingMaintenanceActionsOutput, error) {
+				markers = append(markers, aws.ToString(params.Marker))
+				if params.Marker == nil {
+					return &rds.DescribePendingMaintenanceActionsOutput{
+						PendingMaintenanceActions: []types.ResourcePendingMaintenanceActions{{
+							ResourceIdentifier: aws.String("arn:aws:rds:us-east-1:123456789012:db:orders-db"),
+							PendingMaintenanceActionDetails: []types.PendingMaintenanceAction{{
+								Action:               aws.String("system-update"),
+								Description:          aws.String("New Operating System update is available"),
+								AutoAppliedAfterDate: aws.Time(now.Add(3 * 24 * time.Hour)),
+								ForcedApplyDate:      aws.Time(now.Add(30 * 24 * time.Hour)),
+							}},
+						}},
+						Marker: aws.String("page-2"),
+					}, nil
+				}
+				return &rds.DescribePendingMaintenanceActionsOutput{
+					PendingMaintenanceActions: []types.ResourcePendingMaintenanceActions{{
+						ResourceIdentifier: aws.String("arn:aws:rds:us-east-1:123456789012:db:reports-db"),
+						PendingMaintenanceActionDetails: []types.PendingMaintenanceAction{{
+							Action:          aws.String("db-upgrade"),
+							ForcedApplyDate: aws.Time(now.Add(40 * 24 * time.Hour)),
+						}},
+					}},
+				}, nil
+			},
+		},
+		&mockCloudWatchClient{
+			getMetricDataFunc: func(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+				return &cloudwatch.GetMetricDataOutput{}, nil
+			},
+		},
+		nil,
+		nil,
+		0,
+	)
+
+	instances, errs := client.GetDBInstances(context.Background())
+	if len(errs) > 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+	if len(markers) != 2 || markers[1] != "page-2" {
+		t.Errorf("Expected both pages of maintenance actions, got %v", markers)
+	}
+
+	orders, reports := instances[0], instances[1]
+	if next, ok := orders.NextMaintenance(); !ok || next.Action != "system-update" || !orders.IsMaintenanceDue() {
+		t.Errorf("Expected the system update of orders-db to be due, got %+v", orders.PendingMaintenance)
+	}
+	if at, _ := orders.PendingMaintenance[0].ScheduledAt(); !at.Equal(now.Add(3 * 24 * time.Hour)) {
+		t.Errorf("Expected the earliest date of the action, got %s", at)
+	}
+	if len(reports.PendingMaintenance) != 1 || reports.IsMaintenanceDue() {
+		t.Errorf("Expected the upgrade of reports-db not to be due yet, got %+v", reports.PendingMaintenance)
+	}
+	if orders.IsCertificateExpiring() || !reports.IsCertificateExpiring() || reports.CACertificate != "rds-ca-2019" {
+		t.Errorf("Expected only the certificate of reports-db to be expiring, got %+v and %+v", orders, reports)
+	}
+
+	output := FormatDBInstances(instances, -1)
+	for _, expected := range []string{
+		"CA certificate: rds-ca-2019, expires 2024-03-11",
+		"Maintenance: system-update (New Operating System update is available), applied in the maintenance window after 2024-03-04",
+		"Maintenance: db-upgrade, forced on 2024-04-10",
+		"Maintenance (system-update) scheduled in ~3 days",
+		"CA certificate rds-ca-2019 expires in ~10 days",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain '%s', got:\n%s", expected, output)
+		}
+	}
+
+	summary := GetDBInstancesSummary(instances)
+	if !strings.Contains(summary, "⚠️ 1 with maintenance within 7 days") || !strings.Contains(summary, "⚠️ 1 with CA certificates expiring within 30 days") {
+		t.Errorf("Expected the summary to flag the maintenance and certificate, got '%s'", summary)
+	}
+}
+
+func TestGetDBInstancesMaintenanceError(t *testing.T) {
+	client := NewClient(
+		&mockRDSClient{
+			describeDBInstancesFunc: func(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+				return &rds.DescribeDBInstancesOutput{DBInstances: []types.DBInstance{{
+					DBInstanceIdentifier: aws.String("orders-db"),
+					Engine:               aws.String("postgres"),
+					DBInstanceStatus:     aws.String("available"),
+				}}}, nil
+			},
+			describePendingMaintenanceActionsFunc: func(ctx context.Context, params *rds.DescribePendingMaintenanceActionsInput, optFns ...func(*rds.Options)) (*rds.DescribePendingMaintenanceActionsOutput, error) {
+				return nil, context.DeadlineExceeded
+			},
+		},
+		&mockCloudWatchClient{
+			getMetricDataFunc: func(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+				return &cloudwatch.GetMetricDataOutput{}, nil
+			},
+		},
+		nil,
+		nil,
+		0,
+	)
+
+	instances, errs := client.GetDBInstances(context.Background())
+	if len(instances) != 1 || len(errs) != 1 || !strings.Contains(errs[0].Error(), "pending maintenance") {
+		t.Errorf("Expected the instance along with the maintenance error, got %v and %v", instances, errs)
+	}
+}
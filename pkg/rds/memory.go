@@ -0,0 +1,181 @@
+package rds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+)
+
+// instanceTypesClientAPI defines the interface for the EC2 client that looks
+// up the memory of instance types
+type instanceTypesClientAPI interface {
+	DescribeInstanceTypes(ctx context.Context, params *ec2.DescribeInstanceTypesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstanceTypesOutput, error)
+}
+
+// instanceTypesPerCall is the most instance types DescribeInstanceTypes
+// accepts in one call
+const instanceTypesPerCall = 100
+
+// instanceMemory caches the memory in GiB of the EC2 instance types looked up
+// so far. Instance types do not change, so it is shared by all clients and
+// kept for the lifetime of the process.
+var instanceMemory = struct {
+	sync.Mutex
+	gib map[string]float64
+}{gib: make(map[string]float64)}
+
+// ec2InstanceType returns the EC2 instance type underlying a DB instance
+// class, e.g. "r6g.large" for "db.r6g.large" and for the optimized CPU class
+// "db.r5.large.tpc2.mem2x", or "" when it has none, e.g. "db.serverless"
+func ec2InstanceType(class string) string {
+	parts := strings.Split(strings.TrimPrefix(class, "db."), ".")
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// getInstanceMemory returns the memory in GiB of the DB instance classes,
+// looking up the underlying EC2 instance types not cached yet. Classes whose
+// type cannot be looked up, e.g. without ec2:DescribeInstanceTypes, fall back
+// to an estimate from their size, along with the error of the lookup.
+func (c *Client) getInstanceMemory(ctx context.Context, classes []string) (map[string]float64, error) {
+	instanceMemory.Lock()
+	seen := make(map[string]bool)
+	var missing []string
+	for _, class := range classes {
+		instanceType := ec2InstanceType(class)
+		if _, ok := instanceMemory.gib[instanceType]; !ok && instanceType != "" && !seen[instanceType] {
+			seen[instanceType] = true
+			missing = append(missing, instanceType)
+		}
+	}
+	instanceMemory.Unlock()
+	sort.Strings(missing)
+
+	var err error
+	if len(missing) > 0 && c.instanceTypesClient != nil {
+		err = c.describeInstanceTypes(ctx, missing)
+	}
+
+	instanceMemory.Lock()
+	defer instanceMemory.Unlock()
+	memory := make(map[string]float64, len(classes))
+	for _, class := range classes {
+		if gib, ok := instanceMemory.gib[ec2InstanceType(class)]; ok {
+			memory[class] = gib
+		} else {
+			memory[class] = estimateInstanceMemory(class)
+		}
+	}
+	return memory, err
+}
+
+// describeInstanceTypes looks up the memory of the instance types and caches
+// it. A type EC2 does not know fails the whole call, so such a call is
+// retried one type at a time to cache the others.
+func (c *Client) describeInstanceTypes(ctx context.Context, instanceTypes []string) error {
+	var errs []error
+	for start := 0; start < len(instanceTypes); start += instanceTypesPerCall {
+		batch := instanceTypes[start:min(start+instanceTypesPerCall, len(instanceTypes))]
+		err := c.describeInstanceTypesBatch(ctx, batch)
+		if err == nil || len(batch) == 1 || !isErrorCode(err, "InvalidInstanceType") {
+			if err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		for _, instanceType := range batch {
+			if err := c.describeInstanceTypesBatch(ctx, []string{instanceType}); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// describeInstanceTypesBatch looks up the memory of up to
+// instanceTypesPerCall instance types and caches it
+func (c *Client) describeInstanceTypesBatch(ctx context.Context, instanceTypes []string) error {
+	input := &ec2.DescribeInstanceTypesInput{}
+	for _, instanceType := range instanceTypes {
+		input.InstanceTypes = append(input.InstanceTypes, ec2types.InstanceType(instanceType))
+	}
+
+	for {
+		var result *ec2.DescribeInstanceTypesOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.instanceTypesClient.DescribeInstanceTypes(ctx, input)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to describe instance types %s: %w", strings.Join(instanceTypes, ", "), err)
+		}
+
+		instanceMemory.Lock()
+		for _, info := range result.InstanceTypes {
+			if info.MemoryInfo != nil && aws.ToInt64(info.MemoryInfo.SizeInMiB) > 0 {
+				instanceMemory.gib[string(info.InstanceType)] = float64(aws.ToInt64(info.MemoryInfo.SizeInMiB)) / 1024
+			}
+		}
+		instanceMemory.Unlock()
+
+		if result.NextToken == nil {
+			return nil
+		}
+		input.NextToken = result.NextToken
+	}
+}
+
+// isErrorCode reports whether err is an AWS API error with the given code
+func isErrorCode(err error, code string) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == code
+}
+
+// sizeMemory is the memory in GiB of the sizes of the general purpose
+// families, e.g. m6g.large, which the other families scale
+var sizeMemory = map[string]float64{
+	"micro":    1,
+	"small":    2,
+	"medium":   4,
+	"large":    8,
+	"xlarge":   16,
+	"2xlarge":  32,
+	"4xlarge":  64,
+	"8xlarge":  128,
+	"12xlarge": 192,
+	"16xlarge": 256,
+	"24xlarge": 384,
+	"32xlarge": 512,
+	"48xlarge": 768,
+}
+
+// estimateInstanceMemory estimates the memory in GiB of a DB instance class
+// from its size, for classes whose instance type could not be looked up.
+// Memory optimized families (r, x, z) have two or four times the memory of the
+// general purpose ones.
+func estimateInstanceMemory(class string) float64 {
+	instanceType := ec2InstanceType(class)
+	family, size, _ := strings.Cut(instanceType, ".")
+	memory, ok := sizeMemory[size]
+	if !ok {
+		return 8 // Default fallback
+	}
+
+	switch {
+	case strings.HasPrefix(family, "x"):
+		return memory * 4
+	case strings.HasPrefix(family, "r"), strings.HasPrefix(family, "z"):
+		return memory * 2
+	}
+	return memory
+}
@@ -0,0 +1,127 @@
+package rds
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+)
+
+// Mock EC2 client
+type mockInstanceTypesClient struct {
+	describeInstanceTypesFunc func(ctx context.Context, params *ec2.DescribeInstanceTypesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstanceTypesOutput, error)
+}
+
+func (m *mockInstanceTypesClient) DescribeInstanceTypes(ctx context.Context, params *ec2.DescribeInstanceTypesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstanceTypesOutput, error) {
+	return m.describeInstanceTypesFunc(ctx, params, optFns...)
+}
+
+// resetInstanceMemory empties the cache of instance type memory
+func resetInstanceMemory(t *testing.T) {
+	instanceMemory.Lock()
+	instanceMemory.gib = make(map[string]float64)
+	instanceMemory.Unlock()
+	t.Cleanup(func() {
+		instanceMemory.Lock()
+		instanceMemory.gib = make(map[string]float64)
+		instanceMemory.Unlock()
+	})
+}
+
+func TestEC2InstanceType(t *testing.T) {
+	for class, expected := range map[string]string{
+		"db.r6g.large":           "r6g.large",
+		"db.t3.2xlarge":          "t3.2xlarge",
+		"db.r5.large.tpc2.mem2x": "r5.large",
+		"db.serverless":          "",
+		"":                       "",
+	} {
+		if instanceType := ec2InstanceType(class); instanceType != expected {
+			t.Errorf("Expected %q for %q, got %q", expected, class, instanceType)
+		}
+	}
+}
+
+func TestEstimateInstanceMemory(t *testing.T) {
+	for class, expected := range map[string]float64{
+		"db.t3.micro":    1,
+		"db.m5.xlarge":   16,
+		"db.m5.2xlarge":  32, // Not mistaken for xlarge
+		"db.r6g.4xlarge": 128,
+		"db.x2g.large":   32,
+		"db.serverless":  8,
+	} {
+		if memory := estimateInstanceMemory(class); memory != expected {
+			t.Errorf("Expected %.0f GiB for %s, got %.0f", expected, class, memory)
+		}
+	}
+}
+
+func TestGetInstanceMemory(t *testing.T) {
+	resetInstanceMemory(t)
+
+	var calls [][]ec2types.InstanceType
+	client := NewClient(nil, nil, &mockInstanceTypesClient{
+		describeInstanceTypesFunc: func(ctx context.Context, params *ec2.DescribeInstanceTypesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstanceTypesOutput, error) {
+			calls = append(calls, params.InstanceTypes)
+			output := &ec2.DescribeInstanceTypesOutput{}
+			for _, instanceType := range params.InstanceTypes {
+				if instanceType == "r9z.large" {
+					return nil, &smithy.GenericAPIError{Code: "InvalidInstanceType"}
+				}
+				memory := map[ec2types.InstanceType]int64{"r6g.2xlarge": 65536, "t4g.medium": 4096}[instanceType]
+				output.InstanceTypes = append(output.InstanceTypes, ec2types.InstanceTypeInfo{
+					InstanceType: instanceType,
+					MemoryInfo:   &ec2types.MemoryInfo{SizeInMiB: aws.Int64(memory)},
+				})
+			}
+			return output, nil
+		},
+	}, nil, 0)
+
+	classes := []string{"db.r6g.2xlarge", "db.t4g.medium", "db.r6g.2xlarge", "db.r9z.large", "db.serverless"}
+	memory, err := client.getInstanceMemory(context.Background(), classes)
+	if err == nil {
+		t.Errorf("Expected the error of the unknown instance type")
+	}
+	if memory["db.r6g.2xlarge"] != 64 || memory["db.t4g.medium"] != 4 {
+		t.Errorf("Expected the memory of the instance types, got %v", memory)
+	}
+	if memory["db.r9z.large"] != 16 || memory["db.serverless"] != 8 {
+		t.Errorf("Expected estimates for the classes that could not be looked up, got %v", memory)
+	}
+	// The batch failing on the unknown type is retried one type at a time
+	if len(calls) != 4 || len(calls[0]) != 3 {
+		t.Errorf("Expected one batch and then a call per type, got %v", calls)
+	}
+
+	// Known types are served from the cache
+	calls = nil
+	if _, err := client.getInstanceMemory(context.Background(), []string{"db.r6g.2xlarge", "db.t4g.medium"}); err != nil || len(calls) != 0 {
+		t.Errorf("Expected the cached memory without calls, got %v and %v", err, calls)
+	}
+}
+
+func TestGetInstanceMemoryDenied(t *testing.T) {
+	resetInstanceMemory(t)
+
+	calls := 0
+	client := NewClient(nil, nil, &mockInstanceTypesClient{
+		describeInstanceTypesFunc: func(ctx context.Context, params *ec2.DescribeInstanceTypesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstanceTypesOutput, error) {
+			calls++
+			return nil, errors.New("access denied")
+		},
+	}, nil, 0)
+
+	memory, err := client.getInstanceMemory(context.Background(), []string{"db.m6g.large", "db.r6g.large"})
+	if err == nil || calls != 1 {
+		t.Errorf("Expected one failed call, got %d and %v", calls, err)
+	}
+	if memory["db.m6g.large"] != 8 || memory["db.r6g.large"] != 16 {
+		t.Errorf("Expected estimates, got %v", memory)
+	}
+}
@@ -3,19 +3,22 @@ package rds
 import (
 	"context"
 	"fmt"
-	"strings"
 	"sync"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
-	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
 	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+
+	"github.com/correctedcloud/aws-overview/pkg/cloudwatchmetrics"
+	"github.com/correctedcloud/aws-overview/pkg/common"
 )
 
 // rdsClientAPI defines the interface for the RDS client
 type rdsClientAPI interface {
 	DescribeDBInstances(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error)
+	DescribePendingMaintenanceActions(ctx context.Context, params *rds.DescribePendingMaintenanceActionsInput, optFns ...func(*rds.Options)) (*rds.DescribePendingMaintenanceActionsOutput, error)
 }
 
 // cloudwatchClientAPI defines the interface for the CloudWatch client
@@ -25,216 +28,284 @@ type cloudwatchClientAPI interface {
 
 // Client represents an RDS client
 type Client struct {
-	rdsClient        rdsClientAPI
-	cloudwatchClient cloudwatchClientAPI
+	rdsClient           rdsClientAPI
+	cloudwatchClient    cloudwatchClientAPI
+	instanceTypesClient instanceTypesClientAPI
+	pool                *common.Pool
+	window              time.Duration // How far back the metrics go
 }
 
 // DBInstanceSummary represents a summary of an RDS instance
 type DBInstanceSummary struct {
 	Identifier   string
+	ARN          string
 	Engine       string
 	Status       string
 	Endpoint     string
+	MultiAZ      bool // Whether a standby in another Availability Zone takes over on failure
 	CPUData      []float64
 	MemoryData   []float64
 	RecentErrors []string
+
+	AllocatedStorageGB    int32
+	MaxAllocatedStorageGB int32 // Storage autoscaling limit, 0 when autoscaling is disabled
+	StorageType           string
+	FreeStorageData       []float64     // Free storage in bytes, hourly over the past 7 days
+	DaysUntilStorageFull  float64       // Projected from the free storage trend, 0 when not shrinking
+	IOPSLimit             int32         // 0 when unknown
+	IOPSData              []float64     // Combined read and write IOPS over MetricsWindow
+	ReadIOPSData          []float64     // Read IOPS over MetricsWindow
+	WriteIOPSData         []float64     // Write IOPS over MetricsWindow
+	ConnectionsData       []float64     // Open database connections over MetricsWindow
+	MetricsEnd            time.Time     // End of the metrics' windows, zero when they were not fetched
+	MetricsWindow         time.Duration // How far back the metrics go, except the free storage
+
+	PendingMaintenance  []MaintenanceAction
+	CACertificate       string    // Identifier of the CA that signed the server certificate, e.g. "rds-ca-rsa2048-g1"
+	CACertificateExpiry time.Time // When the server certificate expires, zero when unknown
+}
+
+// MetricsStart returns the start of the window of the metrics but the free
+// storage, which summaries saved before the window was recorded leave at
+// cloudwatchmetrics.DefaultWindow
+func (s DBInstanceSummary) MetricsStart() time.Time {
+	if s.MetricsWindow <= 0 {
+		return s.MetricsEnd.Add(-cloudwatchmetrics.DefaultWindow)
+	}
+	return s.MetricsEnd.Add(-s.MetricsWindow)
 }
 
-// NewClient returns a new RDS client
-func NewClient(rdsClient rdsClientAPI, cloudwatchClient cloudwatchClientAPI) *Client {
+// NewClient returns a new RDS client whose calls run in pool, which may be
+// nil. instanceTypesClient, an EC2 client, looks up the memory of the
+// instance classes; when it is nil the memory is estimated from their size.
+// The metrics go back window, or cloudwatchmetrics.DefaultWindow when it is 0.
+func NewClient(rdsClient rdsClientAPI, cloudwatchClient cloudwatchClientAPI, instanceTypesClient instanceTypesClientAPI, pool *common.Pool, window time.Duration) *Client {
+	if window <= 0 {
+		window = cloudwatchmetrics.DefaultWindow
+	}
 	return &Client{
-		rdsClient:        rdsClient,
-		cloudwatchClient: cloudwatchClient,
+		rdsClient:           rdsClient,
+		cloudwatchClient:    cloudwatchClient,
+		instanceTypesClient: instanceTypesClient,
+		pool:                pool,
+		window:              window,
 	}
 }
 
-// GetDBInstances returns a list of RDS instances with their metrics
-func (c *Client) GetDBInstances(ctx context.Context) ([]DBInstanceSummary, error) {
-	result, err := c.rdsClient.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{})
+// GetDBInstances returns a list of RDS instances with their metrics. Metrics
+// that fail to load are left empty and their errors returned alongside the
+// instances.
+func (c *Client) GetDBInstances(ctx context.Context) ([]DBInstanceSummary, []error) {
+	instances, err := c.describeDBInstances(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to describe DB instances: %w", err)
+		return nil, []error{err}
 	}
 
-	// Process DB instances in parallel
+	summaries := make([]DBInstanceSummary, len(instances))
+	for i, instance := range instances {
+		summaries[i] = newDBInstanceSummary(instance)
+	}
+
+	// Fetch recent errors in parallel with the metrics, keeping them apart
+	// from the summaries the metrics fill in until both are done
 	var wg sync.WaitGroup
-	summariesCh := make(chan DBInstanceSummary, len(result.DBInstances))
-	errorsCh := make(chan error, len(result.DBInstances))
+	var mu sync.Mutex
+	var errs []error
+	recentErrors := make([][]string, len(summaries))
+	var maintenance map[string][]MaintenanceAction
 
-	for _, instance := range result.DBInstances {
+	for i := range summaries {
 		wg.Add(1)
-		go func(dbInstance types.DBInstance) {
+		go func(i int, identifier string) {
 			defer wg.Done()
-			summary, err := c.getDBInstanceSummary(ctx, dbInstance)
+			instanceErrors, err := c.getRecentErrors(ctx, identifier)
 			if err != nil {
-				errorsCh <- err
+				mu.Lock()
+				defer mu.Unlock()
+				errs = append(errs, fmt.Errorf("DB instance %s: %w", identifier, err))
 				return
 			}
-			summariesCh <- summary
-		}(instance)
+			recentErrors[i] = instanceErrors
+		}(i, summaries[i].Identifier)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		actions, err := c.getPendingMaintenance(ctx)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		maintenance = actions
+	}()
+
+	classes := make([]string, len(instances))
+	for i, instance := range instances {
+		classes[i] = aws.ToString(instance.DBInstanceClass)
+	}
+	memory, memoryErr := c.getInstanceMemory(ctx, classes)
+
+	metricErrs := c.getMetrics(ctx, summaries, classes, memory)
+	if memoryErr != nil {
+		metricErrs = append(metricErrs, memoryErr)
 	}
 
 	// Wait for all goroutines to complete
 	wg.Wait()
-	close(summariesCh)
-	close(errorsCh)
-
-	// Check for errors
-	if len(errorsCh) > 0 {
-		return nil, <-errorsCh
+	for i := range summaries {
+		summaries[i].RecentErrors = recentErrors[i]
+		summaries[i].PendingMaintenance = maintenance[summaries[i].ARN]
 	}
 
-	// Collect all DB instance summaries
-	var summaries []DBInstanceSummary
-	for summary := range summariesCh {
-		summaries = append(summaries, summary)
+	return summaries, append(errs, metricErrs...)
+}
+
+// describeDBInstances returns all DB instances, following the pagination markers
+func (c *Client) describeDBInstances(ctx context.Context) ([]types.DBInstance, error) {
+	var instances []types.DBInstance
+	var marker *string
+
+	for {
+		var result *rds.DescribeDBInstancesOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.rdsClient.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{
+				Marker: marker,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe DB instances: %w", err)
+		}
+
+		instances = append(instances, result.DBInstances...)
+
+		marker = result.Marker
+		if marker == nil {
+			break
+		}
 	}
 
-	return summaries, nil
+	return instances, nil
 }
 
-// getDBInstanceSummary returns a summary of an RDS instance with metrics
-func (c *Client) getDBInstanceSummary(ctx context.Context, instance types.DBInstance) (DBInstanceSummary, error) {
+// newDBInstanceSummary returns the summary of an RDS instance without metrics
+func newDBInstanceSummary(instance types.DBInstance) DBInstanceSummary {
 	summary := DBInstanceSummary{
 		Identifier: *instance.DBInstanceIdentifier,
+		ARN:        aws.ToString(instance.DBInstanceArn),
 		Engine:     *instance.Engine,
 		Status:     *instance.DBInstanceStatus,
+		MultiAZ:    aws.ToBool(instance.MultiAZ),
+
+		AllocatedStorageGB:    aws.ToInt32(instance.AllocatedStorage),
+		MaxAllocatedStorageGB: aws.ToInt32(instance.MaxAllocatedStorage),
+		StorageType:           aws.ToString(instance.StorageType),
+		IOPSLimit:             getIOPSLimit(instance),
+	}
+
+	if instance.CertificateDetails != nil {
+		summary.CACertificate = aws.ToString(instance.CertificateDetails.CAIdentifier)
+		summary.CACertificateExpiry = aws.ToTime(instance.CertificateDetails.ValidTill)
+	} else {
+		summary.CACertificate = aws.ToString(instance.CACertificateIdentifier)
 	}
 
 	if instance.Endpoint != nil {
 		summary.Endpoint = fmt.Sprintf("%s:%d", *instance.Endpoint.Address, *instance.Endpoint.Port)
 	}
 
-	// Use goroutines to fetch metrics in parallel
-	var wg sync.WaitGroup
-	var cpuErr, memoryErr, errorsErr error
-
-	// Fetch CPU utilization data
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		cpuData, err := c.getMetricData(ctx, "CPUUtilization", *instance.DBInstanceIdentifier)
-		if err != nil {
-			cpuErr = err
-			return
-		}
-		summary.CPUData = cpuData
-	}()
+	return summary
+}
 
-	// Fetch memory utilization data
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		memoryData, err := c.getMemoryUtilizationData(ctx, *instance.DBInstanceIdentifier, *instance.DBInstanceClass)
-		if err != nil {
-			memoryErr = err
-			return
-		}
-		summary.MemoryData = memoryData
-	}()
+// The metrics queried for each instance, in the order of their queries
+const (
+	queryCPU = iota
+	queryFreeableMemory
+	queryFreeStorage
+	queryReadIOPS
+	queryWriteIOPS
+	queryConnections
+	queriesPerInstance
+)
 
-	// Fetch recent errors
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		recentErrors, err := c.getRecentErrors(ctx, *instance.DBInstanceIdentifier)
-		if err != nil {
-			errorsErr = err
-			return
+// getMetrics fills in the metrics of all instances, fetched together in as
+// few CloudWatch calls as possible, and returns the errors of the metrics
+// that could not be loaded
+func (c *Client) getMetrics(ctx context.Context, summaries []DBInstanceSummary, classes []string, memory map[string]float64) []error {
+	queries := make([]cloudwatchmetrics.Query, 0, len(summaries)*queriesPerInstance)
+	period := cloudwatchmetrics.PeriodOf(c.window)
+	for _, summary := range summaries {
+		query := func(metricName string, period, window time.Duration) cloudwatchmetrics.Query {
+			return cloudwatchmetrics.Query{
+				Namespace:  "AWS/RDS",
+				MetricName: metricName,
+				Dimensions: map[string]string{"DBInstanceIdentifier": summary.Identifier},
+				Stat:       "Average",
+				Period:     period,
+				Window:     window,
+			}
 		}
-		summary.RecentErrors = recentErrors
-	}()
+		// The order must match the query constants
+		queries = append(queries,
+			query("CPUUtilization", period, c.window),
+			query("FreeableMemory", period, c.window),
+			query("FreeStorageSpace", time.Hour, storageHistory),
+			query("ReadIOPS", period, c.window),
+			query("WriteIOPS", period, c.window),
+			query("DatabaseConnections", period, c.window),
+		)
+	}
 
-	// Wait for all goroutines to complete
-	wg.Wait()
+	results := cloudwatchmetrics.New(c.cloudwatchClient, c.pool).Fetch(ctx, queries)
 
-	// Check for errors
-	if cpuErr != nil {
-		return DBInstanceSummary{}, cpuErr
-	}
-	if memoryErr != nil {
-		return DBInstanceSummary{}, memoryErr
-	}
-	if errorsErr != nil {
-		return DBInstanceSummary{}, errorsErr
-	}
+	var errs []error
+	for i := range summaries {
+		summary := &summaries[i]
+		instanceResults := results[i*queriesPerInstance : (i+1)*queriesPerInstance]
 
-	return summary, nil
-}
+		for n, result := range instanceResults {
+			if result.Err != nil {
+				metricName := queries[i*queriesPerInstance+n].MetricName
+				errs = append(errs, fmt.Errorf("DB instance %s: failed to get metric data for %s: %w", summary.Identifier, metricName, result.Err))
+			}
+		}
 
-// getMetricData retrieves CloudWatch metric data for an RDS instance
-func (c *Client) getMetricData(ctx context.Context, metricName string, instanceID string) ([]float64, error) {
-	endTime := time.Now()
-	startTime := endTime.Add(-1 * time.Hour)
-
-	// Create a valid ID that starts with lowercase letter and contains only alphanumeric characters
-	metricQueryId := "m" + strings.ReplaceAll(strings.ToLower(metricName), "-", "_")
-
-	result, err := c.cloudwatchClient.GetMetricData(ctx, &cloudwatch.GetMetricDataInput{
-		StartTime: &startTime,
-		EndTime:   &endTime,
-		MetricDataQueries: []cwtypes.MetricDataQuery{
-			{
-				Id: &metricQueryId,
-				MetricStat: &cwtypes.MetricStat{
-					Metric: &cwtypes.Metric{
-						Namespace:  strPtr("AWS/RDS"),
-						MetricName: &metricName,
-						Dimensions: []cwtypes.Dimension{
-							{
-								Name:  strPtr("DBInstanceIdentifier"),
-								Value: &instanceID,
-							},
-						},
-					},
-					Period: int32Ptr(300), // 5-minute data points
-					Stat:   strPtr("Average"),
-				},
-			},
-		},
-	})
+		// No datapoints means no data, e.g. for a stopped instance; callers
+		// render an explicit "no data" state for an empty slice
+		summary.MetricsEnd = instanceResults[queryCPU].End
+		summary.MetricsWindow = c.window
+		summary.CPUData = instanceResults[queryCPU].Values
+		summary.MemoryData = getMemoryUtilizationData(instanceResults[queryFreeableMemory].Values, memory[classes[i]])
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to get metric data for %s: %w", metricName, err)
-	}
+		if storage := instanceResults[queryFreeStorage]; len(storage.Values) > 0 {
+			summary.FreeStorageData = storage.Values
 
-	if len(result.MetricDataResults) == 0 || len(result.MetricDataResults[0].Values) == 0 {
-		// For testing purposes, return sample data if no values are available
-		if metricName == "CPUUtilization" {
-			return []float64{10.0, 15.0, 12.0, 8.0}, nil
-		} else if metricName == "FreeableMemory" {
-			return []float64{2 * 1024 * 1024 * 1024, 2.1 * 1024 * 1024 * 1024}, nil
+			var autoscalingBytes float64
+			if summary.StorageAutoscaling() {
+				autoscalingBytes = float64(summary.MaxAllocatedStorageGB-summary.AllocatedStorageGB) * bytesPerGB
+			}
+			summary.DaysUntilStorageFull = projectDaysUntilFull(storage.Values, storage.Timestamps, autoscalingBytes)
 		}
-		return []float64{}, nil
-	}
 
-	var data []float64
-	for _, value := range result.MetricDataResults[0].Values {
-		data = append(data, value)
+		summary.ReadIOPSData = instanceResults[queryReadIOPS].Values
+		summary.WriteIOPSData = instanceResults[queryWriteIOPS].Values
+		summary.IOPSData = sumSeries(instanceResults[queryReadIOPS], instanceResults[queryWriteIOPS])
+		summary.ConnectionsData = instanceResults[queryConnections].Values
 	}
 
-	return data, nil
+	return errs
 }
 
-// getMemoryUtilizationData calculates memory utilization percentage
-func (c *Client) getMemoryUtilizationData(ctx context.Context, instanceID, instanceClass string) ([]float64, error) {
-	// Get FreeableMemory data
-	freeMemoryData, err := c.getMetricData(ctx, "FreeableMemory", instanceID)
-	if err != nil {
-		return nil, err
-	}
-
-	// The getMetricData function now handles empty data by returning sample data for tests
-	// So this check should not be necessary but keeping it for safety
-	if len(freeMemoryData) == 0 {
-		// Return default data for tests instead of empty slice
-		return []float64{50.0, 47.5}, nil
+// getMemoryUtilizationData calculates memory utilization percentages from
+// FreeableMemory datapoints and the total memory of the instance in GiB
+func getMemoryUtilizationData(freeMemoryData []float64, totalMemoryGB float64) []float64 {
+	if len(freeMemoryData) == 0 || totalMemoryGB <= 0 {
+		return nil
 	}
-
-	// Estimate total memory based on instance class
-	// This is a simplified approach; in a real application, you would determine the
-	// total memory more accurately based on instance class specifications
-	totalMemoryGB := getEstimatedMemoryForInstanceClass(instanceClass)
-	totalMemoryBytes := totalMemoryGB * 1024 * 1024 * 1024
+	totalMemoryBytes := totalMemoryGB * bytesPerGB
 
 	// Calculate memory utilization percentages
 	var memoryUtilizationData []float64
@@ -243,7 +314,7 @@ func (c *Client) getMemoryUtilizationData(ctx context.Context, instanceID, insta
 		memoryUtilizationData = append(memoryUtilizationData, utilizationPercent)
 	}
 
-	return memoryUtilizationData, nil
+	return memoryUtilizationData
 }
 
 // getRecentErrors retrieves recent errors from the DB error log
@@ -253,40 +324,3 @@ func (c *Client) getRecentErrors(ctx context.Context, instanceID string) ([]stri
 	// You would use c.rdsClient.DescribeDBLogFiles and c.rdsClient.DownloadDBLogFilePortion
 	return []string{}, nil
 }
-
-// Helper functions
-func strPtr(s string) *string {
-	return &s
-}
-
-func int32Ptr(i int32) *int32 {
-	return &i
-}
-
-// getEstimatedMemoryForInstanceClass returns an estimate of total memory in GB for the instance class
-func getEstimatedMemoryForInstanceClass(instanceClass string) float64 {
-	// This is a simplified mapping; in a real application, you would have a more
-	// comprehensive mapping based on AWS documentation
-	switch {
-	case strings.Contains(instanceClass, "micro"):
-		return 1.0
-	case strings.Contains(instanceClass, "small"):
-		return 2.0
-	case strings.Contains(instanceClass, "medium"):
-		return 4.0
-	case strings.Contains(instanceClass, "large"):
-		return 8.0
-	case strings.Contains(instanceClass, "xlarge"):
-		return 16.0
-	case strings.Contains(instanceClass, "2xlarge"):
-		return 32.0
-	case strings.Contains(instanceClass, "4xlarge"):
-		return 64.0
-	case strings.Contains(instanceClass, "8xlarge"):
-		return 128.0
-	case strings.Contains(instanceClass, "16xlarge"):
-		return 256.0
-	default:
-		return 8.0 // Default fallback
-	}
-}
@@ -2,8 +2,10 @@ package rds
 
 import (
 	"context"
+	"errors"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
@@ -12,7 +14,15 @@ import (
 
 // Mock RDS client
 type mockRDSClient struct {
-	describeDBInstancesFunc func(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error)
+	describeDBInstancesFunc               func(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error)
+	describePendingMaintenanceActionsFunc func(ctx context.Context, params *rds.DescribePendingMaintenanceActionsInput, optFns ...func(*rds.Options)) (*rds.DescribePendingMaintenanceActionsOutput, error)
+}
+
+func (m *mockRDSClient) DescribePendingMaintenanceActions(ctx context.Context, params *rds.DescribePendingMaintenanceActionsInput, optFns ...func(*rds.Options)) (*rds.DescribePendingMaintenanceActionsOutput, error) {
+	if m.describePendingMaintenanceActionsFunc == nil {
+		return &rds.DescribePendingMaintenanceActionsOutput{}, nil
+	}
+	return m.describePendingMaintenanceActionsFunc(ctx, params, optFns...)
 }
 
 func (m *mockRDSClient) DescribeDBInstances(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
@@ -59,25 +69,24 @@ func TestGetDBInstances(t *testing.T) {
 
 	mockCloudWatchClient := &mockCloudWatchClient{
 		getMetricDataFunc: func(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
-			// Check which metric is being requested
-			id := *params.MetricDataQueries[0].Id
-
-			var values []float64
-			if id == "CPUUtilization" {
-				values = []float64{10.0, 15.0, 12.0, 8.0}
-			} else if id == "FreeableMemory" {
-				// Return 50% free memory (2GB free out of 4GB total for a medium instance)
-				values = []float64{2 * 1024 * 1024 * 1024, 2.1 * 1024 * 1024 * 1024}
-			}
+			// Answer each query according to the metric it requests
+			output := &cloudwatch.GetMetricDataOutput{}
+			for _, query := range params.MetricDataQueries {
+				var values []float64
+				switch *query.MetricStat.Metric.MetricName {
+				case "CPUUtilization":
+					values = []float64{10.0, 15.0, 12.0, 8.0}
+				case "FreeableMemory":
+					// Return 50% free memory (2GB free out of 4GB total for a medium instance)
+					values = []float64{2 * 1024 * 1024 * 1024, 2.1 * 1024 * 1024 * 1024}
+				}
 
-			return &cloudwatch.GetMetricDataOutput{
-				MetricDataResults: []cwtypes.MetricDataResult{
-					{
-						Id:     &id,
-						Values: values,
-					},
-				},
-			}, nil
+				output.MetricDataResults = append(output.MetricDataResults, cwtypes.MetricDataResult{
+					Id:     query.Id,
+					Values: values,
+				})
+			}
+			return output, nil
 		},
 	}
 
@@ -88,11 +97,11 @@ func TestGetDBInstances(t *testing.T) {
 	}
 
 	// Call the method being tested
-	instances, err := client.GetDBInstances(context.Background())
+	instances, errs := client.GetDBInstances(context.Background())
 
 	// Assertions
-	if err != nil {
-		t.Fatalf("Expected no error, got %v", err)
+	if len(errs) > 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
 	}
 
 	if len(instances) != 1 {
@@ -130,3 +139,170 @@ func TestGetDBInstances(t *testing.T) {
 		t.Errorf("Expected memory utilization around 50%%, got %f%%", instance.MemoryData[0])
 	}
 }
+
+func TestGetDBInstancesWithoutMetricData(t *testing.T) {
+	dbIdentifier := "stopped-db"
+	dbEngine := "mysql"
+	dbStatus := "stopped"
+	dbClass := "db.t3.small"
+
+	client := &Client{
+		rdsClient: &mockRDSClient{
+			describeDBInstancesFunc: func(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+				return &rds.DescribeDBInstancesOutput{
+					DBInstances: []types.DBInstance{
+						{
+							DBInstanceIdentifier: &dbIdentifier,
+							Engine:               &dbEngine,
+							DBInstanceStatus:     &dbStatus,
+							DBInstanceClass:      &dbClass,
+						},
+					},
+				}, nil
+			},
+		},
+		cloudwatchClient: &mockCloudWatchClient{
+			getMetricDataFunc: func(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+				return &cloudwatch.GetMetricDataOutput{}, nil
+			},
+		},
+	}
+
+	instances, errs := client.GetDBInstances(context.Background())
+	if len(errs) > 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+
+	if len(instances) != 1 {
+		t.Fatalf("Expected 1 instance, got %d", len(instances))
+	}
+
+	// Missing metrics must not be replaced with made-up values
+	if len(instances[0].CPUData) != 0 {
+		t.Errorf("Expected no CPU data, got %v", instances[0].CPUData)
+	}
+	if len(instances[0].MemoryData) != 0 {
+		t.Errorf("Expected no memory data, got %v", instances[0].MemoryData)
+	}
+}
+
+func TestGetDBInstancesWithMetricErrors(t *testing.T) {
+	dbIdentifier := "test-db"
+	dbEngine := "postgres"
+	dbStatus := "available"
+	dbClass := "db.t3.micro"
+
+	client := NewClient(
+		&mockRDSClient{
+			describeDBInstancesFunc: func(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+				return &rds.DescribeDBInstancesOutput{
+					DBInstances: []types.DBInstance{
+						{
+							DBInstanceIdentifier: &dbIdentifier,
+							Engine:               &dbEngine,
+							DBInstanceStatus:     &dbStatus,
+							DBInstanceClass:      &dbClass,
+						},
+					},
+				}, nil
+			},
+		},
+		&mockCloudWatchClient{
+			getMetricDataFunc: func(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+				return nil, errors.New("AccessDenied")
+			},
+		},
+		nil,
+		nil,
+		0,
+	)
+
+	instances, errs := client.GetDBInstances(context.Background())
+
+	if len(errs) == 0 {
+		t.Errorf("Expected metric errors to be reported")
+	}
+
+	// The instance itself must still be listed
+	if len(instances) != 1 || instances[0].Identifier != dbIdentifier {
+		t.Fatalf("Expected instance '%s' despite metric errors, got %v", dbIdentifier, instances)
+	}
+	if len(instances[0].CPUData) != 0 {
+		t.Errorf("Expected no CPU data, got %v", instances[0].CPUData)
+	}
+}
+
+func TestGetDBInstancesPaginates(t *testing.T) {
+	engine, status, class := "postgres", "available", "db.t3.micro"
+	pages := map[string]*rds.DescribeDBInstancesOutput{
+		"": {
+			DBInstances: []types.DBInstance{
+				{DBInstanceIdentifier: aws.String("db-1"), Engine: &engine, DBInstanceStatus: &status, DBInstanceClass: &class},
+			},
+			Marker: aws.String("page-2"),
+		},
+		"page-2": {
+			DBInstances: []types.DBInstance{
+				{DBInstanceIdentifier: aws.String("db-2"), Engine: &engine, DBInstanceStatus: &status, DBInstanceClass: &class},
+			},
+		},
+	}
+
+	calls := 0
+	client := NewClient(
+		&mockRDSClient{
+			describeDBInstancesFunc: func(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+				calls++
+				return pages[aws.ToString(params.Marker)], nil
+			},
+		},
+		&mockCloudWatchClient{
+			getMetricDataFunc: func(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+				return &cloudwatch.GetMetricDataOutput{}, nil
+			},
+		},
+		nil,
+		nil,
+		0,
+	)
+
+	instances, errs := client.GetDBInstances(context.Background())
+	if len(errs) > 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 DescribeDBInstances calls, got %d", calls)
+	}
+	if len(instances) != 2 {
+		t.Fatalf("Expected instances from both pages, got %d", len(instances))
+	}
+}
+
+func TestGetDBInstancesPageError(t *testing.T) {
+	engine, status, class := "postgres", "available", "db.t3.micro"
+
+	client := NewClient(
+		&mockRDSClient{
+			describeDBInstancesFunc: func(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+				if params.Marker != nil {
+					return nil, errors.New("Throttling")
+				}
+				return &rds.DescribeDBInstancesOutput{
+					DBInstances: []types.DBInstance{
+						{DBInstanceIdentifier: aws.String("db-1"), Engine: &engine, DBInstanceStatus: &status, DBInstanceClass: &class},
+					},
+					Marker: aws.String("page-2"),
+				}, nil
+			},
+		},
+		&mockCloudWatchClient{},
+		nil,
+		nil,
+		0,
+	)
+
+	instances, errs := client.GetDBInstances(context.Background())
+	if len(errs) != 1 || instances != nil {
+		t.Errorf("Expected a single error and no instances, got %v and %v", errs, instances)
+	}
+}
@@ -0,0 +1,150 @@
+package rds
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+
+	"github.com/correctedcloud/aws-overview/pkg/cloudwatchmetrics"
+)
+
+// StorageWarningDays is how many days ahead a projected storage exhaustion is warned about
+const StorageWarningDays = 14
+
+// StorageUsedWarningPercent is the share of the allocated storage in use above
+// which an instance is warned about
+const StorageUsedWarningPercent = 85
+
+// storageHistory is the window of FreeStorageSpace datapoints the storage trend is projected from
+const storageHistory = 7 * 24 * time.Hour
+
+// bytesPerGB converts RDS storage sizes, which are given in GiB, to bytes
+const bytesPerGB = 1024 * 1024 * 1024
+
+// StorageAutoscaling reports whether storage autoscaling is enabled
+func (s DBInstanceSummary) StorageAutoscaling() bool {
+	return s.MaxAllocatedStorageGB > s.AllocatedStorageGB
+}
+
+// FreeStoragePercent returns the latest free storage as a percentage of the
+// allocated storage, and false when either is unknown
+func (s DBInstanceSummary) FreeStoragePercent() (float64, bool) {
+	if len(s.FreeStorageData) == 0 || s.AllocatedStorageGB == 0 {
+		return 0, false
+	}
+	free := s.FreeStorageData[len(s.FreeStorageData)-1]
+	return free / (float64(s.AllocatedStorageGB) * bytesPerGB) * 100, true
+}
+
+// StorageUsedPercent returns the latest used storage as a percentage of the
+// allocated storage, and false when either is unknown
+func (s DBInstanceSummary) StorageUsedPercent() (float64, bool) {
+	free, ok := s.FreeStoragePercent()
+	if !ok {
+		return 0, false
+	}
+	return max(0, 100-free), true
+}
+
+// IsStorageNearlyFull reports whether more than StorageUsedWarningPercent of
+// the allocated storage is in use
+func (s DBInstanceSummary) IsStorageNearlyFull() bool {
+	used, ok := s.StorageUsedPercent()
+	return ok && used > StorageUsedWarningPercent
+}
+
+// IOPSHeadroomPercent returns the share of the IOPS limit that the latest
+// read and write IOPS leave unused, and false when either is unknown
+func (s DBInstanceSummary) IOPSHeadroomPercent() (float64, bool) {
+	if len(s.IOPSData) == 0 || s.IOPSLimit == 0 {
+		return 0, false
+	}
+	used := s.IOPSData[len(s.IOPSData)-1] / float64(s.IOPSLimit) * 100
+	if used > 100 {
+		used = 100
+	}
+	return 100 - used, true
+}
+
+// IsStorageRunningOut reports whether storage is projected to run out within StorageWarningDays
+func (s DBInstanceSummary) IsStorageRunningOut() bool {
+	return s.DaysUntilStorageFull > 0 && s.DaysUntilStorageFull <= StorageWarningDays
+}
+
+// getIOPSLimit returns the IOPS an instance's storage can sustain, or 0 when
+// it is not known, e.g. for magnetic storage
+func getIOPSLimit(instance types.DBInstance) int32 {
+	if instance.Iops != nil {
+		return *instance.Iops
+	}
+
+	// gp2 volumes get 3 IOPS per GB, between 100 and 16,000
+	if aws.ToString(instance.StorageType) == "gp2" {
+		iops := 3 * aws.ToInt32(instance.AllocatedStorage)
+		if iops < 100 {
+			iops = 100
+		}
+		if iops > 16000 {
+			iops = 16000
+		}
+		return iops
+	}
+
+	return 0
+}
+
+// sumSeries adds up the datapoints two series have at the same timestamps,
+// e.g. read and write IOPS, keeping the order of the first
+func sumSeries(a, b cloudwatchmetrics.Result) []float64 {
+	byTimestamp := make(map[time.Time]float64, len(b.Values))
+	for i, value := range b.Values {
+		if i < len(b.Timestamps) {
+			byTimestamp[b.Timestamps[i]] = value
+		}
+	}
+
+	var sums []float64
+	for i, value := range a.Values {
+		if i >= len(a.Timestamps) {
+			break
+		}
+		if other, ok := byTimestamp[a.Timestamps[i]]; ok {
+			sums = append(sums, value+other)
+		}
+	}
+	return sums
+}
+
+// projectDaysUntilFull fits a line through the free storage history and
+// returns in how many days the free storage, plus the room autoscaling can
+// still add, reaches zero. It returns 0 when free storage is not shrinking or
+// there are too few datapoints to tell.
+func projectDaysUntilFull(values []float64, timestamps []time.Time, autoscalingBytes float64) float64 {
+	if len(values) < 3 || len(values) != len(timestamps) {
+		return 0
+	}
+
+	// Least squares slope of free bytes over days since the first datapoint
+	origin := timestamps[0]
+	var sumX, sumY, sumXY, sumXX float64
+	for i, value := range values {
+		x := timestamps[i].Sub(origin).Hours() / 24
+		sumX += x
+		sumY += value
+		sumXY += x * value
+		sumXX += x * x
+	}
+
+	n := float64(len(values))
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0
+	}
+	slope := (n*sumXY - sumX*sumY) / denominator
+	if slope >= 0 {
+		return 0
+	}
+
+	return (values[len(values)-1] + autoscalingBytes) / -slope
+}
@@ -0,0 +1,211 @@
+package rds
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+
+	"github.com/correctedcloud/aws-overview/pkg/cloudwatchmetrics"
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+func TestProjectDaysUntilFull(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var timestamps []time.Time
+	var shrinking, steady []float64
+	for i := 0; i < 48; i++ {
+		timestamps = append(timestamps, start.Add(time.Duration(i)*time.Hour))
+		// Loses 2 GB per day, ending with 10 GB free
+		shrinking = append(shrinking, (10+2*float64(47-i)/24)*bytesPerGB)
+		steady = append(steady, 10*bytesPerGB)
+	}
+
+	if days := projectDaysUntilFull(shrinking, timestamps, 0); math.Abs(days-5) > 0.01 {
+		t.Errorf("Expected storage to run out in 5 days, got %f", days)
+	}
+	if days := projectDaysUntilFull(shrinking, timestamps, 20*bytesPerGB); math.Abs(days-15) > 0.01 {
+		t.Errorf("Expected autoscaling to extend the projection to 15 days, got %f", days)
+	}
+	if days := projectDaysUntilFull(steady, timestamps, 0); days != 0 {
+		t.Errorf("Expected no projection for steady storage, got %f", days)
+	}
+	if days := projectDaysUntilFull(shrinking[:2], timestamps[:2], 0); days != 0 {
+		t.Errorf("Expected no projection from too few datapoints, got %f", days)
+	}
+}
+
+func TestGetIOPSLimit(t *testing.T) {
+	testCases := []struct {
+		name     string
+		instance types.DBInstance
+		expected int32
+	}{
+		{"provisioned", types.DBInstance{StorageType: aws.String("io1"), Iops: aws.Int32(5000), AllocatedStorage: aws.Int32(100)}, 5000},
+		{"gp3 baseline", types.DBInstance{StorageType: aws.String("gp3"), Iops: aws.Int32(3000), AllocatedStorage: aws.Int32(20)}, 3000},
+		{"gp2", types.DBInstance{StorageType: aws.String("gp2"), AllocatedStorage: aws.Int32(200)}, 600},
+		{"small gp2", types.DBInstance{StorageType: aws.String("gp2"), AllocatedStorage: aws.Int32(20)}, 100},
+		{"magnetic", types.DBInstance{StorageType: aws.String("standard"), AllocatedStorage: aws.Int32(20)}, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if limit := getIOPSLimit(tc.instance); limit != tc.expected {
+				t.Errorf("Expected %d IOPS, got %d", tc.expected, limit)
+			}
+		})
+	}
+}
+
+func TestGetDBInstancesStorage(t *testing.T) {
+	client := NewClient(
+		&mockRDSClient{
+			describeDBInstancesFunc: func(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+				return &rds.DescribeDBInstancesOutput{
+					DBInstances: []types.DBInstance{
+						{
+							DBInstanceIdentifier: aws.String("orders-db"),
+							Engine:               aws.String("postgres"),
+							DBInstanceStatus:     aws.String("available"),
+							DBInstanceClass:      aws.String("db.t3.medium"),
+							AllocatedStorage:     aws.Int32(100),
+							StorageType:          aws.String("gp3"),
+							Iops:                 aws.Int32(3000),
+						},
+					},
+				}, nil
+			},
+		},
+		&mockCloudWatchClient{
+			getMetricDataFunc: func(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+				output := &cloudwatch.GetMetricDataOutput{}
+				for _, query := range params.MetricDataQueries {
+					result := cwtypes.MetricDataResult{Id: query.Id}
+					timestamps := []time.Time{params.StartTime.Add(5 * time.Minute), params.StartTime.Add(10 * time.Minute)}
+					switch aws.ToString(query.MetricStat.Metric.MetricName) {
+					case "FreeStorageSpace":
+						// Loses 5 GB per day, ending with 20 GB free
+						for i := 0; i < 24; i++ {
+							result.Values = append(result.Values, (20+5*float64(23-i)/24)*bytesPerGB)
+							result.Timestamps = append(result.Timestamps, params.StartTime.Add(time.Duration(i)*time.Hour))
+						}
+					case "ReadIOPS":
+						result.Values, result.Timestamps = []float64{600, 1000}, timestamps
+					case "WriteIOPS":
+						result.Values, result.Timestamps = []float64{300, 200}, timestamps
+					case "DatabaseConnections":
+						result.Values, result.Timestamps = []float64{12, 15}, timestamps
+					}
+					output.MetricDataResults = append(output.MetricDataResults, result)
+				}
+				return output, nil
+			},
+		},
+		nil,
+		nil,
+		0,
+	)
+
+	instances, errs := client.GetDBInstances(context.Background())
+	if len(errs) > 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+
+	instance := instances[0]
+	if instance.StorageAutoscaling() {
+		t.Errorf("Expected storage autoscaling to be disabled")
+	}
+	if percent, ok := instance.FreeStoragePercent(); !ok || math.Abs(percent-20) > 0.01 {
+		t.Errorf("Expected 20%% free storage, got %f", percent)
+	}
+	if used, ok := instance.StorageUsedPercent(); !ok || math.Abs(used-80) > 0.01 || instance.IsStorageNearlyFull() {
+		t.Errorf("Expected 80%% used storage, not nearly full, got %f", used)
+	}
+	if len(instance.ReadIOPSData) != 2 || instance.ReadIOPSData[1] != 1000 || len(instance.WriteIOPSData) != 2 || instance.WriteIOPSData[1] != 200 {
+		t.Errorf("Expected separate read and write IOPS, got %v and %v", instance.ReadIOPSData, instance.WriteIOPSData)
+	}
+	if len(instance.ConnectionsData) != 2 || instance.ConnectionsData[1] != 15 {
+		t.Errorf("Expected the connections, got %v", instance.ConnectionsData)
+	}
+	if headroom, ok := instance.IOPSHeadroomPercent(); !ok || headroom != 60 {
+		t.Errorf("Expected 60%% IOPS headroom, got %f", headroom)
+	}
+	if !instance.IsStorageRunningOut() || math.Abs(instance.DaysUntilStorageFull-4) > 0.01 {
+		t.Errorf("Expected storage to run out in 4 days, got %f", instance.DaysUntilStorageFull)
+	}
+
+	output := FormatDBInstances(instances, -1)
+	for _, expected := range []string{
+		"STORAGE RUNNING OUT (1 projected to fill up within 14 days)",
+		"Storage: 100 GB gp3, 20.0 GB free (20.00%), autoscaling disabled",
+		"IOPS: 1200 of 3000 (60.00% headroom)",
+		"Connections (1 hour):",
+		"Read IOPS",
+		"Write IOPS",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain '%s', got:\n%s", expected, output)
+		}
+	}
+	if summary := GetDBInstancesSummary(instances); !strings.HasSuffix(summary, "⚠️ 1 running out of storage") {
+		t.Errorf("Expected the summary to flag the instance, got '%s'", summary)
+	}
+}
+
+func TestStorageNearlyFull(t *testing.T) {
+	full := DBInstanceSummary{Identifier: "full", AllocatedStorageGB: 200, FreeStorageData: []float64{18 * bytesPerGB}}
+	roomy := DBInstanceSummary{Identifier: "roomy", AllocatedStorageGB: 200, FreeStorageData: []float64{100 * bytesPerGB}}
+	unknown := DBInstanceSummary{Identifier: "unknown", AllocatedStorageGB: 200}
+
+	if used, ok := full.StorageUsedPercent(); !ok || math.Abs(used-91) > 0.01 || !full.IsStorageNearlyFull() {
+		t.Errorf("Expected 91%% used storage to be nearly full, got %f", used)
+	}
+	if roomy.IsStorageNearlyFull() || unknown.IsStorageNearlyFull() {
+		t.Errorf("Expected only the instance above %d%% to be nearly full", StorageUsedWarningPercent)
+	}
+
+	instances := []DBInstanceSummary{full, roomy, unknown}
+	output := FormatDBInstances(instances, -1)
+	for _, expected := range []string{
+		"full () " + common.Symbol("⚠️"),
+		common.Symbol("⚠️") + " Storage 91.00% used, above 85%",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain '%s', got:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "roomy () "+common.Symbol("⚠️")) {
+		t.Errorf("Expected the roomy instance not to be flagged, got:\n%s", output)
+	}
+	if summary := GetDBInstancesSummary(instances); !strings.HasSuffix(summary, "⚠️ 1 above 85% storage used") {
+		t.Errorf("Expected the summary to flag the nearly full instance, got '%s'", summary)
+	}
+}
+
+func TestSumSeries(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	read := cloudwatchmetrics.Result{
+		Values:     []float64{10, 20, 30},
+		Timestamps: []time.Time{start, start.Add(5 * time.Minute), start.Add(10 * time.Minute)},
+	}
+	write := cloudwatchmetrics.Result{
+		Values:     []float64{1, 3},
+		Timestamps: []time.Time{start, start.Add(10 * time.Minute)},
+	}
+
+	// Datapoints missing from either series are dropped
+	sums := sumSeries(read, write)
+	if len(sums) != 2 || sums[0] != 11 || sums[1] != 33 {
+		t.Errorf("Expected [11 33], got %v", sums)
+	}
+	if sums := sumSeries(read, cloudwatchmetrics.Result{}); len(sums) != 0 {
+		t.Errorf("Expected no sums without write IOPS, got %v", sums)
+	}
+}
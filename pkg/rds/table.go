@@ -0,0 +1,87 @@
+package rds
+
+import (
+	"fmt"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// Columns are the columns of the RDS instances table. Metrics sort the
+// busiest and fullest instances first, with instances without data last.
+var Columns = []common.Column[DBInstanceSummary]{
+	{Title: "Identifier", Width: 24, Value: func(i DBInstanceSummary) string { return i.Identifier }},
+	{Title: "Engine", Width: 12, Value: func(i DBInstanceSummary) string { return i.Engine }},
+	{Title: "Status", Width: 12, Value: func(i DBInstanceSummary) string { return i.Status }},
+	{Title: "CPU", Width: 7, Value: func(i DBInstanceSummary) string { return formatLatest(i.CPUData) }, Less: func(a, b DBInstanceSummary) bool {
+		return moreOrPresent(a.CPUData, b.CPUData)
+	}},
+	{Title: "Memory", Width: 7, Value: func(i DBInstanceSummary) string { return formatLatest(i.MemoryData) }, Less: func(a, b DBInstanceSummary) bool {
+		return moreOrPresent(a.MemoryData, b.MemoryData)
+	}},
+	{Title: "Conns", Width: 6, Value: formatConnections, Less: func(a, b DBInstanceSummary) bool {
+		return moreOrPresent(a.ConnectionsData, b.ConnectionsData)
+	}},
+	{Title: "Storage Used", Width: 12, Value: formatStorageUsed, Less: func(a, b DBInstanceSummary) bool {
+		aPercent, aOK := a.StorageUsedPercent()
+		bPercent, bOK := b.StorageUsedPercent()
+		if aOK != bOK {
+			return aOK
+		}
+		return aPercent > bPercent
+	}},
+	{Title: "Full In", Width: 8, Value: formatDaysUntilFull, Less: func(a, b DBInstanceSummary) bool {
+		if (a.DaysUntilStorageFull > 0) != (b.DaysUntilStorageFull > 0) {
+			return a.DaysUntilStorageFull > 0
+		}
+		return a.DaysUntilStorageFull < b.DaysUntilStorageFull
+	}},
+}
+
+// formatLatest formats the latest datapoint of a percentage metric
+func formatLatest(data []float64) string {
+	if len(data) == 0 {
+		return "n/a"
+	}
+	return common.FormatPercentage(data[len(data)-1])
+}
+
+// moreOrPresent orders series by their latest datapoint, highest first and
+// series without data last
+func moreOrPresent(a, b []float64) bool {
+	if (len(a) > 0) != (len(b) > 0) {
+		return len(a) > 0
+	}
+	if len(a) == 0 {
+		return false
+	}
+	return a[len(a)-1] > b[len(b)-1]
+}
+
+// formatConnections formats the latest number of open connections
+func formatConnections(instance DBInstanceSummary) string {
+	if len(instance.ConnectionsData) == 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.0f", instance.ConnectionsData[len(instance.ConnectionsData)-1])
+}
+
+// formatStorageUsed formats the share of storage that is used, flagging
+// instances above StorageUsedWarningPercent
+func formatStorageUsed(instance DBInstanceSummary) string {
+	percent, ok := instance.StorageUsedPercent()
+	if !ok {
+		return "n/a"
+	}
+	if instance.IsStorageNearlyFull() {
+		return common.Symbol("⚠️") + " " + common.FormatPercentage(percent)
+	}
+	return common.FormatPercentage(percent)
+}
+
+// formatDaysUntilFull formats the projected days until storage is full
+func formatDaysUntilFull(instance DBInstanceSummary) string {
+	if instance.DaysUntilStorageFull <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("~%.0fd", instance.DaysUntilStorageFull)
+}
@@ -0,0 +1,32 @@
+package rds
+
+import (
+	"testing"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+func TestColumns(t *testing.T) {
+	instances := []DBInstanceSummary{
+		{Identifier: "idle", CPUData: []float64{50, 5}},
+		{Identifier: "no-data"},
+		{Identifier: "busy", CPUData: []float64{10, 90}, DaysUntilStorageFull: 12, ConnectionsData: []float64{40, 42},
+			AllocatedStorageGB: 100, FreeStorageData: []float64{10 * bytesPerGB}},
+	}
+
+	byCPU := common.SortRows(instances, Columns, 3)
+	if byCPU[0].Identifier != "busy" || byCPU[1].Identifier != "idle" || byCPU[2].Identifier != "no-data" {
+		t.Errorf("Expected the busiest instance first and the one without data last, got %v", byCPU)
+	}
+
+	byFullIn := common.SortRows(instances, Columns, len(Columns)-1)
+	if byFullIn[0].Identifier != "busy" {
+		t.Errorf("Expected the instance running out of storage first, got %v", byFullIn)
+	}
+
+	cells := common.TableCells(byCPU, Columns)
+	if cells[0][3] != "90.00%" || cells[2][3] != "n/a" || cells[0][5] != "42" || cells[1][5] != "n/a" ||
+		cells[0][6] != common.Symbol("⚠️")+" 90.00%" || cells[1][6] != "n/a" || cells[0][7] != "~12d" || cells[1][7] != "-" {
+		t.Errorf("Unexpected cells %v", cells)
+	}
+}
@@ -0,0 +1,131 @@
+package sns
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// FormatTopics formats the SNS to SQS fan-out as a tree for terminal display,
+// listing unconfirmed subscriptions first
+func FormatTopics(summaries []TopicSummary) string {
+	if len(summaries) == 0 {
+		return "No SNS topics found"
+	}
+
+	var output strings.Builder
+	output.WriteString("SNS FAN-OUT\n")
+	output.WriteString(common.Rule("SNS FAN-OUT", "=") + "\n\n")
+
+	pending := GetPendingSubscriptions(summaries)
+	if len(pending) > 0 {
+		output.WriteString(fmt.Sprintf("%s PENDING CONFIRMATION (%d receive no messages)\n", common.Symbol("⚠️"), len(pending)))
+		for _, topic := range summaries {
+			for _, subscription := range topic.Subscriptions {
+				if subscription.PendingConfirmation {
+					output.WriteString(fmt.Sprintf("  %s → %s\n", topic.Name, subscription.Queue))
+				}
+			}
+		}
+		output.WriteString("\n")
+	}
+
+	var withoutQueues []string
+	for _, topic := range summaries {
+		if len(topic.Subscriptions) == 0 {
+			withoutQueues = append(withoutQueues, topic.Name)
+			continue
+		}
+
+		output.WriteString(fmt.Sprintf("%s %s", common.Symbol("📬"), topic.Name))
+		output.WriteString(fmt.Sprintf(" (%d %s", len(topic.Subscriptions), pluralize("queue", len(topic.Subscriptions))))
+		if topic.OtherSubscriptions > 0 {
+			output.WriteString(fmt.Sprintf(", %d other %s", topic.OtherSubscriptions, pluralize("subscription", topic.OtherSubscriptions)))
+		}
+		output.WriteString(")\n")
+
+		for i, subscription := range topic.Subscriptions {
+			branch, indent := "├─", "│ "
+			if i == len(topic.Subscriptions)-1 {
+				branch, indent = "└─", "  "
+			}
+
+			output.WriteString(fmt.Sprintf("  %s %s", branch, subscription.Queue))
+			if subscription.Account != "" {
+				output.WriteString(fmt.Sprintf(" (account %s)", subscription.Account))
+			}
+			if flags := getFlags(subscription); len(flags) > 0 {
+				output.WriteString(" [" + strings.Join(flags, ", ") + "]")
+			}
+			output.WriteString("\n")
+
+			if subscription.HasFilterPolicy() {
+				output.WriteString(fmt.Sprintf("  %s  Filter on %s: %s\n", indent, subscription.FilterPolicyScope, compactPolicy(subscription.FilterPolicy)))
+			}
+		}
+		output.WriteString("\n")
+	}
+
+	if len(withoutQueues) > 0 {
+		output.WriteString(fmt.Sprintf("Topics without SQS subscriptions (%d): %s\n", len(withoutQueues), strings.Join(withoutQueues, ", ")))
+	}
+
+	return output.String()
+}
+
+// GetTopicsSummary returns a one-line summary of the SNS to SQS fan-out
+func GetTopicsSummary(summaries []TopicSummary) string {
+	subscriptions, fanOut := 0, 0
+	for _, topic := range summaries {
+		subscriptions += len(topic.Subscriptions)
+		if topic.IsFanOut() {
+			fanOut++
+		}
+	}
+
+	summary := fmt.Sprintf("%d topics, %d SQS subscriptions, %d fanning out to several queues", len(summaries), subscriptions, fanOut)
+	if pending := len(GetPendingSubscriptions(summaries)); pending > 0 {
+		summary += fmt.Sprintf(", ⚠️ %d pending confirmation", pending)
+	}
+
+	return summary
+}
+
+// GetPendingSubscriptions returns the SQS subscriptions that have not been confirmed
+func GetPendingSubscriptions(summaries []TopicSummary) []SubscriptionSummary {
+	var subscriptions []SubscriptionSummary
+	for _, topic := range summaries {
+		for _, subscription := range topic.Subscriptions {
+			if subscription.PendingConfirmation {
+				subscriptions = append(subscriptions, subscription)
+			}
+		}
+	}
+	return subscriptions
+}
+
+// getFlags returns the delivery flags of a subscription worth pointing out
+func getFlags(subscription SubscriptionSummary) []string {
+	var flags []string
+	if subscription.PendingConfirmation {
+		flags = append(flags, "pending confirmation")
+	}
+	if subscription.RawMessageDelivery {
+		flags = append(flags, "raw delivery")
+	}
+	return flags
+}
+
+// compactPolicy collapses the whitespace of a JSON filter policy onto one line
+func compactPolicy(policy string) string {
+	return strings.Join(strings.Fields(policy), " ")
+}
+
+// pluralize appends an "s" to word unless count is 1
+func pluralize(word string, count int) string {
+	if count == 1 {
+		return word
+	}
+	return word + "s"
+}
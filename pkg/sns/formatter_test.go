@@ -0,0 +1,55 @@
+package sns
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatTopics(t *testing.T) {
+	summaries := []TopicSummary{
+		{Name: "alarms", OtherSubscriptions: 1},
+		{
+			Name:               "orders",
+			OtherSubscriptions: 1,
+			Subscriptions: []SubscriptionSummary{
+				{Queue: "billing", FilterPolicy: "{\n  \"type\": [\"paid\"]\n}", FilterPolicyScope: "MessageBody"},
+				{Queue: "partner", Account: "999999999999", PendingConfirmation: true},
+				{Queue: "shipping", RawMessageDelivery: true},
+			},
+		},
+	}
+
+	output := FormatTopics(summaries)
+
+	for _, expected := range []string{
+		"SNS FAN-OUT",
+		"PENDING CONFIRMATION (1 receive no messages)",
+		"  orders → partner",
+		"orders (3 queues, 1 other subscription)",
+		"  ├─ billing\n",
+		"  │   Filter on MessageBody: { \"type\": [\"paid\"] }",
+		"  ├─ partner (account 999999999999) [pending confirmation]",
+		"  └─ shipping [raw delivery]",
+		"Topics without SQS subscriptions (1): alarms",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain '%s', got:\n%s", expected, output)
+		}
+	}
+
+	if output := FormatTopics(nil); output != "No SNS topics found" {
+		t.Errorf("Expected an empty message, got '%s'", output)
+	}
+}
+
+func TestGetTopicsSummary(t *testing.T) {
+	summaries := []TopicSummary{
+		{Name: "alarms"},
+		{Name: "orders", Subscriptions: []SubscriptionSummary{{Queue: "billing"}, {Queue: "shipping", PendingConfirmation: true}}},
+	}
+
+	summary := GetTopicsSummary(summaries)
+	if summary != "2 topics, 2 SQS subscriptions, 1 fanning out to several queues, ⚠️ 1 pending confirmation" {
+		t.Errorf("Unexpected summary '%s'", summary)
+	}
+}
@@ -0,0 +1,265 @@
+package sns
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// snsClientAPI defines the interface for the SNS client
+type snsClientAPI interface {
+	ListTopics(ctx context.Context, params *sns.ListTopicsInput, optFns ...func(*sns.Options)) (*sns.ListTopicsOutput, error)
+	ListSubscriptions(ctx context.Context, params *sns.ListSubscriptionsInput, optFns ...func(*sns.Options)) (*sns.ListSubscriptionsOutput, error)
+	GetSubscriptionAttributes(ctx context.Context, params *sns.GetSubscriptionAttributesInput, optFns ...func(*sns.Options)) (*sns.GetSubscriptionAttributesOutput, error)
+}
+
+// pendingConfirmation is the subscription ARN SNS reports for subscriptions
+// that have not been confirmed yet
+const pendingConfirmation = "PendingConfirmation"
+
+// Client represents an SNS client
+type Client struct {
+	snsClient snsClientAPI
+	pool      *common.Pool
+}
+
+// TopicSummary represents an SNS topic and the SQS queues subscribed to it
+type TopicSummary struct {
+	Name               string
+	ARN                string
+	Subscriptions      []SubscriptionSummary // SQS subscriptions, sorted by queue name
+	OtherSubscriptions int                   // Subscriptions with other protocols, e.g. Lambda or email
+}
+
+// SubscriptionSummary represents the subscription of an SQS queue to a topic
+type SubscriptionSummary struct {
+	ARN                 string
+	Queue               string // Queue name
+	QueueARN            string
+	Account             string // Account of the queue, when it differs from the topic's
+	RawMessageDelivery  bool
+	FilterPolicy        string // JSON filter policy, empty when every message is delivered
+	FilterPolicyScope   string // "MessageAttributes" or "MessageBody"
+	PendingConfirmation bool
+}
+
+// IsFanOut reports whether the topic delivers to more than one queue
+func (t TopicSummary) IsFanOut() bool {
+	return len(t.Subscriptions) > 1
+}
+
+// HasFilterPolicy reports whether the subscription only receives matching messages
+func (s SubscriptionSummary) HasFilterPolicy() bool {
+	return s.FilterPolicy != ""
+}
+
+// NewClient returns a new SNS client whose calls run in pool, which may be nil
+func NewClient(snsClient snsClientAPI, pool *common.Pool) *Client {
+	return &Client{
+		snsClient: snsClient,
+		pool:      pool,
+	}
+}
+
+// GetTopics returns the SNS topics with their SQS subscriptions, sorted by
+// name. Subscriptions whose attributes fail to load are listed without their
+// filter policy and delivery flags, and the errors returned alongside.
+func (c *Client) GetTopics(ctx context.Context) ([]TopicSummary, []error) {
+	topicARNs, err := c.listTopics(ctx)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	subscriptions, err := c.listSubscriptions(ctx)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	topics := make(map[string]*TopicSummary, len(topicARNs))
+	for _, arn := range topicARNs {
+		topics[arn] = &TopicSummary{Name: nameFromARN(arn), ARN: arn}
+	}
+
+	for _, subscription := range subscriptions {
+		topicARN := aws.ToString(subscription.TopicArn)
+		topic, ok := topics[topicARN]
+		if !ok {
+			// Subscriptions can outlive their topic until SNS cleans them up
+			continue
+		}
+
+		if aws.ToString(subscription.Protocol) != "sqs" {
+			topic.OtherSubscriptions++
+			continue
+		}
+
+		queueARN := aws.ToString(subscription.Endpoint)
+		summary := SubscriptionSummary{
+			ARN:                 aws.ToString(subscription.SubscriptionArn),
+			Queue:               nameFromARN(queueARN),
+			QueueARN:            queueARN,
+			PendingConfirmation: aws.ToString(subscription.SubscriptionArn) == pendingConfirmation,
+		}
+		if account := accountFromARN(queueARN); account != accountFromARN(topicARN) {
+			summary.Account = account
+		}
+
+		topic.Subscriptions = append(topic.Subscriptions, summary)
+	}
+
+	// Load the attributes of the SQS subscriptions in parallel
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []error
+
+	for _, topic := range topics {
+		for i := range topic.Subscriptions {
+			if topic.Subscriptions[i].PendingConfirmation {
+				// Unconfirmed subscriptions have no ARN to look up
+				continue
+			}
+
+			wg.Add(1)
+			go func(topicName string, summary *SubscriptionSummary) {
+				defer wg.Done()
+				attributes, err := c.getSubscriptionAttributes(ctx, summary.ARN)
+				if err != nil {
+					mu.Lock()
+					defer mu.Unlock()
+					errs = append(errs, fmt.Errorf("topic %s: %w", topicName, err))
+					return
+				}
+				applyAttributes(summary, attributes)
+			}(topic.Name, &topic.Subscriptions[i])
+		}
+	}
+
+	// Wait for all goroutines to complete
+	wg.Wait()
+
+	summaries := make([]TopicSummary, 0, len(topics))
+	for _, topic := range topics {
+		sort.Slice(topic.Subscriptions, func(i, j int) bool {
+			return topic.Subscriptions[i].Queue < topic.Subscriptions[j].Queue
+		})
+		summaries = append(summaries, *topic)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Name < summaries[j].Name
+	})
+
+	return summaries, errs
+}
+
+// listTopics returns the ARNs of all topics, following the pagination tokens
+func (c *Client) listTopics(ctx context.Context) ([]string, error) {
+	var arns []string
+	var nextToken *string
+
+	for {
+		var result *sns.ListTopicsOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.snsClient.ListTopics(ctx, &sns.ListTopicsInput{
+				NextToken: nextToken,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list topics: %w", err)
+		}
+
+		for _, topic := range result.Topics {
+			arns = append(arns, aws.ToString(topic.TopicArn))
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return arns, nil
+}
+
+// listSubscriptions returns the subscriptions of all topics, following the
+// pagination tokens. A single account-wide listing takes fewer calls than
+// listing the subscriptions of each topic.
+func (c *Client) listSubscriptions(ctx context.Context) ([]types.Subscription, error) {
+	var subscriptions []types.Subscription
+	var nextToken *string
+
+	for {
+		var result *sns.ListSubscriptionsOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.snsClient.ListSubscriptions(ctx, &sns.ListSubscriptionsInput{
+				NextToken: nextToken,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list subscriptions: %w", err)
+		}
+
+		subscriptions = append(subscriptions, result.Subscriptions...)
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return subscriptions, nil
+}
+
+// getSubscriptionAttributes returns the attributes of a subscription
+func (c *Client) getSubscriptionAttributes(ctx context.Context, subscriptionARN string) (map[string]string, error) {
+	var result *sns.GetSubscriptionAttributesOutput
+	err := c.pool.Do(ctx, func() (err error) {
+		result, err = c.snsClient.GetSubscriptionAttributes(ctx, &sns.GetSubscriptionAttributesInput{
+			SubscriptionArn: aws.String(subscriptionARN),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attributes of subscription %s: %w", subscriptionARN, err)
+	}
+	return result.Attributes, nil
+}
+
+// applyAttributes copies the filter policy and delivery flags of a
+// subscription's attributes onto its summary
+func applyAttributes(summary *SubscriptionSummary, attributes map[string]string) {
+	summary.RawMessageDelivery = attributes["RawMessageDelivery"] == "true"
+	summary.FilterPolicy = attributes["FilterPolicy"]
+	summary.FilterPolicyScope = attributes["FilterPolicyScope"]
+	if summary.HasFilterPolicy() && summary.FilterPolicyScope == "" {
+		// Filter policies apply to message attributes unless scoped otherwise
+		summary.FilterPolicyScope = "MessageAttributes"
+	}
+	if attributes["PendingConfirmation"] == "true" {
+		summary.PendingConfirmation = true
+	}
+}
+
+// nameFromARN returns the resource name, which is the last component of an SNS or SQS ARN
+func nameFromARN(arn string) string {
+	parts := strings.Split(arn, ":")
+	return parts[len(parts)-1]
+}
+
+// accountFromARN returns the account ID component of an ARN
+func accountFromARN(arn string) string {
+	parts := strings.Split(arn, ":")
+	if len(parts) < 6 {
+		return ""
+	}
+	return parts[4]
+}
@@ -0,0 +1,129 @@
+package sns
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+)
+
+// Mock SNS client
+type mockSNSClient struct {
+	topics        []string
+	subscriptions []types.Subscription
+	attributes    map[string]map[string]string
+}
+
+func (m *mockSNSClient) ListTopics(ctx context.Context, params *sns.ListTopicsInput, optFns ...func(*sns.Options)) (*sns.ListTopicsOutput, error) {
+	// Serve one topic per page to exercise pagination
+	start := 0
+	if params.NextToken != nil {
+		for i, arn := range m.topics {
+			if arn == *params.NextToken {
+				start = i
+			}
+		}
+	}
+	output := &sns.ListTopicsOutput{Topics: []types.Topic{{TopicArn: aws.String(m.topics[start])}}}
+	if start+1 < len(m.topics) {
+		output.NextToken = aws.String(m.topics[start+1])
+	}
+	return output, nil
+}
+
+func (m *mockSNSClient) ListSubscriptions(ctx context.Context, params *sns.ListSubscriptionsInput, optFns ...func(*sns.Options)) (*sns.ListSubscriptionsOutput, error) {
+	return &sns.ListSubscriptionsOutput{Subscriptions: m.subscriptions}, nil
+}
+
+func (m *mockSNSClient) GetSubscriptionAttributes(ctx context.Context, params *sns.GetSubscriptionAttributesInput, optFns ...func(*sns.Options)) (*sns.GetSubscriptionAttributesOutput, error) {
+	attributes, ok := m.attributes[*params.SubscriptionArn]
+	if !ok {
+		return nil, errors.New("AuthorizationError")
+	}
+	return &sns.GetSubscriptionAttributesOutput{Attributes: attributes}, nil
+}
+
+func subscription(topic, protocol, endpoint, arn string) types.Subscription {
+	return types.Subscription{
+		TopicArn:        aws.String("arn:aws:sns:us-east-1:123456789012:" + topic),
+		Protocol:        aws.String(protocol),
+		Endpoint:        aws.String(endpoint),
+		SubscriptionArn: aws.String(arn),
+	}
+}
+
+func TestGetTopics(t *testing.T) {
+	client := NewClient(&mockSNSClient{
+		topics: []string{
+			"arn:aws:sns:us-east-1:123456789012:orders",
+			"arn:aws:sns:us-east-1:123456789012:alarms",
+		},
+		subscriptions: []types.Subscription{
+			subscription("orders", "sqs", "arn:aws:sqs:us-east-1:123456789012:shipping", "sub-shipping"),
+			subscription("orders", "sqs", "arn:aws:sqs:us-east-1:123456789012:billing", "sub-billing"),
+			subscription("orders", "sqs", "arn:aws:sqs:us-east-1:999999999999:partner", pendingConfirmation),
+			subscription("orders", "lambda", "arn:aws:lambda:us-east-1:123456789012:function:audit", "sub-audit"),
+			subscription("alarms", "email", "ops@example.com", "sub-email"),
+			subscription("deleted", "sqs", "arn:aws:sqs:us-east-1:123456789012:orphan", "sub-orphan"),
+		},
+		attributes: map[string]map[string]string{
+			"sub-shipping": {"RawMessageDelivery": "true"},
+			"sub-billing":  {"RawMessageDelivery": "false", "FilterPolicy": `{"type": ["paid"]}`},
+		},
+	}, nil)
+
+	topics, errs := client.GetTopics(context.Background())
+	if len(errs) > 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+
+	if len(topics) != 2 || topics[0].Name != "alarms" || topics[1].Name != "orders" {
+		t.Fatalf("Expected topics [alarms orders], got %v", topics)
+	}
+
+	alarms := topics[0]
+	if len(alarms.Subscriptions) != 0 || alarms.OtherSubscriptions != 1 {
+		t.Errorf("Expected 'alarms' to only have an email subscription, got %v", alarms)
+	}
+
+	orders := topics[1]
+	if !orders.IsFanOut() || orders.OtherSubscriptions != 1 {
+		t.Errorf("Expected 'orders' to fan out with 1 other subscription, got %v", orders)
+	}
+	if len(orders.Subscriptions) != 3 {
+		t.Fatalf("Expected 3 SQS subscriptions, got %v", orders.Subscriptions)
+	}
+
+	billing, partner, shipping := orders.Subscriptions[0], orders.Subscriptions[1], orders.Subscriptions[2]
+	if billing.Queue != "billing" || billing.RawMessageDelivery || !billing.HasFilterPolicy() || billing.FilterPolicyScope != "MessageAttributes" {
+		t.Errorf("Unexpected billing subscription %v", billing)
+	}
+	if partner.Queue != "partner" || !partner.PendingConfirmation || partner.Account != "999999999999" {
+		t.Errorf("Unexpected partner subscription %v", partner)
+	}
+	if shipping.Queue != "shipping" || !shipping.RawMessageDelivery || shipping.HasFilterPolicy() || shipping.Account != "" {
+		t.Errorf("Unexpected shipping subscription %v", shipping)
+	}
+}
+
+func TestGetTopicsAttributeErrors(t *testing.T) {
+	client := NewClient(&mockSNSClient{
+		topics: []string{"arn:aws:sns:us-east-1:123456789012:orders"},
+		subscriptions: []types.Subscription{
+			subscription("orders", "sqs", "arn:aws:sqs:us-east-1:123456789012:shipping", "sub-shipping"),
+		},
+	}, nil)
+
+	topics, errs := client.GetTopics(context.Background())
+	if len(errs) != 1 {
+		t.Errorf("Expected 1 error, got %v", errs)
+	}
+
+	// The subscription is still listed
+	if len(topics) != 1 || len(topics[0].Subscriptions) != 1 {
+		t.Errorf("Expected the subscription despite the error, got %v", topics)
+	}
+}
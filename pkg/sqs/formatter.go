@@ -3,7 +3,9 @@ package sqs
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/correctedcloud/aws-overview/pkg/cloudwatchmetrics"
 	"github.com/correctedcloud/aws-overview/pkg/common"
 )
 
@@ -15,26 +17,51 @@ func FormatQueues(summaries []QueueSummary) string {
 
 	var output strings.Builder
 	output.WriteString("SQS QUEUES\n")
-	output.WriteString("==========\n\n")
+	output.WriteString(common.Rule("SQS QUEUES", "=") + "\n\n")
 
 	for _, queue := range summaries {
-		queueTypeSymbol := getQueueTypeSymbol(queue.Type)
+		window := common.WithWindow(queue.MetricsStart(), queue.MetricsEnd)
+		queueTypeSymbol := common.Symbol(getQueueTypeSymbol(queue.Type))
 		output.WriteString(fmt.Sprintf("%s %s (%s)\n", queueTypeSymbol, queue.Name, queue.Type))
 
-		output.WriteString("\n  Messages Sent (1 hour):\n")
-		if len(queue.SentMessages) > 0 {
-			sentGraph := common.GenerateSparkline(queue.SentMessages, "Messages Sent", 3)
-			output.WriteString(fmt.Sprintf("%s\n", sentGraph))
+		if queue.HasStuckMessages() {
+			output.WriteString(fmt.Sprintf("  %s STUCK MESSAGES: oldest message is %s old\n", common.Symbol("⚠️"), formatAge(queue.CurrentOldestMessageAge())))
+		}
+
+		if queue.IsDeadLetterQueue() {
+			dlqSymbol := "✅"
+			if queue.ApproximateMessages > 0 {
+				dlqSymbol = "🚨"
+			}
+			output.WriteString(fmt.Sprintf("  %s DEAD-LETTER QUEUE: %d messages\n", dlqSymbol, queue.ApproximateMessages))
+			output.WriteString(fmt.Sprintf("  Source queues: %s\n", strings.Join(queue.SourceQueues, ", ")))
+		}
+
+		if queue.DeadLetterQueue != "" {
+			output.WriteString(fmt.Sprintf("  DLQ: %s (max receives: %d, %d messages)\n",
+				queue.DeadLetterQueue, queue.MaxReceiveCount, queue.DeadLetterQueueMessages))
+		}
+
+		// Visible messages piling up while as many are sent means the
+		// consumers fall behind, which reads best on one chart
+		output.WriteString("\n  Messages Sent vs Visible (" + cloudwatchmetrics.FormatWindow(queue.MetricsWindow) + "):\n")
+		if len(queue.SentMessages) > 0 || len(queue.VisibleMessages) > 0 {
+			messagesChart := common.GenerateChart([]common.Series{
+				{Label: "Sent", Data: queue.SentMessages},
+				{Label: "Visible", Data: queue.VisibleMessages},
+			}, 3, common.WithStats(), window)
+			output.WriteString(fmt.Sprintf("%s\n", messagesChart))
 		} else {
-			output.WriteString("  No message sent data available\n")
+			output.WriteString("  No sent or visible message data available\n")
 		}
 
-		output.WriteString("\n  Visible Messages (1 hour):\n")
-		if len(queue.VisibleMessages) > 0 {
-			visibleGraph := common.GenerateSparkline(queue.VisibleMessages, "Visible Messages", 3)
-			output.WriteString(fmt.Sprintf("%s\n", visibleGraph))
+		output.WriteString("\n  Age of Oldest Message (" + cloudwatchmetrics.FormatWindow(queue.MetricsWindow) + "):\n")
+		if len(queue.OldestMessageAge) > 0 {
+			ageGraph := common.GenerateSparkline(queue.OldestMessageAge, "Oldest Message Age (s)", 3,
+				common.WithStats(), common.WithThreshold(StuckMessageThreshold.Seconds()), window)
+			output.WriteString(fmt.Sprintf("%s\n", ageGraph))
 		} else {
-			output.WriteString("  No visible message data available\n")
+			output.WriteString("  No message age data available\n")
 		}
 
 		output.WriteString("\n")
@@ -43,6 +70,24 @@ func FormatQueues(summaries []QueueSummary) string {
 	return output.String()
 }
 
+// FormatQueueGraphs draws the metrics of a queue as detail graphs width
+// columns wide, for a closer look than FormatQueues gives
+func FormatQueueGraphs(queue QueueSummary, width int) string {
+	window := common.WithWindow(queue.MetricsStart(), queue.MetricsEnd)
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("Graphs of %s (%s)\n", queue.Name, cloudwatchmetrics.FormatWindow(queue.MetricsWindow)))
+	output.WriteString("\nMessages Sent vs Visible:\n")
+	output.WriteString(common.GenerateDetailChart([]common.Series{
+		{Label: "Sent", Data: queue.SentMessages},
+		{Label: "Visible", Data: queue.VisibleMessages},
+	}, width, common.WithStats(), window) + "\n")
+	output.WriteString("\nAge of Oldest Message:\n")
+	output.WriteString(common.GenerateDetailGraph(queue.OldestMessageAge, "Oldest Message Age (s)", width,
+		common.WithStats(), common.WithThreshold(StuckMessageThreshold.Seconds()), window) + "\n")
+	return output.String()
+}
+
 // GetQueuesSummary returns a brief summary of SQS queues
 func GetQueuesSummary(summaries []QueueSummary) string {
 	if len(summaries) == 0 {
@@ -52,6 +97,7 @@ func GetQueuesSummary(summaries []QueueSummary) string {
 	// Count queues by type
 	standard := 0
 	fifo := 0
+	stuck := 0
 
 	// Calculate average sent and visible messages
 	totalSent := 0.0
@@ -66,6 +112,10 @@ func GetQueuesSummary(summaries []QueueSummary) string {
 			standard++
 		}
 
+		if queue.HasStuckMessages() {
+			stuck++
+		}
+
 		// Add the last sent messages data point if available
 		if len(queue.SentMessages) > 0 {
 			totalSent += queue.SentMessages[len(queue.SentMessages)-1]
@@ -79,21 +129,49 @@ func GetQueuesSummary(summaries []QueueSummary) string {
 		}
 	}
 
-	// Calculate averages
-	var sentAvg, visibleAvg float64
+	// Calculate averages, leaving them as "n/a" when no queue reported data
+	sentAvg, visibleAvg := "n/a", "n/a"
 	if sentDataPoints > 0 {
-		sentAvg = totalSent / float64(sentDataPoints)
+		sentAvg = common.FormatFloatWithPrecision(totalSent/float64(sentDataPoints), 1)
 	}
 	if visibleDataPoints > 0 {
-		visibleAvg = totalVisible / float64(visibleDataPoints)
+		visibleAvg = common.FormatFloatWithPrecision(totalVisible/float64(visibleDataPoints), 1)
 	}
 
-	return fmt.Sprintf("%d queues (%d standard, %d FIFO), Recent Avg Sent: %.1f, Recent Avg Visible: %.1f",
+	summary := fmt.Sprintf("%d queues (%d standard, %d FIFO), Recent Avg Sent: %s, Recent Avg Visible: %s",
 		len(summaries),
 		standard,
 		fifo,
 		sentAvg,
 		visibleAvg)
+
+	if stuck > 0 {
+		summary += fmt.Sprintf(", ⚠️ %d with stuck messages", stuck)
+	}
+
+	return summary
+}
+
+// GetQueuesWithNonEmptyDLQ returns the queues whose dead-letter queue contains messages
+func GetQueuesWithNonEmptyDLQ(summaries []QueueSummary) []QueueSummary {
+	var queues []QueueSummary
+	for _, queue := range summaries {
+		if queue.HasNonEmptyDeadLetterQueue() {
+			queues = append(queues, queue)
+		}
+	}
+	return queues
+}
+
+// formatAge formats a message age as a short human-readable duration
+func formatAge(age time.Duration) string {
+	hours := int(age.Hours())
+	minutes := int(age.Minutes()) % 60
+
+	if hours > 0 {
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
+	return fmt.Sprintf("%dm", minutes)
 }
 
 // getQueueTypeSymbol returns an appropriate symbol for a queue type
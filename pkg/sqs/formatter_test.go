@@ -0,0 +1,119 @@
+package sqs
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestFormatQueues(t *testing.T) {
+	// Test with empty summaries
+	emptyResult := FormatQueues([]QueueSummary{})
+	if emptyResult != "No SQS queues found" {
+		t.Errorf("Expected 'No SQS queues found', got '%s'", emptyResult)
+	}
+
+	// Test with actual summaries
+	summaries := []QueueSummary{
+		{
+			Name:             "orders",
+			Type:             "Standard",
+			SentMessages:     []float64{10, 20},
+			VisibleMessages:  []float64{1, 2},
+			OldestMessageAge: []float64{60, 1200},
+		},
+		{
+			Name:             "payments.fifo",
+			Type:             "FIFO",
+			OldestMessageAge: []float64{30},
+		},
+	}
+
+	result := FormatQueues(summaries)
+
+	expectedElements := []string{
+		"SQS QUEUES",
+		"📬 orders (Standard)",
+		"STUCK MESSAGES: oldest message is 20m old",
+		"Age of Oldest Message (1 hour):",
+		"🔄 payments.fifo (FIFO)",
+		"Messages Sent vs Visible (1 hour):",
+		"Sent (min 10, max 20, last 20)",
+		"Oldest Message Age (s) (min 60, max 1200, last 1200, threshold 900)",
+		"No sent or visible message data available",
+	}
+
+	for _, expected := range expectedElements {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected output to contain '%s', but it didn't", expected)
+		}
+	}
+
+	if strings.Count(result, "STUCK MESSAGES") != 1 {
+		t.Errorf("Expected exactly one stuck queue warning, got output:\n%s", result)
+	}
+}
+
+func TestFormatQueueGraphs(t *testing.T) {
+	queue := QueueSummary{
+		Name:             "orders",
+		SentMessages:     []float64{10, 20, 30},
+		VisibleMessages:  []float64{1, 2, 3},
+		OldestMessageAge: []float64{60, 1200},
+	}
+
+	result := FormatQueueGraphs(queue, 60)
+	for _, expected := range []string{
+		"Graphs of orders (1 hour)",
+		"Sent (min 10, max 30, last 30)",
+		"Oldest Message Age (s) (min 60, max 1200, last 1200, threshold 900)",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected output to contain '%s', got:\n%s", expected, result)
+		}
+	}
+	for _, line := range strings.Split(result, "\n") {
+		if !strings.Contains(line, "┤") {
+			continue
+		}
+		if width := len([]rune(ansi.Strip(line))); width > 60 {
+			t.Errorf("Expected the plots to fit 60 columns, got %d in %q", width, line)
+		}
+	}
+}
+
+func TestGetQueuesSummary(t *testing.T) {
+	summaries := []QueueSummary{
+		{Name: "a", Type: "Standard", OldestMessageAge: []float64{3600}},
+		{Name: "b.fifo", Type: "FIFO"},
+	}
+
+	result := GetQueuesSummary(summaries)
+	if !strings.Contains(result, "2 queues (1 standard, 1 FIFO)") {
+		t.Errorf("Expected queue counts in summary, got '%s'", result)
+	}
+	if !strings.Contains(result, "1 with stuck messages") {
+		t.Errorf("Expected stuck queue count in summary, got '%s'", result)
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	testCases := []struct {
+		age      time.Duration
+		expected string
+	}{
+		{5 * time.Minute, "5m"},
+		{65 * time.Minute, "1h 5m"},
+		{0, "0m"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expected, func(t *testing.T) {
+			if got := formatAge(tc.age); got != tc.expected {
+				t.Errorf("Expected '%s', got '%s'", tc.expected, got)
+			}
+		})
+	}
+}
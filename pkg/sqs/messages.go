@@ -0,0 +1,189 @@
+package sqs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// MaxPeekMessages is the most messages a peek returns, the most a single
+// ReceiveMessage call returns
+const MaxPeekMessages = 10
+
+// maxBodyLength is how much of a message body FormatMessages shows
+const maxBodyLength = 4096
+
+// Message is a message peeked at in a queue
+type Message struct {
+	ID           string
+	Body         string
+	SentAt       time.Time
+	ReceiveCount int               // Times the message was received, this peek included
+	GroupID      string            // Message group of a FIFO queue
+	Attributes   map[string]string // Message attributes with a string or number value
+}
+
+// PeekMessages returns up to MaxPeekMessages messages of the queue without
+// consuming them: right after they are received, their visibility timeout is
+// set to 0, so they are visible to consumers again. Each peek still counts as
+// a receive toward the maximum receive count of the queue's redrive policy.
+// SQS samples a subset of its servers, so fewer messages than are waiting may
+// be returned.
+func (c *Client) PeekMessages(ctx context.Context, queueURL string) ([]Message, error) {
+	// A visibility timeout of 0 is not sent with the receive, whose messages
+	// get the queue's default timeout
+	output, err := c.sqsClient.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+		QueueUrl:                    aws.String(queueURL),
+		MaxNumberOfMessages:         MaxPeekMessages,
+		MessageAttributeNames:       []string{"All"},
+		MessageSystemAttributeNames: []types.MessageSystemAttributeName{types.MessageSystemAttributeNameAll},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to peek at messages of %s: %w", queueNameFromURL(queueURL), err)
+	}
+	if err := c.releaseMessages(ctx, queueURL, output.Messages); err != nil {
+		return nil, err
+	}
+
+	messages := make([]Message, 0, len(output.Messages))
+	for _, received := range output.Messages {
+		message := Message{
+			ID:      aws.ToString(received.MessageId),
+			Body:    aws.ToString(received.Body),
+			GroupID: received.Attributes[string(types.MessageSystemAttributeNameMessageGroupId)],
+		}
+		if sent, err := strconv.ParseInt(received.Attributes[string(types.MessageSystemAttributeNameSentTimestamp)], 10, 64); err == nil {
+			message.SentAt = time.UnixMilli(sent)
+		}
+		message.ReceiveCount, _ = strconv.Atoi(received.Attributes[string(types.MessageSystemAttributeNameApproximateReceiveCount)])
+		for name, value := range received.MessageAttributes {
+			if value.StringValue != nil {
+				if message.Attributes == nil {
+					message.Attributes = make(map[string]string)
+				}
+				message.Attributes[name] = *value.StringValue
+			}
+		}
+		messages = append(messages, message)
+	}
+
+	// Oldest first, as consumers would receive them
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].SentAt.Before(messages[j].SentAt)
+	})
+	return messages, nil
+}
+
+// releaseMessages makes received messages visible to consumers again
+func (c *Client) releaseMessages(ctx context.Context, queueURL string, messages []types.Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	entries := make([]types.ChangeMessageVisibilityBatchRequestEntry, len(messages))
+	for i, message := range messages {
+		entries[i] = types.ChangeMessageVisibilityBatchRequestEntry{
+			Id:                aws.String(strconv.Itoa(i)),
+			ReceiptHandle:     message.ReceiptHandle,
+			VisibilityTimeout: 0,
+		}
+	}
+
+	output, err := c.sqsClient.ChangeMessageVisibilityBatch(ctx, &sqs.ChangeMessageVisibilityBatchInput{
+		QueueUrl: aws.String(queueURL),
+		Entries:  entries,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to make the peeked messages of %s visible again: %w", queueNameFromURL(queueURL), err)
+	}
+	if len(output.Failed) > 0 {
+		return fmt.Errorf("failed to make %d peeked messages of %s visible again: %s", len(output.Failed), queueNameFromURL(queueURL), aws.ToString(output.Failed[0].Message))
+	}
+	return nil
+}
+
+// PurgeQueue deletes all messages of the queue. SQS allows one purge per
+// queue every 60 seconds.
+func (c *Client) PurgeQueue(ctx context.Context, queueURL string) error {
+	_, err := c.sqsClient.PurgeQueue(ctx, &sqs.PurgeQueueInput{QueueUrl: aws.String(queueURL)})
+	if err != nil {
+		return fmt.Errorf("failed to purge queue %s: %w", queueNameFromURL(queueURL), err)
+	}
+	return nil
+}
+
+// queueNameFromURL returns the queue name, which is the last component of a
+// queue URL
+func queueNameFromURL(queueURL string) string {
+	return queueURL[strings.LastIndex(queueURL, "/")+1:]
+}
+
+// FormatMessages formats the messages peeked at in a queue, indenting JSON
+// bodies
+func FormatMessages(queue string, messages []Message) string {
+	title := "MESSAGES: " + queue
+	var output strings.Builder
+	output.WriteString(title + "\n")
+	output.WriteString(common.Rule(title, "=") + "\n\n")
+
+	if len(messages) == 0 {
+		output.WriteString("No messages received; the queue is empty or its messages are in flight\n")
+		return output.String()
+	}
+	output.WriteString(fmt.Sprintf("%d messages, the oldest first. Peeking does not consume them, but counts as a receive.\n\n", len(messages)))
+
+	for i, message := range messages {
+		header := fmt.Sprintf("%d. %s", i+1, message.ID)
+		if !message.SentAt.IsZero() {
+			header += "  sent " + message.SentAt.Local().Format("2006-01-02 15:04:05")
+		}
+		header += fmt.Sprintf("  received %d times", message.ReceiveCount)
+		if message.GroupID != "" {
+			header += "  group " + message.GroupID
+		}
+		output.WriteString(header + "\n")
+
+		names := make([]string, 0, len(message.Attributes))
+		for name := range message.Attributes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			output.WriteString(fmt.Sprintf("   %s: %s\n", name, message.Attributes[name]))
+		}
+
+		for _, line := range strings.Split(formatBody(message.Body), "\n") {
+			output.WriteString("   " + line + "\n")
+		}
+		output.WriteString("\n")
+	}
+	return output.String()
+}
+
+// formatBody indents a JSON body and cuts a long one short
+func formatBody(body string) string {
+	var indented bytes.Buffer
+	if json.Indent(&indented, []byte(body), "", "  ") == nil {
+		body = indented.String()
+	}
+	body = strings.TrimRight(body, " \t\r\n")
+	if len(body) > maxBodyLength {
+		cut := maxBodyLength
+		for cut > 0 && !utf8.RuneStart(body[cut]) {
+			cut--
+		}
+		body = fmt.Sprintf("%s… (%d more bytes)", body[:cut], len(body)-cut)
+	}
+	return body
+}
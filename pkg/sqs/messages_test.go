@@ -0,0 +1,156 @@
+package sqs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+const testQueueURL = "https://sqs.us-east-1.amazonaws.com/123456789012/orders-dlq"
+
+func TestPeekMessages(t *testing.T) {
+	// The requests as sent, since the SDK leaves zero values out of them
+	var calls []string
+	var receive, release map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		operation := strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "AmazonSQS.")
+		calls = append(calls, operation)
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode the %s request: %v", operation, err)
+		}
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		switch operation {
+		case "ReceiveMessage":
+			receive = body
+			w.Write([]byte(`{"Messages": [
+				{"MessageId": "b", "ReceiptHandle": "handle-b", "Body": "second",
+				 "Attributes": {"SentTimestamp": "1704110400000", "ApproximateReceiveCount": "6"}},
+				{"MessageId": "a", "ReceiptHandle": "handle-a", "Body": "{\"orderId\":\"ord-1\"}",
+				 "Attributes": {"SentTimestamp": "1704106800000", "ApproximateReceiveCount": "2", "MessageGroupId": "orders"},
+				 "MessageAttributes": {
+					"source": {"DataType": "String", "StringValue": "checkout"},
+					"blob": {"DataType": "Binary", "BinaryValue": "AQ=="}}}
+			]}`))
+		case "ChangeMessageVisibilityBatch":
+			release = body
+			w.Write([]byte(`{"Successful": [{"Id": "0"}, {"Id": "1"}], "Failed": []}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(sqs.NewFromConfig(aws.Config{
+		Region:      "us-east-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKID", "secret", ""),
+	}, func(o *sqs.Options) { o.BaseEndpoint = aws.String(server.URL) }), nil, "", nil, 0)
+
+	messages, err := client.PeekMessages(context.Background(), testQueueURL)
+	if err != nil {
+		t.Fatalf("PeekMessages returned an error: %v", err)
+	}
+	if strings.Join(calls, ",") != "ReceiveMessage,ChangeMessageVisibilityBatch" {
+		t.Fatalf("Expected a receive, then a change of visibility, got %v", calls)
+	}
+	if receive["QueueUrl"] != testQueueURL || receive["MaxNumberOfMessages"] != float64(MaxPeekMessages) {
+		t.Errorf("Expected a receive of %d messages, got %v", MaxPeekMessages, receive)
+	}
+	entries, _ := release["Entries"].([]any)
+	if release["QueueUrl"] != testQueueURL || len(entries) != 2 {
+		t.Fatalf("Expected both messages to be made visible, got %v", release)
+	}
+	for i, handle := range []string{"handle-b", "handle-a"} {
+		entry, _ := entries[i].(map[string]any)
+		if entry["ReceiptHandle"] != handle || entry["VisibilityTimeout"] != float64(0) {
+			t.Errorf("Expected %s to be made visible at once, got %v", handle, entry)
+		}
+	}
+
+	if len(messages) != 2 || messages[0].ID != "a" {
+		t.Fatalf("Expected the messages oldest first, got %+v", messages)
+	}
+	first := messages[0]
+	if !first.SentAt.Equal(time.UnixMilli(1704106800000)) || first.ReceiveCount != 2 || first.GroupID != "orders" {
+		t.Errorf("Unexpected system attributes %+v", first)
+	}
+	if len(first.Attributes) != 1 || first.Attributes["source"] != "checkout" {
+		t.Errorf("Expected only the string message attributes, got %v", first.Attributes)
+	}
+
+	output := FormatMessages("orders-dlq", messages)
+	for _, want := range []string{"MESSAGES: orders-dlq", "2 messages", "received 6 times", "source: checkout", `"orderId": "ord-1"`} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in\n%s", want, output)
+		}
+	}
+}
+
+func TestPeekMessagesReleaseFailed(t *testing.T) {
+	client := NewClient(&mockSQSClient{
+		receiveMessageFunc: func(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+			return &sqs.ReceiveMessageOutput{Messages: []types.Message{{MessageId: aws.String("a"), ReceiptHandle: aws.String("handle-a")}}}, nil
+		},
+		changeVisibilityFunc: func(ctx context.Context, params *sqs.ChangeMessageVisibilityBatchInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityBatchOutput, error) {
+			return &sqs.ChangeMessageVisibilityBatchOutput{Failed: []types.BatchResultErrorEntry{{Id: aws.String("0"), Message: aws.String("receipt handle expired")}}}, nil
+		},
+	}, nil, "", nil, 0)
+
+	_, err := client.PeekMessages(context.Background(), testQueueURL)
+	if err == nil || !strings.Contains(err.Error(), "visible again") {
+		t.Errorf("Expected an error on messages left hidden, got %v", err)
+	}
+}
+
+func TestPeekMessagesError(t *testing.T) {
+	client := NewClient(&mockSQSClient{
+		receiveMessageFunc: func(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+			return nil, errors.New("AccessDenied")
+		},
+	}, nil, "", nil, 0)
+
+	_, err := client.PeekMessages(context.Background(), testQueueURL)
+	if err == nil || !strings.Contains(err.Error(), "orders-dlq") {
+		t.Errorf("Expected an error naming the queue, got %v", err)
+	}
+}
+
+func TestPurgeQueue(t *testing.T) {
+	var purged string
+	client := NewClient(&mockSQSClient{
+		purgeQueueFunc: func(ctx context.Context, params *sqs.PurgeQueueInput, optFns ...func(*sqs.Options)) (*sqs.PurgeQueueOutput, error) {
+			purged = aws.ToString(params.QueueUrl)
+			return &sqs.PurgeQueueOutput{}, nil
+		},
+	}, nil, "", nil, 0)
+
+	if err := client.PurgeQueue(context.Background(), testQueueURL); err != nil {
+		t.Fatalf("PurgeQueue returned an error: %v", err)
+	}
+	if purged != testQueueURL {
+		t.Errorf("Expected %s to be purged, got %q", testQueueURL, purged)
+	}
+}
+
+func TestFormatMessagesEmpty(t *testing.T) {
+	if output := FormatMessages("orders", nil); !strings.Contains(output, "No messages") {
+		t.Errorf("Expected a note on the empty queue, got %s", output)
+	}
+}
+
+func TestFormatBodyTruncates(t *testing.T) {
+	body := formatBody(strings.Repeat("x", maxBodyLength+10))
+	if !strings.HasSuffix(body, "(10 more bytes)") {
+		t.Errorf("Expected a long body to be cut short, got ...%s", body[len(body)-30:])
+	}
+}
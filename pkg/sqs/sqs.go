@@ -2,21 +2,29 @@ package sqs
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
-	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+
+	"github.com/correctedcloud/aws-overview/pkg/cloudwatchmetrics"
+	"github.com/correctedcloud/aws-overview/pkg/common"
 )
 
 // sqsClientAPI defines the interface for the SQS client
 type sqsClientAPI interface {
 	ListQueues(ctx context.Context, params *sqs.ListQueuesInput, optFns ...func(*sqs.Options)) (*sqs.ListQueuesOutput, error)
 	GetQueueAttributes(ctx context.Context, params *sqs.GetQueueAttributesInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error)
+	ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error)
+	PurgeQueue(ctx context.Context, params *sqs.PurgeQueueInput, optFns ...func(*sqs.Options)) (*sqs.PurgeQueueOutput, error)
+	ChangeMessageVisibilityBatch(ctx context.Context, params *sqs.ChangeMessageVisibilityBatchInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityBatchOutput, error)
 }
 
 // cloudwatchClientAPI defines the interface for the CloudWatch client
@@ -28,74 +36,208 @@ type cloudwatchClientAPI interface {
 type Client struct {
 	sqsClient        sqsClientAPI
 	cloudwatchClient cloudwatchClientAPI
+	queueNamePrefix  string
+	pool             *common.Pool
+	window           time.Duration // How far back the metrics go
 }
 
+// listQueuesPageSize is the largest page ListQueues returns. NextToken is
+// only returned when MaxResults is set.
+const listQueuesPageSize = 1000
+
+// StuckMessageThreshold is the age of the oldest message above which a queue is considered stuck
+const StuckMessageThreshold = 15 * time.Minute
+
 // QueueSummary represents a summary of an SQS queue
 type QueueSummary struct {
-	Name            string
-	Type            string // Standard or FIFO
-	SentMessages    []float64
-	VisibleMessages []float64
+	Name                    string
+	URL                     string
+	Type                    string // Standard or FIFO
+	ApproximateMessages     int64
+	DeadLetterQueue         string   // Name of the DLQ from the RedrivePolicy, if any
+	MaxReceiveCount         int      // Receives before a message is moved to the DLQ
+	DeadLetterQueueMessages int64    // ApproximateNumberOfMessages of the DLQ
+	SourceQueues            []string // Queues using this queue as their DLQ
+	SentMessages            []float64
+	VisibleMessages         []float64
+	OldestMessageAge        []float64     // Age of the oldest message in seconds
+	MetricsEnd              time.Time     // End of the metrics' window, zero when they were not fetched
+	MetricsWindow           time.Duration // How far back the metrics go
+}
+
+// MetricsStart returns the start of the metrics' window, which summaries
+// saved before the window was recorded leave at cloudwatchmetrics.DefaultWindow
+func (q QueueSummary) MetricsStart() time.Time {
+	if q.MetricsWindow <= 0 {
+		return q.MetricsEnd.Add(-cloudwatchmetrics.DefaultWindow)
+	}
+	return q.MetricsEnd.Add(-q.MetricsWindow)
+}
+
+// CurrentOldestMessageAge returns the most recent age of the oldest message
+func (q QueueSummary) CurrentOldestMessageAge() time.Duration {
+	if len(q.OldestMessageAge) == 0 {
+		return 0
+	}
+	return time.Duration(q.OldestMessageAge[len(q.OldestMessageAge)-1]) * time.Second
 }
 
-// NewClient returns a new SQS client
-func NewClient(sqsClient sqsClientAPI, cloudwatchClient cloudwatchClientAPI) *Client {
+// HasStuckMessages reports whether the oldest message is older than StuckMessageThreshold
+func (q QueueSummary) HasStuckMessages() bool {
+	return q.CurrentOldestMessageAge() > StuckMessageThreshold
+}
+
+// IsDeadLetterQueue reports whether other queues use this queue as their DLQ
+func (q QueueSummary) IsDeadLetterQueue() bool {
+	return len(q.SourceQueues) > 0
+}
+
+// HasNonEmptyDeadLetterQueue reports whether the queue's DLQ contains messages
+func (q QueueSummary) HasNonEmptyDeadLetterQueue() bool {
+	return q.DeadLetterQueue != "" && q.DeadLetterQueueMessages > 0
+}
+
+// redrivePolicy mirrors the JSON document stored in the RedrivePolicy attribute
+type redrivePolicy struct {
+	DeadLetterTargetArn string      `json:"deadLetterTargetArn"`
+	MaxReceiveCount     json.Number `json:"maxReceiveCount"`
+}
+
+// NewClient returns a new SQS client whose calls run in pool, which may be
+// nil. A non-empty queueNamePrefix limits the queues to those whose name
+// starts with it. The metrics go back window, or
+// cloudwatchmetrics.DefaultWindow when it is 0.
+func NewClient(sqsClient sqsClientAPI, cloudwatchClient cloudwatchClientAPI, queueNamePrefix string, pool *common.Pool, window time.Duration) *Client {
+	if window <= 0 {
+		window = cloudwatchmetrics.DefaultWindow
+	}
 	return &Client{
 		sqsClient:        sqsClient,
 		cloudwatchClient: cloudwatchClient,
+		queueNamePrefix:  queueNamePrefix,
+		pool:             pool,
+		window:           window,
 	}
 }
 
-// GetQueues returns a list of SQS queues with their metrics
-func (c *Client) GetQueues(ctx context.Context) ([]QueueSummary, error) {
-	// List all queues
-	result, err := c.sqsClient.ListQueues(ctx, &sqs.ListQueuesInput{})
+// GetQueues returns a list of SQS queues with their metrics, which are fetched
+// for all queues together once their attributes are loaded. Attributes and
+// metrics that fail to load are left empty and their errors returned
+// alongside the queues. Dead-letter queues outside the name prefix are not
+// loaded, so their message counts are unknown.
+func (c *Client) GetQueues(ctx context.Context) ([]QueueSummary, []error) {
+	queueURLs, err := c.listQueues(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list queues: %w", err)
+		return nil, []error{err}
 	}
 
 	// Process queues in parallel
 	var wg sync.WaitGroup
-	summariesCh := make(chan QueueSummary, len(result.QueueUrls))
-	errorsCh := make(chan error, len(result.QueueUrls))
+	var mu sync.Mutex
+	var summaries []QueueSummary
+	var errs []error
 
-	for _, queueURL := range result.QueueUrls {
+	for _, queueURL := range queueURLs {
 		wg.Add(1)
 		go func(url string) {
 			defer wg.Done()
-			summary, err := c.getQueueSummary(ctx, url)
-			if err != nil {
-				errorsCh <- err
-				return
-			}
-			summariesCh <- summary
+			summary, queueErrs := c.getQueueSummary(ctx, url)
+
+			mu.Lock()
+			defer mu.Unlock()
+			summaries = append(summaries, summary)
+			errs = append(errs, queueErrs...)
 		}(queueURL)
 	}
 
 	// Wait for all goroutines to complete
 	wg.Wait()
-	close(summariesCh)
-	close(errorsCh)
 
-	// Check for errors
-	if len(errorsCh) > 0 {
-		return nil, <-errorsCh
+	errs = append(errs, c.getMetrics(ctx, summaries)...)
+
+	linkDeadLetterQueues(summaries)
+
+	return summaries, errs
+}
+
+// listQueues returns the URLs of all queues matching the name prefix,
+// following the pagination tokens
+func (c *Client) listQueues(ctx context.Context) ([]string, error) {
+	var queueURLs []string
+	var nextToken *string
+
+	input := &sqs.ListQueuesInput{MaxResults: aws.Int32(listQueuesPageSize)}
+	if c.queueNamePrefix != "" {
+		input.QueueNamePrefix = aws.String(c.queueNamePrefix)
 	}
 
-	// Collect all queue summaries
-	var summaries []QueueSummary
-	for summary := range summariesCh {
-		summaries = append(summaries, summary)
+	for {
+		input.NextToken = nextToken
+		var result *sqs.ListQueuesOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.sqsClient.ListQueues(ctx, input)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list queues: %w", err)
+		}
+
+		queueURLs = append(queueURLs, result.QueueUrls...)
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return queueURLs, nil
+}
+
+// linkDeadLetterQueues records each DLQ's source queues and copies the DLQ
+// message count onto the queues that redrive into it
+func linkDeadLetterQueues(summaries []QueueSummary) {
+	byName := make(map[string]int, len(summaries))
+	for i, summary := range summaries {
+		byName[summary.Name] = i
+	}
+
+	for i := range summaries {
+		dlqName := summaries[i].DeadLetterQueue
+		if dlqName == "" {
+			continue
+		}
+
+		j, ok := byName[dlqName]
+		if !ok {
+			continue
+		}
+
+		summaries[j].SourceQueues = append(summaries[j].SourceQueues, summaries[i].Name)
+		summaries[i].DeadLetterQueueMessages = summaries[j].ApproximateMessages
+	}
+}
+
+// parseRedrivePolicy extracts the DLQ name and max receive count from a RedrivePolicy attribute
+func parseRedrivePolicy(policy string) (string, int, error) {
+	var rp redrivePolicy
+	if err := json.Unmarshal([]byte(policy), &rp); err != nil {
+		return "", 0, fmt.Errorf("failed to parse redrive policy: %w", err)
 	}
 
-	return summaries, nil
+	maxReceiveCount, _ := strconv.Atoi(rp.MaxReceiveCount.String())
+	return queueNameFromARN(rp.DeadLetterTargetArn), maxReceiveCount, nil
+}
+
+// queueNameFromARN returns the queue name, which is the last component of an SQS ARN
+func queueNameFromARN(arn string) string {
+	parts := strings.Split(arn, ":")
+	return parts[len(parts)-1]
 }
 
-// getQueueSummary returns a summary of an SQS queue with metrics
-func (c *Client) getQueueSummary(ctx context.Context, queueURL string) (QueueSummary, error) {
-	// Extract queue name from URL
-	nameParts := strings.Split(queueURL, "/")
-	queueName := nameParts[len(nameParts)-1]
+// getQueueSummary returns a summary of an SQS queue without metrics, along
+// with the errors of the attributes that could not be loaded
+func (c *Client) getQueueSummary(ctx context.Context, queueURL string) (QueueSummary, []error) {
+	queueName := queueNameFromURL(queueURL)
 
 	// Get queue attributes to determine type (Standard or FIFO)
 	attributesInput := &sqs.GetQueueAttributesInput{
@@ -104,125 +246,106 @@ func (c *Client) getQueueSummary(ctx context.Context, queueURL string) (QueueSum
 			types.QueueAttributeNameAll, // Get all attributes
 		},
 	}
-	attributesOutput, err := c.sqsClient.GetQueueAttributes(ctx, attributesInput)
+	var errs []error
+	var attributes map[string]string
+	var attributesOutput *sqs.GetQueueAttributesOutput
+	err := c.pool.Do(ctx, func() (err error) {
+		attributesOutput, err = c.sqsClient.GetQueueAttributes(ctx, attributesInput)
+		return err
+	})
 	if err != nil {
-		return QueueSummary{}, fmt.Errorf("failed to get queue attributes: %w", err)
+		errs = append(errs, fmt.Errorf("queue %s: failed to get queue attributes: %w", queueName, err))
+	} else {
+		attributes = attributesOutput.Attributes
 	}
 
 	queueType := "Standard"
 	// Check for FIFO queue suffix in name (.fifo) or for the FifoQueue attribute
 	if strings.HasSuffix(queueName, ".fifo") {
 		queueType = "FIFO"
-	} else if isFifo, ok := attributesOutput.Attributes["FifoQueue"]; ok && isFifo == "true" {
+	} else if isFifo, ok := attributes["FifoQueue"]; ok && isFifo == "true" {
 		queueType = "FIFO"
+	} else if attributes == nil {
+		queueType = "Unknown"
 	}
 
 	summary := QueueSummary{
 		Name: queueName,
+		URL:  queueURL,
 		Type: queueType,
 	}
 
-	// Use goroutines to fetch metrics in parallel
-	var wg sync.WaitGroup
-	var sentErr, visibleErr error
+	if count, ok := attributes["ApproximateNumberOfMessages"]; ok {
+		summary.ApproximateMessages, _ = strconv.ParseInt(count, 10, 64)
+	}
 
-	// Fetch number of messages sent data
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		sentData, err := c.getMetricData(ctx, "NumberOfMessagesSent", queueName)
-		if err != nil {
-			sentErr = err
-			return
-		}
-		summary.SentMessages = sentData
-	}()
-
-	// Fetch number of visible messages data
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		visibleData, err := c.getMetricData(ctx, "ApproximateNumberOfMessagesVisible", queueName)
+	if policy, ok := attributes["RedrivePolicy"]; ok && policy != "" {
+		dlqName, maxReceiveCount, err := parseRedrivePolicy(policy)
 		if err != nil {
-			visibleErr = err
-			return
+			errs = append(errs, fmt.Errorf("queue %s: %w", queueName, err))
+		} else {
+			summary.DeadLetterQueue = dlqName
+			summary.MaxReceiveCount = maxReceiveCount
 		}
-		summary.VisibleMessages = visibleData
-	}()
-
-	// Wait for all goroutines to complete
-	wg.Wait()
+	}
 
-	// Check for errors
-	if sentErr != nil {
-		return QueueSummary{}, sentErr
-	}
-	if visibleErr != nil {
-		return QueueSummary{}, visibleErr
-	}
-
-	return summary, nil
-}
-
-// getMetricData retrieves CloudWatch metric data for an SQS queue
-func (c *Client) getMetricData(ctx context.Context, metricName string, queueName string) ([]float64, error) {
-	endTime := time.Now()
-	startTime := endTime.Add(-1 * time.Hour)
-
-	// Create a valid ID that starts with lowercase letter and contains only alphanumeric characters
-	metricQueryId := "m" + strings.ReplaceAll(strings.ToLower(metricName), "-", "_")
-
-	result, err := c.cloudwatchClient.GetMetricData(ctx, &cloudwatch.GetMetricDataInput{
-		StartTime: &startTime,
-		EndTime:   &endTime,
-		MetricDataQueries: []cwtypes.MetricDataQuery{
-			{
-				Id: &metricQueryId,
-				MetricStat: &cwtypes.MetricStat{
-					Metric: &cwtypes.Metric{
-						Namespace:  strPtr("AWS/SQS"),
-						MetricName: &metricName,
-						Dimensions: []cwtypes.Dimension{
-							{
-								Name:  strPtr("QueueName"),
-								Value: &queueName,
-							},
-						},
-					},
-					Period: int32Ptr(300), // 5-minute data points
-					Stat:   strPtr("Sum"),
-				},
-			},
-		},
-	})
+	return summary, errs
+}
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to get metric data for %s: %w", metricName, err)
-	}
+// The metrics queried for each queue, in the order of their queries
+const (
+	querySent = iota
+	queryVisible
+	queryOldestAge
+	queriesPerQueue
+)
 
-	if len(result.MetricDataResults) == 0 || len(result.MetricDataResults[0].Values) == 0 {
-		// For testing purposes, return sample data if no values are available
-		if metricName == "NumberOfMessagesSent" {
-			return []float64{150.0, 120.0, 180.0, 135.0, 160.0, 140.0, 175.0, 130.0, 190.0, 145.0, 165.0, 135.0}, nil
-		} else if metricName == "ApproximateNumberOfMessagesVisible" {
-			return []float64{15.0, 12.0, 18.0, 13.5, 16.0, 14.0, 17.5, 13.0, 19.0, 14.5, 16.5, 13.5}, nil
+// getMetrics fills in the metrics of all queues, fetched together in as few
+// CloudWatch calls as possible, and returns the errors of the metrics that
+// could not be loaded
+func (c *Client) getMetrics(ctx context.Context, summaries []QueueSummary) []error {
+	queries := make([]cloudwatchmetrics.Query, 0, len(summaries)*queriesPerQueue)
+	for _, summary := range summaries {
+		query := func(metricName, stat string) cloudwatchmetrics.Query {
+			return cloudwatchmetrics.Query{
+				Namespace:  "AWS/SQS",
+				MetricName: metricName,
+				Dimensions: map[string]string{"QueueName": summary.Name},
+				Stat:       stat,
+				Period:     cloudwatchmetrics.PeriodOf(c.window),
+				Window:     c.window,
+			}
 		}
-		return []float64{}, nil
+		// The order must match the query constants
+		queries = append(queries,
+			query("NumberOfMessagesSent", "Sum"),
+			query("ApproximateNumberOfMessagesVisible", "Sum"),
+			query("ApproximateAgeOfOldestMessage", "Maximum"),
+		)
 	}
 
-	var data []float64
-	for _, value := range result.MetricDataResults[0].Values {
-		data = append(data, value)
-	}
+	results := cloudwatchmetrics.New(c.cloudwatchClient, c.pool).Fetch(ctx, queries)
 
-	return data, nil
-}
+	var errs []error
+	for i := range summaries {
+		summary := &summaries[i]
+		queueResults := results[i*queriesPerQueue : (i+1)*queriesPerQueue]
 
-// Helper functions
-func strPtr(s string) *string {
-	return &s
-}
+		for n, result := range queueResults {
+			if result.Err != nil {
+				metricName := queries[i*queriesPerQueue+n].MetricName
+				errs = append(errs, fmt.Errorf("queue %s: failed to get metric data for %s: %w", summary.Name, metricName, result.Err))
+			}
+		}
+
+		// No datapoints means no data, e.g. for an idle queue; callers render
+		// an explicit "no data" state for an empty slice
+		summary.MetricsEnd = queueResults[querySent].End
+		summary.MetricsWindow = c.window
+		summary.SentMessages = queueResults[querySent].Values
+		summary.VisibleMessages = queueResults[queryVisible].Values
+		summary.OldestMessageAge = queueResults[queryOldestAge].Values
+	}
 
-func int32Ptr(i int32) *int32 {
-	return &i
+	return errs
 }
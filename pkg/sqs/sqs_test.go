@@ -0,0 +1,290 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+// Mock SQS client
+type mockSQSClient struct {
+	listQueuesFunc         func(ctx context.Context, params *sqs.ListQueuesInput, optFns ...func(*sqs.Options)) (*sqs.ListQueuesOutput, error)
+	getQueueAttributesFunc func(ctx context.Context, params *sqs.GetQueueAttributesInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error)
+	receiveMessageFunc     func(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error)
+	purgeQueueFunc         func(ctx context.Context, params *sqs.PurgeQueueInput, optFns ...func(*sqs.Options)) (*sqs.PurgeQueueOutput, error)
+	changeVisibilityFunc   func(ctx context.Context, params *sqs.ChangeMessageVisibilityBatchInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityBatchOutput, error)
+}
+
+func (m *mockSQSClient) ListQueues(ctx context.Context, params *sqs.ListQueuesInput, optFns ...func(*sqs.Options)) (*sqs.ListQueuesOutput, error) {
+	return m.listQueuesFunc(ctx, params, optFns...)
+}
+
+func (m *mockSQSClient) GetQueueAttributes(ctx context.Context, params *sqs.GetQueueAttributesInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error) {
+	return m.getQueueAttributesFunc(ctx, params, optFns...)
+}
+
+func (m *mockSQSClient) ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	return m.receiveMessageFunc(ctx, params, optFns...)
+}
+
+func (m *mockSQSClient) PurgeQueue(ctx context.Context, params *sqs.PurgeQueueInput, optFns ...func(*sqs.Options)) (*sqs.PurgeQueueOutput, error) {
+	return m.purgeQueueFunc(ctx, params, optFns...)
+}
+
+func (m *mockSQSClient) ChangeMessageVisibilityBatch(ctx context.Context, params *sqs.ChangeMessageVisibilityBatchInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityBatchOutput, error) {
+	return m.changeVisibilityFunc(ctx, params, optFns...)
+}
+
+// Mock CloudWatch client
+type mockCloudWatchClient struct {
+	getMetricDataFunc func(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error)
+}
+
+func (m *mockCloudWatchClient) GetMetricData(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+	return m.getMetricDataFunc(ctx, params, optFns...)
+}
+
+func TestGetQueuesLinksDeadLetterQueues(t *testing.T) {
+	attributes := map[string]map[string]string{
+		"https://sqs.us-east-1.amazonaws.com/123456789012/orders": {
+			"ApproximateNumberOfMessages": "3",
+			"RedrivePolicy":               `{"deadLetterTargetArn":"arn:aws:sqs:us-east-1:123456789012:orders-dlq","maxReceiveCount":5}`,
+		},
+		"https://sqs.us-east-1.amazonaws.com/123456789012/orders-dlq": {
+			"ApproximateNumberOfMessages": "12",
+		},
+	}
+
+	mockSQS := &mockSQSClient{
+		listQueuesFunc: func(ctx context.Context, params *sqs.ListQueuesInput, optFns ...func(*sqs.Options)) (*sqs.ListQueuesOutput, error) {
+			var urls []string
+			for url := range attributes {
+				urls = append(urls, url)
+			}
+			return &sqs.ListQueuesOutput{QueueUrls: urls}, nil
+		},
+		getQueueAttributesFunc: func(ctx context.Context, params *sqs.GetQueueAttributesInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error) {
+			return &sqs.GetQueueAttributesOutput{Attributes: attributes[*params.QueueUrl]}, nil
+		},
+	}
+
+	mockCloudWatch := &mockCloudWatchClient{
+		getMetricDataFunc: func(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+			output := &cloudwatch.GetMetricDataOutput{}
+			for _, query := range params.MetricDataQueries {
+				output.MetricDataResults = append(output.MetricDataResults, cwtypes.MetricDataResult{Id: query.Id, Values: []float64{1.0}})
+			}
+			return output, nil
+		},
+	}
+
+	client := NewClient(mockSQS, mockCloudWatch, "", nil, 0)
+
+	queues, errs := client.GetQueues(context.Background())
+	if len(errs) > 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+
+	if len(queues) != 2 {
+		t.Fatalf("Expected 2 queues, got %d", len(queues))
+	}
+
+	byName := make(map[string]QueueSummary)
+	for _, queue := range queues {
+		byName[queue.Name] = queue
+	}
+
+	source := byName["orders"]
+	if source.DeadLetterQueue != "orders-dlq" {
+		t.Errorf("Expected DLQ 'orders-dlq', got '%s'", source.DeadLetterQueue)
+	}
+	if source.MaxReceiveCount != 5 {
+		t.Errorf("Expected max receive count 5, got %d", source.MaxReceiveCount)
+	}
+	if source.DeadLetterQueueMessages != 12 {
+		t.Errorf("Expected 12 DLQ messages, got %d", source.DeadLetterQueueMessages)
+	}
+	if !source.HasNonEmptyDeadLetterQueue() {
+		t.Errorf("Expected 'orders' to be flagged with a non-empty DLQ")
+	}
+
+	dlq := byName["orders-dlq"]
+	if !dlq.IsDeadLetterQueue() {
+		t.Errorf("Expected 'orders-dlq' to be a dead-letter queue")
+	}
+	if len(dlq.SourceQueues) != 1 || dlq.SourceQueues[0] != "orders" {
+		t.Errorf("Expected source queues [orders], got %v", dlq.SourceQueues)
+	}
+}
+
+func TestParseRedrivePolicy(t *testing.T) {
+	testCases := []struct {
+		name            string
+		policy          string
+		expectedDLQ     string
+		expectedReceive int
+		wantErr         bool
+	}{
+		{
+			name:            "numeric max receive count",
+			policy:          `{"deadLetterTargetArn":"arn:aws:sqs:us-east-1:123456789012:my-dlq","maxReceiveCount":3}`,
+			expectedDLQ:     "my-dlq",
+			expectedReceive: 3,
+		},
+		{
+			name:            "string max receive count",
+			policy:          `{"deadLetterTargetArn":"arn:aws:sqs:us-east-1:123456789012:my-dlq.fifo","maxReceiveCount":"10"}`,
+			expectedDLQ:     "my-dlq.fifo",
+			expectedReceive: 10,
+		},
+		{
+			name:    "invalid JSON",
+			policy:  `not-json`,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dlq, maxReceive, err := parseRedrivePolicy(tc.policy)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseRedrivePolicy() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if dlq != tc.expectedDLQ {
+				t.Errorf("Expected DLQ '%s', got '%s'", tc.expectedDLQ, dlq)
+			}
+			if maxReceive != tc.expectedReceive {
+				t.Errorf("Expected max receive count %d, got %d", tc.expectedReceive, maxReceive)
+			}
+		})
+	}
+}
+
+func TestGetQueuesPartialFailure(t *testing.T) {
+	okURL := "https://sqs.us-east-1.amazonaws.com/123456789012/ok"
+	brokenURL := "https://sqs.us-east-1.amazonaws.com/123456789012/broken"
+
+	mockSQS := &mockSQSClient{
+		listQueuesFunc: func(ctx context.Context, params *sqs.ListQueuesInput, optFns ...func(*sqs.Options)) (*sqs.ListQueuesOutput, error) {
+			return &sqs.ListQueuesOutput{QueueUrls: []string{okURL, brokenURL}}, nil
+		},
+		getQueueAttributesFunc: func(ctx context.Context, params *sqs.GetQueueAttributesInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error) {
+			if *params.QueueUrl == brokenURL {
+				return nil, errors.New("AccessDenied")
+			}
+			return &sqs.GetQueueAttributesOutput{Attributes: map[string]string{"ApproximateNumberOfMessages": "1"}}, nil
+		},
+	}
+
+	mockCloudWatch := &mockCloudWatchClient{
+		getMetricDataFunc: func(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+			return &cloudwatch.GetMetricDataOutput{}, nil
+		},
+	}
+
+	queues, errs := NewClient(mockSQS, mockCloudWatch, "", nil, 0).GetQueues(context.Background())
+
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %v", errs)
+	}
+	if len(queues) != 2 {
+		t.Fatalf("Expected both queues despite the failure, got %d", len(queues))
+	}
+	for _, queue := range queues {
+		if queue.Name == "broken" && queue.Type != "Unknown" {
+			t.Errorf("Expected unknown type for the broken queue, got '%s'", queue.Type)
+		}
+		if queue.Name == "ok" && queue.Type != "Standard" {
+			t.Errorf("Expected standard type for the ok queue, got '%s'", queue.Type)
+		}
+	}
+}
+
+func TestGetQueuesPaginates(t *testing.T) {
+	pages := map[string]*sqs.ListQueuesOutput{
+		"": {
+			QueueUrls: []string{"https://sqs.us-east-1.amazonaws.com/123456789012/app-a"},
+			NextToken: aws.String("page-2"),
+		},
+		"page-2": {
+			QueueUrls: []string{"https://sqs.us-east-1.amazonaws.com/123456789012/app-b"},
+		},
+	}
+
+	var inputs []sqs.ListQueuesInput
+	mockSQS := &mockSQSClient{
+		listQueuesFunc: func(ctx context.Context, params *sqs.ListQueuesInput, optFns ...func(*sqs.Options)) (*sqs.ListQueuesOutput, error) {
+			inputs = append(inputs, *params)
+			return pages[aws.ToString(params.NextToken)], nil
+		},
+		getQueueAttributesFunc: func(ctx context.Context, params *sqs.GetQueueAttributesInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error) {
+			return &sqs.GetQueueAttributesOutput{Attributes: map[string]string{}}, nil
+		},
+	}
+
+	mockCloudWatch := &mockCloudWatchClient{
+		getMetricDataFunc: func(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+			return &cloudwatch.GetMetricDataOutput{}, nil
+		},
+	}
+
+	queues, errs := NewClient(mockSQS, mockCloudWatch, "app-", nil, 0).GetQueues(context.Background())
+	if len(errs) > 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+	if len(queues) != 2 {
+		t.Fatalf("Expected queues from both pages, got %d", len(queues))
+	}
+
+	if len(inputs) != 2 {
+		t.Fatalf("Expected 2 ListQueues calls, got %d", len(inputs))
+	}
+	for _, input := range inputs {
+		// NextToken is only returned when MaxResults is set
+		if aws.ToInt32(input.MaxResults) != 1000 {
+			t.Errorf("Expected MaxResults 1000, got %v", input.MaxResults)
+		}
+		if aws.ToString(input.QueueNamePrefix) != "app-" {
+			t.Errorf("Expected QueueNamePrefix 'app-', got %v", input.QueueNamePrefix)
+		}
+	}
+}
+
+func TestGetQueuesMetricsWindow(t *testing.T) {
+	mockSQS := &mockSQSClient{
+		listQueuesFunc: func(ctx context.Context, params *sqs.ListQueuesInput, optFns ...func(*sqs.Options)) (*sqs.ListQueuesOutput, error) {
+			return &sqs.ListQueuesOutput{QueueUrls: []string{"https://sqs.us-east-1.amazonaws.com/123456789012/orders"}}, nil
+		},
+		getQueueAttributesFunc: func(ctx context.Context, params *sqs.GetQueueAttributesInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error) {
+			return &sqs.GetQueueAttributesOutput{Attributes: map[string]string{"ApproximateNumberOfMessages": "1"}}, nil
+		},
+	}
+
+	var input *cloudwatch.GetMetricDataInput
+	mockCloudWatch := &mockCloudWatchClient{
+		getMetricDataFunc: func(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+			input = params
+			return &cloudwatch.GetMetricDataOutput{}, nil
+		},
+	}
+
+	queues, errs := NewClient(mockSQS, mockCloudWatch, "", nil, 6*time.Hour).GetQueues(context.Background())
+	if len(errs) != 0 || len(queues) != 1 {
+		t.Fatalf("Expected 1 queue without errors, got %v, %v", queues, errs)
+	}
+	if window := input.EndTime.Sub(*input.StartTime); window != 6*time.Hour {
+		t.Errorf("Expected the metrics of 6 hours, got %s", window)
+	}
+	if period := aws.ToInt32(input.MetricDataQueries[0].MetricStat.Period); period != 1800 {
+		t.Errorf("Expected a period of 30 minutes, got %ds", period)
+	}
+	if queues[0].MetricsWindow != 6*time.Hour || queues[0].MetricsEnd.Sub(queues[0].MetricsStart()) != 6*time.Hour {
+		t.Errorf("Expected the queue to record its 6 hour window, got %s", queues[0].MetricsWindow)
+	}
+}
@@ -0,0 +1,24 @@
+package sqs
+
+import (
+	"strconv"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// Columns are the columns of the SQS queues table. Counts and ages sort the
+// largest first.
+var Columns = []common.Column[QueueSummary]{
+	{Title: "Name", Width: 32, Value: func(q QueueSummary) string { return q.Name }},
+	{Title: "Type", Width: 8, Value: func(q QueueSummary) string { return q.Type }},
+	{Title: "Messages", Width: 9, Value: func(q QueueSummary) string { return strconv.FormatInt(q.ApproximateMessages, 10) }, Less: func(a, b QueueSummary) bool {
+		return a.ApproximateMessages > b.ApproximateMessages
+	}},
+	{Title: "Oldest", Width: 8, Value: func(q QueueSummary) string { return formatAge(q.CurrentOldestMessageAge()) }, Less: func(a, b QueueSummary) bool {
+		return a.CurrentOldestMessageAge() > b.CurrentOldestMessageAge()
+	}},
+	{Title: "DLQ", Width: 24, Value: func(q QueueSummary) string { return q.DeadLetterQueue }},
+	{Title: "DLQ Msgs", Width: 9, Value: func(q QueueSummary) string { return strconv.FormatInt(q.DeadLetterQueueMessages, 10) }, Less: func(a, b QueueSummary) bool {
+		return a.DeadLetterQueueMessages > b.DeadLetterQueueMessages
+	}},
+}
@@ -0,0 +1,30 @@
+package sqs
+
+import (
+	"testing"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+func TestColumns(t *testing.T) {
+	queues := []QueueSummary{
+		{Name: "emails", ApproximateMessages: 3, OldestMessageAge: []float64{120}},
+		{Name: "orders", ApproximateMessages: 1200, OldestMessageAge: []float64{7200}, DeadLetterQueue: "orders-dlq", DeadLetterQueueMessages: 4},
+		{Name: "orders-dlq", ApproximateMessages: 4},
+	}
+
+	byName := common.SortRows(queues, Columns, 0)
+	if byName[0].Name != "emails" || byName[2].Name != "orders-dlq" {
+		t.Errorf("Expected queues sorted by name, got %v", byName)
+	}
+
+	byMessages := common.SortRows(queues, Columns, 2)
+	if byMessages[0].Name != "orders" || byMessages[2].Name != "emails" {
+		t.Errorf("Expected the fullest queue first, got %v", byMessages)
+	}
+
+	cells := common.TableCells(byMessages[:1], Columns)
+	if cells[0][2] != "1200" || cells[0][3] != "2h 0m" || cells[0][4] != "orders-dlq" {
+		t.Errorf("Unexpected cells %v", cells[0])
+	}
+}
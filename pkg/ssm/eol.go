@@ -0,0 +1,59 @@
+package ssm
+
+import (
+	"strings"
+	"time"
+)
+
+// eolEntry is the end-of-life date of an operating system release
+type eolEntry struct {
+	platform string // Prefix of the platform name reported by the SSM agent
+	version  string // Major version, empty when the platform name includes it
+	date     time.Time
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// endOfLife lists the end of standard support of common server operating
+// systems, by platform name and version as reported in the SSM inventory
+var endOfLife = []eolEntry{
+	{"Amazon Linux", "2018.03", date(2023, time.December, 31)},
+	{"Amazon Linux", "2", date(2026, time.June, 30)},
+	{"Amazon Linux", "2023", date(2029, time.June, 30)},
+	{"Ubuntu", "16.04", date(2021, time.April, 30)},
+	{"Ubuntu", "18.04", date(2023, time.May, 31)},
+	{"Ubuntu", "20.04", date(2025, time.May, 31)},
+	{"Ubuntu", "22.04", date(2027, time.June, 1)},
+	{"Ubuntu", "24.04", date(2029, time.May, 31)},
+	{"CentOS", "7", date(2024, time.June, 30)},
+	{"CentOS", "8", date(2021, time.December, 31)},
+	{"Red Hat Enterprise Linux", "7", date(2024, time.June, 30)},
+	{"Red Hat Enterprise Linux", "8", date(2029, time.May, 31)},
+	{"Red Hat Enterprise Linux", "9", date(2032, time.May, 31)},
+	{"Debian", "9", date(2022, time.June, 30)},
+	{"Debian", "10", date(2024, time.June, 30)},
+	{"Debian", "11", date(2026, time.August, 31)},
+	{"Debian", "12", date(2028, time.June, 30)},
+	{"SUSE Linux Enterprise Server", "12", date(2024, time.October, 31)},
+	{"SUSE Linux Enterprise Server", "15", date(2031, time.July, 31)},
+	{"Microsoft Windows Server 2008", "", date(2020, time.January, 14)},
+	{"Microsoft Windows Server 2012", "", date(2023, time.October, 10)},
+	{"Microsoft Windows Server 2016", "", date(2027, time.January, 12)},
+	{"Microsoft Windows Server 2019", "", date(2029, time.January, 9)},
+	{"Microsoft Windows Server 2022", "", date(2031, time.October, 14)},
+}
+
+// lookupEndOfLife returns the end-of-life date of a platform, if known
+func lookupEndOfLife(platform, version string) (time.Time, bool) {
+	for _, entry := range endOfLife {
+		if !strings.HasPrefix(platform, entry.platform) {
+			continue
+		}
+		if entry.version == "" || version == entry.version || strings.HasPrefix(version, entry.version+".") {
+			return entry.date, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,197 @@
+package ssm
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+var timeNow = time.Now
+
+// FormatInstances formats SSM instance summaries for terminal display,
+// listing running instances that are not managed by SSM first
+func FormatInstances(summaries []InstanceSummary) string {
+	if len(summaries) == 0 {
+		return "No instances found"
+	}
+
+	var output strings.Builder
+	output.WriteString("SSM MANAGED INSTANCES\n")
+	output.WriteString(common.Rule("SSM MANAGED INSTANCES", "=") + "\n\n")
+
+	unmanaged := GetUnmanagedInstances(summaries)
+	if len(unmanaged) > 0 {
+		output.WriteString(fmt.Sprintf("%s NOT MANAGED BY SSM (%d running instances)\n", common.Symbol("⚠️"), len(unmanaged)))
+		for _, instance := range unmanaged {
+			output.WriteString(fmt.Sprintf("  %s (%s)\n", displayName(instance), instance.InstanceID))
+		}
+		output.WriteString("\n")
+	}
+
+	for _, instance := range summaries {
+		if !instance.Managed {
+			continue
+		}
+
+		output.WriteString(fmt.Sprintf("%s %s (%s)\n", common.Symbol(getPingStatusSymbol(instance.PingStatus)), displayName(instance), instance.InstanceID))
+
+		ping := instance.PingStatus
+		if !instance.LastPingTime.IsZero() {
+			ping += fmt.Sprintf(" (last ping %s ago)", formatSince(instance.LastPingTime))
+		}
+		agent := instance.AgentVersion
+		if !instance.LatestAgent {
+			agent += " (update available)"
+		}
+		output.WriteString(fmt.Sprintf("  Ping: %s | Agent: %s\n", ping, agent))
+
+		if instance.Platform != "" {
+			output.WriteString("  " + formatPlatform(instance) + "\n")
+		}
+
+		output.WriteString("  " + formatPatchState(instance) + "\n\n")
+	}
+
+	return output.String()
+}
+
+// GetInstancesSummary returns a one-line summary of SSM management and patch compliance
+func GetInstancesSummary(summaries []InstanceSummary) string {
+	managed, online, nonCompliant, endOfLife := 0, 0, 0, 0
+	for _, instance := range summaries {
+		if instance.IsPastEndOfLife() {
+			endOfLife++
+		}
+		if !instance.Managed {
+			continue
+		}
+		managed++
+		if instance.PingStatus == "Online" {
+			online++
+		}
+		if instance.PatchCompliance() == "Non-compliant" {
+			nonCompliant++
+		}
+	}
+
+	summary := fmt.Sprintf("%d managed (%d online), %d non-compliant", managed, online, nonCompliant)
+
+	if endOfLife > 0 {
+		summary += fmt.Sprintf(", %d past end of life", endOfLife)
+	}
+
+	if unmanaged := len(GetUnmanagedInstances(summaries)); unmanaged > 0 {
+		summary += fmt.Sprintf(", ⚠️ %d running instances not managed", unmanaged)
+	}
+
+	if compliant, total := FleetCompliance(summaries); total > 0 {
+		summary += fmt.Sprintf(", fleet %d%% compliant (%d/%d)", compliant*100/total, compliant, total)
+	}
+
+	return summary
+}
+
+// FleetCompliance counts the instances that are managed, patch compliant,
+// free of missing critical patches and on a supported operating system,
+// out of all managed instances and running unmanaged EC2 instances
+func FleetCompliance(summaries []InstanceSummary) (compliant, total int) {
+	for _, instance := range summaries {
+		if !instance.Managed && !instance.IsUnmanaged() {
+			continue
+		}
+		total++
+		if instance.Managed && instance.PatchCompliance() == "Compliant" &&
+			instance.CriticalMissing == 0 && !instance.IsPastEndOfLife() {
+			compliant++
+		}
+	}
+	return compliant, total
+}
+
+// GetUnmanagedInstances returns the running EC2 instances that are not registered with SSM
+func GetUnmanagedInstances(summaries []InstanceSummary) []InstanceSummary {
+	var instances []InstanceSummary
+	for _, instance := range summaries {
+		if instance.IsUnmanaged() {
+			instances = append(instances, instance)
+		}
+	}
+	return instances
+}
+
+// formatPatchState describes the patch compliance of an instance
+func formatPatchState(instance InstanceSummary) string {
+	switch instance.PatchCompliance() {
+	case "Compliant":
+		return fmt.Sprintf("Patches: %s Compliant (last %s %s)",
+			common.Symbol("✅"), instance.LastPatchOperation, instance.LastPatchTime.Format("2006-01-02 15:04"))
+	case "Non-compliant":
+		missing := fmt.Sprintf("%d missing", instance.MissingPatches)
+		if instance.CriticalMissing > 0 {
+			missing += fmt.Sprintf(" (%d critical)", instance.CriticalMissing)
+		}
+		return fmt.Sprintf("Patches: %s Non-compliant: %s, %d failed, %d pending reboot (last %s %s)",
+			common.Symbol("❌"), missing, instance.FailedPatches, instance.PendingReboot,
+			instance.LastPatchOperation, instance.LastPatchTime.Format("2006-01-02 15:04"))
+	default:
+		return fmt.Sprintf("Patches: %s No patch scan reported", common.Symbol("❓"))
+	}
+}
+
+// formatPlatform describes the operating system of an instance and its end-of-life status
+func formatPlatform(instance InstanceSummary) string {
+	platform := strings.TrimSpace(instance.Platform + " " + instance.Version)
+
+	eol, ok := instance.EndOfLife()
+	switch {
+	case !ok:
+		return "Platform: " + platform
+	case instance.IsPastEndOfLife():
+		return fmt.Sprintf("Platform: %s %s END OF LIFE since %s", platform, common.Symbol("🚨"), eol.Format("2006-01-02"))
+	case eol.Sub(timeNow()) < 180*24*time.Hour:
+		return fmt.Sprintf("Platform: %s %s end of life on %s", platform, common.Symbol("⚠️"), eol.Format("2006-01-02"))
+	default:
+		return fmt.Sprintf("Platform: %s (supported until %s)", platform, eol.Format("2006-01-02"))
+	}
+}
+
+// displayName returns the instance name or a placeholder for unnamed instances
+func displayName(instance InstanceSummary) string {
+	if instance.Name == "" {
+		return "<unnamed>"
+	}
+	return instance.Name
+}
+
+// formatSince formats the time elapsed since t as a short duration
+func formatSince(t time.Time) string {
+	duration := timeNow().Sub(t)
+
+	days := int(duration.Hours() / 24)
+	hours := int(duration.Hours()) % 24
+	minutes := int(duration.Minutes()) % 60
+
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh", days, hours)
+	}
+	if hours > 0 {
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
+
+// getPingStatusSymbol returns an appropriate symbol for an SSM ping status
+func getPingStatusSymbol(status string) string {
+	switch status {
+	case "Online":
+		return "🟢"
+	case "ConnectionLost":
+		return "🔴"
+	case "Inactive":
+		return "⚪"
+	default:
+		return "❓"
+	}
+}
@@ -0,0 +1,110 @@
+package ssm
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatInstances(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	if result := FormatInstances(nil); result != "No instances found" {
+		t.Errorf("Expected 'No instances found', got '%s'", result)
+	}
+
+	summaries := []InstanceSummary{
+		{InstanceID: "i-1", Name: "web", State: "running", Managed: true, PingStatus: "Online", AgentVersion: "3.3.0.0",
+			Platform: "Ubuntu", Version: "22.04", LastPingTime: now.Add(-5 * time.Minute), HasPatchState: true,
+			LastPatchOperation: "Scan", LastPatchTime: now},
+		{InstanceID: "i-2", Name: "legacy", State: "running"},
+		{InstanceID: "i-3", Name: "worker", State: "running", Managed: true, PingStatus: "ConnectionLost", LatestAgent: true,
+			Platform: "CentOS Linux", Version: "7.9.2009", HasPatchState: true, MissingPatches: 2, CriticalMissing: 1},
+	}
+
+	result := FormatInstances(summaries)
+
+	expectedElements := []string{
+		"SSM MANAGED INSTANCES",
+		"NOT MANAGED BY SSM (1 running instances)",
+		"legacy (i-2)",
+		"🟢 web (i-1)",
+		"Ping: Online (last ping 5m ago) | Agent: 3.3.0.0 (update available)",
+		"Platform: Ubuntu 22.04 (supported until 2027-06-01)",
+		"Compliant (last Scan 2025-03-01 12:00)",
+		"🔴 worker (i-3)",
+		"Platform: CentOS Linux 7.9.2009 🚨 END OF LIFE since 2024-06-30",
+		"Non-compliant: 2 missing (1 critical), 0 failed, 0 pending reboot",
+	}
+
+	for _, expected := range expectedElements {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected output to contain '%s', but it didn't:\n%s", expected, result)
+		}
+	}
+}
+
+func TestGetInstancesSummary(t *testing.T) {
+	summaries := []InstanceSummary{
+		{InstanceID: "i-1", State: "running", Managed: true, PingStatus: "Online", HasPatchState: true, FailedPatches: 1},
+		{InstanceID: "i-2", State: "running"},
+		{InstanceID: "i-3", State: "stopped"},
+	}
+
+	expected := "1 managed (1 online), 1 non-compliant, ⚠️ 1 running instances not managed, fleet 0% compliant (0/2)"
+	if result := GetInstancesSummary(summaries); result != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, result)
+	}
+}
+
+func TestFleetCompliance(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	summaries := []InstanceSummary{
+		{InstanceID: "i-1", State: "running", Managed: true, Platform: "Amazon Linux", Version: "2023", HasPatchState: true},
+		{InstanceID: "i-2", State: "running", Managed: true, Platform: "Ubuntu", Version: "18.04", HasPatchState: true},
+		{InstanceID: "i-3", State: "running", Managed: true, HasPatchState: true, CriticalMissing: 1},
+		{InstanceID: "i-4", State: "running", Managed: true},
+		{InstanceID: "i-5", State: "running"},
+		{InstanceID: "i-6", State: "stopped"},
+	}
+
+	compliant, total := FleetCompliance(summaries)
+	if compliant != 1 || total != 5 {
+		t.Errorf("Expected 1/5 compliant, got %d/%d", compliant, total)
+	}
+}
+
+func TestLookupEndOfLife(t *testing.T) {
+	testCases := []struct {
+		platform string
+		version  string
+		expected string
+	}{
+		{"Amazon Linux", "2", "2026-06-30"},
+		{"Amazon Linux", "2023", "2029-06-30"},
+		{"Ubuntu", "20.04", "2025-05-31"},
+		{"CentOS Linux", "7.9.2009", "2024-06-30"},
+		{"Red Hat Enterprise Linux Server", "7.9", "2024-06-30"},
+		{"Microsoft Windows Server 2012 R2 Standard", "6.3.9600", "2023-10-10"},
+		{"Ubuntu", "23.10", ""},
+		{"Unknown OS", "1", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.platform+" "+tc.version, func(t *testing.T) {
+			eol, ok := lookupEndOfLife(tc.platform, tc.version)
+			got := ""
+			if ok {
+				got = eol.Format("2006-01-02")
+			}
+			if got != tc.expected {
+				t.Errorf("Expected '%s', got '%s'", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package ssm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// patchStatesBatchSize is the maximum number of instance IDs accepted by DescribeInstancePatchStates
+const patchStatesBatchSize = 50
+
+// ssmClientAPI defines the interface for the SSM client
+type ssmClientAPI interface {
+	DescribeInstanceInformation(ctx context.Context, params *ssm.DescribeInstanceInformationInput, optFns ...func(*ssm.Options)) (*ssm.DescribeInstanceInformationOutput, error)
+	DescribeInstancePatchStates(ctx context.Context, params *ssm.DescribeInstancePatchStatesInput, optFns ...func(*ssm.Options)) (*ssm.DescribeInstancePatchStatesOutput, error)
+}
+
+// ec2ClientAPI defines the interface for the EC2 client
+type ec2ClientAPI interface {
+	DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error)
+}
+
+// Client represents an SSM client
+type Client struct {
+	ssmClient ssmClientAPI
+	ec2Client ec2ClientAPI
+}
+
+// InstanceSummary represents the SSM management and patch state of an instance
+type InstanceSummary struct {
+	InstanceID   string
+	Name         string
+	State        string // EC2 instance state, empty for non-EC2 managed nodes
+	Managed      bool
+	PingStatus   string
+	AgentVersion string
+	LatestAgent  bool
+	Platform     string
+	Version      string // Platform version, e.g. "22.04"
+	LastPingTime time.Time
+
+	// Patch state, only set when the instance reported a patch scan
+	HasPatchState      bool
+	MissingPatches     int32
+	CriticalMissing    int32
+	FailedPatches      int32
+	PendingReboot      int32
+	LastPatchOperation string
+	LastPatchTime      time.Time
+}
+
+// IsUnmanaged reports whether a running EC2 instance is not registered with SSM
+func (s InstanceSummary) IsUnmanaged() bool {
+	return !s.Managed && s.State == string(ec2types.InstanceStateNameRunning)
+}
+
+// PatchCompliance returns "Compliant", "Non-compliant" or "Unknown" when no
+// patch scan was reported
+func (s InstanceSummary) PatchCompliance() string {
+	if !s.HasPatchState {
+		return "Unknown"
+	}
+	if s.MissingPatches > 0 || s.FailedPatches > 0 || s.PendingReboot > 0 {
+		return "Non-compliant"
+	}
+	return "Compliant"
+}
+
+// EndOfLife returns the end-of-life date of the instance's operating system, if known
+func (s InstanceSummary) EndOfLife() (time.Time, bool) {
+	return lookupEndOfLife(s.Platform, s.Version)
+}
+
+// IsPastEndOfLife reports whether the instance runs an operating system past its end of life
+func (s InstanceSummary) IsPastEndOfLife() bool {
+	eol, ok := s.EndOfLife()
+	return ok && timeNow().After(eol)
+}
+
+// NewClient returns a new SSM client
+func NewClient(ssmClient ssmClientAPI, ec2Client ec2ClientAPI) *Client {
+	return &Client{
+		ssmClient: ssmClient,
+		ec2Client: ec2Client,
+	}
+}
+
+// GetInstances returns the EC2 instances and other managed nodes with their
+// SSM management and patch state. Patch states that fail to load are left
+// empty and their errors returned alongside the instances.
+func (c *Client) GetInstances(ctx context.Context) ([]InstanceSummary, []error) {
+	instances, err := c.getEC2Instances(ctx)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	information, err := c.getInstanceInformation(ctx)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	// Merge the managed nodes into the EC2 instances, keeping managed nodes
+	// outside EC2 (e.g. on-premises servers) as well
+	index := make(map[string]int, len(instances))
+	for i, instance := range instances {
+		index[instance.InstanceID] = i
+	}
+
+	var managedIDs []string
+	for _, info := range information {
+		id := aws.ToString(info.InstanceId)
+		i, ok := index[id]
+		if !ok {
+			instances = append(instances, InstanceSummary{InstanceID: id, Name: aws.ToString(info.ComputerName)})
+			i = len(instances) - 1
+			index[id] = i
+		}
+
+		instances[i].Managed = true
+		instances[i].PingStatus = string(info.PingStatus)
+		instances[i].AgentVersion = aws.ToString(info.AgentVersion)
+		instances[i].LatestAgent = aws.ToBool(info.IsLatestVersion)
+		instances[i].Platform = aws.ToString(info.PlatformName)
+		instances[i].Version = aws.ToString(info.PlatformVersion)
+		if info.LastPingDateTime != nil {
+			instances[i].LastPingTime = *info.LastPingDateTime
+		}
+		managedIDs = append(managedIDs, id)
+	}
+
+	var errs []error
+	for start := 0; start < len(managedIDs); start += patchStatesBatchSize {
+		end := min(start+patchStatesBatchSize, len(managedIDs))
+		states, err := c.getPatchStates(ctx, managedIDs[start:end])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, state := range states {
+			i, ok := index[aws.ToString(state.InstanceId)]
+			if !ok {
+				continue
+			}
+			instances[i].HasPatchState = true
+			instances[i].MissingPatches = state.MissingCount
+			instances[i].CriticalMissing = aws.ToInt32(state.CriticalNonCompliantCount)
+			instances[i].FailedPatches = state.FailedCount
+			instances[i].PendingReboot = aws.ToInt32(state.InstalledPendingRebootCount)
+			instances[i].LastPatchOperation = string(state.Operation)
+			if state.OperationEndTime != nil {
+				instances[i].LastPatchTime = *state.OperationEndTime
+			}
+		}
+	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		if instances[i].Name != instances[j].Name {
+			return instances[i].Name < instances[j].Name
+		}
+		return instances[i].InstanceID < instances[j].InstanceID
+	})
+
+	return instances, errs
+}
+
+// getEC2Instances returns the non-terminated EC2 instances with their name and state
+func (c *Client) getEC2Instances(ctx context.Context) ([]InstanceSummary, error) {
+	var instances []InstanceSummary
+	var nextToken *string
+
+	for {
+		resp, err := c.ec2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe instances: %w", err)
+		}
+
+		for _, reservation := range resp.Reservations {
+			for _, instance := range reservation.Instances {
+				if instance.State == nil || instance.State.Name == ec2types.InstanceStateNameTerminated {
+					continue
+				}
+
+				summary := InstanceSummary{
+					InstanceID: aws.ToString(instance.InstanceId),
+					State:      string(instance.State.Name),
+				}
+				for _, tag := range instance.Tags {
+					if aws.ToString(tag.Key) == "Name" {
+						summary.Name = aws.ToString(tag.Value)
+					}
+				}
+				instances = append(instances, summary)
+			}
+		}
+
+		if resp.NextToken == nil {
+			break
+		}
+		nextToken = resp.NextToken
+	}
+
+	return instances, nil
+}
+
+// getInstanceInformation returns all nodes registered with SSM
+func (c *Client) getInstanceInformation(ctx context.Context) ([]types.InstanceInformation, error) {
+	var information []types.InstanceInformation
+	var nextToken *string
+
+	for {
+		resp, err := c.ssmClient.DescribeInstanceInformation(ctx, &ssm.DescribeInstanceInformationInput{
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe instance information: %w", err)
+		}
+
+		information = append(information, resp.InstanceInformationList...)
+
+		if resp.NextToken == nil {
+			break
+		}
+		nextToken = resp.NextToken
+	}
+
+	return information, nil
+}
+
+// getPatchStates returns the patch states of up to patchStatesBatchSize instances
+func (c *Client) getPatchStates(ctx context.Context, instanceIDs []string) ([]types.InstancePatchState, error) {
+	var states []types.InstancePatchState
+	var nextToken *string
+
+	for {
+		resp, err := c.ssmClient.DescribeInstancePatchStates(ctx, &ssm.DescribeInstancePatchStatesInput{
+			InstanceIds: instanceIDs,
+			NextToken:   nextToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe instance patch states: %w", err)
+		}
+
+		states = append(states, resp.InstancePatchStates...)
+
+		if resp.NextToken == nil {
+			break
+		}
+		nextToken = resp.NextToken
+	}
+
+	return states, nil
+}
@@ -0,0 +1,161 @@
+package ssm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// Mock SSM client
+type mockSSMClient struct {
+	describeInstanceInformationFunc func(ctx context.Context, params *ssm.DescribeInstanceInformationInput, optFns ...func(*ssm.Options)) (*ssm.DescribeInstanceInformationOutput, error)
+	describeInstancePatchStatesFunc func(ctx context.Context, params *ssm.DescribeInstancePatchStatesInput, optFns ...func(*ssm.Options)) (*ssm.DescribeInstancePatchStatesOutput, error)
+}
+
+func (m *mockSSMClient) DescribeInstanceInformation(ctx context.Context, params *ssm.DescribeInstanceInformationInput, optFns ...func(*ssm.Options)) (*ssm.DescribeInstanceInformationOutput, error) {
+	return m.describeInstanceInformationFunc(ctx, params, optFns...)
+}
+
+func (m *mockSSMClient) DescribeInstancePatchStates(ctx context.Context, params *ssm.DescribeInstancePatchStatesInput, optFns ...func(*ssm.Options)) (*ssm.DescribeInstancePatchStatesOutput, error) {
+	return m.describeInstancePatchStatesFunc(ctx, params, optFns...)
+}
+
+// Mock EC2 client
+type mockEC2Client struct {
+	describeInstancesFunc func(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error)
+}
+
+func (m *mockEC2Client) DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	return m.describeInstancesFunc(ctx, params, optFns...)
+}
+
+func ec2Instance(id, name string, state ec2types.InstanceStateName) ec2types.Instance {
+	return ec2types.Instance{
+		InstanceId: aws.String(id),
+		State:      &ec2types.InstanceState{Name: state},
+		Tags:       []ec2types.Tag{{Key: aws.String("Name"), Value: aws.String(name)}},
+	}
+}
+
+func TestGetInstances(t *testing.T) {
+	mockEC2 := &mockEC2Client{
+		describeInstancesFunc: func(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+			return &ec2.DescribeInstancesOutput{
+				Reservations: []ec2types.Reservation{
+					{Instances: []ec2types.Instance{
+						ec2Instance("i-managed", "web", ec2types.InstanceStateNameRunning),
+						ec2Instance("i-unmanaged", "legacy", ec2types.InstanceStateNameRunning),
+						ec2Instance("i-stopped", "batch", ec2types.InstanceStateNameStopped),
+					}},
+				},
+			}, nil
+		},
+	}
+
+	mockSSM := &mockSSMClient{
+		describeInstanceInformationFunc: func(ctx context.Context, params *ssm.DescribeInstanceInformationInput, optFns ...func(*ssm.Options)) (*ssm.DescribeInstanceInformationOutput, error) {
+			return &ssm.DescribeInstanceInformationOutput{
+				InstanceInformationList: []types.InstanceInformation{
+					{InstanceId: aws.String("i-managed"), PingStatus: types.PingStatusOnline, AgentVersion: aws.String("3.3.0.0"), IsLatestVersion: aws.Bool(true),
+						PlatformName: aws.String("Ubuntu"), PlatformVersion: aws.String("18.04")},
+					{InstanceId: aws.String("mi-onprem"), ComputerName: aws.String("onprem-db"), PingStatus: types.PingStatusConnectionLost},
+				},
+			}, nil
+		},
+		describeInstancePatchStatesFunc: func(ctx context.Context, params *ssm.DescribeInstancePatchStatesInput, optFns ...func(*ssm.Options)) (*ssm.DescribeInstancePatchStatesOutput, error) {
+			return &ssm.DescribeInstancePatchStatesOutput{
+				InstancePatchStates: []types.InstancePatchState{
+					{InstanceId: aws.String("i-managed"), MissingCount: 3, CriticalNonCompliantCount: aws.Int32(1), Operation: types.PatchOperationTypeScan},
+				},
+			}, nil
+		},
+	}
+
+	instances, errs := NewClient(mockSSM, mockEC2).GetInstances(context.Background())
+	if len(errs) > 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+
+	if len(instances) != 4 {
+		t.Fatalf("Expected 4 instances, got %d", len(instances))
+	}
+
+	byID := make(map[string]InstanceSummary)
+	for _, instance := range instances {
+		byID[instance.InstanceID] = instance
+	}
+
+	managed := byID["i-managed"]
+	if !managed.Managed || managed.AgentVersion != "3.3.0.0" {
+		t.Errorf("Expected 'i-managed' to be managed with agent 3.3.0.0, got %+v", managed)
+	}
+	if managed.Version != "18.04" || !managed.IsPastEndOfLife() {
+		t.Errorf("Expected 'i-managed' to run Ubuntu 18.04 past end of life, got %+v", managed)
+	}
+	if managed.CriticalMissing != 1 {
+		t.Errorf("Expected 1 missing critical patch, got %d", managed.CriticalMissing)
+	}
+	if managed.PatchCompliance() != "Non-compliant" {
+		t.Errorf("Expected 'i-managed' to be non-compliant, got %s", managed.PatchCompliance())
+	}
+
+	if !byID["i-unmanaged"].IsUnmanaged() {
+		t.Errorf("Expected running 'i-unmanaged' to be flagged as unmanaged")
+	}
+	if byID["i-stopped"].IsUnmanaged() {
+		t.Errorf("Expected stopped 'i-stopped' not to be flagged")
+	}
+
+	onprem := byID["mi-onprem"]
+	if !onprem.Managed || onprem.Name != "onprem-db" {
+		t.Errorf("Expected managed on-premises node 'onprem-db', got %+v", onprem)
+	}
+	if onprem.PatchCompliance() != "Unknown" {
+		t.Errorf("Expected unknown compliance without patch state, got %s", onprem.PatchCompliance())
+	}
+}
+
+func TestGetInstancesBatchesPatchStates(t *testing.T) {
+	var information []types.InstanceInformation
+	for i := 0; i < 120; i++ {
+		information = append(information, types.InstanceInformation{InstanceId: aws.String(fmt.Sprintf("mi-%03d", i))})
+	}
+
+	var batches []int
+	mockSSM := &mockSSMClient{
+		describeInstanceInformationFunc: func(ctx context.Context, params *ssm.DescribeInstanceInformationInput, optFns ...func(*ssm.Options)) (*ssm.DescribeInstanceInformationOutput, error) {
+			return &ssm.DescribeInstanceInformationOutput{InstanceInformationList: information}, nil
+		},
+		describeInstancePatchStatesFunc: func(ctx context.Context, params *ssm.DescribeInstancePatchStatesInput, optFns ...func(*ssm.Options)) (*ssm.DescribeInstancePatchStatesOutput, error) {
+			batches = append(batches, len(params.InstanceIds))
+			if len(batches) == 2 {
+				return nil, errors.New("ThrottlingException")
+			}
+			return &ssm.DescribeInstancePatchStatesOutput{}, nil
+		},
+	}
+	mockEC2 := &mockEC2Client{
+		describeInstancesFunc: func(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+			return &ec2.DescribeInstancesOutput{}, nil
+		},
+	}
+
+	instances, errs := NewClient(mockSSM, mockEC2).GetInstances(context.Background())
+
+	if len(batches) != 3 || batches[0] != 50 || batches[2] != 20 {
+		t.Errorf("Expected batches of 50, 50 and 20 instances, got %v", batches)
+	}
+	if len(errs) != 1 {
+		t.Errorf("Expected the failed batch to be reported, got %v", errs)
+	}
+	if len(instances) != 120 {
+		t.Errorf("Expected all 120 instances despite the failed batch, got %d", len(instances))
+	}
+}
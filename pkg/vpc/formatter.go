@@ -0,0 +1,235 @@
+package vpc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// SubnetUse is a subnet and the name of its VPC
+type SubnetUse struct {
+	SubnetSummary
+	VPC string
+}
+
+// NATGatewayUse is a NAT gateway and the name of its VPC
+type NATGatewayUse struct {
+	NATGatewaySummary
+	VPC string
+}
+
+// FormatVPCs formats the VPCs for terminal display, listing the subnets low
+// on free IPs and the saturated NAT gateways first
+func FormatVPCs(summaries []VPCSummary) string {
+	if len(summaries) == 0 {
+		return "No VPCs found"
+	}
+
+	var output strings.Builder
+	output.WriteString("VPCS\n")
+	output.WriteString(common.Rule("VPCS", "=") + "\n\n")
+
+	if low := GetLowIPSubnets(summaries); len(low) > 0 {
+		output.WriteString(fmt.Sprintf("%s LOW ON FREE IPS (%d subnets below %.0f%% free)\n",
+			common.Symbol("⚠️"), len(low), LowFreeIPs))
+		for _, subnet := range low {
+			output.WriteString(fmt.Sprintf("  %s in %s: %s\n", subnetName(subnet.SubnetSummary), subnet.VPC, formatFreeIPs(subnet.SubnetSummary)))
+		}
+		output.WriteString("\n")
+	}
+	if saturated := GetSaturatedNATGateways(summaries); len(saturated) > 0 {
+		output.WriteString(fmt.Sprintf("%s NAT PORT ALLOCATION ERRORS (%d gateways out of source ports)\n",
+			common.Symbol("⚠️"), len(saturated)))
+		for _, gateway := range saturated {
+			output.WriteString(fmt.Sprintf("  %s in %s: %.0f errors in the past hour\n",
+				natGatewayName(gateway.NATGatewaySummary), gateway.VPC, gateway.PortAllocationErrors()))
+		}
+		output.WriteString("\n")
+	}
+
+	for _, vpc := range summaries {
+		header := fmt.Sprintf("%s %s %s", common.Symbol("🌐"), vpcName(vpc), vpc.CIDRBlock)
+		if vpc.IsDefault {
+			header += " (default)"
+		}
+		output.WriteString(header + "\n")
+
+		if len(vpc.InternetGateways) > 0 {
+			var gateways []string
+			for _, gateway := range vpc.InternetGateways {
+				gateways = append(gateways, internetGatewayName(gateway))
+			}
+			output.WriteString(fmt.Sprintf("  Internet gateways: %s\n", strings.Join(gateways, ", ")))
+		}
+
+		if len(vpc.NATGateways) > 0 {
+			output.WriteString("  NAT gateways:\n")
+			for _, gateway := range vpc.NATGateways {
+				output.WriteString(fmt.Sprintf("    %s %s\n", common.Symbol(getNATGatewaySymbol(gateway)), formatNATGateway(gateway)))
+				// Only the gateways out of ports have a curve worth drawing
+				if gateway.Saturated() {
+					output.WriteString(common.GenerateSparkline(gateway.PortAllocationErrorsData, "Port allocation errors", 3,
+						common.WithStats(), common.WithWindow(gateway.MetricsEnd.Add(-time.Hour), gateway.MetricsEnd)) + "\n")
+				}
+			}
+		}
+
+		if len(vpc.Subnets) > 0 {
+			output.WriteString(fmt.Sprintf("  Subnets (%d):\n", len(vpc.Subnets)))
+			for _, subnet := range vpc.Subnets {
+				symbol := "✅"
+				if subnet.LowOnIPs() {
+					symbol = "⚠️"
+				}
+				description := fmt.Sprintf("%s %s in %s: %s", subnetName(subnet), subnet.CIDRBlock, subnet.AvailabilityZone, formatFreeIPs(subnet))
+				if subnet.MapPublicIP {
+					description += ", public"
+				}
+				output.WriteString(fmt.Sprintf("    %s %s\n", common.Symbol(symbol), description))
+			}
+		} else {
+			output.WriteString("  No subnets\n")
+		}
+
+		if len(vpc.Endpoints) > 0 {
+			var endpoints []string
+			for _, endpoint := range vpc.Endpoints {
+				description := fmt.Sprintf("%s (%s)", endpoint.Service(), strings.ToLower(endpoint.Type))
+				if !endpoint.Available() {
+					description += " " + strings.ToLower(endpoint.State)
+				}
+				endpoints = append(endpoints, description)
+			}
+			output.WriteString(fmt.Sprintf("  Endpoints: %s\n", strings.Join(endpoints, ", ")))
+		}
+
+		output.WriteString("\n")
+	}
+
+	return output.String()
+}
+
+// GetVPCsSummary returns a brief summary of the VPCs
+func GetVPCsSummary(summaries []VPCSummary) string {
+	var subnets, gateways, endpoints int
+	for _, vpc := range summaries {
+		subnets += len(vpc.Subnets)
+		gateways += len(vpc.NATGateways)
+		endpoints += len(vpc.Endpoints)
+	}
+
+	summary := fmt.Sprintf("%d VPCs, %d subnets, %d NAT gateways, %d endpoints", len(summaries), subnets, gateways, endpoints)
+	if low := len(GetLowIPSubnets(summaries)); low > 0 {
+		summary += fmt.Sprintf(", %d subnets low on free IPs", low)
+	}
+	if saturated := len(GetSaturatedNATGateways(summaries)); saturated > 0 {
+		summary += fmt.Sprintf(", %d NAT gateways with port allocation errors", saturated)
+	}
+	return summary
+}
+
+// GetLowIPSubnets returns the subnets with less than LowFreeIPs percent of
+// their addresses free, fullest first
+func GetLowIPSubnets(summaries []VPCSummary) []SubnetUse {
+	var low []SubnetUse
+	for _, vpc := range summaries {
+		for _, subnet := range vpc.Subnets {
+			if subnet.LowOnIPs() {
+				low = append(low, SubnetUse{SubnetSummary: subnet, VPC: vpcName(vpc)})
+			}
+		}
+	}
+	sort.SliceStable(low, func(i, j int) bool { return low[i].FreePercent() < low[j].FreePercent() })
+	return low
+}
+
+// GetSaturatedNATGateways returns the NAT gateways with port allocation
+// errors in the past hour, those with the most first
+func GetSaturatedNATGateways(summaries []VPCSummary) []NATGatewayUse {
+	var saturated []NATGatewayUse
+	for _, vpc := range summaries {
+		for _, gateway := range vpc.NATGateways {
+			if gateway.Saturated() {
+				saturated = append(saturated, NATGatewayUse{NATGatewaySummary: gateway, VPC: vpcName(vpc)})
+			}
+		}
+	}
+	sort.SliceStable(saturated, func(i, j int) bool {
+		return saturated[i].PortAllocationErrors() > saturated[j].PortAllocationErrors()
+	})
+	return saturated
+}
+
+// formatFreeIPs describes the free addresses of a subnet, e.g.
+// "12 of 251 IPs free (4.78%)"
+func formatFreeIPs(subnet SubnetSummary) string {
+	total := subnet.TotalIPs()
+	if total <= 0 {
+		return fmt.Sprintf("%d IPs free", subnet.AvailableIPs)
+	}
+	return fmt.Sprintf("%d of %d IPs free (%s)", subnet.AvailableIPs, total, common.FormatPercentage(subnet.FreePercent()))
+}
+
+// formatNATGateway describes a NAT gateway, e.g.
+// "nat-a (nat-123) in subnet-1, 54.1.2.3, available"
+func formatNATGateway(gateway NATGatewaySummary) string {
+	description := fmt.Sprintf("%s in %s", natGatewayName(gateway), gateway.SubnetID)
+	if gateway.ConnectivityType == "private" {
+		description += ", private"
+	} else if gateway.PublicIP != "" {
+		description += ", " + gateway.PublicIP
+	}
+	description += ", " + gateway.State
+	if gateway.Saturated() {
+		description += fmt.Sprintf(", %.0f port allocation errors in the past hour", gateway.PortAllocationErrors())
+	}
+	return description
+}
+
+// vpcName returns the name and ID of a VPC, or its ID when it has no name
+func vpcName(vpc VPCSummary) string {
+	return withName(vpc.Name, vpc.ID)
+}
+
+// subnetName returns the name and ID of a subnet, or its ID when it has no name
+func subnetName(subnet SubnetSummary) string {
+	return withName(subnet.Name, subnet.ID)
+}
+
+// natGatewayName returns the name and ID of a NAT gateway, or its ID when it
+// has no name
+func natGatewayName(gateway NATGatewaySummary) string {
+	return withName(gateway.Name, gateway.ID)
+}
+
+// internetGatewayName returns the name and ID of an internet gateway, or its
+// ID when it has no name
+func internetGatewayName(gateway InternetGatewaySummary) string {
+	return withName(gateway.Name, gateway.ID)
+}
+
+// withName returns "name (id)", or the ID when the name is empty
+func withName(name, id string) string {
+	if name == "" {
+		return id
+	}
+	return fmt.Sprintf("%s (%s)", name, id)
+}
+
+// getNATGatewaySymbol returns the emoji of a NAT gateway's state
+func getNATGatewaySymbol(gateway NATGatewaySummary) string {
+	switch {
+	case gateway.Saturated():
+		return "⚠️"
+	case gateway.State == "available":
+		return "✅"
+	case gateway.State == "pending" || gateway.State == "deleting":
+		return "🔄"
+	case gateway.State == "failed":
+		return "❌"
+	}
+	return "❓"
+}
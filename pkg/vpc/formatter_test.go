@@ -0,0 +1,58 @@
+package vpc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatVPCs(t *testing.T) {
+	summaries := []VPCSummary{
+		{
+			ID: "vpc-1", Name: "prod", CIDRBlock: "10.0.0.0/16",
+			InternetGateways: []InternetGatewaySummary{{ID: "igw-1"}},
+			NATGateways: []NATGatewaySummary{
+				{ID: "nat-1", Name: "nat-a", SubnetID: "subnet-1", State: "available", PublicIP: "54.1.2.3", PortAllocationErrorsData: []float64{0, 40, 80}},
+				{ID: "nat-2", SubnetID: "subnet-2", State: "available", PublicIP: "54.1.2.4", PortAllocationErrorsData: []float64{0, 0, 0}},
+			},
+			Subnets: []SubnetSummary{
+				{ID: "subnet-1", Name: "public-a", CIDRBlock: "10.0.0.0/24", AvailabilityZone: "us-east-1a", AvailableIPs: 240, MapPublicIP: true},
+				{ID: "subnet-2", Name: "workers", CIDRBlock: "10.0.1.0/24", AvailabilityZone: "us-east-1b", AvailableIPs: 9},
+			},
+			Endpoints: []EndpointSummary{
+				{ID: "vpce-1", ServiceName: "com.amazonaws.us-east-1.s3", Type: "Gateway", State: "Available"},
+				{ID: "vpce-2", ServiceName: "com.amazonaws.vpce.us-east-1.vpce-svc-123", Type: "Interface", State: "PendingAcceptance"},
+			},
+		},
+		{ID: "vpc-2", CIDRBlock: "172.31.0.0/16", IsDefault: true},
+	}
+
+	output := FormatVPCs(summaries)
+
+	for _, expected := range []string{
+		"LOW ON FREE IPS (1 subnets below 10% free)\n  workers (subnet-2) in prod (vpc-1): 9 of 251 IPs free (3.59%)\n",
+		"NAT PORT ALLOCATION ERRORS (1 gateways out of source ports)\n  nat-a (nat-1) in prod (vpc-1): 120 errors in the past hour\n",
+		"🌐 prod (vpc-1) 10.0.0.0/16\n  Internet gateways: igw-1\n  NAT gateways:\n",
+		"    ⚠️ nat-a (nat-1) in subnet-1, 54.1.2.3, available, 120 port allocation errors in the past hour\n",
+		"Port allocation errors",
+		"    ✅ nat-2 in subnet-2, 54.1.2.4, available\n",
+		"  Subnets (2):\n    ✅ public-a (subnet-1) 10.0.0.0/24 in us-east-1a: 240 of 251 IPs free (95.62%), public\n",
+		"  Endpoints: s3 (gateway), com.amazonaws.vpce.us-east-1.vpce-svc-123 (interface) pendingacceptance\n",
+		"🌐 vpc-2 172.31.0.0/16 (default)\n  No subnets\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain '%s', got:\n%s", expected, output)
+		}
+	}
+	if strings.Count(output, "Port allocation errors") != 1 {
+		t.Errorf("Expected a graph for the saturated gateway only, got:\n%s", output)
+	}
+
+	summary := GetVPCsSummary(summaries)
+	if summary != "2 VPCs, 2 subnets, 2 NAT gateways, 2 endpoints, 1 subnets low on free IPs, 1 NAT gateways with port allocation errors" {
+		t.Errorf("Unexpected summary: %s", summary)
+	}
+
+	if output := FormatVPCs(nil); output != "No VPCs found" {
+		t.Errorf("Expected 'No VPCs found', got %q", output)
+	}
+}
@@ -0,0 +1,483 @@
+package vpc
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/correctedcloud/aws-overview/pkg/cloudwatchmetrics"
+	"github.com/correctedcloud/aws-overview/pkg/common"
+)
+
+// LowFreeIPs is the share of the usable addresses of a subnet, in percent,
+// below which it is about to run out of IPs for new instances, tasks and
+// interfaces
+const LowFreeIPs = 10.0
+
+// reservedIPs is how many addresses AWS reserves in every subnet
+const reservedIPs = 5
+
+// ec2ClientAPI defines the interface for the EC2 client
+type ec2ClientAPI interface {
+	DescribeVpcs(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error)
+	DescribeSubnets(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error)
+	DescribeNatGateways(ctx context.Context, params *ec2.DescribeNatGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNatGatewaysOutput, error)
+	DescribeInternetGateways(ctx context.Context, params *ec2.DescribeInternetGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInternetGatewaysOutput, error)
+	DescribeVpcEndpoints(ctx context.Context, params *ec2.DescribeVpcEndpointsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcEndpointsOutput, error)
+}
+
+// cloudwatchClientAPI defines the interface for the CloudWatch client
+type cloudwatchClientAPI interface {
+	GetMetricData(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error)
+}
+
+// Client represents a VPC client
+type Client struct {
+	ec2Client        ec2ClientAPI
+	cloudwatchClient cloudwatchClientAPI
+	pool             *common.Pool
+}
+
+// VPCSummary represents a VPC and the networking resources in it
+type VPCSummary struct {
+	ID               string
+	Name             string
+	CIDRBlock        string
+	IsDefault        bool
+	State            string // "pending" or "available"
+	Subnets          []SubnetSummary
+	NATGateways      []NATGatewaySummary
+	InternetGateways []InternetGatewaySummary
+	Endpoints        []EndpointSummary
+}
+
+// SubnetSummary represents a subnet and its free addresses
+type SubnetSummary struct {
+	ID               string
+	Name             string
+	VPCID            string
+	CIDRBlock        string
+	AvailabilityZone string
+	AvailableIPs     int32 // Free IPv4 addresses
+	MapPublicIP      bool  // Whether instances launched in it get a public IP
+}
+
+// NATGatewaySummary represents a NAT gateway
+type NATGatewaySummary struct {
+	ID               string
+	Name             string
+	VPCID            string
+	SubnetID         string
+	State            string // e.g. "available", "pending" or "failed"
+	ConnectivityType string // "public" or "private"
+	PublicIP         string
+
+	// PortAllocationErrorsData is the number of connections that failed for
+	// lack of a free source port per 5 minutes over the past hour, only for
+	// available gateways
+	PortAllocationErrorsData []float64
+	MetricsEnd               time.Time // End of the metric's window, zero when it was not fetched
+}
+
+// InternetGatewaySummary represents an internet gateway attached to a VPC
+type InternetGatewaySummary struct {
+	ID   string
+	Name string
+}
+
+// EndpointSummary represents a VPC endpoint
+type EndpointSummary struct {
+	ID          string
+	ServiceName string // e.g. "com.amazonaws.us-east-1.s3"
+	Type        string // "Gateway", "Interface" or "GatewayLoadBalancer"
+	State       string // e.g. "Available" or "PendingAcceptance"
+}
+
+// TotalIPs returns the number of usable addresses of the subnet, 0 when its
+// CIDR block cannot be parsed
+func (s SubnetSummary) TotalIPs() int {
+	prefix, err := netip.ParsePrefix(s.CIDRBlock)
+	if err != nil || !prefix.Addr().Is4() {
+		return 0
+	}
+	return 1<<(32-prefix.Bits()) - reservedIPs
+}
+
+// FreePercent returns the share of the usable addresses that are free
+func (s SubnetSummary) FreePercent() float64 {
+	total := s.TotalIPs()
+	if total <= 0 {
+		return 0
+	}
+	return float64(s.AvailableIPs) / float64(total) * 100
+}
+
+// LowOnIPs reports whether less than LowFreeIPs percent of the addresses
+// of the subnet are free
+func (s SubnetSummary) LowOnIPs() bool {
+	return s.TotalIPs() > 0 && s.FreePercent() < LowFreeIPs
+}
+
+// PortAllocationErrors returns the connections the gateway failed to
+// translate over the past hour
+func (n NATGatewaySummary) PortAllocationErrors() float64 {
+	var total float64
+	for _, value := range n.PortAllocationErrorsData {
+		total += value
+	}
+	return total
+}
+
+// Saturated reports whether the gateway ran out of source ports in the past
+// hour, failing new connections to a single destination
+func (n NATGatewaySummary) Saturated() bool {
+	return n.PortAllocationErrors() > 0
+}
+
+// Service returns the short name of the endpoint's service, e.g. "s3" or
+// "ecr.dkr" for AWS services, and the full name of PrivateLink services
+func (e EndpointSummary) Service() string {
+	// com.amazonaws.<region>.<service>, or com.amazonaws.vpce.<region>.<id>
+	// for services of other accounts
+	rest, ok := strings.CutPrefix(e.ServiceName, "com.amazonaws.")
+	if !ok || strings.HasPrefix(rest, "vpce.") {
+		return e.ServiceName
+	}
+	if _, service, ok := strings.Cut(rest, "."); ok {
+		return service
+	}
+	return e.ServiceName
+}
+
+// Available reports whether the endpoint accepts traffic
+func (e EndpointSummary) Available() bool {
+	return strings.EqualFold(e.State, string(types.StateAvailable))
+}
+
+// NewClient returns a new VPC client whose calls run in pool, which may be nil
+func NewClient(ec2Client ec2ClientAPI, cloudwatchClient cloudwatchClientAPI, pool *common.Pool) *Client {
+	return &Client{
+		ec2Client:        ec2Client,
+		cloudwatchClient: cloudwatchClient,
+		pool:             pool,
+	}
+}
+
+// GetVPCs returns all VPCs by name with their subnets, NAT gateways,
+// internet gateways and endpoints, and the port allocation errors of the
+// NAT gateways. Resources that fail to load are left out and their errors
+// returned alongside the VPCs.
+func (c *Client) GetVPCs(ctx context.Context) ([]VPCSummary, []error) {
+	vpcs, err := c.describeVPCs(ctx)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	summaries := make([]VPCSummary, len(vpcs))
+	index := make(map[string]*VPCSummary)
+	for i, vpc := range vpcs {
+		summaries[i] = VPCSummary{
+			ID:        aws.ToString(vpc.VpcId),
+			Name:      nameTag(vpc.Tags),
+			CIDRBlock: aws.ToString(vpc.CidrBlock),
+			IsDefault: aws.ToBool(vpc.IsDefault),
+			State:     string(vpc.State),
+		}
+		index[summaries[i].ID] = &summaries[i]
+	}
+
+	var errs []error
+	if subnets, err := c.describeSubnets(ctx); err != nil {
+		errs = append(errs, err)
+	} else {
+		for _, subnet := range subnets {
+			if vpc, ok := index[subnet.VPCID]; ok {
+				vpc.Subnets = append(vpc.Subnets, subnet)
+			}
+		}
+	}
+	if gateways, err := c.describeNATGateways(ctx); err != nil {
+		errs = append(errs, err)
+	} else {
+		for _, gateway := range gateways {
+			if vpc, ok := index[gateway.VPCID]; ok {
+				vpc.NATGateways = append(vpc.NATGateways, gateway)
+			}
+		}
+	}
+	if err := c.describeInternetGateways(ctx, index); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.describeEndpoints(ctx, index); err != nil {
+		errs = append(errs, err)
+	}
+
+	for i := range summaries {
+		sort.Slice(summaries[i].Subnets, func(a, b int) bool {
+			x, y := summaries[i].Subnets[a], summaries[i].Subnets[b]
+			if x.AvailabilityZone != y.AvailabilityZone {
+				return x.AvailabilityZone < y.AvailabilityZone
+			}
+			return x.ID < y.ID
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Name != summaries[j].Name {
+			return summaries[i].Name < summaries[j].Name
+		}
+		return summaries[i].ID < summaries[j].ID
+	})
+
+	errs = append(errs, c.getPortAllocationErrors(ctx, summaries)...)
+	return summaries, errs
+}
+
+// describeVPCs returns all VPCs, following the pagination tokens
+func (c *Client) describeVPCs(ctx context.Context) ([]types.Vpc, error) {
+	var vpcs []types.Vpc
+	var nextToken *string
+
+	for {
+		var result *ec2.DescribeVpcsOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.ec2Client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{
+				NextToken: nextToken,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe VPCs: %w", err)
+		}
+
+		vpcs = append(vpcs, result.Vpcs...)
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return vpcs, nil
+}
+
+// describeSubnets returns all subnets, following the pagination tokens
+func (c *Client) describeSubnets(ctx context.Context) ([]SubnetSummary, error) {
+	var subnets []SubnetSummary
+	var nextToken *string
+
+	for {
+		var result *ec2.DescribeSubnetsOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.ec2Client.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{
+				NextToken: nextToken,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe subnets: %w", err)
+		}
+
+		for _, subnet := range result.Subnets {
+			subnets = append(subnets, SubnetSummary{
+				ID:               aws.ToString(subnet.SubnetId),
+				Name:             nameTag(subnet.Tags),
+				VPCID:            aws.ToString(subnet.VpcId),
+				CIDRBlock:        aws.ToString(subnet.CidrBlock),
+				AvailabilityZone: aws.ToString(subnet.AvailabilityZone),
+				AvailableIPs:     aws.ToInt32(subnet.AvailableIpAddressCount),
+				MapPublicIP:      aws.ToBool(subnet.MapPublicIpOnLaunch),
+			})
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return subnets, nil
+}
+
+// describeNATGateways returns the NAT gateways that have not been deleted,
+// following the pagination tokens
+func (c *Client) describeNATGateways(ctx context.Context) ([]NATGatewaySummary, error) {
+	var gateways []NATGatewaySummary
+	var nextToken *string
+
+	for {
+		var result *ec2.DescribeNatGatewaysOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.ec2Client.DescribeNatGateways(ctx, &ec2.DescribeNatGatewaysInput{
+				NextToken: nextToken,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe NAT gateways: %w", err)
+		}
+
+		for _, gateway := range result.NatGateways {
+			// Deleted gateways stay listed for about an hour
+			if gateway.State == types.NatGatewayStateDeleted {
+				continue
+			}
+			summary := NATGatewaySummary{
+				ID:               aws.ToString(gateway.NatGatewayId),
+				Name:             nameTag(gateway.Tags),
+				VPCID:            aws.ToString(gateway.VpcId),
+				SubnetID:         aws.ToString(gateway.SubnetId),
+				State:            string(gateway.State),
+				ConnectivityType: string(gateway.ConnectivityType),
+			}
+			for _, address := range gateway.NatGatewayAddresses {
+				if address.PublicIp != nil && aws.ToBool(address.IsPrimary) {
+					summary.PublicIP = aws.ToString(address.PublicIp)
+				}
+			}
+			gateways = append(gateways, summary)
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return gateways, nil
+}
+
+// describeInternetGateways adds the internet gateways to the VPCs they are
+// attached to, following the pagination tokens
+func (c *Client) describeInternetGateways(ctx context.Context, vpcs map[string]*VPCSummary) error {
+	var nextToken *string
+
+	for {
+		var result *ec2.DescribeInternetGatewaysOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.ec2Client.DescribeInternetGateways(ctx, &ec2.DescribeInternetGatewaysInput{
+				NextToken: nextToken,
+			})
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to describe internet gateways: %w", err)
+		}
+
+		for _, gateway := range result.InternetGateways {
+			for _, attachment := range gateway.Attachments {
+				if vpc, ok := vpcs[aws.ToString(attachment.VpcId)]; ok {
+					vpc.InternetGateways = append(vpc.InternetGateways, InternetGatewaySummary{
+						ID:   aws.ToString(gateway.InternetGatewayId),
+						Name: nameTag(gateway.Tags),
+					})
+				}
+			}
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return nil
+}
+
+// describeEndpoints adds the endpoints that have not been deleted to their
+// VPCs, following the pagination tokens
+func (c *Client) describeEndpoints(ctx context.Context, vpcs map[string]*VPCSummary) error {
+	var nextToken *string
+
+	for {
+		var result *ec2.DescribeVpcEndpointsOutput
+		err := c.pool.Do(ctx, func() (err error) {
+			result, err = c.ec2Client.DescribeVpcEndpoints(ctx, &ec2.DescribeVpcEndpointsInput{
+				NextToken: nextToken,
+			})
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to describe VPC endpoints: %w", err)
+		}
+
+		for _, endpoint := range result.VpcEndpoints {
+			if endpoint.State == types.StateDeleted {
+				continue
+			}
+			if vpc, ok := vpcs[aws.ToString(endpoint.VpcId)]; ok {
+				vpc.Endpoints = append(vpc.Endpoints, EndpointSummary{
+					ID:          aws.ToString(endpoint.VpcEndpointId),
+					ServiceName: aws.ToString(endpoint.ServiceName),
+					Type:        string(endpoint.VpcEndpointType),
+					State:       string(endpoint.State),
+				})
+			}
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return nil
+}
+
+// getPortAllocationErrors fills in the port allocation errors of the
+// available NAT gateways, fetched together in as few CloudWatch calls as
+// possible, and returns the errors of those that could not be loaded
+func (c *Client) getPortAllocationErrors(ctx context.Context, summaries []VPCSummary) []error {
+	var queries []cloudwatchmetrics.Query
+	var queried []*NATGatewaySummary
+	for i := range summaries {
+		for j := range summaries[i].NATGateways {
+			gateway := &summaries[i].NATGateways[j]
+			if gateway.State != string(types.NatGatewayStateAvailable) {
+				continue
+			}
+			queries = append(queries, cloudwatchmetrics.Query{
+				Namespace:  "AWS/NATGateway",
+				MetricName: "ErrorPortAllocation",
+				Dimensions: map[string]string{"NatGatewayId": gateway.ID},
+				Stat:       "Sum",
+				Period:     5 * time.Minute,
+				Window:     time.Hour,
+			})
+			queried = append(queried, gateway)
+		}
+	}
+	if len(queries) == 0 {
+		return nil
+	}
+
+	results := cloudwatchmetrics.New(c.cloudwatchClient, c.pool).Fetch(ctx, queries)
+
+	var errs []error
+	for i, result := range results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("NAT gateway %s: failed to get metric data for ErrorPortAllocation: %w", queried[i].ID, result.Err))
+			continue
+		}
+		queried[i].PortAllocationErrorsData = result.Values
+		queried[i].MetricsEnd = result.End
+	}
+	return errs
+}
+
+// nameTag returns the value of the Name tag, or "" when there is none
+func nameTag(tags []types.Tag) string {
+	for _, tag := range tags {
+		if aws.ToString(tag.Key) == "Name" {
+			return aws.ToString(tag.Value)
+		}
+	}
+	return ""
+}
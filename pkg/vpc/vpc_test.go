@@ -0,0 +1,180 @@
+package vpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// Mock EC2 client
+type mockEC2Client struct {
+	describeVpcsFunc             func(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error)
+	describeSubnetsFunc          func(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error)
+	describeNatGatewaysFunc      func(ctx context.Context, params *ec2.DescribeNatGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNatGatewaysOutput, error)
+	describeInternetGatewaysFunc func(ctx context.Context, params *ec2.DescribeInternetGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInternetGatewaysOutput, error)
+	describeVpcEndpointsFunc     func(ctx context.Context, params *ec2.DescribeVpcEndpointsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcEndpointsOutput, error)
+}
+
+func (m *mockEC2Client) DescribeVpcs(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error) {
+	return m.describeVpcsFunc(ctx, params, optFns...)
+}
+
+func (m *mockEC2Client) DescribeSubnets(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+	if m.describeSubnetsFunc == nil {
+		return &ec2.DescribeSubnetsOutput{}, nil
+	}
+	return m.describeSubnetsFunc(ctx, params, optFns...)
+}
+
+func (m *mockEC2Client) DescribeNatGateways(ctx context.Context, params *ec2.DescribeNatGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNatGatewaysOutput, error) {
+	if m.describeNatGatewaysFunc == nil {
+		return &ec2.DescribeNatGatewaysOutput{}, nil
+	}
+	return m.describeNatGatewaysFunc(ctx, params, optFns...)
+}
+
+func (m *mockEC2Client) DescribeInternetGateways(ctx context.Context, params *ec2.DescribeInternetGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInternetGatewaysOutput, error) {
+	if m.describeInternetGatewaysFunc == nil {
+		return &ec2.DescribeInternetGatewaysOutput{}, nil
+	}
+	return m.describeInternetGatewaysFunc(ctx, params, optFns...)
+}
+
+func (m *mockEC2Client) DescribeVpcEndpoints(ctx context.Context, params *ec2.DescribeVpcEndpointsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcEndpointsOutput, error) {
+	if m.describeVpcEndpointsFunc == nil {
+		return &ec2.DescribeVpcEndpointsOutput{}, nil
+	}
+	return m.describeVpcEndpointsFunc(ctx, params, optFns...)
+}
+
+// Mock CloudWatch client
+type mockCloudWatchClient struct {
+	getMetricDataFunc func(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error)
+}
+
+func (m *mockCloudWatchClient) GetMetricData(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+	return m.getMetricDataFunc(ctx, params, optFns...)
+}
+
+func TestGetVPCs(t *testing.T) {
+	ec2Client := &mockEC2Client{
+		describeVpcsFunc: func(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error) {
+			return &ec2.DescribeVpcsOutput{Vpcs: []types.Vpc{
+				{VpcId: aws.String("vpc-2"), CidrBlock: aws.String("10.0.0.0/16"), Tags: []types.Tag{{Key: aws.String("Name"), Value: aws.String("prod")}}},
+				{VpcId: aws.String("vpc-1"), CidrBlock: aws.String("172.31.0.0/16"), IsDefault: aws.Bool(true)},
+			}}, nil
+		},
+		describeSubnetsFunc: func(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+			// Return the subnets over two pages
+			if params.NextToken == nil {
+				return &ec2.DescribeSubnetsOutput{
+					Subnets: []types.Subnet{
+						{SubnetId: aws.String("subnet-b"), VpcId: aws.String("vpc-2"), CidrBlock: aws.String("10.0.1.0/24"), AvailabilityZone: aws.String("us-east-1b"), AvailableIpAddressCount: aws.Int32(12)},
+					},
+					NextToken: aws.String("page-2"),
+				}, nil
+			}
+			return &ec2.DescribeSubnetsOutput{
+				Subnets: []types.Subnet{
+					{SubnetId: aws.String("subnet-a"), VpcId: aws.String("vpc-2"), CidrBlock: aws.String("10.0.0.0/24"), AvailabilityZone: aws.String("us-east-1a"), AvailableIpAddressCount: aws.Int32(200)},
+				},
+			}, nil
+		},
+		describeNatGatewaysFunc: func(ctx context.Context, params *ec2.DescribeNatGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNatGatewaysOutput, error) {
+			return &ec2.DescribeNatGatewaysOutput{NatGateways: []types.NatGateway{
+				{NatGatewayId: aws.String("nat-1"), VpcId: aws.String("vpc-2"), SubnetId: aws.String("subnet-a"), State: types.NatGatewayStateAvailable,
+					NatGatewayAddresses: []types.NatGatewayAddress{{PublicIp: aws.String("54.1.2.3"), IsPrimary: aws.Bool(true)}}},
+				{NatGatewayId: aws.String("nat-2"), VpcId: aws.String("vpc-2"), SubnetId: aws.String("subnet-b"), State: types.NatGatewayStatePending},
+				{NatGatewayId: aws.String("nat-0"), VpcId: aws.String("vpc-2"), State: types.NatGatewayStateDeleted},
+			}}, nil
+		},
+		describeInternetGatewaysFunc: func(ctx context.Context, params *ec2.DescribeInternetGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInternetGatewaysOutput, error) {
+			return nil, errors.New("access denied")
+		},
+		describeVpcEndpointsFunc: func(ctx context.Context, params *ec2.DescribeVpcEndpointsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcEndpointsOutput, error) {
+			return &ec2.DescribeVpcEndpointsOutput{VpcEndpoints: []types.VpcEndpoint{
+				{VpcEndpointId: aws.String("vpce-1"), VpcId: aws.String("vpc-2"), ServiceName: aws.String("com.amazonaws.us-east-1.ecr.dkr"), VpcEndpointType: types.VpcEndpointTypeInterface, State: types.StateAvailable},
+			}}, nil
+		},
+	}
+
+	var queried []string
+	cloudwatchClient := &mockCloudWatchClient{
+		getMetricDataFunc: func(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+			output := &cloudwatch.GetMetricDataOutput{}
+			for _, query := range params.MetricDataQueries {
+				queried = append(queried, aws.ToString(query.MetricStat.Metric.Dimensions[0].Value))
+				output.MetricDataResults = append(output.MetricDataResults, cwtypes.MetricDataResult{
+					Id:     query.Id,
+					Values: []float64{0, 4, 6},
+				})
+			}
+			return output, nil
+		},
+	}
+
+	summaries, errs := NewClient(ec2Client, cloudwatchClient, nil).GetVPCs(context.Background())
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "internet gateways") {
+		t.Errorf("Expected only the internet gateways error, got %v", errs)
+	}
+	if len(summaries) != 2 || summaries[0].ID != "vpc-1" || summaries[1].Name != "prod" {
+		t.Fatalf("Expected the unnamed VPC first, got %+v", summaries)
+	}
+
+	prod := summaries[1]
+	if len(prod.Subnets) != 2 || prod.Subnets[0].ID != "subnet-a" || prod.Subnets[1].TotalIPs() != 251 {
+		t.Errorf("Expected the subnets of both pages by zone, got %+v", prod.Subnets)
+	}
+	if len(prod.NATGateways) != 2 || prod.NATGateways[0].PublicIP != "54.1.2.3" {
+		t.Errorf("Expected the NAT gateways that were not deleted, got %+v", prod.NATGateways)
+	}
+	if len(queried) != 1 || queried[0] != "nat-1" || prod.NATGateways[0].PortAllocationErrors() != 10 {
+		t.Errorf("Expected the port allocation errors of the available gateway only, got %v", queried)
+	}
+	if len(prod.Endpoints) != 1 || prod.Endpoints[0].Service() != "ecr.dkr" || !prod.Endpoints[0].Available() {
+		t.Errorf("Expected the ECR endpoint, got %+v", prod.Endpoints)
+	}
+}
+
+func TestGetVPCsError(t *testing.T) {
+	ec2Client := &mockEC2Client{
+		describeVpcsFunc: func(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error) {
+			return nil, errors.New("access denied")
+		},
+	}
+
+	summaries, errs := NewClient(ec2Client, nil, nil).GetVPCs(context.Background())
+	if summaries != nil || len(errs) != 1 {
+		t.Errorf("Expected a single error and no VPCs, got %v and %v", summaries, errs)
+	}
+}
+
+func TestSubnetFreeIPs(t *testing.T) {
+	for _, tc := range []struct {
+		cidr      string
+		available int32
+		total     int
+		low       bool
+	}{
+		{"10.0.0.0/24", 200, 251, false},
+		{"10.0.0.0/24", 20, 251, true},
+		{"10.0.0.0/28", 1, 11, true},
+		{"172.31.0.0/20", 4091, 4091, false},
+		{"", 10, 0, false},
+	} {
+		subnet := SubnetSummary{CIDRBlock: tc.cidr, AvailableIPs: tc.available}
+		if total := subnet.TotalIPs(); total != tc.total {
+			t.Errorf("%s: expected %d usable IPs, got %d", tc.cidr, tc.total, total)
+		}
+		if low := subnet.LowOnIPs(); low != tc.low {
+			t.Errorf("%s with %d free: expected low %v, got %v", tc.cidr, tc.available, tc.low, low)
+		}
+	}
+}